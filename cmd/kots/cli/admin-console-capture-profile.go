@@ -0,0 +1,186 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"time"
+
+	units "github.com/docker/go-units"
+	"github.com/pkg/errors"
+	handlertypes "github.com/replicatedhq/kots/pkg/api/handlers/types"
+	"github.com/replicatedhq/kots/pkg/auth"
+	"github.com/replicatedhq/kots/pkg/k8sutil"
+	"github.com/replicatedhq/kots/pkg/logger"
+	"github.com/replicatedhq/kots/pkg/versionskew"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+func AdminConsoleCaptureProfileCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:           "capture-profile",
+		Short:         "Capture a runtime profile of the admin console",
+		Long:          "Capture a pprof profile (heap, goroutine, cpu, etc) from the running admin console and save it to a file for analysis with go tool pprof",
+		SilenceUsage:  true,
+		SilenceErrors: false,
+		PreRun: func(cmd *cobra.Command, args []string) {
+			viper.BindPFlags(cmd.Flags())
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			v := viper.GetViper()
+
+			log := logger.NewCLILogger()
+			namespace := v.GetString("namespace")
+			profile := v.GetString("profile")
+
+			if err := validateNamespace(namespace); err != nil {
+				return errors.Wrap(err, "failed to validate namespace")
+			}
+
+			output := v.GetString("output")
+			if output == "" {
+				output = fmt.Sprintf("kotsadm-%s-%s.pprof", profile, time.Now().Format("20060102-150405"))
+			}
+
+			clientset, err := k8sutil.GetClientset()
+			if err != nil {
+				return errors.Wrap(err, "failed to get clientset")
+			}
+
+			podName, err := k8sutil.WaitForKotsadm(clientset, namespace, time.Second*5)
+			if err != nil {
+				return errors.Wrap(err, "failed to find kotsadm pod")
+			}
+
+			stopCh := make(chan struct{})
+			defer close(stopCh)
+
+			localPort, errChan, err := k8sutil.PortForward(0, 3000, namespace, podName, false, stopCh, log)
+			if err != nil {
+				return errors.Wrap(err, "failed to start port forwarding")
+			}
+
+			go func() {
+				select {
+				case err := <-errChan:
+					if err != nil {
+						log.Error(err)
+					}
+				case <-stopCh:
+				}
+			}()
+
+			client, err := versionskew.NewClient(fmt.Sprintf("http://localhost:%d", localPort), log)
+			if err != nil {
+				return err
+			}
+
+			authSlug, err := auth.GetOrCreateAuthSlug(clientset, namespace)
+			if err != nil {
+				return withExitCode(ExitCodeAuthFailed, errors.Wrap(err, "failed to get kotsadm auth slug"))
+			}
+
+			diagnostics, err := getRuntimeDiagnostics(client, localPort, authSlug)
+			if err != nil {
+				return errors.Wrap(err, "failed to get runtime diagnostics")
+			}
+			log.ActionWithoutSpinner("Admin Console is using %s of heap across %d goroutines", units.BytesSize(float64(diagnostics.HeapInuse)), diagnostics.NumGoroutine)
+
+			seconds := v.GetInt("seconds")
+			if profile == "profile" || profile == "trace" {
+				log.ActionWithSpinner("Capturing %s profile for %d seconds", profile, seconds)
+			} else {
+				log.ActionWithSpinner("Capturing %s profile", profile)
+			}
+
+			if err := captureProfile(client, localPort, authSlug, profile, seconds, output); err != nil {
+				log.FinishSpinnerWithError()
+				return errors.Wrap(err, "failed to capture profile")
+			}
+
+			log.FinishSpinner()
+			log.ActionWithoutSpinner("Profile written to %s", output)
+
+			return nil
+		},
+	}
+
+	cmd.Flags().String("profile", "heap", "the profile to capture. one of allocs, block, goroutine, heap, mutex, threadcreate, profile (cpu), or trace")
+	cmd.Flags().Int("seconds", 30, "the duration of the cpu profile or trace, in seconds")
+	cmd.Flags().StringP("output", "o", "", "the file to write the profile to. defaults to a file in the current directory named after the profile")
+
+	return cmd
+}
+
+func getRuntimeDiagnostics(client *http.Client, localPort int, authSlug string) (*handlertypes.GetRuntimeDiagnosticsResponse, error) {
+	url := fmt.Sprintf("http://localhost:%d/api/v1/debug/runtime", localPort)
+	newRequest, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create http request")
+	}
+	newRequest.Header.Add("Authorization", authSlug)
+
+	resp, err := client.Do(newRequest)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to execute http request")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, errors.Errorf("unexpected status code %d", resp.StatusCode)
+	}
+
+	respBody, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read server response")
+	}
+
+	diagnostics := handlertypes.GetRuntimeDiagnosticsResponse{}
+	if err := json.Unmarshal(respBody, &diagnostics); err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal response")
+	}
+
+	return &diagnostics, nil
+}
+
+func captureProfile(client *http.Client, localPort int, authSlug string, profile string, seconds int, output string) error {
+	u := fmt.Sprintf("http://localhost:%d/api/v1/debug/pprof/%s", localPort, url.PathEscape(profile))
+	if profile == "profile" || profile == "trace" {
+		u = fmt.Sprintf("%s?seconds=%d", u, seconds)
+	}
+
+	newRequest, err := http.NewRequest("GET", u, nil)
+	if err != nil {
+		return errors.Wrap(err, "failed to create http request")
+	}
+	newRequest.Header.Add("Authorization", authSlug)
+
+	resp, err := client.Do(newRequest)
+	if err != nil {
+		return errors.Wrap(err, "failed to execute http request")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return errors.Errorf("profile %s is not available", profile)
+	} else if resp.StatusCode != http.StatusOK {
+		return errors.Errorf("unexpected status code %d", resp.StatusCode)
+	}
+
+	f, err := os.Create(output)
+	if err != nil {
+		return errors.Wrap(err, "failed to create output file")
+	}
+	defer f.Close()
+
+	if _, err := io.Copy(f, resp.Body); err != nil {
+		return errors.Wrap(err, "failed to write profile")
+	}
+
+	return nil
+}
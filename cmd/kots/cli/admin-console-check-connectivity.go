@@ -0,0 +1,78 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/pkg/errors"
+	handlertypes "github.com/replicatedhq/kots/pkg/api/handlers/types"
+	connectivitytypes "github.com/replicatedhq/kots/pkg/connectivity/types"
+	"github.com/replicatedhq/kots/pkg/print"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+func AdminConsoleCheckConnectivityCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:           "check-connectivity",
+		Short:         "Test the outbound connectivity of the admin console",
+		Long:          "Test the reachability of the vendor portal, the configured registries, the gitops remote and the proxy from the admin console, with the latency and TLS details of each",
+		SilenceUsage:  true,
+		SilenceErrors: false,
+		PreRun: func(cmd *cobra.Command, args []string) {
+			viper.BindPFlags(cmd.Flags())
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			v := viper.GetViper()
+
+			stopCh := make(chan struct{})
+			defer close(stopCh)
+
+			localPort, authSlug, client, err := forwardToAdminConsole(v, stopCh)
+			if err != nil {
+				return err
+			}
+
+			newReq, err := http.NewRequest("GET", fmt.Sprintf("http://localhost:%d/api/v1/debug/connectivity", localPort), nil)
+			if err != nil {
+				return errors.Wrap(err, "failed to create request")
+			}
+			newReq.Header.Add("Content-Type", "application/json")
+			newReq.Header.Add("Authorization", authSlug)
+
+			resp, err := client.Do(newReq)
+			if err != nil {
+				return errors.Wrap(err, "failed to execute request")
+			}
+			defer resp.Body.Close()
+
+			if resp.StatusCode != http.StatusOK {
+				return handlertypes.ErrorFromResponse(resp)
+			}
+
+			report := connectivitytypes.Report{}
+			if err := json.NewDecoder(resp.Body).Decode(&report); err != nil {
+				return errors.Wrap(err, "failed to unmarshal connectivity report")
+			}
+
+			print.Connectivity(&report, v.GetString("output"))
+
+			unreachable := 0
+			for _, check := range report.Checks {
+				if !check.Reachable {
+					unreachable++
+				}
+			}
+			if unreachable > 0 {
+				return errors.Errorf("%d of %d targets are unreachable", unreachable, len(report.Checks))
+			}
+
+			return nil
+		},
+	}
+
+	cmd.Flags().StringP("output", "o", "", "output format. supported values: json")
+
+	return cmd
+}
@@ -0,0 +1,144 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/manifoldco/promptui"
+	"github.com/pkg/errors"
+	"github.com/replicatedhq/kots/pkg/k8sutil"
+	"github.com/replicatedhq/kots/pkg/kotsadm"
+	kotsadmtypes "github.com/replicatedhq/kots/pkg/kotsadm/types"
+	"github.com/replicatedhq/kots/pkg/logger"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+func AdminConsoleRemoveComponentCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "remove-component [ui|minio|registry]",
+		Short: "Remove a component of the admin console that is not needed",
+		Long: `Remove a component of the admin console that the installation does not need, and keep it removed on upgrades.
+
+ui:       stop serving the web console, leaving only the api
+minio:    remove the embedded minio once the files are migrated to another object store
+registry: remove the embedded registry once the files are migrated to another object store
+
+The volumes of minio and the registry are kept unless --delete-data is set.`,
+		Args:          cobra.ExactArgs(1),
+		SilenceUsage:  true,
+		SilenceErrors: false,
+		PreRun: func(cmd *cobra.Command, args []string) {
+			viper.BindPFlags(cmd.Flags())
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			v := viper.GetViper()
+
+			component, err := kotsadmtypes.ParseComponent(args[0])
+			if err != nil {
+				return err
+			}
+
+			namespace := v.GetString("namespace")
+			if err := validateNamespace(namespace); err != nil {
+				return errors.Wrap(err, "failed to validate namespace")
+			}
+
+			deleteData := v.GetBool("delete-data")
+			if deleteData && component != kotsadmtypes.ComponentUI {
+				prompt := promptui.Prompt{
+					Label:     fmt.Sprintf("The volume of %s will be deleted. Do you want to continue", component),
+					IsConfirm: true,
+				}
+
+				for {
+					resp, err := prompt.Run()
+					if err == promptui.ErrInterrupt {
+						os.Exit(-1)
+					}
+					if strings.ToLower(resp) == "n" {
+						os.Exit(-1)
+					}
+					if strings.ToLower(resp) == "y" {
+						break
+					}
+				}
+			}
+
+			clientset, err := k8sutil.GetClientset()
+			if err != nil {
+				return errors.Wrap(err, "failed to get clientset")
+			}
+
+			log := logger.NewCLILogger()
+			log.ActionWithSpinner("Removing %s", component)
+			if err := kotsadm.RemoveComponent(context.TODO(), clientset, namespace, component, deleteData); err != nil {
+				log.FinishSpinnerWithError()
+				return errors.Wrapf(err, "failed to remove %s", component)
+			}
+			log.FinishSpinner()
+
+			log.ActionWithoutSpinner("")
+			log.ActionWithoutSpinner("To deploy it again, run kubectl kots admin-console restore-component %s --namespace %s", component, namespace)
+			log.ActionWithoutSpinner("")
+
+			return nil
+		},
+	}
+
+	cmd.Flags().Bool("delete-data", false, "delete the volume of minio or the registry")
+
+	return cmd
+}
+
+func AdminConsoleRestoreComponentCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:           "restore-component [ui|minio|registry]",
+		Short:         "Deploy a removed component of the admin console again",
+		Long:          "Deploy a component that was removed with kots admin-console remove-component again. The ui is served again right away, minio and the registry are deployed by the next kots admin-console upgrade.",
+		Args:          cobra.ExactArgs(1),
+		SilenceUsage:  true,
+		SilenceErrors: false,
+		PreRun: func(cmd *cobra.Command, args []string) {
+			viper.BindPFlags(cmd.Flags())
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			v := viper.GetViper()
+
+			component, err := kotsadmtypes.ParseComponent(args[0])
+			if err != nil {
+				return err
+			}
+
+			namespace := v.GetString("namespace")
+			if err := validateNamespace(namespace); err != nil {
+				return errors.Wrap(err, "failed to validate namespace")
+			}
+
+			clientset, err := k8sutil.GetClientset()
+			if err != nil {
+				return errors.Wrap(err, "failed to get clientset")
+			}
+
+			log := logger.NewCLILogger()
+			log.ActionWithSpinner("Restoring %s", component)
+			if err := kotsadm.RestoreComponent(context.TODO(), clientset, namespace, component); err != nil {
+				log.FinishSpinnerWithError()
+				return errors.Wrapf(err, "failed to restore %s", component)
+			}
+			log.FinishSpinner()
+
+			if component != kotsadmtypes.ComponentUI {
+				log.ActionWithoutSpinner("")
+				log.ActionWithoutSpinner("%s will be deployed by the next kubectl kots admin-console upgrade --namespace %s", component, namespace)
+				log.ActionWithoutSpinner("")
+			}
+
+			return nil
+		},
+	}
+
+	return cmd
+}
@@ -0,0 +1,185 @@
+package cli
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/replicatedhq/kots/pkg/auth"
+	"github.com/replicatedhq/kots/pkg/crypto"
+	"github.com/replicatedhq/kots/pkg/k8sutil"
+	kotsadmstatetypes "github.com/replicatedhq/kots/pkg/kotsadmstate/types"
+	"github.com/replicatedhq/kots/pkg/logger"
+	"github.com/replicatedhq/kots/pkg/versionskew"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+func AdminConsoleExportCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:           "export",
+		Short:         "Export the state of the admin console to an encrypted archive",
+		Long:          "Export all apps, versions, config, registry settings and sessions of the admin console to a single encrypted archive that can be imported into a fresh admin console in another cluster with kots admin-console import",
+		SilenceUsage:  true,
+		SilenceErrors: false,
+		PreRun: func(cmd *cobra.Command, args []string) {
+			viper.BindPFlags(cmd.Flags())
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			v := viper.GetViper()
+
+			log := logger.NewCLILogger()
+			namespace := v.GetString("namespace")
+
+			if err := validateNamespace(namespace); err != nil {
+				return errors.Wrap(err, "failed to validate namespace")
+			}
+
+			output := v.GetString("output")
+			if output == "" {
+				output = fmt.Sprintf("kotsadm-state-%s.enc", time.Now().Format("20060102-150405"))
+			}
+
+			clientset, err := k8sutil.GetClientset()
+			if err != nil {
+				return errors.Wrap(err, "failed to get clientset")
+			}
+
+			podName, err := k8sutil.WaitForKotsadm(clientset, namespace, time.Second*5)
+			if err != nil {
+				return errors.Wrap(err, "failed to find kotsadm pod")
+			}
+
+			stopCh := make(chan struct{})
+			defer close(stopCh)
+
+			localPort, errChan, err := k8sutil.PortForward(0, 3000, namespace, podName, false, stopCh, log)
+			if err != nil {
+				return errors.Wrap(err, "failed to start port forwarding")
+			}
+
+			go func() {
+				select {
+				case err := <-errChan:
+					if err != nil {
+						log.Error(err)
+					}
+				case <-stopCh:
+				}
+			}()
+
+			client, err := versionskew.NewClient(fmt.Sprintf("http://localhost:%d", localPort), log)
+			if err != nil {
+				return err
+			}
+
+			authSlug, err := auth.GetOrCreateAuthSlug(clientset, namespace)
+			if err != nil {
+				return withExitCode(ExitCodeAuthFailed, errors.Wrap(err, "failed to get kotsadm auth slug"))
+			}
+
+			log.ActionWithSpinner("Exporting the Admin Console")
+
+			cipher, err := crypto.NewAESCipher()
+			if err != nil {
+				log.FinishSpinnerWithError()
+				return errors.Wrap(err, "failed to create cipher")
+			}
+
+			encrypted, err := exportKotsadmState(client, localPort, authSlug, cipher)
+			if err != nil {
+				log.FinishSpinnerWithError()
+				return errors.Wrap(err, "failed to export kotsadm state")
+			}
+
+			if err := ioutil.WriteFile(output, encrypted, 0600); err != nil {
+				log.FinishSpinnerWithError()
+				return errors.Wrap(err, "failed to write archive")
+			}
+
+			log.FinishSpinner()
+			log.ActionWithoutSpinner("Admin Console state written to %s", output)
+			log.ActionWithoutSpinner("The archive can only be imported with this key, store it securely:")
+			fmt.Fprintln(os.Stdout, cipher.ToString())
+
+			return nil
+		},
+	}
+
+	cmd.Flags().StringP("output", "o", "", "the file to write the encrypted archive to. defaults to a file in the current directory")
+
+	return cmd
+}
+
+// exportKotsadmState returns the archive encrypted by the admin console with the key of cipher
+func exportKotsadmState(client *http.Client, localPort int, authSlug string, cipher *crypto.AESCipher) ([]byte, error) {
+	url := fmt.Sprintf("http://localhost:%d/api/v1/kotsadm/export", localPort)
+	newRequest, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create http request")
+	}
+	newRequest.Header.Add("Authorization", authSlug)
+	newRequest.Header.Add(kotsadmstatetypes.ExportKeyHeader, cipher.ToString())
+
+	resp, err := client.Do(newRequest)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to execute http request")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, errors.Errorf("unexpected status code %d", resp.StatusCode)
+	}
+
+	encrypted, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read server response")
+	}
+
+	archive, err := cipher.Decrypt(encrypted)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to decrypt archive")
+	}
+	if err := validateKotsadmStateArchive(archive); err != nil {
+		return nil, errors.Wrap(err, "export is incomplete, check the admin console logs")
+	}
+
+	return encrypted, nil
+}
+
+// validateKotsadmStateArchive checks that the archive is complete. The manifest is the last file of the archive.
+func validateKotsadmStateArchive(archive []byte) error {
+	gzipReader, err := gzip.NewReader(bytes.NewReader(archive))
+	if err != nil {
+		return errors.Wrap(err, "failed to create gzip reader")
+	}
+	defer gzipReader.Close()
+
+	hasManifest := false
+	tarReader := tar.NewReader(gzipReader)
+	for {
+		header, err := tarReader.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return errors.Wrap(err, "failed to read archive")
+		}
+		if header.Name == "manifest.json" {
+			hasManifest = true
+		}
+	}
+
+	if !hasManifest {
+		return errors.New("archive has no manifest")
+	}
+
+	return nil
+}
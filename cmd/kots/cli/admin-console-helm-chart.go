@@ -0,0 +1,76 @@
+package cli
+
+import (
+	"os"
+
+	"github.com/pkg/errors"
+	"github.com/replicatedhq/kots/pkg/kotsadm"
+	kotsadmtypes "github.com/replicatedhq/kots/pkg/kotsadm/types"
+	"github.com/replicatedhq/kots/pkg/logger"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+func AdminConsoleHelmChartCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:           "helm-chart",
+		Short:         "Generate a helm chart for the admin console",
+		Long:          "Generate a helm chart containing the same manifests that kots install would deploy for the admin console. The generated credentials are written to a separate values file that must be passed to helm install.",
+		SilenceUsage:  true,
+		SilenceErrors: false,
+		PreRun: func(cmd *cobra.Command, args []string) {
+			viper.BindPFlags(cmd.Flags())
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			v := viper.GetViper()
+
+			log := logger.NewCLILogger()
+
+			outputDir := ExpandDir(v.GetString("output-dir"))
+			if err := os.MkdirAll(outputDir, 0755); err != nil {
+				return errors.Wrap(err, "failed to create output dir")
+			}
+
+			deployOptions := kotsadmtypes.DeployOptions{
+				SharedPassword:     v.GetString("shared-password"),
+				HTTPProxyEnvValue:  v.GetString("http-proxy"),
+				HTTPSProxyEnvValue: v.GetString("https-proxy"),
+				NoProxyEnvValue:    v.GetString("no-proxy"),
+				IncludeMinio:       true,
+				EnsureRBAC:         true,
+				KotsadmOptions: kotsadmtypes.KotsadmOptions{
+					OverrideVersion:   v.GetString("kotsadm-tag"),
+					OverrideRegistry:  v.GetString("kotsadm-registry"),
+					OverrideNamespace: v.GetString("kotsadm-namespace"),
+					Username:          v.GetString("registry-username"),
+					Password:          v.GetString("registry-password"),
+				},
+			}
+
+			if deployOptions.SharedPassword == "" {
+				return errors.New("--shared-password is required")
+			}
+
+			valuesFile := ExpandDir(v.GetString("values-file"))
+			if err := kotsadm.WriteHelmChart(deployOptions, outputDir, valuesFile); err != nil {
+				return errors.Wrap(err, "failed to write helm chart")
+			}
+
+			log.ActionWithoutSpinner("Admin Console helm chart written to %s", outputDir)
+			log.ActionWithoutSpinner("Admin Console secrets written to %s", valuesFile)
+
+			return nil
+		},
+	}
+
+	cmd.Flags().String("output-dir", "./kotsadm-chart", "the directory to write the helm chart to")
+	cmd.Flags().String("values-file", "./kotsadm-values.yaml", "the file to write the values containing the Admin Console secrets to")
+	cmd.Flags().String("shared-password", "", "shared password to use for the Admin Console")
+	cmd.Flags().String("http-proxy", "", "sets HTTP_PROXY environment variable in all KOTS Admin Console components")
+	cmd.Flags().String("https-proxy", "", "sets HTTPS_PROXY environment variable in all KOTS Admin Console components")
+	cmd.Flags().String("no-proxy", "", "sets NO_PROXY environment variable in all KOTS Admin Console components")
+
+	registryFlags(cmd.Flags())
+
+	return cmd
+}
@@ -0,0 +1,160 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"time"
+
+	"github.com/pkg/errors"
+	handlertypes "github.com/replicatedhq/kots/pkg/api/handlers/types"
+	"github.com/replicatedhq/kots/pkg/auth"
+	"github.com/replicatedhq/kots/pkg/crypto"
+	"github.com/replicatedhq/kots/pkg/k8sutil"
+	"github.com/replicatedhq/kots/pkg/logger"
+	"github.com/replicatedhq/kots/pkg/versionskew"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+func AdminConsoleImportCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:           "import [archive]",
+		Short:         "Import the state of the admin console from an encrypted archive",
+		Long:          "Import the apps, versions, config, registry settings and sessions exported with kots admin-console export into an admin console that has no apps installed",
+		SilenceUsage:  true,
+		SilenceErrors: false,
+		Args:          cobra.ExactArgs(1),
+		PreRun: func(cmd *cobra.Command, args []string) {
+			viper.BindPFlags(cmd.Flags())
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			v := viper.GetViper()
+
+			log := logger.NewCLILogger()
+			namespace := v.GetString("namespace")
+
+			if err := validateNamespace(namespace); err != nil {
+				return errors.Wrap(err, "failed to validate namespace")
+			}
+
+			key := v.GetString("key")
+			if key == "" {
+				return errors.New("--key is required")
+			}
+
+			cipher, err := crypto.AESCipherFromString(key)
+			if err != nil {
+				return errors.Wrap(err, "failed to load key")
+			}
+
+			encrypted, err := ioutil.ReadFile(args[0])
+			if err != nil {
+				return errors.Wrap(err, "failed to read archive")
+			}
+
+			archive, err := cipher.Decrypt(encrypted)
+			if err != nil {
+				return errors.Wrap(err, "failed to decrypt archive, check that the key is the one printed by the export")
+			}
+
+			clientset, err := k8sutil.GetClientset()
+			if err != nil {
+				return errors.Wrap(err, "failed to get clientset")
+			}
+
+			podName, err := k8sutil.WaitForKotsadm(clientset, namespace, time.Second*5)
+			if err != nil {
+				return errors.Wrap(err, "failed to find kotsadm pod")
+			}
+
+			stopCh := make(chan struct{})
+			defer close(stopCh)
+
+			localPort, errChan, err := k8sutil.PortForward(0, 3000, namespace, podName, false, stopCh, log)
+			if err != nil {
+				return errors.Wrap(err, "failed to start port forwarding")
+			}
+
+			go func() {
+				select {
+				case err := <-errChan:
+					if err != nil {
+						log.Error(err)
+					}
+				case <-stopCh:
+				}
+			}()
+
+			client, err := versionskew.NewClient(fmt.Sprintf("http://localhost:%d", localPort), log)
+			if err != nil {
+				return err
+			}
+
+			authSlug, err := auth.GetOrCreateAuthSlug(clientset, namespace)
+			if err != nil {
+				return withExitCode(ExitCodeAuthFailed, errors.Wrap(err, "failed to get kotsadm auth slug"))
+			}
+
+			log.ActionWithSpinner("Importing the Admin Console")
+
+			response, err := importKotsadmState(client, localPort, authSlug, archive, v.GetBool("deploy"))
+			if err != nil {
+				log.FinishSpinnerWithError()
+				return errors.Wrap(err, "failed to import kotsadm state")
+			}
+
+			log.FinishSpinner()
+			for _, a := range response.Apps {
+				if a.DeployedSequence != nil {
+					log.ActionWithoutSpinner("Imported %d versions of %s and deployed sequence %d", a.Versions, a.Slug, *a.DeployedSequence)
+				} else {
+					log.ActionWithoutSpinner("Imported %d versions of %s", a.Versions, a.Slug)
+				}
+			}
+
+			return nil
+		},
+	}
+
+	cmd.Flags().String("key", "", "the key printed by kots admin-console export")
+	cmd.Flags().Bool("deploy", false, "deploy the versions that were deployed when the admin console was exported")
+
+	return cmd
+}
+
+func importKotsadmState(client *http.Client, localPort int, authSlug string, archive []byte, deploy bool) (*handlertypes.ImportKotsadmStateResponse, error) {
+	url := fmt.Sprintf("http://localhost:%d/api/v1/kotsadm/import?deploy=%t", localPort, deploy)
+	newRequest, err := http.NewRequest("POST", url, bytes.NewReader(archive))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create http request")
+	}
+	newRequest.Header.Add("Authorization", authSlug)
+	newRequest.Header.Add("Content-Type", "application/gzip")
+
+	resp, err := client.Do(newRequest)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to execute http request")
+	}
+	defer resp.Body.Close()
+
+	respBody, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read server response")
+	}
+
+	if resp.StatusCode == http.StatusConflict {
+		return nil, errors.New("the admin console already has apps installed, state can only be imported into a fresh admin console")
+	} else if resp.StatusCode != http.StatusOK {
+		return nil, errors.Errorf("unexpected status code %d: %s", resp.StatusCode, string(respBody))
+	}
+
+	response := handlertypes.ImportKotsadmStateResponse{}
+	if err := json.Unmarshal(respBody, &response); err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal response")
+	}
+
+	return &response, nil
+}
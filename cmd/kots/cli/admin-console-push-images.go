@@ -13,6 +13,7 @@ import (
 	"github.com/aws/aws-sdk-go/service/ecr"
 	"github.com/pkg/errors"
 	"github.com/replicatedhq/kots/pkg/docker/registry"
+	"github.com/replicatedhq/kots/pkg/image"
 	"github.com/replicatedhq/kots/pkg/kotsadm"
 	kotsadmtypes "github.com/replicatedhq/kots/pkg/kotsadm/types"
 	"github.com/spf13/cobra"
@@ -55,6 +56,11 @@ func AdminPushImagesCmd() *cobra.Command {
 				}
 			}
 
+			bandwidthLimit, err := image.ParseBandwidthLimit(v.GetString("bandwidth-limit"))
+			if err != nil {
+				return errors.Wrap(err, "failed to parse bandwidth limit")
+			}
+
 			options := kotsadmtypes.PushImagesOptions{
 				KotsadmTag: v.GetString("kotsadm-tag"),
 				Registry: registry.RegistryOptions{
@@ -62,10 +68,12 @@ func AdminPushImagesCmd() *cobra.Command {
 					Username: username,
 					Password: password,
 				},
-				ProgressWriter: os.Stdout,
+				ProgressWriter:   os.Stdout,
+				AllArchitectures: v.GetBool("all-architectures"),
+				BandwidthLimit:   bandwidthLimit,
 			}
 
-			err := kotsadm.PushImages(airgapArchive, options)
+			err = kotsadm.PushImages(airgapArchive, options)
 			if err != nil {
 				return errors.Wrap(err, "failed to push images")
 			}
@@ -76,6 +84,8 @@ func AdminPushImagesCmd() *cobra.Command {
 
 	cmd.Flags().String("registry-username", "", "user name to use to authenticate with the registry")
 	cmd.Flags().String("registry-password", "", "password to use to authenticate with the registry")
+	cmd.Flags().String("bandwidth-limit", "", "maximum rate to push images at, in bytes per second (e.g. 10MB). unlimited by default")
+	cmd.Flags().Bool("all-architectures", false, "push all architectures of multi-arch images instead of only the architectures of the cluster nodes")
 
 	cmd.Flags().String("kotsadm-tag", "", "set to override the tag of kotsadm. this may create an incompatible deployment because the version of kots and kotsadm are designed to work together")
 	cmd.Flags().MarkHidden("kotsadm-tag")
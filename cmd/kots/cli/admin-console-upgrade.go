@@ -9,6 +9,7 @@ import (
 
 	"github.com/manifoldco/promptui"
 	"github.com/pkg/errors"
+	"github.com/replicatedhq/kots/pkg/hostaliases"
 	"github.com/replicatedhq/kots/pkg/kotsadm"
 	kotsadmtypes "github.com/replicatedhq/kots/pkg/kotsadm/types"
 	"github.com/replicatedhq/kots/pkg/logger"
@@ -51,15 +52,25 @@ func AdminConsoleUpgradeCmd() *cobra.Command {
 
 			simultaneousUploads, _ := strconv.Atoi(v.GetString("airgap-upload-parallelism"))
 
+			gcsServiceAccount, azureAccountKey, err := getStorageCredentials(v)
+			if err != nil {
+				return errors.Wrap(err, "failed to get storage credentials")
+			}
+
 			upgradeOptions := kotsadmtypes.UpgradeOptions{
 				Namespace:                 v.GetString("namespace"),
 				ForceUpgradeKurl:          v.GetBool("force-upgrade-kurl"),
 				EnsureRBAC:                v.GetBool("ensure-rbac"),
 				SimultaneousUploads:       simultaneousUploads,
+				ImagePushWorkers:          v.GetInt("image-push-workers"),
 				StorageBaseURI:            v.GetString("storage-base-uri"),
 				StorageBaseURIPlainHTTP:   v.GetBool("storage-base-uri-plainhttp"),
+				StorageGCSServiceAccount:  gcsServiceAccount,
+				StorageAzureAccountKey:    azureAccountKey,
+				MigrateStorage:            v.GetBool("migrate-storage"),
 				IncludeMinio:              v.GetBool("with-minio"),
 				IncludeDockerDistribution: v.GetBool("with-dockerdistribution"),
+				PodSecurityProfile:        kotsadmtypes.PodSecurityProfile(v.GetString("pod-security-profile")),
 
 				KotsadmOptions: kotsadmtypes.KotsadmOptions{
 					OverrideVersion:   v.GetString("kotsadm-tag"),
@@ -70,6 +81,15 @@ func AdminConsoleUpgradeCmd() *cobra.Command {
 				},
 			}
 
+			hostAliasValues, _ := cmd.Flags().GetStringArray("host-alias")
+			if len(hostAliasValues) > 0 {
+				hostAliases, err := hostaliases.Parse(hostAliasValues)
+				if err != nil {
+					return errors.Wrap(err, "failed to parse host aliases")
+				}
+				upgradeOptions.HostAliases = hostAliases
+			}
+
 			timeout, err := time.ParseDuration(v.GetString("wait-duration"))
 			if err != nil {
 				return errors.Wrap(err, "failed to parse timeout value")
@@ -111,6 +131,8 @@ func AdminConsoleUpgradeCmd() *cobra.Command {
 	cmd.Flags().String("registry-password", "", "password to use to authenticate with the registry")
 	cmd.Flags().String("kotsadm-namespace", "", "set to override the namespace of kotsadm images. this may create an incompatible deployment because the version of kots and kotsadm are designed to work together")
 	cmd.Flags().String("wait-duration", "2m", "timeout out to be used while waiting for individual components to be ready.  must be in Go duration format (eg: 10s, 2m)")
+	cmd.Flags().StringArray("host-alias", []string{}, "a host alias in the form ip=hostname[,hostname...] to add to the Admin Console pods, replacing the existing aliases (can be specified multiple times)")
+	cmd.Flags().String("pod-security-profile", string(kotsadmtypes.PodSecurityProfileAuto), "the pod security standard to generate the Admin Console pods for (auto, default or restricted). auto keeps the current profile, or selects restricted if the namespace enforces, warns or audits it with pod security admission")
 	cmd.Flags().Bool("ensure-rbac", true, "when set, kots will create the roles and rolebindings necessary to manage applications")
 	cmd.Flags().String("airgap-upload-parallelism", "", "the number of chunks to upload in parallel when installing or updating in airgap mode")
 	cmd.Flags().MarkHidden("force-upgrade-kurl")
@@ -118,13 +140,20 @@ func AdminConsoleUpgradeCmd() *cobra.Command {
 	cmd.Flags().MarkHidden("kotsadm-namespace")
 	cmd.Flags().MarkHidden("ensure-rbac")
 	cmd.Flags().MarkHidden("airgap-upload-parallelism")
+	cmd.Flags().Int("image-push-workers", 0, "the number of jobs in the cluster that push the images of airgap bundles in parallel")
 
 	// options for the alpha feature of using a reg instead of s3 for storage
-	cmd.Flags().String("storage-base-uri", "", "an s3 or oci-registry uri to use for kots persistent storage in the cluster")
+	cmd.Flags().String("storage-base-uri", "", "an s3, gs://<bucket>, azblob://<account>/<container> or oci-registry uri to use for kots persistent storage. gcs and azure uris are kept when not set")
+	cmd.Flags().String("storage-gcs-service-account-file", "", "path to the json key of the service account used to access the gcs bucket. the default credentials are used when not set")
+	cmd.Flags().String("storage-azure-account-key", "", "the key of the storage account used to access the azure blob container")
+	cmd.Flags().Bool("migrate-storage", false, "when set, the files in the current storage are copied to the storage in --storage-base-uri when the Admin Console starts")
 	cmd.Flags().Bool("with-minio", true, "when set, kots install will deploy a local minio instance for storage")
 	cmd.Flags().Bool("with-dockerdistribution", false, "when set, kots install will deploy a local instance of docker distribution for storage")
 	cmd.Flags().Bool("storage-base-uri-plainhttp", false, "when set, use plain http (not https) connecting to the local oci storage")
 	cmd.Flags().MarkHidden("storage-base-uri")
+	cmd.Flags().MarkHidden("storage-gcs-service-account-file")
+	cmd.Flags().MarkHidden("storage-azure-account-key")
+	cmd.Flags().MarkHidden("migrate-storage")
 	cmd.Flags().MarkHidden("with-minio")
 	cmd.Flags().MarkHidden("with-dockerdistribution")
 	cmd.Flags().MarkHidden("storage-base-uri-plainhttp")
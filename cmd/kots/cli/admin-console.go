@@ -1,12 +1,14 @@
 package cli
 
 import (
+	"context"
 	"os"
 	"os/signal"
 	"time"
 
 	"github.com/pkg/errors"
 	"github.com/replicatedhq/kots/pkg/k8sutil"
+	"github.com/replicatedhq/kots/pkg/kotsadm"
 	"github.com/replicatedhq/kots/pkg/kotsadm/types"
 	"github.com/replicatedhq/kots/pkg/logger"
 	"github.com/spf13/cobra"
@@ -30,13 +32,16 @@ func AdminConsoleCmd() *cobra.Command {
 
 			clientset, err := k8sutil.GetClientset()
 			if err != nil {
-				return errors.Wrap(err, "failed to get clientset")
+				return withExitCode(ExitCodeClusterUnreachable, errors.Wrap(err, "failed to get clientset"))
+			}
+			if err := checkClusterReachable(clientset); err != nil {
+				return err
 			}
 
 			podName, err := k8sutil.WaitForKotsadm(clientset, v.GetString("namespace"), time.Second*5)
 			if err != nil {
 				if _, ok := errors.Cause(err).(*types.ErrorTimeout); ok {
-					return errors.Errorf("kotsadm failed to start: %s. Use the --wait-duration flag to increase timeout.", err)
+					return withExitCode(ExitCodeTimeout, errors.Errorf("kotsadm failed to start: %s. Use the --wait-duration flag to increase timeout.", err))
 				}
 				return errors.Wrap(err, "failed to wait for web")
 			}
@@ -66,8 +71,17 @@ func AdminConsoleCmd() *cobra.Command {
 				log.ActionWithoutSpinner("")
 			}
 
+			removedComponents, err := kotsadm.GetRemovedComponents(context.TODO(), clientset, v.GetString("namespace"))
+			if err != nil {
+				return errors.Wrap(err, "failed to get removed components")
+			}
+
 			log.ActionWithoutSpinner("Press Ctrl+C to exit")
-			log.ActionWithoutSpinner("Go to http://localhost:%d to access the Admin Console", adminConsolePort)
+			if kotsadm.IsComponentRemoved(removedComponents, types.ComponentUI) {
+				log.ActionWithoutSpinner("The Admin Console api is available at http://localhost:%d/api/v1", adminConsolePort)
+			} else {
+				log.ActionWithoutSpinner("Go to http://localhost:%d to access the Admin Console", adminConsolePort)
+			}
 
 			signalChan := make(chan os.Signal, 1)
 			signal.Notify(signalChan, os.Interrupt)
@@ -82,6 +96,13 @@ func AdminConsoleCmd() *cobra.Command {
 
 	cmd.AddCommand(AdminConsoleUpgradeCmd())
 	cmd.AddCommand(AdminPushImagesCmd())
+	cmd.AddCommand(AdminConsoleHelmChartCmd())
+	cmd.AddCommand(AdminConsoleCaptureProfileCmd())
+	cmd.AddCommand(AdminConsoleExportCmd())
+	cmd.AddCommand(AdminConsoleImportCmd())
+	cmd.AddCommand(AdminConsoleCheckConnectivityCmd())
+	cmd.AddCommand(AdminConsoleRemoveComponentCmd())
+	cmd.AddCommand(AdminConsoleRestoreComponentCmd())
 
 	return cmd
 }
@@ -0,0 +1,53 @@
+package cli
+
+import (
+	"github.com/pkg/errors"
+	"github.com/replicatedhq/kots/pkg/airgap/verify"
+	"github.com/replicatedhq/kots/pkg/print"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+func AirgapVerifyCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "verify [bundle]",
+		Short: "Verify an airgap bundle",
+		Long: `Verify the structure of an airgap bundle, that it contains every image the application references, that the checksums of the image archives match their content, and that every digest the image manifests reference is in the bundle.
+
+The command exits with an error if any check fails, so that bundles can be verified in CI before they are shipped.
+
+Examples:
+kubectl kots airgap verify ./my-app.airgap
+kubectl kots airgap verify ./my-app.airgap -o json`,
+		SilenceUsage:  true,
+		SilenceErrors: false,
+		PreRun: func(cmd *cobra.Command, args []string) {
+			viper.BindPFlags(cmd.Flags())
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			v := viper.GetViper()
+
+			if len(args) != 1 {
+				cmd.Help()
+				return errors.New("bundle path is required")
+			}
+
+			result, err := verify.Verify(ExpandDir(args[0]))
+			if err != nil {
+				return errors.Wrap(err, "failed to verify bundle")
+			}
+
+			print.AirgapVerifyResult(result, v.GetString("output"))
+
+			if !result.Passed() {
+				return errors.New("airgap bundle verification failed")
+			}
+
+			return nil
+		},
+	}
+
+	cmd.Flags().StringP("output", "o", "", "output format. supported values: json")
+
+	return cmd
+}
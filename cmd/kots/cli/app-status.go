@@ -10,18 +10,20 @@ import (
 	"github.com/replicatedhq/kots/pkg/auth"
 	"github.com/replicatedhq/kots/pkg/k8sutil"
 	"github.com/replicatedhq/kots/pkg/logger"
+	"github.com/replicatedhq/kots/pkg/versionskew"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 )
 
 func AppStatusCmd() *cobra.Command {
 	cmd := &cobra.Command{
-		Use:           "app-status [appSlug]",
-		Short:         "Returns the app status",
-		Long:          ``,
-		SilenceUsage:  true,
-		SilenceErrors: false,
-		Hidden:        true,
+		Use:               "app-status [appSlug]",
+		Short:             "Returns the app status",
+		Long:              ``,
+		ValidArgsFunction: completeAppSlugArg,
+		SilenceUsage:      true,
+		SilenceErrors:     false,
+		Hidden:            true,
 		PreRun: func(cmd *cobra.Command, args []string) {
 			viper.BindPFlags(cmd.Flags())
 		},
@@ -31,12 +33,12 @@ func AppStatusCmd() *cobra.Command {
 			appSlug := v.GetString("slug")
 			// similar to how "download" works, we support flags and args?
 			if appSlug == "" {
-				if len(args) == 1 {
-					appSlug = args[0]
-				} else {
+				slug, err := appSlugArg(v, args, 0)
+				if err != nil {
 					cmd.Help()
-					os.Exit(1)
+					return err
 				}
+				appSlug = slug
 			}
 
 			log := logger.NewCLILogger()
@@ -70,6 +72,12 @@ func AppStatusCmd() *cobra.Command {
 				}
 			}()
 
+			client, err := versionskew.NewClient(fmt.Sprintf("http://localhost:%d", localPort), log)
+			if err != nil {
+				log.FinishSpinnerWithError()
+				return err
+			}
+
 			url := fmt.Sprintf("http://localhost:%d/api/v1/app/%s/status", localPort, appSlug)
 
 			authSlug, err := auth.GetOrCreateAuthSlug(clientset, v.GetString("namespace"))
@@ -77,9 +85,9 @@ func AppStatusCmd() *cobra.Command {
 				log.FinishSpinnerWithError()
 				log.Info("Unable to authenticate to the Admin Console running in the %s namespace. Ensure you have read access to secrets in this namespace and try again.", v.GetString("namespace"))
 				if v.GetBool("debug") {
-					return errors.Wrap(err, "failed to get kotsadm auth slug")
+					return withExitCode(ExitCodeAuthFailed, errors.Wrap(err, "failed to get kotsadm auth slug"))
 				}
-				os.Exit(2) // not returning error here as we don't want to show the entire stack trace to normal users
+				os.Exit(ExitCodeAuthFailed) // not returning error here as we don't want to show the entire stack trace to normal users
 			}
 
 			newReq, err := http.NewRequest("GET", url, nil)
@@ -89,7 +97,7 @@ func AppStatusCmd() *cobra.Command {
 			newReq.Header.Add("Content-Type", "application/json")
 			newReq.Header.Add("Authorization", authSlug)
 
-			resp, err := http.DefaultClient.Do(newReq)
+			resp, err := client.Do(newReq)
 			if err != nil {
 				log.FinishSpinnerWithError()
 				return errors.Wrap(err, "failed to check for updates")
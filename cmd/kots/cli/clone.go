@@ -0,0 +1,119 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/pkg/errors"
+	handlertypes "github.com/replicatedhq/kots/pkg/api/handlers/types"
+	"github.com/replicatedhq/kots/pkg/logger"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+func CloneCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "clone [slug]",
+		Short: "Clone an application to another namespace",
+		Long: `Create a new application in the Admin Console with the upstream, license, registry settings and config values of an existing application.
+The new application is deployed to the target namespace once its config has been confirmed in the Admin Console.
+With --from-snapshot, the new application has the version of the application in the snapshot, the resources and volumes of the application are restored from the snapshot into the target namespace and it is deployed when the restore completes.
+
+Examples:
+kubectl kots clone my-app --name "My App Staging" --target-namespace staging -n default
+kubectl kots clone my-app --name "My App Restore Test" --target-namespace restore-test --from-snapshot my-app-4fmqk -n default`,
+		ValidArgsFunction: completeAppSlugArg,
+		SilenceUsage:      true,
+		SilenceErrors:     false,
+		PreRun: func(cmd *cobra.Command, args []string) {
+			viper.BindPFlags(cmd.Flags())
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			v := viper.GetViper()
+
+			if len(args) != 1 {
+				cmd.Help()
+				return errors.New("app slug is required")
+			}
+			appSlug := args[0]
+
+			if v.GetString("target-namespace") == "" {
+				return errors.New("--target-namespace is required")
+			}
+			name := v.GetString("name")
+			if name == "" {
+				name = fmt.Sprintf("%s-%s", appSlug, v.GetString("target-namespace"))
+			}
+
+			requestBody, err := json.Marshal(handlertypes.CloneAppRequest{
+				Name:      name,
+				Namespace: v.GetString("target-namespace"),
+			})
+			if err != nil {
+				return errors.Wrap(err, "failed to marshal request json")
+			}
+
+			log := logger.NewCLILogger()
+
+			stopCh := make(chan struct{})
+			defer close(stopCh)
+
+			localPort, authSlug, client, err := forwardToAdminConsole(v, stopCh)
+			if err != nil {
+				return err
+			}
+
+			log.ActionWithSpinner("Cloning application %s", appSlug)
+
+			clonePath := "clone"
+			if snapshotName := v.GetString("from-snapshot"); snapshotName != "" {
+				clonePath = fmt.Sprintf("snapshot/restore/%s/clone", url.PathEscape(snapshotName))
+			}
+			url := fmt.Sprintf("http://localhost:%d/api/v1/app/%s/%s", localPort, url.PathEscape(appSlug), clonePath)
+			newRequest, err := http.NewRequest("POST", url, bytes.NewBuffer(requestBody))
+			if err != nil {
+				log.FinishSpinnerWithError()
+				return errors.Wrap(err, "failed to create http request")
+			}
+			newRequest.Header.Add("Authorization", authSlug)
+			newRequest.Header.Add("Content-Type", "application/json")
+
+			resp, err := client.Do(newRequest)
+			if err != nil {
+				log.FinishSpinnerWithError()
+				return errors.Wrap(err, "failed to execute http request")
+			}
+			defer resp.Body.Close()
+
+			if resp.StatusCode != http.StatusCreated {
+				log.FinishSpinnerWithError()
+				return handlertypes.ErrorFromResponse(resp)
+			}
+
+			response := handlertypes.CloneAppResponse{}
+			if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+				log.FinishSpinnerWithError()
+				return errors.Wrap(err, "failed to decode response")
+			}
+
+			log.FinishSpinner()
+			if v.GetString("from-snapshot") != "" {
+				log.ActionWithoutSpinner("Application %s was created. It is deployed to the %s namespace when the restore completes.", response.Slug, response.Namespace)
+				return nil
+			}
+			log.ActionWithoutSpinner("Application %s was created. Confirm its config in the Admin Console to deploy it to the %s namespace.", response.Slug, response.Namespace)
+
+			return nil
+		},
+	}
+
+	cmd.Flags().StringP("namespace", "n", "", "the namespace in which kots/kotsadm is installed")
+	cmd.Flags().String("name", "", "the name of the new application. defaults to the slug of the application and the target namespace")
+	cmd.Flags().String("target-namespace", "", "the namespace to deploy the new application to")
+	cmd.Flags().String("from-snapshot", "", "the name of a snapshot of the application to restore into the new application")
+
+	return cmd
+}
@@ -0,0 +1,286 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/manifoldco/promptui"
+	"github.com/mattn/go-isatty"
+	"github.com/pkg/errors"
+	downstreamtypes "github.com/replicatedhq/kots/pkg/api/downstream/types"
+	"github.com/replicatedhq/kots/pkg/auth"
+	"github.com/replicatedhq/kots/pkg/k8sutil"
+	"github.com/replicatedhq/kots/pkg/logger"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// completionTimeout limits how long a tab completion waits for the cluster
+const completionTimeout = 5 * time.Second
+
+func CompletionCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "completion [bash|zsh|fish|powershell]",
+		Short: "Generate the shell completion script",
+		Long: `Generate the shell completion script for kots. App slugs, namespaces and sequences are completed from the
+cluster and the Admin Console in the namespace, when they can be reached.
+
+Examples:
+source <(kubectl kots completion bash)
+kubectl kots completion zsh > "${fpath[1]}/_kots"
+kubectl kots completion fish > ~/.config/fish/completions/kots.fish`,
+		ValidArgs:             []string{"bash", "zsh", "fish", "powershell"},
+		Args:                  cobra.ExactValidArgs(1),
+		DisableFlagsInUseLine: true,
+		SilenceUsage:          true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			root := cmd.Root()
+			switch args[0] {
+			case "bash":
+				return root.GenBashCompletion(os.Stdout)
+			case "zsh":
+				return root.GenZshCompletion(os.Stdout)
+			case "fish":
+				return root.GenFishCompletion(os.Stdout, true)
+			case "powershell":
+				return root.GenPowerShellCompletion(os.Stdout)
+			}
+			return nil
+		},
+	}
+
+	return cmd
+}
+
+// registerCompletions adds the dynamic completions of the flags that all commands share
+func registerCompletions(root *cobra.Command) {
+	root.RegisterFlagCompletionFunc("namespace", completeNamespaces)
+}
+
+// completeNamespaces completes the namespaces that the admin console runs in, or all namespaces if pods cannot be
+// listed in all of them
+func completeNamespaces(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	clientset, err := completionClientset()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), completionTimeout)
+	defer cancel()
+
+	namespaces := []string{}
+	pods, err := clientset.CoreV1().Pods("").List(ctx, metav1.ListOptions{LabelSelector: "app=kotsadm"})
+	if err == nil {
+		found := map[string]bool{}
+		for _, pod := range pods.Items {
+			if !found[pod.Namespace] {
+				found[pod.Namespace] = true
+				namespaces = append(namespaces, pod.Namespace)
+			}
+		}
+	} else {
+		list, err := clientset.CoreV1().Namespaces().List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		for _, ns := range list.Items {
+			namespaces = append(namespaces, ns.Name)
+		}
+	}
+	sort.Strings(namespaces)
+
+	return namespaces, cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeAppSlugArg completes the app slug for commands that take it as their first arg
+func completeAppSlugArg(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) != 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	return completeAppSlugs(cmd)
+}
+
+func completeAppSlugs(cmd *cobra.Command) ([]string, cobra.ShellCompDirective) {
+	localPort, authSlug, stop, err := completionForward(cmd)
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	defer stop()
+
+	client := &http.Client{Timeout: completionTimeout}
+	apps, err := getApps(client, fmt.Sprintf("http://localhost:%d/api/v1/apps", localPort), authSlug)
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	slugs := []string{}
+	for _, app := range apps.Apps {
+		slugs = append(slugs, fmt.Sprintf("%s\t%s", app.Slug, app.Name))
+	}
+	return slugs, cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeSequences completes the sequences of the versions of the app in appSlug, with their version labels
+func completeSequences(cmd *cobra.Command, appSlug string) ([]string, cobra.ShellCompDirective) {
+	if appSlug == "" {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	localPort, authSlug, stop, err := completionForward(cmd)
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	defer stop()
+
+	versionsURL := fmt.Sprintf("http://localhost:%d/api/v1/app/%s/versions", localPort, url.PathEscape(appSlug))
+	req, err := http.NewRequest("GET", versionsURL, nil)
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	req.Header.Add("Authorization", authSlug)
+
+	client := &http.Client{Timeout: completionTimeout}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	response := struct {
+		VersionHistory []downstreamtypes.DownstreamVersion `json:"versionHistory"`
+	}{}
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	sequences := []string{}
+	for _, version := range response.VersionHistory {
+		sequences = append(sequences, fmt.Sprintf("%d\t%s (%s)", version.Sequence, version.VersionLabel, version.Status))
+	}
+	return sequences, cobra.ShellCompDirectiveNoFileComp
+}
+
+func completionClientset() (*kubernetes.Clientset, error) {
+	cfg, err := k8sutil.GetClusterConfig()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get cluster config")
+	}
+	cfg.Timeout = completionTimeout
+
+	clientset, err := kubernetes.NewForConfig(cfg)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create clientset")
+	}
+	return clientset, nil
+}
+
+// completionForward starts a port forward to the admin console in the namespace of the command line being completed.
+// Nothing is printed, output would be taken as completions.
+func completionForward(cmd *cobra.Command) (int, string, func(), error) {
+	namespace := ""
+	if flag := cmd.Flag("namespace"); flag != nil {
+		namespace = flag.Value.String()
+	}
+	if namespace == "" {
+		namespace = os.Getenv("KOTS_NAMESPACE")
+	}
+	if namespace == "" {
+		return 0, "", nil, errors.New("namespace is required")
+	}
+
+	clientset, err := completionClientset()
+	if err != nil {
+		return 0, "", nil, err
+	}
+
+	podName, err := k8sutil.FindKotsadm(clientset, namespace)
+	if err != nil {
+		return 0, "", nil, errors.Wrap(err, "failed to find kotsadm pod")
+	}
+
+	log := logger.NewCLILogger()
+	log.Silence()
+
+	stopCh := make(chan struct{})
+	localPort, _, err := k8sutil.PortForward(0, 3000, namespace, podName, false, stopCh, log)
+	if err != nil {
+		close(stopCh)
+		return 0, "", nil, errors.Wrap(err, "failed to start port forwarding")
+	}
+	stop := func() { close(stopCh) }
+
+	authSlug, err := auth.GetOrCreateAuthSlug(clientset, namespace)
+	if err != nil {
+		stop()
+		return 0, "", nil, withExitCode(ExitCodeAuthFailed, errors.Wrap(err, "failed to get kotsadm auth slug"))
+	}
+
+	return localPort, authSlug, stop, nil
+}
+
+// appSlugArg returns args[i] if it is set. Otherwise the app is selected from the apps of the admin console in the
+// namespace, prompting for it if there is more than one and the command is run in a terminal.
+func appSlugArg(v *viper.Viper, args []string, i int) (string, error) {
+	if len(args) > i && args[i] != "" {
+		return args[i], nil
+	}
+
+	if v.GetString("namespace") == "" {
+		return "", errors.New("app slug is required")
+	}
+
+	stopCh := make(chan struct{})
+	defer close(stopCh)
+
+	localPort, authSlug, client, err := forwardToAdminConsole(v, stopCh)
+	if err != nil {
+		return "", err
+	}
+
+	apps, err := getApps(client, fmt.Sprintf("http://localhost:%d/api/v1/apps", localPort), authSlug)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to get apps")
+	}
+
+	switch len(apps.Apps) {
+	case 0:
+		return "", errors.Errorf("no apps found in namespace %s", v.GetString("namespace"))
+	case 1:
+		return apps.Apps[0].Slug, nil
+	}
+
+	if !isatty.IsTerminal(os.Stdin.Fd()) || !isatty.IsTerminal(os.Stdout.Fd()) {
+		return "", errors.New("app slug is required")
+	}
+
+	slugs := []string{}
+	for _, app := range apps.Apps {
+		slugs = append(slugs, app.Slug)
+	}
+
+	prompt := promptui.Select{
+		Label: "Select an app",
+		Items: slugs,
+	}
+	_, slug, err := prompt.Run()
+	if err != nil {
+		if err == promptui.ErrInterrupt {
+			os.Exit(-1)
+		}
+		return "", errors.Wrap(err, "failed to select app")
+	}
+
+	return slug, nil
+}
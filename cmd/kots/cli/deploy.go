@@ -0,0 +1,143 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/pkg/errors"
+	handlertypes "github.com/replicatedhq/kots/pkg/api/handlers/types"
+	"github.com/replicatedhq/kots/pkg/downstream"
+	"github.com/replicatedhq/kots/pkg/logger"
+	"github.com/replicatedhq/kots/pkg/print"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+func DeployCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "deploy [appSlug]",
+		Short: "Deploy a version of an application",
+		Long: `Deploy a version of an application from the version history. With --plan the version is not deployed, instead the impact of deploying it is shown: the images that change, the workloads that roll and the estimated pod restarts, and the PVC, CRD and RBAC changes.
+
+Examples:
+kubectl kots deploy my-app --sequence 5 --plan -n default
+kubectl kots deploy my-app --sequence 5 -n default`,
+		ValidArgsFunction: completeAppSlugArg,
+		SilenceUsage:      true,
+		SilenceErrors:     false,
+		PreRun: func(cmd *cobra.Command, args []string) {
+			viper.BindPFlags(cmd.Flags())
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			v := viper.GetViper()
+
+			if len(args) != 1 {
+				cmd.Help()
+				return errors.New("app slug is required")
+			}
+			appSlug := args[0]
+
+			if !cmd.Flags().Changed("sequence") {
+				return errors.New("--sequence is required")
+			}
+			sequence := v.GetInt64("sequence")
+
+			log := logger.NewCLILogger()
+
+			stopCh := make(chan struct{})
+			defer close(stopCh)
+
+			localPort, authSlug, client, err := forwardToAdminConsole(v, stopCh)
+			if err != nil {
+				return err
+			}
+
+			if v.GetBool("plan") {
+				impactURL := fmt.Sprintf("http://localhost:%d/api/v1/app/%s/sequence/%d/impact", localPort, url.PathEscape(appSlug), sequence)
+				report, err := getImpact(client, impactURL, authSlug)
+				if err != nil {
+					return errors.Wrap(err, "failed to get impact")
+				}
+				print.Impact(report, v.GetString("output"))
+				return nil
+			}
+
+			requestBody, err := json.Marshal(map[string]interface{}{
+				"isCli":                    true,
+				"requirePassingPreflights": v.GetBool("require-passing-preflights"),
+			})
+			if err != nil {
+				return errors.Wrap(err, "failed to marshal request json")
+			}
+
+			log.ActionWithSpinner("Deploying version %d of %s", sequence, appSlug)
+
+			deployURL := fmt.Sprintf("http://localhost:%d/api/v1/app/%s/sequence/%d/deploy", localPort, url.PathEscape(appSlug), sequence)
+			newRequest, err := http.NewRequest("POST", deployURL, bytes.NewBuffer(requestBody))
+			if err != nil {
+				log.FinishSpinnerWithError()
+				return errors.Wrap(err, "failed to create http request")
+			}
+			newRequest.Header.Add("Authorization", authSlug)
+			newRequest.Header.Add("Content-Type", "application/json")
+
+			resp, err := client.Do(newRequest)
+			if err != nil {
+				log.FinishSpinnerWithError()
+				return errors.Wrap(err, "failed to execute http request")
+			}
+			defer resp.Body.Close()
+
+			switch resp.StatusCode {
+			case http.StatusNoContent:
+				log.FinishSpinner()
+				log.ActionWithoutSpinner("Version %d of %s is being deployed", sequence, appSlug)
+			case http.StatusAccepted:
+				log.FinishSpinner()
+				log.ActionWithoutSpinner("Version %d of %s is deployed once the deploy is approved", sequence, appSlug)
+			default:
+				log.FinishSpinnerWithError()
+				return handlertypes.ErrorFromResponse(resp)
+			}
+
+			return nil
+		},
+	}
+
+	cmd.Flags().StringP("namespace", "n", "", "the namespace in which kots/kotsadm is installed")
+	cmd.Flags().Int64("sequence", 0, "the sequence of the version to deploy")
+	cmd.Flags().Bool("require-passing-preflights", false, "fail with exit code 5 instead of deploying the version if its preflight checks failed")
+	cmd.Flags().Bool("plan", false, "show the impact of deploying the version compared to the deployed version, without deploying it")
+	cmd.Flags().StringP("output", "o", "", "output format of the plan. supported values: json")
+
+	return cmd
+}
+
+func getImpact(client *http.Client, url string, authSlug string) (*downstream.ImpactReport, error) {
+	newReq, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create request")
+	}
+	newReq.Header.Add("Content-Type", "application/json")
+	newReq.Header.Add("Authorization", authSlug)
+
+	resp, err := client.Do(newReq)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to execute request")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, handlertypes.ErrorFromResponse(resp)
+	}
+
+	report := &downstream.ImpactReport{}
+	if err := json.NewDecoder(resp.Body).Decode(report); err != nil {
+		return nil, errors.Wrap(err, "failed to decode impact")
+	}
+
+	return report, nil
+}
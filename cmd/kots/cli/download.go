@@ -13,11 +13,12 @@ import (
 
 func DownloadCmd() *cobra.Command {
 	cmd := &cobra.Command{
-		Use:           "download [appSlug]",
-		Short:         "Download Kubernetes manifests from your cluster to the local filesystem",
-		Long:          `Download the active Kubernetes manifests from a cluster to the local filesystem so that they can be edited and then reapplied to the cluster with 'kots upload'.`,
-		SilenceUsage:  true,
-		SilenceErrors: false,
+		Use:               "download [appSlug]",
+		Short:             "Download Kubernetes manifests from your cluster to the local filesystem",
+		Long:              `Download the active Kubernetes manifests from a cluster to the local filesystem so that they can be edited and then reapplied to the cluster with 'kots upload'.`,
+		ValidArgsFunction: completeAppSlugArg,
+		SilenceUsage:      true,
+		SilenceErrors:     false,
 		PreRun: func(cmd *cobra.Command, args []string) {
 			viper.BindPFlags(cmd.Flags())
 		},
@@ -26,12 +27,12 @@ func DownloadCmd() *cobra.Command {
 
 			appSlug := v.GetString("slug")
 			if appSlug == "" {
-				if len(args) == 1 {
-					appSlug = args[0]
-				} else {
+				slug, err := appSlugArg(v, args, 0)
+				if err != nil {
 					cmd.Help()
-					os.Exit(1)
+					return err
 				}
+				appSlug = slug
 			}
 
 			downloadOptions := download.DownloadOptions{
@@ -39,6 +40,10 @@ func DownloadCmd() *cobra.Command {
 				Overwrite:             v.GetBool("overwrite"),
 				DecryptPasswordValues: v.GetBool("decrypt-password-values"),
 			}
+			if cmd.Flags().Changed("sequence") {
+				sequence := v.GetInt64("sequence")
+				downloadOptions.Sequence = &sequence
+			}
 
 			downloadPath := filepath.Join(ExpandDir(v.GetString("dest")), appSlug)
 			if err := download.Download(appSlug, downloadPath, downloadOptions); err != nil {
@@ -65,6 +70,18 @@ func DownloadCmd() *cobra.Command {
 	cmd.Flags().Bool("overwrite", false, "overwrite any local files, if present")
 	cmd.Flags().String("slug", "", "the application slug to download")
 	cmd.Flags().Bool("decrypt-password-values", false, "decrypt password values to plaintext")
+	cmd.Flags().Int64("sequence", 0, "the sequence of the version to download, defaults to the latest version")
+
+	cmd.RegisterFlagCompletionFunc("slug", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return completeAppSlugs(cmd)
+	})
+	cmd.RegisterFlagCompletionFunc("sequence", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		appSlug, _ := cmd.Flags().GetString("slug")
+		if appSlug == "" && len(args) > 0 {
+			appSlug = args[0]
+		}
+		return completeSequences(cmd, appSlug)
+	})
 
 	return cmd
 }
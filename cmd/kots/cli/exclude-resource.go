@@ -0,0 +1,183 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+
+	"github.com/pkg/errors"
+	handlertypes "github.com/replicatedhq/kots/pkg/api/handlers/types"
+	"github.com/replicatedhq/kots/pkg/auth"
+	"github.com/replicatedhq/kots/pkg/downstream"
+	"github.com/replicatedhq/kots/pkg/k8sutil"
+	"github.com/replicatedhq/kots/pkg/logger"
+	"github.com/replicatedhq/kots/pkg/upload"
+	"github.com/replicatedhq/kots/pkg/versionskew"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+func ExcludeResourceCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "resource [appSlug]",
+		Short: "Exclude a rendered resource from being deployed to a downstream",
+		Long: `Creates a new app version that does not deploy the resource to the downstream.
+The exclusion is kept in the downstream kustomization and applies to future versions until it is removed with --remove.
+
+Examples:
+kubectl kots exclude resource my-app --kind Ingress --name my-app-ingress
+kubectl kots exclude resource my-app --kind Ingress --name my-app-ingress --remove`,
+		ValidArgsFunction: completeAppSlugArg,
+		SilenceUsage:      true,
+		SilenceErrors:     false,
+		PreRun: func(cmd *cobra.Command, args []string) {
+			viper.BindPFlags(cmd.Flags())
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			v := viper.GetViper()
+
+			if len(args) == 0 {
+				cmd.Help()
+				os.Exit(1)
+			}
+
+			appSlug := args[0]
+
+			if v.GetString("kind") == "" || v.GetString("name") == "" {
+				return errors.New("--kind and --name are required")
+			}
+
+			log := logger.NewCLILogger()
+			if v.GetBool("remove") {
+				log.ActionWithSpinner("Removing resource exclusion")
+			} else {
+				log.ActionWithSpinner("Excluding resource")
+			}
+
+			stopCh := make(chan struct{})
+			defer close(stopCh)
+			localPort, errChan, err := upload.StartPortForward(v.GetString("namespace"), stopCh, log)
+			if err != nil {
+				log.FinishSpinnerWithError()
+				return err
+			}
+
+			go func() {
+				select {
+				case err := <-errChan:
+					if err != nil {
+						log.Error(err)
+					}
+				case <-stopCh:
+				}
+			}()
+
+			client, err := versionskew.NewClient(fmt.Sprintf("http://localhost:%d", localPort), log)
+			if err != nil {
+				log.FinishSpinnerWithError()
+				return err
+			}
+
+			clientset, err := k8sutil.GetClientset()
+			if err != nil {
+				log.FinishSpinnerWithError()
+				return errors.Wrap(err, "failed to get k8s clientset")
+			}
+
+			authSlug, err := auth.GetOrCreateAuthSlug(clientset, v.GetString("namespace"))
+			if err != nil {
+				log.FinishSpinnerWithError()
+				log.Info("Unable to authenticate to the Admin Console running in the %s namespace. Ensure you have read access to secrets in this namespace and try again.", v.GetString("namespace"))
+				if v.GetBool("debug") {
+					return withExitCode(ExitCodeAuthFailed, errors.Wrap(err, "failed to get kotsadm auth slug"))
+				}
+				os.Exit(ExitCodeAuthFailed) // not returning error here as we don't want to show the entire stack trace to normal users
+			}
+
+			requestBody, err := json.Marshal(map[string]interface{}{
+				"downstream": v.GetString("downstream"),
+				"resource": downstream.ResourceExclusion{
+					Kind:      v.GetString("kind"),
+					Name:      v.GetString("name"),
+					Namespace: v.GetString("resource-namespace"),
+				},
+				"skipPreflights": v.GetBool("skip-preflights"),
+				"deploy":         v.GetBool("deploy"),
+			})
+			if err != nil {
+				log.FinishSpinnerWithError()
+				return errors.Wrap(err, "failed to marshal request")
+			}
+
+			method := "POST"
+			if v.GetBool("remove") {
+				method = "DELETE"
+			}
+			exclusionsURI := fmt.Sprintf("http://localhost:%d/api/v1/app/%s/exclusions", localPort, url.PathEscape(appSlug))
+
+			newReq, err := http.NewRequest(method, exclusionsURI, bytes.NewReader(requestBody))
+			if err != nil {
+				log.FinishSpinnerWithError()
+				return errors.Wrap(err, "failed to create request")
+			}
+			newReq.Header.Add("Content-Type", "application/json")
+			newReq.Header.Add("Authorization", authSlug)
+			resp, err := client.Do(newReq)
+			if err != nil {
+				log.FinishSpinnerWithError()
+				return errors.Wrap(err, "failed to update resource exclusions")
+			}
+			defer resp.Body.Close()
+
+			if resp.StatusCode != http.StatusOK {
+				log.FinishSpinnerWithError()
+				return handlertypes.ErrorFromResponse(resp)
+			}
+
+			type exclusionsResponse struct {
+				Downstream string                         `json:"downstream"`
+				Exclusions []downstream.ResourceExclusion `json:"exclusions"`
+				Sequence   int64                          `json:"sequence"`
+			}
+			er := exclusionsResponse{}
+			if err := json.NewDecoder(resp.Body).Decode(&er); err != nil {
+				log.FinishSpinnerWithError()
+				return errors.Wrap(err, "failed to parse response")
+			}
+
+			log.FinishSpinner()
+
+			log.ActionWithoutSpinner("")
+			if er.Sequence == -1 {
+				log.ActionWithoutSpinner("Resource exclusions for downstream %s are unchanged", er.Downstream)
+			} else if v.GetBool("deploy") {
+				log.ActionWithoutSpinner("Deploying version %d to downstream %s", er.Sequence, er.Downstream)
+			} else {
+				log.ActionWithoutSpinner("Created version %d for downstream %s", er.Sequence, er.Downstream)
+			}
+			for _, exclusion := range er.Exclusions {
+				if exclusion.Namespace != "" {
+					log.ActionWithoutSpinner("  excluded %s %s in namespace %s", exclusion.Kind, exclusion.Name, exclusion.Namespace)
+				} else {
+					log.ActionWithoutSpinner("  excluded %s %s", exclusion.Kind, exclusion.Name)
+				}
+			}
+			log.ActionWithoutSpinner("")
+
+			return nil
+		},
+	}
+
+	cmd.Flags().String("kind", "", "kind of the resource to exclude")
+	cmd.Flags().String("name", "", "name of the resource to exclude")
+	cmd.Flags().String("resource-namespace", "", "namespace of the resource to exclude, matches any namespace if not set")
+	cmd.Flags().String("downstream", "", "name of the downstream, defaults to the first downstream of the app")
+	cmd.Flags().Bool("remove", false, "remove the exclusion so that the resource is deployed again")
+	cmd.Flags().Bool("deploy", false, "deploy the new version after it is created")
+	cmd.Flags().Bool("skip-preflights", false, "set to true to skip preflight checks")
+
+	return cmd
+}
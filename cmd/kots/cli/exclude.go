@@ -0,0 +1,33 @@
+package cli
+
+import (
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+func ExcludeCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:           "exclude",
+		Short:         "Exclude rendered resources from being deployed",
+		Long:          ``,
+		SilenceUsage:  true,
+		SilenceErrors: false,
+		PreRun: func(cmd *cobra.Command, args []string) {
+			viper.BindPFlags(cmd.Flags())
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) == 0 {
+				cmd.Help()
+				os.Exit(1)
+			}
+
+			return nil
+		},
+	}
+
+	cmd.AddCommand(ExcludeResourceCmd())
+
+	return cmd
+}
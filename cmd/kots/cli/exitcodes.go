@@ -0,0 +1,107 @@
+package cli
+
+import (
+	"context"
+	goerrors "errors"
+	"net"
+
+	"github.com/pkg/errors"
+	handlertypes "github.com/replicatedhq/kots/pkg/api/handlers/types"
+	kotsadmtypes "github.com/replicatedhq/kots/pkg/kotsadm/types"
+	"k8s.io/client-go/kubernetes"
+)
+
+// Exit codes of kots commands, so that scripts can tell the class of a failure without parsing the error message.
+// The codes are part of the interface of the cli, existing codes must not change.
+const (
+	// ExitCodeError is returned for failures that do not have a more specific code
+	ExitCodeError = 1
+	// ExitCodeAuthFailed is returned when the cli cannot authenticate to the admin console
+	ExitCodeAuthFailed = 2
+	// ExitCodeClusterUnreachable is returned when the kubernetes api cannot be reached
+	ExitCodeClusterUnreachable = 3
+	// ExitCodeLicenseInvalid is returned when a license cannot be parsed, is not signed or is expired
+	ExitCodeLicenseInvalid = 4
+	// ExitCodePreflightsFailed is returned when preflight checks of a version failed
+	ExitCodePreflightsFailed = 5
+	// ExitCodeTimeout is returned when waiting for a component or a request timed out
+	ExitCodeTimeout = 6
+)
+
+const exitCodesHelp = `Exit codes:
+  0  success
+  1  error without a more specific exit code
+  2  authentication to the admin console failed
+  3  the kubernetes cluster could not be reached
+  4  the license is invalid or expired
+  5  preflight checks failed
+  6  timed out`
+
+// exitError is an error with the exit code that the cli exits with when a command fails with it
+type exitError struct {
+	code int
+	err  error
+}
+
+func (e exitError) Error() string {
+	return e.err.Error()
+}
+
+func (e exitError) Unwrap() error {
+	return e.err
+}
+
+// withExitCode sets the exit code of the cli for the error, unless the error already has one
+func withExitCode(code int, err error) error {
+	if err == nil {
+		return nil
+	}
+	var existing exitError
+	if goerrors.As(err, &existing) {
+		return err
+	}
+	return exitError{code: code, err: err}
+}
+
+// exitCodeFromError returns the exit code for an error returned by a command
+func exitCodeFromError(err error) int {
+	if err == nil {
+		return 0
+	}
+
+	var e exitError
+	if goerrors.As(err, &e) {
+		return e.code
+	}
+
+	var apiErr *handlertypes.APIError
+	if goerrors.As(err, &apiErr) {
+		switch apiErr.Code {
+		case handlertypes.ErrorCodeUnauthorized, handlertypes.ErrorCodeSessionExpired:
+			return ExitCodeAuthFailed
+		case handlertypes.ErrorCodePreflightsFailed:
+			return ExitCodePreflightsFailed
+		}
+	}
+
+	if _, ok := errors.Cause(err).(*kotsadmtypes.ErrorTimeout); ok {
+		return ExitCodeTimeout
+	}
+	if goerrors.Is(err, context.DeadlineExceeded) {
+		return ExitCodeTimeout
+	}
+	var netErr net.Error
+	if goerrors.As(err, &netErr) && netErr.Timeout() {
+		return ExitCodeTimeout
+	}
+
+	return ExitCodeError
+}
+
+// checkClusterReachable returns an error with ExitCodeClusterUnreachable if the kubernetes api cannot be reached
+func checkClusterReachable(clientset kubernetes.Interface) error {
+	if _, err := clientset.Discovery().ServerVersion(); err != nil {
+		return withExitCode(ExitCodeClusterUnreachable, errors.Wrap(err, "failed to reach the kubernetes api"))
+	}
+	return nil
+}
@@ -0,0 +1,65 @@
+package cli
+
+import (
+	"context"
+	"testing"
+
+	"github.com/pkg/errors"
+	handlertypes "github.com/replicatedhq/kots/pkg/api/handlers/types"
+	kotsadmtypes "github.com/replicatedhq/kots/pkg/kotsadm/types"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_exitCodeFromError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{
+			name: "no error",
+			err:  nil,
+			want: 0,
+		},
+		{
+			name: "unclassified error",
+			err:  errors.New("failed"),
+			want: ExitCodeError,
+		},
+		{
+			name: "wrapped exit code",
+			err:  errors.Wrap(withExitCode(ExitCodeLicenseInvalid, errors.New("bad signature")), "failed to get license"),
+			want: ExitCodeLicenseInvalid,
+		},
+		{
+			name: "first exit code is kept",
+			err:  withExitCode(ExitCodeTimeout, withExitCode(ExitCodeClusterUnreachable, errors.New("i/o timeout"))),
+			want: ExitCodeClusterUnreachable,
+		},
+		{
+			name: "failed preflights from the api",
+			err:  errors.Wrap(&handlertypes.APIError{StatusCode: 409, Code: handlertypes.ErrorCodePreflightsFailed}, "failed to deploy"),
+			want: ExitCodePreflightsFailed,
+		},
+		{
+			name: "unauthorized from the api",
+			err:  &handlertypes.APIError{StatusCode: 401, Code: handlertypes.ErrorCodeUnauthorized},
+			want: ExitCodeAuthFailed,
+		},
+		{
+			name: "timeout waiting for kotsadm",
+			err:  errors.Wrap(&kotsadmtypes.ErrorTimeout{}, "failed to wait for web"),
+			want: ExitCodeTimeout,
+		},
+		{
+			name: "context deadline",
+			err:  errors.Wrap(context.DeadlineExceeded, "failed to wait"),
+			want: ExitCodeTimeout,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			require.Equal(t, test.want, exitCodeFromError(test.err))
+		})
+	}
+}
@@ -0,0 +1,180 @@
+package cli
+
+import (
+	"io/ioutil"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/replicatedhq/kots/pkg/identity"
+	"github.com/replicatedhq/kots/pkg/ingress"
+	"github.com/replicatedhq/kots/pkg/k8sutil"
+	"github.com/replicatedhq/kots/pkg/kotsadm"
+	"github.com/replicatedhq/kots/pkg/logger"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+func ExposeCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "expose",
+		Short: "Expose the Admin Console through an ingress, load balancer or node port",
+		Long: `Make the Admin Console reachable without "kubectl kots admin-console" port forwarding, by creating an ingress or
+changing the type of the kotsadm service. The settings are kept when the Admin Console is upgraded.
+
+Examples:
+kubectl kots expose -n default --type ingress --hostname admin.example.com --tls-cert cert.pem --tls-key key.pem
+kubectl kots expose -n default --type loadbalancer
+kubectl kots expose -n default --type nodeport --node-port 30880
+kubectl kots expose -n default --remove`,
+		SilenceUsage:  true,
+		SilenceErrors: false,
+		PreRun: func(cmd *cobra.Command, args []string) {
+			viper.BindPFlags(cmd.Flags())
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			v := viper.GetViper()
+
+			log := logger.NewCLILogger()
+
+			namespace := v.GetString("namespace")
+			if err := validateNamespace(namespace); err != nil {
+				return err
+			}
+
+			clientset, err := k8sutil.GetClientset()
+			if err != nil {
+				return errors.Wrap(err, "failed to get clientset")
+			}
+
+			if v.GetBool("remove") {
+				log.ChildActionWithSpinner("Removing external access to the Admin Console")
+
+				if err := kotsadm.Unexpose(cmd.Context(), clientset, namespace); err != nil {
+					log.FinishSpinnerWithError()
+					return errors.Wrap(err, "failed to remove external access")
+				}
+
+				log.FinishSpinner()
+				log.ActionWithoutSpinner("The Admin Console can be reached with: kubectl kots admin-console -n %s", namespace)
+				return nil
+			}
+
+			opts, err := getExposeOptions(v, namespace)
+			if err != nil {
+				return err
+			}
+
+			identityConfig, err := identity.GetConfig(cmd.Context(), namespace)
+			if err != nil {
+				return errors.Wrap(err, "failed to get identity config")
+			}
+
+			log.ChildActionWithSpinner("Exposing the Admin Console with a %s", strings.ToLower(string(opts.Type)))
+
+			address, err := kotsadm.Expose(cmd.Context(), clientset, *opts)
+			if err != nil {
+				log.FinishSpinnerWithError()
+				return errors.Wrap(err, "failed to expose the admin console")
+			}
+
+			log.FinishSpinner()
+
+			if identityConfig.Spec.Enabled {
+				log.ChildActionWithSpinner("Configuring the Identity Service")
+
+				ingressConfig, err := ingress.GetConfig(cmd.Context(), namespace)
+				if err != nil {
+					log.FinishSpinnerWithError()
+					return errors.Wrap(err, "failed to get ingress config")
+				}
+
+				// the redirect uri of the identity service depends on the address of the admin console
+				if err := identity.Configure(cmd.Context(), clientset, namespace, *identityConfig, *ingressConfig, getHttpProxyEnv(v), v.GetBool("identity-apply-app-branding")); err != nil {
+					log.FinishSpinnerWithError()
+					return errors.Wrap(err, "failed to patch identity service")
+				}
+
+				log.FinishSpinner()
+			}
+
+			log.ActionWithoutSpinner("The Admin Console is available at %s", address)
+
+			return nil
+		},
+	}
+
+	cmd.Flags().String("type", "ingress", "how to expose the Admin Console: ingress, loadbalancer or nodeport")
+	cmd.Flags().String("hostname", "", "the hostname of the ingress rule. the address of the ingress controller is used if not set")
+	cmd.Flags().String("path", "/", "the path of the ingress rule")
+	cmd.Flags().StringToString("ingress-annotations", map[string]string{}, "annotations to add to the ingress, e.g. kubernetes.io/ingress.class=nginx")
+	cmd.Flags().String("tls-secret-name", "", "the name of an existing tls secret for the ingress")
+	cmd.Flags().String("tls-cert", "", "path to a PEM encoded certificate for the ingress")
+	cmd.Flags().String("tls-key", "", "path to a PEM encoded private key for the ingress")
+	cmd.Flags().Int("node-port", 0, "the node port of the kotsadm service. a free port is assigned if not set")
+	cmd.Flags().Duration("wait-duration", 2*time.Minute, "how long to wait for the address of the load balancer")
+	cmd.Flags().Bool("remove", false, "remove the ingress and change the kotsadm service back to ClusterIP")
+
+	cmd.Flags().String("http-proxy", "", "sets HTTP_PROXY environment variable in KOTS Identity Service components")
+	cmd.Flags().String("https-proxy", "", "sets HTTPS_PROXY environment variable in KOTS Identity Service components")
+	cmd.Flags().String("no-proxy", "", "sets NO_PROXY environment variable in KOTS Identity Service components")
+	cmd.Flags().Bool("copy-proxy-env", false, "copy proxy environment variables from current environment into KOTS Identity Service components")
+	cmd.Flags().Bool("identity-apply-app-branding", false, "apply app branding to the identity login screen")
+
+	return cmd
+}
+
+func getExposeOptions(v *viper.Viper, namespace string) (*kotsadm.ExposeOptions, error) {
+	opts := kotsadm.ExposeOptions{
+		Namespace:     namespace,
+		Hostname:      v.GetString("hostname"),
+		Path:          v.GetString("path"),
+		Annotations:   v.GetStringMapString("ingress-annotations"),
+		TLSSecretName: v.GetString("tls-secret-name"),
+		NodePort:      v.GetInt("node-port"),
+		Timeout:       v.GetDuration("wait-duration"),
+	}
+
+	switch strings.ToLower(v.GetString("type")) {
+	case "ingress":
+		opts.Type = kotsadm.ExposeIngress
+	case "loadbalancer":
+		opts.Type = kotsadm.ExposeLoadBalancer
+	case "nodeport":
+		opts.Type = kotsadm.ExposeNodePort
+	default:
+		return nil, errors.Errorf("unsupported type %q, must be one of ingress, loadbalancer or nodeport", v.GetString("type"))
+	}
+
+	hasTLSFiles := v.GetString("tls-cert") != "" || v.GetString("tls-key") != ""
+	if opts.Type != kotsadm.ExposeIngress {
+		if hasTLSFiles || opts.TLSSecretName != "" || opts.Hostname != "" {
+			return nil, errors.New("--hostname and tls flags are only supported with --type ingress")
+		}
+	}
+	if opts.Type != kotsadm.ExposeNodePort && opts.NodePort != 0 {
+		return nil, errors.New("--node-port is only supported with --type nodeport")
+	}
+
+	if hasTLSFiles {
+		if opts.TLSSecretName != "" {
+			return nil, errors.New("only one of --tls-secret-name and --tls-cert can be set")
+		}
+		if v.GetString("tls-cert") == "" || v.GetString("tls-key") == "" {
+			return nil, errors.New("--tls-cert and --tls-key must be set together")
+		}
+
+		cert, err := ioutil.ReadFile(v.GetString("tls-cert"))
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to read tls cert")
+		}
+		key, err := ioutil.ReadFile(v.GetString("tls-key"))
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to read tls key")
+		}
+		opts.TLSCert = cert
+		opts.TLSKey = key
+	}
+
+	return &opts, nil
+}
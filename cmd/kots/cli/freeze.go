@@ -0,0 +1,177 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/pkg/errors"
+	handlertypes "github.com/replicatedhq/kots/pkg/api/handlers/types"
+	"github.com/replicatedhq/kots/pkg/logger"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+type setFreezeRequest struct {
+	Reason    string     `json:"reason"`
+	ExpiresAt *time.Time `json:"expiresAt"`
+}
+
+func FreezeCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "freeze [appSlug]",
+		Short: "Block the deploys of an application, or of all applications",
+		Long: `Block all deploys, manual and automatic, of an application, or of all applications when no application is given, for example during a change freeze. New versions are still downloaded and their preflight checks run, so that they can be deployed once the freeze has ended. The freeze lasts until it expires or is lifted with kots unfreeze.
+
+Examples:
+kubectl kots freeze my-app --expires-in 72h --reason "holiday change freeze" -n default
+kubectl kots freeze --expires-at 2022-01-03T09:00:00Z -n default`,
+		ValidArgsFunction: completeAppSlugArg,
+		SilenceUsage:      true,
+		SilenceErrors:     false,
+		PreRun: func(cmd *cobra.Command, args []string) {
+			viper.BindPFlags(cmd.Flags())
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			v := viper.GetViper()
+
+			if len(args) > 1 {
+				cmd.Help()
+				return errors.New("only one app slug can be given")
+			}
+
+			request := setFreezeRequest{
+				Reason: v.GetString("reason"),
+			}
+
+			expiresIn := v.GetDuration("expires-in")
+			expiresAt := v.GetString("expires-at")
+			if expiresIn != 0 && expiresAt != "" {
+				return errors.New("only one of --expires-in and --expires-at can be set")
+			}
+			if expiresIn < 0 {
+				return errors.New("--expires-in must be positive")
+			}
+			if expiresIn > 0 {
+				t := time.Now().Add(expiresIn)
+				request.ExpiresAt = &t
+			}
+			if expiresAt != "" {
+				t, err := time.Parse(time.RFC3339, expiresAt)
+				if err != nil {
+					return errors.Wrap(err, "failed to parse --expires-at")
+				}
+				request.ExpiresAt = &t
+			}
+
+			requestBody, err := json.Marshal(request)
+			if err != nil {
+				return errors.Wrap(err, "failed to marshal request json")
+			}
+
+			if err := doFreezeRequest(v, "PUT", args, requestBody, http.StatusOK); err != nil {
+				return err
+			}
+
+			log := logger.NewCLILogger()
+			target := "all applications"
+			if len(args) == 1 {
+				target = args[0]
+			}
+			if request.ExpiresAt != nil {
+				log.ActionWithoutSpinner("Deploys of %s are frozen until %s", target, request.ExpiresAt.UTC().Format(time.RFC3339))
+			} else {
+				log.ActionWithoutSpinner("Deploys of %s are frozen until lifted with kots unfreeze", target)
+			}
+
+			return nil
+		},
+	}
+
+	cmd.Flags().StringP("namespace", "n", "", "the namespace in which kots/kotsadm is installed")
+	cmd.Flags().String("reason", "", "the reason for the freeze, shown when a deploy is blocked")
+	cmd.Flags().Duration("expires-in", 0, "how long the freeze lasts, for example 72h. the freeze lasts until it is lifted if neither this nor --expires-at is set")
+	cmd.Flags().String("expires-at", "", "the RFC 3339 time at which the freeze ends")
+
+	return cmd
+}
+
+func UnfreezeCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "unfreeze [appSlug]",
+		Short: "Lift the deploy freeze of an application, or of all applications",
+		Long: `Lift the deploy freeze of an application, or the freeze of all applications when no application is given. Lifting the freeze of an application does not lift a freeze of all applications.
+
+Examples:
+kubectl kots unfreeze my-app -n default`,
+		ValidArgsFunction: completeAppSlugArg,
+		SilenceUsage:      true,
+		SilenceErrors:     false,
+		PreRun: func(cmd *cobra.Command, args []string) {
+			viper.BindPFlags(cmd.Flags())
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			v := viper.GetViper()
+
+			if len(args) > 1 {
+				cmd.Help()
+				return errors.New("only one app slug can be given")
+			}
+
+			if err := doFreezeRequest(v, "DELETE", args, nil, http.StatusNoContent); err != nil {
+				return err
+			}
+
+			log := logger.NewCLILogger()
+			if len(args) == 1 {
+				log.ActionWithoutSpinner("Deploys of %s are no longer frozen", args[0])
+			} else {
+				log.ActionWithoutSpinner("Deploys of all applications are no longer frozen")
+			}
+
+			return nil
+		},
+	}
+
+	cmd.Flags().StringP("namespace", "n", "", "the namespace in which kots/kotsadm is installed")
+
+	return cmd
+}
+
+// doFreezeRequest sends a request to the freeze api of the app in args, or to the global freeze api if no app is given
+func doFreezeRequest(v *viper.Viper, method string, args []string, body []byte, expectStatus int) error {
+	stopCh := make(chan struct{})
+	defer close(stopCh)
+
+	localPort, authSlug, client, err := forwardToAdminConsole(v, stopCh)
+	if err != nil {
+		return err
+	}
+
+	requestURL := fmt.Sprintf("http://localhost:%d/api/v1/freeze", localPort)
+	if len(args) == 1 {
+		requestURL = fmt.Sprintf("http://localhost:%d/api/v1/app/%s/freeze", localPort, url.PathEscape(args[0]))
+	}
+
+	newRequest, err := http.NewRequest(method, requestURL, bytes.NewBuffer(body))
+	if err != nil {
+		return errors.Wrap(err, "failed to create http request")
+	}
+	newRequest.Header.Add("Authorization", authSlug)
+	newRequest.Header.Add("Content-Type", "application/json")
+
+	resp, err := client.Do(newRequest)
+	if err != nil {
+		return errors.Wrap(err, "failed to execute http request")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != expectStatus {
+		return handlertypes.ErrorFromResponse(resp)
+	}
+
+	return nil
+}
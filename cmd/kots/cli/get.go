@@ -7,17 +7,26 @@ import (
 	"fmt"
 	"io/ioutil"
 	"net/http"
+	"net/url"
+	"strings"
 
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 
+	downstreamtypes "github.com/replicatedhq/kots/pkg/api/downstream/types"
 	handlertypes "github.com/replicatedhq/kots/pkg/api/handlers/types"
+	appusagetypes "github.com/replicatedhq/kots/pkg/appusage/types"
 	"github.com/replicatedhq/kots/pkg/auth"
+	"github.com/replicatedhq/kots/pkg/downstream"
+	fleetreporttypes "github.com/replicatedhq/kots/pkg/fleetreport/types"
+	imagereporttypes "github.com/replicatedhq/kots/pkg/imagereport/types"
 	"github.com/replicatedhq/kots/pkg/k8sutil"
 	"github.com/replicatedhq/kots/pkg/logger"
 	"github.com/replicatedhq/kots/pkg/print"
 	"github.com/replicatedhq/kots/pkg/snapshot"
+	"github.com/replicatedhq/kots/pkg/upload"
+	"github.com/replicatedhq/kots/pkg/versionskew"
 )
 
 func GetCmd() *cobra.Command {
@@ -25,15 +34,26 @@ func GetCmd() *cobra.Command {
 		Use:   "get [resource]",
 		Short: "Display kots resources",
 		Long: `Examples:
-kubectl kots get apps`,
-
-		SilenceUsage:  true,
-		SilenceErrors: false,
+kubectl kots get apps
+kubectl kots get apps -l environment=production
+kubectl kots get versions my-app --limit 20 --status deployed,failed
+kubectl kots get manifests my-app --sequence 3 --kind Deployment
+kubectl kots get images my-app --sequence 3
+kubectl kots get prometheus
+kubectl kots get config my-app --sequence 3 --decrypt
+kubectl kots get fleet-report -o json
+kubectl kots get usage my-app
+kubectl kots get scheduled-jobs
+kubectl kots get deploy-history my-app --from 2021-01-01 --to 2021-03-31 --format csv`,
+
+		ValidArgsFunction: completeGetArgs,
+		SilenceUsage:      true,
+		SilenceErrors:     false,
 		PreRun: func(cmd *cobra.Command, args []string) {
 			viper.BindPFlags(cmd.Flags())
 		},
 		RunE: func(cmd *cobra.Command, args []string) error {
-			if len(args) != 1 {
+			if len(args) < 1 {
 				cmd.Help()
 				os.Exit(1)
 			}
@@ -48,6 +68,33 @@ kubectl kots get apps`,
 			case "app", "apps":
 				err := getAppsCmd(cmd, args)
 				return errors.Wrap(err, "failed to get apps")
+			case "manifest", "manifests":
+				err := getManifestsCmd(cmd, args)
+				return errors.Wrap(err, "failed to get manifests")
+			case "image", "images":
+				err := getImagesCmd(cmd, args)
+				return errors.Wrap(err, "failed to get images")
+			case "prometheus":
+				err := getPrometheusCmd(cmd, args)
+				return errors.Wrap(err, "failed to get prometheus settings")
+			case "config":
+				err := getConfigCmd(cmd, args)
+				return errors.Wrap(err, "failed to get config values")
+			case "version", "versions":
+				err := getVersionsCmd(cmd, args)
+				return errors.Wrap(err, "failed to get versions")
+			case "fleet-report":
+				err := getFleetReportCmd(cmd, args)
+				return errors.Wrap(err, "failed to get fleet report")
+			case "usage":
+				err := getUsageCmd(cmd, args)
+				return errors.Wrap(err, "failed to get resource usage")
+			case "scheduled-job", "scheduled-jobs":
+				err := getScheduledJobsCmd(cmd, args)
+				return errors.Wrap(err, "failed to get scheduled jobs")
+			case "deploy-history":
+				err := getDeployHistoryCmd(cmd, args)
+				return errors.Wrap(err, "failed to get deploy history")
 			default:
 				cmd.Help()
 				os.Exit(1)
@@ -58,10 +105,44 @@ kubectl kots get apps`,
 	}
 
 	cmd.Flags().StringP("output", "o", "", "output format. supported values: json")
+	cmd.Flags().Int64("sequence", -1, "app version sequence to get manifests, images or config values for")
+	cmd.Flags().String("kind", "", "only get manifests of this kind")
+	cmd.Flags().String("name", "", "only get manifests with this name")
+	cmd.Flags().Bool("include-secrets", false, "include the values of secrets in the manifests")
+	cmd.Flags().Bool("refresh", false, "resolve image digests again instead of showing the stored report")
+	cmd.Flags().Int("limit", 0, "maximum number of versions to get, all versions are returned if not set")
+	cmd.Flags().Int("offset", 0, "number of newest versions to skip")
+	cmd.Flags().StringSlice("status", []string{}, "only get versions with these statuses")
+	cmd.Flags().StringSlice("source", []string{}, "only get versions from these sources, e.g. \"Upstream Update\"")
+	cmd.RegisterFlagCompletionFunc("sequence", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) < 2 {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		return completeSequences(cmd, args[1])
+	})
+	cmd.Flags().String("from", "", "only get deploys at or after this date (YYYY-MM-DD) or RFC3339 time")
+	cmd.Flags().String("to", "", "only get deploys before this RFC3339 time, or up to the end of this date (YYYY-MM-DD)")
+	cmd.Flags().String("format", "", "format of the deploy history. supported values: csv, json")
+	cmd.Flags().StringP("selector", "l", "", "only get apps whose labels match this selector, e.g. environment=production")
+	cmd.Flags().Bool("decrypt", false, "decrypt the values of password config items. requires a role that can read decrypted config values, and the request is audit logged")
 
 	return cmd
 }
 
+// completeGetArgs completes the resource types, and the app slug for the resources of an app
+func completeGetArgs(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	switch len(args) {
+	case 0:
+		return []string{"apps", "backups", "config", "deploy-history", "fleet-report", "images", "manifests", "prometheus", "restores", "scheduled-jobs", "usage", "versions"}, cobra.ShellCompDirectiveNoFileComp
+	case 1:
+		switch args[0] {
+		case "manifest", "manifests", "image", "images", "config", "version", "versions", "usage", "deploy-history":
+			return completeAppSlugs(cmd)
+		}
+	}
+	return nil, cobra.ShellCompDirectiveNoFileComp
+}
+
 func getBackupsCmd(cmd *cobra.Command, args []string) error {
 	v := viper.GetViper()
 
@@ -133,18 +214,28 @@ func getAppsCmd(cmd *cobra.Command, args []string) error {
 		}
 	}()
 
+	client, err := versionskew.NewClient(fmt.Sprintf("http://localhost:%d", localPort), log)
+	if err != nil {
+		log.FinishSpinnerWithError()
+		return err
+	}
+
 	authSlug, err := auth.GetOrCreateAuthSlug(clientset, namespace)
 	if err != nil {
 		log.FinishSpinnerWithError()
 		log.Info("Unable to authenticate to the Admin Console running in the %s namespace. Ensure you have read access to secrets in this namespace and try again.", namespace)
 		if v.GetBool("debug") {
-			return errors.Wrap(err, "failed to get kotsadm auth slug")
+			return withExitCode(ExitCodeAuthFailed, errors.Wrap(err, "failed to get kotsadm auth slug"))
 		}
-		os.Exit(2) // not returning error here as we don't want to show the entire stack trace to normal users
+		os.Exit(ExitCodeAuthFailed) // not returning error here as we don't want to show the entire stack trace to normal users
 	}
 
-	url := fmt.Sprintf("http://localhost:%d/api/v1/apps", localPort)
-	apps, err := getApps(url, authSlug)
+	urlVals := url.Values{}
+	if selector := v.GetString("selector"); selector != "" {
+		urlVals.Set("labelSelector", selector)
+	}
+	appsURL := fmt.Sprintf("http://localhost:%d/api/v1/apps?%s", localPort, urlVals.Encode())
+	apps, err := getApps(client, appsURL, authSlug)
 	if err != nil {
 		return errors.Wrap(err, "failed to get apps")
 	}
@@ -152,13 +243,14 @@ func getAppsCmd(cmd *cobra.Command, args []string) error {
 	printableApps := make([]print.App, 0)
 	for _, app := range apps.Apps {
 		url := fmt.Sprintf("http://localhost:%d/api/v1/app/%s/status", localPort, app.Slug)
-		appStatus, err := getAppStatus(url, authSlug)
+		appStatus, err := getAppStatus(client, url, authSlug)
 		if err != nil {
 			return errors.Wrapf(err, "failed to get app status for %s", app.Slug)
 		}
 		printableApps = append(printableApps, print.App{
-			Slug:  app.Slug,
-			State: string(appStatus.AppStatus.State),
+			Slug:   app.Slug,
+			State:  string(appStatus.AppStatus.State),
+			Labels: app.Labels,
 		})
 	}
 
@@ -167,7 +259,7 @@ func getAppsCmd(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
-func getApps(url string, authSlug string) (*handlertypes.ListAppsResponse, error) {
+func getApps(client *http.Client, url string, authSlug string) (*handlertypes.ListAppsResponse, error) {
 	newReq, err := http.NewRequest("GET", url, nil)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to create request")
@@ -175,12 +267,16 @@ func getApps(url string, authSlug string) (*handlertypes.ListAppsResponse, error
 	newReq.Header.Add("Content-Type", "application/json")
 	newReq.Header.Add("Authorization", authSlug)
 
-	resp, err := http.DefaultClient.Do(newReq)
+	resp, err := client.Do(newReq)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to execute request")
 	}
 	defer resp.Body.Close()
 
+	if resp.StatusCode != http.StatusOK {
+		return nil, handlertypes.ErrorFromResponse(resp)
+	}
+
 	b, err := ioutil.ReadAll(resp.Body)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to read")
@@ -194,7 +290,7 @@ func getApps(url string, authSlug string) (*handlertypes.ListAppsResponse, error
 	return apps, nil
 }
 
-func getAppStatus(url string, authSlug string) (*handlertypes.AppStatusResponse, error) {
+func getAppStatus(client *http.Client, url string, authSlug string) (*handlertypes.AppStatusResponse, error) {
 	newReq, err := http.NewRequest("GET", url, nil)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to create request")
@@ -202,7 +298,7 @@ func getAppStatus(url string, authSlug string) (*handlertypes.AppStatusResponse,
 	newReq.Header.Add("Content-Type", "application/json")
 	newReq.Header.Add("Authorization", authSlug)
 
-	resp, err := http.DefaultClient.Do(newReq)
+	resp, err := client.Do(newReq)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to execute request")
 	}
@@ -220,3 +316,550 @@ func getAppStatus(url string, authSlug string) (*handlertypes.AppStatusResponse,
 
 	return status, nil
 }
+
+func getManifestsCmd(cmd *cobra.Command, args []string) error {
+	v := viper.GetViper()
+
+	appSlug, err := appSlugArg(v, args, 1)
+	if err != nil {
+		return err
+	}
+
+	sequence := v.GetInt64("sequence")
+	if sequence < 0 {
+		return errors.New("--sequence is required")
+	}
+
+	stopCh := make(chan struct{})
+	defer close(stopCh)
+
+	localPort, authSlug, client, err := forwardToAdminConsole(v, stopCh)
+	if err != nil {
+		return err
+	}
+
+	urlVals := url.Values{}
+	if kind := v.GetString("kind"); kind != "" {
+		urlVals.Set("kind", kind)
+	}
+	if name := v.GetString("name"); name != "" {
+		urlVals.Set("name", name)
+	}
+	if v.GetBool("include-secrets") {
+		urlVals.Set("includeSecrets", "true")
+	}
+	manifestsURL := fmt.Sprintf("http://localhost:%d/api/v1/app/%s/sequence/%d/manifests?%s", localPort, url.PathEscape(appSlug), sequence, urlVals.Encode())
+
+	manifests, err := getManifests(client, manifestsURL, authSlug)
+	if err != nil {
+		return errors.Wrap(err, "failed to get manifests")
+	}
+
+	print.Manifests(manifests, v.GetString("output"))
+
+	return nil
+}
+
+func getManifests(client *http.Client, url string, authSlug string) ([]downstream.RenderedManifest, error) {
+	newReq, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create request")
+	}
+	newReq.Header.Add("Content-Type", "application/json")
+	newReq.Header.Add("Authorization", authSlug)
+
+	resp, err := client.Do(newReq)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to execute request")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, handlertypes.ErrorFromResponse(resp)
+	}
+
+	b, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read")
+	}
+
+	response := struct {
+		Manifests []downstream.RenderedManifest `json:"manifests"`
+	}{}
+	if err := json.Unmarshal(b, &response); err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal manifests")
+	}
+
+	return response.Manifests, nil
+}
+
+func getImagesCmd(cmd *cobra.Command, args []string) error {
+	v := viper.GetViper()
+
+	appSlug, err := appSlugArg(v, args, 1)
+	if err != nil {
+		return err
+	}
+
+	sequence := v.GetInt64("sequence")
+	if sequence < 0 {
+		return errors.New("--sequence is required")
+	}
+
+	stopCh := make(chan struct{})
+	defer close(stopCh)
+
+	localPort, authSlug, client, err := forwardToAdminConsole(v, stopCh)
+	if err != nil {
+		return err
+	}
+
+	urlVals := url.Values{}
+	if v.GetBool("refresh") {
+		urlVals.Set("refresh", "true")
+	}
+	imagesURL := fmt.Sprintf("http://localhost:%d/api/v1/app/%s/sequence/%d/images?%s", localPort, url.PathEscape(appSlug), sequence, urlVals.Encode())
+
+	newReq, err := http.NewRequest("GET", imagesURL, nil)
+	if err != nil {
+		return errors.Wrap(err, "failed to create request")
+	}
+	newReq.Header.Add("Content-Type", "application/json")
+	newReq.Header.Add("Authorization", authSlug)
+
+	resp, err := client.Do(newReq)
+	if err != nil {
+		return errors.Wrap(err, "failed to execute request")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return handlertypes.ErrorFromResponse(resp)
+	}
+
+	report := imagereporttypes.Report{}
+	if err := json.NewDecoder(resp.Body).Decode(&report); err != nil {
+		return errors.Wrap(err, "failed to unmarshal image report")
+	}
+
+	print.ImageReport(&report, v.GetString("output"))
+
+	return nil
+}
+
+// forwardToAdminConsole starts a port forward to the admin console and returns the local port, an auth slug and a client for the api.
+// The port forward is stopped when stopCh is closed.
+func forwardToAdminConsole(v *viper.Viper, stopCh chan struct{}) (int, string, *http.Client, error) {
+	log := logger.NewCLILogger()
+
+	clientset, err := k8sutil.GetClientset()
+	if err != nil {
+		return 0, "", nil, withExitCode(ExitCodeClusterUnreachable, errors.Wrap(err, "failed to get clientset"))
+	}
+	if err := checkClusterReachable(clientset); err != nil {
+		return 0, "", nil, err
+	}
+
+	namespace := v.GetString("namespace")
+	if err := validateNamespace(namespace); err != nil {
+		return 0, "", nil, errors.Wrap(err, "failed to validate namespace")
+	}
+
+	localPort, errChan, err := upload.StartPortForward(namespace, stopCh, log)
+	if err != nil {
+		return 0, "", nil, errors.Wrap(err, "failed to start port forwarding")
+	}
+
+	go func() {
+		select {
+		case err := <-errChan:
+			if err != nil {
+				log.Error(err)
+			}
+		case <-stopCh:
+		}
+	}()
+
+	client, err := versionskew.NewClient(fmt.Sprintf("http://localhost:%d", localPort), log)
+	if err != nil {
+		return 0, "", nil, err
+	}
+
+	authSlug, err := auth.GetOrCreateAuthSlug(clientset, namespace)
+	if err != nil {
+		log.Info("Unable to authenticate to the Admin Console running in the %s namespace. Ensure you have read access to secrets in this namespace and try again.", namespace)
+		if v.GetBool("debug") {
+			return 0, "", nil, withExitCode(ExitCodeAuthFailed, errors.Wrap(err, "failed to get kotsadm auth slug"))
+		}
+		os.Exit(ExitCodeAuthFailed) // not returning error here as we don't want to show the entire stack trace to normal users
+	}
+
+	return localPort, authSlug, client, nil
+}
+
+func getPrometheusCmd(cmd *cobra.Command, args []string) error {
+	v := viper.GetViper()
+
+	log := logger.NewCLILogger()
+
+	stopCh := make(chan struct{})
+	defer close(stopCh)
+
+	clientset, err := k8sutil.GetClientset()
+	if err != nil {
+		return errors.Wrap(err, "failed to get clientset")
+	}
+
+	namespace := v.GetString("namespace")
+	if err := validateNamespace(namespace); err != nil {
+		return errors.Wrap(err, "failed to validate namespace")
+	}
+
+	podName, err := k8sutil.FindKotsadm(clientset, namespace)
+	if err != nil {
+		return errors.Wrap(err, "failed to find kotsadm pod")
+	}
+
+	localPort, errChan, err := k8sutil.PortForward(0, 3000, namespace, podName, false, stopCh, log)
+	if err != nil {
+		return errors.Wrap(err, "failed to start port forwarding")
+	}
+
+	go func() {
+		select {
+		case err := <-errChan:
+			if err != nil {
+				log.Error(err)
+			}
+		case <-stopCh:
+		}
+	}()
+
+	client, err := versionskew.NewClient(fmt.Sprintf("http://localhost:%d", localPort), log)
+	if err != nil {
+		return err
+	}
+
+	authSlug, err := auth.GetOrCreateAuthSlug(clientset, namespace)
+	if err != nil {
+		return withExitCode(ExitCodeAuthFailed, errors.Wrap(err, "failed to get kotsadm auth slug"))
+	}
+
+	newReq, err := http.NewRequest("GET", fmt.Sprintf("http://localhost:%d/api/v1/prometheus", localPort), nil)
+	if err != nil {
+		return errors.Wrap(err, "failed to create request")
+	}
+	newReq.Header.Add("Authorization", authSlug)
+
+	resp, err := client.Do(newReq)
+	if err != nil {
+		return errors.Wrap(err, "failed to execute request")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return handlertypes.ErrorFromResponse(resp)
+	}
+
+	b, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return errors.Wrap(err, "failed to read response body")
+	}
+
+	settings := handlertypes.GetPrometheusSettingsResponse{}
+	if err := json.Unmarshal(b, &settings); err != nil {
+		return errors.Wrap(err, "failed to unmarshal response")
+	}
+
+	print.PrometheusSettings(settings, v.GetString("output"))
+
+	return nil
+}
+
+func getConfigCmd(cmd *cobra.Command, args []string) error {
+	v := viper.GetViper()
+
+	appSlug, err := appSlugArg(v, args, 1)
+	if err != nil {
+		return err
+	}
+
+	stopCh := make(chan struct{})
+	defer close(stopCh)
+
+	localPort, authSlug, client, err := forwardToAdminConsole(v, stopCh)
+	if err != nil {
+		return err
+	}
+
+	urlVals := url.Values{}
+	if sequence := v.GetInt64("sequence"); sequence >= 0 {
+		urlVals.Set("sequence", fmt.Sprintf("%d", sequence))
+	}
+	configValuesURL := fmt.Sprintf("http://localhost:%d/api/v1/app/%s/configvalues", localPort, url.PathEscape(appSlug))
+	if v.GetBool("decrypt") {
+		configValuesURL += "/decrypted"
+	}
+	configValuesURL += "?" + urlVals.Encode()
+
+	newReq, err := http.NewRequest("GET", configValuesURL, nil)
+	if err != nil {
+		return errors.Wrap(err, "failed to create request")
+	}
+	newReq.Header.Add("Content-Type", "application/json")
+	newReq.Header.Add("Authorization", authSlug)
+
+	resp, err := client.Do(newReq)
+	if err != nil {
+		return errors.Wrap(err, "failed to execute request")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return handlertypes.ErrorFromResponse(resp)
+	}
+
+	response := handlertypes.GetAppConfigValuesResponse{}
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return errors.Wrap(err, "failed to unmarshal config values")
+	}
+
+	fmt.Print(response.ConfigValues)
+
+	return nil
+}
+
+func getVersionsCmd(cmd *cobra.Command, args []string) error {
+	v := viper.GetViper()
+
+	appSlug, err := appSlugArg(v, args, 1)
+	if err != nil {
+		return err
+	}
+
+	stopCh := make(chan struct{})
+	defer close(stopCh)
+
+	localPort, authSlug, client, err := forwardToAdminConsole(v, stopCh)
+	if err != nil {
+		return err
+	}
+
+	urlVals := url.Values{}
+	if limit := v.GetInt("limit"); limit > 0 {
+		urlVals.Set("limit", fmt.Sprintf("%d", limit))
+	}
+	if offset := v.GetInt("offset"); offset > 0 {
+		urlVals.Set("offset", fmt.Sprintf("%d", offset))
+	}
+	if statuses := v.GetStringSlice("status"); len(statuses) > 0 {
+		urlVals.Set("status", strings.Join(statuses, ","))
+	}
+	if sources := v.GetStringSlice("source"); len(sources) > 0 {
+		urlVals.Set("source", strings.Join(sources, ","))
+	}
+	versionsURL := fmt.Sprintf("http://localhost:%d/api/v1/app/%s/versions?%s", localPort, url.PathEscape(appSlug), urlVals.Encode())
+
+	newReq, err := http.NewRequest("GET", versionsURL, nil)
+	if err != nil {
+		return errors.Wrap(err, "failed to create request")
+	}
+	newReq.Header.Add("Content-Type", "application/json")
+	newReq.Header.Add("Authorization", authSlug)
+
+	resp, err := client.Do(newReq)
+	if err != nil {
+		return errors.Wrap(err, "failed to execute request")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return handlertypes.ErrorFromResponse(resp)
+	}
+
+	response := struct {
+		VersionHistory []downstreamtypes.DownstreamVersion `json:"versionHistory"`
+		TotalCount     int64                               `json:"totalCount"`
+	}{}
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return errors.Wrap(err, "failed to unmarshal versions")
+	}
+
+	print.Versions(response.VersionHistory, response.TotalCount, v.GetString("output"))
+
+	return nil
+}
+
+func getFleetReportCmd(cmd *cobra.Command, args []string) error {
+	v := viper.GetViper()
+
+	stopCh := make(chan struct{})
+	defer close(stopCh)
+
+	localPort, authSlug, client, err := forwardToAdminConsole(v, stopCh)
+	if err != nil {
+		return err
+	}
+
+	newReq, err := http.NewRequest("GET", fmt.Sprintf("http://localhost:%d/api/v1/fleet-report", localPort), nil)
+	if err != nil {
+		return errors.Wrap(err, "failed to create request")
+	}
+	newReq.Header.Add("Content-Type", "application/json")
+	newReq.Header.Add("Authorization", authSlug)
+
+	resp, err := client.Do(newReq)
+	if err != nil {
+		return errors.Wrap(err, "failed to execute request")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return handlertypes.ErrorFromResponse(resp)
+	}
+
+	report := fleetreporttypes.Report{}
+	if err := json.NewDecoder(resp.Body).Decode(&report); err != nil {
+		return errors.Wrap(err, "failed to unmarshal fleet report")
+	}
+
+	print.FleetReport(&report, v.GetString("output"))
+
+	return nil
+}
+
+func getUsageCmd(cmd *cobra.Command, args []string) error {
+	v := viper.GetViper()
+
+	appSlug, err := appSlugArg(v, args, 1)
+	if err != nil {
+		return err
+	}
+
+	stopCh := make(chan struct{})
+	defer close(stopCh)
+
+	localPort, authSlug, client, err := forwardToAdminConsole(v, stopCh)
+	if err != nil {
+		return err
+	}
+
+	newReq, err := http.NewRequest("GET", fmt.Sprintf("http://localhost:%d/api/v1/app/%s/usage", localPort, url.PathEscape(appSlug)), nil)
+	if err != nil {
+		return errors.Wrap(err, "failed to create request")
+	}
+	newReq.Header.Add("Content-Type", "application/json")
+	newReq.Header.Add("Authorization", authSlug)
+
+	resp, err := client.Do(newReq)
+	if err != nil {
+		return errors.Wrap(err, "failed to execute request")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return handlertypes.ErrorFromResponse(resp)
+	}
+
+	report := appusagetypes.Report{}
+	if err := json.NewDecoder(resp.Body).Decode(&report); err != nil {
+		return errors.Wrap(err, "failed to unmarshal resource usage")
+	}
+
+	print.AppUsage(&report, v.GetString("output"))
+
+	return nil
+}
+
+func getScheduledJobsCmd(cmd *cobra.Command, args []string) error {
+	v := viper.GetViper()
+
+	stopCh := make(chan struct{})
+	defer close(stopCh)
+
+	localPort, authSlug, client, err := forwardToAdminConsole(v, stopCh)
+	if err != nil {
+		return err
+	}
+
+	newReq, err := http.NewRequest("GET", fmt.Sprintf("http://localhost:%d/api/v1/scheduled-jobs", localPort), nil)
+	if err != nil {
+		return errors.Wrap(err, "failed to create request")
+	}
+	newReq.Header.Add("Content-Type", "application/json")
+	newReq.Header.Add("Authorization", authSlug)
+
+	resp, err := client.Do(newReq)
+	if err != nil {
+		return errors.Wrap(err, "failed to execute request")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return handlertypes.ErrorFromResponse(resp)
+	}
+
+	response := handlertypes.ListScheduledJobsResponse{}
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return errors.Wrap(err, "failed to unmarshal scheduled jobs")
+	}
+
+	print.ScheduledJobs(response.Jobs, v.GetString("output"))
+
+	return nil
+}
+
+func getDeployHistoryCmd(cmd *cobra.Command, args []string) error {
+	v := viper.GetViper()
+
+	format := v.GetString("format")
+	if format == "" {
+		format = v.GetString("output")
+	}
+	if format != "" && format != "csv" && format != "json" {
+		return errors.Errorf("unsupported format %q", format)
+	}
+
+	urlVals := url.Values{}
+	if len(args) > 1 {
+		urlVals.Set("appSlug", args[1])
+	}
+	if from := v.GetString("from"); from != "" {
+		urlVals.Set("from", from)
+	}
+	if to := v.GetString("to"); to != "" {
+		urlVals.Set("to", to)
+	}
+
+	stopCh := make(chan struct{})
+	defer close(stopCh)
+
+	localPort, authSlug, client, err := forwardToAdminConsole(v, stopCh)
+	if err != nil {
+		return err
+	}
+
+	newReq, err := http.NewRequest("GET", fmt.Sprintf("http://localhost:%d/api/v1/deploy-history?%s", localPort, urlVals.Encode()), nil)
+	if err != nil {
+		return errors.Wrap(err, "failed to create request")
+	}
+	newReq.Header.Add("Content-Type", "application/json")
+	newReq.Header.Add("Authorization", authSlug)
+
+	resp, err := client.Do(newReq)
+	if err != nil {
+		return errors.Wrap(err, "failed to execute request")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return handlertypes.ErrorFromResponse(resp)
+	}
+
+	response := handlertypes.GetDeployHistoryResponse{}
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return errors.Wrap(err, "failed to unmarshal deploy history")
+	}
+
+	return print.DeployHistory(response.Events, format)
+}
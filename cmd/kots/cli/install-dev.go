@@ -0,0 +1,134 @@
+package cli
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/replicatedhq/kots/pkg/kotsutil"
+	"github.com/replicatedhq/kots/pkg/logger"
+	"github.com/replicatedhq/kots/pkg/pull"
+	"github.com/replicatedhq/kots/pkg/upload"
+	"github.com/replicatedhq/kots/pkg/upstream"
+	upstreamtypes "github.com/replicatedhq/kots/pkg/upstream/types"
+)
+
+const devModePollInterval = 2 * time.Second
+
+type devModeOptions struct {
+	UpstreamURI    string
+	Namespace      string
+	AppName        string
+	APIEndpoint    string
+	Deploy         bool
+	SkipPreflights bool
+}
+
+// localUpstreamURI returns the file:// upstream uri of a local directory of manifests
+func localUpstreamURI(dir string) (string, error) {
+	absDir, err := filepath.Abs(ExpandDir(dir))
+	if err != nil {
+		return "", errors.Wrap(err, "failed to get absolute path")
+	}
+
+	info, err := os.Stat(absDir)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to stat directory")
+	}
+	if !info.IsDir() {
+		return "", errors.Errorf("%s is not a directory", dir)
+	}
+
+	return fmt.Sprintf("file://%s", filepath.ToSlash(absDir)), nil
+}
+
+// runDevMode creates the app from the local directory, then watches the directory and creates a new version
+// each time the files change until interrupted
+func runDevMode(opts devModeOptions, log *logger.CLILogger) error {
+	signalChan := make(chan os.Signal, 1)
+	signal.Notify(signalChan, os.Interrupt)
+	defer signal.Stop(signalChan)
+
+	ticker := time.NewTicker(devModePollInterval)
+	defer ticker.Stop()
+
+	appSlug := ""
+	currentCursor := ""
+	for {
+		updates, err := upstream.GetUpdatesUpstream(opts.UpstreamURI, &upstreamtypes.FetchOptions{
+			CurrentCursor: currentCursor,
+		})
+		if err != nil {
+			// the directory can be briefly missing while an editor or a build tool replaces it
+			log.Error(errors.Wrap(err, "failed to check for changes"))
+		} else if len(updates) > 0 {
+			if appSlug == "" {
+				log.ActionWithoutSpinner("Creating application from %s", opts.UpstreamURI)
+			} else {
+				log.ActionWithoutSpinner("Files changed, creating a new version")
+			}
+
+			slug, cursor, err := uploadDevVersion(opts, appSlug)
+			if err != nil {
+				log.Error(errors.Wrap(err, "failed to create version"))
+			} else {
+				appSlug = slug
+				currentCursor = cursor
+			}
+		}
+
+		select {
+		case <-signalChan:
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// uploadDevVersion pulls the local directory and uploads it to the Admin Console as a new app, or as a new
+// version of the app if appSlug is set. It returns the slug of the app and the cursor of the version.
+func uploadDevVersion(opts devModeOptions, appSlug string) (string, string, error) {
+	rootDir, err := ioutil.TempDir("", "kots-dev")
+	if err != nil {
+		return "", "", errors.Wrap(err, "failed to create temp dir")
+	}
+	defer os.RemoveAll(rootDir)
+
+	pullOptions := pull.PullOptions{
+		RootDir:             rootDir,
+		Namespace:           opts.Namespace,
+		AppSlug:             appSlug,
+		ExcludeAdminConsole: true,
+		CreateAppDir:        false,
+		Silent:              true,
+	}
+	if _, err := pull.Pull(opts.UpstreamURI, pullOptions); err != nil {
+		return "", "", errors.Wrap(err, "failed to pull")
+	}
+
+	// the files can change after the check, so the version is the cursor of the files that were pulled
+	kotsKinds, err := kotsutil.LoadKotsKindsFromPath(rootDir)
+	if err != nil {
+		return "", "", errors.Wrap(err, "failed to load kotskinds")
+	}
+
+	uploadOptions := upload.UploadOptions{
+		Namespace:       opts.Namespace,
+		UpstreamURI:     opts.UpstreamURI,
+		ExistingAppSlug: appSlug,
+		NewAppName:      opts.AppName,
+		Endpoint:        opts.APIEndpoint,
+		Deploy:          opts.Deploy,
+		SkipPreflights:  opts.SkipPreflights,
+	}
+	slug, err := upload.Upload(rootDir, uploadOptions)
+	if err != nil {
+		return "", "", errors.Wrap(err, "failed to upload")
+	}
+
+	return slug, kotsKinds.Installation.Spec.UpdateCursor, nil
+}
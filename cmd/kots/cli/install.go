@@ -21,15 +21,26 @@ import (
 	"github.com/pkg/errors"
 	kotsv1beta1 "github.com/replicatedhq/kots/kotskinds/apis/kots/v1beta1"
 	"github.com/replicatedhq/kots/pkg/auth"
+	"github.com/replicatedhq/kots/pkg/cabundle"
+	"github.com/replicatedhq/kots/pkg/cors"
+	"github.com/replicatedhq/kots/pkg/downstream"
+	"github.com/replicatedhq/kots/pkg/endpoints"
+	"github.com/replicatedhq/kots/pkg/hostaliases"
 	"github.com/replicatedhq/kots/pkg/identity"
+	"github.com/replicatedhq/kots/pkg/image"
 	"github.com/replicatedhq/kots/pkg/k8sutil"
 	"github.com/replicatedhq/kots/pkg/kotsadm"
 	"github.com/replicatedhq/kots/pkg/kotsadm/types"
 	kotsadmtypes "github.com/replicatedhq/kots/pkg/kotsadm/types"
 	"github.com/replicatedhq/kots/pkg/kotsutil"
+	kotslicense "github.com/replicatedhq/kots/pkg/license"
 	"github.com/replicatedhq/kots/pkg/logger"
 	"github.com/replicatedhq/kots/pkg/metrics"
+	"github.com/replicatedhq/kots/pkg/objectstore"
+	"github.com/replicatedhq/kots/pkg/print"
+	"github.com/replicatedhq/kots/pkg/proxyauth"
 	"github.com/replicatedhq/kots/pkg/pull"
+	"github.com/replicatedhq/kots/pkg/storageprofile"
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
 	"github.com/spf13/viper"
@@ -69,9 +80,55 @@ func InstallCmd() *cobra.Command {
 				}
 			}
 
+			var caBundle []byte
+			if caBundlePath := v.GetString("ca-bundle"); caBundlePath != "" {
+				b, err := ioutil.ReadFile(caBundlePath)
+				if err != nil {
+					return errors.Wrap(err, "failed to read ca bundle")
+				}
+				caBundle = b
+				// trust the bundle for the requests made by the cli during the install too
+				if err := cabundle.Load(caBundle); err != nil {
+					return errors.Wrap(err, "failed to load ca bundle")
+				}
+			}
+
+			hostAliasValues, _ := cmd.Flags().GetStringArray("host-alias")
+			hostAliases, err := hostaliases.Parse(hostAliasValues)
+			if err != nil {
+				return errors.Wrap(err, "failed to parse host aliases")
+			}
+			// resolve the aliased hosts for the requests made by the cli during the install too
+			hostaliases.Load(hostAliases)
+
+			if v.GetString("proxy-username") != "" {
+				proxyCredentials := &proxyauth.Credentials{
+					Scheme:   v.GetString("proxy-auth-scheme"),
+					Username: v.GetString("proxy-username"),
+					Password: v.GetString("proxy-password"),
+				}
+				// authenticate to the proxy for the requests made by the cli during the install too
+				if err := proxyauth.Load(proxyCredentials); err != nil {
+					return errors.Wrap(err, "failed to load proxy credentials")
+				}
+			}
+
 			license, err := getLicense(v)
 			if err != nil {
-				return errors.Wrap(err, "failed to get license")
+				return withExitCode(ExitCodeLicenseInvalid, errors.Wrap(err, "failed to get license"))
+			}
+			if license != nil {
+				// show the license before anything is deployed, so that the wrong license file is caught early
+				summary, err := kotslicense.Summarize(license, time.Now())
+				if err != nil {
+					return errors.Wrap(err, "failed to summarize license")
+				}
+				log.ActionWithoutSpinner("Installing with the license of %s", summary.CustomerName)
+				print.License(summary, "")
+				log.ActionWithoutSpinner("")
+				if summary.IsExpired {
+					return withExitCode(ExitCodeLicenseInvalid, errors.Errorf("the license expired at %s", summary.ExpiresAt.Format(time.RFC3339)))
+				}
 			}
 
 			registryConfig, err := getRegistryConfig(v)
@@ -99,6 +156,13 @@ func InstallCmd() *cobra.Command {
 			}()
 
 			upstream := pull.RewriteUpstream(args[0])
+			if v.GetBool("dev") {
+				localUpstream, err := localUpstreamURI(args[0])
+				if err != nil {
+					return errors.Wrap(err, "failed to get local upstream")
+				}
+				upstream = localUpstream
+			}
 
 			namespace := v.GetString("namespace")
 
@@ -115,7 +179,12 @@ func InstallCmd() *cobra.Command {
 			}
 
 			var applicationMetadata []byte
-			if airgapBundle := v.GetString("airgap-bundle"); airgapBundle != "" {
+			if appMetadata := v.GetString("app-metadata"); appMetadata != "" {
+				applicationMetadata, err = pull.GetAppMetadataFromFile(ExpandDir(appMetadata))
+				if err != nil {
+					return errors.Wrapf(err, "failed to get metadata from %s", appMetadata)
+				}
+			} else if airgapBundle := v.GetString("airgap-bundle"); airgapBundle != "" {
 				applicationMetadata, err = pull.GetAppMetadataFromAirgap(airgapBundle)
 				if err != nil {
 					return errors.Wrapf(err, "failed to get metadata from %s", airgapBundle)
@@ -123,7 +192,7 @@ func InstallCmd() *cobra.Command {
 			} else if !v.GetBool("airgap") {
 				applicationMetadata, err = pull.PullApplicationMetadata(upstream)
 				if err != nil {
-					log.Info("Unable to pull application metadata. This can be ignored, but custom branding will not be available in the Admin Console until a license is installed.")
+					log.Info("Unable to pull application metadata. This can be ignored, but custom branding will not be available in the Admin Console until a license is installed. Use --app-metadata to brand the Admin Console from an airgap bundle.")
 				}
 			}
 
@@ -137,6 +206,16 @@ func InstallCmd() *cobra.Command {
 				configValues = parsedConfigValues
 			}
 
+			var additionalManifests map[string][]byte
+			if dir := v.GetString("additional-manifests"); dir != "" {
+				manifests, err := downstream.ReadAdditionalManifestsFromDir(ExpandDir(dir))
+				if err != nil {
+					return errors.Wrap(err, "failed to read additional manifests")
+				}
+
+				additionalManifests = manifests
+			}
+
 			// alpha enablement here
 			// if deploy minio is set and there's no storage base uri, set it
 			// this is likely not going to be the final state of how this is configured
@@ -147,6 +226,18 @@ func InstallCmd() *cobra.Command {
 				}
 			}
 
+			if err := objectstore.ValidateURI(v.GetString("storage-base-uri")); err != nil {
+				return errors.Wrap(err, "invalid storage base uri")
+			}
+			// gcs buckets and azure blob containers replace minio
+			if objectstore.IsExternal(v.GetString("storage-base-uri")) && !cmd.Flags().Changed("with-minio") {
+				v.Set("with-minio", false)
+			}
+			gcsServiceAccount, azureAccountKey, err := getStorageCredentials(v)
+			if err != nil {
+				return errors.Wrap(err, "failed to get storage credentials")
+			}
+
 			isKurl, err := kotsadm.IsKurl()
 			if err != nil {
 				return errors.Wrap(err, "failed to check kURL")
@@ -172,6 +263,38 @@ func InstallCmd() *cobra.Command {
 
 			simultaneousUploads, _ := strconv.Atoi(v.GetString("airgap-upload-parallelism"))
 
+			bandwidthLimit, err := image.ParseBandwidthLimit(v.GetString("bandwidth-limit"))
+			if err != nil {
+				return errors.Wrap(err, "failed to parse bandwidth limit")
+			}
+
+			sessionTTL, sessionIdleTimeout, sessionReauthWindow, err := getSessionDurations(v)
+			if err != nil {
+				return errors.Wrap(err, "failed to parse session flags")
+			}
+
+			corsAllowedOrigins, err := cors.ParseOrigins(strings.Join(v.GetStringSlice("cors-allowed-origins"), ","))
+			if err != nil {
+				return errors.Wrap(err, "failed to parse --cors-allowed-origins")
+			}
+
+			endpointMirrors := endpoints.Mirrors{
+				ReplicatedAPI: v.GetStringSlice("replicated-api-mirror"),
+				Registry:      v.GetStringSlice("replicated-registry-mirror"),
+				Proxy:         v.GetStringSlice("replicated-proxy-mirror"),
+			}
+			for _, mirror := range endpointMirrors.ReplicatedAPI {
+				if u, err := url.Parse(mirror); err != nil || u.Scheme == "" || u.Host == "" {
+					return errors.Errorf("invalid --replicated-api-mirror %q, expected a url like https://replicated-app.example.com", mirror)
+				}
+			}
+
+			switch serviceType := v.GetString("service-type"); serviceType {
+			case "", "ClusterIP", "NodePort", "LoadBalancer":
+			default:
+				return errors.Errorf("unsupported service type %q", serviceType)
+			}
+
 			deployOptions := kotsadmtypes.DeployOptions{
 				Namespace:                 namespace,
 				Context:                   v.GetString("context"),
@@ -180,22 +303,47 @@ func InstallCmd() *cobra.Command {
 				UpstreamURI:               upstream,
 				License:                   license,
 				ConfigValues:              configValues,
+				AdditionalManifests:       additionalManifests,
 				Airgap:                    isAirgap,
 				ProgressWriter:            os.Stdout,
 				StorageBaseURI:            v.GetString("storage-base-uri"),
 				StorageBaseURIPlainHTTP:   v.GetBool("storage-base-uri-plainhttp"),
+				StorageGCSServiceAccount:  gcsServiceAccount,
+				StorageAzureAccountKey:    azureAccountKey,
 				IncludeMinio:              v.GetBool("with-minio"),
 				IncludeDockerDistribution: v.GetBool("with-dockerdistribution"),
 				Timeout:                   time.Minute * 2,
 				HTTPProxyEnvValue:         v.GetString("http-proxy"),
 				HTTPSProxyEnvValue:        v.GetString("https-proxy"),
 				NoProxyEnvValue:           v.GetString("no-proxy"),
+				ProxyAuthScheme:           v.GetString("proxy-auth-scheme"),
+				ProxyUsername:             v.GetString("proxy-username"),
+				ProxyPassword:             v.GetString("proxy-password"),
 				SkipPreflights:            v.GetBool("skip-preflights"),
 				EnsureRBAC:                v.GetBool("ensure-rbac"),
 				InstallID:                 m.InstallID,
 				SimultaneousUploads:       simultaneousUploads,
+				ImagePushWorkers:          v.GetInt("image-push-workers"),
 				DisableImagePush:          v.GetBool("disable-image-push"),
+				AllImageArchitectures:     v.GetBool("all-architectures"),
+				ImagePushBandwidthLimit:   bandwidthLimit,
+				EnableNetworkPolicies:     v.GetBool("enable-network-policies"),
+				CABundle:                  caBundle,
 				AirgapBundle:              v.GetString("airgap-bundle"),
+				ServiceType:               v.GetString("service-type"),
+				ForcePasswordUpdate:       v.GetBool("force-password-update"),
+				ReadOnlyConsole:           v.GetBool("read-only-console"),
+				Headless:                  v.GetBool("headless"),
+				SessionTTL:                sessionTTL,
+				SessionIdleTimeout:        sessionIdleTimeout,
+				SessionReauthWindow:       sessionReauthWindow,
+				CORSAllowedOrigins:        corsAllowedOrigins,
+				EndpointMirrors:           endpointMirrors,
+				HostAliases:               hostAliases,
+				PodSecurityProfile:        kotsadmtypes.PodSecurityProfile(v.GetString("pod-security-profile")),
+
+				StorageAzureIdentityBinding: v.GetString("storage-azure-identity-binding"),
+				ServiceAccountAnnotations:   v.GetStringMapString("service-account-annotations"),
 
 				KotsadmOptions: *registryConfig,
 
@@ -205,10 +353,15 @@ func InstallCmd() *cobra.Command {
 
 			clientset, err := k8sutil.GetClientset()
 			if err != nil {
-				return errors.Wrap(err, "failed to get clientset")
+				return withExitCode(ExitCodeClusterUnreachable, errors.Wrap(err, "failed to get clientset"))
+			}
+			if err := checkClusterReachable(clientset); err != nil {
+				return err
 			}
 			deployOptions.IsOpenShift = k8sutil.IsOpenShift(clientset)
 
+			printStorageProfile(clientset, log)
+
 			timeout, err := time.ParseDuration(v.GetString("wait-duration"))
 			if err != nil {
 				return errors.Wrap(err, "failed to parse timeout value")
@@ -256,12 +409,47 @@ func InstallCmd() *cobra.Command {
 				deployOptions.AirgapRootDir = airgapRootDir
 			}
 
-			log.ActionWithoutSpinner("Deploying Admin Console")
-			if err := kotsadm.Deploy(deployOptions); err != nil {
-				if _, ok := errors.Cause(err).(*types.ErrorTimeout); ok {
-					return errors.Errorf("Failed to deploy: %s. Use the --wait-duration flag to increase timeout.", err)
+			if sharedPassword != "" && !deployOptions.ForcePasswordUpdate && !deployOptions.ExcludeAdminConsole {
+				isPasswordSet, err := kotsadm.IsSharedPasswordSet(namespace, clientset)
+				if err != nil {
+					return errors.Wrap(err, "failed to check admin console password")
+				}
+				if isPasswordSet {
+					log.Info("The Admin Console password is already set, --shared-password is ignored. Use --force-password-update to replace it.")
+				}
+			}
+
+			switch deployMethod := v.GetString("deploy-method"); deployMethod {
+			case "helm":
+				log.ActionWithoutSpinner("Deploying Admin Console with Helm")
+				if err := kotsadm.DeployWithHelm(deployOptions); err != nil {
+					return errors.Wrap(err, "failed to deploy with helm")
+				}
+			case "", "kubectl":
+				listInstalledApps := func() ([]string, error) {
+					return listInstalledAppSlugs(clientset, namespace, log)
+				}
+				isConverged, reason, err := kotsadm.IsInstallConverged(deployOptions, clientset, listInstalledApps)
+				if err != nil {
+					return errors.Wrap(err, "failed to check existing installation")
 				}
-				return errors.Wrap(err, "failed to deploy")
+				if isConverged {
+					log.ActionWithoutSpinner("")
+					log.ActionWithoutSpinner("The Admin Console is already installed in namespace %s, no changes", namespace)
+					log.ActionWithoutSpinner("")
+					return nil
+				}
+				log.Info("Applying changes: %s", reason)
+
+				log.ActionWithoutSpinner("Deploying Admin Console")
+				if err := kotsadm.Deploy(deployOptions); err != nil {
+					if _, ok := errors.Cause(err).(*types.ErrorTimeout); ok {
+						return withExitCode(ExitCodeTimeout, errors.Errorf("Failed to deploy: %s. Use the --wait-duration flag to increase timeout.", err))
+					}
+					return errors.Wrap(err, "failed to deploy")
+				}
+			default:
+				return errors.Errorf("unsupported deploy method %q", deployMethod)
 			}
 
 			if deployOptions.ExcludeAdminConsole && sharedPassword != "" {
@@ -274,7 +462,7 @@ func InstallCmd() *cobra.Command {
 			podName, err := k8sutil.WaitForKotsadm(clientset, namespace, timeout)
 			if err != nil {
 				if _, ok := errors.Cause(err).(*types.ErrorTimeout); ok {
-					return errors.Errorf("kotsadm failed to start: %s. Use the --wait-duration flag to increase timeout.", err)
+					return withExitCode(ExitCodeTimeout, errors.Errorf("kotsadm failed to start: %s. Use the --wait-duration flag to increase timeout.", err))
 				}
 				return errors.Wrap(err, "failed to wait for web")
 			}
@@ -332,6 +520,36 @@ func InstallCmd() *cobra.Command {
 
 			m.ReportInstallFinish()
 
+			if v.GetBool("dev") {
+				log.ActionWithoutSpinner("")
+				log.ActionWithoutSpinner("Go to http://localhost:%d to access the Admin Console", adminConsolePort)
+				log.ActionWithoutSpinner("Watching %s for changes. Press Ctrl+C to exit", args[0])
+				log.ActionWithoutSpinner("")
+
+				appName := v.GetString("name")
+				if appName == "" {
+					appName = filepath.Base(strings.TrimPrefix(upstream, "file://"))
+				}
+
+				err := runDevMode(devModeOptions{
+					UpstreamURI:    upstream,
+					Namespace:      namespace,
+					AppName:        appName,
+					APIEndpoint:    fmt.Sprintf("http://localhost:%d", adminConsolePort),
+					Deploy:         v.GetBool("dev-deploy"),
+					SkipPreflights: v.GetBool("skip-preflights"),
+				}, log)
+				if err != nil {
+					return errors.Wrap(err, "failed to run dev mode")
+				}
+
+				log.ActionWithoutSpinner("Cleaning up")
+				log.ActionWithoutSpinner("")
+				log.ActionWithoutSpinner("To access the Admin Console again, run kubectl kots admin-console --namespace %s", namespace)
+				log.ActionWithoutSpinner("")
+				return nil
+			}
+
 			if v.GetBool("port-forward") && !deployOptions.ExcludeAdminConsole {
 				log.ActionWithoutSpinner("")
 
@@ -341,7 +559,11 @@ func InstallCmd() *cobra.Command {
 				}
 
 				log.ActionWithoutSpinner("Press Ctrl+C to exit")
-				log.ActionWithoutSpinner("Go to http://localhost:%d to access the Admin Console", adminConsolePort)
+				if deployOptions.Headless {
+					log.ActionWithoutSpinner("The Admin Console api is available at http://localhost:%d/api/v1", adminConsolePort)
+				} else {
+					log.ActionWithoutSpinner("Go to http://localhost:%d to access the Admin Console", adminConsolePort)
+				}
 				log.ActionWithoutSpinner("")
 
 				signalChan := make(chan os.Signal, 1)
@@ -368,20 +590,45 @@ func InstallCmd() *cobra.Command {
 	}
 
 	cmd.Flags().String("shared-password", "", "shared password to apply")
+	cmd.Flags().Bool("force-password-update", false, "set to true to replace the Admin Console password when it already exists")
+	cmd.Flags().Bool("headless", false, "set to true to only serve the authenticated api of the Admin Console, without the web console. the web console can be enabled later with kots admin-console restore-component ui")
+	cmd.Flags().Bool("read-only-console", false, "set to true to start the Admin Console in read-only mode, where all changes are disabled until an administrator turns it off")
+	cmd.Flags().String("ca-bundle", "", "path to a pem encoded bundle of certificate authorities to trust, in addition to the system roots, for outbound connections from the Admin Console")
+	cmd.Flags().StringArray("host-alias", []string{}, "a host alias in the form ip=hostname[,hostname...] used by the Admin Console to reach hosts such as replicated.app, registries and git servers at internal addresses (can be specified multiple times)")
+	cmd.Flags().Bool("enable-network-policies", false, "set to true to deploy network policies that restrict traffic to the Admin Console components, for clusters that deny traffic by default")
+	cmd.Flags().String("deploy-method", "kubectl", "the method used to deploy the Admin Console (kubectl or helm)")
+	cmd.Flags().String("service-type", "", "the type of the kotsadm service (ClusterIP, NodePort or LoadBalancer)")
 	cmd.Flags().String("name", "", "name of the application to use in the Admin Console")
 	cmd.Flags().String("local-path", "", "specify a local-path to test the behavior of rendering a replicated app locally (only supported on replicated app types currently)")
 	cmd.Flags().String("license-file", "", "path to a license file to use when download a replicated app")
 	cmd.Flags().String("config-values", "", "path to a manifest containing config values (must be apiVersion: kots.io/v1beta1, kind: ConfigValues)")
+	cmd.Flags().String("additional-manifests", "", "path to a directory of manifests to deploy with the application, in addition to the manifests of the release. these are kept across updates")
+	cmd.Flags().Bool("dev", false, "set to true to install the application from a local directory of manifests and create a new version each time the files change")
+	cmd.Flags().Bool("dev-deploy", true, "when --dev is set, automatically deploy each new version")
 	cmd.Flags().Bool("port-forward", true, "set to false to disable automatic port forward")
 	cmd.Flags().String("wait-duration", "2m", "timeout out to be used while waiting for individual components to be ready.  must be in Go duration format (eg: 10s, 2m)")
 	cmd.Flags().String("http-proxy", "", "sets HTTP_PROXY environment variable in all KOTS Admin Console components")
 	cmd.Flags().String("https-proxy", "", "sets HTTPS_PROXY environment variable in all KOTS Admin Console components")
 	cmd.Flags().String("no-proxy", "", "sets NO_PROXY environment variable in all KOTS Admin Console components")
+	cmd.Flags().String("proxy-auth-scheme", proxyauth.SchemeBasic, "the scheme used to authenticate to the proxy (basic or ntlm)")
+	cmd.Flags().String("proxy-username", "", "the username used to authenticate to the proxy, in the form DOMAIN\\username for ntlm. stored in a secret")
+	cmd.Flags().String("proxy-password", "", "the password used to authenticate to the proxy. stored in a secret")
 	cmd.Flags().Bool("copy-proxy-env", false, "copy proxy environment variables from current environment into all KOTS Admin Console components")
 	cmd.Flags().String("airgap-bundle", "", "path to the application airgap bundle where application metadata will be loaded from")
 	cmd.Flags().Bool("airgap", false, "set to true to run install in airgapped mode. setting --airgap-bundle implies --airgap=true.")
+	cmd.Flags().String("app-metadata", "", "path to an Application spec or an airgap bundle to brand the Admin Console with, instead of pulling the metadata from the upstream")
 	cmd.Flags().Bool("skip-preflights", false, "set to true to skip preflight checks")
 	cmd.Flags().Bool("disable-image-push", false, "set to true to disable images from being pushed to private registry")
+	cmd.Flags().String("bandwidth-limit", "", "maximum rate to push images at, in bytes per second (e.g. 10MB). unlimited by default")
+	cmd.Flags().Bool("all-architectures", false, "push all architectures of multi-arch images instead of only the architectures of the cluster nodes")
+	cmd.Flags().Duration("session-ttl", 0, "the maximum lifetime of an admin console session (e.g. 12h). defaults to 14 days")
+	cmd.Flags().Duration("session-idle-timeout", 0, "log out admin console sessions that are idle for this long (e.g. 30m). disabled by default")
+	cmd.Flags().Duration("session-reauth-window", 0, "require a login within this long for sensitive operations like restoring snapshots (e.g. 15m). disabled by default")
+	cmd.Flags().String("pod-security-profile", string(kotsadmtypes.PodSecurityProfileAuto), "the pod security standard to generate the Admin Console pods for (auto, default or restricted). auto selects restricted if the namespace enforces, warns or audits it with pod security admission")
+	cmd.Flags().StringSlice("cors-allowed-origins", []string{}, "origins allowed to call the admin console api from a browser, like https://portal.example.com or https://*.example.com. all origins are allowed by default")
+	cmd.Flags().StringSlice("replicated-api-mirror", []string{}, "urls of mirrors of the replicated api, like https://replicated-app.example.com, that update checks and license syncs fail over to when the endpoint of the license cannot be reached. can be specified multiple times")
+	cmd.Flags().StringSlice("replicated-registry-mirror", []string{}, "hosts of mirrors of the replicated registry that images are pulled from when registry.replicated.com cannot be reached. can be specified multiple times")
+	cmd.Flags().StringSlice("replicated-proxy-mirror", []string{}, "hosts of mirrors of the replicated proxy registry that images are pulled from when proxy.replicated.com cannot be reached. can be specified multiple times")
 
 	cmd.Flags().String("repo", "", "repo uri to use when installing a helm chart")
 	cmd.Flags().StringSlice("set", []string{}, "values to pass to helm when running helm template")
@@ -398,11 +645,18 @@ func InstallCmd() *cobra.Command {
 	cmd.Flags().MarkHidden("registry-endpoint")
 
 	// options for the alpha feature of using a reg instead of s3 for storage
-	cmd.Flags().String("storage-base-uri", "", "an s3 or oci-registry uri to use for kots persistent storage in the cluster")
+	cmd.Flags().String("storage-base-uri", "", "an s3, gs://<bucket>, azblob://<account>/<container> or oci-registry uri to use for kots persistent storage")
+	cmd.Flags().String("storage-gcs-service-account-file", "", "path to the json key of the service account used to access the gcs bucket. the default credentials are used when not set")
+	cmd.Flags().String("storage-azure-account-key", "", "the key of the storage account used to access the azure blob container")
+	cmd.Flags().String("storage-azure-identity-binding", "", "the aad pod identity binding of the managed identity used to access the azure blob container instead of an account key")
+	cmd.Flags().StringToString("service-account-annotations", map[string]string{}, "annotations to add to the kotsadm service account to access the object store with a cloud identity, e.g. eks.amazonaws.com/role-arn=<role arn> or iam.gke.io/gcp-service-account=<service account>")
 	cmd.Flags().Bool("with-minio", true, "when set, kots install will deploy a local minio instance for storage")
 	cmd.Flags().Bool("with-dockerdistribution", false, "when set, kots install will deploy a local instance of docker distribution for storage")
 	cmd.Flags().Bool("storage-base-uri-plainhttp", false, "when set, use plain http (not https) connecting to the local oci storage")
 	cmd.Flags().MarkHidden("storage-base-uri")
+	cmd.Flags().MarkHidden("storage-gcs-service-account-file")
+	cmd.Flags().MarkHidden("storage-azure-account-key")
+	cmd.Flags().MarkHidden("storage-azure-identity-binding")
 	cmd.Flags().MarkHidden("with-minio")
 	cmd.Flags().MarkHidden("with-dockerdistribution")
 	cmd.Flags().MarkHidden("storage-base-uri-plainhttp")
@@ -412,6 +666,7 @@ func InstallCmd() *cobra.Command {
 
 	cmd.Flags().String("airgap-upload-parallelism", "", "the number of chunks to upload in parallel when installing or updating in airgap mode")
 	cmd.Flags().MarkHidden("airgap-upload-parallelism")
+	cmd.Flags().Int("image-push-workers", 0, "the number of jobs in the cluster that push the images of airgap bundles in parallel. when greater than 1, the app images are not pushed by the cli")
 
 	cmd.Flags().Bool("enable-identity-service", false, "when set, the KOTS identity service will be enabled")
 	cmd.Flags().MarkHidden("enable-identity-service")
@@ -428,6 +683,22 @@ func InstallCmd() *cobra.Command {
 	return cmd
 }
 
+// getSessionDurations returns the session flags, which are stored in minutes and must be at least a minute when set
+func getSessionDurations(v *viper.Viper) (time.Duration, time.Duration, time.Duration, error) {
+	durations := []time.Duration{}
+	for _, flag := range []string{"session-ttl", "session-idle-timeout", "session-reauth-window"} {
+		d := v.GetDuration(flag)
+		if d < 0 {
+			return 0, 0, 0, errors.Errorf("--%s cannot be negative", flag)
+		}
+		if d > 0 && d < time.Minute {
+			return 0, 0, 0, errors.Errorf("--%s must be at least 1m", flag)
+		}
+		durations = append(durations, d)
+	}
+	return durations[0], durations[1], durations[2], nil
+}
+
 func promptForNamespace(upstreamURI string) (string, error) {
 	u, err := url.ParseRequestURI(upstreamURI)
 	if err != nil {
@@ -461,6 +732,29 @@ func promptForNamespace(upstreamURI string) (string, error) {
 	}
 }
 
+// printStorageProfile warns about settings of the default storage class that are known to corrupt or lose the
+// Admin Console data on distributed storage, and prints the recommended settings for it
+func printStorageProfile(clientset kubernetes.Interface, log *logger.CLILogger) {
+	profile, err := storageprofile.GetDefaultProfile(clientset)
+	if err != nil {
+		log.Info("Unable to check the default storage class: %v", err)
+		return
+	}
+	if profile == nil || len(profile.Warnings) == 0 {
+		return
+	}
+
+	log.ActionWithoutSpinner("The default storage class %s uses %s storage with settings that can cause data loss:", profile.StorageClass, profile.Provider)
+	for _, warning := range profile.Warnings {
+		log.ChildActionWithoutSpinner("%s", warning)
+	}
+	log.ActionWithoutSpinner("Recommended settings for the Admin Console:")
+	for _, recommendation := range profile.Recommendations {
+		log.ChildActionWithoutSpinner("%s", recommendation)
+	}
+	log.ActionWithoutSpinner("")
+}
+
 func uploadAirgapArchive(deployOptions kotsadmtypes.DeployOptions, clientset *kubernetes.Clientset, apiEndpoint string, filename string) (bool, error) {
 	body := &bytes.Buffer{}
 	bodyWriter := multipart.NewWriter(body)
@@ -494,7 +788,7 @@ func uploadAirgapArchive(deployOptions kotsadmtypes.DeployOptions, clientset *ku
 
 	authSlug, err := auth.GetOrCreateAuthSlug(clientset, deployOptions.Namespace)
 	if err != nil {
-		return false, errors.Wrap(err, "failed to get kotsadm auth slug")
+		return false, withExitCode(ExitCodeAuthFailed, errors.Wrap(err, "failed to get kotsadm auth slug"))
 	}
 
 	url := fmt.Sprintf("%s/airgap/install", apiEndpoint)
@@ -518,6 +812,39 @@ func uploadAirgapArchive(deployOptions kotsadmtypes.DeployOptions, clientset *ku
 	return false, nil
 }
 
+// listInstalledAppSlugs returns the slugs of the apps installed in the running admin console
+func listInstalledAppSlugs(clientset *kubernetes.Clientset, namespace string, log *logger.CLILogger) ([]string, error) {
+	podName, err := k8sutil.FindKotsadm(clientset, namespace)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to find kotsadm pod")
+	}
+
+	stopCh := make(chan struct{})
+	defer close(stopCh)
+
+	localPort, _, err := k8sutil.PortForward(0, 3000, namespace, podName, false, stopCh, log)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to start port forwarding")
+	}
+
+	authSlug, err := auth.GetOrCreateAuthSlug(clientset, namespace)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get kotsadm auth slug")
+	}
+
+	// the running admin console is replaced by this install, so its version is not checked
+	apps, err := getApps(http.DefaultClient, fmt.Sprintf("http://localhost:%d/api/v1/apps", localPort), authSlug)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get apps")
+	}
+
+	slugs := []string{}
+	for _, app := range apps.Apps {
+		slugs = append(slugs, app.Slug)
+	}
+	return slugs, nil
+}
+
 func getIngressConfig(v *viper.Viper) (*kotsv1beta1.IngressConfig, error) {
 	ingressConfigPath := v.GetString("ingress-config")
 	enableIngress := v.GetBool("enable-ingress") || ingressConfigPath != ""
@@ -701,3 +1028,17 @@ func CheckRBAC() error {
 	}
 	return nil
 }
+
+// getStorageCredentials returns the credentials of the gcs and azure object store drivers from the flags
+func getStorageCredentials(v *viper.Viper) (string, string, error) {
+	gcsServiceAccount := ""
+	if serviceAccountPath := v.GetString("storage-gcs-service-account-file"); serviceAccountPath != "" {
+		content, err := ioutil.ReadFile(ExpandDir(serviceAccountPath))
+		if err != nil {
+			return "", "", errors.Wrap(err, "failed to read gcs service account file")
+		}
+		gcsServiceAccount = string(content)
+	}
+
+	return gcsServiceAccount, v.GetString("storage-azure-account-key"), nil
+}
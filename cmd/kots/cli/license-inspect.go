@@ -0,0 +1,55 @@
+package cli
+
+import (
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/replicatedhq/kots/pkg/license"
+	"github.com/replicatedhq/kots/pkg/print"
+	"github.com/replicatedhq/kots/pkg/pull"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+func LicenseInspectCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "inspect [license file]",
+		Short: "Show the customer, channel, expiration and entitlements of a license file",
+		Long: `Show the customer, channel, expiration and entitlements of a license file, to check that it is the right license before installing with it.
+
+Examples:
+kubectl kots license inspect ./license.yaml
+kubectl kots license inspect ./license.yaml -o json`,
+		SilenceUsage:  true,
+		SilenceErrors: false,
+		PreRun: func(cmd *cobra.Command, args []string) {
+			viper.BindPFlags(cmd.Flags())
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			v := viper.GetViper()
+
+			if len(args) != 1 {
+				cmd.Help()
+				return errors.New("license file is required")
+			}
+
+			l, err := pull.ParseLicenseFromFile(ExpandDir(args[0]))
+			if err != nil {
+				return withExitCode(ExitCodeLicenseInvalid, errors.Wrap(err, "failed to parse license file"))
+			}
+
+			summary, err := license.Summarize(l, time.Now())
+			if err != nil {
+				return errors.Wrap(err, "failed to summarize license")
+			}
+
+			print.License(summary, v.GetString("output"))
+
+			return nil
+		},
+	}
+
+	cmd.Flags().StringP("output", "o", "", "output format. supported values: json")
+
+	return cmd
+}
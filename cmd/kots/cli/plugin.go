@@ -0,0 +1,175 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/replicatedhq/kots/pkg/auth"
+	"github.com/replicatedhq/kots/pkg/k8sutil"
+	"github.com/replicatedhq/kots/pkg/logger"
+	"github.com/replicatedhq/kots/pkg/plugin"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"k8s.io/client-go/kubernetes"
+)
+
+// pluginAPITimeout limits how long finding the admin console can delay running a plugin
+const pluginAPITimeout = 5 * time.Second
+
+func PluginCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "plugin",
+		Short: "Provides utilities for interacting with plugins",
+		Long: `Plugins are executables named kots-<name> anywhere in PATH. "kubectl kots foo bar" runs the first kots-foo-bar
+or kots-foo found, with the remaining args. Built-in commands cannot be overridden by plugins.
+
+Plugins are run with these environment variables:
+KOTS_NAMESPACE      the namespace from KOTS_NAMESPACE or the current kubeconfig context
+KOTS_BINARY         the path of the kots binary, to run kots commands
+KOTS_API_ENDPOINT   the address of the Admin Console API, if it is running in the namespace
+KOTS_AUTH_TOKEN     the Authorization header for the Admin Console API`,
+		SilenceUsage: true,
+	}
+
+	cmd.AddCommand(PluginListCmd())
+
+	return cmd
+}
+
+func PluginListCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:           "list",
+		Short:         "List the plugins found in PATH",
+		Long:          ``,
+		SilenceUsage:  true,
+		SilenceErrors: false,
+		PreRun: func(cmd *cobra.Command, args []string) {
+			viper.BindPFlags(cmd.Flags())
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			plugins := plugin.List(os.Getenv("PATH"))
+			if len(plugins) == 0 {
+				return errors.New("no plugins found in PATH")
+			}
+
+			root := cmd.Root()
+			for _, p := range plugins {
+				fmt.Println(p.Path)
+				if p.ShadowedBy != "" {
+					fmt.Printf("  - warning: %s is shadowed by %s\n", p.Path, p.ShadowedBy)
+				} else if found, _, err := root.Find([]string{p.Name}); err == nil && found != root {
+					fmt.Printf("  - warning: %s is overridden by the built-in %q command\n", p.Path, p.Name)
+				}
+			}
+
+			return nil
+		},
+	}
+
+	return cmd
+}
+
+// runPlugin runs the plugin named by args if they do not name a built-in command. It returns false if there is no
+// such plugin, and otherwise the exit code of the plugin.
+func runPlugin(root *cobra.Command, args []string) (bool, int) {
+	if len(args) == 0 || args[0] == "help" {
+		return false, 0
+	}
+	if found, _, err := root.Find(args); err == nil && found != root {
+		return false, 0
+	}
+
+	p, pluginArgs := plugin.Find(os.Getenv("PATH"), args)
+	if p == nil {
+		return false, 0
+	}
+
+	log := logger.NewCLILogger()
+
+	env, stop := pluginEnv()
+	defer stop()
+
+	pluginCmd := exec.Command(p.Path, pluginArgs...)
+	pluginCmd.Env = append(os.Environ(), env...)
+	pluginCmd.Stdin = os.Stdin
+	pluginCmd.Stdout = os.Stdout
+	pluginCmd.Stderr = os.Stderr
+
+	if err := pluginCmd.Run(); err != nil {
+		if exitErr, ok := err.(*exec.ExitError); ok {
+			return true, exitErr.ExitCode()
+		}
+		log.Error(errors.Wrapf(err, "failed to run plugin %s", p.Path))
+		return true, 1
+	}
+
+	return true, 0
+}
+
+// pluginEnv returns the environment that passes the context of the cli to plugins. The returned func stops the port
+// forward to the admin console, which has to run for as long as the plugin does.
+func pluginEnv() ([]string, func()) {
+	env := []string{}
+	stop := func() {}
+
+	namespace := os.Getenv("KOTS_NAMESPACE")
+	if namespace == "" {
+		ns, _, err := k8sutil.GetKubeConfigFlags().ToRawKubeConfigLoader().Namespace()
+		if err == nil {
+			namespace = ns
+		}
+	}
+	if namespace != "" {
+		env = append(env, fmt.Sprintf("KOTS_NAMESPACE=%s", namespace))
+	}
+
+	if binary, err := os.Executable(); err == nil {
+		env = append(env, fmt.Sprintf("KOTS_BINARY=%s", binary))
+	}
+
+	if namespace == "" {
+		return env, stop
+	}
+
+	// the admin console api is optional, plugins that need it fail on their own if it is not set
+	cfg, err := k8sutil.GetClusterConfig()
+	if err != nil {
+		return env, stop
+	}
+	cfg.Timeout = pluginAPITimeout
+	clientset, err := kubernetes.NewForConfig(cfg)
+	if err != nil {
+		return env, stop
+	}
+
+	podName, err := k8sutil.FindKotsadm(clientset, namespace)
+	if err != nil {
+		return env, stop
+	}
+
+	log := logger.NewCLILogger()
+	log.Silence()
+
+	stopCh := make(chan struct{})
+	localPort, _, err := k8sutil.PortForward(0, 3000, namespace, podName, false, stopCh, log)
+	if err != nil {
+		close(stopCh)
+		return env, stop
+	}
+	stop = func() { close(stopCh) }
+
+	authSlug, err := auth.GetOrCreateAuthSlug(clientset, namespace)
+	if err != nil {
+		return env, stop
+	}
+
+	env = append(env,
+		fmt.Sprintf("KOTS_API_ENDPOINT=http://localhost:%d/api/v1", localPort),
+		fmt.Sprintf("KOTS_AUTH_TOKEN=%s", authSlug),
+	)
+
+	return env, stop
+}
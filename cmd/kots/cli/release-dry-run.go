@@ -0,0 +1,68 @@
+package cli
+
+import (
+	"github.com/pkg/errors"
+	"github.com/replicatedhq/kots/pkg/print"
+	"github.com/replicatedhq/kots/pkg/release/dryrun"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+func ReleaseDryRunCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "dry-run [app dir]",
+		Short: "Simulate installing a release with a customer license",
+		Long: `Simulate what installing the release in a local directory does for a customer, without a cluster or network access. The license is verified, the config is validated and templated with the config values, the release is rendered, the preflight and support bundle specs are parsed and the images the rendered release references are listed.
+
+The command exits with an error if any check fails, so that releases can be gated on it in CI before they are promoted.
+
+Examples:
+kubectl kots release dry-run ./manifests --license-file ./license.yaml
+kubectl kots release dry-run ./manifests --license-file ./license.yaml --config-values ./values.yaml -o json`,
+		SilenceUsage:  true,
+		SilenceErrors: false,
+		PreRun: func(cmd *cobra.Command, args []string) {
+			viper.BindPFlags(cmd.Flags())
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			v := viper.GetViper()
+
+			if len(args) != 1 {
+				cmd.Help()
+				return errors.New("app dir is required")
+			}
+			if v.GetString("license-file") == "" {
+				return errors.New("--license-file is required")
+			}
+
+			opts := dryrun.Options{
+				AppDir:      ExpandDir(args[0]),
+				LicenseFile: ExpandDir(v.GetString("license-file")),
+				Namespace:   v.GetString("namespace"),
+			}
+			if configValuesFile := v.GetString("config-values"); configValuesFile != "" {
+				opts.ConfigValuesFile = ExpandDir(configValuesFile)
+			}
+
+			result, err := dryrun.DryRun(opts)
+			if err != nil {
+				return errors.Wrap(err, "failed to dry run release")
+			}
+
+			print.ReleaseDryRunResult(result, v.GetString("output"))
+
+			if !result.Passed() {
+				return errors.New("release dry run failed")
+			}
+
+			return nil
+		},
+	}
+
+	cmd.Flags().String("license-file", "", "path to the customer license to simulate the install with")
+	cmd.Flags().String("config-values", "", "path to a manifest containing config values (must be apiVersion: kots.io/v1beta1, kind: ConfigValues)")
+	cmd.Flags().StringP("namespace", "n", "", "the namespace to render the release for")
+	cmd.Flags().StringP("output", "o", "", "output format. supported values: json")
+
+	return cmd
+}
@@ -8,13 +8,16 @@ import (
 	"net/http"
 	"net/url"
 	"os"
+	"strings"
 	"time"
 
 	cursor "github.com/ahmetalpbalkan/go-cursor"
 	"github.com/pkg/errors"
+	handlertypes "github.com/replicatedhq/kots/pkg/api/handlers/types"
 	"github.com/replicatedhq/kots/pkg/auth"
 	"github.com/replicatedhq/kots/pkg/k8sutil"
 	"github.com/replicatedhq/kots/pkg/logger"
+	"github.com/replicatedhq/kots/pkg/versionskew"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 )
@@ -23,7 +26,7 @@ func RemoveCmd() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:           "remove [slug]",
 		Short:         "Remove an application from console",
-		Long:          `Remove application reference identified by slug from Admin Console.  This command does not remove application resources from the cluster.`,
+		Long:          `Remove application reference identified by slug from Admin Console.  This command does not remove application resources from the cluster.  The application can be restored with --undo until the Admin Console removes it permanently.`,
 		SilenceUsage:  true,
 		SilenceErrors: false,
 		PreRun: func(cmd *cobra.Command, args []string) {
@@ -57,7 +60,11 @@ func RemoveCmd() *cobra.Command {
 			stopCh := make(chan struct{})
 			defer close(stopCh)
 
-			log.ActionWithoutSpinner("Removing application %s reference from Admin Console", appSlug)
+			if v.GetBool("undo") {
+				log.ActionWithoutSpinner("Restoring removed application %s to Admin Console", appSlug)
+			} else {
+				log.ActionWithoutSpinner("Removing application %s reference from Admin Console", appSlug)
+			}
 
 			localPort, errChan, err := k8sutil.PortForward(0, 3000, namespace, podName, false, stopCh, log)
 			if err != nil {
@@ -74,13 +81,23 @@ func RemoveCmd() *cobra.Command {
 				}
 			}()
 
+			client, err := versionskew.NewClient(fmt.Sprintf("http://localhost:%d", localPort), log)
+			if err != nil {
+				return err
+			}
+
 			authSlug, err := auth.GetOrCreateAuthSlug(clientset, namespace)
 			if err != nil {
-				return errors.Wrap(err, "failed to get kotsadm auth slug")
+				return withExitCode(ExitCodeAuthFailed, errors.Wrap(err, "failed to get kotsadm auth slug"))
+			}
+
+			if v.GetBool("undo") {
+				return undoRemove(client, localPort, authSlug, appSlug, log)
 			}
 
 			requestPayload := map[string]interface{}{
-				"force": v.GetBool("force"),
+				"force":   v.GetBool("force"),
+				"confirm": v.GetString("confirm"),
 			}
 
 			requestBody, err := json.Marshal(requestPayload)
@@ -96,7 +113,7 @@ func RemoveCmd() *cobra.Command {
 			newRequest.Header.Add("Authorization", authSlug)
 			newRequest.Header.Add("Content-Type", "application/json")
 
-			resp, err := http.DefaultClient.Do(newRequest)
+			resp, err := client.Do(newRequest)
 			if err != nil {
 				return errors.Wrap(err, "failed to execute http request")
 			}
@@ -109,7 +126,8 @@ func RemoveCmd() *cobra.Command {
 			}
 
 			type removeAppResponse struct {
-				Error string `json:"error"`
+				Error   string     `json:"error"`
+				PurgeAt *time.Time `json:"purgeAt"`
 			}
 			response := removeAppResponse{}
 			_ = json.Unmarshal(b, &response)
@@ -118,7 +136,7 @@ func RemoveCmd() *cobra.Command {
 				if resp.StatusCode == http.StatusNotFound {
 					return errors.Errorf("app with slug %s not found", appSlug)
 				} else if resp.StatusCode == http.StatusBadRequest {
-					if v.GetBool("force") {
+					if v.GetBool("force") || !strings.Contains(response.Error, "is deployed") {
 						return errors.Wrap(errors.New(response.Error), "failed to remove app")
 					} else {
 						return errors.Errorf("Application is already deployed. Re-run the command with --force flag to remove application reference anyway.")
@@ -129,6 +147,9 @@ func RemoveCmd() *cobra.Command {
 			}
 
 			log.ActionWithoutSpinner("Application %s has been removed", appSlug)
+			if response.PurgeAt != nil {
+				log.ActionWithoutSpinner("It can be restored with --undo until %s", response.PurgeAt.Format(time.RFC3339))
+			}
 
 			return nil
 		},
@@ -136,6 +157,34 @@ func RemoveCmd() *cobra.Command {
 
 	cmd.Flags().StringP("namespace", "n", "", "the namespace in which kots/kotsadm is installed")
 	cmd.Flags().BoolP("force", "f", false, "removing application reference even if it was already deployed")
+	cmd.Flags().String("confirm", "", "the slug of the application, required to remove a protected application")
+	cmd.Flags().Bool("undo", false, "restore an application that was removed and has not been removed permanently yet")
 
 	return cmd
 }
+
+func undoRemove(client *http.Client, localPort int, authSlug string, appSlug string, log *logger.CLILogger) error {
+	url := fmt.Sprintf("http://localhost:%d/api/v1/app/%s/remove/undo", localPort, url.QueryEscape(appSlug))
+	newRequest, err := http.NewRequest("POST", url, nil)
+	if err != nil {
+		return errors.Wrap(err, "failed to create http request")
+	}
+	newRequest.Header.Add("Authorization", authSlug)
+
+	resp, err := client.Do(newRequest)
+	if err != nil {
+		return errors.Wrap(err, "failed to execute http request")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusNoContent {
+		if resp.StatusCode == http.StatusNotFound {
+			return errors.Errorf("app with slug %s not found", appSlug)
+		}
+		return handlertypes.ErrorFromResponse(resp)
+	}
+
+	log.ActionWithoutSpinner("Application %s has been restored", appSlug)
+
+	return nil
+}
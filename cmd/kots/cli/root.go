@@ -5,6 +5,7 @@ import (
 	"strings"
 
 	"github.com/replicatedhq/kots/pkg/k8sutil"
+	"github.com/replicatedhq/kots/pkg/logger"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 )
@@ -13,7 +14,7 @@ func RootCmd() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "kots",
 		Short: "",
-		Long:  `.`,
+		Long:  exitCodesHelp,
 		PreRun: func(cmd *cobra.Command, args []string) {
 			viper.BindPFlags(cmd.Flags())
 		},
@@ -22,6 +23,10 @@ func RootCmd() *cobra.Command {
 	cobra.OnInitialize(initConfig)
 
 	k8sutil.AddFlags(cmd.PersistentFlags())
+	cmd.PersistentFlags().BoolP("quiet", "q", false, "only print errors, without progress and spinners. useful for automation")
+	cmd.PersistentFlags().BoolP("verbose", "v", false, "print debug messages, including the http requests made to the admin console")
+	cmd.PersistentFlags().Bool("debug", false, "same as --verbose, and print full error traces in some cases where a shorter message is shown")
+	registerCompletions(cmd)
 
 	cmd.AddCommand(PullCmd())
 	cmd.AddCommand(InstallCmd())
@@ -29,6 +34,10 @@ func RootCmd() *cobra.Command {
 	cmd.AddCommand(DownloadCmd())
 	cmd.AddCommand(UpstreamCmd())
 	cmd.AddCommand(RemoveCmd())
+	cmd.AddCommand(CloneCmd())
+	cmd.AddCommand(DeployCmd())
+	cmd.AddCommand(FreezeCmd())
+	cmd.AddCommand(UnfreezeCmd())
 	cmd.AddCommand(AdminConsoleCmd())
 	cmd.AddCommand(ResetPasswordCmd())
 	cmd.AddCommand(ResetTLSCmd())
@@ -37,24 +46,46 @@ func RootCmd() *cobra.Command {
 	cmd.AddCommand(BackupCmd())
 	cmd.AddCommand(RestoreCmd())
 	cmd.AddCommand(IngressCmd())
+	cmd.AddCommand(ExposeCmd())
 	cmd.AddCommand(IdentityServiceCmd())
 	cmd.AddCommand(AppStatusCmd())
 	cmd.AddCommand(GetCmd())
 	cmd.AddCommand(SetCmd())
+	cmd.AddCommand(ExcludeCmd())
+	cmd.AddCommand(AirgapCmd())
+	cmd.AddCommand(ReleaseCmd())
+	cmd.AddCommand(LicenseCmd())
+	cmd.AddCommand(TemporaryAccessCmd())
+	cmd.AddCommand(PluginCmd())
+	cmd.AddCommand(CompletionCmd())
 
 	viper.BindPFlags(cmd.Flags())
+	viper.BindPFlags(cmd.PersistentFlags())
 
 	viper.SetEnvKeyReplacer(strings.NewReplacer("-", "_"))
 	return cmd
 }
 
 func InitAndExecute() {
-	if err := RootCmd().Execute(); err != nil {
-		os.Exit(1)
+	cmd := RootCmd()
+
+	if ran, exitCode := runPlugin(cmd, os.Args[1:]); ran {
+		os.Exit(exitCode)
+	}
+
+	if err := cmd.Execute(); err != nil {
+		os.Exit(exitCodeFromError(err))
 	}
 }
 
 func initConfig() {
 	viper.SetEnvPrefix("KOTS")
 	viper.AutomaticEnv()
+
+	// flags are parsed by the time the initializers run, so that every logger created by the command uses them
+	if viper.GetBool("verbose") || viper.GetBool("debug") {
+		logger.SetCLIVerbose()
+	} else if viper.GetBool("quiet") {
+		logger.SetCLIQuiet()
+	}
 }
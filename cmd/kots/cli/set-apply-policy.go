@@ -0,0 +1,149 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/pkg/errors"
+	handlertypes "github.com/replicatedhq/kots/pkg/api/handlers/types"
+	apptypes "github.com/replicatedhq/kots/pkg/app/types"
+	"github.com/replicatedhq/kots/pkg/logger"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+func SetApplyPolicyCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "apply-policy [appSlug]",
+		Short: "Set how the manifests of an application are applied",
+		Long: `Configure the retries, kubectl timeout, waits, adoption of existing resources and re-applying of deleted critical resources that are used when the manifests of an application are applied to the cluster. Only the flags that are specified are changed, a value of 0 uses the default.
+
+Examples:
+kubectl kots set apply-policy my-app --retries 3 --retry-backoff 10s --kubectl-timeout 2m --wait-for-resources --phase-wait 1m -n default
+kubectl kots set apply-policy my-app --adopt-existing-resources -n default
+kubectl kots set apply-policy my-app --reapply-deleted-critical-resources -n default`,
+		ValidArgsFunction: completeAppSlugArg,
+		SilenceUsage:      true,
+		SilenceErrors:     false,
+		PreRun: func(cmd *cobra.Command, args []string) {
+			viper.BindPFlags(cmd.Flags())
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			v := viper.GetViper()
+
+			if len(args) != 1 {
+				cmd.Help()
+				return errors.New("app slug is required")
+			}
+			appSlug := args[0]
+
+			log := logger.NewCLILogger()
+
+			stopCh := make(chan struct{})
+			defer close(stopCh)
+
+			localPort, authSlug, client, err := forwardToAdminConsole(v, stopCh)
+			if err != nil {
+				return err
+			}
+
+			url := fmt.Sprintf("http://localhost:%d/api/v1/app/%s/apply-policy", localPort, url.PathEscape(appSlug))
+
+			applyPolicy, err := getApplyPolicy(client, url, authSlug)
+			if err != nil {
+				return errors.Wrap(err, "failed to get current apply policy")
+			}
+
+			if cmd.Flags().Changed("retries") {
+				applyPolicy.Retries = v.GetInt("retries")
+			}
+			if cmd.Flags().Changed("retry-backoff") {
+				applyPolicy.RetryBackoffSeconds = int(v.GetDuration("retry-backoff") / time.Second)
+			}
+			if cmd.Flags().Changed("kubectl-timeout") {
+				applyPolicy.KubectlTimeoutSeconds = int(v.GetDuration("kubectl-timeout") / time.Second)
+			}
+			if cmd.Flags().Changed("wait-for-resources") {
+				applyPolicy.WaitForResources = v.GetBool("wait-for-resources")
+			}
+			if cmd.Flags().Changed("phase-wait") {
+				applyPolicy.PhaseWaitSeconds = int(v.GetDuration("phase-wait") / time.Second)
+			}
+			if cmd.Flags().Changed("adopt-existing-resources") {
+				applyPolicy.AdoptExistingResources = v.GetBool("adopt-existing-resources")
+			}
+			if cmd.Flags().Changed("reapply-deleted-critical-resources") {
+				applyPolicy.ReapplyDeletedCriticalResources = v.GetBool("reapply-deleted-critical-resources")
+			}
+			if err := applyPolicy.Validate(); err != nil {
+				return err
+			}
+
+			requestBody, err := json.Marshal(applyPolicy)
+			if err != nil {
+				return errors.Wrap(err, "failed to marshal request json")
+			}
+
+			newRequest, err := http.NewRequest("PUT", url, bytes.NewBuffer(requestBody))
+			if err != nil {
+				return errors.Wrap(err, "failed to create http request")
+			}
+			newRequest.Header.Add("Authorization", authSlug)
+			newRequest.Header.Add("Content-Type", "application/json")
+
+			resp, err := client.Do(newRequest)
+			if err != nil {
+				return errors.Wrap(err, "failed to execute http request")
+			}
+			defer resp.Body.Close()
+
+			if resp.StatusCode != http.StatusNoContent {
+				return handlertypes.ErrorFromResponse(resp)
+			}
+
+			log.ActionWithoutSpinner("Apply policy of %s was updated, it is used by the next deploy", appSlug)
+
+			return nil
+		},
+	}
+
+	cmd.Flags().StringP("namespace", "n", "", "the namespace in which kots/kotsadm is installed")
+	cmd.Flags().Int("retries", 0, "the number of times a failed apply is retried")
+	cmd.Flags().Duration("retry-backoff", 0, "the wait before the first retry of a failed apply, doubled after each retry")
+	cmd.Flags().Duration("kubectl-timeout", 0, "the timeout of each request kubectl makes to the api server")
+	cmd.Flags().Bool("wait-for-resources", false, "wait for deleted resources to be gone and for CRDs to be established before the next phase of the deploy")
+	cmd.Flags().Duration("phase-wait", 0, "the wait after CRDs and namespaces are applied, or the maximum wait for CRDs to be established with --wait-for-resources")
+	cmd.Flags().Bool("adopt-existing-resources", false, "adopt the resources of a prior manual install that have the names of the application's resources, the adopted resources are listed in the deploy output")
+	cmd.Flags().Bool("reapply-deleted-critical-resources", false, `apply the resources annotated with kots.io/critical: "true" again as soon as they are deleted between deploys`)
+
+	return cmd
+}
+
+func getApplyPolicy(client *http.Client, url string, authSlug string) (*apptypes.ApplyPolicy, error) {
+	newRequest, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create http request")
+	}
+	newRequest.Header.Add("Authorization", authSlug)
+
+	resp, err := client.Do(newRequest)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to execute http request")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, handlertypes.ErrorFromResponse(resp)
+	}
+
+	applyPolicy := apptypes.ApplyPolicy{}
+	if err := json.NewDecoder(resp.Body).Decode(&applyPolicy); err != nil {
+		return nil, errors.Wrap(err, "failed to decode response")
+	}
+
+	return &applyPolicy, nil
+}
@@ -14,9 +14,11 @@ import (
 	cursor "github.com/ahmetalpbalkan/go-cursor"
 	"github.com/pkg/errors"
 	kotsv1beta1 "github.com/replicatedhq/kots/kotskinds/apis/kots/v1beta1"
+	handlertypes "github.com/replicatedhq/kots/pkg/api/handlers/types"
 	"github.com/replicatedhq/kots/pkg/auth"
 	"github.com/replicatedhq/kots/pkg/k8sutil"
 	"github.com/replicatedhq/kots/pkg/logger"
+	"github.com/replicatedhq/kots/pkg/versionskew"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -74,7 +76,11 @@ func SetConfigCmd() *cobra.Command {
 			stopCh := make(chan struct{})
 			defer close(stopCh)
 
-			log.ActionWithoutSpinner("Updating %s configuration...", appSlug)
+			if v.GetBool("dry-run") {
+				log.ActionWithoutSpinner("Previewing %s configuration...", appSlug)
+			} else {
+				log.ActionWithoutSpinner("Updating %s configuration...", appSlug)
+			}
 
 			localPort, errChan, err := k8sutil.PortForward(0, 3000, namespace, podName, false, stopCh, log)
 			if err != nil {
@@ -91,9 +97,18 @@ func SetConfigCmd() *cobra.Command {
 				}
 			}()
 
+			client, err := versionskew.NewClient(fmt.Sprintf("http://localhost:%d", localPort), log)
+			if err != nil {
+				return err
+			}
+
 			authSlug, err := auth.GetOrCreateAuthSlug(clientset, namespace)
 			if err != nil {
-				return errors.Wrap(err, "failed to get kotsadm auth slug")
+				return withExitCode(ExitCodeAuthFailed, errors.Wrap(err, "failed to get kotsadm auth slug"))
+			}
+
+			if err := validateConfigValuesForLicense(client, localPort, appSlug, authSlug, configValues); err != nil {
+				return err
 			}
 
 			merge := v.GetBool("merge")
@@ -101,6 +116,13 @@ func SetConfigCmd() *cobra.Command {
 				merge = true
 			}
 
+			if v.GetBool("dry-run") {
+				if err := printRecomputedConfigValues(client, localPort, appSlug, authSlug, configValues, merge); err != nil {
+					return err
+				}
+				return previewConfigValues(client, localPort, appSlug, authSlug, configValues, merge)
+			}
+
 			requestPayload := map[string]interface{}{
 				"configValues":   configValues,
 				"merge":          merge,
@@ -121,7 +143,7 @@ func SetConfigCmd() *cobra.Command {
 			newRequest.Header.Add("Authorization", authSlug)
 			newRequest.Header.Add("Content-Type", "application/json")
 
-			resp, err := http.DefaultClient.Do(newRequest)
+			resp, err := client.Do(newRequest)
 			if err != nil {
 				return errors.Wrap(err, "failed to execute http request")
 			}
@@ -158,12 +180,167 @@ func SetConfigCmd() *cobra.Command {
 	cmd.Flags().String("config-file", "", "path to a manifest containing config values (must be apiVersion: kots.io/v1beta1, kind: ConfigValues)")
 	cmd.Flags().Bool("merge", false, "when set to true, only keys specified in config file will be updated. This flag can only be used when --config-file flag is used.")
 
+	cmd.Flags().Bool("dry-run", false, "when set, show the changes to the rendered manifests without creating a new version")
 	cmd.Flags().Bool("deploy", false, "when set, automatically deploy the latest version with the new configuration")
 	cmd.Flags().Bool("skip-preflights", false, "set to true to skip preflight checks when deploying new version")
 
 	return cmd
 }
 
+// previewConfigValues prints the diff of the rendered manifests of the current version with the config values applied
+func previewConfigValues(client *http.Client, localPort int, appSlug string, authSlug string, configValues []byte, merge bool) error {
+	requestBody, err := json.Marshal(handlertypes.PreviewAppConfigRequest{
+		ConfigValues: configValues,
+		Merge:        merge,
+	})
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal request json")
+	}
+
+	url := fmt.Sprintf("http://localhost:%d/api/v1/app/%s/config/values/preview", localPort, url.QueryEscape(appSlug))
+	newRequest, err := http.NewRequest("POST", url, bytes.NewBuffer(requestBody))
+	if err != nil {
+		return errors.Wrap(err, "failed to create http request")
+	}
+	newRequest.Header.Add("Authorization", authSlug)
+	newRequest.Header.Add("Content-Type", "application/json")
+
+	resp, err := client.Do(newRequest)
+	if err != nil {
+		return errors.Wrap(err, "failed to execute http request")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return handlertypes.ErrorFromResponse(resp)
+	}
+
+	preview := handlertypes.PreviewAppConfigResponse{}
+	if err := json.NewDecoder(resp.Body).Decode(&preview); err != nil {
+		return errors.Wrap(err, "failed to decode response")
+	}
+
+	if preview.FilesChanged == 0 {
+		fmt.Printf("No changes to the manifests of version %d\n", preview.Sequence)
+		return nil
+	}
+
+	fmt.Printf("%d files changed, %d lines added, %d lines removed compared to version %d\n\n", preview.FilesChanged, preview.LinesAdded, preview.LinesRemoved, preview.Sequence)
+	fmt.Print(preview.Diff)
+
+	return nil
+}
+
+// printRecomputedConfigValues prints the values of the config items that are derived from the config values
+func printRecomputedConfigValues(client *http.Client, localPort int, appSlug string, authSlug string, configValues []byte, merge bool) error {
+	requestBody, err := json.Marshal(handlertypes.RecomputeAppConfigRequest{
+		ConfigValues: configValues,
+		Merge:        merge,
+	})
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal request json")
+	}
+
+	url := fmt.Sprintf("http://localhost:%d/api/v1/app/%s/config/recompute", localPort, url.QueryEscape(appSlug))
+	newRequest, err := http.NewRequest("POST", url, bytes.NewBuffer(requestBody))
+	if err != nil {
+		return errors.Wrap(err, "failed to create http request")
+	}
+	newRequest.Header.Add("Authorization", authSlug)
+	newRequest.Header.Add("Content-Type", "application/json")
+
+	resp, err := client.Do(newRequest)
+	if err != nil {
+		return errors.Wrap(err, "failed to execute http request")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		// older kotsadm versions don't have this endpoint, the diff still shows the derived values
+		return nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return handlertypes.ErrorFromResponse(resp)
+	}
+
+	recomputed := handlertypes.RecomputeAppConfigResponse{}
+	if err := json.NewDecoder(resp.Body).Decode(&recomputed); err != nil {
+		return errors.Wrap(err, "failed to decode response")
+	}
+
+	if len(recomputed.Items) == 0 {
+		return nil
+	}
+
+	fmt.Println("Derived config items:")
+	for _, item := range recomputed.Items {
+		value := item.Value
+		if value == "" {
+			value = item.Default
+		}
+		fmt.Printf("  %s: %s\n", item.Name, value)
+	}
+	fmt.Println()
+
+	return nil
+}
+
+// validateConfigValuesForLicense fetches the effective config schema from kotsadm and returns
+// an error if any of the values are for items that are hidden by the app's license
+func validateConfigValuesForLicense(client *http.Client, localPort int, appSlug string, authSlug string, configValuesData []byte) error {
+	configValues := kotsv1beta1.ConfigValues{}
+	if err := k8syaml.Unmarshal(configValuesData, &configValues); err != nil {
+		return errors.Wrap(err, "failed to unmarshal config values")
+	}
+
+	url := fmt.Sprintf("http://localhost:%d/api/v1/app/%s/config/schema", localPort, url.QueryEscape(appSlug))
+	newRequest, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return errors.Wrap(err, "failed to create http request")
+	}
+	newRequest.Header.Add("Authorization", authSlug)
+
+	resp, err := client.Do(newRequest)
+	if err != nil {
+		return errors.Wrap(err, "failed to execute http request")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		// older kotsadm versions don't have this endpoint, the values will be validated on the server
+		return nil
+	}
+
+	respBody, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return errors.Wrap(err, "failed to read server response")
+	}
+
+	response := struct {
+		Error      string   `json:"error"`
+		GatedItems []string `json:"gatedItems"`
+	}{}
+	if err := json.Unmarshal(respBody, &response); err != nil {
+		return errors.Wrap(err, "failed to parse config schema")
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return errors.Wrapf(errors.New(response.Error), "unexpected status code from %v", resp.StatusCode)
+	}
+
+	gatedKeys := []string{}
+	for _, gatedItem := range response.GatedItems {
+		if _, ok := configValues.Spec.Values[gatedItem]; ok {
+			gatedKeys = append(gatedKeys, gatedItem)
+		}
+	}
+	if len(gatedKeys) > 0 {
+		return errors.Errorf("the following config items are not available with the current license: %s", strings.Join(gatedKeys, ", "))
+	}
+
+	return nil
+}
+
 func getConfigValuesFromArgs(v *viper.Viper, args []string) ([]byte, error) {
 	if fileName := v.GetString("config-file"); fileName != "" {
 		if len(args) > 1 || v.GetString("key") != "" {
@@ -0,0 +1,111 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+
+	"github.com/pkg/errors"
+	handlertypes "github.com/replicatedhq/kots/pkg/api/handlers/types"
+	"github.com/replicatedhq/kots/pkg/logger"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+type replaceLicenseRequest struct {
+	LicenseData string `json:"licenseData"`
+}
+
+type replaceLicenseResponse struct {
+	Sequence int64 `json:"sequence"`
+	License  struct {
+		ID string `json:"id"`
+	} `json:"license"`
+}
+
+func SetLicenseCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "license [appSlug]",
+		Short: "Replace the license of an application",
+		Long: `Replace the license of an application with a different license for the same application, for example when a new license was issued to the customer. The replaced license is kept in the license history, and a new version is created with the entitlements of the new license.
+
+Examples:
+kubectl kots set license my-app --license-file ./new-license.yaml -n default`,
+		ValidArgsFunction: completeAppSlugArg,
+		SilenceUsage:      true,
+		SilenceErrors:     false,
+		PreRun: func(cmd *cobra.Command, args []string) {
+			viper.BindPFlags(cmd.Flags())
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			v := viper.GetViper()
+
+			if len(args) != 1 {
+				cmd.Help()
+				return errors.New("app slug is required")
+			}
+			appSlug := args[0]
+
+			licenseFile := v.GetString("license-file")
+			if licenseFile == "" {
+				return errors.New("--license-file is required")
+			}
+			licenseData, err := ioutil.ReadFile(ExpandDir(licenseFile))
+			if err != nil {
+				return errors.Wrap(err, "failed to read license file")
+			}
+
+			requestBody, err := json.Marshal(replaceLicenseRequest{
+				LicenseData: string(licenseData),
+			})
+			if err != nil {
+				return errors.Wrap(err, "failed to marshal request json")
+			}
+
+			log := logger.NewCLILogger()
+
+			stopCh := make(chan struct{})
+			defer close(stopCh)
+
+			localPort, authSlug, client, err := forwardToAdminConsole(v, stopCh)
+			if err != nil {
+				return err
+			}
+
+			url := fmt.Sprintf("http://localhost:%d/api/v1/app/%s/license/replace", localPort, url.PathEscape(appSlug))
+			newRequest, err := http.NewRequest("POST", url, bytes.NewBuffer(requestBody))
+			if err != nil {
+				return errors.Wrap(err, "failed to create http request")
+			}
+			newRequest.Header.Add("Authorization", authSlug)
+			newRequest.Header.Add("Content-Type", "application/json")
+
+			resp, err := client.Do(newRequest)
+			if err != nil {
+				return errors.Wrap(err, "failed to execute http request")
+			}
+			defer resp.Body.Close()
+
+			if resp.StatusCode != http.StatusOK {
+				return handlertypes.ErrorFromResponse(resp)
+			}
+
+			response := replaceLicenseResponse{}
+			if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+				return errors.Wrap(err, "failed to decode response")
+			}
+
+			log.ActionWithoutSpinner("The license of %s was replaced with license %s, creating version %d", appSlug, response.License.ID, response.Sequence)
+
+			return nil
+		},
+	}
+
+	cmd.Flags().StringP("namespace", "n", "", "the namespace in which kots/kotsadm is installed")
+	cmd.Flags().String("license-file", "", "path to the new license file")
+
+	return cmd
+}
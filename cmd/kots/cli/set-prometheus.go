@@ -0,0 +1,146 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/pkg/errors"
+	handlertypes "github.com/replicatedhq/kots/pkg/api/handlers/types"
+	"github.com/replicatedhq/kots/pkg/auth"
+	"github.com/replicatedhq/kots/pkg/k8sutil"
+	"github.com/replicatedhq/kots/pkg/logger"
+	prometheustypes "github.com/replicatedhq/kots/pkg/prometheus/types"
+	"github.com/replicatedhq/kots/pkg/versionskew"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+func SetPrometheusCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "prometheus",
+		Short: "Configure the Prometheus endpoint used for application metrics",
+		Long: `Configure the address and credentials of the Prometheus that the Admin Console queries for application metrics.
+The connection is validated before the settings are saved.
+
+Examples:
+kubectl kots set prometheus --address http://prometheus-k8s.monitoring.svc.cluster.local:9090
+kubectl kots set prometheus --address https://prometheus.example.com --username admin --password secret
+kubectl kots set prometheus --address ""`,
+		SilenceUsage:  true,
+		SilenceErrors: false,
+		PreRun: func(cmd *cobra.Command, args []string) {
+			viper.BindPFlags(cmd.Flags())
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			v := viper.GetViper()
+
+			log := logger.NewCLILogger()
+			namespace := v.GetString("namespace")
+
+			if err := validateNamespace(namespace); err != nil {
+				return errors.Wrap(err, "failed to validate namespace")
+			}
+
+			if !cmd.Flags().Changed("address") {
+				return errors.New("--address is required")
+			}
+			if v.GetString("username") != "" && v.GetString("bearer-token") != "" {
+				return errors.New("only one of --username and --bearer-token can be set")
+			}
+
+			requestPayload := map[string]interface{}{
+				"value":          v.GetString("address"),
+				"skipValidation": v.GetBool("skip-validation"),
+			}
+			// credentials are only replaced when one of the flags is set, so the address can be changed on its own
+			if cmd.Flags().Changed("username") || cmd.Flags().Changed("password") || cmd.Flags().Changed("bearer-token") {
+				requestPayload["auth"] = prometheustypes.Auth{
+					Username:    v.GetString("username"),
+					Password:    v.GetString("password"),
+					BearerToken: v.GetString("bearer-token"),
+				}
+			}
+
+			requestBody, err := json.Marshal(requestPayload)
+			if err != nil {
+				return errors.Wrap(err, "failed to marshal request json")
+			}
+
+			clientset, err := k8sutil.GetClientset()
+			if err != nil {
+				return errors.Wrap(err, "failed to get clientset")
+			}
+
+			podName, err := k8sutil.WaitForKotsadm(clientset, namespace, time.Second*5)
+			if err != nil {
+				return errors.Wrap(err, "failed to find kotsadm pod")
+			}
+
+			stopCh := make(chan struct{})
+			defer close(stopCh)
+
+			localPort, errChan, err := k8sutil.PortForward(0, 3000, namespace, podName, false, stopCh, log)
+			if err != nil {
+				return errors.Wrap(err, "failed to start port forwarding")
+			}
+
+			go func() {
+				select {
+				case err := <-errChan:
+					if err != nil {
+						log.Error(err)
+					}
+				case <-stopCh:
+				}
+			}()
+
+			client, err := versionskew.NewClient(fmt.Sprintf("http://localhost:%d", localPort), log)
+			if err != nil {
+				return err
+			}
+
+			authSlug, err := auth.GetOrCreateAuthSlug(clientset, namespace)
+			if err != nil {
+				return withExitCode(ExitCodeAuthFailed, errors.Wrap(err, "failed to get kotsadm auth slug"))
+			}
+
+			log.ActionWithSpinner("Updating Prometheus settings")
+
+			url := fmt.Sprintf("http://localhost:%d/api/v1/prometheus", localPort)
+			newRequest, err := http.NewRequest("POST", url, bytes.NewBuffer(requestBody))
+			if err != nil {
+				log.FinishSpinnerWithError()
+				return errors.Wrap(err, "failed to create http request")
+			}
+			newRequest.Header.Add("Authorization", authSlug)
+			newRequest.Header.Add("Content-Type", "application/json")
+
+			resp, err := client.Do(newRequest)
+			if err != nil {
+				log.FinishSpinnerWithError()
+				return errors.Wrap(err, "failed to execute http request")
+			}
+			defer resp.Body.Close()
+
+			if resp.StatusCode != http.StatusNoContent && resp.StatusCode != http.StatusOK {
+				log.FinishSpinnerWithError()
+				return handlertypes.ErrorFromResponse(resp)
+			}
+
+			log.FinishSpinner()
+
+			return nil
+		},
+	}
+
+	cmd.Flags().String("address", "", "the address of the Prometheus API, e.g. http://prometheus-k8s.monitoring.svc.cluster.local:9090. an empty address removes the setting")
+	cmd.Flags().String("username", "", "the username for basic authentication to Prometheus")
+	cmd.Flags().String("password", "", "the password for basic authentication to Prometheus")
+	cmd.Flags().String("bearer-token", "", "the bearer token to authenticate to Prometheus")
+	cmd.Flags().Bool("skip-validation", false, "set to true to save the settings without checking that Prometheus can be reached")
+
+	return cmd
+}
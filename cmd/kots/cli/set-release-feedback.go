@@ -0,0 +1,104 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/pkg/errors"
+	handlertypes "github.com/replicatedhq/kots/pkg/api/handlers/types"
+	"github.com/replicatedhq/kots/pkg/logger"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+type setReleaseFeedbackRequest struct {
+	Enabled                 bool `json:"enabled"`
+	IncludePreflightDetails bool `json:"includePreflightDetails"`
+}
+
+func SetReleaseFeedbackCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "release-feedback [appSlug]",
+		Short: "Consent to report the rollout of each release of an application to the vendor",
+		Long: `Report whether each release of an application deployed successfully, and a summary of its preflight checks, to the vendor of the application, so that the vendor sees the health of its releases across all installs. Nothing is reported unless it is enabled, and the titles and messages of preflight checks that did not pass are only reported with --include-preflight-details. Every report that is sent is logged by the admin console.
+
+Examples:
+kubectl kots set release-feedback my-app --enabled -n default
+kubectl kots set release-feedback my-app --enabled --include-preflight-details -n default
+kubectl kots set release-feedback my-app --enabled=false -n default`,
+		ValidArgsFunction: completeAppSlugArg,
+		SilenceUsage:      true,
+		SilenceErrors:     false,
+		PreRun: func(cmd *cobra.Command, args []string) {
+			viper.BindPFlags(cmd.Flags())
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			v := viper.GetViper()
+
+			if len(args) != 1 {
+				cmd.Help()
+				return errors.New("app slug is required")
+			}
+			appSlug := args[0]
+
+			if !cmd.Flags().Changed("enabled") {
+				return errors.New("--enabled is required")
+			}
+			if v.GetBool("include-preflight-details") && !v.GetBool("enabled") {
+				return errors.New("--include-preflight-details requires --enabled")
+			}
+
+			requestBody, err := json.Marshal(setReleaseFeedbackRequest{
+				Enabled:                 v.GetBool("enabled"),
+				IncludePreflightDetails: v.GetBool("include-preflight-details"),
+			})
+			if err != nil {
+				return errors.Wrap(err, "failed to marshal request json")
+			}
+
+			stopCh := make(chan struct{})
+			defer close(stopCh)
+
+			localPort, authSlug, client, err := forwardToAdminConsole(v, stopCh)
+			if err != nil {
+				return err
+			}
+
+			requestURL := fmt.Sprintf("http://localhost:%d/api/v1/app/%s/release-feedback", localPort, url.PathEscape(appSlug))
+			newRequest, err := http.NewRequest("PUT", requestURL, bytes.NewBuffer(requestBody))
+			if err != nil {
+				return errors.Wrap(err, "failed to create http request")
+			}
+			newRequest.Header.Add("Authorization", authSlug)
+			newRequest.Header.Add("Content-Type", "application/json")
+
+			resp, err := client.Do(newRequest)
+			if err != nil {
+				return errors.Wrap(err, "failed to execute http request")
+			}
+			defer resp.Body.Close()
+
+			if resp.StatusCode != http.StatusOK {
+				return handlertypes.ErrorFromResponse(resp)
+			}
+
+			log := logger.NewCLILogger()
+			if v.GetBool("enabled") {
+				log.ActionWithoutSpinner("The rollout of each release of %s will be reported to the vendor", appSlug)
+			} else {
+				log.ActionWithoutSpinner("The rollout of releases of %s will not be reported to the vendor", appSlug)
+			}
+
+			return nil
+		},
+	}
+
+	cmd.Flags().StringP("namespace", "n", "", "the namespace in which kots/kotsadm is installed")
+	cmd.Flags().Bool("enabled", false, "report the deploy status and preflight summary of each release to the vendor")
+	cmd.Flags().Bool("include-preflight-details", false, "include the titles and messages of the preflight checks that did not pass")
+
+	return cmd
+}
@@ -0,0 +1,100 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/pkg/errors"
+	handlertypes "github.com/replicatedhq/kots/pkg/api/handlers/types"
+	"github.com/replicatedhq/kots/pkg/logger"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+func SetVersionNotesCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "version-notes [appSlug]",
+		Short: "Set the notes and labels of an application version",
+		Long: `Attach free-form notes and labels to a version of an application. The notes and labels are shown in the version history and replace any that were set before.
+
+Examples:
+kubectl kots set version-notes my-app --sequence 5 --notes "approved for production" --label "change ticket #1234" -n default`,
+		ValidArgsFunction: completeAppSlugArg,
+		SilenceUsage:      true,
+		SilenceErrors:     false,
+		PreRun: func(cmd *cobra.Command, args []string) {
+			viper.BindPFlags(cmd.Flags())
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			v := viper.GetViper()
+
+			if len(args) != 1 {
+				cmd.Help()
+				return errors.New("app slug is required")
+			}
+			appSlug := args[0]
+
+			if !cmd.Flags().Changed("sequence") {
+				return errors.New("--sequence is required")
+			}
+			sequence, err := cmd.Flags().GetInt64("sequence")
+			if err != nil {
+				return errors.Wrap(err, "failed to get sequence")
+			}
+			labels, err := cmd.Flags().GetStringArray("label")
+			if err != nil {
+				return errors.Wrap(err, "failed to get labels")
+			}
+
+			requestBody, err := json.Marshal(handlertypes.SetAppVersionNotesRequest{
+				Notes:  v.GetString("notes"),
+				Labels: labels,
+			})
+			if err != nil {
+				return errors.Wrap(err, "failed to marshal request json")
+			}
+
+			log := logger.NewCLILogger()
+
+			stopCh := make(chan struct{})
+			defer close(stopCh)
+
+			localPort, authSlug, client, err := forwardToAdminConsole(v, stopCh)
+			if err != nil {
+				return err
+			}
+
+			url := fmt.Sprintf("http://localhost:%d/api/v1/app/%s/sequence/%d/notes", localPort, url.PathEscape(appSlug), sequence)
+			newRequest, err := http.NewRequest("PUT", url, bytes.NewBuffer(requestBody))
+			if err != nil {
+				return errors.Wrap(err, "failed to create http request")
+			}
+			newRequest.Header.Add("Authorization", authSlug)
+			newRequest.Header.Add("Content-Type", "application/json")
+
+			resp, err := client.Do(newRequest)
+			if err != nil {
+				return errors.Wrap(err, "failed to execute http request")
+			}
+			defer resp.Body.Close()
+
+			if resp.StatusCode != http.StatusNoContent {
+				return handlertypes.ErrorFromResponse(resp)
+			}
+
+			log.ActionWithoutSpinner("Notes of version %d of %s were updated", sequence, appSlug)
+
+			return nil
+		},
+	}
+
+	cmd.Flags().StringP("namespace", "n", "", "the namespace in which kots/kotsadm is installed")
+	cmd.Flags().Int64("sequence", 0, "the sequence of the version")
+	cmd.Flags().String("notes", "", "the notes of the version")
+	cmd.Flags().StringArray("label", []string{}, "a label of the version. can be specified multiple times")
+
+	return cmd
+}
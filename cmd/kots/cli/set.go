@@ -30,6 +30,11 @@ func SetCmd() *cobra.Command {
 	}
 
 	cmd.AddCommand(SetConfigCmd())
+	cmd.AddCommand(SetPrometheusCmd())
+	cmd.AddCommand(SetVersionNotesCmd())
+	cmd.AddCommand(SetApplyPolicyCmd())
+	cmd.AddCommand(SetLicenseCmd())
+	cmd.AddCommand(SetReleaseFeedbackCmd())
 
 	return cmd
 }
@@ -0,0 +1,254 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	handlertypes "github.com/replicatedhq/kots/pkg/api/handlers/types"
+	"github.com/replicatedhq/kots/pkg/logger"
+	"github.com/replicatedhq/kots/pkg/print"
+	temporaryaccesstypes "github.com/replicatedhq/kots/pkg/temporaryaccess/types"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+type createTemporaryAccessRequest struct {
+	Description         string `json:"description"`
+	AppSlug             string `json:"appSlug"`
+	AllowSupportBundles bool   `json:"allowSupportBundles"`
+	ExpiresInMinutes    int64  `json:"expiresInMinutes"`
+}
+
+type createTemporaryAccessResponse struct {
+	Grant temporaryaccesstypes.Grant `json:"grant"`
+	Path  string                     `json:"path"`
+}
+
+type listTemporaryAccessResponse struct {
+	Grants []temporaryaccesstypes.Grant `json:"grants"`
+}
+
+func TemporaryAccessCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:           "temporary-access",
+		Short:         "Manage time limited read only access links to the admin console",
+		Long:          `Create, list and revoke access links that give read only access to the admin console until they expire, for example to hand to vendor support during an incident instead of sharing the admin console password. Every use of an access link is logged by the admin console.`,
+		SilenceUsage:  true,
+		SilenceErrors: false,
+		PreRun: func(cmd *cobra.Command, args []string) {
+			viper.BindPFlags(cmd.Flags())
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) == 0 {
+				cmd.Help()
+				os.Exit(1)
+			}
+
+			return nil
+		},
+	}
+
+	cmd.AddCommand(TemporaryAccessCreateCmd())
+	cmd.AddCommand(TemporaryAccessListCmd())
+	cmd.AddCommand(TemporaryAccessRevokeCmd())
+
+	return cmd
+}
+
+func TemporaryAccessCreateCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "create",
+		Short: "Create a read only access link that expires",
+		Long: `Create a link that gives read only access to the admin console, or to one application with --app, until it expires or is revoked. The link is only shown once.
+
+Examples:
+kubectl kots temporary-access create --description "support case 1234" --expires-in 4h --console-url https://admin.example.com:8800 -n default
+kubectl kots temporary-access create --app my-app --allow-support-bundles --expires-in 24h -n default`,
+		SilenceUsage:  true,
+		SilenceErrors: false,
+		PreRun: func(cmd *cobra.Command, args []string) {
+			viper.BindPFlags(cmd.Flags())
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			v := viper.GetViper()
+
+			expiresIn := v.GetDuration("expires-in")
+			if expiresIn <= 0 {
+				return errors.New("--expires-in must be positive")
+			}
+
+			requestBody, err := json.Marshal(createTemporaryAccessRequest{
+				Description:         v.GetString("description"),
+				AppSlug:             v.GetString("app"),
+				AllowSupportBundles: v.GetBool("allow-support-bundles"),
+				ExpiresInMinutes:    int64(expiresIn / time.Minute),
+			})
+			if err != nil {
+				return errors.Wrap(err, "failed to marshal request json")
+			}
+
+			b, err := doTemporaryAccessRequest(v, "POST", "", requestBody, http.StatusCreated)
+			if err != nil {
+				return err
+			}
+
+			response := createTemporaryAccessResponse{}
+			if err := json.Unmarshal(b, &response); err != nil {
+				return errors.Wrap(err, "failed to unmarshal response")
+			}
+
+			consoleURL := strings.TrimSuffix(v.GetString("console-url"), "/")
+			if consoleURL == "" {
+				consoleURL = "http://localhost:8800"
+			}
+
+			log := logger.NewCLILogger()
+			log.ActionWithoutSpinner("Access link %s created, it expires at %s", response.Grant.ID, response.Grant.ExpiresAt.UTC().Format(time.RFC3339))
+			log.ActionWithoutSpinner("")
+			log.Info("%s%s", consoleURL, response.Path)
+			log.ActionWithoutSpinner("")
+			log.ActionWithoutSpinner("The link is not shown again. Revoke it with kubectl kots temporary-access revoke %s", response.Grant.ID)
+
+			return nil
+		},
+	}
+
+	cmd.Flags().StringP("namespace", "n", "", "the namespace in which kots/kotsadm is installed")
+	cmd.Flags().String("description", "", "what the access is for, for example a support case")
+	cmd.Flags().String("app", "", "limit the access to this application")
+	cmd.Flags().Bool("allow-support-bundles", false, "also allow collecting support bundles and running preflight checks")
+	cmd.Flags().Duration("expires-in", 4*time.Hour, "how long the link can be used, at most 72h")
+	cmd.Flags().String("console-url", "", "the url of the admin console to print the link with")
+
+	return cmd
+}
+
+func TemporaryAccessListCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "ls",
+		Aliases: []string{"list"},
+		Short:   "List the access links",
+		Long: `List the access links with whether they are active, expired or revoked.
+
+Examples:
+kubectl kots temporary-access ls -n default`,
+		SilenceUsage:  true,
+		SilenceErrors: false,
+		PreRun: func(cmd *cobra.Command, args []string) {
+			viper.BindPFlags(cmd.Flags())
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			v := viper.GetViper()
+
+			output := v.GetString("output")
+			if output != "json" && output != "" {
+				return errors.Errorf("output format %s not supported (allowed formats are: json)", output)
+			}
+
+			b, err := doTemporaryAccessRequest(v, "GET", "", nil, http.StatusOK)
+			if err != nil {
+				return err
+			}
+
+			response := listTemporaryAccessResponse{}
+			if err := json.Unmarshal(b, &response); err != nil {
+				return errors.Wrap(err, "failed to unmarshal response")
+			}
+
+			print.TemporaryAccess(response.Grants, output)
+
+			return nil
+		},
+	}
+
+	cmd.Flags().StringP("namespace", "n", "", "the namespace in which kots/kotsadm is installed")
+	cmd.Flags().StringP("output", "o", "", "output format. supported values: json")
+
+	return cmd
+}
+
+func TemporaryAccessRevokeCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "revoke [id]",
+		Short: "Revoke an access link",
+		Long: `Revoke an access link before it expires. The sessions that were started with the link are ended.
+
+Examples:
+kubectl kots temporary-access revoke 1zPbIQnzvpLJQ3NFk6ZeNvEp3uG -n default`,
+		SilenceUsage:  true,
+		SilenceErrors: false,
+		PreRun: func(cmd *cobra.Command, args []string) {
+			viper.BindPFlags(cmd.Flags())
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			v := viper.GetViper()
+
+			if len(args) != 1 {
+				cmd.Help()
+				return errors.New("the id of the access link is required")
+			}
+
+			if _, err := doTemporaryAccessRequest(v, "DELETE", args[0], nil, http.StatusOK); err != nil {
+				return err
+			}
+
+			log := logger.NewCLILogger()
+			log.ActionWithoutSpinner("Access link %s revoked", args[0])
+
+			return nil
+		},
+	}
+
+	cmd.Flags().StringP("namespace", "n", "", "the namespace in which kots/kotsadm is installed")
+
+	return cmd
+}
+
+// doTemporaryAccessRequest sends a request to the temporary access api, or to the api of one grant if id is set, and
+// returns the response body
+func doTemporaryAccessRequest(v *viper.Viper, method string, id string, body []byte, expectStatus int) ([]byte, error) {
+	stopCh := make(chan struct{})
+	defer close(stopCh)
+
+	localPort, authSlug, client, err := forwardToAdminConsole(v, stopCh)
+	if err != nil {
+		return nil, err
+	}
+
+	requestURL := fmt.Sprintf("http://localhost:%d/api/v1/temporary-access", localPort)
+	if id != "" {
+		requestURL = fmt.Sprintf("%s/%s", requestURL, url.PathEscape(id))
+	}
+
+	newRequest, err := http.NewRequest(method, requestURL, bytes.NewBuffer(body))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create http request")
+	}
+	newRequest.Header.Add("Authorization", authSlug)
+	newRequest.Header.Add("Content-Type", "application/json")
+
+	resp, err := client.Do(newRequest)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to execute http request")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != expectStatus {
+		return nil, handlertypes.ErrorFromResponse(resp)
+	}
+
+	b, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read response body")
+	}
+
+	return b, nil
+}
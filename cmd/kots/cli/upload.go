@@ -44,6 +44,7 @@ func UploadCmd() *cobra.Command {
 				Endpoint:        "http://localhost:3000",
 				Deploy:          v.GetBool("deploy"),
 				SkipPreflights:  v.GetBool("skip-preflights"),
+				SkipValidation:  v.GetBool("skip-validation"),
 			}
 
 			stopCh := make(chan struct{})
@@ -66,7 +67,7 @@ func UploadCmd() *cobra.Command {
 				}
 			}()
 
-			if err := upload.Upload(sourceDir, uploadOptions); err != nil {
+			if _, err := upload.Upload(sourceDir, uploadOptions); err != nil {
 				return errors.Cause(err)
 			}
 
@@ -80,6 +81,7 @@ func UploadCmd() *cobra.Command {
 
 	cmd.Flags().Bool("deploy", false, "when set, automatically deploy the uploaded version")
 	cmd.Flags().Bool("skip-preflights", false, "set to true to skip preflight checks")
+	cmd.Flags().Bool("skip-validation", false, "set to true to upload without checking that the yaml files parse and the overlays build")
 
 	return cmd
 }
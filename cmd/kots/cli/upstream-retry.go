@@ -0,0 +1,147 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+
+	"github.com/pkg/errors"
+	"github.com/replicatedhq/kots/pkg/auth"
+	"github.com/replicatedhq/kots/pkg/k8sutil"
+	"github.com/replicatedhq/kots/pkg/logger"
+	"github.com/replicatedhq/kots/pkg/upload"
+	"github.com/replicatedhq/kots/pkg/versionskew"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+func UpstreamRetryCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:           "retry [appSlug]",
+		Short:         "Retry downloading application updates that failed in the last update check",
+		Long:          "",
+		SilenceUsage:  true,
+		SilenceErrors: false,
+		PreRun: func(cmd *cobra.Command, args []string) {
+			viper.BindPFlags(cmd.Flags())
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			v := viper.GetViper()
+
+			if len(args) == 0 {
+				cmd.Help()
+				os.Exit(1)
+			}
+
+			appSlug := args[0]
+
+			log := logger.NewCLILogger()
+			log.ActionWithSpinner("Retrying failed update downloads")
+
+			stopCh := make(chan struct{})
+			defer close(stopCh)
+			localPort, errChan, err := upload.StartPortForward(v.GetString("namespace"), stopCh, log)
+			if err != nil {
+				log.FinishSpinnerWithError()
+				return err
+			}
+
+			go func() {
+				select {
+				case err := <-errChan:
+					if err != nil {
+						log.Error(err)
+					}
+				case <-stopCh:
+				}
+			}()
+
+			client, err := versionskew.NewClient(fmt.Sprintf("http://localhost:%d", localPort), log)
+			if err != nil {
+				log.FinishSpinnerWithError()
+				return err
+			}
+
+			urlVals := url.Values{}
+			if v.GetBool("skip-preflights") {
+				urlVals.Set("skipPreflights", "true")
+			}
+			retryURI := fmt.Sprintf("http://localhost:%d/api/v1/app/%s/updates/failed/retry?%s", localPort, url.PathEscape(appSlug), urlVals.Encode())
+
+			clientset, err := k8sutil.GetClientset()
+			if err != nil {
+				log.FinishSpinnerWithError()
+				return errors.Wrap(err, "failed to get k8s clientset")
+			}
+
+			authSlug, err := auth.GetOrCreateAuthSlug(clientset, v.GetString("namespace"))
+			if err != nil {
+				log.FinishSpinnerWithError()
+				log.Info("Unable to authenticate to the Admin Console running in the %s namespace. Ensure you have read access to secrets in this namespace and try again.", v.GetString("namespace"))
+				if v.GetBool("debug") {
+					return withExitCode(ExitCodeAuthFailed, errors.Wrap(err, "failed to get kotsadm auth slug"))
+				}
+				os.Exit(ExitCodeAuthFailed) // not returning error here as we don't want to show the entire stack trace to normal users
+			}
+
+			newReq, err := http.NewRequest("POST", retryURI, nil)
+			if err != nil {
+				log.FinishSpinnerWithError()
+				return errors.Wrap(err, "failed to create retry request")
+			}
+			newReq.Header.Add("Content-Type", "application/json")
+			newReq.Header.Add("Authorization", authSlug)
+			resp, err := client.Do(newReq)
+			if err != nil {
+				log.FinishSpinnerWithError()
+				return errors.Wrap(err, "failed to retry update downloads")
+			}
+			defer resp.Body.Close()
+
+			b, err := ioutil.ReadAll(resp.Body)
+			if err != nil {
+				log.FinishSpinnerWithError()
+				return errors.Wrap(err, "failed to read server response")
+			}
+
+			if resp.StatusCode == 404 {
+				log.FinishSpinnerWithError()
+				return errors.Errorf("The application %s was not found in the cluster in the specified namespace", appSlug)
+			} else if resp.StatusCode != 200 {
+				log.FinishSpinnerWithError()
+				if len(b) != 0 {
+					log.Error(errors.New(string(b)))
+				}
+				return errors.Errorf("Unexpected response from the API: %d", resp.StatusCode)
+			}
+
+			type retryResponse struct {
+				AvailableUpdates int `json:"availableUpdates"`
+			}
+			rr := retryResponse{}
+			if err := json.Unmarshal(b, &rr); err != nil {
+				log.FinishSpinnerWithError()
+				return errors.Wrap(err, "failed to parse response")
+			}
+
+			log.FinishSpinner()
+
+			log.ActionWithoutSpinner("")
+			if rr.AvailableUpdates == 0 {
+				log.ActionWithoutSpinner("There are no failed update downloads to retry")
+			} else {
+				log.ActionWithoutSpinner(fmt.Sprintf("Downloading %d updates in the Admin Console", rr.AvailableUpdates))
+			}
+			log.ActionWithoutSpinner("")
+
+			return nil
+		},
+	}
+
+	cmd.Flags().Bool("skip-preflights", false, "set to true to skip preflight checks")
+
+	return cmd
+}
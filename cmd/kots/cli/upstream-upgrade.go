@@ -16,12 +16,14 @@ import (
 	"github.com/pkg/errors"
 	"github.com/replicatedhq/kots/pkg/auth"
 	"github.com/replicatedhq/kots/pkg/docker/registry"
+	"github.com/replicatedhq/kots/pkg/image"
 	"github.com/replicatedhq/kots/pkg/k8sutil"
 	"github.com/replicatedhq/kots/pkg/kotsadm"
 	kotsadmtypes "github.com/replicatedhq/kots/pkg/kotsadm/types"
 	"github.com/replicatedhq/kots/pkg/kotsutil"
 	"github.com/replicatedhq/kots/pkg/logger"
 	"github.com/replicatedhq/kots/pkg/upload"
+	"github.com/replicatedhq/kots/pkg/versionskew"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 	kustomizetypes "sigs.k8s.io/kustomize/api/types"
@@ -88,6 +90,11 @@ func UpstreamUpgradeCmd() *cobra.Command {
 					return errors.Wrap(err, "failed to extract images")
 				}
 
+				bandwidthLimit, err := image.ParseBandwidthLimit(v.GetString("bandwidth-limit"))
+				if err != nil {
+					return errors.Wrap(err, "failed to parse bandwidth limit")
+				}
+
 				pushOptions := kotsadmtypes.PushImagesOptions{
 					Registry: registry.RegistryOptions{
 						Endpoint:  registryEndpoint,
@@ -95,7 +102,9 @@ func UpstreamUpgradeCmd() *cobra.Command {
 						Username:  registryUsername,
 						Password:  registryPassword,
 					},
-					ProgressWriter: os.Stdout,
+					ProgressWriter:   os.Stdout,
+					AllArchitectures: v.GetBool("all-architectures"),
+					BandwidthLimit:   bandwidthLimit,
 				}
 
 				if v.GetBool("disable-image-push") {
@@ -137,6 +146,12 @@ func UpstreamUpgradeCmd() *cobra.Command {
 				}
 			}()
 
+			client, err := versionskew.NewClient(fmt.Sprintf("http://localhost:%d", localPort), log)
+			if err != nil {
+				log.FinishSpinnerWithError()
+				return err
+			}
+
 			contentType := "application/json"
 
 			var requestBody io.Reader
@@ -198,9 +213,9 @@ func UpstreamUpgradeCmd() *cobra.Command {
 				log.FinishSpinnerWithError()
 				log.Info("Unable to authenticate to the Admin Console running in the %s namespace. Ensure you have read access to secrets in this namespace and try again.", v.GetString("namespace"))
 				if v.GetBool("debug") {
-					return errors.Wrap(err, "failed to get kotsadm auth slug")
+					return withExitCode(ExitCodeAuthFailed, errors.Wrap(err, "failed to get kotsadm auth slug"))
 				}
-				os.Exit(2) // not returning error here as we don't want to show the entire stack trace to normal users
+				os.Exit(ExitCodeAuthFailed) // not returning error here as we don't want to show the entire stack trace to normal users
 			}
 
 			newReq, err := http.NewRequest("POST", updateCheckURI, requestBody)
@@ -210,7 +225,7 @@ func UpstreamUpgradeCmd() *cobra.Command {
 			}
 			newReq.Header.Add("Content-Type", contentType)
 			newReq.Header.Add("Authorization", authSlug)
-			resp, err := http.DefaultClient.Do(newReq)
+			resp, err := client.Do(newReq)
 			if err != nil {
 				log.FinishSpinnerWithError()
 				return errors.Wrap(err, "failed to check for updates")
@@ -297,9 +312,8 @@ func UpstreamUpgradeCmd() *cobra.Command {
 	cmd.Flags().String("registry-username", "", "user name to use to authenticate with the registry")
 	cmd.Flags().String("registry-password", "", "password to use to authenticate with the registry")
 	cmd.Flags().Bool("disable-image-push", false, "set to true to disable images from being pushed to private registry")
-
-	cmd.Flags().Bool("debug", false, "when set, log full error traces in some cases where we provide a pretty message")
-	cmd.Flags().MarkHidden("debug")
+	cmd.Flags().String("bandwidth-limit", "", "maximum rate to push images at, in bytes per second (e.g. 10MB). unlimited by default")
+	cmd.Flags().Bool("all-architectures", false, "push all architectures of multi-arch images instead of only the architectures of the cluster nodes")
 
 	return cmd
 }
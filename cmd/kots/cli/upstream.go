@@ -28,6 +28,7 @@ func UpstreamCmd() *cobra.Command {
 	}
 
 	cmd.AddCommand(UpstreamUpgradeCmd())
+	cmd.AddCommand(UpstreamRetryCmd())
 
 	return cmd
 }
@@ -0,0 +1,96 @@
+package cli
+
+import (
+	"fmt"
+
+	"github.com/pkg/errors"
+	"github.com/replicatedhq/kots/pkg/buildversion"
+	"github.com/replicatedhq/kots/pkg/cliupgrade"
+	"github.com/replicatedhq/kots/pkg/logger"
+	"github.com/replicatedhq/kots/pkg/upload"
+	"github.com/replicatedhq/kots/pkg/versionskew"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+func VersionUpgradeCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "upgrade",
+		Short: "Replace this kots binary with another release",
+		Long: `Download the kots release that matches the Admin Console in the namespace, or the latest release,
+verify it against the checksums published with the release, and replace this binary with it`,
+		SilenceUsage:  true,
+		SilenceErrors: false,
+		PreRun: func(cmd *cobra.Command, args []string) {
+			viper.BindPFlags(cmd.Flags())
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			v := viper.GetViper()
+
+			log := logger.NewCLILogger()
+
+			targetVersion := v.GetString("version")
+			if targetVersion == "" && v.GetBool("match-admin-console") {
+				serverVersion, err := getAdminConsoleVersion(v.GetString("namespace"), log)
+				if err != nil {
+					return errors.Wrap(err, "failed to get admin console version")
+				}
+				if !versionskew.IsReleaseVersion(serverVersion) {
+					return errors.Errorf("the Admin Console does not report a release version (%q), use --version", serverVersion)
+				}
+				targetVersion = serverVersion
+			}
+			if targetVersion == "" {
+				latestVersion, err := buildversion.LatestRelease()
+				if err != nil {
+					return errors.Wrap(err, "failed to get latest release")
+				}
+				targetVersion = latestVersion
+			}
+
+			if targetVersion == buildversion.Version() {
+				log.Info("kots is already at version %s", targetVersion)
+				return nil
+			}
+
+			log.ActionWithSpinner("Upgrading kots from %s to %s", buildversion.Version(), targetVersion)
+			if err := cliupgrade.Upgrade(cliupgrade.Options{Version: targetVersion}); err != nil {
+				log.FinishSpinnerWithError()
+				return errors.Wrap(err, "failed to upgrade kots")
+			}
+			log.FinishSpinner()
+
+			return nil
+		},
+	}
+
+	cmd.Flags().String("version", "", "the release to install, e.g. v1.50.0. the latest release is installed if not set")
+	cmd.Flags().Bool("match-admin-console", false, "install the release of the Admin Console in the namespace")
+
+	return cmd
+}
+
+func getAdminConsoleVersion(namespace string, log *logger.CLILogger) (string, error) {
+	if err := validateNamespace(namespace); err != nil {
+		return "", errors.Wrap(err, "failed to validate namespace")
+	}
+
+	stopCh := make(chan struct{})
+	defer close(stopCh)
+
+	localPort, errChan, err := upload.StartPortForward(namespace, stopCh, log)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to start port forwarding")
+	}
+	go func() {
+		select {
+		case err := <-errChan:
+			if err != nil {
+				log.Error(err)
+			}
+		case <-stopCh:
+		}
+	}()
+
+	return versionskew.GetServerVersion(fmt.Sprintf("http://localhost:%d", localPort))
+}
@@ -19,11 +19,14 @@ func VersionCmd() *cobra.Command {
 			// check if this is the latest release, and display possible upgrade instructions
 			isLatest, latestVer, err := buildversion.IsLatestRelease()
 			if err == nil && !isLatest {
-				fmt.Printf("\nVersion %s is available for kots. To install updates, run\n  $ curl https://kots.io/install | bash\n", latestVer)
+				fmt.Printf("\nVersion %s is available for kots. To install updates, run\n  $ kots version upgrade\n", latestVer)
 			}
 
 			return nil
 		},
 	}
+
+	cmd.AddCommand(VersionUpgradeCmd())
+
 	return cmd
 }
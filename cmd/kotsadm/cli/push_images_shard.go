@@ -0,0 +1,43 @@
+package cli
+
+import (
+	"context"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/replicatedhq/kots/pkg/imagepushjob"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+func PushImagesShardCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:    "push-images-shard",
+		Short:  "Pushes a shard of the images of an airgap bundle to the registry",
+		Long:   `Run by the jobs that kotsadm starts to push the images of large airgap bundles in parallel`,
+		Hidden: true,
+		PreRun: func(cmd *cobra.Command, args []string) {
+			viper.BindPFlags(cmd.Flags())
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			v := viper.GetViper()
+
+			pushID := v.GetString("push-id")
+			if pushID == "" {
+				return errors.New("--push-id is required")
+			}
+
+			if err := imagepushjob.RunShard(context.Background(), pushID, v.GetInt("shard")); err != nil {
+				return errors.Wrap(err, "failed to push images")
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().String("push-id", "", "the id of the image push")
+	cmd.Flags().Int("shard", 0, "the index of the shard to push")
+
+	viper.SetEnvKeyReplacer(strings.NewReplacer("-", "_"))
+
+	return cmd
+}
@@ -1,6 +1,9 @@
 package cli
 
 import (
+	"crypto/ed25519"
+	"encoding/hex"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -28,6 +31,13 @@ of a gitops pipeline without setting up a full end-to-end gitops delivery proces
 				Token:           v.GetString("token"),
 				TargetNamespace: v.GetString("target-namespace"),
 			}
+			if publicKey := strings.TrimSpace(v.GetString("manifest-signing-public-key")); publicKey != "" {
+				decoded, err := hex.DecodeString(publicKey)
+				if err != nil || len(decoded) != ed25519.PublicKeySize {
+					return errors.New("manifest signing public key must be a hex encoded ed25519 public key")
+				}
+				c.ManifestSigningPublicKey = ed25519.PublicKey(decoded)
+			}
 			c.ExistingInformers = map[string]bool{}
 			c.HookStopChans = []chan struct{}{}
 			return c.Run()
@@ -39,6 +49,7 @@ of a gitops pipeline without setting up a full end-to-end gitops delivery proces
 	cmd.Flags().String("api-endpoint", "http://kotsadm:8880", "the endpoint of the kotsadm api server to connect to")
 	cmd.Flags().String("token", "", "the token of the cluster")
 	cmd.Flags().String("target-namespace", "", "the namespace to deploy the application to")
+	cmd.Flags().String("manifest-signing-public-key", "", "the hex encoded public key that the manifests of deploys must be signed with. manifests are not verified if empty")
 
 	cmd.Flags().String("kubeconfig", filepath.Join(homeDir(), ".kube", "config"), "the kubeconfig to use when connecting to the cluster")
 
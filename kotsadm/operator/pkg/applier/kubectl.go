@@ -7,6 +7,7 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"time"
 
 	"github.com/pkg/errors"
 	rest "k8s.io/client-go/rest"
@@ -15,12 +16,15 @@ import (
 type Kubectl struct {
 	kubectl string
 	config  *rest.Config
+	// requestTimeout is the timeout of each request to the api server, kubectl's default is used if zero
+	requestTimeout time.Duration
 }
 
-func NewKubectl(kubectl string, config *rest.Config) *Kubectl {
+func NewKubectl(kubectl string, config *rest.Config, requestTimeout time.Duration) *Kubectl {
 	return &Kubectl{
-		kubectl: kubectl,
-		config:  config,
+		kubectl:        kubectl,
+		config:         config,
+		requestTimeout: requestTimeout,
 	}
 }
 
@@ -48,6 +52,12 @@ func (c *Kubectl) connectArgs() []string {
 	if c.config.BearerToken != "" {
 		args = append(args, fmt.Sprintf("--token=%s", c.config.BearerToken))
 	}
+	if c.config.Impersonate.UserName != "" {
+		args = append(args, fmt.Sprintf("--as=%s", c.config.Impersonate.UserName))
+	}
+	if c.requestTimeout > 0 {
+		args = append(args, fmt.Sprintf("--request-timeout=%s", c.requestTimeout))
+	}
 	return args
 }
 
@@ -132,6 +142,23 @@ commonAnnotations:
 	return stdout, stderr, errors.Wrap(err, "failed to run kubectl apply")
 }
 
+// WaitForCondition waits until the resources in the yaml have the condition or the timeout expires
+func (c *Kubectl) WaitForCondition(yamlDoc []byte, condition string, timeout time.Duration) ([]byte, []byte, error) {
+	args := []string{
+		"wait",
+		fmt.Sprintf("--for=condition=%s", condition),
+		fmt.Sprintf("--timeout=%s", timeout),
+		"-f",
+		"-",
+	}
+
+	cmd := c.kubectlCommand(args...)
+	cmd.Stdin = bytes.NewReader(yamlDoc)
+
+	stdout, stderr, err := Run(cmd)
+	return stdout, stderr, errors.Wrap(err, "failed to run kubectl wait")
+}
+
 func (c *Kubectl) kubectlCommand(args ...string) *exec.Cmd {
 	return exec.Command(c.kubectl, append(args, c.connectArgs()...)...)
 }
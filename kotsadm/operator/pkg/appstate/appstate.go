@@ -7,16 +7,24 @@ import (
 	"time"
 
 	"github.com/replicatedhq/kots/kotsadm/operator/pkg/appstate/types"
+	"github.com/replicatedhq/kots/kotsadm/operator/pkg/util"
 	corev1 "k8s.io/api/core/v1"
+	k8sschema "k8s.io/apimachinery/pkg/runtime/schema"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/dynamic/dynamicinformer"
+	kubeinformers "k8s.io/client-go/informers"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/tools/cache"
 )
 
 type Monitor struct {
 	clientset       kubernetes.Interface
+	dynamicClient   dynamic.Interface
+	mapper          *customResourceMapper
 	targetNamespace string
 	appInformersCh  chan appInformer
+	appRemoveCh     chan string
 	appStatusCh     chan types.AppStatus
 	cancel          context.CancelFunc
 }
@@ -33,15 +41,18 @@ type appInformer struct {
 	informers []types.StatusInformer
 }
 
-func NewMonitor(clientset kubernetes.Interface, targetNamespace string) *Monitor {
+func NewMonitor(clientset kubernetes.Interface, dynamicClient dynamic.Interface, targetNamespace string) *Monitor {
 	if targetNamespace == "" {
 		targetNamespace = corev1.NamespaceDefault
 	}
 	ctx, cancel := context.WithCancel(context.Background())
 	m := &Monitor{
 		clientset:       clientset,
+		dynamicClient:   dynamicClient,
+		mapper:          newCustomResourceMapper(clientset.Discovery()),
 		targetNamespace: targetNamespace,
 		appInformersCh:  make(chan appInformer),
+		appRemoveCh:     make(chan string),
 		appStatusCh:     make(chan types.AppStatus),
 		cancel:          cancel,
 	}
@@ -65,6 +76,11 @@ func (m *Monitor) Apply(appID string, sequence int64, informers []types.StatusIn
 	}
 }
 
+// Remove stops watching the status informers of an app
+func (m *Monitor) Remove(appID string) {
+	m.appRemoveCh <- appID
+}
+
 func (m *Monitor) AppStatusChan() <-chan types.AppStatus {
 	return m.appStatusCh
 }
@@ -92,7 +108,7 @@ func (m *Monitor) run(ctx context.Context) {
 				if appMonitor != nil {
 					appMonitor.Shutdown()
 				}
-				appMonitor = NewAppMonitor(m.clientset, m.targetNamespace, appInformer.appID, appInformer.sequence)
+				appMonitor = NewAppMonitor(m.clientset, m.dynamicClient, m.mapper, m.targetNamespace, appInformer.appID, appInformer.sequence)
 				go func() {
 					for appStatus := range appMonitor.AppStatusChan() {
 						m.appStatusCh <- appStatus
@@ -101,12 +117,20 @@ func (m *Monitor) run(ctx context.Context) {
 				appMonitors[appInformer.appID] = appMonitor
 			}
 			appMonitor.Apply(appInformer.informers)
+
+		case appID := <-m.appRemoveCh:
+			if appMonitor, ok := appMonitors[appID]; ok {
+				appMonitor.Shutdown()
+				delete(appMonitors, appID)
+			}
 		}
 	}
 }
 
 type AppMonitor struct {
 	clientset       kubernetes.Interface
+	dynamicClient   dynamic.Interface
+	mapper          *customResourceMapper
 	targetNamespace string
 	appID           string
 	informersCh     chan []types.StatusInformer
@@ -115,11 +139,13 @@ type AppMonitor struct {
 	sequence        int64
 }
 
-func NewAppMonitor(clientset kubernetes.Interface, targetNamespace, appID string, sequence int64) *AppMonitor {
+func NewAppMonitor(clientset kubernetes.Interface, dynamicClient dynamic.Interface, mapper *customResourceMapper, targetNamespace, appID string, sequence int64) *AppMonitor {
 	ctx, cancel := context.WithCancel(context.Background())
 	m := &AppMonitor{
 		appID:           appID,
 		clientset:       clientset,
+		dynamicClient:   dynamicClient,
+		mapper:          mapper,
 		targetNamespace: targetNamespace,
 		informersCh:     make(chan []types.StatusInformer),
 		appStatusCh:     make(chan types.AppStatus),
@@ -171,12 +197,21 @@ func (m *AppMonitor) run(ctx context.Context) {
 	}
 }
 
-type runControllerFunc func(context.Context, kubernetes.Interface, string, []types.StatusInformer, chan<- types.ResourceState)
+const (
+	// informerResyncPeriod is how often the cached resources are replayed to the event handlers. Status changes are
+	// sent by watches as they happen, including changes to the endpoints of services and ingresses, so the resync is
+	// only a safety net.
+	informerResyncPeriod = 10 * time.Minute
+)
+
+// runControllerFunc adds the event handlers of a resource kind to the informers of the namespace's shared informer
+// factory, and returns the informers it uses
+type runControllerFunc func(kubernetes.Interface, kubeinformers.SharedInformerFactory, []types.StatusInformer, chan<- types.ResourceState) []cache.SharedIndexInformer
 
 func (m *AppMonitor) runInformers(ctx context.Context, informers []types.StatusInformer) {
 	informers = normalizeStatusInformers(informers, m.targetNamespace)
 
-	log.Printf("Running informers: %#v", informers)
+	util.Debugf("Running informers: %#v", informers)
 
 	appStatus := types.AppStatus{
 		AppID:          m.appID,
@@ -204,10 +239,10 @@ func (m *AppMonitor) runInformers(ctx context.Context, informers []types.StatusI
 		namespaceKinds[informer.Namespace] = kindsInNs
 	}
 
-	goRun := func(fn runControllerFunc, namespace string, informers []types.StatusInformer) {
+	goRun := func(informer cache.SharedIndexInformer) {
 		shutdown.Add(1)
 		go func() {
-			fn(ctx, m.clientset, namespace, informers, resourceStateCh)
+			runInformer(ctx, informer)
 			shutdown.Done()
 		}()
 	}
@@ -219,13 +254,42 @@ func (m *AppMonitor) runInformers(ctx context.Context, informers []types.StatusI
 		ServiceResourceKind:               runServiceController,
 		StatefulSetResourceKind:           runStatefulSetController,
 	}
+	// kinds without readiness logic in kots are watched with the dynamic client, they are resolved once for all
+	// namespaces
+	customKinds := []string{}
+	for _, kinds := range namespaceKinds {
+		for kind := range kinds {
+			if _, ok := kindImpls[kind]; !ok {
+				customKinds = append(customKinds, kind)
+			}
+		}
+	}
+	customResources := map[string]k8sschema.GroupVersionResource{}
+	if len(customKinds) > 0 {
+		customResources = resolveCustomResourceKinds(m.mapper, customKinds)
+	}
+
 	for namespace, kinds := range namespaceKinds {
+		// the kinds of a namespace share one watch per resource type, e.g. services and ingresses both watch
+		// endpoints
+		factory := kubeinformers.NewSharedInformerFactoryWithOptions(m.clientset, informerResyncPeriod, kubeinformers.WithNamespace(namespace))
+		dynamicFactory := dynamicinformer.NewFilteredDynamicSharedInformerFactory(m.dynamicClient, informerResyncPeriod, namespace, nil)
+		used := map[cache.SharedIndexInformer]bool{}
 		for kind, informers := range kinds {
-			if impl, ok := kindImpls[kind]; ok {
-				goRun(impl, namespace, informers)
-			} else {
-				log.Printf("Informer requested for unsupported resource kind %v", kind)
+			impl, ok := kindImpls[kind]
+			if !ok {
+				if gvr, ok := customResources[kind]; ok {
+					used[runCustomResourceController(dynamicFactory, gvr, kind, informers, resourceStateCh)] = true
+				}
+				continue
 			}
+			for _, informer := range impl(m.clientset, factory, informers, resourceStateCh) {
+				used[informer] = true
+			}
+		}
+		// the informers run once all of their event handlers are added
+		for informer := range used {
+			goRun(informer)
 		}
 	}
 
@@ -241,9 +305,13 @@ func (m *AppMonitor) runInformers(ctx context.Context, informers []types.StatusI
 	}
 }
 
-func runInformer(ctx context.Context, informer cache.SharedInformer, eventHandler EventHandler) {
+func runInformer(ctx context.Context, informer cache.SharedInformer) {
 	defer utilruntime.HandleCrash()
 
+	informer.Run(ctx.Done())
+}
+
+func addEventHandler(informer cache.SharedInformer, eventHandler EventHandler) {
 	informer.AddEventHandler(cache.ResourceEventHandlerFuncs{
 		AddFunc: func(obj interface{}) {
 			eventHandler.ObjectCreated(obj)
@@ -255,6 +323,16 @@ func runInformer(ctx context.Context, informer cache.SharedInformer, eventHandle
 			eventHandler.ObjectDeleted(obj)
 		},
 	})
+}
 
-	informer.Run(ctx.Done())
+// addChangeHandler calls fn with the object of every event of the informer, for resources that other resources'
+// states depend on
+func addChangeHandler(informer cache.SharedInformer, fn func(obj interface{})) {
+	informer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: fn,
+		UpdateFunc: func(old, new interface{}) {
+			fn(new)
+		},
+		DeleteFunc: fn,
+	})
 }
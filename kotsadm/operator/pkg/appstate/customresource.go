@@ -0,0 +1,140 @@
+package appstate
+
+import (
+	"log"
+
+	"github.com/pkg/errors"
+	"github.com/replicatedhq/kots/kotsadm/operator/pkg/appstate/types"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	k8sschema "k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/discovery/cached/memory"
+	"k8s.io/client-go/dynamic/dynamicinformer"
+	"k8s.io/client-go/restmapper"
+	"k8s.io/client-go/tools/cache"
+)
+
+// customResourceMapper resolves the kinds of status informers that have no readiness logic in kots, such as
+// "certificates.cert-manager.io", to the resources the cluster serves
+type customResourceMapper struct {
+	mapper *restmapper.DeferredDiscoveryRESTMapper
+}
+
+func newCustomResourceMapper(discoveryClient discovery.DiscoveryInterface) *customResourceMapper {
+	return &customResourceMapper{
+		mapper: restmapper.NewDeferredDiscoveryRESTMapper(memory.NewMemCacheClient(discoveryClient)),
+	}
+}
+
+func (m *customResourceMapper) resourceFor(kind string) (k8sschema.GroupVersionResource, error) {
+	return m.mapper.ResourceFor(k8sschema.ParseGroupResource(kind).WithVersion(""))
+}
+
+// runCustomResourceController watches resources of a kind that has no readiness logic in kots. These resources are
+// ready when their readiness expression annotation is satisfied, or as soon as they exist if they have none.
+func runCustomResourceController(
+	factory dynamicinformer.DynamicSharedInformerFactory, gvr k8sschema.GroupVersionResource, kind string,
+	informers []types.StatusInformer, resourceStateCh chan<- types.ResourceState,
+) cache.SharedIndexInformer {
+	informer := factory.ForResource(gvr).Informer()
+
+	eventHandler := NewCustomResourceEventHandler(
+		kind,
+		filterStatusInformersByResourceKind(informers, kind),
+		resourceStateCh,
+	)
+	addEventHandler(informer, eventHandler)
+
+	return informer
+}
+
+type customResourceEventHandler struct {
+	kind            string
+	informers       []types.StatusInformer
+	resourceStateCh chan<- types.ResourceState
+}
+
+func NewCustomResourceEventHandler(kind string, informers []types.StatusInformer, resourceStateCh chan<- types.ResourceState) *customResourceEventHandler {
+	return &customResourceEventHandler{
+		kind:            kind,
+		informers:       informers,
+		resourceStateCh: resourceStateCh,
+	}
+}
+
+func (h *customResourceEventHandler) ObjectCreated(obj interface{}) {
+	r := h.cast(obj)
+	if _, ok := h.getInformer(r); !ok {
+		return
+	}
+	h.resourceStateCh <- h.makeResourceState(r, calculateCustomResourceState(r))
+}
+
+func (h *customResourceEventHandler) ObjectUpdated(obj interface{}) {
+	r := h.cast(obj)
+	if _, ok := h.getInformer(r); !ok {
+		return
+	}
+	h.resourceStateCh <- h.makeResourceState(r, calculateCustomResourceState(r))
+}
+
+func (h *customResourceEventHandler) ObjectDeleted(obj interface{}) {
+	r := h.cast(obj)
+	if _, ok := h.getInformer(r); !ok {
+		return
+	}
+	h.resourceStateCh <- h.makeResourceState(r, types.StateMissing)
+}
+
+func (h *customResourceEventHandler) cast(obj interface{}) *unstructured.Unstructured {
+	if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+		obj = tombstone.Obj
+	}
+	r, _ := obj.(*unstructured.Unstructured)
+	return r
+}
+
+func (h *customResourceEventHandler) getInformer(r *unstructured.Unstructured) (types.StatusInformer, bool) {
+	if r != nil {
+		for _, informer := range h.informers {
+			if r.GetNamespace() == informer.Namespace && r.GetName() == informer.Name {
+				return informer, true
+			}
+		}
+	}
+	return types.StatusInformer{}, false
+}
+
+func (h *customResourceEventHandler) makeResourceState(r *unstructured.Unstructured, state types.State) types.ResourceState {
+	return types.ResourceState{
+		Kind:      h.kind,
+		Name:      r.GetName(),
+		Namespace: r.GetNamespace(),
+		State:     state,
+	}
+}
+
+func calculateCustomResourceState(r *unstructured.Unstructured) types.State {
+	if state, ok := readinessExpressionState(r); ok {
+		return state
+	}
+	return types.StateReady
+}
+
+// resolveCustomResourceKinds returns the resources of the kinds, the kinds that the cluster does not serve are logged
+// and left missing
+func resolveCustomResourceKinds(mapper *customResourceMapper, kinds []string) map[string]k8sschema.GroupVersionResource {
+	// the custom resource definitions may have been applied since the last time the kinds were resolved
+	mapper.mapper.Reset()
+
+	resolved := map[string]k8sschema.GroupVersionResource{}
+	for _, kind := range kinds {
+		gvr, err := mapper.resourceFor(kind)
+		if err != nil {
+			log.Printf("Informer requested for unsupported resource kind %v: %v", kind, errors.Cause(err))
+			continue
+		}
+		resolved[kind] = gvr
+	}
+	return resolved
+}
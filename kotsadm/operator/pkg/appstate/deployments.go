@@ -1,14 +1,9 @@
 package appstate
 
 import (
-	"context"
-	"time"
-
 	"github.com/replicatedhq/kots/kotsadm/operator/pkg/appstate/types"
 	appsv1 "k8s.io/api/apps/v1"
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"k8s.io/apimachinery/pkg/runtime"
-	"k8s.io/apimachinery/pkg/watch"
+	kubeinformers "k8s.io/client-go/informers"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/tools/cache"
 )
@@ -22,30 +17,18 @@ func init() {
 }
 
 func runDeploymentController(
-	ctx context.Context, clientset kubernetes.Interface, targetNamespace string,
+	clientset kubernetes.Interface, factory kubeinformers.SharedInformerFactory,
 	informers []types.StatusInformer, resourceStateCh chan<- types.ResourceState,
-) {
-	listwatch := &cache.ListWatch{
-		ListFunc: func(options metav1.ListOptions) (runtime.Object, error) {
-			return clientset.AppsV1().Deployments(targetNamespace).List(context.TODO(), options)
-		},
-		WatchFunc: func(options metav1.ListOptions) (watch.Interface, error) {
-			return clientset.AppsV1().Deployments(targetNamespace).Watch(context.TODO(), options)
-		},
-	}
-	informer := cache.NewSharedInformer(
-		listwatch,
-		&appsv1.Deployment{},
-		time.Minute,
-	)
+) []cache.SharedIndexInformer {
+	informer := factory.Apps().V1().Deployments().Informer()
 
 	eventHandler := NewDeploymentEventHandler(
 		filterStatusInformersByResourceKind(informers, DeploymentResourceKind),
 		resourceStateCh,
 	)
+	addEventHandler(informer, eventHandler)
 
-	runInformer(ctx, informer, eventHandler)
-	return
+	return []cache.SharedIndexInformer{informer}
 }
 
 type deploymentEventHandler struct {
@@ -110,6 +93,9 @@ func makeDeploymentResourceState(r *appsv1.Deployment, state types.State) types.
 }
 
 func calculateDeploymentState(r *appsv1.Deployment) types.State {
+	if state, ok := readinessExpressionState(r); ok {
+		return state
+	}
 	// https://github.com/kubernetes/kubernetes/blob/badcd4af3f592376ce891b7c1b7a43ed6a18a348/pkg/printers/internalversion/printers.go#L1652
 	var desiredReplicas int32
 	if r.Spec.Replicas == nil {
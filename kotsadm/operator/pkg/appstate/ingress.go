@@ -2,15 +2,15 @@ package appstate
 
 import (
 	"context"
-	"time"
 
 	"github.com/replicatedhq/kots/kotsadm/operator/pkg/appstate/types"
 	extensions "k8s.io/api/extensions/v1beta1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/util/intstr"
-	"k8s.io/apimachinery/pkg/watch"
+	kubeinformers "k8s.io/client-go/informers"
 	"k8s.io/client-go/kubernetes"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+	extensionslisters "k8s.io/client-go/listers/extensions/v1beta1"
 	"k8s.io/client-go/tools/cache"
 )
 
@@ -23,44 +23,48 @@ func init() {
 }
 
 func runIngressController(
-	ctx context.Context, clientset kubernetes.Interface, targetNamespace string,
+	clientset kubernetes.Interface, factory kubeinformers.SharedInformerFactory,
 	informers []types.StatusInformer, resourceStateCh chan<- types.ResourceState,
-) {
-	listwatch := &cache.ListWatch{
-		ListFunc: func(options metav1.ListOptions) (runtime.Object, error) {
-			return clientset.ExtensionsV1beta1().Ingresses(targetNamespace).List(context.TODO(), options)
-		},
-		WatchFunc: func(options metav1.ListOptions) (watch.Interface, error) {
-			return clientset.ExtensionsV1beta1().Ingresses(targetNamespace).Watch(context.TODO(), options)
-		},
-	}
-	informer := cache.NewSharedInformer(
-		listwatch,
-		&extensions.Ingress{},
-		// NOTE: ingresses rely on endpoint and service status as well so unless we add
-		// additional informers, we have to resync more frequently.
-		10*time.Second,
-	)
+) []cache.SharedIndexInformer {
+	ingressInformer := factory.Extensions().V1beta1().Ingresses()
+	serviceInformer := factory.Core().V1().Services()
+	endpointsInformer := factory.Core().V1().Endpoints()
 
 	eventHandler := NewIngressEventHandler(
 		clientset,
+		ingressInformer.Lister(),
+		serviceInformer.Lister(),
+		endpointsInformer.Lister(),
 		filterStatusInformersByResourceKind(informers, IngressResourceKind),
 		resourceStateCh,
 	)
+	addEventHandler(ingressInformer.Informer(), eventHandler)
+	// the state of an ingress depends on the endpoints of its backend services
+	addChangeHandler(serviceInformer.Informer(), eventHandler.BackendChanged)
+	addChangeHandler(endpointsInformer.Informer(), eventHandler.BackendChanged)
 
-	runInformer(ctx, informer, eventHandler)
-	return
+	return []cache.SharedIndexInformer{ingressInformer.Informer(), serviceInformer.Informer(), endpointsInformer.Informer()}
 }
 
 type ingressEventHandler struct {
 	clientset       kubernetes.Interface
+	ingressLister   extensionslisters.IngressLister
+	serviceLister   corev1listers.ServiceLister
+	endpointsLister corev1listers.EndpointsLister
 	informers       []types.StatusInformer
 	resourceStateCh chan<- types.ResourceState
 }
 
-func NewIngressEventHandler(clientset kubernetes.Interface, informers []types.StatusInformer, resourceStateCh chan<- types.ResourceState) *ingressEventHandler {
+func NewIngressEventHandler(
+	clientset kubernetes.Interface, ingressLister extensionslisters.IngressLister,
+	serviceLister corev1listers.ServiceLister, endpointsLister corev1listers.EndpointsLister,
+	informers []types.StatusInformer, resourceStateCh chan<- types.ResourceState,
+) *ingressEventHandler {
 	return &ingressEventHandler{
 		clientset:       clientset,
+		ingressLister:   ingressLister,
+		serviceLister:   serviceLister,
+		endpointsLister: endpointsLister,
 		informers:       informers,
 		resourceStateCh: resourceStateCh,
 	}
@@ -71,7 +75,7 @@ func (h *ingressEventHandler) ObjectCreated(obj interface{}) {
 	if _, ok := h.getInformer(r); !ok {
 		return
 	}
-	h.resourceStateCh <- makeIngressResourceState(r, calculateIngressState(h.clientset, r))
+	h.resourceStateCh <- makeIngressResourceState(r, h.calculateIngressState(r))
 }
 
 func (h *ingressEventHandler) ObjectUpdated(obj interface{}) {
@@ -79,7 +83,7 @@ func (h *ingressEventHandler) ObjectUpdated(obj interface{}) {
 	if _, ok := h.getInformer(r); !ok {
 		return
 	}
-	h.resourceStateCh <- makeIngressResourceState(r, calculateIngressState(h.clientset, r))
+	h.resourceStateCh <- makeIngressResourceState(r, h.calculateIngressState(r))
 }
 
 func (h *ingressEventHandler) ObjectDeleted(obj interface{}) {
@@ -90,6 +94,28 @@ func (h *ingressEventHandler) ObjectDeleted(obj interface{}) {
 	h.resourceStateCh <- makeIngressResourceState(r, types.StateMissing)
 }
 
+// BackendChanged updates the state of the ingresses that have the service, or the service of the endpoints, as a
+// backend
+func (h *ingressEventHandler) BackendChanged(obj interface{}) {
+	o, ok := obj.(metav1.Object)
+	if !ok {
+		return
+	}
+	for _, informer := range h.informers {
+		if informer.Namespace != o.GetNamespace() {
+			continue
+		}
+		r, err := h.ingressLister.Ingresses(informer.Namespace).Get(informer.Name)
+		if err != nil {
+			continue
+		}
+		if !ingressHasBackendService(r, o.GetName()) {
+			continue
+		}
+		h.resourceStateCh <- makeIngressResourceState(r, h.calculateIngressState(r))
+	}
+}
+
 func (h *ingressEventHandler) cast(obj interface{}) *extensions.Ingress {
 	r, _ := obj.(*extensions.Ingress)
 	return r
@@ -115,7 +141,10 @@ func makeIngressResourceState(r *extensions.Ingress, state types.State) types.Re
 	}
 }
 
-func calculateIngressState(clientset kubernetes.Interface, r *extensions.Ingress) types.State {
+func (h *ingressEventHandler) calculateIngressState(r *extensions.Ingress) types.State {
+	if state, ok := readinessExpressionState(r); ok {
+		return state
+	}
 	var states []types.State
 	// https://github.com/kubernetes/kubectl/blob/6b77b0790ab40d2a692ad80e9e4c962e784bb9b8/pkg/describe/versioned/describe.go#L2367
 	backend := r.Spec.Backend
@@ -129,10 +158,10 @@ func calculateIngressState(clientset kubernetes.Interface, r *extensions.Ingress
 		}
 		ns = metav1.NamespaceSystem
 	}
-	states = append(states, ingressGetStateFromBackend(clientset, ns, *backend))
+	states = append(states, h.getStateFromBackend(r.Namespace, ns, *backend))
 	for _, rules := range r.Spec.Rules {
 		for _, path := range rules.HTTP.Paths {
-			states = append(states, ingressGetStateFromBackend(clientset, r.Namespace, path.Backend))
+			states = append(states, h.getStateFromBackend(r.Namespace, r.Namespace, path.Backend))
 		}
 	}
 	// https://github.com/kubernetes/kubernetes/blob/badcd4af3f592376ce891b7c1b7a43ed6a18a348/pkg/printers/internalversion/printers.go#L1067
@@ -140,12 +169,51 @@ func calculateIngressState(clientset kubernetes.Interface, r *extensions.Ingress
 	return types.MinState(states...)
 }
 
+// getStateFromBackend reads the backend service from the informer cache of the ingress namespace. Only that namespace
+// is watched, so the default backend in kube-system is read from the api.
+func (h *ingressEventHandler) getStateFromBackend(ingressNamespace string, namespace string, backend extensions.IngressBackend) types.State {
+	if namespace != ingressNamespace {
+		return ingressGetStateFromBackend(h.clientset, namespace, backend)
+	}
+	service, err := h.serviceLister.Services(namespace).Get(backend.ServiceName)
+	if err != nil {
+		return types.StateUnavailable
+	}
+	endpoints, err := h.endpointsLister.Endpoints(namespace).Get(backend.ServiceName)
+	if err != nil {
+		endpoints = nil
+	}
+	return serviceGetStateFromEndpoints(service, endpoints)
+}
+
 func ingressGetStateFromBackend(clientset kubernetes.Interface, namespace string, backend extensions.IngressBackend) (minState types.State) {
-	service, _ := clientset.CoreV1().Services(namespace).Get(context.TODO(), backend.ServiceName, metav1.GetOptions{})
-	if service == nil {
+	service, err := clientset.CoreV1().Services(namespace).Get(context.TODO(), backend.ServiceName, metav1.GetOptions{})
+	if err != nil {
 		return types.StateUnavailable
 	}
-	return serviceGetStateFromEndpoints(clientset, service)
+	endpoints, err := clientset.CoreV1().Endpoints(namespace).Get(context.TODO(), backend.ServiceName, metav1.GetOptions{})
+	if err != nil {
+		endpoints = nil
+	}
+	return serviceGetStateFromEndpoints(service, endpoints)
+}
+
+// ingressHasBackendService returns true if the service is the default backend or a path backend of the ingress
+func ingressHasBackendService(r *extensions.Ingress, serviceName string) bool {
+	if r.Spec.Backend != nil && r.Spec.Backend.ServiceName == serviceName {
+		return true
+	}
+	for _, rules := range r.Spec.Rules {
+		if rules.HTTP == nil {
+			continue
+		}
+		for _, path := range rules.HTTP.Paths {
+			if path.Backend.ServiceName == serviceName {
+				return true
+			}
+		}
+	}
+	return false
 }
 
 func ingressGetStateFromExternalIP(ing *extensions.Ingress) types.State {
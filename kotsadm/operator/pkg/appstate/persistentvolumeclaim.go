@@ -1,14 +1,9 @@
 package appstate
 
 import (
-	"context"
-	"time"
-
 	"github.com/replicatedhq/kots/kotsadm/operator/pkg/appstate/types"
 	corev1 "k8s.io/api/core/v1"
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"k8s.io/apimachinery/pkg/runtime"
-	"k8s.io/apimachinery/pkg/watch"
+	kubeinformers "k8s.io/client-go/informers"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/tools/cache"
 )
@@ -22,30 +17,18 @@ func init() {
 }
 
 func runPersistentVolumeClaimController(
-	ctx context.Context, clientset kubernetes.Interface, targetNamespace string,
+	clientset kubernetes.Interface, factory kubeinformers.SharedInformerFactory,
 	informers []types.StatusInformer, resourceStateCh chan<- types.ResourceState,
-) {
-	listwatch := &cache.ListWatch{
-		ListFunc: func(options metav1.ListOptions) (runtime.Object, error) {
-			return clientset.CoreV1().PersistentVolumeClaims(targetNamespace).List(context.TODO(), options)
-		},
-		WatchFunc: func(options metav1.ListOptions) (watch.Interface, error) {
-			return clientset.CoreV1().PersistentVolumeClaims(targetNamespace).Watch(context.TODO(), options)
-		},
-	}
-	informer := cache.NewSharedInformer(
-		listwatch,
-		&corev1.PersistentVolumeClaim{},
-		time.Minute,
-	)
+) []cache.SharedIndexInformer {
+	informer := factory.Core().V1().PersistentVolumeClaims().Informer()
 
 	eventHandler := NewPersistentVolumeClaimEventHandler(
 		filterStatusInformersByResourceKind(informers, PersistentVolumeClaimResourceKind),
 		resourceStateCh,
 	)
+	addEventHandler(informer, eventHandler)
 
-	runInformer(ctx, informer, eventHandler)
-	return
+	return []cache.SharedIndexInformer{informer}
 }
 
 type persistentVolumeClaimEventHandler struct {
@@ -110,6 +93,9 @@ func makePersistentVolumeClaimResourceState(r *corev1.PersistentVolumeClaim, sta
 }
 
 func calculatePersistentVolumeClaimState(r *corev1.PersistentVolumeClaim) types.State {
+	if state, ok := readinessExpressionState(r); ok {
+		return state
+	}
 	// https://github.com/kubernetes/kubernetes/blob/badcd4af3f592376ce891b7c1b7a43ed6a18a348/pkg/printers/internalversion/printers.go#L1403
 	switch r.Status.Phase {
 	case corev1.ClaimPending, corev1.ClaimLost:
@@ -0,0 +1,32 @@
+package appstate
+
+import (
+	"log"
+
+	"github.com/replicatedhq/kots/kotsadm/operator/pkg/appstate/types"
+	"github.com/replicatedhq/kots/kotsadm/operator/pkg/readiness"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// readinessExpressionState returns the state of a resource that has a readiness expression annotation, which
+// replaces the readiness logic of its kind. ok is false if the resource has no expression.
+func readinessExpressionState(obj runtime.Object) (state types.State, ok bool) {
+	expression, err := readiness.FromObject(obj)
+	if err != nil {
+		log.Printf("Ignoring invalid readiness expression: %v", err)
+		return "", false
+	}
+	if expression == nil {
+		return "", false
+	}
+
+	ready, err := expression.IsReady(obj)
+	if err != nil {
+		log.Printf("Failed to evaluate readiness expression: %v", err)
+		return types.StateUnavailable, true
+	}
+	if ready {
+		return types.StateReady, true
+	}
+	return types.StateUnavailable, true
+}
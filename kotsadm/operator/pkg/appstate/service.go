@@ -1,16 +1,12 @@
 package appstate
 
 import (
-	"context"
-	"time"
-
 	"github.com/replicatedhq/kots/kotsadm/operator/pkg/appstate/types"
 	corev1 "k8s.io/api/core/v1"
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/util/sets"
-	"k8s.io/apimachinery/pkg/watch"
+	kubeinformers "k8s.io/client-go/informers"
 	"k8s.io/client-go/kubernetes"
+	corev1listers "k8s.io/client-go/listers/core/v1"
 	"k8s.io/client-go/tools/cache"
 )
 
@@ -23,44 +19,36 @@ func init() {
 }
 
 func runServiceController(
-	ctx context.Context, clientset kubernetes.Interface, targetNamespace string,
+	clientset kubernetes.Interface, factory kubeinformers.SharedInformerFactory,
 	informers []types.StatusInformer, resourceStateCh chan<- types.ResourceState,
-) {
-	listwatch := &cache.ListWatch{
-		ListFunc: func(options metav1.ListOptions) (runtime.Object, error) {
-			return clientset.CoreV1().Services(targetNamespace).List(context.TODO(), options)
-		},
-		WatchFunc: func(options metav1.ListOptions) (watch.Interface, error) {
-			return clientset.CoreV1().Services(targetNamespace).Watch(context.TODO(), options)
-		},
-	}
-	informer := cache.NewSharedInformer(
-		listwatch,
-		&corev1.Service{},
-		// NOTE: services rely on endpoint status as well so unless we add additional
-		// informers, we have to resync more frequently.
-		10*time.Second,
-	)
+) []cache.SharedIndexInformer {
+	serviceInformer := factory.Core().V1().Services()
+	endpointsInformer := factory.Core().V1().Endpoints()
 
 	eventHandler := NewServiceEventHandler(
-		clientset,
+		serviceInformer.Lister(),
+		endpointsInformer.Lister(),
 		filterStatusInformersByResourceKind(informers, ServiceResourceKind),
 		resourceStateCh,
 	)
+	addEventHandler(serviceInformer.Informer(), eventHandler)
+	// the state of a service depends on its endpoints
+	addChangeHandler(endpointsInformer.Informer(), eventHandler.EndpointsChanged)
 
-	runInformer(ctx, informer, eventHandler)
-	return
+	return []cache.SharedIndexInformer{serviceInformer.Informer(), endpointsInformer.Informer()}
 }
 
 type serviceEventHandler struct {
-	clientset       kubernetes.Interface
+	serviceLister   corev1listers.ServiceLister
+	endpointsLister corev1listers.EndpointsLister
 	informers       []types.StatusInformer
 	resourceStateCh chan<- types.ResourceState
 }
 
-func NewServiceEventHandler(clientset kubernetes.Interface, informers []types.StatusInformer, resourceStateCh chan<- types.ResourceState) *serviceEventHandler {
+func NewServiceEventHandler(serviceLister corev1listers.ServiceLister, endpointsLister corev1listers.EndpointsLister, informers []types.StatusInformer, resourceStateCh chan<- types.ResourceState) *serviceEventHandler {
 	return &serviceEventHandler{
-		clientset:       clientset,
+		serviceLister:   serviceLister,
+		endpointsLister: endpointsLister,
 		informers:       informers,
 		resourceStateCh: resourceStateCh,
 	}
@@ -71,7 +59,7 @@ func (h *serviceEventHandler) ObjectCreated(obj interface{}) {
 	if _, ok := h.getInformer(r); !ok {
 		return
 	}
-	h.resourceStateCh <- makeServiceResourceState(r, calculateServiceState(h.clientset, r))
+	h.resourceStateCh <- makeServiceResourceState(r, calculateServiceState(r, h.getEndpoints(r.Namespace, r.Name)))
 }
 
 func (h *serviceEventHandler) ObjectUpdated(obj interface{}) {
@@ -79,7 +67,7 @@ func (h *serviceEventHandler) ObjectUpdated(obj interface{}) {
 	if _, ok := h.getInformer(r); !ok {
 		return
 	}
-	h.resourceStateCh <- makeServiceResourceState(r, calculateServiceState(h.clientset, r))
+	h.resourceStateCh <- makeServiceResourceState(r, calculateServiceState(r, h.getEndpoints(r.Namespace, r.Name)))
 }
 
 func (h *serviceEventHandler) ObjectDeleted(obj interface{}) {
@@ -90,6 +78,32 @@ func (h *serviceEventHandler) ObjectDeleted(obj interface{}) {
 	h.resourceStateCh <- makeServiceResourceState(r, types.StateMissing)
 }
 
+// EndpointsChanged updates the state of the service of the endpoints. Missing services are reported by the service
+// events.
+func (h *serviceEventHandler) EndpointsChanged(obj interface{}) {
+	endpoints, _ := obj.(*corev1.Endpoints)
+	if endpoints == nil {
+		return
+	}
+	r, err := h.serviceLister.Services(endpoints.Namespace).Get(endpoints.Name)
+	if err != nil {
+		return
+	}
+	if _, ok := h.getInformer(r); !ok {
+		return
+	}
+	h.resourceStateCh <- makeServiceResourceState(r, calculateServiceState(r, h.getEndpoints(r.Namespace, r.Name)))
+}
+
+// getEndpoints returns the endpoints from the informer cache, or nil if there are none
+func (h *serviceEventHandler) getEndpoints(namespace string, name string) *corev1.Endpoints {
+	endpoints, err := h.endpointsLister.Endpoints(namespace).Get(name)
+	if err != nil {
+		return nil
+	}
+	return endpoints
+}
+
 func (h *serviceEventHandler) cast(obj interface{}) *corev1.Service {
 	r, _ := obj.(*corev1.Service)
 	return r
@@ -115,17 +129,19 @@ func makeServiceResourceState(r *corev1.Service, state types.State) types.Resour
 	}
 }
 
-func calculateServiceState(clientset kubernetes.Interface, r *corev1.Service) types.State {
+func calculateServiceState(r *corev1.Service, endpoints *corev1.Endpoints) types.State {
+	if state, ok := readinessExpressionState(r); ok {
+		return state
+	}
 	var states []types.State
 	// https://github.com/kubernetes/kubectl/blob/6b77b0790ab40d2a692ad80e9e4c962e784bb9b8/pkg/describe/versioned/describe.go#L4617
-	states = append(states, serviceGetStateFromEndpoints(clientset, r))
+	states = append(states, serviceGetStateFromEndpoints(r, endpoints))
 	// https://github.com/kubernetes/kubernetes/blob/badcd4af3f592376ce891b7c1b7a43ed6a18a348/pkg/printers/internalversion/printers.go#L1003
 	states = append(states, serviceGetStateFromExternalIP(r))
 	return types.MinState(states...)
 }
 
-func serviceGetStateFromEndpoints(clientset kubernetes.Interface, svc *corev1.Service) (minState types.State) {
-	endpoints, _ := clientset.CoreV1().Endpoints(svc.Namespace).Get(context.TODO(), svc.Name, metav1.GetOptions{})
+func serviceGetStateFromEndpoints(svc *corev1.Service, endpoints *corev1.Endpoints) (minState types.State) {
 	if endpoints == nil {
 		// I'm unsure of the state for this case
 		return types.StateUnavailable
@@ -0,0 +1,113 @@
+package appstate
+
+import (
+	"testing"
+
+	"github.com/replicatedhq/kots/kotsadm/operator/pkg/appstate/types"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+)
+
+func Test_serviceEventHandler_EndpointsChanged(t *testing.T) {
+	service := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default"},
+		Spec: corev1.ServiceSpec{
+			Ports: []corev1.ServicePort{{Name: "http"}},
+		},
+	}
+	ready := &corev1.Endpoints{
+		ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default"},
+		Subsets: []corev1.EndpointSubset{{
+			Addresses: []corev1.EndpointAddress{{IP: "10.0.0.1"}},
+			Ports:     []corev1.EndpointPort{{Name: "http"}},
+		}},
+	}
+	other := &corev1.Endpoints{
+		ObjectMeta: metav1.ObjectMeta{Name: "other", Namespace: "default"},
+	}
+
+	serviceIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	serviceIndexer.Add(service)
+	endpointsIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	endpointsIndexer.Add(ready)
+	endpointsIndexer.Add(other)
+
+	resourceStateCh := make(chan types.ResourceState, 10)
+	h := NewServiceEventHandler(
+		corev1listers.NewServiceLister(serviceIndexer),
+		corev1listers.NewEndpointsLister(endpointsIndexer),
+		[]types.StatusInformer{{Kind: ServiceResourceKind, Name: "web", Namespace: "default"}},
+		resourceStateCh,
+	)
+
+	h.EndpointsChanged(other)
+	if len(resourceStateCh) != 0 {
+		t.Fatalf("EndpointsChanged() sent a state for endpoints of an unwatched service")
+	}
+
+	h.EndpointsChanged(ready)
+	if len(resourceStateCh) != 1 {
+		t.Fatalf("EndpointsChanged() sent %d states, want 1", len(resourceStateCh))
+	}
+	got := <-resourceStateCh
+	want := types.ResourceState{Kind: ServiceResourceKind, Name: "web", Namespace: "default", State: types.StateReady}
+	if got != want {
+		t.Errorf("EndpointsChanged() = %v, want %v", got, want)
+	}
+
+	// a deleted service is reported by the service events
+	h.EndpointsChanged(cache.DeletedFinalStateUnknown{Key: "default/web"})
+	if len(resourceStateCh) != 0 {
+		t.Errorf("EndpointsChanged() sent a state for a tombstone")
+	}
+}
+
+func Test_calculateServiceState(t *testing.T) {
+	service := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default"},
+		Spec: corev1.ServiceSpec{
+			Ports: []corev1.ServicePort{{Name: "http"}},
+		},
+	}
+	tests := []struct {
+		name      string
+		endpoints *corev1.Endpoints
+		want      types.State
+	}{
+		{
+			name:      "no endpoints",
+			endpoints: nil,
+			want:      types.StateUnavailable,
+		},
+		{
+			name: "not ready addresses",
+			endpoints: &corev1.Endpoints{
+				Subsets: []corev1.EndpointSubset{{
+					Addresses:         []corev1.EndpointAddress{{IP: "10.0.0.1"}},
+					NotReadyAddresses: []corev1.EndpointAddress{{IP: "10.0.0.2"}},
+					Ports:             []corev1.EndpointPort{{Name: "http"}},
+				}},
+			},
+			want: types.StateDegraded,
+		},
+		{
+			name: "no addresses",
+			endpoints: &corev1.Endpoints{
+				Subsets: []corev1.EndpointSubset{{
+					NotReadyAddresses: []corev1.EndpointAddress{{IP: "10.0.0.2"}},
+					Ports:             []corev1.EndpointPort{{Name: "http"}},
+				}},
+			},
+			want: types.StateUnavailable,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := calculateServiceState(service, tt.endpoints); got != tt.want {
+				t.Errorf("calculateServiceState() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,193 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/pkg/errors"
+	kuberneteserrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sschema "k8s.io/apimachinery/pkg/runtime/schema"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/restmapper"
+	"sigs.k8s.io/controller-runtime/pkg/client/config"
+)
+
+const (
+	// lastAppliedAnnotation is set by kubectl apply, resources created with kubectl create don't have it
+	lastAppliedAnnotation = "kubectl.kubernetes.io/last-applied-configuration"
+	appSlugAnnotation     = "kots.io/app-slug"
+)
+
+// adoptionCandidate is an existing resource with the name of a resource of the app that was not applied for the app
+type adoptionCandidate struct {
+	gvr       k8sschema.GroupVersionResource
+	kind      string
+	namespace string
+	name      string
+	// previousManager is the tool that created or manages the resource
+	previousManager string
+}
+
+func (a adoptionCandidate) String() string {
+	return fmt.Sprintf("%s/%s in namespace %s (previously managed by %s)", strings.ToLower(a.kind), a.name, a.namespace, a.previousManager)
+}
+
+// findAdoptionCandidates returns the resources in the docs that already exist in the cluster but were not applied
+// for the app, such as the resources of a manual install. An error is returned if a resource belongs to another app,
+// it is never adopted.
+func findAdoptionCandidates(namespace string, slug string, docs []byte) ([]adoptionCandidate, error) {
+	cfg, err := config.GetConfig()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get config")
+	}
+	disc, err := discovery.NewDiscoveryClientForConfig(cfg)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create discovery client")
+	}
+	dyn, err := dynamic.NewForConfig(cfg)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create dynamic client")
+	}
+	groupResources, err := restmapper.GetAPIGroupResources(disc)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get api group resources")
+	}
+	mapper := restmapper.NewDiscoveryRESTMapper(groupResources)
+
+	candidates := []adoptionCandidate{}
+	for _, doc := range strings.Split(string(docs), "\n---\n") {
+		_, o := GetGVKWithNameAndNs([]byte(doc), namespace)
+		if o.APIVersion == "" || o.Kind == "" || o.Metadata.Name == "" {
+			continue
+		}
+
+		gv, err := k8sschema.ParseGroupVersion(o.APIVersion)
+		if err != nil {
+			continue
+		}
+		mapping, err := mapper.RESTMapping(gv.WithKind(o.Kind).GroupKind(), gv.Version)
+		if err != nil {
+			// the apply reports kinds that the cluster doesn't serve
+			log.Printf("skipping adoption of %s %s: %s", o.Kind, o.Metadata.Name, err.Error())
+			continue
+		}
+
+		resourceNamespace := ""
+		if mapping.Scope.Name() == "namespace" {
+			resourceNamespace = namespace
+			if o.Metadata.Namespace != "" {
+				resourceNamespace = o.Metadata.Namespace
+			}
+		}
+
+		existing, err := dyn.Resource(mapping.Resource).Namespace(resourceNamespace).Get(context.TODO(), o.Metadata.Name, metav1.GetOptions{})
+		if kuberneteserrors.IsNotFound(err) {
+			continue
+		} else if err != nil {
+			return nil, errors.Wrapf(err, "failed to get %s %s", o.Kind, o.Metadata.Name)
+		}
+
+		previousManager, err := previousResourceManager(existing.GetAnnotations(), existing.GetLabels(), slug)
+		if err != nil {
+			return nil, errors.Wrapf(err, "cannot adopt %s %s", o.Kind, o.Metadata.Name)
+		}
+		if previousManager == "" {
+			continue
+		}
+
+		candidates = append(candidates, adoptionCandidate{
+			gvr:             mapping.Resource,
+			kind:            o.Kind,
+			namespace:       resourceNamespace,
+			name:            o.Metadata.Name,
+			previousManager: previousManager,
+		})
+	}
+
+	return candidates, nil
+}
+
+// previousResourceManager returns the tool that manages an existing resource, or an empty string if the resource
+// was applied for the app. Resources of other kots apps can't be adopted.
+func previousResourceManager(annotations map[string]string, labels map[string]string, slug string) (string, error) {
+	if isAppResource(annotations, labels, slug) {
+		return "", nil
+	}
+
+	if labels[managedByLabel] == managedByLabelValue {
+		return "", errors.Errorf("it belongs to app %s", labels[instanceLabel])
+	}
+	if otherSlug := annotations[appSlugAnnotation]; otherSlug != "" {
+		return "", errors.Errorf("it belongs to app %s", otherSlug)
+	}
+
+	if manager := labels[managedByLabel]; manager != "" {
+		return manager, nil
+	}
+	if _, ok := annotations[lastAppliedAnnotation]; ok {
+		return "kubectl apply", nil
+	}
+	return "kubectl create", nil
+}
+
+// adoptResources sets the ownership labels and the app slug annotation on the candidates, so that they are managed,
+// updated and removed with the app from now on
+func adoptResources(slug string, candidates []adoptionCandidate) error {
+	if len(candidates) == 0 {
+		return nil
+	}
+
+	cfg, err := config.GetConfig()
+	if err != nil {
+		return errors.Wrap(err, "failed to get config")
+	}
+	dyn, err := dynamic.NewForConfig(cfg)
+	if err != nil {
+		return errors.Wrap(err, "failed to create dynamic client")
+	}
+
+	patch, err := adoptionPatch(slug)
+	if err != nil {
+		return errors.Wrap(err, "failed to create adoption patch")
+	}
+
+	for _, candidate := range candidates {
+		_, err := dyn.Resource(candidate.gvr).Namespace(candidate.namespace).Patch(context.TODO(), candidate.name, k8stypes.MergePatchType, patch, metav1.PatchOptions{})
+		if err != nil {
+			return errors.Wrapf(err, "failed to adopt %s %s", candidate.kind, candidate.name)
+		}
+		log.Printf("adopted %s", candidate)
+	}
+
+	return nil
+}
+
+func adoptionPatch(slug string) ([]byte, error) {
+	patch := map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"labels": map[string]string{
+				managedByLabel: managedByLabelValue,
+				instanceLabel:  slug,
+			},
+			"annotations": map[string]string{
+				appSlugAnnotation: slug,
+			},
+		},
+	}
+	return json.Marshal(patch)
+}
+
+// adoptionReport is the pre-adoption report that is sent with the result of the deploy
+func adoptionReport(candidates []adoptionCandidate) []byte {
+	lines := []string{fmt.Sprintf("adopting %d existing resource(s):", len(candidates))}
+	for _, candidate := range candidates {
+		lines = append(lines, fmt.Sprintf("  %s", candidate))
+	}
+	return []byte(strings.Join(lines, "\n"))
+}
@@ -0,0 +1,93 @@
+package client
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func Test_previousResourceManager(t *testing.T) {
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		labels      map[string]string
+		want        string
+		wantErr     bool
+	}{
+		{
+			name:   "applied for the app",
+			labels: map[string]string{managedByLabel: managedByLabelValue, instanceLabel: "my-app"},
+			want:   "",
+		},
+		{
+			name:        "annotated for the app",
+			annotations: map[string]string{appSlugAnnotation: "my-app"},
+			want:        "",
+		},
+		{
+			name:    "labeled for another app",
+			labels:  map[string]string{managedByLabel: managedByLabelValue, instanceLabel: "other-app"},
+			wantErr: true,
+		},
+		{
+			name:        "annotated for another app",
+			annotations: map[string]string{appSlugAnnotation: "other-app"},
+			wantErr:     true,
+		},
+		{
+			name:   "helm",
+			labels: map[string]string{managedByLabel: "Helm"},
+			want:   "Helm",
+		},
+		{
+			name:        "kubectl apply",
+			annotations: map[string]string{lastAppliedAnnotation: "{}"},
+			want:        "kubectl apply",
+		},
+		{
+			name: "kubectl create",
+			want: "kubectl create",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := previousResourceManager(test.annotations, test.labels, "my-app")
+			if test.wantErr {
+				if err == nil {
+					t.Fatal("expected error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != test.want {
+				t.Errorf("previousResourceManager() = %q, want %q", got, test.want)
+			}
+		})
+	}
+}
+
+func Test_adoptionPatch(t *testing.T) {
+	patch, err := adoptionPatch("my-app")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	decoded := struct {
+		Metadata struct {
+			Labels      map[string]string `json:"labels"`
+			Annotations map[string]string `json:"annotations"`
+		} `json:"metadata"`
+	}{}
+	if err := json.Unmarshal(patch, &decoded); err != nil {
+		t.Fatal(err)
+	}
+
+	if !isAppResource(nil, decoded.Metadata.Labels, "my-app") {
+		t.Errorf("adopted labels %v are not app resource labels", decoded.Metadata.Labels)
+	}
+	if !isAppResource(decoded.Metadata.Annotations, nil, "my-app") {
+		t.Errorf("adopted annotations %v are not app resource annotations", decoded.Metadata.Annotations)
+	}
+}
@@ -2,6 +2,7 @@ package client
 
 import (
 	"bytes"
+	"crypto/ed25519"
 	"encoding/json"
 	"fmt"
 	"log"
@@ -21,6 +22,7 @@ import (
 	"github.com/replicatedhq/kots/kotsadm/operator/pkg/supportbundle"
 	"github.com/replicatedhq/kots/kotsadm/operator/pkg/util"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
 )
@@ -34,6 +36,7 @@ type ApplicationManifests struct {
 	AppSlug              string                `json:"app_slug"`
 	KubectlVersion       string                `json:"kubectl_version"`
 	AdditionalNamespaces []string              `json:"additional_namespaces"`
+	ManagedNamespaces    []ManagedNamespace    `json:"managed_namespaces"`
 	ImagePullSecret      string                `json:"image_pull_secret"`
 	Namespace            string                `json:"namespace"`
 	PreviousManifests    string                `json:"previous_manifests"`
@@ -45,6 +48,33 @@ type ApplicationManifests struct {
 	AnnotateSlug         bool                  `json:"annotate_slug"`
 	IsRestore            bool                  `json:"is_restore"`
 	RestoreLabelSelector *metav1.LabelSelector `json:"restore_label_selector"`
+	// Impersonate is the user that manifests are applied as, the operator's own identity is used if empty
+	Impersonate string `json:"impersonate,omitempty"`
+	// ApplyRetries, ApplyRetryBackoffSeconds, KubectlTimeoutSeconds and PhaseWaitSeconds are the app's apply
+	// policy, the operator's defaults are used if zero
+	ApplyRetries             int `json:"apply_retries,omitempty"`
+	ApplyRetryBackoffSeconds int `json:"apply_retry_backoff_seconds,omitempty"`
+	KubectlTimeoutSeconds    int `json:"kubectl_timeout_seconds,omitempty"`
+	PhaseWaitSeconds         int `json:"phase_wait_seconds,omitempty"`
+	// AdoptExistingResources labels existing resources that were not applied for the app, such as the resources
+	// of a manual install, so that they are managed with the app
+	AdoptExistingResources bool `json:"adopt_existing_resources,omitempty"`
+	// ReapplyCriticalResources applies the resources annotated as critical again when they are deleted between deploys
+	ReapplyCriticalResources bool `json:"reapply_critical_resources,omitempty"`
+	// ManifestsSignature is the signature of the manifests by kotsadm, verified before anything is applied or deleted
+	ManifestsSignature string `json:"manifests_signature,omitempty"`
+	// DeployPhase is install or activate if the app is deployed in two phases, empty otherwise
+	DeployPhase string `json:"deploy_phase,omitempty"`
+	// RequestID is the id of the kotsadm api request that triggered the deploy, it is logged and reported back
+	// with the result so that the deploy can be correlated with the request
+	RequestID string `json:"request_id,omitempty"`
+}
+
+// ManagedNamespace is a namespace that is created with its labels and annotations before the manifests are applied
+type ManagedNamespace struct {
+	Name        string            `json:"name"`
+	Labels      map[string]string `json:"labels"`
+	Annotations map[string]string `json:"annotations"`
 }
 
 // DesiredState is what we receive from the kotsadm-api server
@@ -59,10 +89,20 @@ type InformRequest struct {
 	Informers []types.StatusInformerString `json:"informers"`
 }
 
+type StopInformRequest struct {
+	AppID string `json:"app_id"`
+}
+
+type LogSettingsRequest struct {
+	Level string `json:"level"`
+}
+
 type Client struct {
 	APIEndpoint     string
 	Token           string
 	TargetNamespace string
+	// ManifestSigningPublicKey verifies the manifests of deploys, they are not verified if it is nil
+	ManifestSigningPublicKey ed25519.PublicKey
 
 	watchedNamespaces []string
 	imagePullSecret   string
@@ -77,6 +117,10 @@ type Client struct {
 func (c *Client) Run() error {
 	log.Println("Starting kotsadm-operator loop")
 
+	if c.ManifestSigningPublicKey == nil {
+		log.Println("No manifest signing key is configured, the signatures of manifests will not be verified")
+	}
+
 	supportbundle.StartServer()
 
 	if _, ok := c.ExistingInformers[c.TargetNamespace]; !ok {
@@ -124,7 +168,7 @@ func (c *Client) runAppStateMonitor() error {
 			mtx.Unlock()
 			if lastHash != nextHash {
 				b, _ := json.Marshal(appStatus)
-				log.Printf("Sending app status %s", b)
+				util.Debugf("Sending app status %s", b)
 			}
 			if err := c.sendAppStatus(appStatus); err != nil {
 				log.Printf("error sending app status: %v", err)
@@ -188,8 +232,12 @@ func (c *Client) connect() error {
 	if err != nil {
 		return errors.Wrap(err, "failed to get new kubernetes client")
 	}
+	dynamicClient, err := dynamic.NewForConfig(restconfig)
+	if err != nil {
+		return errors.Wrap(err, "failed to get new dynamic client")
+	}
 
-	c.appStateMonitor = appstate.NewMonitor(clientset, c.TargetNamespace)
+	c.appStateMonitor = appstate.NewMonitor(clientset, dynamicClient, c.TargetNamespace)
 	defer c.appStateMonitor.Shutdown()
 
 	go c.runAppStateMonitor()
@@ -223,7 +271,11 @@ func (c *Client) registerHandlers(socketClient *socket.Client) error {
 		socketDeployMtxs[args.AppID].Lock()
 		defer socketDeployMtxs[args.AppID].Unlock()
 
-		log.Println("received a deploy request for", args.AppSlug)
+		if args.RequestID != "" {
+			log.Printf("received a deploy request for %s (request id %s)", args.AppSlug, args.RequestID)
+		} else {
+			log.Println("received a deploy request for", args.AppSlug)
+		}
 
 		var result *applyResult
 		var deployError error
@@ -251,13 +303,38 @@ func (c *Client) registerHandlers(socketClient *socket.Client) error {
 			}
 		}()
 
-		if args.PreviousManifests != "" {
+		if deployError = c.verifyManifests(args); deployError != nil {
+			log.Printf("not deploying %s: %s", args.AppSlug, deployError.Error())
+			return
+		}
+
+		// resources removed by the deploy must not be applied again, the watch is restarted after a successful deploy
+		stopCriticalResourceWatch(args.AppID)
+
+		if args.DeployPhase == deployPhaseInstall {
+			// the resources of the active version keep serving until the version is activated
+			if args.Manifests, deployError = filterInstallPhaseManifests(args.Manifests); deployError != nil {
+				log.Printf("error filtering install phase manifests: %s", deployError.Error())
+				return
+			}
+			args.PreviousManifests = ""
+		}
+
+		// an activation removes the previous resources once the new ones are applied
+		if args.PreviousManifests != "" && args.DeployPhase != deployPhaseActivate {
 			if deployError = c.diffAndRemovePreviousManifests(args); deployError != nil {
 				log.Printf("error diffing and removing previous manifests: %s", deployError.Error())
 				return
 			}
 		}
 
+		for _, managedNamespace := range args.ManagedNamespaces {
+			if deployError = c.ensureManagedNamespacePresent(managedNamespace, args.AppSlug); deployError != nil {
+				// we don't fail here, the namespace may have been created during install with more privileges
+				log.Printf("error ensuring managed namespace: %s", deployError.Error())
+			}
+		}
+
 		for _, additionalNamespace := range args.AdditionalNamespaces {
 			if additionalNamespace == "*" {
 				continue
@@ -285,6 +362,25 @@ func (c *Client) registerHandlers(socketClient *socket.Client) error {
 			return
 		}
 
+		if args.DeployPhase == deployPhaseActivate && args.PreviousManifests != "" && result != nil && !result.hasErr {
+			if err := c.diffAndRemovePreviousManifests(args); err != nil {
+				log.Printf("error removing the resources of the previously activated version: %s", err.Error())
+				result.hasErr = true
+				result.multiStderr = append(result.multiStderr, []byte(err.Error()))
+			}
+		}
+
+		if args.ReapplyCriticalResources && result != nil && !result.hasErr {
+			targetNamespace := c.TargetNamespace
+			if args.Namespace != "." {
+				targetNamespace = args.Namespace
+			}
+			if err := c.startCriticalResourceWatch(args, targetNamespace); err != nil {
+				// we don't fail here, the deploy succeeded
+				log.Printf("error watching critical resources: %s", err.Error())
+			}
+		}
+
 		c.shutdownNamespacesInformer()
 		if len(c.watchedNamespaces) > 0 {
 			c.runNamespacesInformer()
@@ -296,13 +392,29 @@ func (c *Client) registerHandlers(socketClient *socket.Client) error {
 	}
 
 	err = socketClient.On("appInformers", func(h *socket.Channel, args InformRequest) {
-		log.Printf("received an inform event: %#v", args)
+		util.Debugf("received an inform event: %#v", args)
 		c.applyAppInformers(args.AppID, args.Sequence, args.Informers)
 	})
 	if err != nil {
 		return errors.Wrap(err, "failed to add inform handler")
 	}
 
+	err = socketClient.On("stopAppInformers", func(h *socket.Channel, args StopInformRequest) {
+		util.Debugf("received a stop inform event: %#v", args)
+		c.appStateMonitor.Remove(args.AppID)
+	})
+	if err != nil {
+		return errors.Wrap(err, "failed to add stop inform handler")
+	}
+
+	err = socketClient.On("logSettings", func(h *socket.Channel, args LogSettingsRequest) {
+		log.Printf("setting log level to %s", args.Level)
+		util.SetLogLevel(args.Level)
+	})
+	if err != nil {
+		return errors.Wrap(err, "failed to add log settings handler")
+	}
+
 	return nil
 }
 
@@ -321,6 +433,7 @@ func (c *Client) sendResult(applicationManifests ApplicationManifests, isError b
 		DryrunStderr []byte `json:"dryrunStderr"`
 		ApplyStdout  []byte `json:"applyStdout"`
 		ApplyStderr  []byte `json:"applyStderr"`
+		RequestID    string `json:"requestId,omitempty"`
 	}{
 		applicationManifests.AppID,
 		isError,
@@ -328,6 +441,7 @@ func (c *Client) sendResult(applicationManifests ApplicationManifests, isError b
 		dryrunStderr,
 		applyStdout,
 		applyStderr,
+		applicationManifests.RequestID,
 	}
 
 	b, err := json.Marshal(applyResult)
@@ -396,7 +510,8 @@ func (c *Client) sendAppStatus(appStatus types.AppStatus) error {
 	return nil
 }
 
-func (c *Client) getApplier(kubectlVersion string) (*applier.Kubectl, error) {
+// getApplier returns an applier for the kubectl version that impersonates the user, if set
+func (c *Client) getApplier(kubectlVersion string, impersonate string, kubectlTimeoutSeconds int) (*applier.Kubectl, error) {
 	kubectl, err := util.FindKubectlVersion(kubectlVersion)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to find kubectl")
@@ -406,6 +521,7 @@ func (c *Client) getApplier(kubectlVersion string) (*applier.Kubectl, error) {
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to get in cluster config")
 	}
+	config.Impersonate.UserName = impersonate
 
-	return applier.NewKubectl(kubectl, config), nil
+	return applier.NewKubectl(kubectl, config, time.Duration(kubectlTimeoutSeconds)*time.Second), nil
 }
@@ -0,0 +1,239 @@
+package client
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/replicatedhq/kots/kotsadm/operator/pkg/applier"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	k8sschema "k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/dynamic/dynamicinformer"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/restmapper"
+	"k8s.io/client-go/tools/cache"
+)
+
+// criticalAnnotation marks the resources that are applied again as soon as they are deleted out of band, for
+// example by an accidental kubectl delete, if the apply policy of the app enables it
+const criticalAnnotation = "kots.io/critical"
+
+const (
+	// criticalReapplyRetries is the number of times a failed apply of a deleted critical resource is retried
+	criticalReapplyRetries = 5
+	// criticalDeleteSettleTime gives the api server time to finish deleting a resource with finalizers, a resource
+	// that is still being deleted can't be applied again
+	criticalDeleteSettleTime = 2 * time.Second
+)
+
+var (
+	// criticalWatches are closed to stop watching the critical resources of an app, the key is the app id
+	criticalWatches    = map[string]chan struct{}{}
+	criticalWatchesMtx sync.Mutex
+)
+
+// criticalResource is a resource of the deployed manifests that is annotated as critical
+type criticalResource struct {
+	apiVersion string
+	kind       string
+	name       string
+	// namespace is the namespace the resource is applied in, it is also used for cluster scoped resources
+	namespace string
+	doc       []byte
+}
+
+func (r criticalResource) String() string {
+	return fmt.Sprintf("%s/%s in namespace %s", strings.ToLower(r.kind), r.name, r.namespace)
+}
+
+// findCriticalResources returns the resources of the manifests that are annotated as critical
+func findCriticalResources(manifests []byte, targetNamespace string) []criticalResource {
+	resources := []criticalResource{}
+	for _, doc := range strings.Split(string(manifests), "\n---\n") {
+		_, o := GetGVKWithNameAndNs([]byte(doc), targetNamespace)
+		if o.APIVersion == "" || o.Kind == "" || o.Metadata.Name == "" {
+			continue
+		}
+		if critical, _ := strconv.ParseBool(o.Metadata.Annotations[criticalAnnotation]); !critical {
+			continue
+		}
+
+		namespace := targetNamespace
+		if o.Metadata.Namespace != "" {
+			namespace = o.Metadata.Namespace
+		}
+		resources = append(resources, criticalResource{
+			apiVersion: o.APIVersion,
+			kind:       o.Kind,
+			name:       o.Metadata.Name,
+			namespace:  namespace,
+			doc:        []byte(doc),
+		})
+	}
+	return resources
+}
+
+// stopCriticalResourceWatch stops applying the deleted critical resources of the app again. It is stopped for the
+// duration of a deploy, so that the resources that the deploy removes are not applied again.
+func stopCriticalResourceWatch(appID string) {
+	criticalWatchesMtx.Lock()
+	defer criticalWatchesMtx.Unlock()
+
+	if stopCh, ok := criticalWatches[appID]; ok {
+		close(stopCh)
+		delete(criticalWatches, appID)
+	}
+}
+
+// startCriticalResourceWatch watches the critical resources of the deployed manifests and applies them again with
+// the apply options of the deploy when they are deleted. Each deletion and apply is logged and recorded as an event.
+func (c *Client) startCriticalResourceWatch(applicationManifests ApplicationManifests, targetNamespace string) error {
+	stopCriticalResourceWatch(applicationManifests.AppID)
+
+	decoded, err := base64.StdEncoding.DecodeString(applicationManifests.Manifests)
+	if err != nil {
+		return errors.Wrap(err, "failed to decode manifests")
+	}
+	resources := findCriticalResources(decoded, targetNamespace)
+	if len(resources) == 0 {
+		return nil
+	}
+
+	restconfig, err := rest.InClusterConfig()
+	if err != nil {
+		return errors.Wrap(err, "failed to get in cluster config")
+	}
+	clientset, err := kubernetes.NewForConfig(restconfig)
+	if err != nil {
+		return errors.Wrap(err, "failed to get new kubernetes client")
+	}
+	dynamicClient, err := dynamic.NewForConfig(restconfig)
+	if err != nil {
+		return errors.Wrap(err, "failed to get new dynamic client")
+	}
+	discoveryClient, err := discovery.NewDiscoveryClientForConfig(restconfig)
+	if err != nil {
+		return errors.Wrap(err, "failed to create discovery client")
+	}
+	groupResources, err := restmapper.GetAPIGroupResources(discoveryClient)
+	if err != nil {
+		return errors.Wrap(err, "failed to get api group resources")
+	}
+	mapper := restmapper.NewDiscoveryRESTMapper(groupResources)
+
+	kubernetesApplier, err := c.getApplier(applicationManifests.KubectlVersion, applicationManifests.Impersonate, applicationManifests.KubectlTimeoutSeconds)
+	if err != nil {
+		return errors.Wrap(err, "failed to get applier")
+	}
+
+	stopCh := make(chan struct{})
+	criticalWatchesMtx.Lock()
+	criticalWatches[applicationManifests.AppID] = stopCh
+	criticalWatchesMtx.Unlock()
+
+	for _, resource := range resources {
+		gv, err := k8sschema.ParseGroupVersion(resource.apiVersion)
+		if err != nil {
+			log.Printf("not watching critical resource %s: %s", resource, err.Error())
+			continue
+		}
+		mapping, err := mapper.RESTMapping(gv.WithKind(resource.kind).GroupKind(), gv.Version)
+		if err != nil {
+			log.Printf("not watching critical resource %s: %s", resource, err.Error())
+			continue
+		}
+
+		informerNamespace := ""
+		if mapping.Scope.Name() == "namespace" {
+			informerNamespace = resource.namespace
+		}
+
+		name := resource.name
+		informer := dynamicinformer.NewFilteredDynamicInformer(dynamicClient, mapping.Resource, informerNamespace, 0, cache.Indexers{}, func(options *metav1.ListOptions) {
+			options.FieldSelector = fields.OneTermEqualSelector("metadata.name", name).String()
+		})
+
+		resource := resource
+		informer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+			DeleteFunc: func(obj interface{}) {
+				go reapplyCriticalResource(clientset, kubernetesApplier, applicationManifests, resource, stopCh)
+			},
+		})
+		go informer.Informer().Run(stopCh)
+	}
+
+	log.Printf("watching %d critical resource(s) of %s", len(resources), applicationManifests.AppSlug)
+
+	return nil
+}
+
+// reapplyCriticalResource applies a deleted critical resource again, unless the watch was stopped by a deploy
+func reapplyCriticalResource(clientset kubernetes.Interface, kubernetesApplier *applier.Kubectl, applicationManifests ApplicationManifests, resource criticalResource, stopCh chan struct{}) {
+	log.Printf("critical resource %s of %s was deleted, applying it again", resource, applicationManifests.AppSlug)
+
+	backoff := defaultApplyRetryBackoff
+	for i := 0; ; i++ {
+		select {
+		case <-stopCh:
+			log.Printf("not applying critical resource %s again, a deploy of %s started", resource, applicationManifests.AppSlug)
+			return
+		case <-time.After(criticalDeleteSettleTime):
+		}
+
+		stdout, stderr, err := kubernetesApplier.Apply(resource.namespace, applicationManifests.AppSlug, resource.doc, false, false, applicationManifests.AnnotateSlug)
+		if err == nil {
+			log.Printf("critical resource %s applied again", resource)
+			recordCriticalResourceEvent(clientset, resource, corev1.EventTypeNormal, "CriticalResourceReapplied", "The critical resource was deleted and has been applied again by the admin console")
+			return
+		}
+
+		log.Printf("stdout (critical apply) = %s", stdout)
+		log.Printf("stderr (critical apply) = %s", stderr)
+		if i >= criticalReapplyRetries {
+			log.Printf("failed to apply critical resource %s again: %s", resource, err.Error())
+			recordCriticalResourceEvent(clientset, resource, corev1.EventTypeWarning, "CriticalResourceReapplyFailed", fmt.Sprintf("The critical resource was deleted and could not be applied again: %s", err.Error()))
+			return
+		}
+
+		log.Printf("apply of critical resource %s failed, retrying in %s (%d/%d): %s", resource, backoff, i+1, criticalReapplyRetries, err.Error())
+		time.Sleep(backoff)
+		backoff *= 2
+	}
+}
+
+func recordCriticalResourceEvent(clientset kubernetes.Interface, resource criticalResource, eventType string, reason string, message string) {
+	now := metav1.Now()
+	event := &corev1.Event{
+		ObjectMeta: metav1.ObjectMeta{
+			GenerateName: fmt.Sprintf("%s.", resource.name),
+			Namespace:    resource.namespace,
+		},
+		InvolvedObject: corev1.ObjectReference{
+			APIVersion: resource.apiVersion,
+			Kind:       resource.kind,
+			Name:       resource.name,
+			Namespace:  resource.namespace,
+		},
+		Reason:         reason,
+		Message:        message,
+		Type:           eventType,
+		Source:         corev1.EventSource{Component: "kotsadm-operator"},
+		FirstTimestamp: now,
+		LastTimestamp:  now,
+		Count:          1,
+	}
+	if _, err := clientset.CoreV1().Events(resource.namespace).Create(context.TODO(), event, metav1.CreateOptions{}); err != nil {
+		log.Printf("failed to record event for critical resource %s: %s", resource, err.Error())
+	}
+}
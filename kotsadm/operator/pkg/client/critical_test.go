@@ -0,0 +1,50 @@
+package client
+
+import (
+	"testing"
+)
+
+func Test_findCriticalResources(t *testing.T) {
+	manifests := `apiVersion: v1
+kind: Secret
+metadata:
+  name: tls
+  annotations:
+    kots.io/critical: "true"
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: settings
+  namespace: other
+  annotations:
+    kots.io/critical: "false"
+---
+apiVersion: networking.k8s.io/v1
+kind: NetworkPolicy
+metadata:
+  name: deny-all
+  namespace: other
+  annotations:
+    kots.io/critical: "1"`
+
+	resources := findCriticalResources([]byte(manifests), "default")
+	if len(resources) != 2 {
+		t.Fatalf("expected 2 critical resources, got %d", len(resources))
+	}
+
+	if got := resources[0].String(); got != "secret/tls in namespace default" {
+		t.Errorf("unexpected first resource %q", got)
+	}
+	if got := resources[1].String(); got != "networkpolicy/deny-all in namespace other" {
+		t.Errorf("unexpected second resource %q", got)
+	}
+	if resources[1].apiVersion != "networking.k8s.io/v1" {
+		t.Errorf("unexpected api version %q", resources[1].apiVersion)
+	}
+}
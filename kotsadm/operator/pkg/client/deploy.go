@@ -10,7 +10,6 @@ import (
 
 	"github.com/pkg/errors"
 	"github.com/replicatedhq/kots/kotsadm/operator/pkg/applier"
-	"github.com/replicatedhq/kots/kotsadm/operator/pkg/util"
 	appsv1 "k8s.io/api/apps/v1"
 	batchv1 "k8s.io/api/batch/v1"
 	batchv1beta1 "k8s.io/api/batch/v1beta1"
@@ -32,6 +31,23 @@ import (
 
 var metadataAccessor = meta.NewAccessor()
 
+// managedNamespaceLabel is set on the namespaces created for an app, it must match kotsutil.ManagedNamespaceLabel
+const managedNamespaceLabel = "kots.io/managed-namespace-for"
+
+const (
+	// defaultApplyRetryBackoff is the wait before the first retry of a failed apply
+	defaultApplyRetryBackoff = time.Second * 5
+	// defaultPhaseWait gives the API server time to cache the CRDs before the other manifests are applied
+	defaultPhaseWait = time.Second * 5
+)
+
+// ownership labels of the resources of an app, they must match the labels in kotsutil
+const (
+	managedByLabel      = "app.kubernetes.io/managed-by"
+	managedByLabelValue = "kots"
+	instanceLabel       = "app.kubernetes.io/instance"
+)
+
 type applyResult struct {
 	hasErr      bool
 	multiStdout [][]byte
@@ -104,18 +120,12 @@ func (c *Client) diffAndRemovePreviousManifests(applicationManifests Application
 	}
 
 	// now remove anything that's in previous but not in current
-	kubectl, err := util.FindKubectlVersion(applicationManifests.KubectlVersion)
-	if err != nil {
-		return errors.Wrap(err, "failed to find kubectl")
-	}
-	config, err := rest.InClusterConfig()
-	if err != nil {
-		return errors.Wrap(err, "failed to get in cluster config")
-	}
-
 	// this is pretty raw, and required kubectl...  we should
 	// consider some other options here?
-	kubernetesApplier := applier.NewKubectl(kubectl, config)
+	kubernetesApplier, err := c.getApplier(applicationManifests.KubectlVersion, applicationManifests.Impersonate, applicationManifests.KubectlTimeoutSeconds)
+	if err != nil {
+		return errors.Wrap(err, "failed to get applier")
+	}
 
 	allPVCs := make([]string, 0)
 	for k, previous := range decodedPreviousMap {
@@ -238,13 +248,84 @@ func (c *Client) ensureNamespacePresent(name string) error {
 	return nil
 }
 
+// ensureManagedNamespacePresent creates the namespace or adds the labels and annotations to the existing namespace.
+// Only namespaces that are created here are labeled as managed, so that existing namespaces are not deleted with the app.
+func (c *Client) ensureManagedNamespacePresent(managedNamespace ManagedNamespace, appSlug string) error {
+	restconfig, err := rest.InClusterConfig()
+	if err != nil {
+		return errors.Wrap(err, "failed to get in cluster config")
+	}
+	clientset, err := kubernetes.NewForConfig(restconfig)
+	if err != nil {
+		return errors.Wrap(err, "failed to get new kubernetes client")
+	}
+
+	namespace, err := clientset.CoreV1().Namespaces().Get(context.TODO(), managedNamespace.Name, metav1.GetOptions{})
+	if kuberneteserrors.IsNotFound(err) {
+		namespace = &corev1.Namespace{
+			TypeMeta: metav1.TypeMeta{
+				APIVersion: "v1",
+				Kind:       "Namespace",
+			},
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        managedNamespace.Name,
+				Labels:      map[string]string{managedNamespaceLabel: appSlug},
+				Annotations: map[string]string{},
+			},
+		}
+		mergeNamespaceMetadata(namespace, managedNamespace)
+
+		_, err = clientset.CoreV1().Namespaces().Create(context.TODO(), namespace, metav1.CreateOptions{})
+		if err != nil {
+			return errors.Wrap(err, "failed to create namespace")
+		}
+		return nil
+	} else if err != nil {
+		return errors.Wrap(err, "failed to get namespace")
+	}
+
+	if !mergeNamespaceMetadata(namespace, managedNamespace) {
+		return nil
+	}
+	_, err = clientset.CoreV1().Namespaces().Update(context.TODO(), namespace, metav1.UpdateOptions{})
+	if err != nil {
+		return errors.Wrap(err, "failed to update namespace")
+	}
+
+	return nil
+}
+
+// mergeNamespaceMetadata sets the labels and annotations on the namespace and returns true if any changed
+func mergeNamespaceMetadata(namespace *corev1.Namespace, managedNamespace ManagedNamespace) bool {
+	changed := false
+	if namespace.Labels == nil {
+		namespace.Labels = map[string]string{}
+	}
+	for k, v := range managedNamespace.Labels {
+		if namespace.Labels[k] != v {
+			namespace.Labels[k] = v
+			changed = true
+		}
+	}
+	if namespace.Annotations == nil {
+		namespace.Annotations = map[string]string{}
+	}
+	for k, v := range managedNamespace.Annotations {
+		if namespace.Annotations[k] != v {
+			namespace.Annotations[k] = v
+			changed = true
+		}
+	}
+	return changed
+}
+
 func (c *Client) ensureResourcesPresent(applicationManifests ApplicationManifests) (*applyResult, error) {
 	targetNamespace := c.TargetNamespace
 	if applicationManifests.Namespace != "." {
 		targetNamespace = applicationManifests.Namespace
 	}
 
-	kubernetesApplier, err := c.getApplier(applicationManifests.KubectlVersion)
+	kubernetesApplier, err := c.getApplier(applicationManifests.KubectlVersion, applicationManifests.Impersonate, applicationManifests.KubectlTimeoutSeconds)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to get applier")
 	}
@@ -299,7 +380,7 @@ func (c *Client) ensureResourcesPresent(applicationManifests ApplicationManifest
 
 		// CRDs don't have namespaces, so we can skip splitting
 
-		applyStdout, applyStderr, applyErr := kubernetesApplier.Apply("", applicationManifests.AppSlug, firstApplyDocs, false, applicationManifests.Wait, applicationManifests.AnnotateSlug)
+		applyStdout, applyStderr, applyErr := applyWithRetries(kubernetesApplier, applicationManifests, "", firstApplyDocs)
 		if applyErr != nil {
 			log.Printf("stdout (first apply) = %s", applyStdout)
 			log.Printf("stderr (first apply) = %s", applyStderr)
@@ -314,8 +395,18 @@ func (c *Client) ensureResourcesPresent(applicationManifests ApplicationManifest
 			log.Println("custom resource definition(s) applied")
 		}
 
-		// Give the API server a minute (well, 5 seconds) to cache the CRDs
-		time.Sleep(time.Second * 5)
+		waitForFirstApplyDocs(kubernetesApplier, applicationManifests, firstApplyDocs)
+
+		readyStdout, err := waitForReadiness("", firstApplyDocs)
+		if err != nil {
+			log.Printf("error waiting for readiness of first apply docs: %s", err.Error())
+
+			if err := c.sendResult(applicationManifests, true, []byte{}, []byte{}, readyStdout, []byte(err.Error())); err != nil {
+				return nil, errors.Wrap(err, "failed to report readiness status")
+			}
+
+			return nil, nil
+		}
 	}
 
 	byNamespace, err := docsByNamespace(otherDocs, targetNamespace)
@@ -330,8 +421,49 @@ func (c *Client) ensureResourcesPresent(applicationManifests ApplicationManifest
 			continue
 		}
 
+		if applicationManifests.AdoptExistingResources {
+			candidates, err := findAdoptionCandidates(requestedNamespace, applicationManifests.AppSlug, docs)
+			if err != nil {
+				log.Printf("error finding resources to adopt in namespace %s: %s", requestedNamespace, err.Error())
+				hasErr = true
+				multiStderr = append(multiStderr, []byte(err.Error()))
+				continue
+			}
+			if len(candidates) > 0 {
+				multiStdout = append(multiStdout, adoptionReport(candidates))
+				if err := adoptResources(applicationManifests.AppSlug, candidates); err != nil {
+					log.Printf("error adopting resources in namespace %s: %s", requestedNamespace, err.Error())
+					hasErr = true
+					multiStderr = append(multiStderr, []byte(err.Error()))
+					continue
+				}
+			}
+		}
+
+		docs, pvcExpansions, err := preparePVCExpansions(requestedNamespace, docs)
+		if err != nil {
+			// kubectl would fail to apply the claims, report why instead
+			log.Printf("error expanding pvcs in namespace %s: %s", requestedNamespace, err.Error())
+			hasErr = true
+			multiStderr = append(multiStderr, []byte(err.Error()))
+			continue
+		}
+		if len(pvcExpansions) > 0 {
+			// the claims were expanded whether or not the apply succeeds
+			multiStdout = append(multiStdout, []byte(strings.Join(pvcExpansions, "\n")))
+		}
+
+		docs, statefulSetUpdates, err := prepareStatefulSetUpdates(requestedNamespace, docs)
+		if err != nil {
+			// kubectl would fail to apply the stateful sets, report why instead
+			log.Printf("error preparing stateful set updates in namespace %s: %s", requestedNamespace, err.Error())
+			hasErr = true
+			multiStderr = append(multiStderr, []byte(err.Error()))
+			continue
+		}
+
 		log.Printf("applying manifest(s) in namespace %s", requestedNamespace)
-		applyStdout, applyStderr, applyErr := kubernetesApplier.Apply(requestedNamespace, applicationManifests.AppSlug, docs, false, applicationManifests.Wait, applicationManifests.AnnotateSlug)
+		applyStdout, applyStderr, applyErr := applyWithRetries(kubernetesApplier, applicationManifests, requestedNamespace, docs)
 		if applyErr != nil {
 			log.Printf("stdout (apply) = %s", applyStdout)
 			log.Printf("stderr (apply) = %s", applyStderr)
@@ -346,6 +478,33 @@ func (c *Client) ensureResourcesPresent(applicationManifests ApplicationManifest
 		if len(applyStderr) > 0 {
 			multiStderr = append(multiStderr, applyStderr)
 		}
+		if applyErr != nil {
+			continue
+		}
+
+		for _, update := range statefulSetUpdates {
+			if err := completeStatefulSetUpdate(update); err != nil {
+				log.Printf("error updating stateful set %s: %s", update.name, err.Error())
+				hasErr = true
+				multiStderr = append(multiStderr, []byte(err.Error()))
+				continue
+			}
+			log.Printf("stateful set %s updated", update.name)
+			for name, size := range update.resizedPVCs {
+				multiStdout = append(multiStdout, []byte(fmt.Sprintf("persistentvolumeclaim/%s expanded to %s", name, size.String())))
+			}
+		}
+
+		// the next namespace is only applied once the resources of this one with readiness expressions are ready
+		readyStdout, err := waitForReadiness(requestedNamespace, docs)
+		if len(readyStdout) > 0 {
+			multiStdout = append(multiStdout, readyStdout)
+		}
+		if err != nil {
+			log.Printf("error waiting for readiness in namespace %s: %s", requestedNamespace, err.Error())
+			hasErr = true
+			multiStderr = append(multiStderr, []byte(err.Error()))
+		}
 	}
 
 	result := &applyResult{
@@ -356,6 +515,63 @@ func (c *Client) ensureResourcesPresent(applicationManifests ApplicationManifest
 	return result, nil
 }
 
+// applyWithRetries applies the docs, retrying failed applies as many times as the app's apply policy allows.
+// The wait between retries doubles after each retry.
+func applyWithRetries(kubernetesApplier *applier.Kubectl, applicationManifests ApplicationManifests, namespace string, docs []byte) ([]byte, []byte, error) {
+	backoff := defaultApplyRetryBackoff
+	if applicationManifests.ApplyRetryBackoffSeconds > 0 {
+		backoff = time.Duration(applicationManifests.ApplyRetryBackoffSeconds) * time.Second
+	}
+
+	for i := 0; ; i++ {
+		stdout, stderr, err := kubernetesApplier.Apply(namespace, applicationManifests.AppSlug, docs, false, applicationManifests.Wait, applicationManifests.AnnotateSlug)
+		if err == nil || i >= applicationManifests.ApplyRetries {
+			return stdout, stderr, err
+		}
+
+		log.Printf("apply failed, retrying in %s (%d/%d): %s", backoff, i+1, applicationManifests.ApplyRetries, err.Error())
+		log.Printf("stderr (apply) = %s", stderr)
+		time.Sleep(backoff)
+		backoff *= 2
+	}
+}
+
+// waitForFirstApplyDocs gives the API server time to serve the CRDs before the custom resources using them are
+// applied. If the app waits for resources, it waits up to the phase wait for the CRDs to be established,
+// otherwise it sleeps for the phase wait.
+func waitForFirstApplyDocs(kubernetesApplier *applier.Kubectl, applicationManifests ApplicationManifests, firstApplyDocs []byte) {
+	phaseWait := defaultPhaseWait
+	if applicationManifests.PhaseWaitSeconds > 0 {
+		phaseWait = time.Duration(applicationManifests.PhaseWaitSeconds) * time.Second
+	}
+
+	crds := crdDocs(firstApplyDocs)
+	if !applicationManifests.Wait || crds == nil {
+		time.Sleep(phaseWait)
+		return
+	}
+
+	log.Printf("waiting up to %s for custom resource definition(s) to be established", phaseWait)
+	stdout, stderr, err := kubernetesApplier.WaitForCondition(crds, "Established", phaseWait)
+	if err != nil {
+		// the apply of the custom resources will fail and be reported if the crds are not ready
+		log.Printf("stdout (wait) = %s", stdout)
+		log.Printf("stderr (wait) = %s", stderr)
+		log.Printf("error: %s", err.Error())
+		return
+	}
+	log.Println("custom resource definition(s) established")
+}
+
+// isAppResource returns true if the resource was applied for the app. Resources deployed before the ownership
+// labels were introduced are identified by the app slug annotation.
+func isAppResource(annotations map[string]string, labels map[string]string, slug string) bool {
+	if annotations[appSlugAnnotation] == slug {
+		return true
+	}
+	return labels[managedByLabel] == managedByLabelValue && labels[instanceLabel] == slug
+}
+
 func (c *Client) clearNamespace(slug string, namespace string, isRestore bool, restoreLabelSelector *metav1.LabelSelector) (bool, error) {
 	cfg, err := config.GetConfig()
 	if err != nil {
@@ -424,8 +640,7 @@ func (c *Client) clearNamespace(slug string, namespace string, isRestore bool, r
 				}
 			}
 
-			annotations := u.GetAnnotations()
-			if annotations["kots.io/app-slug"] == slug {
+			if isAppResource(u.GetAnnotations(), u.GetLabels(), slug) {
 				clear = false
 				if u.GetDeletionTimestamp() != nil {
 					log.Printf("%s %s is pending deletion\n", gvr, u.GetName())
@@ -30,6 +30,20 @@ func splitMutlidocYAMLIntoFirstApplyAndOthers(multidoc []byte) ([]byte, []byte,
 	return []byte(strings.Join(firstApply, "\n---\n")), []byte(strings.Join(other, "\n---\n")), nil
 }
 
+// crdDocs returns the custom resource definitions in the multidoc, or nil if there are none
+func crdDocs(multidoc []byte) []byte {
+	crds := []string{}
+	for _, doc := range strings.Split(string(multidoc), "\n---\n") {
+		if IsCRD([]byte(doc)) {
+			crds = append(crds, doc)
+		}
+	}
+	if len(crds) == 0 {
+		return nil
+	}
+	return []byte(strings.Join(crds, "\n---\n"))
+}
+
 func docsByNamespace(multidoc []byte, defaultNamespace string) (map[string][]byte, error) {
 	byNamespace := map[string][]string{}
 
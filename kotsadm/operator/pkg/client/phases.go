@@ -0,0 +1,42 @@
+package client
+
+import (
+	"encoding/base64"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// deployPhaseInstall applies the resources of a version that is deployed in two phases alongside the resources
+	// of the active version, the resources annotated for the activate phase are held back
+	deployPhaseInstall = "install"
+	// deployPhaseActivate applies all resources of the version, then removes the resources of the previously
+	// activated version
+	deployPhaseActivate = "activate"
+
+	// deployPhaseAnnotation marks the resources that switch traffic to a version, such as services and ingresses,
+	// they are only applied when the version is activated
+	deployPhaseAnnotation         = "kots.io/deploy-phase"
+	deployPhaseAnnotationActivate = "activate"
+)
+
+// filterInstallPhaseManifests returns the base64 encoded manifests without the resources that are held back until
+// the version is activated
+func filterInstallPhaseManifests(manifests string) (string, error) {
+	decoded, err := base64.StdEncoding.DecodeString(manifests)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to decode manifests")
+	}
+
+	docs := []string{}
+	for _, doc := range strings.Split(string(decoded), "\n---\n") {
+		_, o := GetGVKWithNameAndNs([]byte(doc), "")
+		if o.Metadata.Annotations[deployPhaseAnnotation] == deployPhaseAnnotationActivate {
+			continue
+		}
+		docs = append(docs, doc)
+	}
+
+	return base64.StdEncoding.EncodeToString([]byte(strings.Join(docs, "\n---\n"))), nil
+}
@@ -0,0 +1,52 @@
+package client
+
+import (
+	"encoding/base64"
+	"testing"
+)
+
+func Test_filterInstallPhaseManifests(t *testing.T) {
+	manifests := `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web-v2
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: web
+  annotations:
+    kots.io/deploy-phase: activate
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: web-v2-preview
+  annotations:
+    kots.io/deploy-phase: install`
+
+	filtered, err := filterInstallPhaseManifests(base64.StdEncoding.EncodeToString([]byte(manifests)))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	decoded, err := base64.StdEncoding.DecodeString(filtered)
+	if err != nil {
+		t.Fatalf("failed to decode filtered manifests: %v", err)
+	}
+
+	expected := `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web-v2
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: web-v2-preview
+  annotations:
+    kots.io/deploy-phase: install`
+	if string(decoded) != expected {
+		t.Errorf("unexpected filtered manifests:\n%s", decoded)
+	}
+}
@@ -0,0 +1,189 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	kuberneteserrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sruntime "k8s.io/apimachinery/pkg/runtime"
+	k8sjson "k8s.io/apimachinery/pkg/runtime/serializer/json"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/config"
+)
+
+// preparePVCExpansions expands the claims in the cluster whose storage requests grew in the docs. The spec of a
+// claim can't be changed other than its storage request, so the docs of expanded claims are applied with the spec
+// of the claim in the cluster. It returns the docs to apply and a line for each expanded claim.
+func preparePVCExpansions(namespace string, docs []byte) ([]byte, []string, error) {
+	splitDocs := strings.Split(string(docs), "\n---\n")
+	hasClaims := false
+	for _, doc := range splitDocs {
+		if _, ok := parsePVCDoc(doc); ok {
+			hasClaims = true
+			break
+		}
+	}
+	if !hasClaims {
+		return docs, []string{}, nil
+	}
+
+	cfg, err := config.GetConfig()
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "failed to get config")
+	}
+	clientset, err := kubernetes.NewForConfig(cfg)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "failed to get client set")
+	}
+
+	return expandPVCs(clientset, namespace, splitDocs)
+}
+
+// pvcExpansion is a claim whose storage request grew in the docs
+type pvcExpansion struct {
+	docIndex int
+	desired  *corev1.PersistentVolumeClaim
+	existing *corev1.PersistentVolumeClaim
+	size     resource.Quantity
+}
+
+// expandPVCs checks that all of the claims whose storage requests grew can be expanded before it expands any of
+// them, so that a deploy that fails does not leave some of its claims expanded
+func expandPVCs(clientset kubernetes.Interface, namespace string, splitDocs []string) ([]byte, []string, error) {
+	expansions := []pvcExpansion{}
+	for i, doc := range splitDocs {
+		desired, ok := parsePVCDoc(doc)
+		if !ok {
+			continue
+		}
+		desiredSize := desired.Spec.Resources.Requests[corev1.ResourceStorage]
+		pvcNamespace := namespace
+		if desired.Namespace != "" {
+			pvcNamespace = desired.Namespace
+		}
+
+		existing, err := clientset.CoreV1().PersistentVolumeClaims(pvcNamespace).Get(context.TODO(), desired.Name, metav1.GetOptions{})
+		if kuberneteserrors.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return nil, nil, errors.Wrapf(err, "failed to get pvc %s", desired.Name)
+		}
+
+		existingSize := existing.Spec.Resources.Requests[corev1.ResourceStorage]
+		switch desiredSize.Cmp(existingSize) {
+		case 0:
+			continue
+		case -1:
+			return nil, nil, errors.Errorf("pvc %s requests %s but is already %s. Volumes can't be shrunk, request at least %s in the manifest", desired.Name, desiredSize.String(), existingSize.String(), existingSize.String())
+		}
+
+		if err := checkPVCExpansion(clientset, existing); err != nil {
+			return nil, nil, errors.Wrapf(err, "pvc %s requests %s but is %s", desired.Name, desiredSize.String(), existingSize.String())
+		}
+
+		expansions = append(expansions, pvcExpansion{
+			docIndex: i,
+			desired:  desired,
+			existing: existing,
+			size:     desiredSize,
+		})
+	}
+
+	expanded := []string{}
+	for _, expansion := range expansions {
+		desired, existing := expansion.desired, expansion.existing
+		if err := expandPVC(clientset, existing, expansion.size); err != nil {
+			return nil, nil, err
+		}
+		expanded = append(expanded, pvcExpansionMessage(desired.Name, existing.Spec.Resources.Requests[corev1.ResourceStorage], expansion.size))
+
+		desired.Spec = *existing.Spec.DeepCopy()
+		desired.Spec.Resources.Requests[corev1.ResourceStorage] = expansion.size
+		desired.TypeMeta = metav1.TypeMeta{
+			APIVersion: "v1",
+			Kind:       "PersistentVolumeClaim",
+		}
+		b, err := encodeYAML(desired)
+		if err != nil {
+			return nil, nil, errors.Wrapf(err, "failed to encode pvc %s", desired.Name)
+		}
+		splitDocs[expansion.docIndex] = string(b)
+	}
+
+	return []byte(strings.Join(splitDocs, "\n---\n")), expanded, nil
+}
+
+// parsePVCDoc returns the claim of the doc if it is a claim that requests storage
+func parsePVCDoc(doc string) (*corev1.PersistentVolumeClaim, bool) {
+	obj, gvk, err := parseK8sYaml([]byte(doc))
+	if err != nil || gvk.Group != "" || gvk.Version != "v1" || gvk.Kind != "PersistentVolumeClaim" {
+		return nil, false
+	}
+	pvc := obj.(*corev1.PersistentVolumeClaim)
+	if _, ok := pvc.Spec.Resources.Requests[corev1.ResourceStorage]; !ok {
+		return nil, false
+	}
+	return pvc, true
+}
+
+// checkPVCExpansion returns an error that explains how to allow the expansion if the claim can't be expanded
+func checkPVCExpansion(clientset kubernetes.Interface, pvc *corev1.PersistentVolumeClaim) error {
+	if pvc.Status.Phase != corev1.ClaimBound {
+		return errors.Errorf("pvc %s is %s, only bound claims can be expanded", pvc.Name, pvc.Status.Phase)
+	}
+	if pvc.Spec.StorageClassName == nil || *pvc.Spec.StorageClassName == "" {
+		return errors.Errorf("pvc %s has no storage class, only dynamically provisioned claims can be expanded", pvc.Name)
+	}
+
+	storageClass, err := clientset.StorageV1().StorageClasses().Get(context.TODO(), *pvc.Spec.StorageClassName, metav1.GetOptions{})
+	if err != nil {
+		return errors.Wrapf(err, "failed to get storage class %s", *pvc.Spec.StorageClassName)
+	}
+	if storageClass.AllowVolumeExpansion == nil || !*storageClass.AllowVolumeExpansion {
+		return errors.Errorf("storage class %s of pvc %s does not allow volume expansion, set allowVolumeExpansion to true in the storage class if its provisioner supports it", storageClass.Name, pvc.Name)
+	}
+
+	return nil
+}
+
+// expandPVC requests the size for the claim if it's smaller
+func expandPVC(clientset kubernetes.Interface, pvc *corev1.PersistentVolumeClaim, size resource.Quantity) error {
+	current := pvc.Spec.Resources.Requests[corev1.ResourceStorage]
+	if current.Cmp(size) >= 0 {
+		return nil
+	}
+
+	log.Printf("expanding pvc %s from %s to %s", pvc.Name, current.String(), size.String())
+	pvc = pvc.DeepCopy()
+	if pvc.Spec.Resources.Requests == nil {
+		pvc.Spec.Resources.Requests = corev1.ResourceList{}
+	}
+	pvc.Spec.Resources.Requests[corev1.ResourceStorage] = size
+	if _, err := clientset.CoreV1().PersistentVolumeClaims(pvc.Namespace).Update(context.TODO(), pvc, metav1.UpdateOptions{}); err != nil {
+		return errors.Wrapf(err, "failed to update pvc %s", pvc.Name)
+	}
+
+	return nil
+}
+
+func pvcExpansionMessage(name string, from resource.Quantity, to resource.Quantity) string {
+	return fmt.Sprintf("persistentvolumeclaim/%s expanded from %s to %s", name, from.String(), to.String())
+}
+
+func encodeYAML(obj k8sruntime.Object) ([]byte, error) {
+	var b bytes.Buffer
+	serializer := k8sjson.NewYAMLSerializer(k8sjson.DefaultMetaFactory, scheme.Scheme, scheme.Scheme)
+	if err := serializer.Encode(obj, &b); err != nil {
+		return nil, err
+	}
+	return bytes.TrimSpace(b.Bytes()), nil
+}
@@ -0,0 +1,108 @@
+package client
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func Test_expandPVCs(t *testing.T) {
+	allow, deny := true, false
+	expandable := &storagev1.StorageClass{ObjectMeta: metav1.ObjectMeta{Name: "expandable"}, AllowVolumeExpansion: &allow}
+	fixed := &storagev1.StorageClass{ObjectMeta: metav1.ObjectMeta{Name: "fixed"}, AllowVolumeExpansion: &deny}
+
+	pvc := func(name string, storageClass string, size string) *corev1.PersistentVolumeClaim {
+		return &corev1.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+			Spec: corev1.PersistentVolumeClaimSpec{
+				StorageClassName: &storageClass,
+				VolumeName:       "pv-" + name,
+				Resources: corev1.ResourceRequirements{
+					Requests: corev1.ResourceList{corev1.ResourceStorage: resource.MustParse(size)},
+				},
+			},
+			Status: corev1.PersistentVolumeClaimStatus{Phase: corev1.ClaimBound},
+		}
+	}
+	doc := func(name string, size string) string {
+		return `apiVersion: v1
+kind: PersistentVolumeClaim
+metadata:
+  name: ` + name + `
+spec:
+  resources:
+    requests:
+      storage: ` + size
+	}
+	configMap := `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: settings`
+
+	getSize := func(t *testing.T, clientset *fake.Clientset, name string) string {
+		existing, err := clientset.CoreV1().PersistentVolumeClaims("default").Get(context.TODO(), name, metav1.GetOptions{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		size := existing.Spec.Resources.Requests[corev1.ResourceStorage]
+		return size.String()
+	}
+
+	t.Run("expands grown claims", func(t *testing.T) {
+		clientset := fake.NewSimpleClientset(expandable, pvc("data", "expandable", "1Gi"), pvc("logs", "expandable", "1Gi"))
+		splitDocs := []string{doc("data", "2Gi"), configMap, doc("logs", "1Gi"), doc("new", "1Gi")}
+
+		docs, expanded, err := expandPVCs(clientset, "default", splitDocs)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(expanded) != 1 || expanded[0] != "persistentvolumeclaim/data expanded from 1Gi to 2Gi" {
+			t.Fatalf("unexpected expanded claims %v", expanded)
+		}
+		if got := getSize(t, clientset, "data"); got != "2Gi" {
+			t.Errorf("expected data to be expanded to 2Gi, got %s", got)
+		}
+
+		// the doc of the expanded claim is applied with the spec of the claim in the cluster
+		applied := strings.Split(string(docs), "\n---\n")
+		if len(applied) != 4 {
+			t.Fatalf("expected 4 docs, got %d", len(applied))
+		}
+		if !strings.Contains(applied[0], "volumeName: pv-data") || !strings.Contains(applied[0], "storage: 2Gi") {
+			t.Errorf("unexpected doc of expanded claim %q", applied[0])
+		}
+		if applied[1] != configMap || applied[2] != doc("logs", "1Gi") || applied[3] != doc("new", "1Gi") {
+			t.Errorf("docs of claims that were not expanded were changed")
+		}
+	})
+
+	t.Run("expands no claim if one can't be expanded", func(t *testing.T) {
+		clientset := fake.NewSimpleClientset(expandable, fixed, pvc("data", "expandable", "1Gi"), pvc("logs", "fixed", "1Gi"))
+		splitDocs := []string{doc("data", "2Gi"), doc("logs", "2Gi")}
+
+		if _, _, err := expandPVCs(clientset, "default", splitDocs); err == nil {
+			t.Fatal("expected error")
+		}
+		if got := getSize(t, clientset, "data"); got != "1Gi" {
+			t.Errorf("expected data not to be expanded, got %s", got)
+		}
+	})
+
+	t.Run("expands no claim if one shrinks", func(t *testing.T) {
+		clientset := fake.NewSimpleClientset(expandable, pvc("data", "expandable", "1Gi"), pvc("logs", "expandable", "2Gi"))
+		splitDocs := []string{doc("data", "2Gi"), doc("logs", "1Gi")}
+
+		if _, _, err := expandPVCs(clientset, "default", splitDocs); err == nil {
+			t.Fatal("expected error")
+		}
+		if got := getSize(t, clientset, "data"); got != "1Gi" {
+			t.Errorf("expected data not to be expanded, got %s", got)
+		}
+	})
+}
@@ -0,0 +1,167 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/replicatedhq/kots/kotsadm/operator/pkg/readiness"
+	kuberneteserrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sschema "k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/restmapper"
+	"sigs.k8s.io/controller-runtime/pkg/client/config"
+)
+
+const (
+	// readinessWait is how long a phase of the apply waits for the resources with readiness expressions to be ready
+	readinessWait = 10 * time.Minute
+	// readinessPollInterval is how often the resources are read while waiting
+	readinessPollInterval = 2 * time.Second
+)
+
+// readinessCheck is a resource of the docs with a readiness expression annotation
+type readinessCheck struct {
+	apiVersion string
+	kind       string
+	// gvr is resolved when waiting, once the custom resource definitions of the previous phase are served
+	gvr        k8sschema.GroupVersionResource
+	namespace  string
+	name       string
+	expression *readiness.Expression
+}
+
+func (r readinessCheck) String() string {
+	return fmt.Sprintf("%s/%s", strings.ToLower(r.kind), r.name)
+}
+
+// getReadinessChecks returns the resources in the docs that have a readiness expression annotation
+func getReadinessChecks(namespace string, docs []byte) ([]readinessCheck, error) {
+	checks := []readinessCheck{}
+	for _, doc := range strings.Split(string(docs), "\n---\n") {
+		_, o := GetGVKWithNameAndNs([]byte(doc), namespace)
+		value, ok := o.Metadata.Annotations[readiness.Annotation]
+		if !ok {
+			continue
+		}
+
+		expression, err := readiness.Parse(value)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid readiness expression of %s %s", o.Kind, o.Metadata.Name)
+		}
+
+		resourceNamespace := namespace
+		if o.Metadata.Namespace != "" {
+			resourceNamespace = o.Metadata.Namespace
+		}
+
+		checks = append(checks, readinessCheck{
+			apiVersion: o.APIVersion,
+			kind:       o.Kind,
+			namespace:  resourceNamespace,
+			name:       o.Metadata.Name,
+			expression: expression,
+		})
+	}
+	return checks, nil
+}
+
+// waitForReadiness waits until the resources in the docs that have a readiness expression annotation are ready,
+// so that the next phase of the apply only starts once they are. It returns the resources that are ready, and an
+// error if any of them is still not ready after the readiness wait.
+func waitForReadiness(namespace string, docs []byte) ([]byte, error) {
+	checks, err := getReadinessChecks(namespace, docs)
+	if err != nil {
+		return nil, err
+	}
+	if len(checks) == 0 {
+		return nil, nil
+	}
+
+	cfg, err := config.GetConfig()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get config")
+	}
+	disc, err := discovery.NewDiscoveryClientForConfig(cfg)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create discovery client")
+	}
+	dyn, err := dynamic.NewForConfig(cfg)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create dynamic client")
+	}
+	groupResources, err := restmapper.GetAPIGroupResources(disc)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get api group resources")
+	}
+	mapper := restmapper.NewDiscoveryRESTMapper(groupResources)
+
+	for i, check := range checks {
+		gv, err := k8sschema.ParseGroupVersion(check.apiVersion)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to parse api version of %s", check)
+		}
+		mapping, err := mapper.RESTMapping(gv.WithKind(check.kind).GroupKind(), gv.Version)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to get resource of %s", check)
+		}
+		checks[i].gvr = mapping.Resource
+		if mapping.Scope.Name() != "namespace" {
+			checks[i].namespace = ""
+		}
+	}
+
+	log.Printf("waiting up to %s for %d resource(s) with readiness expressions in namespace %s", readinessWait, len(checks), namespace)
+
+	ready := []string{}
+	deadline := time.Now().Add(readinessWait)
+	for {
+		pending := []readinessCheck{}
+		for _, check := range checks {
+			isReady, err := isReadinessCheckReady(dyn, check)
+			if err != nil {
+				return nil, err
+			}
+			if isReady {
+				log.Printf("%s is ready", check)
+				ready = append(ready, fmt.Sprintf("%s is ready", check))
+			} else {
+				pending = append(pending, check)
+			}
+		}
+		checks = pending
+
+		if len(checks) == 0 {
+			return []byte(strings.Join(ready, "\n")), nil
+		}
+		if time.Now().After(deadline) {
+			notReady := []string{}
+			for _, check := range checks {
+				notReady = append(notReady, fmt.Sprintf("%s (%s)", check, check.expression))
+			}
+			return []byte(strings.Join(ready, "\n")), errors.Errorf("timed out waiting for readiness of %s", strings.Join(notReady, ", "))
+		}
+
+		time.Sleep(readinessPollInterval)
+	}
+}
+
+func isReadinessCheckReady(dyn dynamic.Interface, check readinessCheck) (bool, error) {
+	obj, err := dyn.Resource(check.gvr).Namespace(check.namespace).Get(context.TODO(), check.name, metav1.GetOptions{})
+	if kuberneteserrors.IsNotFound(err) {
+		return false, nil
+	} else if err != nil {
+		return false, errors.Wrapf(err, "failed to get %s", check)
+	}
+
+	isReady, err := check.expression.IsReady(obj)
+	if err != nil {
+		return false, errors.Wrapf(err, "failed to evaluate readiness of %s", check)
+	}
+	return isReady, nil
+}
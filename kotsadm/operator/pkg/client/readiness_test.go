@@ -0,0 +1,55 @@
+package client
+
+import (
+	"testing"
+)
+
+func Test_getReadinessChecks(t *testing.T) {
+	docs := `apiVersion: cert-manager.io/v1
+kind: Certificate
+metadata:
+  name: tls
+  annotations:
+    kots.io/readiness-expression: '{.status.conditions[?(@.type=="Ready")].status}=True'
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+---
+apiVersion: example.com/v1
+kind: Database
+metadata:
+  name: db
+  namespace: data
+  annotations:
+    kots.io/readiness-expression: '{.status.phase}=Running'`
+
+	checks, err := getReadinessChecks("app", []byte(docs))
+	if err != nil {
+		t.Fatalf("getReadinessChecks() error = %v", err)
+	}
+	if len(checks) != 2 {
+		t.Fatalf("getReadinessChecks() returned %d checks, want 2", len(checks))
+	}
+
+	if checks[0].String() != "certificate/tls" || checks[0].namespace != "app" || checks[0].apiVersion != "cert-manager.io/v1" {
+		t.Errorf("unexpected first check %s in namespace %s", checks[0], checks[0].namespace)
+	}
+	if checks[1].String() != "database/db" || checks[1].namespace != "data" {
+		t.Errorf("unexpected second check %s in namespace %s", checks[1], checks[1].namespace)
+	}
+}
+
+func Test_getReadinessChecksInvalid(t *testing.T) {
+	docs := `apiVersion: example.com/v1
+kind: Database
+metadata:
+  name: db
+  annotations:
+    kots.io/readiness-expression: '.status.phase=Running'`
+
+	if _, err := getReadinessChecks("app", []byte(docs)); err == nil {
+		t.Errorf("getReadinessChecks() expected error")
+	}
+}
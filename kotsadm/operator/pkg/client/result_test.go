@@ -0,0 +1,43 @@
+package client
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func Test_sendResult(t *testing.T) {
+	var received struct {
+		AppID     string `json:"appId"`
+		IsError   bool   `json:"isError"`
+		RequestID string `json:"requestId"`
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "PUT" || r.URL.Path != "/api/v1/deploy/result" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		if err := json.NewDecoder(r.Body).Decode(&received); err != nil {
+			t.Errorf("failed to decode result: %v", err)
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	c := &Client{APIEndpoint: server.URL, Token: "token"}
+	applicationManifests := ApplicationManifests{
+		AppID:          "app-id",
+		ResultCallback: "/api/v1/deploy/result",
+		RequestID:      "request-id",
+	}
+	if err := c.sendResult(applicationManifests, true, nil, nil, nil, []byte("failed")); err != nil {
+		t.Fatalf("failed to send result: %v", err)
+	}
+
+	if received.AppID != "app-id" || !received.IsError {
+		t.Errorf("unexpected result %+v", received)
+	}
+	if received.RequestID != "request-id" {
+		t.Errorf("expected request id %q, got %q", "request-id", received.RequestID)
+	}
+}
@@ -0,0 +1,60 @@
+package client
+
+import (
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// manifestsDigestVersion is the version of the digest that kotsadm signs, the digest must be computed as kotsadm does
+const manifestsDigestVersion = "kots-manifests-v1"
+
+// manifestsDigest returns the sha256 of the length prefixed fields of the deploy that kotsadm signs
+func manifestsDigest(applicationManifests ApplicationManifests) []byte {
+	fields := []string{
+		manifestsDigestVersion,
+		applicationManifests.AppID,
+		applicationManifests.Namespace,
+		applicationManifests.Manifests,
+		applicationManifests.PreviousManifests,
+		applicationManifests.ImagePullSecret,
+		strings.Join(applicationManifests.ClearNamespaces, ","),
+	}
+	if applicationManifests.DeployPhase != "" {
+		fields = append(fields, applicationManifests.DeployPhase)
+	}
+
+	h := sha256.New()
+	for _, field := range fields {
+		binary.Write(h, binary.BigEndian, uint64(len(field)))
+		h.Write([]byte(field))
+	}
+	return h.Sum(nil)
+}
+
+// verifyManifests returns an error if the manifests were not signed with the manifest signing key, which means that
+// they were tampered with after they were rendered, or that they were not sent by this admin console
+func (c *Client) verifyManifests(applicationManifests ApplicationManifests) error {
+	if c.ManifestSigningPublicKey == nil {
+		return nil
+	}
+
+	if applicationManifests.ManifestsSignature == "" {
+		return errors.New("the manifests are not signed")
+	}
+
+	signature, err := base64.StdEncoding.DecodeString(applicationManifests.ManifestsSignature)
+	if err != nil {
+		return errors.Wrap(err, "failed to decode manifests signature")
+	}
+
+	if !ed25519.Verify(c.ManifestSigningPublicKey, manifestsDigest(applicationManifests), signature) {
+		return errors.New("the signature of the manifests is invalid, the manifests may have been tampered with")
+	}
+
+	return nil
+}
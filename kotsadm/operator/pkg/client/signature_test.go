@@ -0,0 +1,58 @@
+package client
+
+import (
+	"crypto/ed25519"
+	"encoding/base64"
+	"encoding/hex"
+	"testing"
+)
+
+func Test_manifestsDigest(t *testing.T) {
+	applicationManifests := ApplicationManifests{
+		AppID:           "app-id",
+		Namespace:       "default",
+		Manifests:       "bWFuaWZlc3Rz",
+		ClearNamespaces: []string{"ns-a", "ns-b"},
+	}
+
+	// kotsadm signs the same digest
+	got := hex.EncodeToString(manifestsDigest(applicationManifests))
+	if got != "657c2ae297b05c8fe29f0b320ff6823128bd1f7c50594d449e900aadbbfaf4b9" {
+		t.Errorf("unexpected digest %s", got)
+	}
+}
+
+func Test_verifyManifests(t *testing.T) {
+	seed := make([]byte, ed25519.SeedSize)
+	for i := range seed {
+		seed[i] = byte(i)
+	}
+	privateKey := ed25519.NewKeyFromSeed(seed)
+
+	applicationManifests := ApplicationManifests{
+		AppID:     "app-id",
+		Namespace: "default",
+		Manifests: "bWFuaWZlc3Rz",
+	}
+	signed := applicationManifests
+	signed.ManifestsSignature = base64.StdEncoding.EncodeToString(ed25519.Sign(privateKey, manifestsDigest(applicationManifests)))
+
+	tampered := signed
+	tampered.Manifests = "dGFtcGVyZWQ="
+
+	c := &Client{}
+	if err := c.verifyManifests(applicationManifests); err != nil {
+		t.Errorf("expected unsigned manifests to be applied without a key, got %v", err)
+	}
+
+	c.ManifestSigningPublicKey = privateKey.Public().(ed25519.PublicKey)
+	if err := c.verifyManifests(signed); err != nil {
+		t.Errorf("expected signed manifests to verify, got %v", err)
+	}
+	if err := c.verifyManifests(tampered); err == nil {
+		t.Error("expected tampered manifests to fail verification")
+	}
+	if err := c.verifyManifests(applicationManifests); err == nil {
+		t.Error("expected unsigned manifests to fail verification")
+	}
+}
@@ -0,0 +1,359 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	kuberneteserrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/kubernetes"
+	"sigs.k8s.io/controller-runtime/pkg/client/config"
+)
+
+const (
+	// statefulSetRolloutAnnotation set to "partitioned" updates the pods of a stateful set one at a time, from the
+	// highest ordinal to the lowest, waiting for each pod to be ready before the next one is updated
+	statefulSetRolloutAnnotation  = "kots.io/statefulset-rollout"
+	statefulSetRolloutPartitioned = "partitioned"
+	// statefulSetRolloutTimeoutAnnotation is how long to wait for each pod to be updated and for volumes to be resized
+	statefulSetRolloutTimeoutAnnotation = "kots.io/statefulset-rollout-timeout"
+
+	defaultStatefulSetRolloutTimeout = time.Minute * 10
+)
+
+// statefulSetUpdate is an update of an existing stateful set that can't be done with kubectl apply alone
+type statefulSetUpdate struct {
+	namespace string
+	name      string
+	timeout   time.Duration
+	// partitions is the number of pods to roll out one at a time, zero if the rollout is not partitioned
+	partitions int32
+	// resizedPVCs are the claims that were resized, by name, and their requested size
+	resizedPVCs map[string]resource.Quantity
+}
+
+// prepareStatefulSetUpdates finds the stateful sets in the docs that need special handling and prepares them to be
+// applied. The volume claim templates of a stateful set can't be changed, so if their storage requests grew, the
+// existing claims are resized and the stateful set is deleted without its pods to be recreated by the apply.
+// Stateful sets with a partitioned rollout are applied with all pods in the partition. The updates must be completed
+// with completeStatefulSetUpdate after the docs are applied.
+func prepareStatefulSetUpdates(namespace string, docs []byte) ([]byte, []statefulSetUpdate, error) {
+	var clientset kubernetes.Interface
+	updates := []statefulSetUpdate{}
+
+	splitDocs := strings.Split(string(docs), "\n---\n")
+	for i, doc := range splitDocs {
+		obj, gvk, err := parseK8sYaml([]byte(doc))
+		if err != nil || gvk.Group != "apps" || gvk.Version != "v1" || gvk.Kind != "StatefulSet" {
+			continue
+		}
+		desired := obj.(*appsv1.StatefulSet)
+		statefulSetNamespace := namespace
+		if desired.Namespace != "" {
+			statefulSetNamespace = desired.Namespace
+		}
+
+		if clientset == nil {
+			cfg, err := config.GetConfig()
+			if err != nil {
+				return nil, nil, errors.Wrap(err, "failed to get config")
+			}
+			clientset, err = kubernetes.NewForConfig(cfg)
+			if err != nil {
+				return nil, nil, errors.Wrap(err, "failed to get client set")
+			}
+		}
+
+		existing, err := clientset.AppsV1().StatefulSets(statefulSetNamespace).Get(context.TODO(), desired.Name, metav1.GetOptions{})
+		if kuberneteserrors.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return nil, nil, errors.Wrapf(err, "failed to get stateful set %s", desired.Name)
+		}
+
+		update := statefulSetUpdate{
+			namespace: statefulSetNamespace,
+			name:      desired.Name,
+			timeout:   statefulSetRolloutTimeout(desired.Annotations),
+		}
+
+		increases, err := volumeClaimTemplateIncreases(existing, desired)
+		if err != nil {
+			return nil, nil, errors.Wrapf(err, "stateful set %s", desired.Name)
+		}
+		if len(increases) > 0 {
+			update.resizedPVCs, err = resizeStatefulSetPVCs(clientset, existing, increases)
+			if err != nil {
+				return nil, nil, errors.Wrapf(err, "failed to resize volumes of stateful set %s", desired.Name)
+			}
+			if err := deleteStatefulSetOrphaningPods(clientset, existing); err != nil {
+				return nil, nil, errors.Wrapf(err, "failed to delete stateful set %s", desired.Name)
+			}
+		}
+
+		if desired.Annotations[statefulSetRolloutAnnotation] == statefulSetRolloutPartitioned {
+			partitioned, partitions, err := setStatefulSetPartition(desired)
+			if err != nil {
+				return nil, nil, errors.Wrapf(err, "failed to partition stateful set %s", desired.Name)
+			}
+			if partitioned != nil {
+				splitDocs[i] = string(partitioned)
+				update.partitions = partitions
+			}
+		}
+
+		if update.partitions > 0 || len(update.resizedPVCs) > 0 {
+			updates = append(updates, update)
+		}
+	}
+
+	return []byte(strings.Join(splitDocs, "\n---\n")), updates, nil
+}
+
+// completeStatefulSetUpdate verifies that the volumes of the stateful set were resized and rolls out its pods one
+// partition at a time
+func completeStatefulSetUpdate(update statefulSetUpdate) error {
+	cfg, err := config.GetConfig()
+	if err != nil {
+		return errors.Wrap(err, "failed to get config")
+	}
+	clientset, err := kubernetes.NewForConfig(cfg)
+	if err != nil {
+		return errors.Wrap(err, "failed to get client set")
+	}
+
+	for name, size := range update.resizedPVCs {
+		log.Printf("waiting for pvc %s to be resized to %s", name, size.String())
+		if err := waitForPVCResize(clientset, update.namespace, name, size, update.timeout); err != nil {
+			return errors.Wrapf(err, "failed to resize pvc %s", name)
+		}
+	}
+
+	for partition := update.partitions - 1; partition >= 0; partition-- {
+		log.Printf("rolling out pod %d of stateful set %s", partition, update.name)
+		patch := fmt.Sprintf(`{"spec":{"updateStrategy":{"type":"RollingUpdate","rollingUpdate":{"partition":%d}}}}`, partition)
+		_, err := clientset.AppsV1().StatefulSets(update.namespace).Patch(context.TODO(), update.name, k8stypes.MergePatchType, []byte(patch), metav1.PatchOptions{})
+		if err != nil {
+			return errors.Wrapf(err, "failed to set partition of stateful set %s to %d", update.name, partition)
+		}
+		if err := waitForStatefulSetPod(clientset, update.namespace, update.name, partition, update.timeout); err != nil {
+			// the partition is left where it is, the pods below it run the previous version
+			return errors.Wrapf(err, "pod %d of stateful set %s was not updated", partition, update.name)
+		}
+	}
+
+	return nil
+}
+
+func statefulSetRolloutTimeout(annotations map[string]string) time.Duration {
+	value, ok := annotations[statefulSetRolloutTimeoutAnnotation]
+	if !ok {
+		return defaultStatefulSetRolloutTimeout
+	}
+	timeout, err := time.ParseDuration(value)
+	if err != nil || timeout <= 0 {
+		log.Printf("invalid %s annotation %q, using %s", statefulSetRolloutTimeoutAnnotation, value, defaultStatefulSetRolloutTimeout)
+		return defaultStatefulSetRolloutTimeout
+	}
+	return timeout
+}
+
+// volumeClaimTemplateIncreases returns the storage requests of the volume claim templates that grew, by template
+// name. Volumes can't be shrunk, so a smaller request is an error.
+func volumeClaimTemplateIncreases(existing *appsv1.StatefulSet, desired *appsv1.StatefulSet) (map[string]resource.Quantity, error) {
+	existingRequests := map[string]resource.Quantity{}
+	for _, template := range existing.Spec.VolumeClaimTemplates {
+		existingRequests[template.Name] = template.Spec.Resources.Requests[corev1.ResourceStorage]
+	}
+
+	increases := map[string]resource.Quantity{}
+	for _, template := range desired.Spec.VolumeClaimTemplates {
+		existingRequest, ok := existingRequests[template.Name]
+		if !ok {
+			continue
+		}
+		desiredRequest, ok := template.Spec.Resources.Requests[corev1.ResourceStorage]
+		if !ok {
+			continue
+		}
+		switch desiredRequest.Cmp(existingRequest) {
+		case 1:
+			increases[template.Name] = desiredRequest
+		case -1:
+			return nil, errors.Errorf("volume claim template %s can't be shrunk from %s to %s", template.Name, existingRequest.String(), desiredRequest.String())
+		}
+	}
+
+	return increases, nil
+}
+
+// resizeStatefulSetPVCs requests the new sizes for the claims created from the templates. The storage classes of
+// the claims must allow volume expansion.
+func resizeStatefulSetPVCs(clientset kubernetes.Interface, statefulSet *appsv1.StatefulSet, increases map[string]resource.Quantity) (map[string]resource.Quantity, error) {
+	pvcs, err := clientset.CoreV1().PersistentVolumeClaims(statefulSet.Namespace).List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list pvcs")
+	}
+
+	toResize := []corev1.PersistentVolumeClaim{}
+	for _, pvc := range pvcs.Items {
+		if _, ok := increases[statefulSetPVCTemplate(pvc.Name, statefulSet.Name, increases)]; ok {
+			toResize = append(toResize, pvc)
+		}
+	}
+
+	// check every claim before resizing any so that a failure doesn't leave some claims resized
+	for i := range toResize {
+		if err := checkPVCExpansion(clientset, &toResize[i]); err != nil {
+			return nil, err
+		}
+	}
+
+	resized := map[string]resource.Quantity{}
+	for i := range toResize {
+		pvc := &toResize[i]
+		size := increases[statefulSetPVCTemplate(pvc.Name, statefulSet.Name, increases)]
+		if err := expandPVC(clientset, pvc, size); err != nil {
+			return nil, err
+		}
+		resized[pvc.Name] = size
+	}
+
+	return resized, nil
+}
+
+// statefulSetPVCTemplate returns the volume claim template that the claim was created from, or an empty string if
+// it was not created for the stateful set. Claims are named <template>-<stateful set>-<ordinal>.
+func statefulSetPVCTemplate(pvcName string, statefulSetName string, templates map[string]resource.Quantity) string {
+	for template := range templates {
+		prefix := fmt.Sprintf("%s-%s-", template, statefulSetName)
+		if !strings.HasPrefix(pvcName, prefix) {
+			continue
+		}
+		if _, err := strconv.Atoi(strings.TrimPrefix(pvcName, prefix)); err == nil {
+			return template
+		}
+	}
+	return ""
+}
+
+// deleteStatefulSetOrphaningPods deletes the stateful set and waits for it to be gone. Its pods keep running and
+// are adopted by the stateful set that is applied next.
+func deleteStatefulSetOrphaningPods(clientset kubernetes.Interface, statefulSet *appsv1.StatefulSet) error {
+	log.Printf("deleting stateful set %s to update its volume claim templates, its pods are kept", statefulSet.Name)
+
+	policy := metav1.DeletePropagationOrphan
+	opts := metav1.DeleteOptions{
+		PropagationPolicy: &policy,
+	}
+	err := clientset.AppsV1().StatefulSets(statefulSet.Namespace).Delete(context.TODO(), statefulSet.Name, opts)
+	if err != nil && !kuberneteserrors.IsNotFound(err) {
+		return errors.Wrap(err, "failed to delete")
+	}
+
+	return wait.PollImmediate(time.Second*2, time.Minute*2, func() (bool, error) {
+		_, err := clientset.AppsV1().StatefulSets(statefulSet.Namespace).Get(context.TODO(), statefulSet.Name, metav1.GetOptions{})
+		if kuberneteserrors.IsNotFound(err) {
+			return true, nil
+		}
+		return false, err
+	})
+}
+
+// setStatefulSetPartition returns the yaml of the stateful set with all of its pods in the partition, so that no
+// pods are updated when it's applied, and the number of pods to roll out. It returns nil if the stateful set does
+// not use rolling updates.
+func setStatefulSetPartition(statefulSet *appsv1.StatefulSet) ([]byte, int32, error) {
+	if statefulSet.Spec.UpdateStrategy.Type == appsv1.OnDeleteStatefulSetStrategyType {
+		log.Printf("stateful set %s uses the OnDelete update strategy, its rollout can't be partitioned", statefulSet.Name)
+		return nil, 0, nil
+	}
+
+	replicas := int32(1)
+	if statefulSet.Spec.Replicas != nil {
+		replicas = *statefulSet.Spec.Replicas
+	}
+	if replicas == 0 {
+		return nil, 0, nil
+	}
+
+	statefulSet.Spec.UpdateStrategy = appsv1.StatefulSetUpdateStrategy{
+		Type: appsv1.RollingUpdateStatefulSetStrategyType,
+		RollingUpdate: &appsv1.RollingUpdateStatefulSetStrategy{
+			Partition: &replicas,
+		},
+	}
+	statefulSet.TypeMeta = metav1.TypeMeta{
+		APIVersion: "apps/v1",
+		Kind:       "StatefulSet",
+	}
+
+	b, err := encodeYAML(statefulSet)
+	if err != nil {
+		return nil, 0, errors.Wrap(err, "failed to encode")
+	}
+
+	return b, replicas, nil
+}
+
+// waitForPVCResize waits until the capacity of the claim is at least the size, or until the volume was expanded
+// and only the file system is waiting for the pod to be restarted
+func waitForPVCResize(clientset kubernetes.Interface, namespace string, name string, size resource.Quantity, timeout time.Duration) error {
+	return wait.PollImmediate(time.Second*2, timeout, func() (bool, error) {
+		pvc, err := clientset.CoreV1().PersistentVolumeClaims(namespace).Get(context.TODO(), name, metav1.GetOptions{})
+		if err != nil {
+			return false, errors.Wrap(err, "failed to get pvc")
+		}
+		if capacity, ok := pvc.Status.Capacity[corev1.ResourceStorage]; ok && capacity.Cmp(size) >= 0 {
+			return true, nil
+		}
+		for _, condition := range pvc.Status.Conditions {
+			if condition.Type == corev1.PersistentVolumeClaimFileSystemResizePending && condition.Status == corev1.ConditionTrue {
+				log.Printf("pvc %s is waiting for its pod to be restarted to resize the file system", name)
+				return true, nil
+			}
+		}
+		return false, nil
+	})
+}
+
+// waitForStatefulSetPod waits until the pod with the ordinal runs the update revision of the stateful set and is ready
+func waitForStatefulSetPod(clientset kubernetes.Interface, namespace string, name string, ordinal int32, timeout time.Duration) error {
+	return wait.PollImmediate(time.Second*2, timeout, func() (bool, error) {
+		statefulSet, err := clientset.AppsV1().StatefulSets(namespace).Get(context.TODO(), name, metav1.GetOptions{})
+		if err != nil {
+			return false, errors.Wrap(err, "failed to get stateful set")
+		}
+		if statefulSet.Status.ObservedGeneration < statefulSet.Generation {
+			return false, nil
+		}
+
+		pod, err := clientset.CoreV1().Pods(namespace).Get(context.TODO(), fmt.Sprintf("%s-%d", name, ordinal), metav1.GetOptions{})
+		if kuberneteserrors.IsNotFound(err) {
+			return false, nil
+		}
+		if err != nil {
+			return false, errors.Wrap(err, "failed to get pod")
+		}
+		if pod.Labels[appsv1.StatefulSetRevisionLabel] != statefulSet.Status.UpdateRevision {
+			return false, nil
+		}
+		for _, condition := range pod.Status.Conditions {
+			if condition.Type == corev1.PodReady {
+				return condition.Status == corev1.ConditionTrue, nil
+			}
+		}
+		return false, nil
+	})
+}
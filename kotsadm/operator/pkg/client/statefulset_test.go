@@ -0,0 +1,168 @@
+package client
+
+import (
+	"strings"
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func statefulSetWithStorage(sizes map[string]string) *appsv1.StatefulSet {
+	statefulSet := &appsv1.StatefulSet{}
+	for name, size := range sizes {
+		statefulSet.Spec.VolumeClaimTemplates = append(statefulSet.Spec.VolumeClaimTemplates, corev1.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Spec: corev1.PersistentVolumeClaimSpec{
+				Resources: corev1.ResourceRequirements{
+					Requests: corev1.ResourceList{corev1.ResourceStorage: resource.MustParse(size)},
+				},
+			},
+		})
+	}
+	return statefulSet
+}
+
+func Test_volumeClaimTemplateIncreases(t *testing.T) {
+	tests := []struct {
+		name     string
+		existing map[string]string
+		desired  map[string]string
+		want     map[string]string
+		wantErr  bool
+	}{
+		{
+			name:     "unchanged",
+			existing: map[string]string{"data": "10Gi"},
+			desired:  map[string]string{"data": "10Gi"},
+			want:     map[string]string{},
+		},
+		{
+			name:     "same size in other units",
+			existing: map[string]string{"data": "1Gi"},
+			desired:  map[string]string{"data": "1024Mi"},
+			want:     map[string]string{},
+		},
+		{
+			name:     "increased",
+			existing: map[string]string{"data": "10Gi", "logs": "1Gi"},
+			desired:  map[string]string{"data": "20Gi", "logs": "1Gi"},
+			want:     map[string]string{"data": "20Gi"},
+		},
+		{
+			name:     "new template",
+			existing: map[string]string{"data": "10Gi"},
+			desired:  map[string]string{"data": "10Gi", "logs": "1Gi"},
+			want:     map[string]string{},
+		},
+		{
+			name:     "shrunk",
+			existing: map[string]string{"data": "10Gi"},
+			desired:  map[string]string{"data": "5Gi"},
+			wantErr:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := volumeClaimTemplateIncreases(statefulSetWithStorage(tt.existing), statefulSetWithStorage(tt.desired))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("volumeClaimTemplateIncreases() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("volumeClaimTemplateIncreases() = %v, want %v", got, tt.want)
+			}
+			for name, size := range tt.want {
+				if q, ok := got[name]; !ok || q.Cmp(resource.MustParse(size)) != 0 {
+					t.Errorf("volumeClaimTemplateIncreases()[%s] = %v, want %s", name, got[name], size)
+				}
+			}
+		})
+	}
+}
+
+func Test_statefulSetPVCTemplate(t *testing.T) {
+	templates := map[string]resource.Quantity{"data": resource.MustParse("20Gi")}
+	tests := []struct {
+		pvcName string
+		want    string
+	}{
+		{pvcName: "data-postgres-0", want: "data"},
+		{pvcName: "data-postgres-12", want: "data"},
+		{pvcName: "data-postgres-replica-0", want: ""},
+		{pvcName: "logs-postgres-0", want: ""},
+		{pvcName: "data-redis-0", want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.pvcName, func(t *testing.T) {
+			if got := statefulSetPVCTemplate(tt.pvcName, "postgres", templates); got != tt.want {
+				t.Errorf("statefulSetPVCTemplate() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_setStatefulSetPartition(t *testing.T) {
+	doc := `apiVersion: apps/v1
+kind: StatefulSet
+metadata:
+  name: postgres
+  annotations:
+    kots.io/statefulset-rollout: partitioned
+spec:
+  replicas: 3
+  serviceName: postgres
+  selector:
+    matchLabels:
+      app: postgres
+  template:
+    metadata:
+      labels:
+        app: postgres
+    spec:
+      containers:
+      - name: postgres
+        image: postgres:10`
+
+	obj, _, err := parseK8sYaml([]byte(doc))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	partitioned, partitions, err := setStatefulSetPartition(obj.(*appsv1.StatefulSet))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if partitions != 3 {
+		t.Errorf("setStatefulSetPartition() partitions = %d, want 3", partitions)
+	}
+
+	obj, _, err = parseK8sYaml(partitioned)
+	if err != nil {
+		t.Fatal(err)
+	}
+	statefulSet := obj.(*appsv1.StatefulSet)
+	if p := statefulSet.Spec.UpdateStrategy.RollingUpdate.Partition; p == nil || *p != 3 {
+		t.Errorf("partition = %v, want 3", p)
+	}
+	if statefulSet.Annotations[statefulSetRolloutAnnotation] != statefulSetRolloutPartitioned {
+		t.Errorf("annotations = %v, want the rollout annotation to be kept", statefulSet.Annotations)
+	}
+	if strings.Contains(string(partitioned), "\n---") {
+		t.Errorf("partitioned yaml must be a single document")
+	}
+
+	onDelete := obj.(*appsv1.StatefulSet).DeepCopy()
+	onDelete.Spec.UpdateStrategy = appsv1.StatefulSetUpdateStrategy{Type: appsv1.OnDeleteStatefulSetStrategyType}
+	partitioned, _, err = setStatefulSetPartition(onDelete)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if partitioned != nil {
+		t.Errorf("setStatefulSetPartition() = %s, want nil for OnDelete", partitioned)
+	}
+}
@@ -0,0 +1,141 @@
+package readiness
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/util/jsonpath"
+)
+
+const (
+	// Annotation is set by vendors on resources whose readiness is not known to kots, such as custom resources, or
+	// is decided differently than kots does. Its value is a JSONPath expression against the resource, optionally
+	// compared to a value, for example:
+	//   {.status.phase}=Running
+	//   {.status.conditions[?(@.type=="Ready")].status}=True
+	//   {.status.readyReplicas}
+	// Without a comparison, the resource is ready when the expression has a value other than "false".
+	Annotation = "kots.io/readiness-expression"
+)
+
+type Expression struct {
+	raw   string
+	path  *jsonpath.JSONPath
+	op    string
+	value string
+}
+
+func (e *Expression) String() string {
+	return e.raw
+}
+
+// Parse parses an expression in the format of the readiness annotation
+func Parse(s string) (*Expression, error) {
+	s = strings.TrimSpace(s)
+	e := &Expression{raw: s}
+
+	// the comparison follows the template, which can itself contain "=" in filters
+	templateEnd := strings.LastIndex(s, "}")
+	if !strings.HasPrefix(s, "{") || templateEnd == -1 {
+		return nil, errors.Errorf("expression %q must start with a {} template", s)
+	}
+	template, comparison := s[:templateEnd+1], strings.TrimSpace(s[templateEnd+1:])
+
+	switch {
+	case comparison == "":
+	case strings.HasPrefix(comparison, "!="):
+		e.op, e.value = "!=", strings.TrimSpace(strings.TrimPrefix(comparison, "!="))
+	case strings.HasPrefix(comparison, "=="):
+		e.op, e.value = "=", strings.TrimSpace(strings.TrimPrefix(comparison, "=="))
+	case strings.HasPrefix(comparison, "="):
+		e.op, e.value = "=", strings.TrimSpace(strings.TrimPrefix(comparison, "="))
+	default:
+		return nil, errors.Errorf("unsupported comparison %q in expression %q", comparison, s)
+	}
+	e.value = strings.Trim(e.value, `"'`)
+
+	e.path = jsonpath.New("readiness").AllowMissingKeys(true)
+	if err := e.path.Parse(template); err != nil {
+		return nil, errors.Wrapf(err, "failed to parse expression %q", s)
+	}
+
+	return e, nil
+}
+
+// FromObject returns the expression of the readiness annotation of the object, or nil if it has none
+func FromObject(obj runtime.Object) (*Expression, error) {
+	accessor, err := meta.Accessor(obj)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get object metadata")
+	}
+
+	value, ok := accessor.GetAnnotations()[Annotation]
+	if !ok {
+		return nil, nil
+	}
+
+	return Parse(value)
+}
+
+// IsReady evaluates the expression against the object. A typed object is converted to its unstructured form first,
+// so that the expression uses the field names of the resource's json, as kubectl does.
+func (e *Expression) IsReady(obj runtime.Object) (bool, error) {
+	var content map[string]interface{}
+	if u, ok := obj.(runtime.Unstructured); ok {
+		content = u.UnstructuredContent()
+	} else {
+		c, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+		if err != nil {
+			return false, errors.Wrap(err, "failed to convert object")
+		}
+		content = c
+	}
+
+	results, err := e.path.FindResults(content)
+	if err != nil {
+		return false, errors.Wrapf(err, "failed to evaluate expression %q", e.raw)
+	}
+
+	values := []string{}
+	for _, result := range results {
+		for _, r := range result {
+			var buf bytes.Buffer
+			if err := e.path.PrintResults(&buf, []reflect.Value{r}); err != nil {
+				return false, errors.Wrapf(err, "failed to print result of expression %q", e.raw)
+			}
+			values = append(values, buf.String())
+		}
+	}
+
+	return e.compare(values), nil
+}
+
+// compare returns true if there are values and all of them satisfy the comparison
+func (e *Expression) compare(values []string) bool {
+	if len(values) == 0 {
+		return false
+	}
+
+	for _, value := range values {
+		switch e.op {
+		case "=":
+			if value != e.value {
+				return false
+			}
+		case "!=":
+			if value == e.value {
+				return false
+			}
+		default:
+			if value == "" || strings.EqualFold(value, "false") {
+				return false
+			}
+		}
+	}
+
+	return true
+}
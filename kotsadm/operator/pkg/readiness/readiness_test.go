@@ -0,0 +1,124 @@
+package readiness
+
+import (
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+func TestExpression_IsReady(t *testing.T) {
+	certificate := &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"apiVersion": "cert-manager.io/v1",
+			"kind":       "Certificate",
+			"status": map[string]interface{}{
+				"phase": "Issued",
+				"conditions": []interface{}{
+					map[string]interface{}{"type": "Issuing", "status": "False"},
+					map[string]interface{}{"type": "Ready", "status": "True"},
+				},
+			},
+		},
+	}
+	deployment := &appsv1.Deployment{
+		Status: appsv1.DeploymentStatus{
+			ReadyReplicas: 2,
+		},
+	}
+
+	tests := []struct {
+		name       string
+		expression string
+		obj        runtime.Object
+		want       bool
+	}{
+		{
+			name:       "equal",
+			expression: "{.status.phase}=Issued",
+			obj:        certificate,
+			want:       true,
+		},
+		{
+			name:       "not equal",
+			expression: "{.status.phase}!=Issued",
+			obj:        certificate,
+			want:       false,
+		},
+		{
+			name:       "filter",
+			expression: `{.status.conditions[?(@.type=="Ready")].status}="True"`,
+			obj:        certificate,
+			want:       true,
+		},
+		{
+			name:       "filter not matching",
+			expression: `{.status.conditions[?(@.type=="Issuing")].status}=True`,
+			obj:        certificate,
+			want:       false,
+		},
+		{
+			name:       "missing field",
+			expression: "{.status.url}",
+			obj:        certificate,
+			want:       false,
+		},
+		{
+			name:       "typed object",
+			expression: "{.status.readyReplicas}=2",
+			obj:        deployment,
+			want:       true,
+		},
+		{
+			name:       "typed object without comparison",
+			expression: "{.status.readyReplicas}",
+			obj:        deployment,
+			want:       true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			e, err := Parse(test.expression)
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+
+			ready, err := e.IsReady(test.obj)
+			if err != nil {
+				t.Fatalf("IsReady() error = %v", err)
+			}
+			if ready != test.want {
+				t.Errorf("IsReady() = %v, want %v", ready, test.want)
+			}
+		})
+	}
+}
+
+func TestParse(t *testing.T) {
+	for _, expression := range []string{".status.phase=Running", "{.status.phase}>1", "{.status.phase"} {
+		if _, err := Parse(expression); err == nil {
+			t.Errorf("Parse(%q) expected error", expression)
+		}
+	}
+}
+
+func TestFromObject(t *testing.T) {
+	e, err := FromObject(&appsv1.Deployment{})
+	if err != nil || e != nil {
+		t.Errorf("FromObject() = %v, %v, want nil expression", e, err)
+	}
+
+	e, err = FromObject(&appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Annotations: map[string]string{Annotation: "{.status.readyReplicas}=1"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("FromObject() error = %v", err)
+	}
+	if e.String() != "{.status.readyReplicas}=1" {
+		t.Errorf("FromObject() = %v", e)
+	}
+}
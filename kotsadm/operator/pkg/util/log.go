@@ -0,0 +1,24 @@
+package util
+
+import (
+	"log"
+	"sync/atomic"
+)
+
+var debugLogging int32
+
+// SetLogLevel enables debug logging if the level is "debug". The level is set by kotsadm.
+func SetLogLevel(level string) {
+	if level == "debug" {
+		atomic.StoreInt32(&debugLogging, 1)
+	} else {
+		atomic.StoreInt32(&debugLogging, 0)
+	}
+}
+
+// Debugf logs only if debug logging is enabled
+func Debugf(format string, v ...interface{}) {
+	if atomic.LoadInt32(&debugLogging) == 1 {
+		log.Printf(format, v...)
+	}
+}
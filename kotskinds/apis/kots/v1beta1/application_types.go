@@ -57,6 +57,33 @@ type ApplicationSpec struct {
 	AdditionalNamespaces         []string          `json:"additionalNamespaces,omitempty"`
 	RequireMinimalRBACPrivileges bool              `json:"requireMinimalRBACPrivileges,omitempty"`
 	ProxyPublicImages            bool              `json:"proxyPublicImages,omitempty"`
+	PostDeployTests              []PostDeployTest  `json:"postDeployTests,omitempty"`
+	// RollbackOnPostDeployTestFailure will redeploy the most recent version that passed its
+	// post deploy tests when a test fails
+	RollbackOnPostDeployTestFailure bool                 `json:"rollbackOnPostDeployTestFailure,omitempty"`
+	MeteredEntitlements             []MeteredEntitlement `json:"meteredEntitlements,omitempty"`
+	// SensitiveConfigAsSecret renders sensitive config items as references to a Secret that is created at deploy time,
+	// so that their values are never written to the rendered archive. With gitops, the Secret is only pushed if
+	// secret encryption is configured, otherwise it must be created in the cluster by other means.
+	SensitiveConfigAsSecret bool `json:"sensitiveConfigAsSecret,omitempty"`
+	// MinKotsVersion is the oldest version of kots that can deploy this release.
+	// Versions are still created with an older admin console, but cannot be deployed until it is upgraded
+	MinKotsVersion string `json:"minKotsVersion,omitempty"`
+	// Namespaces are created by kots with their labels and annotations before the application is deployed,
+	// and deleted when the application is removed. They are otherwise treated like additional namespaces
+	Namespaces []ApplicationNamespace `json:"namespaces,omitempty"`
+	// TwoPhaseDeploy deploys each version in two phases. The resources annotated with kots.io/deploy-phase: activate,
+	// such as the Services and Ingresses that route traffic, are held back when the version is deployed, and the other
+	// resources are applied alongside those of the previous version, which are not removed. The held back resources
+	// are applied, and the resources of the previous version removed, when the version is activated.
+	TwoPhaseDeploy bool `json:"twoPhaseDeploy,omitempty"`
+}
+
+// ApplicationNamespace is a namespace that kots creates and manages for the application
+type ApplicationNamespace struct {
+	Name        string            `json:"name"`
+	Labels      map[string]string `json:"labels,omitempty"`
+	Annotations map[string]string `json:"annotations,omitempty"`
 }
 
 type ApplicationPort struct {
@@ -66,6 +93,39 @@ type ApplicationPort struct {
 	ApplicationURL string `json:"applicationUrl,omitempty"`
 }
 
+// MeteredEntitlement is a license entitlement that the application reports usage against.
+// The limit is read from the integer license field named by LicenseField.
+type MeteredEntitlement struct {
+	Name         string `json:"name"`
+	Title        string `json:"title,omitempty"`
+	LicenseField string `json:"licenseField,omitempty"`
+	// Enforcement is one of "warn" or "block", and defaults to "warn"
+	Enforcement string `json:"enforcement,omitempty"`
+}
+
+// PostDeployTest is a check that is run after a version has been deployed.
+// Exactly one of Job or HTTP should be set.
+type PostDeployTest struct {
+	Name           string              `json:"name"`
+	TimeoutSeconds int                 `json:"timeoutSeconds,omitempty"`
+	Job            *PostDeployTestJob  `json:"job,omitempty"`
+	HTTP           *PostDeployTestHTTP `json:"http,omitempty"`
+}
+
+// PostDeployTestJob references a Job included in the application manifests.
+// The test passes when the Job completes and fails when the Job fails.
+// The Job is deleted before each deploy so that it runs again.
+type PostDeployTestJob struct {
+	Name      string `json:"name"`
+	Namespace string `json:"namespace,omitempty"`
+}
+
+// PostDeployTestHTTP is a GET request made from the admin console
+type PostDeployTestHTTP struct {
+	URL                string `json:"url"`
+	ExpectedStatusCode int    `json:"expectedStatusCode,omitempty"`
+}
+
 type MetricGraph struct {
 	Title           string        `json:"title"`
 	Query           string        `json:"query,omitempty"`
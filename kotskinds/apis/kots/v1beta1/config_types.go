@@ -50,6 +50,10 @@ type ConfigItem struct {
 	Affix       string                 `json:"affix,omitempty"`
 	Required    bool                   `json:"required,omitempty"`
 	Items       []ConfigChildItem      `json:"items,omitempty"`
+	// Sensitive items are stored encrypted like passwords, and are rendered as references to a Secret when the application sets sensitiveConfigAsSecret
+	Sensitive bool `json:"sensitive,omitempty"`
+
+	LicenseRequirements []ConfigLicenseRequirement `json:"licenseRequirements,omitempty"`
 	// Props       map[string]interface{} `json:"props,omitempty"`
 	// DefaultCmd  *ConfigItemCmd         `json:"default_cmd,omitempty"`
 	// ValueCmd    *ConfigItemCmd         `json:"value_cmd,omitempty"`
@@ -62,11 +66,62 @@ type ConfigGroup struct {
 	Description string               `json:"description,omitempty"`
 	When        multitype.QuotedBool `json:"when,omitempty"`
 	Items       []ConfigItem         `json:"items,omitempty"`
+
+	LicenseRequirements []ConfigLicenseRequirement `json:"licenseRequirements,omitempty"`
+}
+
+// ConfigLicenseRequirement hides a group or item unless the license field has the given value.
+// When value is empty, the field is expected to be "true".
+type ConfigLicenseRequirement struct {
+	LicenseField string `json:"licenseField"`
+	Value        string `json:"value,omitempty"`
 }
 
 // ConfigSpec defines the desired state of ConfigSpec
 type ConfigSpec struct {
-	Groups []ConfigGroup `json:"groups"`
+	// SchemaVersion is the version of the config items. Config values saved for an older schema version are
+	// migrated with the migrations up to this version when a version is created from the release.
+	SchemaVersion int               `json:"schemaVersion,omitempty"`
+	Groups        []ConfigGroup     `json:"groups"`
+	Migrations    []ConfigMigration `json:"migrations,omitempty"`
+}
+
+// ConfigMigration updates config values saved for a schema version older than SchemaVersion
+type ConfigMigration struct {
+	SchemaVersion int                   `json:"schemaVersion"`
+	Steps         []ConfigMigrationStep `json:"steps"`
+}
+
+// ConfigMigrationStep is one change to the config values. Exactly one of its fields must be set.
+type ConfigMigrationStep struct {
+	Rename    *ConfigRename    `json:"rename,omitempty"`
+	Split     *ConfigSplit     `json:"split,omitempty"`
+	Transform *ConfigTransform `json:"transform,omitempty"`
+}
+
+// ConfigRename moves the value of an item to a new item name
+type ConfigRename struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+// ConfigSplit replaces an item with new items. The values of the new items are templates that can read the value
+// of the split item with ConfigOption.
+type ConfigSplit struct {
+	From string            `json:"from"`
+	To   []ConfigSplitItem `json:"to"`
+}
+
+type ConfigSplitItem struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// ConfigTransform replaces the value of an item with a template, e.g. to convert it to the format of a new item type.
+// The template can read the current value with ConfigOption.
+type ConfigTransform struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
 }
 
 // ConfigStatus defines the observed state of Config
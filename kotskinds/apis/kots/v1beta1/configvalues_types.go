@@ -26,11 +26,17 @@ type ConfigValue struct {
 	Data           string `json:"data,omitempty"`
 	ValuePlaintext string `json:"valuePlaintext,omitempty"`
 	DataPlaintext  string `json:"dataPlaintext,omitempty"`
+	// VaultPath is the path of a vault secret to read the value from at deploy time instead of storing it
+	VaultPath string `json:"vaultPath,omitempty"`
+	// VaultKey is the key in the vault secret, it defaults to the name of the config item
+	VaultKey string `json:"vaultKey,omitempty"`
 }
 
 // ConfigValuesSpec defines the desired state of ConfigValue
 type ConfigValuesSpec struct {
 	Values map[string]ConfigValue `json:"values"`
+	// SchemaVersion is the config schema version that the values were saved for
+	SchemaVersion int `json:"schemaVersion,omitempty"`
 }
 
 // ConfigValuesStatus defines the observed state of ConfigValues
@@ -0,0 +1,81 @@
+/*
+Copyright 2019 Replicated, Inc..
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+type ExposureMethod string
+
+const (
+	ExposureMethodIngress      ExposureMethod = "ingress"
+	ExposureMethodLoadBalancer ExposureMethod = "loadbalancer"
+	ExposureMethodNodePort     ExposureMethod = "nodeport"
+	// ExposureMethodNone leaves the service as it is in the application
+	ExposureMethodNone ExposureMethod = "none"
+)
+
+// ExposureSpec declares the services of the application that the customer can expose
+type ExposureSpec struct {
+	Services []ExposedService `json:"services" yaml:"services"`
+}
+
+type ExposedService struct {
+	// Name identifies the service in the config items that are generated for it
+	Name        string `json:"name" yaml:"name"`
+	Title       string `json:"title,omitempty" yaml:"title,omitempty"`
+	ServiceName string `json:"serviceName" yaml:"serviceName"`
+	ServicePort int    `json:"servicePort" yaml:"servicePort"`
+	// Path is the path of the ingress rule, "/" by default
+	Path string `json:"path,omitempty" yaml:"path,omitempty"`
+	// Methods the customer can choose from, all methods by default
+	Methods       []ExposureMethod `json:"methods,omitempty" yaml:"methods,omitempty"`
+	DefaultMethod ExposureMethod   `json:"defaultMethod,omitempty" yaml:"defaultMethod,omitempty"`
+	// IngressAnnotations are added to the ingress of the service
+	IngressAnnotations map[string]string `json:"ingressAnnotations,omitempty" yaml:"ingressAnnotations,omitempty"`
+}
+
+// ExposureStatus defines the observed state of Exposure
+type ExposureStatus struct {
+}
+
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// Exposure is the Schema for the exposure document
+// +k8s:openapi-gen=true
+// +kubebuilder:subresource:status
+type Exposure struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   ExposureSpec   `json:"spec,omitempty"`
+	Status ExposureStatus `json:"status,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// ExposureList contains a list of Exposures
+type ExposureList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []Exposure `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&Exposure{}, &ExposureList{})
+}
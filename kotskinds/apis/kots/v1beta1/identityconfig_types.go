@@ -91,6 +91,8 @@ type IdentityPostgresConfig struct {
 type IdentityConfigGroup struct {
 	ID      string   `json:"id" yaml:"id"`
 	RoleIDs []string `json:"roleIds" yaml:"roleIds"`
+	// AppSlugs limits the roles of the group to these apps, the roles apply to all apps when it's empty
+	AppSlugs []string `json:"appSlugs,omitempty" yaml:"appSlugs,omitempty"`
 }
 
 type DexConnectors struct {
@@ -31,6 +31,11 @@ type InstallationSpec struct {
 	EncryptionKey string                  `json:"encryptionKey,omitempty"`
 	KnownImages   []InstallationImage     `json:"knownImages,omitempty"`
 	YAMLErrors    []InstallationYAMLError `json:"yamlErrors,omitempty"`
+
+	// UpstreamChecksum is the sha256 of the upstream archive
+	UpstreamChecksum string `json:"upstreamChecksum,omitempty"`
+	// IsRequired is set for releases that must be deployed before any later release can be deployed
+	IsRequired bool `json:"isRequired,omitempty"`
 }
 
 type InstallationImage struct {
@@ -0,0 +1,84 @@
+/*
+Copyright 2019 Replicated, Inc..
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	KotsAppPhasePending    = "Pending"
+	KotsAppPhaseInstalling = "Installing"
+	KotsAppPhaseInstalled  = "Installed"
+	KotsAppPhaseFailed     = "Failed"
+)
+
+// KotsAppSpec defines the desired state of KotsAppSpec
+type KotsAppSpec struct {
+	// LicenseSecretRef selects the key of a secret in the same namespace holding the license yaml
+	LicenseSecretRef corev1.SecretKeySelector `json:"licenseSecretRef"`
+	// Channel is the channel to install from. When empty, the channel from the license is used. It is only used
+	// when the app is installed.
+	Channel string `json:"channel,omitempty"`
+	// ConfigValuesSecretRef selects the key of a secret in the same namespace holding a ConfigValues yaml
+	ConfigValuesSecretRef *corev1.SecretKeySelector `json:"configValuesSecretRef,omitempty"`
+	// AutoDeploy will deploy new versions as soon as they are downloaded
+	AutoDeploy bool `json:"autoDeploy,omitempty"`
+	// SkipPreflights will deploy versions without waiting for preflight checks
+	SkipPreflights bool `json:"skipPreflights,omitempty"`
+}
+
+// KotsAppStatus defines the observed state of KotsApp
+type KotsAppStatus struct {
+	ObservedGeneration int64        `json:"observedGeneration,omitempty"`
+	Phase              string       `json:"phase,omitempty"`
+	Message            string       `json:"message,omitempty"`
+	AppSlug            string       `json:"appSlug,omitempty"`
+	CurrentSequence    *int64       `json:"currentSequence,omitempty"`
+	LastUpdateCheckAt  *metav1.Time `json:"lastUpdateCheckAt,omitempty"`
+	// ConfigValuesHash is the sha256 of the config values that were last set for the app
+	ConfigValuesHash string `json:"configValuesHash,omitempty"`
+	// LicenseHash is the sha256 of the license that was last synced for the app
+	LicenseHash string `json:"licenseHash,omitempty"`
+}
+
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// KotsApp is the Schema for the kotsapp API
+// +k8s:openapi-gen=true
+// +kubebuilder:subresource:status
+type KotsApp struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   KotsAppSpec   `json:"spec,omitempty"`
+	Status KotsAppStatus `json:"status,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// KotsAppList contains a list of KotsApps
+type KotsAppList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []KotsApp `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&KotsApp{}, &KotsAppList{})
+}
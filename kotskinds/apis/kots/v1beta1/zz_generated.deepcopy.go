@@ -178,6 +178,35 @@ func (in *ApplicationList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ApplicationNamespace) DeepCopyInto(out *ApplicationNamespace) {
+	*out = *in
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Annotations != nil {
+		in, out := &in.Annotations, &out.Annotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ApplicationNamespace.
+func (in *ApplicationNamespace) DeepCopy() *ApplicationNamespace {
+	if in == nil {
+		return nil
+	}
+	out := new(ApplicationNamespace)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ApplicationPort) DeepCopyInto(out *ApplicationPort) {
 	*out = *in
@@ -223,6 +252,25 @@ func (in *ApplicationSpec) DeepCopyInto(out *ApplicationSpec) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.PostDeployTests != nil {
+		in, out := &in.PostDeployTests, &out.PostDeployTests
+		*out = make([]PostDeployTest, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.MeteredEntitlements != nil {
+		in, out := &in.MeteredEntitlements, &out.MeteredEntitlements
+		*out = make([]MeteredEntitlement, len(*in))
+		copy(*out, *in)
+	}
+	if in.Namespaces != nil {
+		in, out := &in.Namespaces, &out.Namespaces
+		*out = make([]ApplicationNamespace, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ApplicationSpec.
@@ -319,6 +367,11 @@ func (in *ConfigGroup) DeepCopyInto(out *ConfigGroup) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.LicenseRequirements != nil {
+		in, out := &in.LicenseRequirements, &out.LicenseRequirements
+		*out = make([]ConfigLicenseRequirement, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConfigGroup.
@@ -346,6 +399,11 @@ func (in *ConfigItem) DeepCopyInto(out *ConfigItem) {
 		*out = make([]ConfigChildItem, len(*in))
 		copy(*out, *in)
 	}
+	if in.LicenseRequirements != nil {
+		in, out := &in.LicenseRequirements, &out.LicenseRequirements
+		*out = make([]ConfigLicenseRequirement, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConfigItem.
@@ -358,6 +416,21 @@ func (in *ConfigItem) DeepCopy() *ConfigItem {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConfigLicenseRequirement) DeepCopyInto(out *ConfigLicenseRequirement) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConfigLicenseRequirement.
+func (in *ConfigLicenseRequirement) DeepCopy() *ConfigLicenseRequirement {
+	if in == nil {
+		return nil
+	}
+	out := new(ConfigLicenseRequirement)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ConfigList) DeepCopyInto(out *ConfigList) {
 	*out = *in
@@ -390,6 +463,73 @@ func (in *ConfigList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConfigMigration) DeepCopyInto(out *ConfigMigration) {
+	*out = *in
+	if in.Steps != nil {
+		in, out := &in.Steps, &out.Steps
+		*out = make([]ConfigMigrationStep, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConfigMigration.
+func (in *ConfigMigration) DeepCopy() *ConfigMigration {
+	if in == nil {
+		return nil
+	}
+	out := new(ConfigMigration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConfigMigrationStep) DeepCopyInto(out *ConfigMigrationStep) {
+	*out = *in
+	if in.Rename != nil {
+		in, out := &in.Rename, &out.Rename
+		*out = new(ConfigRename)
+		**out = **in
+	}
+	if in.Split != nil {
+		in, out := &in.Split, &out.Split
+		*out = new(ConfigSplit)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Transform != nil {
+		in, out := &in.Transform, &out.Transform
+		*out = new(ConfigTransform)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConfigMigrationStep.
+func (in *ConfigMigrationStep) DeepCopy() *ConfigMigrationStep {
+	if in == nil {
+		return nil
+	}
+	out := new(ConfigMigrationStep)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConfigRename) DeepCopyInto(out *ConfigRename) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConfigRename.
+func (in *ConfigRename) DeepCopy() *ConfigRename {
+	if in == nil {
+		return nil
+	}
+	out := new(ConfigRename)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ConfigSpec) DeepCopyInto(out *ConfigSpec) {
 	*out = *in
@@ -400,6 +540,13 @@ func (in *ConfigSpec) DeepCopyInto(out *ConfigSpec) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.Migrations != nil {
+		in, out := &in.Migrations, &out.Migrations
+		*out = make([]ConfigMigration, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConfigSpec.
@@ -412,6 +559,41 @@ func (in *ConfigSpec) DeepCopy() *ConfigSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConfigSplit) DeepCopyInto(out *ConfigSplit) {
+	*out = *in
+	if in.To != nil {
+		in, out := &in.To, &out.To
+		*out = make([]ConfigSplitItem, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConfigSplit.
+func (in *ConfigSplit) DeepCopy() *ConfigSplit {
+	if in == nil {
+		return nil
+	}
+	out := new(ConfigSplit)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConfigSplitItem) DeepCopyInto(out *ConfigSplitItem) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConfigSplitItem.
+func (in *ConfigSplitItem) DeepCopy() *ConfigSplitItem {
+	if in == nil {
+		return nil
+	}
+	out := new(ConfigSplitItem)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ConfigStatus) DeepCopyInto(out *ConfigStatus) {
 	*out = *in
@@ -427,6 +609,21 @@ func (in *ConfigStatus) DeepCopy() *ConfigStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConfigTransform) DeepCopyInto(out *ConfigTransform) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConfigTransform.
+func (in *ConfigTransform) DeepCopy() *ConfigTransform {
+	if in == nil {
+		return nil
+	}
+	out := new(ConfigTransform)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ConfigValue) DeepCopyInto(out *ConfigValue) {
 	*out = *in
@@ -632,6 +829,129 @@ func (in *EntitlementValue) DeepCopy() *EntitlementValue {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExposedService) DeepCopyInto(out *ExposedService) {
+	*out = *in
+	if in.Methods != nil {
+		in, out := &in.Methods, &out.Methods
+		*out = make([]ExposureMethod, len(*in))
+		copy(*out, *in)
+	}
+	if in.IngressAnnotations != nil {
+		in, out := &in.IngressAnnotations, &out.IngressAnnotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExposedService.
+func (in *ExposedService) DeepCopy() *ExposedService {
+	if in == nil {
+		return nil
+	}
+	out := new(ExposedService)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Exposure) DeepCopyInto(out *Exposure) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	out.Status = in.Status
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Exposure.
+func (in *Exposure) DeepCopy() *Exposure {
+	if in == nil {
+		return nil
+	}
+	out := new(Exposure)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *Exposure) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExposureList) DeepCopyInto(out *ExposureList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]Exposure, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExposureList.
+func (in *ExposureList) DeepCopy() *ExposureList {
+	if in == nil {
+		return nil
+	}
+	out := new(ExposureList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ExposureList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExposureSpec) DeepCopyInto(out *ExposureSpec) {
+	*out = *in
+	if in.Services != nil {
+		in, out := &in.Services, &out.Services
+		*out = make([]ExposedService, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExposureSpec.
+func (in *ExposureSpec) DeepCopy() *ExposureSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ExposureSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExposureStatus) DeepCopyInto(out *ExposureStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExposureStatus.
+func (in *ExposureStatus) DeepCopy() *ExposureStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ExposureStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *HelmChart) DeepCopyInto(out *HelmChart) {
 	*out = *in
@@ -810,6 +1130,11 @@ func (in *IdentityConfigGroup) DeepCopyInto(out *IdentityConfigGroup) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.AppSlugs != nil {
+		in, out := &in.AppSlugs, &out.AppSlugs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IdentityConfigGroup.
@@ -1321,6 +1646,110 @@ func (in *InstallationYAMLError) DeepCopy() *InstallationYAMLError {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KotsApp) DeepCopyInto(out *KotsApp) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KotsApp.
+func (in *KotsApp) DeepCopy() *KotsApp {
+	if in == nil {
+		return nil
+	}
+	out := new(KotsApp)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *KotsApp) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KotsAppList) DeepCopyInto(out *KotsAppList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]KotsApp, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KotsAppList.
+func (in *KotsAppList) DeepCopy() *KotsAppList {
+	if in == nil {
+		return nil
+	}
+	out := new(KotsAppList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *KotsAppList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KotsAppSpec) DeepCopyInto(out *KotsAppSpec) {
+	*out = *in
+	in.LicenseSecretRef.DeepCopyInto(&out.LicenseSecretRef)
+	if in.ConfigValuesSecretRef != nil {
+		in, out := &in.ConfigValuesSecretRef, &out.ConfigValuesSecretRef
+		*out = new(v1.SecretKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KotsAppSpec.
+func (in *KotsAppSpec) DeepCopy() *KotsAppSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(KotsAppSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KotsAppStatus) DeepCopyInto(out *KotsAppStatus) {
+	*out = *in
+	if in.CurrentSequence != nil {
+		in, out := &in.CurrentSequence, &out.CurrentSequence
+		*out = new(int64)
+		**out = **in
+	}
+	if in.LastUpdateCheckAt != nil {
+		in, out := &in.LastUpdateCheckAt, &out.LastUpdateCheckAt
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KotsAppStatus.
+func (in *KotsAppStatus) DeepCopy() *KotsAppStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(KotsAppStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *License) DeepCopyInto(out *License) {
 	*out = *in
@@ -1463,6 +1892,21 @@ func (in *MappedChartValue) DeepCopy() *MappedChartValue {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MeteredEntitlement) DeepCopyInto(out *MeteredEntitlement) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MeteredEntitlement.
+func (in *MeteredEntitlement) DeepCopy() *MeteredEntitlement {
+	if in == nil {
+		return nil
+	}
+	out := new(MeteredEntitlement)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *MetricGraph) DeepCopyInto(out *MetricGraph) {
 	*out = *in
@@ -1520,6 +1964,61 @@ func (in *OptionalValue) DeepCopy() *OptionalValue {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PostDeployTest) DeepCopyInto(out *PostDeployTest) {
+	*out = *in
+	if in.Job != nil {
+		in, out := &in.Job, &out.Job
+		*out = new(PostDeployTestJob)
+		**out = **in
+	}
+	if in.HTTP != nil {
+		in, out := &in.HTTP, &out.HTTP
+		*out = new(PostDeployTestHTTP)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PostDeployTest.
+func (in *PostDeployTest) DeepCopy() *PostDeployTest {
+	if in == nil {
+		return nil
+	}
+	out := new(PostDeployTest)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PostDeployTestHTTP) DeepCopyInto(out *PostDeployTestHTTP) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PostDeployTestHTTP.
+func (in *PostDeployTestHTTP) DeepCopy() *PostDeployTestHTTP {
+	if in == nil {
+		return nil
+	}
+	out := new(PostDeployTestHTTP)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PostDeployTestJob) DeepCopyInto(out *PostDeployTestJob) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PostDeployTestJob.
+func (in *PostDeployTestJob) DeepCopy() *PostDeployTestJob {
+	if in == nil {
+		return nil
+	}
+	out := new(PostDeployTestJob)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Storage) DeepCopyInto(out *Storage) {
 	*out = *in
@@ -43,6 +43,10 @@ func (c *FakeKotsV1beta1) ConfigValueses(namespace string) v1beta1.ConfigValuesI
 	return &FakeConfigValueses{c, namespace}
 }
 
+func (c *FakeKotsV1beta1) Exposures(namespace string) v1beta1.ExposureInterface {
+	return &FakeExposures{c, namespace}
+}
+
 func (c *FakeKotsV1beta1) HelmCharts(namespace string) v1beta1.HelmChartInterface {
 	return &FakeHelmCharts{c, namespace}
 }
@@ -63,6 +67,10 @@ func (c *FakeKotsV1beta1) Installations(namespace string) v1beta1.InstallationIn
 	return &FakeInstallations{c, namespace}
 }
 
+func (c *FakeKotsV1beta1) KotsApps(namespace string) v1beta1.KotsAppInterface {
+	return &FakeKotsApps{c, namespace}
+}
+
 func (c *FakeKotsV1beta1) Licenses(namespace string) v1beta1.LicenseInterface {
 	return &FakeLicenses{c, namespace}
 }
@@ -25,6 +25,8 @@ type ConfigExpansion interface{}
 
 type ConfigValuesExpansion interface{}
 
+type ExposureExpansion interface{}
+
 type HelmChartExpansion interface{}
 
 type IdentityExpansion interface{}
@@ -35,4 +37,6 @@ type IngressConfigExpansion interface{}
 
 type InstallationExpansion interface{}
 
+type KotsAppExpansion interface{}
+
 type LicenseExpansion interface{}
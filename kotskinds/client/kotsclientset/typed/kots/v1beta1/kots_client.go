@@ -29,11 +29,13 @@ type KotsV1beta1Interface interface {
 	ApplicationsGetter
 	ConfigsGetter
 	ConfigValuesesGetter
+	ExposuresGetter
 	HelmChartsGetter
 	IdentitiesGetter
 	IdentityConfigsGetter
 	IngressConfigsGetter
 	InstallationsGetter
+	KotsAppsGetter
 	LicensesGetter
 }
 
@@ -58,6 +60,10 @@ func (c *KotsV1beta1Client) ConfigValueses(namespace string) ConfigValuesInterfa
 	return newConfigValueses(c, namespace)
 }
 
+func (c *KotsV1beta1Client) Exposures(namespace string) ExposureInterface {
+	return newExposures(c, namespace)
+}
+
 func (c *KotsV1beta1Client) HelmCharts(namespace string) HelmChartInterface {
 	return newHelmCharts(c, namespace)
 }
@@ -78,6 +84,10 @@ func (c *KotsV1beta1Client) Installations(namespace string) InstallationInterfac
 	return newInstallations(c, namespace)
 }
 
+func (c *KotsV1beta1Client) KotsApps(namespace string) KotsAppInterface {
+	return newKotsApps(c, namespace)
+}
+
 func (c *KotsV1beta1Client) Licenses(namespace string) LicenseInterface {
 	return newLicenses(c, namespace)
 }
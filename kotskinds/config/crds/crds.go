@@ -0,0 +1,11 @@
+// Package crds embeds the generated custom resource definitions that are installed in the cluster
+package crds
+
+import (
+	_ "embed"
+)
+
+// KotsApp is the definition of the KotsApp resource, which the admin console reconciles
+//
+//go:embed kots.io_kotsapps.yaml
+var KotsApp []byte
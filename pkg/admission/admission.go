@@ -0,0 +1,153 @@
+package admission
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/replicatedhq/kots/pkg/admission/types"
+	"github.com/replicatedhq/kots/pkg/k8sutil"
+	kuberneteserrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/restmapper"
+	"sigs.k8s.io/yaml"
+)
+
+const fieldManager = "kots"
+
+// DryRun server side applies every manifest in dry run mode so that the api server, including admission
+// webhooks such as OPA Gatekeeper, validates them without persisting anything. Namespaced manifests
+// without a namespace are validated in defaultNamespace.
+func DryRun(manifests []byte, defaultNamespace string) (*types.Validation, error) {
+	cfg, err := k8sutil.GetClusterConfig()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get cluster config")
+	}
+
+	dynamicClient, err := dynamic.NewForConfig(cfg)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create dynamic client")
+	}
+
+	discoveryClient, err := discovery.NewDiscoveryClientForConfig(cfg)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create discovery client")
+	}
+
+	groupResources, err := restmapper.GetAPIGroupResources(discoveryClient)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get api group resources")
+	}
+	mapper := restmapper.NewDiscoveryRESTMapper(groupResources)
+
+	validation := &types.Validation{
+		Results:     []types.Result{},
+		ValidatedAt: time.Now(),
+	}
+	for _, doc := range bytes.Split(manifests, []byte("\n---\n")) {
+		if len(bytes.TrimSpace(doc)) == 0 {
+			continue
+		}
+
+		obj, err := decodeManifest(doc)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to decode manifest")
+		}
+		if obj == nil {
+			continue
+		}
+
+		validation.Results = append(validation.Results, dryRunObject(dynamicClient, mapper, obj, defaultNamespace))
+	}
+
+	return validation, nil
+}
+
+func decodeManifest(doc []byte) (*unstructured.Unstructured, error) {
+	b, err := yaml.YAMLToJSON(doc)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to convert yaml to json")
+	}
+	if string(b) == "null" {
+		return nil, nil
+	}
+
+	obj := &unstructured.Unstructured{}
+	if err := obj.UnmarshalJSON(b); err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal")
+	}
+	return obj, nil
+}
+
+func dryRunObject(dynamicClient dynamic.Interface, mapper meta.RESTMapper, obj *unstructured.Unstructured, defaultNamespace string) types.Result {
+	gvk := obj.GroupVersionKind()
+	result := types.Result{
+		APIVersion: obj.GetAPIVersion(),
+		Kind:       obj.GetKind(),
+		Name:       obj.GetName(),
+		Namespace:  obj.GetNamespace(),
+	}
+
+	mapping, err := mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+	if err != nil {
+		// the kind may be defined by a custom resource definition in the same deploy
+		result.Status = types.StatusSkipped
+		result.Message = fmt.Sprintf("kind %s is not installed in the cluster", gvk.Kind)
+		return result
+	}
+
+	var resourceClient dynamic.ResourceInterface = dynamicClient.Resource(mapping.Resource)
+	if mapping.Scope.Name() == meta.RESTScopeNameNamespace {
+		if result.Namespace == "" {
+			result.Namespace = defaultNamespace
+		}
+		resourceClient = dynamicClient.Resource(mapping.Resource).Namespace(result.Namespace)
+	}
+
+	data, err := obj.MarshalJSON()
+	if err != nil {
+		result.Status = types.StatusRejected
+		result.Message = errors.Wrap(err, "failed to marshal").Error()
+		return result
+	}
+
+	force := true
+	_, err = resourceClient.Patch(context.TODO(), obj.GetName(), k8stypes.ApplyPatchType, data, metav1.PatchOptions{
+		DryRun:       []string{metav1.DryRunAll},
+		FieldManager: fieldManager,
+		Force:        &force,
+	})
+	if err == nil {
+		result.Status = types.StatusPassed
+		return result
+	}
+
+	if _, ok := err.(kuberneteserrors.APIStatus); !ok || kuberneteserrors.IsNotFound(err) {
+		// the api server did not validate the manifest, or its namespace may be created by the same deploy
+		result.Status = types.StatusSkipped
+		result.Message = err.Error()
+		return result
+	}
+
+	result.Status = types.StatusRejected
+	result.Message = err.Error()
+	return result
+}
+
+// RejectionMessage summarizes the rejected manifests of a validation
+func RejectionMessage(validation *types.Validation) string {
+	rejected := validation.Rejected()
+	messages := make([]string, 0, len(rejected))
+	for _, result := range rejected {
+		messages = append(messages, fmt.Sprintf("%s %s: %s", result.Kind, result.Name, result.Message))
+	}
+	return fmt.Sprintf("%d manifests were rejected by the cluster: %s", len(rejected), strings.Join(messages, "; "))
+}
@@ -0,0 +1,40 @@
+package types
+
+import (
+	"time"
+)
+
+type Status string
+
+const (
+	StatusPassed   Status = "passed"
+	StatusRejected Status = "rejected"
+	StatusSkipped  Status = "skipped"
+)
+
+// Result is the outcome of the server side dry run of a single manifest
+type Result struct {
+	APIVersion string `json:"apiVersion"`
+	Kind       string `json:"kind"`
+	Name       string `json:"name"`
+	Namespace  string `json:"namespace,omitempty"`
+	Status     Status `json:"status"`
+	Message    string `json:"message,omitempty"`
+}
+
+// Validation is the set of dry run results recorded before a version was deployed
+type Validation struct {
+	Results     []Result  `json:"results"`
+	ValidatedAt time.Time `json:"validatedAt"`
+}
+
+// Rejected returns the results that were rejected by the cluster
+func (v Validation) Rejected() []Result {
+	rejected := []Result{}
+	for _, result := range v.Results {
+		if result.Status == StatusRejected {
+			rejected = append(rejected, result)
+		}
+	}
+	return rejected
+}
@@ -17,6 +17,7 @@ import (
 	"github.com/replicatedhq/kots/pkg/airgap/types"
 	"github.com/replicatedhq/kots/pkg/archives"
 	"github.com/replicatedhq/kots/pkg/crypto"
+	deployhistorytypes "github.com/replicatedhq/kots/pkg/deployhistory/types"
 	kotsadmconfig "github.com/replicatedhq/kots/pkg/kotsadmconfig"
 	identity "github.com/replicatedhq/kots/pkg/kotsadmidentity"
 	"github.com/replicatedhq/kots/pkg/kotsutil"
@@ -182,6 +183,11 @@ func CreateAppFromAirgap(opts CreateAirgapAppOpts) (finalError error) {
 		configFile = tmpFile.Name()
 	}
 
+	additionalManifests, err := kotsadmconfig.ReadAdditionalManifestsFromInClusterSecret()
+	if err != nil {
+		return errors.Wrap(err, "failed to read additional manifests from in cluster")
+	}
+
 	identityConfigFile, err := identity.InitAppIdentityConfig(opts.PendingApp.Slug, kotsv1beta1.Storage{}, crypto.AESCipher{})
 	if err != nil {
 		return errors.Wrap(err, "failed to init identity config")
@@ -195,6 +201,7 @@ func CreateAppFromAirgap(opts CreateAirgapAppOpts) (finalError error) {
 		LicenseFile:         licenseFile.Name(),
 		ConfigFile:          configFile,
 		IdentityConfigFile:  identityConfigFile,
+		AdditionalManifests: additionalManifests,
 		AirgapRoot:          archiveDir,
 		AirgapBundle:        airgapBundle,
 		Silent:              true,
@@ -288,7 +295,8 @@ func CreateAppFromAirgap(opts CreateAirgapAppOpts) (finalError error) {
 		}
 		if !needsConfig {
 			if opts.SkipPreflights {
-				if err := version.DeployVersion(opts.PendingApp.ID, newSequence); err != nil {
+				deployer := deployhistorytypes.Deployer{DeployedBy: deployhistorytypes.DeployedByInstall, SkippedPreflights: true}
+				if err := version.DeployVersion(opts.PendingApp.ID, newSequence, deployer); err != nil {
 					return errors.Wrap(err, "failed to deploy version")
 				}
 			} else {
@@ -12,8 +12,10 @@ import (
 	"github.com/pkg/errors"
 	kotsv1beta1 "github.com/replicatedhq/kots/kotskinds/apis/kots/v1beta1"
 	apptypes "github.com/replicatedhq/kots/pkg/app/types"
+	"github.com/replicatedhq/kots/pkg/applock"
 	"github.com/replicatedhq/kots/pkg/crypto"
 	"github.com/replicatedhq/kots/pkg/cursor"
+	"github.com/replicatedhq/kots/pkg/deployapproval"
 	identity "github.com/replicatedhq/kots/pkg/kotsadmidentity"
 	"github.com/replicatedhq/kots/pkg/kotsutil"
 	"github.com/replicatedhq/kots/pkg/logger"
@@ -81,6 +83,18 @@ func UpdateAppFromPath(a *apptypes.App, airgapRoot string, airgapBundlePath stri
 		return errors.Wrap(err, "failed to set tasks status")
 	}
 
+	unlock, err := applock.Lock(a.ID, applock.WaitTimeout)
+	if err != nil {
+		return errors.Wrap(err, "failed to lock app")
+	}
+	defer unlock()
+
+	// the current sequence may have changed while waiting for the lock
+	a, err = store.GetStore().GetApp(a.ID)
+	if err != nil {
+		return errors.Wrap(err, "failed to get app")
+	}
+
 	registrySettings, err := store.GetStore().GetRegistryDetailsForApp(a.ID)
 	if err != nil {
 		return errors.Wrap(err, "failed to get app registry settings")
@@ -170,12 +184,13 @@ func UpdateAppFromPath(a *apptypes.App, airgapRoot string, airgapBundlePath stri
 		Silent:              true,
 		RewriteImages:       true,
 		RewriteImageOptions: pull.RewriteImageOptions{
-			ImageFiles: filepath.Join(airgapRoot, "images"),
-			Host:       registrySettings.Hostname,
-			Namespace:  registrySettings.Namespace,
-			Username:   registrySettings.Username,
-			Password:   registrySettings.Password,
-			IsReadOnly: registrySettings.IsReadOnly,
+			ImageFiles:     filepath.Join(airgapRoot, "images"),
+			Host:           registrySettings.Hostname,
+			Namespace:      registrySettings.Namespace,
+			Username:       registrySettings.Username,
+			Password:       registrySettings.Password,
+			IsReadOnly:     registrySettings.IsReadOnly,
+			BandwidthLimit: a.ImagePushBandwidthLimit,
 		},
 		AppSlug:     a.Slug,
 		AppSequence: appSequence,
@@ -235,7 +250,10 @@ func UpdateAppFromPath(a *apptypes.App, airgapRoot string, airgapBundlePath stri
 	}
 
 	if deploy {
-		err := version.DeployVersion(a.ID, newSequence)
+		_, err := deployapproval.DeployOrRequest(a, newSequence, deployapproval.RequestOptions{
+			RequestedBy:      deployapproval.RequestedByAutomaticDeploy,
+			IsSkipPreflights: skipPreflights,
+		})
 		if err != nil {
 			return errors.Wrap(err, "failed to deploy app version")
 		}
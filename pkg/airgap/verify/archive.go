@@ -0,0 +1,296 @@
+package verify
+
+import (
+	"archive/tar"
+	"bufio"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/containers/image/v5/manifest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/pkg/errors"
+)
+
+// maxMetadataSize is the largest file in an image archive whose content is kept to be parsed as a manifest,
+// index or config
+const maxMetadataSize = 4 * 1024 * 1024
+
+var sha256Hex = regexp.MustCompile(`^[a-f0-9]{64}$`)
+
+type archiveProblems struct {
+	checksums []string
+	digests   []string
+}
+
+type archiveFile struct {
+	size int64
+	// digest is the sha256 of the content, or of the decompressed content if it's gzipped
+	digest string
+	// rawDigest is the sha256 of the content as stored
+	rawDigest string
+	// content is kept for small files
+	content []byte
+}
+
+// readArchiveFiles reads every regular file in the tar stream and computes its digest
+func readArchiveFiles(r io.Reader) (map[string]*archiveFile, error) {
+	files := map[string]*archiveFile{}
+
+	tarReader := tar.NewReader(r)
+	for {
+		header, err := tarReader.Next()
+		if err == io.EOF {
+			return files, nil
+		}
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to read image archive")
+		}
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+
+		file, err := readArchiveFile(tarReader, header.Size)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to read %s", header.Name)
+		}
+		files[strings.TrimPrefix(header.Name, "./")] = file
+	}
+}
+
+func readArchiveFile(r io.Reader, size int64) (*archiveFile, error) {
+	file := &archiveFile{size: size}
+
+	rawHash := sha256.New()
+	bufReader := bufio.NewReader(io.TeeReader(r, rawHash))
+
+	var content io.Writer = ioutil.Discard
+	var kept strings.Builder
+	if size <= maxMetadataSize {
+		content = &kept
+	}
+
+	magic, _ := bufReader.Peek(2)
+	hash := sha256.New()
+	if len(magic) == 2 && magic[0] == 0x1f && magic[1] == 0x8b {
+		gzipReader, err := gzip.NewReader(bufReader)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to read gzipped file")
+		}
+		if _, err := io.Copy(hash, gzipReader); err != nil {
+			return nil, errors.Wrap(err, "failed to decompress file")
+		}
+		// read the rest so that the raw digest covers the whole file
+		if _, err := io.Copy(ioutil.Discard, bufReader); err != nil {
+			return nil, errors.Wrap(err, "failed to read file")
+		}
+	} else {
+		if _, err := io.Copy(io.MultiWriter(hash, content), bufReader); err != nil {
+			return nil, errors.Wrap(err, "failed to read file")
+		}
+		if size <= maxMetadataSize {
+			file.content = []byte(kept.String())
+		}
+	}
+
+	file.digest = "sha256:" + hex.EncodeToString(hash.Sum(nil))
+	file.rawDigest = "sha256:" + hex.EncodeToString(rawHash.Sum(nil))
+	return file, nil
+}
+
+type dockerArchiveManifestItem struct {
+	Config string   `json:"Config"`
+	Layers []string `json:"Layers"`
+}
+
+type dockerImageConfig struct {
+	RootFS struct {
+		DiffIDs []string `json:"diff_ids"`
+	} `json:"rootfs"`
+}
+
+// verifyDockerArchive checks that the config and layers in manifest.json are in the archive and that their digests
+// match the config's file name and the config's layer diff ids
+func verifyDockerArchive(r io.Reader) (archiveProblems, error) {
+	problems := archiveProblems{}
+
+	files, err := readArchiveFiles(r)
+	if err != nil {
+		return problems, err
+	}
+
+	manifestFile, ok := files["manifest.json"]
+	if !ok || manifestFile.content == nil {
+		problems.digests = append(problems.digests, "manifest.json is missing")
+		return problems, nil
+	}
+
+	var items []dockerArchiveManifestItem
+	if err := json.Unmarshal(manifestFile.content, &items); err != nil {
+		problems.digests = append(problems.digests, fmt.Sprintf("manifest.json is invalid: %v", err))
+		return problems, nil
+	}
+	if len(items) == 0 {
+		problems.digests = append(problems.digests, "manifest.json has no images")
+	}
+
+	for _, item := range items {
+		configFile, ok := files[item.Config]
+		if !ok {
+			problems.digests = append(problems.digests, fmt.Sprintf("config %s is missing", item.Config))
+			continue
+		}
+		if name := strings.TrimSuffix(path.Base(item.Config), ".json"); sha256Hex.MatchString(name) && configFile.rawDigest != "sha256:"+name {
+			problems.checksums = append(problems.checksums, fmt.Sprintf("config %s has digest %s", item.Config, configFile.rawDigest))
+		}
+
+		config := dockerImageConfig{}
+		if err := json.Unmarshal(configFile.content, &config); err != nil {
+			problems.digests = append(problems.digests, fmt.Sprintf("config %s is invalid: %v", item.Config, err))
+			continue
+		}
+		if len(config.RootFS.DiffIDs) != len(item.Layers) {
+			problems.digests = append(problems.digests, fmt.Sprintf("config %s has %d layers but the manifest has %d", item.Config, len(config.RootFS.DiffIDs), len(item.Layers)))
+			continue
+		}
+
+		for i, layer := range item.Layers {
+			layerFile, ok := files[layer]
+			if !ok {
+				problems.digests = append(problems.digests, fmt.Sprintf("layer %s is missing", layer))
+				continue
+			}
+			if layerFile.digest != config.RootFS.DiffIDs[i] {
+				problems.checksums = append(problems.checksums, fmt.Sprintf("layer %s has digest %s, expected %s", layer, layerFile.digest, config.RootFS.DiffIDs[i]))
+			}
+		}
+	}
+
+	return problems, nil
+}
+
+type ociDescriptor struct {
+	MediaType string `json:"mediaType"`
+	Digest    string `json:"digest"`
+	Size      int64  `json:"size"`
+}
+
+type ociIndexOrManifest struct {
+	MediaType string          `json:"mediaType"`
+	Manifests []ociDescriptor `json:"manifests"`
+	Config    *ociDescriptor  `json:"config"`
+	Layers    []ociDescriptor `json:"layers"`
+}
+
+// verifyOCIArchive checks that every blob's digest matches its name, that every blob referenced from index.json is
+// in the archive with the referenced size, and that the archive is the digest the image is pinned to, if any
+func verifyOCIArchive(r io.Reader, pinnedDigest string) (archiveProblems, error) {
+	problems := archiveProblems{}
+
+	files, err := readArchiveFiles(r)
+	if err != nil {
+		return problems, err
+	}
+
+	blobs := map[string]*archiveFile{}
+	for name, file := range files {
+		if !strings.HasPrefix(name, "blobs/") {
+			continue
+		}
+		parts := strings.Split(name, "/")
+		if len(parts) != 3 {
+			continue
+		}
+		digest := parts[1] + ":" + parts[2]
+		if file.rawDigest != digest && parts[1] == "sha256" {
+			problems.checksums = append(problems.checksums, fmt.Sprintf("blob %s has digest %s", digest, file.rawDigest))
+		}
+		blobs[digest] = file
+	}
+
+	indexFile, ok := files["index.json"]
+	if !ok || indexFile.content == nil {
+		problems.digests = append(problems.digests, "index.json is missing")
+		return problems, nil
+	}
+	index := ociIndexOrManifest{}
+	if err := json.Unmarshal(indexFile.content, &index); err != nil {
+		problems.digests = append(problems.digests, fmt.Sprintf("index.json is invalid: %v", err))
+		return problems, nil
+	}
+	if len(index.Manifests) == 0 {
+		problems.digests = append(problems.digests, "index.json has no manifests")
+		return problems, nil
+	}
+
+	if pinnedDigest != "" {
+		found := false
+		for _, descriptor := range index.Manifests {
+			if descriptor.Digest == pinnedDigest {
+				found = true
+			}
+		}
+		if !found {
+			problems.digests = append(problems.digests, fmt.Sprintf("the image is pinned to %s but the archive does not contain it", pinnedDigest))
+		}
+	}
+
+	visited := map[string]bool{}
+	var visit func(descriptor ociDescriptor)
+	visit = func(descriptor ociDescriptor) {
+		if visited[descriptor.Digest] {
+			return
+		}
+		visited[descriptor.Digest] = true
+
+		blob, ok := blobs[descriptor.Digest]
+		if !ok {
+			problems.digests = append(problems.digests, fmt.Sprintf("blob %s is referenced but missing", descriptor.Digest))
+			return
+		}
+		if descriptor.Size > 0 && blob.size != descriptor.Size {
+			problems.digests = append(problems.digests, fmt.Sprintf("blob %s has size %d, expected %d", descriptor.Digest, blob.size, descriptor.Size))
+		}
+
+		if !isManifestMediaType(descriptor.MediaType) {
+			return
+		}
+		if blob.content == nil {
+			problems.digests = append(problems.digests, fmt.Sprintf("manifest %s can't be read", descriptor.Digest))
+			return
+		}
+		m := ociIndexOrManifest{}
+		if err := json.Unmarshal(blob.content, &m); err != nil {
+			problems.digests = append(problems.digests, fmt.Sprintf("manifest %s is invalid: %v", descriptor.Digest, err))
+			return
+		}
+		for _, child := range m.Manifests {
+			visit(child)
+		}
+		if m.Config != nil {
+			visit(*m.Config)
+		}
+		for _, layer := range m.Layers {
+			visit(layer)
+		}
+	}
+	for _, descriptor := range index.Manifests {
+		visit(descriptor)
+	}
+
+	return problems, nil
+}
+
+func isManifestMediaType(mediaType string) bool {
+	if manifest.MIMETypeIsMultiImage(mediaType) {
+		return true
+	}
+	return mediaType == manifest.DockerV2Schema2MediaType || mediaType == ocispec.MediaTypeImageManifest
+}
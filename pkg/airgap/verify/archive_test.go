@@ -0,0 +1,175 @@
+package verify
+
+import (
+	"archive/tar"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func sha256Digest(b []byte) string {
+	sum := sha256.Sum256(b)
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
+
+func tarFiles(t *testing.T, files map[string][]byte) []byte {
+	var b bytes.Buffer
+	tw := tar.NewWriter(&b)
+	for name, content := range files {
+		require.NoError(t, tw.WriteHeader(&tar.Header{Name: name, Mode: 0644, Size: int64(len(content)), Typeflag: tar.TypeReg}))
+		_, err := tw.Write(content)
+		require.NoError(t, err)
+	}
+	require.NoError(t, tw.Close())
+	return b.Bytes()
+}
+
+func Test_verifyDockerArchive(t *testing.T) {
+	layer := []byte("layer content")
+	config := []byte(fmt.Sprintf(`{"rootfs": {"type": "layers", "diff_ids": [%q]}}`, sha256Digest(layer)))
+	configName := sha256Digest(config)[len("sha256:"):] + ".json"
+	manifest := []byte(fmt.Sprintf(`[{"Config": %q, "Layers": ["layer.tar"]}]`, configName))
+
+	tests := []struct {
+		name          string
+		files         map[string][]byte
+		wantChecksums int
+		wantDigests   int
+	}{
+		{
+			name: "valid",
+			files: map[string][]byte{
+				"manifest.json": manifest,
+				configName:      config,
+				"layer.tar":     layer,
+			},
+		},
+		{
+			name: "corrupted layer",
+			files: map[string][]byte{
+				"manifest.json": manifest,
+				configName:      config,
+				"layer.tar":     []byte("corrupted"),
+			},
+			wantChecksums: 1,
+		},
+		{
+			name: "missing layer",
+			files: map[string][]byte{
+				"manifest.json": manifest,
+				configName:      config,
+			},
+			wantDigests: 1,
+		},
+		{
+			name: "missing manifest",
+			files: map[string][]byte{
+				configName:  config,
+				"layer.tar": layer,
+			},
+			wantDigests: 1,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			problems, err := verifyDockerArchive(bytes.NewReader(tarFiles(t, test.files)))
+			require.NoError(t, err)
+			require.Len(t, problems.checksums, test.wantChecksums)
+			require.Len(t, problems.digests, test.wantDigests)
+		})
+	}
+}
+
+func Test_verifyOCIArchive(t *testing.T) {
+	layer := []byte("layer content")
+	config := []byte(`{}`)
+	manifest := []byte(fmt.Sprintf(`{"schemaVersion": 2, "mediaType": "application/vnd.oci.image.manifest.v1+json",
+  "config": {"mediaType": "application/vnd.oci.image.config.v1+json", "digest": %q, "size": %d},
+  "layers": [{"mediaType": "application/vnd.oci.image.layer.v1.tar", "digest": %q, "size": %d}]}`,
+		sha256Digest(config), len(config), sha256Digest(layer), len(layer)))
+	index := []byte(fmt.Sprintf(`{"schemaVersion": 2, "manifests": [{"mediaType": "application/vnd.oci.image.manifest.v1+json", "digest": %q, "size": %d}]}`,
+		sha256Digest(manifest), len(manifest)))
+
+	blob := func(b []byte) string {
+		return "blobs/sha256/" + sha256Digest(b)[len("sha256:"):]
+	}
+
+	tests := []struct {
+		name          string
+		files         map[string][]byte
+		pinnedDigest  string
+		wantChecksums int
+		wantDigests   int
+	}{
+		{
+			name: "valid",
+			files: map[string][]byte{
+				"index.json":   index,
+				blob(manifest): manifest,
+				blob(config):   config,
+				blob(layer):    layer,
+			},
+			pinnedDigest: sha256Digest(manifest),
+		},
+		{
+			name: "corrupted blob",
+			files: map[string][]byte{
+				"index.json":   index,
+				blob(manifest): manifest,
+				blob(config):   config,
+				blob(layer):    []byte("corrupted"),
+			},
+			wantChecksums: 1,
+			wantDigests:   1, // the size doesn't match either
+		},
+		{
+			name: "missing blob",
+			files: map[string][]byte{
+				"index.json":   index,
+				blob(manifest): manifest,
+				blob(config):   config,
+			},
+			wantDigests: 1,
+		},
+		{
+			name: "other pinned digest",
+			files: map[string][]byte{
+				"index.json":   index,
+				blob(manifest): manifest,
+				blob(config):   config,
+				blob(layer):    layer,
+			},
+			pinnedDigest: sha256Digest([]byte("other")),
+			wantDigests:  1,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			problems, err := verifyOCIArchive(bytes.NewReader(tarFiles(t, test.files)), test.pinnedDigest)
+			require.NoError(t, err)
+			require.Len(t, problems.checksums, test.wantChecksums)
+			require.Len(t, problems.digests, test.wantDigests)
+		})
+	}
+}
+
+func Test_imageRefFromPath(t *testing.T) {
+	ref, pinnedDigest, err := imageRefFromPath([]string{"docker.io", "library", "redis", "5"})
+	require.NoError(t, err)
+	require.Equal(t, "docker.io/library/redis:5", ref)
+	require.Empty(t, pinnedDigest)
+
+	appRef, err := normalizeImageRef("redis:5")
+	require.NoError(t, err)
+	require.Equal(t, ref, appRef)
+
+	digest := "sha256:" + hex.EncodeToString(make([]byte, 32))
+	ref, pinnedDigest, err = imageRefFromPath([]string{"quay.io", "org", "app", "sha256", digest[len("sha256:"):]})
+	require.NoError(t, err)
+	require.Equal(t, "quay.io/org/app@"+digest, ref)
+	require.Equal(t, digest, pinnedDigest)
+}
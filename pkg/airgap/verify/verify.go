@@ -0,0 +1,291 @@
+package verify
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"io"
+	"io/ioutil"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/docker/distribution/reference"
+	"github.com/pkg/errors"
+	"github.com/replicatedhq/kots/pkg/archives"
+	"github.com/replicatedhq/kots/pkg/image"
+	"github.com/replicatedhq/kots/pkg/kotsutil"
+	"k8s.io/client-go/kubernetes/scheme"
+)
+
+const (
+	CheckStructure = "structure"
+	CheckImages    = "images"
+	CheckChecksums = "checksums"
+	CheckDigests   = "digests"
+)
+
+// Check is the outcome of one kind of verification of the bundle
+type Check struct {
+	Name   string   `json:"name"`
+	Errors []string `json:"errors,omitempty"`
+}
+
+func (c Check) Passed() bool {
+	return len(c.Errors) == 0
+}
+
+// Result is the outcome of the verification of an airgap bundle
+type Result struct {
+	Checks []Check `json:"checks"`
+	// Images are the images in the bundle
+	Images []string `json:"images"`
+	// AppImages are the images referenced by the app
+	AppImages []string `json:"appImages"`
+	// Warnings are problems that don't fail the verification, like images that can't be verified without rendering
+	// the app
+	Warnings []string `json:"warnings,omitempty"`
+}
+
+func (r Result) Passed() bool {
+	for _, check := range r.Checks {
+		if !check.Passed() {
+			return false
+		}
+	}
+	return true
+}
+
+type verifier struct {
+	checks   map[string]*Check
+	warnings []string
+}
+
+func (v *verifier) fail(check string, format string, args ...interface{}) {
+	v.checks[check].Errors = append(v.checks[check].Errors, errors.Errorf(format, args...).Error())
+}
+
+// Verify checks the structure of the airgap bundle, that it contains every image the app references, that the
+// checksums of the archives and image blobs match their content, and that the blobs referenced by the image
+// manifests and the digests images are pinned to are in the bundle. An error is returned only if the bundle can't
+// be read, failed checks are in the result.
+func Verify(bundlePath string) (*Result, error) {
+	v := &verifier{
+		checks: map[string]*Check{},
+	}
+	for _, name := range []string{CheckStructure, CheckImages, CheckChecksums, CheckDigests} {
+		v.checks[name] = &Check{Name: name}
+	}
+
+	f, err := os.Open(bundlePath)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to open bundle")
+	}
+	defer f.Close()
+
+	gzipReader, err := gzip.NewReader(f)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read bundle as gzip")
+	}
+	defer gzipReader.Close()
+
+	var airgapYAML, appArchive []byte
+	bundleImages := map[string]string{} // normalized image ref -> path in bundle
+
+	tarReader := tar.NewReader(gzipReader)
+	for {
+		header, err := tarReader.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			// a corrupted gzip stream fails its checksum here
+			v.fail(CheckChecksums, "failed to read bundle: %v", err)
+			break
+		}
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+
+		name := strings.TrimPrefix(header.Name, "./")
+		switch {
+		case name == "airgap.yaml":
+			airgapYAML, err = ioutil.ReadAll(tarReader)
+		case name == "app.tar.gz":
+			appArchive, err = ioutil.ReadAll(tarReader)
+		case strings.HasPrefix(name, "images/"):
+			err = v.verifyImageFile(name, tarReader, bundleImages)
+		default:
+			v.warnings = append(v.warnings, "unexpected file "+name)
+		}
+		if err != nil {
+			v.fail(CheckChecksums, "failed to read %s: %v", name, err)
+		}
+	}
+
+	if airgapYAML == nil {
+		v.fail(CheckStructure, "airgap.yaml is missing")
+	} else if err := verifyAirgapYAML(airgapYAML); err != nil {
+		v.fail(CheckStructure, "airgap.yaml is invalid: %v", err)
+	}
+
+	appImages := []string{}
+	if appArchive == nil {
+		v.fail(CheckStructure, "app.tar.gz is missing")
+	} else {
+		appImages, err = v.verifyAppImages(appArchive, bundleImages)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to verify app images")
+		}
+	}
+
+	result := &Result{
+		Images:    []string{},
+		AppImages: appImages,
+		Warnings:  v.warnings,
+	}
+	for _, name := range []string{CheckStructure, CheckImages, CheckChecksums, CheckDigests} {
+		result.Checks = append(result.Checks, *v.checks[name])
+	}
+	for ref := range bundleImages {
+		result.Images = append(result.Images, ref)
+	}
+	sort.Strings(result.Images)
+
+	return result, nil
+}
+
+func verifyAirgapYAML(b []byte) error {
+	decode := scheme.Codecs.UniversalDeserializer().Decode
+	_, gvk, err := decode(b, nil, nil)
+	if err != nil {
+		return errors.Wrap(err, "failed to decode")
+	}
+	if gvk.Group != "kots.io" || gvk.Version != "v1beta1" || gvk.Kind != "Airgap" {
+		return errors.Errorf("unexpected kind %s", gvk.String())
+	}
+	return nil
+}
+
+// verifyImageFile verifies an image archive in the bundle. Archives are at images/<format>/<name>/<tag> or
+// images/<format>/<name>/sha256/<digest>.
+func (v *verifier) verifyImageFile(name string, r io.Reader, bundleImages map[string]string) error {
+	parts := strings.Split(name, "/")
+	if len(parts) < 4 {
+		v.fail(CheckStructure, "image path %s has too few parts", name)
+		return nil
+	}
+
+	format := parts[1]
+	if format != "docker-archive" && format != "oci-archive" {
+		v.fail(CheckStructure, "image %s has unknown format %s", name, format)
+		return nil
+	}
+
+	ref, pinnedDigest, err := imageRefFromPath(parts[2:])
+	if err != nil {
+		v.fail(CheckStructure, "image path %s is invalid: %v", name, err)
+		return nil
+	}
+	bundleImages[ref] = name
+
+	var problems archiveProblems
+	if format == "oci-archive" {
+		problems, err = verifyOCIArchive(r, pinnedDigest)
+	} else {
+		problems, err = verifyDockerArchive(r)
+	}
+	if err != nil {
+		return err
+	}
+	for _, problem := range problems.checksums {
+		v.fail(CheckChecksums, "%s: %s", ref, problem)
+	}
+	for _, problem := range problems.digests {
+		v.fail(CheckDigests, "%s: %s", ref, problem)
+	}
+
+	return nil
+}
+
+// imageRefFromPath returns the normalized image ref of an image path in the bundle and the digest it's pinned to
+func imageRefFromPath(nameParts []string) (string, string, error) {
+	if len(nameParts) < 2 {
+		return "", "", errors.New("not enough parts")
+	}
+
+	var refStr, pinnedDigest string
+	if len(nameParts) >= 3 && nameParts[len(nameParts)-2] == "sha256" {
+		pinnedDigest = "sha256:" + nameParts[len(nameParts)-1]
+		refStr = strings.Join(nameParts[:len(nameParts)-2], "/") + "@" + pinnedDigest
+	} else {
+		refStr = strings.Join(nameParts[:len(nameParts)-1], "/") + ":" + nameParts[len(nameParts)-1]
+	}
+
+	ref, err := normalizeImageRef(refStr)
+	if err != nil {
+		return "", "", err
+	}
+	return ref, pinnedDigest, nil
+}
+
+func normalizeImageRef(image string) (string, error) {
+	named, err := reference.ParseNormalizedNamed(image)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to parse %s", image)
+	}
+	return reference.TagNameOnly(named).String(), nil
+}
+
+// verifyAppImages checks that every image that the app references is in the bundle. Images that are templated in
+// the release can't be checked without rendering the app and are reported as warnings.
+func (v *verifier) verifyAppImages(appArchive []byte, bundleImages map[string]string) ([]string, error) {
+	appDir, err := ioutil.TempDir("", "kots-airgap-verify")
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create temp dir")
+	}
+	defer os.RemoveAll(appDir)
+
+	if err := archives.ExtractTGZArchiveFromReader(bytes.NewReader(appArchive), appDir); err != nil {
+		v.fail(CheckStructure, "app.tar.gz can't be extracted: %v", err)
+		return []string{}, nil
+	}
+
+	images, err := image.ListImagesInDir(appDir)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list images in app")
+	}
+
+	kotsKinds, err := kotsutil.LoadKotsKindsFromPath(appDir)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to load kots kinds from app")
+	}
+	images = append(images, kotsKinds.KotsApplication.Spec.AdditionalImages...)
+
+	appImages := []string{}
+	seen := map[string]bool{}
+	for _, appImage := range images {
+		if strings.Contains(appImage, "{{") {
+			v.warnings = append(v.warnings, "image "+appImage+" is templated and can't be verified without rendering the app")
+			continue
+		}
+
+		ref, err := normalizeImageRef(appImage)
+		if err != nil {
+			v.fail(CheckImages, "app image %s is invalid: %v", appImage, err)
+			continue
+		}
+		if seen[ref] {
+			continue
+		}
+		seen[ref] = true
+		appImages = append(appImages, ref)
+
+		if _, ok := bundleImages[ref]; !ok {
+			v.fail(CheckImages, "image %s is referenced by the app but is not in the bundle", ref)
+		}
+	}
+	sort.Strings(appImages)
+
+	return appImages, nil
+}
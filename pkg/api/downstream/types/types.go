@@ -32,6 +32,34 @@ type DownstreamVersion struct {
 	GitDeployable            bool                            `json:"gitDeployable,omitempty"`
 	UpstreamReleasedAt       *time.Time                      `json:"upstreamReleasedAt,omitempty"`
 	YamlErrors               []v1beta1.InstallationYAMLError `json:"yamlErrors,omitempty"`
+	IsRequired               bool                            `json:"isRequired,omitempty"`
+	Notes                    string                          `json:"notes,omitempty"`
+	Labels                   []string                        `json:"labels,omitempty"`
+	// TwoPhaseDeploy is set for versions that are deployed in two phases, they are activated after they are deployed
+	TwoPhaseDeploy bool `json:"twoPhaseDeploy,omitempty"`
+	// ActivationStatus is one of the ActivationStatus constants, empty if the version has not been activated
+	ActivationStatus     string     `json:"activationStatus,omitempty"`
+	ActivationStatusInfo string     `json:"activationStatusInfo,omitempty"`
+	ActivatedAt          *time.Time `json:"activatedAt,omitempty"`
+}
+
+// Statuses of the activation of a version that is deployed in two phases
+const (
+	ActivationStatusActivating = "activating"
+	ActivationStatusActivated  = "activated"
+	ActivationStatusFailed     = "failed"
+)
+
+// VersionHistoryOptions selects a page of the versions of a downstream, newest first
+type VersionHistoryOptions struct {
+	Offset int
+	// Limit is the page size, all versions after Offset are returned if it's 0
+	Limit int
+	// Statuses and Sources are matched case insensitively, empty matches every version
+	Statuses      []string
+	Sources       []string
+	CreatedAfter  *time.Time
+	CreatedBefore *time.Time
 }
 
 type DownstreamOutput struct {
@@ -0,0 +1,94 @@
+package types
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+)
+
+// ErrorCode is a machine readable identifier for the kind of error returned by the API
+type ErrorCode string
+
+const (
+	ErrorCodeBadRequest   ErrorCode = "bad_request"
+	ErrorCodeUnauthorized ErrorCode = "unauthorized"
+	ErrorCodeForbidden    ErrorCode = "forbidden"
+	ErrorCodeNotFound     ErrorCode = "not_found"
+	ErrorCodeConflict     ErrorCode = "conflict"
+	ErrorCodeInternal     ErrorCode = "internal_error"
+
+	// ErrorCodeUploadTooLarge is returned when an upload exceeds the upload quota
+	ErrorCodeUploadTooLarge ErrorCode = "upload_too_large"
+	// ErrorCodeInsufficientDiskSpace is returned when an upload would not leave the reserved disk space free
+	ErrorCodeInsufficientDiskSpace ErrorCode = "insufficient_disk_space"
+
+	// ErrorCodeSessionExpired is returned when the session is past its lifetime or has timed out due to inactivity
+	ErrorCodeSessionExpired ErrorCode = "session_expired"
+	// ErrorCodeReauthRequired is returned for sensitive operations when the session was not issued recently enough
+	ErrorCodeReauthRequired ErrorCode = "reauth_required"
+	// ErrorCodeInvalidCSRFToken is returned for mutating browser requests without the csrf token of the session
+	ErrorCodeInvalidCSRFToken ErrorCode = "invalid_csrf_token"
+
+	// ErrorCodeKotsUpgradeRequired is returned when a version requires a newer admin console
+	ErrorCodeKotsUpgradeRequired ErrorCode = "kots_upgrade_required"
+	// ErrorCodePreflightsFailed is returned when a deploy requires passing preflight checks and they failed
+	ErrorCodePreflightsFailed ErrorCode = "preflights_failed"
+	// ErrorCodeDeploysFrozen is returned when a deploy is blocked by a deploy freeze of the app or of all apps
+	ErrorCodeDeploysFrozen ErrorCode = "deploys_frozen"
+)
+
+// ErrorResponse is the envelope returned by handlers when a request fails.
+// Success and Error are kept for compatibility with clients that predate the code and request id.
+type ErrorResponse struct {
+	Success   bool      `json:"success"`
+	Error     string    `json:"error"`
+	Code      ErrorCode `json:"code"`
+	RequestID string    `json:"requestId,omitempty"`
+}
+
+// APIError is an error that was returned by the API in an ErrorResponse envelope
+type APIError struct {
+	StatusCode int
+	Code       ErrorCode
+	Message    string
+	RequestID  string
+}
+
+func (e *APIError) Error() string {
+	msg := e.Message
+	if msg == "" {
+		msg = fmt.Sprintf("unexpected status code %d", e.StatusCode)
+	}
+	if e.RequestID != "" {
+		return fmt.Sprintf("%s (request id %s)", msg, e.RequestID)
+	}
+	return msg
+}
+
+// ErrorFromResponse reads an error envelope from a failed response. Responses that do not contain
+// an envelope are reported with their status code only.
+func ErrorFromResponse(resp *http.Response) error {
+	apiErr := &APIError{
+		StatusCode: resp.StatusCode,
+		RequestID:  resp.Header.Get("X-Request-Id"),
+	}
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil || len(body) == 0 {
+		return apiErr
+	}
+
+	errorResponse := ErrorResponse{}
+	if err := json.Unmarshal(body, &errorResponse); err != nil {
+		return apiErr
+	}
+
+	apiErr.Code = errorResponse.Code
+	apiErr.Message = errorResponse.Error
+	if errorResponse.RequestID != "" {
+		apiErr.RequestID = errorResponse.RequestID
+	}
+
+	return apiErr
+}
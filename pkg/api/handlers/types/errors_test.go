@@ -0,0 +1,72 @@
+package types
+
+import (
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ErrorFromResponse(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  int
+		body    string
+		header  string
+		want    *APIError
+		wantErr string
+	}{
+		{
+			name:   "envelope",
+			status: http.StatusInternalServerError,
+			body:   `{"success":false,"error":"failed to get app from slug","code":"internal_error","requestId":"abc"}`,
+			want: &APIError{
+				StatusCode: http.StatusInternalServerError,
+				Code:       ErrorCodeInternal,
+				Message:    "failed to get app from slug",
+				RequestID:  "abc",
+			},
+			wantErr: "failed to get app from slug (request id abc)",
+		},
+		{
+			name:   "no body uses the request id header",
+			status: http.StatusForbidden,
+			header: "def",
+			want: &APIError{
+				StatusCode: http.StatusForbidden,
+				RequestID:  "def",
+			},
+			wantErr: "unexpected status code 403 (request id def)",
+		},
+		{
+			name:   "not json",
+			status: http.StatusBadGateway,
+			body:   "<html></html>",
+			want: &APIError{
+				StatusCode: http.StatusBadGateway,
+			},
+			wantErr: "unexpected status code 502",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			req := require.New(t)
+
+			resp := &http.Response{
+				StatusCode: test.status,
+				Header:     http.Header{},
+				Body:       ioutil.NopCloser(strings.NewReader(test.body)),
+			}
+			if test.header != "" {
+				resp.Header.Set("X-Request-Id", test.header)
+			}
+
+			err := ErrorFromResponse(resp)
+			req.Equal(test.want, err)
+			req.EqualError(err, test.wantErr)
+		})
+	}
+}
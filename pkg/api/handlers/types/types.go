@@ -3,33 +3,55 @@ package types
 import (
 	"time"
 
+	kotsv1beta1 "github.com/replicatedhq/kots/kotskinds/apis/kots/v1beta1"
 	appstatustypes "github.com/replicatedhq/kots/pkg/api/appstatus/types"
 	downstreamtypes "github.com/replicatedhq/kots/pkg/api/downstream/types"
 	versiontypes "github.com/replicatedhq/kots/pkg/api/version/types"
+	deployhistorytypes "github.com/replicatedhq/kots/pkg/deployhistory/types"
+	kotsadmstatetypes "github.com/replicatedhq/kots/pkg/kotsadmstate/types"
+	maintenancetypes "github.com/replicatedhq/kots/pkg/maintenance/types"
+	scheduledjobtypes "github.com/replicatedhq/kots/pkg/scheduledjob/types"
 )
 
 type ListAppsResponse struct {
 	Apps []ResponseApp `json:"apps"`
+	// TotalCount is the number of apps that can be listed, of which Apps is a page
+	TotalCount int `json:"totalCount"`
+}
+
+type GetAppsSummaryResponse struct {
+	TotalCount   int                          `json:"totalCount"`
+	StatusCounts map[appstatustypes.State]int `json:"statusCounts"`
+	// PendingUpdatesCount is the number of versions that are newer than the deployed versions, across all apps
+	PendingUpdatesCount         int64 `json:"pendingUpdatesCount"`
+	AppsWithPendingUpdatesCount int   `json:"appsWithPendingUpdatesCount"`
 }
 
 type AppStatusResponse struct {
 	AppStatus *appstatustypes.AppStatus `json:"appstatus"`
+	// MaintenanceMessage is the app or global maintenance message to show, if one is set and has not ended
+	MaintenanceMessage *maintenancetypes.MaintenanceMessage `json:"maintenanceMessage,omitempty"`
 }
 
 type ResponseApp struct {
-	ID                string     `json:"id"`
-	Slug              string     `json:"slug"`
-	Name              string     `json:"name"`
-	IsAirgap          bool       `json:"isAirgap"`
-	CurrentSequence   int64      `json:"currentSequence"`
-	UpstreamURI       string     `json:"upstreamUri"`
-	IconURI           string     `json:"iconUri"`
-	CreatedAt         time.Time  `json:"createdAt"`
-	UpdatedAt         *time.Time `json:"updatedAt"`
-	LastUpdateCheckAt string     `json:"lastUpdateCheckAt"`
-	HasPreflight      bool       `json:"hasPreflight"`
-	IsConfigurable    bool       `json:"isConfigurable"`
-	UpdateCheckerSpec string     `json:"updateCheckerSpec"`
+	ID                      string     `json:"id"`
+	Slug                    string     `json:"slug"`
+	Name                    string     `json:"name"`
+	IsAirgap                bool       `json:"isAirgap"`
+	CurrentSequence         int64      `json:"currentSequence"`
+	UpstreamURI             string     `json:"upstreamUri"`
+	IconURI                 string     `json:"iconUri"`
+	CreatedAt               time.Time  `json:"createdAt"`
+	UpdatedAt               *time.Time `json:"updatedAt"`
+	LastUpdateCheckAt       string     `json:"lastUpdateCheckAt"`
+	HasPreflight            bool       `json:"hasPreflight"`
+	IsConfigurable          bool       `json:"isConfigurable"`
+	UpdateCheckerSpec       string     `json:"updateCheckerSpec"`
+	DeployPolicy            string     `json:"deployPolicy"`
+	AdmissionDryRun         bool       `json:"admissionDryRun"`
+	ImagePushBandwidthLimit int64      `json:"imagePushBandwidthLimit"`
+	IsArchived              bool       `json:"isArchived"`
+	RequireDeployApproval   bool       `json:"requireDeployApproval"`
 
 	IsGitOpsSupported             bool                     `json:"isGitOpsSupported"`
 	IsIdentityServiceSupported    bool                     `json:"isIdentityServiceSupported"`
@@ -41,6 +63,10 @@ type ResponseApp struct {
 	CurrentVersion                *versiontypes.AppVersion `json:"currentVersion"`
 
 	Downstreams []ResponseDownstream `json:"downstreams"`
+	Labels      map[string]string    `json:"labels"`
+
+	// CachedIconPath is the path of the cached icon relative to the api, empty if IconURI should be used as is
+	CachedIconPath string `json:"cachedIconPath,omitempty"`
 }
 
 type ResponseDownstream struct {
@@ -83,3 +109,119 @@ type ResponsePendingApp struct {
 	Name        string `json:"name"`
 	LicenseData string `json:"licenseData"`
 }
+
+type GetRuntimeDiagnosticsResponse struct {
+	GoVersion     string `json:"goVersion"`
+	NumGoroutine  int    `json:"numGoroutine"`
+	NumCPU        int    `json:"numCpu"`
+	HeapAlloc     uint64 `json:"heapAlloc"`
+	HeapInuse     uint64 `json:"heapInuse"`
+	HeapObjects   uint64 `json:"heapObjects"`
+	HeapSys       uint64 `json:"heapSys"`
+	StackInuse    uint64 `json:"stackInuse"`
+	Sys           uint64 `json:"sys"`
+	TotalAlloc    uint64 `json:"totalAlloc"`
+	NumGC         uint32 `json:"numGc"`
+	PauseTotalNs  uint64 `json:"pauseTotalNs"`
+	LastGCUnixNs  uint64 `json:"lastGcUnixNs"`
+	NextGCHeapLen uint64 `json:"nextGcHeapLen"`
+}
+
+// GetPrometheusSettingsResponse does not include the password or bearer token
+type GetPrometheusSettingsResponse struct {
+	Address          string `json:"address"`
+	IsAddressFromEnv bool   `json:"isAddressFromEnv"`
+	Username         string `json:"username,omitempty"`
+	HasPassword      bool   `json:"hasPassword"`
+	HasBearerToken   bool   `json:"hasBearerToken"`
+}
+
+type ImportKotsadmStateResponse struct {
+	Apps []kotsadmstatetypes.ImportedApp `json:"apps"`
+}
+
+type GetAppConfigValuesResponse struct {
+	Sequence     int64  `json:"sequence"`
+	ConfigValues string `json:"configValues"`
+}
+
+type CloneAppRequest struct {
+	Name      string `json:"name"`
+	Namespace string `json:"namespace"`
+}
+
+type CloneAppResponse struct {
+	Slug      string `json:"slug"`
+	Namespace string `json:"namespace"`
+}
+
+// PreviewAppConfigRequest has either the config values to apply to the current version, as set with
+// kots set config, or the config groups for the sequence, as edited in the console
+type PreviewAppConfigRequest struct {
+	ConfigValues []byte                    `json:"configValues,omitempty"`
+	Merge        bool                      `json:"merge,omitempty"`
+	Sequence     int64                     `json:"sequence"`
+	ConfigGroups []kotsv1beta1.ConfigGroup `json:"configGroups,omitempty"`
+}
+
+type PreviewAppConfigResponse struct {
+	Sequence     int64  `json:"sequence"`
+	FilesChanged int    `json:"filesChanged"`
+	LinesAdded   int    `json:"linesAdded"`
+	LinesRemoved int    `json:"linesRemoved"`
+	Diff         string `json:"diff"`
+}
+
+// RecomputeAppConfigRequest has the changed items and either the config values to apply to the current version, as
+// set with kots set config, or the config groups for the sequence, as edited in the console. The changed items are
+// the items of the config values if they are not set.
+type RecomputeAppConfigRequest struct {
+	ChangedItems []string                  `json:"changedItems,omitempty"`
+	ConfigValues []byte                    `json:"configValues,omitempty"`
+	Merge        bool                      `json:"merge,omitempty"`
+	Sequence     int64                     `json:"sequence"`
+	ConfigGroups []kotsv1beta1.ConfigGroup `json:"configGroups,omitempty"`
+}
+
+type RecomputeAppConfigResponse struct {
+	Sequence int64                  `json:"sequence"`
+	Items    []RecomputedConfigItem `json:"items"`
+}
+
+// RecomputedConfigItem is the value of a config item that is derived from the changed items
+type RecomputedConfigItem struct {
+	Name    string `json:"name"`
+	Value   string `json:"value"`
+	Default string `json:"default"`
+}
+
+type SetAppVersionNotesRequest struct {
+	Notes  string   `json:"notes"`
+	Labels []string `json:"labels"`
+}
+
+type GetDeployHistoryResponse struct {
+	Events []deployhistorytypes.Event `json:"events"`
+}
+
+type ListScheduledJobsResponse struct {
+	Jobs []ScheduledJob `json:"jobs"`
+}
+
+type ScheduledJob struct {
+	Job scheduledjobtypes.Job `json:"job"`
+	// AppSlug is empty for jobs that are not app specific
+	AppSlug  string           `json:"appSlug,omitempty"`
+	Schedule string           `json:"schedule,omitempty"`
+	LastRun  *ScheduledJobRun `json:"lastRun,omitempty"`
+	// NextRunAt is in RFC3339 format, it's empty if nothing is scheduled
+	NextRunAt string `json:"nextRunAt,omitempty"`
+}
+
+type ScheduledJobRun struct {
+	Status          scheduledjobtypes.Status `json:"status"`
+	Message         string                   `json:"message,omitempty"`
+	StartedAt       string                   `json:"startedAt"`
+	FinishedAt      string                   `json:"finishedAt,omitempty"`
+	DurationSeconds float64                  `json:"durationSeconds"`
+}
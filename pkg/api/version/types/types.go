@@ -1,6 +1,8 @@
 package types
 
 import (
+	"errors"
+	"fmt"
 	"time"
 
 	"github.com/replicatedhq/kots/pkg/kotsutil"
@@ -12,6 +14,8 @@ type AppVersion struct {
 	Status     string              `json:"status"`
 	CreatedOn  time.Time           `json:"createdOn"`
 	DeployedAt *time.Time          `json:"deployedAt"`
+	Notes      string              `json:"notes,omitempty"`
+	Labels     []string            `json:"labels,omitempty"`
 }
 
 type RealizedLink struct {
@@ -25,3 +29,22 @@ type ForwardedPort struct {
 	ServiceName    string `json:"serviceName"`
 	ServicePort    int    `json:"servicePort"`
 }
+
+// AppVersionNotCreatedError is returned when an app version could not be created. Nothing of the version is kept,
+// its database rows are rolled back and its archive is deleted, so the version can be created again.
+type AppVersionNotCreatedError struct {
+	Err error
+}
+
+func (e AppVersionNotCreatedError) Error() string {
+	return fmt.Sprintf("app version was not created: %s", e.Err)
+}
+
+func (e AppVersionNotCreatedError) Unwrap() error {
+	return e.Err
+}
+
+// IsAppVersionNotCreated returns true if the error is or wraps an AppVersionNotCreatedError
+func IsAppVersionNotCreated(err error) bool {
+	return errors.As(err, &AppVersionNotCreatedError{})
+}
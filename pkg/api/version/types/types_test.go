@@ -0,0 +1,17 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_IsAppVersionNotCreated(t *testing.T) {
+	notCreated := AppVersionNotCreatedError{Err: errors.New("failed to create gitops commit")}
+
+	require.True(t, IsAppVersionNotCreated(notCreated))
+	require.True(t, IsAppVersionNotCreated(errors.Wrap(notCreated, "failed to create new version")))
+	require.False(t, IsAppVersionNotCreated(errors.New("failed to create new version")))
+	require.Equal(t, "app version was not created: failed to create gitops commit", notCreated.Error())
+}
@@ -4,12 +4,16 @@ import (
 	"io/ioutil"
 	"os"
 	"path/filepath"
+	"strconv"
+	"time"
 
 	"github.com/pkg/errors"
 	kotsv1beta1 "github.com/replicatedhq/kots/kotskinds/apis/kots/v1beta1"
 	"github.com/replicatedhq/kots/pkg/crypto"
+	kotsadmtypes "github.com/replicatedhq/kots/pkg/kotsadm/types"
 	identity "github.com/replicatedhq/kots/pkg/kotsadmidentity"
 	"github.com/replicatedhq/kots/pkg/kotsutil"
+	"github.com/replicatedhq/kots/pkg/session"
 	"github.com/replicatedhq/kots/pkg/store"
 	"k8s.io/client-go/kubernetes/scheme"
 )
@@ -23,6 +27,35 @@ func bootstrap() error {
 		return errors.Wrap(err, "failed to bootstrap cluster token")
 	}
 
+	if err := bootstrapReadOnlyMode(); err != nil {
+		return errors.Wrap(err, "failed to bootstrap read-only mode")
+	}
+
+	if err := bootstrapSessionSettings(); err != nil {
+		return errors.Wrap(err, "failed to bootstrap session settings")
+	}
+
+	return nil
+}
+
+// runSchemaMigrations migrates the database to the latest schema version. In dev environments,
+// KOTSADM_SCHEMA_ROLLBACK_VERSION can be set to roll the schema back to that version instead.
+func runSchemaMigrations() error {
+	if rollbackVersion := os.Getenv("KOTSADM_SCHEMA_ROLLBACK_VERSION"); rollbackVersion != "" {
+		targetVersion, err := strconv.ParseInt(rollbackVersion, 10, 64)
+		if err != nil {
+			return errors.Wrap(err, "failed to parse KOTSADM_SCHEMA_ROLLBACK_VERSION")
+		}
+		if err := store.GetStore().RollbackSchemaMigrations(targetVersion); err != nil {
+			return errors.Wrap(err, "failed to roll back schema migrations")
+		}
+		return nil
+	}
+
+	if err := store.GetStore().RunSchemaMigrations(); err != nil {
+		return errors.Wrap(err, "failed to run schema migrations")
+	}
+
 	return nil
 }
 
@@ -48,6 +81,53 @@ func bootstrapClusterToken() error {
 	return nil
 }
 
+// bootstrapReadOnlyMode applies the --read-only-console install flag the first time kotsadm starts
+func bootstrapReadOnlyMode() error {
+	installationParams, err := kotsutil.GetInstallationParams(kotsadmtypes.KotsadmConfigMap)
+	if err != nil {
+		return errors.Wrap(err, "failed to get installation params")
+	}
+
+	if !installationParams.ReadOnlyConsole {
+		return nil
+	}
+
+	if err := store.GetStore().InitReadOnlyMode(true); err != nil {
+		return errors.Wrap(err, "failed to init read-only mode")
+	}
+
+	return nil
+}
+
+// bootstrapSessionSettings applies the session install flags the first time kotsadm starts
+func bootstrapSessionSettings() error {
+	installationParams, err := kotsutil.GetInstallationParams(kotsadmtypes.KotsadmConfigMap)
+	if err != nil {
+		return errors.Wrap(err, "failed to get installation params")
+	}
+
+	if installationParams.SessionTTL == 0 && installationParams.SessionIdleTimeout == 0 && installationParams.SessionReauthWindow == 0 {
+		return nil
+	}
+
+	settings := session.DefaultSettings()
+	if installationParams.SessionTTL > 0 {
+		settings.TTLMinutes = int64(installationParams.SessionTTL / time.Minute)
+	}
+	settings.IdleTimeoutMinutes = int64(installationParams.SessionIdleTimeout / time.Minute)
+	settings.ReauthWindowMinutes = int64(installationParams.SessionReauthWindow / time.Minute)
+
+	if err := session.ValidateSettings(settings); err != nil {
+		return errors.Wrap(err, "invalid session install flags")
+	}
+
+	if err := store.GetStore().InitSessionSettings(settings); err != nil {
+		return errors.Wrap(err, "failed to init session settings")
+	}
+
+	return nil
+}
+
 func bootstrapIdentity() error {
 	err := identity.CreateDexPostgresDatabase("dex", "dex", os.Getenv("DEX_PGPASSWORD"))
 	if err != nil {
@@ -13,17 +13,32 @@ import (
 
 	"github.com/gorilla/mux"
 	"github.com/pkg/errors"
+	"github.com/replicatedhq/kots/pkg/archiveintegrity"
 	"github.com/replicatedhq/kots/pkg/automation"
+	"github.com/replicatedhq/kots/pkg/cabundle"
+	"github.com/replicatedhq/kots/pkg/canary"
+	"github.com/replicatedhq/kots/pkg/configfile"
+	"github.com/replicatedhq/kots/pkg/endpoints"
+	"github.com/replicatedhq/kots/pkg/gitopsstatus"
 	"github.com/replicatedhq/kots/pkg/handlers"
 	"github.com/replicatedhq/kots/pkg/informers"
+	"github.com/replicatedhq/kots/pkg/janitor"
 	"github.com/replicatedhq/kots/pkg/k8sutil"
+	kotsadmtypes "github.com/replicatedhq/kots/pkg/kotsadm/types"
+	"github.com/replicatedhq/kots/pkg/kotsappcontroller"
+	"github.com/replicatedhq/kots/pkg/kotsutil"
 	"github.com/replicatedhq/kots/pkg/logger"
 	"github.com/replicatedhq/kots/pkg/policy"
+	"github.com/replicatedhq/kots/pkg/proxyauth"
 	"github.com/replicatedhq/kots/pkg/rbac"
+	"github.com/replicatedhq/kots/pkg/remoteinstall"
+	"github.com/replicatedhq/kots/pkg/restoredrill"
 	"github.com/replicatedhq/kots/pkg/snapshotscheduler"
 	"github.com/replicatedhq/kots/pkg/socketservice"
 	"github.com/replicatedhq/kots/pkg/store"
 	"github.com/replicatedhq/kots/pkg/supportbundle"
+	"github.com/replicatedhq/kots/pkg/template"
+	"github.com/replicatedhq/kots/pkg/tracing"
 	"github.com/replicatedhq/kots/pkg/updatechecker"
 	"github.com/segmentio/ksuid"
 )
@@ -37,12 +52,21 @@ func Start() {
 	}
 	cancel()
 
+	// refuse to start against a database migrated by a newer kotsadm before anything is written to it
+	if err := runSchemaMigrations(); err != nil {
+		panic(err)
+	}
+
 	if err := bootstrap(); err != nil {
 		panic(err)
 	}
 
 	store.GetStore().RunMigrations()
 
+	if err := applyLogSettings(); err != nil {
+		log.Println("Failed to apply log settings", err)
+	}
+
 	err := bootstrapIdentity()
 	if err != nil {
 		panic(err)
@@ -52,6 +76,31 @@ func Start() {
 		logger.Infof("failed to generate kotsadm id:", err)
 	}
 
+	shutdownTracing, err := tracing.Init("kotsadm")
+	if err != nil {
+		log.Println("Failed to initialize tracing", err)
+	} else {
+		defer shutdownTracing()
+	}
+
+	template.SetConfigFileReader(configfile.ReadIfRef)
+
+	if err := cabundle.Start(os.Getenv("POD_NAMESPACE")); err != nil {
+		log.Println("Failed to load ca bundle", err)
+	}
+
+	if err := proxyauth.Start(os.Getenv("POD_NAMESPACE")); err != nil {
+		log.Println("Failed to load proxy credentials", err)
+	}
+
+	installationParams, err := kotsutil.GetInstallationParams(kotsadmtypes.KotsadmConfigMap)
+	if err != nil {
+		log.Println("Failed to get installation params", err)
+	} else {
+		handlers.SetCORSAllowedOrigins(installationParams.CORSAllowedOrigins)
+		endpoints.SetMirrors(installationParams.EndpointMirrors)
+	}
+
 	supportbundle.StartServer()
 
 	if err := informers.Start(); err != nil {
@@ -66,6 +115,30 @@ func Start() {
 		log.Println("Failed to start snapshot scheduler", err)
 	}
 
+	if err := restoredrill.Start(); err != nil {
+		log.Println("Failed to start restore drill scheduler", err)
+	}
+
+	if err := canary.Start(); err != nil {
+		log.Println("Failed to start canary deploy loop", err)
+	}
+
+	if err := remoteinstall.Start(); err != nil {
+		log.Println("Failed to start remote install refresh loop", err)
+	}
+
+	if err := gitopsstatus.Start(); err != nil {
+		log.Println("Failed to start gitops status loop", err)
+	}
+
+	if err := janitor.Start(); err != nil {
+		log.Println("Failed to start janitor", err)
+	}
+
+	if err := archiveintegrity.Start(); err != nil {
+		log.Println("Failed to start archive scrubber", err)
+	}
+
 	waitForAirgap, err := automation.NeedToWaitForAirgapApp()
 	if err != nil {
 		log.Println("Failed to check if airgap install is in progress", err)
@@ -75,8 +148,14 @@ func Start() {
 		}
 	}
 
+	if err := kotsappcontroller.Start(handlers.SetConfigValues); err != nil {
+		log.Println("Failed to start kotsapp controller", err)
+	}
+
 	r := mux.NewRouter()
 
+	r.Use(handlers.RequestLoggingMiddleware)
+	r.Use(handlers.TracingMiddleware)
 	r.Use(handlers.CorsMiddleware)
 	r.Methods("OPTIONS").HandlerFunc(handlers.CORS)
 
@@ -89,8 +168,11 @@ func Start() {
 	r.HandleFunc("/healthz", handler.Healthz)
 	r.HandleFunc("/api/v1/login", handler.Login)
 	r.HandleFunc("/api/v1/login/info", handler.GetLoginInfo)
+	r.Path("/api/v1/login/ldap").Methods("POST").HandlerFunc(handler.LDAPLogin)
+	r.Path("/api/v1/login/temporary-access").Methods("POST").HandlerFunc(handler.RedeemTemporaryAccess)
 	r.HandleFunc("/api/v1/logout", handler.Logout) // this route uses its own auth
 	r.Path("/api/v1/metadata").Methods("GET").HandlerFunc(handler.Metadata)
+	r.Path("/api/v1/assets/{cacheKey}").Methods("GET").HandlerFunc(handler.GetCachedAsset)
 
 	r.HandleFunc("/api/v1/oidc/login", handler.OIDCLogin)
 	r.HandleFunc("/api/v1/oidc/login/callback", handler.OIDCLoginCallback)
@@ -100,6 +182,8 @@ func Start() {
 
 	// This the handler for license API and should be called by the application only.
 	r.Path("/license/v1/license").Methods("GET").HandlerFunc(handler.GetPlatformLicenseCompatibility)
+	// The application reports metered entitlement usage here, authenticated with its license id.
+	r.Path("/license/v1/usage").Methods("POST").HandlerFunc(handler.ReportEntitlementUsage)
 
 	/**********************************************************************
 	* Cluster auth routes (functions that the operator calls)
@@ -107,6 +191,7 @@ func Start() {
 
 	r.Path("/api/v1/appstatus").Methods("PUT").HandlerFunc(handler.SetAppStatus)
 	r.Path("/api/v1/deploy/result").Methods("PUT").HandlerFunc(handler.UpdateDeployResult)
+	r.Path("/api/v1/activate/result").Methods("PUT").HandlerFunc(handler.UpdateActivateResult)
 	r.Path("/api/v1/undeploy/result").Methods("PUT").HandlerFunc(handler.UpdateUndeployResult)
 	r.Handle("/socket.io/", socketservice.Start())
 
@@ -205,3 +290,19 @@ func generateKotsadmID() error {
 
 	return err
 }
+
+// applyLogSettings applies the log level and sink saved in the admin console. The --debug flag is used if no level
+// was saved.
+func applyLogSettings() error {
+	settings, err := store.GetStore().GetLogSettings()
+	if err != nil {
+		return errors.Wrap(err, "failed to get log settings")
+	}
+	if settings == nil {
+		return nil
+	}
+	if err := logger.Configure(*settings); err != nil {
+		return errors.Wrap(err, "failed to configure logger")
+	}
+	return nil
+}
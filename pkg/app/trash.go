@@ -0,0 +1,33 @@
+package app
+
+import (
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/replicatedhq/kots/pkg/app/types"
+)
+
+const (
+	// DefaultTrashRetentionDays is how long removed apps are kept if APP_TRASH_RETENTION_DAYS is not set
+	DefaultTrashRetentionDays = 7
+)
+
+// TrashRetention returns how long a removed app is kept, with its versions and archives, before it is removed
+// permanently
+func TrashRetention() time.Duration {
+	days, err := strconv.Atoi(os.Getenv("APP_TRASH_RETENTION_DAYS"))
+	if err != nil || days < 0 {
+		days = DefaultTrashRetentionDays
+	}
+	return time.Duration(days) * 24 * time.Hour
+}
+
+// PurgeAt returns when the removed app is removed permanently, or nil if the app is not removed
+func PurgeAt(a *types.App) *time.Time {
+	if a.RemovedAt == nil {
+		return nil
+	}
+	purgeAt := a.RemovedAt.Add(TrashRetention())
+	return &purgeAt
+}
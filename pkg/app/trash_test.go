@@ -0,0 +1,32 @@
+package app
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/replicatedhq/kots/pkg/app/types"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_PurgeAt(t *testing.T) {
+	req := require.New(t)
+
+	defer os.Unsetenv("APP_TRASH_RETENTION_DAYS")
+
+	req.Nil(PurgeAt(&types.App{}))
+
+	removedAt := time.Date(2021, 6, 1, 12, 0, 0, 0, time.UTC)
+	a := &types.App{RemovedAt: &removedAt}
+
+	req.Equal(removedAt.AddDate(0, 0, DefaultTrashRetentionDays), *PurgeAt(a))
+
+	os.Setenv("APP_TRASH_RETENTION_DAYS", "30")
+	req.Equal(removedAt.AddDate(0, 0, 30), *PurgeAt(a))
+
+	os.Setenv("APP_TRASH_RETENTION_DAYS", "0")
+	req.Equal(removedAt, *PurgeAt(a))
+
+	os.Setenv("APP_TRASH_RETENTION_DAYS", "invalid")
+	req.Equal(removedAt.AddDate(0, 0, DefaultTrashRetentionDays), *PurgeAt(a))
+}
@@ -1,6 +1,12 @@
 package types
 
-import "time"
+import (
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
 
 type UndeployStatus string
 
@@ -11,25 +17,138 @@ const (
 	UndeployReset     UndeployStatus = ""
 )
 
+type DeployPolicy string
+
+const (
+	// DeployPolicyLatest deploys only the newest of the downloaded updates
+	DeployPolicyLatest DeployPolicy = "latest"
+	// DeployPolicySequential deploys every downloaded update in order, waiting for the app to be ready between them
+	DeployPolicySequential DeployPolicy = "sequential"
+)
+
+// ApplyPolicy configures how the operator applies the manifests of an app. Zero values use the operator's defaults.
+type ApplyPolicy struct {
+	// Retries is the number of times a failed apply is retried
+	Retries int `json:"retries"`
+	// RetryBackoffSeconds is the wait before the first retry, it doubles after each retry
+	RetryBackoffSeconds int `json:"retryBackoffSeconds"`
+	// KubectlTimeoutSeconds is the timeout of each request kubectl makes to the api server
+	KubectlTimeoutSeconds int `json:"kubectlTimeoutSeconds"`
+	// WaitForResources waits for deleted resources to be gone and for CRDs to be established before the next
+	// phase of the deploy
+	WaitForResources bool `json:"waitForResources"`
+	// PhaseWaitSeconds is the wait after the CRDs and namespaces are applied, or the maximum wait for the CRDs to
+	// be established if WaitForResources is set
+	PhaseWaitSeconds int `json:"phaseWaitSeconds"`
+	// AdoptExistingResources labels resources with the names of the app's resources that already exist but were
+	// not applied for the app, such as the resources of a manual install, so that they are managed with the app.
+	// Resources of other apps are never adopted.
+	AdoptExistingResources bool `json:"adoptExistingResources"`
+	// ReapplyDeletedCriticalResources has the operator apply the resources annotated with kots.io/critical: "true"
+	// again as soon as they are deleted out of band between deploys
+	ReapplyDeletedCriticalResources bool `json:"reapplyDeletedCriticalResources"`
+}
+
+const (
+	maxApplyRetries       = 10
+	maxApplyPolicySeconds = 60 * 60
+)
+
+func (p ApplyPolicy) Validate() error {
+	if p.Retries < 0 || p.Retries > maxApplyRetries {
+		return errors.Errorf("retries must be between 0 and %d", maxApplyRetries)
+	}
+	if err := validateApplyPolicySeconds("retry backoff", p.RetryBackoffSeconds); err != nil {
+		return err
+	}
+	if err := validateApplyPolicySeconds("kubectl timeout", p.KubectlTimeoutSeconds); err != nil {
+		return err
+	}
+	if err := validateApplyPolicySeconds("phase wait", p.PhaseWaitSeconds); err != nil {
+		return err
+	}
+	return nil
+}
+
+func validateApplyPolicySeconds(name string, value int) error {
+	if value < 0 || value > maxApplyPolicySeconds {
+		return errors.Errorf("%s must be between 0 and %d seconds", name, maxApplyPolicySeconds)
+	}
+	return nil
+}
+
+// CanaryPolicy deploys new versions of an app to a single downstream first. The version is promoted to the other
+// downstreams once it has baked on the canary downstream, or the canary downstream is rolled back if the version
+// fails to deploy or fails its post deploy tests on the canary downstream.
+type CanaryPolicy struct {
+	// ClusterID is the id of the canary downstream, canary deploys are disabled if it's empty
+	ClusterID string `json:"clusterId"`
+	// BakeTimeSeconds is how long the version runs on the canary downstream before it is promoted
+	BakeTimeSeconds int `json:"bakeTimeSeconds"`
+}
+
+const (
+	maxCanaryBakeTimeSeconds = 7 * 24 * 60 * 60
+)
+
+func (p CanaryPolicy) Enabled() bool {
+	return p.ClusterID != ""
+}
+
+func (p CanaryPolicy) Validate() error {
+	if p.BakeTimeSeconds < 0 || p.BakeTimeSeconds > maxCanaryBakeTimeSeconds {
+		return errors.Errorf("bake time must be between 0 and %d seconds", maxCanaryBakeTimeSeconds)
+	}
+	return nil
+}
+
 type App struct {
-	ID                    string         `json:"id"`
-	Slug                  string         `json:"slug"`
-	Name                  string         `json:"name"`
-	License               string         `json:"license"`
-	IsAirgap              bool           `json:"isAirgap"`
-	CurrentSequence       int64          `json:"currentSequence"`
-	UpstreamURI           string         `json:"upstreamUri"`
-	IconURI               string         `json:"iconUri"`
-	UpdatedAt             *time.Time     `json:"createdAt"`
-	CreatedAt             time.Time      `json:"updatedAt"`
-	LastUpdateCheckAt     string         `json:"lastUpdateCheckAt"`
-	HasPreflight          bool           `json:"hasPreflight"`
-	IsConfigurable        bool           `json:"isConfigurable"`
-	SnapshotTTL           string         `json:"snapshotTtl"`
-	SnapshotSchedule      string         `json:"snapshotSchedule"`
-	RestoreInProgressName string         `json:"restoreInProgressName"`
-	RestoreUndeployStatus UndeployStatus `json:"restoreUndeloyStatus"`
-	UpdateCheckerSpec     string         `json:"updateCheckerSpec"`
-	IsGitOps              bool           `json:"isGitOps"`
-	InstallState          string         `json:"installState"`
+	ID                      string         `json:"id"`
+	Slug                    string         `json:"slug"`
+	Name                    string         `json:"name"`
+	License                 string         `json:"license"`
+	IsAirgap                bool           `json:"isAirgap"`
+	CurrentSequence         int64          `json:"currentSequence"`
+	UpstreamURI             string         `json:"upstreamUri"`
+	IconURI                 string         `json:"iconUri"`
+	UpdatedAt               *time.Time     `json:"createdAt"`
+	CreatedAt               time.Time      `json:"updatedAt"`
+	LastUpdateCheckAt       string         `json:"lastUpdateCheckAt"`
+	HasPreflight            bool           `json:"hasPreflight"`
+	IsConfigurable          bool           `json:"isConfigurable"`
+	SnapshotTTL             string         `json:"snapshotTtl"`
+	SnapshotSchedule        string         `json:"snapshotSchedule"`
+	RestoreDrillSchedule    string         `json:"restoreDrillSchedule"`
+	ScheduleTimezone        string         `json:"scheduleTimezone"`
+	RestoreInProgressName   string         `json:"restoreInProgressName"`
+	RestoreUndeployStatus   UndeployStatus `json:"restoreUndeloyStatus"`
+	UpdateCheckerSpec       string         `json:"updateCheckerSpec"`
+	DeployPolicy            DeployPolicy   `json:"deployPolicy"`
+	AdmissionDryRun         bool           `json:"admissionDryRun"`
+	ImagePushBandwidthLimit int64          `json:"imagePushBandwidthLimit"`
+	IsArchived              bool           `json:"isArchived"`
+	IsProtected             bool           `json:"isProtected"`
+	RemovedAt               *time.Time     `json:"removedAt,omitempty"`
+	RequireDeployApproval   bool           `json:"requireDeployApproval"`
+	ApplyPolicy             ApplyPolicy    `json:"applyPolicy"`
+	CanaryPolicy            CanaryPolicy   `json:"canaryPolicy"`
+	IsGitOps                bool           `json:"isGitOps"`
+	InstallState            string         `json:"installState"`
+	Namespace               string         `json:"namespace,omitempty"`
+	// Labels organize the apps of multi-app installs, e.g. environment=production or team=payments
+	Labels map[string]string `json:"labels,omitempty"`
+}
+
+// ValidateLabels returns an error if a key or value is not a valid kubernetes label key or value, so that apps
+// can be filtered with label selectors
+func ValidateLabels(labels map[string]string) error {
+	for key, value := range labels {
+		if errs := validation.IsQualifiedName(key); len(errs) > 0 {
+			return errors.Errorf("invalid label key %q: %s", key, strings.Join(errs, "; "))
+		}
+		if errs := validation.IsValidLabelValue(value); len(errs) > 0 {
+			return errors.Errorf("invalid value of label %q: %s", key, strings.Join(errs, "; "))
+		}
+	}
+	return nil
 }
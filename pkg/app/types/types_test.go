@@ -0,0 +1,44 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ValidateLabels(t *testing.T) {
+	tests := []struct {
+		name    string
+		labels  map[string]string
+		wantErr bool
+	}{
+		{
+			name:   "no labels",
+			labels: nil,
+		},
+		{
+			name:   "valid labels",
+			labels: map[string]string{"environment": "production", "example.com/team": "payments", "canary": ""},
+		},
+		{
+			name:    "invalid key",
+			labels:  map[string]string{"team name": "payments"},
+			wantErr: true,
+		},
+		{
+			name:    "invalid value",
+			labels:  map[string]string{"team": "payments/billing"},
+			wantErr: true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := ValidateLabels(test.labels)
+			if test.wantErr {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,195 @@
+package appclone
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/replicatedhq/kots/pkg/app"
+	apptypes "github.com/replicatedhq/kots/pkg/app/types"
+	snapshot "github.com/replicatedhq/kots/pkg/kotsadmsnapshot"
+	"github.com/replicatedhq/kots/pkg/logger"
+	"github.com/replicatedhq/kots/pkg/store"
+	"github.com/replicatedhq/kots/pkg/updatechecker"
+	"github.com/replicatedhq/kots/pkg/version"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+type CloneOptions struct {
+	// Name is the name of the new app
+	Name string
+	// Namespace is the namespace that the new app is deployed to
+	Namespace string
+	// Sequence is the version of the app to clone, the deployed version is cloned if nil
+	Sequence *int64
+}
+
+// Validate returns an error if the app cannot be cloned with the options
+func Validate(a *apptypes.App, opts CloneOptions) error {
+	if strings.TrimSpace(opts.Name) == "" {
+		return errors.New("name is required")
+	}
+	if errs := validation.IsDNS1123Label(opts.Namespace); len(errs) > 0 {
+		return errors.Errorf("invalid namespace %q: %s", opts.Namespace, strings.Join(errs, ", "))
+	}
+	if opts.Namespace == appNamespace(a) {
+		return errors.Errorf("app %s is already deployed to namespace %s", a.Slug, opts.Namespace)
+	}
+	if a.CurrentSequence == -1 {
+		return errors.Errorf("app %s has no versions", a.Slug)
+	}
+	return nil
+}
+
+// Clone creates a new app with the upstream, license, registry settings and config values of the app, deployed
+// to another namespace. The new app starts with a copy of the deployed version of the app, or the latest version
+// if none is deployed, and the config must be confirmed before it can be deployed.
+func Clone(a *apptypes.App, opts CloneOptions) (*apptypes.App, error) {
+	if err := Validate(a, opts); err != nil {
+		return nil, err
+	}
+
+	logger.Infof("cloning app %s to namespace %s", a.Slug, opts.Namespace)
+
+	sequence, err := sequenceToClone(a)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get sequence to clone")
+	}
+	if opts.Sequence != nil {
+		sequence = *opts.Sequence
+	}
+
+	registrySettings, err := store.GetStore().GetRegistryDetailsForApp(a.ID)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get registry settings")
+	}
+
+	archiveDir, err := ioutil.TempDir("", "kotsadm")
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create temp dir")
+	}
+	defer os.RemoveAll(archiveDir)
+
+	if err := store.GetStore().GetAppVersionArchive(a.ID, sequence, archiveDir); err != nil {
+		return nil, errors.Wrap(err, "failed to get app version archive")
+	}
+
+	clone, err := store.GetStore().CreateApp(opts.Name, a.UpstreamURI, a.License, a.IsAirgap, false, registrySettings.IsReadOnly)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create app")
+	}
+
+	if err := store.GetStore().AddAppToAllDownstreams(clone.ID); err != nil {
+		return nil, errors.Wrap(err, "failed to add app to all downstreams")
+	}
+	if err := store.GetStore().SetAppIsAirgap(clone.ID, a.IsAirgap); err != nil {
+		return nil, errors.Wrap(err, "failed to set app is airgap")
+	}
+	if err := store.GetStore().SetAppNamespace(clone.ID, opts.Namespace); err != nil {
+		return nil, errors.Wrap(err, "failed to set app namespace")
+	}
+
+	if registrySettings.Hostname != "" {
+		r := registrySettings
+		if err := store.GetStore().UpdateRegistry(clone.ID, r.Hostname, r.Username, r.Password, r.Namespace, r.IsReadOnly); err != nil {
+			return nil, errors.Wrap(err, "failed to update registry")
+		}
+	}
+
+	// the archive holds the config values. this is the first version of the new app, so it requires
+	// the config to be confirmed before it can be deployed.
+	if _, err := store.GetStore().CreateAppVersion(clone.ID, nil, archiveDir, "Clone", false, &version.DownstreamGitOps{}); err != nil {
+		return nil, errors.Wrap(err, "failed to create app version")
+	}
+
+	if a.UpdateCheckerSpec != "" {
+		if err := store.GetStore().SetUpdateCheckerSpec(clone.ID, a.UpdateCheckerSpec); err != nil {
+			return nil, errors.Wrap(err, "failed to set update checker spec")
+		}
+	}
+	if err := store.GetStore().SetDeployPolicy(clone.ID, a.DeployPolicy); err != nil {
+		return nil, errors.Wrap(err, "failed to set deploy policy")
+	}
+
+	if err := store.GetStore().SetAppInstallState(clone.ID, "installed"); err != nil {
+		return nil, errors.Wrap(err, "failed to set app install state")
+	}
+
+	if err := updatechecker.Configure(clone.ID); err != nil {
+		return nil, errors.Wrap(err, "failed to configure update checker")
+	}
+
+	return store.GetStore().GetApp(clone.ID)
+}
+
+// CloneFromSnapshot clones the version of the app that is in the snapshot and restores the resources and volumes of
+// the app from the snapshot into the namespace of the clone. The clone is deployed once the restore completes, e.g.
+// to test a restore alongside production or to migrate the app to a new namespace.
+func CloneFromSnapshot(ctx context.Context, a *apptypes.App, snapshotName string, opts CloneOptions) (*apptypes.App, error) {
+	kotsadmNamespace := os.Getenv("POD_NAMESPACE")
+
+	backup, err := snapshot.GetBackup(ctx, kotsadmNamespace, snapshotName)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get backup")
+	}
+	sequence, ok, err := snapshot.BackedUpSequence(backup.Annotations, a.ID, a.Slug)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get backed up sequence")
+	}
+	if !ok {
+		return nil, errors.Errorf("snapshot %s does not include app %s", snapshotName, a.Slug)
+	}
+	opts.Sequence = &sequence
+
+	clone, err := Clone(a, opts)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to clone app")
+	}
+
+	restoreOpts := snapshot.CloneRestoreOptions{
+		SourceAppSlug:   a.Slug,
+		SourceNamespace: appNamespace(a),
+		Sequence:        clone.CurrentSequence,
+	}
+	if err := snapshot.CreateCloneRestore(ctx, kotsadmNamespace, snapshotName, clone, restoreOpts); err != nil {
+		return nil, errors.Wrap(err, "failed to create restore")
+	}
+
+	// there is nothing to undeploy in the namespace of the clone, the restore loop waits for the restore to complete
+	if err := app.InitiateRestore(snapshotName, clone.ID); err != nil {
+		return nil, errors.Wrap(err, "failed to initiate restore")
+	}
+	if err := app.SetRestoreUndeployStatus(clone.ID, apptypes.UndeployCompleted); err != nil {
+		return nil, errors.Wrap(err, "failed to set restore undeploy status")
+	}
+
+	return clone, nil
+}
+
+// sequenceToClone returns the deployed sequence of the app, or the latest sequence if none is deployed
+func sequenceToClone(a *apptypes.App) (int64, error) {
+	downstreams, err := store.GetStore().ListDownstreamsForApp(a.ID)
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to list downstreams")
+	}
+	if len(downstreams) > 0 {
+		currentVersion, err := store.GetStore().GetCurrentVersion(a.ID, downstreams[0].ClusterID)
+		if err != nil {
+			return 0, errors.Wrap(err, "failed to get current version")
+		}
+		if currentVersion != nil {
+			return currentVersion.ParentSequence, nil
+		}
+	}
+	return a.CurrentSequence, nil
+}
+
+// appNamespace returns the namespace that the app is deployed to
+func appNamespace(a *apptypes.App) string {
+	if a.Namespace != "" {
+		return a.Namespace
+	}
+	return os.Getenv("POD_NAMESPACE")
+}
@@ -0,0 +1,101 @@
+package applock
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/replicatedhq/kots/pkg/logger"
+	"github.com/replicatedhq/kots/pkg/store"
+	"github.com/segmentio/ksuid"
+)
+
+// The lock of an app is held while a new version of the app is rendered and created so that uploads, update
+// downloads and config changes, possibly on other kotsadm replicas, can't interleave.
+const (
+	// lockTTL is how long the lock is held after the last refresh, it expires if the holder crashed
+	lockTTL         = 2 * time.Minute
+	refreshInterval = 30 * time.Second
+	pollInterval    = time.Second
+
+	// RetryAfter is how long the caller that could not take the lock should wait before retrying
+	RetryAfter = 10 * time.Second
+
+	// WaitTimeout is how long background operations wait for the lock before they give up
+	WaitTimeout = 30 * time.Minute
+)
+
+// LockedError is returned when another operation is creating a version of the app
+type LockedError struct {
+	AppID string
+}
+
+func (e LockedError) Error() string {
+	return fmt.Sprintf("another operation is creating a version of app %s", e.AppID)
+}
+
+// IsLocked returns true if the error is, or wraps, a LockedError
+func IsLocked(err error) bool {
+	_, ok := errors.Cause(err).(LockedError)
+	return ok
+}
+
+// TryLock takes the lock of the app, or returns a LockedError if another operation holds it.
+// The returned function releases the lock.
+func TryLock(appID string) (func(), error) {
+	return tryLock(store.GetStore(), appID)
+}
+
+func tryLock(lockStore store.AppLockStore, appID string) (func(), error) {
+	holder := ksuid.New().String()
+
+	acquired, err := lockStore.AcquireAppLock(appID, holder, time.Now().Add(lockTTL))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to acquire app lock")
+	}
+	if !acquired {
+		return nil, LockedError{AppID: appID}
+	}
+
+	stopCh := make(chan struct{})
+	go refresh(lockStore, appID, holder, stopCh)
+
+	return func() {
+		close(stopCh)
+		if err := lockStore.ReleaseAppLock(appID, holder); err != nil {
+			logger.Error(errors.Wrapf(err, "failed to release lock of app %s", appID))
+		}
+	}, nil
+}
+
+// Lock takes the lock of the app, waiting up to timeout for another operation to release it.
+// The returned function releases the lock.
+func Lock(appID string, timeout time.Duration) (func(), error) {
+	deadline := time.Now().Add(timeout)
+	for {
+		unlock, err := TryLock(appID)
+		if err == nil {
+			return unlock, nil
+		}
+		if !IsLocked(err) || time.Now().After(deadline) {
+			return nil, err
+		}
+		time.Sleep(pollInterval)
+	}
+}
+
+func refresh(lockStore store.AppLockStore, appID string, holder string, stopCh chan struct{}) {
+	ticker := time.NewTicker(refreshInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stopCh:
+			return
+		case <-ticker.C:
+			if err := lockStore.RefreshAppLock(appID, holder, time.Now().Add(lockTTL)); err != nil {
+				logger.Error(errors.Wrapf(err, "failed to refresh lock of app %s", appID))
+			}
+		}
+	}
+}
@@ -0,0 +1,111 @@
+package applock
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+)
+
+// memoryLockStore holds the locks as the app_version_lock table does, now is the time that expired locks are
+// compared to
+type memoryLockStore struct {
+	mtx   sync.Mutex
+	now   time.Time
+	locks map[string]memoryLock
+}
+
+type memoryLock struct {
+	holder    string
+	expiresAt time.Time
+}
+
+func newMemoryLockStore() *memoryLockStore {
+	return &memoryLockStore{now: time.Now(), locks: map[string]memoryLock{}}
+}
+
+func (s *memoryLockStore) AcquireAppLock(appID string, holder string, expiresAt time.Time) (bool, error) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	if lock, ok := s.locks[appID]; ok && !lock.expiresAt.Before(s.now) {
+		return false, nil
+	}
+	s.locks[appID] = memoryLock{holder: holder, expiresAt: expiresAt}
+	return true, nil
+}
+
+func (s *memoryLockStore) RefreshAppLock(appID string, holder string, expiresAt time.Time) error {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	if lock, ok := s.locks[appID]; !ok || lock.holder != holder {
+		return errors.New("lock is no longer held")
+	}
+	s.locks[appID] = memoryLock{holder: holder, expiresAt: expiresAt}
+	return nil
+}
+
+func (s *memoryLockStore) ReleaseAppLock(appID string, holder string) error {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	if lock, ok := s.locks[appID]; ok && lock.holder == holder {
+		delete(s.locks, appID)
+	}
+	return nil
+}
+
+func Test_tryLockContention(t *testing.T) {
+	req := require.New(t)
+	lockStore := newMemoryLockStore()
+
+	unlock, err := tryLock(lockStore, "app-1")
+	req.NoError(err)
+
+	_, err = tryLock(lockStore, "app-1")
+	req.True(IsLocked(err))
+	req.Equal(LockedError{AppID: "app-1"}, err)
+
+	// the locks of other apps are independent
+	unlockOther, err := tryLock(lockStore, "app-2")
+	req.NoError(err)
+	unlockOther()
+
+	unlock()
+
+	unlock, err = tryLock(lockStore, "app-1")
+	req.NoError(err)
+	unlock()
+}
+
+func Test_tryLockExpiry(t *testing.T) {
+	req := require.New(t)
+	lockStore := newMemoryLockStore()
+
+	// the holder crashed without releasing the lock
+	_, err := tryLock(lockStore, "app-1")
+	req.NoError(err)
+
+	lockStore.now = time.Now().Add(lockTTL - time.Minute)
+	_, err = tryLock(lockStore, "app-1")
+	req.True(IsLocked(err))
+
+	lockStore.now = time.Now().Add(lockTTL + time.Minute)
+	unlock, err := tryLock(lockStore, "app-1")
+	req.NoError(err)
+	unlock()
+
+	req.Empty(lockStore.locks)
+}
+
+func Test_IsLocked(t *testing.T) {
+	req := require.New(t)
+
+	req.True(IsLocked(LockedError{AppID: "app-1"}))
+	req.True(IsLocked(errors.Wrap(LockedError{AppID: "app-1"}, "failed to update config")))
+	req.False(IsLocked(errors.New("failed to acquire app lock")))
+	req.False(IsLocked(nil))
+}
@@ -0,0 +1,326 @@
+package appusage
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/pkg/errors"
+	apptypes "github.com/replicatedhq/kots/pkg/app/types"
+	"github.com/replicatedhq/kots/pkg/appusage/types"
+	"github.com/replicatedhq/kots/pkg/k8sutil"
+	"github.com/replicatedhq/kots/pkg/kotsutil"
+	"github.com/replicatedhq/kots/pkg/logger"
+	"github.com/replicatedhq/kots/pkg/store"
+	"github.com/replicatedhq/kots/pkg/version"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/kubernetes"
+	statsv1alpha1 "k8s.io/kubelet/pkg/apis/stats/v1alpha1"
+)
+
+const appSlugLabel = "kots.io/app-slug"
+
+// podMetricsList is the subset of the metrics.k8s.io PodMetricsList that is used
+type podMetricsList struct {
+	Items []struct {
+		Metadata struct {
+			Name      string `json:"name"`
+			Namespace string `json:"namespace"`
+		} `json:"metadata"`
+		Containers []struct {
+			Usage corev1.ResourceList `json:"usage"`
+		} `json:"containers"`
+	} `json:"items"`
+}
+
+// volumeStats is the used and total bytes of a pvc as reported by the kubelet
+type volumeStats struct {
+	usedBytes     int64
+	capacityBytes int64
+}
+
+// GetReport returns the requests, limits and usage of the pods of the deployed version of the app and the storage
+// of their volumes. Pods are found by the app slug label that kots adds to the resources of the app. Usage that
+// metrics-server or the kubelets can't report is left empty.
+func GetReport(a *apptypes.App) (*types.Report, error) {
+	clientset, err := k8sutil.GetClientset()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get clientset")
+	}
+
+	namespaces, err := appNamespaces(a)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get app namespaces")
+	}
+
+	selector := labels.SelectorFromSet(map[string]string{appSlugLabel: a.Slug}).String()
+
+	pods := []corev1.Pod{}
+	pvcs := []corev1.PersistentVolumeClaim{}
+	podMetrics := map[string]corev1.ResourceList{}
+	metricsErrors := []string{}
+	for _, namespace := range namespaces {
+		podList, err := clientset.CoreV1().Pods(namespace).List(context.TODO(), metav1.ListOptions{LabelSelector: selector})
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to list pods in namespace %s", namespace)
+		}
+		pods = append(pods, podList.Items...)
+
+		// volume claim templates of statefulsets are not labeled, their claims are found through the pods
+		pvcList, err := clientset.CoreV1().PersistentVolumeClaims(namespace).List(context.TODO(), metav1.ListOptions{})
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to list pvcs in namespace %s", namespace)
+		}
+		pvcs = append(pvcs, pvcList.Items...)
+
+		if err := getPodMetrics(clientset, namespace, selector, podMetrics); err != nil {
+			metricsErrors = append(metricsErrors, err.Error())
+		}
+	}
+
+	stats, statsErr := getVolumeStats(clientset, pods)
+
+	report := aggregate(pods, pvcs, podMetrics, stats)
+	report.AppSlug = a.Slug
+	report.GeneratedAt = time.Now()
+	report.MetricsAvailable = len(metricsErrors) == 0
+	if len(metricsErrors) > 0 {
+		report.MetricsError = metricsErrors[0]
+	}
+	report.VolumeStatsAvailable = statsErr == nil
+	if statsErr != nil {
+		logger.Debugf("failed to get volume stats: %v", statsErr)
+	}
+
+	return report, nil
+}
+
+// appNamespaces returns the namespace of the app and the additional namespaces of the deployed version. An
+// additional namespace of "*" is all namespaces.
+func appNamespaces(a *apptypes.App) ([]string, error) {
+	namespace := a.Namespace
+	if namespace == "" {
+		namespace = os.Getenv("POD_NAMESPACE")
+	}
+	namespaces := []string{namespace}
+
+	downstreams, err := store.GetStore().ListDownstreamsForApp(a.ID)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list downstreams")
+	}
+	if len(downstreams) == 0 {
+		return namespaces, nil
+	}
+
+	sequence, err := store.GetStore().GetCurrentSequence(a.ID, downstreams[0].ClusterID)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get current sequence")
+	}
+	if sequence == -1 {
+		return namespaces, nil
+	}
+
+	kotsKinds, err := version.GetKotsKinds(a.ID, sequence)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get kots kinds")
+	}
+
+	for _, additional := range kotsutil.AdditionalNamespaces(&kotsKinds.KotsApplication) {
+		if additional == "*" {
+			return []string{metav1.NamespaceAll}, nil
+		}
+		found := false
+		for _, n := range namespaces {
+			if n == additional {
+				found = true
+				break
+			}
+		}
+		if !found {
+			namespaces = append(namespaces, additional)
+		}
+	}
+
+	return namespaces, nil
+}
+
+func getPodMetrics(clientset kubernetes.Interface, namespace string, selector string, podMetrics map[string]corev1.ResourceList) error {
+	path := "/apis/metrics.k8s.io/v1beta1/pods"
+	if namespace != metav1.NamespaceAll {
+		path = "/apis/metrics.k8s.io/v1beta1/namespaces/" + namespace + "/pods"
+	}
+
+	b, err := clientset.CoreV1().RESTClient().Get().AbsPath(path).Param("labelSelector", selector).DoRaw(context.TODO())
+	if err != nil {
+		return errors.Wrap(err, "failed to get pod metrics, is metrics-server installed?")
+	}
+
+	list := podMetricsList{}
+	if err := json.Unmarshal(b, &list); err != nil {
+		return errors.Wrap(err, "failed to unmarshal pod metrics")
+	}
+
+	for _, item := range list.Items {
+		usage := corev1.ResourceList{}
+		for _, container := range item.Containers {
+			addResources(usage, container.Usage)
+		}
+		podMetrics[resourceKey(item.Metadata.Namespace, item.Metadata.Name)] = usage
+	}
+
+	return nil
+}
+
+// getVolumeStats reads the used bytes of the pvcs from the kubelets of the nodes that run the pods
+func getVolumeStats(clientset kubernetes.Interface, pods []corev1.Pod) (map[string]volumeStats, error) {
+	nodes := map[string]bool{}
+	for _, pod := range pods {
+		if pod.Spec.NodeName != "" {
+			nodes[pod.Spec.NodeName] = true
+		}
+	}
+
+	stats := map[string]volumeStats{}
+	for node := range nodes {
+		b, err := clientset.CoreV1().RESTClient().Get().Resource("nodes").Name(node).SubResource("proxy").Suffix("stats/summary").DoRaw(context.TODO())
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to get stats summary of node %s", node)
+		}
+
+		summary := statsv1alpha1.Summary{}
+		if err := json.Unmarshal(b, &summary); err != nil {
+			return nil, errors.Wrapf(err, "failed to unmarshal stats summary of node %s", node)
+		}
+
+		for _, pod := range summary.Pods {
+			for _, volume := range pod.VolumeStats {
+				if volume.PVCRef == nil {
+					continue
+				}
+				s := volumeStats{}
+				if volume.UsedBytes != nil {
+					s.usedBytes = int64(*volume.UsedBytes)
+				}
+				if volume.CapacityBytes != nil {
+					s.capacityBytes = int64(*volume.CapacityBytes)
+				}
+				stats[resourceKey(volume.PVCRef.Namespace, volume.PVCRef.Name)] = s
+			}
+		}
+	}
+
+	return stats, nil
+}
+
+// aggregate builds the report from the pods of the app, the pvcs of their namespaces, the usage of the pods from
+// metrics-server and the stats of the volumes from the kubelets. Only the pvcs that are labeled with the app slug
+// or mounted by a pod of the app are reported.
+func aggregate(pods []corev1.Pod, pvcs []corev1.PersistentVolumeClaim, podMetrics map[string]corev1.ResourceList, stats map[string]volumeStats) *types.Report {
+	report := &types.Report{
+		Pods:    []types.PodUsage{},
+		Volumes: []types.PVCUsage{},
+	}
+
+	mountedPVCs := map[string]bool{}
+	for _, pod := range pods {
+		if pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed {
+			// completed pods don't hold resources
+			continue
+		}
+
+		requests := corev1.ResourceList{}
+		limits := corev1.ResourceList{}
+		for _, container := range pod.Spec.Containers {
+			addResources(requests, container.Resources.Requests)
+			addResources(limits, container.Resources.Limits)
+		}
+		usage := podMetrics[resourceKey(pod.Namespace, pod.Name)]
+
+		podUsage := types.PodUsage{
+			Namespace: pod.Namespace,
+			Name:      pod.Name,
+			Phase:     string(pod.Status.Phase),
+			NodeName:  pod.Spec.NodeName,
+			Usage: types.Usage{
+				CPURequestMillis:   requests.Cpu().MilliValue(),
+				CPULimitMillis:     limits.Cpu().MilliValue(),
+				CPUUsageMillis:     usage.Cpu().MilliValue(),
+				MemoryRequestBytes: requests.Memory().Value(),
+				MemoryLimitBytes:   limits.Memory().Value(),
+				MemoryUsageBytes:   usage.Memory().Value(),
+			},
+		}
+
+		for _, volume := range pod.Spec.Volumes {
+			if volume.PersistentVolumeClaim != nil {
+				mountedPVCs[resourceKey(pod.Namespace, volume.PersistentVolumeClaim.ClaimName)] = true
+			}
+		}
+
+		report.Pods = append(report.Pods, podUsage)
+
+		report.Total.CPURequestMillis += podUsage.CPURequestMillis
+		report.Total.CPULimitMillis += podUsage.CPULimitMillis
+		report.Total.CPUUsageMillis += podUsage.CPUUsageMillis
+		report.Total.MemoryRequestBytes += podUsage.MemoryRequestBytes
+		report.Total.MemoryLimitBytes += podUsage.MemoryLimitBytes
+		report.Total.MemoryUsageBytes += podUsage.MemoryUsageBytes
+	}
+
+	for _, pvc := range pvcs {
+		key := resourceKey(pvc.Namespace, pvc.Name)
+		if pvc.Labels[appSlugLabel] == "" && !mountedPVCs[key] {
+			continue
+		}
+
+		storageRequest := pvc.Spec.Resources.Requests[corev1.ResourceStorage]
+		capacity := pvc.Status.Capacity[corev1.ResourceStorage]
+		pvcUsage := types.PVCUsage{
+			Namespace:     pvc.Namespace,
+			Name:          pvc.Name,
+			RequestBytes:  storageRequest.Value(),
+			CapacityBytes: capacity.Value(),
+			UsedBytes:     stats[key].usedBytes,
+		}
+		if pvc.Spec.StorageClassName != nil {
+			pvcUsage.StorageClass = *pvc.Spec.StorageClassName
+		}
+		if pvcUsage.CapacityBytes == 0 {
+			pvcUsage.CapacityBytes = stats[key].capacityBytes
+		}
+
+		report.Volumes = append(report.Volumes, pvcUsage)
+
+		report.Total.StorageRequestBytes += pvcUsage.RequestBytes
+		report.Total.StorageUsedBytes += pvcUsage.UsedBytes
+	}
+
+	sort.Slice(report.Pods, func(i, j int) bool {
+		return resourceKey(report.Pods[i].Namespace, report.Pods[i].Name) < resourceKey(report.Pods[j].Namespace, report.Pods[j].Name)
+	})
+	sort.Slice(report.Volumes, func(i, j int) bool {
+		return resourceKey(report.Volumes[i].Namespace, report.Volumes[i].Name) < resourceKey(report.Volumes[j].Namespace, report.Volumes[j].Name)
+	})
+
+	return report
+}
+
+func addResources(total corev1.ResourceList, resources corev1.ResourceList) {
+	for name, quantity := range resources {
+		sum, ok := total[name]
+		if !ok {
+			sum = resource.Quantity{}
+		}
+		sum.Add(quantity)
+		total[name] = sum
+	}
+}
+
+func resourceKey(namespace string, name string) string {
+	return namespace + "/" + name
+}
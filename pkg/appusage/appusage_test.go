@@ -0,0 +1,110 @@
+package appusage
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func Test_aggregate(t *testing.T) {
+	req := require.New(t)
+
+	pods := []corev1.Pod{
+		{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "app", Name: "web-1"},
+			Spec: corev1.PodSpec{
+				NodeName: "node-1",
+				Containers: []corev1.Container{
+					{
+						Resources: corev1.ResourceRequirements{
+							Requests: corev1.ResourceList{
+								corev1.ResourceCPU:    resource.MustParse("250m"),
+								corev1.ResourceMemory: resource.MustParse("128Mi"),
+							},
+							Limits: corev1.ResourceList{
+								corev1.ResourceCPU: resource.MustParse("1"),
+							},
+						},
+					},
+					{
+						Resources: corev1.ResourceRequirements{
+							Requests: corev1.ResourceList{
+								corev1.ResourceCPU: resource.MustParse("100m"),
+							},
+						},
+					},
+				},
+				Volumes: []corev1.Volume{
+					{
+						Name: "data",
+						VolumeSource: corev1.VolumeSource{
+							PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: "data-web-1"},
+						},
+					},
+				},
+			},
+			Status: corev1.PodStatus{Phase: corev1.PodRunning},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "app", Name: "migrate"},
+			Spec: corev1.PodSpec{
+				Containers: []corev1.Container{
+					{
+						Resources: corev1.ResourceRequirements{
+							Requests: corev1.ResourceList{
+								corev1.ResourceCPU: resource.MustParse("2"),
+							},
+						},
+					},
+				},
+			},
+			Status: corev1.PodStatus{Phase: corev1.PodSucceeded},
+		},
+	}
+
+	pvcs := []corev1.PersistentVolumeClaim{
+		{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "app", Name: "data-web-1"},
+			Spec: corev1.PersistentVolumeClaimSpec{
+				Resources: corev1.ResourceRequirements{
+					Requests: corev1.ResourceList{
+						corev1.ResourceStorage: resource.MustParse("1Gi"),
+					},
+				},
+			},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "app", Name: "other-app-data"},
+		},
+	}
+
+	podMetrics := map[string]corev1.ResourceList{
+		"app/web-1": {
+			corev1.ResourceCPU:    resource.MustParse("50m"),
+			corev1.ResourceMemory: resource.MustParse("64Mi"),
+		},
+	}
+
+	stats := map[string]volumeStats{
+		"app/data-web-1": {usedBytes: 1024, capacityBytes: 2048},
+	}
+
+	report := aggregate(pods, pvcs, podMetrics, stats)
+
+	req.Len(report.Pods, 1)
+	req.Equal("web-1", report.Pods[0].Name)
+	req.Equal(int64(350), report.Total.CPURequestMillis)
+	req.Equal(int64(1000), report.Total.CPULimitMillis)
+	req.Equal(int64(50), report.Total.CPUUsageMillis)
+	req.Equal(int64(128*1024*1024), report.Total.MemoryRequestBytes)
+	req.Equal(int64(64*1024*1024), report.Total.MemoryUsageBytes)
+
+	req.Len(report.Volumes, 1)
+	req.Equal("data-web-1", report.Volumes[0].Name)
+	req.Equal(int64(1024*1024*1024), report.Volumes[0].RequestBytes)
+	req.Equal(int64(2048), report.Volumes[0].CapacityBytes)
+	req.Equal(int64(1024), report.Total.StorageUsedBytes)
+}
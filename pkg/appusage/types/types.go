@@ -0,0 +1,49 @@
+package types
+
+import (
+	"time"
+)
+
+// Report is the resource usage of the pods and volumes of an app, CPU is in millicores and memory and storage are
+// in bytes
+type Report struct {
+	AppSlug     string    `json:"appSlug"`
+	GeneratedAt time.Time `json:"generatedAt"`
+	// MetricsAvailable is false if the usage could not be read from metrics-server, the usage is 0 then
+	MetricsAvailable bool       `json:"metricsAvailable"`
+	MetricsError     string     `json:"metricsError,omitempty"`
+	Total            Usage      `json:"total"`
+	Pods             []PodUsage `json:"pods"`
+	// VolumeStatsAvailable is false if the used bytes of the volumes could not be read from the kubelets
+	VolumeStatsAvailable bool       `json:"volumeStatsAvailable"`
+	Volumes              []PVCUsage `json:"volumes"`
+}
+
+type Usage struct {
+	CPURequestMillis    int64 `json:"cpuRequestMillis"`
+	CPULimitMillis      int64 `json:"cpuLimitMillis"`
+	CPUUsageMillis      int64 `json:"cpuUsageMillis"`
+	MemoryRequestBytes  int64 `json:"memoryRequestBytes"`
+	MemoryLimitBytes    int64 `json:"memoryLimitBytes"`
+	MemoryUsageBytes    int64 `json:"memoryUsageBytes"`
+	StorageRequestBytes int64 `json:"storageRequestBytes"`
+	StorageUsedBytes    int64 `json:"storageUsedBytes"`
+}
+
+type PodUsage struct {
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+	Phase     string `json:"phase"`
+	NodeName  string `json:"nodeName"`
+	Usage
+}
+
+type PVCUsage struct {
+	Namespace    string `json:"namespace"`
+	Name         string `json:"name"`
+	StorageClass string `json:"storageClass"`
+	RequestBytes int64  `json:"requestBytes"`
+	// CapacityBytes is the capacity of the bound volume, it can be larger than the request
+	CapacityBytes int64 `json:"capacityBytes"`
+	UsedBytes     int64 `json:"usedBytes"`
+}
@@ -0,0 +1,139 @@
+package archiveintegrity
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/replicatedhq/kots/pkg/archiveintegrity/types"
+	"github.com/replicatedhq/kots/pkg/logger"
+	"github.com/replicatedhq/kots/pkg/objectstore"
+	"github.com/replicatedhq/kots/pkg/scheduledjob"
+	scheduledjobtypes "github.com/replicatedhq/kots/pkg/scheduledjob/types"
+	"github.com/replicatedhq/kots/pkg/store"
+	"go.uber.org/zap"
+)
+
+const (
+	interval = 6 * time.Hour
+
+	// verifyInterval is how often each archive is verified
+	verifyInterval = 7 * 24 * time.Hour
+
+	// batchSize is the maximum number of archives verified in a run, so that a run does not download every
+	// archive of an installation with a long version history at once
+	batchSize = 100
+)
+
+var (
+	lastRunAt    *time.Time
+	lastRunAtMtx sync.Mutex
+)
+
+// Start periodically verifies the app version archives against the checksums recorded when they were created, and
+// repairs the corrupt and missing archives that can be rendered again from their upstream
+func Start() error {
+	logger.Debug("starting archive scrubber")
+
+	go func() {
+		for {
+			// the error is logged by the run and recorded in the job history
+			_ = scheduledjob.Run(scheduledjobtypes.JobArchiveScrub, "", scrub)
+
+			now := time.Now()
+			lastRunAtMtx.Lock()
+			lastRunAt = &now
+			lastRunAtMtx.Unlock()
+
+			time.Sleep(interval)
+		}
+	}()
+
+	return nil
+}
+
+// NextRun returns when the scrubber runs next, or nil if it has not run yet
+func NextRun() *time.Time {
+	lastRunAtMtx.Lock()
+	defer lastRunAtMtx.Unlock()
+
+	if lastRunAt == nil {
+		return nil
+	}
+	next := lastRunAt.Add(interval)
+	return &next
+}
+
+func scrub() (string, error) {
+	checksums, err := store.GetStore().ListAppVersionArchiveChecksumsToVerify(time.Now().Add(-verifyInterval), batchSize)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to list archives to verify")
+	}
+
+	var runErr error
+	corrupt, repaired := 0, 0
+	for _, checksum := range checksums {
+		err := Verify(checksum.AppID, checksum.Sequence)
+		if err == nil {
+			continue
+		}
+		if !IsCorrupt(err) {
+			runErr = errors.Wrapf(err, "failed to verify archive of app %s sequence %d", checksum.AppID, checksum.Sequence)
+			logger.Error(runErr)
+			continue
+		}
+
+		corrupt++
+		logger.Error(err)
+
+		// archives that cannot be repaired, as for airgap apps, are verified and reported again in the next runs
+		if err := Repair(checksum.AppID, checksum.Sequence); err != nil {
+			logger.Error(errors.Wrapf(err, "failed to repair archive of app %s sequence %d", checksum.AppID, checksum.Sequence))
+			continue
+		}
+
+		repaired++
+		logger.Info("repaired app version archive",
+			zap.String("appID", checksum.AppID),
+			zap.Int64("sequence", checksum.Sequence))
+	}
+
+	return fmt.Sprintf("verified %d archives, %d corrupt, %d repaired", len(checksums), corrupt, repaired), runErr
+}
+
+// Verify reads the archive of the app version from the object store and checks it against its checksum. The
+// archive is recorded as corrupt if it does not match or is missing.
+func Verify(appID string, sequence int64) error {
+	r, err := store.GetStore().GetAppVersionArchiveReader(appID, sequence)
+	if err != nil {
+		if errors.Cause(err) == objectstore.ErrNotFound {
+			recordVerified(appID, sequence, err.Error())
+		}
+		return err
+	}
+	defer r.Close()
+
+	// the mismatch is recorded by the store when the archive has been read to the end
+	if _, err := io.Copy(ioutil.Discard, r); err != nil {
+		return err
+	}
+
+	recordVerified(appID, sequence, "")
+	return nil
+}
+
+// IsCorrupt returns true if the error returned by Verify is caused by a corrupt or missing archive, rather than by
+// a failure to reach the object store
+func IsCorrupt(err error) bool {
+	cause := errors.Cause(err)
+	return cause == types.ErrChecksumMismatch || cause == objectstore.ErrNotFound
+}
+
+func recordVerified(appID string, sequence int64, verifyError string) {
+	if err := store.GetStore().SetAppVersionArchiveVerified(appID, sequence, verifyError); err != nil {
+		logger.Error(errors.Wrap(err, "failed to record archive verification"))
+	}
+}
@@ -0,0 +1,45 @@
+package archiveintegrity
+
+import (
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/replicatedhq/kots/pkg/archiveintegrity/types"
+	"github.com/replicatedhq/kots/pkg/objectstore"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_IsCorrupt(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{
+			name: "checksum mismatch",
+			err:  errors.Wrap(types.ErrChecksumMismatch, "archive of app a sequence 1 has sha256 b, expected c"),
+			want: true,
+		},
+		{
+			name: "missing archive",
+			err:  errors.Wrap(errors.Wrapf(objectstore.ErrNotFound, "%q in bucket %q", "a/1.tar.gz", "kotsadm"), "failed to get app version archive"),
+			want: true,
+		},
+		{
+			name: "object store unreachable",
+			err:  errors.Wrap(errors.New("connection refused"), "failed to get app version archive"),
+			want: false,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			require.Equal(t, test.want, IsCorrupt(test.err))
+		})
+	}
+}
+
+func Test_distance(t *testing.T) {
+	require.Equal(t, int64(2), distance(3, 5))
+	require.Equal(t, int64(2), distance(5, 3))
+	require.Equal(t, int64(0), distance(4, 4))
+}
@@ -0,0 +1,144 @@
+package archiveintegrity
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/pkg/errors"
+	"github.com/replicatedhq/kots/pkg/logger"
+	kotspull "github.com/replicatedhq/kots/pkg/pull"
+	"github.com/replicatedhq/kots/pkg/reporting"
+	"github.com/replicatedhq/kots/pkg/store"
+	kotsupstream "github.com/replicatedhq/kots/pkg/upstream"
+)
+
+// Repair renders the archive of the app version again from its upstream release, pulled at the update cursor of
+// the version. The config values, identity config and installation are taken from the nearest version of the app
+// with an intact archive, since those of the version are in the corrupt archive. Airgap apps cannot be repaired,
+// their releases are only in the airgap bundles.
+func Repair(appID string, sequence int64) error {
+	a, err := store.GetStore().GetApp(appID)
+	if err != nil {
+		return errors.Wrap(err, "failed to get app")
+	}
+	if a.IsAirgap {
+		return errors.New("archives of airgap apps cannot be rendered again, the upstream is not available")
+	}
+
+	version, err := store.GetStore().GetAppVersion(appID, sequence)
+	if err != nil {
+		return errors.Wrap(err, "failed to get app version")
+	}
+	updateCursor := version.KOTSKinds.Installation.Spec.UpdateCursor
+	if updateCursor == "" {
+		return errors.New("app version has no update cursor")
+	}
+
+	archiveDir, err := intactArchiveNear(appID, sequence)
+	if err != nil {
+		return errors.Wrap(err, "failed to find an intact archive")
+	}
+	defer os.RemoveAll(archiveDir)
+
+	registrySettings, err := store.GetStore().GetRegistryDetailsForApp(appID)
+	if err != nil {
+		return errors.Wrap(err, "failed to get registry settings")
+	}
+
+	appNamespace := os.Getenv("POD_NAMESPACE")
+	if os.Getenv("KOTSADM_TARGET_NAMESPACE") != "" {
+		appNamespace = os.Getenv("KOTSADM_TARGET_NAMESPACE")
+	}
+
+	pullOptions := kotspull.PullOptions{
+		Namespace:           appNamespace,
+		ConfigFile:          filepath.Join(archiveDir, "upstream", "userdata", "config.yaml"),
+		IdentityConfigFile:  filepath.Join(archiveDir, "upstream", "userdata", "identityconfig.yaml"),
+		InstallationFile:    filepath.Join(archiveDir, "upstream", "userdata", "installation.yaml"),
+		UpdateCursor:        updateCursor,
+		RootDir:             archiveDir,
+		ExcludeKotsKinds:    true,
+		ExcludeAdminConsole: true,
+		CreateAppDir:        false,
+		AppSlug:             a.Slug,
+		AppSequence:         sequence,
+		IsGitOps:            a.IsGitOps,
+		ReportingInfo:       reporting.GetReportingInfo(a.ID),
+		RewriteImages:       registrySettings.IsValid(),
+		RewriteImageOptions: kotspull.RewriteImageOptions{
+			Host:       registrySettings.Hostname,
+			Namespace:  registrySettings.Namespace,
+			Username:   registrySettings.Username,
+			Password:   registrySettings.Password,
+			IsReadOnly: registrySettings.IsReadOnly,
+		},
+	}
+	if _, err := os.Stat(pullOptions.IdentityConfigFile); os.IsNotExist(err) {
+		pullOptions.IdentityConfigFile = ""
+	}
+
+	// git and http upstreams are not licensed
+	upstreamURI := a.UpstreamURI
+	if !kotsupstream.IsGenericUpstream(upstreamURI) {
+		license, err := store.GetStore().GetLicenseForAppVersion(appID, sequence)
+		if err != nil {
+			return errors.Wrap(err, "failed to get license for app version")
+		}
+		pullOptions.LicenseObj = license
+		upstreamURI = fmt.Sprintf("replicated://%s", license.Spec.AppSlug)
+	}
+
+	if _, err := kotspull.Pull(upstreamURI, pullOptions); err != nil {
+		return errors.Wrap(err, "failed to pull")
+	}
+
+	if err := store.GetStore().CreateAppVersionArchive(appID, sequence, archiveDir); err != nil {
+		return errors.Wrap(err, "failed to create app version archive")
+	}
+
+	return nil
+}
+
+// intactArchiveNear extracts the archive of the version of the app closest to the sequence that matches its
+// checksum. The caller must remove the returned dir.
+func intactArchiveNear(appID string, sequence int64) (string, error) {
+	versions, err := store.GetStore().GetAppVersionsAfter(appID, -1)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to get app versions")
+	}
+
+	sequences := []int64{}
+	for _, v := range versions {
+		if v.Sequence != sequence {
+			sequences = append(sequences, v.Sequence)
+		}
+	}
+	sort.Slice(sequences, func(i, j int) bool {
+		return distance(sequences[i], sequence) < distance(sequences[j], sequence)
+	})
+
+	for _, s := range sequences {
+		archiveDir, err := ioutil.TempDir("", "kotsadm")
+		if err != nil {
+			return "", errors.Wrap(err, "failed to create temp dir")
+		}
+		if err := store.GetStore().GetAppVersionArchive(appID, s, archiveDir); err != nil {
+			logger.Error(errors.Wrapf(err, "failed to get archive of sequence %d", s))
+			os.RemoveAll(archiveDir)
+			continue
+		}
+		return archiveDir, nil
+	}
+
+	return "", errors.New("no other version of the app has an intact archive")
+}
+
+func distance(a int64, b int64) int64 {
+	if a > b {
+		return a - b
+	}
+	return b - a
+}
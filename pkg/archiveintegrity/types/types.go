@@ -0,0 +1,30 @@
+package types
+
+import (
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// ErrChecksumMismatch is the cause of errors returned when an app version archive does not match its checksum
+var ErrChecksumMismatch = errors.New("app version archive checksum mismatch")
+
+// Checksum is the checksum of an app version archive, recorded when the archive is created
+type Checksum struct {
+	AppID     string    `json:"appId"`
+	Sequence  int64     `json:"sequence"`
+	SHA256    string    `json:"sha256"`
+	Size      int64     `json:"size"`
+	CreatedAt time.Time `json:"createdAt"`
+	// VerifiedAt is when the archive was last verified by the scrubber or the repair
+	VerifiedAt *time.Time `json:"verifiedAt,omitempty"`
+	// VerifyError is set when the archive is corrupt or missing
+	VerifyError string `json:"verifyError,omitempty"`
+	// Signature is the signature of the checksum, empty for checksums recorded before they were signed
+	Signature string `json:"-"`
+}
+
+// IsCorrupt returns true if the last verification of the archive failed
+func (c Checksum) IsCorrupt() bool {
+	return c.VerifyError != ""
+}
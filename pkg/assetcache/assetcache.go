@@ -0,0 +1,217 @@
+package assetcache
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"mime"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/replicatedhq/kots/pkg/assetcache/types"
+	"github.com/replicatedhq/kots/pkg/logger"
+	"github.com/replicatedhq/kots/pkg/store"
+)
+
+const (
+	// refreshAfter is how long a fetched asset is served before it is fetched again
+	refreshAfter = 24 * time.Hour
+	// retryFailedAfter is how long to wait before fetching an asset again after a failed fetch, so that airgapped
+	// consoles do not wait for the fetch to time out on every request
+	retryFailedAfter = time.Hour
+	fetchTimeout     = 5 * time.Second
+	maxAssetSize     = 2 * 1024 * 1024
+)
+
+var (
+	// registeredKeys avoids writing to the store every time an app that is already registered is listed
+	registeredKeys sync.Map
+	// refreshingKeys are the cache keys of assets that are being fetched again in the background
+	refreshingKeys sync.Map
+	// fetchMtx serializes the fetches of assets that have never been fetched successfully
+	fetchMtx sync.Mutex
+)
+
+// CacheKey returns the key that the asset with the uri is cached under. It is safe to expose, the uri cannot be
+// recovered from it.
+func CacheKey(uri string) string {
+	return fmt.Sprintf("%x", sha256.Sum256([]byte(uri)))
+}
+
+// CachedPath registers the uri with the cache and returns the path of the cached asset relative to the api.
+// An empty path is returned for uris that do not need to be cached, like data uris, which should be used as is.
+func CachedPath(uri string) (string, error) {
+	if !needsCache(uri) {
+		return "", nil
+	}
+
+	cacheKey := CacheKey(uri)
+	if _, ok := registeredKeys.Load(cacheKey); !ok {
+		if err := store.GetStore().RegisterCachedAsset(cacheKey, uri); err != nil {
+			return "", errors.Wrap(err, "failed to register asset")
+		}
+		registeredKeys.Store(cacheKey, true)
+	}
+
+	return fmt.Sprintf("/assets/%s", cacheKey), nil
+}
+
+// Get returns the cached asset with the cache key, or nil if the asset is unknown or could never be fetched.
+// An asset that has never been fetched is fetched before returning. A stale asset is returned right away and
+// fetched again in the background.
+func Get(cacheKey string) (*types.Asset, error) {
+	asset, err := store.GetStore().GetCachedAsset(cacheKey)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get cached asset")
+	}
+	if asset == nil {
+		return nil, nil
+	}
+
+	now := time.Now()
+	if !shouldFetch(*asset, now, isOutboundDisabled()) {
+		if !asset.HasData() {
+			return nil, nil
+		}
+		return asset, nil
+	}
+
+	if asset.HasData() {
+		if _, loaded := refreshingKeys.LoadOrStore(cacheKey, true); !loaded {
+			go func(asset types.Asset) {
+				defer refreshingKeys.Delete(asset.CacheKey)
+				if _, err := fetchAndStore(asset); err != nil {
+					logger.Error(errors.Wrapf(err, "failed to refresh asset %s", asset.CacheKey))
+				}
+			}(*asset)
+		}
+		return asset, nil
+	}
+
+	fetchMtx.Lock()
+	defer fetchMtx.Unlock()
+
+	// another request may have fetched the asset while this one was waiting
+	asset, err = store.GetStore().GetCachedAsset(cacheKey)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get cached asset")
+	}
+	if asset == nil {
+		return nil, nil
+	}
+	if shouldFetch(*asset, now, isOutboundDisabled()) {
+		asset, err = fetchAndStore(*asset)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to fetch asset")
+		}
+	}
+
+	if !asset.HasData() {
+		return nil, nil
+	}
+	return asset, nil
+}
+
+// fetchAndStore fetches the asset and stores the result. A failed fetch is recorded, and the data of the last
+// successful fetch is kept.
+func fetchAndStore(asset types.Asset) (*types.Asset, error) {
+	now := time.Now()
+	asset.FetchedAt = &now
+
+	contentType, data, err := fetch(asset.URI)
+	if err != nil {
+		logger.Infof("failed to fetch asset %s: %v", asset.URI, err)
+		asset.FetchError = err.Error()
+	} else {
+		asset.ContentType = contentType
+		asset.Data = data
+		asset.ETag = etag(data)
+		asset.FetchError = ""
+	}
+
+	if err := store.GetStore().SetCachedAsset(asset); err != nil {
+		return nil, errors.Wrap(err, "failed to set cached asset")
+	}
+
+	return &asset, nil
+}
+
+func fetch(uri string) (string, []byte, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), fetchTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, "GET", uri, nil)
+	if err != nil {
+		return "", nil, errors.Wrap(err, "failed to create request")
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return "", nil, errors.Wrap(err, "failed to execute request")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", nil, errors.Errorf("unexpected status code %d", resp.StatusCode)
+	}
+
+	data, err := ioutil.ReadAll(io.LimitReader(resp.Body, maxAssetSize+1))
+	if err != nil {
+		return "", nil, errors.Wrap(err, "failed to read response body")
+	}
+	if len(data) > maxAssetSize {
+		return "", nil, errors.Errorf("asset is larger than %d bytes", maxAssetSize)
+	}
+	if len(data) == 0 {
+		return "", nil, errors.New("asset is empty")
+	}
+
+	contentType := resp.Header.Get("Content-Type")
+	if contentType == "" || contentType == "application/octet-stream" {
+		contentType = http.DetectContentType(data)
+		if strings.HasSuffix(strings.ToLower(req.URL.Path), ".svg") {
+			contentType = "image/svg+xml"
+		}
+	}
+	// the cached assets are served from the origin of the admin console, only images are served
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil || !strings.HasPrefix(mediaType, "image/") {
+		return "", nil, errors.Errorf("unexpected content type %q", contentType)
+	}
+
+	return mediaType, data, nil
+}
+
+func shouldFetch(asset types.Asset, now time.Time, outboundDisabled bool) bool {
+	if outboundDisabled {
+		return false
+	}
+	if asset.FetchedAt == nil {
+		return true
+	}
+	if asset.FetchError != "" {
+		return now.Sub(*asset.FetchedAt) >= retryFailedAfter
+	}
+	return now.Sub(*asset.FetchedAt) >= refreshAfter
+}
+
+func needsCache(uri string) bool {
+	uri = strings.ToLower(strings.TrimSpace(uri))
+	return strings.HasPrefix(uri, "http://") || strings.HasPrefix(uri, "https://")
+}
+
+func etag(data []byte) string {
+	return fmt.Sprintf(`"%x"`, sha256.Sum256(data))
+}
+
+func isOutboundDisabled() bool {
+	disabled, _ := strconv.ParseBool(os.Getenv("DISABLE_OUTBOUND_CONNECTIONS"))
+	return disabled
+}
@@ -0,0 +1,104 @@
+package assetcache
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/replicatedhq/kots/pkg/assetcache/types"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_shouldFetch(t *testing.T) {
+	now := time.Date(2021, 6, 1, 0, 0, 0, 0, time.UTC)
+	ago := func(d time.Duration) *time.Time {
+		t := now.Add(-d)
+		return &t
+	}
+
+	tests := []struct {
+		name             string
+		asset            types.Asset
+		outboundDisabled bool
+		want             bool
+	}{
+		{
+			name:  "never fetched",
+			asset: types.Asset{},
+			want:  true,
+		},
+		{
+			name:             "never fetched, outbound connections disabled",
+			asset:            types.Asset{},
+			outboundDisabled: true,
+			want:             false,
+		},
+		{
+			name:  "fresh",
+			asset: types.Asset{FetchedAt: ago(time.Hour)},
+			want:  false,
+		},
+		{
+			name:  "stale",
+			asset: types.Asset{FetchedAt: ago(refreshAfter)},
+			want:  true,
+		},
+		{
+			name:  "recently failed",
+			asset: types.Asset{FetchedAt: ago(time.Minute), FetchError: "timeout"},
+			want:  false,
+		},
+		{
+			name:  "failed a while ago",
+			asset: types.Asset{FetchedAt: ago(retryFailedAfter), FetchError: "timeout"},
+			want:  true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			require.Equal(t, test.want, shouldFetch(test.asset, now, test.outboundDisabled))
+		})
+	}
+}
+
+func Test_needsCache(t *testing.T) {
+	require.True(t, needsCache("https://example.com/icon.png"))
+	require.True(t, needsCache(" HTTP://example.com/icon.png"))
+	require.False(t, needsCache("data:image/png;base64,iVBORw0KGgo="))
+	require.False(t, needsCache(""))
+}
+
+func Test_fetch(t *testing.T) {
+	png := []byte("\x89PNG\r\n\x1a\n0000")
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/icon.png":
+			w.Write(png)
+		case "/icon.svg":
+			w.Header().Set("Content-Type", "image/svg+xml; charset=utf-8")
+			w.Write([]byte("<svg></svg>"))
+		case "/page.html":
+			w.Header().Set("Content-Type", "text/html")
+			w.Write([]byte("<html></html>"))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	contentType, data, err := fetch(server.URL + "/icon.png")
+	require.NoError(t, err)
+	require.Equal(t, "image/png", contentType)
+	require.Equal(t, png, data)
+
+	contentType, _, err = fetch(server.URL + "/icon.svg")
+	require.NoError(t, err)
+	require.Equal(t, "image/svg+xml", contentType)
+
+	_, _, err = fetch(server.URL + "/page.html")
+	require.Error(t, err)
+
+	_, _, err = fetch(server.URL + "/missing.png")
+	require.Error(t, err)
+}
@@ -0,0 +1,23 @@
+package types
+
+import (
+	"time"
+)
+
+// Asset is an application asset, like an icon, that was fetched from its uri and cached in the store
+type Asset struct {
+	CacheKey    string
+	URI         string
+	ContentType string
+	Data        []byte
+	ETag        string
+	// FetchedAt is nil if the asset has never been fetched
+	FetchedAt *time.Time
+	// FetchError is the error of the last fetch, if it failed
+	FetchError string
+}
+
+// HasData returns true if the asset was fetched successfully at least once
+func (a Asset) HasData() bool {
+	return len(a.Data) > 0
+}
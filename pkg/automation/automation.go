@@ -106,6 +106,9 @@ LICENSE_LOOP:
 
 		desiredAppName := strings.Replace(verifiedLicense.Spec.AppSlug, "-", " ", 0)
 		upstreamURI := fmt.Sprintf("replicated://%s", verifiedLicense.Spec.AppSlug)
+		if channel := licenseSecret.Annotations["kots.io/channel"]; channel != "" {
+			upstreamURI = fmt.Sprintf("%s/%s", upstreamURI, channel)
+		}
 
 		a, err := store.GetStore().CreateApp(desiredAppName, upstreamURI, string(license), verifiedLicense.Spec.IsAirgapSupported, instParams.SkipImagePush, instParams.RegistryIsReadOnly)
 		if err != nil {
@@ -209,7 +209,7 @@ func (f BaseFile) IsKotsKind() (bool, error) {
 }
 
 func iskotsAPIVersionKind(o OverlySimpleGVK) bool {
-	if o.APIVersion == "velero.io/v1" && o.Kind == "Backup" {
+	if o.APIVersion == "velero.io/v1" && (o.Kind == "Backup" || o.Kind == "Restore") {
 		return true
 	}
 	if o.APIVersion == "kots.io/v1beta1" {
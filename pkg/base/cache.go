@@ -0,0 +1,295 @@
+package base
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/replicatedhq/kots/pkg/buildversion"
+	"github.com/replicatedhq/kots/pkg/template"
+	upstreamtypes "github.com/replicatedhq/kots/pkg/upstream/types"
+)
+
+const (
+	// maxCachedBases is the number of rendered bases kept in the cache dir, the least recently used are removed first
+	maxCachedBases = 50
+)
+
+// uncacheableFunctions are the template functions whose output is not determined by the cache key. The sequence
+// changes with every version, and the others depend on the cluster, the environment or the time of the render.
+var uncacheableFunctions = []string{
+	"Sequence",
+	"Now",
+	"NowFmt",
+	"NodeCount",
+	"Distribution",
+	"IsKurl",
+	"KurlString",
+	"KurlInt",
+	"KurlBool",
+	"KurlOption",
+	"KurlAll",
+	"Namespace",
+	"HTTPProxy",
+	"NoProxy",
+}
+
+type cachedBase struct {
+	Path       string           `json:"path"`
+	Namespace  string           `json:"namespace"`
+	Files      []cachedBaseFile `json:"files"`
+	ErrorFiles []cachedBaseFile `json:"errorFiles"`
+	Bases      []cachedBase     `json:"bases"`
+}
+
+type cachedBaseFile struct {
+	Path    string `json:"path"`
+	Content []byte `json:"content"`
+	Error   string `json:"error,omitempty"`
+}
+
+// GetBaseCacheDir returns the directory rendered bases are cached in.
+// Caching is disabled when KOTS_BASE_CACHE_DIR is not set.
+func GetBaseCacheDir() string {
+	return os.Getenv("KOTS_BASE_CACHE_DIR")
+}
+
+// RenderUpstreamCached renders the upstream the same way RenderUpstream does, but returns a previously
+// rendered base when the upstream files and render options are identical to a previous render.
+// Apps and downstreams that share the same upstream release only pay for rendering once.
+func RenderUpstreamCached(u *upstreamtypes.Upstream, renderOptions *RenderOptions) (*Base, error) {
+	cacheDir := GetBaseCacheDir()
+	if cacheDir == "" || !isCacheable(u) {
+		return RenderUpstream(u, renderOptions)
+	}
+
+	key, err := BaseCacheKey(u, renderOptions)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get cache key")
+	}
+	cacheFile := filepath.Join(cacheDir, key+".json")
+
+	if b, err := readCachedBase(cacheFile); err == nil {
+		if renderOptions.Log != nil {
+			renderOptions.Log.Debug("using cached base %s", key)
+		}
+		return b, nil
+	}
+
+	b, err := RenderUpstream(u, renderOptions)
+	if err != nil {
+		return nil, err
+	}
+
+	// the cache is an optimization, failing to write to it should not fail the render
+	if err := writeCachedBase(cacheDir, cacheFile, b); err != nil && renderOptions.Log != nil {
+		renderOptions.Log.Debug("failed to cache base %s: %v", key, err)
+	}
+
+	return b, nil
+}
+
+// BaseCacheKey is the content hash of everything that goes into rendering a base, except for the sequence, so that
+// all versions of the same release share the entry. Upstreams that reference the sequence are not cached.
+func BaseCacheKey(u *upstreamtypes.Upstream, renderOptions *RenderOptions) (string, error) {
+	files := make([]upstreamtypes.UpstreamFile, len(u.Files))
+	copy(files, u.Files)
+	sort.Slice(files, func(i, j int) bool {
+		return files[i].Path < files[j].Path
+	})
+
+	// Log is excluded because it does not change the output
+	options := *renderOptions
+	options.Log = nil
+	options.Sequence = 0
+
+	input := struct {
+		KotsVersion   string
+		Name          string
+		Type          string
+		EncryptionKey string
+		Files         []upstreamtypes.UpstreamFile
+		Options       RenderOptions
+	}{
+		KotsVersion:   buildversion.Version(),
+		Name:          u.Name,
+		Type:          u.Type,
+		EncryptionKey: u.EncryptionKey,
+		Files:         files,
+		Options:       options,
+	}
+
+	b, err := json.Marshal(input)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to marshal cache key input")
+	}
+
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// isCacheable returns false if any of the upstream files use a template function whose output is not determined
+// by the cache key, or can't be parsed to find out
+func isCacheable(u *upstreamtypes.Upstream) bool {
+	for _, f := range u.Files {
+		functions, err := template.ReferencedFunctions(string(f.Content))
+		if err != nil {
+			return false
+		}
+		for _, name := range uncacheableFunctions {
+			if _, ok := functions[name]; ok {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func readCachedBase(cacheFile string) (*Base, error) {
+	b, err := ioutil.ReadFile(cacheFile)
+	if err != nil {
+		return nil, err
+	}
+
+	cached := cachedBase{}
+	if err := json.Unmarshal(b, &cached); err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal cached base")
+	}
+
+	// mark the entry as recently used
+	now := time.Now()
+	_ = os.Chtimes(cacheFile, now, now)
+
+	base := cached.toBase()
+	return &base, nil
+}
+
+func writeCachedBase(cacheDir string, cacheFile string, b *Base) error {
+	if err := os.MkdirAll(cacheDir, 0755); err != nil {
+		return errors.Wrap(err, "failed to create cache dir")
+	}
+
+	data, err := json.Marshal(newCachedBase(*b))
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal base")
+	}
+
+	// write to a temp file first so that concurrent renders never read a partial entry
+	tmpFile, err := ioutil.TempFile(cacheDir, "base-*.tmp")
+	if err != nil {
+		return errors.Wrap(err, "failed to create temp file")
+	}
+	defer os.Remove(tmpFile.Name())
+
+	if _, err := tmpFile.Write(data); err != nil {
+		tmpFile.Close()
+		return errors.Wrap(err, "failed to write temp file")
+	}
+	if err := tmpFile.Close(); err != nil {
+		return errors.Wrap(err, "failed to close temp file")
+	}
+
+	if err := os.Rename(tmpFile.Name(), cacheFile); err != nil {
+		return errors.Wrap(err, "failed to move cache entry")
+	}
+
+	return pruneBaseCache(cacheDir, maxCachedBases)
+}
+
+func pruneBaseCache(cacheDir string, maxEntries int) error {
+	entries, err := filepath.Glob(filepath.Join(cacheDir, "*.json"))
+	if err != nil {
+		return errors.Wrap(err, "failed to list cache entries")
+	}
+	if len(entries) <= maxEntries {
+		return nil
+	}
+
+	type entry struct {
+		path    string
+		modTime int64
+	}
+	sorted := []entry{}
+	for _, e := range entries {
+		info, err := os.Stat(e)
+		if err != nil {
+			continue
+		}
+		sorted = append(sorted, entry{path: e, modTime: info.ModTime().UnixNano()})
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].modTime > sorted[j].modTime
+	})
+
+	for i := maxEntries; i < len(sorted); i++ {
+		if err := os.Remove(sorted[i].path); err != nil && !os.IsNotExist(err) {
+			return errors.Wrapf(err, "failed to remove cache entry %s", sorted[i].path)
+		}
+	}
+
+	return nil
+}
+
+func newCachedBase(b Base) cachedBase {
+	cached := cachedBase{
+		Path:       b.Path,
+		Namespace:  b.Namespace,
+		Files:      newCachedBaseFiles(b.Files),
+		ErrorFiles: newCachedBaseFiles(b.ErrorFiles),
+		Bases:      []cachedBase{},
+	}
+	for _, sub := range b.Bases {
+		cached.Bases = append(cached.Bases, newCachedBase(sub))
+	}
+	return cached
+}
+
+func newCachedBaseFiles(files []BaseFile) []cachedBaseFile {
+	cached := []cachedBaseFile{}
+	for _, f := range files {
+		c := cachedBaseFile{
+			Path:    f.Path,
+			Content: f.Content,
+		}
+		if f.Error != nil {
+			c.Error = f.Error.Error()
+		}
+		cached = append(cached, c)
+	}
+	return cached
+}
+
+func (c cachedBase) toBase() Base {
+	b := Base{
+		Path:       c.Path,
+		Namespace:  c.Namespace,
+		Files:      toBaseFiles(c.Files),
+		ErrorFiles: toBaseFiles(c.ErrorFiles),
+		Bases:      []Base{},
+	}
+	for _, sub := range c.Bases {
+		b.Bases = append(b.Bases, sub.toBase())
+	}
+	return b
+}
+
+func toBaseFiles(cached []cachedBaseFile) []BaseFile {
+	files := []BaseFile{}
+	for _, c := range cached {
+		f := BaseFile{
+			Path:    c.Path,
+			Content: c.Content,
+		}
+		if c.Error != "" {
+			f.Error = errors.New(c.Error)
+		}
+		files = append(files, f)
+	}
+	return files
+}
@@ -0,0 +1,174 @@
+package base
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/pkg/errors"
+	upstreamtypes "github.com/replicatedhq/kots/pkg/upstream/types"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_BaseCacheKey(t *testing.T) {
+	req := require.New(t)
+
+	u := &upstreamtypes.Upstream{
+		Type: "replicated",
+		Files: []upstreamtypes.UpstreamFile{
+			{Path: "a.yaml", Content: []byte("a")},
+			{Path: "b.yaml", Content: []byte("b")},
+		},
+	}
+	reordered := &upstreamtypes.Upstream{
+		Type: "replicated",
+		Files: []upstreamtypes.UpstreamFile{
+			{Path: "b.yaml", Content: []byte("b")},
+			{Path: "a.yaml", Content: []byte("a")},
+		},
+	}
+	changed := &upstreamtypes.Upstream{
+		Type: "replicated",
+		Files: []upstreamtypes.UpstreamFile{
+			{Path: "a.yaml", Content: []byte("a")},
+			{Path: "b.yaml", Content: []byte("c")},
+		},
+	}
+
+	key, err := BaseCacheKey(u, &RenderOptions{Namespace: "default"})
+	req.NoError(err)
+
+	reorderedKey, err := BaseCacheKey(reordered, &RenderOptions{Namespace: "default"})
+	req.NoError(err)
+	req.Equal(key, reorderedKey)
+
+	changedKey, err := BaseCacheKey(changed, &RenderOptions{Namespace: "default"})
+	req.NoError(err)
+	req.NotEqual(key, changedKey)
+
+	otherNamespaceKey, err := BaseCacheKey(u, &RenderOptions{Namespace: "other"})
+	req.NoError(err)
+	req.NotEqual(key, otherNamespaceKey)
+
+	// other versions of the same release share the entry
+	otherSequenceKey, err := BaseCacheKey(u, &RenderOptions{Namespace: "default", Sequence: 3})
+	req.NoError(err)
+	req.Equal(key, otherSequenceKey)
+
+	// the app slug is rendered into the names of the identity service and the sensitive config secret
+	otherAppKey, err := BaseCacheKey(u, &RenderOptions{Namespace: "default", AppSlug: "other-app"})
+	req.NoError(err)
+	req.NotEqual(key, otherAppKey)
+}
+
+func Test_isCacheable(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    bool
+	}{
+		{
+			name:    "no template functions",
+			content: "a: b",
+			want:    true,
+		},
+		{
+			name:    "local registry",
+			content: "image: '{{repl LocalRegistryHost }}/app'",
+			want:    true,
+		},
+		{
+			name:    "helm values named like a function",
+			content: "sequence: '{{ .Values.Sequence }}'",
+			want:    true,
+		},
+		{
+			name:    "sequence",
+			content: "version: '{{repl Sequence }}'",
+			want:    false,
+		},
+		{
+			name:    "node count in a condition",
+			content: "replicas: repl{{ if gt NodeCount 1 }}3repl{{ else }}1repl{{ end }}",
+			want:    false,
+		},
+		{
+			name:    "distribution",
+			content: "provider: '{{repl Distribution }}'",
+			want:    false,
+		},
+		{
+			name:    "now in a pipeline",
+			content: "date: '{{repl Now | upper }}'",
+			want:    false,
+		},
+		{
+			name:    "unparseable",
+			content: "a: '{{repl ConfigOption }'",
+			want:    false,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			u := &upstreamtypes.Upstream{
+				Files: []upstreamtypes.UpstreamFile{
+					{Path: "a.yaml", Content: []byte("a")},
+					{Path: "b.yaml", Content: []byte(test.content)},
+				},
+			}
+			require.Equal(t, test.want, isCacheable(u))
+		})
+	}
+}
+
+func Test_cachedBaseRoundTrip(t *testing.T) {
+	req := require.New(t)
+
+	cacheDir, err := ioutil.TempDir("", "kots-base-cache")
+	req.NoError(err)
+	defer os.RemoveAll(cacheDir)
+
+	b := &Base{
+		Path:      ".",
+		Namespace: "default",
+		Files: []BaseFile{
+			{Path: "deployment.yaml", Content: []byte("kind: Deployment")},
+		},
+		ErrorFiles: []BaseFile{
+			{Path: "broken.yaml", Content: []byte("{"), Error: ParseError{Err: errors.New("invalid yaml")}},
+		},
+		Bases: []Base{
+			{Path: "charts/redis", Files: []BaseFile{{Path: "redis.yaml", Content: []byte("kind: Service")}}},
+		},
+	}
+
+	cacheFile := filepath.Join(cacheDir, "key.json")
+	req.NoError(writeCachedBase(cacheDir, cacheFile, b))
+
+	got, err := readCachedBase(cacheFile)
+	req.NoError(err)
+	req.Equal(b.Path, got.Path)
+	req.Equal(b.Files, got.Files)
+	req.Len(got.ErrorFiles, 1)
+	req.EqualError(got.ErrorFiles[0].Error, b.ErrorFiles[0].Error.Error())
+	req.Equal(b.Bases[0].Files, got.Bases[0].Files)
+}
+
+func Test_pruneBaseCache(t *testing.T) {
+	req := require.New(t)
+
+	cacheDir, err := ioutil.TempDir("", "kots-base-cache")
+	req.NoError(err)
+	defer os.RemoveAll(cacheDir)
+
+	for _, name := range []string{"a", "b", "c"} {
+		req.NoError(ioutil.WriteFile(filepath.Join(cacheDir, name+".json"), []byte("{}"), 0644))
+	}
+
+	req.NoError(pruneBaseCache(cacheDir, 2))
+
+	entries, err := filepath.Glob(filepath.Join(cacheDir, "*.json"))
+	req.NoError(err)
+	req.Len(entries, 2)
+}
@@ -31,7 +31,8 @@ func RenderUpstream(u *upstreamtypes.Upstream, renderOptions *RenderOptions) (*B
 		return RenderHelm(u, renderOptions)
 	}
 
-	if u.Type == "replicated" {
+	// git, http and local upstreams contain the same manifests and kots kinds as a replicated release
+	if u.Type == "replicated" || u.Type == "git" || u.Type == "http" || u.Type == "local" {
 		return renderReplicated(u, renderOptions)
 	}
 
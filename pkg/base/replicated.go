@@ -371,6 +371,22 @@ func findConfigAndLicense(u *upstreamtypes.Upstream, log *logger.CLILogger) (*ko
 	return config, values, identityConfig, license, nil
 }
 
+func findKotsApplication(u *upstreamtypes.Upstream) *kotsv1beta1.Application {
+	for _, file := range u.Files {
+		decode := scheme.Codecs.UniversalDeserializer().Decode
+		obj, gvk, err := decode(file.Content, nil, nil)
+		if err != nil {
+			continue
+		}
+
+		if gvk.Group == "kots.io" && gvk.Version == "v1beta1" && gvk.Kind == "Application" {
+			return obj.(*kotsv1beta1.Application)
+		}
+	}
+
+	return nil
+}
+
 // findHelmChartArchiveInRelease iterates through all files in the release (upstreamFiles), looking for a helm chart archive
 // that matches the chart name and version specified in the kotsHelmChart parameter
 func findHelmChartArchiveInRelease(upstreamFiles []upstreamtypes.UpstreamFile, kotsHelmChart *kotsv1beta1.HelmChart) ([]byte, error) {
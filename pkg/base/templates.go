@@ -4,8 +4,10 @@ import (
 	"github.com/pkg/errors"
 	kotsv1beta1 "github.com/replicatedhq/kots/kotskinds/apis/kots/v1beta1"
 	"github.com/replicatedhq/kots/pkg/crypto"
+	"github.com/replicatedhq/kots/pkg/sensitiveconfig"
 	"github.com/replicatedhq/kots/pkg/template"
 	upstreamtypes "github.com/replicatedhq/kots/pkg/upstream/types"
+	"github.com/replicatedhq/kots/pkg/vault"
 )
 
 func NewConfigContextTemplateBuidler(u *upstreamtypes.Upstream, renderOptions *RenderOptions) (*template.Builder, error) {
@@ -51,7 +53,9 @@ func NewConfigContextTemplateBuidler(u *upstreamtypes.Upstream, renderOptions *R
 	}
 
 	appInfo := template.ApplicationInfo{
-		Slug: renderOptions.AppSlug,
+		Slug:                    renderOptions.AppSlug,
+		SensitiveConfigAsSecret: sensitiveconfig.IsEnabled(findKotsApplication(u)),
+		VaultItems:              vault.ItemNames(configValues),
 	}
 
 	versionInfo := template.VersionInfo{
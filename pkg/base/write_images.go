@@ -1,6 +1,7 @@
 package base
 
 import (
+	"context"
 	"io"
 
 	"github.com/pkg/errors"
@@ -12,6 +13,8 @@ import (
 )
 
 type WriteUpstreamImageOptions struct {
+	// Context is the parent of the image processing span, the background context if nil
+	Context        context.Context
 	BaseDir        string
 	AppSlug        string
 	SourceRegistry registry.RegistryOptions
@@ -41,7 +44,12 @@ func ProcessUpstreamImages(options WriteUpstreamImageOptions) (*WriteUpstreamIma
 		rewriteAll = true
 	}
 
-	newImages, err := image.ProcessImages(options.SourceRegistry, options.DestRegistry, options.AppSlug, options.Log, options.ReportWriter, options.BaseDir, additionalImages, options.CopyImages, rewriteAll, checkedImages)
+	ctx := options.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	newImages, err := image.ProcessImages(ctx, options.SourceRegistry, options.DestRegistry, options.AppSlug, options.Log, options.ReportWriter, options.BaseDir, additionalImages, options.CopyImages, rewriteAll, checkedImages)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to save images")
 	}
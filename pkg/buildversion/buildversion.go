@@ -4,6 +4,7 @@ import (
 	"io/ioutil"
 	"net/http"
 	"runtime"
+	"strings"
 	"time"
 
 	semver "github.com/Masterminds/semver/v3"
@@ -70,6 +71,29 @@ func BuildTime() time.Time {
 	return build.BuildTime
 }
 
+// IsAtLeast returns true if the version of this build is the same as or newer than the given version.
+// Development builds that do not have a version are always considered new enough
+func IsAtLeast(minVersion string) (bool, error) {
+	return isAtLeast(Version(), minVersion)
+}
+
+func isAtLeast(currentVersion string, minVersion string) (bool, error) {
+	minSemver, err := semver.NewVersion(minVersion)
+	if err != nil {
+		return false, errors.Wrapf(err, "minimum version %s does not parse as semver", minVersion)
+	}
+
+	currentSemver, err := semver.NewVersion(currentVersion)
+	if err != nil {
+		return true, nil
+	}
+	if currentSemver.Major() == 0 && currentSemver.Minor() == 0 && currentSemver.Patch() == 0 {
+		return true, nil
+	}
+
+	return !currentSemver.LessThan(minSemver), nil
+}
+
 func getGoInfo() GoInfo {
 	return GoInfo{
 		Version:  runtime.Version(),
@@ -88,14 +112,31 @@ func IsLatestRelease() (bool, string, error) {
 	return isLatestRelease(fastClient, "https://kots.io")
 }
 
-func isLatestRelease(client *http.Client, upstream string) (bool, string, error) {
+// LatestRelease queries for the version of the latest release in the project repo
+func LatestRelease() (string, error) {
+	client := &http.Client{
+		Timeout: time.Second * 10,
+	}
+	return latestRelease(client, "https://kots.io")
+}
+
+func latestRelease(client *http.Client, upstream string) (string, error) {
 	resp, err := client.Get(upstream + "/install?version")
 	if err != nil {
-		return false, "", errors.Wrapf(err, "find latest release")
+		return "", errors.Wrapf(err, "find latest release")
 	}
+	defer resp.Body.Close()
 	body, err := ioutil.ReadAll(resp.Body)
 	if err != nil {
-		return false, "", errors.Wrapf(err, "read latest release body")
+		return "", errors.Wrapf(err, "read latest release body")
+	}
+	return strings.TrimSpace(string(body)), nil
+}
+
+func isLatestRelease(client *http.Client, upstream string) (bool, string, error) {
+	latest, err := latestRelease(client, upstream)
+	if err != nil {
+		return false, "", err
 	}
 
 	currentSemver, err := semver.NewVersion(Version())
@@ -103,13 +144,13 @@ func isLatestRelease(client *http.Client, upstream string) (bool, string, error)
 		return false, "", errors.Wrapf(err, "current release %s does not parse as semver", Version())
 	}
 
-	latestSemver, err := semver.NewVersion(string(body))
+	latestSemver, err := semver.NewVersion(latest)
 	if err != nil {
-		return false, "", errors.Wrapf(err, "latest release %s does not parse as semver", string(body))
+		return false, "", errors.Wrapf(err, "latest release %s does not parse as semver", latest)
 	}
 
 	if currentSemver.LessThan(latestSemver) {
-		return false, string(body), nil
+		return false, latest, nil
 	}
 
 	return true, "", nil
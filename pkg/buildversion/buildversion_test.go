@@ -201,3 +201,63 @@ func TestIsLatestRelease(t *testing.T) {
 		})
 	}
 }
+
+func Test_isAtLeast(t *testing.T) {
+	tests := []struct {
+		name       string
+		current    string
+		minVersion string
+		want       bool
+		wantErr    bool
+	}{
+		{
+			name:       "older",
+			current:    "v1.40.0",
+			minVersion: "1.41.0",
+			want:       false,
+		},
+		{
+			name:       "same",
+			current:    "v1.41.0",
+			minVersion: "v1.41.0",
+			want:       true,
+		},
+		{
+			name:       "newer",
+			current:    "v1.42.1",
+			minVersion: "1.41.0",
+			want:       true,
+		},
+		{
+			name:       "prerelease of the minimum",
+			current:    "v1.41.0-beta.1",
+			minVersion: "1.41.0",
+			want:       false,
+		},
+		{
+			name:       "development build",
+			current:    "v0.0.0-unknown",
+			minVersion: "1.41.0",
+			want:       true,
+		},
+		{
+			name:       "invalid minimum",
+			current:    "v1.41.0",
+			minVersion: "latest",
+			wantErr:    true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := require.New(t)
+
+			got, err := isAtLeast(tt.current, tt.minVersion)
+			if tt.wantErr {
+				req.Error(err)
+				return
+			}
+			req.NoError(err)
+			req.Equal(tt.want, got)
+		})
+	}
+}
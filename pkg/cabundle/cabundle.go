@@ -0,0 +1,392 @@
+package cabundle
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/replicatedhq/kots/pkg/clientcert"
+	"github.com/replicatedhq/kots/pkg/k8sutil"
+	kotsadmtypes "github.com/replicatedhq/kots/pkg/kotsadm/types"
+	"github.com/replicatedhq/kots/pkg/logger"
+	"github.com/replicatedhq/kots/pkg/proxyauth"
+	corev1 "k8s.io/api/core/v1"
+	kuberneteserrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	// ConfigMapName is the config map in the kotsadm namespace that holds the custom ca bundle
+	ConfigMapName = "kotsadm-ca-bundle"
+	// ConfigMapKey is the key of the pem encoded bundle in the config map
+	ConfigMapKey = "ca-bundle.crt"
+)
+
+type state struct {
+	bundle         []byte
+	certificates   map[string]clientcert.ClientCertificate
+	transport      *http.Transport
+	hostTransports map[string]*http.Transport
+}
+
+var (
+	current       atomic.Value // *state
+	baseTransport *http.Transport
+	installOnce   sync.Once
+	loadLock      sync.Mutex
+)
+
+// Validate returns an error if the bundle does not contain at least one pem encoded certificate
+func Validate(bundle []byte) error {
+	found := false
+	rest := bundle
+	for {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			break
+		}
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+		if _, err := x509.ParseCertificate(block.Bytes); err != nil {
+			return errors.Wrap(err, "failed to parse certificate")
+		}
+		found = true
+	}
+	if !found {
+		return errors.New("no pem encoded certificates found")
+	}
+	return nil
+}
+
+// Get returns the custom ca bundle stored in the namespace, or nil if there is none
+func Get(clientset kubernetes.Interface, namespace string) ([]byte, error) {
+	configMap, err := clientset.CoreV1().ConfigMaps(namespace).Get(context.TODO(), ConfigMapName, metav1.GetOptions{})
+	if kuberneteserrors.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get config map")
+	}
+	return []byte(configMap.Data[ConfigMapKey]), nil
+}
+
+// Set stores the custom ca bundle in the namespace. An empty bundle removes it.
+func Set(clientset kubernetes.Interface, namespace string, bundle []byte) error {
+	if len(bundle) == 0 {
+		err := clientset.CoreV1().ConfigMaps(namespace).Delete(context.TODO(), ConfigMapName, metav1.DeleteOptions{})
+		if err != nil && !kuberneteserrors.IsNotFound(err) {
+			return errors.Wrap(err, "failed to delete config map")
+		}
+		return nil
+	}
+
+	if err := Validate(bundle); err != nil {
+		return errors.Wrap(err, "invalid ca bundle")
+	}
+
+	existing, err := clientset.CoreV1().ConfigMaps(namespace).Get(context.TODO(), ConfigMapName, metav1.GetOptions{})
+	if err != nil {
+		if !kuberneteserrors.IsNotFound(err) {
+			return errors.Wrap(err, "failed to get config map")
+		}
+
+		configMap := &corev1.ConfigMap{
+			TypeMeta: metav1.TypeMeta{
+				APIVersion: "v1",
+				Kind:       "ConfigMap",
+			},
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      ConfigMapName,
+				Namespace: namespace,
+				Labels:    kotsadmtypes.GetKotsadmLabels(),
+			},
+			Data: map[string]string{
+				ConfigMapKey: string(bundle),
+			},
+		}
+		if _, err := clientset.CoreV1().ConfigMaps(namespace).Create(context.TODO(), configMap, metav1.CreateOptions{}); err != nil {
+			return errors.Wrap(err, "failed to create config map")
+		}
+		return nil
+	}
+
+	if existing.Data == nil {
+		existing.Data = map[string]string{}
+	}
+	existing.Data[ConfigMapKey] = string(bundle)
+	if _, err := clientset.CoreV1().ConfigMaps(namespace).Update(context.TODO(), existing, metav1.UpdateOptions{}); err != nil {
+		return errors.Wrap(err, "failed to update config map")
+	}
+	return nil
+}
+
+// Load makes the bundle trusted, in addition to the system roots, by all requests made with the default
+// http transport and by image copies that use DockerCertPath. Connections made with the previous bundle are closed.
+func Load(bundle []byte) error {
+	loadLock.Lock()
+	defer loadLock.Unlock()
+
+	certificates := map[string]clientcert.ClientCertificate{}
+	if s := load(); s != nil {
+		certificates = s.certificates
+	}
+	return loadState(bundle, certificates)
+}
+
+// LoadClientCertificates makes requests made with the default http transport and image copies that use
+// DockerCertPath present the client certificate of the host they connect to
+func LoadClientCertificates(certificates map[string]clientcert.ClientCertificate) error {
+	loadLock.Lock()
+	defer loadLock.Unlock()
+
+	var bundle []byte
+	if s := load(); s != nil {
+		bundle = s.bundle
+	}
+	return loadState(bundle, certificates)
+}
+
+func loadState(bundle []byte, certificates map[string]clientcert.ClientCertificate) error {
+	installOnce.Do(func() {
+		baseTransport = http.DefaultTransport.(*http.Transport).Clone()
+		proxyauth.Configure(baseTransport)
+		http.DefaultTransport = &reloadingTransport{}
+	})
+
+	if s := load(); s != nil && bytes.Equal(s.bundle, bundle) && reflect.DeepEqual(s.certificates, certificates) {
+		return nil
+	}
+
+	next := &state{
+		bundle:         bundle,
+		certificates:   certificates,
+		transport:      baseTransport.Clone(),
+		hostTransports: map[string]*http.Transport{},
+	}
+
+	var pool *x509.CertPool
+	if len(bundle) > 0 {
+		if err := Validate(bundle); err != nil {
+			return errors.Wrap(err, "invalid ca bundle")
+		}
+
+		systemPool, err := x509.SystemCertPool()
+		if err != nil || systemPool == nil {
+			systemPool = x509.NewCertPool()
+		}
+		systemPool.AppendCertsFromPEM(bundle)
+		pool = systemPool
+		next.transport.TLSClientConfig = &tls.Config{RootCAs: pool}
+	}
+
+	for host, c := range certificates {
+		cert, err := c.TLSCertificate()
+		if err != nil {
+			return errors.Wrapf(err, "invalid client certificate for %s", host)
+		}
+		transport := baseTransport.Clone()
+		transport.TLSClientConfig = &tls.Config{
+			RootCAs:      pool,
+			Certificates: []tls.Certificate{cert},
+		}
+		next.hostTransports[host] = transport
+	}
+
+	if err := writeCertDir(bundle, certificates); err != nil {
+		return errors.Wrap(err, "failed to write cert dir")
+	}
+	if err := clientcert.Load(certificates); err != nil {
+		return errors.Wrap(err, "failed to load client certificates")
+	}
+
+	previous := load()
+	current.Store(next)
+	if previous != nil {
+		previous.transport.CloseIdleConnections()
+		for _, transport := range previous.hostTransports {
+			transport.CloseIdleConnections()
+		}
+	}
+
+	return nil
+}
+
+// Start loads the bundle and the client certificates stored in the namespace and reloads them whenever they change
+func Start(namespace string) error {
+	clientset, err := k8sutil.GetClientset()
+	if err != nil {
+		return errors.Wrap(err, "failed to get clientset")
+	}
+
+	reload := func() error {
+		bundle, err := Get(clientset, namespace)
+		if err != nil {
+			return errors.Wrap(err, "failed to get ca bundle")
+		}
+		certificates, err := GetClientCertificates(clientset, namespace)
+		if err != nil {
+			return errors.Wrap(err, "failed to get client certificates")
+		}
+
+		loadLock.Lock()
+		defer loadLock.Unlock()
+		if err := loadState(bundle, certificates); err != nil {
+			return errors.Wrap(err, "failed to load ca bundle")
+		}
+		return nil
+	}
+
+	if err := reload(); err != nil {
+		return err
+	}
+
+	go func() {
+		for {
+			time.Sleep(time.Minute)
+			if err := reload(); err != nil {
+				logger.Error(errors.Wrap(err, "failed to reload ca bundle"))
+			}
+		}
+	}()
+
+	return nil
+}
+
+// DockerCertPath returns a directory that can be used as the DockerCertPath of an image SystemContext
+// to trust the loaded bundle and present the client certificate of the registry host, or an empty string
+// if there is neither
+func DockerCertPath(host string) string {
+	s := load()
+	if s == nil {
+		return ""
+	}
+	if matched, ok := matchHost(s.certificates, host); ok {
+		return hostCertDir(matched)
+	}
+	if len(s.bundle) > 0 {
+		return certDir()
+	}
+	return ""
+}
+
+func load() *state {
+	s, _ := current.Load().(*state)
+	return s
+}
+
+// matchHost returns the key of the host in the certificates, with or without the port
+func matchHost(certificates map[string]clientcert.ClientCertificate, host string) (string, bool) {
+	if _, ok := certificates[host]; ok {
+		return host, true
+	}
+	if hostname, _, err := net.SplitHostPort(host); err == nil {
+		if _, ok := certificates[hostname]; ok {
+			return hostname, true
+		}
+	}
+	return "", false
+}
+
+func certDir() string {
+	return filepath.Join(os.TempDir(), "kotsadm-ca-bundle")
+}
+
+func hostCertDir(host string) string {
+	return filepath.Join(certDir(), "hosts", host)
+}
+
+// writeCertDir replaces the bundle in the cert dir and writes a dir with the bundle and the client certificate
+// for each host. containers/image adds all *.crt files in the cert dir to the system roots and presents the
+// *.cert and *.key pair, so files are renamed into place to never be read partially written.
+func writeCertDir(bundle []byte, certificates map[string]clientcert.ClientCertificate) error {
+	if err := writeCertFile(certDir(), "ca.crt", bundle, 0644); err != nil {
+		return errors.Wrap(err, "failed to write bundle")
+	}
+
+	hostDirs, err := ioutil.ReadDir(filepath.Join(certDir(), "hosts"))
+	if err != nil && !os.IsNotExist(err) {
+		return errors.Wrap(err, "failed to read hosts dir")
+	}
+	for _, hostDir := range hostDirs {
+		if _, ok := certificates[hostDir.Name()]; !ok {
+			if err := os.RemoveAll(hostCertDir(hostDir.Name())); err != nil {
+				return errors.Wrapf(err, "failed to remove cert dir for %s", hostDir.Name())
+			}
+		}
+	}
+
+	for host, c := range certificates {
+		dir := hostCertDir(host)
+		if err := writeCertFile(dir, "ca.crt", bundle, 0644); err != nil {
+			return errors.Wrapf(err, "failed to write bundle for %s", host)
+		}
+		if err := writeCertFile(dir, "client.cert", []byte(c.Cert), 0644); err != nil {
+			return errors.Wrapf(err, "failed to write client certificate for %s", host)
+		}
+		if err := writeCertFile(dir, "client.key", []byte(c.Key), 0600); err != nil {
+			return errors.Wrapf(err, "failed to write client key for %s", host)
+		}
+	}
+
+	return nil
+}
+
+// writeCertFile replaces the file in dir with the contents, or removes it if the contents are empty
+func writeCertFile(dir string, name string, contents []byte, mode os.FileMode) error {
+	filename := filepath.Join(dir, name)
+	if len(contents) == 0 {
+		if err := os.Remove(filename); err != nil && !os.IsNotExist(err) {
+			return errors.Wrap(err, "failed to remove file")
+		}
+		return nil
+	}
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return errors.Wrap(err, "failed to create dir")
+	}
+	tmpFile := filename + ".tmp"
+	if err := ioutil.WriteFile(tmpFile, contents, mode); err != nil {
+		return errors.Wrap(err, "failed to write file")
+	}
+	if err := os.Rename(tmpFile, filename); err != nil {
+		return errors.Wrap(err, "failed to rename file")
+	}
+	return nil
+}
+
+type reloadingTransport struct{}
+
+func (t *reloadingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	s := load()
+	if s == nil {
+		return baseTransport.RoundTrip(req)
+	}
+	if host, ok := matchHost(s.certificates, req.URL.Host); ok {
+		return s.hostTransports[host].RoundTrip(req)
+	}
+	return s.transport.RoundTrip(req)
+}
+
+func (t *reloadingTransport) CloseIdleConnections() {
+	if s := load(); s != nil {
+		s.transport.CloseIdleConnections()
+		for _, transport := range s.hostTransports {
+			transport.CloseIdleConnections()
+		}
+	}
+}
@@ -0,0 +1,136 @@
+package cabundle
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/replicatedhq/kots/pkg/clientcert"
+	"github.com/stretchr/testify/require"
+)
+
+func testCertificate(t *testing.T) []byte {
+	cert, _ := testKeyPair(t)
+	return cert
+}
+
+func testKeyPair(t *testing.T) ([]byte, []byte) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test ca"},
+		NotBefore:             time.Now(),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+
+	keyDer, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDer})
+}
+
+func Test_Validate(t *testing.T) {
+	cert := testCertificate(t)
+
+	tests := []struct {
+		name    string
+		bundle  []byte
+		wantErr bool
+	}{
+		{
+			name:   "single certificate",
+			bundle: cert,
+		},
+		{
+			name:   "multiple certificates",
+			bundle: append(append([]byte{}, cert...), cert...),
+		},
+		{
+			name:    "no certificates",
+			bundle:  []byte("not a certificate"),
+			wantErr: true,
+		},
+		{
+			name:    "invalid certificate",
+			bundle:  pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: []byte("invalid")}),
+			wantErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := Validate(test.bundle)
+			if test.wantErr {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func Test_Load(t *testing.T) {
+	req := require.New(t)
+	cert := testCertificate(t)
+
+	req.NoError(Load(cert))
+	certPath := DockerCertPath("registry.example.com")
+	req.NotEmpty(certPath)
+
+	written, err := ioutil.ReadFile(filepath.Join(certPath, "ca.crt"))
+	req.NoError(err)
+	req.Equal(cert, written)
+
+	req.Error(Load([]byte("not a certificate")))
+	req.Equal(certPath, DockerCertPath("registry.example.com"))
+
+	req.NoError(Load(nil))
+	req.Empty(DockerCertPath("registry.example.com"))
+	req.NoFileExists(filepath.Join(certPath, "ca.crt"))
+}
+
+func Test_LoadClientCertificates(t *testing.T) {
+	req := require.New(t)
+	clientCert, clientKey := testKeyPair(t)
+
+	req.NoError(Load(testCertificate(t)))
+	req.NoError(LoadClientCertificates(map[string]clientcert.ClientCertificate{
+		"registry.example.com": {Cert: string(clientCert), Key: string(clientKey)},
+	}))
+	defer func() {
+		req.NoError(LoadClientCertificates(nil))
+		req.NoError(Load(nil))
+	}()
+
+	req.NotNil(clientcert.Get("registry.example.com:443"))
+	req.Nil(clientcert.Get("other.example.com"))
+
+	hostPath := DockerCertPath("registry.example.com:443")
+	req.NotEqual(DockerCertPath("other.example.com"), hostPath)
+	for _, name := range []string{"ca.crt", "client.cert", "client.key"} {
+		req.FileExists(filepath.Join(hostPath, name))
+	}
+
+	req.Error(LoadClientCertificates(map[string]clientcert.ClientCertificate{
+		"registry.example.com": {Cert: string(clientCert), Key: "invalid"},
+	}))
+	req.NotNil(clientcert.Get("registry.example.com"))
+
+	req.NoError(LoadClientCertificates(nil))
+	req.Nil(clientcert.Get("registry.example.com"))
+	req.NoDirExists(hostPath)
+}
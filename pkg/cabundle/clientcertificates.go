@@ -0,0 +1,104 @@
+package cabundle
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/pkg/errors"
+	"github.com/replicatedhq/kots/pkg/clientcert"
+	kotsadmtypes "github.com/replicatedhq/kots/pkg/kotsadm/types"
+	corev1 "k8s.io/api/core/v1"
+	kuberneteserrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	// ClientCertificatesSecretName is the secret in the kotsadm namespace that holds the client certificate of each host
+	ClientCertificatesSecretName = "kotsadm-client-certificates"
+	clientCertificatesSecretKey  = "certificates.json"
+)
+
+// GetClientCertificates returns the client certificates stored in the namespace, keyed by host
+func GetClientCertificates(clientset kubernetes.Interface, namespace string) (map[string]clientcert.ClientCertificate, error) {
+	certificates := map[string]clientcert.ClientCertificate{}
+
+	secret, err := clientset.CoreV1().Secrets(namespace).Get(context.TODO(), ClientCertificatesSecretName, metav1.GetOptions{})
+	if kuberneteserrors.IsNotFound(err) {
+		return certificates, nil
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get secret")
+	}
+
+	if data := secret.Data[clientCertificatesSecretKey]; len(data) > 0 {
+		if err := json.Unmarshal(data, &certificates); err != nil {
+			return nil, errors.Wrap(err, "failed to unmarshal client certificates")
+		}
+	}
+
+	return certificates, nil
+}
+
+// SetClientCertificate stores the client certificate for the host in the namespace and returns the client
+// certificates of all hosts. A nil certificate removes it.
+func SetClientCertificate(clientset kubernetes.Interface, namespace string, host string, certificate *clientcert.ClientCertificate) (map[string]clientcert.ClientCertificate, error) {
+	if host == "" {
+		return nil, errors.New("host is required")
+	}
+	if certificate != nil {
+		if _, err := certificate.TLSCertificate(); err != nil {
+			return nil, errors.Wrap(err, "invalid client certificate")
+		}
+	}
+
+	certificates, err := GetClientCertificates(clientset, namespace)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get client certificates")
+	}
+	if certificate == nil {
+		delete(certificates, host)
+	} else {
+		certificates[host] = *certificate
+	}
+
+	data, err := json.Marshal(certificates)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal client certificates")
+	}
+
+	existing, err := clientset.CoreV1().Secrets(namespace).Get(context.TODO(), ClientCertificatesSecretName, metav1.GetOptions{})
+	if err != nil {
+		if !kuberneteserrors.IsNotFound(err) {
+			return nil, errors.Wrap(err, "failed to get secret")
+		}
+
+		secret := &corev1.Secret{
+			TypeMeta: metav1.TypeMeta{
+				APIVersion: "v1",
+				Kind:       "Secret",
+			},
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      ClientCertificatesSecretName,
+				Namespace: namespace,
+				Labels:    kotsadmtypes.GetKotsadmLabels(),
+			},
+			Data: map[string][]byte{
+				clientCertificatesSecretKey: data,
+			},
+		}
+		if _, err := clientset.CoreV1().Secrets(namespace).Create(context.TODO(), secret, metav1.CreateOptions{}); err != nil {
+			return nil, errors.Wrap(err, "failed to create secret")
+		}
+		return certificates, nil
+	}
+
+	if existing.Data == nil {
+		existing.Data = map[string][]byte{}
+	}
+	existing.Data[clientCertificatesSecretKey] = data
+	if _, err := clientset.CoreV1().Secrets(namespace).Update(context.TODO(), existing, metav1.UpdateOptions{}); err != nil {
+		return nil, errors.Wrap(err, "failed to update secret")
+	}
+	return certificates, nil
+}
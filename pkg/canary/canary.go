@@ -0,0 +1,276 @@
+package canary
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/pkg/errors"
+	apptypes "github.com/replicatedhq/kots/pkg/app/types"
+	"github.com/replicatedhq/kots/pkg/canary/types"
+	deployhistorytypes "github.com/replicatedhq/kots/pkg/deployhistory/types"
+	"github.com/replicatedhq/kots/pkg/logger"
+	postdeploytesttypes "github.com/replicatedhq/kots/pkg/postdeploytest/types"
+	"github.com/replicatedhq/kots/pkg/store"
+	"github.com/replicatedhq/kots/pkg/version"
+	"github.com/segmentio/ksuid"
+)
+
+const (
+	// TestGracePeriod is how long a canary keeps baking after its bake time for its post deploy tests to finish
+	TestGracePeriod = 15 * time.Minute
+)
+
+func Start() error {
+	logger.Debug("starting canary deploy loop")
+
+	startLoop(canaryLoop, 60)
+
+	return nil
+}
+
+func startLoop(fn func(), intervalInSeconds time.Duration) {
+	go func() {
+		for {
+			fn()
+			time.Sleep(time.Second * intervalInSeconds)
+		}
+	}()
+}
+
+func canaryLoop() {
+	appsList, err := store.GetStore().ListInstalledApps()
+	if err != nil {
+		logger.Error(errors.Wrap(err, "failed to list installed apps for canary deploys"))
+		return
+	}
+
+	for _, a := range appsList {
+		if err := handleApp(a); err != nil {
+			logger.Error(errors.Wrapf(err, "failed to handle canary deploys for app %s", a.ID))
+		}
+	}
+}
+
+// DeployVersion deploys the sequence to all downstreams of the app, or only to the canary downstream if the app has
+// a canary policy. The canary is then promoted or rolled back by the canary loop.
+func DeployVersion(a *apptypes.App, sequence int64, deployer deployhistorytypes.Deployer) error {
+	if !a.CanaryPolicy.Enabled() {
+		return version.DeployVersion(a.ID, sequence, deployer)
+	}
+
+	return startCanary(a, sequence, deployer)
+}
+
+/*
+* The app_canary_deploy table holds the canary deploys of each app, at most one of which is baking. A deploy of a
+* new version while a canary is baking supersedes that canary, the new canary rolls back to the version that was
+* deployed before the superseded one.
+*
+* A baking canary is checked on each pass of the loop. It is rolled back as soon as the deploy or a post deploy test
+* fails on the canary downstream, and promoted to the other downstreams once its bake time has passed if it deployed
+* and its post deploy tests passed on the canary downstream. The app status is not used, it is not reported per
+* downstream.
+ */
+func handleApp(a *apptypes.App) error {
+	deploys, err := store.GetStore().ListCanaryDeploys(a.ID)
+	if err != nil {
+		return errors.Wrap(err, "failed to list canary deploys")
+	}
+
+	for _, deploy := range deploys {
+		if deploy.Status == types.StatusBaking {
+			return progressCanary(deploy)
+		}
+	}
+
+	return nil
+}
+
+func startCanary(a *apptypes.App, sequence int64, deployer deployhistorytypes.Deployer) error {
+	clusterID := a.CanaryPolicy.ClusterID
+
+	previousSequence, err := store.GetStore().GetCurrentSequence(a.ID, clusterID)
+	if err != nil {
+		return errors.Wrap(err, "failed to get current sequence of canary downstream")
+	}
+
+	deploys, err := store.GetStore().ListCanaryDeploys(a.ID)
+	if err != nil {
+		return errors.Wrap(err, "failed to list canary deploys")
+	}
+	for _, deploy := range deploys {
+		if deploy.Status != types.StatusBaking {
+			continue
+		}
+		if deploy.Sequence == sequence {
+			return nil
+		}
+		previousSequence = deploy.PreviousSequence
+		message := fmt.Sprintf("superseded by sequence %d", sequence)
+		if err := finishCanary(deploy, types.StatusSuperseded, message); err != nil {
+			return errors.Wrap(err, "failed to supersede canary deploy")
+		}
+	}
+
+	if previousSequence == sequence {
+		// the version already runs on the canary downstream
+		return version.DeployVersion(a.ID, sequence, deployer)
+	}
+
+	if err := store.GetStore().DeleteDownstreamDeployStatus(a.ID, clusterID, sequence); err != nil {
+		return errors.Wrap(err, "failed to delete deploy status")
+	}
+
+	if err := version.DeployVersionToDownstream(a.ID, clusterID, sequence, deployer); err != nil {
+		return errors.Wrap(err, "failed to deploy version to canary downstream")
+	}
+
+	now := time.Now()
+	deploy := types.Deploy{
+		ID:               ksuid.New().String(),
+		AppID:            a.ID,
+		Sequence:         sequence,
+		ClusterID:        clusterID,
+		PreviousSequence: previousSequence,
+		Status:           types.StatusBaking,
+		StartedAt:        now,
+		BakeUntil:        now.Add(time.Duration(a.CanaryPolicy.BakeTimeSeconds) * time.Second),
+	}
+	if err := store.GetStore().CreateCanaryDeploy(deploy); err != nil {
+		return errors.Wrap(err, "failed to create canary deploy")
+	}
+
+	logger.Infof("Deployed sequence %d of app %s to canary downstream %s, baking until %s", sequence, a.ID, clusterID, deploy.BakeUntil.Format(time.RFC3339))
+
+	return nil
+}
+
+func progressCanary(deploy types.Deploy) error {
+	deployStatus, err := store.GetStore().GetStatusForVersion(deploy.AppID, deploy.ClusterID, deploy.Sequence)
+	if err != nil {
+		return errors.Wrap(err, "failed to get deploy status")
+	}
+
+	kotsKinds, err := version.GetKotsKinds(deploy.AppID, deploy.Sequence)
+	if err != nil {
+		return errors.Wrap(err, "failed to load kots kinds")
+	}
+	results, err := store.GetStore().GetDownstreamPostDeployTestResults(deploy.AppID, deploy.ClusterID, deploy.Sequence)
+	if err != nil {
+		return errors.Wrap(err, "failed to get post deploy test results")
+	}
+
+	d, reason := decide(deploy, time.Now(), deployStatus, len(kotsKinds.KotsApplication.Spec.PostDeployTests), results)
+	switch d {
+	case decisionPromote:
+		return promote(deploy)
+	case decisionRollback:
+		return rollback(deploy, reason)
+	}
+
+	return nil
+}
+
+type decision int
+
+const (
+	decisionWait decision = iota
+	decisionPromote
+	decisionRollback
+)
+
+// decide returns whether the canary keeps baking, is promoted or is rolled back, and the reason of a rollback.
+// deployStatus and results are the deploy status and post deploy test results of the version on the canary downstream.
+func decide(deploy types.Deploy, now time.Time, deployStatus string, testCount int, results []postdeploytesttypes.Result) (decision, string) {
+	if deployStatus == "failed" {
+		return decisionRollback, "deploy to the canary downstream failed"
+	}
+	for _, result := range results {
+		if result.Status == postdeploytesttypes.StatusFailed {
+			return decisionRollback, fmt.Sprintf("post deploy test %s failed: %s", result.Name, result.Message)
+		}
+	}
+
+	if now.Before(deploy.BakeUntil) {
+		return decisionWait, ""
+	}
+
+	if deployStatus != "deployed" {
+		return decisionRollback, "version was not deployed to the canary downstream by the end of the bake time"
+	}
+
+	passed := 0
+	for _, result := range results {
+		if result.Status == postdeploytesttypes.StatusPassed {
+			passed++
+		}
+	}
+	if passed < testCount {
+		if now.Before(deploy.BakeUntil.Add(TestGracePeriod)) {
+			return decisionWait, ""
+		}
+		return decisionRollback, "post deploy tests did not finish"
+	}
+
+	return decisionPromote, ""
+}
+
+// promote deploys the canary version to the other downstreams of the app, the canary downstream already runs it
+func promote(deploy types.Deploy) error {
+	downstreams, err := store.GetStore().ListDownstreamsForApp(deploy.AppID)
+	if err != nil {
+		return errors.Wrap(err, "failed to list downstreams")
+	}
+
+	deployer := deployhistorytypes.Deployer{DeployedBy: deployhistorytypes.DeployedByCanaryPromotion}
+	for _, downstream := range downstreams {
+		if downstream.ClusterID == deploy.ClusterID {
+			continue
+		}
+		if err := store.GetStore().DeleteDownstreamDeployStatus(deploy.AppID, downstream.ClusterID, deploy.Sequence); err != nil {
+			return errors.Wrap(err, "failed to delete deploy status")
+		}
+		if err := version.DeployVersionToDownstream(deploy.AppID, downstream.ClusterID, deploy.Sequence, deployer); err != nil {
+			return errors.Wrapf(err, "failed to deploy version to downstream %s", downstream.ClusterID)
+		}
+	}
+
+	logger.Infof("Promoted sequence %d of app %s from canary downstream %s", deploy.Sequence, deploy.AppID, deploy.ClusterID)
+
+	return finishCanary(deploy, types.StatusPromoted, "")
+}
+
+// rollback redeploys the previous version to the canary downstream. The canary downstream keeps the canary version
+// if no version was deployed before it.
+func rollback(deploy types.Deploy, reason string) error {
+	if deploy.PreviousSequence == -1 {
+		logger.Infof("Canary of sequence %d of app %s failed, but there is no version to roll back to: %s", deploy.Sequence, deploy.AppID, reason)
+		return finishCanary(deploy, types.StatusRolledBack, fmt.Sprintf("%s; no version to roll back to", reason))
+	}
+
+	logger.Infof("Canary of sequence %d of app %s failed, rolling back to sequence %d: %s", deploy.Sequence, deploy.AppID, deploy.PreviousSequence, reason)
+
+	if err := store.GetStore().DeleteDownstreamDeployStatus(deploy.AppID, deploy.ClusterID, deploy.PreviousSequence); err != nil {
+		return errors.Wrap(err, "failed to delete deploy status")
+	}
+
+	deployer := deployhistorytypes.Deployer{DeployedBy: deployhistorytypes.DeployedByCanaryRollback}
+	if err := version.DeployVersionToDownstream(deploy.AppID, deploy.ClusterID, deploy.PreviousSequence, deployer); err != nil {
+		return errors.Wrap(err, "failed to deploy previous version to canary downstream")
+	}
+
+	return finishCanary(deploy, types.StatusRolledBack, reason)
+}
+
+func finishCanary(deploy types.Deploy, status types.Status, message string) error {
+	finishedAt := time.Now()
+	deploy.Status = status
+	deploy.Message = message
+	deploy.FinishedAt = &finishedAt
+
+	if err := store.GetStore().UpdateCanaryDeploy(deploy); err != nil {
+		return errors.Wrap(err, "failed to update canary deploy")
+	}
+
+	return nil
+}
@@ -0,0 +1,104 @@
+package canary
+
+import (
+	"testing"
+	"time"
+
+	"github.com/replicatedhq/kots/pkg/canary/types"
+	postdeploytesttypes "github.com/replicatedhq/kots/pkg/postdeploytest/types"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_decide(t *testing.T) {
+	bakeUntil := time.Date(2021, 6, 1, 12, 0, 0, 0, time.UTC)
+	deploy := types.Deploy{Sequence: 3, BakeUntil: bakeUntil}
+
+	baking := bakeUntil.Add(-time.Minute)
+	baked := bakeUntil.Add(time.Minute)
+
+	passed := []postdeploytesttypes.Result{{Name: "smoke", Status: postdeploytesttypes.StatusPassed}}
+	running := []postdeploytesttypes.Result{{Name: "smoke", Status: postdeploytesttypes.StatusRunning}}
+	failed := []postdeploytesttypes.Result{{Name: "smoke", Status: postdeploytesttypes.StatusFailed, Message: "exit 1"}}
+
+	tests := []struct {
+		name         string
+		now          time.Time
+		deployStatus string
+		testCount    int
+		results      []postdeploytesttypes.Result
+		want         decision
+		wantReason   string
+	}{
+		{
+			name:         "baking",
+			now:          baking,
+			deployStatus: "deployed",
+			testCount:    1,
+			results:      passed,
+			want:         decisionWait,
+		},
+		{
+			name:         "failed deploy rolls back while baking",
+			now:          baking,
+			deployStatus: "failed",
+			want:         decisionRollback,
+			wantReason:   "deploy to the canary downstream failed",
+		},
+		{
+			name:         "failed test rolls back while baking",
+			now:          baking,
+			deployStatus: "deployed",
+			testCount:    1,
+			results:      failed,
+			want:         decisionRollback,
+			wantReason:   "post deploy test smoke failed: exit 1",
+		},
+		{
+			name:         "baked and tests passed",
+			now:          baked,
+			deployStatus: "deployed",
+			testCount:    1,
+			results:      passed,
+			want:         decisionPromote,
+		},
+		{
+			name:         "still deploying",
+			now:          baked,
+			deployStatus: "deploying",
+			want:         decisionRollback,
+			wantReason:   "version was not deployed to the canary downstream by the end of the bake time",
+		},
+		{
+			name:         "tests running",
+			now:          baked,
+			deployStatus: "deployed",
+			testCount:    1,
+			results:      running,
+			want:         decisionWait,
+		},
+		{
+			name:         "tests did not finish",
+			now:          bakeUntil.Add(TestGracePeriod),
+			deployStatus: "deployed",
+			testCount:    1,
+			results:      running,
+			want:         decisionRollback,
+			wantReason:   "post deploy tests did not finish",
+		},
+		{
+			name:         "baked without tests",
+			now:          baked,
+			deployStatus: "deployed",
+			want:         decisionPromote,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			req := require.New(t)
+
+			got, reason := decide(deploy, test.now, test.deployStatus, test.testCount, test.results)
+			req.Equal(test.want, got)
+			req.Equal(test.wantReason, reason)
+		})
+	}
+}
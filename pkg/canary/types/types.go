@@ -0,0 +1,32 @@
+package types
+
+import (
+	"time"
+)
+
+type Status string
+
+const (
+	// StatusBaking canaries run on the canary downstream until their bake time has passed
+	StatusBaking     Status = "baking"
+	StatusPromoted   Status = "promoted"
+	StatusRolledBack Status = "rolled_back"
+	// StatusSuperseded canaries were replaced by the canary of a newer version before they were promoted
+	StatusSuperseded Status = "superseded"
+)
+
+// Deploy is the deploy of a version to the canary downstream of an app, before it is deployed to the other
+// downstreams
+type Deploy struct {
+	ID        string `json:"id"`
+	AppID     string `json:"appId"`
+	Sequence  int64  `json:"sequence"`
+	ClusterID string `json:"clusterId"`
+	// PreviousSequence is the sequence the canary downstream is rolled back to, -1 if no version was deployed
+	PreviousSequence int64      `json:"previousSequence"`
+	Status           Status     `json:"status"`
+	Message          string     `json:"message,omitempty"`
+	StartedAt        time.Time  `json:"startedAt"`
+	BakeUntil        time.Time  `json:"bakeUntil"`
+	FinishedAt       *time.Time `json:"finishedAt,omitempty"`
+}
@@ -0,0 +1,69 @@
+package clientcert
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"net"
+	"sync/atomic"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// ClientCertificate is a pem encoded certificate and private key presented to an endpoint that requires mutual tls
+type ClientCertificate struct {
+	Cert string `json:"cert"`
+	Key  string `json:"key"`
+}
+
+// TLSCertificate parses the certificate and key
+func (c ClientCertificate) TLSCertificate() (tls.Certificate, error) {
+	cert, err := tls.X509KeyPair([]byte(c.Cert), []byte(c.Key))
+	if err != nil {
+		return tls.Certificate{}, errors.Wrap(err, "failed to parse key pair")
+	}
+	return cert, nil
+}
+
+// NotAfter returns the expiration of the leaf certificate
+func (c ClientCertificate) NotAfter() (time.Time, error) {
+	cert, err := c.TLSCertificate()
+	if err != nil {
+		return time.Time{}, err
+	}
+	leaf, err := x509.ParseCertificate(cert.Certificate[0])
+	if err != nil {
+		return time.Time{}, errors.Wrap(err, "failed to parse certificate")
+	}
+	return leaf.NotAfter, nil
+}
+
+var loaded atomic.Value // map[string]tls.Certificate
+
+// Load replaces the client certificates used for each host. Hosts are matched with and without the port.
+func Load(certificates map[string]ClientCertificate) error {
+	parsed := map[string]tls.Certificate{}
+	for host, c := range certificates {
+		cert, err := c.TLSCertificate()
+		if err != nil {
+			return errors.Wrapf(err, "invalid client certificate for %s", host)
+		}
+		parsed[host] = cert
+	}
+	loaded.Store(parsed)
+	return nil
+}
+
+// Get returns the client certificate to present to the host, or nil if there is none
+func Get(host string) *tls.Certificate {
+	certificates, _ := loaded.Load().(map[string]tls.Certificate)
+	if cert, ok := certificates[host]; ok {
+		return &cert
+	}
+	if hostname, _, err := net.SplitHostPort(host); err == nil {
+		if cert, ok := certificates[hostname]; ok {
+			return &cert
+		}
+	}
+	return nil
+}
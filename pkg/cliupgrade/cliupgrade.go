@@ -0,0 +1,183 @@
+package cliupgrade
+
+import (
+	"archive/tar"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// ReleasesURL is where the release archives and checksums of the cli are downloaded from
+	ReleasesURL = "https://github.com/replicatedhq/kots/releases/download"
+	// checksumsFile is published with each release, it lists the sha256 of every archive
+	checksumsFile = "checksums.txt"
+)
+
+type Options struct {
+	// Version is the version to install, e.g. "v1.50.0"
+	Version string
+	// ReleasesURL defaults to the github releases of the project
+	ReleasesURL string
+	// ExecutablePath is the binary to replace, the running executable by default
+	ExecutablePath string
+	HTTPClient     *http.Client
+}
+
+// ArchiveName returns the name of the release archive for the platform
+func ArchiveName(goos string, goarch string) string {
+	return fmt.Sprintf("kots_%s_%s.tar.gz", goos, goarch)
+}
+
+// Upgrade downloads the release of the cli for this platform, verifies the archive against the checksums of the
+// release, and replaces the executable with the binary in it. The executable is only replaced once the new binary
+// is completely written next to it.
+func Upgrade(opts Options) error {
+	if opts.Version == "" {
+		return errors.New("version is required")
+	}
+	if !strings.HasPrefix(opts.Version, "v") {
+		opts.Version = "v" + opts.Version
+	}
+	if opts.ReleasesURL == "" {
+		opts.ReleasesURL = ReleasesURL
+	}
+	if opts.HTTPClient == nil {
+		opts.HTTPClient = &http.Client{Timeout: 5 * time.Minute}
+	}
+	if opts.ExecutablePath == "" {
+		executablePath, err := os.Executable()
+		if err != nil {
+			return errors.Wrap(err, "failed to get executable path")
+		}
+		opts.ExecutablePath = executablePath
+	}
+	executablePath, err := filepath.EvalSymlinks(opts.ExecutablePath)
+	if err != nil {
+		return errors.Wrap(err, "failed to resolve executable path")
+	}
+
+	archiveName := ArchiveName(runtime.GOOS, runtime.GOARCH)
+	releaseURL := fmt.Sprintf("%s/%s", strings.TrimSuffix(opts.ReleasesURL, "/"), opts.Version)
+
+	checksums, err := download(opts.HTTPClient, fmt.Sprintf("%s/%s", releaseURL, checksumsFile))
+	if err != nil {
+		return errors.Wrap(err, "failed to download checksums")
+	}
+	wantChecksum, err := findChecksum(checksums, archiveName)
+	if err != nil {
+		return err
+	}
+
+	archive, err := download(opts.HTTPClient, fmt.Sprintf("%s/%s", releaseURL, archiveName))
+	if err != nil {
+		return errors.Wrap(err, "failed to download release archive")
+	}
+	sum := sha256.Sum256(archive)
+	if gotChecksum := hex.EncodeToString(sum[:]); gotChecksum != wantChecksum {
+		return errors.Errorf("checksum of %s is %s, expected %s", archiveName, gotChecksum, wantChecksum)
+	}
+
+	binary, err := extractBinary(archive, "kots")
+	if err != nil {
+		return errors.Wrap(err, "failed to extract binary")
+	}
+
+	if err := replaceExecutable(executablePath, binary); err != nil {
+		return errors.Wrap(err, "failed to replace executable")
+	}
+
+	return nil
+}
+
+func download(client *http.Client, url string) ([]byte, error) {
+	resp, err := client.Get(url)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to execute request")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, errors.Errorf("unexpected status code %d from %s", resp.StatusCode, url)
+	}
+
+	return ioutil.ReadAll(resp.Body)
+}
+
+// findChecksum returns the sha256 of the file in a checksums file, which has a "<sha256>  <file>" line per file
+func findChecksum(checksums []byte, fileName string) (string, error) {
+	scanner := bufio.NewScanner(bytes.NewReader(checksums))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 2 && fields[1] == fileName {
+			return strings.ToLower(fields[0]), nil
+		}
+	}
+	return "", errors.Errorf("no checksum found for %s", fileName)
+}
+
+func extractBinary(archive []byte, name string) ([]byte, error) {
+	gzipReader, err := gzip.NewReader(bytes.NewReader(archive))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create gzip reader")
+	}
+	defer gzipReader.Close()
+
+	tarReader := tar.NewReader(gzipReader)
+	for {
+		header, err := tarReader.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to read archive")
+		}
+		if header.Typeflag != tar.TypeReg || filepath.Base(header.Name) != name {
+			continue
+		}
+		return ioutil.ReadAll(tarReader)
+	}
+
+	return nil, errors.Errorf("%s not found in archive", name)
+}
+
+// replaceExecutable writes the binary to a temp file in the directory of the executable and renames it over the
+// executable, so that the executable is never partially written
+func replaceExecutable(executablePath string, binary []byte) error {
+	tmpFile, err := ioutil.TempFile(filepath.Dir(executablePath), ".kots-upgrade-")
+	if err != nil {
+		return errors.Wrap(err, "failed to create temp file")
+	}
+	defer os.Remove(tmpFile.Name())
+
+	if _, err := tmpFile.Write(binary); err != nil {
+		tmpFile.Close()
+		return errors.Wrap(err, "failed to write binary")
+	}
+	if err := tmpFile.Close(); err != nil {
+		return errors.Wrap(err, "failed to close temp file")
+	}
+
+	if err := os.Chmod(tmpFile.Name(), 0755); err != nil {
+		return errors.Wrap(err, "failed to set file mode")
+	}
+
+	if err := os.Rename(tmpFile.Name(), executablePath); err != nil {
+		return errors.Wrap(err, "failed to rename binary")
+	}
+
+	return nil
+}
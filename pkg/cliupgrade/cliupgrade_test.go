@@ -0,0 +1,124 @@
+package cliupgrade
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Upgrade(t *testing.T) {
+	archive := makeArchive(t, "kots", []byte("new binary"))
+	sum := sha256.Sum256(archive)
+	archiveName := ArchiveName(runtime.GOOS, runtime.GOARCH)
+
+	tests := []struct {
+		name      string
+		checksums string
+		wantErr   string
+		want      string
+	}{
+		{
+			name:      "checksum matches",
+			checksums: fmt.Sprintf("%s  %s\nabc  kots_other_arch.tar.gz\n", hex.EncodeToString(sum[:]), archiveName),
+			want:      "new binary",
+		},
+		{
+			name:      "checksum does not match",
+			checksums: fmt.Sprintf("%s  %s\n", "0000", archiveName),
+			wantErr:   "checksum of",
+			want:      "old binary",
+		},
+		{
+			name:      "no checksum",
+			checksums: "abc  kots_other_arch.tar.gz\n",
+			wantErr:   "no checksum found",
+			want:      "old binary",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			req := require.New(t)
+
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				switch r.URL.Path {
+				case "/v1.50.0/checksums.txt":
+					w.Write([]byte(test.checksums))
+				case "/v1.50.0/" + archiveName:
+					w.Write(archive)
+				default:
+					http.NotFound(w, r)
+				}
+			}))
+			defer server.Close()
+
+			dir, err := ioutil.TempDir("", "kots-upgrade")
+			req.NoError(err)
+			defer os.RemoveAll(dir)
+
+			executablePath := filepath.Join(dir, "kots")
+			req.NoError(ioutil.WriteFile(executablePath, []byte("old binary"), 0755))
+
+			err = Upgrade(Options{
+				Version:        "1.50.0",
+				ReleasesURL:    server.URL,
+				ExecutablePath: executablePath,
+			})
+			if test.wantErr != "" {
+				req.Error(err)
+				req.Contains(err.Error(), test.wantErr)
+			} else {
+				req.NoError(err)
+			}
+
+			contents, err := ioutil.ReadFile(executablePath)
+			req.NoError(err)
+			req.Equal(test.want, string(contents))
+
+			// the temp file is removed
+			files, err := ioutil.ReadDir(dir)
+			req.NoError(err)
+			req.Len(files, 1)
+		})
+	}
+}
+
+func makeArchive(t *testing.T, name string, contents []byte) []byte {
+	buf := bytes.NewBuffer(nil)
+	gzipWriter := gzip.NewWriter(buf)
+	tarWriter := tar.NewWriter(gzipWriter)
+
+	for _, file := range []struct {
+		name     string
+		contents []byte
+	}{
+		{name: "README.md", contents: []byte("readme")},
+		{name: name, contents: contents},
+	} {
+		err := tarWriter.WriteHeader(&tar.Header{
+			Name:     file.name,
+			Mode:     0755,
+			Size:     int64(len(file.contents)),
+			Typeflag: tar.TypeReg,
+		})
+		require.NoError(t, err)
+		_, err = tarWriter.Write(file.contents)
+		require.NoError(t, err)
+	}
+
+	require.NoError(t, tarWriter.Close())
+	require.NoError(t, gzipWriter.Close())
+
+	return buf.Bytes()
+}
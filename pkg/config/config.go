@@ -53,6 +53,7 @@ func templateConfigObjects(configSpec *kotsv1beta1.Config, configValues map[stri
 	}
 
 	ApplyValuesToConfig(configSpec, configVals)
+	ApplyLicenseRequirements(configSpec, license)
 	configDocWithData, err := marshalFunc(configSpec)
 	if err != nil {
 		return "", errors.Wrap(err, "failed to marshal config")
@@ -134,6 +135,50 @@ func ApplyValuesToConfig(config *kotsv1beta1.Config, values map[string]template.
 	}
 }
 
+// ApplyLicenseRequirements sets "when" to false on groups and items whose license requirements
+// are not met by the license, so they are hidden and treated as not required everywhere that
+// already honors "when".
+func ApplyLicenseRequirements(config *kotsv1beta1.Config, license *kotsv1beta1.License) {
+	for idxG, g := range config.Spec.Groups {
+		if !licenseRequirementsMet(g.LicenseRequirements, license) {
+			config.Spec.Groups[idxG].When = "false"
+		}
+		for idxI, i := range g.Items {
+			if !licenseRequirementsMet(i.LicenseRequirements, license) {
+				config.Spec.Groups[idxG].Items[idxI].When = "false"
+			}
+		}
+	}
+}
+
+// LicenseGatedItems returns the names of the items that are hidden because their license
+// requirements, or the requirements of their group, are not met by the license.
+func LicenseGatedItems(config *kotsv1beta1.Config, license *kotsv1beta1.License) []string {
+	gatedItems := []string{}
+	for _, g := range config.Spec.Groups {
+		groupMet := licenseRequirementsMet(g.LicenseRequirements, license)
+		for _, i := range g.Items {
+			if !groupMet || !licenseRequirementsMet(i.LicenseRequirements, license) {
+				gatedItems = append(gatedItems, i.Name)
+			}
+		}
+	}
+	return gatedItems
+}
+
+func licenseRequirementsMet(requirements []kotsv1beta1.ConfigLicenseRequirement, license *kotsv1beta1.License) bool {
+	for _, requirement := range requirements {
+		expected := requirement.Value
+		if expected == "" {
+			expected = "true"
+		}
+		if template.LicenseFieldValue(license, requirement.LicenseField) != expected {
+			return false
+		}
+	}
+	return true
+}
+
 // MarshalConfig runs the same code path as the k8s json->yaml serializer, but uses a different yaml library for those parts
 // first, the object is marshalled to json
 // second, the json is unmarshalled to an object as yaml
@@ -273,3 +273,56 @@ status: {}
 		})
 	}
 }
+
+func TestApplyLicenseRequirements(t *testing.T) {
+	req := require.New(t)
+
+	license := &kotsv1beta1.License{
+		Spec: kotsv1beta1.LicenseSpec{
+			LicenseType: "prod",
+			Entitlements: map[string]kotsv1beta1.EntitlementField{
+				"has_sso": {
+					Value: kotsv1beta1.EntitlementValue{Type: kotsv1beta1.Bool, BoolVal: true},
+				},
+				"tier": {
+					Value: kotsv1beta1.EntitlementValue{Type: kotsv1beta1.String, StrVal: "basic"},
+				},
+			},
+		},
+	}
+
+	config := &kotsv1beta1.Config{
+		Spec: kotsv1beta1.ConfigSpec{
+			Groups: []kotsv1beta1.ConfigGroup{
+				{
+					Name: "sso",
+					LicenseRequirements: []kotsv1beta1.ConfigLicenseRequirement{
+						{LicenseField: "has_sso"},
+					},
+					Items: []kotsv1beta1.ConfigItem{
+						{Name: "sso_url"},
+						{
+							Name: "sso_premium",
+							LicenseRequirements: []kotsv1beta1.ConfigLicenseRequirement{
+								{LicenseField: "tier", Value: "premium"},
+							},
+						},
+					},
+				},
+				{
+					Name: "airgap",
+					LicenseRequirements: []kotsv1beta1.ConfigLicenseRequirement{
+						{LicenseField: "isAirgapSupported"},
+					},
+				},
+			},
+		},
+	}
+
+	ApplyLicenseRequirements(config, license)
+
+	req.Equal("", string(config.Spec.Groups[0].When))
+	req.Equal("", string(config.Spec.Groups[0].Items[0].When))
+	req.Equal("false", string(config.Spec.Groups[0].Items[1].When))
+	req.Equal("false", string(config.Spec.Groups[1].When))
+}
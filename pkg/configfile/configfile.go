@@ -0,0 +1,168 @@
+package configfile
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/replicatedhq/kots/pkg/logger"
+	"github.com/replicatedhq/kots/pkg/store"
+)
+
+const (
+	// ItemType is the config item type for files that are stored out-of-line instead of being inlined into config values
+	ItemType = "large_file"
+
+	// RefPrefix is the prefix of config values that reference a stored file,
+	// e.g. configfile://<sha256>/<filename>
+	RefPrefix = "configfile://"
+
+	// DefaultMaxSize is the largest file that can be uploaded unless overridden by CONFIG_FILE_MAX_SIZE
+	DefaultMaxSize = int64(1 << 30)
+)
+
+var (
+	ErrTooLarge = errors.New("config file is larger than the maximum allowed size")
+
+	checksumRegex = regexp.MustCompile("^[a-f0-9]{64}$")
+)
+
+// ConfigFile describes a stored config file
+type ConfigFile struct {
+	Ref      string `json:"ref"`
+	Filename string `json:"filename"`
+	Checksum string `json:"checksum"`
+	Size     int64  `json:"size"`
+}
+
+// GetMaxSize returns the maximum size in bytes of an uploaded config file
+func GetMaxSize() int64 {
+	maxSize, err := strconv.ParseInt(os.Getenv("CONFIG_FILE_MAX_SIZE"), 10, 64)
+	if err != nil || maxSize <= 0 {
+		return DefaultMaxSize
+	}
+	return maxSize
+}
+
+// IsRef returns true if the config value references a stored file
+func IsRef(value string) bool {
+	return strings.HasPrefix(value, RefPrefix)
+}
+
+// ParseRef returns the checksum and filename from a config file reference
+func ParseRef(ref string) (string, string, error) {
+	if !IsRef(ref) {
+		return "", "", errors.Errorf("%q is not a config file reference", ref)
+	}
+
+	parts := strings.SplitN(strings.TrimPrefix(ref, RefPrefix), "/", 2)
+	checksum := parts[0]
+	if !checksumRegex.MatchString(checksum) {
+		return "", "", errors.Errorf("invalid checksum in config file reference %q", ref)
+	}
+
+	filename := ""
+	if len(parts) == 2 {
+		filename = parts[1]
+	}
+
+	return checksum, filename, nil
+}
+
+func newRef(checksum string, filename string) string {
+	// only the base name is kept so that the reference can always be parsed back
+	filename = path.Base(strings.ReplaceAll(filename, "\\", "/"))
+	if filename == "." || filename == "/" {
+		filename = ""
+	}
+	return fmt.Sprintf("%s%s/%s", RefPrefix, checksum, filename)
+}
+
+// Upload stores the contents of r and returns the reference to put in config values.
+// Files over the maximum size are rejected with ErrTooLarge.
+func Upload(filename string, r io.Reader) (*ConfigFile, error) {
+	tmpFile, err := ioutil.TempFile("", "kotsadm-configfile")
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create temp file")
+	}
+	defer os.Remove(tmpFile.Name())
+	defer tmpFile.Close()
+
+	maxSize := GetMaxSize()
+	hash := sha256.New()
+
+	// read one byte past the limit to be able to tell that the file is too large
+	size, err := io.Copy(io.MultiWriter(tmpFile, hash), io.LimitReader(r, maxSize+1))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to write temp file")
+	}
+	if size > maxSize {
+		return nil, ErrTooLarge
+	}
+	if err := tmpFile.Close(); err != nil {
+		return nil, errors.Wrap(err, "failed to close temp file")
+	}
+
+	checksum := fmt.Sprintf("%x", hash.Sum(nil))
+	if err := store.GetStore().CreateConfigFile(checksum, tmpFile.Name()); err != nil {
+		return nil, errors.Wrap(err, "failed to store config file")
+	}
+
+	ref := newRef(checksum, filename)
+	_, refFilename, _ := ParseRef(ref)
+
+	return &ConfigFile{
+		Ref:      ref,
+		Filename: refFilename,
+		Checksum: checksum,
+		Size:     size,
+	}, nil
+}
+
+// Read returns the contents of the referenced file after verifying its checksum
+func Read(ref string) ([]byte, error) {
+	checksum, _, err := ParseRef(ref)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to parse ref")
+	}
+
+	rc, err := store.GetStore().GetConfigFile(checksum)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get config file")
+	}
+	defer rc.Close()
+
+	contents, err := ioutil.ReadAll(io.LimitReader(rc, GetMaxSize()+1))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read config file")
+	}
+
+	if actual := fmt.Sprintf("%x", sha256.Sum256(contents)); actual != checksum {
+		return nil, errors.Errorf("config file checksum mismatch: expected %s, got %s", checksum, actual)
+	}
+
+	return contents, nil
+}
+
+// ReadIfRef returns the contents of the referenced file if value is a config file reference.
+// It is registered with the template package so that ConfigOptionData resolves references.
+func ReadIfRef(value string) ([]byte, bool) {
+	if !IsRef(value) {
+		return nil, false
+	}
+
+	contents, err := Read(value)
+	if err != nil {
+		logger.Error(errors.Wrapf(err, "failed to read config file %s", value))
+		return nil, true
+	}
+
+	return contents, true
+}
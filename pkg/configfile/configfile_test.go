@@ -0,0 +1,64 @@
+package configfile
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ParseRef(t *testing.T) {
+	checksum := strings.Repeat("a", 64)
+
+	tests := []struct {
+		name         string
+		ref          string
+		wantChecksum string
+		wantFilename string
+		wantErr      bool
+	}{
+		{
+			name:         "with filename",
+			ref:          newRef(checksum, "ca-bundle.pem"),
+			wantChecksum: checksum,
+			wantFilename: "ca-bundle.pem",
+		},
+		{
+			name:         "filename path is dropped",
+			ref:          newRef(checksum, "C:\\certs\\ca-bundle.pem"),
+			wantChecksum: checksum,
+			wantFilename: "ca-bundle.pem",
+		},
+		{
+			name:         "without filename",
+			ref:          newRef(checksum, ""),
+			wantChecksum: checksum,
+			wantFilename: "",
+		},
+		{
+			name:    "not a reference",
+			ref:     "Y29udGVudHM=",
+			wantErr: true,
+		},
+		{
+			name:    "invalid checksum",
+			ref:     RefPrefix + "../../etc/passwd",
+			wantErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			req := require.New(t)
+
+			checksum, filename, err := ParseRef(test.ref)
+			if test.wantErr {
+				req.Error(err)
+				return
+			}
+			req.NoError(err)
+			req.Equal(test.wantChecksum, checksum)
+			req.Equal(test.wantFilename, filename)
+		})
+	}
+}
@@ -0,0 +1,277 @@
+package connectivity
+
+import (
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/replicatedhq/kots/pkg/connectivity/types"
+	"github.com/replicatedhq/kots/pkg/gitops"
+	"github.com/replicatedhq/kots/pkg/logger"
+	"github.com/replicatedhq/kots/pkg/store"
+)
+
+const (
+	DefaultReplicatedEndpoint = "https://replicated.app"
+
+	checkTimeout = 10 * time.Second
+)
+
+type target struct {
+	name    string
+	address string
+	// tcp targets are dialed, the others are requested over http
+	tcp bool
+}
+
+// Check tests the reachability of the license endpoints of the apps, their registries, the gitops remote and the
+// proxy. Checks run concurrently, an unreachable target is reported in its check and not returned as an error.
+func Check() (*types.Report, error) {
+	targets, err := listTargets()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list targets")
+	}
+
+	report := types.Report{
+		Checks: make([]types.Check, len(targets)),
+	}
+	if proxyURL := getProxyURL(); proxyURL != nil {
+		report.Proxy = redactURL(proxyURL)
+	}
+
+	client := &http.Client{
+		Timeout: checkTimeout,
+		Transport: &http.Transport{
+			Proxy: http.ProxyFromEnvironment,
+		},
+		// the first response is enough to know that the target is reachable
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+
+	var wg sync.WaitGroup
+	for i, t := range targets {
+		wg.Add(1)
+		go func(i int, t target) {
+			defer wg.Done()
+			if t.tcp {
+				report.Checks[i] = checkTCP(t.name, t.address)
+			} else {
+				report.Checks[i] = checkHTTP(client, t.name, t.address)
+			}
+		}(i, t)
+	}
+	wg.Wait()
+
+	return &report, nil
+}
+
+func listTargets() ([]target, error) {
+	targets := []target{}
+	seen := map[string]bool{}
+	add := func(t target) {
+		key := fmt.Sprintf("%s/%s", t.name, t.address)
+		if seen[key] {
+			return
+		}
+		seen[key] = true
+		targets = append(targets, t)
+	}
+
+	apps, err := store.GetStore().ListInstalledApps()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list installed apps")
+	}
+
+	for _, a := range apps {
+		license, err := store.GetStore().GetLatestLicenseForApp(a.ID)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to get license for app %s", a.Slug)
+		}
+		endpoint := DefaultReplicatedEndpoint
+		if license != nil && license.Spec.Endpoint != "" {
+			endpoint = license.Spec.Endpoint
+		}
+		add(target{name: types.TargetReplicated, address: endpoint})
+
+		registrySettings, err := store.GetStore().GetRegistryDetailsForApp(a.ID)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to get registry settings for app %s", a.Slug)
+		}
+		if registrySettings.Hostname != "" {
+			add(target{name: types.TargetRegistry, address: fmt.Sprintf("https://%s/v2/", registrySettings.Hostname)})
+		}
+	}
+	if len(apps) == 0 {
+		add(target{name: types.TargetReplicated, address: DefaultReplicatedEndpoint})
+	}
+
+	gitOpsConfig, err := gitops.GetGitOps()
+	if err != nil {
+		logger.Debugf("failed to get gitops config: %v", err)
+	} else if gitOpsConfig.Enabled {
+		address, err := gitOpsAddress(gitOpsConfig)
+		if err != nil {
+			logger.Debugf("failed to get gitops address: %v", err)
+		} else {
+			add(target{name: types.TargetGitOps, address: address, tcp: true})
+		}
+	}
+
+	if proxyURL := getProxyURL(); proxyURL != nil {
+		add(target{name: types.TargetProxy, address: proxyAddress(proxyURL), tcp: true})
+	}
+
+	return targets, nil
+}
+
+// gitOpsAddress returns the host and port of the ssh server that gitops commits are pushed to
+func gitOpsAddress(config gitops.GlobalGitOpsConfig) (string, error) {
+	port := config.SSHPort
+	if port == "" {
+		port = "22"
+	}
+
+	switch config.Provider {
+	case "github":
+		return net.JoinHostPort("github.com", "22"), nil
+	case "gitlab":
+		return net.JoinHostPort("gitlab.com", "22"), nil
+	case "bitbucket":
+		return net.JoinHostPort("bitbucket.org", "22"), nil
+	case "azure_devops":
+		return net.JoinHostPort("ssh.dev.azure.com", "22"), nil
+	case "bitbucket_server", "github_enterprise", "gitlab_enterprise":
+		if config.Hostname == "" {
+			return "", errors.Errorf("hostname is required for provider %s", config.Provider)
+		}
+		return net.JoinHostPort(config.Hostname, port), nil
+	}
+
+	return "", errors.Errorf("unsupported provider type: %s", config.Provider)
+}
+
+func getProxyURL() *url.URL {
+	for _, key := range []string{"HTTPS_PROXY", "https_proxy", "HTTP_PROXY", "http_proxy"} {
+		value := os.Getenv(key)
+		if value == "" {
+			continue
+		}
+		proxyURL, err := url.Parse(value)
+		if err != nil || proxyURL.Host == "" {
+			// like the http transport, a proxy without a scheme is an http proxy
+			proxyURL, err = url.Parse("http://" + value)
+			if err != nil {
+				logger.Debugf("failed to parse proxy %s: %v", key, err)
+				continue
+			}
+		}
+		return proxyURL
+	}
+	return nil
+}
+
+func proxyAddress(proxyURL *url.URL) string {
+	if proxyURL.Port() != "" {
+		return proxyURL.Host
+	}
+	if proxyURL.Scheme == "https" {
+		return net.JoinHostPort(proxyURL.Hostname(), "443")
+	}
+	return net.JoinHostPort(proxyURL.Hostname(), "80")
+}
+
+// redactURL removes the credentials from the url
+func redactURL(u *url.URL) string {
+	redacted := *u
+	redacted.User = nil
+	return redacted.String()
+}
+
+func checkHTTP(client *http.Client, name string, address string) types.Check {
+	check := types.Check{
+		Target:  name,
+		Address: address,
+	}
+
+	req, err := http.NewRequest("GET", address, nil)
+	if err != nil {
+		check.Error = errors.Wrap(err, "failed to create request").Error()
+		return check
+	}
+
+	start := time.Now()
+	resp, err := client.Do(req)
+	check.LatencyMs = time.Since(start).Milliseconds()
+	if err != nil {
+		check.Error = err.Error()
+		return check
+	}
+	defer resp.Body.Close()
+
+	// any response means that the target is reachable, such as a 401 from a registry
+	check.Reachable = true
+	check.TLS = tlsInfo(resp.TLS)
+
+	return check
+}
+
+func checkTCP(name string, address string) types.Check {
+	check := types.Check{
+		Target:  name,
+		Address: address,
+	}
+
+	start := time.Now()
+	conn, err := net.DialTimeout("tcp", address, checkTimeout)
+	check.LatencyMs = time.Since(start).Milliseconds()
+	if err != nil {
+		check.Error = err.Error()
+		return check
+	}
+	conn.Close()
+
+	check.Reachable = true
+
+	return check
+}
+
+func tlsInfo(state *tls.ConnectionState) *types.TLSInfo {
+	if state == nil {
+		return nil
+	}
+
+	info := types.TLSInfo{
+		Version:     tlsVersionName(state.Version),
+		CipherSuite: tls.CipherSuiteName(state.CipherSuite),
+	}
+	if len(state.PeerCertificates) > 0 {
+		cert := state.PeerCertificates[0]
+		info.Subject = cert.Subject.String()
+		info.Issuer = cert.Issuer.String()
+		info.NotAfter = cert.NotAfter
+	}
+
+	return &info
+}
+
+func tlsVersionName(version uint16) string {
+	switch version {
+	case tls.VersionTLS10:
+		return "TLS 1.0"
+	case tls.VersionTLS11:
+		return "TLS 1.1"
+	case tls.VersionTLS12:
+		return "TLS 1.2"
+	case tls.VersionTLS13:
+		return "TLS 1.3"
+	}
+	return fmt.Sprintf("0x%04x", version)
+}
@@ -0,0 +1,37 @@
+package types
+
+import (
+	"time"
+)
+
+const (
+	TargetReplicated = "replicated"
+	TargetRegistry   = "registry"
+	TargetGitOps     = "gitops"
+	TargetProxy      = "proxy"
+)
+
+// Report is the result of testing the outbound connectivity of the admin console
+type Report struct {
+	// Proxy is the proxy used for the http checks, without credentials. It is empty if no proxy is configured.
+	Proxy  string  `json:"proxy"`
+	Checks []Check `json:"checks"`
+}
+
+type Check struct {
+	Target string `json:"target"`
+	// Address is the url of http checks, and the host and port of tcp checks
+	Address   string   `json:"address"`
+	Reachable bool     `json:"reachable"`
+	LatencyMs int64    `json:"latencyMs"`
+	TLS       *TLSInfo `json:"tls,omitempty"`
+	Error     string   `json:"error,omitempty"`
+}
+
+type TLSInfo struct {
+	Version     string    `json:"version"`
+	CipherSuite string    `json:"cipherSuite"`
+	Subject     string    `json:"subject"`
+	Issuer      string    `json:"issuer"`
+	NotAfter    time.Time `json:"notAfter"`
+}
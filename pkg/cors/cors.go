@@ -0,0 +1,65 @@
+package cors
+
+import (
+	"net/url"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// ParseOrigins parses a comma separated list of origins, like https://portal.example.com. An origin can start with
+// a wildcard subdomain, like https://*.example.com, to allow every subdomain of the domain.
+func ParseOrigins(s string) ([]string, error) {
+	origins := []string{}
+	for _, origin := range strings.Split(s, ",") {
+		origin = strings.ToLower(strings.TrimSuffix(strings.TrimSpace(origin), "/"))
+		if origin == "" {
+			continue
+		}
+		if err := validateOrigin(origin); err != nil {
+			return nil, errors.Wrapf(err, "invalid origin %q", origin)
+		}
+		origins = append(origins, origin)
+	}
+	return origins, nil
+}
+
+func validateOrigin(origin string) error {
+	u, err := url.Parse(strings.Replace(origin, "://*.", "://wildcard.", 1))
+	if err != nil {
+		return errors.Wrap(err, "failed to parse")
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return errors.New("scheme must be http or https")
+	}
+	if u.Host == "" {
+		return errors.New("host is required")
+	}
+	if u.Path != "" || u.RawQuery != "" || u.Fragment != "" || u.User != nil {
+		return errors.New("origin must only have a scheme, host and port")
+	}
+	return nil
+}
+
+// IsAllowedOrigin returns true if origin matches one of the allowed origins
+func IsAllowedOrigin(origin string, allowed []string) bool {
+	origin = strings.ToLower(origin)
+	for _, a := range allowed {
+		if a == origin {
+			return true
+		}
+
+		parts := strings.SplitN(a, "://*.", 2)
+		if len(parts) != 2 {
+			continue
+		}
+		prefix, suffix := parts[0]+"://", "."+parts[1]
+		if strings.HasPrefix(origin, prefix) && strings.HasSuffix(origin, suffix) && len(origin) > len(prefix)+len(suffix) {
+			host := strings.TrimSuffix(strings.TrimPrefix(origin, prefix), suffix)
+			if !strings.ContainsAny(host, "/:@") {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,67 @@
+package cors
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ParseOrigins(t *testing.T) {
+	tests := []struct {
+		name    string
+		s       string
+		want    []string
+		wantErr bool
+	}{
+		{
+			name: "empty",
+			s:    "",
+			want: []string{},
+		},
+		{
+			name: "normalized",
+			s:    " https://Portal.example.com/ ,http://localhost:8800",
+			want: []string{"https://portal.example.com", "http://localhost:8800"},
+		},
+		{
+			name: "wildcard subdomain",
+			s:    "https://*.example.com",
+			want: []string{"https://*.example.com"},
+		},
+		{
+			name:    "path",
+			s:       "https://portal.example.com/app",
+			wantErr: true,
+		},
+		{
+			name:    "no scheme",
+			s:       "portal.example.com",
+			wantErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			origins, err := ParseOrigins(test.s)
+			if test.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, test.want, origins)
+		})
+	}
+}
+
+func Test_IsAllowedOrigin(t *testing.T) {
+	allowed := []string{"https://portal.example.com", "https://*.corp.example.com"}
+
+	require.True(t, IsAllowedOrigin("https://portal.example.com", allowed))
+	require.True(t, IsAllowedOrigin("https://Portal.Example.com", allowed))
+	require.True(t, IsAllowedOrigin("https://wiki.corp.example.com", allowed))
+	require.False(t, IsAllowedOrigin("http://portal.example.com", allowed))
+	require.False(t, IsAllowedOrigin("https://corp.example.com", allowed))
+	require.False(t, IsAllowedOrigin("https://evil.com/.corp.example.com", allowed))
+	require.False(t, IsAllowedOrigin("https://portal.example.com.evil.com", allowed))
+	require.False(t, IsAllowedOrigin("", allowed))
+}
@@ -0,0 +1,55 @@
+package cronschedule
+
+import (
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	cron "github.com/robfig/cron/v3"
+)
+
+// Parse parses a standard cron spec or descriptor. A spec without a CRON_TZ= or TZ= prefix runs in the timezone, which
+// is UTC if it's empty.
+func Parse(spec string, timezone string) (cron.Schedule, error) {
+	spec = strings.TrimSpace(spec)
+	if !HasTimezone(spec) {
+		location, err := LoadLocation(timezone)
+		if err != nil {
+			return nil, err
+		}
+		spec = "CRON_TZ=" + location.String() + " " + spec
+	}
+
+	schedule, err := cron.ParseStandard(spec)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to parse cron spec")
+	}
+	return schedule, nil
+}
+
+// Next returns the next time of the spec after now, in the timezone the spec runs in
+func Next(spec string, timezone string, now time.Time) (time.Time, error) {
+	schedule, err := Parse(spec, timezone)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return schedule.Next(now), nil
+}
+
+// HasTimezone returns true if the spec sets its own timezone
+func HasTimezone(spec string) bool {
+	spec = strings.TrimSpace(spec)
+	return strings.HasPrefix(spec, "CRON_TZ=") || strings.HasPrefix(spec, "TZ=")
+}
+
+// LoadLocation returns the location of an IANA timezone name, e.g. "America/New_York", or UTC if the name is empty
+func LoadLocation(timezone string) (*time.Location, error) {
+	if timezone == "" {
+		return time.UTC, nil
+	}
+	location, err := time.LoadLocation(timezone)
+	if err != nil {
+		return nil, errors.Errorf("unknown timezone %q", timezone)
+	}
+	return location, nil
+}
@@ -0,0 +1,71 @@
+package cronschedule
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNext(t *testing.T) {
+	now := time.Date(2021, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		spec     string
+		timezone string
+		want     time.Time
+		wantErr  bool
+	}{
+		{
+			name: "utc by default",
+			spec: "0 2 * * *",
+			want: time.Date(2021, 6, 2, 2, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "app timezone",
+			spec:     "0 2 * * *",
+			timezone: "America/New_York",
+			// 2am EDT is 6am UTC
+			want: time.Date(2021, 6, 2, 6, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "spec timezone overrides the app timezone",
+			spec:     "CRON_TZ=Europe/Berlin 0 2 * * *",
+			timezone: "America/New_York",
+			// 2am CEST is midnight UTC
+			want: time.Date(2021, 6, 2, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "descriptor",
+			spec:     "@daily",
+			timezone: "Asia/Tokyo",
+			// midnight JST is 3pm UTC
+			want: time.Date(2021, 6, 1, 15, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "unknown timezone",
+			spec:     "0 2 * * *",
+			timezone: "Mars/Olympus_Mons",
+			wantErr:  true,
+		},
+		{
+			name:    "invalid spec",
+			spec:    "every day",
+			wantErr: true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			req := require.New(t)
+
+			got, err := Next(test.spec, test.timezone, now)
+			if test.wantErr {
+				req.Error(err)
+				return
+			}
+			req.NoError(err)
+			req.True(test.want.Equal(got), "expected %s, got %s", test.want, got)
+		})
+	}
+}
@@ -0,0 +1,179 @@
+package deployapproval
+
+import (
+	"time"
+
+	"github.com/pkg/errors"
+	apptypes "github.com/replicatedhq/kots/pkg/app/types"
+	"github.com/replicatedhq/kots/pkg/canary"
+	"github.com/replicatedhq/kots/pkg/deployapproval/types"
+	deployhistorytypes "github.com/replicatedhq/kots/pkg/deployhistory/types"
+	"github.com/replicatedhq/kots/pkg/logger"
+	"github.com/replicatedhq/kots/pkg/store"
+	"github.com/segmentio/ksuid"
+	"go.uber.org/zap"
+)
+
+const (
+	// RequestedByAutomaticDeploy is the requester of approvals for updates that are deployed automatically
+	RequestedByAutomaticDeploy = "automatic-deploy"
+)
+
+var (
+	ErrNotPending   = errors.New("deploy approval is not pending")
+	ErrSelfApproval = errors.New("a deploy must be approved by a different user than the one that requested it")
+	ErrUnknownUser  = errors.New("the session does not identify a user, log in again to approve deploys")
+	ErrSuperseded   = errors.New("a newer version has been deployed since the deploy was requested")
+)
+
+type RequestOptions struct {
+	RequestedBy                  string
+	IsSkipPreflights             bool
+	ContinueWithFailedPreflights bool
+	IsCLI                        bool
+}
+
+// Request creates a pending approval to deploy the sequence. If one is already pending, it is returned instead.
+func Request(appID string, sequence int64, opts RequestOptions) (*types.DeployApproval, error) {
+	pending, err := store.GetStore().GetPendingDeployApproval(appID, sequence)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get pending deploy approval")
+	}
+	if pending != nil {
+		return pending, nil
+	}
+
+	approval := types.DeployApproval{
+		ID:                           ksuid.New().String(),
+		AppID:                        appID,
+		Sequence:                     sequence,
+		Status:                       types.StatusPending,
+		RequestedBy:                  opts.RequestedBy,
+		RequestedAt:                  time.Now(),
+		IsSkipPreflights:             opts.IsSkipPreflights,
+		ContinueWithFailedPreflights: opts.ContinueWithFailedPreflights,
+		IsCLI:                        opts.IsCLI,
+	}
+	if err := store.GetStore().CreateDeployApproval(approval); err != nil {
+		return nil, errors.Wrap(err, "failed to create deploy approval")
+	}
+
+	audit("deploy approval requested", approval, opts.RequestedBy)
+
+	return &approval, nil
+}
+
+// DeployOrRequest deploys the sequence, or requests approval to deploy it if the app requires deploy approval.
+// The pending approval is returned if one was requested.
+func DeployOrRequest(a *apptypes.App, sequence int64, opts RequestOptions) (*types.DeployApproval, error) {
+	if a.RequireDeployApproval {
+		approval, err := Request(a.ID, sequence, opts)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to request deploy approval")
+		}
+		return approval, nil
+	}
+
+	deployer := deployhistorytypes.Deployer{
+		DeployedBy:        opts.RequestedBy,
+		SkippedPreflights: opts.IsSkipPreflights,
+	}
+	if err := canary.DeployVersion(a, sequence, deployer); err != nil {
+		return nil, errors.Wrap(err, "failed to deploy version")
+	}
+	return nil, nil
+}
+
+// CheckApprover returns an error if the user cannot approve or reject the deploy approval
+func CheckApprover(approval *types.DeployApproval, userID string) error {
+	if approval.Status != types.StatusPending {
+		return ErrNotPending
+	}
+	if userID == "" {
+		return ErrUnknownUser
+	}
+	if userID == approval.RequestedBy {
+		return ErrSelfApproval
+	}
+	return nil
+}
+
+// SetApproved records that the user approved the deploy, before the version is deployed by the caller, so that
+// an approval is only deployed once. Reopen must be called if the deploy fails.
+func SetApproved(approval *types.DeployApproval, userID string, clusterID string) error {
+	if err := CheckApprover(approval, userID); err != nil {
+		return err
+	}
+
+	deployedSequence, err := store.GetStore().GetCurrentParentSequence(approval.AppID, clusterID)
+	if err != nil {
+		return errors.Wrap(err, "failed to get deployed sequence")
+	}
+	if err := checkNotSuperseded(approval, deployedSequence); err != nil {
+		return err
+	}
+
+	decided, err := store.GetStore().SetDeployApprovalDecision(approval.ID, types.StatusApproved, userID, time.Now())
+	if err != nil {
+		return errors.Wrap(err, "failed to set deploy approval decision")
+	}
+	if !decided {
+		return ErrNotPending
+	}
+
+	audit("deploy approved", *approval, userID)
+
+	return nil
+}
+
+// Reopen sets an approval back to pending after the deploy of the approved version failed, so that it can be
+// approved again
+func Reopen(approval *types.DeployApproval, userID string) error {
+	if err := store.GetStore().ReopenDeployApproval(approval.ID); err != nil {
+		return errors.Wrap(err, "failed to reopen deploy approval")
+	}
+
+	audit("deploy approval reopened after a failed deploy", *approval, userID)
+
+	return nil
+}
+
+// checkNotSuperseded returns an error if the approval would roll back a newer deployed version
+func checkNotSuperseded(approval *types.DeployApproval, deployedSequence int64) error {
+	if approval.Sequence < deployedSequence {
+		return ErrSuperseded
+	}
+	return nil
+}
+
+// Reject rejects a pending deploy approval. The user that requested the deploy can reject it to cancel the request.
+func Reject(approval *types.DeployApproval, userID string) error {
+	if approval.Status != types.StatusPending {
+		return ErrNotPending
+	}
+	if userID == "" {
+		return ErrUnknownUser
+	}
+
+	decided, err := store.GetStore().SetDeployApprovalDecision(approval.ID, types.StatusRejected, userID, time.Now())
+	if err != nil {
+		return errors.Wrap(err, "failed to set deploy approval decision")
+	}
+	if !decided {
+		return ErrNotPending
+	}
+
+	audit("deploy rejected", *approval, userID)
+
+	return nil
+}
+
+func audit(msg string, approval types.DeployApproval, userID string) {
+	logger.Info(msg,
+		zap.String("audit", "deploy-approval"),
+		zap.String("approvalID", approval.ID),
+		zap.String("appID", approval.AppID),
+		zap.Int64("sequence", approval.Sequence),
+		zap.String("requestedBy", approval.RequestedBy),
+		zap.String("user", userID))
+}
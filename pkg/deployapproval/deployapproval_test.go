@@ -0,0 +1,95 @@
+package deployapproval
+
+import (
+	"testing"
+
+	"github.com/replicatedhq/kots/pkg/deployapproval/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_CheckApprover(t *testing.T) {
+	tests := []struct {
+		name     string
+		approval types.DeployApproval
+		userID   string
+		want     error
+	}{
+		{
+			name:     "another user",
+			approval: types.DeployApproval{Status: types.StatusPending, RequestedBy: "alice@example.com"},
+			userID:   "bob@example.com",
+			want:     nil,
+		},
+		{
+			name:     "automatic deploy",
+			approval: types.DeployApproval{Status: types.StatusPending, RequestedBy: RequestedByAutomaticDeploy},
+			userID:   "bob@example.com",
+			want:     nil,
+		},
+		{
+			name:     "same user",
+			approval: types.DeployApproval{Status: types.StatusPending, RequestedBy: "alice@example.com"},
+			userID:   "alice@example.com",
+			want:     ErrSelfApproval,
+		},
+		{
+			name:     "unknown user",
+			approval: types.DeployApproval{Status: types.StatusPending, RequestedBy: "alice@example.com"},
+			userID:   "",
+			want:     ErrUnknownUser,
+		},
+		{
+			name:     "already approved",
+			approval: types.DeployApproval{Status: types.StatusApproved, RequestedBy: "alice@example.com"},
+			userID:   "bob@example.com",
+			want:     ErrNotPending,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.want, CheckApprover(&test.approval, test.userID))
+		})
+	}
+}
+
+func Test_checkNotSuperseded(t *testing.T) {
+	tests := []struct {
+		name             string
+		sequence         int64
+		deployedSequence int64
+		want             error
+	}{
+		{
+			name:             "newer version",
+			sequence:         5,
+			deployedSequence: 4,
+			want:             nil,
+		},
+		{
+			name:             "deployed version",
+			sequence:         4,
+			deployedSequence: 4,
+			want:             nil,
+		},
+		{
+			name:             "nothing deployed",
+			sequence:         0,
+			deployedSequence: -1,
+			want:             nil,
+		},
+		{
+			name:             "older version",
+			sequence:         3,
+			deployedSequence: 4,
+			want:             ErrSuperseded,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			approval := types.DeployApproval{Status: types.StatusPending, Sequence: test.sequence}
+			assert.Equal(t, test.want, checkNotSuperseded(&approval, test.deployedSequence))
+		})
+	}
+}
@@ -0,0 +1,31 @@
+package types
+
+import (
+	"time"
+)
+
+type Status string
+
+const (
+	StatusPending  Status = "pending"
+	StatusApproved Status = "approved"
+	StatusRejected Status = "rejected"
+)
+
+// DeployApproval is a request to deploy a version of an app that requires deploy approval.
+// The version is deployed when a user other than the one that requested it approves it.
+type DeployApproval struct {
+	ID          string     `json:"id"`
+	AppID       string     `json:"appId"`
+	Sequence    int64      `json:"sequence"`
+	Status      Status     `json:"status"`
+	RequestedBy string     `json:"requestedBy"`
+	RequestedAt time.Time  `json:"requestedAt"`
+	DecidedBy   string     `json:"decidedBy,omitempty"`
+	DecidedAt   *time.Time `json:"decidedAt,omitempty"`
+
+	// options of the deploy request, applied when the version is deployed
+	IsSkipPreflights             bool `json:"isSkipPreflights"`
+	ContinueWithFailedPreflights bool `json:"continueWithFailedPreflights"`
+	IsCLI                        bool `json:"isCli"`
+}
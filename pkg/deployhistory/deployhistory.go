@@ -0,0 +1,108 @@
+package deployhistory
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/replicatedhq/kots/pkg/deployhistory/types"
+)
+
+const dateLayout = "2006-01-02"
+
+var csvHeader = []string{
+	"deployed_at",
+	"app",
+	"downstream",
+	"sequence",
+	"version",
+	"deployed_by",
+	"approved_by",
+	"preflight_state",
+	"result",
+	"result_at",
+	"phase",
+}
+
+// WriteCSV writes the deploy events as CSV with a header row. Times are written in RFC3339 in UTC.
+func WriteCSV(w io.Writer, events []types.Event) error {
+	writer := csv.NewWriter(w)
+
+	if err := writer.Write(csvHeader); err != nil {
+		return errors.Wrap(err, "failed to write header")
+	}
+
+	for _, event := range events {
+		resultAt := ""
+		if event.ResultAt != nil {
+			resultAt = event.ResultAt.UTC().Format(time.RFC3339)
+		}
+		record := []string{
+			event.DeployedAt.UTC().Format(time.RFC3339),
+			event.AppSlug,
+			event.DownstreamName,
+			fmt.Sprintf("%d", event.Sequence),
+			event.VersionLabel,
+			event.DeployedBy,
+			event.ApprovedBy,
+			event.PreflightState,
+			event.Result,
+			resultAt,
+			event.Phase,
+		}
+		if err := writer.Write(record); err != nil {
+			return errors.Wrapf(err, "failed to write event %s", event.ID)
+		}
+	}
+
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return errors.Wrap(err, "failed to flush")
+	}
+
+	return nil
+}
+
+// ParseRange parses the bounds of a date range given as RFC3339 times or as dates, and returns them in UTC. A date as
+// the end of the range includes the whole day. Empty bounds are returned as nil.
+func ParseRange(from string, to string) (*time.Time, *time.Time, error) {
+	var fromTime, toTime *time.Time
+
+	if from != "" {
+		t, _, err := parseTime(from)
+		if err != nil {
+			return nil, nil, errors.Wrap(err, "invalid from")
+		}
+		fromTime = &t
+	}
+
+	if to != "" {
+		t, isDate, err := parseTime(to)
+		if err != nil {
+			return nil, nil, errors.Wrap(err, "invalid to")
+		}
+		if isDate {
+			t = t.AddDate(0, 0, 1)
+		}
+		toTime = &t
+	}
+
+	if fromTime != nil && toTime != nil && !fromTime.Before(*toTime) {
+		return nil, nil, errors.New("from must be before to")
+	}
+
+	return fromTime, toTime, nil
+}
+
+func parseTime(s string) (time.Time, bool, error) {
+	if t, err := time.Parse(dateLayout, s); err == nil {
+		return t, true, nil
+	}
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		return time.Time{}, false, errors.Errorf("%q is neither a date (%s) nor an RFC3339 time", s, dateLayout)
+	}
+	return t.UTC(), false, nil
+}
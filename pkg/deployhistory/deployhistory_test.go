@@ -0,0 +1,121 @@
+package deployhistory
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/replicatedhq/kots/pkg/deployhistory/types"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_WriteCSV(t *testing.T) {
+	deployedAt := time.Date(2021, 3, 1, 10, 0, 0, 0, time.UTC)
+	resultAt := deployedAt.Add(time.Minute)
+
+	events := []types.Event{
+		{
+			ID:             "1",
+			AppSlug:        "my-app",
+			DownstreamName: "this-cluster",
+			Sequence:       3,
+			VersionLabel:   "1.2.0, beta",
+			DeployedAt:     deployedAt,
+			DeployedBy:     "alice",
+			ApprovedBy:     "bob",
+			PreflightState: "pass",
+			Result:         types.ResultDeployed,
+			ResultAt:       &resultAt,
+		},
+		{
+			ID:             "2",
+			AppSlug:        "my-app",
+			DownstreamName: "this-cluster",
+			Sequence:       4,
+			VersionLabel:   "1.3.0",
+			DeployedAt:     deployedAt.Add(time.Hour),
+			DeployedBy:     types.DeployedByAutomaticDeploy,
+			PreflightState: types.PreflightStateSkipped,
+			Result:         types.ResultPending,
+		},
+		{
+			ID:             "3",
+			AppSlug:        "my-app",
+			DownstreamName: "this-cluster",
+			Sequence:       4,
+			VersionLabel:   "1.3.0",
+			DeployedAt:     deployedAt.Add(2 * time.Hour),
+			DeployedBy:     "alice",
+			PreflightState: "pass",
+			Result:         types.ResultDeployed,
+			ResultAt:       &resultAt,
+			Phase:          types.PhaseActivate,
+		},
+	}
+
+	buf := bytes.NewBuffer(nil)
+	err := WriteCSV(buf, events)
+	require.NoError(t, err)
+
+	want := `deployed_at,app,downstream,sequence,version,deployed_by,approved_by,preflight_state,result,result_at,phase
+2021-03-01T10:00:00Z,my-app,this-cluster,3,"1.2.0, beta",alice,bob,pass,deployed,2021-03-01T10:01:00Z,
+2021-03-01T11:00:00Z,my-app,this-cluster,4,1.3.0,automatic-deploy,,skipped,pending,,
+2021-03-01T12:00:00Z,my-app,this-cluster,4,1.3.0,alice,,pass,deployed,2021-03-01T10:01:00Z,activate
+`
+	require.Equal(t, want, buf.String())
+}
+
+func Test_ParseRange(t *testing.T) {
+	date := func(year int, month time.Month, day int) *time.Time {
+		d := time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+		return &d
+	}
+
+	tests := []struct {
+		name     string
+		from     string
+		to       string
+		wantFrom *time.Time
+		wantTo   *time.Time
+		wantErr  bool
+	}{
+		{
+			name: "no range",
+		},
+		{
+			name:     "dates include the whole last day",
+			from:     "2021-01-01",
+			to:       "2021-03-31",
+			wantFrom: date(2021, 1, 1),
+			wantTo:   date(2021, 4, 1),
+		},
+		{
+			name:     "times are converted to utc",
+			from:     "2021-01-01T02:00:00+02:00",
+			wantFrom: date(2021, 1, 1),
+		},
+		{
+			name:    "from after to",
+			from:    "2021-03-01",
+			to:      "2021-02-01",
+			wantErr: true,
+		},
+		{
+			name:    "invalid date",
+			to:      "31/03/2021",
+			wantErr: true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			from, to, err := ParseRange(test.from, test.to)
+			if test.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, test.wantFrom, from)
+			require.Equal(t, test.wantTo, to)
+		})
+	}
+}
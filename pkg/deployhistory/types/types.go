@@ -0,0 +1,66 @@
+package types
+
+import (
+	"time"
+)
+
+// Names of the kotsadm processes that deploy versions without a user, recorded as the deployer in the deploy history
+const (
+	DeployedByAutomaticDeploy = "automatic-deploy"
+	DeployedByInstall         = "install"
+	DeployedByCanaryPromotion = "canary-promotion"
+	DeployedByCanaryRollback  = "canary-rollback"
+	DeployedByPostDeployTest  = "post-deploy-test-rollback"
+	DeployedBySnapshotRestore = "snapshot-restore"
+	DeployedByStateImport     = "state-import"
+)
+
+// Results of deploy events. A deploy is pending until the operator reports its result.
+const (
+	ResultPending  = "pending"
+	ResultDeployed = "deployed"
+	ResultFailed   = "failed"
+)
+
+// PhaseActivate is the phase of the deploy events that activate a version of an app that is deployed in two phases.
+// The events of deploys have no phase.
+const PhaseActivate = "activate"
+
+// PreflightStateSkipped is the preflight state of deploys that skipped preflight checks
+const PreflightStateSkipped = "skipped"
+
+// Deployer is who deployed a version, recorded with each deploy event
+type Deployer struct {
+	// DeployedBy is the id of the user that deployed the version, or one of the DeployedBy constants for deploys
+	// that kotsadm started on its own
+	DeployedBy string
+	// ApprovedBy is the id of the user that approved the deploy, for apps that require deploy approval
+	ApprovedBy        string
+	SkippedPreflights bool
+}
+
+// Event is a deploy of an app version to one downstream
+type Event struct {
+	ID             string     `json:"id"`
+	AppID          string     `json:"appId"`
+	AppSlug        string     `json:"appSlug"`
+	ClusterID      string     `json:"clusterId"`
+	DownstreamName string     `json:"downstreamName"`
+	Sequence       int64      `json:"sequence"`
+	VersionLabel   string     `json:"versionLabel"`
+	DeployedAt     time.Time  `json:"deployedAt"`
+	DeployedBy     string     `json:"deployedBy"`
+	ApprovedBy     string     `json:"approvedBy,omitempty"`
+	PreflightState string     `json:"preflightState,omitempty"`
+	Result         string     `json:"result"`
+	ResultAt       *time.Time `json:"resultAt,omitempty"`
+	Phase          string     `json:"phase,omitempty"`
+}
+
+// ListOptions selects the deploy events to export, oldest first
+type ListOptions struct {
+	// AppID limits the events to one app, events of all apps are listed if it's empty
+	AppID string
+	From  *time.Time
+	To    *time.Time
+}
@@ -14,6 +14,7 @@ import (
 	"github.com/docker/distribution/registry/client/auth/challenge"
 	"github.com/pkg/errors"
 	"github.com/replicatedhq/kots/pkg/buildversion"
+	"github.com/replicatedhq/kots/pkg/clientcert"
 	"github.com/replicatedhq/kots/pkg/logger"
 )
 
@@ -45,10 +46,28 @@ func LoadAuthForRegistry(endpoint string) (string, string, error) {
 	return username, password, nil
 }
 
-func CheckAccess(endpoint, username, password, org string, requestedAction ScopeAction) error {
+// CheckAccess checks that the user has the requested access to the org in the registry. The client certificate
+// is presented to registries that require mutual tls, the one loaded for the endpoint is used if it is nil.
+func CheckAccess(endpoint, username, password, org string, requestedAction ScopeAction, clientCertificate *tls.Certificate) error {
 
 	endpoint = sanitizeEndpoint(endpoint)
 
+	client := insecureClient
+	if clientCertificate == nil {
+		clientCertificate = clientcert.Get(endpoint)
+	}
+	if clientCertificate != nil {
+		client = &http.Client{
+			Transport: &http.Transport{
+				TLSClientConfig: &tls.Config{
+					InsecureSkipVerify: true,
+					Certificates:       []tls.Certificate{*clientCertificate},
+				},
+				Proxy: http.ProxyFromEnvironment,
+			},
+		}
+	}
+
 	// We need to check if we can push images to a repo.
 	// We cannot get push permission to an org alone.
 	scope := org + "/testrepo"
@@ -64,11 +83,11 @@ func CheckAccess(endpoint, username, password, org string, requestedAction Scope
 	}
 
 	pingURL := fmt.Sprintf("https://%s/v2/", endpoint)
-	resp, err := insecureClient.Get(pingURL)
+	resp, err := client.Get(pingURL)
 	if err != nil {
 		// attempt with http
 		pingURL = fmt.Sprintf("http://%s/v2/", endpoint)
-		resp, err = insecureClient.Get(pingURL)
+		resp, err = client.Get(pingURL)
 		if err != nil {
 			return errors.Wrap(err, "failed to ping registry")
 		}
@@ -108,7 +127,7 @@ func CheckAccess(endpoint, username, password, org string, requestedAction Scope
 	req.Header.Add("User-Agent", fmt.Sprintf("KOTS/%s", buildversion.Version()))
 	req.Header.Set("Authorization", fmt.Sprintf("Basic %s", basicAuthToken))
 
-	resp, err = insecureClient.Do(req)
+	resp, err = client.Do(req)
 	if err != nil {
 		return errors.Wrap(err, "failed to execute auth request")
 	}
@@ -9,6 +9,7 @@ import (
 
 	"github.com/pkg/errors"
 	kotsv1beta1 "github.com/replicatedhq/kots/kotskinds/apis/kots/v1beta1"
+	"github.com/replicatedhq/kots/pkg/endpoints"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
@@ -31,7 +32,17 @@ type Credentials struct {
 	Password string
 }
 
+// ProxyEndpointFromLicense returns the replicated registry and proxy registry of the license endpoint. When
+// mirrors of the registries are configured, the first healthy one of the registry and its mirrors is returned.
 func ProxyEndpointFromLicense(license *kotsv1beta1.License) *RegistryProxyInfo {
+	info := proxyEndpointFromLicense(license)
+	return &RegistryProxyInfo{
+		Registry: endpoints.RegistryHost(info.Registry),
+		Proxy:    endpoints.ProxyHost(info.Proxy),
+	}
+}
+
+func proxyEndpointFromLicense(license *kotsv1beta1.License) *RegistryProxyInfo {
 	defaultInfo := &RegistryProxyInfo{
 		Registry: "registry.replicated.com",
 		Proxy:    "proxy.replicated.com",
@@ -9,9 +9,11 @@ import (
 
 	"github.com/mholt/archiver"
 	"github.com/pkg/errors"
+	handlertypes "github.com/replicatedhq/kots/pkg/api/handlers/types"
 	"github.com/replicatedhq/kots/pkg/auth"
 	"github.com/replicatedhq/kots/pkg/k8sutil"
 	"github.com/replicatedhq/kots/pkg/logger"
+	"github.com/replicatedhq/kots/pkg/versionskew"
 )
 
 type DownloadOptions struct {
@@ -19,6 +21,8 @@ type DownloadOptions struct {
 	Overwrite             bool
 	Silent                bool
 	DecryptPasswordValues bool
+	// Sequence is the version to download, the latest version is downloaded if it is nil
+	Sequence *int64
 }
 
 func Download(appSlug string, path string, downloadOptions DownloadOptions) error {
@@ -60,15 +64,24 @@ func Download(appSlug string, path string, downloadOptions DownloadOptions) erro
 		}
 	}()
 
+	client, err := versionskew.NewClient(fmt.Sprintf("http://localhost:%d", localPort), log)
+	if err != nil {
+		log.FinishSpinnerWithError()
+		return err
+	}
+
 	authSlug, err := auth.GetOrCreateAuthSlug(clientset, downloadOptions.Namespace)
 	if err != nil {
 		log.FinishSpinnerWithError()
 		return errors.Wrap(err, "failed to get kotsadm auth slug")
 	}
 
-	url := fmt.Sprintf("http://localhost:%d/api/v1/download?slug=%s", localPort, appSlug)
+	url := fmt.Sprintf("http://localhost:%d/api/v1/app/%s/download", localPort, appSlug)
 	if downloadOptions.DecryptPasswordValues {
-		url = fmt.Sprintf("%s&decryptPasswordValues=1", url)
+		url = fmt.Sprintf("%s/decrypted", url)
+	}
+	if downloadOptions.Sequence != nil {
+		url = fmt.Sprintf("%s?sequence=%d", url, *downloadOptions.Sequence)
 	}
 
 	newRequest, err := http.NewRequest("GET", url, nil)
@@ -78,7 +91,7 @@ func Download(appSlug string, path string, downloadOptions DownloadOptions) erro
 	}
 	newRequest.Header.Add("Authorization", authSlug)
 
-	resp, err := http.DefaultClient.Do(newRequest)
+	resp, err := client.Do(newRequest)
 	if err != nil {
 		log.FinishSpinnerWithError()
 		return errors.Wrap(err, "failed to get from kotsadm")
@@ -90,7 +103,7 @@ func Download(appSlug string, path string, downloadOptions DownloadOptions) erro
 		if resp.StatusCode == http.StatusNotFound {
 			return errors.Errorf("app with slug %s not found", appSlug)
 		} else {
-			return errors.Errorf("unexpected status code from %s: %s", url, resp.Status)
+			return errors.Wrapf(handlertypes.ErrorFromResponse(resp), "unexpected status code from %s", url)
 		}
 	}
 
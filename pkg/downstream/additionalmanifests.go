@@ -0,0 +1,120 @@
+package downstream
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+
+	"github.com/pkg/errors"
+	"github.com/replicatedhq/kots/pkg/base"
+	"github.com/replicatedhq/kots/pkg/k8sutil"
+	"gopkg.in/yaml.v2"
+	kustomizetypes "sigs.k8s.io/kustomize/api/types"
+)
+
+const (
+	// AdditionalManifestsDirName is the overlay, relative to the downstream dir, that holds the manifests supplied
+	// with kots install --additional-manifests. It is part of the downstream, so it is preserved across updates.
+	AdditionalManifestsDirName = "additional-manifests"
+)
+
+// file names are also used as secret keys to hand the manifests to the admin console
+var additionalManifestNameRegex = regexp.MustCompile(`^[-._a-zA-Z0-9]+\.ya?ml$`)
+
+// ReadAdditionalManifestsFromDir reads the yaml files at the top level of dir, keyed by file name.
+func ReadAdditionalManifestsFromDir(dir string) (map[string][]byte, error) {
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read dir")
+	}
+
+	manifests := map[string][]byte{}
+	for _, file := range files {
+		if file.IsDir() {
+			continue
+		}
+		ext := filepath.Ext(file.Name())
+		if ext != ".yaml" && ext != ".yml" {
+			continue
+		}
+
+		content, err := ioutil.ReadFile(filepath.Join(dir, file.Name()))
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to read %s", file.Name())
+		}
+		manifests[file.Name()] = content
+	}
+
+	if len(manifests) == 0 {
+		return nil, errors.Errorf("no yaml files found in %s", dir)
+	}
+
+	if err := ValidateAdditionalManifests(manifests); err != nil {
+		return nil, err
+	}
+
+	return manifests, nil
+}
+
+// ValidateAdditionalManifests checks that every document of every manifest is a kubernetes object with a name.
+func ValidateAdditionalManifests(manifests map[string][]byte) error {
+	for name, content := range manifests {
+		if !additionalManifestNameRegex.MatchString(name) {
+			return errors.Errorf("invalid file name %q", name)
+		}
+		if name == "kustomization.yaml" || name == "kustomization.yml" {
+			return errors.Errorf("%s is generated and cannot be supplied", name)
+		}
+
+		docs := 0
+		for i, doc := range bytes.Split(content, []byte("\n---\n")) {
+			if len(bytes.TrimSpace(doc)) == 0 {
+				continue
+			}
+
+			o := base.OverlySimpleGVK{}
+			if err := yaml.Unmarshal(doc, &o); err != nil {
+				return errors.Wrapf(err, "failed to parse document %d of %s", i, name)
+			}
+			if o.APIVersion == "" || o.Kind == "" || o.Metadata.Name == "" {
+				return errors.Errorf("document %d of %s must have an apiVersion, kind and metadata.name", i, name)
+			}
+			docs++
+		}
+		if docs == 0 {
+			return errors.Errorf("%s does not contain any documents", name)
+		}
+	}
+
+	return nil
+}
+
+// writeAdditionalManifests writes the manifests and a kustomization listing them to dir
+func writeAdditionalManifests(dir string, manifests map[string][]byte) error {
+	if err := os.MkdirAll(dir, 0744); err != nil {
+		return errors.Wrap(err, "failed to mkdir")
+	}
+
+	resources := []string{}
+	for name, content := range manifests {
+		if err := ioutil.WriteFile(filepath.Join(dir, name), content, 0644); err != nil {
+			return errors.Wrapf(err, "failed to write %s", name)
+		}
+		resources = append(resources, name)
+	}
+
+	kustomization := kustomizetypes.Kustomization{
+		TypeMeta: kustomizetypes.TypeMeta{
+			APIVersion: "kustomize.config.k8s.io/v1beta1",
+			Kind:       "Kustomization",
+		},
+		Resources: resources,
+	}
+	if err := k8sutil.WriteKustomizationToFile(kustomization, filepath.Join(dir, "kustomization.yaml")); err != nil {
+		return errors.Wrap(err, "failed to write kustomization")
+	}
+
+	return nil
+}
@@ -0,0 +1,107 @@
+package downstream
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/replicatedhq/kots/pkg/k8sutil"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ValidateAdditionalManifests(t *testing.T) {
+	tests := []struct {
+		name      string
+		manifests map[string][]byte
+		wantErr   bool
+	}{
+		{
+			name: "valid",
+			manifests: map[string][]byte{
+				"network-policy.yaml": []byte("apiVersion: networking.k8s.io/v1\nkind: NetworkPolicy\nmetadata:\n  name: deny-all\n---\napiVersion: monitoring.coreos.com/v1\nkind: PodMonitor\nmetadata:\n  name: app\n"),
+			},
+		},
+		{
+			name: "missing name",
+			manifests: map[string][]byte{
+				"network-policy.yaml": []byte("apiVersion: networking.k8s.io/v1\nkind: NetworkPolicy\n"),
+			},
+			wantErr: true,
+		},
+		{
+			name: "empty",
+			manifests: map[string][]byte{
+				"empty.yaml": []byte("---\n"),
+			},
+			wantErr: true,
+		},
+		{
+			name: "kustomization",
+			manifests: map[string][]byte{
+				"kustomization.yaml": []byte("apiVersion: kustomize.config.k8s.io/v1beta1\nkind: Kustomization\nmetadata:\n  name: k\n"),
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalid file name",
+			manifests: map[string][]byte{
+				"network policy.yaml": []byte("apiVersion: networking.k8s.io/v1\nkind: NetworkPolicy\nmetadata:\n  name: deny-all\n"),
+			},
+			wantErr: true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := ValidateAdditionalManifests(test.manifests)
+			if test.wantErr {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func Test_WriteDownstreamAdditionalManifests(t *testing.T) {
+	rootDir, err := ioutil.TempDir("", "kots")
+	require.NoError(t, err)
+	defer os.RemoveAll(rootDir)
+
+	networkPolicy := []byte("apiVersion: networking.k8s.io/v1\nkind: NetworkPolicy\nmetadata:\n  name: deny-all\n")
+
+	d, err := CreateDownstream(nil, "this-cluster")
+	require.NoError(t, err)
+
+	options := WriteOptions{
+		DownstreamDir: filepath.Join(rootDir, "overlays", "downstreams", "this-cluster"),
+		MidstreamDir:  filepath.Join(rootDir, "overlays", "midstream"),
+		AdditionalManifests: map[string][]byte{
+			"network-policy.yaml": networkPolicy,
+		},
+	}
+	err = d.WriteDownstream(options)
+	require.NoError(t, err)
+
+	k, err := k8sutil.ReadKustomizationFromFile(filepath.Join(options.DownstreamDir, "kustomization.yaml"))
+	require.NoError(t, err)
+	require.Equal(t, []string{"../../midstream", AdditionalManifestsDirName}, k.Bases)
+
+	k, err = k8sutil.ReadKustomizationFromFile(filepath.Join(options.DownstreamDir, AdditionalManifestsDirName, "kustomization.yaml"))
+	require.NoError(t, err)
+	require.Equal(t, []string{"network-policy.yaml"}, k.Resources)
+
+	content, err := ioutil.ReadFile(filepath.Join(options.DownstreamDir, AdditionalManifestsDirName, "network-policy.yaml"))
+	require.NoError(t, err)
+	require.Equal(t, networkPolicy, content)
+
+	// an existing downstream is not overwritten, so the manifests are kept across updates
+	d, err = CreateDownstream(nil, "this-cluster")
+	require.NoError(t, err)
+	options.AdditionalManifests = nil
+	err = d.WriteDownstream(options)
+	require.NoError(t, err)
+
+	_, err = os.Stat(filepath.Join(options.DownstreamDir, AdditionalManifestsDirName, "network-policy.yaml"))
+	require.NoError(t, err)
+}
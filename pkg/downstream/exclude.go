@@ -0,0 +1,125 @@
+package downstream
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/replicatedhq/kots/pkg/k8sutil"
+	"sigs.k8s.io/kustomize/api/resid"
+	kustomizetypes "sigs.k8s.io/kustomize/api/types"
+)
+
+const deletePatchDirective = "$patch: delete"
+
+// ResourceExclusion is a rendered resource that is removed from a downstream by a delete patch
+// in the downstream kustomization. An empty namespace matches the resource in any namespace.
+type ResourceExclusion struct {
+	Kind      string `json:"kind"`
+	Name      string `json:"name"`
+	Namespace string `json:"namespace,omitempty"`
+}
+
+func (e ResourceExclusion) matches(target *kustomizetypes.Selector) bool {
+	return target != nil &&
+		strings.EqualFold(target.Kind, e.Kind) &&
+		target.Name == e.Name &&
+		target.Namespace == e.Namespace
+}
+
+// ListExclusions returns the resources excluded from the downstream in an extracted app version archive
+func ListExclusions(archiveDir string, downstreamName string) ([]ResourceExclusion, error) {
+	k, err := k8sutil.ReadKustomizationFromFile(downstreamKustomizationPath(archiveDir, downstreamName))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read downstream kustomization")
+	}
+
+	exclusions := []ResourceExclusion{}
+	for _, patch := range k.Patches {
+		if !isExclusionPatch(patch) {
+			continue
+		}
+		exclusions = append(exclusions, ResourceExclusion{
+			Kind:      patch.Target.Kind,
+			Name:      patch.Target.Name,
+			Namespace: patch.Target.Namespace,
+		})
+	}
+
+	return exclusions, nil
+}
+
+// AddExclusion adds a delete patch for the resource to the downstream kustomization.
+// It returns false if the resource was already excluded.
+func AddExclusion(archiveDir string, downstreamName string, exclusion ResourceExclusion) (bool, error) {
+	if exclusion.Kind == "" || exclusion.Name == "" {
+		return false, errors.New("kind and name are required")
+	}
+
+	kustomizationPath := downstreamKustomizationPath(archiveDir, downstreamName)
+	k, err := k8sutil.ReadKustomizationFromFile(kustomizationPath)
+	if err != nil {
+		return false, errors.Wrap(err, "failed to read downstream kustomization")
+	}
+
+	for _, patch := range k.Patches {
+		if isExclusionPatch(patch) && exclusion.matches(patch.Target) {
+			return false, nil
+		}
+	}
+
+	k.Patches = append(k.Patches, kustomizetypes.Patch{
+		// the name in the patch is not used, the target selects the resource to delete
+		Patch: fmt.Sprintf("%s\nkind: %s\nmetadata:\n  name: %s\n", deletePatchDirective, exclusion.Kind, exclusion.Name),
+		Target: &kustomizetypes.Selector{
+			Gvk:       resid.Gvk{Kind: exclusion.Kind},
+			Name:      exclusion.Name,
+			Namespace: exclusion.Namespace,
+		},
+	})
+
+	if err := k8sutil.WriteKustomizationToFile(*k, kustomizationPath); err != nil {
+		return false, errors.Wrap(err, "failed to write downstream kustomization")
+	}
+
+	return true, nil
+}
+
+// RemoveExclusion removes the delete patch for the resource from the downstream kustomization.
+// It returns false if the resource was not excluded.
+func RemoveExclusion(archiveDir string, downstreamName string, exclusion ResourceExclusion) (bool, error) {
+	kustomizationPath := downstreamKustomizationPath(archiveDir, downstreamName)
+	k, err := k8sutil.ReadKustomizationFromFile(kustomizationPath)
+	if err != nil {
+		return false, errors.Wrap(err, "failed to read downstream kustomization")
+	}
+
+	removed := false
+	patches := []kustomizetypes.Patch{}
+	for _, patch := range k.Patches {
+		if isExclusionPatch(patch) && exclusion.matches(patch.Target) {
+			removed = true
+			continue
+		}
+		patches = append(patches, patch)
+	}
+	if !removed {
+		return false, nil
+	}
+	k.Patches = patches
+
+	if err := k8sutil.WriteKustomizationToFile(*k, kustomizationPath); err != nil {
+		return false, errors.Wrap(err, "failed to write downstream kustomization")
+	}
+
+	return true, nil
+}
+
+func isExclusionPatch(patch kustomizetypes.Patch) bool {
+	return patch.Target != nil && strings.HasPrefix(patch.Patch, deletePatchDirective)
+}
+
+func downstreamKustomizationPath(archiveDir string, downstreamName string) string {
+	return filepath.Join(archiveDir, "overlays", "downstreams", downstreamName, "kustomization.yaml")
+}
@@ -0,0 +1,65 @@
+package downstream
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Exclusions(t *testing.T) {
+	req := require.New(t)
+
+	archiveDir, err := ioutil.TempDir("", "kots-exclusions")
+	req.NoError(err)
+	defer os.RemoveAll(archiveDir)
+
+	downstreamDir := filepath.Join(archiveDir, "overlays", "downstreams", "this-cluster")
+	req.NoError(os.MkdirAll(downstreamDir, 0755))
+	req.NoError(ioutil.WriteFile(filepath.Join(downstreamDir, "kustomization.yaml"), []byte(`apiVersion: kustomize.config.k8s.io/v1beta1
+kind: Kustomization
+bases:
+- ../../midstream
+patches:
+- path: replicas.yaml
+  target:
+    kind: Deployment
+    name: web
+`), 0644))
+
+	ingress := ResourceExclusion{Kind: "Ingress", Name: "web"}
+
+	exclusions, err := ListExclusions(archiveDir, "this-cluster")
+	req.NoError(err)
+	req.Empty(exclusions)
+
+	added, err := AddExclusion(archiveDir, "this-cluster", ingress)
+	req.NoError(err)
+	req.True(added)
+
+	added, err = AddExclusion(archiveDir, "this-cluster", ingress)
+	req.NoError(err)
+	req.False(added)
+
+	exclusions, err = ListExclusions(archiveDir, "this-cluster")
+	req.NoError(err)
+	req.Equal([]ResourceExclusion{ingress}, exclusions)
+
+	removed, err := RemoveExclusion(archiveDir, "this-cluster", ResourceExclusion{Kind: "Deployment", Name: "web"})
+	req.NoError(err)
+	req.False(removed, "patches that are not exclusions must not be removed")
+
+	removed, err = RemoveExclusion(archiveDir, "this-cluster", ingress)
+	req.NoError(err)
+	req.True(removed)
+
+	exclusions, err = ListExclusions(archiveDir, "this-cluster")
+	req.NoError(err)
+	req.Empty(exclusions)
+
+	b, err := ioutil.ReadFile(filepath.Join(downstreamDir, "kustomization.yaml"))
+	req.NoError(err)
+	req.Contains(string(b), "replicas.yaml")
+}
@@ -0,0 +1,280 @@
+package downstream
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v2"
+)
+
+const (
+	ChangeAdded    = "added"
+	ChangeRemoved  = "removed"
+	ChangeModified = "modified"
+)
+
+// ImpactReport summarizes what deploying a version changes in the cluster, compared to the deployed version
+type ImpactReport struct {
+	Sequence int64 `json:"sequence"`
+	// DeployedSequence is nil if no version is deployed, every resource is added then
+	DeployedSequence *int64   `json:"deployedSequence,omitempty"`
+	ImagesAdded      []string `json:"imagesAdded"`
+	ImagesRemoved    []string `json:"imagesRemoved"`
+	// RollingWorkloads are the existing workloads whose pod template changes, their pods are replaced
+	RollingWorkloads []WorkloadRollout `json:"rollingWorkloads"`
+	PVCChanges       []ResourceChange  `json:"pvcChanges"`
+	CRDChanges       []ResourceChange  `json:"crdChanges"`
+	RBACChanges      []ResourceChange  `json:"rbacChanges"`
+	// EstimatedRestarts is the number of pods of the rolling workloads, pods of DaemonSets are counted once per node
+	EstimatedRestarts int `json:"estimatedRestarts"`
+}
+
+type WorkloadRollout struct {
+	Kind      string `json:"kind"`
+	Namespace string `json:"namespace,omitempty"`
+	Name      string `json:"name"`
+	Pods      int    `json:"pods"`
+}
+
+type ResourceChange struct {
+	Kind      string `json:"kind"`
+	Namespace string `json:"namespace,omitempty"`
+	Name      string `json:"name"`
+	Change    string `json:"change"`
+	// Detail describes the change, e.g. the storage request of a resized PVC
+	Detail string `json:"detail,omitempty"`
+}
+
+var rbacKinds = map[string]bool{
+	"Role":               true,
+	"ClusterRole":        true,
+	"RoleBinding":        true,
+	"ClusterRoleBinding": true,
+}
+
+var workloadKinds = map[string]bool{
+	"Deployment":  true,
+	"StatefulSet": true,
+	"DaemonSet":   true,
+}
+
+type impactResource struct {
+	Kind     string `yaml:"kind"`
+	Metadata struct {
+		Name      string `yaml:"name"`
+		Namespace string `yaml:"namespace"`
+	} `yaml:"metadata"`
+	Spec struct {
+		Replicas  *int        `yaml:"replicas"`
+		Template  interface{} `yaml:"template"`
+		Resources struct {
+			Requests map[string]string `yaml:"requests"`
+		} `yaml:"resources"`
+	} `yaml:"spec"`
+
+	content interface{}
+	images  []string
+}
+
+func (r impactResource) key() string {
+	return fmt.Sprintf("%s/%s/%s", r.Kind, r.Metadata.Namespace, r.Metadata.Name)
+}
+
+// AnalyzeImpact compares the kustomize output of the deployed version with that of the version to deploy. The
+// deployed manifests are empty if no version is deployed. nodeCount is used to estimate the restarts of DaemonSets.
+func AnalyzeImpact(deployed []byte, pending []byte, nodeCount int) (*ImpactReport, error) {
+	deployedResources, err := parseImpactResources(deployed)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to parse deployed manifests")
+	}
+	pendingResources, err := parseImpactResources(pending)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to parse pending manifests")
+	}
+
+	report := &ImpactReport{
+		ImagesAdded:      []string{},
+		ImagesRemoved:    []string{},
+		RollingWorkloads: []WorkloadRollout{},
+		PVCChanges:       []ResourceChange{},
+		CRDChanges:       []ResourceChange{},
+		RBACChanges:      []ResourceChange{},
+	}
+
+	deployedImages := map[string]bool{}
+	for _, r := range deployedResources {
+		for _, image := range r.images {
+			deployedImages[image] = true
+		}
+	}
+	pendingImages := map[string]bool{}
+	for _, r := range pendingResources {
+		for _, image := range r.images {
+			pendingImages[image] = true
+		}
+	}
+	for image := range pendingImages {
+		if !deployedImages[image] {
+			report.ImagesAdded = append(report.ImagesAdded, image)
+		}
+	}
+	for image := range deployedImages {
+		if !pendingImages[image] {
+			report.ImagesRemoved = append(report.ImagesRemoved, image)
+		}
+	}
+	sort.Strings(report.ImagesAdded)
+	sort.Strings(report.ImagesRemoved)
+
+	for key, p := range pendingResources {
+		d, exists := deployedResources[key]
+
+		if exists && workloadKinds[p.Kind] && !reflect.DeepEqual(d.Spec.Template, p.Spec.Template) {
+			pods := 1
+			if p.Kind == "DaemonSet" {
+				pods = nodeCount
+			} else if p.Spec.Replicas != nil {
+				pods = *p.Spec.Replicas
+			}
+			report.RollingWorkloads = append(report.RollingWorkloads, WorkloadRollout{
+				Kind:      p.Kind,
+				Namespace: p.Metadata.Namespace,
+				Name:      p.Metadata.Name,
+				Pods:      pods,
+			})
+			report.EstimatedRestarts += pods
+		}
+
+		change := ChangeAdded
+		if exists {
+			if reflect.DeepEqual(d.content, p.content) {
+				continue
+			}
+			change = ChangeModified
+		}
+		addResourceChange(report, p, change, pvcDetail(d, p, exists))
+	}
+
+	for key, d := range deployedResources {
+		if _, ok := pendingResources[key]; !ok {
+			addResourceChange(report, d, ChangeRemoved, "")
+		}
+	}
+
+	sort.Slice(report.RollingWorkloads, func(i, j int) bool {
+		return rolloutKey(report.RollingWorkloads[i]) < rolloutKey(report.RollingWorkloads[j])
+	})
+	sortResourceChanges(report.PVCChanges)
+	sortResourceChanges(report.CRDChanges)
+	sortResourceChanges(report.RBACChanges)
+
+	return report, nil
+}
+
+func addResourceChange(report *ImpactReport, r impactResource, change string, detail string) {
+	resourceChange := ResourceChange{
+		Kind:      r.Kind,
+		Namespace: r.Metadata.Namespace,
+		Name:      r.Metadata.Name,
+		Change:    change,
+		Detail:    detail,
+	}
+
+	switch {
+	case r.Kind == "PersistentVolumeClaim":
+		report.PVCChanges = append(report.PVCChanges, resourceChange)
+	case r.Kind == "CustomResourceDefinition":
+		report.CRDChanges = append(report.CRDChanges, resourceChange)
+	case rbacKinds[r.Kind]:
+		report.RBACChanges = append(report.RBACChanges, resourceChange)
+	}
+}
+
+// pvcDetail describes a change of the storage request of a PVC, which requires a volume expansion
+func pvcDetail(deployed impactResource, pending impactResource, exists bool) string {
+	if pending.Kind != "PersistentVolumeClaim" {
+		return ""
+	}
+	pendingStorage := pending.Spec.Resources.Requests["storage"]
+	if !exists {
+		if pendingStorage == "" {
+			return ""
+		}
+		return fmt.Sprintf("storage %s", pendingStorage)
+	}
+	deployedStorage := deployed.Spec.Resources.Requests["storage"]
+	if deployedStorage == pendingStorage {
+		return ""
+	}
+	return fmt.Sprintf("storage %s -> %s", deployedStorage, pendingStorage)
+}
+
+func parseImpactResources(manifests []byte) (map[string]impactResource, error) {
+	resources := map[string]impactResource{}
+	for _, doc := range bytes.Split(manifests, []byte("\n---\n")) {
+		if len(bytes.TrimSpace(doc)) == 0 {
+			continue
+		}
+
+		r := impactResource{}
+		if err := yaml.Unmarshal(doc, &r); err != nil {
+			return nil, errors.Wrap(err, "failed to unmarshal manifest")
+		}
+		if r.Kind == "" {
+			continue
+		}
+		if err := yaml.Unmarshal(doc, &r.content); err != nil {
+			return nil, errors.Wrap(err, "failed to unmarshal manifest content")
+		}
+		r.images = containerImages(r.content)
+
+		resources[r.key()] = r
+	}
+	return resources, nil
+}
+
+// containerImages returns the images of the containers anywhere in the resource, such as in the pod template of a
+// workload or the job template of a CronJob
+func containerImages(content interface{}) []string {
+	images := []string{}
+	switch c := content.(type) {
+	case map[interface{}]interface{}:
+		for k, v := range c {
+			key, _ := k.(string)
+			if key == "containers" || key == "initContainers" {
+				if containers, ok := v.([]interface{}); ok {
+					for _, container := range containers {
+						if m, ok := container.(map[interface{}]interface{}); ok {
+							if image, ok := m["image"].(string); ok && image != "" {
+								images = append(images, image)
+							}
+						}
+					}
+				}
+				continue
+			}
+			images = append(images, containerImages(v)...)
+		}
+	case []interface{}:
+		for _, v := range c {
+			images = append(images, containerImages(v)...)
+		}
+	}
+	return images
+}
+
+func rolloutKey(w WorkloadRollout) string {
+	return strings.Join([]string{w.Kind, w.Namespace, w.Name}, "/")
+}
+
+func sortResourceChanges(changes []ResourceChange) {
+	sort.Slice(changes, func(i, j int) bool {
+		a := strings.Join([]string{changes[i].Kind, changes[i].Namespace, changes[i].Name}, "/")
+		b := strings.Join([]string{changes[j].Kind, changes[j].Namespace, changes[j].Name}, "/")
+		return a < b
+	})
+}
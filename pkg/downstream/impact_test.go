@@ -0,0 +1,153 @@
+package downstream
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_AnalyzeImpact(t *testing.T) {
+	deployed := []byte(`apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+spec:
+  replicas: 3
+  template:
+    spec:
+      containers:
+      - name: web
+        image: web:1.0
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: worker
+spec:
+  template:
+    spec:
+      containers:
+      - name: worker
+        image: worker:1.0
+---
+apiVersion: apps/v1
+kind: DaemonSet
+metadata:
+  name: agent
+spec:
+  template:
+    spec:
+      containers:
+      - name: agent
+        image: agent:1.0
+---
+apiVersion: v1
+kind: PersistentVolumeClaim
+metadata:
+  name: data
+spec:
+  resources:
+    requests:
+      storage: 10Gi
+---
+apiVersion: rbac.authorization.k8s.io/v1
+kind: Role
+metadata:
+  name: reader
+rules: []
+`)
+
+	pending := []byte(`apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+spec:
+  replicas: 3
+  template:
+    spec:
+      containers:
+      - name: web
+        image: web:1.1
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: worker
+spec:
+  replicas: 2
+  template:
+    spec:
+      containers:
+      - name: worker
+        image: worker:1.0
+---
+apiVersion: apps/v1
+kind: DaemonSet
+metadata:
+  name: agent
+spec:
+  template:
+    spec:
+      initContainers:
+      - name: init
+        image: busybox:1.33
+      containers:
+      - name: agent
+        image: agent:1.0
+---
+apiVersion: v1
+kind: PersistentVolumeClaim
+metadata:
+  name: data
+spec:
+  resources:
+    requests:
+      storage: 20Gi
+---
+apiVersion: apiextensions.k8s.io/v1
+kind: CustomResourceDefinition
+metadata:
+  name: widgets.example.com
+`)
+
+	report, err := AnalyzeImpact(deployed, pending, 4)
+	require.NoError(t, err)
+
+	require.Equal(t, []string{"busybox:1.33", "web:1.1"}, report.ImagesAdded)
+	require.Equal(t, []string{"web:1.0"}, report.ImagesRemoved)
+	require.Equal(t, []WorkloadRollout{
+		{Kind: "DaemonSet", Name: "agent", Pods: 4},
+		{Kind: "Deployment", Name: "web", Pods: 3},
+	}, report.RollingWorkloads)
+	require.Equal(t, 7, report.EstimatedRestarts)
+	require.Equal(t, []ResourceChange{
+		{Kind: "PersistentVolumeClaim", Name: "data", Change: ChangeModified, Detail: "storage 10Gi -> 20Gi"},
+	}, report.PVCChanges)
+	require.Equal(t, []ResourceChange{
+		{Kind: "CustomResourceDefinition", Name: "widgets.example.com", Change: ChangeAdded},
+	}, report.CRDChanges)
+	require.Equal(t, []ResourceChange{
+		{Kind: "Role", Name: "reader", Change: ChangeRemoved},
+	}, report.RBACChanges)
+}
+
+func Test_AnalyzeImpactFirstDeploy(t *testing.T) {
+	pending := []byte(`apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+spec:
+  template:
+    spec:
+      containers:
+      - name: web
+        image: web:1.0
+`)
+
+	report, err := AnalyzeImpact(nil, pending, 1)
+	require.NoError(t, err)
+
+	require.Equal(t, []string{"web:1.0"}, report.ImagesAdded)
+	require.Empty(t, report.RollingWorkloads)
+	require.Equal(t, 0, report.EstimatedRestarts)
+}
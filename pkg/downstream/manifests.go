@@ -0,0 +1,134 @@
+package downstream
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/replicatedhq/kots/pkg/base"
+	"gopkg.in/yaml.v2"
+)
+
+const RedactedValue = "***REDACTED***"
+
+// RenderedManifest is a single resource from the kustomize output of a downstream
+type RenderedManifest struct {
+	APIVersion string `json:"apiVersion"`
+	Kind       string `json:"kind"`
+	Name       string `json:"name"`
+	Namespace  string `json:"namespace,omitempty"`
+	Content    string `json:"content"`
+}
+
+type ManifestFilter struct {
+	// Kind and Name are matched case insensitively, empty matches everything
+	Kind string
+	Name string
+	// IncludeSecrets returns the values of Secret resources instead of redacting them
+	IncludeSecrets bool
+}
+
+// RenderManifests runs kustomize build for the downstream in an extracted app version archive.
+// The first downstream is used if downstreamName is empty, and the midstream if there are no downstreams.
+func RenderManifests(archiveDir string, downstreamName string, kustomizeVersion string) ([]byte, error) {
+	buildTarget := filepath.Join(archiveDir, "overlays", "midstream")
+	if downstreamName != "" {
+		if downstreamName != filepath.Base(downstreamName) || downstreamName == "." || downstreamName == ".." {
+			return nil, errors.Errorf("invalid downstream name %q", downstreamName)
+		}
+		buildTarget = filepath.Join(archiveDir, "overlays", "downstreams", downstreamName)
+	} else {
+		children, err := ioutil.ReadDir(filepath.Join(archiveDir, "overlays", "downstreams"))
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to read downstreams dir")
+		}
+		for _, child := range children {
+			if child.IsDir() {
+				buildTarget = filepath.Join(archiveDir, "overlays", "downstreams", child.Name())
+				break
+			}
+		}
+	}
+
+	out, err := exec.Command(fmt.Sprintf("kustomize%s", kustomizeVersion), "build", buildTarget).Output()
+	if err != nil {
+		if ee, ok := err.(*exec.ExitError); ok {
+			return nil, errors.Errorf("kustomize stderr: %q", string(ee.Stderr))
+		}
+		return nil, errors.Wrap(err, "failed to run kustomize build")
+	}
+
+	return out, nil
+}
+
+// FilterManifests splits the multi-doc kustomize output into resources that match the filter,
+// redacting the values of Secrets unless the filter includes them
+func FilterManifests(manifests []byte, filter ManifestFilter) ([]RenderedManifest, error) {
+	result := []RenderedManifest{}
+	for _, doc := range bytes.Split(manifests, []byte("\n---\n")) {
+		if len(bytes.TrimSpace(doc)) == 0 {
+			continue
+		}
+
+		o := base.OverlySimpleGVK{}
+		if err := yaml.Unmarshal(doc, &o); err != nil {
+			return nil, errors.Wrap(err, "failed to unmarshal manifest")
+		}
+		if filter.Kind != "" && !strings.EqualFold(filter.Kind, o.Kind) {
+			continue
+		}
+		if filter.Name != "" && !strings.EqualFold(filter.Name, o.Metadata.Name) {
+			continue
+		}
+
+		content := doc
+		if o.Kind == "Secret" && !filter.IncludeSecrets {
+			redacted, err := redactSecret(doc)
+			if err != nil {
+				return nil, errors.Wrapf(err, "failed to redact secret %s", o.Metadata.Name)
+			}
+			content = redacted
+		}
+
+		result = append(result, RenderedManifest{
+			APIVersion: o.APIVersion,
+			Kind:       o.Kind,
+			Name:       o.Metadata.Name,
+			Namespace:  o.Metadata.Namespace,
+			Content:    strings.TrimSpace(string(content)) + "\n",
+		})
+	}
+
+	return result, nil
+}
+
+func redactSecret(doc []byte) ([]byte, error) {
+	secret := yaml.MapSlice{}
+	if err := yaml.Unmarshal(doc, &secret); err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal")
+	}
+
+	for i, item := range secret {
+		if item.Key != "data" && item.Key != "stringData" {
+			continue
+		}
+		values, ok := item.Value.(yaml.MapSlice)
+		if !ok {
+			continue
+		}
+		for j := range values {
+			values[j].Value = RedactedValue
+		}
+		secret[i].Value = values
+	}
+
+	b, err := yaml.Marshal(secret)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal")
+	}
+	return b, nil
+}
@@ -0,0 +1,94 @@
+package downstream
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_FilterManifests(t *testing.T) {
+	manifests := []byte(`apiVersion: v1
+kind: Secret
+metadata:
+  name: db-creds
+data:
+  password: c2VjcmV0
+stringData:
+  user: admin
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+  namespace: app
+spec:
+  replicas: 1
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: web
+`)
+
+	tests := []struct {
+		name         string
+		filter       ManifestFilter
+		wantKinds    []string
+		wantInSecret string
+	}{
+		{
+			name:         "all manifests with secrets redacted",
+			filter:       ManifestFilter{},
+			wantKinds:    []string{"Secret", "Deployment", "Service"},
+			wantInSecret: RedactedValue,
+		},
+		{
+			name:         "include secrets",
+			filter:       ManifestFilter{Kind: "secret", IncludeSecrets: true},
+			wantKinds:    []string{"Secret"},
+			wantInSecret: "c2VjcmV0",
+		},
+		{
+			name:      "filter by name",
+			filter:    ManifestFilter{Name: "web"},
+			wantKinds: []string{"Deployment", "Service"},
+		},
+		{
+			name:      "filter by kind and name",
+			filter:    ManifestFilter{Kind: "Deployment", Name: "web"},
+			wantKinds: []string{"Deployment"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			req := require.New(t)
+
+			result, err := FilterManifests(manifests, test.filter)
+			req.NoError(err)
+
+			kinds := []string{}
+			for _, manifest := range result {
+				kinds = append(kinds, manifest.Kind)
+				if manifest.Kind == "Secret" {
+					req.Contains(manifest.Content, test.wantInSecret)
+					if test.wantInSecret == RedactedValue {
+						req.NotContains(manifest.Content, "c2VjcmV0")
+						req.NotContains(manifest.Content, "admin")
+					}
+				}
+			}
+			req.Equal(test.wantKinds, kinds)
+		})
+	}
+}
+
+func Test_RenderManifestsInvalidDownstream(t *testing.T) {
+	for _, name := range []string{"..", ".", "../../base", "this-cluster/../../upstream", "/etc"} {
+		t.Run(name, func(t *testing.T) {
+			_, err := RenderManifests("archive", name, "")
+			require.Error(t, err)
+			require.Contains(t, err.Error(), "invalid downstream name")
+		})
+	}
+}
@@ -0,0 +1,111 @@
+package downstream
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/pkg/errors"
+	downstreamtypes "github.com/replicatedhq/kots/pkg/api/downstream/types"
+	"github.com/replicatedhq/kots/pkg/store"
+)
+
+const (
+	// DefaultOutputRetention is the number of sequences for which the full output archive is kept
+	DefaultOutputRetention = 100
+)
+
+// WriteOutputArchive writes the dry run, apply, and render output as separate files in a gzipped tarball
+func WriteOutputArchive(output downstreamtypes.DownstreamOutput, w io.Writer) error {
+	gzipWriter := gzip.NewWriter(w)
+	tarWriter := tar.NewWriter(gzipWriter)
+
+	files := []struct {
+		name     string
+		contents string
+	}{
+		{"dryrun-stdout.log", output.DryrunStdout},
+		{"dryrun-stderr.log", output.DryrunStderr},
+		{"apply-stdout.log", output.ApplyStdout},
+		{"apply-stderr.log", output.ApplyStderr},
+		{"render-error.log", output.RenderError},
+	}
+
+	now := time.Now()
+	for _, file := range files {
+		if file.contents == "" {
+			continue
+		}
+
+		header := &tar.Header{
+			Name:    file.name,
+			Mode:    0644,
+			Size:    int64(len(file.contents)),
+			ModTime: now,
+		}
+		if err := tarWriter.WriteHeader(header); err != nil {
+			return errors.Wrapf(err, "failed to write header for %s", file.name)
+		}
+		if _, err := io.WriteString(tarWriter, file.contents); err != nil {
+			return errors.Wrapf(err, "failed to write %s", file.name)
+		}
+	}
+
+	if err := tarWriter.Close(); err != nil {
+		return errors.Wrap(err, "failed to close tar writer")
+	}
+	if err := gzipWriter.Close(); err != nil {
+		return errors.Wrap(err, "failed to close gzip writer")
+	}
+
+	return nil
+}
+
+// SaveOutputArchive stores the full output for a sequence and removes the archives that are
+// older than the retention policy allows
+func SaveOutputArchive(appID string, clusterID string, sequence int64, output downstreamtypes.DownstreamOutput) error {
+	tmpDir, err := ioutil.TempDir("", "kotsadm")
+	if err != nil {
+		return errors.Wrap(err, "failed to create temp dir")
+	}
+	defer os.RemoveAll(tmpDir)
+
+	archivePath := filepath.Join(tmpDir, "output.tar.gz")
+	f, err := os.Create(archivePath)
+	if err != nil {
+		return errors.Wrap(err, "failed to create archive file")
+	}
+	defer f.Close()
+
+	if err := WriteOutputArchive(output, f); err != nil {
+		return errors.Wrap(err, "failed to write output archive")
+	}
+
+	if err := store.GetStore().CreateDownstreamOutputArchive(appID, clusterID, sequence, archivePath); err != nil {
+		return errors.Wrap(err, "failed to store output archive")
+	}
+
+	retention := GetOutputRetention()
+	if retention > 0 && sequence-int64(retention) >= 0 {
+		if err := store.GetStore().DeleteDownstreamOutputArchivesBefore(appID, clusterID, sequence-int64(retention)+1); err != nil {
+			return errors.Wrap(err, "failed to delete expired output archives")
+		}
+	}
+
+	return nil
+}
+
+// GetOutputRetention returns the number of sequences to keep output archives for.
+// A value of 0 disables pruning.
+func GetOutputRetention() int {
+	retention, err := strconv.Atoi(os.Getenv("DOWNSTREAM_OUTPUT_RETENTION"))
+	if err != nil || retention < 0 {
+		return DefaultOutputRetention
+	}
+	return retention
+}
@@ -0,0 +1,139 @@
+package downstream
+
+import (
+	"bufio"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/marccampbell/yaml-toolbox/pkg/splitter"
+	"github.com/pkg/errors"
+)
+
+// DefaultMaxSearchMatches is the number of matching lines a search returns when the options don't limit it
+const DefaultMaxSearchMatches = 1000
+
+type SearchOptions struct {
+	// Query is a plain text, case insensitive query unless Regex is set
+	Query string
+	Regex bool
+	// File is a glob matched against the file names of the rendered contents, empty matches everything
+	File string
+	// Filter selects the resources to search in, the values of secrets are redacted before searching unless it
+	// includes them
+	Filter ManifestFilter
+	// MaxMatches is the number of matching lines after which the search stops
+	MaxMatches int
+}
+
+type SearchMatch struct {
+	// Line is 1-based
+	Line int    `json:"line"`
+	Text string `json:"text"`
+}
+
+type SearchResult struct {
+	File      string        `json:"file"`
+	Kind      string        `json:"kind"`
+	Name      string        `json:"name"`
+	Namespace string        `json:"namespace,omitempty"`
+	Matches   []SearchMatch `json:"matches"`
+}
+
+// SearchManifests searches the kustomize output of a downstream, split into the same files as the rendered contents.
+// Results are sorted by file name. The returned bool is true if the search stopped at MaxMatches.
+func SearchManifests(manifests []byte, opts SearchOptions) ([]SearchResult, bool, error) {
+	match, err := searchMatcher(opts)
+	if err != nil {
+		return nil, false, err
+	}
+
+	maxMatches := opts.MaxMatches
+	if maxMatches <= 0 {
+		maxMatches = DefaultMaxSearchMatches
+	}
+
+	files, err := splitter.SplitYAML(manifests)
+	if err != nil {
+		return nil, false, errors.Wrap(err, "failed to split manifests")
+	}
+
+	filenames := []string{}
+	for filename := range files {
+		if opts.File != "" {
+			matched, err := path.Match(opts.File, filename)
+			if err != nil {
+				return nil, false, errors.Wrap(err, "invalid file pattern")
+			}
+			if !matched {
+				continue
+			}
+		}
+		filenames = append(filenames, filename)
+	}
+	sort.Strings(filenames)
+
+	results := []SearchResult{}
+	matchCount := 0
+	for _, filename := range filenames {
+		rendered, err := FilterManifests(files[filename], opts.Filter)
+		if err != nil {
+			return nil, false, errors.Wrapf(err, "failed to filter %s", filename)
+		}
+
+		for _, manifest := range rendered {
+			result := SearchResult{
+				File:      filename,
+				Kind:      manifest.Kind,
+				Name:      manifest.Name,
+				Namespace: manifest.Namespace,
+				Matches:   []SearchMatch{},
+			}
+
+			scanner := bufio.NewScanner(strings.NewReader(manifest.Content))
+			scanner.Buffer(make([]byte, 0, 64*1024), len(manifest.Content)+1)
+			line := 0
+			for scanner.Scan() {
+				line++
+				if !match(scanner.Text()) {
+					continue
+				}
+				result.Matches = append(result.Matches, SearchMatch{Line: line, Text: scanner.Text()})
+				matchCount++
+				if matchCount >= maxMatches {
+					results = append(results, result)
+					return results, true, nil
+				}
+			}
+			if err := scanner.Err(); err != nil {
+				return nil, false, errors.Wrapf(err, "failed to scan %s", filename)
+			}
+
+			if len(result.Matches) > 0 {
+				results = append(results, result)
+			}
+		}
+	}
+
+	return results, false, nil
+}
+
+func searchMatcher(opts SearchOptions) (func(line string) bool, error) {
+	if opts.Query == "" {
+		return nil, errors.New("query is required")
+	}
+
+	if opts.Regex {
+		re, err := regexp.Compile(opts.Query)
+		if err != nil {
+			return nil, errors.Wrap(err, "invalid regular expression")
+		}
+		return re.MatchString, nil
+	}
+
+	query := strings.ToLower(opts.Query)
+	return func(line string) bool {
+		return strings.Contains(strings.ToLower(line), query)
+	}, nil
+}
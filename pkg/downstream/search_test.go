@@ -0,0 +1,91 @@
+package downstream
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_SearchManifests(t *testing.T) {
+	manifests := []byte(`apiVersion: v1
+kind: Secret
+metadata:
+  name: db-creds
+stringData:
+  DATABASE_URL: postgres://db
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+spec:
+  template:
+    spec:
+      containers:
+      - name: web
+        image: nginx:1.19
+        env:
+        - name: DATABASE_URL
+          value: postgres://db
+`)
+
+	tests := []struct {
+		name          string
+		opts          SearchOptions
+		wantLines     map[string]int // kind -> line of the first match
+		wantTruncated bool
+		wantErr       bool
+	}{
+		{
+			name:      "case insensitive text",
+			opts:      SearchOptions{Query: "database_url"},
+			wantLines: map[string]int{"Deployment": 12, "Secret": 6},
+		},
+		{
+			name:      "secret values are redacted",
+			opts:      SearchOptions{Query: "postgres://"},
+			wantLines: map[string]int{"Deployment": 13},
+		},
+		{
+			name:      "regex with kind filter",
+			opts:      SearchOptions{Query: `image: nginx:\d`, Regex: true, Filter: ManifestFilter{Kind: "deployment"}},
+			wantLines: map[string]int{"Deployment": 10},
+		},
+		{
+			name:          "stops at max matches",
+			opts:          SearchOptions{Query: "name: web", Filter: ManifestFilter{Kind: "Deployment"}, MaxMatches: 1},
+			wantLines:     map[string]int{"Deployment": 4},
+			wantTruncated: true,
+		},
+		{
+			name:    "invalid regex",
+			opts:    SearchOptions{Query: "(", Regex: true},
+			wantErr: true,
+		},
+		{
+			name:    "empty query",
+			opts:    SearchOptions{},
+			wantErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			req := require.New(t)
+
+			results, truncated, err := SearchManifests(manifests, test.opts)
+			if test.wantErr {
+				req.Error(err)
+				return
+			}
+			req.NoError(err)
+			req.Equal(test.wantTruncated, truncated)
+
+			lines := map[string]int{}
+			for _, result := range results {
+				lines[result.Kind] = result.Matches[0].Line
+			}
+			req.Equal(test.wantLines, lines)
+		})
+	}
+}
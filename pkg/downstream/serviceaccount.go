@@ -0,0 +1,28 @@
+package downstream
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+// ServiceAccountUsername returns the username that the operator impersonates to apply manifests as the
+// service account, which is formatted as "namespace/name"
+func ServiceAccountUsername(serviceAccount string) (string, error) {
+	parts := strings.Split(serviceAccount, "/")
+	if len(parts) != 2 {
+		return "", errors.Errorf("service account %q must be formatted as namespace/name", serviceAccount)
+	}
+
+	namespace, name := parts[0], parts[1]
+	if errs := validation.IsDNS1123Label(namespace); len(errs) > 0 {
+		return "", errors.Errorf("invalid namespace %q: %s", namespace, strings.Join(errs, ", "))
+	}
+	if errs := validation.IsDNS1123Subdomain(name); len(errs) > 0 {
+		return "", errors.Errorf("invalid service account name %q: %s", name, strings.Join(errs, ", "))
+	}
+
+	return fmt.Sprintf("system:serviceaccount:%s:%s", namespace, name), nil
+}
@@ -0,0 +1,53 @@
+package downstream
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_ServiceAccountUsername(t *testing.T) {
+	tests := []struct {
+		name           string
+		serviceAccount string
+		want           string
+		wantErr        bool
+	}{
+		{
+			name:           "valid",
+			serviceAccount: "app/deployer",
+			want:           "system:serviceaccount:app:deployer",
+		},
+		{
+			name:           "missing namespace",
+			serviceAccount: "deployer",
+			wantErr:        true,
+		},
+		{
+			name:           "too many parts",
+			serviceAccount: "app/deployer/extra",
+			wantErr:        true,
+		},
+		{
+			name:           "invalid namespace",
+			serviceAccount: "App/deployer",
+			wantErr:        true,
+		},
+		{
+			name:           "empty name",
+			serviceAccount: "app/",
+			wantErr:        true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ServiceAccountUsername(tt.serviceAccount)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
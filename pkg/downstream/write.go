@@ -12,6 +12,8 @@ import (
 type WriteOptions struct {
 	DownstreamDir string
 	MidstreamDir  string
+	// AdditionalManifests are written to an overlay of the downstream when it is created, keyed by file name
+	AdditionalManifests map[string][]byte
 }
 
 func (d *Downstream) WriteDownstream(options WriteOptions) error {
@@ -43,6 +45,13 @@ func (d *Downstream) WriteDownstream(options WriteOptions) error {
 		relativeMidstreamDir,
 	}
 
+	if len(options.AdditionalManifests) > 0 {
+		if err := writeAdditionalManifests(filepath.Join(renderDir, AdditionalManifestsDirName), options.AdditionalManifests); err != nil {
+			return errors.Wrap(err, "failed to write additional manifests")
+		}
+		d.Kustomization.Bases = append(d.Kustomization.Bases, AdditionalManifestsDirName)
+	}
+
 	if err := k8sutil.WriteKustomizationToFile(*d.Kustomization, fileRenderPath); err != nil {
 		return errors.Wrap(err, "failed to write kustomization to file")
 	}
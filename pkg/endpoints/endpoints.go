@@ -0,0 +1,229 @@
+package endpoints
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/replicatedhq/kots/pkg/logger"
+)
+
+const (
+	// unhealthyPeriod is how long an endpoint that failed is tried after the other endpoints
+	unhealthyPeriod = 5 * time.Minute
+	probeTimeout    = 5 * time.Second
+)
+
+// Mirrors are alternative endpoints of the vendor for installations that cannot reach the vendor endpoints, for
+// example because of regional egress restrictions. Requests go to the vendor endpoint first, and fail over to the
+// mirrors in order when it cannot be reached.
+type Mirrors struct {
+	// ReplicatedAPI are urls of mirrors of the replicated api that is the endpoint of the license
+	ReplicatedAPI []string
+	// Registry are hosts of mirrors of the replicated registry
+	Registry []string
+	// Proxy are hosts of mirrors of the replicated proxy registry
+	Proxy []string
+}
+
+var (
+	mirrorsMtx sync.RWMutex
+	mirrors    Mirrors
+
+	health = newHealthTracker()
+)
+
+// SetMirrors sets the mirrors used for requests to the vendor endpoints
+func SetMirrors(m Mirrors) {
+	mirrorsMtx.Lock()
+	defer mirrorsMtx.Unlock()
+
+	mirrors = Mirrors{
+		ReplicatedAPI: normalize(m.ReplicatedAPI, func(s string) string { return strings.TrimSuffix(s, "/") }),
+		Registry:      normalize(m.Registry, normalizeHost),
+		Proxy:         normalize(m.Proxy, normalizeHost),
+	}
+}
+
+// GetMirrors returns the mirrors used for requests to the vendor endpoints
+func GetMirrors() Mirrors {
+	mirrorsMtx.RLock()
+	defer mirrorsMtx.RUnlock()
+	return mirrors
+}
+
+// ReplicatedAPIEndpoints returns the endpoints to send requests for the replicated api to, in the order they should
+// be tried: the endpoint of the license and its mirrors, with the endpoints that failed recently last
+func ReplicatedAPIEndpoints(primary string) []string {
+	return health.order(append([]string{strings.TrimSuffix(primary, "/")}, GetMirrors().ReplicatedAPI...))
+}
+
+// DoReplicatedAPI sends the request that newRequest builds for an endpoint of the replicated api, failing over to
+// the next endpoint when the endpoint cannot be reached or returns a server error. The response of the last
+// endpoint is returned as is.
+func DoReplicatedAPI(primary string, newRequest func(endpoint string) (*http.Request, error)) (*http.Response, error) {
+	return do(http.DefaultClient, ReplicatedAPIEndpoints(primary), newRequest)
+}
+
+// MarkFailed records that a request to the endpoint failed, for requests that are not sent with DoReplicatedAPI
+func MarkFailed(endpoint string) {
+	health.markUnhealthy(strings.TrimSuffix(endpoint, "/"))
+}
+
+// RegistryHost returns the host to pull images of the replicated registry from
+func RegistryHost(primary string) string {
+	return selectHost(primary, GetMirrors().Registry)
+}
+
+// ProxyHost returns the host to pull images through the replicated proxy registry from
+func ProxyHost(primary string) string {
+	return selectHost(primary, GetMirrors().Proxy)
+}
+
+func do(client *http.Client, endpoints []string, newRequest func(endpoint string) (*http.Request, error)) (*http.Response, error) {
+	var lastErr error
+	for i, endpoint := range endpoints {
+		req, err := newRequest(endpoint)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to create request")
+		}
+
+		isLast := i == len(endpoints)-1
+		resp, err := client.Do(req)
+		if err == nil && resp.StatusCode < 500 {
+			health.markHealthy(endpoint)
+			return resp, nil
+		}
+
+		health.markUnhealthy(endpoint)
+		if err == nil {
+			if isLast {
+				return resp, nil
+			}
+			resp.Body.Close()
+			err = errors.Errorf("unexpected status code %d", resp.StatusCode)
+		}
+		lastErr = errors.Wrapf(err, "failed to send request to %s", endpoint)
+		if !isLast {
+			logger.Infof("Request to %s failed, failing over to the next endpoint: %v", endpoint, err)
+		}
+	}
+	return nil, lastErr
+}
+
+// selectHost returns the first healthy host of the primary host and its mirrors. Hosts are probed on the registry
+// api, and the primary host is returned when none of them respond.
+func selectHost(primary string, hostMirrors []string) string {
+	if len(hostMirrors) == 0 {
+		return primary
+	}
+
+	client := &http.Client{Timeout: probeTimeout}
+	for _, host := range health.order(append([]string{primary}, hostMirrors...)) {
+		if health.isRecentlyHealthy(host) {
+			return host
+		}
+		if probeRegistry(client, host) {
+			health.markHealthy(host)
+			return host
+		}
+		health.markUnhealthy(host)
+	}
+
+	return primary
+}
+
+func probeRegistry(client *http.Client, host string) bool {
+	resp, err := client.Get(fmt.Sprintf("https://%s/v2/", host))
+	if err != nil {
+		logger.Debugf("failed to probe registry %s: %v", host, err)
+		return false
+	}
+	defer resp.Body.Close()
+
+	// the registry api responds with 401 without credentials
+	return resp.StatusCode < 500
+}
+
+func normalizeHost(host string) string {
+	host = strings.TrimPrefix(host, "https://")
+	host = strings.TrimPrefix(host, "http://")
+	return strings.TrimSuffix(host, "/")
+}
+
+func normalize(values []string, fn func(string) string) []string {
+	normalized := []string{}
+	for _, value := range values {
+		if value = fn(strings.TrimSpace(value)); value != "" {
+			normalized = append(normalized, value)
+		}
+	}
+	return normalized
+}
+
+// healthTracker remembers which endpoints failed and which responded recently
+type healthTracker struct {
+	mtx       sync.Mutex
+	failedAt  map[string]time.Time
+	healthyAt map[string]time.Time
+	now       func() time.Time
+}
+
+func newHealthTracker() *healthTracker {
+	return &healthTracker{
+		failedAt:  map[string]time.Time{},
+		healthyAt: map[string]time.Time{},
+		now:       time.Now,
+	}
+}
+
+// order returns the endpoints without duplicates, with the endpoints that failed recently moved to the end
+func (h *healthTracker) order(endpoints []string) []string {
+	h.mtx.Lock()
+	defer h.mtx.Unlock()
+
+	healthy := []string{}
+	unhealthy := []string{}
+	seen := map[string]bool{}
+	for _, endpoint := range endpoints {
+		if seen[endpoint] {
+			continue
+		}
+		seen[endpoint] = true
+
+		if failedAt, ok := h.failedAt[endpoint]; ok && h.now().Sub(failedAt) < unhealthyPeriod {
+			unhealthy = append(unhealthy, endpoint)
+		} else {
+			healthy = append(healthy, endpoint)
+		}
+	}
+
+	return append(healthy, unhealthy...)
+}
+
+func (h *healthTracker) isRecentlyHealthy(endpoint string) bool {
+	h.mtx.Lock()
+	defer h.mtx.Unlock()
+
+	healthyAt, ok := h.healthyAt[endpoint]
+	return ok && h.now().Sub(healthyAt) < unhealthyPeriod
+}
+
+func (h *healthTracker) markHealthy(endpoint string) {
+	h.mtx.Lock()
+	defer h.mtx.Unlock()
+
+	delete(h.failedAt, endpoint)
+	h.healthyAt[endpoint] = h.now()
+}
+
+func (h *healthTracker) markUnhealthy(endpoint string) {
+	h.mtx.Lock()
+	defer h.mtx.Unlock()
+
+	delete(h.healthyAt, endpoint)
+	h.failedAt[endpoint] = h.now()
+}
@@ -0,0 +1,87 @@
+package endpoints
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_healthTrackerOrder(t *testing.T) {
+	now := time.Date(2021, 6, 1, 0, 0, 0, 0, time.UTC)
+	h := newHealthTracker()
+	h.now = func() time.Time { return now }
+
+	endpoints := []string{"https://replicated.app", "https://mirror-1.example.com", "https://mirror-2.example.com", "https://replicated.app"}
+	require.Equal(t, []string{"https://replicated.app", "https://mirror-1.example.com", "https://mirror-2.example.com"}, h.order(endpoints))
+
+	h.markUnhealthy("https://replicated.app")
+	require.Equal(t, []string{"https://mirror-1.example.com", "https://mirror-2.example.com", "https://replicated.app"}, h.order(endpoints))
+
+	// failed endpoints are tried first again after a while
+	now = now.Add(unhealthyPeriod)
+	require.Equal(t, []string{"https://replicated.app", "https://mirror-1.example.com", "https://mirror-2.example.com"}, h.order(endpoints))
+
+	h.markUnhealthy("https://mirror-1.example.com")
+	h.markHealthy("https://mirror-1.example.com")
+	require.Equal(t, []string{"https://replicated.app", "https://mirror-1.example.com", "https://mirror-2.example.com"}, h.order(endpoints))
+}
+
+func Test_do(t *testing.T) {
+	health = newHealthTracker()
+
+	primaryRequests := 0
+	primary := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		primaryRequests++
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer primary.Close()
+
+	mirror := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer mirror.Close()
+
+	newRequest := func(endpoint string) (*http.Request, error) {
+		return http.NewRequest("GET", endpoint+"/release/my-app", nil)
+	}
+
+	resp, err := do(http.DefaultClient, health.order([]string{primary.URL, mirror.URL}), newRequest)
+	require.NoError(t, err)
+	resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.Equal(t, 1, primaryRequests)
+
+	// the mirror is tried first while the primary endpoint is unhealthy
+	resp, err = do(http.DefaultClient, health.order([]string{primary.URL, mirror.URL}), newRequest)
+	require.NoError(t, err)
+	resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.Equal(t, 1, primaryRequests)
+
+	// the response of the last endpoint is returned when all of them fail
+	resp, err = do(http.DefaultClient, []string{primary.URL}, newRequest)
+	require.NoError(t, err)
+	resp.Body.Close()
+	require.Equal(t, http.StatusBadGateway, resp.StatusCode)
+}
+
+func Test_SetMirrors(t *testing.T) {
+	defer SetMirrors(Mirrors{})
+
+	SetMirrors(Mirrors{
+		ReplicatedAPI: []string{"https://mirror.example.com/", " "},
+		Registry:      []string{"https://registry-mirror.example.com/"},
+		Proxy:         []string{"proxy-mirror.example.com"},
+	})
+
+	require.Equal(t, Mirrors{
+		ReplicatedAPI: []string{"https://mirror.example.com"},
+		Registry:      []string{"registry-mirror.example.com"},
+		Proxy:         []string{"proxy-mirror.example.com"},
+	}, GetMirrors())
+
+	require.Equal(t, []string{"https://replicated.app", "https://mirror.example.com"}, ReplicatedAPIEndpoints("https://replicated.app/"))
+}
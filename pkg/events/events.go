@@ -0,0 +1,84 @@
+package events
+
+import (
+	"sync"
+	"time"
+
+	"github.com/replicatedhq/kots/pkg/events/types"
+	"github.com/replicatedhq/kots/pkg/logger"
+)
+
+const (
+	// subscriptionBufferSize is how many events a subscriber can fall behind before events are dropped for it
+	subscriptionBufferSize = 64
+)
+
+var (
+	subscriptionsLock = sync.Mutex{}
+	subscriptions     = map[*Subscription]struct{}{}
+)
+
+// Subscription receives the events published after it was created. Events are only delivered within this kotsadm
+// process, and a subscriber that does not keep up misses events, so subscribers should refresh the state they
+// display when Dropped is set.
+type Subscription struct {
+	C chan types.Event
+
+	droppedLock sync.Mutex
+	dropped     bool
+}
+
+func Subscribe() *Subscription {
+	s := &Subscription{
+		C: make(chan types.Event, subscriptionBufferSize),
+	}
+
+	subscriptionsLock.Lock()
+	defer subscriptionsLock.Unlock()
+
+	subscriptions[s] = struct{}{}
+
+	return s
+}
+
+// Close stops the delivery of events to the subscription
+func (s *Subscription) Close() {
+	subscriptionsLock.Lock()
+	defer subscriptionsLock.Unlock()
+
+	delete(subscriptions, s)
+}
+
+// Dropped reports whether events were dropped since the last call, and resets it
+func (s *Subscription) Dropped() bool {
+	s.droppedLock.Lock()
+	defer s.droppedLock.Unlock()
+
+	dropped := s.dropped
+	s.dropped = false
+	return dropped
+}
+
+// Publish sends the event to every subscription without blocking
+func Publish(eventType types.EventType, appID string, data interface{}) {
+	event := types.Event{
+		Type:      eventType,
+		AppID:     appID,
+		Data:      data,
+		CreatedAt: time.Now(),
+	}
+
+	subscriptionsLock.Lock()
+	defer subscriptionsLock.Unlock()
+
+	for s := range subscriptions {
+		select {
+		case s.C <- event:
+		default:
+			logger.Debugf("dropping %s event for slow subscriber", eventType)
+			s.droppedLock.Lock()
+			s.dropped = true
+			s.droppedLock.Unlock()
+		}
+	}
+}
@@ -0,0 +1,43 @@
+package events
+
+import (
+	"testing"
+
+	"github.com/replicatedhq/kots/pkg/events/types"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Publish(t *testing.T) {
+	s := Subscribe()
+	defer s.Close()
+
+	Publish(types.EventTypeNewVersion, "app-id", types.NewVersionData{Sequence: 1})
+
+	event := <-s.C
+	require.Equal(t, types.EventTypeNewVersion, event.Type)
+	require.Equal(t, "app-id", event.AppID)
+	require.Equal(t, types.NewVersionData{Sequence: 1}, event.Data)
+	require.False(t, s.Dropped())
+}
+
+func Test_PublishSlowSubscriber(t *testing.T) {
+	s := Subscribe()
+	defer s.Close()
+
+	for i := 0; i < subscriptionBufferSize+1; i++ {
+		Publish(types.EventTypeTaskStatus, "", types.TaskStatusData{ID: "update-download"})
+	}
+
+	require.Len(t, s.C, subscriptionBufferSize)
+	require.True(t, s.Dropped())
+	require.False(t, s.Dropped())
+}
+
+func Test_Close(t *testing.T) {
+	s := Subscribe()
+	s.Close()
+
+	Publish(types.EventTypeTaskStatus, "", types.TaskStatusData{ID: "update-download"})
+
+	require.Len(t, s.C, 0)
+}
@@ -0,0 +1,40 @@
+package types
+
+import "time"
+
+type EventType string
+
+const (
+	// EventTypeAppStatus is published when the status informers report a change of the resources of an app
+	EventTypeAppStatus EventType = "appStatus"
+	// EventTypeTaskStatus is published when the status of a task, such as "update-download", is set or cleared
+	EventTypeTaskStatus EventType = "taskStatus"
+	// EventTypeNewVersion is published when a version of an app is created
+	EventTypeNewVersion EventType = "newVersion"
+)
+
+type Event struct {
+	Type EventType `json:"type"`
+	// AppID is empty for events that are not about an app, which are sent to every subscriber
+	AppID     string      `json:"appId,omitempty"`
+	Data      interface{} `json:"data"`
+	CreatedAt time.Time   `json:"createdAt"`
+}
+
+type AppStatusData struct {
+	State    string `json:"state"`
+	Sequence int64  `json:"sequence"`
+}
+
+type TaskStatusData struct {
+	ID      string `json:"id"`
+	Status  string `json:"status"`
+	Message string `json:"message"`
+	// Cleared is true when the task status was removed
+	Cleared bool `json:"cleared,omitempty"`
+}
+
+type NewVersionData struct {
+	Sequence int64  `json:"sequence"`
+	Source   string `json:"source"`
+}
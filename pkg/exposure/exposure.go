@@ -0,0 +1,390 @@
+package exposure
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+
+	"github.com/pkg/errors"
+	kotsv1beta1 "github.com/replicatedhq/kots/kotskinds/apis/kots/v1beta1"
+	"github.com/replicatedhq/kots/kotskinds/multitype"
+	"github.com/replicatedhq/kots/pkg/template"
+	corev1 "k8s.io/api/core/v1"
+	extensionsv1beta1 "k8s.io/api/extensions/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	k8syaml "sigs.k8s.io/yaml"
+)
+
+const (
+	// ConfigGroupName is the name of the config group that is added to the config of the application for the
+	// services of the exposure
+	ConfigGroupName = "kots_exposure"
+)
+
+var (
+	AllMethods = []kotsv1beta1.ExposureMethod{
+		kotsv1beta1.ExposureMethodIngress,
+		kotsv1beta1.ExposureMethodLoadBalancer,
+		kotsv1beta1.ExposureMethodNodePort,
+		kotsv1beta1.ExposureMethodNone,
+	}
+
+	methodTitles = map[kotsv1beta1.ExposureMethod]string{
+		kotsv1beta1.ExposureMethodIngress:      "Ingress",
+		kotsv1beta1.ExposureMethodLoadBalancer: "Load Balancer",
+		kotsv1beta1.ExposureMethodNodePort:     "Node Port",
+		kotsv1beta1.ExposureMethodNone:         "Not exposed",
+	}
+
+	serviceNameRegex = regexp.MustCompile(`^[a-z0-9_]+$`)
+)
+
+// Resources are the manifests that expose the services of the application
+type Resources struct {
+	// Resources are new resources, e.g. ingresses and tls secrets, by filename
+	Resources map[string][]byte
+	// Patches are strategic merge patches of the services of the application, by filename
+	Patches map[string][]byte
+}
+
+// ItemName returns the name of a config item of an exposed service
+func ItemName(service kotsv1beta1.ExposedService, field string) string {
+	return fmt.Sprintf("%s_%s_%s", ConfigGroupName, service.Name, field)
+}
+
+// Methods returns the methods the customer can choose from for the service
+func Methods(service kotsv1beta1.ExposedService) []kotsv1beta1.ExposureMethod {
+	if len(service.Methods) == 0 {
+		return AllMethods
+	}
+	return service.Methods
+}
+
+// DefaultMethod returns the method of the service until the customer chooses one
+func DefaultMethod(service kotsv1beta1.ExposedService) kotsv1beta1.ExposureMethod {
+	if service.DefaultMethod != "" {
+		return service.DefaultMethod
+	}
+	return Methods(service)[0]
+}
+
+func Validate(spec kotsv1beta1.ExposureSpec) error {
+	names := map[string]bool{}
+	for _, service := range spec.Services {
+		if !serviceNameRegex.MatchString(service.Name) {
+			return errors.Errorf("name %q must only contain lowercase letters, digits and underscores", service.Name)
+		}
+		if names[service.Name] {
+			return errors.Errorf("name %q is used by more than one service", service.Name)
+		}
+		names[service.Name] = true
+
+		if service.ServiceName == "" {
+			return errors.Errorf("service %q does not have a serviceName", service.Name)
+		}
+		if service.ServicePort <= 0 {
+			return errors.Errorf("service %q does not have a servicePort", service.Name)
+		}
+
+		for _, method := range service.Methods {
+			if _, ok := methodTitles[method]; !ok {
+				return errors.Errorf("service %q has unsupported method %q", service.Name, method)
+			}
+		}
+		if !hasMethod(Methods(service), DefaultMethod(service)) {
+			return errors.Errorf("default method %q of service %q is not one of its methods", service.DefaultMethod, service.Name)
+		}
+	}
+	return nil
+}
+
+// ConfigGroup returns the config group in which the customer chooses how each service is exposed
+func ConfigGroup(spec kotsv1beta1.ExposureSpec) kotsv1beta1.ConfigGroup {
+	group := kotsv1beta1.ConfigGroup{
+		Name:        ConfigGroupName,
+		Title:       "Exposure",
+		Description: "Choose how the services of the application are reached from outside of the cluster.",
+		Items:       []kotsv1beta1.ConfigItem{},
+	}
+
+	for _, service := range spec.Services {
+		title := service.Title
+		if title == "" {
+			title = service.ServiceName
+		}
+
+		methodItem := kotsv1beta1.ConfigItem{
+			Name:    ItemName(service, "method"),
+			Type:    "select_one",
+			Title:   fmt.Sprintf("%s exposure", title),
+			Default: multitype.FromString(string(DefaultMethod(service))),
+			Items:   []kotsv1beta1.ConfigChildItem{},
+		}
+		for _, method := range Methods(service) {
+			methodItem.Items = append(methodItem.Items, kotsv1beta1.ConfigChildItem{
+				Name:  string(method),
+				Title: methodTitles[method],
+			})
+		}
+		group.Items = append(group.Items, methodItem)
+
+		methodIs := func(method kotsv1beta1.ExposureMethod) string {
+			return fmt.Sprintf(`ConfigOptionEquals "%s" "%s"`, ItemName(service, "method"), method)
+		}
+		whenIngress := multitype.QuotedBool(fmt.Sprintf("repl{{ %s }}", methodIs(kotsv1beta1.ExposureMethodIngress)))
+		whenTLS := multitype.QuotedBool(fmt.Sprintf(`repl{{ and (%s) (ConfigOptionEquals "%s" "1") }}`, methodIs(kotsv1beta1.ExposureMethodIngress), ItemName(service, "tls")))
+
+		if hasMethod(Methods(service), kotsv1beta1.ExposureMethodIngress) {
+			group.Items = append(group.Items,
+				kotsv1beta1.ConfigItem{
+					Name:     ItemName(service, "hostname"),
+					Type:     "text",
+					Title:    fmt.Sprintf("%s hostname", title),
+					HelpText: "The ingress accepts requests for any hostname if this is empty.",
+					When:     whenIngress,
+				},
+				kotsv1beta1.ConfigItem{
+					Name:     ItemName(service, "ingress_class"),
+					Type:     "text",
+					Title:    fmt.Sprintf("%s ingress class", title),
+					HelpText: "The default ingress class of the cluster is used if this is empty.",
+					When:     whenIngress,
+				},
+				kotsv1beta1.ConfigItem{
+					Name:    ItemName(service, "tls"),
+					Type:    "bool",
+					Title:   fmt.Sprintf("Terminate TLS for %s", title),
+					Default: multitype.FromString("0"),
+					When:    whenIngress,
+				},
+				kotsv1beta1.ConfigItem{
+					Name:     ItemName(service, "tls_cert"),
+					Type:     "file",
+					Title:    fmt.Sprintf("%s TLS certificate", title),
+					Required: true,
+					When:     whenTLS,
+				},
+				kotsv1beta1.ConfigItem{
+					Name:     ItemName(service, "tls_key"),
+					Type:     "file",
+					Title:    fmt.Sprintf("%s TLS key", title),
+					Required: true,
+					When:     whenTLS,
+				},
+			)
+		}
+
+		if hasMethod(Methods(service), kotsv1beta1.ExposureMethodNodePort) {
+			group.Items = append(group.Items, kotsv1beta1.ConfigItem{
+				Name:     ItemName(service, "node_port"),
+				Type:     "text",
+				Title:    fmt.Sprintf("%s node port", title),
+				HelpText: "Kubernetes assigns a port if this is empty.",
+				When:     multitype.QuotedBool(fmt.Sprintf("repl{{ %s }}", methodIs(kotsv1beta1.ExposureMethodNodePort))),
+			})
+		}
+	}
+
+	return group
+}
+
+// Render returns the manifests that expose the services as the customer chose in the config
+func Render(exposure kotsv1beta1.Exposure, builder template.Builder, additionalLabels map[string]string) (*Resources, error) {
+	resources := &Resources{
+		Resources: map[string][]byte{},
+		Patches:   map[string][]byte{},
+	}
+
+	for _, service := range exposure.Spec.Services {
+		method, err := builder.String(fmt.Sprintf(`{{repl ConfigOption "%s" }}`, ItemName(service, "method")))
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to render method of service %s", service.Name)
+		}
+		if method == "" || !hasMethod(Methods(service), kotsv1beta1.ExposureMethod(method)) {
+			method = string(DefaultMethod(service))
+		}
+
+		switch kotsv1beta1.ExposureMethod(method) {
+		case kotsv1beta1.ExposureMethodIngress:
+			if err := renderIngress(service, builder, additionalLabels, resources); err != nil {
+				return nil, errors.Wrapf(err, "failed to render ingress of service %s", service.Name)
+			}
+
+		case kotsv1beta1.ExposureMethodLoadBalancer:
+			patch, err := servicePatch(service, corev1.ServiceTypeLoadBalancer, 0)
+			if err != nil {
+				return nil, errors.Wrapf(err, "failed to render service patch of service %s", service.Name)
+			}
+			resources.Patches[fmt.Sprintf("exposure-%s.yaml", service.ServiceName)] = patch
+
+		case kotsv1beta1.ExposureMethodNodePort:
+			nodePortValue, err := builder.String(fmt.Sprintf(`{{repl ConfigOption "%s" }}`, ItemName(service, "node_port")))
+			if err != nil {
+				return nil, errors.Wrapf(err, "failed to render node port of service %s", service.Name)
+			}
+			nodePort := 0
+			if nodePortValue != "" {
+				nodePort, err = strconv.Atoi(nodePortValue)
+				if err != nil {
+					return nil, errors.Errorf("node port %q of service %s is not a number", nodePortValue, service.Name)
+				}
+			}
+			patch, err := servicePatch(service, corev1.ServiceTypeNodePort, nodePort)
+			if err != nil {
+				return nil, errors.Wrapf(err, "failed to render service patch of service %s", service.Name)
+			}
+			resources.Patches[fmt.Sprintf("exposure-%s.yaml", service.ServiceName)] = patch
+		}
+	}
+
+	return resources, nil
+}
+
+func renderIngress(service kotsv1beta1.ExposedService, builder template.Builder, additionalLabels map[string]string, resources *Resources) error {
+	hostname, err := builder.String(fmt.Sprintf(`{{repl ConfigOption "%s" }}`, ItemName(service, "hostname")))
+	if err != nil {
+		return errors.Wrap(err, "failed to render hostname")
+	}
+	ingressClass, err := builder.String(fmt.Sprintf(`{{repl ConfigOption "%s" }}`, ItemName(service, "ingress_class")))
+	if err != nil {
+		return errors.Wrap(err, "failed to render ingress class")
+	}
+	tls, err := builder.Bool(fmt.Sprintf(`{{repl ConfigOption "%s" }}`, ItemName(service, "tls")), false)
+	if err != nil {
+		return errors.Wrap(err, "failed to render tls")
+	}
+
+	name := fmt.Sprintf("%s-exposure", service.ServiceName)
+
+	path := service.Path
+	if path == "" {
+		path = "/"
+	}
+
+	annotations := map[string]string{}
+	for k, v := range service.IngressAnnotations {
+		annotations[k] = v
+	}
+	if ingressClass != "" {
+		annotations["kubernetes.io/ingress.class"] = ingressClass
+	}
+
+	ingress := &extensionsv1beta1.Ingress{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "extensions/v1beta1",
+			Kind:       "Ingress",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        name,
+			Labels:      additionalLabels,
+			Annotations: annotations,
+		},
+		Spec: extensionsv1beta1.IngressSpec{
+			Rules: []extensionsv1beta1.IngressRule{
+				{
+					Host: hostname,
+					IngressRuleValue: extensionsv1beta1.IngressRuleValue{
+						HTTP: &extensionsv1beta1.HTTPIngressRuleValue{
+							Paths: []extensionsv1beta1.HTTPIngressPath{
+								{
+									Path: path,
+									Backend: extensionsv1beta1.IngressBackend{
+										ServiceName: service.ServiceName,
+										ServicePort: intstr.FromInt(service.ServicePort),
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	if tls {
+		cert, err := builder.String(fmt.Sprintf(`{{repl ConfigOptionData "%s" }}`, ItemName(service, "tls_cert")))
+		if err != nil {
+			return errors.Wrap(err, "failed to render tls certificate")
+		}
+		key, err := builder.String(fmt.Sprintf(`{{repl ConfigOptionData "%s" }}`, ItemName(service, "tls_key")))
+		if err != nil {
+			return errors.Wrap(err, "failed to render tls key")
+		}
+		if cert == "" || key == "" {
+			return errors.New("tls is enabled but the certificate or key is missing")
+		}
+
+		secret := &corev1.Secret{
+			TypeMeta: metav1.TypeMeta{
+				APIVersion: "v1",
+				Kind:       "Secret",
+			},
+			ObjectMeta: metav1.ObjectMeta{
+				Name:   fmt.Sprintf("%s-tls", name),
+				Labels: additionalLabels,
+			},
+			Type: corev1.SecretTypeTLS,
+			Data: map[string][]byte{
+				corev1.TLSCertKey:       []byte(cert),
+				corev1.TLSPrivateKeyKey: []byte(key),
+			},
+		}
+		b, err := k8syaml.Marshal(secret)
+		if err != nil {
+			return errors.Wrap(err, "failed to marshal tls secret")
+		}
+		resources.Resources[fmt.Sprintf("%s-tls-secret.yaml", name)] = b
+
+		ingressTLS := extensionsv1beta1.IngressTLS{
+			SecretName: secret.Name,
+		}
+		if hostname != "" {
+			ingressTLS.Hosts = []string{hostname}
+		}
+		ingress.Spec.TLS = []extensionsv1beta1.IngressTLS{ingressTLS}
+	}
+
+	b, err := k8syaml.Marshal(ingress)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal ingress")
+	}
+	resources.Resources[fmt.Sprintf("%s-ingress.yaml", name)] = b
+
+	return nil
+}
+
+// servicePatch changes the type of the service of the application. The node port is only set if it is not 0.
+func servicePatch(service kotsv1beta1.ExposedService, serviceType corev1.ServiceType, nodePort int) ([]byte, error) {
+	spec := map[string]interface{}{
+		"type": serviceType,
+	}
+	if nodePort != 0 {
+		spec["ports"] = []map[string]interface{}{
+			{
+				"port":     service.ServicePort,
+				"nodePort": nodePort,
+			},
+		}
+	}
+
+	patch := map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "Service",
+		"metadata": map[string]interface{}{
+			"name": service.ServiceName,
+		},
+		"spec": spec,
+	}
+
+	return k8syaml.Marshal(patch)
+}
+
+func hasMethod(methods []kotsv1beta1.ExposureMethod, method kotsv1beta1.ExposureMethod) bool {
+	for _, m := range methods {
+		if m == method {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,215 @@
+package exposure
+
+import (
+	"encoding/base64"
+	"testing"
+
+	kotsv1beta1 "github.com/replicatedhq/kots/kotskinds/apis/kots/v1beta1"
+	"github.com/replicatedhq/kots/pkg/template"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	extensionsv1beta1 "k8s.io/api/extensions/v1beta1"
+	k8syaml "sigs.k8s.io/yaml"
+)
+
+func testExposure() kotsv1beta1.Exposure {
+	return kotsv1beta1.Exposure{
+		Spec: kotsv1beta1.ExposureSpec{
+			Services: []kotsv1beta1.ExposedService{
+				{
+					Name:          "web",
+					Title:         "Web",
+					ServiceName:   "web",
+					ServicePort:   80,
+					DefaultMethod: kotsv1beta1.ExposureMethodIngress,
+				},
+				{
+					Name:        "api",
+					ServiceName: "api",
+					ServicePort: 8080,
+					Methods: []kotsv1beta1.ExposureMethod{
+						kotsv1beta1.ExposureMethodNodePort,
+						kotsv1beta1.ExposureMethodLoadBalancer,
+					},
+				},
+			},
+		},
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		spec    kotsv1beta1.ExposureSpec
+		wantErr bool
+	}{
+		{
+			name: "valid",
+			spec: testExposure().Spec,
+		},
+		{
+			name: "duplicate name",
+			spec: kotsv1beta1.ExposureSpec{
+				Services: []kotsv1beta1.ExposedService{
+					{Name: "web", ServiceName: "web", ServicePort: 80},
+					{Name: "web", ServiceName: "web2", ServicePort: 80},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalid name",
+			spec: kotsv1beta1.ExposureSpec{
+				Services: []kotsv1beta1.ExposedService{
+					{Name: "my-web", ServiceName: "web", ServicePort: 80},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "default method not allowed",
+			spec: kotsv1beta1.ExposureSpec{
+				Services: []kotsv1beta1.ExposedService{
+					{
+						Name:          "web",
+						ServiceName:   "web",
+						ServicePort:   80,
+						Methods:       []kotsv1beta1.ExposureMethod{kotsv1beta1.ExposureMethodIngress},
+						DefaultMethod: kotsv1beta1.ExposureMethodNodePort,
+					},
+				},
+			},
+			wantErr: true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := Validate(test.spec)
+			if test.wantErr {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestConfigGroup(t *testing.T) {
+	req := require.New(t)
+
+	group := ConfigGroup(testExposure().Spec)
+	req.Equal(ConfigGroupName, group.Name)
+
+	itemNames := []string{}
+	for _, item := range group.Items {
+		itemNames = append(itemNames, item.Name)
+	}
+	req.Equal([]string{
+		"kots_exposure_web_method",
+		"kots_exposure_web_hostname",
+		"kots_exposure_web_ingress_class",
+		"kots_exposure_web_tls",
+		"kots_exposure_web_tls_cert",
+		"kots_exposure_web_tls_key",
+		"kots_exposure_web_node_port",
+		"kots_exposure_api_method",
+		"kots_exposure_api_node_port",
+	}, itemNames)
+
+	// the first method is the default if there is no default method
+	apiMethod := group.Items[7]
+	req.Equal("nodeport", apiMethod.Default.String())
+	req.Len(apiMethod.Items, 2)
+}
+
+func TestRender(t *testing.T) {
+	tests := []struct {
+		name          string
+		values        map[string]template.ItemValue
+		wantResources []string
+		wantPatches   map[string]string
+		wantHost      string
+	}{
+		{
+			name:          "defaults",
+			values:        map[string]template.ItemValue{},
+			wantResources: []string{"web-exposure-ingress.yaml"},
+			wantPatches: map[string]string{
+				"exposure-api.yaml": "apiVersion: v1\nkind: Service\nmetadata:\n  name: api\nspec:\n  type: NodePort\n",
+			},
+		},
+		{
+			name: "ingress with tls and load balancer",
+			values: map[string]template.ItemValue{
+				"kots_exposure_web_hostname":      {Value: "web.example.com"},
+				"kots_exposure_web_ingress_class": {Value: "nginx"},
+				"kots_exposure_web_tls":           {Value: "1"},
+				"kots_exposure_web_tls_cert":      {Value: base64.StdEncoding.EncodeToString([]byte("cert"))},
+				"kots_exposure_web_tls_key":       {Value: base64.StdEncoding.EncodeToString([]byte("key"))},
+				"kots_exposure_api_method":        {Value: "loadbalancer"},
+			},
+			wantResources: []string{"web-exposure-ingress.yaml", "web-exposure-tls-secret.yaml"},
+			wantHost:      "web.example.com",
+			wantPatches: map[string]string{
+				"exposure-api.yaml": "apiVersion: v1\nkind: Service\nmetadata:\n  name: api\nspec:\n  type: LoadBalancer\n",
+			},
+		},
+		{
+			name: "node ports",
+			values: map[string]template.ItemValue{
+				"kots_exposure_web_method":    {Value: "nodeport"},
+				"kots_exposure_web_node_port": {Value: "30080"},
+				"kots_exposure_api_method":    {Value: "nodeport"},
+			},
+			wantResources: []string{},
+			wantPatches: map[string]string{
+				"exposure-web.yaml": "apiVersion: v1\nkind: Service\nmetadata:\n  name: web\nspec:\n  ports:\n  - nodePort: 30080\n    port: 80\n  type: NodePort\n",
+				"exposure-api.yaml": "apiVersion: v1\nkind: Service\nmetadata:\n  name: api\nspec:\n  type: NodePort\n",
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			req := require.New(t)
+
+			exposure := testExposure()
+			builder, _, err := template.NewBuilder(template.BuilderOptions{
+				ConfigGroups:   []kotsv1beta1.ConfigGroup{ConfigGroup(exposure.Spec)},
+				ExistingValues: test.values,
+			})
+			req.NoError(err)
+
+			resources, err := Render(exposure, builder, map[string]string{"kots.io/app": "my-app"})
+			req.NoError(err)
+
+			resourceNames := []string{}
+			for name := range resources.Resources {
+				resourceNames = append(resourceNames, name)
+			}
+			req.ElementsMatch(test.wantResources, resourceNames)
+
+			patches := map[string]string{}
+			for name, patch := range resources.Patches {
+				patches[name] = string(patch)
+			}
+			req.Equal(test.wantPatches, patches)
+
+			if b, ok := resources.Resources["web-exposure-ingress.yaml"]; ok {
+				ingress := extensionsv1beta1.Ingress{}
+				req.NoError(k8syaml.Unmarshal(b, &ingress))
+				req.Equal("web", ingress.Spec.Rules[0].HTTP.Paths[0].Backend.ServiceName)
+				req.Equal(80, ingress.Spec.Rules[0].HTTP.Paths[0].Backend.ServicePort.IntValue())
+				req.Equal("/", ingress.Spec.Rules[0].HTTP.Paths[0].Path)
+				req.Equal(test.wantHost, ingress.Spec.Rules[0].Host)
+			}
+
+			if b, ok := resources.Resources["web-exposure-tls-secret.yaml"]; ok {
+				secret := corev1.Secret{}
+				req.NoError(k8syaml.Unmarshal(b, &secret))
+				req.Equal(corev1.SecretTypeTLS, secret.Type)
+				req.Equal("cert", string(secret.Data[corev1.TLSCertKey]))
+				req.Equal("key", string(secret.Data[corev1.TLSPrivateKeyKey]))
+			}
+		})
+	}
+}
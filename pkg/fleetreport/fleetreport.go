@@ -0,0 +1,124 @@
+package fleetreport
+
+import (
+	"time"
+
+	"github.com/pkg/errors"
+	apptypes "github.com/replicatedhq/kots/pkg/app/types"
+	"github.com/replicatedhq/kots/pkg/buildversion"
+	"github.com/replicatedhq/kots/pkg/fleetreport/types"
+	"github.com/replicatedhq/kots/pkg/k8sutil"
+	"github.com/replicatedhq/kots/pkg/kurl"
+	"github.com/replicatedhq/kots/pkg/logger"
+	"github.com/replicatedhq/kots/pkg/store"
+	"github.com/replicatedhq/kots/pkg/version"
+)
+
+// Generate returns the report of the installation and all of its installed apps. Information that can't be read
+// from the cluster is left empty, so that a report is returned for clusters with restricted access.
+func Generate() (*types.Report, error) {
+	report := types.Report{
+		GeneratedAt: time.Now(),
+		KotsVersion: buildversion.Version(),
+		IsKurl:      kurl.IsKurl(),
+		Apps:        []types.App{},
+	}
+
+	configMap, err := k8sutil.GetKotsadmIDConfigMap()
+	if err != nil {
+		logger.Debugf("failed to get kotsadm id: %v", err)
+	} else if configMap != nil {
+		report.ClusterID = configMap.Data["id"]
+	}
+
+	k8sVersion, err := k8sutil.GetK8sVersion()
+	if err != nil {
+		logger.Debugf("failed to get k8s version: %v", err)
+	} else {
+		report.K8sVersion = k8sVersion
+	}
+
+	apps, err := store.GetStore().ListInstalledApps()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list installed apps")
+	}
+
+	for _, a := range apps {
+		app, err := reportApp(a)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to report app %s", a.Slug)
+		}
+		report.Apps = append(report.Apps, *app)
+	}
+
+	return &report, nil
+}
+
+func reportApp(a *apptypes.App) (*types.App, error) {
+	app := types.App{
+		ID:         a.ID,
+		Slug:       a.Slug,
+		Name:       a.Name,
+		IsAirgap:   a.IsAirgap,
+		IsArchived: a.IsArchived,
+	}
+
+	license, err := store.GetStore().GetLatestLicenseForApp(a.ID)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get license")
+	}
+	app.LicenseID = license.Spec.LicenseID
+	app.LicenseType = license.Spec.LicenseType
+	app.CustomerName = license.Spec.CustomerName
+	app.ChannelID = license.Spec.ChannelID
+	app.ChannelName = license.Spec.ChannelName
+
+	appStatus, err := store.GetStore().GetAppStatus(a.ID)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get app status")
+	}
+	if appStatus != nil {
+		app.State = string(appStatus.State)
+	}
+
+	downstreams, err := store.GetStore().ListDownstreamsForApp(a.ID)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list downstreams")
+	}
+	if len(downstreams) == 0 {
+		return &app, nil
+	}
+	clusterID := downstreams[0].ClusterID
+
+	pendingVersions, err := store.GetReadStore().GetPendingVersions(a.ID, clusterID)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get pending versions")
+	}
+	app.PendingVersions = len(pendingVersions)
+
+	currentVersion, err := store.GetReadStore().GetCurrentVersion(a.ID, clusterID)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get current version")
+	}
+	if currentVersion == nil {
+		return &app, nil
+	}
+
+	app.DeployedVersion = &types.Version{
+		Sequence:     currentVersion.Sequence,
+		VersionLabel: currentVersion.VersionLabel,
+		DeployedAt:   currentVersion.DeployedAt,
+	}
+
+	kotsKinds, err := version.GetKotsKinds(a.ID, currentVersion.ParentSequence)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to load kots kinds of deployed version")
+	}
+	app.DeployedVersion.ChannelSequence = kotsKinds.Installation.Spec.UpdateCursor
+	if kotsKinds.Installation.Spec.ChannelID != "" || kotsKinds.Installation.Spec.ChannelName != "" {
+		app.ChannelID = kotsKinds.Installation.Spec.ChannelID
+		app.ChannelName = kotsKinds.Installation.Spec.ChannelName
+	}
+
+	return &app, nil
+}
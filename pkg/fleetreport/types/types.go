@@ -0,0 +1,40 @@
+package types
+
+import (
+	"time"
+)
+
+// Report summarizes the installation and its apps, for vendors and MSPs that collect the reports of many clusters
+type Report struct {
+	GeneratedAt time.Time `json:"generatedAt"`
+	// ClusterID is the id of the kotsadm installation, it is empty if the id has not been generated yet
+	ClusterID   string `json:"clusterId"`
+	KotsVersion string `json:"kotsVersion"`
+	K8sVersion  string `json:"k8sVersion"`
+	IsKurl      bool   `json:"isKurl"`
+	Apps        []App  `json:"apps"`
+}
+
+type App struct {
+	ID           string `json:"id"`
+	Slug         string `json:"slug"`
+	Name         string `json:"name"`
+	IsAirgap     bool   `json:"isAirgap"`
+	IsArchived   bool   `json:"isArchived"`
+	LicenseID    string `json:"licenseId"`
+	LicenseType  string `json:"licenseType"`
+	CustomerName string `json:"customerName"`
+	// ChannelID and ChannelName are those of the deployed version, or of the license if no version is deployed
+	ChannelID       string   `json:"channelId"`
+	ChannelName     string   `json:"channelName"`
+	State           string   `json:"state"`
+	DeployedVersion *Version `json:"deployedVersion"`
+	PendingVersions int      `json:"pendingVersions"`
+}
+
+type Version struct {
+	Sequence        int64      `json:"sequence"`
+	VersionLabel    string     `json:"versionLabel"`
+	ChannelSequence string     `json:"channelSequence"`
+	DeployedAt      *time.Time `json:"deployedAt"`
+}
@@ -0,0 +1,81 @@
+package freeze
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/replicatedhq/kots/pkg/freeze/types"
+	"github.com/replicatedhq/kots/pkg/store"
+)
+
+// FrozenError is returned when a deploy is blocked by a deploy freeze
+type FrozenError struct {
+	Freeze types.Freeze
+	// IsGlobal is true if the freeze applies to all apps
+	IsGlobal bool
+}
+
+func (e FrozenError) Error() string {
+	msg := "deploys of this app are frozen"
+	if e.IsGlobal {
+		msg = "deploys of all apps are frozen"
+	}
+	if e.Freeze.Reason != "" {
+		msg = fmt.Sprintf("%s: %s", msg, e.Freeze.Reason)
+	}
+	if e.Freeze.ExpiresAt != nil {
+		msg = fmt.Sprintf("%s (until %s)", msg, e.Freeze.ExpiresAt.UTC().Format(time.RFC3339))
+	}
+	return msg
+}
+
+// IsFrozen returns true if the error, or the error it wraps, is a FrozenError
+func IsFrozen(err error) bool {
+	_, ok := errors.Cause(err).(FrozenError)
+	return ok
+}
+
+// GetActiveFreeze returns the freeze that blocks deploys of an app: the app's own freeze unless it has expired,
+// otherwise the global freeze unless it has expired. Nil is returned if deploys are not frozen.
+func GetActiveFreeze(appID string) (*types.Freeze, bool, error) {
+	now := time.Now()
+
+	appFreeze, err := store.GetStore().GetAppFreeze(appID)
+	if err != nil {
+		return nil, false, errors.Wrap(err, "failed to get app freeze")
+	}
+	if appFreeze != nil && appFreeze.IsActive(now) {
+		return appFreeze, false, nil
+	}
+
+	globalFreeze, err := store.GetStore().GetGlobalFreeze()
+	if err != nil {
+		return nil, false, errors.Wrap(err, "failed to get global freeze")
+	}
+	if globalFreeze != nil && globalFreeze.IsActive(now) {
+		return globalFreeze, true, nil
+	}
+
+	return nil, false, nil
+}
+
+// CheckDeploy returns a FrozenError if deploys of the app are frozen
+func CheckDeploy(appID string) error {
+	activeFreeze, isGlobal, err := GetActiveFreeze(appID)
+	if err != nil {
+		return errors.Wrap(err, "failed to get active freeze")
+	}
+	if activeFreeze != nil {
+		return FrozenError{Freeze: *activeFreeze, IsGlobal: isGlobal}
+	}
+	return nil
+}
+
+// Validate returns an error if the freeze cannot be set
+func Validate(freeze types.Freeze, now time.Time) error {
+	if freeze.ExpiresAt != nil && !freeze.ExpiresAt.After(now) {
+		return errors.New("expiry time must be in the future")
+	}
+	return nil
+}
@@ -0,0 +1,43 @@
+package freeze
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/replicatedhq/kots/pkg/freeze/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Validate(t *testing.T) {
+	now := time.Now()
+	later := now.Add(time.Hour)
+	earlier := now.Add(-time.Hour)
+
+	assert.NoError(t, Validate(types.Freeze{}, now))
+	assert.NoError(t, Validate(types.Freeze{ExpiresAt: &later}, now))
+	assert.Error(t, Validate(types.Freeze{ExpiresAt: &earlier}, now))
+	assert.Error(t, Validate(types.Freeze{ExpiresAt: &now}, now))
+}
+
+func Test_FreezeIsActive(t *testing.T) {
+	now := time.Now()
+	later := now.Add(time.Hour)
+
+	assert.True(t, types.Freeze{}.IsActive(now))
+	assert.True(t, types.Freeze{ExpiresAt: &later}.IsActive(now))
+	assert.False(t, types.Freeze{ExpiresAt: &later}.IsActive(later))
+}
+
+func Test_FrozenError(t *testing.T) {
+	expiresAt := time.Date(2021, 12, 27, 9, 0, 0, 0, time.UTC)
+
+	err := FrozenError{Freeze: types.Freeze{Reason: "holiday change freeze", ExpiresAt: &expiresAt}, IsGlobal: true}
+	assert.Equal(t, "deploys of all apps are frozen: holiday change freeze (until 2021-12-27T09:00:00Z)", err.Error())
+
+	err = FrozenError{}
+	assert.Equal(t, "deploys of this app are frozen", err.Error())
+
+	assert.True(t, IsFrozen(errors.Wrap(err, "failed to deploy version")))
+	assert.False(t, IsFrozen(errors.New("failed to deploy version")))
+}
@@ -0,0 +1,20 @@
+package types
+
+import (
+	"time"
+)
+
+// Freeze blocks all deploys, manual and automatic, of one app or of all apps during a change freeze.
+// Versions can still be downloaded and their preflight checks run.
+type Freeze struct {
+	Reason string `json:"reason,omitempty"`
+	// ExpiresAt is nil if the freeze lasts until it is lifted
+	ExpiresAt *time.Time `json:"expiresAt,omitempty"`
+	CreatedBy string     `json:"createdBy,omitempty"`
+	CreatedAt time.Time  `json:"createdAt"`
+}
+
+// IsActive returns true if the freeze has not expired
+func (f Freeze) IsActive(now time.Time) bool {
+	return f.ExpiresAt == nil || now.Before(*f.ExpiresAt)
+}
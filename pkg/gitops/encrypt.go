@@ -0,0 +1,396 @@
+package gitops
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/pem"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	ghodssyaml "github.com/ghodss/yaml"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	SecretEncryptionSealedSecrets = "sealedsecrets"
+	SecretEncryptionSOPS          = "sops"
+
+	SealedSecretsScopeStrict        = "strict"
+	SealedSecretsScopeNamespaceWide = "namespace-wide"
+	SealedSecretsScopeClusterWide   = "cluster-wide"
+
+	// renderChecksumPrefix is the first line of an encrypted app yaml. encryption is not deterministic,
+	// so the checksum of the plaintext render and encryption settings is used to tell if the app yaml needs to be updated.
+	renderChecksumPrefix = "# kots-render-sha256: "
+)
+
+// SecretEncryption configures how secret manifests are encrypted before they are pushed to a gitops repo
+type SecretEncryption struct {
+	// Method is one of "sealedsecrets" or "sops", secrets are pushed unencrypted if empty
+	Method string `json:"method"`
+
+	// SealedSecretsCert is the PEM encoded certificate of the sealed secrets controller
+	SealedSecretsCert string `json:"sealedSecretsCert,omitempty"`
+	// SealedSecretsScope is one of "strict", "namespace-wide" or "cluster-wide", defaults to "strict"
+	SealedSecretsScope string `json:"sealedSecretsScope,omitempty"`
+
+	// SOPSAgeRecipients are the age public keys that secrets are encrypted for
+	SOPSAgeRecipients []string `json:"sopsAgeRecipients,omitempty"`
+	// SOPSPGPKeys are the armored PGP public keys that secrets are encrypted for
+	SOPSPGPKeys []string `json:"sopsPgpKeys,omitempty"`
+}
+
+type manifestGVK struct {
+	APIVersion string `json:"apiVersion"`
+	Kind       string `json:"kind"`
+}
+
+type sealedSecret struct {
+	APIVersion string            `json:"apiVersion"`
+	Kind       string            `json:"kind"`
+	Metadata   metav1.ObjectMeta `json:"metadata"`
+	Spec       sealedSecretSpec  `json:"spec"`
+}
+
+type sealedSecretSpec struct {
+	EncryptedData map[string]string    `json:"encryptedData"`
+	Template      sealedSecretTemplate `json:"template"`
+}
+
+type sealedSecretTemplate struct {
+	Metadata metav1.ObjectMeta `json:"metadata"`
+	Type     corev1.SecretType `json:"type,omitempty"`
+}
+
+func (e SecretEncryption) Validate() error {
+	switch e.Method {
+	case "":
+		return nil
+
+	case SecretEncryptionSealedSecrets:
+		switch e.SealedSecretsScope {
+		case "", SealedSecretsScopeStrict, SealedSecretsScopeNamespaceWide, SealedSecretsScopeClusterWide:
+		default:
+			return errors.Errorf("unsupported sealed secrets scope %q", e.SealedSecretsScope)
+		}
+		if _, err := parseSealedSecretsCert(e.SealedSecretsCert); err != nil {
+			return errors.Wrap(err, "invalid sealed secrets certificate")
+		}
+		return nil
+
+	case SecretEncryptionSOPS:
+		if len(e.SOPSAgeRecipients) == 0 && len(e.SOPSPGPKeys) == 0 {
+			return errors.New("at least one age recipient or pgp key is required")
+		}
+		return nil
+	}
+
+	return errors.Errorf("unsupported secret encryption method %q", e.Method)
+}
+
+// EncryptSecrets replaces the secrets in the rendered manifests with their encrypted form.
+// A checksum of the plaintext manifests is added as the first line so that unchanged renders can be detected.
+func EncryptSecrets(manifests []byte, encryption SecretEncryption) ([]byte, error) {
+	if encryption.Method == "" {
+		return manifests, nil
+	}
+
+	var rsaPublicKey *rsa.PublicKey
+	if encryption.Method == SecretEncryptionSealedSecrets {
+		key, err := parseSealedSecretsCert(encryption.SealedSecretsCert)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to parse sealed secrets certificate")
+		}
+		rsaPublicKey = key
+	}
+
+	docs := bytes.Split(manifests, []byte("\n---\n"))
+	encryptedDocs := make([][]byte, 0, len(docs))
+	for _, doc := range docs {
+		gvk := manifestGVK{}
+		if err := ghodssyaml.Unmarshal(doc, &gvk); err != nil || gvk.APIVersion != "v1" || gvk.Kind != "Secret" {
+			encryptedDocs = append(encryptedDocs, doc)
+			continue
+		}
+
+		var encryptedDoc []byte
+		var err error
+		switch encryption.Method {
+		case SecretEncryptionSealedSecrets:
+			encryptedDoc, err = sealSecret(doc, rsaPublicKey, encryption.SealedSecretsScope)
+		case SecretEncryptionSOPS:
+			encryptedDoc, err = sopsEncryptSecret(doc, encryption)
+		default:
+			err = errors.Errorf("unsupported secret encryption method %q", encryption.Method)
+		}
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to encrypt secret")
+		}
+
+		encryptedDocs = append(encryptedDocs, bytes.TrimSuffix(encryptedDoc, []byte("\n")))
+	}
+
+	encrypted := bytes.Join(encryptedDocs, []byte("\n---\n"))
+	if !bytes.HasSuffix(encrypted, []byte("\n")) {
+		encrypted = append(encrypted, '\n')
+	}
+
+	return append([]byte(renderChecksum(manifests, encryption)+"\n"), encrypted...), nil
+}
+
+// isUnchangedRender returns true if the current app yaml was created from the same plaintext manifests
+func isUnchangedRender(current []byte, manifests []byte, encryption SecretEncryption) bool {
+	if encryption.Method == "" {
+		return bytes.Equal(current, manifests)
+	}
+
+	firstLine := strings.SplitN(string(current), "\n", 2)[0]
+	return firstLine == renderChecksum(manifests, encryption)
+}
+
+func renderChecksum(manifests []byte, encryption SecretEncryption) string {
+	h := sha256.New()
+	h.Write(manifests)
+	h.Write([]byte(fmt.Sprintf("%#v", encryption)))
+	return renderChecksumPrefix + hex.EncodeToString(h.Sum(nil))
+}
+
+func parseSealedSecretsCert(certPEM string) (*rsa.PublicKey, error) {
+	block, _ := pem.Decode([]byte(certPEM))
+	if block == nil {
+		return nil, errors.New("no pem data found")
+	}
+
+	var publicKey interface{}
+	switch block.Type {
+	case "CERTIFICATE":
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to parse certificate")
+		}
+		publicKey = cert.PublicKey
+	case "PUBLIC KEY":
+		key, err := x509.ParsePKIXPublicKey(block.Bytes)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to parse public key")
+		}
+		publicKey = key
+	default:
+		return nil, errors.Errorf("unexpected pem block type %q", block.Type)
+	}
+
+	rsaPublicKey, ok := publicKey.(*rsa.PublicKey)
+	if !ok {
+		return nil, errors.New("public key is not an rsa key")
+	}
+
+	return rsaPublicKey, nil
+}
+
+// sealSecret converts a secret to a SealedSecret the same way kubeseal does
+func sealSecret(doc []byte, publicKey *rsa.PublicKey, scope string) ([]byte, error) {
+	secret := corev1.Secret{}
+	if err := ghodssyaml.Unmarshal(doc, &secret); err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal secret")
+	}
+
+	if secret.Namespace == "" {
+		// secrets without a namespace are deployed to the kotsadm namespace
+		secret.Namespace = os.Getenv("POD_NAMESPACE")
+	}
+
+	data := map[string][]byte{}
+	for key, value := range secret.Data {
+		data[key] = value
+	}
+	for key, value := range secret.StringData {
+		data[key] = []byte(value)
+	}
+
+	var label []byte
+	annotations := map[string]string{}
+	switch scope {
+	case "", SealedSecretsScopeStrict:
+		label = []byte(fmt.Sprintf("%s/%s", secret.Namespace, secret.Name))
+	case SealedSecretsScopeNamespaceWide:
+		label = []byte(secret.Namespace)
+		annotations["sealedsecrets.bitnami.com/namespace-wide"] = "true"
+	case SealedSecretsScopeClusterWide:
+		annotations["sealedsecrets.bitnami.com/cluster-wide"] = "true"
+	default:
+		return nil, errors.Errorf("unsupported sealed secrets scope %q", scope)
+	}
+
+	encryptedData := map[string]string{}
+	for key, value := range data {
+		ciphertext, err := hybridEncrypt(publicKey, value, label)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to encrypt key %s", key)
+		}
+		encryptedData[key] = base64.StdEncoding.EncodeToString(ciphertext)
+	}
+
+	templateMeta := metav1.ObjectMeta{
+		Name:        secret.Name,
+		Namespace:   secret.Namespace,
+		Labels:      secret.Labels,
+		Annotations: secret.Annotations,
+	}
+
+	sealed := sealedSecret{
+		APIVersion: "bitnami.com/v1alpha1",
+		Kind:       "SealedSecret",
+		Metadata: metav1.ObjectMeta{
+			Name:      secret.Name,
+			Namespace: secret.Namespace,
+		},
+		Spec: sealedSecretSpec{
+			EncryptedData: encryptedData,
+			Template: sealedSecretTemplate{
+				Metadata: templateMeta,
+				Type:     secret.Type,
+			},
+		},
+	}
+	if len(annotations) > 0 {
+		sealed.Metadata.Annotations = annotations
+	}
+
+	b, err := ghodssyaml.Marshal(sealed)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal sealed secret")
+	}
+
+	return b, nil
+}
+
+// hybridEncrypt encrypts the plaintext with a random AES-GCM session key, which is encrypted with RSA-OAEP.
+// The output is the length of the encrypted session key, the encrypted session key and the ciphertext.
+func hybridEncrypt(publicKey *rsa.PublicKey, plaintext []byte, label []byte) ([]byte, error) {
+	sessionKey := make([]byte, 32)
+	if _, err := rand.Read(sessionKey); err != nil {
+		return nil, errors.Wrap(err, "failed to generate session key")
+	}
+
+	block, err := aes.NewCipher(sessionKey)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create aes cipher")
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create gcm")
+	}
+
+	encryptedSessionKey, err := rsa.EncryptOAEP(sha256.New(), rand.Reader, publicKey, sessionKey, label)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to encrypt session key")
+	}
+
+	ciphertext := make([]byte, 2)
+	binary.BigEndian.PutUint16(ciphertext, uint16(len(encryptedSessionKey)))
+	ciphertext = append(ciphertext, encryptedSessionKey...)
+
+	// the session key is only used once, so a zero nonce is safe
+	nonce := make([]byte, aead.NonceSize())
+	return aead.Seal(ciphertext, nonce, plaintext, nil), nil
+}
+
+// sopsEncryptSecret encrypts the data of a secret using the sops binary
+func sopsEncryptSecret(doc []byte, encryption SecretEncryption) ([]byte, error) {
+	workDir, err := ioutil.TempDir("", "kotsadm-sops")
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create temp dir")
+	}
+	defer os.RemoveAll(workDir)
+
+	secretFile := filepath.Join(workDir, "secret.yaml")
+	if err := ioutil.WriteFile(secretFile, doc, 0600); err != nil {
+		return nil, errors.Wrap(err, "failed to write secret")
+	}
+
+	args := []string{
+		"--encrypt",
+		"--input-type", "yaml",
+		"--output-type", "yaml",
+		"--encrypted-regex", "^(data|stringData)$",
+	}
+	if len(encryption.SOPSAgeRecipients) > 0 {
+		args = append(args, "--age", strings.Join(encryption.SOPSAgeRecipients, ","))
+	}
+
+	gnupgHome := filepath.Join(workDir, "gnupg")
+	if len(encryption.SOPSPGPKeys) > 0 {
+		fingerprints, err := importPGPKeys(gnupgHome, encryption.SOPSPGPKeys)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to import pgp keys")
+		}
+		args = append(args, "--pgp", strings.Join(fingerprints, ","))
+	}
+	args = append(args, secretFile)
+
+	cmd := exec.Command("sops", args...)
+	cmd.Env = append(os.Environ(), fmt.Sprintf("GNUPGHOME=%s", gnupgHome))
+	out, err := cmd.Output()
+	if err != nil {
+		if ee, ok := err.(*exec.ExitError); ok {
+			err = fmt.Errorf("sops stderr: %q", string(ee.Stderr))
+		}
+		return nil, errors.Wrap(err, "failed to run sops")
+	}
+
+	return out, nil
+}
+
+// importPGPKeys imports the keys into a new keyring and returns the fingerprints of their primary keys
+func importPGPKeys(gnupgHome string, keys []string) ([]string, error) {
+	if err := os.MkdirAll(gnupgHome, 0700); err != nil {
+		return nil, errors.Wrap(err, "failed to create gnupg home")
+	}
+
+	env := append(os.Environ(), fmt.Sprintf("GNUPGHOME=%s", gnupgHome))
+
+	cmd := exec.Command("gpg", "--batch", "--import")
+	cmd.Env = env
+	cmd.Stdin = strings.NewReader(strings.Join(keys, "\n"))
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return nil, errors.Wrapf(err, "failed to import keys: %s", string(out))
+	}
+
+	cmd = exec.Command("gpg", "--batch", "--with-colons", "--fingerprint")
+	cmd.Env = env
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list keys")
+	}
+
+	fingerprints := []string{}
+	previousRecordType := ""
+	for _, line := range strings.Split(string(out), "\n") {
+		fields := strings.Split(line, ":")
+		if fields[0] == "fpr" && previousRecordType == "pub" && len(fields) > 9 {
+			fingerprints = append(fingerprints, fields[9])
+		}
+		previousRecordType = fields[0]
+	}
+	if len(fingerprints) == 0 {
+		return nil, errors.New("no public keys found")
+	}
+
+	sort.Strings(fingerprints)
+	return fingerprints, nil
+}
@@ -0,0 +1,119 @@
+package gitops
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/pem"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	ghodssyaml "github.com/ghodss/yaml"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_EncryptSecretsSealedSecrets(t *testing.T) {
+	req := require.New(t)
+
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	req.NoError(err)
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "sealed-secret"},
+		NotBefore:    time.Now(),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	certDER, err := x509.CreateCertificate(rand.Reader, template, template, &privateKey.PublicKey, privateKey)
+	req.NoError(err)
+
+	encryption := SecretEncryption{
+		Method:            SecretEncryptionSealedSecrets,
+		SealedSecretsCert: string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: certDER})),
+	}
+	req.NoError(encryption.Validate())
+
+	manifests := []byte(`apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: config
+data:
+  key: value
+---
+apiVersion: v1
+kind: Secret
+metadata:
+  name: creds
+  namespace: app
+type: Opaque
+data:
+  password: aHVudGVyMg==
+stringData:
+  user: admin
+`)
+
+	encrypted, err := EncryptSecrets(manifests, encryption)
+	req.NoError(err)
+	req.NotContains(string(encrypted), "aHVudGVyMg==")
+	req.True(isUnchangedRender(encrypted, manifests, encryption))
+	req.False(isUnchangedRender(encrypted, append(manifests, '\n'), encryption))
+
+	docs := strings.Split(string(encrypted), "\n---\n")
+	req.Len(docs, 2)
+	req.Contains(docs[0], "kind: ConfigMap")
+
+	sealed := sealedSecret{}
+	req.NoError(ghodssyaml.Unmarshal([]byte(docs[1]), &sealed))
+	req.Equal("SealedSecret", sealed.Kind)
+	req.Equal("creds", sealed.Spec.Template.Metadata.Name)
+	req.Equal("app", sealed.Spec.Template.Metadata.Namespace)
+
+	decrypted := map[string]string{}
+	for key, value := range sealed.Spec.EncryptedData {
+		ciphertext, err := base64.StdEncoding.DecodeString(value)
+		req.NoError(err)
+
+		plaintext, err := hybridDecrypt(privateKey, ciphertext, []byte("app/creds"))
+		req.NoError(err)
+		decrypted[key] = string(plaintext)
+	}
+	req.Equal(map[string]string{"password": "hunter2", "user": "admin"}, decrypted)
+}
+
+func Test_EncryptSecretsDisabled(t *testing.T) {
+	req := require.New(t)
+
+	manifests := []byte("apiVersion: v1\nkind: Secret\nmetadata:\n  name: creds\n")
+
+	encrypted, err := EncryptSecrets(manifests, SecretEncryption{})
+	req.NoError(err)
+	req.Equal(manifests, encrypted)
+	req.True(isUnchangedRender(encrypted, manifests, SecretEncryption{}))
+}
+
+func hybridDecrypt(privateKey *rsa.PrivateKey, ciphertext []byte, label []byte) ([]byte, error) {
+	keyLen := int(binary.BigEndian.Uint16(ciphertext))
+	sessionKey, err := rsa.DecryptOAEP(sha256.New(), rand.Reader, privateKey, ciphertext[2:2+keyLen], label)
+	if err != nil {
+		return nil, err
+	}
+
+	block, err := aes.NewCipher(sessionKey)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+
+	return aead.Open(nil, make([]byte, aead.NonceSize()), ciphertext[2+keyLen:], nil)
+}
@@ -25,6 +25,8 @@ import (
 	"github.com/replicatedhq/kots/pkg/k8sutil"
 	"github.com/replicatedhq/kots/pkg/kotsadm/types"
 	"github.com/replicatedhq/kots/pkg/kotsutil"
+	"github.com/replicatedhq/kots/pkg/logger"
+	"github.com/replicatedhq/kots/pkg/sensitiveconfig"
 	"golang.org/x/crypto/ssh"
 	v1 "k8s.io/api/core/v1"
 	kuberneteserrors "k8s.io/apimachinery/pkg/api/errors"
@@ -43,16 +45,21 @@ type GitOpsConfig struct {
 	Action      string `json:"action"`
 	PublicKey   string `json:"publicKey"`
 	PrivateKey  string `json:"-"`
+	AccessToken string `json:"-"`
 	IsConnected bool   `json:"isConnected"`
+
+	SecretEncryption SecretEncryption `json:"secretEncryption"`
+	SyncStatusSource SyncStatusSource `json:"syncStatusSource"`
 }
 
 type GlobalGitOpsConfig struct {
-	Enabled  bool   `json:"enabled"`
-	Hostname string `json:"hostname"`
-	HTTPPort string `json:"httpPort"`
-	SSHPort  string `json:"sshPort"`
-	Provider string `json:"provider"`
-	URI      string `json:"uri"`
+	Enabled        bool   `json:"enabled"`
+	Hostname       string `json:"hostname"`
+	HTTPPort       string `json:"httpPort"`
+	SSHPort        string `json:"sshPort"`
+	Provider       string `json:"provider"`
+	URI            string `json:"uri"`
+	HasAccessToken bool   `json:"hasAccessToken"`
 }
 
 type KeyPair struct {
@@ -71,6 +78,9 @@ func (g *GitOpsConfig) CommitURL(hash string) string {
 	case "bitbucket", "bitbucket_server":
 		return fmt.Sprintf("%s/commits/%s", g.RepoURI, hash)
 
+	case "azure_devops":
+		return fmt.Sprintf("%s/commit/%s", g.RepoURI, hash)
+
 	default:
 		return fmt.Sprintf("%s/commit/%s", g.RepoURI, hash)
 	}
@@ -95,6 +105,14 @@ func (g *GitOpsConfig) CloneURL() (string, error) {
 		repo = uriParts[6]
 	}
 
+	if g.Provider == "azure_devops" {
+		// https://dev.azure.com/{organization}/{project}/_git/{repository}
+		if len(uriParts) < 7 || uriParts[5] != "_git" {
+			return "", errors.Errorf("unexpected azure devops url format: %s", g.RepoURI)
+		}
+		return fmt.Sprintf("git@ssh.dev.azure.com:v3/%s/%s/%s", uriParts[3], uriParts[4], uriParts[6]), nil
+	}
+
 	switch g.Provider {
 	case "github":
 		return fmt.Sprintf("git@github.com:%s/%s.git", owner, repo), nil
@@ -159,7 +177,7 @@ func GetDownstreamGitOps(appID string, clusterID string) (*GitOpsConfig, error)
 				if err != nil {
 					return nil, errors.Wrap(err, "failed to parse index")
 				}
-				provider, publicKey, privateKey, repoURI, hostname, httpPort, sshPort := gitOpsConfigFromSecretData(idx, secret.Data)
+				provider, publicKey, privateKey, repoURI, hostname, httpPort, sshPort, accessToken := gitOpsConfigFromSecretData(idx, secret.Data)
 
 				cipher, err := crypto.AESCipherFromString(os.Getenv("API_ENCRYPTION_KEY"))
 				if err != nil {
@@ -175,24 +193,49 @@ func GetDownstreamGitOps(appID string, clusterID string) (*GitOpsConfig, error)
 					return nil, errors.Wrap(err, "failed to decrypt")
 				}
 
+				decryptedAccessToken := []byte{}
+				if accessToken != "" {
+					decodedAccessToken, err := base64.StdEncoding.DecodeString(accessToken)
+					if err != nil {
+						return nil, errors.Wrap(err, "failed to decode access token")
+					}
+					decryptedAccessToken, err = cipher.Decrypt(decodedAccessToken)
+					if err != nil {
+						return nil, errors.Wrap(err, "failed to decrypt access token")
+					}
+				}
+
 				gitOpsConfig := GitOpsConfig{
-					Provider:   provider,
-					PublicKey:  publicKey,
-					PrivateKey: string(decryptedPrivateKey),
-					RepoURI:    repoURI,
-					Hostname:   hostname,
-					HTTPPort:   httpPort,
-					SSHPort:    sshPort,
-					Branch:     configMapData["branch"],
-					Path:       configMapData["path"],
-					Format:     configMapData["format"],
-					Action:     configMapData["action"],
+					Provider:    provider,
+					PublicKey:   publicKey,
+					PrivateKey:  string(decryptedPrivateKey),
+					AccessToken: string(decryptedAccessToken),
+					RepoURI:     repoURI,
+					Hostname:    hostname,
+					HTTPPort:    httpPort,
+					SSHPort:     sshPort,
+					Branch:      configMapData["branch"],
+					Path:        configMapData["path"],
+					Format:      configMapData["format"],
+					Action:      configMapData["action"],
 				}
 
 				if lastError, ok := configMapData["lastError"]; ok && lastError == "" {
 					gitOpsConfig.IsConnected = true
 				}
 
+				if secretEncryption := configMapData["secretEncryption"]; secretEncryption != "" {
+					if err := json.Unmarshal([]byte(secretEncryption), &gitOpsConfig.SecretEncryption); err != nil {
+						return nil, errors.Wrap(err, "failed to unmarshal secret encryption")
+					}
+				}
+
+				if syncStatusSource := configMapData["syncStatusSource"]; syncStatusSource != "" {
+					if err := json.Unmarshal([]byte(syncStatusSource), &gitOpsConfig.SyncStatusSource); err != nil {
+						return nil, errors.Wrap(err, "failed to unmarshal sync status source")
+					}
+				}
+
 				return &gitOpsConfig, nil
 			}
 		}
@@ -226,7 +269,14 @@ func DisableDownstreamGitOps(appID string, clusterID string) error {
 	return nil
 }
 
-func UpdateDownstreamGitOps(appID, clusterID, uri, branch, path, format, action string) error {
+func UpdateDownstreamGitOps(appID, clusterID, uri, branch, path, format, action string, secretEncryption SecretEncryption, syncStatusSource SyncStatusSource) error {
+	if err := secretEncryption.Validate(); err != nil {
+		return errors.Wrap(err, "invalid secret encryption")
+	}
+	if err := syncStatusSource.Validate(); err != nil {
+		return errors.Wrap(err, "invalid sync status source")
+	}
+
 	clientset, err := k8sutil.GetClientset()
 	if err != nil {
 		return errors.Wrap(err, "failed to get k8s client set")
@@ -256,6 +306,22 @@ func UpdateDownstreamGitOps(appID, clusterID, uri, branch, path, format, action
 		"action":  action,
 	}
 
+	if secretEncryption.Method != "" {
+		secretEncryptionMarshalled, err := json.Marshal(secretEncryption)
+		if err != nil {
+			return errors.Wrap(err, "failed to marshal secret encryption")
+		}
+		newAppData["secretEncryption"] = string(secretEncryptionMarshalled)
+	}
+
+	if syncStatusSource.Engine != "" {
+		syncStatusSourceMarshalled, err := json.Marshal(syncStatusSource)
+		if err != nil {
+			return errors.Wrap(err, "failed to marshal sync status source")
+		}
+		newAppData["syncStatusSource"] = string(syncStatusSourceMarshalled)
+	}
+
 	// check if to reset or keep last error
 	appDataEncoded, ok := configMapData[appKey]
 	if ok {
@@ -390,7 +456,9 @@ func TestGitOpsConnection(gitOpsConfig *GitOpsConfig) (string, error) {
 	return ref.Name().Short(), nil
 }
 
-func CreateGitOps(provider string, repoURI string, hostname string, httpPort string, sshPort string) error {
+// CreateGitOps creates or updates the provider for repoURI. The access token is only used for the commit status api
+// of providers that support it, and an existing token is kept if accessToken is empty.
+func CreateGitOps(provider string, repoURI string, hostname string, httpPort string, sshPort string, accessToken string) error {
 	clientset, err := k8sutil.GetClientset()
 	if err != nil {
 		return errors.Wrap(err, "failed to get k8s client set")
@@ -491,6 +559,15 @@ func CreateGitOps(provider string, repoURI string, hostname string, httpPort str
 		secretData[sshPortKey] = []byte(sshPort)
 	}
 
+	if accessToken != "" {
+		cipher, err := crypto.AESCipherFromString(os.Getenv("API_ENCRYPTION_KEY"))
+		if err != nil {
+			return errors.Wrap(err, "failed to create aes cipher")
+		}
+		encryptedAccessToken := cipher.Encrypt([]byte(accessToken))
+		secretData[fmt.Sprintf("provider.%d.accessToken", repoIdx)] = []byte(base64.StdEncoding.EncodeToString(encryptedAccessToken))
+	}
+
 	if secretExists {
 		secret.Data = secretData
 		_, err = clientset.CoreV1().Secrets(os.Getenv("POD_NAMESPACE")).Update(context.TODO(), secret, metav1.UpdateOptions{})
@@ -554,12 +631,14 @@ func GetGitOps() (GlobalGitOpsConfig, error) {
 		Hostname: string(secret.Data["provider.0.hostname"]),
 		HTTPPort: string(secret.Data["provider.0.httpPort"]),
 		SSHPort:  string(secret.Data["provider.0.sshPort"]),
+
+		HasAccessToken: len(secret.Data["provider.0.accessToken"]) > 0,
 	}
 
 	return parsedConfig, nil
 }
 
-func gitOpsConfigFromSecretData(idx int64, secretData map[string][]byte) (string, string, string, string, string, string, string) {
+func gitOpsConfigFromSecretData(idx int64, secretData map[string][]byte) (string, string, string, string, string, string, string, string) {
 	provider := ""
 	publicKey := ""
 	privateKey := ""
@@ -567,6 +646,7 @@ func gitOpsConfigFromSecretData(idx int64, secretData map[string][]byte) (string
 	hostname := ""
 	httpPort := ""
 	sshPort := ""
+	accessToken := ""
 
 	providerDecoded, ok := secretData[fmt.Sprintf("provider.%d.type", idx)]
 	if ok {
@@ -603,7 +683,12 @@ func gitOpsConfigFromSecretData(idx int64, secretData map[string][]byte) (string
 		sshPort = string(sshPortDecoded)
 	}
 
-	return provider, publicKey, privateKey, repoURI, hostname, httpPort, sshPort
+	accessTokenDecoded, ok := secretData[fmt.Sprintf("provider.%d.accessToken", idx)]
+	if ok {
+		accessToken = string(accessTokenDecoded)
+	}
+
+	return provider, publicKey, privateKey, repoURI, hostname, httpPort, sshPort, accessToken
 }
 
 func getAuth(privateKey string) (transport.AuthMethod, error) {
@@ -633,6 +718,30 @@ func CreateGitOpsCommit(gitOpsConfig *GitOpsConfig, appSlug string, appName stri
 		return "", errors.Wrap(err, "failed to run kustomize")
 	}
 
+	// the secret with the sensitive config values is created by the deploy, which gitops replaces. it's only pushed
+	// when secrets are encrypted, otherwise it has to be created in the cluster by other means.
+	if sensitiveconfig.IsEnabled(&kotsKinds.KotsApplication) {
+		if gitOpsConfig.SecretEncryption.Method == "" {
+			logger.Infof("not pushing the sensitive config secret of %s, secret encryption is not configured", appSlug)
+		} else {
+			cipher, err := crypto.AESCipherFromString(kotsKinds.Installation.Spec.EncryptionKey)
+			if err != nil {
+				return "", errors.Wrap(err, "failed to load encryption cipher")
+			}
+			sensitiveConfigSecret, err := sensitiveconfig.BuildSecretManifest(appSlug, kotsKinds.Config, kotsKinds.ConfigValues, cipher)
+			if err != nil {
+				return "", errors.Wrap(err, "failed to build sensitive config secret")
+			}
+			out = append(out, []byte("\n---\n")...)
+			out = append(out, sensitiveConfigSecret...)
+		}
+	}
+
+	manifests, err := EncryptSecrets(out, gitOpsConfig.SecretEncryption)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to encrypt secrets")
+	}
+
 	// using the deploy key, create the commit in a new branch
 	auth, err := getAuth(gitOpsConfig.PrivateKey)
 	if err != nil {
@@ -678,14 +787,14 @@ func CreateGitOpsCommit(gitOpsConfig *GitOpsConfig, appSlug string, appName stri
 		if err != nil {
 			return "", errors.Wrap(err, "failed to read current app yaml")
 		}
-		if string(currentRevision) == string(out) {
+		if isUnchangedRender(currentRevision, out, gitOpsConfig.SecretEncryption) {
 			return "", nil
 		}
 	} else if !os.IsNotExist(err) {
 		return "", errors.Wrap(err, "failed to stat current app yaml")
 	}
 
-	err = ioutil.WriteFile(filePath, out, 0644)
+	err = ioutil.WriteFile(filePath, manifests, 0644)
 	if err != nil {
 		return "", errors.Wrap(err, "failed to write updated app yaml")
 	}
@@ -715,6 +824,11 @@ func CreateGitOpsCommit(gitOpsConfig *GitOpsConfig, appSlug string, appName stri
 		return "", errors.Wrap(err, "failed to push")
 	}
 
+	description := fmt.Sprintf("%s version %d", appName, newSequence)
+	if err := SetCommitStatus(gitOpsConfig, updatedHash.String(), CommitStatusSuccess, description); err != nil {
+		logger.Error(errors.Wrap(err, "failed to set commit status"))
+	}
+
 	return gitOpsConfig.CommitURL(updatedHash.String()), nil
 }
 
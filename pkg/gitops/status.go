@@ -0,0 +1,150 @@
+package gitops
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	CommitStatusPending = "pending"
+	CommitStatusSuccess = "success"
+	CommitStatusFailure = "failure"
+
+	// commitStatusKey identifies the statuses set by kots on a commit
+	commitStatusKey = "kots"
+)
+
+// SupportsCommitStatus returns true if commit statuses can be set for the provider
+func (g *GitOpsConfig) SupportsCommitStatus() bool {
+	switch g.Provider {
+	case "bitbucket_server", "azure_devops":
+		return g.AccessToken != ""
+	}
+	return false
+}
+
+// SetCommitStatus sets the kots status of a commit using the provider's commit status api.
+// It does nothing if the provider does not support commit statuses or no access token is configured.
+func SetCommitStatus(gitOpsConfig *GitOpsConfig, hash string, state string, description string) error {
+	if !gitOpsConfig.SupportsCommitStatus() {
+		return nil
+	}
+
+	var req *http.Request
+	var err error
+	switch gitOpsConfig.Provider {
+	case "bitbucket_server":
+		req, err = bitbucketServerCommitStatusRequest(gitOpsConfig, hash, state, description)
+	case "azure_devops":
+		req, err = azureDevOpsCommitStatusRequest(gitOpsConfig, hash, state, description)
+	}
+	if err != nil {
+		return errors.Wrap(err, "failed to create request")
+	}
+
+	client := &http.Client{Timeout: 30 * time.Second}
+	resp, err := client.Do(req)
+	if err != nil {
+		return errors.Wrap(err, "failed to send request")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		body, _ := ioutil.ReadAll(resp.Body)
+		return errors.Errorf("unexpected status code %d: %s", resp.StatusCode, string(body))
+	}
+
+	return nil
+}
+
+// bitbucketServerCommitStatusRequest uses the build status api, authenticated with an http access token.
+// https://developer.atlassian.com/server/bitbucket/how-tos/updating-build-status-for-commits/
+func bitbucketServerCommitStatusRequest(gitOpsConfig *GitOpsConfig, hash string, state string, description string) (*http.Request, error) {
+	repoURL, err := url.Parse(gitOpsConfig.RepoURI)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to parse repo uri")
+	}
+
+	bitbucketState := "INPROGRESS"
+	switch state {
+	case CommitStatusSuccess:
+		bitbucketState = "SUCCESSFUL"
+	case CommitStatusFailure:
+		bitbucketState = "FAILED"
+	}
+
+	body, err := json.Marshal(map[string]string{
+		"state":       bitbucketState,
+		"key":         commitStatusKey,
+		"name":        "KOTS Admin Console",
+		"url":         gitOpsConfig.CommitURL(hash),
+		"description": description,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal body")
+	}
+
+	statusURL := fmt.Sprintf("%s://%s/rest/build-status/1.0/commits/%s", repoURL.Scheme, repoURL.Host, hash)
+	req, err := http.NewRequest("POST", statusURL, bytes.NewReader(body))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create request")
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", gitOpsConfig.AccessToken))
+
+	return req, nil
+}
+
+// azureDevOpsCommitStatusRequest uses the git statuses api, authenticated with a personal access token.
+// https://docs.microsoft.com/en-us/rest/api/azure/devops/git/statuses/create
+func azureDevOpsCommitStatusRequest(gitOpsConfig *GitOpsConfig, hash string, state string, description string) (*http.Request, error) {
+	// https://dev.azure.com/{organization}/{project}/_git/{repository}
+	uriParts := strings.Split(gitOpsConfig.RepoURI, "/")
+	if len(uriParts) < 7 || uriParts[5] != "_git" {
+		return nil, errors.Errorf("unexpected azure devops url format: %s", gitOpsConfig.RepoURI)
+	}
+	organization, project, repository := uriParts[3], uriParts[4], uriParts[6]
+
+	azureState := "pending"
+	switch state {
+	case CommitStatusSuccess:
+		azureState = "succeeded"
+	case CommitStatusFailure:
+		azureState = "failed"
+	}
+
+	body, err := json.Marshal(map[string]interface{}{
+		"state":       azureState,
+		"description": description,
+		"targetUrl":   gitOpsConfig.CommitURL(hash),
+		"context": map[string]string{
+			"name":  commitStatusKey,
+			"genre": "continuous-deployment",
+		},
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal body")
+	}
+
+	statusURL := fmt.Sprintf("%s://%s/%s/%s/_apis/git/repositories/%s/commits/%s/statuses?api-version=6.0",
+		strings.TrimSuffix(uriParts[0], ":"), uriParts[2], organization, project, repository, hash)
+	req, err := http.NewRequest("POST", statusURL, bytes.NewReader(body))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create request")
+	}
+	req.Header.Set("Content-Type", "application/json")
+	// personal access tokens are sent as the password with an empty username
+	basicAuth := base64.StdEncoding.EncodeToString([]byte(":" + gitOpsConfig.AccessToken))
+	req.Header.Set("Authorization", fmt.Sprintf("Basic %s", basicAuth))
+
+	return req, nil
+}
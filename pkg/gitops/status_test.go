@@ -0,0 +1,133 @@
+package gitops
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_SetCommitStatus(t *testing.T) {
+	type request struct {
+		path          string
+		authorization string
+		body          map[string]interface{}
+	}
+
+	var received *request
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body := map[string]interface{}{}
+		json.NewDecoder(r.Body).Decode(&body)
+		received = &request{
+			path:          r.URL.Path,
+			authorization: r.Header.Get("Authorization"),
+			body:          body,
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name          string
+		gitOpsConfig  GitOpsConfig
+		wantPath      string
+		wantAuth      string
+		wantState     string
+		wantNoRequest bool
+	}{
+		{
+			name: "bitbucket server",
+			gitOpsConfig: GitOpsConfig{
+				Provider:    "bitbucket_server",
+				RepoURI:     server.URL + "/projects/PRJ/repos/app",
+				AccessToken: "token",
+			},
+			wantPath:  "/rest/build-status/1.0/commits/abc123",
+			wantAuth:  "Bearer token",
+			wantState: "SUCCESSFUL",
+		},
+		{
+			name: "azure devops",
+			gitOpsConfig: GitOpsConfig{
+				Provider:    "azure_devops",
+				RepoURI:     server.URL + "/org/project/_git/app",
+				AccessToken: "token",
+			},
+			wantPath:  "/org/project/_apis/git/repositories/app/commits/abc123/statuses",
+			wantAuth:  "Basic OnRva2Vu",
+			wantState: "succeeded",
+		},
+		{
+			name: "no access token",
+			gitOpsConfig: GitOpsConfig{
+				Provider: "azure_devops",
+				RepoURI:  server.URL + "/org/project/_git/app",
+			},
+			wantNoRequest: true,
+		},
+		{
+			name: "unsupported provider",
+			gitOpsConfig: GitOpsConfig{
+				Provider:    "github",
+				RepoURI:     "https://github.com/owner/app",
+				AccessToken: "token",
+			},
+			wantNoRequest: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			req := require.New(t)
+			received = nil
+
+			err := SetCommitStatus(&test.gitOpsConfig, "abc123", CommitStatusSuccess, "app version 1")
+			req.NoError(err)
+
+			if test.wantNoRequest {
+				req.Nil(received)
+				return
+			}
+
+			req.NotNil(received)
+			req.Equal(test.wantPath, received.path)
+			req.Equal(test.wantAuth, received.authorization)
+			req.Equal(test.wantState, received.body["state"])
+			req.Equal("app version 1", received.body["description"])
+		})
+	}
+}
+
+func Test_CloneURL(t *testing.T) {
+	tests := []struct {
+		name         string
+		gitOpsConfig GitOpsConfig
+		want         string
+	}{
+		{
+			name:         "github",
+			gitOpsConfig: GitOpsConfig{Provider: "github", RepoURI: "https://github.com/owner/app"},
+			want:         "git@github.com:owner/app.git",
+		},
+		{
+			name:         "bitbucket server",
+			gitOpsConfig: GitOpsConfig{Provider: "bitbucket_server", RepoURI: "https://bitbucket.example.com:7990/projects/PRJ/repos/app", Hostname: "bitbucket.example.com", SSHPort: "7999"},
+			want:         "git@bitbucket.example.com:7999/PRJ/app.git",
+		},
+		{
+			name:         "azure devops",
+			gitOpsConfig: GitOpsConfig{Provider: "azure_devops", RepoURI: "https://dev.azure.com/org/project/_git/app"},
+			want:         "git@ssh.dev.azure.com:v3/org/project/app",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			cloneURL, err := test.gitOpsConfig.CloneURL()
+			require.NoError(t, err)
+			require.Equal(t, test.want, cloneURL)
+		})
+	}
+}
@@ -0,0 +1,241 @@
+package gitops
+
+import (
+	"context"
+	"strings"
+
+	"github.com/pkg/errors"
+	appstatustypes "github.com/replicatedhq/kots/pkg/api/appstatus/types"
+	"github.com/replicatedhq/kots/pkg/k8sutil"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+)
+
+const (
+	SyncEngineArgoCD = "argocd"
+	SyncEngineFlux   = "flux"
+
+	SyncPhaseSynced  = "synced"
+	SyncPhaseSyncing = "syncing"
+	SyncPhaseFailed  = "failed"
+
+	defaultArgoCDNamespace = "argocd"
+	defaultFluxNamespace   = "flux-system"
+)
+
+var (
+	argoCDApplicationGVR = schema.GroupVersionResource{
+		Group:    "argoproj.io",
+		Version:  "v1alpha1",
+		Resource: "applications",
+	}
+	fluxKustomizationGVR = schema.GroupVersionResource{
+		Group:    "kustomize.toolkit.fluxcd.io",
+		Version:  "v1beta1",
+		Resource: "kustomizations",
+	}
+)
+
+// SyncStatusSource is the gitops engine resource that applies the commits pushed for a downstream
+type SyncStatusSource struct {
+	// Engine is one of "argocd" or "flux", sync status is not read if empty
+	Engine string `json:"engine"`
+	// Name is the name of the Argo CD Application or Flux Kustomization
+	Name string `json:"name"`
+	// Namespace defaults to "argocd" for Argo CD and "flux-system" for Flux
+	Namespace string `json:"namespace,omitempty"`
+}
+
+// SyncStatus is the state of a downstream as reported by the gitops engine
+type SyncStatus struct {
+	// Revision is the commit that was last applied
+	Revision string
+	// AttemptedRevision is the commit that was last attempted, which is the same as Revision unless it failed or is in progress
+	AttemptedRevision string
+	// Phase is one of "synced", "syncing" or "failed"
+	Phase   string
+	State   appstatustypes.State
+	Message string
+}
+
+type argoCDApplication struct {
+	Status struct {
+		Sync struct {
+			Status   string `json:"status"`
+			Revision string `json:"revision"`
+		} `json:"sync"`
+		Health struct {
+			Status  string `json:"status"`
+			Message string `json:"message"`
+		} `json:"health"`
+		OperationState *struct {
+			Phase      string `json:"phase"`
+			Message    string `json:"message"`
+			SyncResult *struct {
+				Revision string `json:"revision"`
+			} `json:"syncResult"`
+		} `json:"operationState"`
+	} `json:"status"`
+}
+
+type fluxKustomization struct {
+	Status struct {
+		LastAppliedRevision   string             `json:"lastAppliedRevision"`
+		LastAttemptedRevision string             `json:"lastAttemptedRevision"`
+		Conditions            []metav1.Condition `json:"conditions"`
+	} `json:"status"`
+}
+
+func (s SyncStatusSource) Validate() error {
+	switch s.Engine {
+	case "":
+		return nil
+	case SyncEngineArgoCD, SyncEngineFlux:
+		if s.Name == "" {
+			return errors.New("name is required")
+		}
+		return nil
+	}
+
+	return errors.Errorf("unsupported gitops engine %q", s.Engine)
+}
+
+// GetSyncStatus reads the sync and health status of the source from the cluster
+func GetSyncStatus(source SyncStatusSource) (*SyncStatus, error) {
+	cfg, err := k8sutil.GetClusterConfig()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get cluster config")
+	}
+
+	dynamicClient, err := dynamic.NewForConfig(cfg)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create dynamic client")
+	}
+
+	switch source.Engine {
+	case SyncEngineArgoCD:
+		namespace := source.Namespace
+		if namespace == "" {
+			namespace = defaultArgoCDNamespace
+		}
+		obj, err := dynamicClient.Resource(argoCDApplicationGVR).Namespace(namespace).Get(context.TODO(), source.Name, metav1.GetOptions{})
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to get argo cd application")
+		}
+		return argoCDSyncStatus(obj)
+
+	case SyncEngineFlux:
+		namespace := source.Namespace
+		if namespace == "" {
+			namespace = defaultFluxNamespace
+		}
+		obj, err := dynamicClient.Resource(fluxKustomizationGVR).Namespace(namespace).Get(context.TODO(), source.Name, metav1.GetOptions{})
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to get flux kustomization")
+		}
+		return fluxSyncStatus(obj)
+	}
+
+	return nil, errors.Errorf("unsupported gitops engine %q", source.Engine)
+}
+
+func argoCDSyncStatus(obj *unstructured.Unstructured) (*SyncStatus, error) {
+	app := argoCDApplication{}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, &app); err != nil {
+		return nil, errors.Wrap(err, "failed to convert argo cd application")
+	}
+
+	syncStatus := &SyncStatus{
+		Revision:          app.Status.Sync.Revision,
+		AttemptedRevision: app.Status.Sync.Revision,
+		Phase:             SyncPhaseSyncing,
+		Message:           app.Status.Health.Message,
+	}
+
+	if app.Status.Sync.Status == "Synced" {
+		syncStatus.Phase = SyncPhaseSynced
+	}
+
+	if op := app.Status.OperationState; op != nil {
+		if op.SyncResult != nil && op.SyncResult.Revision != "" {
+			syncStatus.AttemptedRevision = op.SyncResult.Revision
+		}
+		switch op.Phase {
+		case "Failed", "Error":
+			syncStatus.Phase = SyncPhaseFailed
+			syncStatus.Message = op.Message
+		case "Running", "Terminating":
+			syncStatus.Phase = SyncPhaseSyncing
+		}
+	}
+
+	switch app.Status.Health.Status {
+	case "Healthy":
+		syncStatus.State = appstatustypes.StateReady
+	case "Degraded":
+		syncStatus.State = appstatustypes.StateDegraded
+	case "Missing":
+		syncStatus.State = appstatustypes.StateMissing
+	default: // Progressing, Suspended, Unknown
+		syncStatus.State = appstatustypes.StateUnavailable
+	}
+
+	return syncStatus, nil
+}
+
+func fluxSyncStatus(obj *unstructured.Unstructured) (*SyncStatus, error) {
+	kustomization := fluxKustomization{}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, &kustomization); err != nil {
+		return nil, errors.Wrap(err, "failed to convert flux kustomization")
+	}
+
+	syncStatus := &SyncStatus{
+		Revision:          fluxRevisionCommit(kustomization.Status.LastAppliedRevision),
+		AttemptedRevision: fluxRevisionCommit(kustomization.Status.LastAttemptedRevision),
+		Phase:             SyncPhaseSyncing,
+		State:             appstatustypes.StateUnavailable,
+	}
+	if syncStatus.AttemptedRevision == "" {
+		syncStatus.AttemptedRevision = syncStatus.Revision
+	}
+
+	for _, condition := range kustomization.Status.Conditions {
+		if condition.Type != "Ready" {
+			continue
+		}
+
+		syncStatus.Message = condition.Message
+		switch condition.Status {
+		case metav1.ConditionTrue:
+			syncStatus.Phase = SyncPhaseSynced
+			syncStatus.State = appstatustypes.StateReady
+		case metav1.ConditionFalse:
+			syncStatus.State = appstatustypes.StateDegraded
+			if condition.Reason != "Progressing" {
+				syncStatus.Phase = SyncPhaseFailed
+			}
+		}
+	}
+
+	return syncStatus, nil
+}
+
+// fluxRevisionCommit returns the commit from a flux revision, which is formatted as "<branch>/<commit>" or "<branch>@sha1:<commit>"
+func fluxRevisionCommit(revision string) string {
+	if idx := strings.LastIndexAny(revision, "/:"); idx != -1 {
+		return revision[idx+1:]
+	}
+	return revision
+}
+
+// CommitFromURL returns the commit hash from a commit url created by CommitURL
+func CommitFromURL(commitURL string) string {
+	if commitURL == "" {
+		return ""
+	}
+	parts := strings.Split(strings.TrimSuffix(commitURL, "/"), "/")
+	return parts[len(parts)-1]
+}
@@ -0,0 +1,100 @@
+package gitops
+
+import (
+	"testing"
+
+	appstatustypes "github.com/replicatedhq/kots/pkg/api/appstatus/types"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func Test_argoCDSyncStatus(t *testing.T) {
+	tests := []struct {
+		name   string
+		status map[string]interface{}
+		want   SyncStatus
+	}{
+		{
+			name: "synced and healthy",
+			status: map[string]interface{}{
+				"sync":   map[string]interface{}{"status": "Synced", "revision": "abc"},
+				"health": map[string]interface{}{"status": "Healthy"},
+				"operationState": map[string]interface{}{
+					"phase":      "Succeeded",
+					"syncResult": map[string]interface{}{"revision": "abc"},
+				},
+			},
+			want: SyncStatus{Revision: "abc", AttemptedRevision: "abc", Phase: SyncPhaseSynced, State: appstatustypes.StateReady},
+		},
+		{
+			name: "sync failed",
+			status: map[string]interface{}{
+				"sync":   map[string]interface{}{"status": "OutOfSync", "revision": "abc"},
+				"health": map[string]interface{}{"status": "Degraded"},
+				"operationState": map[string]interface{}{
+					"phase":      "Failed",
+					"message":    "one or more objects failed to apply",
+					"syncResult": map[string]interface{}{"revision": "def"},
+				},
+			},
+			want: SyncStatus{Revision: "abc", AttemptedRevision: "def", Phase: SyncPhaseFailed, State: appstatustypes.StateDegraded, Message: "one or more objects failed to apply"},
+		},
+		{
+			name: "progressing",
+			status: map[string]interface{}{
+				"sync":   map[string]interface{}{"status": "OutOfSync", "revision": "abc"},
+				"health": map[string]interface{}{"status": "Progressing"},
+			},
+			want: SyncStatus{Revision: "abc", AttemptedRevision: "abc", Phase: SyncPhaseSyncing, State: appstatustypes.StateUnavailable},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			obj := &unstructured.Unstructured{Object: map[string]interface{}{"status": test.status}}
+			got, err := argoCDSyncStatus(obj)
+			require.NoError(t, err)
+			require.Equal(t, test.want, *got)
+		})
+	}
+}
+
+func Test_fluxSyncStatus(t *testing.T) {
+	tests := []struct {
+		name   string
+		status map[string]interface{}
+		want   SyncStatus
+	}{
+		{
+			name: "ready",
+			status: map[string]interface{}{
+				"lastAppliedRevision":   "main/abc",
+				"lastAttemptedRevision": "main/abc",
+				"conditions": []interface{}{
+					map[string]interface{}{"type": "Ready", "status": "True", "reason": "ReconciliationSucceeded", "message": "Applied revision: main/abc", "lastTransitionTime": "2021-05-01T00:00:00Z"},
+				},
+			},
+			want: SyncStatus{Revision: "abc", AttemptedRevision: "abc", Phase: SyncPhaseSynced, State: appstatustypes.StateReady, Message: "Applied revision: main/abc"},
+		},
+		{
+			name: "failed",
+			status: map[string]interface{}{
+				"lastAppliedRevision":   "main@sha1:abc",
+				"lastAttemptedRevision": "main@sha1:def",
+				"conditions": []interface{}{
+					map[string]interface{}{"type": "Ready", "status": "False", "reason": "ReconciliationFailed", "message": "validation failed", "lastTransitionTime": "2021-05-01T00:00:00Z"},
+				},
+			},
+			want: SyncStatus{Revision: "abc", AttemptedRevision: "def", Phase: SyncPhaseFailed, State: appstatustypes.StateDegraded, Message: "validation failed"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			obj := &unstructured.Unstructured{Object: map[string]interface{}{"status": test.status}}
+			got, err := fluxSyncStatus(obj)
+			require.NoError(t, err)
+			require.Equal(t, test.want, *got)
+		})
+	}
+}
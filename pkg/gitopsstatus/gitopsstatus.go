@@ -0,0 +1,158 @@
+package gitopsstatus
+
+import (
+	"time"
+
+	"github.com/pkg/errors"
+	appstatustypes "github.com/replicatedhq/kots/pkg/api/appstatus/types"
+	downstreamtypes "github.com/replicatedhq/kots/pkg/api/downstream/types"
+	apptypes "github.com/replicatedhq/kots/pkg/app/types"
+	"github.com/replicatedhq/kots/pkg/gitops"
+	"github.com/replicatedhq/kots/pkg/logger"
+	"github.com/replicatedhq/kots/pkg/store"
+)
+
+// Start reads the sync and health status of gitops enabled downstreams from their gitops engine
+// and reflects it as the app status and the status of the version that was applied
+func Start() error {
+	logger.Debug("starting gitops status loop")
+
+	startLoop(syncStatusLoop, 30)
+
+	return nil
+}
+
+func startLoop(fn func(), intervalInSeconds time.Duration) {
+	go func() {
+		for {
+			fn()
+			time.Sleep(time.Second * intervalInSeconds)
+		}
+	}()
+}
+
+func syncStatusLoop() {
+	appsList, err := store.GetStore().ListInstalledApps()
+	if err != nil {
+		logger.Error(errors.Wrap(err, "failed to list installed apps for gitops status"))
+		return
+	}
+
+	for _, a := range appsList {
+		if a.RestoreInProgressName != "" || a.IsArchived {
+			continue
+		}
+		if err := handleApp(a); err != nil {
+			logger.Error(errors.Wrapf(err, "failed to handle gitops status for app %s", a.ID))
+		}
+	}
+}
+
+func handleApp(a *apptypes.App) error {
+	downstreams, err := store.GetStore().ListDownstreamsForApp(a.ID)
+	if err != nil {
+		return errors.Wrap(err, "failed to list downstreams")
+	}
+
+	for _, d := range downstreams {
+		downstreamGitOps, err := gitops.GetDownstreamGitOps(a.ID, d.ClusterID)
+		if err != nil {
+			return errors.Wrap(err, "failed to get downstream gitops")
+		}
+		if downstreamGitOps == nil || downstreamGitOps.SyncStatusSource.Engine == "" {
+			continue
+		}
+
+		syncStatus, err := gitops.GetSyncStatus(downstreamGitOps.SyncStatusSource)
+		if err != nil {
+			return errors.Wrap(err, "failed to get sync status")
+		}
+
+		if err := updateStatus(a.ID, d.ClusterID, downstreamGitOps.SyncStatusSource, syncStatus); err != nil {
+			return errors.Wrap(err, "failed to update status")
+		}
+	}
+
+	return nil
+}
+
+func updateStatus(appID string, clusterID string, source gitops.SyncStatusSource, syncStatus *gitops.SyncStatus) error {
+	versions, err := store.GetStore().GetPendingVersions(appID, clusterID)
+	if err != nil {
+		return errors.Wrap(err, "failed to get pending versions")
+	}
+	currentVersion, err := store.GetStore().GetCurrentVersion(appID, clusterID)
+	if err != nil {
+		return errors.Wrap(err, "failed to get current version")
+	}
+	if currentVersion != nil {
+		versions = append(versions, *currentVersion)
+	}
+
+	appliedVersion := findVersionForCommit(versions, syncStatus.Revision)
+	attemptedVersion := findVersionForCommit(versions, syncStatus.AttemptedRevision)
+
+	if attemptedVersion != nil {
+		status, statusInfo := versionStatus(syncStatus)
+		if attemptedVersion.Status != status {
+			if err := store.GetStore().UpdateDownstreamVersionStatus(appID, attemptedVersion.Sequence, status, statusInfo); err != nil {
+				return errors.Wrap(err, "failed to update attempted version status")
+			}
+		}
+	}
+
+	if appliedVersion != nil && appliedVersion != attemptedVersion && appliedVersion.Status != "deployed" {
+		if err := store.GetStore().UpdateDownstreamVersionStatus(appID, appliedVersion.Sequence, "deployed", ""); err != nil {
+			return errors.Wrap(err, "failed to update applied version status")
+		}
+	}
+
+	sequence := int64(-1)
+	if appliedVersion != nil {
+		sequence = appliedVersion.Sequence
+	} else if currentVersion != nil {
+		sequence = currentVersion.Sequence
+	}
+
+	kind := "Application"
+	if source.Engine == gitops.SyncEngineFlux {
+		kind = "Kustomization"
+	}
+	resourceStates := []appstatustypes.ResourceState{
+		{
+			Kind:      kind,
+			Name:      source.Name,
+			Namespace: source.Namespace,
+			State:     syncStatus.State,
+		},
+	}
+	if err := store.GetStore().SetAppStatus(appID, resourceStates, time.Now(), sequence); err != nil {
+		return errors.Wrap(err, "failed to set app status")
+	}
+
+	return nil
+}
+
+// findVersionForCommit returns the version whose gitops commit is the given commit
+func findVersionForCommit(versions []downstreamtypes.DownstreamVersion, commit string) *downstreamtypes.DownstreamVersion {
+	if commit == "" {
+		return nil
+	}
+	for i, v := range versions {
+		if gitops.CommitFromURL(v.CommitURL) == commit {
+			return &versions[i]
+		}
+	}
+	return nil
+}
+
+// versionStatus maps the sync phase to a downstream version status
+func versionStatus(syncStatus *gitops.SyncStatus) (string, string) {
+	switch syncStatus.Phase {
+	case gitops.SyncPhaseSynced:
+		return "deployed", ""
+	case gitops.SyncPhaseFailed:
+		return "failed", syncStatus.Message
+	}
+	return "deploying", ""
+}
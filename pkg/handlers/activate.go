@@ -0,0 +1,121 @@
+package handlers
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+	"github.com/pkg/errors"
+	handlertypes "github.com/replicatedhq/kots/pkg/api/handlers/types"
+	deployhistorytypes "github.com/replicatedhq/kots/pkg/deployhistory/types"
+	"github.com/replicatedhq/kots/pkg/logger"
+	"github.com/replicatedhq/kots/pkg/socketservice"
+	"github.com/replicatedhq/kots/pkg/store"
+	"github.com/replicatedhq/kots/pkg/version"
+	"go.uber.org/zap"
+)
+
+// ActivateAppVersion applies the resources that were held back when a version of an app that is deployed in two
+// phases was deployed, and removes the resources of the previously activated version
+func (h *Handler) ActivateAppVersion(w http.ResponseWriter, r *http.Request) {
+	appSlug := mux.Vars(r)["appSlug"]
+	sequence, err := strconv.ParseInt(mux.Vars(r)["sequence"], 10, 64)
+	if err != nil {
+		BadRequestJSON(w, r, "failed to parse sequence", err)
+		return
+	}
+
+	a, err := store.GetStore().GetAppFromSlug(appSlug)
+	if err != nil {
+		NotFoundJSON(w, r, "failed to get app from slug", err)
+		return
+	}
+
+	downstreams, err := store.GetStore().ListDownstreamsForApp(a.ID)
+	if err != nil {
+		InternalErrorJSON(w, r, "failed to list downstreams for app", err)
+		return
+	} else if len(downstreams) == 0 {
+		InternalErrorJSON(w, r, "failed to activate version", errors.New("no downstreams for app"))
+		return
+	}
+
+	deployer := deployhistorytypes.Deployer{DeployedBy: sessionUserID(r)}
+	err = socketservice.ActivateAppVersion(a.ID, downstreams[0].ClusterID, sequence, deployer)
+	switch errors.Cause(err) {
+	case nil:
+	case version.ErrNotTwoPhaseDeploy, version.ErrNotCurrentVersion, version.ErrNotDeployed, version.ErrAlreadyActivated:
+		BadRequestJSON(w, r, err.Error(), err)
+		return
+	default:
+		deployFailedJSON(w, r, "failed to activate version", err)
+		return
+	}
+
+	logger.Info("version activation requested",
+		zap.String("audit", "version.activate"),
+		zap.String("app", a.Slug),
+		zap.Int64("sequence", sequence),
+		zap.String("user", deployer.DeployedBy))
+
+	JSON(w, http.StatusNoContent, "")
+}
+
+// UpdateActivateResult is called by the operator with the result of an activation
+func (h *Handler) UpdateActivateResult(w http.ResponseWriter, r *http.Request) {
+	auth, err := parseClusterAuthorization(r.Header.Get("Authorization"))
+	if err != nil {
+		ErrorJSON(w, r, http.StatusForbidden, handlertypes.ErrorCodeForbidden, "failed to parse cluster authorization", err)
+		return
+	}
+
+	clusterID, err := store.GetStore().GetClusterIDFromDeployToken(auth.Password)
+	if err != nil {
+		ErrorJSON(w, r, http.StatusForbidden, handlertypes.ErrorCodeForbidden, "invalid deploy token", err)
+		return
+	}
+
+	updateActivateResultRequest := UpdateDeployResultRequest{}
+	if err := json.NewDecoder(r.Body).Decode(&updateActivateResultRequest); err != nil {
+		InternalErrorJSON(w, r, "failed to decode request body", err)
+		return
+	}
+
+	sequence, err := store.GetStore().GetActivatingSequence(updateActivateResultRequest.AppID, clusterID)
+	if err != nil {
+		InternalErrorJSON(w, r, "failed to get activating sequence", err)
+		return
+	}
+	if sequence == -1 {
+		// the result was already reported
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	logger.InfoFields("received activate result",
+		logger.RequestID(updateActivateResultRequest.RequestID),
+		zap.String("app_id", updateActivateResultRequest.AppID),
+		zap.String("cluster_id", clusterID),
+		zap.Int64("sequence", sequence),
+		zap.Bool("is_error", updateActivateResultRequest.IsError))
+
+	statusInfo := ""
+	if updateActivateResultRequest.IsError {
+		// the operator sends the output base64 encoded
+		decoded, err := base64.StdEncoding.DecodeString(updateActivateResultRequest.ApplyStderr)
+		if err != nil {
+			decoded = []byte(updateActivateResultRequest.ApplyStderr)
+		}
+		statusInfo = string(decoded)
+	}
+
+	err = store.GetStore().SetDownstreamActivationResult(updateActivateResultRequest.AppID, clusterID, sequence, updateActivateResultRequest.IsError, statusInfo)
+	if err != nil {
+		InternalErrorJSON(w, r, "failed to set activation result", err)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
@@ -0,0 +1,68 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+	admissiontypes "github.com/replicatedhq/kots/pkg/admission/types"
+	"github.com/replicatedhq/kots/pkg/store"
+)
+
+type SetAdmissionDryRunRequest struct {
+	Enabled bool `json:"enabled"`
+}
+
+type GetAdmissionValidationResponse struct {
+	// Validation is nil if the sequence was deployed without an admission dry run
+	Validation *admissiontypes.Validation `json:"validation"`
+}
+
+// SetAdmissionDryRun enables or disables the server side dry run of manifests before they are deployed
+func (h *Handler) SetAdmissionDryRun(w http.ResponseWriter, r *http.Request) {
+	request := SetAdmissionDryRunRequest{}
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		BadRequestJSON(w, r, "failed to decode request body", err)
+		return
+	}
+
+	foundApp, err := store.GetStore().GetAppFromSlug(mux.Vars(r)["appSlug"])
+	if err != nil {
+		NotFoundJSON(w, r, "failed to get app from slug", err)
+		return
+	}
+
+	if err := store.GetStore().SetAdmissionDryRun(foundApp.ID, request.Enabled); err != nil {
+		InternalErrorJSON(w, r, "failed to set admission dry run", err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *Handler) GetAdmissionValidation(w http.ResponseWriter, r *http.Request) {
+	appSlug := mux.Vars(r)["appSlug"]
+	clusterID := mux.Vars(r)["clusterId"]
+	sequence, err := strconv.ParseInt(mux.Vars(r)["sequence"], 10, 64)
+	if err != nil {
+		BadRequestJSON(w, r, "failed to parse sequence", err)
+		return
+	}
+
+	a, err := store.GetStore().GetAppFromSlug(appSlug)
+	if err != nil {
+		InternalErrorJSON(w, r, "failed to get app from slug", err)
+		return
+	}
+
+	validation, err := store.GetStore().GetDownstreamAdmissionValidation(a.ID, clusterID, sequence)
+	if err != nil {
+		InternalErrorJSON(w, r, "failed to get admission validation", err)
+		return
+	}
+
+	JSON(w, http.StatusOK, GetAdmissionValidationResponse{
+		Validation: validation,
+	})
+}
@@ -15,9 +15,11 @@ import (
 	"github.com/pkg/errors"
 	"github.com/replicatedhq/kots/pkg/airgap"
 	"github.com/replicatedhq/kots/pkg/automation"
+	"github.com/replicatedhq/kots/pkg/image"
 	"github.com/replicatedhq/kots/pkg/kotsutil"
 	"github.com/replicatedhq/kots/pkg/logger"
 	"github.com/replicatedhq/kots/pkg/store"
+	"github.com/replicatedhq/kots/pkg/uploadquota"
 	"github.com/replicatedhq/kots/pkg/util"
 )
 
@@ -49,6 +51,11 @@ type GetAirgapUploadConfigResponse struct {
 	SimultaneousUploads int `json:"simultaneousUploads"`
 }
 
+type SetImagePushBandwidthLimitRequest struct {
+	// BandwidthLimit is a rate such as "10MB", an empty string removes the limit
+	BandwidthLimit string `json:"bandwidthLimit"`
+}
+
 var uploadedAirgapBundleChunks = map[string]struct{}{}
 var chunkLock sync.Mutex
 var fileLock sync.Mutex
@@ -102,6 +109,34 @@ func (h *Handler) GetAirgapUploadConfig(w http.ResponseWriter, r *http.Request)
 	JSON(w, http.StatusOK, response)
 }
 
+// SetImagePushBandwidthLimit sets the rate at which images from airgap updates of the app are pushed to the registry
+func (h *Handler) SetImagePushBandwidthLimit(w http.ResponseWriter, r *http.Request) {
+	request := SetImagePushBandwidthLimitRequest{}
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		BadRequestJSON(w, r, "failed to decode request body", err)
+		return
+	}
+
+	bytesPerSecond, err := image.ParseBandwidthLimit(request.BandwidthLimit)
+	if err != nil {
+		BadRequestJSON(w, r, "invalid bandwidth limit", err)
+		return
+	}
+
+	foundApp, err := store.GetStore().GetAppFromSlug(mux.Vars(r)["appSlug"])
+	if err != nil {
+		NotFoundJSON(w, r, "failed to get app from slug", err)
+		return
+	}
+
+	if err := store.GetStore().SetImagePushBandwidthLimit(foundApp.ID, bytesPerSecond); err != nil {
+		InternalErrorJSON(w, r, "failed to set image push bandwidth limit", err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
 func (h *Handler) CheckAirgapBundleChunk(w http.ResponseWriter, r *http.Request) {
 	resumableIdentifier := r.FormValue("resumableIdentifier")
 	resumableChunkNumber := r.FormValue("resumableChunkNumber")
@@ -141,6 +176,15 @@ func (h *Handler) CheckAirgapBundleChunk(w http.ResponseWriter, r *http.Request)
 }
 
 func (h *Handler) UploadAirgapBundleChunk(w http.ResponseWriter, r *http.Request) {
+	quota, err := uploadquota.Get()
+	if err != nil {
+		InternalErrorJSON(w, r, "failed to get upload quota", err)
+		return
+	}
+	if !limitUploadBody(w, r, "airgap bundle", quota.AirgapBundleBytes) {
+		return
+	}
+
 	resumableIdentifier := r.FormValue("resumableIdentifier")
 	resumableTotalChunks := r.FormValue("resumableTotalChunks")
 	resumableTotalSize := r.FormValue("resumableTotalSize")
@@ -161,6 +205,11 @@ func (h *Handler) UploadAirgapBundleChunk(w http.ResponseWriter, r *http.Request
 		return
 	}
 
+	if err := uploadquota.CheckSize("airgap bundle", totalSize, quota.AirgapBundleBytes); err != nil {
+		uploadErrorJSON(w, r, err)
+		return
+	}
+
 	chunkNumber, err := strconv.ParseInt(resumableChunkNumber, 10, 64)
 	if err != nil {
 		logger.Error(errors.Wrap(err, "failed to parse chunk number as integer"))
@@ -178,6 +227,10 @@ func (h *Handler) UploadAirgapBundleChunk(w http.ResponseWriter, r *http.Request
 	// read chunk data
 	airgapBundleChunk, _, err := r.FormFile("file")
 	if err != nil {
+		if isBodyTooLarge(err) {
+			uploadErrorJSON(w, r, uploadquota.TooLargeError{Name: "airgap bundle", Limit: quota.AirgapBundleBytes})
+			return
+		}
 		logger.Error(err)
 		w.WriteHeader(http.StatusInternalServerError)
 		return
@@ -186,36 +239,45 @@ func (h *Handler) UploadAirgapBundleChunk(w http.ResponseWriter, r *http.Request
 
 	airgapBundlePath := getAirgapBundlePath(resumableIdentifier)
 
-	func() {
+	err = func() error {
 		// create airgap bundle file if not exists
 		fileLock.Lock()
 		defer fileLock.Unlock()
 
-		_, err = os.Stat(airgapBundlePath)
+		_, err := os.Stat(airgapBundlePath)
 		if os.IsNotExist(err) {
 
 			// this is a new upload.  assume only one upload can happen at a time and free up some ephemeral storage.
 			cleanupTempAirgapBundles()
 
+			// the file is sparse until the chunks are written, so check that the whole bundle fits before accepting it
+			if err := uploadquota.CheckDiskSpace("airgap bundle", filepath.Dir(airgapBundlePath), totalSize, quota.DiskReserveBytes); err != nil {
+				return err
+			}
+
 			f, err := os.Create(airgapBundlePath)
 			if err != nil {
-				logger.Error(err)
-				w.WriteHeader(http.StatusInternalServerError)
-				return
+				return err
 			}
 			defer f.Close()
 
 			if err := f.Truncate(totalSize); err != nil {
-				logger.Error(err)
-				w.WriteHeader(http.StatusInternalServerError)
-				return
+				return err
 			}
 		} else if err != nil {
-			logger.Error(err)
-			w.WriteHeader(http.StatusInternalServerError)
-			return
+			return err
 		}
+		return nil
 	}()
+	if err != nil {
+		if _, ok := errors.Cause(err).(uploadquota.InsufficientDiskSpaceError); ok {
+			uploadErrorJSON(w, r, err)
+			return
+		}
+		logger.Error(err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
 
 	airgapBundle, err := os.OpenFile(airgapBundlePath, os.O_RDWR, 0644)
 	if err != nil {
@@ -520,6 +582,10 @@ func (h *Handler) UploadInitialAirgapApp(w http.ResponseWriter, r *http.Request)
 		logger.Error(errors.Wrap(err, "failed to validate token"))
 		return
 	}
+	if err := requireNotReadOnlyMode(w, r); err != nil {
+		logger.Error(err)
+		return
+	}
 
 	appSlug := r.FormValue("appSlug")
 	archiveFile, archiveHeader, err := r.FormFile("appArchive")
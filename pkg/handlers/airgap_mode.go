@@ -0,0 +1,110 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/replicatedhq/kots/pkg/applock"
+	license "github.com/replicatedhq/kots/pkg/kotsadmlicense"
+	"github.com/replicatedhq/kots/pkg/store"
+	"github.com/replicatedhq/kots/pkg/updatechecker"
+)
+
+type SetAppAirgapModeRequest struct {
+	IsAirgap bool `json:"isAirgap"`
+	// License is an optional license to sync before converting an airgap app to online mode, for licenses that were
+	// changed in the vendor portal since the last airgap bundle
+	License string `json:"license"`
+}
+
+type SetAppAirgapModeResponse struct {
+	IsAirgap bool `json:"isAirgap"`
+	// LicenseSynced is true if converting to online mode synced a newer license
+	LicenseSynced bool `json:"licenseSynced"`
+}
+
+// SetAppAirgapMode converts an airgap app to online mode or an online app to airgap mode. Converting to online mode
+// syncs the license from the vendor portal first, so an app without connectivity stays in airgap mode. The update
+// checker is configured for the new mode.
+func (h *Handler) SetAppAirgapMode(w http.ResponseWriter, r *http.Request) {
+	request := SetAppAirgapModeRequest{}
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		BadRequestJSON(w, r, "failed to decode request body", err)
+		return
+	}
+
+	foundApp, err := store.GetStore().GetAppFromSlug(mux.Vars(r)["appSlug"])
+	if err != nil {
+		NotFoundJSON(w, r, "failed to get app from slug", err)
+		return
+	}
+
+	response := SetAppAirgapModeResponse{
+		IsAirgap: request.IsAirgap,
+	}
+
+	if foundApp.IsAirgap == request.IsAirgap {
+		JSON(w, http.StatusOK, response)
+		return
+	}
+
+	currentLicense, err := store.GetStore().GetLatestLicenseForApp(foundApp.ID)
+	if err != nil {
+		InternalErrorJSON(w, r, "failed to get current license", err)
+		return
+	}
+
+	if request.IsAirgap {
+		if !currentLicense.Spec.IsAirgapSupported {
+			BadRequestJSON(w, r, "license does not support airgap installs", nil)
+			return
+		}
+	} else {
+		if request.License != "" {
+			uploadedLicense, err := license.GetParsedLicense(request.License)
+			if err != nil {
+				BadRequestJSON(w, r, "failed to parse license", err)
+				return
+			}
+			if uploadedLicense.Spec.AppSlug != currentLicense.Spec.AppSlug {
+				BadRequestJSON(w, r, "license is for a different app", nil)
+				return
+			}
+			_, synced, err := license.Sync(foundApp, request.License, false)
+			if err != nil {
+				if applock.IsLocked(err) {
+					appLockedJSON(w, r, err)
+					return
+				}
+				BadRequestJSON(w, r, "failed to sync uploaded license", err)
+				return
+			}
+			response.LicenseSynced = synced
+		}
+
+		// syncing from the vendor portal verifies the connectivity that online mode needs
+		_, synced, err := license.Sync(foundApp, "", false)
+		if err != nil {
+			if applock.IsLocked(err) {
+				appLockedJSON(w, r, err)
+				return
+			}
+			BadRequestJSON(w, r, "failed to sync license from the vendor portal, the app needs connectivity to the vendor portal for online mode", err)
+			return
+		}
+		response.LicenseSynced = response.LicenseSynced || synced
+	}
+
+	if err := store.GetStore().SetAppIsAirgap(foundApp.ID, request.IsAirgap); err != nil {
+		InternalErrorJSON(w, r, "failed to set app airgap mode", err)
+		return
+	}
+
+	if err := updatechecker.Configure(foundApp.ID); err != nil {
+		InternalErrorJSON(w, r, "failed to configure update checker", err)
+		return
+	}
+
+	JSON(w, http.StatusOK, response)
+}
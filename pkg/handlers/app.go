@@ -4,19 +4,30 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/gorilla/mux"
 	"github.com/pkg/errors"
+	appstatustypes "github.com/replicatedhq/kots/pkg/api/appstatus/types"
 	downstreamtypes "github.com/replicatedhq/kots/pkg/api/downstream/types"
 	"github.com/replicatedhq/kots/pkg/api/handlers/types"
 	apptypes "github.com/replicatedhq/kots/pkg/app/types"
+	"github.com/replicatedhq/kots/pkg/assetcache"
 	"github.com/replicatedhq/kots/pkg/gitops"
 	"github.com/replicatedhq/kots/pkg/logger"
+	"github.com/replicatedhq/kots/pkg/maintenance"
 	"github.com/replicatedhq/kots/pkg/rbac"
 	"github.com/replicatedhq/kots/pkg/render"
 	"github.com/replicatedhq/kots/pkg/session"
+	sessiontypes "github.com/replicatedhq/kots/pkg/session/types"
+	"github.com/replicatedhq/kots/pkg/socketservice"
 	"github.com/replicatedhq/kots/pkg/store"
+	"github.com/replicatedhq/kots/pkg/updatechecker"
 	"github.com/replicatedhq/kots/pkg/version"
+	"k8s.io/apimachinery/pkg/labels"
 )
 
 func (h *Handler) GetPendingApp(w http.ResponseWriter, r *http.Request) {
@@ -64,6 +75,10 @@ func (h *Handler) GetPendingApp(w http.ResponseWriter, r *http.Request) {
 	JSON(w, http.StatusOK, pendingAppResponse)
 }
 
+// ListApps returns the apps the session can read. The offset and limit query params return a page of the apps, so that
+// the details of every app are not loaded when there are many, and the "sort" query param orders them by "name",
+// "createdAt" or "updatedAt", descending when prefixed with "-". Apps are ordered by name by default.
+// The "labelSelector" query param lists only the apps whose labels match it, e.g. "environment=production".
 func (h *Handler) ListApps(w http.ResponseWriter, r *http.Request) {
 	sess := session.ContextGetSession(r)
 	if sess == nil {
@@ -72,28 +87,40 @@ func (h *Handler) ListApps(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	apps, err := store.GetStore().ListInstalledApps()
+	offset, limit, err := getPagination(r)
+	if err != nil {
+		BadRequestJSON(w, r, "invalid pagination", err)
+		return
+	}
+
+	sortBy := r.URL.Query().Get("sort")
+	if err := validateAppsSort(sortBy); err != nil {
+		BadRequestJSON(w, r, "invalid sort", err)
+		return
+	}
+
+	selector, err := labels.Parse(r.URL.Query().Get("labelSelector"))
+	if err != nil {
+		BadRequestJSON(w, r, "invalid label selector", err)
+		return
+	}
+
+	// archived apps are only listed when they are asked for
+	listArchived, _ := strconv.ParseBool(r.URL.Query().Get("archived"))
+
+	apps, err := listReadableApps(r, sess, listArchived, selector)
 	if err != nil {
 		logger.Error(err)
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
 
-	defaultRoles := rbac.DefaultRoles() // TODO (ethan): this should be set in the handler
+	sortApps(apps, sortBy)
+	totalCount := len(apps)
+	apps = paginateApps(apps, offset, limit)
 
 	responseApps := []types.ResponseApp{}
 	for _, a := range apps {
-		if sess.HasRBAC { // handle pre-rbac sessions
-			allow, err := rbac.CheckAccess(r.Context(), defaultRoles, "read", fmt.Sprintf("app.%s", a.Slug), sess.Roles)
-			if err != nil {
-				logger.Error(errors.Wrapf(err, "failed to check access for app %s", a.Slug))
-				w.WriteHeader(http.StatusInternalServerError)
-				return
-			} else if !allow {
-				continue
-			}
-		}
-
 		responseApp, err := responseAppFromApp(a)
 		if err != nil {
 			logger.Error(err)
@@ -104,12 +131,155 @@ func (h *Handler) ListApps(w http.ResponseWriter, r *http.Request) {
 	}
 
 	listAppsResponse := types.ListAppsResponse{
-		Apps: responseApps,
+		Apps:       responseApps,
+		TotalCount: totalCount,
 	}
 
 	JSON(w, http.StatusOK, listAppsResponse)
 }
 
+// GetAppsSummary returns the number of apps the session can read in each state and the number of pending updates,
+// without loading the details of every app. The "labelSelector" query param summarizes only the matching apps.
+func (h *Handler) GetAppsSummary(w http.ResponseWriter, r *http.Request) {
+	sess := session.ContextGetSession(r)
+	if sess == nil {
+		logger.Error(errors.New("invalid session"))
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	selector, err := labels.Parse(r.URL.Query().Get("labelSelector"))
+	if err != nil {
+		BadRequestJSON(w, r, "invalid label selector", err)
+		return
+	}
+
+	apps, err := listReadableApps(r, sess, false, selector)
+	if err != nil {
+		logger.Error(err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	pendingVersionCounts, err := store.GetReadStore().ListPendingVersionCounts()
+	if err != nil {
+		logger.Error(errors.Wrap(err, "failed to list pending version counts"))
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	response := types.GetAppsSummaryResponse{
+		TotalCount:   len(apps),
+		StatusCounts: map[appstatustypes.State]int{},
+	}
+	for _, a := range apps {
+		appStatus, err := store.GetStore().GetAppStatus(a.ID)
+		if err != nil {
+			logger.Error(errors.Wrapf(err, "failed to get status of app %s", a.Slug))
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		response.StatusCounts[appStatus.State]++
+
+		if count := pendingVersionCounts[a.ID]; count > 0 {
+			response.PendingUpdatesCount += count
+			response.AppsWithPendingUpdatesCount++
+		}
+	}
+
+	JSON(w, http.StatusOK, response)
+}
+
+// listReadableApps returns the installed apps, archived or not, that match the label selector and that the session can read
+func listReadableApps(r *http.Request, sess *sessiontypes.Session, archived bool, selector labels.Selector) ([]*apptypes.App, error) {
+	apps, err := store.GetStore().ListInstalledApps()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list installed apps")
+	}
+
+	defaultRoles := rbac.DefaultRoles() // TODO (ethan): this should be set in the handler
+
+	readableApps := []*apptypes.App{}
+	for _, a := range apps {
+		if a.IsArchived != archived {
+			continue
+		}
+		if !selector.Matches(labels.Set(a.Labels)) {
+			continue
+		}
+
+		if sess.HasRBAC { // handle pre-rbac sessions
+			allow, err := rbac.CheckAccess(r.Context(), defaultRoles, "read", fmt.Sprintf("app.%s", a.Slug), sess.Roles)
+			if err != nil {
+				return nil, errors.Wrapf(err, "failed to check access for app %s", a.Slug)
+			} else if !allow {
+				continue
+			}
+		}
+
+		readableApps = append(readableApps, a)
+	}
+
+	return readableApps, nil
+}
+
+func validateAppsSort(sortBy string) error {
+	switch strings.TrimPrefix(sortBy, "-") {
+	case "", "name", "createdAt", "updatedAt":
+		return nil
+	}
+	return errors.Errorf("unsupported sort %q", sortBy)
+}
+
+// sortApps orders the apps by name, createdAt or updatedAt, descending when the field is prefixed with "-".
+// Ties are ordered by slug so that pages are stable.
+func sortApps(apps []*apptypes.App, sortBy string) {
+	desc := strings.HasPrefix(sortBy, "-")
+	field := strings.TrimPrefix(sortBy, "-")
+
+	sort.SliceStable(apps, func(i, j int) bool {
+		a, b := apps[i], apps[j]
+		if desc {
+			a, b = b, a
+		}
+
+		switch field {
+		case "createdAt":
+			if !a.CreatedAt.Equal(b.CreatedAt) {
+				return a.CreatedAt.Before(b.CreatedAt)
+			}
+		case "updatedAt":
+			aUpdatedAt, bUpdatedAt := time.Time{}, time.Time{}
+			if a.UpdatedAt != nil {
+				aUpdatedAt = *a.UpdatedAt
+			}
+			if b.UpdatedAt != nil {
+				bUpdatedAt = *b.UpdatedAt
+			}
+			if !aUpdatedAt.Equal(bUpdatedAt) {
+				return aUpdatedAt.Before(bUpdatedAt)
+			}
+		default:
+			if aName, bName := strings.ToLower(a.Name), strings.ToLower(b.Name); aName != bName {
+				return aName < bName
+			}
+		}
+		return a.Slug < b.Slug
+	})
+}
+
+// paginateApps returns the apps after offset, at most limit of them unless limit is 0
+func paginateApps(apps []*apptypes.App, offset int, limit int) []*apptypes.App {
+	if offset >= len(apps) {
+		return []*apptypes.App{}
+	}
+	apps = apps[offset:]
+	if limit > 0 && limit < len(apps) {
+		apps = apps[:limit]
+	}
+	return apps
+}
+
 func (h *Handler) GetAppStatus(w http.ResponseWriter, r *http.Request) {
 	appSlug := mux.Vars(r)["appSlug"]
 	a, err := store.GetStore().GetAppFromSlug(appSlug)
@@ -126,8 +296,16 @@ func (h *Handler) GetAppStatus(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	maintenanceMessage, err := maintenance.GetMaintenanceMessage(a.ID)
+	if err != nil {
+		logger.Error(err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
 	appStatusResponse := types.AppStatusResponse{
-		AppStatus: appStatus,
+		AppStatus:          appStatus,
+		MaintenanceMessage: maintenanceMessage,
 	}
 	JSON(w, http.StatusOK, appStatusResponse)
 }
@@ -190,17 +368,17 @@ func responseAppFromApp(a *apptypes.App) (*types.ResponseApp, error) {
 			return nil, errors.Wrap(err, "failed to get realized links from app spec")
 		}
 
-		currentVersion, err := store.GetStore().GetCurrentVersion(a.ID, d.ClusterID)
+		currentVersion, err := store.GetReadStore().GetCurrentVersion(a.ID, d.ClusterID)
 		if err != nil {
 			return nil, errors.Wrap(err, "failed to get current downstream version")
 		}
 
-		pendingVersions, err := store.GetStore().GetPendingVersions(a.ID, d.ClusterID)
+		pendingVersions, err := store.GetReadStore().GetPendingVersions(a.ID, d.ClusterID)
 		if err != nil {
 			return nil, errors.Wrap(err, "failed to get pending versions")
 		}
 
-		pastVersions, err := store.GetStore().GetPastVersions(a.ID, d.ClusterID)
+		pastVersions, err := store.GetReadStore().GetPastVersions(a.ID, d.ClusterID)
 		if err != nil {
 			return nil, errors.Wrap(err, "failed to get past versions")
 		}
@@ -274,6 +452,11 @@ func responseAppFromApp(a *apptypes.App) (*types.ResponseApp, error) {
 		HasPreflight:                  a.HasPreflight,
 		IsConfigurable:                a.IsConfigurable,
 		UpdateCheckerSpec:             a.UpdateCheckerSpec,
+		DeployPolicy:                  string(a.DeployPolicy),
+		AdmissionDryRun:               a.AdmissionDryRun,
+		ImagePushBandwidthLimit:       a.ImagePushBandwidthLimit,
+		IsArchived:                    a.IsArchived,
+		RequireDeployApproval:         a.RequireDeployApproval,
 		IsGitOpsSupported:             license.Spec.IsGitOpsSupported,
 		IsIdentityServiceSupported:    license.Spec.IsIdentityServiceSupported,
 		IsAppIdentityServiceSupported: isAppIdentityServiceSupported,
@@ -283,18 +466,37 @@ func responseAppFromApp(a *apptypes.App) (*types.ResponseApp, error) {
 		LicenseType:                   license.Spec.LicenseType,
 		CurrentVersion:                currentVersion,
 		Downstreams:                   responseDownstreams,
+		Labels:                        a.Labels,
 	}
 
+	// a missing cached icon falls back to the icon uri in the console, so it does not fail the request
+	cachedIconPath, err := assetcache.CachedPath(a.IconURI)
+	if err != nil {
+		logger.Error(errors.Wrap(err, "failed to get cached icon path"))
+	}
+	responseApp.CachedIconPath = cachedIconPath
+
 	return &responseApp, nil
 }
 
 type GetAppVersionsResponse struct {
 	VersionHistory []downstreamtypes.DownstreamVersion `json:"versionHistory"`
+	// TotalCount is the number of versions that match the filters, of which VersionHistory is a page
+	TotalCount int64 `json:"totalCount"`
 }
 
+// GetAppVersionHistory returns the versions of the app, newest first. The offset and limit query params return a page
+// of the versions, and they can be filtered with the comma separated "status" and "source" query params, and the
+// "createdAfter" and "createdBefore" RFC 3339 timestamps.
 func (h *Handler) GetAppVersionHistory(w http.ResponseWriter, r *http.Request) {
 	appSlug := mux.Vars(r)["appSlug"]
 
+	opts, err := getVersionHistoryOptions(r)
+	if err != nil {
+		BadRequestJSON(w, r, "invalid version history options", err)
+		return
+	}
+
 	foundApp, err := store.GetStore().GetAppFromSlug(appSlug)
 	if err != nil {
 		err = errors.Wrap(err, "failed to get app from slug")
@@ -318,50 +520,78 @@ func (h *Handler) GetAppVersionHistory(w http.ResponseWriter, r *http.Request) {
 
 	clusterID := downstreams[0].ClusterID
 
-	currentVersion, err := store.GetStore().GetCurrentVersion(foundApp.ID, clusterID)
+	versions, total, err := store.GetReadStore().ListDownstreamVersions(foundApp.ID, clusterID, *opts)
 	if err != nil {
-		err = errors.Wrap(err, "failed to get current downstream version")
+		err = errors.Wrap(err, "failed to list versions")
 		logger.Error(err)
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
 
-	pendingVersions, err := store.GetStore().GetPendingVersions(foundApp.ID, clusterID)
-	if err != nil {
-		err = errors.Wrap(err, "failed to get pending versions")
-		logger.Error(err)
-		w.WriteHeader(http.StatusInternalServerError)
-		return
+	response := GetAppVersionsResponse{
+		VersionHistory: versions,
+		TotalCount:     total,
 	}
 
-	pastVersions, err := store.GetStore().GetPastVersions(foundApp.ID, clusterID)
+	JSON(w, http.StatusOK, response)
+}
+
+func getVersionHistoryOptions(r *http.Request) (*downstreamtypes.VersionHistoryOptions, error) {
+	offset, limit, err := getPagination(r)
 	if err != nil {
-		err = errors.Wrap(err, "failed to get past versions")
-		logger.Error(err)
-		w.WriteHeader(http.StatusInternalServerError)
-		return
+		return nil, err
 	}
 
-	response := GetAppVersionsResponse{
-		VersionHistory: []downstreamtypes.DownstreamVersion{},
+	opts := &downstreamtypes.VersionHistoryOptions{
+		Offset:   offset,
+		Limit:    limit,
+		Statuses: splitQueryList(r.URL.Query().Get("status")),
+		Sources:  splitQueryList(r.URL.Query().Get("source")),
 	}
-	response.VersionHistory = append(response.VersionHistory, pendingVersions...)
-	if currentVersion != nil {
-		response.VersionHistory = append(response.VersionHistory, *currentVersion)
+
+	if s := r.URL.Query().Get("createdAfter"); s != "" {
+		t, err := time.Parse(time.RFC3339, s)
+		if err != nil {
+			return nil, errors.Wrap(err, "invalid createdAfter")
+		}
+		opts.CreatedAfter = &t
+	}
+	if s := r.URL.Query().Get("createdBefore"); s != "" {
+		t, err := time.Parse(time.RFC3339, s)
+		if err != nil {
+			return nil, errors.Wrap(err, "invalid createdBefore")
+		}
+		opts.CreatedBefore = &t
 	}
-	response.VersionHistory = append(response.VersionHistory, pastVersions...)
 
-	JSON(w, http.StatusOK, response)
+	return opts, nil
+}
+
+// splitQueryList splits a comma separated query param, dropping empty values
+func splitQueryList(s string) []string {
+	values := []string{}
+	for _, value := range strings.Split(s, ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
 }
 
 type RemoveAppRequest struct {
 	Force bool `json:"force"`
+	// Confirm must be the slug of the app to remove a protected app
+	Confirm string `json:"confirm"`
 }
 
 type RemoveAppResponse struct {
 	Error string `json:"error,omitempty"`
+	// PurgeAt is when the app is removed permanently, it can be restored until then
+	PurgeAt *time.Time `json:"purgeAt,omitempty"`
 }
 
+// RemoveApp moves the app to the trash, it is removed permanently with its namespaces once the trash retention has
+// passed. Protected apps can only be removed by a cluster admin who confirms the app slug.
 func (h *Handler) RemoveApp(w http.ResponseWriter, r *http.Request) {
 	appSlug := mux.Vars(r)["appSlug"]
 
@@ -389,6 +619,25 @@ func (h *Handler) RemoveApp(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if app.RemovedAt != nil {
+		response.Error = fmt.Sprintf("application %s is already removed", appSlug)
+		JSON(w, http.StatusBadRequest, response)
+		return
+	}
+
+	if app.IsProtected {
+		if !isClusterAdmin(r) {
+			response.Error = fmt.Sprintf("application %s is protected and can only be removed by a cluster admin", appSlug)
+			JSON(w, http.StatusForbidden, response)
+			return
+		}
+		if removeAppRequest.Confirm != app.Slug {
+			response.Error = fmt.Sprintf("application %s is protected, confirm the removal with the app slug", appSlug)
+			JSON(w, http.StatusBadRequest, response)
+			return
+		}
+	}
+
 	if !removeAppRequest.Force {
 		downstreams, err := store.GetStore().ListDownstreamsForApp(app.ID)
 		if err != nil {
@@ -416,7 +665,7 @@ func (h *Handler) RemoveApp(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	err = store.GetStore().RemoveApp(app.ID)
+	err = store.GetStore().TrashApp(app.ID)
 	if err != nil {
 		response.Error = "failed to remove app"
 		logger.Error(errors.Wrap(err, response.Error))
@@ -424,5 +673,18 @@ func (h *Handler) RemoveApp(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	updatechecker.Stop(app.ID)
+
+	// the app is already removed, the operator stops its informers when it reconnects
+	if err := socketservice.StopAppInformers(app.ID); err != nil {
+		logger.Error(errors.Wrap(err, "failed to stop app informers"))
+	}
+
+	purgeAt, err := getPurgeAt(app.ID)
+	if err != nil {
+		logger.Error(errors.Wrap(err, "failed to get purge time"))
+	}
+	response.PurgeAt = purgeAt
+
 	JSON(w, http.StatusOK, response)
 }
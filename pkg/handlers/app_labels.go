@@ -0,0 +1,42 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	apptypes "github.com/replicatedhq/kots/pkg/app/types"
+	"github.com/replicatedhq/kots/pkg/store"
+)
+
+type SetAppLabelsRequest struct {
+	Labels map[string]string `json:"labels"`
+}
+
+// SetAppLabels replaces the labels of the app. Apps can be listed by label selector, for example
+// "environment=production" or "team in (payments,billing)".
+func (h *Handler) SetAppLabels(w http.ResponseWriter, r *http.Request) {
+	request := SetAppLabelsRequest{}
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		BadRequestJSON(w, r, "failed to decode request body", err)
+		return
+	}
+
+	if err := apptypes.ValidateLabels(request.Labels); err != nil {
+		BadRequestJSON(w, r, "invalid labels", err)
+		return
+	}
+
+	foundApp, err := store.GetStore().GetAppFromSlug(mux.Vars(r)["appSlug"])
+	if err != nil {
+		NotFoundJSON(w, r, "failed to get app from slug", err)
+		return
+	}
+
+	if err := store.GetStore().SetAppLabels(foundApp.ID, request.Labels); err != nil {
+		InternalErrorJSON(w, r, "failed to set app labels", err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
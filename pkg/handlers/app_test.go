@@ -0,0 +1,77 @@
+package handlers
+
+import (
+	"testing"
+	"time"
+
+	apptypes "github.com/replicatedhq/kots/pkg/app/types"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_sortApps(t *testing.T) {
+	now := time.Now()
+	earlier := now.Add(-time.Hour)
+
+	apps := func() []*apptypes.App {
+		return []*apptypes.App{
+			{Slug: "b", Name: "Beta", CreatedAt: now},
+			{Slug: "a", Name: "alpha", CreatedAt: earlier, UpdatedAt: &now},
+			{Slug: "c", Name: "Gamma", CreatedAt: now, UpdatedAt: &earlier},
+		}
+	}
+	slugs := func(apps []*apptypes.App) []string {
+		result := []string{}
+		for _, a := range apps {
+			result = append(result, a.Slug)
+		}
+		return result
+	}
+
+	tests := []struct {
+		sortBy string
+		want   []string
+	}{
+		{
+			sortBy: "",
+			want:   []string{"a", "b", "c"},
+		},
+		{
+			sortBy: "-name",
+			want:   []string{"c", "b", "a"},
+		},
+		{
+			sortBy: "createdAt",
+			want:   []string{"a", "b", "c"},
+		},
+		{
+			sortBy: "-createdAt",
+			want:   []string{"c", "b", "a"},
+		},
+		{
+			sortBy: "updatedAt",
+			want:   []string{"b", "c", "a"},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.sortBy, func(t *testing.T) {
+			sorted := apps()
+			sortApps(sorted, test.sortBy)
+			require.Equal(t, test.want, slugs(sorted))
+		})
+	}
+}
+
+func Test_paginateApps(t *testing.T) {
+	apps := []*apptypes.App{{Slug: "a"}, {Slug: "b"}, {Slug: "c"}}
+
+	require.Len(t, paginateApps(apps, 0, 0), 3)
+	require.Equal(t, []*apptypes.App{{Slug: "b"}}, paginateApps(apps, 1, 1))
+	require.Equal(t, []*apptypes.App{{Slug: "b"}, {Slug: "c"}}, paginateApps(apps, 1, 5))
+	require.Empty(t, paginateApps(apps, 3, 1))
+}
+
+func Test_validateAppsSort(t *testing.T) {
+	require.NoError(t, validateAppsSort(""))
+	require.NoError(t, validateAppsSort("-updatedAt"))
+	require.Error(t, validateAppsSort("status"))
+}
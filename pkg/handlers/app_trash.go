@@ -0,0 +1,150 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/pkg/errors"
+	handlertypes "github.com/replicatedhq/kots/pkg/api/handlers/types"
+	"github.com/replicatedhq/kots/pkg/app"
+	"github.com/replicatedhq/kots/pkg/logger"
+	"github.com/replicatedhq/kots/pkg/rbac"
+	"github.com/replicatedhq/kots/pkg/session"
+	"github.com/replicatedhq/kots/pkg/socketservice"
+	"github.com/replicatedhq/kots/pkg/store"
+	"github.com/replicatedhq/kots/pkg/updatechecker"
+)
+
+type SetAppProtectedRequest struct {
+	Protected bool `json:"protected"`
+}
+
+type RemovedApp struct {
+	ID          string     `json:"id"`
+	Slug        string     `json:"slug"`
+	Name        string     `json:"name"`
+	IsProtected bool       `json:"isProtected"`
+	RemovedAt   *time.Time `json:"removedAt"`
+	PurgeAt     *time.Time `json:"purgeAt"`
+}
+
+type ListRemovedAppsResponse struct {
+	Apps []RemovedApp `json:"apps"`
+}
+
+// SetAppProtected protects the app from removal by anyone but a cluster admin, who must confirm the removal with the
+// app slug. Only a cluster admin can change the protection.
+func (h *Handler) SetAppProtected(w http.ResponseWriter, r *http.Request) {
+	request := SetAppProtectedRequest{}
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		BadRequestJSON(w, r, "failed to decode request body", err)
+		return
+	}
+
+	if !isClusterAdmin(r) {
+		ErrorJSON(w, r, http.StatusForbidden, handlertypes.ErrorCodeForbidden, "only a cluster admin can change the protection of an app", nil)
+		return
+	}
+
+	foundApp, err := store.GetStore().GetAppFromSlug(mux.Vars(r)["appSlug"])
+	if err != nil {
+		NotFoundJSON(w, r, "failed to get app from slug", err)
+		return
+	}
+
+	if err := store.GetStore().SetAppProtected(foundApp.ID, request.Protected); err != nil {
+		InternalErrorJSON(w, r, "failed to set app protected", err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// ListRemovedApps returns the apps in the trash and when they are removed permanently
+func (h *Handler) ListRemovedApps(w http.ResponseWriter, r *http.Request) {
+	removedApps, err := store.GetStore().ListRemovedApps()
+	if err != nil {
+		InternalErrorJSON(w, r, "failed to list removed apps", err)
+		return
+	}
+
+	response := ListRemovedAppsResponse{
+		Apps: []RemovedApp{},
+	}
+	for _, a := range removedApps {
+		response.Apps = append(response.Apps, RemovedApp{
+			ID:          a.ID,
+			Slug:        a.Slug,
+			Name:        a.Name,
+			IsProtected: a.IsProtected,
+			RemovedAt:   a.RemovedAt,
+			PurgeAt:     app.PurgeAt(a),
+		})
+	}
+
+	JSON(w, http.StatusOK, response)
+}
+
+// RestoreRemovedApp undoes the removal of an app that is still in the trash
+func (h *Handler) RestoreRemovedApp(w http.ResponseWriter, r *http.Request) {
+	foundApp, err := store.GetStore().GetAppFromSlug(mux.Vars(r)["appSlug"])
+	if err != nil {
+		NotFoundJSON(w, r, "failed to get app from slug", err)
+		return
+	}
+
+	if foundApp.RemovedAt == nil {
+		BadRequestJSON(w, r, "app is not removed", nil)
+		return
+	}
+
+	if err := store.GetStore().RestoreTrashedApp(foundApp.ID); err != nil {
+		InternalErrorJSON(w, r, "failed to restore removed app", err)
+		return
+	}
+
+	if foundApp.IsArchived {
+		// archived apps don't check for updates or run informers
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	if err := updatechecker.Configure(foundApp.ID); err != nil {
+		InternalErrorJSON(w, r, "failed to configure update checker", err)
+		return
+	}
+
+	socketservice.ResumeAppInformers(foundApp.ID)
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// getPurgeAt returns when the removed app is removed permanently
+func getPurgeAt(appID string) (*time.Time, error) {
+	a, err := store.GetStore().GetApp(appID)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get app")
+	}
+	return app.PurgeAt(a), nil
+}
+
+// isClusterAdmin returns true if the session of the request has the cluster admin role. Sessions from before rbac
+// have all permissions.
+func isClusterAdmin(r *http.Request) bool {
+	sess := session.ContextGetSession(r)
+	if sess == nil {
+		logger.Error(errors.New("invalid session"))
+		return false
+	}
+	if !sess.HasRBAC {
+		return true
+	}
+	for _, role := range sess.Roles {
+		if role == rbac.ClusterAdminRoleID {
+			return true
+		}
+	}
+	return false
+}
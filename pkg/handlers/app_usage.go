@@ -0,0 +1,27 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/replicatedhq/kots/pkg/appusage"
+	"github.com/replicatedhq/kots/pkg/store"
+)
+
+// GetAppResourceUsage returns the cpu and memory requests and usage of the pods of the app and the storage of their
+// volumes, to right-size the cluster before an upgrade
+func (h *Handler) GetAppResourceUsage(w http.ResponseWriter, r *http.Request) {
+	foundApp, err := store.GetStore().GetAppFromSlug(mux.Vars(r)["appSlug"])
+	if err != nil {
+		NotFoundJSON(w, r, "failed to get app from slug", err)
+		return
+	}
+
+	report, err := appusage.GetReport(foundApp)
+	if err != nil {
+		InternalErrorJSON(w, r, "failed to get app resource usage", err)
+		return
+	}
+
+	JSON(w, http.StatusOK, report)
+}
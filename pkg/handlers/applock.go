@@ -0,0 +1,34 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	handlertypes "github.com/replicatedhq/kots/pkg/api/handlers/types"
+	"github.com/replicatedhq/kots/pkg/applock"
+)
+
+// lockAppJSON takes the lock of the app before a version is created. If the lock can't be taken, an error is
+// written and false is returned.
+func lockAppJSON(w http.ResponseWriter, r *http.Request, appID string) (func(), bool) {
+	unlock, err := applock.TryLock(appID)
+	if err != nil {
+		if applock.IsLocked(err) {
+			appLockedJSON(w, r, err)
+		} else {
+			InternalErrorJSON(w, r, "failed to lock app", err)
+		}
+		return nil, false
+	}
+	return unlock, true
+}
+
+// appLockedJSON is ErrorJSON for requests that lost the race to create a version of the app
+func appLockedJSON(w http.ResponseWriter, r *http.Request, err error) {
+	setAppLockedRetryAfter(w)
+	ErrorJSON(w, r, http.StatusConflict, handlertypes.ErrorCodeConflict, err.Error(), nil)
+}
+
+func setAppLockedRetryAfter(w http.ResponseWriter) {
+	w.Header().Set("Retry-After", strconv.Itoa(int(applock.RetryAfter.Seconds())))
+}
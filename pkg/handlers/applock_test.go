@@ -0,0 +1,29 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	handlertypes "github.com/replicatedhq/kots/pkg/api/handlers/types"
+	"github.com/replicatedhq/kots/pkg/applock"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_appLockedJSON(t *testing.T) {
+	req := require.New(t)
+
+	r := httptest.NewRequest("PUT", "/api/v1/app/my-app/config", nil)
+	w := httptest.NewRecorder()
+	appLockedJSON(w, r, applock.LockedError{AppID: "app-1"})
+
+	req.Equal(http.StatusConflict, w.Code)
+	req.Equal("10", w.Header().Get("Retry-After"))
+
+	response := handlertypes.ErrorResponse{}
+	req.NoError(json.Unmarshal(w.Body.Bytes(), &response))
+	req.False(response.Success)
+	req.Equal(handlertypes.ErrorCodeConflict, response.Code)
+	req.Equal("another operation is creating a version of app app-1", response.Error)
+}
@@ -0,0 +1,48 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	apptypes "github.com/replicatedhq/kots/pkg/app/types"
+	"github.com/replicatedhq/kots/pkg/store"
+)
+
+// GetApplyPolicy returns the retries, timeouts and waits the operator uses when it applies the app
+func (h *Handler) GetApplyPolicy(w http.ResponseWriter, r *http.Request) {
+	foundApp, err := store.GetStore().GetAppFromSlug(mux.Vars(r)["appSlug"])
+	if err != nil {
+		NotFoundJSON(w, r, "failed to get app from slug", err)
+		return
+	}
+
+	JSON(w, http.StatusOK, foundApp.ApplyPolicy)
+}
+
+// SetApplyPolicy replaces the apply policy of the app, it is used by the next deploy
+func (h *Handler) SetApplyPolicy(w http.ResponseWriter, r *http.Request) {
+	request := apptypes.ApplyPolicy{}
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		BadRequestJSON(w, r, "failed to decode request body", err)
+		return
+	}
+
+	if err := request.Validate(); err != nil {
+		BadRequestJSON(w, r, "invalid apply policy", err)
+		return
+	}
+
+	foundApp, err := store.GetStore().GetAppFromSlug(mux.Vars(r)["appSlug"])
+	if err != nil {
+		NotFoundJSON(w, r, "failed to get app from slug", err)
+		return
+	}
+
+	if err := store.GetStore().SetApplyPolicy(foundApp.ID, request); err != nil {
+		InternalErrorJSON(w, r, "failed to set apply policy", err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
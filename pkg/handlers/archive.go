@@ -0,0 +1,60 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/pkg/errors"
+	"github.com/replicatedhq/kots/pkg/logger"
+	"github.com/replicatedhq/kots/pkg/socketservice"
+	"github.com/replicatedhq/kots/pkg/store"
+	"github.com/replicatedhq/kots/pkg/updatechecker"
+)
+
+// ArchiveApp stops the update checks, status informers and scheduled jobs of an app and hides it from the app list.
+// The workloads of the app are not changed.
+func (h *Handler) ArchiveApp(w http.ResponseWriter, r *http.Request) {
+	foundApp, err := store.GetStore().GetAppFromSlug(mux.Vars(r)["appSlug"])
+	if err != nil {
+		NotFoundJSON(w, r, "failed to get app from slug", err)
+		return
+	}
+
+	if err := store.GetStore().SetAppArchived(foundApp.ID, true); err != nil {
+		InternalErrorJSON(w, r, "failed to archive app", err)
+		return
+	}
+
+	updatechecker.Stop(foundApp.ID)
+
+	// the app is already archived, the operator stops its informers when it reconnects
+	if err := socketservice.StopAppInformers(foundApp.ID); err != nil {
+		logger.Error(errors.Wrap(err, "failed to stop app informers"))
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// UnarchiveApp restores an archived app to the app list and restarts its update checks, status informers
+// and scheduled jobs
+func (h *Handler) UnarchiveApp(w http.ResponseWriter, r *http.Request) {
+	foundApp, err := store.GetStore().GetAppFromSlug(mux.Vars(r)["appSlug"])
+	if err != nil {
+		NotFoundJSON(w, r, "failed to get app from slug", err)
+		return
+	}
+
+	if err := store.GetStore().SetAppArchived(foundApp.ID, false); err != nil {
+		InternalErrorJSON(w, r, "failed to unarchive app", err)
+		return
+	}
+
+	if err := updatechecker.Configure(foundApp.ID); err != nil {
+		InternalErrorJSON(w, r, "failed to configure update checker", err)
+		return
+	}
+
+	socketservice.ResumeAppInformers(foundApp.ID)
+
+	w.WriteHeader(http.StatusNoContent)
+}
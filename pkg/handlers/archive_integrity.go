@@ -0,0 +1,73 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+	"github.com/replicatedhq/kots/pkg/archiveintegrity"
+	archiveintegritytypes "github.com/replicatedhq/kots/pkg/archiveintegrity/types"
+	"github.com/replicatedhq/kots/pkg/store"
+)
+
+type ListCorruptAppVersionArchivesResponse struct {
+	Archives []archiveintegritytypes.Checksum `json:"archives"`
+}
+
+type RepairAppVersionArchiveResponse struct {
+	// Repaired is false if the archive matched its checksum and did not need to be repaired
+	Repaired bool `json:"repaired"`
+}
+
+// ListCorruptAppVersionArchives returns the app version archives that did not match their checksum, or were
+// missing, when they were last verified
+func (h *Handler) ListCorruptAppVersionArchives(w http.ResponseWriter, r *http.Request) {
+	a, err := store.GetStore().GetAppFromSlug(mux.Vars(r)["appSlug"])
+	if err != nil {
+		NotFoundJSON(w, r, "failed to get app from slug", err)
+		return
+	}
+
+	archives, err := store.GetStore().ListCorruptAppVersionArchives(a.ID)
+	if err != nil {
+		InternalErrorJSON(w, r, "failed to list corrupt archives", err)
+		return
+	}
+
+	JSON(w, http.StatusOK, ListCorruptAppVersionArchivesResponse{
+		Archives: archives,
+	})
+}
+
+// RepairAppVersionArchive verifies the archive of the app version, and renders it again from the upstream if it
+// is corrupt or missing
+func (h *Handler) RepairAppVersionArchive(w http.ResponseWriter, r *http.Request) {
+	sequence, err := strconv.ParseInt(mux.Vars(r)["sequence"], 10, 64)
+	if err != nil {
+		BadRequestJSON(w, r, "failed to parse sequence", err)
+		return
+	}
+
+	a, err := store.GetStore().GetAppFromSlug(mux.Vars(r)["appSlug"])
+	if err != nil {
+		NotFoundJSON(w, r, "failed to get app from slug", err)
+		return
+	}
+
+	err = archiveintegrity.Verify(a.ID, sequence)
+	if err == nil {
+		JSON(w, http.StatusOK, RepairAppVersionArchiveResponse{Repaired: false})
+		return
+	}
+	if !archiveintegrity.IsCorrupt(err) {
+		InternalErrorJSON(w, r, "failed to verify archive", err)
+		return
+	}
+
+	if err := archiveintegrity.Repair(a.ID, sequence); err != nil {
+		InternalErrorJSON(w, r, "failed to repair archive", err)
+		return
+	}
+
+	JSON(w, http.StatusOK, RepairAppVersionArchiveResponse{Repaired: true})
+}
@@ -0,0 +1,75 @@
+package handlers
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gorilla/mux"
+	"github.com/pkg/errors"
+	"github.com/replicatedhq/kots/pkg/assetcache"
+	"github.com/replicatedhq/kots/pkg/logger"
+)
+
+// GetCachedAsset route is UNAUTHENTICATED
+// It serves the cached application icons, which are shown before the user is logged in and are loaded by img tags
+// that cannot send the authorization header. Assets are only reachable by the hash of their uri.
+func (h *Handler) GetCachedAsset(w http.ResponseWriter, r *http.Request) {
+	asset, err := assetcache.Get(mux.Vars(r)["cacheKey"])
+	if err != nil {
+		logger.Error(errors.Wrap(err, "failed to get cached asset"))
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	if asset == nil {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	// svg icons can contain scripts
+	w.Header().Set("Content-Security-Policy", "default-src 'none'; style-src 'unsafe-inline'; sandbox")
+	writeWithETag(w, r, asset.ContentType, asset.ETag, asset.Data)
+}
+
+// writeWithETag writes the response, or a 304 if the client already has it. Clients revalidate the response every
+// time it is used, so that changes are picked up right away without downloading unchanged responses again.
+func writeWithETag(w http.ResponseWriter, r *http.Request, contentType string, etag string, data []byte) {
+	w.Header().Set("ETag", etag)
+	w.Header().Set("Cache-Control", "no-cache")
+
+	if etagMatches(r.Header.Get("If-None-Match"), etag) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	w.Header().Set("Content-Type", contentType)
+	w.WriteHeader(http.StatusOK)
+	if _, err := w.Write(data); err != nil {
+		logger.Error(errors.Wrap(err, "failed to write response"))
+	}
+}
+
+// JSONWithETag marshals the payload and writes it with writeWithETag
+func JSONWithETag(w http.ResponseWriter, r *http.Request, payload interface{}) {
+	response, err := json.Marshal(payload)
+	if err != nil {
+		logger.Error(errors.Wrap(err, "failed to marshal response"))
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	writeWithETag(w, r, "application/json", fmt.Sprintf(`"%x"`, sha256.Sum256(response)), response)
+}
+
+func etagMatches(ifNoneMatch string, etag string) bool {
+	for _, candidate := range strings.Split(ifNoneMatch, ",") {
+		candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+		if candidate == "*" || candidate == etag {
+			return true
+		}
+	}
+	return false
+}
@@ -146,6 +146,34 @@ func (h *Handler) GetBackup(w http.ResponseWriter, r *http.Request) {
 	JSON(w, http.StatusOK, getBackupResponse)
 }
 
+type GetBackupContentsResponse struct {
+	Contents *snapshottypes.BackupContents `json:"contents"`
+	Success  bool                          `json:"success"`
+	Error    string                        `json:"error,omitempty"`
+}
+
+// GetBackupContents lists the resources and volumes that a completed backup captured
+func (h *Handler) GetBackupContents(w http.ResponseWriter, r *http.Request) {
+	getBackupContentsResponse := GetBackupContentsResponse{}
+
+	contents, err := snapshot.GetBackupContents(r.Context(), os.Getenv("POD_NAMESPACE"), mux.Vars(r)["snapshotName"])
+	if errors.Cause(err) == snapshot.ErrBackupNotCompleted {
+		getBackupContentsResponse.Error = "backup is not completed"
+		JSON(w, http.StatusConflict, getBackupContentsResponse)
+		return
+	} else if err != nil {
+		logger.Error(err)
+		getBackupContentsResponse.Error = "failed to get backup contents"
+		JSON(w, http.StatusInternalServerError, getBackupContentsResponse)
+		return
+	}
+	getBackupContentsResponse.Contents = contents
+
+	getBackupContentsResponse.Success = true
+
+	JSON(w, http.StatusOK, getBackupContentsResponse)
+}
+
 type DeleteBackupResponse struct {
 	Success bool   `json:"success"`
 	Error   string `json:"error,omitempty"`
@@ -0,0 +1,72 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"os"
+
+	"github.com/replicatedhq/kots/pkg/cabundle"
+	"github.com/replicatedhq/kots/pkg/k8sutil"
+)
+
+type GetCABundleResponse struct {
+	CABundle string `json:"caBundle"`
+}
+
+type SetCABundleRequest struct {
+	// CABundle is the pem encoded bundle to trust, in addition to the system roots. An empty bundle removes it.
+	CABundle string `json:"caBundle"`
+}
+
+func (h *Handler) GetCABundle(w http.ResponseWriter, r *http.Request) {
+	clientset, err := k8sutil.GetClientset()
+	if err != nil {
+		InternalErrorJSON(w, r, "failed to get clientset", err)
+		return
+	}
+
+	bundle, err := cabundle.Get(clientset, os.Getenv("POD_NAMESPACE"))
+	if err != nil {
+		InternalErrorJSON(w, r, "failed to get ca bundle", err)
+		return
+	}
+
+	JSON(w, http.StatusOK, GetCABundleResponse{
+		CABundle: string(bundle),
+	})
+}
+
+func (h *Handler) SetCABundle(w http.ResponseWriter, r *http.Request) {
+	request := SetCABundleRequest{}
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		BadRequestJSON(w, r, "failed to decode request body", err)
+		return
+	}
+
+	bundle := []byte(request.CABundle)
+	if len(bundle) > 0 {
+		if err := cabundle.Validate(bundle); err != nil {
+			BadRequestJSON(w, r, "invalid ca bundle", err)
+			return
+		}
+	}
+
+	clientset, err := k8sutil.GetClientset()
+	if err != nil {
+		InternalErrorJSON(w, r, "failed to get clientset", err)
+		return
+	}
+
+	if err := cabundle.Set(clientset, os.Getenv("POD_NAMESPACE"), bundle); err != nil {
+		InternalErrorJSON(w, r, "failed to set ca bundle", err)
+		return
+	}
+
+	// the bundle is reloaded periodically, load it now so that it is used by the next request
+	if err := cabundle.Load(bundle); err != nil {
+		InternalErrorJSON(w, r, "failed to load ca bundle", err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
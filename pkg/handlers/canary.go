@@ -0,0 +1,84 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/pkg/errors"
+	apptypes "github.com/replicatedhq/kots/pkg/app/types"
+	canarytypes "github.com/replicatedhq/kots/pkg/canary/types"
+	"github.com/replicatedhq/kots/pkg/store"
+)
+
+type GetCanaryDeploysResponse struct {
+	Policy  apptypes.CanaryPolicy `json:"policy"`
+	Deploys []canarytypes.Deploy  `json:"deploys"`
+}
+
+// GetCanaryDeploys returns the canary policy of the app and its baking and finished canary deploys
+func (h *Handler) GetCanaryDeploys(w http.ResponseWriter, r *http.Request) {
+	foundApp, err := store.GetStore().GetAppFromSlug(mux.Vars(r)["appSlug"])
+	if err != nil {
+		NotFoundJSON(w, r, "failed to get app from slug", err)
+		return
+	}
+
+	deploys, err := store.GetStore().ListCanaryDeploys(foundApp.ID)
+	if err != nil {
+		InternalErrorJSON(w, r, "failed to list canary deploys", err)
+		return
+	}
+
+	JSON(w, http.StatusOK, GetCanaryDeploysResponse{
+		Policy:  foundApp.CanaryPolicy,
+		Deploys: deploys,
+	})
+}
+
+// SetCanaryPolicy replaces the canary policy of the app, it is used by the next deploy. A canary that is baking
+// is promoted or rolled back regardless.
+func (h *Handler) SetCanaryPolicy(w http.ResponseWriter, r *http.Request) {
+	request := apptypes.CanaryPolicy{}
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		BadRequestJSON(w, r, "failed to decode request body", err)
+		return
+	}
+
+	if err := request.Validate(); err != nil {
+		BadRequestJSON(w, r, "invalid canary policy", err)
+		return
+	}
+
+	foundApp, err := store.GetStore().GetAppFromSlug(mux.Vars(r)["appSlug"])
+	if err != nil {
+		NotFoundJSON(w, r, "failed to get app from slug", err)
+		return
+	}
+
+	if request.Enabled() {
+		downstreams, err := store.GetStore().ListDownstreamsForApp(foundApp.ID)
+		if err != nil {
+			InternalErrorJSON(w, r, "failed to list downstreams for app", err)
+			return
+		}
+		isDownstream := false
+		for _, downstream := range downstreams {
+			if downstream.ClusterID == request.ClusterID {
+				isDownstream = true
+				break
+			}
+		}
+		if !isDownstream {
+			BadRequestJSON(w, r, "invalid canary policy", errors.Errorf("cluster %s is not a downstream of the app", request.ClusterID))
+			return
+		}
+	}
+
+	if err := store.GetStore().SetCanaryPolicy(foundApp.ID, request); err != nil {
+		InternalErrorJSON(w, r, "failed to set canary policy", err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
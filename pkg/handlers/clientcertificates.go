@@ -0,0 +1,117 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/pkg/errors"
+	"github.com/replicatedhq/kots/pkg/cabundle"
+	"github.com/replicatedhq/kots/pkg/clientcert"
+	"github.com/replicatedhq/kots/pkg/k8sutil"
+)
+
+type ClientCertificateResponse struct {
+	Host     string    `json:"host"`
+	NotAfter time.Time `json:"notAfter"`
+}
+
+type ListClientCertificatesResponse struct {
+	ClientCertificates []ClientCertificateResponse `json:"clientCertificates"`
+}
+
+type SetClientCertificateRequest struct {
+	Cert string `json:"cert"`
+	Key  string `json:"key"`
+}
+
+// ListClientCertificates lists the hosts that a client certificate is presented to, such as private registries
+// and custom upstream endpoints that require mutual tls. The keys are never returned.
+func (h *Handler) ListClientCertificates(w http.ResponseWriter, r *http.Request) {
+	clientset, err := k8sutil.GetClientset()
+	if err != nil {
+		InternalErrorJSON(w, r, "failed to get clientset", err)
+		return
+	}
+
+	certificates, err := cabundle.GetClientCertificates(clientset, os.Getenv("POD_NAMESPACE"))
+	if err != nil {
+		InternalErrorJSON(w, r, "failed to get client certificates", err)
+		return
+	}
+
+	response := ListClientCertificatesResponse{
+		ClientCertificates: []ClientCertificateResponse{},
+	}
+	for host, c := range certificates {
+		notAfter, err := c.NotAfter()
+		if err != nil {
+			InternalErrorJSON(w, r, "failed to parse client certificate", err)
+			return
+		}
+		response.ClientCertificates = append(response.ClientCertificates, ClientCertificateResponse{
+			Host:     host,
+			NotAfter: notAfter,
+		})
+	}
+	sort.Slice(response.ClientCertificates, func(i, j int) bool {
+		return response.ClientCertificates[i].Host < response.ClientCertificates[j].Host
+	})
+
+	JSON(w, http.StatusOK, response)
+}
+
+func (h *Handler) SetClientCertificate(w http.ResponseWriter, r *http.Request) {
+	request := SetClientCertificateRequest{}
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		BadRequestJSON(w, r, "failed to decode request body", err)
+		return
+	}
+
+	certificate := &clientcert.ClientCertificate{
+		Cert: request.Cert,
+		Key:  request.Key,
+	}
+	if _, err := certificate.TLSCertificate(); err != nil {
+		BadRequestJSON(w, r, "invalid client certificate", err)
+		return
+	}
+
+	if err := setClientCertificate(mux.Vars(r)["host"], certificate); err != nil {
+		InternalErrorJSON(w, r, "failed to set client certificate", err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *Handler) DeleteClientCertificate(w http.ResponseWriter, r *http.Request) {
+	if err := setClientCertificate(mux.Vars(r)["host"], nil); err != nil {
+		InternalErrorJSON(w, r, "failed to delete client certificate", err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// setClientCertificate stores the client certificate for the host and loads it so that it is used by the next request
+func setClientCertificate(host string, certificate *clientcert.ClientCertificate) error {
+	clientset, err := k8sutil.GetClientset()
+	if err != nil {
+		return errors.Wrap(err, "failed to get clientset")
+	}
+
+	certificates, err := cabundle.SetClientCertificate(clientset, os.Getenv("POD_NAMESPACE"), host, certificate)
+	if err != nil {
+		return errors.Wrap(err, "failed to store client certificate")
+	}
+
+	if err := cabundle.LoadClientCertificates(certificates); err != nil {
+		return errors.Wrap(err, "failed to load client certificates")
+	}
+
+	return nil
+}
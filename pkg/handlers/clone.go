@@ -0,0 +1,83 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	handlertypes "github.com/replicatedhq/kots/pkg/api/handlers/types"
+	"github.com/replicatedhq/kots/pkg/appclone"
+	"github.com/replicatedhq/kots/pkg/store"
+)
+
+// CloneApp creates a copy of an app that is deployed to another namespace. The copy has the same upstream,
+// license and config values, and its config must be confirmed before the first deploy.
+func (h *Handler) CloneApp(w http.ResponseWriter, r *http.Request) {
+	request := handlertypes.CloneAppRequest{}
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		BadRequestJSON(w, r, "failed to decode request body", err)
+		return
+	}
+
+	foundApp, err := store.GetStore().GetAppFromSlug(mux.Vars(r)["appSlug"])
+	if err != nil {
+		NotFoundJSON(w, r, "failed to get app from slug", err)
+		return
+	}
+
+	opts := appclone.CloneOptions{
+		Name:      request.Name,
+		Namespace: request.Namespace,
+	}
+	if err := appclone.Validate(foundApp, opts); err != nil {
+		BadRequestJSON(w, r, "invalid clone options", err)
+		return
+	}
+
+	clone, err := appclone.Clone(foundApp, opts)
+	if err != nil {
+		InternalErrorJSON(w, r, "failed to clone app", err)
+		return
+	}
+
+	JSON(w, http.StatusCreated, handlertypes.CloneAppResponse{
+		Slug:      clone.Slug,
+		Namespace: clone.Namespace,
+	})
+}
+
+// CloneAppFromSnapshot creates a copy of an app with the version in the snapshot, in another namespace, and restores
+// the app's resources and volumes from the snapshot into it. The copy is deployed when the restore completes.
+func (h *Handler) CloneAppFromSnapshot(w http.ResponseWriter, r *http.Request) {
+	request := handlertypes.CloneAppRequest{}
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		BadRequestJSON(w, r, "failed to decode request body", err)
+		return
+	}
+
+	foundApp, err := store.GetStore().GetAppFromSlug(mux.Vars(r)["appSlug"])
+	if err != nil {
+		NotFoundJSON(w, r, "failed to get app from slug", err)
+		return
+	}
+
+	opts := appclone.CloneOptions{
+		Name:      request.Name,
+		Namespace: request.Namespace,
+	}
+	if err := appclone.Validate(foundApp, opts); err != nil {
+		BadRequestJSON(w, r, "invalid clone options", err)
+		return
+	}
+
+	clone, err := appclone.CloneFromSnapshot(r.Context(), foundApp, mux.Vars(r)["snapshotName"], opts)
+	if err != nil {
+		InternalErrorJSON(w, r, "failed to clone app from snapshot", err)
+		return
+	}
+
+	JSON(w, http.StatusCreated, handlertypes.CloneAppResponse{
+		Slug:      clone.Slug,
+		Namespace: clone.Namespace,
+	})
+}
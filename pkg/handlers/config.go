@@ -16,8 +16,11 @@ import (
 	kotsv1beta1 "github.com/replicatedhq/kots/kotskinds/apis/kots/v1beta1"
 	"github.com/replicatedhq/kots/kotskinds/multitype"
 	apptypes "github.com/replicatedhq/kots/pkg/app/types"
+	"github.com/replicatedhq/kots/pkg/applock"
 	kotsconfig "github.com/replicatedhq/kots/pkg/config"
+	"github.com/replicatedhq/kots/pkg/configfile"
 	"github.com/replicatedhq/kots/pkg/crypto"
+	"github.com/replicatedhq/kots/pkg/deployapproval"
 	kotsadmconfig "github.com/replicatedhq/kots/pkg/kotsadmconfig"
 	"github.com/replicatedhq/kots/pkg/kotsutil"
 	"github.com/replicatedhq/kots/pkg/logger"
@@ -25,6 +28,7 @@ import (
 	"github.com/replicatedhq/kots/pkg/preflight"
 	registrytypes "github.com/replicatedhq/kots/pkg/registry/types"
 	"github.com/replicatedhq/kots/pkg/render"
+	"github.com/replicatedhq/kots/pkg/sensitiveconfig"
 	"github.com/replicatedhq/kots/pkg/store"
 	"github.com/replicatedhq/kots/pkg/template"
 	"github.com/replicatedhq/kots/pkg/version"
@@ -47,6 +51,7 @@ type UpdateAppConfigResponse struct {
 	Success       bool     `json:"success"`
 	Error         string   `json:"error,omitempty"`
 	RequiredItems []string `json:"requiredItems,omitempty"`
+	GatedItems    []string `json:"gatedItems,omitempty"`
 }
 
 type LiveAppConfigResponse struct {
@@ -61,6 +66,27 @@ type CurrentAppConfigResponse struct {
 	ConfigGroups []kotsv1beta1.ConfigGroup `json:"configGroups"`
 }
 
+type ConfigSchemaItem struct {
+	Name     string `json:"name"`
+	Title    string `json:"title,omitempty"`
+	Type     string `json:"type"`
+	Required bool   `json:"required,omitempty"`
+	ReadOnly bool   `json:"readonly,omitempty"`
+}
+
+type ConfigSchemaGroup struct {
+	Name  string             `json:"name"`
+	Title string             `json:"title,omitempty"`
+	Items []ConfigSchemaItem `json:"items"`
+}
+
+type GetAppConfigSchemaResponse struct {
+	Success    bool                `json:"success"`
+	Error      string              `json:"error,omitempty"`
+	Groups     []ConfigSchemaGroup `json:"groups"`
+	GatedItems []string            `json:"gatedItems"`
+}
+
 func (h *Handler) UpdateAppConfig(w http.ResponseWriter, r *http.Request) {
 	updateAppConfigResponse := UpdateAppConfigResponse{
 		Success: false,
@@ -108,13 +134,23 @@ func (h *Handler) UpdateAppConfig(w http.ResponseWriter, r *http.Request) {
 	isPrimaryVersion := true
 	skipPrefligths := false
 	deploy := false
-	resp, err := updateAppConfig(foundApp, updateAppConfigRequest.Sequence, updateAppConfigRequest.ConfigGroups, createNewVersion, isPrimaryVersion, skipPrefligths, deploy)
+	resp, err := updateAppConfig(foundApp, updateAppConfigRequest.Sequence, updateAppConfigRequest.ConfigGroups, createNewVersion, isPrimaryVersion, skipPrefligths, deploy, sessionUserID(r))
 	if err != nil {
 		logger.Error(err)
+		if applock.IsLocked(err) {
+			setAppLockedRetryAfter(w)
+			JSON(w, http.StatusConflict, resp)
+			return
+		}
 		JSON(w, http.StatusInternalServerError, resp)
 		return
 	}
 
+	if len(resp.GatedItems) > 0 {
+		JSON(w, http.StatusForbidden, resp)
+		return
+	}
+
 	if len(resp.RequiredItems) > 0 {
 		JSON(w, http.StatusBadRequest, resp)
 		return
@@ -178,24 +214,7 @@ func (h *Handler) LiveAppConfig(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// get values from request
-	configValues := map[string]template.ItemValue{}
-
-	for _, group := range liveAppConfigRequest.ConfigGroups {
-		for _, item := range group.Items {
-			generatedValue := template.ItemValue{}
-			if item.Value.Type == multitype.String {
-				generatedValue.Value = item.Value.StrVal
-			} else {
-				generatedValue.Value = item.Value.BoolVal
-			}
-			if item.Default.Type == multitype.String {
-				generatedValue.Default = item.Default.StrVal
-			} else {
-				generatedValue.Default = item.Default.BoolVal
-			}
-			configValues[item.Name] = generatedValue
-		}
-	}
+	configValues := itemValuesFromConfigGroups(liveAppConfigRequest.ConfigGroups)
 
 	registryInfo, err := store.GetStore().GetRegistryDetailsForApp(foundApp.ID)
 	if err != nil {
@@ -225,6 +244,28 @@ func (h *Handler) LiveAppConfig(w http.ResponseWriter, r *http.Request) {
 	JSON(w, http.StatusOK, LiveAppConfigResponse{Success: true, ConfigGroups: renderedConfig.Spec.Groups})
 }
 
+// itemValuesFromConfigGroups returns the values of the items of config groups that were edited in the console
+func itemValuesFromConfigGroups(configGroups []kotsv1beta1.ConfigGroup) map[string]template.ItemValue {
+	configValues := map[string]template.ItemValue{}
+	for _, group := range configGroups {
+		for _, item := range group.Items {
+			generatedValue := template.ItemValue{}
+			if item.Value.Type == multitype.String {
+				generatedValue.Value = item.Value.StrVal
+			} else {
+				generatedValue.Value = item.Value.BoolVal
+			}
+			if item.Default.Type == multitype.String {
+				generatedValue.Default = item.Default.StrVal
+			} else {
+				generatedValue.Default = item.Default.BoolVal
+			}
+			configValues[item.Name] = generatedValue
+		}
+	}
+	return configValues
+}
+
 func (h *Handler) CurrentAppConfig(w http.ResponseWriter, r *http.Request) {
 	currentAppConfigResponse := CurrentAppConfigResponse{
 		Success: false,
@@ -318,6 +359,130 @@ func (h *Handler) CurrentAppConfig(w http.ResponseWriter, r *http.Request) {
 	JSON(w, http.StatusOK, CurrentAppConfigResponse{Success: true, ConfigGroups: renderedConfig.Spec.Groups})
 }
 
+// GetAppConfigSchema returns the config groups and items that are visible for the current version,
+// with the license requirements evaluated against the app's license. Items hidden by the license
+// are listed separately so that clients can validate values before submitting them.
+func (h *Handler) GetAppConfigSchema(w http.ResponseWriter, r *http.Request) {
+	getAppConfigSchemaResponse := GetAppConfigSchemaResponse{
+		Success:    false,
+		Groups:     []ConfigSchemaGroup{},
+		GatedItems: []string{},
+	}
+
+	foundApp, err := store.GetStore().GetAppFromSlug(mux.Vars(r)["appSlug"])
+	if err != nil {
+		logger.Error(err)
+		getAppConfigSchemaResponse.Error = "failed to get app from app slug"
+		JSON(w, http.StatusInternalServerError, getAppConfigSchemaResponse)
+		return
+	}
+
+	appLicense, err := store.GetStore().GetLatestLicenseForApp(foundApp.ID)
+	if err != nil {
+		logger.Error(err)
+		getAppConfigSchemaResponse.Error = "failed to get license for app"
+		JSON(w, http.StatusInternalServerError, getAppConfigSchemaResponse)
+		return
+	}
+
+	archiveDir, err := ioutil.TempDir("", "kotsadm")
+	if err != nil {
+		logger.Error(err)
+		getAppConfigSchemaResponse.Error = "failed to create temp dir"
+		JSON(w, http.StatusInternalServerError, getAppConfigSchemaResponse)
+		return
+	}
+	defer os.RemoveAll(archiveDir)
+
+	err = store.GetStore().GetAppVersionArchive(foundApp.ID, foundApp.CurrentSequence, archiveDir)
+	if err != nil {
+		logger.Error(err)
+		getAppConfigSchemaResponse.Error = "failed to get app version archive"
+		JSON(w, http.StatusInternalServerError, getAppConfigSchemaResponse)
+		return
+	}
+
+	kotsKinds, err := kotsutil.LoadKotsKindsFromPath(archiveDir)
+	if err != nil {
+		logger.Error(err)
+		getAppConfigSchemaResponse.Error = "failed to load kots kinds from path"
+		JSON(w, http.StatusInternalServerError, getAppConfigSchemaResponse)
+		return
+	}
+
+	if kotsKinds.Config == nil {
+		getAppConfigSchemaResponse.Success = true
+		JSON(w, http.StatusOK, getAppConfigSchemaResponse)
+		return
+	}
+
+	configValues := map[string]template.ItemValue{}
+	if kotsKinds.ConfigValues != nil {
+		for key, value := range kotsKinds.ConfigValues.Spec.Values {
+			configValues[key] = template.ItemValue{
+				Default: value.Default,
+				Value:   value.Value,
+			}
+		}
+	}
+
+	registryInfo, err := store.GetStore().GetRegistryDetailsForApp(foundApp.ID)
+	if err != nil {
+		logger.Error(err)
+		getAppConfigSchemaResponse.Error = "failed to get app registry info"
+		JSON(w, http.StatusInternalServerError, getAppConfigSchemaResponse)
+		return
+	}
+
+	localRegistry := template.LocalRegistry{
+		Host:      registryInfo.Hostname,
+		Namespace: registryInfo.Namespace,
+		Username:  registryInfo.Username,
+		Password:  registryInfo.Password,
+		ReadOnly:  registryInfo.IsReadOnly,
+	}
+
+	// the gated items have to be computed before templating, since templating hides them
+	getAppConfigSchemaResponse.GatedItems = kotsconfig.LicenseGatedItems(kotsKinds.Config, appLicense)
+
+	versionInfo := template.VersionInfoFromInstallation(foundApp.CurrentSequence, foundApp.IsAirgap, kotsKinds.Installation.Spec)
+	renderedConfig, err := kotsconfig.TemplateConfigObjects(kotsKinds.Config, configValues, appLicense, localRegistry, &versionInfo, kotsKinds.IdentityConfig)
+	if err != nil {
+		logger.Error(err)
+		getAppConfigSchemaResponse.Error = "failed to render templates"
+		JSON(w, http.StatusInternalServerError, getAppConfigSchemaResponse)
+		return
+	}
+
+	for _, group := range renderedConfig.Spec.Groups {
+		if group.When == "false" {
+			continue
+		}
+
+		schemaGroup := ConfigSchemaGroup{
+			Name:  group.Name,
+			Title: group.Title,
+			Items: []ConfigSchemaItem{},
+		}
+		for _, item := range group.Items {
+			if item.When == "false" {
+				continue
+			}
+			schemaGroup.Items = append(schemaGroup.Items, ConfigSchemaItem{
+				Name:     item.Name,
+				Title:    item.Title,
+				Type:     item.Type,
+				Required: item.Required,
+				ReadOnly: item.ReadOnly,
+			})
+		}
+		getAppConfigSchemaResponse.Groups = append(getAppConfigSchemaResponse.Groups, schemaGroup)
+	}
+
+	getAppConfigSchemaResponse.Success = true
+	JSON(w, http.StatusOK, getAppConfigSchemaResponse)
+}
+
 func isVersionConfigEditable(app *apptypes.App, sequence int64) (bool, error) {
 	// Only latest and currently deployed versions can be edited
 	if app.CurrentSequence == sequence {
@@ -369,11 +534,22 @@ func shouldCreateNewAppVersion(appID string, sequence int64) (bool, error) {
 
 // if isPrimaryVersion is false, missing a required config field will not cause a failure, and instead will create
 // the app version with status needs_config
-func updateAppConfig(updateApp *apptypes.App, sequence int64, configGroups []kotsv1beta1.ConfigGroup, createNewVersion bool, isPrimaryVersion bool, skipPreflights bool, deploy bool) (UpdateAppConfigResponse, error) {
+// requestedBy is the user that requested the deploy, for apps that require deploy approval
+func updateAppConfig(updateApp *apptypes.App, sequence int64, configGroups []kotsv1beta1.ConfigGroup, createNewVersion bool, isPrimaryVersion bool, skipPreflights bool, deploy bool, requestedBy string) (UpdateAppConfigResponse, error) {
 	updateAppConfigResponse := UpdateAppConfigResponse{
 		Success: false,
 	}
 
+	unlock, err := applock.TryLock(updateApp.ID)
+	if err != nil {
+		updateAppConfigResponse.Error = "failed to lock app"
+		if applock.IsLocked(err) {
+			updateAppConfigResponse.Error = err.Error()
+		}
+		return updateAppConfigResponse, err
+	}
+	defer unlock()
+
 	archiveDir, err := ioutil.TempDir("", "kotsadm")
 	if err != nil {
 		updateAppConfigResponse.Error = "failed to create temp dir"
@@ -387,10 +563,69 @@ func updateAppConfig(updateApp *apptypes.App, sequence int64, configGroups []kot
 		return updateAppConfigResponse, err
 	}
 
+	app, resp, err := renderConfigGroups(updateApp, sequence, archiveDir, configGroups, isPrimaryVersion)
+	if err != nil || len(resp.RequiredItems) > 0 || len(resp.GatedItems) > 0 {
+		return resp, err
+	}
+
+	if createNewVersion {
+		newSequence, err := store.GetStore().CreateAppVersion(updateApp.ID, &app.CurrentSequence, archiveDir, "Config Change", false, &version.DownstreamGitOps{})
+		if err != nil {
+			updateAppConfigResponse.Error = "failed to create an app version"
+			return updateAppConfigResponse, err
+		}
+		sequence = newSequence
+	} else {
+		if err := kotsadmconfig.UpdateConfigValuesInDB(archiveDir, updateApp.ID, int64(sequence)); err != nil {
+			updateAppConfigResponse.Error = "failed to update config values in db"
+			return updateAppConfigResponse, err
+		}
+
+		if err := store.GetStore().CreateAppVersionArchive(updateApp.ID, int64(sequence), archiveDir); err != nil {
+			updateAppConfigResponse.Error = "failed to create app version archive"
+			return updateAppConfigResponse, err
+		}
+	}
+
+	if err := store.GetStore().SetDownstreamVersionPendingPreflight(updateApp.ID, int64(sequence)); err != nil {
+		updateAppConfigResponse.Error = "failed to set downstream status to 'pending preflight'"
+		return updateAppConfigResponse, err
+	}
+
+	if !skipPreflights {
+		if err := preflight.Run(updateApp.ID, updateApp.Slug, int64(sequence), updateApp.IsAirgap, archiveDir); err != nil {
+			updateAppConfigResponse.Error = errors.Cause(err).Error()
+			return updateAppConfigResponse, err
+		}
+	}
+
+	if deploy {
+		_, err := deployapproval.DeployOrRequest(updateApp, sequence, deployapproval.RequestOptions{
+			RequestedBy:      requestedBy,
+			IsSkipPreflights: skipPreflights,
+		})
+		if err != nil {
+			updateAppConfigResponse.Error = "failed to deploy"
+			return updateAppConfigResponse, err
+		}
+	}
+
+	updateAppConfigResponse.Success = true
+	return updateAppConfigResponse, nil
+}
+
+// renderConfigGroups writes the values of the config groups to the version archive in archiveDir and renders it.
+// Required items that are not set are returned in the response if isPrimaryVersion is true, and items that are
+// gated by the license and set to a new value are always returned.
+func renderConfigGroups(updateApp *apptypes.App, sequence int64, archiveDir string, configGroups []kotsv1beta1.ConfigGroup, isPrimaryVersion bool) (*apptypes.App, UpdateAppConfigResponse, error) {
+	updateAppConfigResponse := UpdateAppConfigResponse{
+		Success: false,
+	}
+
 	kotsKinds, err := kotsutil.LoadKotsKindsFromPath(archiveDir)
 	if err != nil {
 		updateAppConfigResponse.Error = "failed to load kots kinds from path"
-		return updateAppConfigResponse, err
+		return nil, updateAppConfigResponse, err
 	}
 
 	// check for unset required items
@@ -416,12 +651,16 @@ func updateAppConfig(updateApp *apptypes.App, sequence int64, configGroups []kot
 	if len(requiredItems) > 0 && isPrimaryVersion {
 		updateAppConfigResponse.RequiredItems = requiredItems
 		updateAppConfigResponse.Error = fmt.Sprintf("The following fields are required: %s", strings.Join(requiredItemsTitles, ", "))
-		return updateAppConfigResponse, nil
+		return nil, updateAppConfigResponse, nil
 	}
 
 	// we don't merge, this is a wholesale replacement of the config values
 	// so we don't need the complex logic in kots, we can just write
 	values := kotsKinds.ConfigValues.Spec.Values
+	existingValues := map[string]kotsv1beta1.ConfigValue{}
+	for name, value := range values {
+		existingValues[name] = value
+	}
 	for _, group := range configGroups {
 		for _, item := range group.Items {
 			if item.Value.Type == multitype.Bool {
@@ -431,12 +670,16 @@ func updateAppConfig(updateApp *apptypes.App, sequence int64, configGroups []kot
 				values[item.Name] = v
 			} else if item.Value.Type == multitype.String {
 				updatedValue := item.Value.String()
-				if item.Type == "password" {
+				if item.Type == configfile.ItemType && updatedValue != "" && !configfile.IsRef(updatedValue) {
+					updateAppConfigResponse.Error = fmt.Sprintf("%s must be uploaded before it can be saved", item.Name)
+					return nil, updateAppConfigResponse, errors.Errorf("value of %s is not a config file reference", item.Name)
+				}
+				if sensitiveconfig.IsEncrypted(item) {
 					// encrypt using the key
 					cipher, err := crypto.AESCipherFromString(kotsKinds.Installation.Spec.EncryptionKey)
 					if err != nil {
 						updateAppConfigResponse.Error = "failed to load encryption cipher"
-						return updateAppConfigResponse, err
+						return nil, updateAppConfigResponse, err
 					}
 
 					// if the decryption succeeds, don't encrypt again
@@ -455,7 +698,28 @@ func updateAppConfig(updateApp *apptypes.App, sequence int64, configGroups []kot
 
 	if kotsKinds.ConfigValues == nil {
 		updateAppConfigResponse.Error = "no config values found"
-		return updateAppConfigResponse, errors.New("no config values found")
+		return nil, updateAppConfigResponse, errors.New("no config values found")
+	}
+
+	if kotsKinds.Config != nil {
+		appLicense, err := store.GetStore().GetLatestLicenseForApp(updateApp.ID)
+		if err != nil {
+			updateAppConfigResponse.Error = "failed to get license"
+			return nil, updateAppConfigResponse, err
+		}
+		cipher, err := crypto.AESCipherFromString(kotsKinds.Installation.Spec.EncryptionKey)
+		if err != nil {
+			updateAppConfigResponse.Error = "failed to load encryption cipher"
+			return nil, updateAppConfigResponse, err
+		}
+
+		gatedItems := kotsconfig.LicenseGatedItems(kotsKinds.Config, appLicense)
+		changedGatedItems := changedLicenseGatedItems(gatedItems, configGroups, existingValues, values, cipher)
+		if len(changedGatedItems) > 0 {
+			updateAppConfigResponse.GatedItems = changedGatedItems
+			updateAppConfigResponse.Error = fmt.Sprintf("The license does not allow setting the following fields: %s", strings.Join(changedGatedItems, ", "))
+			return nil, updateAppConfigResponse, nil
+		}
 	}
 
 	kotsKinds.ConfigValues.Spec.Values = values
@@ -463,29 +727,29 @@ func updateAppConfig(updateApp *apptypes.App, sequence int64, configGroups []kot
 	configValuesSpec, err := kotsKinds.Marshal("kots.io", "v1beta1", "ConfigValues")
 	if err != nil {
 		updateAppConfigResponse.Error = "failed to marshal config values spec"
-		return updateAppConfigResponse, err
+		return nil, updateAppConfigResponse, err
 	}
 
 	if err := ioutil.WriteFile(filepath.Join(archiveDir, "upstream", "userdata", "config.yaml"), []byte(configValuesSpec), 0644); err != nil {
 		updateAppConfigResponse.Error = "failed to write config.yaml to upstream/userdata"
-		return updateAppConfigResponse, err
+		return nil, updateAppConfigResponse, err
 	}
 
 	registrySettings, err := store.GetStore().GetRegistryDetailsForApp(updateApp.ID)
 	if err != nil {
 		updateAppConfigResponse.Error = "failed to get registry settings"
-		return updateAppConfigResponse, err
+		return nil, updateAppConfigResponse, err
 	}
 
 	app, err := store.GetStore().GetApp(updateApp.ID)
 	if err != nil {
 		updateAppConfigResponse.Error = "failed to get app"
-		return updateAppConfigResponse, err
+		return nil, updateAppConfigResponse, err
 	}
 	downstreams, err := store.GetStore().ListDownstreamsForApp(updateApp.ID)
 	if err != nil {
 		updateAppConfigResponse.Error = "failed to list downstreams for app"
-		return updateAppConfigResponse, err
+		return nil, updateAppConfigResponse, err
 	}
 
 	if app.CurrentSequence != sequence {
@@ -494,7 +758,7 @@ func updateAppConfig(updateApp *apptypes.App, sequence int64, configGroups []kot
 		versionRegistrySettings, err := midstream.LoadPrivateRegistryInfo(archiveDir)
 		if err != nil {
 			updateAppConfigResponse.Error = "failed to get version registry settings"
-			return updateAppConfigResponse, err
+			return nil, updateAppConfigResponse, err
 		}
 
 		if versionRegistrySettings == nil {
@@ -510,50 +774,47 @@ func updateAppConfig(updateApp *apptypes.App, sequence int64, configGroups []kot
 	err = render.RenderDir(archiveDir, app, downstreams, registrySettings)
 	if err != nil {
 		updateAppConfigResponse.Error = "failed to render archive directory"
-		return updateAppConfigResponse, err
+		return nil, updateAppConfigResponse, err
 	}
 
-	if createNewVersion {
-		newSequence, err := store.GetStore().CreateAppVersion(updateApp.ID, &updateApp.CurrentSequence, archiveDir, "Config Change", false, &version.DownstreamGitOps{})
-		if err != nil {
-			updateAppConfigResponse.Error = "failed to create an app version"
-			return updateAppConfigResponse, err
-		}
-		sequence = newSequence
-	} else {
-		if err := kotsadmconfig.UpdateConfigValuesInDB(archiveDir, updateApp.ID, int64(sequence)); err != nil {
-			updateAppConfigResponse.Error = "failed to update config values in db"
-			return updateAppConfigResponse, err
-		}
+	return app, updateAppConfigResponse, nil
+}
 
-		if err := store.GetStore().CreateAppVersionArchive(updateApp.ID, int64(sequence), archiveDir); err != nil {
-			updateAppConfigResponse.Error = "failed to create app version archive"
-			return updateAppConfigResponse, err
-		}
+// changedLicenseGatedItems returns the items that are gated by the license and set to a new value. Without the
+// entitlement, the values of gated items can only be kept or cleared.
+func changedLicenseGatedItems(gatedItems []string, configGroups []kotsv1beta1.ConfigGroup, existingValues map[string]kotsv1beta1.ConfigValue, newValues map[string]kotsv1beta1.ConfigValue, cipher *crypto.AESCipher) []string {
+	isGated := map[string]struct{}{}
+	for _, name := range gatedItems {
+		isGated[name] = struct{}{}
 	}
 
-	if err := store.GetStore().SetDownstreamVersionPendingPreflight(updateApp.ID, int64(sequence)); err != nil {
-		updateAppConfigResponse.Error = "failed to set downstream status to 'pending preflight'"
-		return updateAppConfigResponse, err
-	}
+	changed := []string{}
+	for _, group := range configGroups {
+		for _, item := range group.Items {
+			if _, ok := isGated[item.Name]; !ok {
+				continue
+			}
 
-	if !skipPreflights {
-		if err := preflight.Run(updateApp.ID, updateApp.Slug, int64(sequence), updateApp.IsAirgap, archiveDir); err != nil {
-			updateAppConfigResponse.Error = errors.Cause(err).Error()
-			return updateAppConfigResponse, err
-		}
-	}
+			newValue := newValues[item.Name].Value
+			existingValue := existingValues[item.Name].Value
+			if newValue == "" || newValue == existingValue {
+				continue
+			}
 
-	if deploy {
-		err := version.DeployVersion(updateApp.ID, sequence)
-		if err != nil {
-			updateAppConfigResponse.Error = "failed to deploy"
-			return updateAppConfigResponse, err
+			// the same value can be encrypted differently
+			if sensitiveconfig.IsEncrypted(item) {
+				decryptedNew, errNew := decrypt(newValue, cipher)
+				decryptedExisting, errExisting := decrypt(existingValue, cipher)
+				if errNew == nil && errExisting == nil && decryptedNew == decryptedExisting {
+					continue
+				}
+			}
+
+			changed = append(changed, item.Name)
 		}
 	}
 
-	updateAppConfigResponse.Success = true
-	return updateAppConfigResponse, nil
+	return changed
 }
 
 func decrypt(input string, cipher *crypto.AESCipher) (string, error) {
@@ -599,8 +860,7 @@ func (h *Handler) SetAppConfigValues(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	decode := scheme.Codecs.UniversalDeserializer().Decode
-	decoded, gvk, err := decode(setAppConfigValuesRequest.ConfigValues, nil, nil)
+	newConfigValues, err := decodeConfigValues(setAppConfigValuesRequest.ConfigValues)
 	if err != nil {
 		setAppConfigValuesResponse.Error = "failed to decode config values"
 		logger.Error(errors.Wrap(err, setAppConfigValuesResponse.Error))
@@ -608,14 +868,6 @@ func (h *Handler) SetAppConfigValues(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if gvk.String() != "kots.io/v1beta1, Kind=ConfigValues" {
-		setAppConfigValuesResponse.Error = fmt.Sprintf("%q is not a valid ConfigValues GVK", gvk.String())
-		logger.Errorf(setAppConfigValuesResponse.Error)
-		JSON(w, http.StatusInternalServerError, setAppConfigValuesResponse)
-		return
-	}
-	newConfigValues := decoded.(*kotsv1beta1.ConfigValues)
-
 	foundApp, err := store.GetStore().GetAppFromSlug(mux.Vars(r)["appSlug"])
 	if err != nil {
 		setAppConfigValuesResponse.Error = "failed to get app from app slug"
@@ -624,61 +876,121 @@ func (h *Handler) SetAppConfigValues(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	archiveDir, err := ioutil.TempDir("", "kotsadm")
+	configGroups, err := configGroupsForValues(foundApp, newConfigValues, setAppConfigValuesRequest.Merge)
 	if err != nil {
-		setAppConfigValuesResponse.Error = "failed to create temp dir"
-		logger.Error(errors.Wrap(err, setAppConfigValuesResponse.Error))
+		setAppConfigValuesResponse.Error = err.Error()
+		logger.Error(errors.Wrap(err, "failed to get config groups for values"))
 		JSON(w, http.StatusInternalServerError, setAppConfigValuesResponse)
 		return
 	}
+
+	createNewVersion := true
+	isPrimaryVersion := true // see comment in updateAppConfig
+	resp, err := updateAppConfig(foundApp, foundApp.CurrentSequence, configGroups, createNewVersion, isPrimaryVersion, setAppConfigValuesRequest.SkipPreflights, setAppConfigValuesRequest.Deploy, sessionUserID(r))
+	if err != nil {
+		logger.Error(errors.Wrap(err, "failed to create new version"))
+		if applock.IsLocked(err) {
+			setAppLockedRetryAfter(w)
+			JSON(w, http.StatusConflict, resp)
+			return
+		}
+		JSON(w, http.StatusInternalServerError, resp)
+		return
+	}
+
+	if len(resp.GatedItems) > 0 {
+		logger.Error(errors.New(resp.Error))
+		JSON(w, http.StatusForbidden, resp)
+		return
+	}
+
+	if len(resp.RequiredItems) > 0 {
+		logger.Error(errors.Wrap(err, "failed to set all required items"))
+		JSON(w, http.StatusBadRequest, resp)
+		return
+	}
+
+	setAppConfigValuesResponse.Success = true
+	JSON(w, http.StatusOK, setAppConfigValuesResponse)
+}
+
+// SetConfigValues replaces the config values of the app with the values of a ConfigValues yaml and creates a new
+// version, which is deployed if deploy is true. It is used by the KotsApp controller.
+func SetConfigValues(foundApp *apptypes.App, configValuesData []byte, deploy bool, skipPreflights bool) error {
+	newConfigValues, err := decodeConfigValues(configValuesData)
+	if err != nil {
+		return errors.Wrap(err, "failed to decode config values")
+	}
+
+	configGroups, err := configGroupsForValues(foundApp, newConfigValues, false)
+	if err != nil {
+		return errors.Wrap(err, "failed to get config groups for values")
+	}
+
+	createNewVersion := true
+	isPrimaryVersion := true // see comment in updateAppConfig
+	resp, err := updateAppConfig(foundApp, foundApp.CurrentSequence, configGroups, createNewVersion, isPrimaryVersion, skipPreflights, deploy, deployapproval.RequestedByAutomaticDeploy)
+	if err != nil {
+		return errors.Wrap(err, "failed to update app config")
+	}
+	if len(resp.RequiredItems) > 0 || len(resp.GatedItems) > 0 {
+		return errors.New(resp.Error)
+	}
+
+	return nil
+}
+
+func decodeConfigValues(data []byte) (*kotsv1beta1.ConfigValues, error) {
+	decode := scheme.Codecs.UniversalDeserializer().Decode
+	decoded, gvk, err := decode(data, nil, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to decode")
+	}
+
+	if gvk.String() != "kots.io/v1beta1, Kind=ConfigValues" {
+		return nil, errors.Errorf("%q is not a valid ConfigValues GVK", gvk.String())
+	}
+
+	return decoded.(*kotsv1beta1.ConfigValues), nil
+}
+
+// configGroupsForValues returns the config groups of the current version of the app with the values applied and
+// the templates rendered. If merge is true, values that are not set keep the existing values.
+func configGroupsForValues(foundApp *apptypes.App, newConfigValues *kotsv1beta1.ConfigValues, merge bool) ([]kotsv1beta1.ConfigGroup, error) {
+	archiveDir, err := ioutil.TempDir("", "kotsadm")
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create temp dir")
+	}
 	defer os.RemoveAll(archiveDir)
 
 	err = store.GetStore().GetAppVersionArchive(foundApp.ID, foundApp.CurrentSequence, archiveDir)
 	if err != nil {
-		setAppConfigValuesResponse.Error = "failed to get app version archive"
-		logger.Error(errors.Wrap(err, setAppConfigValuesResponse.Error))
-		JSON(w, http.StatusInternalServerError, setAppConfigValuesResponse)
-		return
+		return nil, errors.Wrap(err, "failed to get app version archive")
 	}
 
 	kotsKinds, err := kotsutil.LoadKotsKindsFromPath(archiveDir)
 	if err != nil {
-		setAppConfigValuesResponse.Error = "failed to load kots kinds from path"
-		logger.Error(errors.Wrap(err, setAppConfigValuesResponse.Error))
-		JSON(w, http.StatusInternalServerError, setAppConfigValuesResponse)
-		return
+		return nil, errors.Wrap(err, "failed to load kots kinds from path")
 	}
 
 	if kotsKinds.Config == nil {
-		setAppConfigValuesResponse.Error = fmt.Sprintf("app %s does not have a config", foundApp.Slug)
-		logger.Errorf(setAppConfigValuesResponse.Error)
-		JSON(w, http.StatusInternalServerError, setAppConfigValuesResponse)
-		return
+		return nil, errors.Errorf("app %s does not have a config", foundApp.Slug)
 	}
 
-	if setAppConfigValuesRequest.Merge {
+	if merge {
 		if err := kotsKinds.DecryptConfigValues(); err != nil {
-			setAppConfigValuesResponse.Error = "failed to decrypt existing values"
-			logger.Error(errors.Wrap(err, setAppConfigValuesResponse.Error))
-			JSON(w, http.StatusInternalServerError, setAppConfigValuesResponse)
-			return
+			return nil, errors.Wrap(err, "failed to decrypt existing values")
 		}
 
 		newConfigValues, err = mergeConfigValues(kotsKinds.Config, kotsKinds.ConfigValues, newConfigValues)
 		if err != nil {
-			setAppConfigValuesResponse.Error = "failed to create new config"
-			logger.Error(errors.Wrap(err, setAppConfigValuesResponse.Error))
-			JSON(w, http.StatusInternalServerError, setAppConfigValuesResponse)
-			return
+			return nil, errors.Wrap(err, "failed to create new config")
 		}
 	}
 
-	newConfig, err := updateConfigObject(kotsKinds.Config, newConfigValues, setAppConfigValuesRequest.Merge)
+	newConfig, err := updateConfigObject(kotsKinds.Config, newConfigValues, merge)
 	if err != nil {
-		setAppConfigValuesResponse.Error = "failed to create new config object"
-		logger.Error(errors.Wrap(err, setAppConfigValuesResponse.Error))
-		JSON(w, http.StatusInternalServerError, setAppConfigValuesResponse)
-		return
+		return nil, errors.Wrap(err, "failed to create new config object")
 	}
 
 	configValueMap := map[string]template.ItemValue{}
@@ -692,10 +1004,7 @@ func (h *Handler) SetAppConfigValues(w http.ResponseWriter, r *http.Request) {
 
 	registryInfo, err := store.GetStore().GetRegistryDetailsForApp(foundApp.ID)
 	if err != nil {
-		setAppConfigValuesResponse.Error = "failed to get app registry info"
-		logger.Error(errors.Wrap(err, setAppConfigValuesResponse.Error))
-		JSON(w, http.StatusInternalServerError, setAppConfigValuesResponse)
-		return
+		return nil, errors.Wrap(err, "failed to get app registry info")
 	}
 
 	localRegistry := template.LocalRegistry{
@@ -709,29 +1018,10 @@ func (h *Handler) SetAppConfigValues(w http.ResponseWriter, r *http.Request) {
 	versionInfo := template.VersionInfoFromInstallation(foundApp.CurrentSequence+1, foundApp.IsAirgap, kotsKinds.Installation.Spec) // sequence +1 because the sequence will be incremented on save (and we want the preview to be accurate)
 	renderedConfig, err := kotsconfig.TemplateConfigObjects(newConfig, configValueMap, kotsKinds.License, localRegistry, &versionInfo, kotsKinds.IdentityConfig)
 	if err != nil {
-		setAppConfigValuesResponse.Error = "failed to render templates"
-		logger.Error(errors.Wrap(err, setAppConfigValuesResponse.Error))
-		JSON(w, http.StatusInternalServerError, setAppConfigValuesResponse)
-		return
-	}
-
-	createNewVersion := true
-	isPrimaryVersion := true // see comment in updateAppConfig
-	resp, err := updateAppConfig(foundApp, foundApp.CurrentSequence, renderedConfig.Spec.Groups, createNewVersion, isPrimaryVersion, setAppConfigValuesRequest.SkipPreflights, setAppConfigValuesRequest.Deploy)
-	if err != nil {
-		logger.Error(errors.Wrap(err, "failed to create new version"))
-		JSON(w, http.StatusInternalServerError, resp)
-		return
-	}
-
-	if len(resp.RequiredItems) > 0 {
-		logger.Error(errors.Wrap(err, "failed to set all required items"))
-		JSON(w, http.StatusBadRequest, resp)
-		return
+		return nil, errors.Wrap(err, "failed to render templates")
 	}
 
-	setAppConfigValuesResponse.Success = true
-	JSON(w, http.StatusOK, setAppConfigValuesResponse)
+	return renderedConfig.Spec.Groups, nil
 }
 
 func mergeConfigValues(config *kotsv1beta1.Config, existingValues *kotsv1beta1.ConfigValues, newValues *kotsv1beta1.ConfigValues) (*kotsv1beta1.ConfigValues, error) {
@@ -780,7 +1070,8 @@ func mergeConfigValues(config *kotsv1beta1.Config, existingValues *kotsv1beta1.C
 			Name: existingValues.ObjectMeta.Name,
 		},
 		Spec: kotsv1beta1.ConfigValuesSpec{
-			Values: mergedValues,
+			Values:        mergedValues,
+			SchemaVersion: existingValues.Spec.SchemaVersion,
 		},
 	}
 
@@ -0,0 +1,66 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/pkg/errors"
+	"github.com/replicatedhq/kots/pkg/configfile"
+	"github.com/replicatedhq/kots/pkg/logger"
+	"github.com/replicatedhq/kots/pkg/store"
+)
+
+type UploadAppConfigFileResponse struct {
+	Success bool   `json:"success"`
+	Error   string `json:"error,omitempty"`
+	// Value is the reference to set as the value of the large file config item
+	Value    string `json:"value,omitempty"`
+	Filename string `json:"filename,omitempty"`
+	Checksum string `json:"checksum,omitempty"`
+	Size     int64  `json:"size,omitempty"`
+}
+
+// UploadAppConfigFile stores a file for a large file config item out-of-line.
+// The returned value is then saved as the item value with UpdateAppConfig.
+func (h *Handler) UploadAppConfigFile(w http.ResponseWriter, r *http.Request) {
+	uploadAppConfigFileResponse := UploadAppConfigFileResponse{
+		Success: false,
+	}
+
+	_, err := store.GetStore().GetAppFromSlug(mux.Vars(r)["appSlug"])
+	if err != nil {
+		logger.Error(err)
+		uploadAppConfigFileResponse.Error = "failed to get app from slug"
+		JSON(w, http.StatusInternalServerError, uploadAppConfigFileResponse)
+		return
+	}
+
+	file, header, err := r.FormFile("file")
+	if err != nil {
+		logger.Error(err)
+		uploadAppConfigFileResponse.Error = "failed to read file from request"
+		JSON(w, http.StatusBadRequest, uploadAppConfigFileResponse)
+		return
+	}
+	defer file.Close()
+
+	configFile, err := configfile.Upload(header.Filename, file)
+	if errors.Cause(err) == configfile.ErrTooLarge {
+		uploadAppConfigFileResponse.Error = err.Error()
+		JSON(w, http.StatusRequestEntityTooLarge, uploadAppConfigFileResponse)
+		return
+	}
+	if err != nil {
+		logger.Error(err)
+		uploadAppConfigFileResponse.Error = "failed to upload config file"
+		JSON(w, http.StatusInternalServerError, uploadAppConfigFileResponse)
+		return
+	}
+
+	uploadAppConfigFileResponse.Success = true
+	uploadAppConfigFileResponse.Value = configFile.Ref
+	uploadAppConfigFileResponse.Filename = configFile.Filename
+	uploadAppConfigFileResponse.Checksum = configFile.Checksum
+	uploadAppConfigFileResponse.Size = configFile.Size
+	JSON(w, http.StatusOK, uploadAppConfigFileResponse)
+}
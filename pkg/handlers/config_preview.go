@@ -0,0 +1,131 @@
+package handlers
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"os"
+
+	"github.com/gorilla/mux"
+	"github.com/pkg/errors"
+	kotsv1beta1 "github.com/replicatedhq/kots/kotskinds/apis/kots/v1beta1"
+	handlertypes "github.com/replicatedhq/kots/pkg/api/handlers/types"
+	apptypes "github.com/replicatedhq/kots/pkg/app/types"
+	"github.com/replicatedhq/kots/pkg/kotsutil"
+	"github.com/replicatedhq/kots/pkg/kustomize"
+	"github.com/replicatedhq/kots/pkg/store"
+)
+
+// PreviewAppConfig renders the manifests for proposed config values and returns the diff against the manifests of
+// the version without creating a new version
+func (h *Handler) PreviewAppConfig(w http.ResponseWriter, r *http.Request) {
+	request := handlertypes.PreviewAppConfigRequest{}
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		BadRequestJSON(w, r, "failed to decode request body", err)
+		return
+	}
+
+	foundApp, err := store.GetStore().GetAppFromSlug(mux.Vars(r)["appSlug"])
+	if err != nil {
+		NotFoundJSON(w, r, "failed to get app from slug", err)
+		return
+	}
+
+	sequence := request.Sequence
+	configGroups := request.ConfigGroups
+	if len(request.ConfigValues) > 0 {
+		newConfigValues, err := decodeConfigValues(request.ConfigValues)
+		if err != nil {
+			BadRequestJSON(w, r, "failed to decode config values", err)
+			return
+		}
+
+		sequence = foundApp.CurrentSequence
+		configGroups, err = configGroupsForValues(foundApp, newConfigValues, request.Merge)
+		if err != nil {
+			InternalErrorJSON(w, r, "failed to get config groups for values", err)
+			return
+		}
+	}
+
+	diff, patch, err := previewConfigGroups(foundApp, sequence, configGroups)
+	if err != nil {
+		if invalidErr, ok := errors.Cause(err).(invalidItemsError); ok {
+			BadRequestJSON(w, r, invalidErr.message, nil)
+			return
+		}
+		InternalErrorJSON(w, r, "failed to preview config", err)
+		return
+	}
+
+	JSON(w, http.StatusOK, handlertypes.PreviewAppConfigResponse{
+		Sequence:     sequence,
+		FilesChanged: diff.FilesChanged,
+		LinesAdded:   diff.LinesAdded,
+		LinesRemoved: diff.LinesRemoved,
+		Diff:         patch,
+	})
+}
+
+// invalidItemsError is returned when required items are not set or items gated by the license are changed
+type invalidItemsError struct {
+	message string
+}
+
+func (e invalidItemsError) Error() string {
+	return e.message
+}
+
+// previewConfigGroups renders the version with the values of the config groups in a temp dir and diffs the
+// manifests against the manifests of the stored version
+func previewConfigGroups(foundApp *apptypes.App, sequence int64, configGroups []kotsv1beta1.ConfigGroup) (*kustomize.Diff, string, error) {
+	baseDir, err := ioutil.TempDir("", "kotsadm")
+	if err != nil {
+		return nil, "", errors.Wrap(err, "failed to create temp dir")
+	}
+	defer os.RemoveAll(baseDir)
+
+	if err := store.GetStore().GetAppVersionArchive(foundApp.ID, sequence, baseDir); err != nil {
+		return nil, "", errors.Wrap(err, "failed to get app version archive")
+	}
+
+	archiveDir, err := ioutil.TempDir("", "kotsadm")
+	if err != nil {
+		return nil, "", errors.Wrap(err, "failed to create temp dir")
+	}
+	defer os.RemoveAll(archiveDir)
+
+	if err := store.GetStore().GetAppVersionArchive(foundApp.ID, sequence, archiveDir); err != nil {
+		return nil, "", errors.Wrap(err, "failed to get app version archive")
+	}
+
+	isPrimaryVersion := true
+	_, resp, err := renderConfigGroups(foundApp, sequence, archiveDir, configGroups, isPrimaryVersion)
+	if err != nil {
+		return nil, "", errors.Wrap(err, resp.Error)
+	}
+	if len(resp.RequiredItems) > 0 || len(resp.GatedItems) > 0 {
+		return nil, "", invalidItemsError{message: resp.Error}
+	}
+
+	kotsKinds, err := kotsutil.LoadKotsKindsFromPath(archiveDir)
+	if err != nil {
+		return nil, "", errors.Wrap(err, "failed to load kots kinds from path")
+	}
+
+	downstreams, err := store.GetStore().ListDownstreamsForApp(foundApp.ID)
+	if err != nil {
+		return nil, "", errors.Wrap(err, "failed to list downstreams for app")
+	}
+	if len(downstreams) == 0 {
+		return nil, "", errors.New("app has no downstreams")
+	}
+
+	// there's only ever one downstream
+	diff, patch, err := kustomize.UnifiedDiffAppVersionsForDownstream(downstreams[0].Name, archiveDir, baseDir, kotsKinds.KustomizeVersion())
+	if err != nil {
+		return nil, "", errors.Wrap(err, "failed to diff rendered manifests")
+	}
+
+	return diff, patch, nil
+}
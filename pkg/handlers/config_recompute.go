@@ -0,0 +1,137 @@
+package handlers
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"sort"
+
+	"github.com/gorilla/mux"
+	kotsv1beta1 "github.com/replicatedhq/kots/kotskinds/apis/kots/v1beta1"
+	handlertypes "github.com/replicatedhq/kots/pkg/api/handlers/types"
+	"github.com/replicatedhq/kots/pkg/kotsutil"
+	"github.com/replicatedhq/kots/pkg/store"
+	"github.com/replicatedhq/kots/pkg/template"
+)
+
+// RecomputeAppConfig returns the values of the config items that are derived from the changed items, such as read
+// only items templated from them. Only the dependents of the changed items are built, not the whole config.
+func (h *Handler) RecomputeAppConfig(w http.ResponseWriter, r *http.Request) {
+	request := handlertypes.RecomputeAppConfigRequest{}
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		BadRequestJSON(w, r, "failed to decode request body", err)
+		return
+	}
+
+	foundApp, err := store.GetStore().GetAppFromSlug(mux.Vars(r)["appSlug"])
+	if err != nil {
+		NotFoundJSON(w, r, "failed to get app from slug", err)
+		return
+	}
+
+	var newConfigValues *kotsv1beta1.ConfigValues
+	sequence := request.Sequence
+	if len(request.ConfigValues) > 0 {
+		newConfigValues, err = decodeConfigValues(request.ConfigValues)
+		if err != nil {
+			BadRequestJSON(w, r, "failed to decode config values", err)
+			return
+		}
+		sequence = foundApp.CurrentSequence
+	}
+
+	archiveDir, err := ioutil.TempDir("", "kotsadm")
+	if err != nil {
+		InternalErrorJSON(w, r, "failed to create temp dir", err)
+		return
+	}
+	defer os.RemoveAll(archiveDir)
+
+	if err := store.GetStore().GetAppVersionArchive(foundApp.ID, sequence, archiveDir); err != nil {
+		InternalErrorJSON(w, r, "failed to get app version archive", err)
+		return
+	}
+
+	kotsKinds, err := kotsutil.LoadKotsKindsFromPath(archiveDir)
+	if err != nil {
+		InternalErrorJSON(w, r, "failed to load kots kinds from path", err)
+		return
+	}
+	if kotsKinds.Config == nil {
+		BadRequestJSON(w, r, "the app does not have a config", nil)
+		return
+	}
+
+	changedItems := request.ChangedItems
+	configValues := map[string]template.ItemValue{}
+	if newConfigValues != nil {
+		if request.Merge {
+			if err := kotsKinds.DecryptConfigValues(); err != nil {
+				InternalErrorJSON(w, r, "failed to decrypt existing values", err)
+				return
+			}
+			if kotsKinds.ConfigValues != nil {
+				for key, value := range kotsKinds.ConfigValues.Spec.Values {
+					configValues[key] = template.ItemValue{Default: value.Default, Value: value.Value}
+				}
+			}
+		}
+		for key, value := range newConfigValues.Spec.Values {
+			configValues[key] = template.ItemValue{Default: value.Default, Value: value.Value}
+			if len(request.ChangedItems) == 0 {
+				changedItems = append(changedItems, key)
+			}
+		}
+	} else {
+		configValues = itemValuesFromConfigGroups(request.ConfigGroups)
+	}
+
+	registryInfo, err := store.GetStore().GetRegistryDetailsForApp(foundApp.ID)
+	if err != nil {
+		InternalErrorJSON(w, r, "failed to get app registry info", err)
+		return
+	}
+
+	appLicense, err := store.GetStore().GetLatestLicenseForApp(foundApp.ID)
+	if err != nil {
+		InternalErrorJSON(w, r, "failed to get license for app", err)
+		return
+	}
+
+	versionInfo := template.VersionInfoFromInstallation(sequence+1, foundApp.IsAirgap, kotsKinds.Installation.Spec) // sequence +1 because the sequence will be incremented on save
+	recomputed, err := template.RecomputeConfigValues(template.BuilderOptions{
+		ConfigGroups:   kotsKinds.Config.Spec.Groups,
+		ExistingValues: configValues,
+		LocalRegistry: template.LocalRegistry{
+			Host:      registryInfo.Hostname,
+			Namespace: registryInfo.Namespace,
+			Username:  registryInfo.Username,
+			Password:  registryInfo.Password,
+			ReadOnly:  registryInfo.IsReadOnly,
+		},
+		License:     appLicense,
+		VersionInfo: &versionInfo,
+	}, changedItems)
+	if err != nil {
+		BadRequestJSON(w, r, "failed to recompute config values", err)
+		return
+	}
+
+	items := []handlertypes.RecomputedConfigItem{}
+	for name, value := range recomputed {
+		items = append(items, handlertypes.RecomputedConfigItem{
+			Name:    name,
+			Value:   value.ValueStr(),
+			Default: value.DefaultStr(),
+		})
+	}
+	sort.Slice(items, func(i, j int) bool {
+		return items[i].Name < items[j].Name
+	})
+
+	JSON(w, http.StatusOK, handlertypes.RecomputeAppConfigResponse{
+		Sequence: sequence,
+		Items:    items,
+	})
+}
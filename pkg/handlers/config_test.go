@@ -0,0 +1,56 @@
+package handlers
+
+import (
+	"encoding/base64"
+	"testing"
+
+	kotsv1beta1 "github.com/replicatedhq/kots/kotskinds/apis/kots/v1beta1"
+	"github.com/replicatedhq/kots/pkg/crypto"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_changedLicenseGatedItems(t *testing.T) {
+	req := require.New(t)
+
+	cipher, err := crypto.NewAESCipher()
+	req.NoError(err)
+	encrypt := func(value string) string {
+		return base64.StdEncoding.EncodeToString(cipher.Encrypt([]byte(value)))
+	}
+
+	configGroups := []kotsv1beta1.ConfigGroup{
+		{
+			Name: "features",
+			Items: []kotsv1beta1.ConfigItem{
+				{Name: "hostname", Type: "text"},
+				{Name: "sso_url", Type: "text"},
+				{Name: "sso_secret", Type: "password"},
+				{Name: "audit_log", Type: "bool"},
+			},
+		},
+	}
+	gatedItems := []string{"sso_url", "sso_secret", "audit_log"}
+
+	existingValues := map[string]kotsv1beta1.ConfigValue{
+		"hostname":   {Value: "example.com"},
+		"sso_url":    {Value: "https://sso.example.com"},
+		"sso_secret": {Value: encrypt("secret")},
+	}
+
+	// kept, re-encrypted and cleared values of gated items are allowed, as are changes to other items
+	newValues := map[string]kotsv1beta1.ConfigValue{
+		"hostname":   {Value: "other.example.com"},
+		"sso_url":    {Value: "https://sso.example.com"},
+		"sso_secret": {Value: encrypt("secret")},
+		"audit_log":  {Value: ""},
+	}
+	req.Empty(changedLicenseGatedItems(gatedItems, configGroups, existingValues, newValues, cipher))
+
+	newValues = map[string]kotsv1beta1.ConfigValue{
+		"hostname":   {Value: "example.com"},
+		"sso_url":    {Value: "https://other.example.com"},
+		"sso_secret": {Value: encrypt("other")},
+		"audit_log":  {Value: "1"},
+	}
+	req.Equal([]string{"sso_url", "sso_secret", "audit_log"}, changedLicenseGatedItems(gatedItems, configGroups, existingValues, newValues, cipher))
+}
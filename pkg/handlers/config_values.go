@@ -0,0 +1,90 @@
+package handlers
+
+import (
+	"io/ioutil"
+	"net/http"
+	"os"
+	"strconv"
+
+	"github.com/gorilla/mux"
+	handlertypes "github.com/replicatedhq/kots/pkg/api/handlers/types"
+	"github.com/replicatedhq/kots/pkg/kotsutil"
+	"github.com/replicatedhq/kots/pkg/logger"
+	"github.com/replicatedhq/kots/pkg/store"
+	"go.uber.org/zap"
+)
+
+// GetAppConfigValues returns the config values of an app version as they are stored, with the values of
+// password items encrypted. The "sequence" query param defaults to the current sequence of the app.
+func (h *Handler) GetAppConfigValues(w http.ResponseWriter, r *http.Request) {
+	getAppConfigValues(w, r, false)
+}
+
+// GetAppConfigValuesDecrypted returns the config values of an app version with the values of password items
+// decrypted, to back them up or move them to another install. Every request is audit logged.
+func (h *Handler) GetAppConfigValuesDecrypted(w http.ResponseWriter, r *http.Request) {
+	getAppConfigValues(w, r, true)
+}
+
+func getAppConfigValues(w http.ResponseWriter, r *http.Request, decrypt bool) {
+	foundApp, err := store.GetStore().GetAppFromSlug(mux.Vars(r)["appSlug"])
+	if err != nil {
+		NotFoundJSON(w, r, "failed to get app from slug", err)
+		return
+	}
+
+	sequence := foundApp.CurrentSequence
+	if s := r.URL.Query().Get("sequence"); s != "" {
+		sequence, err = strconv.ParseInt(s, 10, 64)
+		if err != nil {
+			BadRequestJSON(w, r, "invalid sequence", err)
+			return
+		}
+	}
+
+	archiveDir, err := ioutil.TempDir("", "kotsadm")
+	if err != nil {
+		InternalErrorJSON(w, r, "failed to create temp dir", err)
+		return
+	}
+	defer os.RemoveAll(archiveDir)
+
+	if err := store.GetStore().GetAppVersionArchive(foundApp.ID, sequence, archiveDir); err != nil {
+		NotFoundJSON(w, r, "failed to get app version archive", err)
+		return
+	}
+
+	kotsKinds, err := kotsutil.LoadKotsKindsFromPath(archiveDir)
+	if err != nil {
+		InternalErrorJSON(w, r, "failed to load kots kinds", err)
+		return
+	}
+	if kotsKinds.ConfigValues == nil {
+		NotFoundJSON(w, r, "app version has no config values", nil)
+		return
+	}
+
+	if decrypt {
+		logger.Info("decrypted config values retrieved",
+			zap.String("audit", "config-values"),
+			zap.String("appID", foundApp.ID),
+			zap.Int64("sequence", sequence),
+			zap.String("user", sessionUserID(r)))
+
+		if err := kotsKinds.DecryptConfigValues(); err != nil {
+			InternalErrorJSON(w, r, "failed to decrypt config values", err)
+			return
+		}
+	}
+
+	configValues, err := kotsKinds.Marshal("kots.io", "v1beta1", "ConfigValues")
+	if err != nil {
+		InternalErrorJSON(w, r, "failed to marshal config values", err)
+		return
+	}
+
+	JSON(w, http.StatusOK, handlertypes.GetAppConfigValuesResponse{
+		Sequence:     sequence,
+		ConfigValues: configValues,
+	})
+}
@@ -1,83 +1,189 @@
 package handlers
 
 import (
-	"io/ioutil"
+	"io"
 	"net/http"
 	"os"
-	"path/filepath"
 	"strconv"
-	"strings"
 
 	"github.com/gorilla/mux"
-	"github.com/replicatedhq/kots/pkg/logger"
+	"github.com/pkg/errors"
+	"github.com/replicatedhq/kots/pkg/kotsutil"
 	"github.com/replicatedhq/kots/pkg/store"
 )
 
 type GetAppContentsResponse struct {
 	Files map[string][]byte `json:"files"`
+	// Total is the number of files in the archive, of which Files is a page when a limit is set
+	Total   int  `json:"total"`
+	HasMore bool `json:"hasMore"`
 }
 
+type ListAppContentsDirResponse struct {
+	Entries []kotsutil.ArchiveEntry `json:"entries"`
+	Total   int                     `json:"total"`
+	HasMore bool                    `json:"hasMore"`
+}
+
+type GetAppContentsFileResponse struct {
+	Path    string `json:"path"`
+	Content []byte `json:"content"`
+}
+
+// GetAppContents returns the files of an app version archive. The offset and limit query params return a page of
+// the files in archive order, all files are returned if limit is not set.
 func (h *Handler) GetAppContents(w http.ResponseWriter, r *http.Request) {
-	appSlug := mux.Vars(r)["appSlug"]
-	sequence, err := strconv.Atoi(mux.Vars(r)["sequence"])
+	offset, limit, err := getPagination(r)
 	if err != nil {
-		logger.Error(err)
-		w.WriteHeader(500)
+		BadRequestJSON(w, r, "invalid pagination", err)
 		return
 	}
 
-	a, err := store.GetStore().GetAppFromSlug(appSlug)
-	if err != nil {
-		logger.Error(err)
-		w.WriteHeader(500)
+	archive, ok := getAppContentsArchive(w, r)
+	if !ok {
 		return
 	}
+	defer archive.Close()
 
-	archivePath, err := ioutil.TempDir("", "kotsadm")
+	files, total, err := kotsutil.ReadArchiveFiles(archive, offset, limit)
 	if err != nil {
-		logger.Error(err)
-		w.WriteHeader(500)
+		InternalErrorJSON(w, r, "failed to read app version archive", err)
 		return
 	}
-	defer os.RemoveAll(archivePath)
 
-	err = store.GetStore().GetAppVersionArchive(a.ID, int64(sequence), archivePath)
+	// paths have a leading slash, as when the archive was extracted to walk it
+	archiveFiles := map[string][]byte{}
+	for path, contents := range files {
+		archiveFiles["/"+path] = contents
+	}
+
+	getAppContentsResponse := GetAppContentsResponse{
+		Files:   archiveFiles,
+		Total:   total,
+		HasMore: limit > 0 && offset+limit < total,
+	}
+
+	JSON(w, 200, getAppContentsResponse)
+}
+
+// ListAppContentsDir returns the files and directories directly in the directory in the path query param, without
+// their contents, so that the file tree can be loaded one directory at a time
+func (h *Handler) ListAppContentsDir(w http.ResponseWriter, r *http.Request) {
+	offset, limit, err := getPagination(r)
 	if err != nil {
-		logger.Error(err)
-		w.WriteHeader(500)
+		BadRequestJSON(w, r, "invalid pagination", err)
 		return
 	}
 
-	// walk the parth, adding all to the files map
-	// base64 decode these
-	archiveFiles := map[string][]byte{}
+	archive, ok := getAppContentsArchive(w, r)
+	if !ok {
+		return
+	}
+	defer archive.Close()
 
-	err = filepath.Walk(archivePath, func(path string, info os.FileInfo, err error) error {
-		if err != nil {
-			return err
-		}
+	entries, err := kotsutil.ListArchiveDir(archive, r.URL.Query().Get("path"))
+	if os.IsNotExist(err) {
+		NotFoundJSON(w, r, "directory not found", err)
+		return
+	} else if err != nil {
+		InternalErrorJSON(w, r, "failed to list app version archive", err)
+		return
+	}
 
-		if info.IsDir() {
-			return nil
-		}
+	total := len(entries)
+	if offset > total {
+		offset = total
+	}
+	end := total
+	if limit > 0 && offset+limit < total {
+		end = offset + limit
+	}
 
-		contents, err := ioutil.ReadFile(path)
-		if err != nil {
-			return err
-		}
+	listAppContentsDirResponse := ListAppContentsDirResponse{
+		Entries: entries[offset:end],
+		Total:   total,
+		HasMore: end < total,
+	}
 
-		archiveFiles[strings.TrimPrefix(path, archivePath)] = contents
-		return nil
-	})
-	if err != nil {
-		logger.Error(err)
-		w.WriteHeader(500)
+	JSON(w, 200, listAppContentsDirResponse)
+}
+
+// GetAppContentsFile returns a single file of an app version archive
+func (h *Handler) GetAppContentsFile(w http.ResponseWriter, r *http.Request) {
+	path := r.URL.Query().Get("path")
+	if path == "" {
+		BadRequestJSON(w, r, "path is required", nil)
 		return
 	}
 
-	getAppContentsResponse := GetAppContentsResponse{
-		Files: archiveFiles,
+	archive, ok := getAppContentsArchive(w, r)
+	if !ok {
+		return
 	}
+	defer archive.Close()
 
-	JSON(w, 200, getAppContentsResponse)
+	content, err := kotsutil.ReadFileFromArchive(archive, path)
+	if os.IsNotExist(err) {
+		NotFoundJSON(w, r, "file not found", err)
+		return
+	} else if err != nil {
+		InternalErrorJSON(w, r, "failed to read app version archive", err)
+		return
+	}
+
+	getAppContentsFileResponse := GetAppContentsFileResponse{
+		Path:    path,
+		Content: content,
+	}
+
+	JSON(w, 200, getAppContentsFileResponse)
+}
+
+// getAppContentsArchive returns the archive stream of the app version in the request vars. The error response is
+// written if it can't be opened.
+func getAppContentsArchive(w http.ResponseWriter, r *http.Request) (io.ReadCloser, bool) {
+	appSlug := mux.Vars(r)["appSlug"]
+	sequence, err := strconv.ParseInt(mux.Vars(r)["sequence"], 10, 64)
+	if err != nil {
+		BadRequestJSON(w, r, "invalid sequence", err)
+		return nil, false
+	}
+
+	a, err := store.GetStore().GetAppFromSlug(appSlug)
+	if err != nil {
+		if store.GetStore().IsNotFound(err) {
+			NotFoundJSON(w, r, "app not found", err)
+			return nil, false
+		}
+		InternalErrorJSON(w, r, "failed to get app", err)
+		return nil, false
+	}
+
+	archive, err := store.GetReadStore().GetAppVersionArchiveReader(a.ID, sequence)
+	if err != nil {
+		InternalErrorJSON(w, r, "failed to get app version archive", err)
+		return nil, false
+	}
+
+	return archive, true
+}
+
+// getPagination parses the offset and limit query params. A limit of 0 means no limit.
+func getPagination(r *http.Request) (int, int, error) {
+	offset, limit := 0, 0
+	if s := r.URL.Query().Get("offset"); s != "" {
+		o, err := strconv.Atoi(s)
+		if err != nil || o < 0 {
+			return 0, 0, errors.Errorf("invalid offset %q", s)
+		}
+		offset = o
+	}
+	if s := r.URL.Query().Get("limit"); s != "" {
+		l, err := strconv.Atoi(s)
+		if err != nil || l < 0 {
+			return 0, 0, errors.Errorf("invalid limit %q", s)
+		}
+		limit = l
+	}
+	return offset, limit, nil
 }
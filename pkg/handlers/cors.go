@@ -2,13 +2,33 @@ package handlers
 
 import (
 	"net/http"
+
+	"github.com/replicatedhq/kots/pkg/cors"
 )
 
+// corsAllowedOrigins are the origins allowed to make cross-origin requests, all origins are allowed if it's empty
+var corsAllowedOrigins []string
+
+// SetCORSAllowedOrigins restricts cross-origin requests to the origins in the list, see cors.ParseOrigins
+func SetCORSAllowedOrigins(origins []string) {
+	corsAllowedOrigins = origins
+}
+
 func CORS(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Access-Control-Allow-Methods", "GET, POST, OPTIONS, DELETE, PUT")
-	w.Header().Set("Access-Control-Allow-Origin", "*")
-	w.Header().Set("Access-Control-Allow-Headers", "content-type, origin, accept, authorization")
-	w.Header().Set("Access-Control-Expose-Headers", "content-disposition")
+	w.Header().Set("Access-Control-Allow-Headers", "content-type, origin, accept, authorization, x-csrf-token, x-request-id")
+	w.Header().Set("Access-Control-Expose-Headers", "content-disposition, x-csrf-token, x-request-id")
+
+	if len(corsAllowedOrigins) == 0 {
+		w.Header().Set("Access-Control-Allow-Origin", "*")
+		return
+	}
+
+	// the response depends on the origin when only some origins are allowed, caches must not share it
+	w.Header().Add("Vary", "Origin")
+	if origin := r.Header.Get("Origin"); origin != "" && cors.IsAllowedOrigin(origin, corsAllowedOrigins) {
+		w.Header().Set("Access-Control-Allow-Origin", origin)
+	}
 }
 
 func handleOptionsRequest(w http.ResponseWriter, r *http.Request) (isOptionsRequest bool) {
@@ -0,0 +1,44 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/pkg/errors"
+	handlertypes "github.com/replicatedhq/kots/pkg/api/handlers/types"
+	"github.com/replicatedhq/kots/pkg/session"
+	"github.com/replicatedhq/kots/pkg/session/types"
+)
+
+// CSRFTokenHeader carries the csrf token of the session. It is returned on every authenticated response and must be
+// sent back on mutating requests made by a browser.
+const CSRFTokenHeader = "X-Csrf-Token"
+
+// requireCSRFToken rejects mutating requests from browsers that don't have the csrf token of the session. Requests
+// from the kots cli and other non-browser clients don't send the Origin or Sec-Fetch-Site headers and are not checked.
+func requireCSRFToken(w http.ResponseWriter, r *http.Request, sess *types.Session) error {
+	w.Header().Set(CSRFTokenHeader, session.CSRFToken(sess))
+
+	if sess.ID == session.CLISessionID || !isMutatingRequest(r) || !isBrowserRequest(r) {
+		return nil
+	}
+
+	if !session.ValidCSRFToken(sess, r.Header.Get(CSRFTokenHeader)) {
+		err := errors.New("missing or invalid csrf token")
+		ErrorJSON(w, r, http.StatusForbidden, handlertypes.ErrorCodeInvalidCSRFToken, err.Error(), nil)
+		return err
+	}
+
+	return nil
+}
+
+func isMutatingRequest(r *http.Request) bool {
+	switch r.Method {
+	case "POST", "PUT", "PATCH", "DELETE":
+		return true
+	}
+	return false
+}
+
+func isBrowserRequest(r *http.Request) bool {
+	return r.Header.Get("Origin") != "" || r.Header.Get("Sec-Fetch-Site") != ""
+}
@@ -0,0 +1,137 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/replicatedhq/kots/pkg/session"
+	"github.com/replicatedhq/kots/pkg/session/types"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_requireCSRFToken(t *testing.T) {
+	sess := &types.Session{ID: "session-id"}
+	token := session.CSRFToken(sess)
+
+	tests := []struct {
+		name    string
+		method  string
+		headers map[string]string
+		sess    *types.Session
+		wantErr bool
+	}{
+		{
+			name:   "browser read",
+			method: "GET",
+			headers: map[string]string{
+				"Origin": "https://portal.example.com",
+			},
+			sess: sess,
+		},
+		{
+			name:   "browser write without token",
+			method: "POST",
+			headers: map[string]string{
+				"Sec-Fetch-Site": "same-origin",
+			},
+			sess:    sess,
+			wantErr: true,
+		},
+		{
+			name:   "browser write with wrong token",
+			method: "PUT",
+			headers: map[string]string{
+				"Origin":        "https://portal.example.com",
+				CSRFTokenHeader: session.CSRFToken(&types.Session{ID: "other-session-id"}),
+			},
+			sess:    sess,
+			wantErr: true,
+		},
+		{
+			name:   "browser write with token",
+			method: "DELETE",
+			headers: map[string]string{
+				"Origin":        "https://portal.example.com",
+				CSRFTokenHeader: token,
+			},
+			sess: sess,
+		},
+		{
+			name:   "non-browser write",
+			method: "POST",
+			sess:   sess,
+		},
+		{
+			name:   "kots cli",
+			method: "POST",
+			headers: map[string]string{
+				"Origin": "https://portal.example.com",
+			},
+			sess: &types.Session{ID: session.CLISessionID},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			req := require.New(t)
+
+			r := httptest.NewRequest(test.method, "/api/v1/apps", nil)
+			for k, v := range test.headers {
+				r.Header.Set(k, v)
+			}
+			w := httptest.NewRecorder()
+
+			err := requireCSRFToken(w, r, test.sess)
+			req.Equal(session.CSRFToken(test.sess), w.Header().Get(CSRFTokenHeader))
+			if test.wantErr {
+				req.Error(err)
+				req.Equal(http.StatusForbidden, w.Code)
+				return
+			}
+			req.NoError(err)
+		})
+	}
+}
+
+func Test_CORS(t *testing.T) {
+	defer SetCORSAllowedOrigins(nil)
+
+	tests := []struct {
+		name       string
+		allowed    []string
+		origin     string
+		wantOrigin string
+	}{
+		{
+			name:       "all origins allowed by default",
+			origin:     "https://portal.example.com",
+			wantOrigin: "*",
+		},
+		{
+			name:       "allowed origin",
+			allowed:    []string{"https://*.example.com"},
+			origin:     "https://portal.example.com",
+			wantOrigin: "https://portal.example.com",
+		},
+		{
+			name:       "origin not allowed",
+			allowed:    []string{"https://*.example.com"},
+			origin:     "https://portal.example.org",
+			wantOrigin: "",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			SetCORSAllowedOrigins(test.allowed)
+
+			r := httptest.NewRequest("OPTIONS", "/api/v1/apps", nil)
+			r.Header.Set("Origin", test.origin)
+			w := httptest.NewRecorder()
+
+			require.True(t, handleOptionsRequest(w, r))
+			require.Equal(t, test.wantOrigin, w.Header().Get("Access-Control-Allow-Origin"))
+		})
+	}
+}
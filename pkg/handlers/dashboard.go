@@ -2,12 +2,13 @@ package handlers
 
 import (
 	"net/http"
-	"os"
+	"strconv"
 
 	"github.com/gorilla/mux"
 	"github.com/pkg/errors"
 	appstatustypes "github.com/replicatedhq/kots/pkg/api/appstatus/types"
 	"github.com/replicatedhq/kots/pkg/logger"
+	"github.com/replicatedhq/kots/pkg/prometheus"
 	"github.com/replicatedhq/kots/pkg/store"
 	"github.com/replicatedhq/kots/pkg/version"
 )
@@ -43,17 +44,14 @@ func (h *Handler) GetAppDashboard(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	prometheusAddress, err := store.GetStore().GetPrometheusAddress()
+	prometheusSettings, err := prometheus.GetSettings()
 	if err != nil {
 		logger.Error(err)
 		w.WriteHeader(500)
 		return
 	}
-	if prometheusAddress == "" {
-		prometheusAddress = os.Getenv("PROMETHEUS_ADDRESS")
-	}
 
-	metrics, err := version.GetMetricCharts(a.ID, parentSequence, prometheusAddress)
+	metrics, err := version.GetMetricCharts(a.ID, parentSequence, *prometheusSettings)
 	if err != nil {
 		logger.Error(errors.Wrap(err, "failed to get metric charts"))
 		metrics = []version.MetricChart{}
@@ -62,8 +60,77 @@ func (h *Handler) GetAppDashboard(w http.ResponseWriter, r *http.Request) {
 	getAppDashboardResponse := GetAppDashboardResponse{
 		AppStatus:         appStatus,
 		Metrics:           metrics,
-		PrometheusAddress: prometheusAddress,
+		PrometheusAddress: prometheusSettings.Address,
 	}
 
 	JSON(w, 200, getAppDashboardResponse)
 }
+
+type GetAppMetricChartResponse struct {
+	Chart *version.MetricChart `json:"chart"`
+}
+
+// GetAppMetricChart proxies a range query for one of the graphs declared in the application spec of the deployed version.
+// The graph is selected by its index, arbitrary queries are not accepted.
+func (h *Handler) GetAppMetricChart(w http.ResponseWriter, r *http.Request) {
+	graphIndex, err := strconv.Atoi(mux.Vars(r)["graphIndex"])
+	if err != nil {
+		BadRequestJSON(w, r, "failed to parse graph index", err)
+		return
+	}
+
+	start, err := strconv.ParseUint(r.URL.Query().Get("start"), 10, 64)
+	if err != nil {
+		BadRequestJSON(w, r, "failed to parse start", err)
+		return
+	}
+	end, err := strconv.ParseUint(r.URL.Query().Get("end"), 10, 64)
+	if err != nil {
+		BadRequestJSON(w, r, "failed to parse end", err)
+		return
+	}
+	step := uint64(0)
+	if s := r.URL.Query().Get("step"); s != "" {
+		step, err = strconv.ParseUint(s, 10, 64)
+		if err != nil {
+			BadRequestJSON(w, r, "failed to parse step", err)
+			return
+		}
+	}
+
+	a, err := store.GetStore().GetAppFromSlug(mux.Vars(r)["appSlug"])
+	if err != nil {
+		NotFoundJSON(w, r, "failed to get app from slug", err)
+		return
+	}
+
+	parentSequence, err := store.GetStore().GetCurrentParentSequence(a.ID, mux.Vars(r)["clusterId"])
+	if err != nil {
+		InternalErrorJSON(w, r, "failed to get current parent sequence", err)
+		return
+	}
+
+	prometheusSettings, err := prometheus.GetSettings()
+	if err != nil {
+		InternalErrorJSON(w, r, "failed to get prometheus settings", err)
+		return
+	}
+	if prometheusSettings.Address == "" {
+		NotFoundJSON(w, r, "prometheus address is not configured", errors.New("prometheus address is not configured"))
+		return
+	}
+
+	chart, err := version.GetMetricChart(a.ID, parentSequence, *prometheusSettings, graphIndex, uint(start), uint(end), uint(step))
+	if err != nil {
+		if errors.Cause(err) == version.ErrGraphNotFound {
+			NotFoundJSON(w, r, "graph not found", err)
+			return
+		}
+		BadRequestJSON(w, r, "failed to get metric chart", err)
+		return
+	}
+
+	JSON(w, http.StatusOK, GetAppMetricChartResponse{
+		Chart: chart,
+	})
+}
@@ -1,7 +1,9 @@
 package handlers
 
 import (
+	"context"
 	"encoding/json"
+	"fmt"
 	"io/ioutil"
 	"net/http"
 	"os"
@@ -11,12 +13,22 @@ import (
 	"github.com/gorilla/mux"
 	"github.com/pkg/errors"
 	downstreamtypes "github.com/replicatedhq/kots/pkg/api/downstream/types"
+	handlertypes "github.com/replicatedhq/kots/pkg/api/handlers/types"
 	"github.com/replicatedhq/kots/pkg/app"
 	apptypes "github.com/replicatedhq/kots/pkg/app/types"
+	"github.com/replicatedhq/kots/pkg/canary"
+	"github.com/replicatedhq/kots/pkg/deployapproval"
+	deployapprovaltypes "github.com/replicatedhq/kots/pkg/deployapproval/types"
+	deployhistorytypes "github.com/replicatedhq/kots/pkg/deployhistory/types"
+	"github.com/replicatedhq/kots/pkg/downstream"
+	"github.com/replicatedhq/kots/pkg/freeze"
 	"github.com/replicatedhq/kots/pkg/kotsutil"
 	"github.com/replicatedhq/kots/pkg/logger"
+	"github.com/replicatedhq/kots/pkg/postdeploytest"
+	"github.com/replicatedhq/kots/pkg/preflight"
 	"github.com/replicatedhq/kots/pkg/redact"
 	"github.com/replicatedhq/kots/pkg/reporting"
+	"github.com/replicatedhq/kots/pkg/socketservice"
 	"github.com/replicatedhq/kots/pkg/store"
 	"github.com/replicatedhq/kots/pkg/supportbundle"
 	"github.com/replicatedhq/kots/pkg/version"
@@ -31,6 +43,8 @@ type UpdateDeployResultRequest struct {
 	ApplyStdout  string `json:"applyStdout"`
 	ApplyStderr  string `json:"applyStderr"`
 	RenderError  string `json:"renderError"`
+	// RequestID is the id of the request that triggered the deploy, as sent to the operator
+	RequestID string `json:"requestId,omitempty"`
 }
 
 type UpdateUndeployResultRequest struct {
@@ -42,6 +56,13 @@ type DeployAppVersionRequest struct {
 	IsSkipPreflights             bool `json:"isSkipPreflights"`
 	ContinueWithFailedPreflights bool `json:"continueWithFailedPreflights"`
 	IsCLI                        bool `json:"isCli"`
+	// RequirePassingPreflights fails the deploy if the preflight checks of the version failed or have not completed
+	RequirePassingPreflights bool `json:"requirePassingPreflights"`
+}
+
+type DeployAppVersionResponse struct {
+	// DeployApproval is the pending approval to deploy the version, for apps that require deploy approval
+	DeployApproval *deployapprovaltypes.DeployApproval `json:"deployApproval,omitempty"`
 }
 
 func (h *Handler) DeployAppVersion(w http.ResponseWriter, r *http.Request) {
@@ -49,92 +70,160 @@ func (h *Handler) DeployAppVersion(w http.ResponseWriter, r *http.Request) {
 
 	request := DeployAppVersionRequest{}
 	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
-		logger.Error(err)
-		w.WriteHeader(400)
+		BadRequestJSON(w, r, "failed to decode request body", err)
 		return
 	}
 
 	sequence, err := strconv.Atoi(mux.Vars(r)["sequence"])
 	if err != nil {
-		logger.Error(err)
-		w.WriteHeader(http.StatusInternalServerError)
+		BadRequestJSON(w, r, "failed to parse sequence", err)
 		return
 	}
 
 	a, err := store.GetStore().GetAppFromSlug(appSlug)
 	if err != nil {
-		logger.Error(err)
-		w.WriteHeader(http.StatusInternalServerError)
+		InternalErrorJSON(w, r, "failed to get app from slug", err)
 		return
 	}
 
 	downstreams, err := store.GetStore().ListDownstreamsForApp(a.ID)
 	if err != nil {
-		err = errors.Wrap(err, "failed to list downstreams for app")
-		logger.Error(err)
-		w.WriteHeader(http.StatusInternalServerError)
+		InternalErrorJSON(w, r, "failed to list downstreams for app", err)
 		return
 	} else if len(downstreams) == 0 {
-		err = errors.New("no downstreams for app")
-		logger.Error(err)
-		w.WriteHeader(http.StatusInternalServerError)
+		InternalErrorJSON(w, r, "no downstreams for app", errors.New("no downstreams for app"))
 		return
 	}
 
-	if err := store.GetStore().DeleteDownstreamDeployStatus(a.ID, downstreams[0].ClusterID, int64(sequence)); err != nil {
-		logger.Error(err)
-		w.WriteHeader(http.StatusInternalServerError)
+	skippedVersion, err := version.GetSkippedRequiredVersion(a.ID, downstreams[0].ClusterID, int64(sequence))
+	if err != nil {
+		InternalErrorJSON(w, r, "failed to check for required versions", err)
+		return
+	}
+	if skippedVersion != nil {
+		message := fmt.Sprintf("Version %s is required and must be deployed before this version", skippedVersion.VersionLabel)
+		ErrorJSON(w, r, http.StatusConflict, handlertypes.ErrorCodeConflict, message, nil)
 		return
 	}
 
-	if err := version.DeployVersion(a.ID, int64(sequence)); err != nil {
-		logger.Error(err)
-		w.WriteHeader(http.StatusInternalServerError)
+	if err := version.CheckMinKotsVersion(a.ID, int64(sequence)); err != nil {
+		if _, ok := errors.Cause(err).(version.KotsUpgradeRequiredError); ok {
+			ErrorJSON(w, r, http.StatusConflict, handlertypes.ErrorCodeKotsUpgradeRequired, err.Error(), nil)
+			return
+		}
+		InternalErrorJSON(w, r, "failed to check minimum kots version", err)
 		return
 	}
 
+	if request.RequirePassingPreflights {
+		state, err := preflight.GetState(a.ID, int64(sequence))
+		if err != nil {
+			InternalErrorJSON(w, r, "failed to get preflight state", err)
+			return
+		}
+		if state == "" {
+			ErrorJSON(w, r, http.StatusConflict, handlertypes.ErrorCodeConflict, "The preflight checks of the version have not completed", nil)
+			return
+		}
+		if state == "fail" {
+			ErrorJSON(w, r, http.StatusConflict, handlertypes.ErrorCodePreflightsFailed, "The preflight checks of the version failed", nil)
+			return
+		}
+	}
+
+	if err := freeze.CheckDeploy(a.ID); err != nil {
+		deployFailedJSON(w, r, "failed to check deploy freeze", err)
+		return
+	}
+
+	if a.RequireDeployApproval {
+		approval, err := deployapproval.Request(a.ID, int64(sequence), deployapproval.RequestOptions{
+			RequestedBy:                  sessionUserID(r),
+			IsSkipPreflights:             request.IsSkipPreflights,
+			ContinueWithFailedPreflights: request.ContinueWithFailedPreflights,
+			IsCLI:                        request.IsCLI,
+		})
+		if err != nil {
+			InternalErrorJSON(w, r, "failed to request deploy approval", err)
+			return
+		}
+		JSON(w, http.StatusAccepted, DeployAppVersionResponse{DeployApproval: approval})
+		return
+	}
+
+	deployer := deployhistorytypes.Deployer{
+		DeployedBy:        sessionUserID(r),
+		SkippedPreflights: request.IsSkipPreflights,
+	}
+	if err := deployAppVersion(r.Context(), a.ID, downstreams[0].ClusterID, int64(sequence), request, deployer, GetRequestID(r)); err != nil {
+		deployFailedJSON(w, r, "failed to deploy version", err)
+		return
+	}
+
+	JSON(w, 204, "")
+}
+
+// deployAppVersion deploys the sequence and reports the preflight choices of the deploy request
+func deployAppVersion(ctx context.Context, appID string, clusterID string, sequence int64, request DeployAppVersionRequest, deployer deployhistorytypes.Deployer, requestID string) error {
+	// checked before the deploy status is deleted, so that a blocked deploy does not change it
+	if err := freeze.CheckDeploy(appID); err != nil {
+		return err
+	}
+
+	if err := store.GetStore().DeleteDownstreamDeployStatus(appID, clusterID, sequence); err != nil {
+		return errors.Wrap(err, "failed to delete downstream deploy status")
+	}
+
+	a, err := store.GetStore().GetApp(appID)
+	if err != nil {
+		return errors.Wrap(err, "failed to get app")
+	}
+
+	// recorded before deploying so that the deploy loop cannot pick up the version without it
+	socketservice.SetDeployRequestID(ctx, appID, sequence, requestID)
+
+	if err := canary.DeployVersion(a, sequence, deployer); err != nil {
+		return errors.Wrap(err, "failed to deploy version")
+	}
+
 	// preflights reports
 	go func() {
 		if request.IsSkipPreflights || request.ContinueWithFailedPreflights {
-			if err := reporting.ReportAppInfo(a.ID, int64(sequence), request.IsSkipPreflights, request.IsCLI); err != nil {
+			if err := reporting.ReportAppInfo(appID, sequence, request.IsSkipPreflights, request.IsCLI); err != nil {
 				logger.Debugf("failed to send preflights data to replicated app: %v", err)
 				return
 			}
 		}
 	}()
 
-	JSON(w, 204, "")
+	return nil
 }
 
 // NOTE: this uses special cluster authorization
 func (h *Handler) UpdateDeployResult(w http.ResponseWriter, r *http.Request) {
 	auth, err := parseClusterAuthorization(r.Header.Get("Authorization"))
 	if err != nil {
-		logger.Error(err)
-		w.WriteHeader(http.StatusForbidden)
+		ErrorJSON(w, r, http.StatusForbidden, handlertypes.ErrorCodeForbidden, "failed to parse cluster authorization", err)
 		return
 	}
 
 	clusterID, err := store.GetStore().GetClusterIDFromDeployToken(auth.Password)
 	if err != nil {
-		logger.Error(err)
-		w.WriteHeader(http.StatusForbidden)
+		ErrorJSON(w, r, http.StatusForbidden, handlertypes.ErrorCodeForbidden, "invalid deploy token", err)
 		return
 	}
 
 	updateDeployResultRequest := UpdateDeployResultRequest{}
 	err = json.NewDecoder(r.Body).Decode(&updateDeployResultRequest)
 	if err != nil {
-		logger.Error(err)
-		w.WriteHeader(http.StatusInternalServerError)
+		InternalErrorJSON(w, r, "failed to decode request body", err)
 		return
 	}
 
 	// sequence really should be passed down to operator and returned from it
 	currentSequence, err := store.GetStore().GetCurrentSequence(updateDeployResultRequest.AppID, clusterID)
 	if err != nil {
-		logger.Error(err)
-		w.WriteHeader(http.StatusInternalServerError)
+		InternalErrorJSON(w, r, "failed to get current sequence", err)
 		return
 	}
 
@@ -145,8 +234,7 @@ func (h *Handler) UpdateDeployResult(w http.ResponseWriter, r *http.Request) {
 
 	alreadySuccessful, err := store.GetStore().IsDownstreamDeploySuccessful(updateDeployResultRequest.AppID, clusterID, currentSequence)
 	if err != nil {
-		logger.Error(err)
-		w.WriteHeader(http.StatusInternalServerError)
+		InternalErrorJSON(w, r, "failed to check if deploy was already successful", err)
 		return
 	}
 
@@ -155,6 +243,18 @@ func (h *Handler) UpdateDeployResult(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	requestID := updateDeployResultRequest.RequestID
+	if requestID == "" {
+		// operators from before the request id was reported back
+		requestID = socketservice.GetDeployRequestID(updateDeployResultRequest.AppID, currentSequence)
+	}
+	logger.InfoFields("received deploy result",
+		logger.RequestID(requestID),
+		zap.String("app_id", updateDeployResultRequest.AppID),
+		zap.String("cluster_id", clusterID),
+		zap.Int64("sequence", currentSequence),
+		zap.Bool("is_error", updateDeployResultRequest.IsError))
+
 	downstreamOutput := downstreamtypes.DownstreamOutput{
 		DryrunStdout: updateDeployResultRequest.DryrunStdout,
 		DryrunStderr: updateDeployResultRequest.DryrunStderr,
@@ -164,11 +264,29 @@ func (h *Handler) UpdateDeployResult(w http.ResponseWriter, r *http.Request) {
 	}
 	err = store.GetStore().UpdateDownstreamDeployStatus(updateDeployResultRequest.AppID, clusterID, currentSequence, updateDeployResultRequest.IsError, downstreamOutput)
 	if err != nil {
-		logger.Error(err)
-		w.WriteHeader(http.StatusInternalServerError)
+		InternalErrorJSON(w, r, "failed to update downstream deploy status", err)
+		return
+	}
+
+	err = store.GetStore().SetDeployEventResult(updateDeployResultRequest.AppID, clusterID, currentSequence, updateDeployResultRequest.IsError)
+	if err != nil {
+		InternalErrorJSON(w, r, "failed to set deploy event result", err)
 		return
 	}
 
+	// the full output is kept as an artifact for post-incident review, but losing it should not fail the deploy
+	if err := downstream.SaveOutputArchive(updateDeployResultRequest.AppID, clusterID, currentSequence, downstreamOutput); err != nil {
+		logger.Error(errors.Wrapf(err, "failed to save output archive for sequence %d", currentSequence))
+	}
+
+	if !updateDeployResultRequest.IsError {
+		go func() {
+			if err := postdeploytest.Run(updateDeployResultRequest.AppID, clusterID, currentSequence); err != nil {
+				logger.Error(errors.Wrapf(err, "failed to run post deploy tests for sequence %d", currentSequence))
+			}
+		}()
+	}
+
 	w.WriteHeader(http.StatusOK)
 	return
 }
@@ -212,23 +330,20 @@ func createSupportBundleSpec(appID string, sequence int64, origin string, inClus
 func (h *Handler) UpdateUndeployResult(w http.ResponseWriter, r *http.Request) {
 	auth, err := parseClusterAuthorization(r.Header.Get("Authorization"))
 	if err != nil {
-		logger.Error(err)
-		w.WriteHeader(http.StatusForbidden)
+		ErrorJSON(w, r, http.StatusForbidden, handlertypes.ErrorCodeForbidden, "failed to parse cluster authorization", err)
 		return
 	}
 
 	_, err = store.GetStore().GetClusterIDFromDeployToken(auth.Password)
 	if err != nil {
-		logger.Error(err)
-		w.WriteHeader(http.StatusForbidden)
+		ErrorJSON(w, r, http.StatusForbidden, handlertypes.ErrorCodeForbidden, "invalid deploy token", err)
 		return
 	}
 
 	updateUndeployResultRequest := UpdateUndeployResultRequest{}
 	err = json.NewDecoder(r.Body).Decode(&updateUndeployResultRequest)
 	if err != nil {
-		logger.Error(err)
-		w.WriteHeader(http.StatusInternalServerError)
+		InternalErrorJSON(w, r, "failed to decode request body", err)
 		return
 	}
 
@@ -245,9 +360,7 @@ func (h *Handler) UpdateUndeployResult(w http.ResponseWriter, r *http.Request) {
 
 	foundApp, err := store.GetStore().GetApp(updateUndeployResultRequest.AppID)
 	if err != nil {
-		err = errors.Wrap(err, "failed to get app")
-		logger.Error(err)
-		w.WriteHeader(http.StatusInternalServerError)
+		InternalErrorJSON(w, r, "failed to get app", err)
 		return
 	}
 
@@ -0,0 +1,185 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/pkg/errors"
+	handlertypes "github.com/replicatedhq/kots/pkg/api/handlers/types"
+	apptypes "github.com/replicatedhq/kots/pkg/app/types"
+	"github.com/replicatedhq/kots/pkg/deployapproval"
+	deployapprovaltypes "github.com/replicatedhq/kots/pkg/deployapproval/types"
+	deployhistorytypes "github.com/replicatedhq/kots/pkg/deployhistory/types"
+	"github.com/replicatedhq/kots/pkg/logger"
+	"github.com/replicatedhq/kots/pkg/session"
+	"github.com/replicatedhq/kots/pkg/store"
+	"github.com/replicatedhq/kots/pkg/version"
+)
+
+type SetRequireDeployApprovalRequest struct {
+	Required bool `json:"required"`
+}
+
+type ListDeployApprovalsResponse struct {
+	DeployApprovals []deployapprovaltypes.DeployApproval `json:"deployApprovals"`
+}
+
+// SetRequireDeployApproval enables or disables the two person rule for deploys of an app
+func (h *Handler) SetRequireDeployApproval(w http.ResponseWriter, r *http.Request) {
+	request := SetRequireDeployApprovalRequest{}
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		BadRequestJSON(w, r, "failed to decode request body", err)
+		return
+	}
+
+	foundApp, err := store.GetStore().GetAppFromSlug(mux.Vars(r)["appSlug"])
+	if err != nil {
+		NotFoundJSON(w, r, "failed to get app from slug", err)
+		return
+	}
+
+	if err := store.GetStore().SetRequireDeployApproval(foundApp.ID, request.Required); err != nil {
+		InternalErrorJSON(w, r, "failed to set require deploy approval", err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *Handler) ListDeployApprovals(w http.ResponseWriter, r *http.Request) {
+	foundApp, err := store.GetStore().GetAppFromSlug(mux.Vars(r)["appSlug"])
+	if err != nil {
+		NotFoundJSON(w, r, "failed to get app from slug", err)
+		return
+	}
+
+	approvals, err := store.GetStore().ListDeployApprovals(foundApp.ID)
+	if err != nil {
+		InternalErrorJSON(w, r, "failed to list deploy approvals", err)
+		return
+	}
+
+	JSON(w, http.StatusOK, ListDeployApprovalsResponse{
+		DeployApprovals: approvals,
+	})
+}
+
+// ApproveDeploy deploys the version of a pending deploy approval. The approver must be a different user than
+// the one that requested the deploy.
+func (h *Handler) ApproveDeploy(w http.ResponseWriter, r *http.Request) {
+	foundApp, approval, ok := getDeployApprovalFromRequest(w, r)
+	if !ok {
+		return
+	}
+
+	userID := sessionUserID(r)
+	if err := deployapproval.CheckApprover(approval, userID); err != nil {
+		deployApprovalErrorJSON(w, r, err)
+		return
+	}
+
+	downstreams, err := store.GetStore().ListDownstreamsForApp(foundApp.ID)
+	if err != nil {
+		InternalErrorJSON(w, r, "failed to list downstreams for app", err)
+		return
+	} else if len(downstreams) == 0 {
+		InternalErrorJSON(w, r, "no downstreams for app", errors.New("no downstreams for app"))
+		return
+	}
+
+	if err := version.CheckMinKotsVersion(foundApp.ID, approval.Sequence); err != nil {
+		if _, ok := errors.Cause(err).(version.KotsUpgradeRequiredError); ok {
+			ErrorJSON(w, r, http.StatusConflict, handlertypes.ErrorCodeKotsUpgradeRequired, err.Error(), nil)
+			return
+		}
+		InternalErrorJSON(w, r, "failed to check minimum kots version", err)
+		return
+	}
+
+	clusterID := downstreams[0].ClusterID
+	if err := deployapproval.SetApproved(approval, userID, clusterID); err != nil {
+		deployApprovalErrorJSON(w, r, err)
+		return
+	}
+
+	deployRequest := DeployAppVersionRequest{
+		IsSkipPreflights:             approval.IsSkipPreflights,
+		ContinueWithFailedPreflights: approval.ContinueWithFailedPreflights,
+		IsCLI:                        approval.IsCLI,
+	}
+	deployer := deployhistorytypes.Deployer{
+		DeployedBy:        approval.RequestedBy,
+		ApprovedBy:        userID,
+		SkippedPreflights: approval.IsSkipPreflights,
+	}
+	if err := deployAppVersion(r.Context(), foundApp.ID, clusterID, approval.Sequence, deployRequest, deployer, GetRequestID(r)); err != nil {
+		// the approval is pending again if the deploy fails, so that it can be approved again
+		if err := deployapproval.Reopen(approval, userID); err != nil {
+			logger.Error(errors.Wrap(err, "failed to reopen deploy approval"))
+		}
+		deployFailedJSON(w, r, "failed to deploy version", err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// RejectDeploy rejects a pending deploy approval without deploying the version
+func (h *Handler) RejectDeploy(w http.ResponseWriter, r *http.Request) {
+	_, approval, ok := getDeployApprovalFromRequest(w, r)
+	if !ok {
+		return
+	}
+
+	if err := deployapproval.Reject(approval, sessionUserID(r)); err != nil {
+		deployApprovalErrorJSON(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func getDeployApprovalFromRequest(w http.ResponseWriter, r *http.Request) (*apptypes.App, *deployapprovaltypes.DeployApproval, bool) {
+	foundApp, err := store.GetStore().GetAppFromSlug(mux.Vars(r)["appSlug"])
+	if err != nil {
+		NotFoundJSON(w, r, "failed to get app from slug", err)
+		return nil, nil, false
+	}
+
+	approval, err := store.GetStore().GetDeployApproval(mux.Vars(r)["approvalId"])
+	if err != nil {
+		if store.GetStore().IsNotFound(err) {
+			NotFoundJSON(w, r, "deploy approval not found", err)
+		} else {
+			InternalErrorJSON(w, r, "failed to get deploy approval", err)
+		}
+		return nil, nil, false
+	}
+	if approval.AppID != foundApp.ID {
+		NotFoundJSON(w, r, "deploy approval not found", errors.New("deploy approval belongs to another app"))
+		return nil, nil, false
+	}
+
+	return foundApp, approval, true
+}
+
+func deployApprovalErrorJSON(w http.ResponseWriter, r *http.Request, err error) {
+	switch errors.Cause(err) {
+	case deployapproval.ErrNotPending, deployapproval.ErrSuperseded:
+		ErrorJSON(w, r, http.StatusConflict, handlertypes.ErrorCodeConflict, err.Error(), nil)
+	case deployapproval.ErrSelfApproval, deployapproval.ErrUnknownUser:
+		ErrorJSON(w, r, http.StatusForbidden, handlertypes.ErrorCodeForbidden, err.Error(), nil)
+	default:
+		InternalErrorJSON(w, r, "failed to decide deploy approval", err)
+	}
+}
+
+// sessionUserID returns the id of the user of the request's session, or an empty string if it is not known
+func sessionUserID(r *http.Request) string {
+	sess := session.ContextGetSession(r)
+	if sess == nil {
+		return ""
+	}
+	return sess.UserID
+}
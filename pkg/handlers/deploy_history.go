@@ -0,0 +1,59 @@
+package handlers
+
+import (
+	"net/http"
+
+	handlertypes "github.com/replicatedhq/kots/pkg/api/handlers/types"
+	"github.com/replicatedhq/kots/pkg/deployhistory"
+	deployhistorytypes "github.com/replicatedhq/kots/pkg/deployhistory/types"
+	"github.com/replicatedhq/kots/pkg/logger"
+	"github.com/replicatedhq/kots/pkg/store"
+)
+
+// GetDeployHistory exports the deploy events of all apps, or of the app in the appSlug param, deployed between the
+// from and to params. The events are returned as CSV if the format param is csv, and as JSON otherwise.
+func (h *Handler) GetDeployHistory(w http.ResponseWriter, r *http.Request) {
+	format := r.URL.Query().Get("format")
+	if format != "" && format != "csv" && format != "json" {
+		BadRequestJSON(w, r, "unsupported format "+format, nil)
+		return
+	}
+
+	from, to, err := deployhistory.ParseRange(r.URL.Query().Get("from"), r.URL.Query().Get("to"))
+	if err != nil {
+		BadRequestJSON(w, r, "failed to parse date range", err)
+		return
+	}
+
+	opts := deployhistorytypes.ListOptions{
+		From: from,
+		To:   to,
+	}
+
+	if appSlug := r.URL.Query().Get("appSlug"); appSlug != "" {
+		a, err := store.GetStore().GetAppFromSlug(appSlug)
+		if err != nil {
+			NotFoundJSON(w, r, "failed to get app from slug", err)
+			return
+		}
+		opts.AppID = a.ID
+	}
+
+	events, err := store.GetStore().ListDeployEvents(opts)
+	if err != nil {
+		InternalErrorJSON(w, r, "failed to list deploy events", err)
+		return
+	}
+
+	if format != "csv" {
+		JSON(w, http.StatusOK, handlertypes.GetDeployHistoryResponse{Events: events})
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/csv")
+	w.Header().Set("Content-Disposition", `attachment; filename="deploy-history.csv"`)
+	w.WriteHeader(http.StatusOK)
+	if err := deployhistory.WriteCSV(w, events); err != nil {
+		logger.Error(err)
+	}
+}
@@ -0,0 +1,82 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/pprof"
+	"runtime"
+
+	"github.com/gorilla/mux"
+	handlertypes "github.com/replicatedhq/kots/pkg/api/handlers/types"
+	"github.com/replicatedhq/kots/pkg/connectivity"
+	"github.com/replicatedhq/kots/pkg/janitor"
+)
+
+// GetRuntimeDiagnostics returns memory and goroutine statistics of the running kotsadm process
+func (h *Handler) GetRuntimeDiagnostics(w http.ResponseWriter, r *http.Request) {
+	memStats := runtime.MemStats{}
+	runtime.ReadMemStats(&memStats)
+
+	JSON(w, http.StatusOK, handlertypes.GetRuntimeDiagnosticsResponse{
+		GoVersion:     runtime.Version(),
+		NumGoroutine:  runtime.NumGoroutine(),
+		NumCPU:        runtime.NumCPU(),
+		HeapAlloc:     memStats.HeapAlloc,
+		HeapInuse:     memStats.HeapInuse,
+		HeapObjects:   memStats.HeapObjects,
+		HeapSys:       memStats.HeapSys,
+		StackInuse:    memStats.StackInuse,
+		Sys:           memStats.Sys,
+		TotalAlloc:    memStats.TotalAlloc,
+		NumGC:         memStats.NumGC,
+		PauseTotalNs:  memStats.PauseTotalNs,
+		LastGCUnixNs:  memStats.LastGC,
+		NextGCHeapLen: memStats.NextGC,
+	})
+}
+
+// GetJanitorDiagnostics returns the temp paths and archives that the janitor reclaimed since kotsadm started
+func (h *Handler) GetJanitorDiagnostics(w http.ResponseWriter, r *http.Request) {
+	JSON(w, http.StatusOK, janitor.GetStats())
+}
+
+// CheckConnectivity tests the outbound connectivity of kotsadm to the vendor portal, the registries, the gitops
+// remote and the proxy
+func (h *Handler) CheckConnectivity(w http.ResponseWriter, r *http.Request) {
+	report, err := connectivity.Check()
+	if err != nil {
+		InternalErrorJSON(w, r, "failed to check connectivity", err)
+		return
+	}
+
+	JSON(w, http.StatusOK, report)
+}
+
+// GetPprofProfile serves the named runtime profile in the pprof format.
+// The "profile" (cpu) and "trace" profiles honor the "seconds" query param like net/http/pprof does.
+func (h *Handler) GetPprofProfile(w http.ResponseWriter, r *http.Request) {
+	profileName := mux.Vars(r)["profile"]
+
+	switch profileName {
+	case "profile":
+		pprof.Profile(w, r)
+	case "trace":
+		pprof.Trace(w, r)
+	case "cmdline":
+		pprof.Cmdline(w, r)
+	default:
+		if isKnownProfile(profileName) {
+			pprof.Handler(profileName).ServeHTTP(w, r)
+			return
+		}
+		NotFoundJSON(w, r, "unknown profile "+profileName, nil)
+	}
+}
+
+func isKnownProfile(name string) bool {
+	for _, knownProfile := range []string{"allocs", "block", "goroutine", "heap", "mutex", "threadcreate"} {
+		if name == knownProfile {
+			return true
+		}
+	}
+	return false
+}
@@ -9,6 +9,7 @@ import (
 	"path/filepath"
 	"strconv"
 
+	"github.com/gorilla/mux"
 	"github.com/mholt/archiver"
 	"github.com/pkg/errors"
 	"github.com/replicatedhq/kots/pkg/kotsutil"
@@ -16,74 +17,89 @@ import (
 	"github.com/replicatedhq/kots/pkg/store"
 )
 
-// NOTE: this uses special kots token authorization
+// NOTE: this uses special kots token authorization. The kots cli downloads from DownloadAppArchive, this route is
+// kept for older clis.
 func (h *Handler) DownloadApp(w http.ResponseWriter, r *http.Request) {
 	if err := requireValidKOTSToken(w, r); err != nil {
 		logger.Error(err)
 		return
 	}
 
-	a, err := store.GetStore().GetAppFromSlug(r.URL.Query().Get("slug"))
+	decryptPasswordValues := false
+	if r.URL.Query().Get("decryptPasswordValues") != "" {
+		var err error
+		decryptPasswordValues, err = strconv.ParseBool(r.URL.Query().Get("decryptPasswordValues"))
+		if err != nil {
+			InternalErrorJSON(w, r, "failed to parse query parameter", err)
+			return
+		}
+	}
+
+	downloadApp(w, r, r.URL.Query().Get("slug"), decryptPasswordValues)
+}
+
+func (h *Handler) DownloadAppArchive(w http.ResponseWriter, r *http.Request) {
+	downloadApp(w, r, mux.Vars(r)["appSlug"], false)
+}
+
+func (h *Handler) DownloadAppArchiveDecrypted(w http.ResponseWriter, r *http.Request) {
+	downloadApp(w, r, mux.Vars(r)["appSlug"], true)
+}
+
+func downloadApp(w http.ResponseWriter, r *http.Request, appSlug string, decryptPasswordValues bool) {
+	a, err := store.GetStore().GetAppFromSlug(appSlug)
 	if err != nil {
-		logger.Error(err)
 		if errors.Is(err, sql.ErrNoRows) {
-			w.WriteHeader(404)
+			NotFoundJSON(w, r, "app not found", err)
 		} else {
-			w.WriteHeader(500)
+			InternalErrorJSON(w, r, "failed to get app from slug", err)
 		}
 		return
 	}
 
-	decryptPasswordValues := false
-	if r.URL.Query().Get("decryptPasswordValues") != "" {
-		decryptPasswordValues, err = strconv.ParseBool(r.URL.Query().Get("decryptPasswordValues"))
+	sequence := a.CurrentSequence
+	if r.URL.Query().Get("sequence") != "" {
+		sequence, err = strconv.ParseInt(r.URL.Query().Get("sequence"), 10, 64)
 		if err != nil {
-			logger.Error(err)
-			w.WriteHeader(500)
+			BadRequestJSON(w, r, "failed to parse sequence", err)
 			return
 		}
 	}
 
 	archivePath, err := ioutil.TempDir("", "kotsadm")
 	if err != nil {
-		logger.Error(err)
-		w.WriteHeader(500)
+		InternalErrorJSON(w, r, "failed to create temp dir", err)
 		return
 	}
 	defer os.RemoveAll(archivePath)
 
-	err = store.GetStore().GetAppVersionArchive(a.ID, a.CurrentSequence, archivePath)
+	err = store.GetStore().GetAppVersionArchive(a.ID, sequence, archivePath)
 	if err != nil {
-		logger.Error(err)
-		w.WriteHeader(500)
+		InternalErrorJSON(w, r, "failed to get app version archive", err)
 		return
 	}
 
 	if decryptPasswordValues {
 		kotsKinds, err := kotsutil.LoadKotsKindsFromPath(archivePath)
 		if err != nil {
-			logger.Error(err)
-			w.WriteHeader(500)
+			InternalErrorJSON(w, r, "failed to load kots kinds from path", err)
 			return
 		}
 
 		if kotsKinds.ConfigValues != nil {
 			if err := kotsKinds.DecryptConfigValues(); err != nil {
-				logger.Error(err)
-				w.WriteHeader(500)
+				InternalErrorJSON(w, r, "failed to decrypt config values", err)
 				return
 			}
 
 			updated, err := kotsKinds.Marshal("kots.io", "v1beta1", "ConfigValues")
 			if err != nil {
-				logger.Error(err)
-				w.WriteHeader(500)
+				InternalErrorJSON(w, r, "failed to marshal config values", err)
 				return
 			}
 
 			if err := ioutil.WriteFile(filepath.Join(archivePath, "upstream", "userdata", "config.yaml"), []byte(updated), 0644); err != nil {
-				logger.Error(err)
-				w.WriteHeader(500)
+				InternalErrorJSON(w, r, "failed to write file", err)
 				return
 			}
 		}
@@ -105,16 +121,14 @@ func (h *Handler) DownloadApp(w http.ResponseWriter, r *http.Request) {
 
 	tmpDir, err := ioutil.TempDir("", "kotsadm")
 	if err != nil {
-		logger.Error(err)
-		w.WriteHeader(500)
+		InternalErrorJSON(w, r, "failed to create temp dir", err)
 		return
 	}
 	defer os.RemoveAll(tmpDir)
 	fileToSend := filepath.Join(tmpDir, "archive.tar.gz")
 
 	if err != nil {
-		logger.Error(err)
-		w.WriteHeader(500)
+		InternalErrorJSON(w, r, "failed to create temp dir", err)
 		return
 	}
 
@@ -124,22 +138,19 @@ func (h *Handler) DownloadApp(w http.ResponseWriter, r *http.Request) {
 		},
 	}
 	if err := tarGz.Archive(paths, fileToSend); err != nil {
-		logger.Error(err)
-		w.WriteHeader(500)
+		InternalErrorJSON(w, r, "failed to create archive", err)
 		return
 	}
 
 	fi, err := os.Stat(fileToSend)
 	if err != nil {
-		logger.Error(err)
-		w.WriteHeader(500)
+		InternalErrorJSON(w, r, "failed to stat archive", err)
 		return
 	}
 
 	f, err := os.Open(fileToSend)
 	if err != nil {
-		logger.Error(err)
-		w.WriteHeader(500)
+		InternalErrorJSON(w, r, "failed to open archive", err)
 		return
 	}
 
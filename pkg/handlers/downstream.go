@@ -1,11 +1,17 @@
 package handlers
 
 import (
+	"fmt"
+	"io"
 	"net/http"
+	"os"
+	"path/filepath"
 	"strconv"
 
 	"github.com/gorilla/mux"
+	"github.com/replicatedhq/kots/pkg/downstream"
 	"github.com/replicatedhq/kots/pkg/logger"
+	postdeploytesttypes "github.com/replicatedhq/kots/pkg/postdeploytest/types"
 	"github.com/replicatedhq/kots/pkg/store"
 )
 
@@ -25,22 +31,19 @@ func (h *Handler) GetDownstreamOutput(w http.ResponseWriter, r *http.Request) {
 	clusterID := mux.Vars(r)["clusterId"]
 	sequence, err := strconv.Atoi(mux.Vars(r)["sequence"])
 	if err != nil {
-		logger.Error(err)
-		w.WriteHeader(http.StatusInternalServerError)
+		BadRequestJSON(w, r, "failed to parse sequence", err)
 		return
 	}
 
 	a, err := store.GetStore().GetAppFromSlug(appSlug)
 	if err != nil {
-		logger.Error(err)
-		w.WriteHeader(http.StatusInternalServerError)
+		InternalErrorJSON(w, r, "failed to get app from slug", err)
 		return
 	}
 
 	output, err := store.GetStore().GetDownstreamOutput(a.ID, clusterID, int64(sequence))
 	if err != nil {
-		logger.Error(err)
-		w.WriteHeader(http.StatusInternalServerError)
+		InternalErrorJSON(w, r, "failed to get downstream output", err)
 		return
 	}
 
@@ -57,3 +60,92 @@ func (h *Handler) GetDownstreamOutput(w http.ResponseWriter, r *http.Request) {
 
 	JSON(w, http.StatusOK, getDownstreamOutputResponse)
 }
+
+type GetPostDeployTestResultsResponse struct {
+	Results []postdeploytesttypes.Result `json:"results"`
+}
+
+func (h *Handler) GetPostDeployTestResults(w http.ResponseWriter, r *http.Request) {
+	appSlug := mux.Vars(r)["appSlug"]
+	clusterID := mux.Vars(r)["clusterId"]
+	sequence, err := strconv.Atoi(mux.Vars(r)["sequence"])
+	if err != nil {
+		BadRequestJSON(w, r, "failed to parse sequence", err)
+		return
+	}
+
+	a, err := store.GetStore().GetAppFromSlug(appSlug)
+	if err != nil {
+		InternalErrorJSON(w, r, "failed to get app from slug", err)
+		return
+	}
+
+	results, err := store.GetStore().GetDownstreamPostDeployTestResults(a.ID, clusterID, int64(sequence))
+	if err != nil {
+		InternalErrorJSON(w, r, "failed to get downstream post deploy test results", err)
+		return
+	}
+	if results == nil {
+		results = []postdeploytesttypes.Result{}
+	}
+
+	JSON(w, http.StatusOK, GetPostDeployTestResultsResponse{
+		Results: results,
+	})
+}
+
+// DownloadDownstreamOutput returns the complete dry run and apply output for a sequence as a tarball.
+// Sequences deployed before output archives were stored are served from the recorded output.
+func (h *Handler) DownloadDownstreamOutput(w http.ResponseWriter, r *http.Request) {
+	appSlug := mux.Vars(r)["appSlug"]
+	clusterID := mux.Vars(r)["clusterId"]
+	sequence, err := strconv.Atoi(mux.Vars(r)["sequence"])
+	if err != nil {
+		BadRequestJSON(w, r, "failed to parse sequence", err)
+		return
+	}
+
+	a, err := store.GetStore().GetAppFromSlug(appSlug)
+	if err != nil {
+		InternalErrorJSON(w, r, "failed to get app from slug", err)
+		return
+	}
+
+	filename := fmt.Sprintf("%s-sequence-%d-output.tar.gz", a.Slug, sequence)
+
+	archivePath, err := store.GetStore().GetDownstreamOutputArchive(a.ID, clusterID, int64(sequence))
+	if err != nil && !store.GetStore().IsNotFound(err) {
+		InternalErrorJSON(w, r, "failed to get downstream output archive", err)
+		return
+	}
+
+	if err == nil {
+		defer os.RemoveAll(filepath.Dir(archivePath))
+
+		f, err := os.Open(archivePath)
+		if err != nil {
+			InternalErrorJSON(w, r, "failed to open archive", err)
+			return
+		}
+		defer f.Close()
+
+		w.Header().Set("Content-Type", "application/gzip")
+		w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%s", filename))
+		w.WriteHeader(http.StatusOK)
+		io.Copy(w, f)
+		return
+	}
+
+	output, err := store.GetStore().GetDownstreamOutput(a.ID, clusterID, int64(sequence))
+	if err != nil {
+		InternalErrorJSON(w, r, "failed to get downstream output", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/gzip")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%s", filename))
+	w.WriteHeader(http.StatusOK)
+	if err := downstream.WriteOutputArchive(*output, w); err != nil {
+		logger.Error(err)
+	}
+}
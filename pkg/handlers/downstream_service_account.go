@@ -0,0 +1,67 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/replicatedhq/kots/pkg/downstream"
+	"github.com/replicatedhq/kots/pkg/store"
+)
+
+type DownstreamServiceAccountRequest struct {
+	// ServiceAccount is formatted as "namespace/name". An empty service account applies manifests as the operator.
+	ServiceAccount string `json:"serviceAccount"`
+}
+
+type GetDownstreamServiceAccountResponse struct {
+	ServiceAccount string `json:"serviceAccount"`
+}
+
+func (h *Handler) GetDownstreamServiceAccount(w http.ResponseWriter, r *http.Request) {
+	foundApp, err := store.GetStore().GetAppFromSlug(mux.Vars(r)["appSlug"])
+	if err != nil {
+		NotFoundJSON(w, r, "failed to get app from slug", err)
+		return
+	}
+
+	serviceAccount, err := store.GetStore().GetDownstreamServiceAccount(foundApp.ID, mux.Vars(r)["clusterId"])
+	if err != nil {
+		InternalErrorJSON(w, r, "failed to get downstream service account", err)
+		return
+	}
+
+	JSON(w, http.StatusOK, GetDownstreamServiceAccountResponse{
+		ServiceAccount: serviceAccount,
+	})
+}
+
+// SetDownstreamServiceAccount sets the service account that the operator impersonates when applying the app's
+// manifests to the downstream. The service account must be allowed to manage the app's resources.
+func (h *Handler) SetDownstreamServiceAccount(w http.ResponseWriter, r *http.Request) {
+	request := DownstreamServiceAccountRequest{}
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		BadRequestJSON(w, r, "failed to decode request body", err)
+		return
+	}
+
+	if request.ServiceAccount != "" {
+		if _, err := downstream.ServiceAccountUsername(request.ServiceAccount); err != nil {
+			BadRequestJSON(w, r, "invalid service account", err)
+			return
+		}
+	}
+
+	foundApp, err := store.GetStore().GetAppFromSlug(mux.Vars(r)["appSlug"])
+	if err != nil {
+		NotFoundJSON(w, r, "failed to get app from slug", err)
+		return
+	}
+
+	if err := store.GetStore().SetDownstreamServiceAccount(foundApp.ID, mux.Vars(r)["clusterId"], request.ServiceAccount); err != nil {
+		InternalErrorJSON(w, r, "failed to set downstream service account", err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -1,6 +1,15 @@
 package handlers
 
-import "github.com/pkg/errors"
+import (
+	"net/http"
+
+	"github.com/pkg/errors"
+	handlertypes "github.com/replicatedhq/kots/pkg/api/handlers/types"
+	"github.com/replicatedhq/kots/pkg/logger"
+	"github.com/segmentio/ksuid"
+)
+
+const RequestIDHeader = "X-Request-Id"
 
 type ErrorResponse struct {
 	Error   string `json:"error"`
@@ -15,3 +24,46 @@ func NewErrorResponse(err error) ErrorResponse {
 		Err:     err,
 	}
 }
+
+// ErrorJSON logs err with the request id and writes a typed error envelope.
+// The message is returned to the client and should say what failed, the error itself is only logged.
+func ErrorJSON(w http.ResponseWriter, r *http.Request, code int, errorCode handlertypes.ErrorCode, message string, err error) {
+	requestID := GetRequestID(r)
+
+	if err != nil {
+		logger.Errorf("request %s: %s: %v", requestID, message, err)
+	}
+
+	w.Header().Set(RequestIDHeader, requestID)
+	JSON(w, code, handlertypes.ErrorResponse{
+		Success:   false,
+		Error:     message,
+		Code:      errorCode,
+		RequestID: requestID,
+	})
+}
+
+// InternalErrorJSON is ErrorJSON for unexpected server side errors
+func InternalErrorJSON(w http.ResponseWriter, r *http.Request, message string, err error) {
+	ErrorJSON(w, r, http.StatusInternalServerError, handlertypes.ErrorCodeInternal, message, err)
+}
+
+// BadRequestJSON is ErrorJSON for requests that cannot be processed as sent
+func BadRequestJSON(w http.ResponseWriter, r *http.Request, message string, err error) {
+	ErrorJSON(w, r, http.StatusBadRequest, handlertypes.ErrorCodeBadRequest, message, err)
+}
+
+// NotFoundJSON is ErrorJSON for resources that do not exist
+func NotFoundJSON(w http.ResponseWriter, r *http.Request, message string, err error) {
+	ErrorJSON(w, r, http.StatusNotFound, handlertypes.ErrorCodeNotFound, message, err)
+}
+
+// GetRequestID returns the id of the request, assigning one if the client did not send it
+func GetRequestID(r *http.Request) string {
+	requestID := r.Header.Get(RequestIDHeader)
+	if requestID == "" {
+		requestID = ksuid.New().String()
+		r.Header.Set(RequestIDHeader, requestID)
+	}
+	return requestID
+}
@@ -0,0 +1,118 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/replicatedhq/kots/pkg/events"
+	"github.com/replicatedhq/kots/pkg/logger"
+	"github.com/replicatedhq/kots/pkg/rbac"
+	"github.com/replicatedhq/kots/pkg/session"
+	"github.com/replicatedhq/kots/pkg/store"
+)
+
+const (
+	// eventsHeartbeatInterval keeps the stream from being closed by idle timeouts of proxies
+	eventsHeartbeatInterval = 30 * time.Second
+)
+
+// StreamEvents streams app status, task status and new version events as server-sent events, so that the console
+// does not have to poll for them. Events of apps the session cannot read are not sent. A "resync" event is sent
+// when events were dropped because the client did not keep up, after which the client should refetch its state.
+func (h *Handler) StreamEvents(w http.ResponseWriter, r *http.Request) {
+	sess := session.ContextGetSession(r)
+	if sess == nil {
+		logger.Error(errors.New("invalid session"))
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		logger.Error(errors.New("response writer does not support streaming"))
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	subscription := events.Subscribe()
+	defer subscription.Close()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	// whether the session can read an app is checked once per app for the lifetime of the stream
+	readableApps := map[string]bool{}
+	canReadApp := func(appID string) (bool, error) {
+		if allow, ok := readableApps[appID]; ok {
+			return allow, nil
+		}
+		allow := true
+		if sess.HasRBAC { // handle pre-rbac sessions
+			a, err := store.GetStore().GetApp(appID)
+			if err != nil {
+				return false, errors.Wrap(err, "failed to get app")
+			}
+			allow, err = rbac.CheckAccess(r.Context(), rbac.DefaultRoles(), "read", fmt.Sprintf("app.%s", a.Slug), sess.Roles)
+			if err != nil {
+				return false, errors.Wrapf(err, "failed to check access for app %s", a.Slug)
+			}
+		}
+		readableApps[appID] = allow
+		return allow, nil
+	}
+
+	heartbeat := time.NewTicker(eventsHeartbeatInterval)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+
+		case <-heartbeat.C:
+			if _, err := fmt.Fprint(w, ": heartbeat\n\n"); err != nil {
+				return
+			}
+			flusher.Flush()
+
+		case event := <-subscription.C:
+			if subscription.Dropped() {
+				if err := writeServerSentEvent(w, "resync", struct{}{}); err != nil {
+					return
+				}
+			}
+
+			if event.AppID != "" {
+				allow, err := canReadApp(event.AppID)
+				if err != nil {
+					logger.Error(errors.Wrap(err, "failed to check access to event"))
+					continue
+				}
+				if !allow {
+					continue
+				}
+			}
+
+			if err := writeServerSentEvent(w, string(event.Type), event); err != nil {
+				return
+			}
+			flusher.Flush()
+		}
+	}
+}
+
+func writeServerSentEvent(w http.ResponseWriter, eventType string, data interface{}) error {
+	b, err := json.Marshal(data)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal event")
+	}
+
+	_, err = fmt.Fprintf(w, "event: %s\ndata: %s\n\n", eventType, b)
+	return err
+}
@@ -0,0 +1,196 @@
+package handlers
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"os"
+
+	"github.com/gorilla/mux"
+	"github.com/pkg/errors"
+	apptypes "github.com/replicatedhq/kots/pkg/app/types"
+	"github.com/replicatedhq/kots/pkg/deployapproval"
+	"github.com/replicatedhq/kots/pkg/downstream"
+	"github.com/replicatedhq/kots/pkg/preflight"
+	"github.com/replicatedhq/kots/pkg/store"
+	"github.com/replicatedhq/kots/pkg/version"
+)
+
+type ResourceExclusionRequest struct {
+	// Downstream defaults to the first downstream of the app
+	Downstream     string                       `json:"downstream,omitempty"`
+	Resource       downstream.ResourceExclusion `json:"resource"`
+	SkipPreflights bool                         `json:"skipPreflights"`
+	Deploy         bool                         `json:"deploy"`
+}
+
+type ResourceExclusionsResponse struct {
+	Downstream string                         `json:"downstream"`
+	Exclusions []downstream.ResourceExclusion `json:"exclusions"`
+	// Sequence is the version created by the change, or -1 if nothing changed
+	Sequence int64 `json:"sequence"`
+}
+
+func (h *Handler) GetResourceExclusions(w http.ResponseWriter, r *http.Request) {
+	a, err := store.GetStore().GetAppFromSlug(mux.Vars(r)["appSlug"])
+	if err != nil {
+		NotFoundJSON(w, r, "failed to get app from slug", err)
+		return
+	}
+
+	archiveDir, err := ioutil.TempDir("", "kotsadm")
+	if err != nil {
+		InternalErrorJSON(w, r, "failed to create temp dir", err)
+		return
+	}
+	defer os.RemoveAll(archiveDir)
+
+	if err := store.GetStore().GetAppVersionArchive(a.ID, a.CurrentSequence, archiveDir); err != nil {
+		InternalErrorJSON(w, r, "failed to get app version archive", err)
+		return
+	}
+
+	downstreamName, err := exclusionDownstreamName(a, r.URL.Query().Get("downstream"))
+	if err != nil {
+		BadRequestJSON(w, r, err.Error(), err)
+		return
+	}
+
+	exclusions, err := downstream.ListExclusions(archiveDir, downstreamName)
+	if err != nil {
+		InternalErrorJSON(w, r, "failed to list resource exclusions", err)
+		return
+	}
+
+	JSON(w, http.StatusOK, ResourceExclusionsResponse{
+		Downstream: downstreamName,
+		Exclusions: exclusions,
+		Sequence:   -1,
+	})
+}
+
+// AddResourceExclusion creates a new app version that does not deploy the resource to the downstream
+func (h *Handler) AddResourceExclusion(w http.ResponseWriter, r *http.Request) {
+	h.updateResourceExclusions(w, r, downstream.AddExclusion)
+}
+
+// RemoveResourceExclusion creates a new app version that deploys a previously excluded resource to the downstream
+func (h *Handler) RemoveResourceExclusion(w http.ResponseWriter, r *http.Request) {
+	h.updateResourceExclusions(w, r, downstream.RemoveExclusion)
+}
+
+func (h *Handler) updateResourceExclusions(w http.ResponseWriter, r *http.Request, update func(string, string, downstream.ResourceExclusion) (bool, error)) {
+	request := ResourceExclusionRequest{}
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		BadRequestJSON(w, r, "failed to decode request body", err)
+		return
+	}
+	if request.Resource.Kind == "" || request.Resource.Name == "" {
+		BadRequestJSON(w, r, "resource kind and name are required", nil)
+		return
+	}
+
+	a, err := store.GetStore().GetAppFromSlug(mux.Vars(r)["appSlug"])
+	if err != nil {
+		NotFoundJSON(w, r, "failed to get app from slug", err)
+		return
+	}
+
+	unlock, ok := lockAppJSON(w, r, a.ID)
+	if !ok {
+		return
+	}
+	defer unlock()
+
+	// the current sequence may have changed while the lock was not held
+	a, err = store.GetStore().GetApp(a.ID)
+	if err != nil {
+		InternalErrorJSON(w, r, "failed to get app", err)
+		return
+	}
+
+	downstreamName, err := exclusionDownstreamName(a, request.Downstream)
+	if err != nil {
+		BadRequestJSON(w, r, err.Error(), err)
+		return
+	}
+
+	archiveDir, err := ioutil.TempDir("", "kotsadm")
+	if err != nil {
+		InternalErrorJSON(w, r, "failed to create temp dir", err)
+		return
+	}
+	defer os.RemoveAll(archiveDir)
+
+	if err := store.GetStore().GetAppVersionArchive(a.ID, a.CurrentSequence, archiveDir); err != nil {
+		InternalErrorJSON(w, r, "failed to get app version archive", err)
+		return
+	}
+
+	changed, err := update(archiveDir, downstreamName, request.Resource)
+	if err != nil {
+		InternalErrorJSON(w, r, "failed to update resource exclusions", err)
+		return
+	}
+
+	response := ResourceExclusionsResponse{
+		Downstream: downstreamName,
+		Sequence:   -1,
+	}
+
+	if changed {
+		newSequence, err := store.GetStore().CreateAppVersion(a.ID, &a.CurrentSequence, archiveDir, "Resource Exclusion", request.SkipPreflights, &version.DownstreamGitOps{})
+		if err != nil {
+			InternalErrorJSON(w, r, "failed to create an app version", err)
+			return
+		}
+		response.Sequence = newSequence
+
+		if !request.SkipPreflights {
+			if err := preflight.Run(a.ID, a.Slug, newSequence, a.IsAirgap, archiveDir); err != nil {
+				InternalErrorJSON(w, r, "failed to run preflights", err)
+				return
+			}
+		}
+
+		if request.Deploy {
+			_, err := deployapproval.DeployOrRequest(a, newSequence, deployapproval.RequestOptions{
+				RequestedBy:      sessionUserID(r),
+				IsSkipPreflights: request.SkipPreflights,
+			})
+			if err != nil {
+				deployFailedJSON(w, r, "failed to deploy version", err)
+				return
+			}
+		}
+	}
+
+	exclusions, err := downstream.ListExclusions(archiveDir, downstreamName)
+	if err != nil {
+		InternalErrorJSON(w, r, "failed to list resource exclusions", err)
+		return
+	}
+	response.Exclusions = exclusions
+
+	JSON(w, http.StatusOK, response)
+}
+
+// exclusionDownstreamName returns the named downstream of the app, or the first downstream if name is empty
+func exclusionDownstreamName(a *apptypes.App, name string) (string, error) {
+	downstreams, err := store.GetStore().ListDownstreamsForApp(a.ID)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to list downstreams for app")
+	}
+	if len(downstreams) == 0 {
+		return "", errors.New("app has no downstreams")
+	}
+	if name == "" {
+		return downstreams[0].Name, nil
+	}
+	for _, d := range downstreams {
+		if d.Name == name {
+			return d.Name, nil
+		}
+	}
+	return "", errors.Errorf("downstream %s not found", name)
+}
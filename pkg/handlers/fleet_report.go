@@ -0,0 +1,19 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/replicatedhq/kots/pkg/fleetreport"
+)
+
+// GetFleetReport returns the versions, channels and licenses of all installed apps and the kots and kubernetes
+// versions of the cluster in one document
+func (h *Handler) GetFleetReport(w http.ResponseWriter, r *http.Request) {
+	report, err := fleetreport.Generate()
+	if err != nil {
+		InternalErrorJSON(w, r, "failed to generate fleet report", err)
+		return
+	}
+
+	JSON(w, http.StatusOK, report)
+}
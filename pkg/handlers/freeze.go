@@ -0,0 +1,162 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/pkg/errors"
+	handlertypes "github.com/replicatedhq/kots/pkg/api/handlers/types"
+	"github.com/replicatedhq/kots/pkg/freeze"
+	freezetypes "github.com/replicatedhq/kots/pkg/freeze/types"
+	"github.com/replicatedhq/kots/pkg/logger"
+	"github.com/replicatedhq/kots/pkg/store"
+	"go.uber.org/zap"
+)
+
+type SetFreezeRequest struct {
+	Reason string `json:"reason"`
+	// ExpiresAt is nil for a freeze that lasts until it is lifted
+	ExpiresAt *time.Time `json:"expiresAt"`
+}
+
+type GetFreezeResponse struct {
+	// Freeze is nil if no freeze is set
+	Freeze   *freezetypes.Freeze `json:"freeze"`
+	IsActive bool                `json:"isActive"`
+}
+
+func (h *Handler) GetGlobalFreeze(w http.ResponseWriter, r *http.Request) {
+	f, err := store.GetStore().GetGlobalFreeze()
+	if err != nil {
+		InternalErrorJSON(w, r, "failed to get freeze", err)
+		return
+	}
+
+	JSON(w, http.StatusOK, getFreezeResponse(f))
+}
+
+func (h *Handler) SetGlobalFreeze(w http.ResponseWriter, r *http.Request) {
+	f, ok := decodeFreeze(w, r)
+	if !ok {
+		return
+	}
+
+	if err := store.GetStore().SetGlobalFreeze(f); err != nil {
+		InternalErrorJSON(w, r, "failed to set freeze", err)
+		return
+	}
+
+	logger.Info("deploys frozen", zap.String("audit", "freeze"), zap.String("user", f.CreatedBy), zap.String("reason", f.Reason))
+
+	JSON(w, http.StatusOK, getFreezeResponse(f))
+}
+
+func (h *Handler) ClearGlobalFreeze(w http.ResponseWriter, r *http.Request) {
+	if err := store.GetStore().SetGlobalFreeze(nil); err != nil {
+		InternalErrorJSON(w, r, "failed to clear freeze", err)
+		return
+	}
+
+	logger.Info("deploys unfrozen", zap.String("audit", "freeze"), zap.String("user", sessionUserID(r)))
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *Handler) GetAppFreeze(w http.ResponseWriter, r *http.Request) {
+	foundApp, err := store.GetStore().GetAppFromSlug(mux.Vars(r)["appSlug"])
+	if err != nil {
+		NotFoundJSON(w, r, "failed to get app from slug", err)
+		return
+	}
+
+	f, err := store.GetStore().GetAppFreeze(foundApp.ID)
+	if err != nil {
+		InternalErrorJSON(w, r, "failed to get app freeze", err)
+		return
+	}
+
+	JSON(w, http.StatusOK, getFreezeResponse(f))
+}
+
+func (h *Handler) SetAppFreeze(w http.ResponseWriter, r *http.Request) {
+	f, ok := decodeFreeze(w, r)
+	if !ok {
+		return
+	}
+
+	foundApp, err := store.GetStore().GetAppFromSlug(mux.Vars(r)["appSlug"])
+	if err != nil {
+		NotFoundJSON(w, r, "failed to get app from slug", err)
+		return
+	}
+
+	if err := store.GetStore().SetAppFreeze(foundApp.ID, f); err != nil {
+		InternalErrorJSON(w, r, "failed to set app freeze", err)
+		return
+	}
+
+	logger.Info("app deploys frozen", zap.String("audit", "freeze"), zap.String("app", foundApp.Slug), zap.String("user", f.CreatedBy), zap.String("reason", f.Reason))
+
+	JSON(w, http.StatusOK, getFreezeResponse(f))
+}
+
+func (h *Handler) ClearAppFreeze(w http.ResponseWriter, r *http.Request) {
+	foundApp, err := store.GetStore().GetAppFromSlug(mux.Vars(r)["appSlug"])
+	if err != nil {
+		NotFoundJSON(w, r, "failed to get app from slug", err)
+		return
+	}
+
+	if err := store.GetStore().SetAppFreeze(foundApp.ID, nil); err != nil {
+		InternalErrorJSON(w, r, "failed to clear app freeze", err)
+		return
+	}
+
+	logger.Info("app deploys unfrozen", zap.String("audit", "freeze"), zap.String("app", foundApp.Slug), zap.String("user", sessionUserID(r)))
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// decodeFreeze reads the freeze of a set freeze request, writing the error response if it is invalid
+func decodeFreeze(w http.ResponseWriter, r *http.Request) (*freezetypes.Freeze, bool) {
+	request := SetFreezeRequest{}
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		BadRequestJSON(w, r, "failed to decode request body", err)
+		return nil, false
+	}
+
+	now := time.Now()
+	f := freezetypes.Freeze{
+		Reason:    request.Reason,
+		ExpiresAt: request.ExpiresAt,
+		CreatedBy: sessionUserID(r),
+		CreatedAt: now,
+	}
+	if err := freeze.Validate(f, now); err != nil {
+		BadRequestJSON(w, r, "invalid freeze", err)
+		return nil, false
+	}
+
+	return &f, true
+}
+
+func getFreezeResponse(f *freezetypes.Freeze) GetFreezeResponse {
+	response := GetFreezeResponse{
+		Freeze: f,
+	}
+	if f != nil {
+		response.IsActive = f.IsActive(time.Now())
+	}
+	return response
+}
+
+// deployFailedJSON writes the error response of a failed deploy, a conflict if deploys are frozen
+func deployFailedJSON(w http.ResponseWriter, r *http.Request, message string, err error) {
+	if freeze.IsFrozen(err) {
+		ErrorJSON(w, r, http.StatusConflict, handlertypes.ErrorCodeDeploysFrozen, errors.Cause(err).Error(), nil)
+		return
+	}
+	InternalErrorJSON(w, r, message, err)
+}
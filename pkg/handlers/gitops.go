@@ -23,6 +23,9 @@ type UpdateAppGitOpsInput struct {
 	Path   string `json:"path"`
 	Format string `json:"format"`
 	Action string `json:"action"`
+	// SecretEncryption and SyncStatusSource are left unchanged if not set
+	SecretEncryption *gitops.SecretEncryption `json:"secretEncryption,omitempty"`
+	SyncStatusSource *gitops.SyncStatusSource `json:"syncStatusSource,omitempty"`
 }
 
 type CreateGitOpsRequest struct {
@@ -34,6 +37,8 @@ type CreateGitOpsInput struct {
 	Hostname string `json:"hostname"`
 	HTTPPort string `json:"httpPort"`
 	SSHPort  string `json:"sshPort"`
+	// AccessToken is used for the commit status api of bitbucket server and azure devops
+	AccessToken string `json:"accessToken"`
 }
 
 func (h *Handler) UpdateAppGitOps(w http.ResponseWriter, r *http.Request) {
@@ -55,7 +60,37 @@ func (h *Handler) UpdateAppGitOps(w http.ResponseWriter, r *http.Request) {
 	}
 
 	gitOpsInput := updateAppGitOpsRequest.GitOpsInput
-	if err := gitops.UpdateDownstreamGitOps(a.ID, clusterID, gitOpsInput.URI, gitOpsInput.Branch, gitOpsInput.Path, gitOpsInput.Format, gitOpsInput.Action); err != nil {
+
+	downstreamGitOps, err := gitops.GetDownstreamGitOps(a.ID, clusterID)
+	if err != nil {
+		logger.Error(err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	secretEncryption := gitops.SecretEncryption{}
+	if gitOpsInput.SecretEncryption != nil {
+		if err := gitOpsInput.SecretEncryption.Validate(); err != nil {
+			JSON(w, http.StatusBadRequest, NewErrorResponse(err))
+			return
+		}
+		secretEncryption = *gitOpsInput.SecretEncryption
+	} else if downstreamGitOps != nil {
+		secretEncryption = downstreamGitOps.SecretEncryption
+	}
+
+	syncStatusSource := gitops.SyncStatusSource{}
+	if gitOpsInput.SyncStatusSource != nil {
+		if err := gitOpsInput.SyncStatusSource.Validate(); err != nil {
+			JSON(w, http.StatusBadRequest, NewErrorResponse(err))
+			return
+		}
+		syncStatusSource = *gitOpsInput.SyncStatusSource
+	} else if downstreamGitOps != nil {
+		syncStatusSource = downstreamGitOps.SyncStatusSource
+	}
+
+	if err := gitops.UpdateDownstreamGitOps(a.ID, clusterID, gitOpsInput.URI, gitOpsInput.Branch, gitOpsInput.Path, gitOpsInput.Format, gitOpsInput.Action, secretEncryption, syncStatusSource); err != nil {
 		logger.Error(err)
 		w.WriteHeader(http.StatusInternalServerError)
 		return
@@ -159,7 +194,7 @@ func (h *Handler) InitGitOpsConnection(w http.ResponseWriter, r *http.Request) {
 	// If a branch is not provided, use the default branch
 	if downstreamGitOps.Branch == "" {
 		err := gitops.UpdateDownstreamGitOps(a.ID, d.ClusterID, downstreamGitOps.RepoURI, defaultBranchName,
-			downstreamGitOps.Path, downstreamGitOps.Format, downstreamGitOps.Action)
+			downstreamGitOps.Path, downstreamGitOps.Format, downstreamGitOps.Action, downstreamGitOps.SecretEncryption, downstreamGitOps.SyncStatusSource)
 		if err != nil {
 			logger.Infof("Failed to update the gitops configmap with the default branch: %v", err)
 
@@ -307,7 +342,7 @@ func (h *Handler) CreateGitOps(w http.ResponseWriter, r *http.Request) {
 	}
 
 	gitOpsInput := createGitOpsRequest.GitOpsInput
-	if err := gitops.CreateGitOps(gitOpsInput.Provider, gitOpsInput.URI, gitOpsInput.Hostname, gitOpsInput.HTTPPort, gitOpsInput.SSHPort); err != nil {
+	if err := gitops.CreateGitOps(gitOpsInput.Provider, gitOpsInput.URI, gitOpsInput.Hostname, gitOpsInput.HTTPPort, gitOpsInput.SSHPort, gitOpsInput.AccessToken); err != nil {
 		logger.Error(err)
 		w.WriteHeader(http.StatusInternalServerError)
 		return
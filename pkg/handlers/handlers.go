@@ -89,10 +89,22 @@ func RegisterSessionAuthRoutes(r *mux.Router, kotsStore store.Store, handler KOT
 		HandlerFunc(middleware.EnforceAccess(policy.AppList, handler.GetPendingApp))
 	r.Name("ListApps").Path("/api/v1/apps").Methods("GET").
 		HandlerFunc(middleware.EnforceAccess(policy.AppList, handler.ListApps))
+	r.Name("StreamEvents").Path("/api/v1/events").Methods("GET").
+		HandlerFunc(middleware.EnforceAccess(policy.AppList, handler.StreamEvents))
+	r.Name("GetAppsSummary").Path("/api/v1/apps/summary").Methods("GET").
+		HandlerFunc(middleware.EnforceAccess(policy.AppList, handler.GetAppsSummary))
+	r.Name("ListRemovedApps").Path("/api/v1/apps/removed").Methods("GET").
+		HandlerFunc(middleware.EnforceAccess(policy.AppList, handler.ListRemovedApps))
+	r.Name("GetFleetReport").Path("/api/v1/fleet-report").Methods("GET").
+		HandlerFunc(middleware.EnforceAccess(policy.AppList, handler.GetFleetReport))
+	r.Name("GetDeployHistory").Path("/api/v1/deploy-history").Methods("GET").
+		HandlerFunc(middleware.EnforceAccess(policy.AppList, handler.GetDeployHistory))
 	r.Name("GetApp").Path("/api/v1/app/{appSlug}").Methods("GET").
 		HandlerFunc(middleware.EnforceAccess(policy.AppRead, handler.GetApp))
 	r.Name("GetAppStatus").Path("/api/v1/app/{appSlug}/status").Methods("GET").
 		HandlerFunc(middleware.EnforceAccess(policy.AppStatusRead, handler.GetAppStatus))
+	r.Name("GetAppResourceUsage").Path("/api/v1/app/{appSlug}/usage").Methods("GET").
+		HandlerFunc(middleware.EnforceAccess(policy.AppStatusRead, handler.GetAppResourceUsage))
 	r.Name("GetAppVersionHistory").Path("/api/v1/app/{appSlug}/versions").Methods("GET").
 		HandlerFunc(middleware.EnforceAccess(policy.AppDownstreamRead, handler.GetAppVersionHistory))
 	r.Name("GetUpdateDownloadStatus").Path("/api/v1/app/{appSlug}/task/updatedownload").Methods("GET").
@@ -117,6 +129,8 @@ func RegisterSessionAuthRoutes(r *mux.Router, kotsStore store.Store, handler KOT
 		HandlerFunc(middleware.EnforceAccess(policy.AppDownstreamWrite, handler.ResetAirgapInstallStatus))
 	r.Name("GetAirgapUploadConfig").Path("/api/v1/app/{appSlug}/airgap/config").Methods("GET").
 		HandlerFunc(middleware.EnforceAccess(policy.AppDownstreamWrite, handler.GetAirgapUploadConfig))
+	r.Name("SetImagePushBandwidthLimit").Path("/api/v1/app/{appSlug}/airgap/bandwidth-limit").Methods("PUT").
+		HandlerFunc(middleware.EnforceAccess(policy.AppDownstreamWrite, handler.SetImagePushBandwidthLimit))
 
 	// Implemented handlers
 	r.Name("IgnorePreflightRBACErrors").Path("/api/v1/app/{appSlug}/sequence/{sequence}/preflight/ignore-rbac").Methods("POST").
@@ -136,17 +150,69 @@ func RegisterSessionAuthRoutes(r *mux.Router, kotsStore store.Store, handler KOT
 		HandlerFunc(middleware.EnforceAccess(policy.AppDownstreamWrite, handler.DeployAppVersion))
 	r.Name("RedeployAppVersion").Path("/api/v1/app/{appSlug}/sequence/{sequence}/redeploy").Methods("POST").
 		HandlerFunc(middleware.EnforceAccess(policy.AppDownstreamWrite, handler.RedeployAppVersion))
+	r.Name("ActivateAppVersion").Path("/api/v1/app/{appSlug}/sequence/{sequence}/activate").Methods("POST").
+		HandlerFunc(middleware.EnforceAccess(policy.AppDownstreamWrite, handler.ActivateAppVersion))
+	r.Name("SetAppVersionNotes").Path("/api/v1/app/{appSlug}/sequence/{sequence}/notes").Methods("PUT").
+		HandlerFunc(middleware.EnforceAccess(policy.AppDownstreamWrite, handler.SetAppVersionNotes))
+	r.Name("ListCorruptAppVersionArchives").Path("/api/v1/app/{appSlug}/archives/corrupt").Methods("GET").
+		HandlerFunc(middleware.EnforceAccess(policy.AppDownstreamRead, handler.ListCorruptAppVersionArchives))
+	r.Name("RepairAppVersionArchive").Path("/api/v1/app/{appSlug}/sequence/{sequence}/archive/repair").Methods("POST").
+		HandlerFunc(middleware.EnforceAccess(policy.AppDownstreamWrite, handler.RepairAppVersionArchive))
+	r.Name("ListDeployApprovals").Path("/api/v1/app/{appSlug}/deploy-approvals").Methods("GET").
+		HandlerFunc(middleware.EnforceAccess(policy.AppDownstreamRead, handler.ListDeployApprovals))
+	r.Name("ApproveDeploy").Path("/api/v1/app/{appSlug}/deploy-approval/{approvalId}/approve").Methods("POST").
+		HandlerFunc(middleware.EnforceAccess(policy.AppDeployApprovalWrite, handler.ApproveDeploy))
+	r.Name("RejectDeploy").Path("/api/v1/app/{appSlug}/deploy-approval/{approvalId}/reject").Methods("POST").
+		HandlerFunc(middleware.EnforceAccess(policy.AppDeployApprovalWrite, handler.RejectDeploy))
+	r.Name("SetRequireDeployApproval").Path("/api/v1/app/{appSlug}/require-deploy-approval").Methods("PUT").
+		HandlerFunc(middleware.EnforceAccess(policy.AppUpdate, handler.SetRequireDeployApproval))
 	r.Name("GetAppRenderedContents").Path("/api/v1/app/{appSlug}/sequence/{sequence}/renderedcontents").Methods("GET").
 		HandlerFunc(middleware.EnforceAccess(policy.AppDownstreamFiletreeRead, handler.GetAppRenderedContents))
+	r.Name("GetAppRenderedManifests").Path("/api/v1/app/{appSlug}/sequence/{sequence}/manifests").Methods("GET").
+		HandlerFunc(middleware.EnforceAccess(policy.AppDownstreamFiletreeRead, handler.GetAppRenderedManifests))
+	r.Name("GetAppVersionImpact").Path("/api/v1/app/{appSlug}/sequence/{sequence}/impact").Methods("GET").
+		HandlerFunc(middleware.EnforceAccess(policy.AppDownstreamFiletreeRead, handler.GetAppVersionImpact))
+	r.Name("SearchAppRenderedContents").Path("/api/v1/app/{appSlug}/sequence/{sequence}/renderedcontents/search").Methods("GET").
+		HandlerFunc(middleware.EnforceAccess(policy.AppDownstreamFiletreeRead, handler.SearchAppRenderedContents))
+	r.Name("GetImageReport").Path("/api/v1/app/{appSlug}/sequence/{sequence}/images").Methods("GET").
+		HandlerFunc(middleware.EnforceAccess(policy.AppDownstreamRead, handler.GetImageReport))
+	r.Name("GetResourceExclusions").Path("/api/v1/app/{appSlug}/exclusions").Methods("GET").
+		HandlerFunc(middleware.EnforceAccess(policy.AppDownstreamRead, handler.GetResourceExclusions))
+	r.Name("AddResourceExclusion").Path("/api/v1/app/{appSlug}/exclusions").Methods("POST").
+		HandlerFunc(middleware.EnforceAccess(policy.AppDownstreamWrite, handler.AddResourceExclusion))
+	r.Name("RemoveResourceExclusion").Path("/api/v1/app/{appSlug}/exclusions").Methods("DELETE").
+		HandlerFunc(middleware.EnforceAccess(policy.AppDownstreamWrite, handler.RemoveResourceExclusion))
 	r.Name("GetAppContents").Path("/api/v1/app/{appSlug}/sequence/{sequence}/contents").Methods("GET").
 		HandlerFunc(middleware.EnforceAccess(policy.AppDownstreamFiletreeRead, handler.GetAppContents))
+	r.Name("ListAppContentsDir").Path("/api/v1/app/{appSlug}/sequence/{sequence}/contents/dir").Methods("GET").
+		HandlerFunc(middleware.EnforceAccess(policy.AppDownstreamFiletreeRead, handler.ListAppContentsDir))
+	r.Name("GetAppContentsFile").Path("/api/v1/app/{appSlug}/sequence/{sequence}/contents/file").Methods("GET").
+		HandlerFunc(middleware.EnforceAccess(policy.AppDownstreamFiletreeRead, handler.GetAppContentsFile))
+	r.Name("DownloadAppArchive").Path("/api/v1/app/{appSlug}/download").Methods("GET").
+		HandlerFunc(middleware.EnforceAccess(policy.AppDownstreamFiletreeRead, handler.DownloadAppArchive))
+	r.Name("DownloadAppArchiveDecrypted").Path("/api/v1/app/{appSlug}/download/decrypted").Methods("GET").
+		HandlerFunc(middleware.EnforceAccess(policy.AppDownstreamConfigDecryptedRead, handler.DownloadAppArchiveDecrypted))
 	r.Name("GetAppDashboard").Path("/api/v1/app/{appSlug}/cluster/{clusterId}/dashboard").Methods("GET").
 		HandlerFunc(middleware.EnforceAccess(policy.AppRead, handler.GetAppDashboard))
+	r.Name("GetAppMetricChart").Path("/api/v1/app/{appSlug}/cluster/{clusterId}/metrics/{graphIndex}").Methods("GET").
+		HandlerFunc(middleware.EnforceAccess(policy.AppRead, handler.GetAppMetricChart))
 	r.Name("GetDownstreamOutput").Path("/api/v1/app/{appSlug}/cluster/{clusterId}/sequence/{sequence}/downstreamoutput").Methods("GET").
 		HandlerFunc(middleware.EnforceAccess(policy.AppDownstreamLogsRead, handler.GetDownstreamOutput))
+	r.Name("DownloadDownstreamOutput").Path("/api/v1/app/{appSlug}/cluster/{clusterId}/sequence/{sequence}/downstreamoutput/download").Methods("GET").
+		HandlerFunc(middleware.EnforceAccess(policy.AppDownstreamLogsRead, handler.DownloadDownstreamOutput))
+	r.Name("GetPostDeployTestResults").Path("/api/v1/app/{appSlug}/cluster/{clusterId}/sequence/{sequence}/postdeploytests").Methods("GET").
+		HandlerFunc(middleware.EnforceAccess(policy.AppDownstreamRead, handler.GetPostDeployTestResults))
+	r.Name("GetAdmissionValidation").Path("/api/v1/app/{appSlug}/cluster/{clusterId}/sequence/{sequence}/admission").Methods("GET").
+		HandlerFunc(middleware.EnforceAccess(policy.AppDownstreamRead, handler.GetAdmissionValidation))
+	r.Name("GetDownstreamServiceAccount").Path("/api/v1/app/{appSlug}/cluster/{clusterId}/serviceaccount").Methods("GET").
+		HandlerFunc(middleware.EnforceAccess(policy.AppDownstreamRead, handler.GetDownstreamServiceAccount))
+	r.Name("SetDownstreamServiceAccount").Path("/api/v1/app/{appSlug}/cluster/{clusterId}/serviceaccount").Methods("PUT").
+		HandlerFunc(middleware.EnforceAccess(policy.AppDownstreamWrite, handler.SetDownstreamServiceAccount))
 
 	r.Name("GetKotsadmRegistry").Path("/api/v1/registry").Methods("GET").
 		HandlerFunc(middleware.EnforceAccess(policy.RegistryRead, handler.GetKotsadmRegistry))
+	r.Name("UpdateKotsadmRegistry").Path("/api/v1/registry").Methods("PUT").
+		HandlerFunc(middleware.EnforceAccess(policy.RegistryWrite, handler.UpdateKotsadmRegistry))
 	r.Name("GetImageRewriteStatusOld").Path("/api/v1/imagerewritestatus").Methods("GET").
 		HandlerFunc(middleware.EnforceAccess(policy.RegistryRead, handler.GetImageRewriteStatus))
 
@@ -161,24 +227,90 @@ func RegisterSessionAuthRoutes(r *mux.Router, kotsStore store.Store, handler KOT
 
 	r.Name("UpdateAppConfig").Path("/api/v1/app/{appSlug}/config").Methods("PUT").
 		HandlerFunc(middleware.EnforceAccess(policy.AppDownstreamConfigWrite, handler.UpdateAppConfig))
+	r.Name("GetAppConfigSchema").Path("/api/v1/app/{appSlug}/config/schema").Methods("GET").
+		HandlerFunc(middleware.EnforceAccess(policy.AppDownstreamConfigRead, handler.GetAppConfigSchema))
 	r.Name("CurrentAppConfig").Path("/api/v1/app/{appSlug}/config/{sequence}").Methods("GET").
 		HandlerFunc(middleware.EnforceAccess(policy.AppDownstreamConfigRead, handler.CurrentAppConfig))
 	r.Name("LiveAppConfig").Path("/api/v1/app/{appSlug}/liveconfig").Methods("POST").
 		HandlerFunc(middleware.EnforceAccess(policy.AppDownstreamConfigWrite, handler.LiveAppConfig))
 	r.Name("SetAppConfigValues").Path("/api/v1/app/{appSlug}/config/values").Methods("POST").
 		HandlerFunc(middleware.EnforceAccess(policy.AppDownstreamConfigWrite, handler.SetAppConfigValues))
+	r.Name("PreviewAppConfig").Path("/api/v1/app/{appSlug}/config/values/preview").Methods("POST").
+		HandlerFunc(middleware.EnforceAccess(policy.AppDownstreamConfigWrite, handler.PreviewAppConfig))
+	r.Name("RecomputeAppConfig").Path("/api/v1/app/{appSlug}/config/recompute").Methods("POST").
+		HandlerFunc(middleware.EnforceAccess(policy.AppDownstreamConfigWrite, handler.RecomputeAppConfig))
+	r.Name("UploadAppConfigFile").Path("/api/v1/app/{appSlug}/config/file").Methods("POST").
+		HandlerFunc(middleware.EnforceAccess(policy.AppDownstreamConfigWrite, handler.UploadAppConfigFile))
+	r.Name("GetAppConfigValues").Path("/api/v1/app/{appSlug}/configvalues").Methods("GET").
+		HandlerFunc(middleware.EnforceAccess(policy.AppDownstreamConfigRead, handler.GetAppConfigValues))
+	r.Name("GetAppConfigValuesDecrypted").Path("/api/v1/app/{appSlug}/configvalues/decrypted").Methods("GET").
+		HandlerFunc(middleware.EnforceAccess(policy.AppDownstreamConfigDecryptedRead, handler.GetAppConfigValuesDecrypted))
 
 	r.Name("SyncLicense").Path("/api/v1/app/{appSlug}/license").Methods("PUT").
 		HandlerFunc(middleware.EnforceAccess(policy.AppLicenseWrite, handler.SyncLicense))
+	r.Name("ReplaceLicense").Path("/api/v1/app/{appSlug}/license/replace").Methods("POST").
+		HandlerFunc(middleware.EnforceAccess(policy.AppLicenseWrite, handler.ReplaceLicense))
 	r.Name("GetLicense").Path("/api/v1/app/{appSlug}/license").Methods("GET").
 		HandlerFunc(middleware.EnforceAccess(policy.AppLicenseRead, handler.GetLicense))
+	r.Name("GetEntitlementUsage").Path("/api/v1/app/{appSlug}/license/usage").Methods("GET").
+		HandlerFunc(middleware.EnforceAccess(policy.AppLicenseRead, handler.GetEntitlementUsage))
 
 	r.Name("AppUpdateCheck").Path("/api/v1/app/{appSlug}/updatecheck").Methods("POST").
 		HandlerFunc(middleware.EnforceAccess(policy.AppDownstreamWrite, handler.AppUpdateCheck))
 	r.Name("UpdateCheckerSpec").Path("/api/v1/app/{appSlug}/updatecheckerspec").Methods("PUT").
 		HandlerFunc(middleware.EnforceAccess(policy.AppDownstreamWrite, handler.UpdateCheckerSpec))
+	r.Name("GetAppSchedules").Path("/api/v1/app/{appSlug}/schedules").Methods("GET").
+		HandlerFunc(middleware.EnforceAccess(policy.AppRead, handler.GetAppSchedules))
+	r.Name("SetScheduleTimezone").Path("/api/v1/app/{appSlug}/schedules/timezone").Methods("PUT").
+		HandlerFunc(middleware.EnforceAccess(policy.AppDownstreamWrite, handler.SetScheduleTimezone))
+	r.Name("SetAdmissionDryRun").Path("/api/v1/app/{appSlug}/admission-dry-run").Methods("PUT").
+		HandlerFunc(middleware.EnforceAccess(policy.AppDownstreamWrite, handler.SetAdmissionDryRun))
+	r.Name("GetApplyPolicy").Path("/api/v1/app/{appSlug}/apply-policy").Methods("GET").
+		HandlerFunc(middleware.EnforceAccess(policy.AppDownstreamRead, handler.GetApplyPolicy))
+	r.Name("SetApplyPolicy").Path("/api/v1/app/{appSlug}/apply-policy").Methods("PUT").
+		HandlerFunc(middleware.EnforceAccess(policy.AppDownstreamWrite, handler.SetApplyPolicy))
+	r.Name("GetCanaryDeploys").Path("/api/v1/app/{appSlug}/canary").Methods("GET").
+		HandlerFunc(middleware.EnforceAccess(policy.AppDownstreamRead, handler.GetCanaryDeploys))
+	r.Name("SetCanaryPolicy").Path("/api/v1/app/{appSlug}/canary/policy").Methods("PUT").
+		HandlerFunc(middleware.EnforceAccess(policy.AppDownstreamWrite, handler.SetCanaryPolicy))
+	r.Name("GetFailedUpdateDownloads").Path("/api/v1/app/{appSlug}/updates/failed").Methods("GET").
+		HandlerFunc(middleware.EnforceAccess(policy.AppDownstreamRead, handler.GetFailedUpdateDownloads))
+	r.Name("RetryFailedUpdateDownloads").Path("/api/v1/app/{appSlug}/updates/failed/retry").Methods("POST").
+		HandlerFunc(middleware.EnforceAccess(policy.AppDownstreamWrite, handler.RetryFailedUpdateDownloads))
 	r.Name("RemoveApp").Path("/api/v1/app/{appSlug}/remove").Methods("POST").
 		HandlerFunc(middleware.EnforceAccess(policy.AppUpdate, handler.RemoveApp))
+	r.Name("RestoreRemovedApp").Path("/api/v1/app/{appSlug}/remove/undo").Methods("POST").
+		HandlerFunc(middleware.EnforceAccess(policy.AppUpdate, handler.RestoreRemovedApp))
+	r.Name("SetAppProtected").Path("/api/v1/app/{appSlug}/protected").Methods("PUT").
+		HandlerFunc(middleware.EnforceAccess(policy.AppUpdate, handler.SetAppProtected))
+	r.Name("SetAppLabels").Path("/api/v1/app/{appSlug}/labels").Methods("PUT").
+		HandlerFunc(middleware.EnforceAccess(policy.AppUpdate, handler.SetAppLabels))
+	r.Name("ArchiveApp").Path("/api/v1/app/{appSlug}/archive").Methods("POST").
+		HandlerFunc(middleware.EnforceAccess(policy.AppUpdate, handler.ArchiveApp))
+	r.Name("UnarchiveApp").Path("/api/v1/app/{appSlug}/unarchive").Methods("POST").
+		HandlerFunc(middleware.EnforceAccess(policy.AppUpdate, handler.UnarchiveApp))
+	r.Name("SetAppAirgapMode").Path("/api/v1/app/{appSlug}/airgap-mode").Methods("PUT").
+		HandlerFunc(middleware.EnforceAccess(policy.AppUpdate, handler.SetAppAirgapMode))
+	r.Name("CloneApp").Path("/api/v1/app/{appSlug}/clone").Methods("POST").
+		HandlerFunc(middleware.EnforceAccess(policy.AppCreate, handler.CloneApp))
+	r.Name("GetAppMaintenanceMessage").Path("/api/v1/app/{appSlug}/maintenance").Methods("GET").
+		HandlerFunc(middleware.EnforceAccess(policy.AppMaintenanceRead, handler.GetAppMaintenanceMessage))
+	r.Name("SetAppMaintenanceMessage").Path("/api/v1/app/{appSlug}/maintenance").Methods("PUT").
+		HandlerFunc(middleware.EnforceAccess(policy.AppMaintenanceWrite, handler.SetAppMaintenanceMessage))
+	r.Name("ClearAppMaintenanceMessage").Path("/api/v1/app/{appSlug}/maintenance").Methods("DELETE").
+		HandlerFunc(middleware.EnforceAccess(policy.AppMaintenanceWrite, handler.ClearAppMaintenanceMessage))
+	r.Name("GetAppFreeze").Path("/api/v1/app/{appSlug}/freeze").Methods("GET").
+		HandlerFunc(middleware.EnforceAccess(policy.AppFreezeRead, handler.GetAppFreeze))
+	r.Name("SetAppFreeze").Path("/api/v1/app/{appSlug}/freeze").Methods("PUT").
+		HandlerFunc(middleware.EnforceAccess(policy.AppFreezeWrite, handler.SetAppFreeze))
+	r.Name("ClearAppFreeze").Path("/api/v1/app/{appSlug}/freeze").Methods("DELETE").
+		HandlerFunc(middleware.EnforceAccess(policy.AppFreezeWrite, handler.ClearAppFreeze))
+
+	// Release feedback
+	r.Name("GetReleaseFeedbackConsent").Path("/api/v1/app/{appSlug}/release-feedback").Methods("GET").
+		HandlerFunc(middleware.EnforceAccess(policy.AppReleaseFeedbackRead, handler.GetReleaseFeedbackConsent))
+	r.Name("SetReleaseFeedbackConsent").Path("/api/v1/app/{appSlug}/release-feedback").Methods("PUT").
+		HandlerFunc(middleware.EnforceAccess(policy.AppReleaseFeedbackWrite, handler.SetReleaseFeedbackConsent))
 
 	// App snapshot routes
 	r.Name("CreateApplicationBackup").Path("/api/v1/app/{appSlug}/snapshot/backup").Methods("POST").
@@ -189,6 +321,8 @@ func RegisterSessionAuthRoutes(r *mux.Router, kotsStore store.Store, handler KOT
 		HandlerFunc(middleware.EnforceAccess(policy.AppRestoreWrite, handler.CancelRestore))
 	r.Name("CreateApplicationRestore").Path("/api/v1/app/{appSlug}/snapshot/restore/{snapshotName}").Methods("POST").
 		HandlerFunc(middleware.EnforceAccess(policy.AppRestoreWrite, handler.CreateApplicationRestore))
+	r.Name("CloneAppFromSnapshot").Path("/api/v1/app/{appSlug}/snapshot/restore/{snapshotName}/clone").Methods("POST").
+		HandlerFunc(middleware.EnforceAccess(policy.AppCreate, handler.CloneAppFromSnapshot))
 	r.Name("GetRestoreDetails").Path("/api/v1/app/{appSlug}/snapshot/restore/{restoreName}").Methods("GET").
 		HandlerFunc(middleware.EnforceAccess(policy.AppRestoreRead, handler.GetRestoreDetails))
 	r.Name("ListBackups").Path("/api/v1/app/{appSlug}/snapshots").Methods("GET").
@@ -197,6 +331,12 @@ func RegisterSessionAuthRoutes(r *mux.Router, kotsStore store.Store, handler KOT
 		HandlerFunc(middleware.EnforceAccess(policy.AppSnapshotsettingsRead, handler.GetSnapshotConfig))
 	r.Name("SaveSnapshotConfig").Path("/api/v1/app/{appSlug}/snapshot/config").Methods("PUT").
 		HandlerFunc(middleware.EnforceAccess(policy.AppSnapshotsettingsWrite, handler.SaveSnapshotConfig))
+	r.Name("GetRestoreDrills").Path("/api/v1/app/{appSlug}/restore-drills").Methods("GET").
+		HandlerFunc(middleware.EnforceAccess(policy.AppRestoreRead, handler.GetRestoreDrills))
+	r.Name("RunRestoreDrill").Path("/api/v1/app/{appSlug}/restore-drills").Methods("POST").
+		HandlerFunc(middleware.EnforceAccess(policy.AppRestoreWrite, handler.RunRestoreDrill))
+	r.Name("SetRestoreDrillSchedule").Path("/api/v1/app/{appSlug}/restore-drills/schedule").Methods("PUT").
+		HandlerFunc(middleware.EnforceAccess(policy.AppSnapshotsettingsWrite, handler.SetRestoreDrillSchedule))
 
 	// Global snapshot routes
 	r.Name("ListInstanceBackups").Path("/api/v1/snapshots").Methods("GET").
@@ -215,6 +355,8 @@ func RegisterSessionAuthRoutes(r *mux.Router, kotsStore store.Store, handler KOT
 		HandlerFunc(middleware.EnforceAccess(policy.SnapshotsettingsWrite, handler.ConfigureFileSystemSnapshotProvider))
 	r.Name("GetBackup").Path("/api/v1/snapshot/{snapshotName}").Methods("GET").
 		HandlerFunc(middleware.EnforceAccess(policy.BackupRead, handler.GetBackup))
+	r.Name("GetBackupContents").Path("/api/v1/snapshot/{snapshotName}/contents").Methods("GET").
+		HandlerFunc(middleware.EnforceAccess(policy.BackupRead, handler.GetBackupContents))
 	r.Name("DeleteBackup").Path("/api/v1/snapshot/{snapshotName}/delete").Methods("POST").
 		HandlerFunc(middleware.EnforceAccess(policy.BackupWrite, handler.DeleteBackup))
 	r.Name("RestoreApps").Path("/api/v1/snapshot/{snapshotName}/restore-apps").Methods("POST").
@@ -226,6 +368,12 @@ func RegisterSessionAuthRoutes(r *mux.Router, kotsStore store.Store, handler KOT
 	r.Name("GetVeleroStatus").Path("/api/v1/velero").Methods("GET").
 		HandlerFunc(middleware.EnforceAccess(policy.BackupRead, handler.GetVeleroStatus))
 
+	// Kotsadm state
+	r.Name("ExportKotsadmState").Path("/api/v1/kotsadm/export").Methods("GET").
+		HandlerFunc(middleware.EnforceAccess(policy.KotsadmStateExport, handler.ExportKotsadmState))
+	r.Name("ImportKotsadmState").Path("/api/v1/kotsadm/import").Methods("POST").
+		HandlerFunc(middleware.EnforceAccess(policy.RestoreWrite, handler.ImportKotsadmState))
+
 	// KURL
 	r.Name("Kurl").Path("/api/v1/kurl").HandlerFunc(NotImplemented) // I'm not sure why this is here
 	r.Name("GenerateNodeJoinCommandWorker").Path("/api/v1/kurl/generate-node-join-command-worker").Methods("POST").
@@ -243,9 +391,105 @@ func RegisterSessionAuthRoutes(r *mux.Router, kotsStore store.Store, handler KOT
 	r.Name("GetKurlNodes").Path("/api/v1/kurl/nodes").Methods("GET").
 		HandlerFunc(middleware.EnforceAccess(policy.ClusterRead, handler.GetKurlNodes))
 
+	// Diagnostics
+	r.Name("GetRuntimeDiagnostics").Path("/api/v1/debug/runtime").Methods("GET").
+		HandlerFunc(middleware.EnforceAccess(policy.DiagnosticsRead, handler.GetRuntimeDiagnostics))
+	r.Name("GetPprofProfile").Path("/api/v1/debug/pprof/{profile}").Methods("GET").
+		HandlerFunc(middleware.EnforceAccess(policy.DiagnosticsRead, handler.GetPprofProfile))
+	r.Name("GetJanitorDiagnostics").Path("/api/v1/debug/janitor").Methods("GET").
+		HandlerFunc(middleware.EnforceAccess(policy.DiagnosticsRead, handler.GetJanitorDiagnostics))
+	r.Name("CheckConnectivity").Path("/api/v1/debug/connectivity").Methods("GET").
+		HandlerFunc(middleware.EnforceAccess(policy.DiagnosticsRead, handler.CheckConnectivity))
+	r.Name("ListScheduledJobs").Path("/api/v1/scheduled-jobs").Methods("GET").
+		HandlerFunc(middleware.EnforceAccess(policy.DiagnosticsRead, handler.ListScheduledJobs))
+
 	// Prometheus
 	r.Name("SetPrometheusAddress").Path("/api/v1/prometheus").Methods("POST").
 		HandlerFunc(middleware.EnforceAccess(policy.PrometheussettingsWrite, handler.SetPrometheusAddress))
+	r.Name("GetPrometheusSettings").Path("/api/v1/prometheus").Methods("GET").
+		HandlerFunc(middleware.EnforceAccess(policy.PrometheussettingsRead, handler.GetPrometheusSettings))
+	r.Name("ValidatePrometheusSettings").Path("/api/v1/prometheus/validate").Methods("POST").
+		HandlerFunc(middleware.EnforceAccess(policy.PrometheussettingsRead, handler.ValidatePrometheusSettings))
+
+	// CA Bundle
+	r.Name("GetCABundle").Path("/api/v1/kotsadm/ca-bundle").Methods("GET").
+		HandlerFunc(middleware.EnforceAccess(policy.CABundleRead, handler.GetCABundle))
+	r.Name("SetCABundle").Path("/api/v1/kotsadm/ca-bundle").Methods("PUT").
+		HandlerFunc(middleware.EnforceAccess(policy.CABundleWrite, handler.SetCABundle))
+
+	// Client Certificates
+	r.Name("ListClientCertificates").Path("/api/v1/kotsadm/client-certificates").Methods("GET").
+		HandlerFunc(middleware.EnforceAccess(policy.ClientCertificatesRead, handler.ListClientCertificates))
+	r.Name("SetClientCertificate").Path("/api/v1/kotsadm/client-certificates/{host}").Methods("PUT").
+		HandlerFunc(middleware.EnforceAccess(policy.ClientCertificatesWrite, handler.SetClientCertificate))
+	r.Name("DeleteClientCertificate").Path("/api/v1/kotsadm/client-certificates/{host}").Methods("DELETE").
+		HandlerFunc(middleware.EnforceAccess(policy.ClientCertificatesWrite, handler.DeleteClientCertificate))
+
+	// Maintenance
+	r.Name("GetGlobalMaintenanceMessage").Path("/api/v1/maintenance").Methods("GET").
+		HandlerFunc(middleware.EnforceAccess(policy.MaintenanceRead, handler.GetGlobalMaintenanceMessage))
+	r.Name("SetGlobalMaintenanceMessage").Path("/api/v1/maintenance").Methods("PUT").
+		HandlerFunc(middleware.EnforceAccess(policy.MaintenanceWrite, handler.SetGlobalMaintenanceMessage))
+	r.Name("ClearGlobalMaintenanceMessage").Path("/api/v1/maintenance").Methods("DELETE").
+		HandlerFunc(middleware.EnforceAccess(policy.MaintenanceWrite, handler.ClearGlobalMaintenanceMessage))
+
+	// Freeze
+	r.Name("GetGlobalFreeze").Path("/api/v1/freeze").Methods("GET").
+		HandlerFunc(middleware.EnforceAccess(policy.FreezeRead, handler.GetGlobalFreeze))
+	r.Name("SetGlobalFreeze").Path("/api/v1/freeze").Methods("PUT").
+		HandlerFunc(middleware.EnforceAccess(policy.FreezeWrite, handler.SetGlobalFreeze))
+	r.Name("ClearGlobalFreeze").Path("/api/v1/freeze").Methods("DELETE").
+		HandlerFunc(middleware.EnforceAccess(policy.FreezeWrite, handler.ClearGlobalFreeze))
+
+	// Read-only mode
+	r.Name("GetReadOnlyMode").Path("/api/v1/readonlymode").Methods("GET").
+		HandlerFunc(middleware.EnforceAccess(policy.ReadOnlyModeRead, handler.GetReadOnlyMode))
+	r.Name("SetReadOnlyMode").Path("/api/v1/readonlymode").Methods("PUT").
+		HandlerFunc(middleware.EnforceAccess(policy.ReadOnlyModeWrite, handler.SetReadOnlyMode))
+
+	// Upload quota
+	r.Name("GetUploadQuota").Path("/api/v1/upload-quota").Methods("GET").
+		HandlerFunc(middleware.EnforceAccess(policy.UploadQuotaRead, handler.GetUploadQuota))
+	r.Name("SetUploadQuota").Path("/api/v1/upload-quota").Methods("PUT").
+		HandlerFunc(middleware.EnforceAccess(policy.UploadQuotaWrite, handler.SetUploadQuota))
+
+	// Session settings
+	r.Name("GetSessionSettings").Path("/api/v1/session-settings").Methods("GET").
+		HandlerFunc(middleware.EnforceAccess(policy.SessionSettingsRead, handler.GetSessionSettings))
+	r.Name("SetSessionSettings").Path("/api/v1/session-settings").Methods("PUT").
+		HandlerFunc(middleware.EnforceAccess(policy.SessionSettingsWrite, handler.SetSessionSettings))
+
+	// LDAP
+	r.Name("GetLDAPSettings").Path("/api/v1/ldap/settings").Methods("GET").
+		HandlerFunc(middleware.EnforceAccess(policy.LDAPRead, handler.GetLDAPSettings))
+	r.Name("SetLDAPSettings").Path("/api/v1/ldap/settings").Methods("PUT").
+		HandlerFunc(middleware.EnforceAccess(policy.LDAPWrite, handler.SetLDAPSettings))
+	r.Name("TestLDAPConnection").Path("/api/v1/ldap/test").Methods("POST").
+		HandlerFunc(middleware.EnforceAccess(policy.LDAPWrite, handler.TestLDAPConnection))
+
+	// Temporary access
+	r.Name("ListTemporaryAccess").Path("/api/v1/temporary-access").Methods("GET").
+		HandlerFunc(middleware.EnforceAccess(policy.TemporaryAccessRead, handler.ListTemporaryAccess))
+	r.Name("CreateTemporaryAccess").Path("/api/v1/temporary-access").Methods("POST").
+		HandlerFunc(middleware.EnforceAccess(policy.TemporaryAccessWrite, handler.CreateTemporaryAccess))
+	r.Name("RevokeTemporaryAccess").Path("/api/v1/temporary-access/{id}").Methods("DELETE").
+		HandlerFunc(middleware.EnforceAccess(policy.TemporaryAccessWrite, handler.RevokeTemporaryAccess))
+
+	// Remote installs
+	r.Name("ListRemoteInstalls").Path("/api/v1/remote-installs").Methods("GET").
+		HandlerFunc(middleware.EnforceAccess(policy.RemoteInstallRead, handler.ListRemoteInstalls))
+	r.Name("CreateRemoteInstall").Path("/api/v1/remote-installs").Methods("POST").
+		HandlerFunc(middleware.EnforceAccess(policy.RemoteInstallWrite, handler.CreateRemoteInstall))
+	r.Name("DeleteRemoteInstall").Path("/api/v1/remote-install/{remoteInstallId}").Methods("DELETE").
+		HandlerFunc(middleware.EnforceAccess(policy.RemoteInstallWrite, handler.DeleteRemoteInstall))
+	r.Name("RefreshRemoteInstall").Path("/api/v1/remote-install/{remoteInstallId}/refresh").Methods("POST").
+		HandlerFunc(middleware.EnforceAccess(policy.RemoteInstallWrite, handler.RefreshRemoteInstall))
+
+	// Log settings
+	r.Name("GetLogSettings").Path("/api/v1/log-settings").Methods("GET").
+		HandlerFunc(middleware.EnforceAccess(policy.LogSettingsRead, handler.GetLogSettings))
+	r.Name("SetLogSettings").Path("/api/v1/log-settings").Methods("PUT").
+		HandlerFunc(middleware.EnforceAccess(policy.LogSettingsWrite, handler.SetLogSettings))
 
 	// GitOps
 	r.Name("UpdateAppGitOps").Path("/api/v1/gitops/app/{appId}/cluster/{clusterId}/update").Methods("PUT").
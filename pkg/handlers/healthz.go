@@ -2,6 +2,8 @@ package handlers
 
 import (
 	"net/http"
+
+	"github.com/replicatedhq/kots/pkg/buildversion"
 )
 
 type HealthzResponse struct {
@@ -24,14 +26,15 @@ type StorageResponse struct {
 }
 
 // Healthz route is UNAUTHENTICATED
+// The kots cli compares its version with the version in the response before each command.
 func (h *Handler) Healthz(w http.ResponseWriter, r *http.Request) {
 	// TODO
 	isDatabaseConnected := true
 	isStorageAvailable := true
 
 	healthzResponse := HealthzResponse{
-		Version: "test",
-		GitSHA:  "test",
+		Version: buildversion.Version(),
+		GitSHA:  buildversion.GitSHA(),
 		Status: StatusResponse{
 			Database: DatabaseResponse{
 				Connected: isDatabaseConnected,
@@ -254,6 +254,20 @@ func (h *Handler) ConfigureAppIdentityService(w http.ResponseWriter, r *http.Req
 		return
 	}
 
+	unlock, ok := lockAppJSON(w, r, a.ID)
+	if !ok {
+		return
+	}
+	defer unlock()
+
+	// the current sequence may have changed while the lock was not held
+	a, err = store.GetStore().GetApp(a.ID)
+	if err != nil {
+		logger.Error(err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
 	archiveDir, err := ioutil.TempDir("", "kotsadm")
 	if err != nil {
 		err = errors.Wrap(err, "failed to create temp dir")
@@ -647,26 +661,9 @@ func (h *Handler) GetAppIdentityServiceConfig(w http.ResponseWriter, r *http.Req
 		return
 	}
 
-	archiveDir, err := ioutil.TempDir("", "kotsadm")
-	if err != nil {
-		err = errors.Wrap(err, "failed to create temp dir")
-		logger.Error(err)
-		w.WriteHeader(http.StatusInternalServerError)
-		return
-	}
-	defer os.RemoveAll(archiveDir)
-
-	err = store.GetStore().GetAppVersionArchive(a.ID, a.CurrentSequence, archiveDir)
-	if err != nil {
-		err = errors.Wrap(err, "failed to get current app version archive")
-		logger.Error(err)
-		w.WriteHeader(http.StatusInternalServerError)
-		return
-	}
-
-	kotsKinds, err := kotsutil.LoadKotsKindsFromPath(archiveDir)
+	kotsKinds, err := version.GetKotsKinds(a.ID, a.CurrentSequence)
 	if err != nil {
-		err = errors.Wrap(err, "failed to load kotskinds from path")
+		err = errors.Wrap(err, "failed to load kotskinds from archive")
 		logger.Error(err)
 		w.WriteHeader(http.StatusInternalServerError)
 		return
@@ -0,0 +1,36 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+	"github.com/replicatedhq/kots/pkg/imagereport"
+	"github.com/replicatedhq/kots/pkg/store"
+)
+
+// GetImageReport returns the images of an app version and the digests they resolve to.
+// The report is generated once per sequence, set the "refresh" query param to resolve the digests again.
+func (h *Handler) GetImageReport(w http.ResponseWriter, r *http.Request) {
+	sequence, err := strconv.ParseInt(mux.Vars(r)["sequence"], 10, 64)
+	if err != nil {
+		BadRequestJSON(w, r, "failed to parse sequence", err)
+		return
+	}
+
+	a, err := store.GetStore().GetAppFromSlug(mux.Vars(r)["appSlug"])
+	if err != nil {
+		NotFoundJSON(w, r, "failed to get app from slug", err)
+		return
+	}
+
+	refresh, _ := strconv.ParseBool(r.URL.Query().Get("refresh"))
+
+	report, err := imagereport.Get(a.ID, sequence, refresh)
+	if err != nil {
+		InternalErrorJSON(w, r, "failed to get image report", err)
+		return
+	}
+
+	JSON(w, http.StatusOK, report)
+}
@@ -0,0 +1,92 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+	"github.com/pkg/errors"
+	"github.com/replicatedhq/kots/pkg/downstream"
+	"github.com/replicatedhq/kots/pkg/k8sutil"
+	"github.com/replicatedhq/kots/pkg/logger"
+	"github.com/replicatedhq/kots/pkg/store"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// GetAppVersionImpact compares the kustomize output of a version with that of the deployed version, and summarizes
+// the images, rollouts, PVCs, CRDs and RBAC that deploying it changes. The "downstream" query param selects the
+// downstream like for the rendered manifests.
+func (h *Handler) GetAppVersionImpact(w http.ResponseWriter, r *http.Request) {
+	appSlug := mux.Vars(r)["appSlug"]
+	sequence, err := strconv.ParseInt(mux.Vars(r)["sequence"], 10, 64)
+	if err != nil {
+		BadRequestJSON(w, r, "invalid sequence", err)
+		return
+	}
+
+	a, err := store.GetStore().GetAppFromSlug(appSlug)
+	if err != nil {
+		NotFoundJSON(w, r, "app not found", err)
+		return
+	}
+
+	downstreams, err := store.GetStore().ListDownstreamsForApp(a.ID)
+	if err != nil {
+		InternalErrorJSON(w, r, "failed to list downstreams for app", err)
+		return
+	}
+	if len(downstreams) == 0 {
+		InternalErrorJSON(w, r, "no downstreams for app", errors.New("no downstreams for app"))
+		return
+	}
+
+	currentVersion, err := store.GetStore().GetCurrentVersion(a.ID, downstreams[0].ClusterID)
+	if err != nil {
+		InternalErrorJSON(w, r, "failed to get current version", err)
+		return
+	}
+
+	pending, ok := renderAppManifests(w, r, appSlug, sequence)
+	if !ok {
+		return
+	}
+
+	var deployed []byte
+	if currentVersion != nil {
+		deployed, ok = renderAppManifests(w, r, appSlug, currentVersion.ParentSequence)
+		if !ok {
+			return
+		}
+	}
+
+	report, err := downstream.AnalyzeImpact(deployed, pending, countNodes(r.Context()))
+	if err != nil {
+		InternalErrorJSON(w, r, "failed to analyze impact", err)
+		return
+	}
+	report.Sequence = sequence
+	if currentVersion != nil {
+		report.DeployedSequence = &currentVersion.ParentSequence
+	}
+
+	JSON(w, http.StatusOK, report)
+}
+
+// countNodes returns the number of nodes in the cluster, or 1 if they can't be listed
+func countNodes(ctx context.Context) int {
+	clientset, err := k8sutil.GetClientset()
+	if err != nil {
+		logger.Error(errors.Wrap(err, "failed to get clientset"))
+		return 1
+	}
+	nodes, err := clientset.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		logger.Error(errors.Wrap(err, "failed to list nodes"))
+		return 1
+	}
+	if len(nodes.Items) == 0 {
+		return 1
+	}
+	return len(nodes.Items)
+}
@@ -38,8 +38,11 @@ type KOTSHandler interface {
 
 	// Apps
 	ListApps(w http.ResponseWriter, r *http.Request)
+	GetAppsSummary(w http.ResponseWriter, r *http.Request)
+	StreamEvents(w http.ResponseWriter, r *http.Request)
 	GetApp(w http.ResponseWriter, r *http.Request)
 	GetAppStatus(w http.ResponseWriter, r *http.Request)
+	GetAppResourceUsage(w http.ResponseWriter, r *http.Request)
 	GetAppVersionHistory(w http.ResponseWriter, r *http.Request)
 	GetUpdateDownloadStatus(w http.ResponseWriter, r *http.Request) // NOTE: appSlug is unused
 	GetPendingApp(w http.ResponseWriter, r *http.Request)
@@ -54,6 +57,7 @@ type KOTSHandler interface {
 	GetAirgapInstallStatus(w http.ResponseWriter, r *http.Request)
 	ResetAirgapInstallStatus(w http.ResponseWriter, r *http.Request)
 	GetAirgapUploadConfig(w http.ResponseWriter, r *http.Request)
+	SetImagePushBandwidthLimit(w http.ResponseWriter, r *http.Request)
 
 	// Implemented handlers
 	IgnorePreflightRBACErrors(w http.ResponseWriter, r *http.Request)
@@ -65,28 +69,92 @@ type KOTSHandler interface {
 
 	DeployAppVersion(w http.ResponseWriter, r *http.Request)
 	RedeployAppVersion(w http.ResponseWriter, r *http.Request)
+	ActivateAppVersion(w http.ResponseWriter, r *http.Request)
+	SetAppVersionNotes(w http.ResponseWriter, r *http.Request)
+	ListCorruptAppVersionArchives(w http.ResponseWriter, r *http.Request)
+	RepairAppVersionArchive(w http.ResponseWriter, r *http.Request)
+	ListDeployApprovals(w http.ResponseWriter, r *http.Request)
+	ApproveDeploy(w http.ResponseWriter, r *http.Request)
+	RejectDeploy(w http.ResponseWriter, r *http.Request)
+	SetRequireDeployApproval(w http.ResponseWriter, r *http.Request)
 	GetAppRenderedContents(w http.ResponseWriter, r *http.Request)
+	GetAppRenderedManifests(w http.ResponseWriter, r *http.Request)
+	GetAppVersionImpact(w http.ResponseWriter, r *http.Request)
+	SearchAppRenderedContents(w http.ResponseWriter, r *http.Request)
+	GetImageReport(w http.ResponseWriter, r *http.Request)
+	GetResourceExclusions(w http.ResponseWriter, r *http.Request)
+	AddResourceExclusion(w http.ResponseWriter, r *http.Request)
+	RemoveResourceExclusion(w http.ResponseWriter, r *http.Request)
 	GetAppContents(w http.ResponseWriter, r *http.Request)
+	ListAppContentsDir(w http.ResponseWriter, r *http.Request)
+	GetAppContentsFile(w http.ResponseWriter, r *http.Request)
+	DownloadAppArchive(w http.ResponseWriter, r *http.Request)
+	DownloadAppArchiveDecrypted(w http.ResponseWriter, r *http.Request)
 	GetAppDashboard(w http.ResponseWriter, r *http.Request)
+	GetAppMetricChart(w http.ResponseWriter, r *http.Request)
 	GetDownstreamOutput(w http.ResponseWriter, r *http.Request)
+	DownloadDownstreamOutput(w http.ResponseWriter, r *http.Request)
+	GetPostDeployTestResults(w http.ResponseWriter, r *http.Request)
+	GetAdmissionValidation(w http.ResponseWriter, r *http.Request)
+	GetDownstreamServiceAccount(w http.ResponseWriter, r *http.Request)
+	SetDownstreamServiceAccount(w http.ResponseWriter, r *http.Request)
 
 	GetKotsadmRegistry(w http.ResponseWriter, r *http.Request)
+	UpdateKotsadmRegistry(w http.ResponseWriter, r *http.Request)
 	GetImageRewriteStatus(w http.ResponseWriter, r *http.Request)
 	UpdateAppRegistry(w http.ResponseWriter, r *http.Request)
 	GetAppRegistry(w http.ResponseWriter, r *http.Request)
 	ValidateAppRegistry(w http.ResponseWriter, r *http.Request)
 
 	UpdateAppConfig(w http.ResponseWriter, r *http.Request)
+	GetAppConfigSchema(w http.ResponseWriter, r *http.Request)
 	CurrentAppConfig(w http.ResponseWriter, r *http.Request)
 	LiveAppConfig(w http.ResponseWriter, r *http.Request)
 	SetAppConfigValues(w http.ResponseWriter, r *http.Request)
+	PreviewAppConfig(w http.ResponseWriter, r *http.Request)
+	RecomputeAppConfig(w http.ResponseWriter, r *http.Request)
+	UploadAppConfigFile(w http.ResponseWriter, r *http.Request)
+	GetAppConfigValues(w http.ResponseWriter, r *http.Request)
+	GetAppConfigValuesDecrypted(w http.ResponseWriter, r *http.Request)
 
 	SyncLicense(w http.ResponseWriter, r *http.Request)
+	ReplaceLicense(w http.ResponseWriter, r *http.Request)
 	GetLicense(w http.ResponseWriter, r *http.Request)
+	GetEntitlementUsage(w http.ResponseWriter, r *http.Request)
 
 	AppUpdateCheck(w http.ResponseWriter, r *http.Request)
 	UpdateCheckerSpec(w http.ResponseWriter, r *http.Request)
+	GetAppSchedules(w http.ResponseWriter, r *http.Request)
+	SetScheduleTimezone(w http.ResponseWriter, r *http.Request)
+	SetAdmissionDryRun(w http.ResponseWriter, r *http.Request)
+	GetApplyPolicy(w http.ResponseWriter, r *http.Request)
+	SetApplyPolicy(w http.ResponseWriter, r *http.Request)
+	GetCanaryDeploys(w http.ResponseWriter, r *http.Request)
+	SetCanaryPolicy(w http.ResponseWriter, r *http.Request)
+	GetFailedUpdateDownloads(w http.ResponseWriter, r *http.Request)
+	RetryFailedUpdateDownloads(w http.ResponseWriter, r *http.Request)
 	RemoveApp(w http.ResponseWriter, r *http.Request)
+	RestoreRemovedApp(w http.ResponseWriter, r *http.Request)
+	SetAppProtected(w http.ResponseWriter, r *http.Request)
+	SetAppLabels(w http.ResponseWriter, r *http.Request)
+	ListRemovedApps(w http.ResponseWriter, r *http.Request)
+	GetFleetReport(w http.ResponseWriter, r *http.Request)
+	GetDeployHistory(w http.ResponseWriter, r *http.Request)
+	ArchiveApp(w http.ResponseWriter, r *http.Request)
+	UnarchiveApp(w http.ResponseWriter, r *http.Request)
+	SetAppAirgapMode(w http.ResponseWriter, r *http.Request)
+	CloneApp(w http.ResponseWriter, r *http.Request)
+	CloneAppFromSnapshot(w http.ResponseWriter, r *http.Request)
+	GetAppMaintenanceMessage(w http.ResponseWriter, r *http.Request)
+	SetAppMaintenanceMessage(w http.ResponseWriter, r *http.Request)
+	ClearAppMaintenanceMessage(w http.ResponseWriter, r *http.Request)
+	GetAppFreeze(w http.ResponseWriter, r *http.Request)
+	SetAppFreeze(w http.ResponseWriter, r *http.Request)
+	ClearAppFreeze(w http.ResponseWriter, r *http.Request)
+
+	// Release feedback
+	GetReleaseFeedbackConsent(w http.ResponseWriter, r *http.Request)
+	SetReleaseFeedbackConsent(w http.ResponseWriter, r *http.Request)
 
 	// App snapshot routes
 	CreateApplicationBackup(w http.ResponseWriter, r *http.Request)
@@ -97,6 +165,9 @@ type KOTSHandler interface {
 	ListBackups(w http.ResponseWriter, r *http.Request)
 	GetSnapshotConfig(w http.ResponseWriter, r *http.Request)
 	SaveSnapshotConfig(w http.ResponseWriter, r *http.Request)
+	GetRestoreDrills(w http.ResponseWriter, r *http.Request)
+	RunRestoreDrill(w http.ResponseWriter, r *http.Request)
+	SetRestoreDrillSchedule(w http.ResponseWriter, r *http.Request)
 
 	// Global snapshot routes
 	ListInstanceBackups(w http.ResponseWriter, r *http.Request)
@@ -107,11 +178,14 @@ type KOTSHandler interface {
 	UpdateGlobalSnapshotSettings(w http.ResponseWriter, r *http.Request)
 	ConfigureFileSystemSnapshotProvider(w http.ResponseWriter, r *http.Request)
 	GetBackup(w http.ResponseWriter, r *http.Request)
+	GetBackupContents(w http.ResponseWriter, r *http.Request)
 	DeleteBackup(w http.ResponseWriter, r *http.Request)
 	RestoreApps(w http.ResponseWriter, r *http.Request)
 	GetRestoreAppsStatus(w http.ResponseWriter, r *http.Request)
 	DownloadSnapshotLogs(w http.ResponseWriter, r *http.Request)
 	GetVeleroStatus(w http.ResponseWriter, r *http.Request)
+	ExportKotsadmState(w http.ResponseWriter, r *http.Request)
+	ImportKotsadmState(w http.ResponseWriter, r *http.Request)
 
 	// KURL
 	GenerateNodeJoinCommandWorker(w http.ResponseWriter, r *http.Request)
@@ -122,8 +196,66 @@ type KOTSHandler interface {
 	DeleteNode(w http.ResponseWriter, r *http.Request)
 	GetKurlNodes(w http.ResponseWriter, r *http.Request)
 
+	// Diagnostics
+	GetRuntimeDiagnostics(w http.ResponseWriter, r *http.Request)
+	GetPprofProfile(w http.ResponseWriter, r *http.Request)
+	GetJanitorDiagnostics(w http.ResponseWriter, r *http.Request)
+	ListScheduledJobs(w http.ResponseWriter, r *http.Request)
+	CheckConnectivity(w http.ResponseWriter, r *http.Request)
+
 	// Prometheus
 	SetPrometheusAddress(w http.ResponseWriter, r *http.Request)
+	GetPrometheusSettings(w http.ResponseWriter, r *http.Request)
+	ValidatePrometheusSettings(w http.ResponseWriter, r *http.Request)
+
+	// CA Bundle
+	GetCABundle(w http.ResponseWriter, r *http.Request)
+	SetCABundle(w http.ResponseWriter, r *http.Request)
+
+	// Client Certificates
+	ListClientCertificates(w http.ResponseWriter, r *http.Request)
+	SetClientCertificate(w http.ResponseWriter, r *http.Request)
+	DeleteClientCertificate(w http.ResponseWriter, r *http.Request)
+
+	// Maintenance
+	GetGlobalMaintenanceMessage(w http.ResponseWriter, r *http.Request)
+	SetGlobalMaintenanceMessage(w http.ResponseWriter, r *http.Request)
+	ClearGlobalMaintenanceMessage(w http.ResponseWriter, r *http.Request)
+
+	// Freeze
+	GetGlobalFreeze(w http.ResponseWriter, r *http.Request)
+	SetGlobalFreeze(w http.ResponseWriter, r *http.Request)
+	ClearGlobalFreeze(w http.ResponseWriter, r *http.Request)
+	GetReadOnlyMode(w http.ResponseWriter, r *http.Request)
+	SetReadOnlyMode(w http.ResponseWriter, r *http.Request)
+
+	// Upload quota
+	GetUploadQuota(w http.ResponseWriter, r *http.Request)
+	SetUploadQuota(w http.ResponseWriter, r *http.Request)
+
+	// Session settings
+	GetSessionSettings(w http.ResponseWriter, r *http.Request)
+	SetSessionSettings(w http.ResponseWriter, r *http.Request)
+
+	// LDAP
+	GetLDAPSettings(w http.ResponseWriter, r *http.Request)
+	SetLDAPSettings(w http.ResponseWriter, r *http.Request)
+	TestLDAPConnection(w http.ResponseWriter, r *http.Request)
+
+	// Temporary access
+	ListTemporaryAccess(w http.ResponseWriter, r *http.Request)
+	CreateTemporaryAccess(w http.ResponseWriter, r *http.Request)
+	RevokeTemporaryAccess(w http.ResponseWriter, r *http.Request)
+
+	// Remote installs
+	ListRemoteInstalls(w http.ResponseWriter, r *http.Request)
+	CreateRemoteInstall(w http.ResponseWriter, r *http.Request)
+	DeleteRemoteInstall(w http.ResponseWriter, r *http.Request)
+	RefreshRemoteInstall(w http.ResponseWriter, r *http.Request)
+
+	// Log settings
+	GetLogSettings(w http.ResponseWriter, r *http.Request)
+	SetLogSettings(w http.ResponseWriter, r *http.Request)
 
 	// GitOps
 	UpdateAppGitOps(w http.ResponseWriter, r *http.Request)
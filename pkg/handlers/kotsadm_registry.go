@@ -0,0 +1,97 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+
+	"github.com/pkg/errors"
+	dockerregistry "github.com/replicatedhq/kots/pkg/docker/registry"
+	"github.com/replicatedhq/kots/pkg/k8sutil"
+	"github.com/replicatedhq/kots/pkg/kotsadm"
+	kotsadmtypes "github.com/replicatedhq/kots/pkg/kotsadm/types"
+	"github.com/replicatedhq/kots/pkg/logger"
+	registrytypes "github.com/replicatedhq/kots/pkg/registry/types"
+)
+
+// UpdateKotsadmRegistryRequest sets the registry of the admin console images, the upstream images are used again
+// if the hostname is empty
+type UpdateKotsadmRegistryRequest struct {
+	Hostname  string `json:"hostname"`
+	Namespace string `json:"namespace"`
+	Username  string `json:"username"`
+	Password  string `json:"password"`
+}
+
+type UpdateKotsadmRegistryResponse struct {
+	Hostname  string `json:"hostname"`
+	Namespace string `json:"namespace"`
+	Username  string `json:"username"`
+}
+
+// UpdateKotsadmRegistry rewrites the images of the admin console components to a private registry and rolls out
+// the deployments and statefulsets, for clusters that can no longer pull the images from docker hub
+func (h *Handler) UpdateKotsadmRegistry(w http.ResponseWriter, r *http.Request) {
+	request := UpdateKotsadmRegistryRequest{}
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		BadRequestJSON(w, r, "failed to decode request body", err)
+		return
+	}
+
+	clientset, err := k8sutil.GetClientset()
+	if err != nil {
+		InternalErrorJSON(w, r, "failed to get clientset", err)
+		return
+	}
+
+	namespace := os.Getenv("POD_NAMESPACE")
+
+	current, err := kotsadm.GetKotsadmOptionsFromCluster(namespace, clientset)
+	if err != nil {
+		InternalErrorJSON(w, r, "failed to get current registry", err)
+		return
+	}
+
+	options := kotsadmtypes.KotsadmOptions{
+		OverrideRegistry:  request.Hostname,
+		OverrideNamespace: request.Namespace,
+		Username:          request.Username,
+		Password:          request.Password,
+		IsReadOnly:        current.IsReadOnly,
+	}
+
+	if request.Hostname != "" {
+		if request.Password == registrytypes.PasswordMask {
+			if current.OverrideRegistry != request.Hostname || current.Password == "" {
+				BadRequestJSON(w, r, fmt.Sprintf("no password found for %s", request.Hostname), errors.New("password is masked"))
+				return
+			}
+			options.Password = current.Password
+		}
+
+		err := dockerregistry.CheckAccess(request.Hostname, options.Username, options.Password, request.Namespace, dockerregistry.ActionPull, nil)
+		if err != nil {
+			logger.Infof("Failed to test pull access to %q with user %q: %v", request.Hostname, request.Username, err)
+			BadRequestJSON(w, r, "failed to pull from registry", err)
+			return
+		}
+	} else {
+		options = kotsadmtypes.KotsadmOptions{
+			IsReadOnly: current.IsReadOnly,
+		}
+	}
+
+	if err := kotsadm.SetKotsadmRegistry(r.Context(), clientset, namespace, options); err != nil {
+		InternalErrorJSON(w, r, "failed to set admin console registry", err)
+		return
+	}
+
+	logger.Infof("Admin console images moved to registry %q", request.Hostname)
+
+	JSON(w, http.StatusOK, UpdateKotsadmRegistryResponse{
+		Hostname:  options.OverrideRegistry,
+		Namespace: options.OverrideNamespace,
+		Username:  options.Username,
+	})
+}
@@ -0,0 +1,60 @@
+package handlers
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/pkg/errors"
+	handlertypes "github.com/replicatedhq/kots/pkg/api/handlers/types"
+	"github.com/replicatedhq/kots/pkg/crypto"
+	"github.com/replicatedhq/kots/pkg/kotsadmstate"
+	kotsadmstatetypes "github.com/replicatedhq/kots/pkg/kotsadmstate/types"
+	"github.com/replicatedhq/kots/pkg/logger"
+	"go.uber.org/zap"
+)
+
+// ExportKotsadmState returns a gzipped tar archive of all apps, versions, registry settings and sessions, encrypted
+// with the key in the export key header. The archive is never sent unencrypted.
+func (h *Handler) ExportKotsadmState(w http.ResponseWriter, r *http.Request) {
+	cipher, err := crypto.AESCipherFromString(r.Header.Get(kotsadmstatetypes.ExportKeyHeader))
+	if err != nil {
+		BadRequestJSON(w, r, fmt.Sprintf("the %s header must be set to an encryption key", kotsadmstatetypes.ExportKeyHeader), err)
+		return
+	}
+
+	archive := bytes.NewBuffer(nil)
+	if err := kotsadmstate.Export(archive); err != nil {
+		InternalErrorJSON(w, r, "failed to export kotsadm state", err)
+		return
+	}
+
+	logger.Info("kotsadm state exported", zap.String("audit", "kotsadm-state-export"), zap.String("user", sessionUserID(r)))
+
+	w.Header().Set("Content-Type", "application/octet-stream")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=kotsadm-state-%s.enc", time.Now().Format("20060102-150405")))
+	w.WriteHeader(http.StatusOK)
+	w.Write(cipher.Encrypt(archive.Bytes()))
+}
+
+// ImportKotsadmState restores an archive created by ExportKotsadmState into a kotsadm without apps
+func (h *Handler) ImportKotsadmState(w http.ResponseWriter, r *http.Request) {
+	opts := kotsadmstatetypes.ImportOptions{
+		Deploy: r.URL.Query().Get("deploy") == "true",
+	}
+
+	importedApps, err := kotsadmstate.Import(r.Body, opts)
+	if err != nil {
+		if errors.Cause(err) == kotsadmstate.ErrNotEmpty {
+			ErrorJSON(w, r, http.StatusConflict, handlertypes.ErrorCodeConflict, err.Error(), nil)
+			return
+		}
+		InternalErrorJSON(w, r, "failed to import kotsadm state", err)
+		return
+	}
+
+	JSON(w, http.StatusOK, handlertypes.ImportKotsadmStateResponse{
+		Apps: importedApps,
+	})
+}
@@ -0,0 +1,264 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/replicatedhq/kots/pkg/ldapauth"
+	ldapauthtypes "github.com/replicatedhq/kots/pkg/ldapauth/types"
+	"github.com/replicatedhq/kots/pkg/logger"
+	"github.com/replicatedhq/kots/pkg/session"
+	"github.com/replicatedhq/kots/pkg/store"
+	usertypes "github.com/replicatedhq/kots/pkg/user/types"
+)
+
+// GetLDAPSettingsResponse does not include the bind password
+type GetLDAPSettingsResponse struct {
+	ldapauthtypes.Settings
+	HasBindPassword bool `json:"hasBindPassword"`
+}
+
+type SetLDAPSettingsRequest struct {
+	// BindPassword in the settings replaces the stored one only if it is set
+	Settings ldapauthtypes.Settings `json:"settings"`
+	// ClearBindPassword removes the stored bind password, for anonymous searches
+	ClearBindPassword bool `json:"clearBindPassword"`
+	// SkipValidation saves the settings without checking that the server can be reached
+	SkipValidation bool `json:"skipValidation"`
+}
+
+type TestLDAPConnectionRequest struct {
+	// Settings are tested instead of the saved settings if set. The saved bind password is used if it is not set.
+	Settings *ldapauthtypes.Settings `json:"settings,omitempty"`
+	// Username and Password test logging in as a user if set
+	Username string `json:"username,omitempty"`
+	Password string `json:"password,omitempty"`
+}
+
+type TestLDAPConnectionResponse struct {
+	Success bool   `json:"success"`
+	Error   string `json:"error,omitempty"`
+	// Groups are the groups the test user is a member of, and Roles the roles they map to
+	Groups []string `json:"groups,omitempty"`
+	Roles  []string `json:"roles,omitempty"`
+}
+
+type LDAPLoginRequest struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+}
+
+func (h *Handler) GetLDAPSettings(w http.ResponseWriter, r *http.Request) {
+	settings, err := ldapauth.GetSettings()
+	if err != nil {
+		InternalErrorJSON(w, r, "failed to get ldap settings", err)
+		return
+	}
+
+	response := GetLDAPSettingsResponse{
+		Settings:        *settings,
+		HasBindPassword: settings.BindPassword != "",
+	}
+	response.BindPassword = ""
+
+	JSON(w, http.StatusOK, response)
+}
+
+func (h *Handler) SetLDAPSettings(w http.ResponseWriter, r *http.Request) {
+	setLDAPSettingsRequest := SetLDAPSettingsRequest{}
+	if err := json.NewDecoder(r.Body).Decode(&setLDAPSettingsRequest); err != nil {
+		BadRequestJSON(w, r, "failed to decode request body", err)
+		return
+	}
+
+	settings, err := mergeLDAPSettings(setLDAPSettingsRequest.Settings, setLDAPSettingsRequest.ClearBindPassword)
+	if err == errBindPasswordRequired {
+		BadRequestJSON(w, r, err.Error(), err)
+		return
+	}
+	if err != nil {
+		InternalErrorJSON(w, r, "failed to get ldap settings", err)
+		return
+	}
+
+	if settings.Enabled {
+		if err := ldapauth.Validate(settings); err != nil {
+			BadRequestJSON(w, r, fmt.Sprintf("invalid ldap settings: %v", err), err)
+			return
+		}
+		if !setLDAPSettingsRequest.SkipValidation {
+			if err := ldapauth.TestConnection(settings); err != nil {
+				BadRequestJSON(w, r, fmt.Sprintf("failed to connect to ldap server: %v", err), err)
+				return
+			}
+		}
+	}
+
+	if err := store.GetStore().SetLDAPSettings(settings); err != nil {
+		InternalErrorJSON(w, r, "failed to set ldap settings", err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// TestLDAPConnection connects to the ldap server and optionally logs in as a user, without creating a session. It
+// requires write access because the stored bind password is sent to the server in the request settings.
+func (h *Handler) TestLDAPConnection(w http.ResponseWriter, r *http.Request) {
+	testLDAPConnectionRequest := TestLDAPConnectionRequest{}
+	if err := json.NewDecoder(r.Body).Decode(&testLDAPConnectionRequest); err != nil {
+		BadRequestJSON(w, r, "failed to decode request body", err)
+		return
+	}
+
+	var settings ldapauthtypes.Settings
+	if testLDAPConnectionRequest.Settings != nil {
+		s, err := mergeLDAPSettings(*testLDAPConnectionRequest.Settings, false)
+		if err == errBindPasswordRequired {
+			BadRequestJSON(w, r, err.Error(), err)
+			return
+		}
+		if err != nil {
+			InternalErrorJSON(w, r, "failed to get ldap settings", err)
+			return
+		}
+		settings = s
+	} else {
+		s, err := ldapauth.GetSettings()
+		if err != nil {
+			InternalErrorJSON(w, r, "failed to get ldap settings", err)
+			return
+		}
+		settings = *s
+	}
+
+	response := TestLDAPConnectionResponse{}
+	if err := ldapauth.Validate(settings); err != nil {
+		response.Error = err.Error()
+		JSON(w, http.StatusOK, response)
+		return
+	}
+	if err := ldapauth.TestConnection(settings); err != nil {
+		response.Error = err.Error()
+		JSON(w, http.StatusOK, response)
+		return
+	}
+
+	if testLDAPConnectionRequest.Username != "" {
+		user, err := ldapauth.Authenticate(settings, testLDAPConnectionRequest.Username, testLDAPConnectionRequest.Password)
+		if err != nil {
+			response.Error = err.Error()
+			JSON(w, http.StatusOK, response)
+			return
+		}
+		response.Groups = user.Groups
+		response.Roles = session.GetSessionRolesFromRBAC(user.Groups, settings.Groups)
+	}
+
+	response.Success = true
+	JSON(w, http.StatusOK, response)
+}
+
+// LDAPLogin creates a session for an ldap user with the roles of the groups they are a member of
+func (h *Handler) LDAPLogin(w http.ResponseWriter, r *http.Request) {
+	loginResponse := LoginResponse{}
+
+	loginRequest := LDAPLoginRequest{}
+	if err := json.NewDecoder(r.Body).Decode(&loginRequest); err != nil {
+		logger.Error(err)
+		JSON(w, http.StatusBadRequest, loginResponse)
+		return
+	}
+
+	settings, err := ldapauth.GetSettings()
+	if err != nil {
+		logger.Error(err)
+		JSON(w, http.StatusInternalServerError, loginResponse)
+		return
+	}
+	if !settings.Enabled {
+		loginResponse.Error = "LDAP authentication is not enabled."
+		JSON(w, http.StatusForbidden, loginResponse)
+		return
+	}
+
+	ldapUser, err := ldapauth.Authenticate(*settings, loginRequest.Username, loginRequest.Password)
+	if err == ldapauth.ErrInvalidCredentials {
+		loginResponse.Error = "Invalid username or password. Please try again."
+		JSON(w, http.StatusUnauthorized, loginResponse)
+		return
+	} else if err != nil {
+		logger.Error(err)
+		loginResponse.Error = "Failed to connect to the LDAP server."
+		JSON(w, http.StatusInternalServerError, loginResponse)
+		return
+	}
+
+	// unlike the identity service there are no default groups, any user in the directory would be an admin
+	roles := session.GetSessionRolesFromRBAC(ldapUser.Groups, settings.Groups)
+	if len(roles) == 0 {
+		loginResponse.Error = "User must be a part of at least 1 group with roles."
+		JSON(w, http.StatusForbidden, loginResponse)
+		return
+	}
+
+	sessionSettings, err := session.GetSettings(store.GetStore())
+	if err != nil {
+		logger.Error(err)
+		JSON(w, http.StatusInternalServerError, loginResponse)
+		return
+	}
+
+	user := &usertypes.User{
+		ID: ldapUser.Username,
+	}
+
+	issuedAt := time.Now()
+	expiresAt := session.ExpiresAt(sessionSettings, issuedAt)
+	createdSession, err := store.GetStore().CreateSession(user, issuedAt, expiresAt, roles)
+	if err != nil {
+		logger.Error(err)
+		JSON(w, http.StatusInternalServerError, loginResponse)
+		return
+	}
+
+	signedJWT, err := session.SignJWT(createdSession)
+	if err != nil {
+		logger.Error(err)
+		JSON(w, http.StatusInternalServerError, loginResponse)
+		return
+	}
+
+	loginResponse.Token = fmt.Sprintf("Bearer %s", signedJWT)
+	loginResponse.CSRFToken = session.CSRFToken(createdSession)
+
+	JSON(w, http.StatusOK, loginResponse)
+}
+
+// errBindPasswordRequired is returned when the stored bind password can't be used with the new settings
+var errBindPasswordRequired = errors.New("the bind password is required when the host, port or bind dn change")
+
+// mergeLDAPSettings keeps the stored bind password if the settings do not set one, so that it does not have to be
+// sent back to change other settings. It is only kept if the host, port and bind dn are unchanged.
+func mergeLDAPSettings(settings ldapauthtypes.Settings, clearBindPassword bool) (ldapauthtypes.Settings, error) {
+	if settings.BindPassword != "" || clearBindPassword {
+		return settings, nil
+	}
+
+	stored, err := ldapauth.GetSettings()
+	if err != nil {
+		return ldapauthtypes.Settings{}, err
+	}
+	if stored.BindPassword == "" || settings.BindDN == "" {
+		return settings, nil
+	}
+	if !ldapauth.CanReuseBindPassword(*stored, settings) {
+		return ldapauthtypes.Settings{}, errBindPasswordRequired
+	}
+	settings.BindPassword = stored.BindPassword
+
+	return settings, nil
+}
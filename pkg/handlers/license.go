@@ -13,6 +13,7 @@ import (
 	"github.com/gorilla/mux"
 	"github.com/pkg/errors"
 	kotsv1beta1 "github.com/replicatedhq/kots/kotskinds/apis/kots/v1beta1"
+	"github.com/replicatedhq/kots/pkg/applock"
 	kotsadmtypes "github.com/replicatedhq/kots/pkg/kotsadm/types"
 	license "github.com/replicatedhq/kots/pkg/kotsadmlicense"
 	"github.com/replicatedhq/kots/pkg/kotsutil"
@@ -23,6 +24,8 @@ import (
 	kotspull "github.com/replicatedhq/kots/pkg/pull"
 	"github.com/replicatedhq/kots/pkg/registry"
 	"github.com/replicatedhq/kots/pkg/store"
+	"github.com/replicatedhq/kots/pkg/uploadquota"
+	"github.com/replicatedhq/kots/pkg/util"
 	serializer "k8s.io/apimachinery/pkg/runtime/serializer/json"
 	"k8s.io/client-go/kubernetes/scheme"
 )
@@ -53,6 +56,17 @@ type SyncLicenseResponse struct {
 	License LicenseResponse `json:"license"`
 }
 
+type ReplaceLicenseRequest struct {
+	LicenseData string `json:"licenseData"`
+}
+
+type ReplaceLicenseResponse struct {
+	Success  bool            `json:"success"`
+	Error    string          `json:"error,omitempty"`
+	Sequence int64           `json:"sequence"`
+	License  LicenseResponse `json:"license"`
+}
+
 type GetLicenseResponse struct {
 	Success bool            `json:"success"`
 	Error   string          `json:"error,omitempty"`
@@ -122,6 +136,12 @@ func (h *Handler) SyncLicense(w http.ResponseWriter, r *http.Request) {
 	if err != nil {
 		syncLicenseResponse.Error = "failed to sync license"
 		logger.Error(errors.Wrap(err, syncLicenseResponse.Error))
+		if applock.IsLocked(err) {
+			syncLicenseResponse.Error = errors.Cause(err).Error()
+			setAppLockedRetryAfter(w)
+			JSON(w, http.StatusConflict, syncLicenseResponse)
+			return
+		}
 		JSON(w, http.StatusInternalServerError, syncLicenseResponse)
 		return
 	}
@@ -154,6 +174,76 @@ func (h *Handler) SyncLicense(w http.ResponseWriter, r *http.Request) {
 	JSON(w, http.StatusOK, syncLicenseResponse)
 }
 
+// ReplaceLicense replaces the license of an app with a different license for the same app. A new version is created
+// with the entitlements of the new license.
+func (h *Handler) ReplaceLicense(w http.ResponseWriter, r *http.Request) {
+	replaceLicenseResponse := ReplaceLicenseResponse{
+		Success: false,
+	}
+
+	replaceLicenseRequest := ReplaceLicenseRequest{}
+	if err := json.NewDecoder(r.Body).Decode(&replaceLicenseRequest); err != nil {
+		replaceLicenseResponse.Error = "failed to decode request"
+		logger.Error(errors.Wrap(err, replaceLicenseResponse.Error))
+		JSON(w, http.StatusBadRequest, replaceLicenseResponse)
+		return
+	}
+
+	foundApp, err := store.GetStore().GetAppFromSlug(mux.Vars(r)["appSlug"])
+	if err != nil {
+		replaceLicenseResponse.Error = "failed to get app from slug"
+		logger.Error(errors.Wrap(err, replaceLicenseResponse.Error))
+		JSON(w, http.StatusNotFound, replaceLicenseResponse)
+		return
+	}
+
+	newLicense, newSequence, err := license.Replace(foundApp, replaceLicenseRequest.LicenseData, sessionUserID(r))
+	if err != nil {
+		replaceLicenseResponse.Error = "failed to replace license"
+		logger.Error(errors.Wrap(err, replaceLicenseResponse.Error))
+		if cause, ok := errors.Cause(err).(util.ActionableError); ok {
+			replaceLicenseResponse.Error = cause.Message
+			JSON(w, http.StatusBadRequest, replaceLicenseResponse)
+			return
+		}
+		if applock.IsLocked(err) {
+			replaceLicenseResponse.Error = errors.Cause(err).Error()
+			setAppLockedRetryAfter(w)
+			JSON(w, http.StatusConflict, replaceLicenseResponse)
+			return
+		}
+		JSON(w, http.StatusInternalServerError, replaceLicenseResponse)
+		return
+	}
+
+	entitlements, expiresAt, err := getLicenseEntitlements(newLicense)
+	if err != nil {
+		replaceLicenseResponse.Error = "failed to get license entitlements"
+		logger.Error(errors.Wrap(err, replaceLicenseResponse.Error))
+		JSON(w, http.StatusInternalServerError, replaceLicenseResponse)
+		return
+	}
+
+	replaceLicenseResponse.Success = true
+	replaceLicenseResponse.Sequence = newSequence
+	replaceLicenseResponse.License = LicenseResponse{
+		ID:                         newLicense.Spec.LicenseID,
+		Assignee:                   newLicense.Spec.CustomerName,
+		ChannelName:                newLicense.Spec.ChannelName,
+		LicenseSequence:            newLicense.Spec.LicenseSequence,
+		LicenseType:                newLicense.Spec.LicenseType,
+		Entitlements:               entitlements,
+		ExpiresAt:                  expiresAt,
+		IsAirgapSupported:          newLicense.Spec.IsAirgapSupported,
+		IsGitOpsSupported:          newLicense.Spec.IsGitOpsSupported,
+		IsIdentityServiceSupported: newLicense.Spec.IsIdentityServiceSupported,
+		IsGeoaxisSupported:         newLicense.Spec.IsGeoaxisSupported,
+		IsSnapshotSupported:        newLicense.Spec.IsSnapshotSupported,
+	}
+
+	JSON(w, http.StatusOK, replaceLicenseResponse)
+}
+
 func (h *Handler) GetLicense(w http.ResponseWriter, r *http.Request) {
 	getLicenseResponse := GetLicenseResponse{
 		Success: false,
@@ -235,8 +325,21 @@ func getLicenseEntitlements(license *kotsv1beta1.License) ([]EntitlementResponse
 }
 
 func (h *Handler) UploadNewLicense(w http.ResponseWriter, r *http.Request) {
+	quota, err := uploadquota.Get()
+	if err != nil {
+		InternalErrorJSON(w, r, "failed to get upload quota", err)
+		return
+	}
+	if !limitUploadBody(w, r, "license", quota.LicenseBytes) {
+		return
+	}
+
 	uploadLicenseRequest := UploadLicenseRequest{}
 	if err := json.NewDecoder(r.Body).Decode(&uploadLicenseRequest); err != nil {
+		if isBodyTooLarge(err) {
+			uploadErrorJSON(w, r, uploadquota.TooLargeError{Name: "license", Limit: quota.LicenseBytes})
+			return
+		}
 		logger.Error(err)
 		w.WriteHeader(500)
 		return
@@ -0,0 +1,105 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/pkg/errors"
+	"github.com/replicatedhq/kots/pkg/logger"
+	loggertypes "github.com/replicatedhq/kots/pkg/logger/types"
+	"github.com/replicatedhq/kots/pkg/socketservice"
+	"github.com/replicatedhq/kots/pkg/store"
+)
+
+// GetLogSettingsResponse does not include the authorization header of the sink
+type GetLogSettingsResponse struct {
+	loggertypes.Settings
+	HasAuthHeader bool `json:"hasAuthHeader"`
+}
+
+type SetLogSettingsRequest struct {
+	// AuthHeader in the sink replaces the stored one only if it is set
+	Settings loggertypes.Settings `json:"settings"`
+	// ClearAuthHeader removes the stored authorization header of the sink
+	ClearAuthHeader bool `json:"clearAuthHeader"`
+}
+
+func (h *Handler) GetLogSettings(w http.ResponseWriter, r *http.Request) {
+	settings, err := store.GetStore().GetLogSettings()
+	if err != nil {
+		InternalErrorJSON(w, r, "failed to get log settings", err)
+		return
+	}
+	if settings == nil {
+		settings = &loggertypes.Settings{}
+	}
+	// the level can also be set with the --debug flag
+	settings.Level = logger.GetLevel()
+
+	response := GetLogSettingsResponse{
+		Settings: *settings,
+	}
+	if settings.Sink != nil {
+		sink := *settings.Sink
+		response.HasAuthHeader = sink.AuthHeader != ""
+		sink.AuthHeader = ""
+		response.Sink = &sink
+	}
+
+	JSON(w, http.StatusOK, response)
+}
+
+// SetLogSettings applies the log level to kotsadm and the connected operators and ships the logs of kotsadm to the
+// sink. The sink is created before the settings are saved, so a syslog server that cannot be dialed is rejected.
+func (h *Handler) SetLogSettings(w http.ResponseWriter, r *http.Request) {
+	setLogSettingsRequest := SetLogSettingsRequest{}
+	if err := json.NewDecoder(r.Body).Decode(&setLogSettingsRequest); err != nil {
+		BadRequestJSON(w, r, "failed to decode request body", err)
+		return
+	}
+
+	settings, err := mergeLogSettings(setLogSettingsRequest.Settings, setLogSettingsRequest.ClearAuthHeader)
+	if err != nil {
+		InternalErrorJSON(w, r, "failed to get log settings", err)
+		return
+	}
+
+	if err := logger.Configure(settings); err != nil {
+		BadRequestJSON(w, r, fmt.Sprintf("invalid log settings: %v", err), err)
+		return
+	}
+
+	if err := store.GetStore().SetLogSettings(settings); err != nil {
+		InternalErrorJSON(w, r, "failed to set log settings", err)
+		return
+	}
+
+	if err := socketservice.SetOperatorLogLevel(logger.GetLevel()); err != nil {
+		logger.Error(errors.Wrap(err, "failed to set operator log level"))
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// mergeLogSettings keeps the stored authorization header of the sink if the settings do not set one, so that it does
+// not have to be sent back to change other settings
+func mergeLogSettings(settings loggertypes.Settings, clearAuthHeader bool) (loggertypes.Settings, error) {
+	if settings.Sink == nil || settings.Sink.AuthHeader != "" || clearAuthHeader {
+		return settings, nil
+	}
+
+	stored, err := store.GetStore().GetLogSettings()
+	if err != nil {
+		return loggertypes.Settings{}, err
+	}
+	if stored == nil || stored.Sink == nil {
+		return settings, nil
+	}
+
+	sink := *settings.Sink
+	sink.AuthHeader = stored.Sink.AuthHeader
+	settings.Sink = &sink
+
+	return settings, nil
+}
@@ -16,6 +16,7 @@ import (
 	identityclient "github.com/replicatedhq/kots/pkg/identity/client"
 	ingress "github.com/replicatedhq/kots/pkg/ingress"
 	"github.com/replicatedhq/kots/pkg/k8sutil"
+	"github.com/replicatedhq/kots/pkg/ldapauth"
 	"github.com/replicatedhq/kots/pkg/logger"
 	"github.com/replicatedhq/kots/pkg/session"
 	"github.com/replicatedhq/kots/pkg/store"
@@ -32,6 +33,8 @@ type LoginRequest struct {
 type LoginResponse struct {
 	Error string `json:"error,omitempty"`
 	Token string `json:"token,omitempty"`
+	// CSRFToken must be sent in the X-Csrf-Token header of mutating requests
+	CSRFToken string `json:"csrfToken,omitempty"`
 }
 
 type LoginMethod string
@@ -92,7 +95,15 @@ func (h *Handler) Login(w http.ResponseWriter, r *http.Request) {
 	// TODO: super user permissions
 	roles := session.GetSessionRolesFromRBAC(nil, identity.DefaultGroups)
 
-	issuedAt, expiresAt := time.Now(), time.Now().AddDate(0, 0, 14)
+	sessionSettings, err := session.GetSettings(store.GetStore())
+	if err != nil {
+		logger.Error(err)
+		JSON(w, http.StatusInternalServerError, loginResponse)
+		return
+	}
+
+	issuedAt := time.Now()
+	expiresAt := session.ExpiresAt(sessionSettings, issuedAt)
 	createdSession, err := store.GetStore().CreateSession(foundUser, issuedAt, expiresAt, roles)
 	if err != nil {
 		logger.Error(err)
@@ -108,6 +119,7 @@ func (h *Handler) Login(w http.ResponseWriter, r *http.Request) {
 	}
 
 	loginResponse.Token = fmt.Sprintf("Bearer %s", signedJWT)
+	loginResponse.CSRFToken = session.CSRFToken(createdSession)
 
 	JSON(w, http.StatusOK, loginResponse)
 }
@@ -335,7 +347,15 @@ func (h *Handler) OIDCLoginCallback(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	issuedAt, expiresAt := time.Now(), time.Now().AddDate(0, 0, 14)
+	sessionSettings, err := session.GetSettings(store.GetStore())
+	if err != nil {
+		logger.Error(errors.Wrap(err, "failed to get session settings"))
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	issuedAt := time.Now()
+	expiresAt := session.ExpiresAt(sessionSettings, issuedAt)
 	createdSession, err := store.GetStore().CreateSession(user, issuedAt, expiresAt, roles) // idToken.IssuedAt, idToken.Expiry
 	if err != nil {
 		logger.Error(errors.Wrap(err, "failed to create session"))
@@ -389,7 +409,9 @@ func (h *Handler) OIDCLoginCallback(w http.ResponseWriter, r *http.Request) {
 
 type GetLoginInfoResponse struct {
 	Method LoginMethod `json:"method"`
-	Error  string      `json:"error,omitempty"`
+	// LDAPEnabled is true if users can also log in with their ldap credentials
+	LDAPEnabled bool   `json:"ldapEnabled"`
+	Error       string `json:"error,omitempty"`
 }
 
 func (h *Handler) GetLoginInfo(w http.ResponseWriter, r *http.Request) {
@@ -401,6 +423,15 @@ func (h *Handler) GetLoginInfo(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
+
+	ldapSettings, err := ldapauth.GetSettings()
+	if err != nil {
+		logger.Error(errors.Wrap(err, "failed to get ldap settings"))
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	getLoginInfoResponse.LDAPEnabled = ldapSettings.Enabled
+
 	if !identityConfig.Spec.Enabled || !identityConfig.Spec.DisablePasswordAuth {
 		getLoginInfoResponse.Method = PasswordAuth
 		JSON(w, http.StatusOK, getLoginInfoResponse)
@@ -0,0 +1,124 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/replicatedhq/kots/pkg/maintenance"
+	maintenancetypes "github.com/replicatedhq/kots/pkg/maintenance/types"
+	"github.com/replicatedhq/kots/pkg/store"
+)
+
+type GetMaintenanceMessageResponse struct {
+	// MaintenanceMessage is nil if no message is set
+	MaintenanceMessage *maintenancetypes.MaintenanceMessage `json:"maintenanceMessage"`
+	IsActive           bool                                 `json:"isActive"`
+}
+
+func (h *Handler) GetGlobalMaintenanceMessage(w http.ResponseWriter, r *http.Request) {
+	message, err := store.GetStore().GetGlobalMaintenanceMessage()
+	if err != nil {
+		InternalErrorJSON(w, r, "failed to get maintenance message", err)
+		return
+	}
+
+	JSON(w, http.StatusOK, getMaintenanceMessageResponse(message))
+}
+
+func (h *Handler) SetGlobalMaintenanceMessage(w http.ResponseWriter, r *http.Request) {
+	message := maintenancetypes.MaintenanceMessage{}
+	if err := json.NewDecoder(r.Body).Decode(&message); err != nil {
+		BadRequestJSON(w, r, "failed to decode request body", err)
+		return
+	}
+
+	if err := maintenance.Validate(message); err != nil {
+		BadRequestJSON(w, r, "invalid maintenance message", err)
+		return
+	}
+
+	if err := store.GetStore().SetGlobalMaintenanceMessage(&message); err != nil {
+		InternalErrorJSON(w, r, "failed to set maintenance message", err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *Handler) ClearGlobalMaintenanceMessage(w http.ResponseWriter, r *http.Request) {
+	if err := store.GetStore().SetGlobalMaintenanceMessage(nil); err != nil {
+		InternalErrorJSON(w, r, "failed to clear maintenance message", err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *Handler) GetAppMaintenanceMessage(w http.ResponseWriter, r *http.Request) {
+	foundApp, err := store.GetStore().GetAppFromSlug(mux.Vars(r)["appSlug"])
+	if err != nil {
+		NotFoundJSON(w, r, "failed to get app from slug", err)
+		return
+	}
+
+	message, err := store.GetStore().GetAppMaintenanceMessage(foundApp.ID)
+	if err != nil {
+		InternalErrorJSON(w, r, "failed to get app maintenance message", err)
+		return
+	}
+
+	JSON(w, http.StatusOK, getMaintenanceMessageResponse(message))
+}
+
+func (h *Handler) SetAppMaintenanceMessage(w http.ResponseWriter, r *http.Request) {
+	message := maintenancetypes.MaintenanceMessage{}
+	if err := json.NewDecoder(r.Body).Decode(&message); err != nil {
+		BadRequestJSON(w, r, "failed to decode request body", err)
+		return
+	}
+
+	if err := maintenance.Validate(message); err != nil {
+		BadRequestJSON(w, r, "invalid maintenance message", err)
+		return
+	}
+
+	foundApp, err := store.GetStore().GetAppFromSlug(mux.Vars(r)["appSlug"])
+	if err != nil {
+		NotFoundJSON(w, r, "failed to get app from slug", err)
+		return
+	}
+
+	if err := store.GetStore().SetAppMaintenanceMessage(foundApp.ID, &message); err != nil {
+		InternalErrorJSON(w, r, "failed to set app maintenance message", err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *Handler) ClearAppMaintenanceMessage(w http.ResponseWriter, r *http.Request) {
+	foundApp, err := store.GetStore().GetAppFromSlug(mux.Vars(r)["appSlug"])
+	if err != nil {
+		NotFoundJSON(w, r, "failed to get app from slug", err)
+		return
+	}
+
+	if err := store.GetStore().SetAppMaintenanceMessage(foundApp.ID, nil); err != nil {
+		InternalErrorJSON(w, r, "failed to clear app maintenance message", err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func getMaintenanceMessageResponse(message *maintenancetypes.MaintenanceMessage) GetMaintenanceMessageResponse {
+	response := GetMaintenanceMessageResponse{
+		MaintenanceMessage: message,
+	}
+	if message != nil {
+		response.IsActive = message.IsActive(time.Now())
+	}
+	return response
+}
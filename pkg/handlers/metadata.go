@@ -7,6 +7,7 @@ import (
 
 	"github.com/pkg/errors"
 	kotsv1beta1 "github.com/replicatedhq/kots/kotskinds/apis/kots/v1beta1"
+	"github.com/replicatedhq/kots/pkg/assetcache"
 	"github.com/replicatedhq/kots/pkg/k8sutil"
 	"github.com/replicatedhq/kots/pkg/kurl"
 	"github.com/replicatedhq/kots/pkg/logger"
@@ -21,6 +22,9 @@ type MetadataResponse struct {
 	Namespace     string `json:"namespace"`
 	IsKurlEnabled bool   `json:"isKurlEnabled"`
 	UpstreamURI   string `json:"upstreamUri"`
+	// CachedIconPath is the path of the cached icon relative to the api, so that airgapped consoles do not wait for
+	// the icon uri to time out. It is empty if IconURI should be used as is.
+	CachedIconPath string `json:"cachedIconPath,omitempty"`
 }
 
 // Metadata route is UNAUTHENTICATED
@@ -80,5 +84,11 @@ func (h *Handler) Metadata(w http.ResponseWriter, r *http.Request) {
 		metadataResponse.UpstreamURI = brandingConfigMap.Data["upstreamUri"]
 	}
 
-	JSON(w, 200, metadataResponse)
+	cachedIconPath, err := assetcache.CachedPath(metadataResponse.IconURI)
+	if err != nil {
+		logger.Error(errors.Wrap(err, "failed to get cached icon path"))
+	}
+	metadataResponse.CachedIconPath = cachedIconPath
+
+	JSONWithETag(w, r, metadataResponse)
 }
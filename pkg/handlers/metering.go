@@ -0,0 +1,150 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/pkg/errors"
+	apptypes "github.com/replicatedhq/kots/pkg/app/types"
+	"github.com/replicatedhq/kots/pkg/logger"
+	"github.com/replicatedhq/kots/pkg/metering"
+	meteringtypes "github.com/replicatedhq/kots/pkg/metering/types"
+	"github.com/replicatedhq/kots/pkg/reporting"
+	"github.com/replicatedhq/kots/pkg/store"
+)
+
+type ReportEntitlementUsageRequest struct {
+	Entitlement string `json:"entitlement"`
+	Source      string `json:"source"`
+	Value       int64  `json:"value"`
+}
+
+type ReportEntitlementUsageResponse struct {
+	Success bool                                  `json:"success"`
+	Error   string                                `json:"error,omitempty"`
+	Usage   *meteringtypes.EntitlementUsageStatus `json:"usage,omitempty"`
+}
+
+type GetEntitlementUsageResponse struct {
+	Success bool                                   `json:"success"`
+	Error   string                                 `json:"error,omitempty"`
+	Usage   []meteringtypes.EntitlementUsageStatus `json:"usage"`
+}
+
+// ReportEntitlementUsage is called by the application to report consumption of a metered entitlement.
+// The application authenticates with its license id as the basic auth username.
+func (h *Handler) ReportEntitlementUsage(w http.ResponseWriter, r *http.Request) {
+	reportEntitlementUsageResponse := ReportEntitlementUsageResponse{
+		Success: false,
+	}
+
+	licenseID, _, ok := r.BasicAuth()
+	if !ok || licenseID == "" {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	a, err := getAppForLicenseID(licenseID)
+	if err != nil {
+		logger.Error(err)
+		reportEntitlementUsageResponse.Error = "failed to get app for license"
+		JSON(w, http.StatusInternalServerError, reportEntitlementUsageResponse)
+		return
+	}
+	if a == nil {
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+
+	request := ReportEntitlementUsageRequest{}
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		logger.Error(err)
+		reportEntitlementUsageResponse.Error = "failed to decode request body"
+		JSON(w, http.StatusBadRequest, reportEntitlementUsageResponse)
+		return
+	}
+	if request.Entitlement == "" || request.Value < 0 {
+		reportEntitlementUsageResponse.Error = "entitlement and a non-negative value are required"
+		JSON(w, http.StatusBadRequest, reportEntitlementUsageResponse)
+		return
+	}
+	if request.Source == "" {
+		request.Source = "default"
+	}
+
+	status, err := metering.ReportUsage(a.ID, request.Entitlement, request.Source, request.Value)
+	if errors.Cause(err) == metering.ErrUnknownEntitlement {
+		reportEntitlementUsageResponse.Error = err.Error()
+		JSON(w, http.StatusBadRequest, reportEntitlementUsageResponse)
+		return
+	}
+	if errors.Cause(err) == metering.ErrLimitExceeded {
+		reportEntitlementUsageResponse.Error = err.Error()
+		reportEntitlementUsageResponse.Usage = status
+		JSON(w, http.StatusForbidden, reportEntitlementUsageResponse)
+		return
+	}
+	if err != nil {
+		logger.Error(err)
+		reportEntitlementUsageResponse.Error = "failed to report usage"
+		JSON(w, http.StatusInternalServerError, reportEntitlementUsageResponse)
+		return
+	}
+
+	go func() {
+		if err := reporting.SendEntitlementUsage(a.ID); err != nil {
+			logger.Debugf("failed to send entitlement usage: %v", err)
+		}
+	}()
+
+	reportEntitlementUsageResponse.Success = true
+	reportEntitlementUsageResponse.Usage = status
+	JSON(w, http.StatusOK, reportEntitlementUsageResponse)
+}
+
+func (h *Handler) GetEntitlementUsage(w http.ResponseWriter, r *http.Request) {
+	getEntitlementUsageResponse := GetEntitlementUsageResponse{
+		Success: false,
+		Usage:   []meteringtypes.EntitlementUsageStatus{},
+	}
+
+	a, err := store.GetStore().GetAppFromSlug(mux.Vars(r)["appSlug"])
+	if err != nil {
+		logger.Error(err)
+		getEntitlementUsageResponse.Error = "failed to get app from slug"
+		JSON(w, http.StatusInternalServerError, getEntitlementUsageResponse)
+		return
+	}
+
+	usage, err := metering.GetUsage(a.ID)
+	if err != nil {
+		logger.Error(err)
+		getEntitlementUsageResponse.Error = "failed to get entitlement usage"
+		JSON(w, http.StatusInternalServerError, getEntitlementUsageResponse)
+		return
+	}
+
+	getEntitlementUsageResponse.Success = true
+	getEntitlementUsageResponse.Usage = usage
+	JSON(w, http.StatusOK, getEntitlementUsageResponse)
+}
+
+func getAppForLicenseID(licenseID string) (*apptypes.App, error) {
+	apps, err := store.GetStore().ListInstalledApps()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list installed apps")
+	}
+
+	for _, a := range apps {
+		license, err := store.GetStore().GetLatestLicenseForApp(a.ID)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to get license for app %s", a.Slug)
+		}
+		if license.Spec.LicenseID == licenseID {
+			return a, nil
+		}
+	}
+
+	return nil, nil
+}
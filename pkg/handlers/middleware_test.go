@@ -0,0 +1,101 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/replicatedhq/kots/pkg/logger"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_RequestLoggingMiddleware(t *testing.T) {
+	tests := []struct {
+		name            string
+		clientRequestID string
+	}{
+		{
+			name: "assigns a request id",
+		},
+		{
+			name:            "keeps the client request id",
+			clientRequestID: "client-id",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			req := require.New(t)
+
+			contextRequestID := ""
+			handler := RequestLoggingMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				contextRequestID = logger.RequestIDFromContext(r.Context())
+				w.WriteHeader(http.StatusTeapot)
+			}))
+
+			r := httptest.NewRequest("GET", "/api/v1/ping", nil)
+			if test.clientRequestID != "" {
+				r.Header.Set(RequestIDHeader, test.clientRequestID)
+			}
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, r)
+
+			req.Equal(http.StatusTeapot, w.Code)
+			req.NotEmpty(contextRequestID)
+			req.Equal(contextRequestID, w.Header().Get(RequestIDHeader))
+			if test.clientRequestID != "" {
+				req.Equal(test.clientRequestID, contextRequestID)
+			}
+		})
+	}
+}
+
+func Test_RequestLoggingMiddlewareHijack(t *testing.T) {
+	req := require.New(t)
+
+	handler := RequestLoggingMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hijacker, ok := w.(http.Hijacker)
+		req.True(ok)
+
+		conn, bufrw, err := hijacker.Hijack()
+		req.NoError(err)
+		defer conn.Close()
+
+		bufrw.WriteString("HTTP/1.1 101 Switching Protocols\r\n\r\n")
+		bufrw.Flush()
+	}))
+
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	resp, err := http.Get(server.URL + "/socket.io/")
+	req.NoError(err)
+	defer resp.Body.Close()
+
+	req.Equal(http.StatusSwitchingProtocols, resp.StatusCode)
+}
+
+func Test_TracingMiddlewareHijack(t *testing.T) {
+	req := require.New(t)
+
+	handler := TracingMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hijacker, ok := w.(http.Hijacker)
+		req.True(ok)
+
+		conn, bufrw, err := hijacker.Hijack()
+		req.NoError(err)
+		defer conn.Close()
+
+		bufrw.WriteString("HTTP/1.1 101 Switching Protocols\r\n\r\n")
+		bufrw.Flush()
+	}))
+
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	resp, err := http.Get(server.URL + "/socket.io/")
+	req.NoError(err)
+	defer resp.Body.Close()
+
+	req.Equal(http.StatusSwitchingProtocols, resp.StatusCode)
+}
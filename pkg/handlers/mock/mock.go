@@ -321,6 +321,30 @@ func (mr *MockKOTSHandlerMockRecorder) ListApps(w, r interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListApps", reflect.TypeOf((*MockKOTSHandler)(nil).ListApps), w, r)
 }
 
+// GetAppsSummary mocks base method
+func (m *MockKOTSHandler) GetAppsSummary(w http.ResponseWriter, r *http.Request) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "GetAppsSummary", w, r)
+}
+
+// GetAppsSummary indicates an expected call of GetAppsSummary
+func (mr *MockKOTSHandlerMockRecorder) GetAppsSummary(w, r interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAppsSummary", reflect.TypeOf((*MockKOTSHandler)(nil).GetAppsSummary), w, r)
+}
+
+// StreamEvents mocks base method
+func (m *MockKOTSHandler) StreamEvents(w http.ResponseWriter, r *http.Request) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "StreamEvents", w, r)
+}
+
+// StreamEvents indicates an expected call of StreamEvents
+func (mr *MockKOTSHandlerMockRecorder) StreamEvents(w, r interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StreamEvents", reflect.TypeOf((*MockKOTSHandler)(nil).StreamEvents), w, r)
+}
+
 // GetApp mocks base method
 func (m *MockKOTSHandler) GetApp(w http.ResponseWriter, r *http.Request) {
 	m.ctrl.T.Helper()
@@ -345,6 +369,18 @@ func (mr *MockKOTSHandlerMockRecorder) GetAppStatus(w, r interface{}) *gomock.Ca
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAppStatus", reflect.TypeOf((*MockKOTSHandler)(nil).GetAppStatus), w, r)
 }
 
+// GetAppResourceUsage mocks base method
+func (m *MockKOTSHandler) GetAppResourceUsage(w http.ResponseWriter, r *http.Request) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "GetAppResourceUsage", w, r)
+}
+
+// GetAppResourceUsage indicates an expected call of GetAppResourceUsage
+func (mr *MockKOTSHandlerMockRecorder) GetAppResourceUsage(w, r interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAppResourceUsage", reflect.TypeOf((*MockKOTSHandler)(nil).GetAppResourceUsage), w, r)
+}
+
 // GetAppVersionHistory mocks base method
 func (m *MockKOTSHandler) GetAppVersionHistory(w http.ResponseWriter, r *http.Request) {
 	m.ctrl.T.Helper()
@@ -489,6 +525,18 @@ func (mr *MockKOTSHandlerMockRecorder) GetAirgapUploadConfig(w, r interface{}) *
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAirgapUploadConfig", reflect.TypeOf((*MockKOTSHandler)(nil).GetAirgapUploadConfig), w, r)
 }
 
+// SetImagePushBandwidthLimit mocks base method
+func (m *MockKOTSHandler) SetImagePushBandwidthLimit(w http.ResponseWriter, r *http.Request) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetImagePushBandwidthLimit", w, r)
+}
+
+// SetImagePushBandwidthLimit indicates an expected call of SetImagePushBandwidthLimit
+func (mr *MockKOTSHandlerMockRecorder) SetImagePushBandwidthLimit(w, r interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetImagePushBandwidthLimit", reflect.TypeOf((*MockKOTSHandler)(nil).SetImagePushBandwidthLimit), w, r)
+}
+
 // IgnorePreflightRBACErrors mocks base method
 func (m *MockKOTSHandler) IgnorePreflightRBACErrors(w http.ResponseWriter, r *http.Request) {
 	m.ctrl.T.Helper()
@@ -585,6 +633,102 @@ func (mr *MockKOTSHandlerMockRecorder) RedeployAppVersion(w, r interface{}) *gom
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RedeployAppVersion", reflect.TypeOf((*MockKOTSHandler)(nil).RedeployAppVersion), w, r)
 }
 
+// ActivateAppVersion mocks base method
+func (m *MockKOTSHandler) ActivateAppVersion(w http.ResponseWriter, r *http.Request) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "ActivateAppVersion", w, r)
+}
+
+// ActivateAppVersion indicates an expected call of ActivateAppVersion
+func (mr *MockKOTSHandlerMockRecorder) ActivateAppVersion(w, r interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ActivateAppVersion", reflect.TypeOf((*MockKOTSHandler)(nil).ActivateAppVersion), w, r)
+}
+
+// SetAppVersionNotes mocks base method
+func (m *MockKOTSHandler) SetAppVersionNotes(w http.ResponseWriter, r *http.Request) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetAppVersionNotes", w, r)
+}
+
+// SetAppVersionNotes indicates an expected call of SetAppVersionNotes
+func (mr *MockKOTSHandlerMockRecorder) SetAppVersionNotes(w, r interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetAppVersionNotes", reflect.TypeOf((*MockKOTSHandler)(nil).SetAppVersionNotes), w, r)
+}
+
+// ListCorruptAppVersionArchives mocks base method
+func (m *MockKOTSHandler) ListCorruptAppVersionArchives(w http.ResponseWriter, r *http.Request) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "ListCorruptAppVersionArchives", w, r)
+}
+
+// ListCorruptAppVersionArchives indicates an expected call of ListCorruptAppVersionArchives
+func (mr *MockKOTSHandlerMockRecorder) ListCorruptAppVersionArchives(w, r interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListCorruptAppVersionArchives", reflect.TypeOf((*MockKOTSHandler)(nil).ListCorruptAppVersionArchives), w, r)
+}
+
+// RepairAppVersionArchive mocks base method
+func (m *MockKOTSHandler) RepairAppVersionArchive(w http.ResponseWriter, r *http.Request) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "RepairAppVersionArchive", w, r)
+}
+
+// RepairAppVersionArchive indicates an expected call of RepairAppVersionArchive
+func (mr *MockKOTSHandlerMockRecorder) RepairAppVersionArchive(w, r interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RepairAppVersionArchive", reflect.TypeOf((*MockKOTSHandler)(nil).RepairAppVersionArchive), w, r)
+}
+
+// ListDeployApprovals mocks base method
+func (m *MockKOTSHandler) ListDeployApprovals(w http.ResponseWriter, r *http.Request) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "ListDeployApprovals", w, r)
+}
+
+// ListDeployApprovals indicates an expected call of ListDeployApprovals
+func (mr *MockKOTSHandlerMockRecorder) ListDeployApprovals(w, r interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListDeployApprovals", reflect.TypeOf((*MockKOTSHandler)(nil).ListDeployApprovals), w, r)
+}
+
+// ApproveDeploy mocks base method
+func (m *MockKOTSHandler) ApproveDeploy(w http.ResponseWriter, r *http.Request) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "ApproveDeploy", w, r)
+}
+
+// ApproveDeploy indicates an expected call of ApproveDeploy
+func (mr *MockKOTSHandlerMockRecorder) ApproveDeploy(w, r interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ApproveDeploy", reflect.TypeOf((*MockKOTSHandler)(nil).ApproveDeploy), w, r)
+}
+
+// RejectDeploy mocks base method
+func (m *MockKOTSHandler) RejectDeploy(w http.ResponseWriter, r *http.Request) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "RejectDeploy", w, r)
+}
+
+// RejectDeploy indicates an expected call of RejectDeploy
+func (mr *MockKOTSHandlerMockRecorder) RejectDeploy(w, r interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RejectDeploy", reflect.TypeOf((*MockKOTSHandler)(nil).RejectDeploy), w, r)
+}
+
+// SetRequireDeployApproval mocks base method
+func (m *MockKOTSHandler) SetRequireDeployApproval(w http.ResponseWriter, r *http.Request) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetRequireDeployApproval", w, r)
+}
+
+// SetRequireDeployApproval indicates an expected call of SetRequireDeployApproval
+func (mr *MockKOTSHandlerMockRecorder) SetRequireDeployApproval(w, r interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetRequireDeployApproval", reflect.TypeOf((*MockKOTSHandler)(nil).SetRequireDeployApproval), w, r)
+}
+
 // GetAppRenderedContents mocks base method
 func (m *MockKOTSHandler) GetAppRenderedContents(w http.ResponseWriter, r *http.Request) {
 	m.ctrl.T.Helper()
@@ -597,6 +741,90 @@ func (mr *MockKOTSHandlerMockRecorder) GetAppRenderedContents(w, r interface{})
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAppRenderedContents", reflect.TypeOf((*MockKOTSHandler)(nil).GetAppRenderedContents), w, r)
 }
 
+// GetAppRenderedManifests mocks base method
+func (m *MockKOTSHandler) GetAppRenderedManifests(w http.ResponseWriter, r *http.Request) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "GetAppRenderedManifests", w, r)
+}
+
+// GetAppRenderedManifests indicates an expected call of GetAppRenderedManifests
+func (mr *MockKOTSHandlerMockRecorder) GetAppRenderedManifests(w, r interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAppRenderedManifests", reflect.TypeOf((*MockKOTSHandler)(nil).GetAppRenderedManifests), w, r)
+}
+
+// GetAppVersionImpact mocks base method
+func (m *MockKOTSHandler) GetAppVersionImpact(w http.ResponseWriter, r *http.Request) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "GetAppVersionImpact", w, r)
+}
+
+// GetAppVersionImpact indicates an expected call of GetAppVersionImpact
+func (mr *MockKOTSHandlerMockRecorder) GetAppVersionImpact(w, r interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAppVersionImpact", reflect.TypeOf((*MockKOTSHandler)(nil).GetAppVersionImpact), w, r)
+}
+
+// SearchAppRenderedContents mocks base method
+func (m *MockKOTSHandler) SearchAppRenderedContents(w http.ResponseWriter, r *http.Request) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SearchAppRenderedContents", w, r)
+}
+
+// SearchAppRenderedContents indicates an expected call of SearchAppRenderedContents
+func (mr *MockKOTSHandlerMockRecorder) SearchAppRenderedContents(w, r interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchAppRenderedContents", reflect.TypeOf((*MockKOTSHandler)(nil).SearchAppRenderedContents), w, r)
+}
+
+// GetImageReport mocks base method
+func (m *MockKOTSHandler) GetImageReport(w http.ResponseWriter, r *http.Request) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "GetImageReport", w, r)
+}
+
+// GetImageReport indicates an expected call of GetImageReport
+func (mr *MockKOTSHandlerMockRecorder) GetImageReport(w, r interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetImageReport", reflect.TypeOf((*MockKOTSHandler)(nil).GetImageReport), w, r)
+}
+
+// GetResourceExclusions mocks base method
+func (m *MockKOTSHandler) GetResourceExclusions(w http.ResponseWriter, r *http.Request) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "GetResourceExclusions", w, r)
+}
+
+// GetResourceExclusions indicates an expected call of GetResourceExclusions
+func (mr *MockKOTSHandlerMockRecorder) GetResourceExclusions(w, r interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetResourceExclusions", reflect.TypeOf((*MockKOTSHandler)(nil).GetResourceExclusions), w, r)
+}
+
+// AddResourceExclusion mocks base method
+func (m *MockKOTSHandler) AddResourceExclusion(w http.ResponseWriter, r *http.Request) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "AddResourceExclusion", w, r)
+}
+
+// AddResourceExclusion indicates an expected call of AddResourceExclusion
+func (mr *MockKOTSHandlerMockRecorder) AddResourceExclusion(w, r interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddResourceExclusion", reflect.TypeOf((*MockKOTSHandler)(nil).AddResourceExclusion), w, r)
+}
+
+// RemoveResourceExclusion mocks base method
+func (m *MockKOTSHandler) RemoveResourceExclusion(w http.ResponseWriter, r *http.Request) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "RemoveResourceExclusion", w, r)
+}
+
+// RemoveResourceExclusion indicates an expected call of RemoveResourceExclusion
+func (mr *MockKOTSHandlerMockRecorder) RemoveResourceExclusion(w, r interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveResourceExclusion", reflect.TypeOf((*MockKOTSHandler)(nil).RemoveResourceExclusion), w, r)
+}
+
 // GetAppContents mocks base method
 func (m *MockKOTSHandler) GetAppContents(w http.ResponseWriter, r *http.Request) {
 	m.ctrl.T.Helper()
@@ -609,6 +837,54 @@ func (mr *MockKOTSHandlerMockRecorder) GetAppContents(w, r interface{}) *gomock.
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAppContents", reflect.TypeOf((*MockKOTSHandler)(nil).GetAppContents), w, r)
 }
 
+// ListAppContentsDir mocks base method
+func (m *MockKOTSHandler) ListAppContentsDir(w http.ResponseWriter, r *http.Request) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "ListAppContentsDir", w, r)
+}
+
+// ListAppContentsDir indicates an expected call of ListAppContentsDir
+func (mr *MockKOTSHandlerMockRecorder) ListAppContentsDir(w, r interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAppContentsDir", reflect.TypeOf((*MockKOTSHandler)(nil).ListAppContentsDir), w, r)
+}
+
+// GetAppContentsFile mocks base method
+func (m *MockKOTSHandler) GetAppContentsFile(w http.ResponseWriter, r *http.Request) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "GetAppContentsFile", w, r)
+}
+
+// GetAppContentsFile indicates an expected call of GetAppContentsFile
+func (mr *MockKOTSHandlerMockRecorder) GetAppContentsFile(w, r interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAppContentsFile", reflect.TypeOf((*MockKOTSHandler)(nil).GetAppContentsFile), w, r)
+}
+
+// DownloadAppArchive mocks base method
+func (m *MockKOTSHandler) DownloadAppArchive(w http.ResponseWriter, r *http.Request) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "DownloadAppArchive", w, r)
+}
+
+// DownloadAppArchive indicates an expected call of DownloadAppArchive
+func (mr *MockKOTSHandlerMockRecorder) DownloadAppArchive(w, r interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DownloadAppArchive", reflect.TypeOf((*MockKOTSHandler)(nil).DownloadAppArchive), w, r)
+}
+
+// DownloadAppArchiveDecrypted mocks base method
+func (m *MockKOTSHandler) DownloadAppArchiveDecrypted(w http.ResponseWriter, r *http.Request) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "DownloadAppArchiveDecrypted", w, r)
+}
+
+// DownloadAppArchiveDecrypted indicates an expected call of DownloadAppArchiveDecrypted
+func (mr *MockKOTSHandlerMockRecorder) DownloadAppArchiveDecrypted(w, r interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DownloadAppArchiveDecrypted", reflect.TypeOf((*MockKOTSHandler)(nil).DownloadAppArchiveDecrypted), w, r)
+}
+
 // GetAppDashboard mocks base method
 func (m *MockKOTSHandler) GetAppDashboard(w http.ResponseWriter, r *http.Request) {
 	m.ctrl.T.Helper()
@@ -621,6 +897,18 @@ func (mr *MockKOTSHandlerMockRecorder) GetAppDashboard(w, r interface{}) *gomock
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAppDashboard", reflect.TypeOf((*MockKOTSHandler)(nil).GetAppDashboard), w, r)
 }
 
+// GetAppMetricChart mocks base method
+func (m *MockKOTSHandler) GetAppMetricChart(w http.ResponseWriter, r *http.Request) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "GetAppMetricChart", w, r)
+}
+
+// GetAppMetricChart indicates an expected call of GetAppMetricChart
+func (mr *MockKOTSHandlerMockRecorder) GetAppMetricChart(w, r interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAppMetricChart", reflect.TypeOf((*MockKOTSHandler)(nil).GetAppMetricChart), w, r)
+}
+
 // GetDownstreamOutput mocks base method
 func (m *MockKOTSHandler) GetDownstreamOutput(w http.ResponseWriter, r *http.Request) {
 	m.ctrl.T.Helper()
@@ -633,6 +921,66 @@ func (mr *MockKOTSHandlerMockRecorder) GetDownstreamOutput(w, r interface{}) *go
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDownstreamOutput", reflect.TypeOf((*MockKOTSHandler)(nil).GetDownstreamOutput), w, r)
 }
 
+// DownloadDownstreamOutput mocks base method
+func (m *MockKOTSHandler) DownloadDownstreamOutput(w http.ResponseWriter, r *http.Request) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "DownloadDownstreamOutput", w, r)
+}
+
+// DownloadDownstreamOutput indicates an expected call of DownloadDownstreamOutput
+func (mr *MockKOTSHandlerMockRecorder) DownloadDownstreamOutput(w, r interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DownloadDownstreamOutput", reflect.TypeOf((*MockKOTSHandler)(nil).DownloadDownstreamOutput), w, r)
+}
+
+// GetPostDeployTestResults mocks base method
+func (m *MockKOTSHandler) GetPostDeployTestResults(w http.ResponseWriter, r *http.Request) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "GetPostDeployTestResults", w, r)
+}
+
+// GetPostDeployTestResults indicates an expected call of GetPostDeployTestResults
+func (mr *MockKOTSHandlerMockRecorder) GetPostDeployTestResults(w, r interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPostDeployTestResults", reflect.TypeOf((*MockKOTSHandler)(nil).GetPostDeployTestResults), w, r)
+}
+
+// GetAdmissionValidation mocks base method
+func (m *MockKOTSHandler) GetAdmissionValidation(w http.ResponseWriter, r *http.Request) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "GetAdmissionValidation", w, r)
+}
+
+// GetAdmissionValidation indicates an expected call of GetAdmissionValidation
+func (mr *MockKOTSHandlerMockRecorder) GetAdmissionValidation(w, r interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAdmissionValidation", reflect.TypeOf((*MockKOTSHandler)(nil).GetAdmissionValidation), w, r)
+}
+
+// GetDownstreamServiceAccount mocks base method
+func (m *MockKOTSHandler) GetDownstreamServiceAccount(w http.ResponseWriter, r *http.Request) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "GetDownstreamServiceAccount", w, r)
+}
+
+// GetDownstreamServiceAccount indicates an expected call of GetDownstreamServiceAccount
+func (mr *MockKOTSHandlerMockRecorder) GetDownstreamServiceAccount(w, r interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDownstreamServiceAccount", reflect.TypeOf((*MockKOTSHandler)(nil).GetDownstreamServiceAccount), w, r)
+}
+
+// SetDownstreamServiceAccount mocks base method
+func (m *MockKOTSHandler) SetDownstreamServiceAccount(w http.ResponseWriter, r *http.Request) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetDownstreamServiceAccount", w, r)
+}
+
+// SetDownstreamServiceAccount indicates an expected call of SetDownstreamServiceAccount
+func (mr *MockKOTSHandlerMockRecorder) SetDownstreamServiceAccount(w, r interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetDownstreamServiceAccount", reflect.TypeOf((*MockKOTSHandler)(nil).SetDownstreamServiceAccount), w, r)
+}
+
 // GetKotsadmRegistry mocks base method
 func (m *MockKOTSHandler) GetKotsadmRegistry(w http.ResponseWriter, r *http.Request) {
 	m.ctrl.T.Helper()
@@ -645,6 +993,18 @@ func (mr *MockKOTSHandlerMockRecorder) GetKotsadmRegistry(w, r interface{}) *gom
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetKotsadmRegistry", reflect.TypeOf((*MockKOTSHandler)(nil).GetKotsadmRegistry), w, r)
 }
 
+// UpdateKotsadmRegistry mocks base method
+func (m *MockKOTSHandler) UpdateKotsadmRegistry(w http.ResponseWriter, r *http.Request) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "UpdateKotsadmRegistry", w, r)
+}
+
+// UpdateKotsadmRegistry indicates an expected call of UpdateKotsadmRegistry
+func (mr *MockKOTSHandlerMockRecorder) UpdateKotsadmRegistry(w, r interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateKotsadmRegistry", reflect.TypeOf((*MockKOTSHandler)(nil).UpdateKotsadmRegistry), w, r)
+}
+
 // GetImageRewriteStatus mocks base method
 func (m *MockKOTSHandler) GetImageRewriteStatus(w http.ResponseWriter, r *http.Request) {
 	m.ctrl.T.Helper()
@@ -705,6 +1065,18 @@ func (mr *MockKOTSHandlerMockRecorder) UpdateAppConfig(w, r interface{}) *gomock
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateAppConfig", reflect.TypeOf((*MockKOTSHandler)(nil).UpdateAppConfig), w, r)
 }
 
+// GetAppConfigSchema mocks base method
+func (m *MockKOTSHandler) GetAppConfigSchema(w http.ResponseWriter, r *http.Request) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "GetAppConfigSchema", w, r)
+}
+
+// GetAppConfigSchema indicates an expected call of GetAppConfigSchema
+func (mr *MockKOTSHandlerMockRecorder) GetAppConfigSchema(w, r interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAppConfigSchema", reflect.TypeOf((*MockKOTSHandler)(nil).GetAppConfigSchema), w, r)
+}
+
 // CurrentAppConfig mocks base method
 func (m *MockKOTSHandler) CurrentAppConfig(w http.ResponseWriter, r *http.Request) {
 	m.ctrl.T.Helper()
@@ -741,6 +1113,66 @@ func (mr *MockKOTSHandlerMockRecorder) SetAppConfigValues(w, r interface{}) *gom
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetAppConfigValues", reflect.TypeOf((*MockKOTSHandler)(nil).SetAppConfigValues), w, r)
 }
 
+// PreviewAppConfig mocks base method
+func (m *MockKOTSHandler) PreviewAppConfig(w http.ResponseWriter, r *http.Request) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "PreviewAppConfig", w, r)
+}
+
+// PreviewAppConfig indicates an expected call of PreviewAppConfig
+func (mr *MockKOTSHandlerMockRecorder) PreviewAppConfig(w, r interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PreviewAppConfig", reflect.TypeOf((*MockKOTSHandler)(nil).PreviewAppConfig), w, r)
+}
+
+// RecomputeAppConfig mocks base method
+func (m *MockKOTSHandler) RecomputeAppConfig(w http.ResponseWriter, r *http.Request) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "RecomputeAppConfig", w, r)
+}
+
+// RecomputeAppConfig indicates an expected call of RecomputeAppConfig
+func (mr *MockKOTSHandlerMockRecorder) RecomputeAppConfig(w, r interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecomputeAppConfig", reflect.TypeOf((*MockKOTSHandler)(nil).RecomputeAppConfig), w, r)
+}
+
+// UploadAppConfigFile mocks base method
+func (m *MockKOTSHandler) UploadAppConfigFile(w http.ResponseWriter, r *http.Request) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "UploadAppConfigFile", w, r)
+}
+
+// UploadAppConfigFile indicates an expected call of UploadAppConfigFile
+func (mr *MockKOTSHandlerMockRecorder) UploadAppConfigFile(w, r interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UploadAppConfigFile", reflect.TypeOf((*MockKOTSHandler)(nil).UploadAppConfigFile), w, r)
+}
+
+// GetAppConfigValues mocks base method
+func (m *MockKOTSHandler) GetAppConfigValues(w http.ResponseWriter, r *http.Request) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "GetAppConfigValues", w, r)
+}
+
+// GetAppConfigValues indicates an expected call of GetAppConfigValues
+func (mr *MockKOTSHandlerMockRecorder) GetAppConfigValues(w, r interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAppConfigValues", reflect.TypeOf((*MockKOTSHandler)(nil).GetAppConfigValues), w, r)
+}
+
+// GetAppConfigValuesDecrypted mocks base method
+func (m *MockKOTSHandler) GetAppConfigValuesDecrypted(w http.ResponseWriter, r *http.Request) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "GetAppConfigValuesDecrypted", w, r)
+}
+
+// GetAppConfigValuesDecrypted indicates an expected call of GetAppConfigValuesDecrypted
+func (mr *MockKOTSHandlerMockRecorder) GetAppConfigValuesDecrypted(w, r interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAppConfigValuesDecrypted", reflect.TypeOf((*MockKOTSHandler)(nil).GetAppConfigValuesDecrypted), w, r)
+}
+
 // SyncLicense mocks base method
 func (m *MockKOTSHandler) SyncLicense(w http.ResponseWriter, r *http.Request) {
 	m.ctrl.T.Helper()
@@ -753,6 +1185,18 @@ func (mr *MockKOTSHandlerMockRecorder) SyncLicense(w, r interface{}) *gomock.Cal
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SyncLicense", reflect.TypeOf((*MockKOTSHandler)(nil).SyncLicense), w, r)
 }
 
+// ReplaceLicense mocks base method
+func (m *MockKOTSHandler) ReplaceLicense(w http.ResponseWriter, r *http.Request) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "ReplaceLicense", w, r)
+}
+
+// ReplaceLicense indicates an expected call of ReplaceLicense
+func (mr *MockKOTSHandlerMockRecorder) ReplaceLicense(w, r interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReplaceLicense", reflect.TypeOf((*MockKOTSHandler)(nil).ReplaceLicense), w, r)
+}
+
 // GetLicense mocks base method
 func (m *MockKOTSHandler) GetLicense(w http.ResponseWriter, r *http.Request) {
 	m.ctrl.T.Helper()
@@ -765,6 +1209,18 @@ func (mr *MockKOTSHandlerMockRecorder) GetLicense(w, r interface{}) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLicense", reflect.TypeOf((*MockKOTSHandler)(nil).GetLicense), w, r)
 }
 
+// GetEntitlementUsage mocks base method
+func (m *MockKOTSHandler) GetEntitlementUsage(w http.ResponseWriter, r *http.Request) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "GetEntitlementUsage", w, r)
+}
+
+// GetEntitlementUsage indicates an expected call of GetEntitlementUsage
+func (mr *MockKOTSHandlerMockRecorder) GetEntitlementUsage(w, r interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEntitlementUsage", reflect.TypeOf((*MockKOTSHandler)(nil).GetEntitlementUsage), w, r)
+}
+
 // AppUpdateCheck mocks base method
 func (m *MockKOTSHandler) AppUpdateCheck(w http.ResponseWriter, r *http.Request) {
 	m.ctrl.T.Helper()
@@ -789,61 +1245,397 @@ func (mr *MockKOTSHandlerMockRecorder) UpdateCheckerSpec(w, r interface{}) *gomo
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateCheckerSpec", reflect.TypeOf((*MockKOTSHandler)(nil).UpdateCheckerSpec), w, r)
 }
 
-// RemoveApp mocks base method
-func (m *MockKOTSHandler) RemoveApp(w http.ResponseWriter, r *http.Request) {
+// GetAppSchedules mocks base method
+func (m *MockKOTSHandler) GetAppSchedules(w http.ResponseWriter, r *http.Request) {
 	m.ctrl.T.Helper()
-	m.ctrl.Call(m, "RemoveApp", w, r)
+	m.ctrl.Call(m, "GetAppSchedules", w, r)
 }
 
-// RemoveApp indicates an expected call of RemoveApp
-func (mr *MockKOTSHandlerMockRecorder) RemoveApp(w, r interface{}) *gomock.Call {
+// GetAppSchedules indicates an expected call of GetAppSchedules
+func (mr *MockKOTSHandlerMockRecorder) GetAppSchedules(w, r interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveApp", reflect.TypeOf((*MockKOTSHandler)(nil).RemoveApp), w, r)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAppSchedules", reflect.TypeOf((*MockKOTSHandler)(nil).GetAppSchedules), w, r)
 }
 
-// CreateApplicationBackup mocks base method
-func (m *MockKOTSHandler) CreateApplicationBackup(w http.ResponseWriter, r *http.Request) {
+// SetScheduleTimezone mocks base method
+func (m *MockKOTSHandler) SetScheduleTimezone(w http.ResponseWriter, r *http.Request) {
 	m.ctrl.T.Helper()
-	m.ctrl.Call(m, "CreateApplicationBackup", w, r)
+	m.ctrl.Call(m, "SetScheduleTimezone", w, r)
 }
 
-// CreateApplicationBackup indicates an expected call of CreateApplicationBackup
-func (mr *MockKOTSHandlerMockRecorder) CreateApplicationBackup(w, r interface{}) *gomock.Call {
+// SetScheduleTimezone indicates an expected call of SetScheduleTimezone
+func (mr *MockKOTSHandlerMockRecorder) SetScheduleTimezone(w, r interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateApplicationBackup", reflect.TypeOf((*MockKOTSHandler)(nil).CreateApplicationBackup), w, r)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetScheduleTimezone", reflect.TypeOf((*MockKOTSHandler)(nil).SetScheduleTimezone), w, r)
 }
 
-// GetRestoreStatus mocks base method
-func (m *MockKOTSHandler) GetRestoreStatus(w http.ResponseWriter, r *http.Request) {
+// SetAdmissionDryRun mocks base method
+func (m *MockKOTSHandler) SetAdmissionDryRun(w http.ResponseWriter, r *http.Request) {
 	m.ctrl.T.Helper()
-	m.ctrl.Call(m, "GetRestoreStatus", w, r)
+	m.ctrl.Call(m, "SetAdmissionDryRun", w, r)
 }
 
-// GetRestoreStatus indicates an expected call of GetRestoreStatus
-func (mr *MockKOTSHandlerMockRecorder) GetRestoreStatus(w, r interface{}) *gomock.Call {
+// SetAdmissionDryRun indicates an expected call of SetAdmissionDryRun
+func (mr *MockKOTSHandlerMockRecorder) SetAdmissionDryRun(w, r interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRestoreStatus", reflect.TypeOf((*MockKOTSHandler)(nil).GetRestoreStatus), w, r)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetAdmissionDryRun", reflect.TypeOf((*MockKOTSHandler)(nil).SetAdmissionDryRun), w, r)
 }
 
-// CancelRestore mocks base method
-func (m *MockKOTSHandler) CancelRestore(w http.ResponseWriter, r *http.Request) {
+// GetApplyPolicy mocks base method
+func (m *MockKOTSHandler) GetApplyPolicy(w http.ResponseWriter, r *http.Request) {
 	m.ctrl.T.Helper()
-	m.ctrl.Call(m, "CancelRestore", w, r)
+	m.ctrl.Call(m, "GetApplyPolicy", w, r)
 }
 
-// CancelRestore indicates an expected call of CancelRestore
-func (mr *MockKOTSHandlerMockRecorder) CancelRestore(w, r interface{}) *gomock.Call {
+// GetApplyPolicy indicates an expected call of GetApplyPolicy
+func (mr *MockKOTSHandlerMockRecorder) GetApplyPolicy(w, r interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CancelRestore", reflect.TypeOf((*MockKOTSHandler)(nil).CancelRestore), w, r)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetApplyPolicy", reflect.TypeOf((*MockKOTSHandler)(nil).GetApplyPolicy), w, r)
 }
 
-// CreateApplicationRestore mocks base method
-func (m *MockKOTSHandler) CreateApplicationRestore(w http.ResponseWriter, r *http.Request) {
+// SetApplyPolicy mocks base method
+func (m *MockKOTSHandler) SetApplyPolicy(w http.ResponseWriter, r *http.Request) {
 	m.ctrl.T.Helper()
-	m.ctrl.Call(m, "CreateApplicationRestore", w, r)
+	m.ctrl.Call(m, "SetApplyPolicy", w, r)
 }
 
-// CreateApplicationRestore indicates an expected call of CreateApplicationRestore
+// SetApplyPolicy indicates an expected call of SetApplyPolicy
+func (mr *MockKOTSHandlerMockRecorder) SetApplyPolicy(w, r interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetApplyPolicy", reflect.TypeOf((*MockKOTSHandler)(nil).SetApplyPolicy), w, r)
+}
+
+// GetCanaryDeploys mocks base method
+func (m *MockKOTSHandler) GetCanaryDeploys(w http.ResponseWriter, r *http.Request) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "GetCanaryDeploys", w, r)
+}
+
+// GetCanaryDeploys indicates an expected call of GetCanaryDeploys
+func (mr *MockKOTSHandlerMockRecorder) GetCanaryDeploys(w, r interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCanaryDeploys", reflect.TypeOf((*MockKOTSHandler)(nil).GetCanaryDeploys), w, r)
+}
+
+// SetCanaryPolicy mocks base method
+func (m *MockKOTSHandler) SetCanaryPolicy(w http.ResponseWriter, r *http.Request) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetCanaryPolicy", w, r)
+}
+
+// SetCanaryPolicy indicates an expected call of SetCanaryPolicy
+func (mr *MockKOTSHandlerMockRecorder) SetCanaryPolicy(w, r interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetCanaryPolicy", reflect.TypeOf((*MockKOTSHandler)(nil).SetCanaryPolicy), w, r)
+}
+
+// GetFailedUpdateDownloads mocks base method
+func (m *MockKOTSHandler) GetFailedUpdateDownloads(w http.ResponseWriter, r *http.Request) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "GetFailedUpdateDownloads", w, r)
+}
+
+// GetFailedUpdateDownloads indicates an expected call of GetFailedUpdateDownloads
+func (mr *MockKOTSHandlerMockRecorder) GetFailedUpdateDownloads(w, r interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFailedUpdateDownloads", reflect.TypeOf((*MockKOTSHandler)(nil).GetFailedUpdateDownloads), w, r)
+}
+
+// RetryFailedUpdateDownloads mocks base method
+func (m *MockKOTSHandler) RetryFailedUpdateDownloads(w http.ResponseWriter, r *http.Request) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "RetryFailedUpdateDownloads", w, r)
+}
+
+// RetryFailedUpdateDownloads indicates an expected call of RetryFailedUpdateDownloads
+func (mr *MockKOTSHandlerMockRecorder) RetryFailedUpdateDownloads(w, r interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RetryFailedUpdateDownloads", reflect.TypeOf((*MockKOTSHandler)(nil).RetryFailedUpdateDownloads), w, r)
+}
+
+// RemoveApp mocks base method
+func (m *MockKOTSHandler) RemoveApp(w http.ResponseWriter, r *http.Request) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "RemoveApp", w, r)
+}
+
+// RemoveApp indicates an expected call of RemoveApp
+func (mr *MockKOTSHandlerMockRecorder) RemoveApp(w, r interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveApp", reflect.TypeOf((*MockKOTSHandler)(nil).RemoveApp), w, r)
+}
+
+// RestoreRemovedApp mocks base method
+func (m *MockKOTSHandler) RestoreRemovedApp(w http.ResponseWriter, r *http.Request) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "RestoreRemovedApp", w, r)
+}
+
+// RestoreRemovedApp indicates an expected call of RestoreRemovedApp
+func (mr *MockKOTSHandlerMockRecorder) RestoreRemovedApp(w, r interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestoreRemovedApp", reflect.TypeOf((*MockKOTSHandler)(nil).RestoreRemovedApp), w, r)
+}
+
+// SetAppProtected mocks base method
+func (m *MockKOTSHandler) SetAppProtected(w http.ResponseWriter, r *http.Request) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetAppProtected", w, r)
+}
+
+// SetAppProtected indicates an expected call of SetAppProtected
+func (mr *MockKOTSHandlerMockRecorder) SetAppProtected(w, r interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetAppProtected", reflect.TypeOf((*MockKOTSHandler)(nil).SetAppProtected), w, r)
+}
+
+// SetAppLabels mocks base method
+func (m *MockKOTSHandler) SetAppLabels(w http.ResponseWriter, r *http.Request) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetAppLabels", w, r)
+}
+
+// SetAppLabels indicates an expected call of SetAppLabels
+func (mr *MockKOTSHandlerMockRecorder) SetAppLabels(w, r interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetAppLabels", reflect.TypeOf((*MockKOTSHandler)(nil).SetAppLabels), w, r)
+}
+
+// ListRemovedApps mocks base method
+func (m *MockKOTSHandler) ListRemovedApps(w http.ResponseWriter, r *http.Request) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "ListRemovedApps", w, r)
+}
+
+// ListRemovedApps indicates an expected call of ListRemovedApps
+func (mr *MockKOTSHandlerMockRecorder) ListRemovedApps(w, r interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListRemovedApps", reflect.TypeOf((*MockKOTSHandler)(nil).ListRemovedApps), w, r)
+}
+
+// GetFleetReport mocks base method
+func (m *MockKOTSHandler) GetFleetReport(w http.ResponseWriter, r *http.Request) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "GetFleetReport", w, r)
+}
+
+// GetFleetReport indicates an expected call of GetFleetReport
+func (mr *MockKOTSHandlerMockRecorder) GetFleetReport(w, r interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFleetReport", reflect.TypeOf((*MockKOTSHandler)(nil).GetFleetReport), w, r)
+}
+
+// GetDeployHistory mocks base method
+func (m *MockKOTSHandler) GetDeployHistory(w http.ResponseWriter, r *http.Request) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "GetDeployHistory", w, r)
+}
+
+// GetDeployHistory indicates an expected call of GetDeployHistory
+func (mr *MockKOTSHandlerMockRecorder) GetDeployHistory(w, r interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDeployHistory", reflect.TypeOf((*MockKOTSHandler)(nil).GetDeployHistory), w, r)
+}
+
+// ArchiveApp mocks base method
+func (m *MockKOTSHandler) ArchiveApp(w http.ResponseWriter, r *http.Request) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "ArchiveApp", w, r)
+}
+
+// ArchiveApp indicates an expected call of ArchiveApp
+func (mr *MockKOTSHandlerMockRecorder) ArchiveApp(w, r interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ArchiveApp", reflect.TypeOf((*MockKOTSHandler)(nil).ArchiveApp), w, r)
+}
+
+// UnarchiveApp mocks base method
+func (m *MockKOTSHandler) UnarchiveApp(w http.ResponseWriter, r *http.Request) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "UnarchiveApp", w, r)
+}
+
+// UnarchiveApp indicates an expected call of UnarchiveApp
+func (mr *MockKOTSHandlerMockRecorder) UnarchiveApp(w, r interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnarchiveApp", reflect.TypeOf((*MockKOTSHandler)(nil).UnarchiveApp), w, r)
+}
+
+// SetAppAirgapMode mocks base method
+func (m *MockKOTSHandler) SetAppAirgapMode(w http.ResponseWriter, r *http.Request) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetAppAirgapMode", w, r)
+}
+
+// SetAppAirgapMode indicates an expected call of SetAppAirgapMode
+func (mr *MockKOTSHandlerMockRecorder) SetAppAirgapMode(w, r interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetAppAirgapMode", reflect.TypeOf((*MockKOTSHandler)(nil).SetAppAirgapMode), w, r)
+}
+
+// CloneApp mocks base method
+func (m *MockKOTSHandler) CloneApp(w http.ResponseWriter, r *http.Request) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "CloneApp", w, r)
+}
+
+// CloneApp indicates an expected call of CloneApp
+func (mr *MockKOTSHandlerMockRecorder) CloneApp(w, r interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CloneApp", reflect.TypeOf((*MockKOTSHandler)(nil).CloneApp), w, r)
+}
+
+// CloneAppFromSnapshot mocks base method
+func (m *MockKOTSHandler) CloneAppFromSnapshot(w http.ResponseWriter, r *http.Request) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "CloneAppFromSnapshot", w, r)
+}
+
+// CloneAppFromSnapshot indicates an expected call of CloneAppFromSnapshot
+func (mr *MockKOTSHandlerMockRecorder) CloneAppFromSnapshot(w, r interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CloneAppFromSnapshot", reflect.TypeOf((*MockKOTSHandler)(nil).CloneAppFromSnapshot), w, r)
+}
+
+// GetAppMaintenanceMessage mocks base method
+func (m *MockKOTSHandler) GetAppMaintenanceMessage(w http.ResponseWriter, r *http.Request) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "GetAppMaintenanceMessage", w, r)
+}
+
+// GetAppMaintenanceMessage indicates an expected call of GetAppMaintenanceMessage
+func (mr *MockKOTSHandlerMockRecorder) GetAppMaintenanceMessage(w, r interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAppMaintenanceMessage", reflect.TypeOf((*MockKOTSHandler)(nil).GetAppMaintenanceMessage), w, r)
+}
+
+// SetAppMaintenanceMessage mocks base method
+func (m *MockKOTSHandler) SetAppMaintenanceMessage(w http.ResponseWriter, r *http.Request) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetAppMaintenanceMessage", w, r)
+}
+
+// SetAppMaintenanceMessage indicates an expected call of SetAppMaintenanceMessage
+func (mr *MockKOTSHandlerMockRecorder) SetAppMaintenanceMessage(w, r interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetAppMaintenanceMessage", reflect.TypeOf((*MockKOTSHandler)(nil).SetAppMaintenanceMessage), w, r)
+}
+
+// ClearAppMaintenanceMessage mocks base method
+func (m *MockKOTSHandler) ClearAppMaintenanceMessage(w http.ResponseWriter, r *http.Request) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "ClearAppMaintenanceMessage", w, r)
+}
+
+// ClearAppMaintenanceMessage indicates an expected call of ClearAppMaintenanceMessage
+func (mr *MockKOTSHandlerMockRecorder) ClearAppMaintenanceMessage(w, r interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClearAppMaintenanceMessage", reflect.TypeOf((*MockKOTSHandler)(nil).ClearAppMaintenanceMessage), w, r)
+}
+
+// GetAppFreeze mocks base method
+func (m *MockKOTSHandler) GetAppFreeze(w http.ResponseWriter, r *http.Request) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "GetAppFreeze", w, r)
+}
+
+// GetAppFreeze indicates an expected call of GetAppFreeze
+func (mr *MockKOTSHandlerMockRecorder) GetAppFreeze(w, r interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAppFreeze", reflect.TypeOf((*MockKOTSHandler)(nil).GetAppFreeze), w, r)
+}
+
+// SetAppFreeze mocks base method
+func (m *MockKOTSHandler) SetAppFreeze(w http.ResponseWriter, r *http.Request) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetAppFreeze", w, r)
+}
+
+// SetAppFreeze indicates an expected call of SetAppFreeze
+func (mr *MockKOTSHandlerMockRecorder) SetAppFreeze(w, r interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetAppFreeze", reflect.TypeOf((*MockKOTSHandler)(nil).SetAppFreeze), w, r)
+}
+
+// ClearAppFreeze mocks base method
+func (m *MockKOTSHandler) ClearAppFreeze(w http.ResponseWriter, r *http.Request) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "ClearAppFreeze", w, r)
+}
+
+// ClearAppFreeze indicates an expected call of ClearAppFreeze
+func (mr *MockKOTSHandlerMockRecorder) ClearAppFreeze(w, r interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClearAppFreeze", reflect.TypeOf((*MockKOTSHandler)(nil).ClearAppFreeze), w, r)
+}
+
+// GetReleaseFeedbackConsent mocks base method
+func (m *MockKOTSHandler) GetReleaseFeedbackConsent(w http.ResponseWriter, r *http.Request) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "GetReleaseFeedbackConsent", w, r)
+}
+
+// GetReleaseFeedbackConsent indicates an expected call of GetReleaseFeedbackConsent
+func (mr *MockKOTSHandlerMockRecorder) GetReleaseFeedbackConsent(w, r interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetReleaseFeedbackConsent", reflect.TypeOf((*MockKOTSHandler)(nil).GetReleaseFeedbackConsent), w, r)
+}
+
+// SetReleaseFeedbackConsent mocks base method
+func (m *MockKOTSHandler) SetReleaseFeedbackConsent(w http.ResponseWriter, r *http.Request) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetReleaseFeedbackConsent", w, r)
+}
+
+// SetReleaseFeedbackConsent indicates an expected call of SetReleaseFeedbackConsent
+func (mr *MockKOTSHandlerMockRecorder) SetReleaseFeedbackConsent(w, r interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetReleaseFeedbackConsent", reflect.TypeOf((*MockKOTSHandler)(nil).SetReleaseFeedbackConsent), w, r)
+}
+
+// CreateApplicationBackup mocks base method
+func (m *MockKOTSHandler) CreateApplicationBackup(w http.ResponseWriter, r *http.Request) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "CreateApplicationBackup", w, r)
+}
+
+// CreateApplicationBackup indicates an expected call of CreateApplicationBackup
+func (mr *MockKOTSHandlerMockRecorder) CreateApplicationBackup(w, r interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateApplicationBackup", reflect.TypeOf((*MockKOTSHandler)(nil).CreateApplicationBackup), w, r)
+}
+
+// GetRestoreStatus mocks base method
+func (m *MockKOTSHandler) GetRestoreStatus(w http.ResponseWriter, r *http.Request) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "GetRestoreStatus", w, r)
+}
+
+// GetRestoreStatus indicates an expected call of GetRestoreStatus
+func (mr *MockKOTSHandlerMockRecorder) GetRestoreStatus(w, r interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRestoreStatus", reflect.TypeOf((*MockKOTSHandler)(nil).GetRestoreStatus), w, r)
+}
+
+// CancelRestore mocks base method
+func (m *MockKOTSHandler) CancelRestore(w http.ResponseWriter, r *http.Request) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "CancelRestore", w, r)
+}
+
+// CancelRestore indicates an expected call of CancelRestore
+func (mr *MockKOTSHandlerMockRecorder) CancelRestore(w, r interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CancelRestore", reflect.TypeOf((*MockKOTSHandler)(nil).CancelRestore), w, r)
+}
+
+// CreateApplicationRestore mocks base method
+func (m *MockKOTSHandler) CreateApplicationRestore(w http.ResponseWriter, r *http.Request) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "CreateApplicationRestore", w, r)
+}
+
+// CreateApplicationRestore indicates an expected call of CreateApplicationRestore
 func (mr *MockKOTSHandlerMockRecorder) CreateApplicationRestore(w, r interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateApplicationRestore", reflect.TypeOf((*MockKOTSHandler)(nil).CreateApplicationRestore), w, r)
@@ -897,6 +1689,42 @@ func (mr *MockKOTSHandlerMockRecorder) SaveSnapshotConfig(w, r interface{}) *gom
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveSnapshotConfig", reflect.TypeOf((*MockKOTSHandler)(nil).SaveSnapshotConfig), w, r)
 }
 
+// GetRestoreDrills mocks base method
+func (m *MockKOTSHandler) GetRestoreDrills(w http.ResponseWriter, r *http.Request) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "GetRestoreDrills", w, r)
+}
+
+// GetRestoreDrills indicates an expected call of GetRestoreDrills
+func (mr *MockKOTSHandlerMockRecorder) GetRestoreDrills(w, r interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRestoreDrills", reflect.TypeOf((*MockKOTSHandler)(nil).GetRestoreDrills), w, r)
+}
+
+// RunRestoreDrill mocks base method
+func (m *MockKOTSHandler) RunRestoreDrill(w http.ResponseWriter, r *http.Request) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "RunRestoreDrill", w, r)
+}
+
+// RunRestoreDrill indicates an expected call of RunRestoreDrill
+func (mr *MockKOTSHandlerMockRecorder) RunRestoreDrill(w, r interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RunRestoreDrill", reflect.TypeOf((*MockKOTSHandler)(nil).RunRestoreDrill), w, r)
+}
+
+// SetRestoreDrillSchedule mocks base method
+func (m *MockKOTSHandler) SetRestoreDrillSchedule(w http.ResponseWriter, r *http.Request) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetRestoreDrillSchedule", w, r)
+}
+
+// SetRestoreDrillSchedule indicates an expected call of SetRestoreDrillSchedule
+func (mr *MockKOTSHandlerMockRecorder) SetRestoreDrillSchedule(w, r interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetRestoreDrillSchedule", reflect.TypeOf((*MockKOTSHandler)(nil).SetRestoreDrillSchedule), w, r)
+}
+
 // ListInstanceBackups mocks base method
 func (m *MockKOTSHandler) ListInstanceBackups(w http.ResponseWriter, r *http.Request) {
 	m.ctrl.T.Helper()
@@ -993,6 +1821,18 @@ func (mr *MockKOTSHandlerMockRecorder) GetBackup(w, r interface{}) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBackup", reflect.TypeOf((*MockKOTSHandler)(nil).GetBackup), w, r)
 }
 
+// GetBackupContents mocks base method
+func (m *MockKOTSHandler) GetBackupContents(w http.ResponseWriter, r *http.Request) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "GetBackupContents", w, r)
+}
+
+// GetBackupContents indicates an expected call of GetBackupContents
+func (mr *MockKOTSHandlerMockRecorder) GetBackupContents(w, r interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBackupContents", reflect.TypeOf((*MockKOTSHandler)(nil).GetBackupContents), w, r)
+}
+
 // DeleteBackup mocks base method
 func (m *MockKOTSHandler) DeleteBackup(w http.ResponseWriter, r *http.Request) {
 	m.ctrl.T.Helper()
@@ -1053,6 +1893,30 @@ func (mr *MockKOTSHandlerMockRecorder) GetVeleroStatus(w, r interface{}) *gomock
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetVeleroStatus", reflect.TypeOf((*MockKOTSHandler)(nil).GetVeleroStatus), w, r)
 }
 
+// ExportKotsadmState mocks base method
+func (m *MockKOTSHandler) ExportKotsadmState(w http.ResponseWriter, r *http.Request) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "ExportKotsadmState", w, r)
+}
+
+// ExportKotsadmState indicates an expected call of ExportKotsadmState
+func (mr *MockKOTSHandlerMockRecorder) ExportKotsadmState(w, r interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExportKotsadmState", reflect.TypeOf((*MockKOTSHandler)(nil).ExportKotsadmState), w, r)
+}
+
+// ImportKotsadmState mocks base method
+func (m *MockKOTSHandler) ImportKotsadmState(w http.ResponseWriter, r *http.Request) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "ImportKotsadmState", w, r)
+}
+
+// ImportKotsadmState indicates an expected call of ImportKotsadmState
+func (mr *MockKOTSHandlerMockRecorder) ImportKotsadmState(w, r interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ImportKotsadmState", reflect.TypeOf((*MockKOTSHandler)(nil).ImportKotsadmState), w, r)
+}
+
 // GenerateNodeJoinCommandWorker mocks base method
 func (m *MockKOTSHandler) GenerateNodeJoinCommandWorker(w http.ResponseWriter, r *http.Request) {
 	m.ctrl.T.Helper()
@@ -1137,6 +2001,66 @@ func (mr *MockKOTSHandlerMockRecorder) GetKurlNodes(w, r interface{}) *gomock.Ca
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetKurlNodes", reflect.TypeOf((*MockKOTSHandler)(nil).GetKurlNodes), w, r)
 }
 
+// GetRuntimeDiagnostics mocks base method
+func (m *MockKOTSHandler) GetRuntimeDiagnostics(w http.ResponseWriter, r *http.Request) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "GetRuntimeDiagnostics", w, r)
+}
+
+// GetRuntimeDiagnostics indicates an expected call of GetRuntimeDiagnostics
+func (mr *MockKOTSHandlerMockRecorder) GetRuntimeDiagnostics(w, r interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRuntimeDiagnostics", reflect.TypeOf((*MockKOTSHandler)(nil).GetRuntimeDiagnostics), w, r)
+}
+
+// GetPprofProfile mocks base method
+func (m *MockKOTSHandler) GetPprofProfile(w http.ResponseWriter, r *http.Request) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "GetPprofProfile", w, r)
+}
+
+// GetPprofProfile indicates an expected call of GetPprofProfile
+func (mr *MockKOTSHandlerMockRecorder) GetPprofProfile(w, r interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPprofProfile", reflect.TypeOf((*MockKOTSHandler)(nil).GetPprofProfile), w, r)
+}
+
+// GetJanitorDiagnostics mocks base method
+func (m *MockKOTSHandler) GetJanitorDiagnostics(w http.ResponseWriter, r *http.Request) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "GetJanitorDiagnostics", w, r)
+}
+
+// GetJanitorDiagnostics indicates an expected call of GetJanitorDiagnostics
+func (mr *MockKOTSHandlerMockRecorder) GetJanitorDiagnostics(w, r interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetJanitorDiagnostics", reflect.TypeOf((*MockKOTSHandler)(nil).GetJanitorDiagnostics), w, r)
+}
+
+// ListScheduledJobs mocks base method
+func (m *MockKOTSHandler) ListScheduledJobs(w http.ResponseWriter, r *http.Request) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "ListScheduledJobs", w, r)
+}
+
+// ListScheduledJobs indicates an expected call of ListScheduledJobs
+func (mr *MockKOTSHandlerMockRecorder) ListScheduledJobs(w, r interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListScheduledJobs", reflect.TypeOf((*MockKOTSHandler)(nil).ListScheduledJobs), w, r)
+}
+
+// CheckConnectivity mocks base method
+func (m *MockKOTSHandler) CheckConnectivity(w http.ResponseWriter, r *http.Request) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "CheckConnectivity", w, r)
+}
+
+// CheckConnectivity indicates an expected call of CheckConnectivity
+func (mr *MockKOTSHandlerMockRecorder) CheckConnectivity(w, r interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckConnectivity", reflect.TypeOf((*MockKOTSHandler)(nil).CheckConnectivity), w, r)
+}
+
 // SetPrometheusAddress mocks base method
 func (m *MockKOTSHandler) SetPrometheusAddress(w http.ResponseWriter, r *http.Request) {
 	m.ctrl.T.Helper()
@@ -1149,6 +2073,378 @@ func (mr *MockKOTSHandlerMockRecorder) SetPrometheusAddress(w, r interface{}) *g
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetPrometheusAddress", reflect.TypeOf((*MockKOTSHandler)(nil).SetPrometheusAddress), w, r)
 }
 
+// GetPrometheusSettings mocks base method
+func (m *MockKOTSHandler) GetPrometheusSettings(w http.ResponseWriter, r *http.Request) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "GetPrometheusSettings", w, r)
+}
+
+// GetPrometheusSettings indicates an expected call of GetPrometheusSettings
+func (mr *MockKOTSHandlerMockRecorder) GetPrometheusSettings(w, r interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPrometheusSettings", reflect.TypeOf((*MockKOTSHandler)(nil).GetPrometheusSettings), w, r)
+}
+
+// ValidatePrometheusSettings mocks base method
+func (m *MockKOTSHandler) ValidatePrometheusSettings(w http.ResponseWriter, r *http.Request) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "ValidatePrometheusSettings", w, r)
+}
+
+// ValidatePrometheusSettings indicates an expected call of ValidatePrometheusSettings
+func (mr *MockKOTSHandlerMockRecorder) ValidatePrometheusSettings(w, r interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ValidatePrometheusSettings", reflect.TypeOf((*MockKOTSHandler)(nil).ValidatePrometheusSettings), w, r)
+}
+
+// GetCABundle mocks base method
+func (m *MockKOTSHandler) GetCABundle(w http.ResponseWriter, r *http.Request) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "GetCABundle", w, r)
+}
+
+// GetCABundle indicates an expected call of GetCABundle
+func (mr *MockKOTSHandlerMockRecorder) GetCABundle(w, r interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCABundle", reflect.TypeOf((*MockKOTSHandler)(nil).GetCABundle), w, r)
+}
+
+// SetCABundle mocks base method
+func (m *MockKOTSHandler) SetCABundle(w http.ResponseWriter, r *http.Request) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetCABundle", w, r)
+}
+
+// SetCABundle indicates an expected call of SetCABundle
+func (mr *MockKOTSHandlerMockRecorder) SetCABundle(w, r interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetCABundle", reflect.TypeOf((*MockKOTSHandler)(nil).SetCABundle), w, r)
+}
+
+// ListClientCertificates mocks base method
+func (m *MockKOTSHandler) ListClientCertificates(w http.ResponseWriter, r *http.Request) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "ListClientCertificates", w, r)
+}
+
+// ListClientCertificates indicates an expected call of ListClientCertificates
+func (mr *MockKOTSHandlerMockRecorder) ListClientCertificates(w, r interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListClientCertificates", reflect.TypeOf((*MockKOTSHandler)(nil).ListClientCertificates), w, r)
+}
+
+// SetClientCertificate mocks base method
+func (m *MockKOTSHandler) SetClientCertificate(w http.ResponseWriter, r *http.Request) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetClientCertificate", w, r)
+}
+
+// SetClientCertificate indicates an expected call of SetClientCertificate
+func (mr *MockKOTSHandlerMockRecorder) SetClientCertificate(w, r interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetClientCertificate", reflect.TypeOf((*MockKOTSHandler)(nil).SetClientCertificate), w, r)
+}
+
+// DeleteClientCertificate mocks base method
+func (m *MockKOTSHandler) DeleteClientCertificate(w http.ResponseWriter, r *http.Request) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "DeleteClientCertificate", w, r)
+}
+
+// DeleteClientCertificate indicates an expected call of DeleteClientCertificate
+func (mr *MockKOTSHandlerMockRecorder) DeleteClientCertificate(w, r interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteClientCertificate", reflect.TypeOf((*MockKOTSHandler)(nil).DeleteClientCertificate), w, r)
+}
+
+// GetGlobalMaintenanceMessage mocks base method
+func (m *MockKOTSHandler) GetGlobalMaintenanceMessage(w http.ResponseWriter, r *http.Request) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "GetGlobalMaintenanceMessage", w, r)
+}
+
+// GetGlobalMaintenanceMessage indicates an expected call of GetGlobalMaintenanceMessage
+func (mr *MockKOTSHandlerMockRecorder) GetGlobalMaintenanceMessage(w, r interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetGlobalMaintenanceMessage", reflect.TypeOf((*MockKOTSHandler)(nil).GetGlobalMaintenanceMessage), w, r)
+}
+
+// SetGlobalMaintenanceMessage mocks base method
+func (m *MockKOTSHandler) SetGlobalMaintenanceMessage(w http.ResponseWriter, r *http.Request) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetGlobalMaintenanceMessage", w, r)
+}
+
+// SetGlobalMaintenanceMessage indicates an expected call of SetGlobalMaintenanceMessage
+func (mr *MockKOTSHandlerMockRecorder) SetGlobalMaintenanceMessage(w, r interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetGlobalMaintenanceMessage", reflect.TypeOf((*MockKOTSHandler)(nil).SetGlobalMaintenanceMessage), w, r)
+}
+
+// ClearGlobalMaintenanceMessage mocks base method
+func (m *MockKOTSHandler) ClearGlobalMaintenanceMessage(w http.ResponseWriter, r *http.Request) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "ClearGlobalMaintenanceMessage", w, r)
+}
+
+// ClearGlobalMaintenanceMessage indicates an expected call of ClearGlobalMaintenanceMessage
+func (mr *MockKOTSHandlerMockRecorder) ClearGlobalMaintenanceMessage(w, r interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClearGlobalMaintenanceMessage", reflect.TypeOf((*MockKOTSHandler)(nil).ClearGlobalMaintenanceMessage), w, r)
+}
+
+// GetGlobalFreeze mocks base method
+func (m *MockKOTSHandler) GetGlobalFreeze(w http.ResponseWriter, r *http.Request) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "GetGlobalFreeze", w, r)
+}
+
+// GetGlobalFreeze indicates an expected call of GetGlobalFreeze
+func (mr *MockKOTSHandlerMockRecorder) GetGlobalFreeze(w, r interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetGlobalFreeze", reflect.TypeOf((*MockKOTSHandler)(nil).GetGlobalFreeze), w, r)
+}
+
+// SetGlobalFreeze mocks base method
+func (m *MockKOTSHandler) SetGlobalFreeze(w http.ResponseWriter, r *http.Request) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetGlobalFreeze", w, r)
+}
+
+// SetGlobalFreeze indicates an expected call of SetGlobalFreeze
+func (mr *MockKOTSHandlerMockRecorder) SetGlobalFreeze(w, r interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetGlobalFreeze", reflect.TypeOf((*MockKOTSHandler)(nil).SetGlobalFreeze), w, r)
+}
+
+// ClearGlobalFreeze mocks base method
+func (m *MockKOTSHandler) ClearGlobalFreeze(w http.ResponseWriter, r *http.Request) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "ClearGlobalFreeze", w, r)
+}
+
+// ClearGlobalFreeze indicates an expected call of ClearGlobalFreeze
+func (mr *MockKOTSHandlerMockRecorder) ClearGlobalFreeze(w, r interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClearGlobalFreeze", reflect.TypeOf((*MockKOTSHandler)(nil).ClearGlobalFreeze), w, r)
+}
+
+// GetReadOnlyMode mocks base method
+func (m *MockKOTSHandler) GetReadOnlyMode(w http.ResponseWriter, r *http.Request) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "GetReadOnlyMode", w, r)
+}
+
+// GetReadOnlyMode indicates an expected call of GetReadOnlyMode
+func (mr *MockKOTSHandlerMockRecorder) GetReadOnlyMode(w, r interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetReadOnlyMode", reflect.TypeOf((*MockKOTSHandler)(nil).GetReadOnlyMode), w, r)
+}
+
+// SetReadOnlyMode mocks base method
+func (m *MockKOTSHandler) SetReadOnlyMode(w http.ResponseWriter, r *http.Request) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetReadOnlyMode", w, r)
+}
+
+// SetReadOnlyMode indicates an expected call of SetReadOnlyMode
+func (mr *MockKOTSHandlerMockRecorder) SetReadOnlyMode(w, r interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetReadOnlyMode", reflect.TypeOf((*MockKOTSHandler)(nil).SetReadOnlyMode), w, r)
+}
+
+// GetUploadQuota mocks base method
+func (m *MockKOTSHandler) GetUploadQuota(w http.ResponseWriter, r *http.Request) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "GetUploadQuota", w, r)
+}
+
+// GetUploadQuota indicates an expected call of GetUploadQuota
+func (mr *MockKOTSHandlerMockRecorder) GetUploadQuota(w, r interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUploadQuota", reflect.TypeOf((*MockKOTSHandler)(nil).GetUploadQuota), w, r)
+}
+
+// SetUploadQuota mocks base method
+func (m *MockKOTSHandler) SetUploadQuota(w http.ResponseWriter, r *http.Request) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetUploadQuota", w, r)
+}
+
+// SetUploadQuota indicates an expected call of SetUploadQuota
+func (mr *MockKOTSHandlerMockRecorder) SetUploadQuota(w, r interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetUploadQuota", reflect.TypeOf((*MockKOTSHandler)(nil).SetUploadQuota), w, r)
+}
+
+// GetSessionSettings mocks base method
+func (m *MockKOTSHandler) GetSessionSettings(w http.ResponseWriter, r *http.Request) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "GetSessionSettings", w, r)
+}
+
+// GetSessionSettings indicates an expected call of GetSessionSettings
+func (mr *MockKOTSHandlerMockRecorder) GetSessionSettings(w, r interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSessionSettings", reflect.TypeOf((*MockKOTSHandler)(nil).GetSessionSettings), w, r)
+}
+
+// SetSessionSettings mocks base method
+func (m *MockKOTSHandler) SetSessionSettings(w http.ResponseWriter, r *http.Request) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetSessionSettings", w, r)
+}
+
+// SetSessionSettings indicates an expected call of SetSessionSettings
+func (mr *MockKOTSHandlerMockRecorder) SetSessionSettings(w, r interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetSessionSettings", reflect.TypeOf((*MockKOTSHandler)(nil).SetSessionSettings), w, r)
+}
+
+// GetLDAPSettings mocks base method
+func (m *MockKOTSHandler) GetLDAPSettings(w http.ResponseWriter, r *http.Request) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "GetLDAPSettings", w, r)
+}
+
+// GetLDAPSettings indicates an expected call of GetLDAPSettings
+func (mr *MockKOTSHandlerMockRecorder) GetLDAPSettings(w, r interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLDAPSettings", reflect.TypeOf((*MockKOTSHandler)(nil).GetLDAPSettings), w, r)
+}
+
+// SetLDAPSettings mocks base method
+func (m *MockKOTSHandler) SetLDAPSettings(w http.ResponseWriter, r *http.Request) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetLDAPSettings", w, r)
+}
+
+// SetLDAPSettings indicates an expected call of SetLDAPSettings
+func (mr *MockKOTSHandlerMockRecorder) SetLDAPSettings(w, r interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetLDAPSettings", reflect.TypeOf((*MockKOTSHandler)(nil).SetLDAPSettings), w, r)
+}
+
+// TestLDAPConnection mocks base method
+func (m *MockKOTSHandler) TestLDAPConnection(w http.ResponseWriter, r *http.Request) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "TestLDAPConnection", w, r)
+}
+
+// TestLDAPConnection indicates an expected call of TestLDAPConnection
+func (mr *MockKOTSHandlerMockRecorder) TestLDAPConnection(w, r interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TestLDAPConnection", reflect.TypeOf((*MockKOTSHandler)(nil).TestLDAPConnection), w, r)
+}
+
+// ListTemporaryAccess mocks base method
+func (m *MockKOTSHandler) ListTemporaryAccess(w http.ResponseWriter, r *http.Request) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "ListTemporaryAccess", w, r)
+}
+
+// ListTemporaryAccess indicates an expected call of ListTemporaryAccess
+func (mr *MockKOTSHandlerMockRecorder) ListTemporaryAccess(w, r interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTemporaryAccess", reflect.TypeOf((*MockKOTSHandler)(nil).ListTemporaryAccess), w, r)
+}
+
+// CreateTemporaryAccess mocks base method
+func (m *MockKOTSHandler) CreateTemporaryAccess(w http.ResponseWriter, r *http.Request) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "CreateTemporaryAccess", w, r)
+}
+
+// CreateTemporaryAccess indicates an expected call of CreateTemporaryAccess
+func (mr *MockKOTSHandlerMockRecorder) CreateTemporaryAccess(w, r interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateTemporaryAccess", reflect.TypeOf((*MockKOTSHandler)(nil).CreateTemporaryAccess), w, r)
+}
+
+// RevokeTemporaryAccess mocks base method
+func (m *MockKOTSHandler) RevokeTemporaryAccess(w http.ResponseWriter, r *http.Request) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "RevokeTemporaryAccess", w, r)
+}
+
+// RevokeTemporaryAccess indicates an expected call of RevokeTemporaryAccess
+func (mr *MockKOTSHandlerMockRecorder) RevokeTemporaryAccess(w, r interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeTemporaryAccess", reflect.TypeOf((*MockKOTSHandler)(nil).RevokeTemporaryAccess), w, r)
+}
+
+// ListRemoteInstalls mocks base method
+func (m *MockKOTSHandler) ListRemoteInstalls(w http.ResponseWriter, r *http.Request) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "ListRemoteInstalls", w, r)
+}
+
+// ListRemoteInstalls indicates an expected call of ListRemoteInstalls
+func (mr *MockKOTSHandlerMockRecorder) ListRemoteInstalls(w, r interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListRemoteInstalls", reflect.TypeOf((*MockKOTSHandler)(nil).ListRemoteInstalls), w, r)
+}
+
+// CreateRemoteInstall mocks base method
+func (m *MockKOTSHandler) CreateRemoteInstall(w http.ResponseWriter, r *http.Request) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "CreateRemoteInstall", w, r)
+}
+
+// CreateRemoteInstall indicates an expected call of CreateRemoteInstall
+func (mr *MockKOTSHandlerMockRecorder) CreateRemoteInstall(w, r interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateRemoteInstall", reflect.TypeOf((*MockKOTSHandler)(nil).CreateRemoteInstall), w, r)
+}
+
+// DeleteRemoteInstall mocks base method
+func (m *MockKOTSHandler) DeleteRemoteInstall(w http.ResponseWriter, r *http.Request) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "DeleteRemoteInstall", w, r)
+}
+
+// DeleteRemoteInstall indicates an expected call of DeleteRemoteInstall
+func (mr *MockKOTSHandlerMockRecorder) DeleteRemoteInstall(w, r interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteRemoteInstall", reflect.TypeOf((*MockKOTSHandler)(nil).DeleteRemoteInstall), w, r)
+}
+
+// RefreshRemoteInstall mocks base method
+func (m *MockKOTSHandler) RefreshRemoteInstall(w http.ResponseWriter, r *http.Request) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "RefreshRemoteInstall", w, r)
+}
+
+// RefreshRemoteInstall indicates an expected call of RefreshRemoteInstall
+func (mr *MockKOTSHandlerMockRecorder) RefreshRemoteInstall(w, r interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RefreshRemoteInstall", reflect.TypeOf((*MockKOTSHandler)(nil).RefreshRemoteInstall), w, r)
+}
+
+// GetLogSettings mocks base method
+func (m *MockKOTSHandler) GetLogSettings(w http.ResponseWriter, r *http.Request) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "GetLogSettings", w, r)
+}
+
+// GetLogSettings indicates an expected call of GetLogSettings
+func (mr *MockKOTSHandlerMockRecorder) GetLogSettings(w, r interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLogSettings", reflect.TypeOf((*MockKOTSHandler)(nil).GetLogSettings), w, r)
+}
+
+// SetLogSettings mocks base method
+func (m *MockKOTSHandler) SetLogSettings(w http.ResponseWriter, r *http.Request) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetLogSettings", w, r)
+}
+
+// SetLogSettings indicates an expected call of SetLogSettings
+func (mr *MockKOTSHandlerMockRecorder) SetLogSettings(w, r interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetLogSettings", reflect.TypeOf((*MockKOTSHandler)(nil).SetLogSettings), w, r)
+}
+
 // UpdateAppGitOps mocks base method
 func (m *MockKOTSHandler) UpdateAppGitOps(w http.ResponseWriter, r *http.Request) {
 	m.ctrl.T.Helper()
@@ -2,14 +2,56 @@ package handlers
 
 import (
 	"encoding/json"
+	"fmt"
 	"net/http"
+	"os"
 
+	handlertypes "github.com/replicatedhq/kots/pkg/api/handlers/types"
 	"github.com/replicatedhq/kots/pkg/logger"
+	"github.com/replicatedhq/kots/pkg/prometheus"
+	prometheustypes "github.com/replicatedhq/kots/pkg/prometheus/types"
 	"github.com/replicatedhq/kots/pkg/store"
 )
 
 type SetPrometheusAddressRequest struct {
 	Value string `json:"value"`
+	// Auth replaces the stored credentials if it is set. An empty auth removes them.
+	Auth *prometheustypes.Auth `json:"auth,omitempty"`
+	// SkipValidation saves the settings without checking that prometheus can be reached
+	SkipValidation bool `json:"skipValidation"`
+}
+
+type ValidatePrometheusSettingsResponse struct {
+	Success bool   `json:"success"`
+	Error   string `json:"error,omitempty"`
+}
+
+// GetPrometheusSettings returns the prometheus settings without the password or bearer token
+func (h *Handler) GetPrometheusSettings(w http.ResponseWriter, r *http.Request) {
+	address, err := store.GetStore().GetPrometheusAddress()
+	if err != nil {
+		InternalErrorJSON(w, r, "failed to get prometheus address", err)
+		return
+	}
+
+	auth, err := store.GetStore().GetPrometheusAuth()
+	if err != nil {
+		InternalErrorJSON(w, r, "failed to get prometheus auth", err)
+		return
+	}
+
+	response := handlertypes.GetPrometheusSettingsResponse{
+		Address:        address,
+		Username:       auth.Username,
+		HasPassword:    auth.Password != "",
+		HasBearerToken: auth.BearerToken != "",
+	}
+	if address == "" && os.Getenv("PROMETHEUS_ADDRESS") != "" {
+		response.Address = os.Getenv("PROMETHEUS_ADDRESS")
+		response.IsAddressFromEnv = true
+	}
+
+	JSON(w, http.StatusOK, response)
 }
 
 func (h *Handler) SetPrometheusAddress(w http.ResponseWriter, r *http.Request) {
@@ -20,11 +62,57 @@ func (h *Handler) SetPrometheusAddress(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	auth, err := store.GetStore().GetPrometheusAuth()
+	if err != nil {
+		InternalErrorJSON(w, r, "failed to get prometheus auth", err)
+		return
+	}
+	if setPrometheusAddressRequest.Auth != nil {
+		auth = *setPrometheusAddressRequest.Auth
+	}
+
+	if setPrometheusAddressRequest.Value != "" && !setPrometheusAddressRequest.SkipValidation {
+		settings := prometheustypes.Settings{
+			Address: setPrometheusAddressRequest.Value,
+			Auth:    auth,
+		}
+		if err := prometheus.Validate(settings); err != nil {
+			BadRequestJSON(w, r, fmt.Sprintf("failed to validate prometheus settings: %v", err), err)
+			return
+		}
+	}
+
 	if err := store.GetStore().SetPrometheusAddress(setPrometheusAddressRequest.Value); err != nil {
 		logger.Error(err)
 		w.WriteHeader(500)
 		return
 	}
 
+	if setPrometheusAddressRequest.Auth != nil {
+		if err := store.GetStore().SetPrometheusAuth(auth); err != nil {
+			InternalErrorJSON(w, r, "failed to set prometheus auth", err)
+			return
+		}
+	}
+
 	JSON(w, 204, "")
 }
+
+// ValidatePrometheusSettings checks that prometheus can be reached with the saved settings
+func (h *Handler) ValidatePrometheusSettings(w http.ResponseWriter, r *http.Request) {
+	settings, err := prometheus.GetSettings()
+	if err != nil {
+		InternalErrorJSON(w, r, "failed to get prometheus settings", err)
+		return
+	}
+
+	response := ValidatePrometheusSettingsResponse{
+		Success: true,
+	}
+	if err := prometheus.Validate(*settings); err != nil {
+		response.Success = false
+		response.Error = err.Error()
+	}
+
+	JSON(w, http.StatusOK, response)
+}
@@ -0,0 +1,60 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/pkg/errors"
+	"github.com/replicatedhq/kots/pkg/policy"
+	"github.com/replicatedhq/kots/pkg/store"
+)
+
+type ReadOnlyModeResponse struct {
+	Enabled bool `json:"enabled"`
+}
+
+type SetReadOnlyModeRequest struct {
+	Enabled bool `json:"enabled"`
+}
+
+func (h *Handler) GetReadOnlyMode(w http.ResponseWriter, r *http.Request) {
+	enabled, err := store.GetStore().IsReadOnlyMode()
+	if err != nil {
+		InternalErrorJSON(w, r, "failed to get read-only mode", err)
+		return
+	}
+
+	JSON(w, http.StatusOK, ReadOnlyModeResponse{
+		Enabled: enabled,
+	})
+}
+
+// SetReadOnlyMode turns read-only mode on or off. While it is on, all handlers with a write policy return 403.
+func (h *Handler) SetReadOnlyMode(w http.ResponseWriter, r *http.Request) {
+	request := SetReadOnlyModeRequest{}
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		BadRequestJSON(w, r, "failed to decode request body", err)
+		return
+	}
+
+	if err := store.GetStore().SetReadOnlyMode(request.Enabled); err != nil {
+		InternalErrorJSON(w, r, "failed to set read-only mode", err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// requireNotReadOnlyMode returns 403 while read-only mode is on. Routes that are authenticated with the KOTS token
+// don't go through the policy middleware, so the handlers that make changes check it themselves.
+func requireNotReadOnlyMode(w http.ResponseWriter, r *http.Request) error {
+	isReadOnly, err := store.GetStore().IsReadOnlyMode()
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return errors.Wrap(err, "failed to check read-only mode")
+	}
+	if isReadOnly {
+		return policy.ReadOnlyModeError{}.Abort(w)
+	}
+	return nil
+}
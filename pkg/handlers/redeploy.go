@@ -6,6 +6,9 @@ import (
 
 	"github.com/gorilla/mux"
 	"github.com/pkg/errors"
+	handlertypes "github.com/replicatedhq/kots/pkg/api/handlers/types"
+	deployhistorytypes "github.com/replicatedhq/kots/pkg/deployhistory/types"
+	"github.com/replicatedhq/kots/pkg/freeze"
 	"github.com/replicatedhq/kots/pkg/logger"
 	"github.com/replicatedhq/kots/pkg/socketservice"
 	"github.com/replicatedhq/kots/pkg/store"
@@ -40,13 +43,33 @@ func (h *Handler) RedeployAppVersion(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// redeploying another version would roll the app back without approval
+	if a.RequireDeployApproval {
+		deployedSequence, err := store.GetStore().GetCurrentParentSequence(a.ID, downstreams[0].ClusterID)
+		if err != nil {
+			InternalErrorJSON(w, r, "failed to get deployed sequence", err)
+			return
+		}
+		if int64(sequence) != deployedSequence {
+			ErrorJSON(w, r, http.StatusConflict, handlertypes.ErrorCodeConflict, "only the deployed version of an app that requires deploy approval can be redeployed", nil)
+			return
+		}
+	}
+
 	if err := store.GetStore().DeleteDownstreamDeployStatus(a.ID, downstreams[0].ClusterID, int64(sequence)); err != nil {
 		logger.Error(err)
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
 
-	if err := socketservice.RedeployAppVersion(a.ID, int64(sequence), nil); err != nil {
+	socketservice.SetDeployRequestID(r.Context(), a.ID, int64(sequence), GetRequestID(r))
+
+	deployer := deployhistorytypes.Deployer{DeployedBy: sessionUserID(r)}
+	if err := socketservice.RedeployAppVersion(a.ID, int64(sequence), nil, deployer); err != nil {
+		if freeze.IsFrozen(err) {
+			deployFailedJSON(w, r, "failed to redeploy version", err)
+			return
+		}
 		logger.Error(err)
 		w.WriteHeader(http.StatusInternalServerError)
 		return
@@ -1,12 +1,13 @@
 package handlers
 
 import (
+	"crypto/tls"
 	"encoding/json"
-	"io/ioutil"
 	"net/http"
 	"net/url"
 	"os"
 	"path/filepath"
+	"strings"
 
 	"github.com/aws/aws-sdk-go/aws/awserr"
 	"github.com/containers/image/v5/docker"
@@ -14,12 +15,15 @@ import (
 	"github.com/gorilla/mux"
 	"github.com/pkg/errors"
 	apptypes "github.com/replicatedhq/kots/pkg/app/types"
+	"github.com/replicatedhq/kots/pkg/applock"
+	"github.com/replicatedhq/kots/pkg/clientcert"
 	dockerregistry "github.com/replicatedhq/kots/pkg/docker/registry"
 	"github.com/replicatedhq/kots/pkg/logger"
 	"github.com/replicatedhq/kots/pkg/preflight"
 	"github.com/replicatedhq/kots/pkg/registry"
 	registrytypes "github.com/replicatedhq/kots/pkg/registry/types"
 	"github.com/replicatedhq/kots/pkg/store"
+	"github.com/replicatedhq/kots/pkg/tracing"
 	"github.com/replicatedhq/kots/pkg/version"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/client-go/kubernetes/scheme"
@@ -31,6 +35,9 @@ type UpdateAppRegistryRequest struct {
 	Password   string `json:"password"`
 	Namespace  string `json:"namespace"`
 	IsReadOnly bool   `json:"isReadOnly"`
+	// ClientCert and ClientKey are presented to registries that require mutual tls
+	ClientCert string `json:"clientCert,omitempty"`
+	ClientKey  string `json:"clientKey,omitempty"`
 }
 
 type UpdateAppRegistryResponse struct {
@@ -42,13 +49,14 @@ type UpdateAppRegistryResponse struct {
 }
 
 type GetAppRegistryResponse struct {
-	Success    bool   `json:"success"`
-	Error      string `json:"error,omitempty"`
-	Hostname   string `json:"hostname"`
-	Namespace  string `json:"namespace"`
-	Username   string `json:"username"`
-	Password   string `json:"password"`
-	IsReadOnly bool   `json:"isReadOnly"`
+	Success              bool   `json:"success"`
+	Error                string `json:"error,omitempty"`
+	Hostname             string `json:"hostname"`
+	Namespace            string `json:"namespace"`
+	Username             string `json:"username"`
+	Password             string `json:"password"`
+	IsReadOnly           bool   `json:"isReadOnly"`
+	HasClientCertificate bool   `json:"hasClientCertificate"`
 }
 
 type GetKotsadmRegistryResponse struct {
@@ -67,6 +75,8 @@ type ValidateAppRegistryRequest struct {
 	Username   string `json:"username"`
 	Password   string `json:"password"`
 	IsReadOnly bool   `json:"isReadOnly"`
+	ClientCert string `json:"clientCert,omitempty"`
+	ClientKey  string `json:"clientKey,omitempty"`
 }
 
 type ValidateAppRegistryResponse struct {
@@ -139,17 +149,37 @@ func (h *Handler) UpdateAppRegistry(w http.ResponseWriter, r *http.Request) {
 		registryPassword = registrySettings.Password
 	}
 
+	clientCertificate, err := registryClientCertificate(updateAppRegistryRequest.ClientCert, updateAppRegistryRequest.ClientKey)
+	if err != nil {
+		JSON(w, 400, NewErrorResponse(err))
+		return
+	}
+
 	access := dockerregistry.ActionPush
 	if updateAppRegistryRequest.IsReadOnly {
 		access = dockerregistry.ActionPull
 	}
-	err = dockerregistry.CheckAccess(updateAppRegistryRequest.Hostname, updateAppRegistryRequest.Username, registryPassword, updateAppRegistryRequest.Namespace, access)
+	err = dockerregistry.CheckAccess(updateAppRegistryRequest.Hostname, updateAppRegistryRequest.Username, registryPassword, updateAppRegistryRequest.Namespace, access, clientCertificate)
 	if err != nil {
 		logger.Infof("Failed to test %s access to %q with user %q: %v", access, updateAppRegistryRequest.Hostname, updateAppRegistryRequest.Username, err)
 		JSON(w, 400, NewErrorResponse(err))
 		return
 	}
 
+	if clientCertificate != nil {
+		// the client certificate is stored by host so that it is used for every push and pull from the registry
+		err := setClientCertificate(registryHost(updateAppRegistryRequest.Hostname), &clientcert.ClientCertificate{
+			Cert: updateAppRegistryRequest.ClientCert,
+			Key:  updateAppRegistryRequest.ClientKey,
+		})
+		if err != nil {
+			logger.Error(errors.Wrap(err, "failed to set registry client certificate"))
+			updateAppRegistryResponse.Error = err.Error()
+			JSON(w, http.StatusInternalServerError, updateAppRegistryResponse)
+			return
+		}
+	}
+
 	updateAppRegistryResponse.Hostname = updateAppRegistryRequest.Hostname
 	updateAppRegistryResponse.Username = updateAppRegistryRequest.Username
 	updateAppRegistryResponse.Namespace = updateAppRegistryRequest.Namespace
@@ -170,6 +200,7 @@ func (h *Handler) UpdateAppRegistry(w http.ResponseWriter, r *http.Request) {
 
 	// in a goroutine, start pushing the images to the remote registry
 	// we will let this function return while this happens
+	ctx := tracing.Detach(r.Context())
 	go func() {
 		skipImagePush := updateAppRegistryRequest.IsReadOnly
 		if foundApp.IsAirgap {
@@ -177,8 +208,22 @@ func (h *Handler) UpdateAppRegistry(w http.ResponseWriter, r *http.Request) {
 			skipImagePush = true
 		}
 
+		unlock, err := applock.Lock(foundApp.ID, applock.WaitTimeout)
+		if err != nil {
+			logger.Error(errors.Wrap(err, "failed to lock app"))
+			return
+		}
+		defer unlock()
+
+		// another version may have been created while waiting for the lock
+		currentApp, err := store.GetStore().GetApp(foundApp.ID)
+		if err != nil {
+			logger.Error(errors.Wrap(err, "failed to get app"))
+			return
+		}
+
 		appDir, err := registry.RewriteImages(
-			foundApp.ID, foundApp.CurrentSequence, updateAppRegistryRequest.Hostname,
+			ctx, currentApp.ID, currentApp.CurrentSequence, updateAppRegistryRequest.Hostname,
 			updateAppRegistryRequest.Username, registryPassword,
 			updateAppRegistryRequest.Namespace, skipImagePush, nil)
 		if err != nil {
@@ -199,7 +244,7 @@ func (h *Handler) UpdateAppRegistry(w http.ResponseWriter, r *http.Request) {
 		}
 		defer os.RemoveAll(appDir)
 
-		newSequence, err := store.GetStore().CreateAppVersion(foundApp.ID, &foundApp.CurrentSequence, appDir, "Registry Change", false, &version.DownstreamGitOps{})
+		newSequence, err := store.GetStore().CreateAppVersion(currentApp.ID, &currentApp.CurrentSequence, appDir, "Registry Change", false, &version.DownstreamGitOps{})
 		if err != nil {
 			logger.Error(errors.Wrap(err, "failed to create app version"))
 			return
@@ -221,6 +266,22 @@ func (h *Handler) UpdateAppRegistry(w http.ResponseWriter, r *http.Request) {
 	JSON(w, http.StatusOK, updateAppRegistryResponse)
 }
 
+// registryClientCertificate parses the client certificate in a registry settings request, if there is one
+func registryClientCertificate(cert string, key string) (*tls.Certificate, error) {
+	if cert == "" && key == "" {
+		return nil, nil
+	}
+	clientCertificate, err := clientcert.ClientCertificate{Cert: cert, Key: key}.TLSCertificate()
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid client certificate")
+	}
+	return &clientCertificate, nil
+}
+
+func registryHost(hostname string) string {
+	return strings.Split(hostname, "/")[0]
+}
+
 func registrySettingsChanged(app *apptypes.App, new UpdateAppRegistryRequest, current registrytypes.RegistrySettings) (bool, error) {
 	if new.Hostname != current.Hostname {
 		return true, nil
@@ -240,18 +301,7 @@ func registrySettingsChanged(app *apptypes.App, new UpdateAppRegistryRequest, cu
 
 	// Because an old version can be editted, we may need to push images if registry hostname has changed
 	// TODO: Handle namespace changes too
-	archiveDir, err := ioutil.TempDir("", "kotsadm-")
-	if err != nil {
-		return false, errors.Wrap(err, "failed to create temp dir")
-	}
-	defer os.RemoveAll(archiveDir)
-
-	err = store.GetStore().GetAppVersionArchive(app.ID, app.CurrentSequence, archiveDir)
-	if err != nil {
-		return false, errors.Wrap(err, "failed to get version archive")
-	}
-
-	secretData, err := ioutil.ReadFile(filepath.Join(archiveDir, "overlays", "midstream", "secret.yaml"))
+	secretData, err := version.GetArchiveFile(app.ID, app.CurrentSequence, filepath.Join("overlays", "midstream", "secret.yaml"))
 	if err != nil {
 		if os.IsNotExist(err) {
 			if new.Hostname != "" {
@@ -326,6 +376,10 @@ func (h *Handler) GetAppRegistry(w http.ResponseWriter, r *http.Request) {
 		getAppRegistryResponse.Password = registrytypes.PasswordMask
 	}
 
+	if settings.Hostname != "" {
+		getAppRegistryResponse.HasClientCertificate = clientcert.Get(registryHost(settings.Hostname)) != nil
+	}
+
 	getAppRegistryResponse.Success = true
 
 	JSON(w, 200, getAppRegistryResponse)
@@ -418,7 +472,13 @@ func (h *Handler) ValidateAppRegistry(w http.ResponseWriter, r *http.Request) {
 		access = dockerregistry.ActionPull
 	}
 
-	err = dockerregistry.CheckAccess(validateAppRegistryRequest.Hostname, validateAppRegistryRequest.Username, password, validateAppRegistryRequest.Namespace, access)
+	clientCertificate, err := registryClientCertificate(validateAppRegistryRequest.ClientCert, validateAppRegistryRequest.ClientKey)
+	if err != nil {
+		JSON(w, 400, NewErrorResponse(err))
+		return
+	}
+
+	err = dockerregistry.CheckAccess(validateAppRegistryRequest.Hostname, validateAppRegistryRequest.Username, password, validateAppRegistryRequest.Namespace, access, clientCertificate)
 	if err != nil {
 		// NOTE: it is possible this is a 500 sometimes
 		logger.Infof("Failed to test %s access to %q with user %q: %v", access, validateAppRegistryRequest.Hostname, validateAppRegistryRequest.Username, err)
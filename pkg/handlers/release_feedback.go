@@ -0,0 +1,68 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/replicatedhq/kots/pkg/logger"
+	releasefeedbacktypes "github.com/replicatedhq/kots/pkg/releasefeedback/types"
+	"github.com/replicatedhq/kots/pkg/store"
+	"go.uber.org/zap"
+)
+
+type SetReleaseFeedbackConsentRequest struct {
+	Enabled                 bool `json:"enabled"`
+	IncludePreflightDetails bool `json:"includePreflightDetails"`
+}
+
+// GetReleaseFeedbackConsent returns a disabled consent if it was never given
+func (h *Handler) GetReleaseFeedbackConsent(w http.ResponseWriter, r *http.Request) {
+	foundApp, err := store.GetStore().GetAppFromSlug(mux.Vars(r)["appSlug"])
+	if err != nil {
+		NotFoundJSON(w, r, "failed to get app from slug", err)
+		return
+	}
+
+	consent, err := store.GetStore().GetReleaseFeedbackConsent(foundApp.ID)
+	if err != nil {
+		InternalErrorJSON(w, r, "failed to get release feedback consent", err)
+		return
+	}
+	if consent == nil {
+		consent = &releasefeedbacktypes.Consent{}
+	}
+
+	JSON(w, http.StatusOK, consent)
+}
+
+func (h *Handler) SetReleaseFeedbackConsent(w http.ResponseWriter, r *http.Request) {
+	request := SetReleaseFeedbackConsentRequest{}
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		BadRequestJSON(w, r, "failed to decode request body", err)
+		return
+	}
+
+	foundApp, err := store.GetStore().GetAppFromSlug(mux.Vars(r)["appSlug"])
+	if err != nil {
+		NotFoundJSON(w, r, "failed to get app from slug", err)
+		return
+	}
+
+	consent := releasefeedbacktypes.Consent{
+		Enabled: request.Enabled,
+		// details are only reported if reporting is enabled
+		IncludePreflightDetails: request.Enabled && request.IncludePreflightDetails,
+		UpdatedBy:               sessionUserID(r),
+		UpdatedAt:               time.Now(),
+	}
+	if err := store.GetStore().SetReleaseFeedbackConsent(foundApp.ID, consent); err != nil {
+		InternalErrorJSON(w, r, "failed to set release feedback consent", err)
+		return
+	}
+
+	logger.Info("release feedback consent changed", zap.String("audit", "release-feedback"), zap.String("app", foundApp.Slug), zap.String("user", consent.UpdatedBy), zap.Bool("enabled", consent.Enabled), zap.Bool("includePreflightDetails", consent.IncludePreflightDetails))
+
+	JSON(w, http.StatusOK, consent)
+}
@@ -0,0 +1,157 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/replicatedhq/kots/pkg/remoteinstall"
+	remoteinstalltypes "github.com/replicatedhq/kots/pkg/remoteinstall/types"
+	"github.com/replicatedhq/kots/pkg/store"
+	"github.com/segmentio/ksuid"
+)
+
+type ListRemoteInstallsResponse struct {
+	RemoteInstalls []remoteinstalltypes.RemoteInstall `json:"remoteInstalls"`
+}
+
+type CreateRemoteInstallRequest struct {
+	Name string `json:"name"`
+	URL  string `json:"url"`
+	// Token is the value of the authorization header for the remote admin console, such as its kotsadm auth string
+	Token                 string `json:"token"`
+	InsecureSkipTLSVerify bool   `json:"insecureSkipTLSVerify"`
+}
+
+// ListRemoteInstalls returns the registered remote installations with the last report fetched from each, so that
+// their apps can be shown read-only next to the apps of this installation
+func (h *Handler) ListRemoteInstalls(w http.ResponseWriter, r *http.Request) {
+	remoteInstalls, err := store.GetStore().ListRemoteInstalls()
+	if err != nil {
+		InternalErrorJSON(w, r, "failed to list remote installs", err)
+		return
+	}
+
+	JSON(w, http.StatusOK, ListRemoteInstallsResponse{
+		RemoteInstalls: remoteInstalls,
+	})
+}
+
+// CreateRemoteInstall registers a remote installation. Its report is fetched first, so that a wrong url or token is
+// rejected instead of being stored.
+func (h *Handler) CreateRemoteInstall(w http.ResponseWriter, r *http.Request) {
+	request := CreateRemoteInstallRequest{}
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		BadRequestJSON(w, r, "failed to decode request body", err)
+		return
+	}
+
+	name := strings.TrimSpace(request.Name)
+	if name == "" {
+		BadRequestJSON(w, r, "name is required", nil)
+		return
+	}
+	if request.Token == "" {
+		BadRequestJSON(w, r, "token is required", nil)
+		return
+	}
+	url, err := remoteinstall.NormalizeURL(request.URL)
+	if err != nil {
+		BadRequestJSON(w, r, "invalid url", err)
+		return
+	}
+
+	remoteInstalls, err := store.GetStore().ListRemoteInstalls()
+	if err != nil {
+		InternalErrorJSON(w, r, "failed to list remote installs", err)
+		return
+	}
+	for _, existing := range remoteInstalls {
+		if existing.URL == url {
+			BadRequestJSON(w, r, "a remote install with this url is already registered", nil)
+			return
+		}
+	}
+
+	remoteInstall := remoteinstalltypes.RemoteInstall{
+		ID:                    ksuid.New().String(),
+		Name:                  name,
+		URL:                   url,
+		Token:                 request.Token,
+		InsecureSkipTLSVerify: request.InsecureSkipTLSVerify,
+		CreatedAt:             time.Now(),
+	}
+
+	report, err := remoteinstall.FetchReport(remoteInstall)
+	if err != nil {
+		BadRequestJSON(w, r, "failed to fetch report from remote install", err)
+		return
+	}
+
+	if err := store.GetStore().CreateRemoteInstall(remoteInstall); err != nil {
+		InternalErrorJSON(w, r, "failed to create remote install", err)
+		return
+	}
+
+	fetchedAt := time.Now()
+	if err := store.GetStore().SetRemoteInstallReport(remoteInstall.ID, report, fetchedAt); err != nil {
+		InternalErrorJSON(w, r, "failed to set remote install report", err)
+		return
+	}
+	remoteInstall.LastReport = report
+	remoteInstall.LastReportAt = &fetchedAt
+
+	JSON(w, http.StatusOK, remoteInstall)
+}
+
+// DeleteRemoteInstall unregisters a remote installation and deletes its token
+func (h *Handler) DeleteRemoteInstall(w http.ResponseWriter, r *http.Request) {
+	remoteInstall, err := getRemoteInstallFromRequest(w, r)
+	if err != nil {
+		return
+	}
+
+	if err := store.GetStore().DeleteRemoteInstall(remoteInstall.ID); err != nil {
+		InternalErrorJSON(w, r, "failed to delete remote install", err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// RefreshRemoteInstall fetches the report of a remote installation now instead of waiting for the refresh loop
+func (h *Handler) RefreshRemoteInstall(w http.ResponseWriter, r *http.Request) {
+	remoteInstall, err := getRemoteInstallFromRequest(w, r)
+	if err != nil {
+		return
+	}
+
+	if err := remoteinstall.Refresh(*remoteInstall); err != nil {
+		InternalErrorJSON(w, r, "failed to refresh remote install", err)
+		return
+	}
+
+	remoteInstall, err = store.GetStore().GetRemoteInstall(remoteInstall.ID)
+	if err != nil {
+		InternalErrorJSON(w, r, "failed to get remote install", err)
+		return
+	}
+
+	JSON(w, http.StatusOK, remoteInstall)
+}
+
+// getRemoteInstallFromRequest writes the error response if the remote install of the request can't be found
+func getRemoteInstallFromRequest(w http.ResponseWriter, r *http.Request) (*remoteinstalltypes.RemoteInstall, error) {
+	remoteInstall, err := store.GetStore().GetRemoteInstall(mux.Vars(r)["remoteInstallId"])
+	if err != nil {
+		if store.GetStore().IsNotFound(err) {
+			NotFoundJSON(w, r, "remote install not found", err)
+		} else {
+			InternalErrorJSON(w, r, "failed to get remote install", err)
+		}
+		return nil, err
+	}
+	return remoteInstall, nil
+}
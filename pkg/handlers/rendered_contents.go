@@ -48,7 +48,7 @@ func (h *Handler) GetAppRenderedContents(w http.ResponseWriter, r *http.Request)
 	}
 	defer os.RemoveAll(archivePath)
 
-	err = store.GetStore().GetAppVersionArchive(a.ID, int64(sequence), archivePath)
+	err = store.GetReadStore().GetAppVersionArchive(a.ID, int64(sequence), archivePath)
 	if err != nil {
 		logger.Error(err)
 		w.WriteHeader(500)
@@ -0,0 +1,169 @@
+package handlers
+
+import (
+	"io/ioutil"
+	"net/http"
+	"os"
+	"strconv"
+
+	"github.com/gorilla/mux"
+	"github.com/pkg/errors"
+	"github.com/replicatedhq/kots/pkg/downstream"
+	"github.com/replicatedhq/kots/pkg/kotsutil"
+	"github.com/replicatedhq/kots/pkg/store"
+)
+
+type GetAppRenderedManifestsResponse struct {
+	Manifests []downstream.RenderedManifest `json:"manifests"`
+}
+
+// GetAppRenderedManifests returns the kustomize output of a downstream for an app version.
+// Results can be filtered with the "kind" and "name" query params, and the values of secrets
+// are redacted unless "includeSecrets" is true.
+func (h *Handler) GetAppRenderedManifests(w http.ResponseWriter, r *http.Request) {
+	appSlug := mux.Vars(r)["appSlug"]
+	sequence, err := strconv.ParseInt(mux.Vars(r)["sequence"], 10, 64)
+	if err != nil {
+		BadRequestJSON(w, r, "invalid sequence", err)
+		return
+	}
+
+	filter := getManifestFilter(r)
+
+	rendered, ok := renderAppManifests(w, r, appSlug, sequence)
+	if !ok {
+		return
+	}
+
+	manifests, err := downstream.FilterManifests(rendered, filter)
+	if err != nil {
+		InternalErrorJSON(w, r, "failed to filter manifests", err)
+		return
+	}
+
+	JSON(w, http.StatusOK, GetAppRenderedManifestsResponse{
+		Manifests: manifests,
+	})
+}
+
+type SearchAppRenderedContentsResponse struct {
+	Results []downstream.SearchResult `json:"results"`
+	// Truncated is true if the search stopped at the maximum number of matches
+	Truncated bool `json:"truncated"`
+}
+
+// SearchAppRenderedContents searches the kustomize output of a downstream for an app version, so that operators can
+// find where a value is defined without downloading the archive. The "q" query param is matched case insensitively,
+// or as a regular expression if "regex" is true. Results can be filtered with the "kind", "name" and "file" query
+// params, "file" being a glob of the rendered contents file names. The values of secrets are redacted before
+// searching unless "includeSecrets" is true.
+func (h *Handler) SearchAppRenderedContents(w http.ResponseWriter, r *http.Request) {
+	appSlug := mux.Vars(r)["appSlug"]
+	sequence, err := strconv.ParseInt(mux.Vars(r)["sequence"], 10, 64)
+	if err != nil {
+		BadRequestJSON(w, r, "invalid sequence", err)
+		return
+	}
+
+	isRegex, _ := strconv.ParseBool(r.URL.Query().Get("regex"))
+	opts := downstream.SearchOptions{
+		Query:  r.URL.Query().Get("q"),
+		Regex:  isRegex,
+		File:   r.URL.Query().Get("file"),
+		Filter: getManifestFilter(r),
+	}
+	if opts.Query == "" {
+		BadRequestJSON(w, r, "q is required", nil)
+		return
+	}
+	if s := r.URL.Query().Get("limit"); s != "" {
+		limit, err := strconv.Atoi(s)
+		if err != nil || limit < 0 {
+			BadRequestJSON(w, r, "invalid limit", err)
+			return
+		}
+		opts.MaxMatches = limit
+	}
+
+	rendered, ok := renderAppManifests(w, r, appSlug, sequence)
+	if !ok {
+		return
+	}
+
+	results, truncated, err := downstream.SearchManifests(rendered, opts)
+	if err != nil {
+		BadRequestJSON(w, r, "invalid search", err)
+		return
+	}
+
+	JSON(w, http.StatusOK, SearchAppRenderedContentsResponse{
+		Results:   results,
+		Truncated: truncated,
+	})
+}
+
+func getManifestFilter(r *http.Request) downstream.ManifestFilter {
+	includeSecrets, _ := strconv.ParseBool(r.URL.Query().Get("includeSecrets"))
+	return downstream.ManifestFilter{
+		Kind:           r.URL.Query().Get("kind"),
+		Name:           r.URL.Query().Get("name"),
+		IncludeSecrets: includeSecrets,
+	}
+}
+
+// renderAppManifests returns the kustomize output of the downstream in the "downstream" query param for an app
+// version. The error response is written if it can't be rendered.
+func renderAppManifests(w http.ResponseWriter, r *http.Request, appSlug string, sequence int64) ([]byte, bool) {
+	a, err := store.GetStore().GetAppFromSlug(appSlug)
+	if err != nil {
+		NotFoundJSON(w, r, "app not found", err)
+		return nil, false
+	}
+
+	// the name is used as a path in the archive, so only the downstreams of the app are accepted
+	downstreamName := r.URL.Query().Get("downstream")
+	if downstreamName != "" {
+		downstreams, err := store.GetStore().ListDownstreamsForApp(a.ID)
+		if err != nil {
+			InternalErrorJSON(w, r, "failed to list downstreams", err)
+			return nil, false
+		}
+		found := false
+		for _, d := range downstreams {
+			if d.Name == downstreamName {
+				found = true
+				break
+			}
+		}
+		if !found {
+			BadRequestJSON(w, r, "unknown downstream", errors.Errorf("downstream %q not found for app %s", downstreamName, a.Slug))
+			return nil, false
+		}
+	}
+
+	archivePath, err := ioutil.TempDir("", "kotsadm")
+	if err != nil {
+		InternalErrorJSON(w, r, "failed to create temp dir", err)
+		return nil, false
+	}
+	defer os.RemoveAll(archivePath)
+
+	if err := store.GetReadStore().GetAppVersionArchive(a.ID, sequence, archivePath); err != nil {
+		NotFoundJSON(w, r, "failed to get app version archive", err)
+		return nil, false
+	}
+
+	kotsKinds, err := kotsutil.LoadKotsKindsFromPath(archivePath)
+	if err != nil {
+		InternalErrorJSON(w, r, "failed to load kots kinds", err)
+		return nil, false
+	}
+
+	rendered, err := downstream.RenderManifests(archivePath, downstreamName, kotsKinds.KustomizeVersion())
+	if err != nil {
+		InternalErrorJSON(w, r, "failed to render manifests", errors.Wrap(err, "failed to render manifests"))
+		return nil, false
+	}
+
+	return rendered, true
+}
@@ -0,0 +1,105 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/replicatedhq/kots/pkg/cronschedule"
+	"github.com/replicatedhq/kots/pkg/restoredrill"
+	restoredrilltypes "github.com/replicatedhq/kots/pkg/restoredrill/types"
+	"github.com/replicatedhq/kots/pkg/store"
+)
+
+type GetRestoreDrillsResponse struct {
+	Schedule  string                    `json:"schedule"`
+	Namespace string                    `json:"namespace"`
+	Drills    []restoredrilltypes.Drill `json:"drills"`
+}
+
+type SetRestoreDrillScheduleRequest struct {
+	// Schedule is a cron expression, drills are not scheduled if it's empty
+	Schedule string `json:"schedule"`
+}
+
+// GetRestoreDrills returns the restore drill schedule of the app and its scheduled, running and finished drills
+func (h *Handler) GetRestoreDrills(w http.ResponseWriter, r *http.Request) {
+	foundApp, err := store.GetStore().GetAppFromSlug(mux.Vars(r)["appSlug"])
+	if err != nil {
+		NotFoundJSON(w, r, "failed to get app from slug", err)
+		return
+	}
+
+	drills, err := store.GetStore().ListRestoreDrills(foundApp.ID)
+	if err != nil {
+		InternalErrorJSON(w, r, "failed to list restore drills", err)
+		return
+	}
+
+	JSON(w, http.StatusOK, GetRestoreDrillsResponse{
+		Schedule:  foundApp.RestoreDrillSchedule,
+		Namespace: restoredrill.Namespace(foundApp),
+		Drills:    drills,
+	})
+}
+
+// SetRestoreDrillSchedule replaces the restore drill schedule of the app and queues the next drill
+func (h *Handler) SetRestoreDrillSchedule(w http.ResponseWriter, r *http.Request) {
+	request := SetRestoreDrillScheduleRequest{}
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		BadRequestJSON(w, r, "failed to decode request body", err)
+		return
+	}
+
+	foundApp, err := store.GetStore().GetAppFromSlug(mux.Vars(r)["appSlug"])
+	if err != nil {
+		NotFoundJSON(w, r, "failed to get app from slug", err)
+		return
+	}
+
+	if request.Schedule != "" {
+		if _, err := cronschedule.Parse(request.Schedule, foundApp.ScheduleTimezone); err != nil {
+			BadRequestJSON(w, r, "invalid cron schedule expression", err)
+			return
+		}
+	}
+
+	if request.Schedule == foundApp.RestoreDrillSchedule {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	if err := store.GetStore().DeleteScheduledRestoreDrills(foundApp.ID); err != nil {
+		InternalErrorJSON(w, r, "failed to delete scheduled restore drills", err)
+		return
+	}
+	if err := store.GetStore().SetRestoreDrillSchedule(foundApp.ID, request.Schedule); err != nil {
+		InternalErrorJSON(w, r, "failed to set restore drill schedule", err)
+		return
+	}
+	if request.Schedule != "" {
+		if err := restoredrill.Schedule(foundApp.ID, request.Schedule, foundApp.ScheduleTimezone); err != nil {
+			InternalErrorJSON(w, r, "failed to schedule restore drill", err)
+			return
+		}
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// RunRestoreDrill queues a restore drill of the latest backup of the app, it starts within a minute, or after the
+// drill that is running
+func (h *Handler) RunRestoreDrill(w http.ResponseWriter, r *http.Request) {
+	foundApp, err := store.GetStore().GetAppFromSlug(mux.Vars(r)["appSlug"])
+	if err != nil {
+		NotFoundJSON(w, r, "failed to get app from slug", err)
+		return
+	}
+
+	if err := restoredrill.RunNow(foundApp.ID); err != nil {
+		InternalErrorJSON(w, r, "failed to queue restore drill", err)
+		return
+	}
+
+	w.WriteHeader(http.StatusAccepted)
+}
@@ -0,0 +1,138 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	handlertypes "github.com/replicatedhq/kots/pkg/api/handlers/types"
+	"github.com/replicatedhq/kots/pkg/archiveintegrity"
+	"github.com/replicatedhq/kots/pkg/janitor"
+	scheduledjobtypes "github.com/replicatedhq/kots/pkg/scheduledjob/types"
+	"github.com/replicatedhq/kots/pkg/store"
+	"github.com/replicatedhq/kots/pkg/updatechecker"
+)
+
+// ListScheduledJobs returns the update checks, snapshots and janitor runs that kotsadm schedules, with their last run
+// from the job history and their next run
+func (h *Handler) ListScheduledJobs(w http.ResponseWriter, r *http.Request) {
+	latestRuns, err := store.GetStore().ListLatestScheduledJobRuns()
+	if err != nil {
+		InternalErrorJSON(w, r, "failed to list scheduled job runs", err)
+		return
+	}
+
+	now := time.Now()
+	lastRun := func(job scheduledjobtypes.Job, appID string) *handlertypes.ScheduledJobRun {
+		for _, run := range latestRuns {
+			if run.Job == job && run.AppID == appID {
+				return toScheduledJobRun(run, now)
+			}
+		}
+		return nil
+	}
+
+	apps, err := store.GetStore().ListInstalledApps()
+	if err != nil {
+		InternalErrorJSON(w, r, "failed to list installed apps", err)
+		return
+	}
+
+	response := handlertypes.ListScheduledJobsResponse{
+		Jobs: []handlertypes.ScheduledJob{},
+	}
+
+	for _, a := range apps {
+		updateCheck := handlertypes.ScheduledJob{
+			Job:      scheduledjobtypes.JobUpdateCheck,
+			AppSlug:  a.Slug,
+			Schedule: a.UpdateCheckerSpec,
+			LastRun:  lastRun(scheduledjobtypes.JobUpdateCheck, a.ID),
+		}
+		if next := updatechecker.NextRun(a.ID); next != nil {
+			updateCheck.NextRunAt = next.Format(time.RFC3339)
+		}
+		response.Jobs = append(response.Jobs, updateCheck)
+
+		snapshot := handlertypes.ScheduledJob{
+			Job:      scheduledjobtypes.JobSnapshot,
+			AppSlug:  a.Slug,
+			Schedule: a.SnapshotSchedule,
+			LastRun:  lastRun(scheduledjobtypes.JobSnapshot, a.ID),
+		}
+		if a.SnapshotSchedule != "" {
+			pending, err := store.GetStore().ListPendingScheduledSnapshots(a.ID)
+			if err != nil {
+				InternalErrorJSON(w, r, "failed to list pending scheduled snapshots", err)
+				return
+			}
+			if len(pending) > 0 {
+				snapshot.NextRunAt = pending[0].ScheduledTimestamp.Format(time.RFC3339)
+			}
+		}
+		if snapshot.Schedule != "" || snapshot.LastRun != nil {
+			response.Jobs = append(response.Jobs, snapshot)
+		}
+	}
+
+	clusters, err := store.GetStore().ListClusters()
+	if err != nil {
+		InternalErrorJSON(w, r, "failed to list clusters", err)
+		return
+	}
+
+	for _, c := range clusters {
+		instanceSnapshot := handlertypes.ScheduledJob{
+			Job:      scheduledjobtypes.JobInstanceSnapshot,
+			Schedule: c.SnapshotSchedule,
+			LastRun:  lastRun(scheduledjobtypes.JobInstanceSnapshot, ""),
+		}
+		if c.SnapshotSchedule != "" {
+			pending, err := store.GetStore().ListPendingScheduledInstanceSnapshots(c.ClusterID)
+			if err != nil {
+				InternalErrorJSON(w, r, "failed to list pending scheduled instance snapshots", err)
+				return
+			}
+			if len(pending) > 0 {
+				instanceSnapshot.NextRunAt = pending[0].ScheduledTimestamp.Format(time.RFC3339)
+			}
+		}
+		if instanceSnapshot.Schedule != "" || instanceSnapshot.LastRun != nil {
+			response.Jobs = append(response.Jobs, instanceSnapshot)
+		}
+	}
+
+	janitorJob := handlertypes.ScheduledJob{
+		Job:      scheduledjobtypes.JobJanitor,
+		Schedule: "@hourly",
+		LastRun:  lastRun(scheduledjobtypes.JobJanitor, ""),
+	}
+	if next := janitor.NextRun(); next != nil {
+		janitorJob.NextRunAt = next.Format(time.RFC3339)
+	}
+	response.Jobs = append(response.Jobs, janitorJob)
+
+	archiveScrubJob := handlertypes.ScheduledJob{
+		Job:      scheduledjobtypes.JobArchiveScrub,
+		Schedule: "@every 6h",
+		LastRun:  lastRun(scheduledjobtypes.JobArchiveScrub, ""),
+	}
+	if next := archiveintegrity.NextRun(); next != nil {
+		archiveScrubJob.NextRunAt = next.Format(time.RFC3339)
+	}
+	response.Jobs = append(response.Jobs, archiveScrubJob)
+
+	JSON(w, http.StatusOK, response)
+}
+
+func toScheduledJobRun(run scheduledjobtypes.Run, now time.Time) *handlertypes.ScheduledJobRun {
+	r := &handlertypes.ScheduledJobRun{
+		Status:          run.Status,
+		Message:         run.Message,
+		StartedAt:       run.StartedAt.Format(time.RFC3339),
+		DurationSeconds: run.Duration(now).Seconds(),
+	}
+	if run.FinishedAt != nil {
+		r.FinishedAt = run.FinishedAt.Format(time.RFC3339)
+	}
+	return r
+}
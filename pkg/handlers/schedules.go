@@ -0,0 +1,159 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/pkg/errors"
+	"github.com/replicatedhq/kots/pkg/cronschedule"
+	"github.com/replicatedhq/kots/pkg/logger"
+	restoredrilltypes "github.com/replicatedhq/kots/pkg/restoredrill/types"
+	"github.com/replicatedhq/kots/pkg/store"
+	"github.com/replicatedhq/kots/pkg/updatechecker"
+)
+
+type GetAppSchedulesResponse struct {
+	// Timezone is the timezone of the schedules that do not set one with CRON_TZ, UTC if empty
+	Timezone     string       `json:"timezone"`
+	UpdateCheck  ScheduleInfo `json:"updateCheck"`
+	Snapshot     ScheduleInfo `json:"snapshot"`
+	RestoreDrill ScheduleInfo `json:"restoreDrill"`
+}
+
+type ScheduleInfo struct {
+	Schedule string `json:"schedule"`
+	// NextRunAt is in RFC3339 format with the offset of the timezone, it's empty if nothing is scheduled
+	NextRunAt string `json:"nextRunAt,omitempty"`
+}
+
+type SetScheduleTimezoneRequest struct {
+	Timezone string `json:"timezone"`
+}
+
+// GetAppSchedules returns the update check, snapshot and restore drill schedules of the app and their next runs in
+// the timezone of the app
+func (h *Handler) GetAppSchedules(w http.ResponseWriter, r *http.Request) {
+	foundApp, err := store.GetStore().GetAppFromSlug(mux.Vars(r)["appSlug"])
+	if err != nil {
+		NotFoundJSON(w, r, "failed to get app from slug", err)
+		return
+	}
+
+	location, err := cronschedule.LoadLocation(foundApp.ScheduleTimezone)
+	if err != nil {
+		InternalErrorJSON(w, r, "failed to load timezone", err)
+		return
+	}
+
+	response := GetAppSchedulesResponse{
+		Timezone: foundApp.ScheduleTimezone,
+		UpdateCheck: ScheduleInfo{
+			Schedule: foundApp.UpdateCheckerSpec,
+		},
+		Snapshot: ScheduleInfo{
+			Schedule: foundApp.SnapshotSchedule,
+		},
+		RestoreDrill: ScheduleInfo{
+			Schedule: foundApp.RestoreDrillSchedule,
+		},
+	}
+
+	if next := updatechecker.NextRun(foundApp.ID); next != nil {
+		response.UpdateCheck.NextRunAt = next.In(location).Format(time.RFC3339)
+	}
+
+	if foundApp.SnapshotSchedule != "" {
+		pending, err := store.GetStore().ListPendingScheduledSnapshots(foundApp.ID)
+		if err != nil {
+			InternalErrorJSON(w, r, "failed to list pending scheduled snapshots", err)
+			return
+		}
+		if len(pending) > 0 {
+			response.Snapshot.NextRunAt = pending[0].ScheduledTimestamp.In(location).Format(time.RFC3339)
+		} else {
+			response.Snapshot.NextRunAt = nextRunAt(foundApp.SnapshotSchedule, foundApp.ScheduleTimezone, location)
+		}
+	}
+
+	if foundApp.RestoreDrillSchedule != "" {
+		drills, err := store.GetStore().ListRestoreDrills(foundApp.ID)
+		if err != nil {
+			InternalErrorJSON(w, r, "failed to list restore drills", err)
+			return
+		}
+		for _, drill := range drills {
+			if drill.Status == restoredrilltypes.StatusScheduled {
+				response.RestoreDrill.NextRunAt = drill.ScheduledAt.In(location).Format(time.RFC3339)
+				break
+			}
+		}
+		if response.RestoreDrill.NextRunAt == "" {
+			response.RestoreDrill.NextRunAt = nextRunAt(foundApp.RestoreDrillSchedule, foundApp.ScheduleTimezone, location)
+		}
+	}
+
+	JSON(w, http.StatusOK, response)
+}
+
+// SetScheduleTimezone sets the timezone of the schedules of the app. The update checker is configured again, and
+// the next snapshot and restore drill are queued again by their schedulers.
+func (h *Handler) SetScheduleTimezone(w http.ResponseWriter, r *http.Request) {
+	request := SetScheduleTimezoneRequest{}
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		BadRequestJSON(w, r, "failed to decode request body", err)
+		return
+	}
+
+	if _, err := cronschedule.LoadLocation(request.Timezone); err != nil {
+		BadRequestJSON(w, r, fmt.Sprintf("invalid timezone: %v", err), err)
+		return
+	}
+
+	foundApp, err := store.GetStore().GetAppFromSlug(mux.Vars(r)["appSlug"])
+	if err != nil {
+		NotFoundJSON(w, r, "failed to get app from slug", err)
+		return
+	}
+
+	if request.Timezone == foundApp.ScheduleTimezone {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	if err := store.GetStore().SetScheduleTimezone(foundApp.ID, request.Timezone); err != nil {
+		InternalErrorJSON(w, r, "failed to set schedule timezone", err)
+		return
+	}
+
+	if err := updatechecker.Configure(foundApp.ID); err != nil {
+		InternalErrorJSON(w, r, "failed to reconfigure update checker", err)
+		return
+	}
+	if foundApp.SnapshotSchedule != "" {
+		if err := store.GetStore().DeletePendingScheduledSnapshots(foundApp.ID); err != nil {
+			InternalErrorJSON(w, r, "failed to delete scheduled snapshots", err)
+			return
+		}
+	}
+	if foundApp.RestoreDrillSchedule != "" {
+		if err := store.GetStore().DeleteScheduledRestoreDrills(foundApp.ID); err != nil {
+			InternalErrorJSON(w, r, "failed to delete scheduled restore drills", err)
+			return
+		}
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// nextRunAt returns the next time of the schedule, or an empty string if it cannot be parsed
+func nextRunAt(schedule string, timezone string, location *time.Location) string {
+	next, err := cronschedule.Next(schedule, timezone, time.Now())
+	if err != nil {
+		logger.Error(errors.Wrapf(err, "failed to get next run of schedule %q", schedule))
+		return ""
+	}
+	return next.In(location).Format(time.RFC3339)
+}
@@ -6,8 +6,11 @@ import (
 	"net/http"
 	"os"
 	"strings"
+	"time"
 
 	"github.com/pkg/errors"
+	handlertypes "github.com/replicatedhq/kots/pkg/api/handlers/types"
+	"github.com/replicatedhq/kots/pkg/logger"
 	"github.com/replicatedhq/kots/pkg/session"
 	"github.com/replicatedhq/kots/pkg/session/types"
 	"github.com/replicatedhq/kots/pkg/store"
@@ -74,6 +77,31 @@ func requireValidSession(kotsStore store.Store, w http.ResponseWriter, r *http.R
 		return nil, err
 	}
 
+	settings, err := session.GetSettings(kotsStore)
+	if err != nil {
+		InternalErrorJSON(w, r, "failed to get session settings", nil)
+		return nil, err
+	}
+
+	now := time.Now()
+	if err := session.CheckActive(sess, settings, now); err != nil {
+		ErrorJSON(w, r, http.StatusUnauthorized, handlertypes.ErrorCodeSessionExpired, err.Error(), nil)
+		return nil, err
+	}
+
+	// the idle timeout slides with every request, a failure to save the activity should not fail the request
+	if session.NeedsActivityUpdate(sess, now) {
+		if err := kotsStore.UpdateSessionActivity(sess.ID, now); err != nil {
+			logger.Error(errors.Wrap(err, "failed to update session activity"))
+		} else {
+			sess.LastActiveAt = now
+		}
+	}
+
+	if err := requireCSRFToken(w, r, sess); err != nil {
+		return nil, err
+	}
+
 	return sess, nil
 }
 
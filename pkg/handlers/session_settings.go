@@ -0,0 +1,41 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/replicatedhq/kots/pkg/session"
+	sessiontypes "github.com/replicatedhq/kots/pkg/session/types"
+	"github.com/replicatedhq/kots/pkg/store"
+)
+
+func (h *Handler) GetSessionSettings(w http.ResponseWriter, r *http.Request) {
+	settings, err := session.GetSettings(store.GetStore())
+	if err != nil {
+		InternalErrorJSON(w, r, "failed to get session settings", err)
+		return
+	}
+
+	JSON(w, http.StatusOK, settings)
+}
+
+// SetSessionSettings applies to existing sessions too, a shorter ttl or idle timeout can end them
+func (h *Handler) SetSessionSettings(w http.ResponseWriter, r *http.Request) {
+	settings := sessiontypes.SessionSettings{}
+	if err := json.NewDecoder(r.Body).Decode(&settings); err != nil {
+		BadRequestJSON(w, r, "failed to decode request body", err)
+		return
+	}
+
+	if err := session.ValidateSettings(settings); err != nil {
+		BadRequestJSON(w, r, "invalid session settings", err)
+		return
+	}
+
+	if err := session.SetSettings(store.GetStore(), settings); err != nil {
+		InternalErrorJSON(w, r, "failed to set session settings", err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -12,6 +12,7 @@ import (
 
 	"github.com/gorilla/mux"
 	"github.com/pkg/errors"
+	"github.com/replicatedhq/kots/pkg/cronschedule"
 	"github.com/replicatedhq/kots/pkg/k8sutil"
 	"github.com/replicatedhq/kots/pkg/kotsadm"
 	kotsadmtypes "github.com/replicatedhq/kots/pkg/kotsadm/types"
@@ -22,7 +23,6 @@ import (
 	kotssnapshot "github.com/replicatedhq/kots/pkg/snapshot"
 	kotssnapshottypes "github.com/replicatedhq/kots/pkg/snapshot/types"
 	"github.com/replicatedhq/kots/pkg/store"
-	"github.com/robfig/cron"
 	"k8s.io/apimachinery/pkg/util/rand"
 	"k8s.io/client-go/kubernetes"
 )
@@ -500,7 +500,7 @@ func (h *Handler) SaveSnapshotConfig(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	cronSchedule, err := cron.NewParser(cron.Minute | cron.Hour | cron.Dom | cron.Month | cron.Dow | cron.Descriptor).Parse(requestBody.Schedule)
+	cronSchedule, err := cronschedule.Parse(requestBody.Schedule, app.ScheduleTimezone)
 	if err != nil {
 		logger.Error(err)
 		responseBody.Error = fmt.Sprintf("Invalid cron schedule expression: %s", requestBody.Schedule)
@@ -668,7 +668,7 @@ func (h *Handler) SaveInstanceSnapshotConfig(w http.ResponseWriter, r *http.Requ
 		return
 	}
 
-	cronSchedule, err := cron.ParseStandard(requestBody.Schedule)
+	cronSchedule, err := cronschedule.Parse(requestBody.Schedule, "")
 	if err != nil {
 		logger.Error(err)
 		responseBody.Error = fmt.Sprintf("Invalid cron schedule expression: %s", requestBody.Schedule)
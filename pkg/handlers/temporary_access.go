@@ -0,0 +1,161 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/pkg/errors"
+	"github.com/replicatedhq/kots/pkg/logger"
+	"github.com/replicatedhq/kots/pkg/session"
+	"github.com/replicatedhq/kots/pkg/store"
+	"github.com/replicatedhq/kots/pkg/temporaryaccess"
+	temporaryaccesstypes "github.com/replicatedhq/kots/pkg/temporaryaccess/types"
+	"go.uber.org/zap"
+)
+
+type ListTemporaryAccessResponse struct {
+	Grants []temporaryaccesstypes.Grant `json:"grants"`
+}
+
+type CreateTemporaryAccessRequest struct {
+	Description         string `json:"description"`
+	AppSlug             string `json:"appSlug"`
+	AllowSupportBundles bool   `json:"allowSupportBundles"`
+	ExpiresInMinutes    int64  `json:"expiresInMinutes"`
+}
+
+type CreateTemporaryAccessResponse struct {
+	Grant temporaryaccesstypes.Grant `json:"grant"`
+	// Token is only returned when the grant is created
+	Token string `json:"token"`
+	// Path is the path of the access link on the admin console, the token is in the fragment so that it is not
+	// sent to the server or recorded in proxy logs when the link is opened
+	Path string `json:"path"`
+}
+
+type RedeemTemporaryAccessRequest struct {
+	Token string `json:"token"`
+}
+
+func (h *Handler) ListTemporaryAccess(w http.ResponseWriter, r *http.Request) {
+	grants, err := store.GetStore().ListTemporaryAccess()
+	if err != nil {
+		InternalErrorJSON(w, r, "failed to list temporary access", err)
+		return
+	}
+
+	JSON(w, http.StatusOK, ListTemporaryAccessResponse{Grants: grants})
+}
+
+func (h *Handler) CreateTemporaryAccess(w http.ResponseWriter, r *http.Request) {
+	request := CreateTemporaryAccessRequest{}
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		BadRequestJSON(w, r, "failed to decode request body", err)
+		return
+	}
+
+	duration := time.Duration(request.ExpiresInMinutes) * time.Minute
+	if err := temporaryaccess.ValidateDuration(duration); err != nil {
+		BadRequestJSON(w, r, err.Error(), err)
+		return
+	}
+
+	if request.AppSlug != "" {
+		if _, err := store.GetStore().GetAppFromSlug(request.AppSlug); err != nil {
+			NotFoundJSON(w, r, "failed to get app from slug", err)
+			return
+		}
+	}
+
+	grant, token, err := temporaryaccess.Create(temporaryaccess.CreateOptions{
+		Description:         request.Description,
+		AppSlug:             request.AppSlug,
+		AllowSupportBundles: request.AllowSupportBundles,
+		Duration:            duration,
+		CreatedBy:           sessionUserID(r),
+	})
+	if err != nil {
+		InternalErrorJSON(w, r, "failed to create temporary access", err)
+		return
+	}
+
+	JSON(w, http.StatusCreated, CreateTemporaryAccessResponse{
+		Grant: *grant,
+		Token: token,
+		Path:  fmt.Sprintf("/secure-console#access=%s", url.QueryEscape(token)),
+	})
+}
+
+func (h *Handler) RevokeTemporaryAccess(w http.ResponseWriter, r *http.Request) {
+	id := mux.Vars(r)["id"]
+
+	grant, err := store.GetStore().GetTemporaryAccess(id)
+	if err != nil {
+		InternalErrorJSON(w, r, "failed to get temporary access", err)
+		return
+	}
+	if grant == nil {
+		NotFoundJSON(w, r, fmt.Sprintf("temporary access %s not found", id), nil)
+		return
+	}
+
+	if err := temporaryaccess.Revoke(id, sessionUserID(r)); err != nil {
+		InternalErrorJSON(w, r, "failed to revoke temporary access", err)
+		return
+	}
+
+	JSON(w, http.StatusOK, map[string]interface{}{})
+}
+
+// RedeemTemporaryAccess creates a session for the token of an access link, it is the login of the access link
+func (h *Handler) RedeemTemporaryAccess(w http.ResponseWriter, r *http.Request) {
+	loginResponse := LoginResponse{}
+
+	request := RedeemTemporaryAccessRequest{}
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		logger.Error(err)
+		JSON(w, http.StatusBadRequest, loginResponse)
+		return
+	}
+
+	_, createdSession, err := temporaryaccess.Redeem(request.Token)
+	if errors.Cause(err) == temporaryaccess.ErrInvalidToken {
+		loginResponse.Error = "This access link is invalid, has expired or was revoked."
+		JSON(w, http.StatusUnauthorized, loginResponse)
+		return
+	} else if err != nil {
+		logger.Error(err)
+		JSON(w, http.StatusInternalServerError, loginResponse)
+		return
+	}
+
+	signedJWT, err := session.SignJWT(createdSession)
+	if err != nil {
+		logger.Error(err)
+		JSON(w, http.StatusInternalServerError, loginResponse)
+		return
+	}
+
+	loginResponse.Token = fmt.Sprintf("Bearer %s", signedJWT)
+	loginResponse.CSRFToken = session.CSRFToken(createdSession)
+
+	JSON(w, http.StatusOK, loginResponse)
+}
+
+// auditTemporaryAccessRequest logs every request that is made with the session of an access link
+func auditTemporaryAccessRequest(r *http.Request) {
+	sess := session.ContextGetSession(r)
+	if !temporaryaccess.IsTemporaryAccessSession(sess) {
+		return
+	}
+
+	logger.Info("temporary access request",
+		zap.String("audit", "temporary-access"),
+		zap.String("user", sess.UserID),
+		zap.String("method", r.Method),
+		zap.String("path", r.URL.Path))
+}
@@ -17,6 +17,7 @@ import (
 	"github.com/replicatedhq/kots/pkg/store"
 	"github.com/replicatedhq/kots/pkg/supportbundle"
 	"github.com/replicatedhq/kots/pkg/supportbundle/types"
+	"github.com/replicatedhq/kots/pkg/uploadquota"
 	redact2 "github.com/replicatedhq/troubleshoot/pkg/redact"
 )
 
@@ -342,8 +343,27 @@ func (h *Handler) CollectSupportBundle(w http.ResponseWriter, r *http.Request) {
 // UploadSupportBundle route is UNAUTHENTICATED
 // This request comes from the `kubectl support-bundle` command.
 func (h *Handler) UploadSupportBundle(w http.ResponseWriter, r *http.Request) {
+	quota, err := uploadquota.Get()
+	if err != nil {
+		InternalErrorJSON(w, r, "failed to get upload quota", err)
+		return
+	}
+	if !limitUploadBody(w, r, "support bundle", quota.SupportBundleBytes) {
+		return
+	}
+	if r.ContentLength > 0 {
+		if err := uploadquota.CheckDiskSpace("support bundle", os.TempDir(), r.ContentLength, quota.DiskReserveBytes); err != nil {
+			uploadErrorJSON(w, r, err)
+			return
+		}
+	}
+
 	bundleContents, err := ioutil.ReadAll(r.Body)
 	if err != nil {
+		if isBodyTooLarge(err) {
+			uploadErrorJSON(w, r, uploadquota.TooLargeError{Name: "support bundle", Limit: quota.SupportBundleBytes})
+			return
+		}
 		logger.Error(errors.Wrap(err, "failed to read request body"))
 		w.WriteHeader(http.StatusInternalServerError)
 		return
@@ -3,17 +3,21 @@ package handlers
 import (
 	"encoding/json"
 	"errors"
+	"fmt"
 	"net/http"
 
 	"github.com/gorilla/mux"
+	apptypes "github.com/replicatedhq/kots/pkg/app/types"
+	"github.com/replicatedhq/kots/pkg/cronschedule"
 	"github.com/replicatedhq/kots/pkg/logger"
 	"github.com/replicatedhq/kots/pkg/store"
 	"github.com/replicatedhq/kots/pkg/updatechecker"
-	cron "github.com/robfig/cron/v3"
 )
 
 type UpdateCheckerSpecRequest struct {
 	UpdateCheckerSpec string `json:"updateCheckerSpec"`
+	// DeployPolicy is left unchanged if empty
+	DeployPolicy apptypes.DeployPolicy `json:"deployPolicy,omitempty"`
 }
 
 type UpdateCheckerSpecResponse struct {
@@ -49,7 +53,7 @@ func (h *Handler) UpdateCheckerSpec(w http.ResponseWriter, r *http.Request) {
 	// validate cron spec
 	cronSpec := updateCheckerSpecRequest.UpdateCheckerSpec
 	if cronSpec != "@never" && cronSpec != "@default" {
-		_, err := cron.ParseStandard(cronSpec)
+		_, err := cronschedule.Parse(cronSpec, foundApp.ScheduleTimezone)
 		if err != nil {
 			logger.Error(err)
 			updateCheckerSpecResponse.Error = "failed to parse cron spec"
@@ -58,6 +62,14 @@ func (h *Handler) UpdateCheckerSpec(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
+	deployPolicy := updateCheckerSpecRequest.DeployPolicy
+	if deployPolicy != "" && deployPolicy != apptypes.DeployPolicyLatest && deployPolicy != apptypes.DeployPolicySequential {
+		logger.Error(fmt.Errorf("invalid deploy policy %q", deployPolicy))
+		updateCheckerSpecResponse.Error = "invalid deploy policy"
+		JSON(w, 400, updateCheckerSpecResponse)
+		return
+	}
+
 	if err := store.GetStore().SetUpdateCheckerSpec(foundApp.ID, cronSpec); err != nil {
 		logger.Error(err)
 		updateCheckerSpecResponse.Error = "failed to set update checker spec"
@@ -65,6 +77,15 @@ func (h *Handler) UpdateCheckerSpec(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if deployPolicy != "" {
+		if err := store.GetStore().SetDeployPolicy(foundApp.ID, deployPolicy); err != nil {
+			logger.Error(err)
+			updateCheckerSpecResponse.Error = "failed to set deploy policy"
+			JSON(w, 500, updateCheckerSpecResponse)
+			return
+		}
+	}
+
 	// reconfigure update checker for the app
 	if err := updatechecker.Configure(foundApp.ID); err != nil {
 		logger.Error(err)
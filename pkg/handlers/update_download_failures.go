@@ -0,0 +1,76 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+	"github.com/pkg/errors"
+	"github.com/replicatedhq/kots/pkg/logger"
+	"github.com/replicatedhq/kots/pkg/store"
+	"github.com/replicatedhq/kots/pkg/updatechecker"
+	updatecheckertypes "github.com/replicatedhq/kots/pkg/updatechecker/types"
+	"github.com/replicatedhq/kots/pkg/util"
+)
+
+type GetFailedUpdateDownloadsResponse struct {
+	Failures []updatecheckertypes.UpdateDownloadFailure `json:"failures"`
+}
+
+type RetryFailedUpdateDownloadsResponse struct {
+	AvailableUpdates int64 `json:"availableUpdates"`
+}
+
+func (h *Handler) GetFailedUpdateDownloads(w http.ResponseWriter, r *http.Request) {
+	foundApp, err := store.GetStore().GetAppFromSlug(mux.Vars(r)["appSlug"])
+	if err != nil {
+		logger.Error(err)
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	failures, err := store.GetStore().ListUpdateDownloadFailures(foundApp.ID)
+	if err != nil {
+		logger.Error(errors.Wrap(err, "failed to list update download failures"))
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	JSON(w, http.StatusOK, GetFailedUpdateDownloadsResponse{
+		Failures: failures,
+	})
+}
+
+func (h *Handler) RetryFailedUpdateDownloads(w http.ResponseWriter, r *http.Request) {
+	foundApp, err := store.GetStore().GetAppFromSlug(mux.Vars(r)["appSlug"])
+	if err != nil {
+		logger.Error(err)
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	if foundApp.IsAirgap {
+		logger.Error(errors.New("not an online app"))
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte("Failed downloads cannot be retried for an airgap install, upload the airgap bundle again instead"))
+		return
+	}
+
+	skipPreflights, _ := strconv.ParseBool(r.URL.Query().Get("skipPreflights"))
+
+	availableUpdates, err := updatechecker.RetryFailedDownloads(foundApp.ID, skipPreflights)
+	if err != nil {
+		logger.Error(err)
+		w.WriteHeader(http.StatusInternalServerError)
+
+		cause := errors.Cause(err)
+		if _, ok := cause.(util.ActionableError); ok {
+			w.Write([]byte(cause.Error()))
+		}
+		return
+	}
+
+	JSON(w, http.StatusOK, RetryFailedUpdateDownloadsResponse{
+		AvailableUpdates: availableUpdates,
+	})
+}
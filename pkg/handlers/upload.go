@@ -9,7 +9,7 @@ import (
 	"path/filepath"
 	"strings"
 
-	"github.com/pkg/errors"
+	"github.com/replicatedhq/kots/pkg/deployapproval"
 	"github.com/replicatedhq/kots/pkg/kotsutil"
 	"github.com/replicatedhq/kots/pkg/logger"
 	"github.com/replicatedhq/kots/pkg/preflight"
@@ -38,40 +38,39 @@ func (h *Handler) UploadExistingApp(w http.ResponseWriter, r *http.Request) {
 		logger.Error(err)
 		return
 	}
+	if err := requireNotReadOnlyMode(w, r); err != nil {
+		logger.Error(err)
+		return
+	}
 
 	metadata := r.FormValue("metadata")
 	uploadExistingAppRequest := UploadExistingAppRequest{}
 	if err := json.NewDecoder(strings.NewReader(metadata)).Decode(&uploadExistingAppRequest); err != nil {
-		logger.Error(err)
-		w.WriteHeader(400)
+		BadRequestJSON(w, r, "failed to decode request body", err)
 		return
 	}
 
 	archive, _, err := r.FormFile("file")
 	if err != nil {
-		logger.Error(err)
-		w.WriteHeader(500)
+		InternalErrorJSON(w, r, "failed to read uploaded file", err)
 		return
 	}
 
 	tmpFile, err := ioutil.TempFile("", "kotsadm")
 	if err != nil {
-		logger.Error(err)
-		w.WriteHeader(500)
+		InternalErrorJSON(w, r, "failed to create temp file", err)
 		return
 	}
 	_, err = io.Copy(tmpFile, archive)
 	if err != nil {
-		logger.Error(err)
-		w.WriteHeader(500)
+		InternalErrorJSON(w, r, "failed to copy archive", err)
 		return
 	}
 	defer os.RemoveAll(tmpFile.Name())
 
 	archiveDir, err := version.ExtractArchiveToTempDirectory(tmpFile.Name())
 	if err != nil {
-		logger.Error(err)
-		w.WriteHeader(500)
+		InternalErrorJSON(w, r, "failed to extract archive", err)
 		return
 	}
 	defer os.RemoveAll(archiveDir)
@@ -79,83 +78,83 @@ func (h *Handler) UploadExistingApp(w http.ResponseWriter, r *http.Request) {
 	// encrypt any plain text values
 	kotsKinds, err := kotsutil.LoadKotsKindsFromPath(archiveDir)
 	if err != nil {
-		logger.Error(err)
-		w.WriteHeader(500)
+		InternalErrorJSON(w, r, "failed to load kots kinds from path", err)
 		return
 	}
 
 	if kotsKinds.ConfigValues != nil {
 		if err := kotsKinds.EncryptConfigValues(); err != nil {
-			logger.Error(err)
-			w.WriteHeader(500)
+			InternalErrorJSON(w, r, "failed to encrypt config values", err)
 			return
 		}
 		updated, err := kotsKinds.Marshal("kots.io", "v1beta1", "ConfigValues")
 		if err != nil {
-			logger.Error(err)
-			w.WriteHeader(500)
+			InternalErrorJSON(w, r, "failed to marshal config values", err)
 			return
 		}
 
 		if err := ioutil.WriteFile(filepath.Join(archiveDir, "upstream", "userdata", "config.yaml"), []byte(updated), 0644); err != nil {
-			logger.Error(err)
-			w.WriteHeader(500)
+			InternalErrorJSON(w, r, "failed to write file", err)
 			return
 		}
 	}
 
 	a, err := store.GetStore().GetAppFromSlug(uploadExistingAppRequest.Slug)
 	if err != nil {
-		logger.Error(err)
-		w.WriteHeader(500)
+		InternalErrorJSON(w, r, "failed to get app from slug", err)
 		return
 	}
 
+	unlock, ok := lockAppJSON(w, r, a.ID)
+	if !ok {
+		return
+	}
+	defer unlock()
+
 	registrySettings, err := store.GetStore().GetRegistryDetailsForApp(a.ID)
 	if err != nil {
-		logger.Error(err)
-		w.WriteHeader(500)
+		InternalErrorJSON(w, r, "failed to get registry details for app", err)
 		return
 	}
 	app, err := store.GetStore().GetApp(a.ID)
 	if err != nil {
-		logger.Error(err)
-		w.WriteHeader(500)
+		InternalErrorJSON(w, r, "failed to get app", err)
 		return
 	}
 	downstreams, err := store.GetStore().ListDownstreamsForApp(a.ID)
 	if err != nil {
-		logger.Error(err)
-		w.WriteHeader(500)
+		InternalErrorJSON(w, r, "failed to list downstreams for app", err)
 		return
 	}
 
 	err = render.RenderDir(archiveDir, app, downstreams, registrySettings)
 	if err != nil {
-		logger.Error(err)
-		w.WriteHeader(500)
+		InternalErrorJSON(w, r, "failed to render dir", err)
 		return
 	}
 
-	newSequence, err := store.GetStore().CreateAppVersion(a.ID, &a.CurrentSequence, archiveDir, "KOTS Upload", false, &version.DownstreamGitOps{})
+	// the app is loaded again after the lock is taken so that the current sequence is not stale
+	newSequence, err := store.GetStore().CreateAppVersion(a.ID, &app.CurrentSequence, archiveDir, "KOTS Upload", false, &version.DownstreamGitOps{})
 	if err != nil {
-		logger.Error(err)
-		w.WriteHeader(500)
+		InternalErrorJSON(w, r, "failed to create app version", err)
 		return
 	}
 
 	if !uploadExistingAppRequest.SkipPreflights {
 		if err := preflight.Run(a.ID, a.Slug, newSequence, a.IsAirgap, archiveDir); err != nil {
-			logger.Error(err)
-			w.WriteHeader(500)
+			InternalErrorJSON(w, r, "failed to run preflights", err)
 			return
 		}
 	}
 
 	if uploadExistingAppRequest.Deploy {
-		if err := version.DeployVersion(a.ID, newSequence); err != nil {
-			logger.Error(errors.Wrap(err, "failed to deploy latest version"))
-			w.WriteHeader(500)
+		_, err := deployapproval.DeployOrRequest(a, newSequence, deployapproval.RequestOptions{
+			RequestedBy:      sessionUserID(r),
+			IsSkipPreflights: uploadExistingAppRequest.SkipPreflights,
+			IsCLI:            true,
+		})
+		if err != nil {
+			deployFailedJSON(w, r, "failed to deploy latest version", err)
 			return
 		}
 	}
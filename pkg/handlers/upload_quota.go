@@ -0,0 +1,74 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/pkg/errors"
+	handlertypes "github.com/replicatedhq/kots/pkg/api/handlers/types"
+	"github.com/replicatedhq/kots/pkg/store"
+	"github.com/replicatedhq/kots/pkg/uploadquota"
+	uploadquotatypes "github.com/replicatedhq/kots/pkg/uploadquota/types"
+)
+
+func (h *Handler) GetUploadQuota(w http.ResponseWriter, r *http.Request) {
+	quota, err := uploadquota.Get()
+	if err != nil {
+		InternalErrorJSON(w, r, "failed to get upload quota", err)
+		return
+	}
+
+	JSON(w, http.StatusOK, quota)
+}
+
+func (h *Handler) SetUploadQuota(w http.ResponseWriter, r *http.Request) {
+	quota := uploadquotatypes.UploadQuota{}
+	if err := json.NewDecoder(r.Body).Decode(&quota); err != nil {
+		BadRequestJSON(w, r, "failed to decode request body", err)
+		return
+	}
+
+	if err := uploadquota.Validate(quota); err != nil {
+		BadRequestJSON(w, r, "invalid upload quota", err)
+		return
+	}
+
+	if err := store.GetStore().SetUploadQuota(quota); err != nil {
+		InternalErrorJSON(w, r, "failed to set upload quota", err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// limitUploadBody rejects the request if its content length is over the limit and makes reading past the limit
+// fail. False is returned if the response has been written.
+func limitUploadBody(w http.ResponseWriter, r *http.Request, name string, limit int64) bool {
+	if err := uploadquota.CheckSize(name, r.ContentLength, limit); err != nil {
+		uploadErrorJSON(w, r, err)
+		return false
+	}
+	if limit > 0 {
+		r.Body = http.MaxBytesReader(w, r.Body, limit)
+	}
+	return true
+}
+
+// isBodyTooLarge returns true if err is from reading past the limit of limitUploadBody
+func isBodyTooLarge(err error) bool {
+	return err != nil && strings.Contains(errors.Cause(err).Error(), "http: request body too large")
+}
+
+// uploadErrorJSON writes 413 for uploads over the quota, 507 for uploads that would fill the disk, and 500 for
+// anything else
+func uploadErrorJSON(w http.ResponseWriter, r *http.Request, err error) {
+	switch cause := errors.Cause(err).(type) {
+	case uploadquota.TooLargeError:
+		ErrorJSON(w, r, http.StatusRequestEntityTooLarge, handlertypes.ErrorCodeUploadTooLarge, cause.Error(), nil)
+	case uploadquota.InsufficientDiskSpaceError:
+		ErrorJSON(w, r, http.StatusInsufficientStorage, handlertypes.ErrorCodeInsufficientDiskSpace, cause.Error(), nil)
+	default:
+		InternalErrorJSON(w, r, "failed to check upload", err)
+	}
+}
@@ -0,0 +1,94 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gorilla/mux"
+	"github.com/pkg/errors"
+	handlertypes "github.com/replicatedhq/kots/pkg/api/handlers/types"
+	"github.com/replicatedhq/kots/pkg/logger"
+	"github.com/replicatedhq/kots/pkg/store"
+	"go.uber.org/zap"
+)
+
+const (
+	maxVersionNotesLength = 4096
+	maxVersionLabelLength = 64
+	maxVersionLabels      = 20
+)
+
+// SetAppVersionNotes replaces the notes and labels that operators attached to a version, e.g. the change ticket
+// that the version was deployed for. Every change is audit logged.
+func (h *Handler) SetAppVersionNotes(w http.ResponseWriter, r *http.Request) {
+	request := handlertypes.SetAppVersionNotesRequest{}
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		BadRequestJSON(w, r, "failed to decode request body", err)
+		return
+	}
+
+	sequence, err := strconv.ParseInt(mux.Vars(r)["sequence"], 10, 64)
+	if err != nil {
+		BadRequestJSON(w, r, "failed to parse sequence", err)
+		return
+	}
+
+	labels, err := validateVersionNotes(request.Notes, request.Labels)
+	if err != nil {
+		BadRequestJSON(w, r, err.Error(), nil)
+		return
+	}
+
+	foundApp, err := store.GetStore().GetAppFromSlug(mux.Vars(r)["appSlug"])
+	if err != nil {
+		NotFoundJSON(w, r, "failed to get app from slug", err)
+		return
+	}
+
+	if err := store.GetStore().SetAppVersionNotes(foundApp.ID, sequence, request.Notes, labels); err != nil {
+		if store.GetStore().IsNotFound(err) {
+			NotFoundJSON(w, r, "version not found", err)
+			return
+		}
+		InternalErrorJSON(w, r, "failed to set version notes", err)
+		return
+	}
+
+	logger.Info("version notes set",
+		zap.String("audit", "version-notes"),
+		zap.String("appID", foundApp.ID),
+		zap.Int64("sequence", sequence),
+		zap.String("notes", request.Notes),
+		zap.Strings("labels", labels),
+		zap.String("user", sessionUserID(r)))
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// validateVersionNotes returns the labels trimmed and without duplicates
+func validateVersionNotes(notes string, labels []string) ([]string, error) {
+	if len(notes) > maxVersionNotesLength {
+		return nil, errors.Errorf("notes must be at most %d characters", maxVersionNotesLength)
+	}
+
+	validLabels := []string{}
+	seen := map[string]bool{}
+	for _, label := range labels {
+		label = strings.TrimSpace(label)
+		if label == "" || seen[label] {
+			continue
+		}
+		if len(label) > maxVersionLabelLength {
+			return nil, errors.Errorf("label %q must be at most %d characters", label, maxVersionLabelLength)
+		}
+		seen[label] = true
+		validLabels = append(validLabels, label)
+	}
+	if len(validLabels) > maxVersionLabels {
+		return nil, errors.Errorf("a version can have at most %d labels", maxVersionLabels)
+	}
+
+	return validLabels, nil
+}
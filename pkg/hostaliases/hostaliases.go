@@ -0,0 +1,92 @@
+package hostaliases
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+)
+
+var (
+	loaded atomic.Value // map[string]string
+
+	dialer = &net.Dialer{
+		Timeout:   30 * time.Second,
+		KeepAlive: 30 * time.Second,
+	}
+)
+
+// Parse parses host aliases in the form ip=hostname[,hostname...]
+func Parse(values []string) ([]corev1.HostAlias, error) {
+	aliases := []corev1.HostAlias{}
+	for _, value := range values {
+		parts := strings.SplitN(value, "=", 2)
+		if len(parts) != 2 {
+			return nil, errors.Errorf("host alias %q must be in the form ip=hostname[,hostname...]", value)
+		}
+
+		ip := strings.TrimSpace(parts[0])
+		if net.ParseIP(ip) == nil {
+			return nil, errors.Errorf("host alias %q has an invalid ip address", value)
+		}
+
+		hostnames := []string{}
+		for _, hostname := range strings.Split(parts[1], ",") {
+			hostname = strings.TrimSpace(hostname)
+			if hostname == "" {
+				continue
+			}
+			hostnames = append(hostnames, hostname)
+		}
+		if len(hostnames) == 0 {
+			return nil, errors.Errorf("host alias %q has no hostnames", value)
+		}
+
+		aliases = append(aliases, corev1.HostAlias{
+			IP:        ip,
+			Hostnames: hostnames,
+		})
+	}
+	return aliases, nil
+}
+
+// Load makes connections to the aliased hostnames that are dialed with DialContext, including all requests made
+// with the default http transport, go to the ip of the alias. Pods get the same aliases in /etc/hosts, so this
+// is only needed by processes that run outside of the cluster, such as the cli.
+func Load(aliases []corev1.HostAlias) {
+	addresses := map[string]string{}
+	for _, alias := range aliases {
+		for _, hostname := range alias.Hostnames {
+			addresses[strings.ToLower(hostname)] = alias.IP
+		}
+	}
+	loaded.Store(addresses)
+
+	// the ca bundle replaces the default transport with one that already dials with the host aliases applied
+	if transport, ok := http.DefaultTransport.(*http.Transport); ok {
+		transport.DialContext = DialContext
+	}
+}
+
+// Resolve returns the address to dial for the address, replacing the host with the ip of its alias
+func Resolve(address string) string {
+	host, port, err := net.SplitHostPort(address)
+	if err != nil {
+		return address
+	}
+	addresses, _ := loaded.Load().(map[string]string)
+	if ip, ok := addresses[strings.ToLower(host)]; ok {
+		return net.JoinHostPort(ip, port)
+	}
+	return address
+}
+
+// DialContext dials the address with the loaded host aliases applied
+func DialContext(ctx context.Context, network, address string) (net.Conn, error) {
+	return dialer.DialContext(ctx, network, Resolve(address))
+}
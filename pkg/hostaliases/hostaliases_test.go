@@ -0,0 +1,77 @@
+package hostaliases
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func Test_Parse(t *testing.T) {
+	tests := []struct {
+		name    string
+		values  []string
+		want    []corev1.HostAlias
+		wantErr bool
+	}{
+		{
+			name:   "none",
+			values: nil,
+			want:   []corev1.HostAlias{},
+		},
+		{
+			name:   "multiple hostnames",
+			values: []string{"10.0.0.5=replicated.app, registry.replicated.com"},
+			want: []corev1.HostAlias{
+				{IP: "10.0.0.5", Hostnames: []string{"replicated.app", "registry.replicated.com"}},
+			},
+		},
+		{
+			name:   "multiple aliases",
+			values: []string{"10.0.0.5=replicated.app", "fd00::1=git.example.com"},
+			want: []corev1.HostAlias{
+				{IP: "10.0.0.5", Hostnames: []string{"replicated.app"}},
+				{IP: "fd00::1", Hostnames: []string{"git.example.com"}},
+			},
+		},
+		{
+			name:    "missing ip",
+			values:  []string{"replicated.app"},
+			wantErr: true,
+		},
+		{
+			name:    "invalid ip",
+			values:  []string{"replicated.app=10.0.0.5"},
+			wantErr: true,
+		},
+		{
+			name:    "no hostnames",
+			values:  []string{"10.0.0.5=,"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Parse(tt.values)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_Resolve(t *testing.T) {
+	Load([]corev1.HostAlias{
+		{IP: "10.0.0.5", Hostnames: []string{"replicated.app"}},
+		{IP: "fd00::1", Hostnames: []string{"Git.Example.com"}},
+	})
+	defer Load(nil)
+
+	require.Equal(t, "10.0.0.5:443", Resolve("replicated.app:443"))
+	require.Equal(t, "[fd00::1]:22", Resolve("git.example.com:22"))
+	require.Equal(t, "proxy.replicated.com:443", Resolve("proxy.replicated.com:443"))
+	require.Equal(t, "replicated.app", Resolve("replicated.app"))
+}
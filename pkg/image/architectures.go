@@ -0,0 +1,85 @@
+package image
+
+import (
+	"context"
+
+	"github.com/containers/image/v5/copy"
+	"github.com/containers/image/v5/manifest"
+	"github.com/containers/image/v5/types"
+	"github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/pkg/errors"
+)
+
+// ArchitectureCopyOptions sets the image list selection on the copy options so that only the instances of a
+// multi-arch source image that match the architectures are copied. All instances are copied if architectures is
+// empty or if the image is referenced by digest, because copying a subset would change the digest of the index.
+func ArchitectureCopyOptions(ctx context.Context, srcRef types.ImageReference, architectures []string, pinnedDigest bool, options *copy.Options) error {
+	options.ImageListSelection = copy.CopyAllImages
+	if len(architectures) == 0 || pinnedDigest {
+		return nil
+	}
+
+	src, err := srcRef.NewImageSource(ctx, options.SourceCtx)
+	if err != nil {
+		return errors.Wrap(err, "failed to open source image")
+	}
+	defer src.Close()
+
+	b, mimeType, err := src.GetManifest(ctx, nil)
+	if err != nil {
+		return errors.Wrap(err, "failed to get source manifest")
+	}
+	if !manifest.MIMETypeIsMultiImage(mimeType) {
+		return nil
+	}
+
+	instances, err := instancesForArchitectures(b, mimeType, architectures)
+	if err != nil {
+		return errors.Wrap(err, "failed to select image instances")
+	}
+	if len(instances) == 0 {
+		return errors.Errorf("image does not contain any of the architectures %v", architectures)
+	}
+
+	options.ImageListSelection = copy.CopySpecificImages
+	options.Instances = instances
+	return nil
+}
+
+// instancesForArchitectures returns the digests of the manifests in an image index or manifest list
+// whose platform matches one of the architectures
+func instancesForArchitectures(b []byte, mimeType string, architectures []string) ([]digest.Digest, error) {
+	wanted := map[string]bool{}
+	for _, arch := range architectures {
+		wanted[arch] = true
+	}
+
+	instances := []digest.Digest{}
+	switch mimeType {
+	case ocispec.MediaTypeImageIndex:
+		index, err := manifest.OCI1IndexFromManifest(b)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to parse oci index")
+		}
+		for _, m := range index.Manifests {
+			if m.Platform != nil && wanted[m.Platform.Architecture] {
+				instances = append(instances, m.Digest)
+			}
+		}
+	case manifest.DockerV2ListMediaType:
+		list, err := manifest.Schema2ListFromManifest(b)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to parse manifest list")
+		}
+		for _, m := range list.Manifests {
+			if wanted[m.Platform.Architecture] {
+				instances = append(instances, m.Digest)
+			}
+		}
+	default:
+		return nil, errors.Errorf("unsupported manifest list type %s", mimeType)
+	}
+
+	return instances, nil
+}
@@ -0,0 +1,82 @@
+package image
+
+import (
+	"testing"
+
+	"github.com/containers/image/v5/manifest"
+	"github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_instancesForArchitectures(t *testing.T) {
+	amd64 := digest.FromString("amd64")
+	arm64 := digest.FromString("arm64")
+	ppc64le := digest.FromString("ppc64le")
+
+	ociIndex := `{
+  "schemaVersion": 2,
+  "mediaType": "application/vnd.oci.image.index.v1+json",
+  "manifests": [
+    {"mediaType": "application/vnd.oci.image.manifest.v1+json", "size": 1, "digest": "` + amd64.String() + `", "platform": {"architecture": "amd64", "os": "linux"}},
+    {"mediaType": "application/vnd.oci.image.manifest.v1+json", "size": 1, "digest": "` + arm64.String() + `", "platform": {"architecture": "arm64", "os": "linux"}},
+    {"mediaType": "application/vnd.oci.image.manifest.v1+json", "size": 1, "digest": "` + ppc64le.String() + `", "platform": {"architecture": "ppc64le", "os": "linux"}}
+  ]
+}`
+
+	dockerList := `{
+  "schemaVersion": 2,
+  "mediaType": "application/vnd.docker.distribution.manifest.list.v2+json",
+  "manifests": [
+    {"mediaType": "application/vnd.docker.distribution.manifest.v2+json", "size": 1, "digest": "` + amd64.String() + `", "platform": {"architecture": "amd64", "os": "linux"}},
+    {"mediaType": "application/vnd.docker.distribution.manifest.v2+json", "size": 1, "digest": "` + arm64.String() + `", "platform": {"architecture": "arm64", "os": "linux"}}
+  ]
+}`
+
+	tests := []struct {
+		name          string
+		manifest      string
+		mimeType      string
+		architectures []string
+		want          []digest.Digest
+	}{
+		{
+			name:          "oci index single architecture",
+			manifest:      ociIndex,
+			mimeType:      ocispec.MediaTypeImageIndex,
+			architectures: []string{"arm64"},
+			want:          []digest.Digest{arm64},
+		},
+		{
+			name:          "oci index multiple architectures",
+			manifest:      ociIndex,
+			mimeType:      ocispec.MediaTypeImageIndex,
+			architectures: []string{"amd64", "ppc64le"},
+			want:          []digest.Digest{amd64, ppc64le},
+		},
+		{
+			name:          "docker manifest list",
+			manifest:      dockerList,
+			mimeType:      manifest.DockerV2ListMediaType,
+			architectures: []string{"amd64"},
+			want:          []digest.Digest{amd64},
+		},
+		{
+			name:          "no matching architecture",
+			manifest:      dockerList,
+			mimeType:      manifest.DockerV2ListMediaType,
+			architectures: []string{"s390x"},
+			want:          []digest.Digest{},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			req := require.New(t)
+
+			instances, err := instancesForArchitectures([]byte(test.manifest), test.mimeType, test.architectures)
+			req.NoError(err)
+			req.Equal(test.want, instances)
+		})
+	}
+}
@@ -11,6 +11,7 @@ import (
 	"path"
 	"path/filepath"
 	"runtime"
+	"sort"
 	"strings"
 	"time"
 
@@ -23,9 +24,12 @@ import (
 	"github.com/docker/distribution/reference"
 	"github.com/docker/distribution/registry/api/errcode"
 	"github.com/pkg/errors"
+	"github.com/replicatedhq/kots/pkg/cabundle"
 	"github.com/replicatedhq/kots/pkg/docker/registry"
 	"github.com/replicatedhq/kots/pkg/k8sdoc"
 	"github.com/replicatedhq/kots/pkg/logger"
+	"github.com/replicatedhq/kots/pkg/tracing"
+	"go.opentelemetry.io/otel/attribute"
 	kustomizeimage "sigs.k8s.io/kustomize/api/types"
 )
 
@@ -49,7 +53,20 @@ type ImageInfo struct {
 	IsPrivate bool
 }
 
-func ProcessImages(srcRegistry, destRegistry registry.RegistryOptions, appSlug string, log *logger.CLILogger, reportWriter io.Writer, upstreamDir string, additionalImages []string, copyImages, allImagesPrivate bool, checkedImages map[string]ImageInfo) ([]kustomizeimage.Image, error) {
+func ProcessImages(ctx context.Context, srcRegistry, destRegistry registry.RegistryOptions, appSlug string, log *logger.CLILogger, reportWriter io.Writer, upstreamDir string, additionalImages []string, copyImages, allImagesPrivate bool, checkedImages map[string]ImageInfo) ([]kustomizeimage.Image, error) {
+	_, span := tracing.Start(ctx, "image.ProcessImages",
+		attribute.String("app.slug", appSlug),
+		attribute.String("registry.endpoint", destRegistry.Endpoint),
+		attribute.Bool("images.copy", copyImages))
+	newImages, err := processImages(srcRegistry, destRegistry, appSlug, log, reportWriter, upstreamDir, additionalImages, copyImages, allImagesPrivate, checkedImages)
+	if err == nil {
+		span.SetAttributes(attribute.Int("images.count", len(newImages)))
+	}
+	tracing.End(span, err)
+	return newImages, err
+}
+
+func processImages(srcRegistry, destRegistry registry.RegistryOptions, appSlug string, log *logger.CLILogger, reportWriter io.Writer, upstreamDir string, additionalImages []string, copyImages, allImagesPrivate bool, checkedImages map[string]ImageInfo) ([]kustomizeimage.Image, error) {
 	newImages := []kustomizeimage.Image{}
 
 	err := filepath.Walk(upstreamDir,
@@ -198,6 +215,45 @@ func GetObjectsWithImages(upstreamDir string) ([]k8sdoc.K8sDoc, error) {
 	return objects, nil
 }
 
+// ListImagesInDir returns the unique images referenced by the objects in the files in the dir
+func ListImagesInDir(dir string) ([]string, error) {
+	uniqueImages := map[string]bool{}
+
+	err := filepath.Walk(dir,
+		func(path string, info os.FileInfo, err error) error {
+			if err != nil {
+				return err
+			}
+
+			if info.IsDir() {
+				return nil
+			}
+
+			contents, err := ioutil.ReadFile(path)
+			if err != nil {
+				return err
+			}
+
+			return listImagesInFile(contents, func(images []string, doc k8sdoc.K8sDoc) error {
+				for _, image := range images {
+					uniqueImages[image] = true
+				}
+				return nil
+			})
+		})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to walk dir")
+	}
+
+	result := make([]string, 0, len(uniqueImages))
+	for image := range uniqueImages {
+		result = append(result, image)
+	}
+	sort.Strings(result)
+
+	return result, nil
+}
+
 func processImagesInFileBetweenRegistries(srcRegistry, destRegistry registry.RegistryOptions, appSlug string, log *logger.CLILogger, reportWriter io.Writer, fileData []byte, copyImages, allImagesPrivate bool, checkedImages map[string]ImageInfo, alreadyPushedImagesFromOtherFiles []kustomizeimage.Image) ([]kustomizeimage.Image, error) {
 	savedImages := make(map[string]bool)
 	newImages := []kustomizeimage.Image{}
@@ -310,6 +366,7 @@ func processOneImage(srcRegistry, destRegistry registry.RegistryOptions, image s
 	if err != nil {
 		return nil, errors.Wrapf(err, "failed to parse source image name %s", sourceImage)
 	}
+	sourceCtx.DockerCertPath = cabundle.DockerCertPath(reference.Domain(srcRef.DockerReference()))
 
 	destStr := fmt.Sprintf("docker://%s", DestRef(destRegistry, image))
 	destRef, err := alltransports.ParseImageName(destStr)
@@ -320,6 +377,7 @@ func processOneImage(srcRegistry, destRegistry registry.RegistryOptions, image s
 	destCtx := &types.SystemContext{
 		DockerInsecureSkipTLSVerify: types.OptionalBoolTrue,
 		DockerDisableV1Ping:         true,
+		DockerCertPath:              cabundle.DockerCertPath(reference.Domain(destRef.DockerReference())),
 	}
 
 	if destRegistry.Username != "" && destRegistry.Password != "" {
@@ -456,7 +514,10 @@ func (ref *ImageRef) String() string {
 	return refStr
 }
 
-func CopyFromFileToRegistry(path string, name string, tag string, digest string, auth RegistryAuth, reportWriter io.Writer) error {
+// CopyFromFileToRegistry pushes an image archive from an airgap bundle to the registry. The format is the transport
+// of the archive, docker-archive or oci-archive. Only the architectures given are pushed from multi-arch images,
+// or all of them if the list is empty. A positive bandwidthLimit limits the push to that many bytes per second.
+func CopyFromFileToRegistry(path string, format string, name string, tag string, digest string, auth RegistryAuth, architectures []string, bandwidthLimit int64, reportWriter io.Writer) error {
 	policy, err := signature.NewPolicyFromBytes(imagePolicy)
 	if err != nil {
 		return errors.Wrap(err, "failed to read default policy")
@@ -466,7 +527,10 @@ func CopyFromFileToRegistry(path string, name string, tag string, digest string,
 		return errors.Wrap(err, "failed to create policy")
 	}
 
-	srcRef, err := alltransports.ParseImageName(fmt.Sprintf("docker-archive:%s", path))
+	if format == "" {
+		format = "docker-archive"
+	}
+	srcRef, err := alltransports.ParseImageName(fmt.Sprintf("%s:%s", format, path))
 	if err != nil {
 		return errors.Wrap(err, "failed to parse src image name")
 	}
@@ -480,6 +544,7 @@ func CopyFromFileToRegistry(path string, name string, tag string, digest string,
 	destCtx := &types.SystemContext{
 		DockerInsecureSkipTLSVerify: types.OptionalBoolTrue,
 		DockerDisableV1Ping:         true,
+		DockerCertPath:              cabundle.DockerCertPath(reference.Domain(destRef.DockerReference())),
 	}
 
 	if auth.Username != "" && auth.Password != "" {
@@ -501,14 +566,19 @@ func CopyFromFileToRegistry(path string, name string, tag string, digest string,
 		}
 	}
 
-	_, err = CopyImageWithGC(context.Background(), policyContext, destRef, srcRef, &copy.Options{
+	copyOptions := &copy.Options{
 		RemoveSignatures:      true,
 		SignBy:                "",
 		ReportWriter:          reportWriter,
 		SourceCtx:             nil,
 		DestinationCtx:        destCtx,
 		ForceManifestMIMEType: "",
-	})
+	}
+	if err := ArchitectureCopyOptions(context.Background(), srcRef, architectures, digest != "", copyOptions); err != nil {
+		return errors.Wrap(err, "failed to select architectures")
+	}
+
+	_, err = CopyImageWithGC(context.Background(), policyContext, destRef, ThrottleReference(srcRef, bandwidthLimit), copyOptions)
 	if err != nil {
 		return errors.Wrap(err, "failed to copy image")
 	}
@@ -525,7 +595,10 @@ func IsPrivateImage(image string) (bool, error) {
 			return false, errors.Wrapf(err, "failed to parse image ref %q", image)
 		}
 
-		sysCtx := types.SystemContext{DockerDisableV1Ping: true}
+		sysCtx := types.SystemContext{
+			DockerDisableV1Ping: true,
+			DockerCertPath:      cabundle.DockerCertPath(reference.Domain(ref.DockerReference())),
+		}
 
 		// allow pulling images from http/invalid https docker repos
 		// intended for development only, _THIS MAKES THINGS INSECURE_
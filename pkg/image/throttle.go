@@ -0,0 +1,109 @@
+package image
+
+import (
+	"context"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/containers/image/v5/types"
+	"github.com/docker/go-units"
+	"github.com/pkg/errors"
+)
+
+// maxThrottledRead keeps individual reads small so that throughput stays close to the limit
+const maxThrottledRead = 32 * 1024
+
+// ThrottleReference returns a reference that reads the blobs of the source image at no more than bytesPerSecond.
+// Limiting the source also limits the push, since blobs are streamed to the destination as they are read.
+// The reference is returned unchanged if bytesPerSecond is not positive.
+func ThrottleReference(ref types.ImageReference, bytesPerSecond int64) types.ImageReference {
+	if bytesPerSecond <= 0 {
+		return ref
+	}
+	return throttledReference{
+		ImageReference: ref,
+		limiter:        &bandwidthLimiter{bytesPerSecond: bytesPerSecond},
+	}
+}
+
+type throttledReference struct {
+	types.ImageReference
+	limiter *bandwidthLimiter
+}
+
+func (r throttledReference) NewImageSource(ctx context.Context, sys *types.SystemContext) (types.ImageSource, error) {
+	src, err := r.ImageReference.NewImageSource(ctx, sys)
+	if err != nil {
+		return nil, err
+	}
+	return throttledSource{ImageSource: src, limiter: r.limiter}, nil
+}
+
+type throttledSource struct {
+	types.ImageSource
+	limiter *bandwidthLimiter
+}
+
+func (s throttledSource) GetBlob(ctx context.Context, info types.BlobInfo, cache types.BlobInfoCache) (io.ReadCloser, int64, error) {
+	rc, size, err := s.ImageSource.GetBlob(ctx, info, cache)
+	if err != nil {
+		return nil, 0, err
+	}
+	return &throttledReadCloser{ReadCloser: rc, limiter: s.limiter}, size, nil
+}
+
+type throttledReadCloser struct {
+	io.ReadCloser
+	limiter *bandwidthLimiter
+}
+
+func (r *throttledReadCloser) Read(p []byte) (int, error) {
+	if len(p) > maxThrottledRead {
+		p = p[:maxThrottledRead]
+	}
+	n, err := r.ReadCloser.Read(p)
+	r.limiter.wait(n)
+	return n, err
+}
+
+// bandwidthLimiter is shared by all blobs of an image, which are copied concurrently
+type bandwidthLimiter struct {
+	mu             sync.Mutex
+	bytesPerSecond int64
+	next           time.Time
+}
+
+// wait blocks until n more bytes can be transferred without exceeding the limit
+func (l *bandwidthLimiter) wait(n int) {
+	if n <= 0 {
+		return
+	}
+
+	l.mu.Lock()
+	now := time.Now()
+	if l.next.Before(now) {
+		l.next = now
+	}
+	l.next = l.next.Add(time.Duration(int64(n) * int64(time.Second) / l.bytesPerSecond))
+	delay := l.next.Sub(now)
+	l.mu.Unlock()
+
+	time.Sleep(delay)
+}
+
+// ParseBandwidthLimit parses a rate such as "10MB" or "500k" as bytes per second.
+// An empty string means no limit.
+func ParseBandwidthLimit(limit string) (int64, error) {
+	if limit == "" {
+		return 0, nil
+	}
+	bytesPerSecond, err := units.FromHumanSize(limit)
+	if err != nil {
+		return 0, errors.Wrapf(err, "failed to parse bandwidth limit %q", limit)
+	}
+	if bytesPerSecond < 0 {
+		return 0, errors.Errorf("bandwidth limit %q must not be negative", limit)
+	}
+	return bytesPerSecond, nil
+}
@@ -0,0 +1,36 @@
+package image
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ParseBandwidthLimit(t *testing.T) {
+	tests := []struct {
+		limit   string
+		want    int64
+		wantErr bool
+	}{
+		{limit: "", want: 0},
+		{limit: "0", want: 0},
+		{limit: "500k", want: 500 * 1000},
+		{limit: "10MB", want: 10 * 1000 * 1000},
+		{limit: "1.5GB", want: 1500 * 1000 * 1000},
+		{limit: "fast", wantErr: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.limit, func(t *testing.T) {
+			req := require.New(t)
+
+			got, err := ParseBandwidthLimit(test.limit)
+			if test.wantErr {
+				req.Error(err)
+				return
+			}
+			req.NoError(err)
+			req.Equal(test.want, got)
+		})
+	}
+}
@@ -0,0 +1,511 @@
+package imagepushjob
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/replicatedhq/kots/pkg/image"
+	"github.com/replicatedhq/kots/pkg/imagepushjob/types"
+	"github.com/replicatedhq/kots/pkg/k8sutil"
+	kotsadmtypes "github.com/replicatedhq/kots/pkg/kotsadm/types"
+	"github.com/replicatedhq/kots/pkg/logger"
+	"github.com/replicatedhq/kots/pkg/objectstore"
+	"github.com/replicatedhq/kots/pkg/rand"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	kuberneteserrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	kustomizetypes "sigs.k8s.io/kustomize/api/types"
+)
+
+const (
+	// WorkersEnv is the env var with the number of jobs that push the images of an airgap bundle in parallel.
+	// Images are pushed by kotsadm itself if it is not set or less than 2.
+	WorkersEnv = "AIRGAP_PUSH_WORKERS"
+
+	keyPrefix    = "image-push"
+	pollInterval = 5 * time.Second
+)
+
+// Workers returns the number of push jobs to fan the image pushes out to
+func Workers() int {
+	workers, err := strconv.Atoi(os.Getenv(WorkersEnv))
+	if err != nil || workers < 1 {
+		return 1
+	}
+	return workers
+}
+
+type PushOptions struct {
+	// ImagesDir or AirgapBundle is where the images are read from
+	ImagesDir    string
+	AirgapBundle string
+	Workers      int
+	PushOptions  kotsadmtypes.PushImagesOptions
+}
+
+// Push copies the image files to the object store, splits them in shards of about the same size and starts a job
+// for each shard that pushes its images to the registry. It waits for all the jobs to finish and returns the
+// rewritten images. The jobs, the registry credentials and the image files are removed when it returns.
+func Push(ctx context.Context, options PushOptions) ([]kustomizetypes.Image, error) {
+	clientset, err := k8sutil.GetClientset()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get clientset")
+	}
+
+	driver, err := objectstore.GetDriver()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get object store driver")
+	}
+
+	namespace := os.Getenv("POD_NAMESPACE")
+	pushID := rand.StringWithCharset(8, rand.LOWER_CASE)
+	prefix := fmt.Sprintf("%s/%s/", keyPrefix, pushID)
+	progressWriter := options.PushOptions.ProgressWriter
+
+	defer func() {
+		if err := cleanup(context.Background(), clientset, driver, namespace, pushID, prefix); err != nil {
+			logger.Error(errors.Wrapf(err, "failed to clean up image push %s", pushID))
+		}
+	}()
+
+	writeProgressLine(progressWriter, "Copying images to the object store...")
+
+	var files []types.ShardFile
+	if options.AirgapBundle != "" {
+		files, err = uploadFromBundle(ctx, driver, prefix, options.AirgapBundle, options.PushOptions)
+	} else {
+		files, err = uploadFromPath(ctx, driver, prefix, options.ImagesDir, options.PushOptions)
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to upload images")
+	}
+
+	images := []kustomizetypes.Image{}
+	for _, f := range files {
+		images = append(images, kustomizetypes.Image{
+			Name:    f.SourceName,
+			NewName: f.Name,
+			NewTag:  f.Tag,
+			Digest:  f.Digest,
+		})
+	}
+	if len(files) == 0 {
+		return images, nil
+	}
+
+	architectures := options.PushOptions.Architectures
+	if len(architectures) == 0 && !options.PushOptions.AllArchitectures {
+		// all architectures are pushed if the node architectures cannot be detected, as when kotsadm pushes them
+		architectures, _ = k8sutil.GetNodeArchitectures(clientset)
+	}
+
+	shards := splitShards(files, options.Workers)
+	for i, shardFiles := range shards {
+		shard := types.Shard{
+			RegistryEndpoint:  options.PushOptions.Registry.Endpoint,
+			RegistryNamespace: options.PushOptions.Registry.Namespace,
+			Architectures:     architectures,
+			BandwidthLimit:    options.PushOptions.BandwidthLimit,
+			Files:             shardFiles,
+		}
+		b, err := json.Marshal(shard)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to marshal shard")
+		}
+		if err := driver.PutObject(ctx, shardKey(prefix, i), bytes.NewReader(b)); err != nil {
+			return nil, errors.Wrapf(err, "failed to upload shard %d", i)
+		}
+	}
+
+	if err := createCredentialsSecret(ctx, clientset, namespace, pushID, options.PushOptions); err != nil {
+		return nil, errors.Wrap(err, "failed to create registry credentials secret")
+	}
+
+	podTemplate, err := kotsadmPodTemplate(ctx, clientset, namespace)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get kotsadm pod template")
+	}
+
+	for i, shardFiles := range shards {
+		job := pushJob(podTemplate, namespace, pushID, i)
+		if _, err := clientset.BatchV1().Jobs(namespace).Create(ctx, job, metav1.CreateOptions{}); err != nil {
+			return nil, errors.Wrapf(err, "failed to create job %s", job.Name)
+		}
+		writeProgressLine(progressWriter, fmt.Sprintf("Started job %s to push %d images", job.Name, len(shardFiles)))
+	}
+
+	if err := waitForJobs(ctx, clientset, namespace, pushID, len(shards), progressWriter); err != nil {
+		return nil, err
+	}
+
+	return images, nil
+}
+
+func uploadFromPath(ctx context.Context, driver objectstore.Driver, prefix string, imagesDir string, options kotsadmtypes.PushImagesOptions) ([]types.ShardFile, error) {
+	formatDirs, err := os.ReadDir(imagesDir)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read images dir")
+	}
+
+	files := []types.ShardFile{}
+	for _, f := range formatDirs {
+		if !f.IsDir() {
+			continue
+		}
+
+		formatRoot := filepath.Join(imagesDir, f.Name())
+		err := filepath.Walk(formatRoot, func(path string, info os.FileInfo, err error) error {
+			if err != nil {
+				return err
+			}
+			if info.IsDir() {
+				return nil
+			}
+
+			pathParts := strings.Split(path[len(formatRoot)+1:], string(os.PathSeparator))
+			file, err := newShardFile(prefix, len(files), f.Name(), pathParts, info.Size(), options)
+			if err != nil {
+				return err
+			}
+
+			writeProgressLine(options.ProgressWriter, fmt.Sprintf("Copying image %s:%s", file.Name, file.Tag))
+			if err := objectstore.Upload(ctx, driver, file.Key, path); err != nil {
+				return errors.Wrapf(err, "failed to upload %s", path)
+			}
+
+			files = append(files, file)
+			return nil
+		})
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to walk images dir")
+		}
+	}
+
+	return files, nil
+}
+
+func uploadFromBundle(ctx context.Context, driver objectstore.Driver, prefix string, airgapBundle string, options kotsadmtypes.PushImagesOptions) ([]types.ShardFile, error) {
+	fileReader, err := os.Open(airgapBundle)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to open file")
+	}
+	defer fileReader.Close()
+
+	gzipReader, err := gzip.NewReader(fileReader)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get new gzip reader")
+	}
+	defer gzipReader.Close()
+
+	files := []types.ShardFile{}
+	tarReader := tar.NewReader(gzipReader)
+	for {
+		header, err := tarReader.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to read archive")
+		}
+
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+
+		// path is like "images/<format>/image/name/tag"
+		pathParts := strings.Split(header.Name, "/")
+		if len(pathParts) < 4 || pathParts[0] != "images" {
+			continue
+		}
+
+		file, err := newShardFile(prefix, len(files), pathParts[1], pathParts[2:], header.Size, options)
+		if err != nil {
+			return nil, err
+		}
+
+		writeProgressLine(options.ProgressWriter, fmt.Sprintf("Copying image %s:%s", file.Name, file.Tag))
+		if err := driver.PutObject(ctx, file.Key, tarReader); err != nil {
+			return nil, errors.Wrapf(err, "failed to upload %s", header.Name)
+		}
+
+		files = append(files, file)
+	}
+
+	return files, nil
+}
+
+func newShardFile(prefix string, index int, format string, pathParts []string, size int64, options kotsadmtypes.PushImagesOptions) (types.ShardFile, error) {
+	rewrittenImage, err := image.ImageInfoFromFile(options.Registry, pathParts)
+	if err != nil {
+		return types.ShardFile{}, errors.Wrapf(err, "failed to decode image from path %s", strings.Join(pathParts, "/"))
+	}
+
+	return types.ShardFile{
+		Key:        fmt.Sprintf("%simages/%d", prefix, index),
+		Format:     format,
+		SourceName: rewrittenImage.Name,
+		Name:       rewrittenImage.NewName,
+		Tag:        rewrittenImage.NewTag,
+		Digest:     rewrittenImage.Digest,
+		Size:       size,
+	}, nil
+}
+
+// splitShards splits the files in at most n shards of about the same total size, largest files first
+func splitShards(files []types.ShardFile, n int) [][]types.ShardFile {
+	if n > len(files) {
+		n = len(files)
+	}
+	if n < 1 {
+		n = 1
+	}
+
+	sorted := make([]types.ShardFile, len(files))
+	copy(sorted, files)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Size > sorted[j].Size
+	})
+
+	shards := make([][]types.ShardFile, n)
+	sizes := make([]int64, n)
+	for _, f := range sorted {
+		smallest := 0
+		for i := range sizes {
+			if sizes[i] < sizes[smallest] {
+				smallest = i
+			}
+		}
+		shards[smallest] = append(shards[smallest], f)
+		sizes[smallest] += f.Size
+	}
+
+	return shards
+}
+
+func shardKey(prefix string, shard int) string {
+	return fmt.Sprintf("%sshard-%d.json", prefix, shard)
+}
+
+func jobName(pushID string, shard int) string {
+	return fmt.Sprintf("kotsadm-image-push-%s-%d", pushID, shard)
+}
+
+func secretName(pushID string) string {
+	return fmt.Sprintf("kotsadm-image-push-%s", pushID)
+}
+
+func pushLabels(pushID string) map[string]string {
+	return map[string]string{
+		"kots.io/image-push-id": pushID,
+	}
+}
+
+func createCredentialsSecret(ctx context.Context, clientset kubernetes.Interface, namespace string, pushID string, options kotsadmtypes.PushImagesOptions) error {
+	secret := &corev1.Secret{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "v1",
+			Kind:       "Secret",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      secretName(pushID),
+			Namespace: namespace,
+			Labels:    kotsadmtypes.GetKotsadmLabels(pushLabels(pushID)),
+		},
+		Data: map[string][]byte{
+			"username": []byte(options.Registry.Username),
+			"password": []byte(options.Registry.Password),
+		},
+	}
+
+	_, err := clientset.CoreV1().Secrets(namespace).Create(ctx, secret, metav1.CreateOptions{})
+	return err
+}
+
+// kotsadmPodTemplate returns the pod template of kotsadm, which can be a deployment or a statefulset
+func kotsadmPodTemplate(ctx context.Context, clientset kubernetes.Interface, namespace string) (*corev1.PodTemplateSpec, error) {
+	deployment, err := clientset.AppsV1().Deployments(namespace).Get(ctx, "kotsadm", metav1.GetOptions{})
+	if err == nil {
+		return &deployment.Spec.Template, nil
+	}
+	if !kuberneteserrors.IsNotFound(err) {
+		return nil, errors.Wrap(err, "failed to get deployment")
+	}
+
+	statefulset, err := clientset.AppsV1().StatefulSets(namespace).Get(ctx, "kotsadm", metav1.GetOptions{})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get statefulset")
+	}
+	return &statefulset.Spec.Template, nil
+}
+
+// pushJob runs the kotsadm image with the env of kotsadm, so that it can read the shard from the object store
+func pushJob(podTemplate *corev1.PodTemplateSpec, namespace string, pushID string, shard int) *batchv1.Job {
+	kotsadmContainer := podTemplate.Spec.Containers[0]
+	for _, c := range podTemplate.Spec.Containers {
+		if c.Name == "kotsadm" {
+			kotsadmContainer = c
+		}
+	}
+
+	env := append([]corev1.EnvVar{}, kotsadmContainer.Env...)
+	for _, key := range []string{"username", "password"} {
+		env = append(env, corev1.EnvVar{
+			Name: "REGISTRY_" + strings.ToUpper(key),
+			ValueFrom: &corev1.EnvVarSource{
+				SecretKeyRef: &corev1.SecretKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{Name: secretName(pushID)},
+					Key:                  key,
+				},
+			},
+		})
+	}
+
+	var backoffLimit int32 = 2
+	labels := kotsadmtypes.GetKotsadmLabels(pushLabels(pushID))
+
+	return &batchv1.Job{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "batch/v1",
+			Kind:       "Job",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      jobName(pushID, shard),
+			Namespace: namespace,
+			Labels:    labels,
+		},
+		Spec: batchv1.JobSpec{
+			BackoffLimit: &backoffLimit,
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: labels,
+				},
+				Spec: corev1.PodSpec{
+					RestartPolicy:      corev1.RestartPolicyNever,
+					ServiceAccountName: podTemplate.Spec.ServiceAccountName,
+					SecurityContext:    podTemplate.Spec.SecurityContext,
+					ImagePullSecrets:   podTemplate.Spec.ImagePullSecrets,
+					NodeSelector:       podTemplate.Spec.NodeSelector,
+					Tolerations:        podTemplate.Spec.Tolerations,
+					Containers: []corev1.Container{
+						{
+							Name:            "image-push",
+							Image:           kotsadmContainer.Image,
+							ImagePullPolicy: kotsadmContainer.ImagePullPolicy,
+							Command: []string{
+								"/kotsadm",
+								"push-images-shard",
+								"--push-id", pushID,
+								"--shard", strconv.Itoa(shard),
+							},
+							Env:             env,
+							EnvFrom:         kotsadmContainer.EnvFrom,
+							SecurityContext: kotsadmContainer.SecurityContext,
+							VolumeMounts: []corev1.VolumeMount{
+								{Name: "tmp", MountPath: "/tmp"},
+							},
+						},
+					},
+					Volumes: []corev1.Volume{
+						{
+							Name: "tmp",
+							VolumeSource: corev1.VolumeSource{
+								EmptyDir: &corev1.EmptyDirVolumeSource{},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// waitForJobs returns when all the jobs have completed, or with an error as soon as one of them fails
+func waitForJobs(ctx context.Context, clientset kubernetes.Interface, namespace string, pushID string, count int, progressWriter io.Writer) error {
+	lastCompleted := -1
+	for {
+		completed := 0
+		for i := 0; i < count; i++ {
+			job, err := clientset.BatchV1().Jobs(namespace).Get(ctx, jobName(pushID, i), metav1.GetOptions{})
+			if err != nil {
+				return errors.Wrapf(err, "failed to get job %s", jobName(pushID, i))
+			}
+			for _, condition := range job.Status.Conditions {
+				if condition.Status != corev1.ConditionTrue {
+					continue
+				}
+				if condition.Type == batchv1.JobFailed {
+					return errors.Errorf("job %s failed: %s", job.Name, condition.Message)
+				}
+				if condition.Type == batchv1.JobComplete {
+					completed++
+				}
+			}
+		}
+
+		if completed != lastCompleted {
+			writeProgressLine(progressWriter, fmt.Sprintf("Pushing images: %d of %d jobs completed", completed, count))
+			lastCompleted = completed
+		}
+		if completed == count {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(pollInterval):
+		}
+	}
+}
+
+func cleanup(ctx context.Context, clientset kubernetes.Interface, driver objectstore.Driver, namespace string, pushID string, prefix string) error {
+	propagation := metav1.DeletePropagationBackground
+	err := clientset.BatchV1().Jobs(namespace).DeleteCollection(ctx, metav1.DeleteOptions{PropagationPolicy: &propagation}, metav1.ListOptions{
+		LabelSelector: fmt.Sprintf("kots.io/image-push-id=%s", pushID),
+	})
+	if err != nil {
+		return errors.Wrap(err, "failed to delete jobs")
+	}
+
+	err = clientset.CoreV1().Secrets(namespace).Delete(ctx, secretName(pushID), metav1.DeleteOptions{})
+	if err != nil && !kuberneteserrors.IsNotFound(err) {
+		return errors.Wrap(err, "failed to delete secret")
+	}
+
+	keys := []string{}
+	err = driver.ListObjects(ctx, prefix, func(o objectstore.Object) error {
+		keys = append(keys, o.Key)
+		return nil
+	})
+	if err != nil {
+		return errors.Wrap(err, "failed to list objects")
+	}
+	if len(keys) > 0 {
+		if err := driver.DeleteObjects(ctx, keys); err != nil {
+			return errors.Wrap(err, "failed to delete objects")
+		}
+	}
+
+	return nil
+}
+
+func writeProgressLine(progressWriter io.Writer, line string) {
+	if progressWriter == nil {
+		return
+	}
+	fmt.Fprintf(progressWriter, "%s\n", line)
+}
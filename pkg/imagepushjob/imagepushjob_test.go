@@ -0,0 +1,67 @@
+package imagepushjob
+
+import (
+	"testing"
+
+	"github.com/replicatedhq/kots/pkg/imagepushjob/types"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_splitShards(t *testing.T) {
+	files := func(sizes ...int64) []types.ShardFile {
+		result := []types.ShardFile{}
+		for _, size := range sizes {
+			result = append(result, types.ShardFile{Size: size})
+		}
+		return result
+	}
+	shardSizes := func(shards [][]types.ShardFile) []int64 {
+		result := []int64{}
+		for _, shard := range shards {
+			var total int64
+			for _, f := range shard {
+				total += f.Size
+			}
+			result = append(result, total)
+		}
+		return result
+	}
+
+	tests := []struct {
+		name    string
+		files   []types.ShardFile
+		workers int
+		want    []int64
+	}{
+		{
+			name:    "one worker",
+			files:   files(1, 2, 3),
+			workers: 1,
+			want:    []int64{6},
+		},
+		{
+			name:    "largest files are spread first",
+			files:   files(1, 8, 2, 7, 3, 5),
+			workers: 2,
+			want:    []int64{13, 13},
+		},
+		{
+			name:    "more workers than files",
+			files:   files(4, 2),
+			workers: 5,
+			want:    []int64{4, 2},
+		},
+		{
+			name:    "no workers",
+			files:   files(4, 2),
+			workers: 0,
+			want:    []int64{6},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			shards := splitShards(test.files, test.workers)
+			require.Equal(t, test.want, shardSizes(shards))
+		})
+	}
+}
@@ -0,0 +1,76 @@
+package imagepushjob
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/replicatedhq/kots/pkg/image"
+	"github.com/replicatedhq/kots/pkg/imagepushjob/types"
+	"github.com/replicatedhq/kots/pkg/objectstore"
+)
+
+// RunShard pushes the images of one shard from the object store to the registry. It is run by the push jobs, with
+// the registry credentials in the REGISTRY_USERNAME and REGISTRY_PASSWORD env vars.
+func RunShard(ctx context.Context, pushID string, shardIndex int) error {
+	driver, err := objectstore.GetDriver()
+	if err != nil {
+		return errors.Wrap(err, "failed to get object store driver")
+	}
+
+	prefix := fmt.Sprintf("%s/%s/", keyPrefix, pushID)
+
+	r, err := driver.GetObject(ctx, shardKey(prefix, shardIndex))
+	if err != nil {
+		return errors.Wrap(err, "failed to get shard")
+	}
+	shard := types.Shard{}
+	err = json.NewDecoder(r).Decode(&shard)
+	r.Close()
+	if err != nil {
+		return errors.Wrap(err, "failed to decode shard")
+	}
+
+	registryAuth := image.RegistryAuth{
+		Username: os.Getenv("REGISTRY_USERNAME"),
+		Password: os.Getenv("REGISTRY_PASSWORD"),
+	}
+
+	for i, file := range shard.Files {
+		fmt.Printf("Pushing image %s:%s (%d of %d)\n", file.Name, file.Tag, i+1, len(shard.Files))
+		if err := pushShardFile(ctx, driver, file, shard, registryAuth); err != nil {
+			return errors.Wrapf(err, "failed to push image %s:%s", file.Name, file.Tag)
+		}
+	}
+
+	return nil
+}
+
+func pushShardFile(ctx context.Context, driver objectstore.Driver, file types.ShardFile, shard types.Shard, registryAuth image.RegistryAuth) error {
+	tmpFile, err := ioutil.TempFile("", "kotsadm-image-push-")
+	if err != nil {
+		return errors.Wrap(err, "failed to create temp file")
+	}
+	defer os.Remove(tmpFile.Name())
+
+	err = objectstore.Download(ctx, driver, file.Key, tmpFile)
+	tmpFile.Close()
+	if err != nil {
+		return errors.Wrap(err, "failed to download image")
+	}
+
+	for i := 0; i < 5; i++ {
+		err = image.CopyFromFileToRegistry(tmpFile.Name(), file.Format, file.Name, file.Tag, file.Digest, registryAuth, shard.Architectures, shard.BandwidthLimit, os.Stdout)
+		if err == nil {
+			return nil
+		}
+		fmt.Printf("encountered error (#%d) copying image, waiting 10s before trying again: %s\n", i+1, err.Error())
+		time.Sleep(time.Second * 10)
+	}
+
+	return err
+}
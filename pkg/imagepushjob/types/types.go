@@ -0,0 +1,23 @@
+package types
+
+// Shard is the list of image files that one push job copies from the object store to the registry
+type Shard struct {
+	RegistryEndpoint  string      `json:"registryEndpoint"`
+	RegistryNamespace string      `json:"registryNamespace"`
+	Architectures     []string    `json:"architectures,omitempty"`
+	BandwidthLimit    int64       `json:"bandwidthLimit,omitempty"`
+	Files             []ShardFile `json:"files"`
+}
+
+type ShardFile struct {
+	// Key is the key of the image file in the object store
+	Key    string `json:"key"`
+	Format string `json:"format"`
+	// SourceName is the name of the image in the airgap bundle
+	SourceName string `json:"sourceName"`
+	// Name, Tag and Digest are the destination of the image in the registry
+	Name   string `json:"name"`
+	Tag    string `json:"tag"`
+	Digest string `json:"digest,omitempty"`
+	Size   int64  `json:"size"`
+}
@@ -0,0 +1,235 @@
+package imagereport
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	imagedocker "github.com/containers/image/v5/docker"
+	dockerref "github.com/containers/image/v5/docker/reference"
+	containerstypes "github.com/containers/image/v5/types"
+	"github.com/pkg/errors"
+	kotsv1beta1 "github.com/replicatedhq/kots/kotskinds/apis/kots/v1beta1"
+	"github.com/replicatedhq/kots/pkg/cabundle"
+	"github.com/replicatedhq/kots/pkg/docker/registry"
+	"github.com/replicatedhq/kots/pkg/downstream"
+	"github.com/replicatedhq/kots/pkg/imagereport/types"
+	"github.com/replicatedhq/kots/pkg/k8sdoc"
+	"github.com/replicatedhq/kots/pkg/kotsutil"
+	registrytypes "github.com/replicatedhq/kots/pkg/registry/types"
+	"github.com/replicatedhq/kots/pkg/store"
+)
+
+// Get returns the stored image report for the app version, generating it first if there is none
+// or if refresh is true
+func Get(appID string, sequence int64, refresh bool) (*types.Report, error) {
+	if !refresh {
+		report, err := store.GetStore().GetImageReport(appID, sequence)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to get image report")
+		}
+		if report != nil {
+			return report, nil
+		}
+	}
+
+	report, err := Generate(appID, sequence)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to generate image report")
+	}
+
+	if err := store.GetStore().SetImageReport(appID, *report); err != nil {
+		return nil, errors.Wrap(err, "failed to set image report")
+	}
+
+	return report, nil
+}
+
+// Generate renders the app version and lists every image it references, both as released and as it
+// will be deployed, and resolves the digest that each deployed image currently points to
+func Generate(appID string, sequence int64) (*types.Report, error) {
+	archiveDir, err := ioutil.TempDir("", "kotsadm")
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create temp dir")
+	}
+	defer os.RemoveAll(archiveDir)
+
+	if err := store.GetStore().GetAppVersionArchive(appID, sequence, archiveDir); err != nil {
+		return nil, errors.Wrap(err, "failed to get app version archive")
+	}
+
+	kotsKinds, err := kotsutil.LoadKotsKindsFromPath(archiveDir)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to load kotskinds")
+	}
+
+	baseManifests, err := exec.Command(fmt.Sprintf("kustomize%s", kotsKinds.KustomizeVersion()), "build", filepath.Join(archiveDir, "base")).Output()
+	if err != nil {
+		if ee, ok := err.(*exec.ExitError); ok {
+			err = errors.Errorf("kustomize stderr: %q", string(ee.Stderr))
+		}
+		return nil, errors.Wrap(err, "failed to build base")
+	}
+
+	renderedManifests, err := downstream.RenderManifests(archiveDir, "", kotsKinds.KustomizeVersion())
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to render manifests")
+	}
+
+	registrySettings, err := store.GetStore().GetRegistryDetailsForApp(appID)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get registry settings")
+	}
+
+	images := matchImages(listImages(baseManifests), listImages(renderedManifests))
+
+	auths := registryAuths(kotsKinds.License, registrySettings)
+	digests := map[string]string{}
+	for i, image := range images {
+		if digest, ok := digests[image.Rewritten]; ok {
+			images[i].Digest = digest
+			continue
+		}
+
+		digest, err := resolveDigest(image.Rewritten, auths)
+		if err != nil {
+			images[i].Error = err.Error()
+			continue
+		}
+		digests[image.Rewritten] = digest
+		images[i].Digest = digest
+	}
+
+	return &types.Report{
+		Sequence:  sequence,
+		Images:    images,
+		CreatedAt: time.Now(),
+	}, nil
+}
+
+type objectImages struct {
+	kind      string
+	name      string
+	namespace string
+	images    []string
+}
+
+func (o objectImages) key() string {
+	return fmt.Sprintf("%s/%s", strings.ToLower(o.kind), o.name)
+}
+
+func listImages(manifests []byte) []objectImages {
+	objects := []objectImages{}
+	for _, doc := range strings.Split(string(manifests), "\n---\n") {
+		parsed, err := k8sdoc.ParseYAML([]byte(doc))
+		if err != nil {
+			continue
+		}
+
+		o := objectImages{}
+		switch d := parsed.(type) {
+		case *k8sdoc.Doc:
+			o.kind, o.name, o.namespace = d.Kind, d.Metadata.Name, d.Metadata.Namespace
+		case *k8sdoc.PodDoc:
+			o.kind, o.name, o.namespace = d.Kind, d.Metadata.Name, d.Metadata.Namespace
+		}
+
+		for _, image := range parsed.ListImages() {
+			if image != "" {
+				o.images = append(o.images, image)
+			}
+		}
+		if len(o.images) > 0 {
+			objects = append(objects, o)
+		}
+	}
+	return objects
+}
+
+// matchImages pairs each rendered image with the image in the same position of the same object in the base.
+// Images are only rewritten in place, so the containers of an object are in the same order in both.
+func matchImages(base []objectImages, rendered []objectImages) []types.Image {
+	baseImages := map[string][]string{}
+	for _, o := range base {
+		baseImages[o.key()] = o.images
+	}
+
+	images := []types.Image{}
+	for _, o := range rendered {
+		original := baseImages[o.key()]
+		for i, image := range o.images {
+			reportImage := types.Image{
+				Kind:      o.kind,
+				Name:      o.name,
+				Namespace: o.namespace,
+				Original:  image,
+				Rewritten: image,
+			}
+			if len(original) == len(o.images) {
+				reportImage.Original = original[i]
+			}
+			images = append(images, reportImage)
+		}
+	}
+	return images
+}
+
+// registryAuths returns the credentials for the registries that images can be rewritten to
+func registryAuths(license *kotsv1beta1.License, registrySettings registrytypes.RegistrySettings) map[string]*containerstypes.DockerAuthConfig {
+	auths := map[string]*containerstypes.DockerAuthConfig{}
+
+	if license != nil {
+		licenseAuth := &containerstypes.DockerAuthConfig{
+			Username: license.Spec.LicenseID,
+			Password: license.Spec.LicenseID,
+		}
+		for _, host := range registry.ProxyEndpointFromLicense(license).ToSlice() {
+			auths[host] = licenseAuth
+		}
+	}
+
+	if registrySettings.Hostname != "" && registrySettings.Username != "" {
+		host := strings.Split(registrySettings.Hostname, "/")[0]
+		auths[host] = &containerstypes.DockerAuthConfig{
+			Username: registrySettings.Username,
+			Password: registrySettings.Password,
+		}
+	}
+
+	return auths
+}
+
+func resolveDigest(image string, auths map[string]*containerstypes.DockerAuthConfig) (string, error) {
+	if idx := strings.Index(image, "@"); idx != -1 {
+		return image[idx+1:], nil
+	}
+
+	// ParseReference requires the // prefix
+	ref, err := imagedocker.ParseReference(fmt.Sprintf("//%s", image))
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to parse image ref %q", image)
+	}
+
+	sysCtx := containerstypes.SystemContext{
+		DockerDisableV1Ping: true,
+		DockerCertPath:      cabundle.DockerCertPath(dockerref.Domain(ref.DockerReference())),
+	}
+	if os.Getenv("KOTSADM_INSECURE_SRCREGISTRY") == "true" {
+		sysCtx.DockerInsecureSkipTLSVerify = containerstypes.OptionalBoolTrue
+	}
+	if auth, ok := auths[dockerref.Domain(ref.DockerReference())]; ok {
+		sysCtx.DockerAuthConfig = auth
+	}
+
+	digest, err := imagedocker.GetDigest(context.Background(), &sysCtx, ref)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to get digest")
+	}
+
+	return digest.String(), nil
+}
@@ -0,0 +1,64 @@
+package imagereport
+
+import (
+	"testing"
+
+	"github.com/replicatedhq/kots/pkg/imagereport/types"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_matchImages(t *testing.T) {
+	base := listImages([]byte(`apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+spec:
+  template:
+    spec:
+      initContainers:
+      - image: busybox
+      containers:
+      - image: quay.io/acme/web:1.0
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: web
+`))
+
+	rendered := listImages([]byte(`apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+spec:
+  template:
+    spec:
+      initContainers:
+      - image: registry.example.com/acme/busybox:latest
+      containers:
+      - image: proxy.replicated.com/proxy/app/quay.io/acme/web:1.0
+---
+apiVersion: v1
+kind: Pod
+metadata:
+  name: debug
+  namespace: tools
+spec:
+  containers:
+  - image: alpine@sha256:abc
+`))
+
+	want := []types.Image{
+		{Kind: "Deployment", Name: "web", Original: "quay.io/acme/web:1.0", Rewritten: "proxy.replicated.com/proxy/app/quay.io/acme/web:1.0"},
+		{Kind: "Deployment", Name: "web", Original: "busybox", Rewritten: "registry.example.com/acme/busybox:latest"},
+		{Kind: "Pod", Name: "debug", Namespace: "tools", Original: "alpine@sha256:abc", Rewritten: "alpine@sha256:abc"},
+	}
+
+	require.Equal(t, want, matchImages(base, rendered))
+}
+
+func Test_resolveDigestPinned(t *testing.T) {
+	digest, err := resolveDigest("registry.example.com/acme/web@sha256:abc", nil)
+	require.NoError(t, err)
+	require.Equal(t, "sha256:abc", digest)
+}
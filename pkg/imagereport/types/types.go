@@ -0,0 +1,26 @@
+package types
+
+import (
+	"time"
+)
+
+// Image is a container image referenced by a rendered resource
+type Image struct {
+	Kind      string `json:"kind"`
+	Name      string `json:"name"`
+	Namespace string `json:"namespace,omitempty"`
+	// Original is the image as it is referenced in the upstream release
+	Original string `json:"original"`
+	// Rewritten is the image that will be deployed after registry and proxy rewrites
+	Rewritten string `json:"rewritten"`
+	// Digest is the digest the rewritten image resolved to when the report was generated
+	Digest string `json:"digest,omitempty"`
+	// Error is set if the digest could not be resolved
+	Error string `json:"error,omitempty"`
+}
+
+type Report struct {
+	Sequence  int64     `json:"sequence"`
+	Images    []Image   `json:"images"`
+	CreatedAt time.Time `json:"createdAt"`
+}
@@ -0,0 +1,335 @@
+package janitor
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/replicatedhq/kots/pkg/app"
+	"github.com/replicatedhq/kots/pkg/janitor/types"
+	"github.com/replicatedhq/kots/pkg/k8sutil"
+	"github.com/replicatedhq/kots/pkg/kotsutil"
+	"github.com/replicatedhq/kots/pkg/logger"
+	"github.com/replicatedhq/kots/pkg/scheduledjob"
+	scheduledjobtypes "github.com/replicatedhq/kots/pkg/scheduledjob/types"
+	"github.com/replicatedhq/kots/pkg/session"
+	sessiontypes "github.com/replicatedhq/kots/pkg/session/types"
+	"github.com/replicatedhq/kots/pkg/store"
+	"go.uber.org/zap"
+)
+
+const (
+	interval = time.Hour
+
+	// tempMaxAge is how long a temp path must be unmodified before it is considered leaked by an operation
+	// that crashed or did not clean up after itself
+	tempMaxAge = 24 * time.Hour
+
+	// archiveMinAge protects archives of versions that are being created, the archive is uploaded before
+	// the version is committed to the database
+	archiveMinAge = time.Hour
+
+	// jobRunsMaxAge is how long the history of the scheduled jobs is kept
+	jobRunsMaxAge = 30 * 24 * time.Hour
+
+	// staleTaskMaxAge is how long a running task status can go without an update before it is considered to be
+	// left behind by a crash, running tasks update their status every few seconds
+	staleTaskMaxAge = 10 * time.Minute
+)
+
+// tempPrefixes are the prefixes of the temp dirs and files that kotsadm creates
+var tempPrefixes = []string{
+	"kots",
+	"airgap-kots",
+	"app-version-archive",
+	"archive",
+	"chart",
+	"downloadUpstream",
+	"headless-airgap",
+	"license",
+	"temp-image-pull",
+	"troubleshoot",
+	"workDir",
+}
+
+// tempExclusions are temp paths that are intentionally long lived
+var tempExclusions = []string{
+	"kotsadm-ca-bundle",
+	"kots-base-cache",
+}
+
+var (
+	stats    types.Stats
+	statsMtx sync.Mutex
+)
+
+// Start periodically removes leaked temp dirs, app versions that were left half created, app version archives that
+// are not referenced by any version, removed apps whose trash retention has passed, expired and idle sessions and
+// running task statuses that are no longer updated
+func Start() error {
+	logger.Debug("starting janitor")
+
+	go func() {
+		for {
+			// the error is logged by the run and recorded in the job history
+			_ = scheduledjob.Run(scheduledjobtypes.JobJanitor, "", run)
+			time.Sleep(interval)
+		}
+	}()
+
+	return nil
+}
+
+// GetStats returns what the janitor reclaimed since kotsadm started
+func GetStats() types.Stats {
+	statsMtx.Lock()
+	defer statsMtx.Unlock()
+
+	return stats
+}
+
+// NextRun returns when the janitor runs next, or nil if it has not run yet
+func NextRun() *time.Time {
+	statsMtx.Lock()
+	defer statsMtx.Unlock()
+
+	if stats.LastRunAt == nil {
+		return nil
+	}
+	next := stats.LastRunAt.Add(interval)
+	return &next
+}
+
+// run returns a summary of what was reclaimed, and the last error of the run
+func run() (string, error) {
+	now := time.Now()
+	var runErr error
+
+	tempPaths, tempBytes, err := cleanTempDir(os.TempDir(), now.Add(-tempMaxAge))
+	if err != nil {
+		runErr = errors.Wrap(err, "failed to clean temp dir")
+		logger.Error(runErr)
+	}
+
+	// purged before the orphaned archives are deleted, so that the archives of purged apps are deleted in this run
+	appsPurged, err := purgeRemovedApps(now)
+	if err != nil {
+		runErr = errors.Wrap(err, "failed to purge removed apps")
+		logger.Error(runErr)
+	}
+
+	// deleted before the orphaned archives, so that the archives of the incomplete versions are deleted in this run
+	incompleteVersions, err := store.GetStore().DeleteIncompleteAppVersions(now.Add(-archiveMinAge))
+	if err != nil {
+		runErr = errors.Wrap(err, "failed to delete incomplete app versions")
+		logger.Error(runErr)
+	}
+
+	archives, archiveBytes, err := store.GetStore().DeleteOrphanedAppVersionArchives(now.Add(-archiveMinAge))
+	if err != nil {
+		runErr = errors.Wrap(err, "failed to delete orphaned app version archives")
+		logger.Error(runErr)
+	}
+
+	jobRuns, err := store.GetStore().DeleteScheduledJobRunsBefore(now.Add(-jobRunsMaxAge))
+	if err != nil {
+		runErr = errors.Wrap(err, "failed to delete scheduled job runs")
+		logger.Error(runErr)
+	}
+
+	sessionsExpired, err := deleteInactiveSessions(now)
+	if err != nil {
+		runErr = errors.Wrap(err, "failed to delete inactive sessions")
+		logger.Error(runErr)
+	}
+
+	staleTasks, err := store.GetStore().ClearStaleTaskStatuses(now.Add(-staleTaskMaxAge))
+	if err != nil {
+		runErr = errors.Wrap(err, "failed to clear stale task statuses")
+		logger.Error(runErr)
+	}
+	if len(staleTasks) > 0 {
+		// a stuck update-download status would otherwise keep the update checker from downloading updates
+		logger.Info("janitor cleared stale task statuses", zap.Strings("tasks", staleTasks))
+	}
+
+	if tempPaths > 0 || archives > 0 || appsPurged > 0 || incompleteVersions > 0 {
+		logger.Info("janitor reclaimed space",
+			zap.Int64("tempPaths", tempPaths),
+			zap.Int64("tempBytes", tempBytes),
+			zap.Int64("appsPurged", appsPurged),
+			zap.Int64("incompleteVersions", incompleteVersions),
+			zap.Int64("archives", archives),
+			zap.Int64("archiveBytes", archiveBytes),
+			zap.Int64("jobRuns", jobRuns))
+	}
+	if sessionsExpired > 0 {
+		logger.Info("janitor deleted inactive sessions", zap.Int64("sessions", sessionsExpired))
+	}
+
+	statsMtx.Lock()
+	defer statsMtx.Unlock()
+
+	stats.LastRunAt = &now
+	stats.LastError = ""
+	if runErr != nil {
+		stats.LastError = runErr.Error()
+	}
+	stats.TempPathsRemoved += tempPaths
+	stats.TempBytesReclaimed += tempBytes
+	stats.ArchivesRemoved += archives
+	stats.ArchiveBytesReclaimed += archiveBytes
+	stats.AppsPurged += appsPurged
+	stats.IncompleteVersionsDeleted += incompleteVersions
+	stats.SessionsExpired += sessionsExpired
+	stats.StaleTasksCleared += int64(len(staleTasks))
+
+	summary := fmt.Sprintf("removed %d temp paths, %d archives, %d incomplete versions and %d apps, reclaimed %d bytes, deleted %d sessions, cleared %d stale tasks", tempPaths, archives, incompleteVersions, appsPurged, tempBytes+archiveBytes, sessionsExpired, len(staleTasks))
+	return summary, runErr
+}
+
+// deleteInactiveSessions deletes the sessions that are past their lifetime or idle timeout. They are already rejected
+// when used, but would otherwise be kept in the sessions secret forever.
+func deleteInactiveSessions(now time.Time) (int64, error) {
+	settings, err := session.GetSettings(store.GetStore())
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to get session settings")
+	}
+
+	sessions, err := store.GetStore().ListSessions()
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to list sessions")
+	}
+
+	ids := inactiveSessionIDs(sessions, settings, now)
+	if len(ids) == 0 {
+		return 0, nil
+	}
+
+	if err := store.GetStore().DeleteSessions(ids); err != nil {
+		return 0, errors.Wrap(err, "failed to delete sessions")
+	}
+
+	return int64(len(ids)), nil
+}
+
+func inactiveSessionIDs(sessions []sessiontypes.Session, settings sessiontypes.SessionSettings, now time.Time) []string {
+	ids := []string{}
+	for i := range sessions {
+		if session.CheckActive(&sessions[i], settings, now) != nil {
+			ids = append(ids, sessions[i].ID)
+		}
+	}
+	return ids
+}
+
+// purgeRemovedApps permanently removes the apps that have been in the trash for longer than the retention, and the
+// namespaces that kots created for them
+func purgeRemovedApps(now time.Time) (int64, error) {
+	removedApps, err := store.GetStore().ListRemovedApps()
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to list removed apps")
+	}
+
+	purged := int64(0)
+	for _, a := range removedApps {
+		purgeAt := app.PurgeAt(a)
+		if purgeAt == nil || purgeAt.After(now) {
+			continue
+		}
+
+		if err := store.GetStore().RemoveApp(a.ID); err != nil {
+			logger.Error(errors.Wrapf(err, "failed to remove app %s", a.Slug))
+			continue
+		}
+		purged++
+
+		// the app is already removed, so failing to clean up its namespaces is not an error
+		clientset, err := k8sutil.GetClientset()
+		if err != nil {
+			logger.Error(errors.Wrap(err, "failed to get clientset"))
+		} else if err := kotsutil.DeleteManagedNamespaces(clientset, a.Slug); err != nil {
+			logger.Error(errors.Wrapf(err, "failed to delete managed namespaces of app %s", a.Slug))
+		}
+	}
+
+	return purged, nil
+}
+
+// cleanTempDir removes the kotsadm temp dirs and files in dir that have not been modified since olderThan.
+// A dir is only removed if nothing in it was modified since olderThan.
+func cleanTempDir(dir string, olderThan time.Time) (int64, int64, error) {
+	entries, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return 0, 0, errors.Wrap(err, "failed to read dir")
+	}
+
+	removed, reclaimed := int64(0), int64(0)
+	for _, entry := range entries {
+		if !isKotsadmTempPath(entry.Name()) {
+			continue
+		}
+
+		entryPath := filepath.Join(dir, entry.Name())
+		size, lastModified, err := sizeAndLastModified(entryPath)
+		if err != nil {
+			logger.Error(errors.Wrapf(err, "failed to stat %s", entryPath))
+			continue
+		}
+		if lastModified.After(olderThan) {
+			continue
+		}
+
+		if err := os.RemoveAll(entryPath); err != nil {
+			logger.Error(errors.Wrapf(err, "failed to remove %s", entryPath))
+			continue
+		}
+		removed++
+		reclaimed += size
+	}
+
+	return removed, reclaimed, nil
+}
+
+func isKotsadmTempPath(name string) bool {
+	for _, exclusion := range tempExclusions {
+		if name == exclusion {
+			return false
+		}
+	}
+	for _, prefix := range tempPrefixes {
+		if strings.HasPrefix(name, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// sizeAndLastModified returns the total size of the files in the path and the most recent modification time
+func sizeAndLastModified(root string) (int64, time.Time, error) {
+	size := int64(0)
+	lastModified := time.Time{}
+
+	err := filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.ModTime().After(lastModified) {
+			lastModified = info.ModTime()
+		}
+		if !info.IsDir() {
+			size += info.Size()
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, time.Time{}, err
+	}
+
+	return size, lastModified, nil
+}
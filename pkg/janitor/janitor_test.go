@@ -0,0 +1,68 @@
+package janitor
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	sessiontypes "github.com/replicatedhq/kots/pkg/session/types"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_cleanTempDir(t *testing.T) {
+	req := require.New(t)
+
+	dir, err := ioutil.TempDir("", "janitor-test")
+	req.NoError(err)
+	defer os.RemoveAll(dir)
+
+	old := time.Now().Add(-48 * time.Hour)
+
+	// a leaked dir that is removed
+	req.NoError(os.MkdirAll(filepath.Join(dir, "kotsadm123", "upstream"), 0755))
+	req.NoError(ioutil.WriteFile(filepath.Join(dir, "kotsadm123", "upstream", "app.yaml"), []byte("12345"), 0644))
+	req.NoError(os.Chtimes(filepath.Join(dir, "kotsadm123", "upstream", "app.yaml"), old, old))
+	req.NoError(os.Chtimes(filepath.Join(dir, "kotsadm123", "upstream"), old, old))
+	req.NoError(os.Chtimes(filepath.Join(dir, "kotsadm123"), old, old))
+
+	// a dir with a recently modified file is in use
+	req.NoError(os.MkdirAll(filepath.Join(dir, "kotsadm456"), 0755))
+	req.NoError(ioutil.WriteFile(filepath.Join(dir, "kotsadm456", "archive.tar.gz"), []byte("12345"), 0644))
+	req.NoError(os.Chtimes(filepath.Join(dir, "kotsadm456"), old, old))
+
+	// excluded and unknown paths are kept
+	req.NoError(ioutil.WriteFile(filepath.Join(dir, "kotsadm-ca-bundle"), []byte("12345"), 0644))
+	req.NoError(os.Chtimes(filepath.Join(dir, "kotsadm-ca-bundle"), old, old))
+	req.NoError(ioutil.WriteFile(filepath.Join(dir, "other"), []byte("12345"), 0644))
+	req.NoError(os.Chtimes(filepath.Join(dir, "other"), old, old))
+
+	removed, reclaimed, err := cleanTempDir(dir, time.Now().Add(-24*time.Hour))
+	req.NoError(err)
+	req.Equal(int64(1), removed)
+	req.Equal(int64(5), reclaimed)
+
+	_, err = os.Stat(filepath.Join(dir, "kotsadm123"))
+	req.True(os.IsNotExist(err))
+	for _, name := range []string{"kotsadm456", "kotsadm-ca-bundle", "other"} {
+		_, err = os.Stat(filepath.Join(dir, name))
+		req.NoError(err)
+	}
+}
+
+func Test_inactiveSessionIDs(t *testing.T) {
+	now := time.Now()
+	settings := sessiontypes.SessionSettings{
+		TTLMinutes:         12 * 60,
+		IdleTimeoutMinutes: 30,
+	}
+
+	sessions := []sessiontypes.Session{
+		{ID: "active", IssuedAt: now.Add(-time.Hour), ExpiresAt: now.Add(11 * time.Hour), LastActiveAt: now.Add(-time.Minute)},
+		{ID: "expired", IssuedAt: now.Add(-13 * time.Hour), ExpiresAt: now.Add(-time.Hour), LastActiveAt: now.Add(-time.Minute)},
+		{ID: "idle", IssuedAt: now.Add(-2 * time.Hour), ExpiresAt: now.Add(10 * time.Hour), LastActiveAt: now.Add(-time.Hour)},
+	}
+
+	require.ElementsMatch(t, []string{"expired", "idle"}, inactiveSessionIDs(sessions, settings, now))
+}
@@ -0,0 +1,22 @@
+package types
+
+import (
+	"time"
+)
+
+// Stats are the totals of what the janitor reclaimed since kotsadm started
+type Stats struct {
+	LastRunAt             *time.Time `json:"lastRunAt,omitempty"`
+	LastError             string     `json:"lastError,omitempty"`
+	TempPathsRemoved      int64      `json:"tempPathsRemoved"`
+	TempBytesReclaimed    int64      `json:"tempBytesReclaimed"`
+	ArchivesRemoved       int64      `json:"archivesRemoved"`
+	ArchiveBytesReclaimed int64      `json:"archiveBytesReclaimed"`
+	AppsPurged            int64      `json:"appsPurged"`
+	// IncompleteVersionsDeleted are the app versions that were left half created by a failure
+	IncompleteVersionsDeleted int64 `json:"incompleteVersionsDeleted"`
+	// SessionsExpired are the sessions that were deleted after their lifetime or idle timeout passed
+	SessionsExpired int64 `json:"sessionsExpired"`
+	// StaleTasksCleared are the running task statuses that were left behind by a crash
+	StaleTasksCleared int64 `json:"staleTasksCleared"`
+}
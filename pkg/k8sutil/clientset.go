@@ -19,6 +19,11 @@ func AddFlags(flags *flag.FlagSet) {
 	kubernetesConfigFlags.AddFlags(flags)
 }
 
+// GetKubeConfigFlags returns the kubernetes config flags so they can be used as a rest client getter
+func GetKubeConfigFlags() *genericclioptions.ConfigFlags {
+	return kubernetesConfigFlags
+}
+
 func GetClientset() (*kubernetes.Clientset, error) {
 	cfg, err := GetClusterConfig()
 	if err != nil {
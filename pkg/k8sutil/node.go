@@ -0,0 +1,32 @@
+package k8sutil
+
+import (
+	"context"
+	"sort"
+
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// GetNodeArchitectures returns the distinct cpu architectures of the nodes in the cluster, such as amd64 and arm64
+func GetNodeArchitectures(clientset kubernetes.Interface) ([]string, error) {
+	nodes, err := clientset.CoreV1().Nodes().List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list nodes")
+	}
+
+	found := map[string]bool{}
+	architectures := []string{}
+	for _, node := range nodes.Items {
+		arch := node.Status.NodeInfo.Architecture
+		if arch == "" || found[arch] {
+			continue
+		}
+		found[arch] = true
+		architectures = append(architectures, arch)
+	}
+	sort.Strings(architectures)
+
+	return architectures, nil
+}
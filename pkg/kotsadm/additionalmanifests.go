@@ -0,0 +1,44 @@
+package kotsadm
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	kotsadmobjects "github.com/replicatedhq/kots/pkg/kotsadm/objects"
+	"github.com/replicatedhq/kots/pkg/kotsadm/types"
+	corev1 "k8s.io/api/core/v1"
+	kuberneteserrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+func ensureAdditionalManifestsSecret(deployOptions *types.DeployOptions, clientset *kubernetes.Clientset) (bool, error) {
+	existingSecret, err := getAdditionalManifestsSecret(deployOptions.Namespace, clientset)
+	if err != nil {
+		return false, errors.Wrap(err, "failed to check for existing additional manifests secret")
+	}
+
+	if existingSecret != nil {
+		return false, nil
+	}
+
+	_, err = clientset.CoreV1().Secrets(deployOptions.Namespace).Create(context.TODO(), kotsadmobjects.AdditionalManifestsSecret(deployOptions.Namespace, deployOptions.AdditionalManifests), metav1.CreateOptions{})
+	if err != nil {
+		return false, errors.Wrap(err, "failed to create additional manifests secret")
+	}
+
+	return true, nil
+}
+
+func getAdditionalManifestsSecret(namespace string, clientset *kubernetes.Clientset) (*corev1.Secret, error) {
+	additionalManifestsSecret, err := clientset.CoreV1().Secrets(namespace).Get(context.TODO(), "kotsadm-default-additional-manifests", metav1.GetOptions{})
+	if err != nil {
+		if kuberneteserrors.IsNotFound(err) {
+			return nil, nil
+		}
+
+		return nil, errors.Wrap(err, "failed to get additional manifests secret from cluster")
+	}
+
+	return additionalManifestsSecret, nil
+}
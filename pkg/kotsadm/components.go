@@ -0,0 +1,254 @@
+package kotsadm
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/replicatedhq/kots/pkg/kotsadm/types"
+	"github.com/replicatedhq/kots/pkg/objectstore"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	kuberneteserrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// RemoveComponent removes a component of the admin console that the installation does not need. The embedded
+// object stores can only be removed once the admin console no longer stores its files in them, and their volumes
+// are kept unless deleteData is set. The component is recorded as removed so that upgrades do not deploy it again.
+func RemoveComponent(ctx context.Context, clientset kubernetes.Interface, namespace string, component types.Component, deleteData bool) error {
+	deployment, err := clientset.AppsV1().Deployments(namespace).Get(ctx, "kotsadm", metav1.GetOptions{})
+	if err != nil {
+		return errors.Wrap(err, "failed to get kotsadm deployment")
+	}
+
+	if err := checkComponentRemovable(deployment, component); err != nil {
+		return err
+	}
+
+	switch component {
+	case types.ComponentUI:
+		err = updateDeploymentPodSpec(ctx, clientset, namespace, "kotsadm", func(podSpec *corev1.PodSpec) error {
+			return setKotsadmContainerEnv(podSpec, "DISABLE_SPA_SERVING", "1")
+		})
+		if err != nil {
+			return errors.Wrap(err, "failed to disable ui")
+		}
+
+	case types.ComponentMinio:
+		if err := removeStatefulSetComponent(ctx, clientset, namespace, "kotsadm-minio", deleteData); err != nil {
+			return errors.Wrap(err, "failed to remove minio")
+		}
+		err := clientset.NetworkingV1().NetworkPolicies(namespace).Delete(ctx, "kotsadm-minio", metav1.DeleteOptions{})
+		if err != nil && !kuberneteserrors.IsNotFound(err) {
+			return errors.Wrap(err, "failed to delete minio network policy")
+		}
+
+	case types.ComponentRegistry:
+		if err := removeStatefulSetComponent(ctx, clientset, namespace, "kotsadm-storage-registry", deleteData); err != nil {
+			return errors.Wrap(err, "failed to remove registry")
+		}
+		err := clientset.CoreV1().ConfigMaps(namespace).Delete(ctx, "kotsadm-storage-registry-config", metav1.DeleteOptions{})
+		if err != nil && !kuberneteserrors.IsNotFound(err) {
+			return errors.Wrap(err, "failed to delete registry config map")
+		}
+	}
+
+	if err := setComponentRemoved(ctx, clientset, namespace, component, true); err != nil {
+		return errors.Wrap(err, "failed to record removed component")
+	}
+
+	return nil
+}
+
+// RestoreComponent undoes the removal of a component. The ui is served again right away, the embedded object
+// stores are deployed again by the next upgrade of the admin console.
+func RestoreComponent(ctx context.Context, clientset kubernetes.Interface, namespace string, component types.Component) error {
+	if component == types.ComponentUI {
+		err := updateDeploymentPodSpec(ctx, clientset, namespace, "kotsadm", func(podSpec *corev1.PodSpec) error {
+			return setKotsadmContainerEnv(podSpec, "DISABLE_SPA_SERVING", "")
+		})
+		if err != nil {
+			return errors.Wrap(err, "failed to enable ui")
+		}
+	}
+
+	if err := setComponentRemoved(ctx, clientset, namespace, component, false); err != nil {
+		return errors.Wrap(err, "failed to record restored component")
+	}
+
+	return nil
+}
+
+// GetRemovedComponents returns the components that were removed from the admin console in the namespace
+func GetRemovedComponents(ctx context.Context, clientset kubernetes.Interface, namespace string) ([]types.Component, error) {
+	configMap, err := clientset.CoreV1().ConfigMaps(namespace).Get(ctx, types.KotsadmConfigMap, metav1.GetOptions{})
+	if err != nil {
+		if kuberneteserrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, errors.Wrap(err, "failed to get config map")
+	}
+
+	return parseRemovedComponents(configMap.Data[types.RemovedComponentsKey]), nil
+}
+
+func parseRemovedComponents(value string) []types.Component {
+	components := []types.Component{}
+	for _, name := range strings.Split(value, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			components = append(components, types.Component(name))
+		}
+	}
+	return components
+}
+
+// IsComponentRemoved returns true if the component is in the list of removed components
+func IsComponentRemoved(components []types.Component, component types.Component) bool {
+	for _, c := range components {
+		if c == component {
+			return true
+		}
+	}
+	return false
+}
+
+func setComponentRemoved(ctx context.Context, clientset kubernetes.Interface, namespace string, component types.Component, removed bool) error {
+	configMap, err := clientset.CoreV1().ConfigMaps(namespace).Get(ctx, types.KotsadmConfigMap, metav1.GetOptions{})
+	if err != nil {
+		return errors.Wrap(err, "failed to get config map")
+	}
+
+	names := []string{}
+	for _, c := range parseRemovedComponents(configMap.Data[types.RemovedComponentsKey]) {
+		if c != component {
+			names = append(names, string(c))
+		}
+	}
+	if removed {
+		names = append(names, string(component))
+	}
+	sort.Strings(names)
+
+	if configMap.Data == nil {
+		configMap.Data = map[string]string{}
+	}
+	if len(names) > 0 {
+		configMap.Data[types.RemovedComponentsKey] = strings.Join(names, ",")
+	} else {
+		delete(configMap.Data, types.RemovedComponentsKey)
+	}
+
+	if _, err := clientset.CoreV1().ConfigMaps(namespace).Update(ctx, configMap, metav1.UpdateOptions{}); err != nil {
+		return errors.Wrap(err, "failed to update config map")
+	}
+
+	return nil
+}
+
+// checkComponentRemovable returns an error if removing the component would lose files that the admin console
+// stores, or is still migrating from, in the component
+func checkComponentRemovable(deployment *appsv1.Deployment, component types.Component) error {
+	if component == types.ComponentUI {
+		return nil
+	}
+
+	storageBaseURI := getKotsadmContainerEnv(deployment, "STORAGE_BASEURI")
+	if isStoredInComponent(storageBaseURI, component) {
+		return errors.Errorf("the admin console stores its files in %s. migrate them to another object store with kots admin-console upgrade --storage-base-uri <uri> --migrate-storage before removing it", component)
+	}
+
+	migrateFromURI := getKotsadmContainerEnv(deployment, "STORAGE_MIGRATE_FROM_BASEURI")
+	if migrateFromURI != "" && isStoredInComponent(migrateFromURI, component) && !isDeploymentRolledOut(deployment) {
+		return errors.Errorf("the admin console is migrating its files from %s. wait for the admin console to be ready before removing it", component)
+	}
+
+	return nil
+}
+
+// isStoredInComponent returns true if the storage base uri is the embedded object store of the component.
+// An empty storage base uri is minio.
+func isStoredInComponent(storageBaseURI string, component types.Component) bool {
+	switch component {
+	case types.ComponentMinio:
+		return !objectstore.IsExternal(storageBaseURI) && !objectstore.IsOCI(storageBaseURI)
+	case types.ComponentRegistry:
+		return objectstore.IsOCI(storageBaseURI) && strings.Contains(storageBaseURI, "kotsadm-storage-registry")
+	}
+	return false
+}
+
+func isDeploymentRolledOut(deployment *appsv1.Deployment) bool {
+	replicas := int32(1)
+	if deployment.Spec.Replicas != nil {
+		replicas = *deployment.Spec.Replicas
+	}
+	return deployment.Status.ObservedGeneration == deployment.Generation &&
+		deployment.Status.UpdatedReplicas == replicas &&
+		deployment.Status.ReadyReplicas == replicas &&
+		deployment.Status.UnavailableReplicas == 0
+}
+
+func getKotsadmContainerEnv(deployment *appsv1.Deployment, name string) string {
+	for _, container := range deployment.Spec.Template.Spec.Containers {
+		if container.Name != "kotsadm" {
+			continue
+		}
+		for _, env := range container.Env {
+			if env.Name == name {
+				return env.Value
+			}
+		}
+	}
+	return ""
+}
+
+// setKotsadmContainerEnv sets an env var of the kotsadm container, or removes it when the value is empty
+func setKotsadmContainerEnv(podSpec *corev1.PodSpec, name string, value string) error {
+	for i, container := range podSpec.Containers {
+		if container.Name != "kotsadm" {
+			continue
+		}
+
+		env := []corev1.EnvVar{}
+		for _, e := range container.Env {
+			if e.Name != name {
+				env = append(env, e)
+			}
+		}
+		if value != "" {
+			env = append(env, corev1.EnvVar{Name: name, Value: value})
+		}
+		podSpec.Containers[i].Env = env
+		return nil
+	}
+
+	return errors.New("failed to find kotsadm container")
+}
+
+// removeStatefulSetComponent deletes the statefulset and service of an embedded component. The volume of its only
+// replica is deleted when deleteData is set.
+func removeStatefulSetComponent(ctx context.Context, clientset kubernetes.Interface, namespace string, name string, deleteData bool) error {
+	err := clientset.AppsV1().StatefulSets(namespace).Delete(ctx, name, metav1.DeleteOptions{})
+	if err != nil && !kuberneteserrors.IsNotFound(err) {
+		return errors.Wrap(err, "failed to delete statefulset")
+	}
+
+	err = clientset.CoreV1().Services(namespace).Delete(ctx, name, metav1.DeleteOptions{})
+	if err != nil && !kuberneteserrors.IsNotFound(err) {
+		return errors.Wrap(err, "failed to delete service")
+	}
+
+	if deleteData {
+		// volume claim templates are named after the statefulset
+		pvcName := name + "-" + name + "-0"
+		err = clientset.CoreV1().PersistentVolumeClaims(namespace).Delete(ctx, pvcName, metav1.DeleteOptions{})
+		if err != nil && !kuberneteserrors.IsNotFound(err) {
+			return errors.Wrap(err, "failed to delete volume claim")
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,118 @@
+package kotsadm
+
+import (
+	"context"
+	"testing"
+
+	"github.com/replicatedhq/kots/pkg/kotsadm/types"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func kotsadmDeploymentWithEnv(env ...corev1.EnvVar) *appsv1.Deployment {
+	return &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "kotsadm", Namespace: "default"},
+		Spec: appsv1.DeploymentSpec{
+			Template: corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{{Name: "kotsadm", Env: env}},
+				},
+			},
+		},
+	}
+}
+
+func Test_checkComponentRemovable(t *testing.T) {
+	migrating := kotsadmDeploymentWithEnv(
+		corev1.EnvVar{Name: "STORAGE_BASEURI", Value: "s3://my-bucket?region=us-east-1"},
+		corev1.EnvVar{Name: "STORAGE_MIGRATE_FROM_BASEURI", Value: "s3://kotsadm-minio:9000/kotsadm"},
+	)
+	migrating.Generation = 2
+	migrated := migrating.DeepCopy()
+	migrated.Status = appsv1.DeploymentStatus{ObservedGeneration: 2, UpdatedReplicas: 1, ReadyReplicas: 1}
+
+	tests := []struct {
+		name       string
+		deployment *appsv1.Deployment
+		component  types.Component
+		wantErr    bool
+	}{
+		{
+			name:       "ui",
+			deployment: kotsadmDeploymentWithEnv(),
+			component:  types.ComponentUI,
+		},
+		{
+			name:       "minio in use",
+			deployment: kotsadmDeploymentWithEnv(),
+			component:  types.ComponentMinio,
+			wantErr:    true,
+		},
+		{
+			name:       "minio while migrating",
+			deployment: migrating,
+			component:  types.ComponentMinio,
+			wantErr:    true,
+		},
+		{
+			name:       "minio after migrating",
+			deployment: migrated,
+			component:  types.ComponentMinio,
+		},
+		{
+			name:       "registry in use",
+			deployment: kotsadmDeploymentWithEnv(corev1.EnvVar{Name: "STORAGE_BASEURI", Value: "docker://kotsadm-storage-registry:5000"}),
+			component:  types.ComponentRegistry,
+			wantErr:    true,
+		},
+		{
+			name:       "registry with minio storage",
+			deployment: kotsadmDeploymentWithEnv(),
+			component:  types.ComponentRegistry,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := checkComponentRemovable(test.deployment, test.component)
+			if test.wantErr {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func Test_RemoveComponentUI(t *testing.T) {
+	ctx := context.Background()
+	clientset := fake.NewSimpleClientset(
+		kotsadmDeploymentWithEnv(corev1.EnvVar{Name: "STORAGE_BASEURI", Value: "s3://my-bucket?region=us-east-1"}),
+		&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: types.KotsadmConfigMap, Namespace: "default"}},
+	)
+
+	err := RemoveComponent(ctx, clientset, "default", types.ComponentUI, false)
+	require.NoError(t, err)
+
+	deployment, err := clientset.AppsV1().Deployments("default").Get(ctx, "kotsadm", metav1.GetOptions{})
+	require.NoError(t, err)
+	require.Equal(t, "1", getKotsadmContainerEnv(deployment, "DISABLE_SPA_SERVING"))
+
+	removed, err := GetRemovedComponents(ctx, clientset, "default")
+	require.NoError(t, err)
+	require.Equal(t, []types.Component{types.ComponentUI}, removed)
+
+	err = RestoreComponent(ctx, clientset, "default", types.ComponentUI)
+	require.NoError(t, err)
+
+	deployment, err = clientset.AppsV1().Deployments("default").Get(ctx, "kotsadm", metav1.GetOptions{})
+	require.NoError(t, err)
+	require.Equal(t, "", getKotsadmContainerEnv(deployment, "DISABLE_SPA_SERVING"))
+	require.Equal(t, "s3://my-bucket?region=us-east-1", getKotsadmContainerEnv(deployment, "STORAGE_BASEURI"))
+
+	removed, err = GetRemovedComponents(ctx, clientset, "default")
+	require.NoError(t, err)
+	require.Empty(t, removed)
+}
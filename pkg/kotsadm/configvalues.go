@@ -39,7 +39,7 @@ func ensureConfigValuesSecret(deployOptions *types.DeployOptions, clientset *kub
 	return true, nil
 }
 
-func getConfigValuesSecret(namespace string, clientset *kubernetes.Clientset) (*corev1.Secret, error) {
+func getConfigValuesSecret(namespace string, clientset kubernetes.Interface) (*corev1.Secret, error) {
 	configValuesSecret, err := clientset.CoreV1().Secrets(namespace).Get(context.TODO(), "kotsadm-default-configvalues", metav1.GetOptions{})
 	if err != nil {
 		if kuberneteserrors.IsNotFound(err) {
@@ -0,0 +1,207 @@
+package kotsadm
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+
+	"github.com/pkg/errors"
+	"github.com/replicatedhq/kots/pkg/kotsadm/types"
+	kotsadmversion "github.com/replicatedhq/kots/pkg/kotsadm/version"
+	corev1 "k8s.io/api/core/v1"
+	kuberneteserrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	serializer "k8s.io/apimachinery/pkg/runtime/serializer/json"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/scheme"
+)
+
+// installOptionsHashAnnotation is set on the kotsadm deployment to the hash of the options of the last install
+const installOptionsHashAnnotation = "kots.io/install-options-hash"
+
+// InstalledAppsLister returns the slugs of the apps that are installed in the running admin console
+type InstalledAppsLister func() ([]string, error)
+
+// IsInstallConverged returns true when the admin console and app described by deployOptions
+// are already running in the cluster and running install again would not change anything.
+// The returned string describes the first difference that was found. listInstalledApps is only called
+// once the admin console is known to be running.
+func IsInstallConverged(deployOptions types.DeployOptions, clientset kubernetes.Interface, listInstalledApps InstalledAppsLister) (bool, string, error) {
+	if deployOptions.ExcludeAdminConsole {
+		return false, "admin console is excluded", nil
+	}
+
+	if deployOptions.AirgapRootDir != "" || deployOptions.AirgapBundle != "" {
+		return false, "airgap bundle was provided", nil
+	}
+
+	deployment, err := clientset.AppsV1().Deployments(deployOptions.Namespace).Get(context.TODO(), "kotsadm", metav1.GetOptions{})
+	if err != nil {
+		if kuberneteserrors.IsNotFound(err) {
+			return false, "admin console is not installed", nil
+		}
+		return false, "", errors.Wrap(err, "failed to get kotsadm deployment")
+	}
+
+	if deployment.Status.AvailableReplicas == 0 {
+		return false, "admin console is not ready", nil
+	}
+
+	desiredImage := fmt.Sprintf("%s/kotsadm:%s", kotsadmversion.KotsadmRegistry(deployOptions.KotsadmOptions), kotsadmversion.KotsadmTag(deployOptions.KotsadmOptions))
+	imageFound := false
+	for _, container := range deployment.Spec.Template.Spec.Containers {
+		if container.Name == "kotsadm" && container.Image == desiredImage {
+			imageFound = true
+		}
+	}
+	if !imageFound {
+		return false, "admin console image differs", nil
+	}
+
+	passwordSecret, err := getSharedPasswordSecret(deployOptions.Namespace, clientset)
+	if err != nil {
+		return false, "", errors.Wrap(err, "failed to get shared password secret")
+	}
+	if passwordSecret == nil {
+		return false, "admin console password is not set", nil
+	}
+	if deployOptions.ForcePasswordUpdate {
+		return false, "password update was requested", nil
+	}
+
+	service, err := clientset.CoreV1().Services(deployOptions.Namespace).Get(context.TODO(), "kotsadm", metav1.GetOptions{})
+	if err != nil {
+		if kuberneteserrors.IsNotFound(err) {
+			return false, "admin console service does not exist", nil
+		}
+		return false, "", errors.Wrap(err, "failed to get kotsadm service")
+	}
+	desiredServiceType := corev1.ServiceTypeClusterIP
+	if deployOptions.ServiceType != "" {
+		desiredServiceType = corev1.ServiceType(deployOptions.ServiceType)
+	} else if deployOptions.IngressConfig.Spec.Enabled && deployOptions.IngressConfig.Spec.NodePort != nil {
+		desiredServiceType = corev1.ServiceTypeNodePort
+	}
+	if service.Spec.Type != desiredServiceType {
+		return false, "admin console service type differs", nil
+	}
+
+	if deployOptions.License != nil {
+		// the license secret is consumed (deleted) by kotsadm once the app has been installed,
+		// so a present one must match exactly and a missing one is checked against the installed apps.
+		licenseSecret, err := getLicenseSecret(deployOptions.Namespace, clientset)
+		if err != nil {
+			return false, "", errors.Wrap(err, "failed to get license secret")
+		}
+		if licenseSecret != nil {
+			s := serializer.NewYAMLSerializer(serializer.DefaultMetaFactory, scheme.Scheme, scheme.Scheme)
+			var b bytes.Buffer
+			if err := s.Encode(deployOptions.License, &b); err != nil {
+				return false, "", errors.Wrap(err, "failed to encode license")
+			}
+			if !bytes.Equal(licenseSecret.Data["license"], b.Bytes()) {
+				return false, "license differs", nil
+			}
+			return false, "app installation is pending", nil
+		}
+
+		// the secret is also missing if the app was removed, or failed to install
+		installedApps, err := listInstalledApps()
+		if err != nil {
+			return false, "", errors.Wrap(err, "failed to list installed apps")
+		}
+		isInstalled := false
+		for _, slug := range installedApps {
+			if slug == deployOptions.License.Spec.AppSlug {
+				isInstalled = true
+				break
+			}
+		}
+		if !isInstalled {
+			return false, "app is not installed", nil
+		}
+	}
+
+	if deployOptions.ConfigValues != nil {
+		configValuesSecret, err := getConfigValuesSecret(deployOptions.Namespace, clientset)
+		if err != nil {
+			return false, "", errors.Wrap(err, "failed to get config values secret")
+		}
+		if configValuesSecret != nil {
+			return false, "app configuration is pending", nil
+		}
+	}
+
+	// the license and config values secrets are consumed by kotsadm, so changes to the config values and all
+	// other options that are not checked above are found by comparing with the options of the last install
+	optionsHash, err := InstallOptionsHash(deployOptions)
+	if err != nil {
+		return false, "", errors.Wrap(err, "failed to hash install options")
+	}
+	if deployment.Annotations[installOptionsHashAnnotation] != optionsHash {
+		return false, "install options differ from the last install", nil
+	}
+
+	return true, "", nil
+}
+
+// InstallOptionsHash returns the hash of the deploy options that install applies. The credentials are excluded
+// since they are generated by every install and never replace existing ones, a password update is requested
+// with ForcePasswordUpdate. The hash must be taken before Deploy, which fills in options from the cluster.
+func InstallOptionsHash(deployOptions types.DeployOptions) (string, error) {
+	options := deployOptions
+	options.Context = ""
+	options.SharedPassword = ""
+	options.SharedPasswordBcrypt = ""
+	options.S3AccessKey = ""
+	options.S3SecretKey = ""
+	options.JWT = ""
+	options.PostgresPassword = ""
+	options.APIEncryptionKey = ""
+	options.AutoCreateClusterToken = ""
+	options.ProgressWriter = nil
+	options.Timeout = 0
+	options.InstallID = ""
+	options.AirgapRootDir = ""
+	options.ForcePasswordUpdate = false
+
+	b, err := json.Marshal(options)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to marshal options")
+	}
+
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// setInstallOptionsHash records the hash of the options of an install on the kotsadm deployment
+func setInstallOptionsHash(namespace string, optionsHash string, clientset kubernetes.Interface) error {
+	deployment, err := clientset.AppsV1().Deployments(namespace).Get(context.TODO(), "kotsadm", metav1.GetOptions{})
+	if err != nil {
+		return errors.Wrap(err, "failed to get kotsadm deployment")
+	}
+
+	if deployment.Annotations == nil {
+		deployment.Annotations = map[string]string{}
+	}
+	deployment.Annotations[installOptionsHashAnnotation] = optionsHash
+
+	if _, err := clientset.AppsV1().Deployments(namespace).Update(context.TODO(), deployment, metav1.UpdateOptions{}); err != nil {
+		return errors.Wrap(err, "failed to update kotsadm deployment")
+	}
+
+	return nil
+}
+
+// IsSharedPasswordSet returns true if the admin console password has been set. An existing password is only
+// replaced when the password update is forced.
+func IsSharedPasswordSet(namespace string, clientset kubernetes.Interface) (bool, error) {
+	passwordSecret, err := getSharedPasswordSecret(namespace, clientset)
+	if err != nil {
+		return false, errors.Wrap(err, "failed to get shared password secret")
+	}
+	return passwordSecret != nil, nil
+}
@@ -0,0 +1,213 @@
+package kotsadm
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/pkg/errors"
+	kotsv1beta1 "github.com/replicatedhq/kots/kotskinds/apis/kots/v1beta1"
+	"github.com/replicatedhq/kots/pkg/kotsadm/types"
+	kotsadmversion "github.com/replicatedhq/kots/pkg/kotsadm/version"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func Test_IsInstallConverged(t *testing.T) {
+	deployment := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "kotsadm", Namespace: "default"},
+		Spec: appsv1.DeploymentSpec{
+			Template: corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{
+						{
+							Name:  "kotsadm",
+							Image: fmt.Sprintf("%s/kotsadm:%s", kotsadmversion.KotsadmRegistry(types.KotsadmOptions{}), kotsadmversion.KotsadmTag(types.KotsadmOptions{})),
+						},
+					},
+				},
+			},
+		},
+		Status: appsv1.DeploymentStatus{AvailableReplicas: 1},
+	}
+	passwordSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "kotsadm-password", Namespace: "default"},
+	}
+	service := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{Name: "kotsadm", Namespace: "default"},
+		Spec:       corev1.ServiceSpec{Type: corev1.ServiceTypeClusterIP},
+	}
+	licenseSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "kotsadm-default-license", Namespace: "default"},
+		Data:       map[string][]byte{"license": []byte("another license")},
+	}
+	license := &kotsv1beta1.License{
+		Spec: kotsv1beta1.LicenseSpec{AppSlug: "my-app"},
+	}
+	configValues := func(value string) *kotsv1beta1.ConfigValues {
+		return &kotsv1beta1.ConfigValues{
+			Spec: kotsv1beta1.ConfigValuesSpec{
+				Values: map[string]kotsv1beta1.ConfigValue{"hostname": {Value: value}},
+			},
+		}
+	}
+
+	installedApps := func(slugs ...string) InstalledAppsLister {
+		return func() ([]string, error) {
+			return slugs, nil
+		}
+	}
+
+	tests := []struct {
+		name              string
+		objects           []runtime.Object
+		deployOptions     types.DeployOptions
+		lastInstall       *types.DeployOptions // defaults to deployOptions
+		listInstalledApps InstalledAppsLister
+		wantConverged     bool
+		wantReason        string
+		wantErr           bool
+	}{
+		{
+			name:          "admin console is not installed",
+			objects:       []runtime.Object{},
+			deployOptions: types.DeployOptions{Namespace: "default", License: license},
+			listInstalledApps: func() ([]string, error) {
+				return nil, errors.New("the admin console is not running")
+			},
+			wantConverged: false,
+			wantReason:    "admin console is not installed",
+		},
+		{
+			name:              "admin console without app",
+			objects:           []runtime.Object{deployment, passwordSecret, service},
+			deployOptions:     types.DeployOptions{Namespace: "default"},
+			listInstalledApps: installedApps(),
+			wantConverged:     true,
+		},
+		{
+			name:              "app is installed",
+			objects:           []runtime.Object{deployment, passwordSecret, service},
+			deployOptions:     types.DeployOptions{Namespace: "default", License: license},
+			listInstalledApps: installedApps("other-app", "my-app"),
+			wantConverged:     true,
+		},
+		{
+			name:              "app is not installed",
+			objects:           []runtime.Object{deployment, passwordSecret, service},
+			deployOptions:     types.DeployOptions{Namespace: "default", License: license},
+			listInstalledApps: installedApps("other-app"),
+			wantConverged:     false,
+			wantReason:        "app is not installed",
+		},
+		{
+			name:              "license differs",
+			objects:           []runtime.Object{deployment, passwordSecret, service, licenseSecret},
+			deployOptions:     types.DeployOptions{Namespace: "default", License: license},
+			listInstalledApps: installedApps("my-app"),
+			wantConverged:     false,
+			wantReason:        "license differs",
+		},
+		{
+			name:              "password update was requested",
+			objects:           []runtime.Object{deployment, passwordSecret, service},
+			deployOptions:     types.DeployOptions{Namespace: "default", License: license, ForcePasswordUpdate: true},
+			listInstalledApps: installedApps("my-app"),
+			wantConverged:     false,
+			wantReason:        "password update was requested",
+		},
+		{
+			name:              "service type differs",
+			objects:           []runtime.Object{deployment, passwordSecret, service},
+			deployOptions:     types.DeployOptions{Namespace: "default", ServiceType: "NodePort"},
+			listInstalledApps: installedApps(),
+			wantConverged:     false,
+			wantReason:        "admin console service type differs",
+		},
+		{
+			name:              "credentials are generated again",
+			objects:           []runtime.Object{deployment, passwordSecret, service},
+			deployOptions:     types.DeployOptions{Namespace: "default", License: license},
+			lastInstall:       &types.DeployOptions{Namespace: "default", License: license, JWT: "jwt", PostgresPassword: "password"},
+			listInstalledApps: installedApps("my-app"),
+			wantConverged:     true,
+		},
+		{
+			name:              "config values differ",
+			objects:           []runtime.Object{deployment, passwordSecret, service},
+			deployOptions:     types.DeployOptions{Namespace: "default", License: license, ConfigValues: configValues("new.example.com")},
+			lastInstall:       &types.DeployOptions{Namespace: "default", License: license, ConfigValues: configValues("example.com")},
+			listInstalledApps: installedApps("my-app"),
+			wantConverged:     false,
+			wantReason:        "install options differ from the last install",
+		},
+		{
+			name:              "other options differ",
+			objects:           []runtime.Object{deployment, passwordSecret, service},
+			deployOptions:     types.DeployOptions{Namespace: "default", HTTPProxyEnvValue: "http://proxy:3128"},
+			lastInstall:       &types.DeployOptions{Namespace: "default"},
+			listInstalledApps: installedApps(),
+			wantConverged:     false,
+			wantReason:        "install options differ from the last install",
+		},
+		{
+			name:          "installed apps can't be listed",
+			objects:       []runtime.Object{deployment, passwordSecret, service},
+			deployOptions: types.DeployOptions{Namespace: "default", License: license},
+			listInstalledApps: func() ([]string, error) {
+				return nil, errors.New("connection refused")
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			req := require.New(t)
+
+			lastInstall := test.deployOptions
+			if test.lastInstall != nil {
+				lastInstall = *test.lastInstall
+			}
+			optionsHash, err := InstallOptionsHash(lastInstall)
+			req.NoError(err)
+
+			objects := []runtime.Object{}
+			for _, obj := range test.objects {
+				if d, ok := obj.(*appsv1.Deployment); ok {
+					d = d.DeepCopy()
+					d.Annotations = map[string]string{installOptionsHashAnnotation: optionsHash}
+					obj = d
+				}
+				objects = append(objects, obj)
+			}
+
+			clientset := fake.NewSimpleClientset(objects...)
+			converged, reason, err := IsInstallConverged(test.deployOptions, clientset, test.listInstalledApps)
+			if test.wantErr {
+				req.Error(err)
+				return
+			}
+			req.NoError(err)
+			req.Equal(test.wantConverged, converged)
+			req.Equal(test.wantReason, reason)
+		})
+	}
+}
+
+func Test_IsSharedPasswordSet(t *testing.T) {
+	req := require.New(t)
+
+	isSet, err := IsSharedPasswordSet("default", fake.NewSimpleClientset())
+	req.NoError(err)
+	req.False(isSet)
+
+	isSet, err = IsSharedPasswordSet("default", fake.NewSimpleClientset(&corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "kotsadm-password", Namespace: "default"},
+	}))
+	req.NoError(err)
+	req.True(isSet)
+}
@@ -0,0 +1,68 @@
+package kotsadm
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	"github.com/replicatedhq/kots/kotskinds/config/crds"
+	"github.com/replicatedhq/kots/pkg/k8sutil"
+	"github.com/replicatedhq/kots/pkg/logger"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apiextensionsclientset "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset"
+	kuberneteserrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/yaml"
+)
+
+// ensureKotsAppCRD installs the KotsApp definition, so that apps can be installed by creating KotsApp resources in
+// the kotsadm namespace. The definition is cluster scoped, a user that can't create it is warned and the admin
+// console is installed without it.
+func ensureKotsAppCRD(log *logger.CLILogger) error {
+	crd := apiextensionsv1.CustomResourceDefinition{}
+	if err := yaml.Unmarshal(crds.KotsApp, &crd); err != nil {
+		return errors.Wrap(err, "failed to unmarshal crd")
+	}
+
+	cfg, err := k8sutil.GetClusterConfig()
+	if err != nil {
+		return errors.Wrap(err, "failed to get cluster config")
+	}
+
+	clientset, err := apiextensionsclientset.NewForConfig(cfg)
+	if err != nil {
+		return errors.Wrap(err, "failed to create apiextensions clientset")
+	}
+
+	existing, err := clientset.ApiextensionsV1().CustomResourceDefinitions().Get(context.TODO(), crd.Name, metav1.GetOptions{})
+	if err != nil {
+		if !kuberneteserrors.IsNotFound(err) {
+			if kuberneteserrors.IsForbidden(err) {
+				log.Info("Not installing the KotsApp custom resource definition, the user is not allowed to get it")
+				return nil
+			}
+			return errors.Wrap(err, "failed to get existing crd")
+		}
+
+		_, err := clientset.ApiextensionsV1().CustomResourceDefinitions().Create(context.TODO(), &crd, metav1.CreateOptions{})
+		if kuberneteserrors.IsForbidden(err) {
+			log.Info("Not installing the KotsApp custom resource definition, the user is not allowed to create it")
+			return nil
+		}
+		if err != nil {
+			return errors.Wrap(err, "failed to create crd")
+		}
+		return nil
+	}
+
+	existing.Spec = crd.Spec
+	_, err = clientset.ApiextensionsV1().CustomResourceDefinitions().Update(context.TODO(), existing, metav1.UpdateOptions{})
+	if kuberneteserrors.IsForbidden(err) {
+		log.Info("Not updating the KotsApp custom resource definition, the user is not allowed to update it")
+		return nil
+	}
+	if err != nil {
+		return errors.Wrap(err, "failed to update crd")
+	}
+
+	return nil
+}
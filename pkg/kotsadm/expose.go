@@ -0,0 +1,289 @@
+package kotsadm
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	kotsv1beta1 "github.com/replicatedhq/kots/kotskinds/apis/kots/v1beta1"
+	"github.com/replicatedhq/kots/pkg/ingress"
+	"github.com/replicatedhq/kots/pkg/kotsadm/types"
+	corev1 "k8s.io/api/core/v1"
+	kuberneteserrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// ExposeTLSSecretName is the secret created from the certificate and key passed to Expose
+const ExposeTLSSecretName = "kotsadm-expose-tls"
+
+type ExposeType string
+
+const (
+	ExposeIngress      ExposeType = "Ingress"
+	ExposeLoadBalancer ExposeType = "LoadBalancer"
+	ExposeNodePort     ExposeType = "NodePort"
+)
+
+type ExposeOptions struct {
+	Namespace string
+	Type      ExposeType
+	// Hostname and Path are the rule of the ingress. The address of the ingress controller is used if no hostname is set.
+	Hostname    string
+	Path        string
+	Annotations map[string]string
+	// TLSSecretName is the secret with the certificate of the ingress. It is created from TLSCert and TLSKey if they are set.
+	TLSSecretName string
+	TLSCert       []byte
+	TLSKey        []byte
+	// NodePort is the port of the service on the nodes, a free port is assigned if it is 0
+	NodePort int
+	// Timeout is how long to wait for the load balancer address
+	Timeout time.Duration
+}
+
+// Expose makes the admin console reachable without a port forward through an ingress, a load balancer or a node port
+// on the kotsadm service. The settings are saved to the ingress config so that upgrades keep them. It returns the url
+// of the admin console.
+func Expose(ctx context.Context, clientset *kubernetes.Clientset, opts ExposeOptions) (string, error) {
+	if len(opts.TLSCert) > 0 || len(opts.TLSKey) > 0 {
+		if err := ensureExposeTLSSecret(ctx, clientset, opts.Namespace, opts.TLSCert, opts.TLSKey); err != nil {
+			return "", errors.Wrap(err, "failed to ensure tls secret")
+		}
+		opts.TLSSecretName = ExposeTLSSecretName
+	}
+
+	ingressConfig := exposeIngressConfig(opts)
+	if err := ingress.SetConfig(ctx, opts.Namespace, ingressConfig); err != nil {
+		return "", errors.Wrap(err, "failed to set ingress config")
+	}
+
+	if err := EnsureIngress(ctx, opts.Namespace, clientset, ingressConfig.Spec); err != nil {
+		return "", errors.Wrap(err, "failed to ensure ingress")
+	}
+
+	serviceType := string(corev1.ServiceTypeClusterIP)
+	if opts.Type != ExposeIngress {
+		serviceType = string(opts.Type)
+	}
+	if err := ensureKotsadmService(opts.Namespace, clientset, int32(opts.NodePort), serviceType); err != nil {
+		return "", errors.Wrap(err, "failed to ensure kotsadm service")
+	}
+
+	return waitForExposedURL(ctx, clientset, opts)
+}
+
+// Unexpose removes the ingress and changes the kotsadm service back to ClusterIP, so that the admin console is only
+// reachable with a port forward
+func Unexpose(ctx context.Context, clientset *kubernetes.Clientset, namespace string) error {
+	if err := ingress.SetConfig(ctx, namespace, kotsv1beta1.IngressConfig{}); err != nil {
+		return errors.Wrap(err, "failed to set ingress config")
+	}
+
+	if err := DeleteIngress(ctx, namespace, clientset); err != nil {
+		return errors.Wrap(err, "failed to delete ingress")
+	}
+
+	if err := ensureKotsadmService(namespace, clientset, 0, string(corev1.ServiceTypeClusterIP)); err != nil {
+		return errors.Wrap(err, "failed to ensure kotsadm service")
+	}
+
+	err := clientset.CoreV1().Secrets(namespace).Delete(ctx, ExposeTLSSecretName, metav1.DeleteOptions{})
+	if err != nil && !kuberneteserrors.IsNotFound(err) {
+		return errors.Wrap(err, "failed to delete tls secret")
+	}
+
+	return nil
+}
+
+func exposeIngressConfig(opts ExposeOptions) kotsv1beta1.IngressConfig {
+	ingressConfig := kotsv1beta1.IngressConfig{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "kots.io/v1beta1",
+			Kind:       "IngressConfig",
+		},
+		Spec: kotsv1beta1.IngressConfigSpec{
+			Enabled: true,
+		},
+	}
+
+	switch opts.Type {
+	case ExposeIngress:
+		path := opts.Path
+		if path == "" {
+			path = "/"
+		}
+		ingressConfig.Spec.Ingress = &kotsv1beta1.IngressResourceConfig{
+			Host:          opts.Hostname,
+			Path:          path,
+			TLSSecretName: opts.TLSSecretName,
+			Annotations:   opts.Annotations,
+		}
+	case ExposeNodePort:
+		ingressConfig.Spec.NodePort = &kotsv1beta1.IngressNodePortConfig{
+			Port: opts.NodePort,
+		}
+	}
+
+	return ingressConfig
+}
+
+func ensureExposeTLSSecret(ctx context.Context, clientset kubernetes.Interface, namespace string, cert []byte, key []byte) error {
+	if len(cert) == 0 || len(key) == 0 {
+		return errors.New("both a certificate and a key are required")
+	}
+
+	secret := &corev1.Secret{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "v1",
+			Kind:       "Secret",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      ExposeTLSSecretName,
+			Namespace: namespace,
+			Labels:    types.GetKotsadmLabels(),
+		},
+		Type: corev1.SecretTypeTLS,
+		Data: map[string][]byte{
+			corev1.TLSCertKey:       cert,
+			corev1.TLSPrivateKeyKey: key,
+		},
+	}
+
+	existing, err := clientset.CoreV1().Secrets(namespace).Get(ctx, ExposeTLSSecretName, metav1.GetOptions{})
+	if err != nil {
+		if !kuberneteserrors.IsNotFound(err) {
+			return errors.Wrap(err, "failed to get existing secret")
+		}
+		if _, err := clientset.CoreV1().Secrets(namespace).Create(ctx, secret, metav1.CreateOptions{}); err != nil {
+			return errors.Wrap(err, "failed to create secret")
+		}
+		return nil
+	}
+
+	existing.Data = secret.Data
+	if _, err := clientset.CoreV1().Secrets(namespace).Update(ctx, existing, metav1.UpdateOptions{}); err != nil {
+		return errors.Wrap(err, "failed to update secret")
+	}
+	return nil
+}
+
+// waitForExposedURL returns the url of the admin console once the address of the load balancer or ingress is known
+func waitForExposedURL(ctx context.Context, clientset kubernetes.Interface, opts ExposeOptions) (string, error) {
+	if opts.Type == ExposeIngress && opts.Hostname != "" {
+		return ingress.GetAddress(exposeIngressConfig(opts).Spec), nil
+	}
+
+	timeout := opts.Timeout
+	if timeout == 0 {
+		timeout = 2 * time.Minute
+	}
+	deadline := time.Now().Add(timeout)
+
+	for {
+		address, err := exposedURL(ctx, clientset, opts)
+		if err != nil {
+			return "", err
+		}
+		if address != "" {
+			return address, nil
+		}
+		if time.Now().After(deadline) {
+			return "", errors.Errorf("timed out waiting for the address of the %s", strings.ToLower(string(opts.Type)))
+		}
+		time.Sleep(2 * time.Second)
+	}
+}
+
+// exposedURL returns the url of the admin console, or an empty string if the address is not assigned yet
+func exposedURL(ctx context.Context, clientset kubernetes.Interface, opts ExposeOptions) (string, error) {
+	switch opts.Type {
+	case ExposeIngress:
+		kotsadmIngress, err := clientset.ExtensionsV1beta1().Ingresses(opts.Namespace).Get(ctx, "kotsadm", metav1.GetOptions{})
+		if err != nil {
+			return "", errors.Wrap(err, "failed to get ingress")
+		}
+		host := loadBalancerHost(kotsadmIngress.Status.LoadBalancer)
+		if host == "" {
+			return "", nil
+		}
+		scheme := "http"
+		if opts.TLSSecretName != "" || len(opts.TLSCert) > 0 {
+			scheme = "https"
+		}
+		return formatURL(scheme, host, opts.Path), nil
+
+	case ExposeLoadBalancer:
+		service, err := clientset.CoreV1().Services(opts.Namespace).Get(ctx, "kotsadm", metav1.GetOptions{})
+		if err != nil {
+			return "", errors.Wrap(err, "failed to get service")
+		}
+		host := loadBalancerHost(service.Status.LoadBalancer)
+		if host == "" || len(service.Spec.Ports) == 0 {
+			return "", nil
+		}
+		return formatURL("http", net.JoinHostPort(host, fmt.Sprintf("%d", service.Spec.Ports[0].Port)), ""), nil
+
+	case ExposeNodePort:
+		service, err := clientset.CoreV1().Services(opts.Namespace).Get(ctx, "kotsadm", metav1.GetOptions{})
+		if err != nil {
+			return "", errors.Wrap(err, "failed to get service")
+		}
+		if len(service.Spec.Ports) == 0 || service.Spec.Ports[0].NodePort == 0 {
+			return "", nil
+		}
+		nodes, err := clientset.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return "", errors.Wrap(err, "failed to list nodes")
+		}
+		host := ""
+		for _, node := range nodes.Items {
+			if host = nodeAddress(node); host != "" {
+				break
+			}
+		}
+		if host == "" {
+			return "", errors.New("no node has an address")
+		}
+		return formatURL("http", net.JoinHostPort(host, fmt.Sprintf("%d", service.Spec.Ports[0].NodePort)), ""), nil
+	}
+
+	return "", errors.Errorf("unknown expose type %q", opts.Type)
+}
+
+func loadBalancerHost(status corev1.LoadBalancerStatus) string {
+	for _, lbIngress := range status.Ingress {
+		if lbIngress.Hostname != "" {
+			return lbIngress.Hostname
+		}
+		if lbIngress.IP != "" {
+			return lbIngress.IP
+		}
+	}
+	return ""
+}
+
+// nodeAddress prefers the external address of the node, nodes in private networks only have an internal one
+func nodeAddress(node corev1.Node) string {
+	for _, addressType := range []corev1.NodeAddressType{corev1.NodeExternalIP, corev1.NodeInternalIP} {
+		for _, address := range node.Status.Addresses {
+			if address.Type == addressType && address.Address != "" {
+				return address.Address
+			}
+		}
+	}
+	return ""
+}
+
+func formatURL(scheme string, host string, path string) string {
+	u := url.URL{
+		Scheme: scheme,
+		Host:   host,
+		Path:   path,
+	}
+	return strings.TrimRight(u.String(), "/")
+}
@@ -0,0 +1,103 @@
+package kotsadm
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	extensionsv1beta1 "k8s.io/api/extensions/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func Test_exposedURL(t *testing.T) {
+	service := func(serviceType corev1.ServiceType, nodePort int32, lbIngress ...corev1.LoadBalancerIngress) *corev1.Service {
+		return &corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{Name: "kotsadm", Namespace: "default"},
+			Spec: corev1.ServiceSpec{
+				Type:  serviceType,
+				Ports: []corev1.ServicePort{{Port: 3000, NodePort: nodePort}},
+			},
+			Status: corev1.ServiceStatus{
+				LoadBalancer: corev1.LoadBalancerStatus{Ingress: lbIngress},
+			},
+		}
+	}
+	node := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: "node-1"},
+		Status: corev1.NodeStatus{
+			Addresses: []corev1.NodeAddress{
+				{Type: corev1.NodeInternalIP, Address: "10.0.0.5"},
+				{Type: corev1.NodeExternalIP, Address: "203.0.113.5"},
+			},
+		},
+	}
+
+	tests := []struct {
+		name    string
+		objects []runtime.Object
+		opts    ExposeOptions
+		want    string
+	}{
+		{
+			name:    "load balancer pending",
+			objects: []runtime.Object{service(corev1.ServiceTypeLoadBalancer, 0)},
+			opts:    ExposeOptions{Namespace: "default", Type: ExposeLoadBalancer},
+			want:    "",
+		},
+		{
+			name:    "load balancer with ip",
+			objects: []runtime.Object{service(corev1.ServiceTypeLoadBalancer, 0, corev1.LoadBalancerIngress{IP: "198.51.100.7"})},
+			opts:    ExposeOptions{Namespace: "default", Type: ExposeLoadBalancer},
+			want:    "http://198.51.100.7:3000",
+		},
+		{
+			name:    "node port prefers the external ip",
+			objects: []runtime.Object{service(corev1.ServiceTypeNodePort, 30880), node},
+			opts:    ExposeOptions{Namespace: "default", Type: ExposeNodePort},
+			want:    "http://203.0.113.5:30880",
+		},
+		{
+			name: "ingress with tls",
+			objects: []runtime.Object{&extensionsv1beta1.Ingress{
+				ObjectMeta: metav1.ObjectMeta{Name: "kotsadm", Namespace: "default"},
+				Status: extensionsv1beta1.IngressStatus{
+					LoadBalancer: corev1.LoadBalancerStatus{
+						Ingress: []corev1.LoadBalancerIngress{{Hostname: "lb.example.com"}},
+					},
+				},
+			}},
+			opts: ExposeOptions{Namespace: "default", Type: ExposeIngress, Path: "/admin", TLSSecretName: "tls"},
+			want: "https://lb.example.com/admin",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			clientset := fake.NewSimpleClientset(test.objects...)
+			got, err := exposedURL(context.Background(), clientset, test.opts)
+			require.NoError(t, err)
+			require.Equal(t, test.want, got)
+		})
+	}
+}
+
+func Test_exposeIngressConfig(t *testing.T) {
+	config := exposeIngressConfig(ExposeOptions{Type: ExposeIngress, Hostname: "admin.example.com", TLSSecretName: "tls"})
+	require.True(t, config.Spec.Enabled)
+	require.Nil(t, config.Spec.NodePort)
+	require.Equal(t, "admin.example.com", config.Spec.Ingress.Host)
+	require.Equal(t, "/", config.Spec.Ingress.Path)
+	require.Equal(t, "tls", config.Spec.Ingress.TLSSecretName)
+
+	config = exposeIngressConfig(ExposeOptions{Type: ExposeNodePort, NodePort: 30880})
+	require.Nil(t, config.Spec.Ingress)
+	require.Equal(t, 30880, config.Spec.NodePort.Port)
+
+	config = exposeIngressConfig(ExposeOptions{Type: ExposeLoadBalancer})
+	require.True(t, config.Spec.Enabled)
+	require.Nil(t, config.Spec.Ingress)
+	require.Nil(t, config.Spec.NodePort)
+}
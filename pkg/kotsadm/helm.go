@@ -0,0 +1,361 @@
+package kotsadm
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"github.com/replicatedhq/kots/pkg/buildversion"
+	"github.com/replicatedhq/kots/pkg/k8sutil"
+	"github.com/replicatedhq/kots/pkg/kotsadm/types"
+	"github.com/replicatedhq/kots/pkg/logger"
+	"helm.sh/helm/v3/pkg/action"
+	"helm.sh/helm/v3/pkg/chart/loader"
+	"helm.sh/helm/v3/pkg/storage/driver"
+	corev1 "k8s.io/api/core/v1"
+	kuberneteserrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/serializer/json"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	HelmChartName = "kotsadm"
+
+	// helmDevChartVersion is the chart version of builds that are not versioned with semver, helm refuses to
+	// load charts without a semver version
+	helmDevChartVersion = "0.0.0-dev"
+
+	// helmNamespacePlaceholder is rendered in place of the namespace so that it can be replaced with the release namespace
+	helmNamespacePlaceholder = "kotsadm-helm-release-namespace"
+
+	// helmSecretValuePlaceholder is rendered in place of the data of secrets so that it can be replaced with a
+	// reference to the value
+	helmSecretValuePlaceholder = "__kotsadm_helm_secret_value_%d__"
+)
+
+// HelmChart will return a map containing the files of a helm chart that installs the admin console, and the values
+// to install it with. The templates are generated from the same manifests that Deploy uses. The data of the secrets
+// is read from the values, so that neither the templates nor the values.yaml of the chart contain credentials.
+func HelmChart(deployOptions types.DeployOptions) (map[string][]byte, map[string]interface{}, error) {
+	deployOptions.Namespace = helmNamespacePlaceholder
+
+	if deployOptions.JWT == "" {
+		deployOptions.JWT = uuid.New().String()
+	}
+	if deployOptions.PostgresPassword == "" {
+		deployOptions.PostgresPassword = uuid.New().String()
+	}
+	if deployOptions.AutoCreateClusterToken == "" {
+		deployOptions.AutoCreateClusterToken = uuid.New().String()
+	}
+
+	docs, err := YAML(deployOptions)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "failed to get admin console yaml")
+	}
+
+	if deployOptions.License != nil {
+		licenseDocs, err := getLicenseSecretYAML(&deployOptions)
+		if err != nil {
+			return nil, nil, errors.Wrap(err, "failed to get license secret yaml")
+		}
+		for n, v := range licenseDocs {
+			docs[n] = v
+		}
+	}
+
+	secretValues := map[string]interface{}{}
+	valueReferences := map[string]string{}
+	files := map[string][]byte{}
+	for name, doc := range docs {
+		doc, err := templateHelmSecret(doc, secretValues, valueReferences)
+		if err != nil {
+			return nil, nil, errors.Wrapf(err, "failed to template secret in %s", name)
+		}
+
+		// escape anything that helm would otherwise try to render
+		escaped := strings.ReplaceAll(string(doc), "{{", `{{ "{{" }}`)
+		escaped = strings.ReplaceAll(escaped, helmNamespacePlaceholder, "{{ .Release.Namespace }}")
+		for placeholder, reference := range valueReferences {
+			escaped = strings.ReplaceAll(escaped, placeholder, reference)
+		}
+		files[filepath.Join("templates", name)] = []byte(escaped)
+	}
+
+	files["Chart.yaml"] = []byte(fmt.Sprintf(`apiVersion: v2
+name: %s
+description: The KOTS Admin Console
+type: application
+version: %s
+appVersion: %q
+`, HelmChartName, helmChartVersion(buildversion.Version()), buildversion.Version()))
+	files["values.yaml"] = []byte("# secrets holds the data of the admin console secrets by secret name and key, kots generates them with the chart\nsecrets: {}\n")
+
+	values := map[string]interface{}{
+		"secrets": secretValues,
+	}
+
+	return files, values, nil
+}
+
+// templateHelmSecret moves the data of the secret in doc to secretValues and replaces it with placeholders for
+// the references to the values, which are added to valueReferences. Docs that are not secrets are returned unchanged.
+func templateHelmSecret(doc []byte, secretValues map[string]interface{}, valueReferences map[string]string) ([]byte, error) {
+	obj, gvk, err := scheme.Codecs.UniversalDeserializer().Decode(doc, nil, nil)
+	if err != nil || gvk.Group != "" || gvk.Version != "v1" || gvk.Kind != "Secret" {
+		return doc, nil
+	}
+	secret := obj.(*corev1.Secret)
+
+	data := map[string]interface{}{}
+	placeholders := map[string]string{}
+	for key, value := range secret.Data {
+		data[key] = string(value)
+	}
+	for key, value := range secret.StringData {
+		data[key] = value
+	}
+	for key := range data {
+		placeholder := fmt.Sprintf(helmSecretValuePlaceholder, len(valueReferences))
+		valueReferences[placeholder] = fmt.Sprintf(`{{ index .Values.secrets %q %q | quote }}`, secret.Name, key)
+		placeholders[key] = placeholder
+	}
+	secretValues[secret.Name] = data
+
+	secret.Data = nil
+	secret.StringData = placeholders
+
+	s := json.NewYAMLSerializer(json.DefaultMetaFactory, scheme.Scheme, scheme.Scheme)
+	var b bytes.Buffer
+	if err := s.Encode(secret, &b); err != nil {
+		return nil, errors.Wrap(err, "failed to encode secret")
+	}
+
+	return b.Bytes(), nil
+}
+
+// helmChartVersion returns the chart version of the kots version, or the dev version if it is not semver
+func helmChartVersion(kotsVersion string) string {
+	version, err := semver.StrictNewVersion(strings.TrimPrefix(kotsVersion, "v"))
+	if err != nil {
+		return helmDevChartVersion
+	}
+	return version.String()
+}
+
+// WriteHelmChart writes the admin console helm chart to dir. The values with the generated secrets are written to
+// valuesFile, which is kept out of the chart so that it can be stored like any other credentials.
+func WriteHelmChart(deployOptions types.DeployOptions, dir string, valuesFile string) error {
+	files, values, err := HelmChart(deployOptions)
+	if err != nil {
+		return errors.Wrap(err, "failed to generate helm chart")
+	}
+
+	for name, content := range files {
+		filename := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(filename), 0755); err != nil {
+			return errors.Wrapf(err, "failed to create dir for %s", name)
+		}
+		if err := ioutil.WriteFile(filename, content, 0644); err != nil {
+			return errors.Wrapf(err, "failed to write %s", name)
+		}
+	}
+
+	b, err := yaml.Marshal(values)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal values")
+	}
+	if err := ioutil.WriteFile(valuesFile, b, 0600); err != nil {
+		return errors.Wrap(err, "failed to write values")
+	}
+
+	return nil
+}
+
+// DeployWithHelm installs or upgrades the admin console as a helm release in the namespace from deployOptions
+func DeployWithHelm(deployOptions types.DeployOptions) error {
+	if deployOptions.AirgapRootDir != "" || deployOptions.AirgapBundle != "" {
+		return errors.New("airgap installs are not supported with the helm deploy method")
+	}
+
+	clientset, err := k8sutil.GetClientset()
+	if err != nil {
+		return errors.Wrap(err, "failed to get clientset")
+	}
+
+	log := logger.NewCLILogger()
+
+	log.ChildActionWithSpinner("Creating namespace")
+	namespace := &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: deployOptions.Namespace,
+		},
+	}
+	_, err = clientset.CoreV1().Namespaces().Create(context.TODO(), namespace, metav1.CreateOptions{})
+	if err != nil && !kuberneteserrors.IsAlreadyExists(err) {
+		return errors.Wrap(err, "failed to create namespace")
+	}
+	log.FinishChildSpinner()
+
+	// secrets are generated into the chart, reuse existing values so that upgrading the release doesn't rotate them
+	if err := loadHelmSecretsFromCluster(&deployOptions, clientset); err != nil {
+		return errors.Wrap(err, "failed to load existing secrets")
+	}
+
+	if deployOptions.SharedPasswordBcrypt == "" && deployOptions.SharedPassword == "" {
+		sharedPassword, err := promptForSharedPassword()
+		if err != nil {
+			return errors.Wrap(err, "failed to prompt for shared password")
+		}
+		deployOptions.SharedPassword = sharedPassword
+	}
+
+	// like Deploy, the config values and additional manifests are handed to kotsadm in secrets that it reads on startup
+	restartKotsadmAPI := false
+	if deployOptions.ConfigValues != nil {
+		updated, err := ensureConfigValuesSecret(&deployOptions, clientset)
+		if err != nil {
+			return errors.Wrap(err, "failed to ensure config values secret")
+		}
+		restartKotsadmAPI = restartKotsadmAPI || updated
+	}
+	if len(deployOptions.AdditionalManifests) > 0 {
+		updated, err := ensureAdditionalManifestsSecret(&deployOptions, clientset)
+		if err != nil {
+			return errors.Wrap(err, "failed to ensure additional manifests secret")
+		}
+		restartKotsadmAPI = restartKotsadmAPI || updated
+	}
+
+	// the chart is loaded from memory and the secrets are only passed as values, they are never written to disk
+	files, values, err := HelmChart(deployOptions)
+	if err != nil {
+		return errors.Wrap(err, "failed to generate helm chart")
+	}
+	bufferedFiles := []*loader.BufferedFile{}
+	for name, content := range files {
+		bufferedFiles = append(bufferedFiles, &loader.BufferedFile{Name: name, Data: content})
+	}
+	chart, err := loader.LoadFiles(bufferedFiles)
+	if err != nil {
+		return errors.Wrap(err, "failed to load helm chart")
+	}
+
+	cfg := &action.Configuration{}
+	if err := cfg.Init(k8sutil.GetKubeConfigFlags(), deployOptions.Namespace, "secret", logger.Debugf); err != nil {
+		return errors.Wrap(err, "failed to init helm configuration")
+	}
+
+	log.ChildActionWithSpinner("Installing the Admin Console helm chart")
+	history := action.NewHistory(cfg)
+	history.Max = 1
+	if _, err := history.Run(HelmChartName); err == driver.ErrReleaseNotFound {
+		install := action.NewInstall(cfg)
+		install.ReleaseName = HelmChartName
+		install.Namespace = deployOptions.Namespace
+		install.Wait = true
+		install.Timeout = deployOptions.Timeout
+		if _, err := install.Run(chart, values); err != nil {
+			return errors.Wrap(err, "failed to install helm chart")
+		}
+		// kotsadm was started after the secrets were created
+		restartKotsadmAPI = false
+	} else if err != nil {
+		return errors.Wrap(err, "failed to get helm release history")
+	} else {
+		upgrade := action.NewUpgrade(cfg)
+		upgrade.Namespace = deployOptions.Namespace
+		upgrade.Wait = true
+		upgrade.Timeout = deployOptions.Timeout
+		if _, err := upgrade.Run(HelmChartName, chart, values); err != nil {
+			return errors.Wrap(err, "failed to upgrade helm chart")
+		}
+	}
+	log.FinishChildSpinner()
+
+	if restartKotsadmAPI {
+		log.ChildActionWithSpinner("Waiting for Admin Console to be ready")
+		if err := restartKotsadm(&deployOptions, clientset); err != nil {
+			return errors.Wrap(err, "failed to restart admin console")
+		}
+		if err := k8sutil.WaitForDeploymentReady(context.TODO(), clientset, deployOptions.Namespace, "kotsadm", deployOptions.Timeout); err != nil {
+			return errors.Wrap(err, "failed to wait for admin console")
+		}
+		log.FinishChildSpinner()
+	}
+
+	return nil
+}
+
+func loadHelmSecretsFromCluster(deployOptions *types.DeployOptions, clientset *kubernetes.Clientset) error {
+	if deployOptions.SharedPasswordBcrypt == "" && !deployOptions.ForcePasswordUpdate {
+		secret, err := getSharedPasswordSecret(deployOptions.Namespace, clientset)
+		if err != nil {
+			return errors.Wrap(err, "failed to get shared password secret")
+		}
+		if secret != nil {
+			deployOptions.SharedPasswordBcrypt = string(secret.Data["passwordBcrypt"])
+		}
+	}
+
+	if deployOptions.JWT == "" {
+		secret, err := getJWTSessionSecret(deployOptions.Namespace, clientset)
+		if err != nil {
+			return errors.Wrap(err, "failed to get jwt secret")
+		}
+		if secret != nil {
+			deployOptions.JWT = string(secret.Data["key"])
+		}
+	}
+
+	if deployOptions.PostgresPassword == "" {
+		secret, err := getPostgresSecret(deployOptions.Namespace, clientset)
+		if err != nil {
+			return errors.Wrap(err, "failed to get postgres secret")
+		}
+		if secret != nil {
+			deployOptions.PostgresPassword = string(secret.Data["password"])
+		}
+	}
+
+	if deployOptions.S3AccessKey == "" || deployOptions.S3SecretKey == "" {
+		secret, err := getS3Secret(deployOptions.Namespace, clientset)
+		if err != nil {
+			return errors.Wrap(err, "failed to get s3 secret")
+		}
+		if secret != nil {
+			deployOptions.S3AccessKey = string(secret.Data["accesskey"])
+			deployOptions.S3SecretKey = string(secret.Data["secretkey"])
+		}
+	}
+
+	if deployOptions.APIEncryptionKey == "" {
+		secret, err := getAPIEncryptionSecret(deployOptions.Namespace, clientset)
+		if err != nil {
+			return errors.Wrap(err, "failed to get encryption secret")
+		}
+		if secret != nil {
+			deployOptions.APIEncryptionKey = string(secret.Data["encryptionKey"])
+		}
+	}
+
+	if deployOptions.AutoCreateClusterToken == "" {
+		token, err := getAPIClusterToken(deployOptions.Namespace, clientset)
+		if err != nil {
+			return errors.Wrap(err, "failed to get cluster token")
+		}
+		deployOptions.AutoCreateClusterToken = token
+	}
+
+	return nil
+}
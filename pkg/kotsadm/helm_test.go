@@ -0,0 +1,100 @@
+package kotsadm
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/replicatedhq/kots/pkg/kotsadm/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"helm.sh/helm/v3/pkg/chart/loader"
+	"helm.sh/helm/v3/pkg/chartutil"
+)
+
+func Test_HelmChart(t *testing.T) {
+	req := require.New(t)
+
+	files, values, err := HelmChart(types.DeployOptions{
+		Namespace:        "my-namespace",
+		SharedPassword:   "my-shared-password",
+		PostgresPassword: "my-postgres-password",
+		JWT:              "my-jwt",
+		S3SecretKey:      "my-s3-secret-key",
+		IncludeMinio:     true,
+		EnsureRBAC:       true,
+	})
+	req.NoError(err)
+
+	secrets, ok := values["secrets"].(map[string]interface{})
+	req.True(ok)
+	req.Equal("my-jwt", secrets["kotsadm-session"].(map[string]interface{})["key"])
+	req.Equal("my-postgres-password", secrets["kotsadm-postgres"].(map[string]interface{})["password"])
+	req.Contains(secrets, "kotsadm-password")
+
+	req.Contains(files, "Chart.yaml")
+	req.Contains(files, "values.yaml")
+	assert.Contains(t, files, "templates/kotsadm-deployment.yaml")
+
+	for name, content := range files {
+		assert.NotContains(t, string(content), helmNamespacePlaceholder, name)
+		assert.NotContains(t, string(content), "my-namespace", name)
+		assert.NotContains(t, string(content), "my-shared-password", name)
+		assert.NotContains(t, string(content), "my-postgres-password", name)
+		assert.NotContains(t, string(content), "my-jwt", name)
+		assert.NotContains(t, string(content), "my-s3-secret-key", name)
+		if strings.HasPrefix(name, "templates/") && strings.Contains(string(content), "namespace:") {
+			assert.Contains(t, string(content), "{{ .Release.Namespace }}", name)
+		}
+	}
+}
+
+func Test_WriteHelmChart(t *testing.T) {
+	req := require.New(t)
+
+	dir, err := ioutil.TempDir("", "kotsadm-chart")
+	req.NoError(err)
+	defer os.RemoveAll(dir)
+
+	valuesFile := filepath.Join(dir, "values.yaml")
+	chartDir := filepath.Join(dir, "chart")
+	err = WriteHelmChart(types.DeployOptions{
+		SharedPassword: "password",
+		IncludeMinio:   true,
+		EnsureRBAC:     true,
+	}, chartDir, valuesFile)
+	req.NoError(err)
+
+	values, err := chartutil.ReadValuesFile(valuesFile)
+	req.NoError(err)
+	req.Contains(values, "secrets")
+
+	// helm validates the chart metadata when it is loaded
+	chart, err := loader.Load(chartDir)
+	req.NoError(err)
+	req.Equal(HelmChartName, chart.Metadata.Name)
+	req.NotEmpty(chart.Templates)
+	req.NoError(chart.Validate())
+}
+
+func Test_helmChartVersion(t *testing.T) {
+	tests := []struct {
+		kotsVersion string
+		want        string
+	}{
+		{kotsVersion: "v1.45.0", want: "1.45.0"},
+		{kotsVersion: "v1.45.0-beta.1", want: "1.45.0-beta.1"},
+		{kotsVersion: "v0.0.0-unknown", want: "0.0.0-unknown"},
+		{kotsVersion: "", want: "0.0.0-dev"},
+		{kotsVersion: "a1b2c3d-dirty", want: "0.0.0-dev"},
+		{kotsVersion: "v1.45", want: "0.0.0-dev"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.kotsVersion, func(t *testing.T) {
+			assert.Equal(t, test.want, helmChartVersion(test.kotsVersion))
+		})
+	}
+}
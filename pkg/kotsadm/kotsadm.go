@@ -35,7 +35,7 @@ func getKotsadmYAML(deployOptions types.DeployOptions) (map[string][]byte, error
 	docs["kotsadm-rolebinding.yaml"] = roleBinding.Bytes()
 
 	var serviceAccount bytes.Buffer
-	if err := s.Encode(kotsadmobjects.KotsadmServiceAccount(deployOptions.Namespace), &serviceAccount); err != nil {
+	if err := s.Encode(kotsadmobjects.KotsadmServiceAccount(deployOptions.Namespace, deployOptions.ServiceAccountAnnotations), &serviceAccount); err != nil {
 		return nil, errors.Wrap(err, "failed to marshal kotsadm service account")
 	}
 	docs["kotsadm-serviceaccount.yaml"] = serviceAccount.Bytes()
@@ -52,7 +52,7 @@ func getKotsadmYAML(deployOptions types.DeployOptions) (map[string][]byte, error
 	}
 
 	var service bytes.Buffer
-	if err := s.Encode(kotsadmobjects.KotsadmService(deployOptions.Namespace, nodePort), &service); err != nil {
+	if err := s.Encode(kotsadmobjects.KotsadmService(deployOptions.Namespace, nodePort, deployOptions.ServiceType), &service); err != nil {
 		return nil, errors.Wrap(err, "failed to marshal kotsadm service")
 	}
 	docs["kotsadm-service.yaml"] = service.Bytes()
@@ -129,7 +129,7 @@ func ensureKotsadmComponent(deployOptions *types.DeployOptions, clientset *kuber
 		nodePort = int32(deployOptions.IngressConfig.Spec.NodePort.Port)
 	}
 
-	if err := ensureKotsadmService(deployOptions.Namespace, clientset, nodePort); err != nil {
+	if err := ensureKotsadmService(deployOptions.Namespace, clientset, nodePort, deployOptions.ServiceType); err != nil {
 		return errors.Wrap(err, "failed to ensure kotsadm service")
 	}
 
@@ -154,7 +154,7 @@ func ensureKotsadmRBAC(deployOptions types.DeployOptions, clientset *kubernetes.
 		return errors.Wrap(err, "failed to ensure kotsadm role binding")
 	}
 
-	if err := ensureKotsadmServiceAccount(deployOptions.Namespace, clientset); err != nil {
+	if err := ensureKotsadmServiceAccount(deployOptions.Namespace, deployOptions.ServiceAccountAnnotations, clientset); err != nil {
 		return errors.Wrap(err, "failed to ensure kotsadm service account")
 	}
 
@@ -172,7 +172,7 @@ func ensureKotsadmClusterRBAC(deployOptions types.DeployOptions, clientset *kube
 		return errors.Wrap(err, "failed to ensure kotsadm cluster role binding")
 	}
 
-	if err := ensureKotsadmServiceAccount(deployOptions.Namespace, clientset); err != nil {
+	if err := ensureKotsadmServiceAccount(deployOptions.Namespace, deployOptions.ServiceAccountAnnotations, clientset); err != nil {
 		return errors.Wrap(err, "failed to ensure kotsadm service account")
 	}
 
@@ -286,17 +286,40 @@ func updateKotsadmRoleBinding(existing, desiredRoleBinding *rbacv1.RoleBinding)
 	return existing
 }
 
-func ensureKotsadmServiceAccount(namespace string, clientset *kubernetes.Clientset) error {
-	_, err := clientset.CoreV1().ServiceAccounts(namespace).Get(context.TODO(), "kotsadm", metav1.GetOptions{})
+// ensureKotsadmServiceAccount creates the service account, or adds the annotations to the existing one. Annotations
+// are not removed, they may have been added by the user or by the cloud provider.
+func ensureKotsadmServiceAccount(namespace string, annotations map[string]string, clientset *kubernetes.Clientset) error {
+	existing, err := clientset.CoreV1().ServiceAccounts(namespace).Get(context.TODO(), "kotsadm", metav1.GetOptions{})
 	if err != nil {
 		if !kuberneteserrors.IsNotFound(err) {
 			return errors.Wrap(err, "failed to get serviceaccouont")
 		}
 
-		_, err := clientset.CoreV1().ServiceAccounts(namespace).Create(context.TODO(), kotsadmobjects.KotsadmServiceAccount(namespace), metav1.CreateOptions{})
+		_, err := clientset.CoreV1().ServiceAccounts(namespace).Create(context.TODO(), kotsadmobjects.KotsadmServiceAccount(namespace, annotations), metav1.CreateOptions{})
 		if err != nil {
 			return errors.Wrap(err, "failed to create serviceaccount")
 		}
+
+		return nil
+	}
+
+	changed := false
+	for key, value := range annotations {
+		if existing.Annotations[key] == value {
+			continue
+		}
+		if existing.Annotations == nil {
+			existing.Annotations = map[string]string{}
+		}
+		existing.Annotations[key] = value
+		changed = true
+	}
+	if !changed {
+		return nil
+	}
+
+	if _, err := clientset.CoreV1().ServiceAccounts(namespace).Update(context.TODO(), existing, metav1.UpdateOptions{}); err != nil {
+		return errors.Wrap(err, "failed to update serviceaccount")
 	}
 
 	return nil
@@ -328,8 +351,8 @@ func ensureKotsadmDeployment(deployOptions types.DeployOptions, clientset *kuber
 	return nil
 }
 
-func ensureKotsadmService(namespace string, clientset *kubernetes.Clientset, nodePort int32) error {
-	service := kotsadmobjects.KotsadmService(namespace, nodePort)
+func ensureKotsadmService(namespace string, clientset *kubernetes.Clientset, nodePort int32, serviceType string) error {
+	service := kotsadmobjects.KotsadmService(namespace, nodePort, serviceType)
 
 	existing, err := clientset.CoreV1().Services(namespace).Get(context.TODO(), "kotsadm", metav1.GetOptions{})
 	if err != nil {
@@ -356,6 +379,7 @@ func ensureKotsadmService(namespace string, clientset *kubernetes.Clientset, nod
 }
 
 func updateKotsadmService(existing, desiredService *corev1.Service) *corev1.Service {
+	existing.Spec.Type = desiredService.Spec.Type
 	existing.Spec.Ports = desiredService.Spec.Ports
 
 	return existing
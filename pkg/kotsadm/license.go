@@ -57,7 +57,7 @@ func ensureLicenseSecret(deployOptions *types.DeployOptions, clientset *kubernet
 	return true, nil
 }
 
-func getLicenseSecret(namespace string, clientset *kubernetes.Clientset) (*corev1.Secret, error) {
+func getLicenseSecret(namespace string, clientset kubernetes.Interface) (*corev1.Secret, error) {
 	licenseSecret, err := clientset.CoreV1().Secrets(namespace).Get(context.TODO(), "kotsadm-default-license", metav1.GetOptions{})
 	if err != nil {
 		if kuberneteserrors.IsNotFound(err) {
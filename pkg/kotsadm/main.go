@@ -11,6 +11,7 @@ import (
 	"github.com/pkg/errors"
 	kotsv1beta1 "github.com/replicatedhq/kots/kotskinds/apis/kots/v1beta1"
 	kotsscheme "github.com/replicatedhq/kots/kotskinds/client/kotsclientset/scheme"
+	"github.com/replicatedhq/kots/pkg/cabundle"
 	"github.com/replicatedhq/kots/pkg/docker/registry"
 	"github.com/replicatedhq/kots/pkg/identity"
 	"github.com/replicatedhq/kots/pkg/ingress"
@@ -18,6 +19,8 @@ import (
 	"github.com/replicatedhq/kots/pkg/kotsadm/types"
 	"github.com/replicatedhq/kots/pkg/kotsutil"
 	"github.com/replicatedhq/kots/pkg/logger"
+	"github.com/replicatedhq/kots/pkg/objectstore"
+	"github.com/replicatedhq/kots/pkg/proxyauth"
 	corev1 "k8s.io/api/core/v1"
 	kuberneteserrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -88,6 +91,16 @@ func YAML(deployOptions types.DeployOptions) (map[string][]byte, error) {
 		docs[n] = v
 	}
 
+	if deployOptions.EnableNetworkPolicies {
+		networkPolicyDocs, err := getNetworkPolicyYAML(deployOptions)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to get network policy yaml")
+		}
+		for n, v := range networkPolicyDocs {
+			docs[n] = v
+		}
+	}
+
 	return docs, nil
 }
 
@@ -114,10 +127,62 @@ func Upgrade(upgradeOptions types.UpgradeOptions) error {
 	deployOptions.KotsadmOptions = upgradeOptions.KotsadmOptions
 	deployOptions.EnsureRBAC = upgradeOptions.EnsureRBAC
 	deployOptions.SimultaneousUploads = upgradeOptions.SimultaneousUploads
+	deployOptions.ImagePushWorkers = upgradeOptions.ImagePushWorkers
 	deployOptions.StorageBaseURI = upgradeOptions.StorageBaseURI
 	deployOptions.StorageBaseURIPlainHTTP = upgradeOptions.StorageBaseURIPlainHTTP
+	deployOptions.StorageGCSServiceAccount = upgradeOptions.StorageGCSServiceAccount
+	deployOptions.StorageAzureAccountKey = upgradeOptions.StorageAzureAccountKey
 	deployOptions.IncludeMinio = upgradeOptions.IncludeMinio
 	deployOptions.IncludeDockerDistribution = upgradeOptions.IncludeDockerDistribution
+	deployOptions.HostAliases = upgradeOptions.HostAliases
+
+	// components that were removed with kots admin-console remove-component are not deployed again
+	removedComponents, err := GetRemovedComponents(context.TODO(), clientset, upgradeOptions.Namespace)
+	if err != nil {
+		return errors.Wrap(err, "failed to get removed components")
+	}
+	if IsComponentRemoved(removedComponents, types.ComponentMinio) {
+		deployOptions.IncludeMinio = false
+	}
+	if IsComponentRemoved(removedComponents, types.ComponentRegistry) {
+		deployOptions.IncludeDockerDistribution = false
+	}
+
+	podSecurityProfile := upgradeOptions.PodSecurityProfile
+	if podSecurityProfile == "" || podSecurityProfile == types.PodSecurityProfileAuto {
+		podSecurityProfile, err = getPodSecurityProfileFromCluster(context.TODO(), clientset, upgradeOptions.Namespace)
+		if err != nil {
+			return errors.Wrap(err, "failed to get current pod security profile")
+		}
+	}
+	deployOptions.PodSecurityProfile, err = ResolvePodSecurityProfile(context.TODO(), clientset, upgradeOptions.Namespace, podSecurityProfile)
+	if err != nil {
+		return errors.Wrap(err, "failed to check pod security")
+	}
+
+	if err := objectstore.ValidateURI(deployOptions.StorageBaseURI); err != nil {
+		return errors.Wrap(err, "invalid storage base uri")
+	}
+
+	currentStorageBaseURI, err := getStorageBaseURIFromCluster(upgradeOptions.Namespace, clientset)
+	if err != nil {
+		return errors.Wrap(err, "failed to get current storage base uri")
+	}
+	if upgradeOptions.MigrateStorage {
+		if objectstore.IsOCI(currentStorageBaseURI) || objectstore.IsOCI(deployOptions.StorageBaseURI) {
+			return errors.New("migrating to or from an oci registry is not supported")
+		}
+		if currentStorageBaseURI == deployOptions.StorageBaseURI {
+			return errors.New("the storage base uri is unchanged, there is nothing to migrate")
+		}
+		deployOptions.MigrateStorageFromURI = currentStorageBaseURI
+		if deployOptions.MigrateStorageFromURI == "" {
+			deployOptions.MigrateStorageFromURI = "s3://kotsadm-minio:9000/kotsadm"
+		}
+	} else if deployOptions.StorageBaseURI == "" && objectstore.IsExternal(currentStorageBaseURI) {
+		// external object stores are not deployed with the admin console, so they are kept unless they are migrated
+		deployOptions.StorageBaseURI = currentStorageBaseURI
+	}
 
 	if err := ensureKotsadm(*deployOptions, clientset, log); err != nil {
 		return errors.Wrap(err, "failed to upgrade admin console")
@@ -131,11 +196,18 @@ func Upgrade(upgradeOptions types.UpgradeOptions) error {
 }
 
 func Deploy(deployOptions types.DeployOptions) error {
+	optionsHash, err := InstallOptionsHash(deployOptions)
+	if err != nil {
+		return errors.Wrap(err, "failed to hash install options")
+	}
 
 	airgapPath := ""
 	var images []kustomizetypes.Image
 
-	if deployOptions.AirgapRootDir != "" && deployOptions.KotsadmOptions.OverrideRegistry != "" {
+	// the app images are pushed by jobs in the cluster when the airgap bundle is uploaded to the admin console
+	pushWithJobs := deployOptions.ImagePushWorkers > 1 && !deployOptions.DisableImagePush
+
+	if deployOptions.AirgapRootDir != "" && deployOptions.KotsadmOptions.OverrideRegistry != "" && !pushWithJobs {
 		var err error
 		pushOptions := types.PushImagesOptions{
 			Registry: registry.RegistryOptions{
@@ -144,7 +216,9 @@ func Deploy(deployOptions types.DeployOptions) error {
 				Username:  deployOptions.KotsadmOptions.Username,
 				Password:  deployOptions.KotsadmOptions.Password,
 			},
-			ProgressWriter: deployOptions.ProgressWriter,
+			ProgressWriter:   deployOptions.ProgressWriter,
+			AllArchitectures: deployOptions.AllImageArchitectures,
+			BandwidthLimit:   deployOptions.ImagePushBandwidthLimit,
 		}
 
 		if deployOptions.DisableImagePush {
@@ -171,6 +245,8 @@ func Deploy(deployOptions types.DeployOptions) error {
 	log := logger.NewCLILogger()
 	if deployOptions.AirgapRootDir != "" && deployOptions.KotsadmOptions.OverrideRegistry == "" {
 		log.Info("not pushing airgapped app images as no registry was provided")
+	} else if deployOptions.AirgapRootDir != "" && pushWithJobs {
+		log.Info("app images will be pushed by %d jobs in the cluster once the airgap bundle is uploaded to the admin console", deployOptions.ImagePushWorkers)
 	}
 
 	if !deployOptions.ExcludeAdminConsole {
@@ -204,6 +280,15 @@ func Deploy(deployOptions types.DeployOptions) error {
 			return errors.Wrap(err, "failed to get limit ranges for namespace")
 		}
 		deployOptions.LimitRange = limitRange
+
+		profile, err := ResolvePodSecurityProfile(context.TODO(), clientset, deployOptions.Namespace, deployOptions.PodSecurityProfile)
+		if err != nil {
+			return errors.Wrap(err, "failed to check pod security")
+		}
+		if profile == types.PodSecurityProfileRestricted {
+			log.Info("Generating the admin console for the restricted pod security standard")
+		}
+		deployOptions.PodSecurityProfile = profile
 	}
 
 	if airgapPath != "" {
@@ -233,6 +318,12 @@ func Deploy(deployOptions types.DeployOptions) error {
 		return errors.Wrap(err, "failed to deploy admin console")
 	}
 
+	if !deployOptions.ExcludeAdminConsole {
+		if err := setInstallOptionsHash(deployOptions.Namespace, optionsHash, clientset); err != nil {
+			return errors.Wrap(err, "failed to record install options")
+		}
+	}
+
 	return nil
 }
 
@@ -357,6 +448,19 @@ func ensureKotsadm(deployOptions types.DeployOptions, clientset *kubernetes.Clie
 		}
 	}
 
+	if len(deployOptions.AdditionalManifests) > 0 {
+		// like config values, the additional manifests are handed to kotsadm in a secret
+		// that is used when the app is installed, after which they are part of the downstream
+		updated, err := ensureAdditionalManifestsSecret(&deployOptions, clientset)
+		if err != nil {
+			return errors.Wrap(err, "failed to ensure additional manifests secret")
+		}
+
+		if updated {
+			restartKotsadmAPI = true
+		}
+	}
+
 	if deployOptions.ExcludeAdminConsole && deployOptions.EnsureKotsadmConfig {
 		if err := ensureKotsadmConfig(deployOptions, clientset); err != nil {
 			return errors.Wrap(err, "failed to ensure kotsadm config")
@@ -370,6 +474,37 @@ func ensureKotsadm(deployOptions types.DeployOptions, clientset *kubernetes.Clie
 			return errors.Wrap(err, "failed to ensure kotsadm config")
 		}
 
+		if deployOptions.EnableNetworkPolicies {
+			if err := ensureNetworkPolicies(deployOptions, clientset); err != nil {
+				return errors.Wrap(err, "failed to ensure network policies")
+			}
+		}
+
+		if len(deployOptions.CABundle) > 0 {
+			if err := cabundle.Set(clientset, deployOptions.Namespace, deployOptions.CABundle); err != nil {
+				return errors.Wrap(err, "failed to ensure ca bundle")
+			}
+		}
+
+		if deployOptions.ProxyUsername != "" {
+			proxyCredentials := &proxyauth.Credentials{
+				Scheme:   deployOptions.ProxyAuthScheme,
+				Username: deployOptions.ProxyUsername,
+				Password: deployOptions.ProxyPassword,
+			}
+			if err := proxyauth.Set(clientset, deployOptions.Namespace, proxyCredentials); err != nil {
+				return errors.Wrap(err, "failed to ensure proxy credentials")
+			}
+		}
+
+		storageCredentials := objectstore.Credentials{
+			GCSServiceAccount:      deployOptions.StorageGCSServiceAccount,
+			AzureStorageAccountKey: deployOptions.StorageAzureAccountKey,
+		}
+		if err := objectstore.SetCredentials(clientset, deployOptions.Namespace, storageCredentials); err != nil {
+			return errors.Wrap(err, "failed to ensure object store credentials")
+		}
+
 		if err := ensureStorage(deployOptions, clientset, log); err != nil {
 			return errors.Wrap(err, "failed to ensure postgres")
 		}
@@ -413,6 +548,10 @@ func ensureKotsadm(deployOptions types.DeployOptions, clientset *kubernetes.Clie
 		if err := ensureDisasterRecoveryLabels(&deployOptions, clientset); err != nil {
 			return errors.Wrap(err, "failed to ensure disaster recovery labels")
 		}
+
+		if err := ensureKotsAppCRD(log); err != nil {
+			return errors.Wrap(err, "failed to ensure kotsapp crd")
+		}
 	}
 
 	ctx := context.TODO()
@@ -810,6 +949,21 @@ func readDeployOptionsFromCluster(namespace string, clientset *kubernetes.Client
 		IsOpenShift: k8sutil.IsOpenShift(clientset),
 	}
 
+	// keep the service type that is already in the cluster so that upgrades don't change how the admin console is exposed
+	kotsadmService, err := clientset.CoreV1().Services(namespace).Get(context.TODO(), "kotsadm", metav1.GetOptions{})
+	if err != nil && !kuberneteserrors.IsNotFound(err) {
+		return nil, errors.Wrap(err, "failed to get kotsadm service")
+	}
+	if err == nil && kotsadmService.Spec.Type != "" {
+		deployOptions.ServiceType = string(kotsadmService.Spec.Type)
+	}
+
+	enableNetworkPolicies, err := hasNetworkPolicies(namespace, clientset)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to check for network policies")
+	}
+	deployOptions.EnableNetworkPolicies = enableNetworkPolicies
+
 	// Shared password, we can't read the original, but we can check if there's a bcrypted value
 	// the caller should not recreate if there is a password bcrypt on the return value
 	sharedPasswordSecret, err := getSharedPasswordSecret(namespace, clientset)
@@ -902,6 +1056,31 @@ func readDeployOptionsFromCluster(namespace string, clientset *kubernetes.Client
 	return &deployOptions, nil
 }
 
+// getStorageBaseURIFromCluster returns the storage base uri of the kotsadm deployment, which is empty when the
+// admin console stores its files in minio
+func getStorageBaseURIFromCluster(namespace string, clientset kubernetes.Interface) (string, error) {
+	deployment, err := clientset.AppsV1().Deployments(namespace).Get(context.TODO(), "kotsadm", metav1.GetOptions{})
+	if err != nil {
+		if kuberneteserrors.IsNotFound(err) {
+			return "", nil
+		}
+		return "", errors.Wrap(err, "failed to get kotsadm deployment")
+	}
+
+	for _, container := range deployment.Spec.Template.Spec.Containers {
+		if container.Name != "kotsadm" {
+			continue
+		}
+		for _, env := range container.Env {
+			if env.Name == "STORAGE_BASEURI" {
+				return env.Value, nil
+			}
+		}
+	}
+
+	return "", nil
+}
+
 func GetKotsadmOptionsFromCluster(namespace string, clientset kubernetes.Interface) (types.KotsadmOptions, error) {
 	kotsadmOptions := types.KotsadmOptions{}
 
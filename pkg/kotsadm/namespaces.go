@@ -7,6 +7,7 @@ import (
 	kotsv1beta1 "github.com/replicatedhq/kots/kotskinds/apis/kots/v1beta1"
 	"github.com/replicatedhq/kots/kotskinds/client/kotsclientset/scheme"
 	"github.com/replicatedhq/kots/pkg/kotsadm/types"
+	"github.com/replicatedhq/kots/pkg/kotsutil"
 	"github.com/replicatedhq/kots/pkg/logger"
 	corev1 "k8s.io/api/core/v1"
 	kuberneteserrors "k8s.io/apimachinery/pkg/api/errors"
@@ -31,6 +32,20 @@ func ensureAdditionalNamespaces(deployOptions *types.DeployOptions, clientset *k
 	}
 
 	application := obj.(*kotsv1beta1.Application)
+
+	appSlug := ""
+	if deployOptions.License != nil {
+		appSlug = deployOptions.License.Spec.AppSlug
+	}
+	if len(application.Spec.Namespaces) > 0 {
+		log.ChildActionWithSpinner("Creating application namespaces")
+		if err := kotsutil.EnsureManagedNamespaces(clientset, application, appSlug); err != nil {
+			log.FinishSpinnerWithError()
+			return errors.Wrap(err, "failed to ensure application namespaces")
+		}
+		log.FinishChildSpinner()
+	}
+
 	for _, additionalNamespace := range application.Spec.AdditionalNamespaces {
 		// We support "*" for additional namespaces to handle pullsecret propagation
 		if additionalNamespace == "*" {
@@ -0,0 +1,91 @@
+package kotsadm
+
+import (
+	"bytes"
+	"context"
+
+	"github.com/pkg/errors"
+	kotsadmobjects "github.com/replicatedhq/kots/pkg/kotsadm/objects"
+	"github.com/replicatedhq/kots/pkg/kotsadm/types"
+	networkingv1 "k8s.io/api/networking/v1"
+	kuberneteserrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/serializer/json"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/scheme"
+)
+
+func getNetworkPolicyYAML(deployOptions types.DeployOptions) (map[string][]byte, error) {
+	docs := map[string][]byte{}
+	s := json.NewYAMLSerializer(json.DefaultMetaFactory, scheme.Scheme, scheme.Scheme)
+
+	policies := map[string]*networkingv1.NetworkPolicy{
+		"kotsadm-networkpolicy.yaml":  kotsadmobjects.KotsadmNetworkPolicy(deployOptions),
+		"postgres-networkpolicy.yaml": kotsadmobjects.PostgresNetworkPolicy(deployOptions),
+		"minio-networkpolicy.yaml":    kotsadmobjects.MinioNetworkPolicy(deployOptions),
+	}
+	for filename, policy := range policies {
+		var b bytes.Buffer
+		if err := s.Encode(policy, &b); err != nil {
+			return nil, errors.Wrapf(err, "failed to marshal %s network policy", policy.Name)
+		}
+		docs[filename] = b.Bytes()
+	}
+
+	return docs, nil
+}
+
+func ensureNetworkPolicies(deployOptions types.DeployOptions, clientset *kubernetes.Clientset) error {
+	policies := []*networkingv1.NetworkPolicy{
+		kotsadmobjects.KotsadmNetworkPolicy(deployOptions),
+		kotsadmobjects.PostgresNetworkPolicy(deployOptions),
+	}
+	if deployOptions.IncludeMinio {
+		policies = append(policies, kotsadmobjects.MinioNetworkPolicy(deployOptions))
+	}
+
+	for _, policy := range policies {
+		if err := ensureNetworkPolicy(deployOptions.Namespace, policy, clientset); err != nil {
+			return errors.Wrapf(err, "failed to ensure %s network policy", policy.Name)
+		}
+	}
+
+	return nil
+}
+
+func ensureNetworkPolicy(namespace string, policy *networkingv1.NetworkPolicy, clientset *kubernetes.Clientset) error {
+	existing, err := clientset.NetworkingV1().NetworkPolicies(namespace).Get(context.TODO(), policy.Name, metav1.GetOptions{})
+	if err != nil {
+		if !kuberneteserrors.IsNotFound(err) {
+			return errors.Wrap(err, "failed to get existing network policy")
+		}
+
+		_, err := clientset.NetworkingV1().NetworkPolicies(namespace).Create(context.TODO(), policy, metav1.CreateOptions{})
+		if err != nil {
+			return errors.Wrap(err, "failed to create network policy")
+		}
+
+		return nil
+	}
+
+	existing.Spec = policy.Spec
+	_, err = clientset.NetworkingV1().NetworkPolicies(namespace).Update(context.TODO(), existing, metav1.UpdateOptions{})
+	if err != nil {
+		return errors.Wrap(err, "failed to update network policy")
+	}
+
+	return nil
+}
+
+// hasNetworkPolicies returns true if the admin console was installed with network policies,
+// so that upgrades keep them up to date
+func hasNetworkPolicies(namespace string, clientset *kubernetes.Clientset) (bool, error) {
+	_, err := clientset.NetworkingV1().NetworkPolicies(namespace).Get(context.TODO(), "kotsadm", metav1.GetOptions{})
+	if kuberneteserrors.IsNotFound(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, errors.Wrap(err, "failed to get kotsadm network policy")
+	}
+	return true, nil
+}
@@ -2,6 +2,7 @@ package kotsadm
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/replicatedhq/kots/pkg/kotsadm/types"
 	kotsadmversion "github.com/replicatedhq/kots/pkg/kotsadm/version"
@@ -14,6 +15,31 @@ func KotsadmConfigMap(deployOptions types.DeployOptions) *corev1.ConfigMap {
 		"initial-app-images-pushed": fmt.Sprintf("%v", deployOptions.AppImagesPushed),
 		"skip-preflights":           fmt.Sprintf("%v", deployOptions.SkipPreflights),
 		"registry-is-read-only":     fmt.Sprintf("%v", deployOptions.DisableImagePush),
+		"read-only-console":         fmt.Sprintf("%v", deployOptions.ReadOnlyConsole),
+	}
+	if deployOptions.SessionTTL > 0 {
+		data["session-ttl"] = deployOptions.SessionTTL.String()
+	}
+	if deployOptions.SessionIdleTimeout > 0 {
+		data["session-idle-timeout"] = deployOptions.SessionIdleTimeout.String()
+	}
+	if deployOptions.SessionReauthWindow > 0 {
+		data["session-reauth-window"] = deployOptions.SessionReauthWindow.String()
+	}
+	if deployOptions.Headless {
+		data[types.RemovedComponentsKey] = string(types.ComponentUI)
+	}
+	if len(deployOptions.CORSAllowedOrigins) > 0 {
+		data["cors-allowed-origins"] = strings.Join(deployOptions.CORSAllowedOrigins, ",")
+	}
+	if len(deployOptions.EndpointMirrors.ReplicatedAPI) > 0 {
+		data["replicated-api-mirrors"] = strings.Join(deployOptions.EndpointMirrors.ReplicatedAPI, ",")
+	}
+	if len(deployOptions.EndpointMirrors.Registry) > 0 {
+		data["replicated-registry-mirrors"] = strings.Join(deployOptions.EndpointMirrors.Registry, ",")
+	}
+	if len(deployOptions.EndpointMirrors.Proxy) > 0 {
+		data["replicated-proxy-mirrors"] = strings.Join(deployOptions.EndpointMirrors.Proxy, ",")
 	}
 	if kotsadmversion.KotsadmPullSecret(deployOptions.Namespace, deployOptions.KotsadmOptions) != nil {
 		data["kotsadm-registry"] = kotsadmversion.KotsadmRegistry(deployOptions.KotsadmOptions)
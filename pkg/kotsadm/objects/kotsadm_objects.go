@@ -10,7 +10,7 @@ import (
 	"github.com/replicatedhq/kots/pkg/ingress"
 	"github.com/replicatedhq/kots/pkg/kotsadm/types"
 	kotsadmversion "github.com/replicatedhq/kots/pkg/kotsadm/version"
-	"github.com/replicatedhq/kots/pkg/util"
+	"github.com/replicatedhq/kots/pkg/objectstore"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	extensionsv1beta1 "k8s.io/api/extensions/v1beta1"
@@ -120,16 +120,19 @@ func KotsadmRoleBinding(roleBindingNamespace string, kotsadmNamespace string) *r
 	return roleBinding
 }
 
-func KotsadmServiceAccount(namespace string) *corev1.ServiceAccount {
+// KotsadmServiceAccount returns the service account of the admin console. The annotations bind it to a cloud
+// identity, such as eks.amazonaws.com/role-arn or iam.gke.io/gcp-service-account, to access the object store.
+func KotsadmServiceAccount(namespace string, annotations map[string]string) *corev1.ServiceAccount {
 	serviceAccount := &corev1.ServiceAccount{
 		TypeMeta: metav1.TypeMeta{
 			APIVersion: "v1",
 			Kind:       "ServiceAccount",
 		},
 		ObjectMeta: metav1.ObjectMeta{
-			Name:      "kotsadm",
-			Namespace: namespace,
-			Labels:    types.GetKotsadmLabels(),
+			Name:        "kotsadm",
+			Namespace:   namespace,
+			Labels:      types.GetKotsadmLabels(),
+			Annotations: annotations,
 		},
 	}
 
@@ -178,6 +181,11 @@ func UpdateKotsadmDeployment(deployment *appsv1.Deployment, deployOptions types.
 	}
 	deployment.Spec.Template.Spec.Volumes = newVolumes
 
+	// pod security profile
+	deployment.Spec.Template.Spec.SecurityContext = desiredDeployment.Spec.Template.Spec.SecurityContext
+	deployment.Spec.Template.Spec.Containers[containerIdx].SecurityContext = desiredDeployment.Spec.Template.Spec.Containers[0].SecurityContext
+	deployment.Spec.Template.Spec.Containers[containerIdx].VolumeMounts = desiredDeployment.Spec.Template.Spec.Containers[0].VolumeMounts
+
 	// copy the env vars from the desired to existing. this could undo a change that the user had.
 	// we don't know which env vars we set and which are user edited. this method avoids deleting
 	// env vars that the user added, but doesn't handle edited vars
@@ -193,22 +201,38 @@ func UpdateKotsadmDeployment(deployment *appsv1.Deployment, deployOptions types.
 			}
 		}
 
+		// the object store to migrate from is only set by the upgrade that migrates it
+		if existingEnv.Name == "STORAGE_MIGRATE_FROM_BASEURI" {
+			isUnxpected = false
+		}
+
 		if isUnxpected {
 			mergedEnvs = append(mergedEnvs, existingEnv)
 		}
 	}
 	deployment.Spec.Template.Spec.Containers[containerIdx].Env = mergedEnvs
 
+	// host aliases are kept unless new ones are set
+	if deployOptions.HostAliases != nil {
+		deployment.Spec.Template.Spec.HostAliases = deployOptions.HostAliases
+	}
+
+	// the azure identity binding is kept unless a new one is set
+	if deployOptions.StorageAzureIdentityBinding != "" {
+		if deployment.Spec.Template.Labels == nil {
+			deployment.Spec.Template.Labels = map[string]string{}
+		}
+		deployment.Spec.Template.Labels[azureIdentityBindingLabel] = deployOptions.StorageAzureIdentityBinding
+	}
+
 	return nil
 }
 
+// azureIdentityBindingLabel selects the pods that aad pod identity assigns a managed identity to
+const azureIdentityBindingLabel = "aadpodidbinding"
+
 func KotsadmDeployment(deployOptions types.DeployOptions) *appsv1.Deployment {
-	var securityContext corev1.PodSecurityContext
-	if !deployOptions.IsOpenShift {
-		securityContext = corev1.PodSecurityContext{
-			RunAsUser: util.IntPointer(1001),
-		}
-	}
+	securityContext := kotsadmPodSecurityContext(deployOptions)
 
 	var pullSecrets []corev1.LocalObjectReference
 	if s := kotsadmversion.KotsadmPullSecret(deployOptions.Namespace, deployOptions.KotsadmOptions); s != nil {
@@ -219,6 +243,7 @@ func KotsadmDeployment(deployOptions types.DeployOptions) *appsv1.Deployment {
 		}
 	}
 
+	optionalReadReplica := true
 	env := []corev1.EnvVar{
 		{
 			Name: "SHARED_PASSWORD_BCRYPT",
@@ -275,6 +300,19 @@ func KotsadmDeployment(deployOptions types.DeployOptions) *appsv1.Deployment {
 				},
 			},
 		},
+		{
+			// the read replica for the heavy GET endpoints is optional, the primary is used if the key is not set
+			Name: "POSTGRES_READ_URI",
+			ValueFrom: &corev1.EnvVarSource{
+				SecretKeyRef: &corev1.SecretKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{
+						Name: "kotsadm-postgres",
+					},
+					Key:      "read_uri",
+					Optional: &optionalReadReplica,
+				},
+			},
+		},
 		{
 			Name: "POD_NAMESPACE",
 			ValueFrom: &corev1.EnvVarSource{
@@ -302,6 +340,10 @@ func KotsadmDeployment(deployOptions types.DeployOptions) *appsv1.Deployment {
 			Name:  "API_ADVERTISE_ENDPOINT",
 			Value: "http://localhost:8800",
 		},
+		{
+			Name:  "KOTS_BASE_CACHE_DIR",
+			Value: "/tmp/kots-base-cache",
+		},
 	}
 
 	if strings.HasPrefix(deployOptions.StorageBaseURI, "docker://") {
@@ -313,44 +355,27 @@ func KotsadmDeployment(deployOptions types.DeployOptions) *appsv1.Deployment {
 			Name:  "STORAGE_BASEURI_PLAINHTTP",
 			Value: strconv.FormatBool(deployOptions.StorageBaseURIPlainHTTP),
 		})
+	} else if objectstore.IsExternal(deployOptions.StorageBaseURI) {
+		env = append(env, corev1.EnvVar{
+			Name:  "STORAGE_BASEURI",
+			Value: deployOptions.StorageBaseURI,
+		})
 	} else {
-		s3env := []corev1.EnvVar{
-			{
-				Name:  "S3_ENDPOINT",
-				Value: "http://kotsadm-minio:9000",
-			},
-			{
-				Name:  "S3_BUCKET_NAME",
-				Value: "kotsadm",
-			},
-			{
-				Name: "S3_ACCESS_KEY_ID",
-				ValueFrom: &corev1.EnvVarSource{
-					SecretKeyRef: &corev1.SecretKeySelector{
-						LocalObjectReference: corev1.LocalObjectReference{
-							Name: "kotsadm-minio",
-						},
-						Key: "accesskey",
-					},
-				},
-			},
-			{
-				Name: "S3_SECRET_ACCESS_KEY",
-				ValueFrom: &corev1.EnvVarSource{
-					SecretKeyRef: &corev1.SecretKeySelector{
-						LocalObjectReference: corev1.LocalObjectReference{
-							Name: "kotsadm-minio",
-						},
-						Key: "secretkey",
-					},
-				},
-			},
-			{
-				Name:  "S3_BUCKET_ENDPOINT",
-				Value: "true",
-			},
+		env = append(env, minioEnv()...)
+	}
+
+	if deployOptions.MigrateStorageFromURI != "" {
+		env = append(env, corev1.EnvVar{
+			Name:  "STORAGE_MIGRATE_FROM_BASEURI",
+			Value: deployOptions.MigrateStorageFromURI,
+		})
+		if !objectstore.IsExternal(deployOptions.MigrateStorageFromURI) && objectstore.IsExternal(deployOptions.StorageBaseURI) {
+			env = append(env, minioEnv()...)
 		}
-		env = append(env, s3env...)
+	}
+
+	if objectstore.IsExternal(deployOptions.StorageBaseURI) || objectstore.IsExternal(deployOptions.MigrateStorageFromURI) {
+		env = append(env, objectStoreCredentialsEnv()...)
 	}
 
 	env = append(env, GetProxyEnv(deployOptions)...)
@@ -362,6 +387,13 @@ func KotsadmDeployment(deployOptions types.DeployOptions) *appsv1.Deployment {
 		})
 	}
 
+	if deployOptions.Headless {
+		env = append(env, corev1.EnvVar{
+			Name:  "DISABLE_SPA_SERVING",
+			Value: "1",
+		})
+	}
+
 	if deployOptions.InstallID != "" {
 		env = append(env, corev1.EnvVar{
 			Name:  "KOTS_INSTALL_ID",
@@ -376,6 +408,20 @@ func KotsadmDeployment(deployOptions types.DeployOptions) *appsv1.Deployment {
 		})
 	}
 
+	if deployOptions.ImagePushWorkers > 1 {
+		env = append(env, corev1.EnvVar{
+			Name:  "AIRGAP_PUSH_WORKERS",
+			Value: fmt.Sprintf("%d", deployOptions.ImagePushWorkers),
+		})
+	}
+
+	podLabels := map[string]string{
+		"app": "kotsadm",
+	}
+	if deployOptions.StorageAzureIdentityBinding != "" {
+		podLabels[azureIdentityBindingLabel] = deployOptions.StorageAzureIdentityBinding
+	}
+
 	deployment := &appsv1.Deployment{
 		TypeMeta: metav1.TypeMeta{
 			APIVersion: "apps/v1",
@@ -394,9 +440,7 @@ func KotsadmDeployment(deployOptions types.DeployOptions) *appsv1.Deployment {
 			},
 			Template: corev1.PodTemplateSpec{
 				ObjectMeta: metav1.ObjectMeta{
-					Labels: types.GetKotsadmLabels(map[string]string{
-						"app": "kotsadm",
-					}),
+					Labels: types.GetKotsadmLabels(podLabels),
 					Annotations: map[string]string{
 						"backup.velero.io/backup-volumes":   "backup",
 						"pre.hook.backup.velero.io/command": `["/backup.sh"]`,
@@ -407,7 +451,8 @@ func KotsadmDeployment(deployOptions types.DeployOptions) *appsv1.Deployment {
 					Affinity: &corev1.Affinity{
 						NodeAffinity: defaultKotsNodeAffinity(),
 					},
-					SecurityContext: &securityContext,
+					SecurityContext: securityContext,
+					HostAliases:     deployOptions.HostAliases,
 					Volumes: []corev1.Volume{
 						{
 							Name: "migrations",
@@ -663,10 +708,84 @@ func KotsadmDeployment(deployOptions types.DeployOptions) *appsv1.Deployment {
 		},
 	}
 
+	applyPodSecurityProfile(&deployment.Spec.Template.Spec, deployOptions)
+
 	return deployment
 }
 
-func KotsadmService(namespace string, nodePort int32) *corev1.Service {
+// minioEnv returns the env vars of the s3 driver for the minio instance that is deployed with the admin console
+func minioEnv() []corev1.EnvVar {
+	return []corev1.EnvVar{
+		{
+			Name:  "S3_ENDPOINT",
+			Value: "http://kotsadm-minio:9000",
+		},
+		{
+			Name:  "S3_BUCKET_NAME",
+			Value: "kotsadm",
+		},
+		{
+			Name: "S3_ACCESS_KEY_ID",
+			ValueFrom: &corev1.EnvVarSource{
+				SecretKeyRef: &corev1.SecretKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{
+						Name: "kotsadm-minio",
+					},
+					Key: "accesskey",
+				},
+			},
+		},
+		{
+			Name: "S3_SECRET_ACCESS_KEY",
+			ValueFrom: &corev1.EnvVarSource{
+				SecretKeyRef: &corev1.SecretKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{
+						Name: "kotsadm-minio",
+					},
+					Key: "secretkey",
+				},
+			},
+		},
+		{
+			Name:  "S3_BUCKET_ENDPOINT",
+			Value: "true",
+		},
+	}
+}
+
+// objectStoreCredentialsEnv returns the env vars with the credentials of the gcs and azure drivers. They are
+// optional because gcs can use the default credentials of the node instead.
+func objectStoreCredentialsEnv() []corev1.EnvVar {
+	optional := true
+	return []corev1.EnvVar{
+		{
+			Name: "GCS_SERVICE_ACCOUNT",
+			ValueFrom: &corev1.EnvVarSource{
+				SecretKeyRef: &corev1.SecretKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{
+						Name: objectstore.SecretName,
+					},
+					Key:      objectstore.GCSServiceAccountKey,
+					Optional: &optional,
+				},
+			},
+		},
+		{
+			Name: "AZURE_STORAGE_ACCOUNT_KEY",
+			ValueFrom: &corev1.EnvVarSource{
+				SecretKeyRef: &corev1.SecretKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{
+						Name: objectstore.SecretName,
+					},
+					Key:      objectstore.AzureStorageAccountKeyKey,
+					Optional: &optional,
+				},
+			},
+		},
+	}
+}
+
+func KotsadmService(namespace string, nodePort int32, serviceType string) *corev1.Service {
 	port := corev1.ServicePort{
 		Name:       "http",
 		Port:       3000,
@@ -674,9 +793,11 @@ func KotsadmService(namespace string, nodePort int32) *corev1.Service {
 		NodePort:   nodePort,
 	}
 
-	serviceType := corev1.ServiceTypeClusterIP
-	if nodePort != 0 {
-		serviceType = corev1.ServiceTypeNodePort
+	desiredServiceType := corev1.ServiceTypeClusterIP
+	if serviceType != "" {
+		desiredServiceType = corev1.ServiceType(serviceType)
+	} else if nodePort != 0 {
+		desiredServiceType = corev1.ServiceTypeNodePort
 	}
 
 	service := &corev1.Service{
@@ -693,7 +814,7 @@ func KotsadmService(namespace string, nodePort int32) *corev1.Service {
 			Selector: map[string]string{
 				"app": "kotsadm",
 			},
-			Type: serviceType,
+			Type: desiredServiceType,
 			Ports: []corev1.ServicePort{
 				port,
 			},
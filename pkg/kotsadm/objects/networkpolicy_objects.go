@@ -0,0 +1,87 @@
+package kotsadm
+
+import (
+	"github.com/replicatedhq/kots/pkg/kotsadm/types"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+// KotsadmNetworkPolicy allows ingress to the admin console api and ui from anywhere, since it is reached
+// through port forwards, node ports, ingresses and by the operator. Egress is not restricted because
+// kotsadm talks to the kubernetes api, upstream registries and the replicated apis.
+func KotsadmNetworkPolicy(deployOptions types.DeployOptions) *networkingv1.NetworkPolicy {
+	return networkPolicy(deployOptions, "kotsadm", []networkingv1.NetworkPolicyIngressRule{
+		{
+			Ports: []networkingv1.NetworkPolicyPort{tcpPort(3000)},
+		},
+	}, false)
+}
+
+// PostgresNetworkPolicy only allows the kotsadm pods to connect to postgres, and denies all egress
+func PostgresNetworkPolicy(deployOptions types.DeployOptions) *networkingv1.NetworkPolicy {
+	return networkPolicy(deployOptions, "kotsadm-postgres", []networkingv1.NetworkPolicyIngressRule{
+		{
+			From:  []networkingv1.NetworkPolicyPeer{kotsadmPeer()},
+			Ports: []networkingv1.NetworkPolicyPort{tcpPort(5432)},
+		},
+	}, true)
+}
+
+// MinioNetworkPolicy only allows the kotsadm pods to connect to minio, and denies all egress
+func MinioNetworkPolicy(deployOptions types.DeployOptions) *networkingv1.NetworkPolicy {
+	return networkPolicy(deployOptions, "kotsadm-minio", []networkingv1.NetworkPolicyIngressRule{
+		{
+			From:  []networkingv1.NetworkPolicyPeer{kotsadmPeer()},
+			Ports: []networkingv1.NetworkPolicyPort{tcpPort(9000)},
+		},
+	}, true)
+}
+
+func networkPolicy(deployOptions types.DeployOptions, app string, ingress []networkingv1.NetworkPolicyIngressRule, denyEgress bool) *networkingv1.NetworkPolicy {
+	policyTypes := []networkingv1.PolicyType{networkingv1.PolicyTypeIngress}
+	if denyEgress {
+		policyTypes = append(policyTypes, networkingv1.PolicyTypeEgress)
+	}
+
+	return &networkingv1.NetworkPolicy{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "networking.k8s.io/v1",
+			Kind:       "NetworkPolicy",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      app,
+			Namespace: deployOptions.Namespace,
+			Labels:    types.GetKotsadmLabels(),
+		},
+		Spec: networkingv1.NetworkPolicySpec{
+			PodSelector: metav1.LabelSelector{
+				MatchLabels: map[string]string{
+					"app": app,
+				},
+			},
+			Ingress:     ingress,
+			PolicyTypes: policyTypes,
+		},
+	}
+}
+
+func kotsadmPeer() networkingv1.NetworkPolicyPeer {
+	return networkingv1.NetworkPolicyPeer{
+		PodSelector: &metav1.LabelSelector{
+			MatchLabels: map[string]string{
+				"app": "kotsadm",
+			},
+		},
+	}
+}
+
+func tcpPort(port int) networkingv1.NetworkPolicyPort {
+	protocol := corev1.ProtocolTCP
+	p := intstr.FromInt(port)
+	return networkingv1.NetworkPolicyPort{
+		Protocol: &protocol,
+		Port:     &p,
+	}
+}
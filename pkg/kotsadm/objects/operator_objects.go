@@ -4,9 +4,9 @@ import (
 	"fmt"
 
 	"github.com/pkg/errors"
+	"github.com/replicatedhq/kots/pkg/cabundle"
 	"github.com/replicatedhq/kots/pkg/kotsadm/types"
 	kotsadmversion "github.com/replicatedhq/kots/pkg/kotsadm/version"
-	"github.com/replicatedhq/kots/pkg/util"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
@@ -131,13 +131,6 @@ func OperatorServiceAccount(namespace string) *corev1.ServiceAccount {
 }
 
 func UpdateOperatorDeployment(deployment *appsv1.Deployment, deployOptions types.DeployOptions) error {
-	var securityContext corev1.PodSecurityContext
-	if !deployOptions.IsOpenShift {
-		securityContext = corev1.PodSecurityContext{
-			RunAsUser: util.IntPointer(1001),
-		}
-	}
-
 	desiredDeployment := OperatorDeployment(deployOptions)
 
 	// ensure the non-optional kots labels are present (added in 1.11.0)
@@ -151,7 +144,7 @@ func UpdateOperatorDeployment(deployment *appsv1.Deployment, deployOptions types
 	deployment.Spec.Template.ObjectMeta.Labels[types.KotsadmKey] = types.KotsadmLabelValue
 
 	// security context (added in 1.11.0)
-	deployment.Spec.Template.Spec.SecurityContext = &securityContext
+	deployment.Spec.Template.Spec.SecurityContext = desiredDeployment.Spec.Template.Spec.SecurityContext
 	containerIdx := -1
 	for idx, c := range deployment.Spec.Template.Spec.Containers {
 		if c.Name == "kotsadm-operator" {
@@ -187,16 +180,21 @@ func UpdateOperatorDeployment(deployment *appsv1.Deployment, deployOptions types
 	}
 	deployment.Spec.Template.Spec.Containers[containerIdx].Env = mergedEnvs
 
+	// ca bundle and the tmp dir of the restricted pod security profile
+	deployment.Spec.Template.Spec.Volumes = desiredDeployment.Spec.Template.Spec.Volumes
+	deployment.Spec.Template.Spec.Containers[containerIdx].VolumeMounts = desiredDeployment.Spec.Template.Spec.Containers[0].VolumeMounts
+	deployment.Spec.Template.Spec.Containers[containerIdx].SecurityContext = desiredDeployment.Spec.Template.Spec.Containers[0].SecurityContext
+
+	// host aliases are kept unless new ones are set
+	if deployOptions.HostAliases != nil {
+		deployment.Spec.Template.Spec.HostAliases = deployOptions.HostAliases
+	}
+
 	return nil
 }
 
 func OperatorDeployment(deployOptions types.DeployOptions) *appsv1.Deployment {
-	var securityContext corev1.PodSecurityContext
-	if !deployOptions.IsOpenShift {
-		securityContext = corev1.PodSecurityContext{
-			RunAsUser: util.IntPointer(1001),
-		}
-	}
+	securityContext := kotsadmPodSecurityContext(deployOptions)
 
 	var pullSecrets []corev1.LocalObjectReference
 	if s := kotsadmversion.KotsadmPullSecret(deployOptions.Namespace, deployOptions.KotsadmOptions); s != nil {
@@ -207,6 +205,9 @@ func OperatorDeployment(deployOptions types.DeployOptions) *appsv1.Deployment {
 		}
 	}
 
+	// the ca bundle is optional, it only exists if a custom bundle was set. the same goes for the signing key.
+	optional := true
+
 	deployment := &appsv1.Deployment{
 		TypeMeta: metav1.TypeMeta{
 			APIVersion: "apps/v1",
@@ -233,16 +234,42 @@ func OperatorDeployment(deployOptions types.DeployOptions) *appsv1.Deployment {
 					Affinity: &corev1.Affinity{
 						NodeAffinity: defaultKotsNodeAffinity(),
 					},
-					SecurityContext:    &securityContext,
+					SecurityContext:    securityContext,
 					ServiceAccountName: "kotsadm-operator",
 					RestartPolicy:      corev1.RestartPolicyAlways,
 					ImagePullSecrets:   pullSecrets,
+					HostAliases:        deployOptions.HostAliases,
+					Volumes: []corev1.Volume{
+						{
+							Name: "ca-bundle",
+							VolumeSource: corev1.VolumeSource{
+								ConfigMap: &corev1.ConfigMapVolumeSource{
+									LocalObjectReference: corev1.LocalObjectReference{
+										Name: cabundle.ConfigMapName,
+									},
+									Optional: &optional,
+								},
+							},
+						},
+					},
 					Containers: []corev1.Container{
 						{
 							Image:           fmt.Sprintf("%s/kotsadm-operator:%s", kotsadmversion.KotsadmRegistry(deployOptions.KotsadmOptions), kotsadmversion.KotsadmTag(deployOptions.KotsadmOptions)),
 							ImagePullPolicy: corev1.PullIfNotPresent,
 							Name:            "kotsadm-operator",
+							VolumeMounts: []corev1.VolumeMount{
+								{
+									Name:      "ca-bundle",
+									MountPath: "/etc/kotsadm/ca-bundle",
+									ReadOnly:  true,
+								},
+							},
 							Env: []corev1.EnvVar{
+								{
+									// added to the system roots, the default cert file is still loaded
+									Name:  "SSL_CERT_DIR",
+									Value: "/etc/kotsadm/ca-bundle",
+								},
 								{
 									Name:  "KOTSADM_API_ENDPOINT",
 									Value: fmt.Sprintf("http://kotsadm.%s.svc.cluster.local:3000", deployOptions.Namespace),
@@ -258,6 +285,19 @@ func OperatorDeployment(deployOptions types.DeployOptions) *appsv1.Deployment {
 										},
 									},
 								},
+								{
+									// manifests are not verified if the signing key does not exist
+									Name: "KOTSADM_MANIFEST_SIGNING_PUBLIC_KEY",
+									ValueFrom: &corev1.EnvVarSource{
+										SecretKeyRef: &corev1.SecretKeySelector{
+											LocalObjectReference: corev1.LocalObjectReference{
+												Name: types.ManifestSigningSecret,
+											},
+											Key:      "public-key",
+											Optional: &optional,
+										},
+									},
+								},
 								{
 									Name: "KOTSADM_TARGET_NAMESPACE",
 									ValueFrom: &corev1.EnvVarSource{
@@ -284,5 +324,7 @@ func OperatorDeployment(deployOptions types.DeployOptions) *appsv1.Deployment {
 		},
 	}
 
+	applyPodSecurityProfile(&deployment.Spec.Template.Spec, deployOptions)
+
 	return deployment
 }
@@ -0,0 +1,74 @@
+package kotsadm
+
+import (
+	"github.com/replicatedhq/kots/pkg/kotsadm/types"
+	"github.com/replicatedhq/kots/pkg/util"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// kotsadmPodSecurityContext returns the security context of the kotsadm and operator pods. openshift assigns the
+// user from the range of the namespace, so the user is not set there.
+func kotsadmPodSecurityContext(deployOptions types.DeployOptions) *corev1.PodSecurityContext {
+	securityContext := &corev1.PodSecurityContext{}
+	if !deployOptions.IsOpenShift {
+		securityContext.RunAsUser = util.IntPointer(1001)
+	}
+
+	if deployOptions.PodSecurityProfile == types.PodSecurityProfileRestricted {
+		runAsNonRoot := true
+		securityContext.RunAsNonRoot = &runAsNonRoot
+		securityContext.SeccompProfile = &corev1.SeccompProfile{
+			Type: corev1.SeccompProfileTypeRuntimeDefault,
+		}
+	}
+
+	return securityContext
+}
+
+// restrictedContainerSecurityContext is the security context that the restricted profile requires for every container
+func restrictedContainerSecurityContext() *corev1.SecurityContext {
+	allowPrivilegeEscalation := false
+	readOnlyRootFilesystem := true
+	runAsNonRoot := true
+	return &corev1.SecurityContext{
+		AllowPrivilegeEscalation: &allowPrivilegeEscalation,
+		ReadOnlyRootFilesystem:   &readOnlyRootFilesystem,
+		RunAsNonRoot:             &runAsNonRoot,
+		Capabilities: &corev1.Capabilities{
+			Drop: []corev1.Capability{"ALL"},
+		},
+	}
+}
+
+// applyPodSecurityProfile sets the container security contexts of the profile. The restricted profile has a
+// read-only root filesystem, so an empty dir is mounted at /tmp for the files that the containers write, and it is
+// also the home dir for the caches of the tools that kotsadm runs.
+func applyPodSecurityProfile(podSpec *corev1.PodSpec, deployOptions types.DeployOptions) {
+	if deployOptions.PodSecurityProfile != types.PodSecurityProfileRestricted {
+		return
+	}
+
+	podSpec.Volumes = append(podSpec.Volumes, corev1.Volume{
+		Name: "tmp",
+		VolumeSource: corev1.VolumeSource{
+			EmptyDir: &corev1.EmptyDirVolumeSource{},
+		},
+	})
+
+	tmpMount := corev1.VolumeMount{
+		Name:      "tmp",
+		MountPath: "/tmp",
+	}
+	for i := range podSpec.InitContainers {
+		podSpec.InitContainers[i].SecurityContext = restrictedContainerSecurityContext()
+		podSpec.InitContainers[i].VolumeMounts = append(podSpec.InitContainers[i].VolumeMounts, tmpMount)
+	}
+	for i := range podSpec.Containers {
+		podSpec.Containers[i].SecurityContext = restrictedContainerSecurityContext()
+		podSpec.Containers[i].VolumeMounts = append(podSpec.Containers[i].VolumeMounts, tmpMount)
+		podSpec.Containers[i].Env = append(podSpec.Containers[i].Env, corev1.EnvVar{
+			Name:  "HOME",
+			Value: "/tmp",
+		})
+	}
+}
@@ -115,6 +115,28 @@ func ApiEncryptionKeySecret(namespace string, key string) *corev1.Secret {
 	return secret
 }
 
+// ManifestSigningSecret is the key that kotsadm signs the manifests and archive checksums with, the operator
+// verifies the manifests with the public key
+func ManifestSigningSecret(namespace string, publicKey string, privateKey string) *corev1.Secret {
+	secret := &corev1.Secret{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "v1",
+			Kind:       "Secret",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      types.ManifestSigningSecret,
+			Namespace: namespace,
+			Labels:    types.GetKotsadmLabels(),
+		},
+		Data: map[string][]byte{
+			"public-key":  []byte(publicKey),
+			"private-key": []byte(privateKey),
+		},
+	}
+
+	return secret
+}
+
 func ApiClusterTokenSecret(deployOptions types.DeployOptions) *corev1.Secret {
 	return &corev1.Secret{
 		TypeMeta: metav1.TypeMeta{
@@ -8,6 +8,7 @@ import (
 	kotsv1beta1 "github.com/replicatedhq/kots/kotskinds/apis/kots/v1beta1"
 	kotsadmobjects "github.com/replicatedhq/kots/pkg/kotsadm/objects"
 	"github.com/replicatedhq/kots/pkg/kotsadm/types"
+	"github.com/replicatedhq/kots/pkg/kotsutil"
 	rbacv1 "k8s.io/api/rbac/v1"
 	kuberneteserrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -115,7 +116,7 @@ func ensureOperatorRBAC(deployOptions types.DeployOptions, clientset *kubernetes
 	}
 
 	application := obj.(*kotsv1beta1.Application)
-	for _, additionalNamespace := range application.Spec.AdditionalNamespaces {
+	for _, additionalNamespace := range kotsutil.AdditionalNamespaces(application) {
 		if err = ensureOperatorRole(additionalNamespace, clientset); err != nil {
 			return errors.Wrap(err, "failed to ensure operator additional namespace role")
 		}
@@ -255,7 +256,7 @@ func isOperatorClusterScoped(applicationMetadata []byte) (bool, error) {
 		return true, nil
 	}
 
-	for _, additionalNamespace := range application.Spec.AdditionalNamespaces {
+	for _, additionalNamespace := range kotsutil.AdditionalNamespaces(application) {
 		if additionalNamespace == "*" {
 			return true, nil
 		}
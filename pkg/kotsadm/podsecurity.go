@@ -0,0 +1,67 @@
+package kotsadm
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	"github.com/replicatedhq/kots/pkg/kotsadm/types"
+	corev1 "k8s.io/api/core/v1"
+	kuberneteserrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// ResolvePodSecurityProfile checks the profile against the pod security admission labels of the namespace. The
+// restricted profile is selected automatically if the namespace enforces, warns or audits the restricted standard,
+// and the default profile is refused if the namespace enforces it, since the pods would not be admitted.
+func ResolvePodSecurityProfile(ctx context.Context, clientset kubernetes.Interface, namespace string, profile types.PodSecurityProfile) (types.PodSecurityProfile, error) {
+	labels := map[string]string{}
+	ns, err := clientset.CoreV1().Namespaces().Get(ctx, namespace, metav1.GetOptions{})
+	if err != nil && !kuberneteserrors.IsNotFound(err) {
+		return "", errors.Wrap(err, "failed to get namespace")
+	}
+	if err == nil && ns.Labels != nil {
+		labels = ns.Labels
+	}
+
+	enforcesRestricted := labels[types.PodSecurityEnforceLabel] == string(types.PodSecurityProfileRestricted)
+	prefersRestricted := enforcesRestricted ||
+		labels[types.PodSecurityWarnLabel] == string(types.PodSecurityProfileRestricted) ||
+		labels[types.PodSecurityAuditLabel] == string(types.PodSecurityProfileRestricted)
+
+	switch profile {
+	case "", types.PodSecurityProfileAuto:
+		if prefersRestricted {
+			return types.PodSecurityProfileRestricted, nil
+		}
+		return types.PodSecurityProfileDefault, nil
+	case types.PodSecurityProfileDefault:
+		if enforcesRestricted {
+			return "", errors.Errorf("namespace %s enforces the restricted pod security standard, use the restricted pod security profile", namespace)
+		}
+		return types.PodSecurityProfileDefault, nil
+	case types.PodSecurityProfileRestricted:
+		return types.PodSecurityProfileRestricted, nil
+	default:
+		return "", errors.Errorf("unsupported pod security profile %q", profile)
+	}
+}
+
+// getPodSecurityProfileFromCluster returns the profile that the kotsadm deployment was generated with, so that
+// upgrades keep a restricted profile that was selected explicitly
+func getPodSecurityProfileFromCluster(ctx context.Context, clientset kubernetes.Interface, namespace string) (types.PodSecurityProfile, error) {
+	deployment, err := clientset.AppsV1().Deployments(namespace).Get(ctx, "kotsadm", metav1.GetOptions{})
+	if err != nil {
+		if kuberneteserrors.IsNotFound(err) {
+			return types.PodSecurityProfileAuto, nil
+		}
+		return "", errors.Wrap(err, "failed to get kotsadm deployment")
+	}
+
+	securityContext := deployment.Spec.Template.Spec.SecurityContext
+	if securityContext != nil && securityContext.SeccompProfile != nil && securityContext.SeccompProfile.Type == corev1.SeccompProfileTypeRuntimeDefault {
+		return types.PodSecurityProfileRestricted, nil
+	}
+
+	return types.PodSecurityProfileAuto, nil
+}
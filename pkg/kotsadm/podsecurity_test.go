@@ -0,0 +1,110 @@
+package kotsadm
+
+import (
+	"context"
+	"testing"
+
+	kotsadmobjects "github.com/replicatedhq/kots/pkg/kotsadm/objects"
+	"github.com/replicatedhq/kots/pkg/kotsadm/types"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func Test_ResolvePodSecurityProfile(t *testing.T) {
+	tests := []struct {
+		name    string
+		labels  map[string]string
+		profile types.PodSecurityProfile
+		want    types.PodSecurityProfile
+		wantErr bool
+	}{
+		{
+			name:    "auto without labels",
+			profile: types.PodSecurityProfileAuto,
+			want:    types.PodSecurityProfileDefault,
+		},
+		{
+			name:    "auto with restricted enforced",
+			labels:  map[string]string{types.PodSecurityEnforceLabel: "restricted"},
+			profile: types.PodSecurityProfileAuto,
+			want:    types.PodSecurityProfileRestricted,
+		},
+		{
+			name:    "auto with restricted warnings",
+			labels:  map[string]string{types.PodSecurityEnforceLabel: "baseline", types.PodSecurityWarnLabel: "restricted"},
+			profile: types.PodSecurityProfileAuto,
+			want:    types.PodSecurityProfileRestricted,
+		},
+		{
+			name:    "auto with baseline enforced",
+			labels:  map[string]string{types.PodSecurityEnforceLabel: "baseline"},
+			profile: "",
+			want:    types.PodSecurityProfileDefault,
+		},
+		{
+			name:    "default with restricted enforced",
+			labels:  map[string]string{types.PodSecurityEnforceLabel: "restricted"},
+			profile: types.PodSecurityProfileDefault,
+			wantErr: true,
+		},
+		{
+			name:    "restricted without labels",
+			profile: types.PodSecurityProfileRestricted,
+			want:    types.PodSecurityProfileRestricted,
+		},
+		{
+			name:    "unsupported",
+			profile: "privileged",
+			wantErr: true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			clientset := fake.NewSimpleClientset(&corev1.Namespace{
+				ObjectMeta: metav1.ObjectMeta{Name: "default", Labels: test.labels},
+			})
+
+			got, err := ResolvePodSecurityProfile(context.Background(), clientset, "default", test.profile)
+			if test.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, test.want, got)
+		})
+	}
+}
+
+func Test_restrictedPodSecurityProfile(t *testing.T) {
+	deployOptions := types.DeployOptions{
+		Namespace:          "default",
+		PodSecurityProfile: types.PodSecurityProfileRestricted,
+	}
+
+	for _, podSpec := range []corev1.PodSpec{
+		kotsadmobjects.KotsadmDeployment(deployOptions).Spec.Template.Spec,
+		kotsadmobjects.OperatorDeployment(deployOptions).Spec.Template.Spec,
+	} {
+		require.NotNil(t, podSpec.SecurityContext.RunAsNonRoot)
+		require.True(t, *podSpec.SecurityContext.RunAsNonRoot)
+		require.Equal(t, corev1.SeccompProfileTypeRuntimeDefault, podSpec.SecurityContext.SeccompProfile.Type)
+
+		containers := append(podSpec.InitContainers, podSpec.Containers...)
+		for _, container := range containers {
+			securityContext := container.SecurityContext
+			require.NotNil(t, securityContext, container.Name)
+			require.False(t, *securityContext.AllowPrivilegeEscalation, container.Name)
+			require.True(t, *securityContext.ReadOnlyRootFilesystem, container.Name)
+			require.Equal(t, []corev1.Capability{"ALL"}, securityContext.Capabilities.Drop, container.Name)
+			require.Contains(t, container.VolumeMounts, corev1.VolumeMount{Name: "tmp", MountPath: "/tmp"}, container.Name)
+		}
+	}
+
+	// the default profile does not change the containers
+	deployOptions.PodSecurityProfile = types.PodSecurityProfileDefault
+	podSpec := kotsadmobjects.KotsadmDeployment(deployOptions).Spec.Template.Spec
+	require.Nil(t, podSpec.SecurityContext.SeccompProfile)
+	require.Nil(t, podSpec.Containers[0].SecurityContext)
+}
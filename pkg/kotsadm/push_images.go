@@ -22,7 +22,9 @@ import (
 	"github.com/containers/image/v5/transports/alltransports"
 	containerstypes "github.com/containers/image/v5/types"
 	"github.com/pkg/errors"
+	"github.com/replicatedhq/kots/pkg/cabundle"
 	"github.com/replicatedhq/kots/pkg/image"
+	"github.com/replicatedhq/kots/pkg/k8sutil"
 	"github.com/replicatedhq/kots/pkg/kotsadm/types"
 	"k8s.io/client-go/kubernetes/scheme"
 	kustomizetypes "sigs.k8s.io/kustomize/api/types"
@@ -121,6 +123,8 @@ func ExtractAppAirgapArchive(archive string, destDir string, excludeImages bool,
 }
 
 func pushKotsadmImagesFromPath(rootDir string, options types.PushImagesOptions) error {
+	options.Architectures = resolveArchitectures(options)
+
 	fileInfos, err := ioutil.ReadDir(rootDir)
 	if err != nil {
 		return errors.Wrap(err, "failed to read dir")
@@ -197,6 +201,7 @@ func pushOneImage(rootDir string, format string, imageName string, tag string, o
 	destCtx := &containerstypes.SystemContext{
 		DockerInsecureSkipTLSVerify: containerstypes.OptionalBoolTrue,
 		DockerDisableV1Ping:         true,
+		DockerCertPath:              cabundle.DockerCertPath(strings.Split(options.Registry.Endpoint, "/")[0]),
 	}
 	if options.Registry.Username != "" && options.Registry.Password != "" {
 		destCtx.DockerAuthConfig = &containerstypes.DockerAuthConfig{
@@ -229,14 +234,19 @@ func pushOneImage(rootDir string, format string, imageName string, tag string, o
 
 	writeProgressLine(options.ProgressWriter, fmt.Sprintf("Pushing %s", destStr))
 
-	_, err = image.CopyImageWithGC(context.Background(), policyContext, destRef, localRef, &copy.Options{
+	copyOptions := &copy.Options{
 		RemoveSignatures:      true,
 		SignBy:                "",
 		ReportWriter:          options.ProgressWriter,
 		SourceCtx:             nil,
 		DestinationCtx:        destCtx,
 		ForceManifestMIMEType: "",
-	})
+	}
+	if err := image.ArchitectureCopyOptions(context.Background(), localRef, options.Architectures, false, copyOptions); err != nil {
+		return errors.Wrap(err, "failed to select architectures")
+	}
+
+	_, err = image.CopyImageWithGC(context.Background(), policyContext, destRef, image.ThrottleReference(localRef, options.BandwidthLimit), copyOptions)
 	if err != nil {
 		return errors.Wrapf(err, "failed to push image")
 	}
@@ -249,6 +259,8 @@ func writeProgressLine(progressWriter io.Writer, line string) {
 }
 
 func TagAndPushAppImagesFromPath(imagesDir string, options types.PushImagesOptions) ([]kustomizetypes.Image, error) {
+	options.Architectures = resolveArchitectures(options)
+
 	formatDirs, err := ioutil.ReadDir(imagesDir)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to read images dir")
@@ -273,7 +285,7 @@ func TagAndPushAppImagesFromPath(imagesDir string, options types.PushImagesOptio
 				}
 
 				layers := make(map[string]*types.LayerInfo)
-				if options.LogForUI {
+				if options.LogForUI && f.Name() == "docker-archive" {
 					layers, err = getLayerInfo(path)
 					if err != nil {
 						return errors.Wrap(err, "failed to get layer info")
@@ -326,7 +338,7 @@ func TagAndPushAppImagesFromPath(imagesDir string, options types.PushImagesOptio
 				reportWriter.Write([]byte(fmt.Sprintf("+file.begin:%s\n", imageFile.FilePath)))
 			}
 			for i := 0; i < 5; i++ {
-				err = image.CopyFromFileToRegistry(imageFile.FilePath, rewrittenImage.NewName, rewrittenImage.NewTag, rewrittenImage.Digest, registryAuth, reportWriter)
+				err = image.CopyFromFileToRegistry(imageFile.FilePath, imageFile.Format, rewrittenImage.NewName, rewrittenImage.NewTag, rewrittenImage.Digest, registryAuth, options.Architectures, options.BandwidthLimit, reportWriter)
 				if err == nil {
 					break // image copy succeeded, exit the retry loop
 				} else {
@@ -366,6 +378,8 @@ func TagAndPushAppImagesFromBundle(airgapBundle string, options types.PushImages
 		return nil, errors.Wrap(err, "failed to get layer info from bundle")
 	}
 
+	options.Architectures = resolveArchitectures(options)
+
 	fileReader, err := os.Open(airgapBundle)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to open file")
@@ -456,7 +470,7 @@ func TagAndPushAppImagesFromBundle(airgapBundle string, options types.PushImages
 				reportWriter.Write([]byte(fmt.Sprintf("+file.begin:%s\n", imageFile.FilePath)))
 			}
 			for i := 0; i < 5; i++ {
-				err = image.CopyFromFileToRegistry(tmpFile.Name(), rewrittenImage.NewName, rewrittenImage.NewTag, rewrittenImage.Digest, registryAuth, reportWriter)
+				err = image.CopyFromFileToRegistry(tmpFile.Name(), imageFile.Format, rewrittenImage.NewName, rewrittenImage.NewTag, rewrittenImage.Digest, registryAuth, options.Architectures, options.BandwidthLimit, reportWriter)
 				if err == nil {
 					break // image copy succeeded, exit the retry loop
 				} else {
@@ -562,19 +576,20 @@ func getImageListFromBundle(airgapBundle string, getLayerInfo bool) (map[string]
 			continue
 		}
 
+		pathParts := strings.Split(header.Name, string(os.PathSeparator))
+		if len(pathParts) < 3 {
+			return nil, errors.Errorf("not enough parts in image path: %q", header.Name)
+		}
+
+		// layer progress is only reported for docker archives, oci archives can contain multiple images
 		layers := make(map[string]*types.LayerInfo)
-		if getLayerInfo {
+		if getLayerInfo && pathParts[1] == "docker-archive" {
 			layers, err = getLayerInfoFromReader(tarReader)
 			if err != nil {
 				return nil, errors.Wrap(err, "failed to get layer info")
 			}
 		}
 
-		pathParts := strings.Split(header.Name, string(os.PathSeparator))
-		if len(pathParts) < 3 {
-			return nil, errors.Errorf("not enough parts in image path: %q", header.Name)
-		}
-
 		imageFiles[header.Name] = &types.ImageFile{
 			Format:   pathParts[1], // path is like "images/<format>/image/name/tag"
 			FilePath: header.Name,
@@ -871,3 +886,31 @@ func isAppArchive(rootDir string) bool {
 
 	return false
 }
+
+// resolveArchitectures returns the architectures to push from multi-arch images, or nil to push all of them.
+// If no architectures are given, they are detected from the nodes in the cluster.
+func resolveArchitectures(options types.PushImagesOptions) []string {
+	if options.AllArchitectures {
+		return nil
+	}
+	if len(options.Architectures) > 0 {
+		return options.Architectures
+	}
+
+	clientset, err := k8sutil.GetClientset()
+	if err != nil {
+		writeProgressLine(options.ProgressWriter, fmt.Sprintf("Pushing all image architectures, failed to get clientset: %v", err))
+		return nil
+	}
+
+	architectures, err := k8sutil.GetNodeArchitectures(clientset)
+	if err != nil {
+		writeProgressLine(options.ProgressWriter, fmt.Sprintf("Pushing all image architectures, failed to detect node architectures: %v", err))
+		return nil
+	}
+	if len(architectures) > 0 {
+		writeProgressLine(options.ProgressWriter, fmt.Sprintf("Pushing images for architectures %s", strings.Join(architectures, ", ")))
+	}
+
+	return architectures
+}
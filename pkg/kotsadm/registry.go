@@ -0,0 +1,209 @@
+package kotsadm
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/docker/distribution/reference"
+	"github.com/pkg/errors"
+	"github.com/replicatedhq/kots/pkg/kotsadm/types"
+	kotsadmversion "github.com/replicatedhq/kots/pkg/kotsadm/version"
+	corev1 "k8s.io/api/core/v1"
+	kuberneteserrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// componentImageRepos are the upstream repositories of the admin console component images, by image name
+var componentImageRepos = map[string]string{
+	"kotsadm":            "kotsadm/kotsadm",
+	"kotsadm-migrations": "kotsadm/kotsadm-migrations",
+	"kotsadm-operator":   "kotsadm/kotsadm-operator",
+	"minio":              "minio/minio",
+	"postgres":           "postgres",
+}
+
+// SetKotsadmRegistry changes the registry that the admin console components (api, operator, minio and postgres)
+// pull their images from, without reinstalling the admin console. The pull secret and the registry in the kotsadm
+// config map are updated, and the images of the deployments and statefulsets are rewritten, which rolls out their
+// pods. The upstream images are used again if options has no registry. The kotsadm workload is updated last since
+// its pods are replaced.
+func SetKotsadmRegistry(ctx context.Context, clientset kubernetes.Interface, namespace string, options types.KotsadmOptions) error {
+	pullSecret := kotsadmversion.KotsadmPullSecret(namespace, options)
+	if err := ensureKotsadmPullSecret(ctx, clientset, namespace, pullSecret); err != nil {
+		return errors.Wrap(err, "failed to ensure pull secret")
+	}
+
+	if err := setKotsadmRegistryInConfigMap(ctx, clientset, namespace, options); err != nil {
+		return errors.Wrap(err, "failed to update config map")
+	}
+
+	rewritePodSpec := func(podSpec *corev1.PodSpec) error {
+		return rewriteComponentPodSpec(podSpec, options, pullSecret != nil)
+	}
+
+	if err := updateDeploymentPodSpec(ctx, clientset, namespace, "kotsadm-operator", rewritePodSpec); err != nil {
+		return errors.Wrap(err, "failed to update operator")
+	}
+	for _, name := range []string{"kotsadm-minio", "kotsadm-postgres", "kotsadm"} {
+		if err := updateStatefulSetPodSpec(ctx, clientset, namespace, name, rewritePodSpec); err != nil {
+			return errors.Wrapf(err, "failed to update %s", name)
+		}
+	}
+	if err := updateDeploymentPodSpec(ctx, clientset, namespace, "kotsadm", rewritePodSpec); err != nil {
+		return errors.Wrap(err, "failed to update kotsadm")
+	}
+
+	return nil
+}
+
+func ensureKotsadmPullSecret(ctx context.Context, clientset kubernetes.Interface, namespace string, secret *corev1.Secret) error {
+	if secret == nil {
+		err := clientset.CoreV1().Secrets(namespace).Delete(ctx, types.PrivateKotsadmRegistrySecret, metav1.DeleteOptions{})
+		if err != nil && !kuberneteserrors.IsNotFound(err) {
+			return errors.Wrap(err, "failed to delete secret")
+		}
+		return nil
+	}
+
+	existing, err := clientset.CoreV1().Secrets(namespace).Get(ctx, secret.Name, metav1.GetOptions{})
+	if err != nil {
+		if !kuberneteserrors.IsNotFound(err) {
+			return errors.Wrap(err, "failed to get existing secret")
+		}
+		if _, err := clientset.CoreV1().Secrets(namespace).Create(ctx, secret, metav1.CreateOptions{}); err != nil {
+			return errors.Wrap(err, "failed to create secret")
+		}
+		return nil
+	}
+
+	existing.Data = secret.Data
+	if _, err := clientset.CoreV1().Secrets(namespace).Update(ctx, existing, metav1.UpdateOptions{}); err != nil {
+		return errors.Wrap(err, "failed to update secret")
+	}
+
+	return nil
+}
+
+func setKotsadmRegistryInConfigMap(ctx context.Context, clientset kubernetes.Interface, namespace string, options types.KotsadmOptions) error {
+	configMap, err := clientset.CoreV1().ConfigMaps(namespace).Get(ctx, types.KotsadmConfigMap, metav1.GetOptions{})
+	if err != nil {
+		return errors.Wrap(err, "failed to get config map")
+	}
+
+	if configMap.Data == nil {
+		configMap.Data = map[string]string{}
+	}
+	if options.OverrideRegistry == "" {
+		delete(configMap.Data, "kotsadm-registry")
+	} else {
+		configMap.Data["kotsadm-registry"] = kotsadmversion.KotsadmRegistry(options)
+	}
+
+	if _, err := clientset.CoreV1().ConfigMaps(namespace).Update(ctx, configMap, metav1.UpdateOptions{}); err != nil {
+		return errors.Wrap(err, "failed to update config map")
+	}
+
+	return nil
+}
+
+func updateDeploymentPodSpec(ctx context.Context, clientset kubernetes.Interface, namespace string, name string, update func(*corev1.PodSpec) error) error {
+	deployment, err := clientset.AppsV1().Deployments(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		if kuberneteserrors.IsNotFound(err) {
+			return nil
+		}
+		return errors.Wrap(err, "failed to get deployment")
+	}
+
+	if err := update(&deployment.Spec.Template.Spec); err != nil {
+		return err
+	}
+
+	if _, err := clientset.AppsV1().Deployments(namespace).Update(ctx, deployment, metav1.UpdateOptions{}); err != nil {
+		return errors.Wrap(err, "failed to update deployment")
+	}
+
+	return nil
+}
+
+func updateStatefulSetPodSpec(ctx context.Context, clientset kubernetes.Interface, namespace string, name string, update func(*corev1.PodSpec) error) error {
+	statefulset, err := clientset.AppsV1().StatefulSets(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		if kuberneteserrors.IsNotFound(err) {
+			return nil
+		}
+		return errors.Wrap(err, "failed to get statefulset")
+	}
+
+	if err := update(&statefulset.Spec.Template.Spec); err != nil {
+		return err
+	}
+
+	if _, err := clientset.AppsV1().StatefulSets(namespace).Update(ctx, statefulset, metav1.UpdateOptions{}); err != nil {
+		return errors.Wrap(err, "failed to update statefulset")
+	}
+
+	return nil
+}
+
+// rewriteComponentPodSpec rewrites the images of the admin console components in the pod spec, and adds or removes
+// the pull secret. Other images, such as sidecars added by the cluster, are not changed.
+func rewriteComponentPodSpec(podSpec *corev1.PodSpec, options types.KotsadmOptions, hasPullSecret bool) error {
+	for i, container := range podSpec.InitContainers {
+		image, err := rewriteComponentImage(container.Image, options)
+		if err != nil {
+			return errors.Wrapf(err, "failed to rewrite image of init container %s", container.Name)
+		}
+		podSpec.InitContainers[i].Image = image
+	}
+	for i, container := range podSpec.Containers {
+		image, err := rewriteComponentImage(container.Image, options)
+		if err != nil {
+			return errors.Wrapf(err, "failed to rewrite image of container %s", container.Name)
+		}
+		podSpec.Containers[i].Image = image
+	}
+
+	pullSecrets := []corev1.LocalObjectReference{}
+	for _, pullSecret := range podSpec.ImagePullSecrets {
+		if pullSecret.Name != types.PrivateKotsadmRegistrySecret {
+			pullSecrets = append(pullSecrets, pullSecret)
+		}
+	}
+	if hasPullSecret {
+		pullSecrets = append(pullSecrets, corev1.LocalObjectReference{Name: types.PrivateKotsadmRegistrySecret})
+	}
+	podSpec.ImagePullSecrets = pullSecrets
+
+	return nil
+}
+
+// rewriteComponentImage moves an admin console component image to the registry in options, or back to its upstream
+// repository if there is no registry. The tag of the image is kept so that the admin console version does not change.
+func rewriteComponentImage(image string, options types.KotsadmOptions) (string, error) {
+	named, err := reference.ParseNormalizedNamed(image)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to parse image %s", image)
+	}
+
+	parts := strings.Split(reference.Path(named), "/")
+	imageName := parts[len(parts)-1]
+
+	upstreamRepo, ok := componentImageRepos[imageName]
+	if !ok {
+		return image, nil
+	}
+
+	tagged, ok := named.(reference.Tagged)
+	if !ok {
+		return "", errors.Errorf("only tagged images can be rewritten: %s", image)
+	}
+
+	if options.OverrideRegistry == "" {
+		return fmt.Sprintf("%s:%s", upstreamRepo, tagged.Tag()), nil
+	}
+
+	return fmt.Sprintf("%s/%s:%s", kotsadmversion.KotsadmRegistry(options), imageName, tagged.Tag()), nil
+}
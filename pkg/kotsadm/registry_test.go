@@ -0,0 +1,129 @@
+package kotsadm
+
+import (
+	"context"
+	"testing"
+
+	"github.com/replicatedhq/kots/pkg/kotsadm/types"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func Test_rewriteComponentImage(t *testing.T) {
+	privateRegistry := types.KotsadmOptions{
+		OverrideRegistry:  "registry.example.com",
+		OverrideNamespace: "mirror",
+	}
+
+	tests := []struct {
+		name    string
+		image   string
+		options types.KotsadmOptions
+		want    string
+	}{
+		{
+			name:    "kotsadm to private registry",
+			image:   "kotsadm/kotsadm:v1.50.0",
+			options: privateRegistry,
+			want:    "registry.example.com/mirror/kotsadm:v1.50.0",
+		},
+		{
+			name:    "postgres to private registry",
+			image:   "postgres:10.17-alpine",
+			options: privateRegistry,
+			want:    "registry.example.com/mirror/postgres:10.17-alpine",
+		},
+		{
+			name:    "minio back to docker hub",
+			image:   "registry.example.com/mirror/minio:RELEASE.2021-05-20T22-31-44Z",
+			options: types.KotsadmOptions{},
+			want:    "minio/minio:RELEASE.2021-05-20T22-31-44Z",
+		},
+		{
+			name:    "operator back to docker hub",
+			image:   "registry.example.com/mirror/kotsadm-operator:v1.50.0",
+			options: types.KotsadmOptions{},
+			want:    "kotsadm/kotsadm-operator:v1.50.0",
+		},
+		{
+			name:    "other images are not rewritten",
+			image:   "istio/proxyv2:1.9.0",
+			options: privateRegistry,
+			want:    "istio/proxyv2:1.9.0",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := rewriteComponentImage(test.image, test.options)
+			require.NoError(t, err)
+			require.Equal(t, test.want, got)
+		})
+	}
+}
+
+func Test_SetKotsadmRegistry(t *testing.T) {
+	req := require.New(t)
+
+	podSpec := func(image string) corev1.PodTemplateSpec {
+		return corev1.PodTemplateSpec{
+			Spec: corev1.PodSpec{
+				Containers: []corev1.Container{{Name: "main", Image: image}},
+			},
+		}
+	}
+	clientset := fake.NewSimpleClientset(
+		&corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: types.KotsadmConfigMap, Namespace: "default"},
+			Data:       map[string]string{"registry-is-read-only": "false"},
+		},
+		&appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: "kotsadm", Namespace: "default"},
+			Spec:       appsv1.DeploymentSpec{Template: podSpec("kotsadm/kotsadm:v1.50.0")},
+		},
+		&appsv1.StatefulSet{
+			ObjectMeta: metav1.ObjectMeta{Name: "kotsadm-postgres", Namespace: "default"},
+			Spec:       appsv1.StatefulSetSpec{Template: podSpec("postgres:10.17-alpine")},
+		},
+	)
+
+	options := types.KotsadmOptions{
+		OverrideRegistry:  "registry.example.com",
+		OverrideNamespace: "mirror",
+		Username:          "user",
+		Password:          "pass",
+	}
+	err := SetKotsadmRegistry(context.Background(), clientset, "default", options)
+	req.NoError(err)
+
+	configMap, err := clientset.CoreV1().ConfigMaps("default").Get(context.Background(), types.KotsadmConfigMap, metav1.GetOptions{})
+	req.NoError(err)
+	req.Equal("registry.example.com/mirror", configMap.Data["kotsadm-registry"])
+
+	_, err = clientset.CoreV1().Secrets("default").Get(context.Background(), types.PrivateKotsadmRegistrySecret, metav1.GetOptions{})
+	req.NoError(err)
+
+	deployment, err := clientset.AppsV1().Deployments("default").Get(context.Background(), "kotsadm", metav1.GetOptions{})
+	req.NoError(err)
+	req.Equal("registry.example.com/mirror/kotsadm:v1.50.0", deployment.Spec.Template.Spec.Containers[0].Image)
+	req.Equal([]corev1.LocalObjectReference{{Name: types.PrivateKotsadmRegistrySecret}}, deployment.Spec.Template.Spec.ImagePullSecrets)
+
+	statefulset, err := clientset.AppsV1().StatefulSets("default").Get(context.Background(), "kotsadm-postgres", metav1.GetOptions{})
+	req.NoError(err)
+	req.Equal("registry.example.com/mirror/postgres:10.17-alpine", statefulset.Spec.Template.Spec.Containers[0].Image)
+
+	// back to the upstream images
+	err = SetKotsadmRegistry(context.Background(), clientset, "default", types.KotsadmOptions{})
+	req.NoError(err)
+
+	configMap, err = clientset.CoreV1().ConfigMaps("default").Get(context.Background(), types.KotsadmConfigMap, metav1.GetOptions{})
+	req.NoError(err)
+	req.NotContains(configMap.Data, "kotsadm-registry")
+
+	deployment, err = clientset.AppsV1().Deployments("default").Get(context.Background(), "kotsadm", metav1.GetOptions{})
+	req.NoError(err)
+	req.Equal("kotsadm/kotsadm:v1.50.0", deployment.Spec.Template.Spec.Containers[0].Image)
+	req.Empty(deployment.Spec.Template.Spec.ImagePullSecrets)
+}
@@ -13,6 +13,7 @@ import (
 	identitydeploy "github.com/replicatedhq/kots/pkg/identity/deploy"
 	kotsadmobjects "github.com/replicatedhq/kots/pkg/kotsadm/objects"
 	"github.com/replicatedhq/kots/pkg/kotsadm/types"
+	"github.com/replicatedhq/kots/pkg/manifestsigning"
 	"golang.org/x/crypto/bcrypt"
 	corev1 "k8s.io/api/core/v1"
 	kuberneteserrors "k8s.io/apimachinery/pkg/api/errors"
@@ -82,6 +83,16 @@ func getSecretsYAML(deployOptions *types.DeployOptions) (map[string][]byte, erro
 	}
 	docs["secret-api-cluster-token.yaml"] = tokenSecret.Bytes()
 
+	publicKey, privateKey, err := manifestsigning.GenerateKey()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to generate manifest signing key")
+	}
+	var signingSecret bytes.Buffer
+	if err := s.Encode(kotsadmobjects.ManifestSigningSecret(deployOptions.Namespace, publicKey, privateKey), &signingSecret); err != nil {
+		return nil, errors.Wrap(err, "failed to marshal manifest signing secret")
+	}
+	docs["secret-manifest-signing.yaml"] = signingSecret.Bytes()
+
 	// this secret is optional
 	if secret := kotsadmobjects.PrivateKotsadmRegistrySecret(deployOptions.Namespace, deployOptions.KotsadmOptions); secret != nil {
 		var registrySecret bytes.Buffer
@@ -131,6 +142,10 @@ func ensureSecrets(deployOptions *types.DeployOptions, clientset *kubernetes.Cli
 		return errors.Wrap(err, "failed to ensure api cluster token secret")
 	}
 
+	if err := ensureManifestSigningSecret(deployOptions.Namespace, clientset); err != nil {
+		return errors.Wrap(err, "failed to ensure manifest signing secret")
+	}
+
 	return nil
 }
 
@@ -221,7 +236,7 @@ func ensurePostgresSecret(deployOptions types.DeployOptions, clientset *kubernet
 	return nil
 }
 
-func getSharedPasswordSecret(namespace string, clientset *kubernetes.Clientset) (*corev1.Secret, error) {
+func getSharedPasswordSecret(namespace string, clientset kubernetes.Interface) (*corev1.Secret, error) {
 	sharedPasswordSecret, err := clientset.CoreV1().Secrets(namespace).Get(context.TODO(), "kotsadm-password", metav1.GetOptions{})
 	if err != nil {
 		if kuberneteserrors.IsNotFound(err) {
@@ -235,6 +250,16 @@ func getSharedPasswordSecret(namespace string, clientset *kubernetes.Clientset)
 }
 
 func ensureSharedPasswordSecret(deployOptions *types.DeployOptions, clientset *kubernetes.Clientset) error {
+	existingSharedPasswordSecret, err := getSharedPasswordSecret(deployOptions.Namespace, clientset)
+	if err != nil {
+		return errors.Wrap(err, "failed to check for existing password secret")
+	}
+
+	// an existing password is never changed unless explicitly requested so that re-running install is safe
+	if existingSharedPasswordSecret != nil && !deployOptions.ForcePasswordUpdate {
+		return nil
+	}
+
 	if deployOptions.SharedPassword == "" {
 		sharedPassword, err := promptForSharedPassword()
 		if err != nil {
@@ -249,18 +274,23 @@ func ensureSharedPasswordSecret(deployOptions *types.DeployOptions, clientset *k
 		return errors.Wrap(err, "failed to bcrypt shared password")
 	}
 
-	existingSharedPasswordSecret, err := getSharedPasswordSecret(deployOptions.Namespace, clientset)
-	if err != nil {
-		return errors.Wrap(err, "failed to check for existing password secret")
-	}
 	if existingSharedPasswordSecret == nil {
 		_, err := clientset.CoreV1().Secrets(deployOptions.Namespace).Create(context.TODO(), kotsadmobjects.SharedPasswordSecret(deployOptions.Namespace, string(bcryptPassword)), metav1.CreateOptions{})
 		if err != nil {
 			return errors.Wrap(err, "failed to create password secret")
 		}
+		return nil
 	}
 
-	// TODO handle update
+	if existingSharedPasswordSecret.Data == nil {
+		existingSharedPasswordSecret.Data = map[string][]byte{}
+	}
+	existingSharedPasswordSecret.Data["passwordBcrypt"] = bcryptPassword
+
+	_, err = clientset.CoreV1().Secrets(deployOptions.Namespace).Update(context.TODO(), existingSharedPasswordSecret, metav1.UpdateOptions{})
+	if err != nil {
+		return errors.Wrap(err, "failed to update password secret")
+	}
 
 	return nil
 }
@@ -359,6 +389,30 @@ func ensureAPIClusterTokenSecret(deployOptions types.DeployOptions, clientset *k
 	return nil
 }
 
+// ensureManifestSigningSecret creates the signing key on install, and on the upgrade of installs from before
+// manifests were signed. An existing key is never replaced, the operator verifies with its public key.
+func ensureManifestSigningSecret(namespace string, clientset *kubernetes.Clientset) error {
+	_, err := clientset.CoreV1().Secrets(namespace).Get(context.TODO(), types.ManifestSigningSecret, metav1.GetOptions{})
+	if err == nil {
+		return nil
+	}
+	if !kuberneteserrors.IsNotFound(err) {
+		return errors.Wrap(err, "failed to get existing manifest signing secret")
+	}
+
+	publicKey, privateKey, err := manifestsigning.GenerateKey()
+	if err != nil {
+		return errors.Wrap(err, "failed to generate key")
+	}
+
+	_, err = clientset.CoreV1().Secrets(namespace).Create(context.TODO(), kotsadmobjects.ManifestSigningSecret(namespace, publicKey, privateKey), metav1.CreateOptions{})
+	if err != nil {
+		return errors.Wrap(err, "failed to create manifest signing secret")
+	}
+
+	return nil
+}
+
 func getAPIClusterToken(namespace string, clientset *kubernetes.Clientset) (string, error) {
 	apiSecret, err := clientset.CoreV1().Secrets(namespace).Get(context.TODO(), types.ClusterTokenSecret, metav1.GetOptions{})
 	if err != nil {
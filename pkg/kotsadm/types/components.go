@@ -0,0 +1,29 @@
+package types
+
+import (
+	"github.com/pkg/errors"
+)
+
+// Component is a part of the admin console that can be removed when the installation does not need it
+type Component string
+
+const (
+	// ComponentUI is the web console served by kotsadm. Without it, the admin console only serves the api.
+	ComponentUI Component = "ui"
+	// ComponentMinio is the embedded minio object store, which is unused once the files are migrated to another store
+	ComponentMinio Component = "minio"
+	// ComponentRegistry is the embedded docker distribution registry used as an oci object store
+	ComponentRegistry Component = "registry"
+)
+
+// RemovedComponentsKey is the key of the kotsadm config map that lists the removed components, so that upgrades
+// do not deploy them again
+const RemovedComponentsKey = "removed-components"
+
+func ParseComponent(name string) (Component, error) {
+	switch c := Component(name); c {
+	case ComponentUI, ComponentMinio, ComponentRegistry:
+		return c, nil
+	}
+	return "", errors.Errorf("unsupported component %q, must be %s, %s or %s", name, ComponentUI, ComponentMinio, ComponentRegistry)
+}
@@ -5,6 +5,7 @@ const KotsadmLabelValue = "true"
 
 const ClusterTokenSecret = "kotsadm-cluster-token"
 const PrivateKotsadmRegistrySecret = "kotsadm-private-registry"
+const ManifestSigningSecret = "kotsadm-manifest-signing"
 const KotsadmConfigMap = "kotsadm-confg"
 
 const ExcludeKey = "velero.io/exclude-from-backup"
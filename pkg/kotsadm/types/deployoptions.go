@@ -5,6 +5,7 @@ import (
 	"time"
 
 	kotsv1beta1 "github.com/replicatedhq/kots/kotskinds/apis/kots/v1beta1"
+	"github.com/replicatedhq/kots/pkg/endpoints"
 	corev1 "k8s.io/api/core/v1"
 )
 
@@ -26,6 +27,7 @@ type DeployOptions struct {
 	IsOpenShift               bool
 	License                   *kotsv1beta1.License
 	ConfigValues              *kotsv1beta1.ConfigValues
+	AdditionalManifests       map[string][]byte
 	Airgap                    bool
 	AirgapRootDir             string
 	AirgapBundle              string
@@ -33,20 +35,47 @@ type DeployOptions struct {
 	ProgressWriter            io.Writer
 	StorageBaseURI            string
 	StorageBaseURIPlainHTTP   bool
+	StorageGCSServiceAccount  string
+	StorageAzureAccountKey    string
+	MigrateStorageFromURI     string
 	IncludeMinio              bool
 	IncludeDockerDistribution bool
 	Timeout                   time.Duration
 	HTTPProxyEnvValue         string
 	HTTPSProxyEnvValue        string
 	NoProxyEnvValue           string
+	ProxyAuthScheme           string
+	ProxyUsername             string
+	ProxyPassword             string
 	ExcludeAdminConsole       bool
 	EnsureKotsadmConfig       bool
 	SkipPreflights            bool
 	EnsureRBAC                bool
 	InstallID                 string
 	SimultaneousUploads       int
-	DisableImagePush          bool
-	UpstreamURI               string
+	// ImagePushWorkers is the number of jobs that push the images of airgap bundles uploaded to the admin console
+	ImagePushWorkers        int
+	DisableImagePush        bool
+	AllImageArchitectures   bool
+	ImagePushBandwidthLimit int64
+	EnableNetworkPolicies   bool
+	CABundle                []byte
+	UpstreamURI             string
+	ForcePasswordUpdate     bool
+	ReadOnlyConsole         bool
+	Headless                bool
+	SessionTTL              time.Duration
+	SessionIdleTimeout      time.Duration
+	SessionReauthWindow     time.Duration
+	CORSAllowedOrigins      []string
+	EndpointMirrors         endpoints.Mirrors
+	HostAliases             []corev1.HostAlias
+	PodSecurityProfile      PodSecurityProfile
+
+	// StorageAzureIdentityBinding is the aad pod identity binding of the managed identity used to access azure storage
+	StorageAzureIdentityBinding string
+	// ServiceAccountAnnotations bind the kotsadm service account to a cloud identity, e.g. iam roles for service accounts
+	ServiceAccountAnnotations map[string]string
 
 	IdentityConfig kotsv1beta1.IdentityConfig
 	IngressConfig  kotsv1beta1.IngressConfig
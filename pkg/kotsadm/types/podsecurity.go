@@ -0,0 +1,20 @@
+package types
+
+// PodSecurityProfile is the Pod Security Standard that the admin console pods are generated for
+type PodSecurityProfile string
+
+const (
+	// PodSecurityProfileAuto selects the restricted profile if the namespace enforces it with pod security admission
+	PodSecurityProfileAuto PodSecurityProfile = "auto"
+	// PodSecurityProfileDefault only sets the user the pods run as
+	PodSecurityProfileDefault PodSecurityProfile = "default"
+	// PodSecurityProfileRestricted satisfies the restricted Pod Security Standard: pods run as non-root with a
+	// read-only root filesystem, the runtime default seccomp profile and all capabilities dropped
+	PodSecurityProfileRestricted PodSecurityProfile = "restricted"
+)
+
+const (
+	PodSecurityEnforceLabel = "pod-security.kubernetes.io/enforce"
+	PodSecurityWarnLabel    = "pod-security.kubernetes.io/warn"
+	PodSecurityAuditLabel   = "pod-security.kubernetes.io/audit"
+)
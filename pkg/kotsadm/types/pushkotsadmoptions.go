@@ -14,6 +14,12 @@ type PushImagesOptions struct {
 	Log            *logger.CLILogger
 	ProgressWriter io.Writer
 	LogForUI       bool
+	// Architectures to push from multi-arch images. The architectures of the nodes in the cluster are used if empty.
+	Architectures []string
+	// AllArchitectures pushes every architecture of multi-arch images
+	AllArchitectures bool
+	// BandwidthLimit is the maximum push rate in bytes per second, 0 is unlimited
+	BandwidthLimit int64
 }
 
 type ImageFile struct {
@@ -2,6 +2,8 @@ package types
 
 import (
 	"time"
+
+	corev1 "k8s.io/api/core/v1"
 )
 
 type UpgradeOptions struct {
@@ -10,10 +12,16 @@ type UpgradeOptions struct {
 	Timeout                   time.Duration
 	EnsureRBAC                bool
 	SimultaneousUploads       int
+	ImagePushWorkers          int
 	StorageBaseURI            string
 	StorageBaseURIPlainHTTP   bool
+	StorageGCSServiceAccount  string
+	StorageAzureAccountKey    string
+	MigrateStorage            bool
 	IncludeMinio              bool
 	IncludeDockerDistribution bool
+	HostAliases               []corev1.HostAlias
+	PodSecurityProfile        PodSecurityProfile
 
 	KotsadmOptions KotsadmOptions
 }
@@ -156,3 +156,39 @@ func ReadConfigValuesFromInClusterSecret() (string, error) {
 
 	return "", nil
 }
+
+// ReadAdditionalManifestsFromInClusterSecret returns the manifests supplied with kots install --additional-manifests,
+// keyed by file name, and deletes the secret they were handed over in
+func ReadAdditionalManifestsFromInClusterSecret() (map[string][]byte, error) {
+	log := logger.NewCLILogger()
+
+	clientset, err := k8sutil.GetClientset()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get k8s client set")
+	}
+
+	additionalManifestsSecrets, err := clientset.CoreV1().Secrets(os.Getenv("POD_NAMESPACE")).List(context.TODO(), metav1.ListOptions{
+		LabelSelector: "kots.io/automation=additionalmanifests",
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list additional manifests secrets")
+	}
+
+	// just get the first
+	for _, additionalManifestsSecret := range additionalManifestsSecrets.Items {
+		if len(additionalManifestsSecret.Data) == 0 {
+			log.Error(errors.Errorf("additional manifests secret %q does not contain any manifests", additionalManifestsSecret.Name))
+			continue
+		}
+
+		// delete it, these are one time use secrets
+		err = clientset.CoreV1().Secrets(additionalManifestsSecret.Namespace).Delete(context.TODO(), additionalManifestsSecret.Name, metav1.DeleteOptions{})
+		if err != nil {
+			log.Error(errors.Errorf("error deleting additional manifests secret: %v", err))
+		}
+
+		return additionalManifestsSecret.Data, nil
+	}
+
+	return nil, nil
+}
@@ -1,6 +1,7 @@
 package license
 
 import (
+	"fmt"
 	"io/ioutil"
 	"os"
 	"path/filepath"
@@ -8,13 +9,17 @@ import (
 	"github.com/pkg/errors"
 	kotsv1beta1 "github.com/replicatedhq/kots/kotskinds/apis/kots/v1beta1"
 	apptypes "github.com/replicatedhq/kots/pkg/app/types"
+	"github.com/replicatedhq/kots/pkg/applock"
 	"github.com/replicatedhq/kots/pkg/kotsutil"
 	kotslicense "github.com/replicatedhq/kots/pkg/license"
+	"github.com/replicatedhq/kots/pkg/logger"
 	"github.com/replicatedhq/kots/pkg/preflight"
 	kotspull "github.com/replicatedhq/kots/pkg/pull"
 	"github.com/replicatedhq/kots/pkg/render"
 	"github.com/replicatedhq/kots/pkg/store"
+	"github.com/replicatedhq/kots/pkg/util"
 	"github.com/replicatedhq/kots/pkg/version"
+	"go.uber.org/zap"
 	"k8s.io/client-go/kubernetes/scheme"
 )
 
@@ -49,6 +54,18 @@ func Sync(a *apptypes.App, licenseString string, failOnVersionCreate bool) (*kot
 		licenseString = string(licenseData.LicenseBytes)
 	}
 
+	unlock, err := applock.TryLock(a.ID)
+	if err != nil {
+		return nil, false, errors.Wrap(err, "failed to lock app")
+	}
+	defer unlock()
+
+	// the current sequence may have changed while the lock was not held
+	a, err = store.GetStore().GetApp(a.ID)
+	if err != nil {
+		return nil, false, errors.Wrap(err, "failed to get app")
+	}
+
 	archiveDir, err := ioutil.TempDir("", "kotsadm")
 	if err != nil {
 		return nil, false, errors.Wrap(err, "failed to create temp dir")
@@ -84,20 +101,108 @@ func Sync(a *apptypes.App, licenseString string, failOnVersionCreate bool) (*kot
 	return updatedLicense, synced, nil
 }
 
-// Gets the license as it was at a given app sequence
-func GetCurrentLicenseString(a *apptypes.App) (string, error) {
+// Replace installs a different license for the app, for example when the customer is issued a new license. The new
+// license must be for the same app and not be installed for another app. The replaced license is archived, and a new
+// version is created with the entitlements of the new license, with the changed fields in its source.
+func Replace(a *apptypes.App, licenseString string, replacedBy string) (*kotsv1beta1.License, int64, error) {
+	unverifiedLicense, err := GetParsedLicense(licenseString)
+	if err != nil {
+		return nil, 0, util.ActionableError{Message: "the license file could not be parsed"}
+	}
+	newLicense, err := kotspull.VerifySignature(unverifiedLicense)
+	if err != nil {
+		return nil, 0, util.ActionableError{Message: "the license signature is not valid"}
+	}
+
+	currentLicense, err := store.GetStore().GetLatestLicenseForApp(a.ID)
+	if err != nil {
+		return nil, 0, errors.Wrap(err, "failed to get current license")
+	}
+	if err := validateReplacementLicense(currentLicense, newLicense); err != nil {
+		return nil, 0, err
+	}
+
+	allLicenses, err := store.GetStore().GetAllAppLicenses()
+	if err != nil {
+		return nil, 0, errors.Wrap(err, "failed to get all app licenses")
+	}
+	existingLicense, err := CheckDoesLicenseExists(allLicenses, licenseString)
+	if err != nil {
+		return nil, 0, errors.Wrap(err, "failed to check if license exists")
+	}
+	if existingLicense != nil {
+		return nil, 0, util.ActionableError{Message: "the license is already installed for another app"}
+	}
+
+	unlock, err := applock.TryLock(a.ID)
+	if err != nil {
+		return nil, 0, errors.Wrap(err, "failed to lock app")
+	}
+	defer unlock()
+
+	// the current sequence may have changed while the lock was not held
+	a, err = store.GetStore().GetApp(a.ID)
+	if err != nil {
+		return nil, 0, errors.Wrap(err, "failed to get app")
+	}
+
 	archiveDir, err := ioutil.TempDir("", "kotsadm")
 	if err != nil {
-		return "", errors.Wrap(err, "failed to create temp dir")
+		return nil, 0, errors.Wrap(err, "failed to create temp dir")
 	}
 	defer os.RemoveAll(archiveDir)
 
-	err = store.GetStore().GetAppVersionArchive(a.ID, a.CurrentSequence, archiveDir)
+	if err := store.GetStore().GetAppVersionArchive(a.ID, a.CurrentSequence, archiveDir); err != nil {
+		return nil, 0, errors.Wrap(err, "failed to get latest app version")
+	}
+
+	if err := store.GetStore().ArchiveAppLicense(a.ID, newLicense.Spec.LicenseID, replacedBy); err != nil {
+		return nil, 0, errors.Wrap(err, "failed to archive current license")
+	}
+
+	newSequence, err := store.GetStore().UpdateAppLicense(a.ID, a.CurrentSequence, archiveDir, newLicense, licenseString, true, &version.DownstreamGitOps{}, &render.Renderer{})
 	if err != nil {
-		return "", errors.Wrap(err, "failed to get latest app version")
+		return nil, 0, errors.Wrap(err, "failed to update license")
+	}
+
+	logger.Info("license replaced",
+		zap.String("audit", "license-replace"),
+		zap.String("appID", a.ID),
+		zap.String("previousLicenseID", currentLicense.Spec.LicenseID),
+		zap.String("licenseID", newLicense.Spec.LicenseID),
+		zap.Int64("sequence", newSequence),
+		zap.String("user", replacedBy))
+
+	if err := preflight.Run(a.ID, a.Slug, newSequence, a.IsAirgap, archiveDir); err != nil {
+		return nil, 0, errors.Wrap(err, "failed to run preflights")
 	}
 
-	kotsLicense, err := ioutil.ReadFile(filepath.Join(archiveDir, "upstream", "userdata", "license.yaml"))
+	return newLicense, newSequence, nil
+}
+
+// validateReplacementLicense returns an actionable error if the new license cannot replace the current license
+func validateReplacementLicense(currentLicense *kotsv1beta1.License, newLicense *kotsv1beta1.License) error {
+	if newLicense.Spec.AppSlug != currentLicense.Spec.AppSlug {
+		return util.ActionableError{Message: fmt.Sprintf("the license is for app %q, not %q", newLicense.Spec.AppSlug, currentLicense.Spec.AppSlug)}
+	}
+	if newLicense.Spec.LicenseID == currentLicense.Spec.LicenseID {
+		return util.ActionableError{Message: "the license is already installed, sync it to get its latest version"}
+	}
+
+	expired, err := kotspull.LicenseIsExpired(newLicense)
+	if err != nil {
+		return errors.Wrap(err, "failed to check if license is expired")
+	}
+	if expired {
+		return util.ActionableError{Message: "the license is expired"}
+	}
+
+	return nil
+}
+
+// Gets the license as it was at a given app sequence
+func GetCurrentLicenseString(a *apptypes.App) (string, error) {
+	kotsLicense, err := version.GetArchiveFile(a.ID, a.CurrentSequence, filepath.Join("upstream", "userdata", "license.yaml"))
 	if err != nil {
 		return "", errors.Wrap(err, "failed to read license file from archive")
 	}
@@ -0,0 +1,68 @@
+package license
+
+import (
+	"testing"
+
+	"github.com/pkg/errors"
+	kotsv1beta1 "github.com/replicatedhq/kots/kotskinds/apis/kots/v1beta1"
+	"github.com/replicatedhq/kots/pkg/util"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_validateReplacementLicense(t *testing.T) {
+	newLicense := func(appSlug string, licenseID string, expiresAt string) *kotsv1beta1.License {
+		license := &kotsv1beta1.License{
+			Spec: kotsv1beta1.LicenseSpec{
+				AppSlug:      appSlug,
+				LicenseID:    licenseID,
+				Entitlements: map[string]kotsv1beta1.EntitlementField{},
+			},
+		}
+		if expiresAt != "" {
+			license.Spec.Entitlements["expires_at"] = kotsv1beta1.EntitlementField{
+				Value: kotsv1beta1.EntitlementValue{Type: kotsv1beta1.String, StrVal: expiresAt},
+			}
+		}
+		return license
+	}
+
+	current := newLicense("my-app", "license-1", "")
+
+	tests := []struct {
+		name       string
+		newLicense *kotsv1beta1.License
+		wantErr    bool
+	}{
+		{
+			name:       "different license for the same app",
+			newLicense: newLicense("my-app", "license-2", "2099-01-01T00:00:00Z"),
+		},
+		{
+			name:       "license for another app",
+			newLicense: newLicense("other-app", "license-2", ""),
+			wantErr:    true,
+		},
+		{
+			name:       "same license",
+			newLicense: newLicense("my-app", "license-1", ""),
+			wantErr:    true,
+		},
+		{
+			name:       "expired license",
+			newLicense: newLicense("my-app", "license-2", "2001-01-01T00:00:00Z"),
+			wantErr:    true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := validateReplacementLicense(current, test.newLicense)
+			if !test.wantErr {
+				require.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			_, ok := errors.Cause(err).(util.ActionableError)
+			require.True(t, ok)
+		})
+	}
+}
@@ -4,7 +4,6 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"io/ioutil"
 	"math"
 	"os"
 	"strconv"
@@ -23,6 +22,7 @@ import (
 	"github.com/replicatedhq/kots/pkg/render/helper"
 	kotssnapshot "github.com/replicatedhq/kots/pkg/snapshot"
 	"github.com/replicatedhq/kots/pkg/store"
+	"github.com/replicatedhq/kots/pkg/version"
 	velerov1 "github.com/vmware-tanzu/velero/pkg/apis/velero/v1"
 	veleroclientv1 "github.com/vmware-tanzu/velero/pkg/generated/clientset/versioned/typed/velero/v1"
 	velerolabel "github.com/vmware-tanzu/velero/pkg/label"
@@ -52,26 +52,15 @@ func CreateApplicationBackup(ctx context.Context, a *apptypes.App, isScheduled b
 		zap.String("appID", a.ID),
 		zap.Int64("sequence", parentSequence))
 
-	archiveDir, err := ioutil.TempDir("", "kotsadm")
-	if err != nil {
-		return nil, errors.Wrap(err, "failed to create temp dir")
-	}
-	defer os.RemoveAll(archiveDir)
-
-	err = store.GetStore().GetAppVersionArchive(a.ID, parentSequence, archiveDir)
-	if err != nil {
-		return nil, errors.Wrap(err, "failed to get app version archive")
-	}
-
 	kotsadmNamespace := os.Getenv("POD_NAMESPACE")
 	kotsadmVeleroBackendStorageLocation, err := kotssnapshot.FindBackupStoreLocation(ctx, kotsadmNamespace)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to find backupstoragelocations")
 	}
 
-	kotsKinds, err := kotsutil.LoadKotsKindsFromPath(archiveDir)
+	kotsKinds, err := version.GetKotsKinds(a.ID, parentSequence)
 	if err != nil {
-		return nil, errors.Wrap(err, "failed to load kots kinds from path")
+		return nil, errors.Wrap(err, "failed to load kots kinds from archive")
 	}
 
 	backupSpec, err := kotsKinds.Marshal("velero.io", "v1", "Backup")
@@ -91,6 +80,9 @@ func CreateApplicationBackup(ctx context.Context, a *apptypes.App, isScheduled b
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to load backup from contents")
 	}
+	if err := addFreezeHooks(veleroBackup, a.Slug); err != nil {
+		return nil, errors.Wrap(err, "failed to add freeze hooks")
+	}
 
 	appNamespace := kotsadmNamespace
 	if os.Getenv("KOTSADM_TARGET_NAMESPACE") != "" {
@@ -101,7 +93,7 @@ func CreateApplicationBackup(ctx context.Context, a *apptypes.App, isScheduled b
 		veleroBackup.Spec.IncludedNamespaces = []string{}
 	}
 	veleroBackup.Spec.IncludedNamespaces = append(veleroBackup.Spec.IncludedNamespaces, appNamespace)
-	veleroBackup.Spec.IncludedNamespaces = append(veleroBackup.Spec.IncludedNamespaces, kotsKinds.KotsApplication.Spec.AdditionalNamespaces...)
+	veleroBackup.Spec.IncludedNamespaces = append(veleroBackup.Spec.IncludedNamespaces, kotsutil.AdditionalNamespaces(&kotsKinds.KotsApplication)...)
 
 	snapshotTrigger := "manual"
 	if isScheduled {
@@ -217,20 +209,9 @@ func CreateInstanceBackup(ctx context.Context, cluster *downstreamtypes.Downstre
 			continue
 		}
 
-		archiveDir, err := ioutil.TempDir("", "kotsadm")
+		kotsKinds, err := version.GetKotsKinds(a.ID, parentSequence)
 		if err != nil {
-			return nil, errors.Wrapf(err, "failed to create temp dir for app %s", a.Slug)
-		}
-		defer os.RemoveAll(archiveDir)
-
-		err = store.GetStore().GetAppVersionArchive(a.ID, parentSequence, archiveDir)
-		if err != nil {
-			return nil, errors.Wrapf(err, "failed to get app version archive for app %s", a.Slug)
-		}
-
-		kotsKinds, err := kotsutil.LoadKotsKindsFromPath(archiveDir)
-		if err != nil {
-			return nil, errors.Wrap(err, "failed to load kots kinds from path")
+			return nil, errors.Wrapf(err, "failed to load kots kinds for app %s", a.Slug)
 		}
 
 		backupSpec, err := kotsKinds.Marshal("velero.io", "v1", "Backup")
@@ -252,12 +233,15 @@ func CreateInstanceBackup(ctx context.Context, cluster *downstreamtypes.Downstre
 		if err != nil {
 			return nil, errors.Wrap(err, "failed to load backup from contents")
 		}
+		if err := addFreezeHooks(veleroBackup, a.Slug); err != nil {
+			return nil, errors.Wrapf(err, "failed to add freeze hooks for app %s", a.Slug)
+		}
 
 		// ** merge app backup info ** //
 
 		// included namespaces
 		includedNamespaces = append(includedNamespaces, veleroBackup.Spec.IncludedNamespaces...)
-		includedNamespaces = append(includedNamespaces, kotsKinds.KotsApplication.Spec.AdditionalNamespaces...)
+		includedNamespaces = append(includedNamespaces, kotsutil.AdditionalNamespaces(&kotsKinds.KotsApplication)...)
 
 		// excluded namespaces
 		excludedNamespaces = append(excludedNamespaces, veleroBackup.Spec.ExcludedNamespaces...)
@@ -0,0 +1,166 @@
+package snapshot
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/replicatedhq/kots/pkg/k8sutil"
+	"github.com/replicatedhq/kots/pkg/kotsadmsnapshot/types"
+	kotssnapshot "github.com/replicatedhq/kots/pkg/snapshot"
+	velerov1 "github.com/vmware-tanzu/velero/pkg/apis/velero/v1"
+	veleroclientv1 "github.com/vmware-tanzu/velero/pkg/generated/clientset/versioned/typed/velero/v1"
+	velerolabel "github.com/vmware-tanzu/velero/pkg/label"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var ErrBackupNotCompleted = errors.New("backup is not completed")
+
+// volumeSnapshot is the part of a velero volume snapshot in the backup contents that is listed
+type volumeSnapshot struct {
+	Spec struct {
+		PersistentVolumeName string `json:"persistentVolumeName"`
+	} `json:"spec"`
+	Status struct {
+		Phase string `json:"phase"`
+	} `json:"status"`
+}
+
+// GetBackupContents returns the resources and volumes in a backup, as listed in the backup contents velero uploaded
+// to the storage location. ErrBackupNotCompleted is returned if velero is not done with the backup.
+func GetBackupContents(ctx context.Context, kotsadmNamespace string, backupName string) (*types.BackupContents, error) {
+	cfg, err := k8sutil.GetClusterConfig()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get cluster config")
+	}
+
+	veleroClient, err := veleroclientv1.NewForConfig(cfg)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create clientset")
+	}
+
+	backendStorageLocation, err := kotssnapshot.FindBackupStoreLocation(ctx, kotsadmNamespace)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to find backupstoragelocations")
+	}
+
+	veleroNamespace := backendStorageLocation.Namespace
+
+	backup, err := veleroClient.Backups(veleroNamespace).Get(ctx, backupName, metav1.GetOptions{})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get backup")
+	}
+	if backup.Status.Phase != velerov1.BackupPhaseCompleted && backup.Status.Phase != velerov1.BackupPhasePartiallyFailed {
+		return nil, ErrBackupNotCompleted
+	}
+
+	resourceList, err := downloadBackupResourceList(veleroNamespace, backupName)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to download backup resource list")
+	}
+
+	contents := &types.BackupContents{
+		Name:      backup.Name,
+		Status:    string(backup.Status.Phase),
+		Resources: listBackupResources(resourceList),
+		Volumes:   []types.BackupContentsVolume{},
+	}
+
+	backupVolumes, err := veleroClient.PodVolumeBackups(veleroNamespace).List(ctx, metav1.ListOptions{
+		LabelSelector: fmt.Sprintf("velero.io/backup-name=%s", velerolabel.GetValidName(backupName)),
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list volumes")
+	}
+	for _, backupVolume := range backupVolumes.Items {
+		contents.Volumes = append(contents.Volumes, types.BackupContentsVolume{
+			Type:         "restic",
+			Name:         backupVolume.Spec.Volume,
+			PodName:      backupVolume.Spec.Pod.Name,
+			PodNamespace: backupVolume.Spec.Pod.Namespace,
+			SizeBytes:    backupVolume.Status.Progress.TotalBytes,
+			Phase:        string(backupVolume.Status.Phase),
+		})
+	}
+
+	// velero only uploads the volume snapshots file if it attempted snapshots
+	if backup.Status.VolumeSnapshotsAttempted > 0 {
+		snapshots, err := downloadBackupVolumeSnapshots(veleroNamespace, backupName)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to download backup volume snapshots")
+		}
+		for _, snapshot := range snapshots {
+			contents.Volumes = append(contents.Volumes, types.BackupContentsVolume{
+				Type:  "snapshot",
+				Name:  snapshot.Spec.PersistentVolumeName,
+				Phase: snapshot.Status.Phase,
+			})
+		}
+	}
+
+	return contents, nil
+}
+
+func downloadBackupResourceList(veleroNamespace string, backupName string) (map[string][]string, error) {
+	r, err := DownloadRequest(veleroNamespace, velerov1.DownloadTargetKindBackupResourceList, backupName)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to make download request")
+	}
+	defer r.Close()
+
+	resourceList := map[string][]string{}
+	if err := json.NewDecoder(r).Decode(&resourceList); err != nil {
+		return nil, errors.Wrap(err, "failed to decode resource list")
+	}
+
+	return resourceList, nil
+}
+
+func downloadBackupVolumeSnapshots(veleroNamespace string, backupName string) ([]volumeSnapshot, error) {
+	r, err := DownloadRequest(veleroNamespace, velerov1.DownloadTargetKindBackupVolumeSnapshots, backupName)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to make download request")
+	}
+	defer r.Close()
+
+	snapshots := []volumeSnapshot{}
+	if err := json.NewDecoder(r).Decode(&snapshots); err != nil {
+		return nil, errors.Wrap(err, "failed to decode volume snapshots")
+	}
+
+	return snapshots, nil
+}
+
+// listBackupResources converts the velero resource list, which maps a group version kind to "namespace/name" or
+// just "name" for cluster scoped resources, to a sorted list of resources
+func listBackupResources(resourceList map[string][]string) []types.BackupResource {
+	resources := []types.BackupResource{}
+	for gvk, names := range resourceList {
+		for _, name := range names {
+			resource := types.BackupResource{
+				GroupVersionKind: gvk,
+				Name:             name,
+			}
+			if parts := strings.SplitN(name, "/", 2); len(parts) == 2 {
+				resource.Namespace = parts[0]
+				resource.Name = parts[1]
+			}
+			resources = append(resources, resource)
+		}
+	}
+
+	sort.Slice(resources, func(i, j int) bool {
+		if resources[i].GroupVersionKind != resources[j].GroupVersionKind {
+			return resources[i].GroupVersionKind < resources[j].GroupVersionKind
+		}
+		if resources[i].Namespace != resources[j].Namespace {
+			return resources[i].Namespace < resources[j].Namespace
+		}
+		return resources[i].Name < resources[j].Name
+	})
+
+	return resources
+}
@@ -0,0 +1,32 @@
+package snapshot
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/replicatedhq/kots/pkg/kotsadmsnapshot/types"
+)
+
+func TestListBackupResources(t *testing.T) {
+	resourceList := map[string][]string{
+		"v1/Secret":          {"default/db-creds"},
+		"apps/v1/Deployment": {"default/web", "default/api"},
+		"rbac.authorization.k8s.io/v1/ClusterRole": {"my-app"},
+	}
+
+	want := []types.BackupResource{
+		{GroupVersionKind: "apps/v1/Deployment", Namespace: "default", Name: "api"},
+		{GroupVersionKind: "apps/v1/Deployment", Namespace: "default", Name: "web"},
+		{GroupVersionKind: "rbac.authorization.k8s.io/v1/ClusterRole", Name: "my-app"},
+		{GroupVersionKind: "v1/Secret", Namespace: "default", Name: "db-creds"},
+	}
+
+	got := listBackupResources(resourceList)
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected %v, got %v", want, got)
+	}
+
+	if got := listBackupResources(nil); len(got) != 0 {
+		t.Errorf("Expected no resources, got %v", got)
+	}
+}
@@ -0,0 +1,187 @@
+package snapshot
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	apptypes "github.com/replicatedhq/kots/pkg/app/types"
+	"github.com/replicatedhq/kots/pkg/kotsutil"
+	"github.com/replicatedhq/kots/pkg/render/helper"
+	"github.com/replicatedhq/kots/pkg/version"
+	velerov1 "github.com/vmware-tanzu/velero/pkg/apis/velero/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// The freeze annotations on the Backup spec of an app quiesce the pods of a database while their volumes are backed
+// up, so that the snapshot is consistent rather than crash-consistent. The pods are selected with the label selector
+// in FreezeSelectorAnnotation, the filesystems in FreezePathsAnnotation are frozen with fsfreeze, or the commands in
+// FreezeCommandAnnotation and ThawCommandAnnotation are run with /bin/sh.
+const (
+	FreezeSelectorAnnotation  = "kots.io/backup-freeze-selector"
+	FreezeContainerAnnotation = "kots.io/backup-freeze-container"
+	FreezePathsAnnotation     = "kots.io/backup-freeze-paths"
+	FreezeCommandAnnotation   = "kots.io/backup-freeze-command"
+	ThawCommandAnnotation     = "kots.io/backup-thaw-command"
+	// FreezeTimeoutAnnotation is the timeout of each freeze and thaw command, velero's default is used if not set
+	FreezeTimeoutAnnotation = "kots.io/backup-freeze-timeout"
+)
+
+var freezeAnnotations = []string{
+	FreezeSelectorAnnotation,
+	FreezeContainerAnnotation,
+	FreezePathsAnnotation,
+	FreezeCommandAnnotation,
+	ThawCommandAnnotation,
+	FreezeTimeoutAnnotation,
+}
+
+// addFreezeHooks adds the hooks of the freeze annotations of the backup to its spec and removes the annotations.
+// The hooks only select the pods of the app.
+func addFreezeHooks(veleroBackup *velerov1.Backup, appSlug string) error {
+	hook, err := freezeHook(veleroBackup.Annotations, appSlug)
+	if err != nil {
+		return err
+	}
+
+	for _, annotation := range freezeAnnotations {
+		delete(veleroBackup.Annotations, annotation)
+	}
+
+	if hook != nil {
+		veleroBackup.Spec.Hooks.Resources = append(veleroBackup.Spec.Hooks.Resources, *hook)
+	}
+
+	return nil
+}
+
+// freezeHook returns the backup hook of the freeze annotations, or nil if the selector annotation is not set.
+// The backup fails if a pod can't be frozen, the thaw is attempted regardless.
+func freezeHook(annotations map[string]string, appSlug string) (*velerov1.BackupResourceHookSpec, error) {
+	selector := annotations[FreezeSelectorAnnotation]
+	if selector == "" {
+		return nil, nil
+	}
+
+	labelSelector, err := metav1.ParseToLabelSelector(selector)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to parse %s", FreezeSelectorAnnotation)
+	}
+	if labelSelector.MatchLabels == nil {
+		labelSelector.MatchLabels = map[string]string{}
+	}
+	labelSelector.MatchLabels["kots.io/app-slug"] = appSlug
+
+	timeout := metav1.Duration{}
+	if value := annotations[FreezeTimeoutAnnotation]; value != "" {
+		d, err := time.ParseDuration(value)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to parse %s", FreezeTimeoutAnnotation)
+		}
+		timeout.Duration = d
+	}
+
+	freezeCommands := [][]string{}
+	thawCommands := [][]string{}
+	for _, path := range strings.Split(annotations[FreezePathsAnnotation], ",") {
+		path = strings.TrimSpace(path)
+		if path == "" {
+			continue
+		}
+		freezeCommands = append(freezeCommands, []string{"/sbin/fsfreeze", "--freeze", path})
+		thawCommands = append(thawCommands, []string{"/sbin/fsfreeze", "--unfreeze", path})
+	}
+	if command := annotations[FreezeCommandAnnotation]; command != "" {
+		freezeCommands = append(freezeCommands, []string{"/bin/sh", "-c", command})
+	}
+	if command := annotations[ThawCommandAnnotation]; command != "" {
+		thawCommands = append(thawCommands, []string{"/bin/sh", "-c", command})
+	}
+
+	if len(freezeCommands) == 0 {
+		return nil, errors.Errorf("%s requires %s or %s", FreezeSelectorAnnotation, FreezePathsAnnotation, FreezeCommandAnnotation)
+	}
+
+	container := annotations[FreezeContainerAnnotation]
+	hook := &velerov1.BackupResourceHookSpec{
+		Name:              fmt.Sprintf("%s-freeze", appSlug),
+		IncludedResources: []string{"pods"},
+		LabelSelector:     labelSelector,
+	}
+	for _, command := range freezeCommands {
+		hook.PreHooks = append(hook.PreHooks, velerov1.BackupResourceHook{
+			Exec: &velerov1.ExecHook{
+				Container: container,
+				Command:   command,
+				OnError:   velerov1.HookErrorModeFail,
+				Timeout:   timeout,
+			},
+		})
+	}
+	// thaw in the reverse order of the freeze
+	for i := len(thawCommands) - 1; i >= 0; i-- {
+		hook.PostHooks = append(hook.PostHooks, velerov1.BackupResourceHook{
+			Exec: &velerov1.ExecHook{
+				Container: container,
+				Command:   thawCommands[i],
+				OnError:   velerov1.HookErrorModeContinue,
+				Timeout:   timeout,
+			},
+		})
+	}
+
+	return hook, nil
+}
+
+// getAppRestoreHooks returns the hooks of the Restore spec of the version of the app that was backed up, the hooks
+// are empty if the app doesn't have a Restore spec
+func getAppRestoreHooks(a *apptypes.App, sequence int64) (velerov1.RestoreHooks, error) {
+	kotsKinds, err := version.GetKotsKinds(a.ID, sequence)
+	if err != nil {
+		return velerov1.RestoreHooks{}, errors.Wrap(err, "failed to load kots kinds from archive")
+	}
+
+	restoreSpec, err := kotsKinds.Marshal("velero.io", "v1", "Restore")
+	if err != nil {
+		return velerov1.RestoreHooks{}, errors.Wrap(err, "failed to get restore spec from kotskinds")
+	}
+	if restoreSpec == "" {
+		return velerov1.RestoreHooks{}, nil
+	}
+
+	renderedRestore, err := helper.RenderAppFile(a, nil, []byte(restoreSpec), kotsKinds)
+	if err != nil {
+		return velerov1.RestoreHooks{}, errors.Wrap(err, "failed to render restore")
+	}
+	veleroRestore, err := kotsutil.LoadRestoreFromContents(renderedRestore)
+	if err != nil {
+		return velerov1.RestoreHooks{}, errors.Wrap(err, "failed to load restore from contents")
+	}
+
+	return veleroRestore.Spec.Hooks, nil
+}
+
+// BackedUpSequence returns the sequence of the app that is in the backup. The returned bool is false if the backup
+// doesn't include the app.
+func BackedUpSequence(annotations map[string]string, appID string, appSlug string) (int64, bool, error) {
+	if annotations["kots.io/instance"] == "true" {
+		appsSequences := map[string]int64{}
+		if err := json.Unmarshal([]byte(annotations["kots.io/apps-sequences"]), &appsSequences); err != nil {
+			return 0, false, errors.Wrap(err, "failed to unmarshal apps sequences")
+		}
+		sequence, ok := appsSequences[appSlug]
+		return sequence, ok, nil
+	}
+
+	if annotations["kots.io/app-id"] != appID {
+		return 0, false, nil
+	}
+	sequence, err := strconv.ParseInt(annotations["kots.io/app-sequence"], 10, 64)
+	if err != nil {
+		return 0, false, errors.Wrap(err, "failed to parse app sequence")
+	}
+	return sequence, true, nil
+}
@@ -0,0 +1,113 @@
+package snapshot
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	velerov1 "github.com/vmware-tanzu/velero/pkg/apis/velero/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestFreezeHook(t *testing.T) {
+	hook, err := freezeHook(map[string]string{}, "my-app")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if hook != nil {
+		t.Errorf("Expected no hook without a selector, got %+v", hook)
+	}
+
+	hook, err = freezeHook(map[string]string{
+		FreezeSelectorAnnotation:  "app=postgres",
+		FreezeContainerAnnotation: "postgres",
+		FreezePathsAnnotation:     "/var/lib/postgresql, /var/log/postgresql",
+		FreezeCommandAnnotation:   "psql -c CHECKPOINT",
+		FreezeTimeoutAnnotation:   "1m",
+	}, "my-app")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	wantSelector := map[string]string{"app": "postgres", "kots.io/app-slug": "my-app"}
+	if !reflect.DeepEqual(hook.LabelSelector.MatchLabels, wantSelector) {
+		t.Errorf("Expected selector %v, got %v", wantSelector, hook.LabelSelector.MatchLabels)
+	}
+
+	wantFreeze := [][]string{
+		{"/sbin/fsfreeze", "--freeze", "/var/lib/postgresql"},
+		{"/sbin/fsfreeze", "--freeze", "/var/log/postgresql"},
+		{"/bin/sh", "-c", "psql -c CHECKPOINT"},
+	}
+	if got := hookCommands(hook.PreHooks); !reflect.DeepEqual(got, wantFreeze) {
+		t.Errorf("Expected freeze commands %v, got %v", wantFreeze, got)
+	}
+	wantThaw := [][]string{
+		{"/sbin/fsfreeze", "--unfreeze", "/var/log/postgresql"},
+		{"/sbin/fsfreeze", "--unfreeze", "/var/lib/postgresql"},
+	}
+	if got := hookCommands(hook.PostHooks); !reflect.DeepEqual(got, wantThaw) {
+		t.Errorf("Expected thaw commands %v, got %v", wantThaw, got)
+	}
+
+	pre := hook.PreHooks[0].Exec
+	if pre.Container != "postgres" || pre.OnError != velerov1.HookErrorModeFail || pre.Timeout != (metav1.Duration{Duration: time.Minute}) {
+		t.Errorf("Unexpected freeze hook %+v", pre)
+	}
+	if hook.PostHooks[0].Exec.OnError != velerov1.HookErrorModeContinue {
+		t.Errorf("Expected thaw to continue on error, got %s", hook.PostHooks[0].Exec.OnError)
+	}
+
+	if _, err := freezeHook(map[string]string{FreezeSelectorAnnotation: "app=postgres"}, "my-app"); err == nil {
+		t.Error("Expected error for a selector without commands")
+	}
+}
+
+func hookCommands(hooks []velerov1.BackupResourceHook) [][]string {
+	commands := [][]string{}
+	for _, hook := range hooks {
+		commands = append(commands, hook.Exec.Command)
+	}
+	return commands
+}
+
+func TestBackedUpSequence(t *testing.T) {
+	tests := []struct {
+		name         string
+		annotations  map[string]string
+		wantSequence int64
+		wantOk       bool
+	}{
+		{
+			name:         "app backup",
+			annotations:  map[string]string{"kots.io/app-id": "app-id", "kots.io/app-sequence": "3"},
+			wantSequence: 3,
+			wantOk:       true,
+		},
+		{
+			name:        "backup of another app",
+			annotations: map[string]string{"kots.io/app-id": "other-id", "kots.io/app-sequence": "3"},
+		},
+		{
+			name:         "instance backup",
+			annotations:  map[string]string{"kots.io/instance": "true", "kots.io/apps-sequences": `{"my-app":5}`},
+			wantSequence: 5,
+			wantOk:       true,
+		},
+		{
+			name:        "instance backup without the app",
+			annotations: map[string]string{"kots.io/instance": "true", "kots.io/apps-sequences": `{"other-app":5}`},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			sequence, ok, err := BackedUpSequence(test.annotations, "app-id", "my-app")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if ok != test.wantOk || sequence != test.wantSequence {
+				t.Errorf("Expected %d %v, got %d %v", test.wantSequence, test.wantOk, sequence, ok)
+			}
+		})
+	}
+}
@@ -4,15 +4,18 @@ import (
 	"context"
 	"fmt"
 	"math"
+	"strconv"
 	"strings"
 	"time"
 
 	units "github.com/docker/go-units"
 	"github.com/pkg/errors"
+	apptypes "github.com/replicatedhq/kots/pkg/app/types"
 	"github.com/replicatedhq/kots/pkg/k8sutil"
 	"github.com/replicatedhq/kots/pkg/kotsadmsnapshot/types"
 	"github.com/replicatedhq/kots/pkg/logger"
 	kotssnapshot "github.com/replicatedhq/kots/pkg/snapshot"
+	"github.com/replicatedhq/kots/pkg/store"
 	velerov1 "github.com/vmware-tanzu/velero/pkg/apis/velero/v1"
 	veleroclientv1 "github.com/vmware-tanzu/velero/pkg/generated/clientset/versioned/typed/velero/v1"
 	velerolabel "github.com/vmware-tanzu/velero/pkg/label"
@@ -105,6 +108,103 @@ func CreateApplicationRestore(ctx context.Context, kotsadmNamespace string, snap
 		}
 	}
 
+	a, err := store.GetStore().GetAppFromSlug(appSlug)
+	if err != nil {
+		return errors.Wrap(err, "failed to get app from slug")
+	}
+	sequence, ok, err := BackedUpSequence(backup.Annotations, a.ID, appSlug)
+	if err != nil {
+		return errors.Wrap(err, "failed to get backed up sequence")
+	}
+	if ok {
+		// post-restore hooks of the app, e.g. to rebuild indexes after the data is restored
+		hooks, err := getAppRestoreHooks(a, sequence)
+		if err != nil {
+			return errors.Wrap(err, "failed to get restore hooks")
+		}
+		restore.Spec.Hooks = hooks
+	}
+
+	_, err = veleroClient.Restores(veleroNamespace).Create(ctx, restore, metav1.CreateOptions{})
+	if err != nil {
+		return errors.Wrap(err, "failed to create restore")
+	}
+
+	return nil
+}
+
+// The annotations of a restore into a clone of the app that was backed up
+const (
+	CloneRestoreSourceAnnotation = "kots.io/clone-source-app"
+	// RestoreSequenceAnnotation is the sequence of the clone that is marked as deployed when the restore completes
+	RestoreSequenceAnnotation = "kots.io/app-sequence"
+)
+
+type CloneRestoreOptions struct {
+	// SourceAppSlug and SourceNamespace are the app that was backed up and the namespace it is deployed to
+	SourceAppSlug   string
+	SourceNamespace string
+	// Sequence is the version of the clone that the restored resources belong to
+	Sequence int64
+}
+
+// CreateCloneRestore restores the resources of an app in the snapshot from the namespace of the app into the namespace
+// of a clone of the app. Cluster scoped resources and the additional namespaces of the app are not restored, they are
+// shared with the app that was backed up.
+func CreateCloneRestore(ctx context.Context, kotsadmNamespace string, snapshotName string, clone *apptypes.App, opts CloneRestoreOptions) error {
+	logger.Debug("creating clone restore",
+		zap.String("snapshotName", snapshotName),
+		zap.String("appSlug", clone.Slug))
+
+	bsl, err := kotssnapshot.FindBackupStoreLocation(ctx, kotsadmNamespace)
+	if err != nil {
+		return errors.Wrap(err, "failed to get velero namespace")
+	}
+
+	veleroNamespace := bsl.Namespace
+
+	cfg, err := k8sutil.GetClusterConfig()
+	if err != nil {
+		return errors.Wrap(err, "failed to get cluster config")
+	}
+
+	veleroClient, err := veleroclientv1.NewForConfig(cfg)
+	if err != nil {
+		return errors.Wrap(err, "failed to create clientset")
+	}
+
+	hooks, err := getAppRestoreHooks(clone, opts.Sequence)
+	if err != nil {
+		return errors.Wrap(err, "failed to get restore hooks")
+	}
+
+	trueVal, falseVal := true, false
+	restore := &velerov1.Restore{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: veleroNamespace,
+			Name:      fmt.Sprintf("%s.%s", snapshotName, clone.Slug),
+			Annotations: map[string]string{
+				CloneRestoreSourceAnnotation: opts.SourceAppSlug,
+				RestoreSequenceAnnotation:    strconv.FormatInt(opts.Sequence, 10),
+			},
+		},
+		Spec: velerov1.RestoreSpec{
+			BackupName:              snapshotName,
+			RestorePVs:              &trueVal,
+			IncludeClusterResources: &falseVal,
+			IncludedNamespaces:      []string{opts.SourceNamespace},
+			NamespaceMapping: map[string]string{
+				opts.SourceNamespace: clone.Namespace,
+			},
+			LabelSelector: &metav1.LabelSelector{
+				MatchLabels: map[string]string{
+					"kots.io/app-slug": opts.SourceAppSlug,
+				},
+			},
+			Hooks: hooks,
+		},
+	}
+
 	_, err = veleroClient.Restores(veleroNamespace).Create(ctx, restore, metav1.CreateOptions{})
 	if err != nil {
 		return errors.Wrap(err, "failed to create restore")
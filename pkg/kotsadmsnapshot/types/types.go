@@ -41,6 +41,33 @@ type BackupDetail struct {
 	Warnings        []SnapshotError  `json:"warnings"`
 }
 
+// BackupContents are the resources and volumes that a completed backup captured
+type BackupContents struct {
+	Name      string                 `json:"name"`
+	Status    string                 `json:"status"`
+	Resources []BackupResource       `json:"resources"`
+	Volumes   []BackupContentsVolume `json:"volumes"`
+}
+
+type BackupResource struct {
+	// GroupVersionKind is formatted as "apps/v1/Deployment", or "v1/Secret" for the core group
+	GroupVersionKind string `json:"groupVersionKind"`
+	// Namespace is empty for cluster scoped resources
+	Namespace string `json:"namespace,omitempty"`
+	Name      string `json:"name"`
+}
+
+type BackupContentsVolume struct {
+	// Type is "restic" for volumes backed up from pods and "snapshot" for volumes snapshotted by the cloud provider
+	Type string `json:"type"`
+	// Name is the pod volume name, or the persistent volume name of a snapshot
+	Name         string `json:"name"`
+	PodName      string `json:"podName,omitempty"`
+	PodNamespace string `json:"podNamespace,omitempty"`
+	SizeBytes    int64  `json:"sizeBytes,omitempty"`
+	Phase        string `json:"phase"`
+}
+
 type RestoreDetail struct {
 	Name     string                `json:"name"`
 	Phase    velerov1.RestorePhase `json:"phase"`
@@ -0,0 +1,373 @@
+package kotsadmstate
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/replicatedhq/kots/pkg/archives"
+	"github.com/replicatedhq/kots/pkg/buildversion"
+	deployhistorytypes "github.com/replicatedhq/kots/pkg/deployhistory/types"
+	"github.com/replicatedhq/kots/pkg/k8sutil"
+	"github.com/replicatedhq/kots/pkg/kotsadmstate/types"
+	"github.com/replicatedhq/kots/pkg/logger"
+	"github.com/replicatedhq/kots/pkg/store"
+	"github.com/replicatedhq/kots/pkg/updatechecker"
+	"github.com/replicatedhq/kots/pkg/version"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	manifestFileName  = "manifest.json"
+	sessionSecretName = "kotsadm-session"
+)
+
+var (
+	ErrNotEmpty = errors.New("kotsadm already has apps installed, state can only be imported into a fresh admin console")
+)
+
+// Export writes the state of kotsadm to w as a gzipped tar archive. This includes all apps with their versions,
+// which hold the config values, as well as registry settings and sessions. The archive is not encrypted, the export
+// handler encrypts it before it is sent.
+func Export(w io.Writer) error {
+	gzipWriter := gzip.NewWriter(w)
+	defer gzipWriter.Close()
+	tarWriter := tar.NewWriter(gzipWriter)
+	defer tarWriter.Close()
+
+	manifest := types.Manifest{
+		FormatVersion:  types.FormatVersion,
+		KotsadmVersion: buildversion.Version(),
+		ExportedAt:     time.Now(),
+		SessionKey:     os.Getenv("SESSION_KEY"),
+	}
+
+	apps, err := store.GetStore().ListInstalledApps()
+	if err != nil {
+		return errors.Wrap(err, "failed to list installed apps")
+	}
+
+	// the manifest is written last because it lists the sequences of the archives that were written
+	for _, a := range apps {
+		exportedApp, err := exportApp(a.ID, tarWriter)
+		if err != nil {
+			return errors.Wrapf(err, "failed to export app %s", a.Slug)
+		}
+		manifest.Apps = append(manifest.Apps, *exportedApp)
+	}
+
+	sessions, err := store.GetStore().ListSessions()
+	if err != nil {
+		return errors.Wrap(err, "failed to list sessions")
+	}
+	manifest.Sessions = sessions
+
+	b, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal manifest")
+	}
+	if err := writeTarFile(tarWriter, manifestFileName, b); err != nil {
+		return errors.Wrap(err, "failed to write manifest")
+	}
+
+	return nil
+}
+
+func exportApp(appID string, tarWriter *tar.Writer) (*types.App, error) {
+	a, err := store.GetStore().GetApp(appID)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get app")
+	}
+
+	registrySettings, err := store.GetStore().GetRegistryDetailsForApp(a.ID)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get registry settings")
+	}
+
+	exportedApp := types.App{
+		Slug:        a.Slug,
+		Name:        a.Name,
+		UpstreamURI: a.UpstreamURI,
+		License:     a.License,
+		IsAirgap:    a.IsAirgap,
+		Registry: types.Registry{
+			Hostname:   registrySettings.Hostname,
+			Username:   registrySettings.Username,
+			Password:   registrySettings.Password,
+			Namespace:  registrySettings.Namespace,
+			IsReadOnly: registrySettings.IsReadOnly,
+		},
+		UpdateCheckerSpec: a.UpdateCheckerSpec,
+		DeployPolicy:      a.DeployPolicy,
+		SnapshotTTL:       a.SnapshotTTL,
+		SnapshotSchedule:  a.SnapshotSchedule,
+	}
+
+	downstreams, err := store.GetStore().ListDownstreamsForApp(a.ID)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list downstreams")
+	}
+	if len(downstreams) > 0 {
+		deployedSequence, err := store.GetStore().GetCurrentSequence(a.ID, downstreams[0].ClusterID)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to get current sequence")
+		}
+		if deployedSequence != -1 {
+			exportedApp.DeployedSequence = &deployedSequence
+		}
+	}
+
+	versions, err := store.GetStore().GetAppVersionsAfter(a.ID, -1)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get app versions")
+	}
+	for _, v := range versions {
+		exportedApp.Sequences = append(exportedApp.Sequences, v.Sequence)
+		if v.Notes != "" || len(v.Labels) > 0 {
+			exportedApp.VersionNotes = append(exportedApp.VersionNotes, types.VersionNotes{
+				Sequence: v.Sequence,
+				Notes:    v.Notes,
+				Labels:   v.Labels,
+			})
+		}
+	}
+	sort.Slice(exportedApp.Sequences, func(i, j int) bool { return exportedApp.Sequences[i] < exportedApp.Sequences[j] })
+
+	for _, sequence := range exportedApp.Sequences {
+		if err := exportAppVersionArchive(a.ID, sequence, versionArchivePath(a.Slug, sequence), tarWriter); err != nil {
+			return nil, errors.Wrapf(err, "failed to export version %d", sequence)
+		}
+	}
+
+	return &exportedApp, nil
+}
+
+func exportAppVersionArchive(appID string, sequence int64, name string, tarWriter *tar.Writer) error {
+	archive, err := store.GetStore().GetAppVersionArchiveReader(appID, sequence)
+	if err != nil {
+		return errors.Wrap(err, "failed to get app version archive")
+	}
+	defer archive.Close()
+
+	b, err := ioutil.ReadAll(archive)
+	if err != nil {
+		return errors.Wrap(err, "failed to read app version archive")
+	}
+
+	return writeTarFile(tarWriter, name, b)
+}
+
+// Import restores the state exported from another kotsadm. Apps are recreated with the same version sequences,
+// and the versions that were deployed are deployed again if opts.Deploy is set.
+// ErrNotEmpty is returned if any apps are installed already.
+func Import(r io.Reader, opts types.ImportOptions) ([]types.ImportedApp, error) {
+	installedApps, err := store.GetStore().ListInstalledApps()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list installed apps")
+	}
+	if len(installedApps) > 0 {
+		return nil, ErrNotEmpty
+	}
+
+	tmpDir, err := ioutil.TempDir("", "kotsadm-import")
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create temp dir")
+	}
+	defer os.RemoveAll(tmpDir)
+
+	if err := archives.ExtractTGZArchiveFromReader(r, tmpDir); err != nil {
+		return nil, errors.Wrap(err, "failed to extract archive")
+	}
+
+	b, err := ioutil.ReadFile(filepath.Join(tmpDir, manifestFileName))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read manifest")
+	}
+	manifest := types.Manifest{}
+	if err := json.Unmarshal(b, &manifest); err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal manifest")
+	}
+	if manifest.FormatVersion > types.FormatVersion {
+		return nil, errors.Errorf("archive format version %d is not supported by this version of kotsadm, upgrade to %s or later", manifest.FormatVersion, manifest.KotsadmVersion)
+	}
+
+	if manifest.SessionKey != "" {
+		if err := importSessionKey(manifest.SessionKey); err != nil {
+			return nil, errors.Wrap(err, "failed to import session key")
+		}
+	}
+	if err := store.GetStore().ImportSessions(manifest.Sessions); err != nil {
+		return nil, errors.Wrap(err, "failed to import sessions")
+	}
+
+	importedApps := []types.ImportedApp{}
+	for _, exportedApp := range manifest.Apps {
+		importedApp, err := importApp(exportedApp, tmpDir, opts)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to import app %s", exportedApp.Slug)
+		}
+		importedApps = append(importedApps, *importedApp)
+	}
+
+	return importedApps, nil
+}
+
+func importApp(exportedApp types.App, archiveDir string, opts types.ImportOptions) (*types.ImportedApp, error) {
+	logger.Infof("importing app %s with %d versions", exportedApp.Slug, len(exportedApp.Sequences))
+
+	a, err := store.GetStore().CreateApp(exportedApp.Name, exportedApp.UpstreamURI, exportedApp.License, exportedApp.IsAirgap, false, exportedApp.Registry.IsReadOnly)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create app")
+	}
+
+	if err := store.GetStore().AddAppToAllDownstreams(a.ID); err != nil {
+		return nil, errors.Wrap(err, "failed to add app to all downstreams")
+	}
+	if err := store.GetStore().SetAppIsAirgap(a.ID, exportedApp.IsAirgap); err != nil {
+		return nil, errors.Wrap(err, "failed to set app is airgap")
+	}
+
+	if exportedApp.Registry.Hostname != "" {
+		r := exportedApp.Registry
+		if err := store.GetStore().UpdateRegistry(a.ID, r.Hostname, r.Username, r.Password, r.Namespace, r.IsReadOnly); err != nil {
+			return nil, errors.Wrap(err, "failed to update registry")
+		}
+	}
+
+	// the sequences of the imported versions match the exported ones because versions are always numbered from 0
+	var currentSequence *int64
+	for _, sequence := range exportedApp.Sequences {
+		newSequence, err := importAppVersion(a.ID, currentSequence, filepath.Join(archiveDir, versionArchivePath(exportedApp.Slug, sequence)))
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to import version %d", sequence)
+		}
+		if newSequence != sequence {
+			return nil, errors.Errorf("version %d was imported as %d", sequence, newSequence)
+		}
+		currentSequence = &newSequence
+	}
+
+	for _, versionNotes := range exportedApp.VersionNotes {
+		if err := store.GetStore().SetAppVersionNotes(a.ID, versionNotes.Sequence, versionNotes.Notes, versionNotes.Labels); err != nil {
+			return nil, errors.Wrapf(err, "failed to set notes of version %d", versionNotes.Sequence)
+		}
+	}
+
+	if exportedApp.UpdateCheckerSpec != "" {
+		if err := store.GetStore().SetUpdateCheckerSpec(a.ID, exportedApp.UpdateCheckerSpec); err != nil {
+			return nil, errors.Wrap(err, "failed to set update checker spec")
+		}
+	}
+	if exportedApp.DeployPolicy != "" {
+		if err := store.GetStore().SetDeployPolicy(a.ID, exportedApp.DeployPolicy); err != nil {
+			return nil, errors.Wrap(err, "failed to set deploy policy")
+		}
+	}
+	if exportedApp.SnapshotTTL != "" {
+		if err := store.GetStore().SetSnapshotTTL(a.ID, exportedApp.SnapshotTTL); err != nil {
+			return nil, errors.Wrap(err, "failed to set snapshot ttl")
+		}
+	}
+	if exportedApp.SnapshotSchedule != "" {
+		if err := store.GetStore().SetSnapshotSchedule(a.ID, exportedApp.SnapshotSchedule); err != nil {
+			return nil, errors.Wrap(err, "failed to set snapshot schedule")
+		}
+	}
+
+	if err := store.GetStore().SetAppInstallState(a.ID, "installed"); err != nil {
+		return nil, errors.Wrap(err, "failed to set app install state")
+	}
+
+	importedApp := types.ImportedApp{
+		Slug:     a.Slug,
+		Versions: len(exportedApp.Sequences),
+	}
+
+	if opts.Deploy && exportedApp.DeployedSequence != nil {
+		deployer := deployhistorytypes.Deployer{DeployedBy: deployhistorytypes.DeployedByStateImport}
+		if err := version.DeployVersion(a.ID, *exportedApp.DeployedSequence, deployer); err != nil {
+			return nil, errors.Wrap(err, "failed to deploy version")
+		}
+		importedApp.DeployedSequence = exportedApp.DeployedSequence
+	}
+
+	if err := updatechecker.Configure(a.ID); err != nil {
+		return nil, errors.Wrap(err, "failed to configure update checker")
+	}
+
+	return &importedApp, nil
+}
+
+func importAppVersion(appID string, currentSequence *int64, archivePath string) (int64, error) {
+	tmpDir, err := ioutil.TempDir("", "kotsadm")
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to create temp dir")
+	}
+	defer os.RemoveAll(tmpDir)
+
+	if err := archives.ExtractTGZArchiveFromFile(archivePath, tmpDir); err != nil {
+		return 0, errors.Wrap(err, "failed to extract version archive")
+	}
+
+	newSequence, err := store.GetStore().CreateAppVersion(appID, currentSequence, tmpDir, "Import", true, &version.DownstreamGitOps{})
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to create app version")
+	}
+
+	return newSequence, nil
+}
+
+// importSessionKey replaces the key that signs session tokens so that the imported sessions stay valid
+func importSessionKey(key string) error {
+	clientset, err := k8sutil.GetClientset()
+	if err != nil {
+		return errors.Wrap(err, "failed to get clientset")
+	}
+
+	namespace := os.Getenv("POD_NAMESPACE")
+	secret, err := clientset.CoreV1().Secrets(namespace).Get(context.TODO(), sessionSecretName, metav1.GetOptions{})
+	if err != nil {
+		return errors.Wrap(err, "failed to get session secret")
+	}
+
+	secret.Data["key"] = []byte(key)
+	if _, err := clientset.CoreV1().Secrets(namespace).Update(context.TODO(), secret, metav1.UpdateOptions{}); err != nil {
+		return errors.Wrap(err, "failed to update session secret")
+	}
+
+	// the secret is only read into the environment when the pod starts
+	if err := os.Setenv("SESSION_KEY", key); err != nil {
+		return errors.Wrap(err, "failed to set session key")
+	}
+
+	return nil
+}
+
+func versionArchivePath(appSlug string, sequence int64) string {
+	return filepath.Join("apps", appSlug, fmt.Sprintf("%d.tar.gz", sequence))
+}
+
+func writeTarFile(tarWriter *tar.Writer, name string, data []byte) error {
+	header := &tar.Header{
+		Name:    name,
+		Mode:    0644,
+		Size:    int64(len(data)),
+		ModTime: time.Now(),
+	}
+	if err := tarWriter.WriteHeader(header); err != nil {
+		return errors.Wrap(err, "failed to write tar header")
+	}
+	if _, err := tarWriter.Write(data); err != nil {
+		return errors.Wrap(err, "failed to write tar data")
+	}
+	return nil
+}
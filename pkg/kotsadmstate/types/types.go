@@ -0,0 +1,72 @@
+package types
+
+import (
+	"time"
+
+	apptypes "github.com/replicatedhq/kots/pkg/app/types"
+	sessiontypes "github.com/replicatedhq/kots/pkg/session/types"
+)
+
+// ExportKeyHeader is the header of the export request with the key that the archive is encrypted with, as
+// returned by crypto.AESCipher.ToString
+const ExportKeyHeader = "X-Kotsadm-Export-Key"
+
+// FormatVersion is the version of the export archive layout. Imports of archives with a newer format are refused.
+const FormatVersion = 1
+
+// Manifest describes the contents of an export archive. It is stored as manifest.json at the root of the archive,
+// next to the version archives of each app in apps/<slug>/<sequence>.tar.gz.
+type Manifest struct {
+	FormatVersion  int       `json:"formatVersion"`
+	KotsadmVersion string    `json:"kotsadmVersion"`
+	ExportedAt     time.Time `json:"exportedAt"`
+	Apps           []App     `json:"apps"`
+
+	// sessions stay valid after an import because the session signing key is exported with them
+	Sessions   []sessiontypes.Session `json:"sessions"`
+	SessionKey string                 `json:"sessionKey"`
+}
+
+type App struct {
+	Slug              string                `json:"slug"`
+	Name              string                `json:"name"`
+	UpstreamURI       string                `json:"upstreamUri"`
+	License           string                `json:"license"`
+	IsAirgap          bool                  `json:"isAirgap"`
+	Sequences         []int64               `json:"sequences"`
+	DeployedSequence  *int64                `json:"deployedSequence,omitempty"`
+	Registry          Registry              `json:"registry"`
+	UpdateCheckerSpec string                `json:"updateCheckerSpec"`
+	DeployPolicy      apptypes.DeployPolicy `json:"deployPolicy"`
+	SnapshotTTL       string                `json:"snapshotTtl"`
+	SnapshotSchedule  string                `json:"snapshotSchedule"`
+	VersionNotes      []VersionNotes        `json:"versionNotes,omitempty"`
+}
+
+// VersionNotes are the notes and labels that operators attached to a version
+type VersionNotes struct {
+	Sequence int64    `json:"sequence"`
+	Notes    string   `json:"notes,omitempty"`
+	Labels   []string `json:"labels,omitempty"`
+}
+
+// Registry holds the registry settings of an app. The password is not encrypted because the
+// api encryption key differs between installations, the export archive is encrypted as a whole instead.
+type Registry struct {
+	Hostname   string `json:"hostname"`
+	Username   string `json:"username"`
+	Password   string `json:"password"`
+	Namespace  string `json:"namespace"`
+	IsReadOnly bool   `json:"isReadOnly"`
+}
+
+type ImportOptions struct {
+	// Deploy deploys the versions that were deployed when the state was exported
+	Deploy bool `json:"deploy"`
+}
+
+type ImportedApp struct {
+	Slug             string `json:"slug"`
+	Versions         int    `json:"versions"`
+	DeployedSequence *int64 `json:"deployedSequence,omitempty"`
+}
@@ -18,6 +18,7 @@ import (
 	kotspull "github.com/replicatedhq/kots/pkg/pull"
 	"github.com/replicatedhq/kots/pkg/reporting"
 	"github.com/replicatedhq/kots/pkg/store"
+	kotsupstream "github.com/replicatedhq/kots/pkg/upstream"
 	"github.com/replicatedhq/kots/pkg/version"
 )
 
@@ -86,11 +87,6 @@ func DownloadUpdate(appID string, archiveDir string, toCursor string, skipPrefli
 		return 0, errors.Wrap(err, "failed to get new app sequence")
 	}
 
-	latestLicense, err := store.GetStore().GetLatestLicenseForApp(a.ID)
-	if err != nil {
-		return 0, errors.Wrap(err, "failed to get latest license")
-	}
-
 	identityConfigFile := filepath.Join(archiveDir, "upstream", "userdata", "identityconfig.yaml")
 	if _, err := os.Stat(identityConfigFile); os.IsNotExist(err) {
 		file, err := identity.InitAppIdentityConfig(a.Slug, kotsv1beta1.Storage{}, crypto.AESCipher{})
@@ -109,7 +105,6 @@ func DownloadUpdate(appID string, archiveDir string, toCursor string, skipPrefli
 	}
 
 	pullOptions := kotspull.PullOptions{
-		LicenseObj:          latestLicense,
 		Namespace:           appNamespace,
 		ConfigFile:          filepath.Join(archiveDir, "upstream", "userdata", "config.yaml"),
 		IdentityConfigFile:  identityConfigFile,
@@ -134,7 +129,18 @@ func DownloadUpdate(appID string, archiveDir string, toCursor string, skipPrefli
 		},
 	}
 
-	if _, err := kotspull.Pull(fmt.Sprintf("replicated://%s", beforeKotsKinds.License.Spec.AppSlug), pullOptions); err != nil {
+	// git and http upstreams are not licensed
+	upstreamURI := a.UpstreamURI
+	if !kotsupstream.IsGenericUpstream(upstreamURI) {
+		latestLicense, err := store.GetStore().GetLatestLicenseForApp(a.ID)
+		if err != nil {
+			return 0, errors.Wrap(err, "failed to get latest license")
+		}
+		pullOptions.LicenseObj = latestLicense
+		upstreamURI = fmt.Sprintf("replicated://%s", beforeKotsKinds.License.Spec.AppSlug)
+	}
+
+	if _, err := kotspull.Pull(upstreamURI, pullOptions); err != nil {
 		return 0, errors.Wrap(err, "failed to pull")
 	}
 
@@ -0,0 +1,330 @@
+package kotsappcontroller
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/pkg/errors"
+	kotsv1beta1 "github.com/replicatedhq/kots/kotskinds/apis/kots/v1beta1"
+	"github.com/replicatedhq/kots/kotskinds/client/kotsclientset"
+	apptypes "github.com/replicatedhq/kots/pkg/app/types"
+	"github.com/replicatedhq/kots/pkg/automation"
+	"github.com/replicatedhq/kots/pkg/deployapproval"
+	"github.com/replicatedhq/kots/pkg/k8sutil"
+	kotsadmtypes "github.com/replicatedhq/kots/pkg/kotsadm/types"
+	kotsadmlicense "github.com/replicatedhq/kots/pkg/kotsadmlicense"
+	"github.com/replicatedhq/kots/pkg/kotsutil"
+	"github.com/replicatedhq/kots/pkg/logger"
+	"github.com/replicatedhq/kots/pkg/store"
+	"github.com/replicatedhq/kots/pkg/updatechecker"
+	corev1 "k8s.io/api/core/v1"
+	kuberneteserrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	reconcileIntervalInSeconds = 60
+	updateCheckInterval        = 4 * time.Hour
+)
+
+// ConfigValuesSetter sets the config values of an installed app from a ConfigValues yaml and creates a new version,
+// which is deployed if deploy is true
+type ConfigValuesSetter func(a *apptypes.App, configValuesData []byte, deploy bool, skipPreflights bool) error
+
+// Start will start the KotsApp controller. KotsApp resources in the kotsadm namespace
+// describe apps that should be installed, and are reconciled by handing them to the
+// same automation that "kots install" uses. Changes to the license and config values
+// of installed apps are applied with setConfigValues and a license sync.
+func Start(setConfigValues ConfigValuesSetter) error {
+	logger.Debug("starting kotsapp controller")
+
+	go func() {
+		for {
+			reconcileLoop(setConfigValues)
+			time.Sleep(time.Second * reconcileIntervalInSeconds)
+		}
+	}()
+
+	return nil
+}
+
+func reconcileLoop(setConfigValues ConfigValuesSetter) {
+	cfg, err := k8sutil.GetClusterConfig()
+	if err != nil {
+		logger.Error(errors.Wrap(err, "failed to get cluster config"))
+		return
+	}
+
+	clientset, err := kubernetes.NewForConfig(cfg)
+	if err != nil {
+		logger.Error(errors.Wrap(err, "failed to create clientset"))
+		return
+	}
+
+	kotsClient, err := kotsclientset.NewForConfig(cfg)
+	if err != nil {
+		logger.Error(errors.Wrap(err, "failed to create kots clientset"))
+		return
+	}
+
+	kotsApps, err := kotsClient.KotsV1beta1().KotsApps(os.Getenv("POD_NAMESPACE")).List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		if kuberneteserrors.IsNotFound(err) {
+			// the crd is not installed
+			return
+		}
+		logger.Error(errors.Wrap(err, "failed to list kotsapps"))
+		return
+	}
+
+	for i := range kotsApps.Items {
+		kotsApp := &kotsApps.Items[i]
+
+		status, err := reconcile(clientset, kotsApp, setConfigValues)
+		if err != nil {
+			logger.Error(errors.Wrapf(err, "failed to reconcile kotsapp %s", kotsApp.Name))
+			status.Phase = kotsv1beta1.KotsAppPhaseFailed
+			status.Message = err.Error()
+		}
+		status.ObservedGeneration = kotsApp.Generation
+
+		kotsApp.Status = status
+		if _, err := kotsClient.KotsV1beta1().KotsApps(kotsApp.Namespace).UpdateStatus(context.TODO(), kotsApp, metav1.UpdateOptions{}); err != nil {
+			logger.Error(errors.Wrapf(err, "failed to update status for kotsapp %s", kotsApp.Name))
+		}
+	}
+}
+
+// reconcile installs the app if it's not yet installed. Once it is, it applies changes to the license and config
+// values, and checks for updates. the returned status is always valid, even when an error is returned.
+func reconcile(clientset kubernetes.Interface, kotsApp *kotsv1beta1.KotsApp, setConfigValues ConfigValuesSetter) (kotsv1beta1.KotsAppStatus, error) {
+	status := *kotsApp.Status.DeepCopy()
+
+	licenseData, err := getSecretKey(clientset, kotsApp.Namespace, kotsApp.Spec.LicenseSecretRef)
+	if err != nil {
+		return status, errors.Wrap(err, "failed to get license")
+	}
+
+	license, err := kotsutil.LoadLicenseFromBytes(licenseData)
+	if err != nil {
+		return status, errors.Wrap(err, "failed to load license")
+	}
+	status.AppSlug = license.Spec.AppSlug
+
+	var configValuesData []byte
+	if kotsApp.Spec.ConfigValuesSecretRef != nil {
+		configValuesData, err = getSecretKey(clientset, kotsApp.Namespace, *kotsApp.Spec.ConfigValuesSecretRef)
+		if err != nil {
+			return status, errors.Wrap(err, "failed to get config values")
+		}
+	}
+
+	a, err := store.GetStore().GetAppFromSlug(license.Spec.AppSlug)
+	if err != nil && !store.GetStore().IsNotFound(err) {
+		return status, errors.Wrap(err, "failed to get app")
+	}
+
+	if a == nil {
+		status.Phase = kotsv1beta1.KotsAppPhaseInstalling
+		status.Message = ""
+		if err := install(clientset, kotsApp, license.Spec.AppSlug, licenseData, configValuesData); err != nil {
+			return status, errors.Wrap(err, "failed to install app")
+		}
+		// the app is installed with this license and these config values
+		status.LicenseHash = dataHash(licenseData)
+		if configValuesData != nil {
+			status.ConfigValuesHash = dataHash(configValuesData)
+		}
+
+		a, err = store.GetStore().GetAppFromSlug(license.Spec.AppSlug)
+		if err != nil {
+			if store.GetStore().IsNotFound(err) {
+				status.Phase = kotsv1beta1.KotsAppPhasePending
+				status.Message = "waiting for app to be installed"
+				return status, nil
+			}
+			return status, errors.Wrap(err, "failed to get installed app")
+		}
+	}
+
+	status.Phase = kotsv1beta1.KotsAppPhaseInstalled
+	status.Message = ""
+
+	if err := reconcileLicense(a, kotsApp, licenseData, &status); err != nil {
+		return status, errors.Wrap(err, "failed to reconcile license")
+	}
+
+	if err := reconcileConfigValues(a, kotsApp, configValuesData, &status, setConfigValues); err != nil {
+		return status, errors.Wrap(err, "failed to reconcile config values")
+	}
+
+	specChanged := kotsApp.Generation != kotsApp.Status.ObservedGeneration
+	updateCheckDue := status.LastUpdateCheckAt == nil || time.Since(status.LastUpdateCheckAt.Time) > updateCheckInterval
+	if specChanged || updateCheckDue {
+		if _, err := updatechecker.CheckForUpdates(a.ID, kotsApp.Spec.AutoDeploy, kotsApp.Spec.SkipPreflights, false); err != nil {
+			return status, errors.Wrap(err, "failed to check for updates")
+		}
+		now := metav1.Now()
+		status.LastUpdateCheckAt = &now
+	}
+
+	downstreams, err := store.GetStore().ListDownstreamsForApp(a.ID)
+	if err != nil {
+		return status, errors.Wrap(err, "failed to list downstreams for app")
+	}
+	if len(downstreams) > 0 {
+		currentSequence := downstreams[0].CurrentSequence
+		status.CurrentSequence = &currentSequence
+	}
+
+	return status, nil
+}
+
+// reconcileLicense syncs the license of the secret for the installed app if it changed since it was last synced. The
+// new version is deployed if the KotsApp enables automatic deploys.
+func reconcileLicense(a *apptypes.App, kotsApp *kotsv1beta1.KotsApp, licenseData []byte, status *kotsv1beta1.KotsAppStatus) error {
+	hash := dataHash(licenseData)
+	if hash == status.LicenseHash {
+		return nil
+	}
+
+	_, synced, err := kotsadmlicense.Sync(a, string(licenseData), false)
+	if err != nil {
+		return errors.Wrap(err, "failed to sync license")
+	}
+
+	if synced && kotsApp.Spec.AutoDeploy {
+		updatedApp, err := store.GetStore().GetApp(a.ID)
+		if err != nil {
+			return errors.Wrap(err, "failed to get updated app")
+		}
+
+		_, err = deployapproval.DeployOrRequest(updatedApp, updatedApp.CurrentSequence, deployapproval.RequestOptions{
+			RequestedBy:      deployapproval.RequestedByAutomaticDeploy,
+			IsSkipPreflights: kotsApp.Spec.SkipPreflights,
+		})
+		if err != nil {
+			return errors.Wrap(err, "failed to deploy")
+		}
+	}
+
+	status.LicenseHash = hash
+	return nil
+}
+
+// reconcileConfigValues sets the config values of the secret for the installed app if they changed since they were
+// last set. Apps that were installed before the hash was recorded have their config values set once.
+func reconcileConfigValues(a *apptypes.App, kotsApp *kotsv1beta1.KotsApp, configValuesData []byte, status *kotsv1beta1.KotsAppStatus, setConfigValues ConfigValuesSetter) error {
+	if configValuesData == nil {
+		return nil
+	}
+
+	hash := dataHash(configValuesData)
+	if hash == status.ConfigValuesHash {
+		return nil
+	}
+
+	if err := setConfigValues(a, configValuesData, kotsApp.Spec.AutoDeploy, kotsApp.Spec.SkipPreflights); err != nil {
+		return errors.Wrap(err, "failed to set config values")
+	}
+
+	status.ConfigValuesHash = hash
+	return nil
+}
+
+func dataHash(data []byte) string {
+	return fmt.Sprintf("%x", sha256.Sum256(data))
+}
+
+// install writes the license and config values to the secrets that automated installs
+// are read from, and runs the automated install
+func install(clientset kubernetes.Interface, kotsApp *kotsv1beta1.KotsApp, appSlug string, licenseData []byte, configValuesData []byte) error {
+	if configValuesData != nil {
+		configValuesSecret := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      fmt.Sprintf("kotsapp-%s-configvalues", kotsApp.Name),
+				Namespace: kotsApp.Namespace,
+				Labels: kotsadmtypes.GetKotsadmLabels(map[string]string{
+					"kots.io/automation": "configvalues",
+				}),
+			},
+			Data: map[string][]byte{
+				"configvalues": configValuesData,
+			},
+		}
+		if err := createOrUpdateSecret(clientset, configValuesSecret); err != nil {
+			return errors.Wrap(err, "failed to create config values secret")
+		}
+	}
+
+	licenseSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      fmt.Sprintf("kotsapp-%s-license", kotsApp.Name),
+			Namespace: kotsApp.Namespace,
+			Labels: kotsadmtypes.GetKotsadmLabels(map[string]string{
+				"kots.io/automation": "license",
+				"kots.io/app":        appSlug,
+			}),
+			Annotations: map[string]string{
+				"kots.io/airgap":  "false",
+				"kots.io/channel": kotsApp.Spec.Channel,
+			},
+		},
+		Data: map[string][]byte{
+			"license": licenseData,
+		},
+	}
+	if err := createOrUpdateSecret(clientset, licenseSecret); err != nil {
+		return errors.Wrap(err, "failed to create license secret")
+	}
+
+	if err := automation.AutomateInstall(); err != nil {
+		return errors.Wrap(err, "failed to run automated install")
+	}
+
+	return nil
+}
+
+func getSecretKey(clientset kubernetes.Interface, namespace string, selector corev1.SecretKeySelector) ([]byte, error) {
+	secret, err := clientset.CoreV1().Secrets(namespace).Get(context.TODO(), selector.Name, metav1.GetOptions{})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get secret %s", selector.Name)
+	}
+
+	data, ok := secret.Data[selector.Key]
+	if !ok {
+		return nil, errors.Errorf("secret %s does not contain key %s", selector.Name, selector.Key)
+	}
+
+	return data, nil
+}
+
+func createOrUpdateSecret(clientset kubernetes.Interface, secret *corev1.Secret) error {
+	existing, err := clientset.CoreV1().Secrets(secret.Namespace).Get(context.TODO(), secret.Name, metav1.GetOptions{})
+	if err != nil {
+		if !kuberneteserrors.IsNotFound(err) {
+			return errors.Wrap(err, "failed to get existing secret")
+		}
+
+		_, err := clientset.CoreV1().Secrets(secret.Namespace).Create(context.TODO(), secret, metav1.CreateOptions{})
+		if err != nil {
+			return errors.Wrap(err, "failed to create secret")
+		}
+		return nil
+	}
+
+	existing.Labels = secret.Labels
+	existing.Annotations = secret.Annotations
+	existing.Data = secret.Data
+
+	_, err = clientset.CoreV1().Secrets(secret.Namespace).Update(context.TODO(), existing, metav1.UpdateOptions{})
+	if err != nil {
+		return errors.Wrap(err, "failed to update secret")
+	}
+
+	return nil
+}
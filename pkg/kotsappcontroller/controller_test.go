@@ -0,0 +1,158 @@
+package kotsappcontroller
+
+import (
+	"context"
+	"testing"
+
+	"github.com/pkg/errors"
+	kotsv1beta1 "github.com/replicatedhq/kots/kotskinds/apis/kots/v1beta1"
+	apptypes "github.com/replicatedhq/kots/pkg/app/types"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func Test_reconcileConfigValues(t *testing.T) {
+	configValues := []byte("apiVersion: kots.io/v1beta1\nkind: ConfigValues\n")
+
+	tests := []struct {
+		name             string
+		configValues     []byte
+		statusHash       string
+		setErr           error
+		expectSet        bool
+		expectErr        bool
+		expectStatusHash string
+	}{
+		{
+			name:             "no config values",
+			configValues:     nil,
+			statusHash:       "",
+			expectSet:        false,
+			expectStatusHash: "",
+		},
+		{
+			name:             "config values are unchanged",
+			configValues:     configValues,
+			statusHash:       dataHash(configValues),
+			expectSet:        false,
+			expectStatusHash: dataHash(configValues),
+		},
+		{
+			name:             "config values changed",
+			configValues:     configValues,
+			statusHash:       "abc",
+			expectSet:        true,
+			expectStatusHash: dataHash(configValues),
+		},
+		{
+			name:             "config values were not set yet",
+			configValues:     configValues,
+			statusHash:       "",
+			expectSet:        true,
+			expectStatusHash: dataHash(configValues),
+		},
+		{
+			name:             "setting config values fails",
+			configValues:     configValues,
+			statusHash:       "abc",
+			setErr:           errors.New("app is locked"),
+			expectSet:        true,
+			expectErr:        true,
+			expectStatusHash: "abc",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			req := require.New(t)
+
+			kotsApp := &kotsv1beta1.KotsApp{
+				Spec: kotsv1beta1.KotsAppSpec{
+					AutoDeploy:     true,
+					SkipPreflights: true,
+				},
+			}
+			status := kotsv1beta1.KotsAppStatus{
+				ConfigValuesHash: test.statusHash,
+			}
+
+			set := false
+			setConfigValues := func(a *apptypes.App, configValuesData []byte, deploy bool, skipPreflights bool) error {
+				set = true
+				req.Equal("app-id", a.ID)
+				req.Equal(test.configValues, configValuesData)
+				req.True(deploy)
+				req.True(skipPreflights)
+				return test.setErr
+			}
+
+			err := reconcileConfigValues(&apptypes.App{ID: "app-id"}, kotsApp, test.configValues, &status, setConfigValues)
+			if test.expectErr {
+				req.Error(err)
+			} else {
+				req.NoError(err)
+			}
+			req.Equal(test.expectSet, set)
+			req.Equal(test.expectStatusHash, status.ConfigValuesHash)
+		})
+	}
+}
+
+func Test_getSecretKey(t *testing.T) {
+	req := require.New(t)
+
+	clientset := fake.NewSimpleClientset(&corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "license",
+			Namespace: "default",
+		},
+		Data: map[string][]byte{
+			"license.yaml": []byte("license"),
+		},
+	})
+
+	data, err := getSecretKey(clientset, "default", corev1.SecretKeySelector{
+		LocalObjectReference: corev1.LocalObjectReference{Name: "license"},
+		Key:                  "license.yaml",
+	})
+	req.NoError(err)
+	req.Equal([]byte("license"), data)
+
+	_, err = getSecretKey(clientset, "default", corev1.SecretKeySelector{
+		LocalObjectReference: corev1.LocalObjectReference{Name: "license"},
+		Key:                  "other",
+	})
+	req.Error(err)
+
+	_, err = getSecretKey(clientset, "default", corev1.SecretKeySelector{
+		LocalObjectReference: corev1.LocalObjectReference{Name: "missing"},
+		Key:                  "license.yaml",
+	})
+	req.Error(err)
+}
+
+func Test_createOrUpdateSecret(t *testing.T) {
+	req := require.New(t)
+
+	clientset := fake.NewSimpleClientset()
+
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "kotsapp-app-configvalues",
+			Namespace: "default",
+		},
+		Data: map[string][]byte{
+			"configvalues": []byte("a"),
+		},
+	}
+	req.NoError(createOrUpdateSecret(clientset, secret.DeepCopy()))
+
+	secret.Data["configvalues"] = []byte("b")
+	req.NoError(createOrUpdateSecret(clientset, secret.DeepCopy()))
+
+	updated, err := clientset.CoreV1().Secrets("default").Get(context.TODO(), "kotsapp-app-configvalues", metav1.GetOptions{})
+	req.NoError(err)
+	req.Equal([]byte("b"), updated.Data["configvalues"])
+}
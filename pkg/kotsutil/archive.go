@@ -0,0 +1,188 @@
+package kotsutil
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+var errStopWalk = errors.New("stop walking archive")
+
+// LoadKotsKindsFromArchive loads the kots kinds from a gzipped tar stream of an app version
+// archive without extracting the archive to disk
+func LoadKotsKindsFromArchive(r io.Reader) (*KotsKinds, error) {
+	kotsKinds := emptyKotsKinds()
+
+	err := walkArchive(r, func(name string, size int64, contents io.Reader) error {
+		data, err := ioutil.ReadAll(contents)
+		if err != nil {
+			return errors.Wrapf(err, "failed to read %s", name)
+		}
+		return kotsKinds.addKotsKind(data)
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to walk archive")
+	}
+
+	return &kotsKinds, nil
+}
+
+// ReadFileFromArchive returns the contents of a single file in a gzipped tar stream of an app version archive.
+// The returned error satisfies os.IsNotExist when the archive does not contain the file.
+func ReadFileFromArchive(r io.Reader, filename string) ([]byte, error) {
+	filename = normalizeArchivePath(filename)
+
+	var data []byte
+	err := walkArchive(r, func(name string, size int64, contents io.Reader) error {
+		if name != filename {
+			return nil
+		}
+
+		d, err := ioutil.ReadAll(contents)
+		if err != nil {
+			return errors.Wrapf(err, "failed to read %s", name)
+		}
+		data = d
+		return errStopWalk
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to walk archive")
+	}
+
+	if data == nil {
+		return nil, &os.PathError{Op: "open", Path: filename, Err: os.ErrNotExist}
+	}
+
+	return data, nil
+}
+
+// ArchiveEntry is a file or a directory in an app version archive
+type ArchiveEntry struct {
+	Name  string `json:"name"`
+	Path  string `json:"path"`
+	IsDir bool   `json:"isDir"`
+	// Size is the size of a file in bytes, or the number of files under a directory
+	Size int64 `json:"size"`
+}
+
+// ListArchiveDir returns the files and directories directly in dir, an empty dir being the root of the archive.
+// Directories are listed first, and each are sorted by name. Only the tar headers are read, not the file contents.
+// The returned error satisfies os.IsNotExist when there are no files under dir.
+func ListArchiveDir(r io.Reader, dir string) ([]ArchiveEntry, error) {
+	dir = normalizeArchivePath(dir)
+	prefix := ""
+	if dir != "" {
+		prefix = dir + "/"
+	}
+
+	dirs := map[string]*ArchiveEntry{}
+	entries := []ArchiveEntry{}
+	err := walkArchive(r, func(name string, size int64, contents io.Reader) error {
+		if !strings.HasPrefix(name, prefix) {
+			return nil
+		}
+
+		rest := strings.TrimPrefix(name, prefix)
+		if i := strings.Index(rest, "/"); i != -1 {
+			dirName := rest[:i]
+			if _, ok := dirs[dirName]; !ok {
+				dirs[dirName] = &ArchiveEntry{Name: dirName, Path: prefix + dirName, IsDir: true}
+			}
+			dirs[dirName].Size++
+			return nil
+		}
+
+		entries = append(entries, ArchiveEntry{Name: rest, Path: name, Size: size})
+		return nil
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to walk archive")
+	}
+
+	if dir != "" && len(dirs) == 0 && len(entries) == 0 {
+		return nil, &os.PathError{Op: "open", Path: dir, Err: os.ErrNotExist}
+	}
+
+	dirEntries := []ArchiveEntry{}
+	for _, entry := range dirs {
+		dirEntries = append(dirEntries, *entry)
+	}
+	sort.Slice(dirEntries, func(i, j int) bool {
+		return dirEntries[i].Name < dirEntries[j].Name
+	})
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].Name < entries[j].Name
+	})
+
+	return append(dirEntries, entries...), nil
+}
+
+// ReadArchiveFiles returns the contents of the files in a gzipped tar stream of an app version archive, keyed by
+// path. The first offset files in archive order are skipped, and at most limit files are read if limit is not 0.
+// The total number of files in the archive is returned with them.
+func ReadArchiveFiles(r io.Reader, offset int, limit int) (map[string][]byte, int, error) {
+	files := map[string][]byte{}
+	total := 0
+	err := walkArchive(r, func(name string, size int64, contents io.Reader) error {
+		index := total
+		total++
+		if index < offset || (limit > 0 && index >= offset+limit) {
+			return nil
+		}
+
+		data, err := ioutil.ReadAll(contents)
+		if err != nil {
+			return errors.Wrapf(err, "failed to read %s", name)
+		}
+		files[name] = data
+		return nil
+	})
+	if err != nil {
+		return nil, 0, errors.Wrap(err, "failed to walk archive")
+	}
+
+	return files, total, nil
+}
+
+// walkArchive calls fn for each regular file in the gzipped tar stream, in the order they are archived.
+// fn can return errStopWalk to stop reading the rest of the archive.
+func walkArchive(r io.Reader, fn func(name string, size int64, contents io.Reader) error) error {
+	gzipReader, err := gzip.NewReader(r)
+	if err != nil {
+		return errors.Wrap(err, "failed to create gzip reader")
+	}
+	defer gzipReader.Close()
+
+	tarReader := tar.NewReader(gzipReader)
+	for {
+		header, err := tarReader.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return errors.Wrap(err, "failed to read tar header")
+		}
+
+		if header.Typeflag != tar.TypeReg && header.Typeflag != tar.TypeRegA {
+			continue
+		}
+
+		if err := fn(normalizeArchivePath(header.Name), header.Size, tarReader); err != nil {
+			if err == errStopWalk {
+				return nil
+			}
+			return err
+		}
+	}
+}
+
+func normalizeArchivePath(name string) string {
+	return strings.TrimPrefix(path.Clean("/"+name), "/")
+}
@@ -0,0 +1,180 @@
+package kotsutil
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_LoadKotsKindsFromArchive(t *testing.T) {
+	req := require.New(t)
+
+	archive := makeArchive(t, map[string]string{
+		"upstream/license.yaml": `apiVersion: kots.io/v1beta1
+kind: License
+spec:
+  appSlug: my-app
+`,
+		"upstream/deployment.yaml": `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: my-app
+`,
+		"upstream/README.md": "not yaml",
+	})
+
+	kotsKinds, err := LoadKotsKindsFromArchive(bytes.NewReader(archive))
+	req.NoError(err)
+	req.NotNil(kotsKinds.License)
+	req.Equal("my-app", kotsKinds.License.Spec.AppSlug)
+	req.Nil(kotsKinds.Config)
+}
+
+func Test_ReadFileFromArchive(t *testing.T) {
+	archive := makeArchive(t, map[string]string{
+		"upstream/userdata/license.yaml": "license",
+		"overlays/midstream/secret.yaml": "secret",
+	})
+
+	tests := []struct {
+		name     string
+		filename string
+		want     string
+		notExist bool
+	}{
+		{
+			name:     "finds the file",
+			filename: "overlays/midstream/secret.yaml",
+			want:     "secret",
+		},
+		{
+			name:     "normalizes the path",
+			filename: "./upstream/userdata/license.yaml",
+			want:     "license",
+		},
+		{
+			name:     "missing file",
+			filename: "upstream/userdata/config.yaml",
+			notExist: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			req := require.New(t)
+
+			got, err := ReadFileFromArchive(bytes.NewReader(archive), test.filename)
+			if test.notExist {
+				req.True(os.IsNotExist(err))
+				return
+			}
+			req.NoError(err)
+			req.Equal(test.want, string(got))
+		})
+	}
+}
+
+func Test_ListArchiveDir(t *testing.T) {
+	archive := makeArchive(t, map[string]string{
+		"upstream/userdata/license.yaml": "license",
+		"upstream/deployment.yaml":       "deployment",
+		"overlays/midstream/secret.yaml": "secret",
+		"README.md":                      "readme",
+	})
+
+	tests := []struct {
+		name     string
+		dir      string
+		want     []ArchiveEntry
+		notExist bool
+	}{
+		{
+			name: "root",
+			dir:  "",
+			want: []ArchiveEntry{
+				{Name: "overlays", Path: "overlays", IsDir: true, Size: 1},
+				{Name: "upstream", Path: "upstream", IsDir: true, Size: 2},
+				{Name: "README.md", Path: "README.md", Size: 6},
+			},
+		},
+		{
+			name: "subdirectory",
+			dir:  "/upstream/",
+			want: []ArchiveEntry{
+				{Name: "userdata", Path: "upstream/userdata", IsDir: true, Size: 1},
+				{Name: "deployment.yaml", Path: "upstream/deployment.yaml", Size: 10},
+			},
+		},
+		{
+			name:     "missing directory",
+			dir:      "base",
+			notExist: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			req := require.New(t)
+
+			got, err := ListArchiveDir(bytes.NewReader(archive), test.dir)
+			if test.notExist {
+				req.True(os.IsNotExist(err))
+				return
+			}
+			req.NoError(err)
+			req.Equal(test.want, got)
+		})
+	}
+}
+
+func Test_ReadArchiveFiles(t *testing.T) {
+	req := require.New(t)
+
+	archive := makeArchive(t, map[string]string{
+		"a.yaml": "a",
+		"b.yaml": "b",
+		"c.yaml": "c",
+	})
+
+	all, total, err := ReadArchiveFiles(bytes.NewReader(archive), 0, 0)
+	req.NoError(err)
+	req.Equal(3, total)
+	req.Len(all, 3)
+
+	page, total, err := ReadArchiveFiles(bytes.NewReader(archive), 1, 1)
+	req.NoError(err)
+	req.Equal(3, total)
+	req.Len(page, 1)
+
+	past, total, err := ReadArchiveFiles(bytes.NewReader(archive), 3, 1)
+	req.NoError(err)
+	req.Equal(3, total)
+	req.Len(past, 0)
+}
+
+func makeArchive(t *testing.T, files map[string]string) []byte {
+	buf := bytes.NewBuffer(nil)
+	gzipWriter := gzip.NewWriter(buf)
+	tarWriter := tar.NewWriter(gzipWriter)
+
+	for name, contents := range files {
+		err := tarWriter.WriteHeader(&tar.Header{
+			Name:     name,
+			Mode:     0644,
+			Size:     int64(len(contents)),
+			Typeflag: tar.TypeReg,
+		})
+		require.NoError(t, err)
+		_, err = tarWriter.Write([]byte(contents))
+		require.NoError(t, err)
+	}
+
+	require.NoError(t, tarWriter.Close())
+	require.NoError(t, gzipWriter.Close())
+
+	return buf.Bytes()
+}
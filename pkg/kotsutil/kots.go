@@ -10,12 +10,16 @@ import (
 	"path/filepath"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/pkg/errors"
 	kotsv1beta1 "github.com/replicatedhq/kots/kotskinds/apis/kots/v1beta1"
 	kotsscheme "github.com/replicatedhq/kots/kotskinds/client/kotsclientset/scheme"
+	"github.com/replicatedhq/kots/pkg/cors"
 	"github.com/replicatedhq/kots/pkg/crypto"
+	"github.com/replicatedhq/kots/pkg/endpoints"
 	"github.com/replicatedhq/kots/pkg/k8sutil"
+	"github.com/replicatedhq/kots/pkg/sensitiveconfig"
 	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
 	troubleshootscheme "github.com/replicatedhq/troubleshoot/pkg/client/troubleshootclientset/scheme"
 	"github.com/replicatedhq/troubleshoot/pkg/docrewrite"
@@ -58,6 +62,8 @@ type KotsKinds struct {
 	IdentityConfig *kotsv1beta1.IdentityConfig
 
 	Backup *velerov1.Backup
+	// Restore is only used for the hooks that are added to the restores of the app
+	Restore *velerov1.Restore
 }
 
 func (k *KotsKinds) EncryptConfigValues() error {
@@ -76,24 +82,24 @@ func (k *KotsKinds) EncryptConfigValues() error {
 		updated[name] = configValue
 
 		if configValue.ValuePlaintext != "" {
-			// ensure it's a password type
-			configItemType := ""
+			// ensure it's a password or another sensitive item
+			var configItem *kotsv1beta1.ConfigItem
 
 			for _, group := range k.Config.Spec.Groups {
-				for _, item := range group.Items {
-					if item.Name == name {
-						configItemType = item.Type
+				for i := range group.Items {
+					if group.Items[i].Name == name {
+						configItem = &group.Items[i]
 						goto Found
 					}
 				}
 			}
 		Found:
 
-			if configItemType == "" {
+			if configItem == nil {
 				return errors.Errorf("Cannot encrypt item %q because item type was not found", name)
 			}
-			if configItemType != "password" {
-				return errors.Errorf("Cannot encrypt item %q because item type was %q (not password)", name, configItemType)
+			if !sensitiveconfig.IsEncrypted(*configItem) {
+				return errors.Errorf("Cannot encrypt item %q because item type was %q (not password or sensitive)", name, configItem.Type)
 			}
 
 			encrypted := cipher.Encrypt([]byte(configValue.ValuePlaintext))
@@ -314,6 +320,16 @@ func (o KotsKinds) Marshal(g string, v string, k string) (string, error) {
 				}
 				return string(b.Bytes()), nil
 			}
+			if k == "Restore" {
+				if o.Restore == nil {
+					return "", nil
+				}
+				var b bytes.Buffer
+				if err := s.Encode(o.Restore, &b); err != nil {
+					return "", errors.Wrap(err, "failed to encode restore")
+				}
+				return string(b.Bytes()), nil
+			}
 		}
 	}
 
@@ -342,7 +358,6 @@ func emptyKotsKinds() KotsKinds {
 
 func LoadKotsKindsFromPath(fromDir string) (*KotsKinds, error) {
 	kotsKinds := emptyKotsKinds()
-	decode := scheme.Codecs.UniversalDeserializer().Decode
 
 	err := filepath.Walk(fromDir,
 		func(path string, info os.FileInfo, err error) error {
@@ -359,55 +374,7 @@ func LoadKotsKindsFromPath(fromDir string) (*KotsKinds, error) {
 				return err
 			}
 
-			decoded, gvk, err := decode(contents, nil, nil)
-			if err != nil {
-				// TODO: log something on yaml errors (based on file extention)
-				return nil // not an error because the file might not be yaml
-			}
-
-			if strings.HasPrefix(gvk.String(), "troubleshoot.replicated.com/v1beta1,") {
-				contents, err = docrewrite.ConvertToV1Beta2(contents)
-				if err != nil {
-					return errors.Wrap(err, "failed to convert to v1beta2")
-				}
-				decoded, gvk, err = decode(contents, nil, nil)
-				if err != nil {
-					return err
-				}
-			}
-
-			switch gvk.String() {
-			case "kots.io/v1beta1, Kind=Config":
-				kotsKinds.Config = decoded.(*kotsv1beta1.Config)
-			case "kots.io/v1beta1, Kind=ConfigValues":
-				kotsKinds.ConfigValues = decoded.(*kotsv1beta1.ConfigValues)
-			case "kots.io/v1beta1, Kind=Application":
-				kotsKinds.KotsApplication = *decoded.(*kotsv1beta1.Application)
-			case "kots.io/v1beta1, Kind=License":
-				kotsKinds.License = decoded.(*kotsv1beta1.License)
-			case "kots.io/v1beta1, Kind=Identity":
-				kotsKinds.Identity = decoded.(*kotsv1beta1.Identity)
-			case "kots.io/v1beta1, Kind=IdentityConfig":
-				kotsKinds.IdentityConfig = decoded.(*kotsv1beta1.IdentityConfig)
-			case "kots.io/v1beta1, Kind=Installation":
-				kotsKinds.Installation = *decoded.(*kotsv1beta1.Installation)
-			case "troubleshoot.sh/v1beta2, Kind=Collector":
-				kotsKinds.Collector = decoded.(*troubleshootv1beta2.Collector)
-			case "troubleshoot.sh/v1beta2, Kind=Analyzer":
-				kotsKinds.Analyzer = decoded.(*troubleshootv1beta2.Analyzer)
-			case "troubleshoot.sh/v1beta2, Kind=SupportBundle":
-				kotsKinds.SupportBundle = decoded.(*troubleshootv1beta2.SupportBundle)
-			case "troubleshoot.sh/v1beta2, Kind=Redactor":
-				kotsKinds.Redactor = decoded.(*troubleshootv1beta2.Redactor)
-			case "troubleshoot.sh/v1beta2, Kind=Preflight":
-				kotsKinds.Preflight = decoded.(*troubleshootv1beta2.Preflight)
-			case "velero.io/v1, Kind=Backup":
-				kotsKinds.Backup = decoded.(*velerov1.Backup)
-			case "app.k8s.io/v1beta1, Kind=Application":
-				kotsKinds.Application = decoded.(*applicationv1beta1.Application)
-			}
-
-			return nil
+			return kotsKinds.addKotsKind(contents)
 		})
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to walk upstream dir")
@@ -416,6 +383,63 @@ func LoadKotsKindsFromPath(fromDir string) (*KotsKinds, error) {
 	return &kotsKinds, nil
 }
 
+// addKotsKind decodes contents and sets the matching kind, contents that are not a kots kind are ignored
+func (k *KotsKinds) addKotsKind(contents []byte) error {
+	decode := scheme.Codecs.UniversalDeserializer().Decode
+
+	decoded, gvk, err := decode(contents, nil, nil)
+	if err != nil {
+		// TODO: log something on yaml errors (based on file extention)
+		return nil // not an error because the file might not be yaml
+	}
+
+	if strings.HasPrefix(gvk.String(), "troubleshoot.replicated.com/v1beta1,") {
+		contents, err = docrewrite.ConvertToV1Beta2(contents)
+		if err != nil {
+			return errors.Wrap(err, "failed to convert to v1beta2")
+		}
+		decoded, gvk, err = decode(contents, nil, nil)
+		if err != nil {
+			return err
+		}
+	}
+
+	switch gvk.String() {
+	case "kots.io/v1beta1, Kind=Config":
+		k.Config = decoded.(*kotsv1beta1.Config)
+	case "kots.io/v1beta1, Kind=ConfigValues":
+		k.ConfigValues = decoded.(*kotsv1beta1.ConfigValues)
+	case "kots.io/v1beta1, Kind=Application":
+		k.KotsApplication = *decoded.(*kotsv1beta1.Application)
+	case "kots.io/v1beta1, Kind=License":
+		k.License = decoded.(*kotsv1beta1.License)
+	case "kots.io/v1beta1, Kind=Identity":
+		k.Identity = decoded.(*kotsv1beta1.Identity)
+	case "kots.io/v1beta1, Kind=IdentityConfig":
+		k.IdentityConfig = decoded.(*kotsv1beta1.IdentityConfig)
+	case "kots.io/v1beta1, Kind=Installation":
+		k.Installation = *decoded.(*kotsv1beta1.Installation)
+	case "troubleshoot.sh/v1beta2, Kind=Collector":
+		k.Collector = decoded.(*troubleshootv1beta2.Collector)
+	case "troubleshoot.sh/v1beta2, Kind=Analyzer":
+		k.Analyzer = decoded.(*troubleshootv1beta2.Analyzer)
+	case "troubleshoot.sh/v1beta2, Kind=SupportBundle":
+		k.SupportBundle = decoded.(*troubleshootv1beta2.SupportBundle)
+	case "troubleshoot.sh/v1beta2, Kind=Redactor":
+		k.Redactor = decoded.(*troubleshootv1beta2.Redactor)
+	case "troubleshoot.sh/v1beta2, Kind=Preflight":
+		k.Preflight = decoded.(*troubleshootv1beta2.Preflight)
+	case "velero.io/v1, Kind=Backup":
+		k.Backup = decoded.(*velerov1.Backup)
+	case "velero.io/v1, Kind=Restore":
+		k.Restore = decoded.(*velerov1.Restore)
+	case "app.k8s.io/v1beta1, Kind=Application":
+		k.Application = decoded.(*applicationv1beta1.Application)
+	}
+
+	return nil
+}
+
 func LoadInstallationFromPath(installationFilePath string) (*kotsv1beta1.Installation, error) {
 	installationData, err := ioutil.ReadFile(installationFilePath)
 	if err != nil {
@@ -549,6 +573,21 @@ func LoadBackupFromContents(content []byte) (*velerov1.Backup, error) {
 	return obj.(*velerov1.Backup), nil
 }
 
+func LoadRestoreFromContents(content []byte) (*velerov1.Restore, error) {
+	decode := scheme.Codecs.UniversalDeserializer().Decode
+
+	obj, gvk, err := decode(content, nil, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to decode content")
+	}
+
+	if gvk.String() != "velero.io/v1, Kind=Restore" {
+		return nil, errors.Errorf("unexpected gvk: %s", gvk.String())
+	}
+
+	return obj.(*velerov1.Restore), nil
+}
+
 func SupportBundleToCollector(sb *troubleshootv1beta2.SupportBundle) *troubleshootv1beta2.Collector {
 	return &troubleshootv1beta2.Collector{
 		TypeMeta: metav1.TypeMeta{
@@ -583,6 +622,13 @@ type InstallationParams struct {
 	SkipImagePush      bool
 	SkipPreflights     bool
 	RegistryIsReadOnly bool
+	ReadOnlyConsole    bool
+	// the session durations are 0 when they were not set at install
+	SessionTTL          time.Duration
+	SessionIdleTimeout  time.Duration
+	SessionReauthWindow time.Duration
+	CORSAllowedOrigins  []string
+	EndpointMirrors     endpoints.Mirrors
 }
 
 func GetInstallationParams(configMapName string) (InstallationParams, error) {
@@ -605,6 +651,16 @@ func GetInstallationParams(configMapName string) (InstallationParams, error) {
 	autoConfig.SkipImagePush, _ = strconv.ParseBool(kotsadmConfigMap.Data["initial-app-images-pushed"])
 	autoConfig.SkipPreflights, _ = strconv.ParseBool(kotsadmConfigMap.Data["skip-preflights"])
 	autoConfig.RegistryIsReadOnly, _ = strconv.ParseBool(kotsadmConfigMap.Data["registry-is-read-only"])
+	autoConfig.ReadOnlyConsole, _ = strconv.ParseBool(kotsadmConfigMap.Data["read-only-console"])
+	autoConfig.SessionTTL, _ = time.ParseDuration(kotsadmConfigMap.Data["session-ttl"])
+	autoConfig.SessionIdleTimeout, _ = time.ParseDuration(kotsadmConfigMap.Data["session-idle-timeout"])
+	autoConfig.SessionReauthWindow, _ = time.ParseDuration(kotsadmConfigMap.Data["session-reauth-window"])
+	autoConfig.CORSAllowedOrigins, _ = cors.ParseOrigins(kotsadmConfigMap.Data["cors-allowed-origins"])
+	autoConfig.EndpointMirrors = endpoints.Mirrors{
+		ReplicatedAPI: strings.Split(kotsadmConfigMap.Data["replicated-api-mirrors"], ","),
+		Registry:      strings.Split(kotsadmConfigMap.Data["replicated-registry-mirrors"], ","),
+		Proxy:         strings.Split(kotsadmConfigMap.Data["replicated-proxy-mirrors"], ","),
+	}
 
 	return autoConfig, nil
 }
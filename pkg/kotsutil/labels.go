@@ -0,0 +1,55 @@
+package kotsutil
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+// Ownership labels are set on every resource that kots applies for an application so that kots and
+// third party tools can identify the resources that kots manages.
+// The operator duplicates ManagedByLabel, ManagedByLabelValue and InstanceLabel.
+const (
+	ManagedByLabel      = "app.kubernetes.io/managed-by"
+	ManagedByLabelValue = "kots"
+	// InstanceLabel is set to the app slug
+	InstanceLabel = "app.kubernetes.io/instance"
+	// VersionLabel is set to the version label of the deployed version, if it is a valid label value
+	VersionLabel = "app.kubernetes.io/version"
+	// SequenceLabel is set to the deployed sequence
+	SequenceLabel = "kots.io/app-sequence"
+)
+
+var invalidLabelValueChars = regexp.MustCompile(`[^A-Za-z0-9_.-]`)
+
+// OwnershipLabels returns the ownership labels for the resources of a deployed version of an app
+func OwnershipLabels(appSlug string, versionLabel string, sequence int64) map[string]string {
+	labels := map[string]string{
+		ManagedByLabel: ManagedByLabelValue,
+		InstanceLabel:  appSlug,
+		SequenceLabel:  strconv.FormatInt(sequence, 10),
+	}
+
+	if value := labelValueFromVersion(versionLabel); value != "" {
+		labels[VersionLabel] = value
+	}
+
+	return labels
+}
+
+// labelValueFromVersion replaces the characters of a version label that are not allowed in label values,
+// such as the "+" of semver build metadata. An empty string is returned if the result is not a valid label value.
+func labelValueFromVersion(versionLabel string) string {
+	value := invalidLabelValueChars.ReplaceAllString(versionLabel, "-")
+	if len(value) > validation.LabelValueMaxLength {
+		value = value[:validation.LabelValueMaxLength]
+	}
+	value = strings.Trim(value, "-_.")
+
+	if len(validation.IsValidLabelValue(value)) > 0 {
+		return ""
+	}
+	return value
+}
@@ -0,0 +1,66 @@
+package kotsutil
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_OwnershipLabels(t *testing.T) {
+	tests := []struct {
+		name         string
+		versionLabel string
+		want         map[string]string
+	}{
+		{
+			name:         "semver",
+			versionLabel: "1.2.3",
+			want: map[string]string{
+				ManagedByLabel: ManagedByLabelValue,
+				InstanceLabel:  "my-app",
+				VersionLabel:   "1.2.3",
+				SequenceLabel:  "4",
+			},
+		},
+		{
+			name:         "build metadata",
+			versionLabel: "1.2.3+build.5",
+			want: map[string]string{
+				ManagedByLabel: ManagedByLabelValue,
+				InstanceLabel:  "my-app",
+				VersionLabel:   "1.2.3-build.5",
+				SequenceLabel:  "4",
+			},
+		},
+		{
+			name:         "no version label",
+			versionLabel: "",
+			want: map[string]string{
+				ManagedByLabel: ManagedByLabelValue,
+				InstanceLabel:  "my-app",
+				SequenceLabel:  "4",
+			},
+		},
+		{
+			name:         "invalid version label",
+			versionLabel: "(beta)",
+			want: map[string]string{
+				ManagedByLabel: ManagedByLabelValue,
+				InstanceLabel:  "my-app",
+				VersionLabel:   "beta",
+				SequenceLabel:  "4",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, OwnershipLabels("my-app", tt.versionLabel, 4))
+		})
+	}
+}
+
+func Test_labelValueFromVersion(t *testing.T) {
+	assert.Equal(t, "", labelValueFromVersion("+++"))
+	assert.Len(t, labelValueFromVersion(strings.Repeat("1", 100)), 63)
+}
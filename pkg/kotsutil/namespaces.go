@@ -0,0 +1,127 @@
+package kotsutil
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	kotsv1beta1 "github.com/replicatedhq/kots/kotskinds/apis/kots/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	kuberneteserrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/kubernetes"
+)
+
+// ManagedNamespaceLabel is set on the namespaces that kots created for an application. The value is the app slug.
+const ManagedNamespaceLabel = "kots.io/managed-namespace-for"
+
+// AdditionalNamespaces returns the additional namespaces of the application followed by the namespaces
+// that kots manages for it
+func AdditionalNamespaces(application *kotsv1beta1.Application) []string {
+	if application == nil {
+		return nil
+	}
+
+	namespaces := append([]string{}, application.Spec.AdditionalNamespaces...)
+	for _, ns := range application.Spec.Namespaces {
+		found := false
+		for _, n := range namespaces {
+			if n == ns.Name {
+				found = true
+				break
+			}
+		}
+		if !found {
+			namespaces = append(namespaces, ns.Name)
+		}
+	}
+	return namespaces
+}
+
+// EnsureManagedNamespaces creates the namespaces that kots manages for the application and sets their labels and annotations.
+// Namespaces that kots creates are labeled with the app slug, if it is known, so that they can be deleted with the app.
+func EnsureManagedNamespaces(clientset kubernetes.Interface, application *kotsv1beta1.Application, appSlug string) error {
+	if application == nil {
+		return nil
+	}
+
+	for _, ns := range application.Spec.Namespaces {
+		existing, err := clientset.CoreV1().Namespaces().Get(context.TODO(), ns.Name, metav1.GetOptions{})
+		if kuberneteserrors.IsNotFound(err) {
+			namespace := &corev1.Namespace{
+				TypeMeta: metav1.TypeMeta{
+					APIVersion: "v1",
+					Kind:       "Namespace",
+				},
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        ns.Name,
+					Labels:      map[string]string{},
+					Annotations: map[string]string{},
+				},
+			}
+			mergeNamespaceMetadata(namespace, ns)
+			if appSlug != "" {
+				namespace.Labels[ManagedNamespaceLabel] = appSlug
+			}
+
+			if _, err := clientset.CoreV1().Namespaces().Create(context.TODO(), namespace, metav1.CreateOptions{}); err != nil {
+				return errors.Wrapf(err, "failed to create namespace %s", ns.Name)
+			}
+			continue
+		} else if err != nil {
+			return errors.Wrapf(err, "failed to get namespace %s", ns.Name)
+		}
+
+		if !mergeNamespaceMetadata(existing, ns) {
+			continue
+		}
+		if _, err := clientset.CoreV1().Namespaces().Update(context.TODO(), existing, metav1.UpdateOptions{}); err != nil {
+			return errors.Wrapf(err, "failed to update namespace %s", ns.Name)
+		}
+	}
+
+	return nil
+}
+
+// DeleteManagedNamespaces deletes the namespaces that kots created for the application
+func DeleteManagedNamespaces(clientset kubernetes.Interface, appSlug string) error {
+	namespaces, err := clientset.CoreV1().Namespaces().List(context.TODO(), metav1.ListOptions{
+		LabelSelector: labels.SelectorFromSet(map[string]string{ManagedNamespaceLabel: appSlug}).String(),
+	})
+	if err != nil {
+		return errors.Wrap(err, "failed to list namespaces")
+	}
+
+	for _, namespace := range namespaces.Items {
+		err := clientset.CoreV1().Namespaces().Delete(context.TODO(), namespace.Name, metav1.DeleteOptions{})
+		if err != nil && !kuberneteserrors.IsNotFound(err) {
+			return errors.Wrapf(err, "failed to delete namespace %s", namespace.Name)
+		}
+	}
+
+	return nil
+}
+
+// mergeNamespaceMetadata sets the labels and annotations from the spec on the namespace and returns true if any changed
+func mergeNamespaceMetadata(namespace *corev1.Namespace, ns kotsv1beta1.ApplicationNamespace) bool {
+	changed := false
+	if namespace.Labels == nil {
+		namespace.Labels = map[string]string{}
+	}
+	for k, v := range ns.Labels {
+		if namespace.Labels[k] != v {
+			namespace.Labels[k] = v
+			changed = true
+		}
+	}
+	if namespace.Annotations == nil {
+		namespace.Annotations = map[string]string{}
+	}
+	for k, v := range ns.Annotations {
+		if namespace.Annotations[k] != v {
+			namespace.Annotations[k] = v
+			changed = true
+		}
+	}
+	return changed
+}
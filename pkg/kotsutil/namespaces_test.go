@@ -0,0 +1,76 @@
+package kotsutil
+
+import (
+	"context"
+	"testing"
+
+	kotsv1beta1 "github.com/replicatedhq/kots/kotskinds/apis/kots/v1beta1"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	kuberneteserrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func Test_AdditionalNamespaces(t *testing.T) {
+	application := &kotsv1beta1.Application{
+		Spec: kotsv1beta1.ApplicationSpec{
+			AdditionalNamespaces: []string{"monitoring", "*"},
+			Namespaces: []kotsv1beta1.ApplicationNamespace{
+				{Name: "monitoring"},
+				{Name: "mesh"},
+			},
+		},
+	}
+
+	require.Equal(t, []string{"monitoring", "*", "mesh"}, AdditionalNamespaces(application))
+	require.Nil(t, AdditionalNamespaces(nil))
+}
+
+func Test_EnsureAndDeleteManagedNamespaces(t *testing.T) {
+	req := require.New(t)
+
+	clientset := fake.NewSimpleClientset(&corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   "existing",
+			Labels: map[string]string{"team": "platform"},
+		},
+	})
+
+	application := &kotsv1beta1.Application{
+		Spec: kotsv1beta1.ApplicationSpec{
+			Namespaces: []kotsv1beta1.ApplicationNamespace{
+				{
+					Name:   "mesh",
+					Labels: map[string]string{"istio-injection": "enabled"},
+				},
+				{
+					Name:        "existing",
+					Labels:      map[string]string{"pod-security.kubernetes.io/enforce": "baseline"},
+					Annotations: map[string]string{"owner": "my-app"},
+				},
+			},
+		},
+	}
+
+	err := EnsureManagedNamespaces(clientset, application, "my-app")
+	req.NoError(err)
+
+	mesh, err := clientset.CoreV1().Namespaces().Get(context.TODO(), "mesh", metav1.GetOptions{})
+	req.NoError(err)
+	req.Equal(map[string]string{"istio-injection": "enabled", ManagedNamespaceLabel: "my-app"}, mesh.Labels)
+
+	existing, err := clientset.CoreV1().Namespaces().Get(context.TODO(), "existing", metav1.GetOptions{})
+	req.NoError(err)
+	req.Equal(map[string]string{"team": "platform", "pod-security.kubernetes.io/enforce": "baseline"}, existing.Labels)
+	req.Equal(map[string]string{"owner": "my-app"}, existing.Annotations)
+
+	err = DeleteManagedNamespaces(clientset, "my-app")
+	req.NoError(err)
+
+	_, err = clientset.CoreV1().Namespaces().Get(context.TODO(), "mesh", metav1.GetOptions{})
+	req.True(kuberneteserrors.IsNotFound(err))
+
+	_, err = clientset.CoreV1().Namespaces().Get(context.TODO(), "existing", metav1.GetOptions{})
+	req.NoError(err)
+}
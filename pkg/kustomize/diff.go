@@ -4,8 +4,12 @@ import (
 	"bufio"
 	"bytes"
 	"fmt"
+	"io/ioutil"
+	"os"
 	"os/exec"
 	"path/filepath"
+	"regexp"
+	"sort"
 	"strings"
 
 	"github.com/marccampbell/yaml-toolbox/pkg/splitter"
@@ -17,8 +21,14 @@ type Diff struct {
 	FilesChanged int `json:"filesChanged"`
 	LinesAdded   int `json:"linesAdded"`
 	LinesRemoved int `json:"linesRemoved"`
+	// ImagesChanged is the number of images that are only in one of the versions
+	ImagesChanged int `json:"imagesChanged"`
+	// ConfigChanged is true if the config values differ between the versions
+	ConfigChanged bool `json:"configChanged"`
 }
 
+var imageLineRegex = regexp.MustCompile(`^\s*(?:-\s+)?image:\s*["']?([^"'\s]+)["']?\s*$`)
+
 func diffContent(baseContent string, updatedContent string) (int, int, error) {
 	dmp := diffmatchpatch.New()
 
@@ -44,34 +54,204 @@ func diffContent(baseContent string, updatedContent string) (int, int, error) {
 	return additions, deletions, nil
 }
 
+// the number of unchanged lines shown around the changes in a unified diff
+const unifiedDiffContext = 3
+
+type diffLine struct {
+	op   diffmatchpatch.Operation
+	text string
+}
+
+// unifiedDiff returns the changes between the base and updated content of a file in the unified diff format,
+// or an empty string if the content is the same
+func unifiedDiff(filename string, baseContent string, updatedContent string) string {
+	dmp := diffmatchpatch.New()
+
+	charsA, charsB, lines := dmp.DiffLinesToChars(baseContent, updatedContent)
+
+	diffs := dmp.DiffMain(charsA, charsB, false)
+	diffs = dmp.DiffCharsToLines(diffs, lines)
+
+	diffLines := []diffLine{}
+	for _, diff := range diffs {
+		for _, line := range strings.SplitAfter(diff.Text, "\n") {
+			if line == "" {
+				continue
+			}
+			diffLines = append(diffLines, diffLine{op: diff.Type, text: strings.TrimSuffix(line, "\n")})
+		}
+	}
+
+	return formatUnifiedDiff(filename, diffLines)
+}
+
+func formatUnifiedDiff(filename string, diffLines []diffLine) string {
+	// group the changed lines and the lines around them into hunks, merging hunks that overlap
+	type hunk struct{ start, end int }
+	hunks := []hunk{}
+	for i, line := range diffLines {
+		if line.op == diffmatchpatch.DiffEqual {
+			continue
+		}
+		start := i - unifiedDiffContext
+		if start < 0 {
+			start = 0
+		}
+		end := i + unifiedDiffContext + 1
+		if end > len(diffLines) {
+			end = len(diffLines)
+		}
+		if len(hunks) > 0 && start <= hunks[len(hunks)-1].end {
+			hunks[len(hunks)-1].end = end
+			continue
+		}
+		hunks = append(hunks, hunk{start: start, end: end})
+	}
+	if len(hunks) == 0 {
+		return ""
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "--- a/%s\n+++ b/%s\n", filename, filename)
+
+	baseLine, updatedLine, next := 0, 0, 0
+	for _, h := range hunks {
+		for ; next < h.start; next++ {
+			baseLine++
+			updatedLine++
+		}
+
+		baseCount, updatedCount := 0, 0
+		for _, line := range diffLines[h.start:h.end] {
+			if line.op != diffmatchpatch.DiffInsert {
+				baseCount++
+			}
+			if line.op != diffmatchpatch.DiffDelete {
+				updatedCount++
+			}
+		}
+		fmt.Fprintf(&b, "@@ -%s +%s @@\n", hunkRange(baseLine, baseCount), hunkRange(updatedLine, updatedCount))
+
+		for ; next < h.end; next++ {
+			line := diffLines[next]
+			switch line.op {
+			case diffmatchpatch.DiffInsert:
+				b.WriteString("+")
+				updatedLine++
+			case diffmatchpatch.DiffDelete:
+				b.WriteString("-")
+				baseLine++
+			default:
+				b.WriteString(" ")
+				baseLine++
+				updatedLine++
+			}
+			b.WriteString(line.text)
+			b.WriteString("\n")
+		}
+	}
+
+	return b.String()
+}
+
+// hunkRange formats the range of a hunk that starts after the given number of lines
+func hunkRange(linesBefore int, count int) string {
+	if count == 0 {
+		return fmt.Sprintf("%d,0", linesBefore)
+	}
+	return fmt.Sprintf("%d,%d", linesBefore+1, count)
+}
+
 // DiffAppVersionsForDownstream will generate a diff of the rendered yaml between two different
 // archivedirs
 func DiffAppVersionsForDownstream(downstreamName string, archive string, diffBasePath string, kustomizeVersion string) (*Diff, error) {
+	archiveFiles, baseFiles, err := buildAppVersionsForDownstream(downstreamName, archive, diffBasePath, kustomizeVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	diff, err := diffFiles(archiveFiles, baseFiles)
+	if err != nil {
+		return nil, err
+	}
+
+	configChanged, err := configValuesChanged(archive, diffBasePath)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to diff config values")
+	}
+	diff.ConfigChanged = configChanged
+
+	return diff, nil
+}
+
+// UnifiedDiffAppVersionsForDownstream will generate a diff of the rendered yaml between two different
+// archivedirs, along with a unified diff of the changed files
+func UnifiedDiffAppVersionsForDownstream(downstreamName string, archive string, diffBasePath string, kustomizeVersion string) (*Diff, string, error) {
+	archiveFiles, baseFiles, err := buildAppVersionsForDownstream(downstreamName, archive, diffBasePath, kustomizeVersion)
+	if err != nil {
+		return nil, "", err
+	}
+
+	diff, err := diffFiles(archiveFiles, baseFiles)
+	if err != nil {
+		return nil, "", err
+	}
+
+	configChanged, err := configValuesChanged(archive, diffBasePath)
+	if err != nil {
+		return nil, "", errors.Wrap(err, "failed to diff config values")
+	}
+	diff.ConfigChanged = configChanged
+
+	filenames := []string{}
+	for filename := range archiveFiles {
+		filenames = append(filenames, filename)
+	}
+	for filename := range baseFiles {
+		if _, ok := archiveFiles[filename]; !ok {
+			filenames = append(filenames, filename)
+		}
+	}
+	sort.Strings(filenames)
+
+	var unified strings.Builder
+	for _, filename := range filenames {
+		unified.WriteString(unifiedDiff(filename, string(baseFiles[filename]), string(archiveFiles[filename])))
+	}
+
+	return diff, unified.String(), nil
+}
+
+func buildAppVersionsForDownstream(downstreamName string, archive string, diffBasePath string, kustomizeVersion string) (map[string][]byte, map[string][]byte, error) {
 	// kustomize build both of these archives before diffing
 	archiveOutput, err := exec.Command(fmt.Sprintf("kustomize%s", kustomizeVersion), "build", filepath.Join(archive, "overlays", "downstreams", downstreamName)).Output()
 	if err != nil {
 		if ee, ok := err.(*exec.ExitError); ok {
 			err = fmt.Errorf("kustomize stderr: %q", string(ee.Stderr))
 		}
-		return nil, errors.Wrap(err, "failed to run kustomize on archive dir")
+		return nil, nil, errors.Wrap(err, "failed to run kustomize on archive dir")
 	}
 	baseOutput, err := exec.Command(fmt.Sprintf("kustomize%s", kustomizeVersion), "build", filepath.Join(diffBasePath, "overlays", "downstreams", downstreamName)).Output()
 	if err != nil {
 		if ee, ok := err.(*exec.ExitError); ok {
 			err = fmt.Errorf("kustomize stderr: %q", string(ee.Stderr))
 		}
-		return nil, errors.Wrap(err, "failed to run kustomize on base dir")
+		return nil, nil, errors.Wrap(err, "failed to run kustomize on base dir")
 	}
 
 	archiveFiles, err := splitter.SplitYAML(archiveOutput)
 	if err != nil {
-		return nil, errors.Wrap(err, "failed to split archive yaml")
+		return nil, nil, errors.Wrap(err, "failed to split archive yaml")
 	}
 	baseFiles, err := splitter.SplitYAML(baseOutput)
 	if err != nil {
-		return nil, errors.Wrap(err, "failed to split base yaml")
+		return nil, nil, errors.Wrap(err, "failed to split base yaml")
 	}
 
+	return archiveFiles, baseFiles, nil
+}
+
+func diffFiles(archiveFiles map[string][]byte, baseFiles map[string][]byte) (*Diff, error) {
 	diff := Diff{}
 
 	for archiveFilename, archiveContents := range archiveFiles {
@@ -111,5 +291,56 @@ func DiffAppVersionsForDownstream(downstreamName string, archive string, diffBas
 		}
 	}
 
+	archiveImages := listImages(archiveFiles)
+	baseImages := listImages(baseFiles)
+	for image := range archiveImages {
+		if !baseImages[image] {
+			diff.ImagesChanged++
+		}
+	}
+	for image := range baseImages {
+		if !archiveImages[image] {
+			diff.ImagesChanged++
+		}
+	}
+
 	return &diff, nil
 }
+
+// listImages returns the images referenced by "image:" fields in the rendered files
+func listImages(files map[string][]byte) map[string]bool {
+	images := map[string]bool{}
+	for _, contents := range files {
+		scanner := bufio.NewScanner(bytes.NewReader(contents))
+		for scanner.Scan() {
+			if matches := imageLineRegex.FindStringSubmatch(scanner.Text()); matches != nil {
+				images[matches[1]] = true
+			}
+		}
+	}
+	return images
+}
+
+// configValuesChanged compares the config values that the two archives were rendered with
+func configValuesChanged(archive string, diffBasePath string) (bool, error) {
+	archiveValues, err := readConfigValues(archive)
+	if err != nil {
+		return false, err
+	}
+	baseValues, err := readConfigValues(diffBasePath)
+	if err != nil {
+		return false, err
+	}
+	return !bytes.Equal(archiveValues, baseValues), nil
+}
+
+func readConfigValues(archive string) ([]byte, error) {
+	b, err := ioutil.ReadFile(filepath.Join(archive, "upstream", "userdata", "config.yaml"))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read config values")
+	}
+	return bytes.TrimSpace(b), nil
+}
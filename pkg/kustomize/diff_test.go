@@ -1,8 +1,10 @@
 package kustomize
 
 import (
+	"fmt"
 	"testing"
 
+	"github.com/sergi/go-diff/diffmatchpatch"
 	"github.com/stretchr/testify/require"
 	"gopkg.in/go-playground/assert.v1"
 )
@@ -58,3 +60,71 @@ func Test_diffContent(t *testing.T) {
 		})
 	}
 }
+
+func Test_formatUnifiedDiff(t *testing.T) {
+	diffLines := []diffLine{}
+	for i := 1; i <= 12; i++ {
+		diffLines = append(diffLines, diffLine{op: diffmatchpatch.DiffEqual, text: fmt.Sprintf("line%d", i)})
+		if i == 5 {
+			diffLines = append(diffLines,
+				diffLine{op: diffmatchpatch.DiffDelete, text: "old"},
+				diffLine{op: diffmatchpatch.DiffInsert, text: "new"},
+			)
+		}
+	}
+
+	expected := `--- a/deployment.yaml
++++ b/deployment.yaml
+@@ -3,7 +3,7 @@
+ line3
+ line4
+ line5
+-old
++new
+ line6
+ line7
+ line8
+`
+	require.Equal(t, expected, formatUnifiedDiff("deployment.yaml", diffLines))
+
+	require.Empty(t, formatUnifiedDiff("deployment.yaml", []diffLine{{op: diffmatchpatch.DiffEqual, text: "line1"}}))
+
+	added := []diffLine{{op: diffmatchpatch.DiffInsert, text: "a"}, {op: diffmatchpatch.DiffInsert, text: "b"}}
+	require.Equal(t, "--- a/service.yaml\n+++ b/service.yaml\n@@ -0,0 +1,2 @@\n+a\n+b\n", formatUnifiedDiff("service.yaml", added))
+}
+
+func Test_diffFilesImagesChanged(t *testing.T) {
+	req := require.New(t)
+
+	baseFiles := map[string][]byte{
+		"deployment-web.yaml": []byte(`spec:
+  template:
+    spec:
+      containers:
+      - image: nginx:1.19
+        name: web
+      - name: sidecar
+        image: "busybox:1.32"
+`),
+	}
+	archiveFiles := map[string][]byte{
+		"deployment-web.yaml": []byte(`spec:
+  template:
+    spec:
+      containers:
+      - image: nginx:1.20
+        name: web
+      - name: sidecar
+        image: "busybox:1.32"
+`),
+	}
+
+	diff, err := diffFiles(archiveFiles, baseFiles)
+	req.NoError(err)
+	req.Equal(1, diff.FilesChanged)
+	req.Equal(2, diff.ImagesChanged)
+
+	diff, err = diffFiles(baseFiles, baseFiles)
+	req.NoError(err)
+	req.Equal(0, diff.ImagesChanged)
+}
@@ -0,0 +1,294 @@
+package ldapauth
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/replicatedhq/kots/pkg/ldapauth/types"
+	"github.com/replicatedhq/kots/pkg/store"
+	"gopkg.in/ldap.v2"
+)
+
+const requestTimeout = 10 * time.Second
+
+var ErrInvalidCredentials = errors.New("invalid username or password")
+
+// GetSettings returns the ldap settings without defaults applied, or disabled settings if they were never set
+func GetSettings() (*types.Settings, error) {
+	settings, err := store.GetStore().GetLDAPSettings()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get ldap settings")
+	}
+	if settings == nil {
+		settings = &types.Settings{}
+	}
+	return settings, nil
+}
+
+// Validate checks the settings without connecting to the server
+func Validate(settings types.Settings) error {
+	if settings.Host == "" {
+		return errors.New("host is required")
+	}
+	switch settings.Security {
+	case "", types.SecurityNone, types.SecurityTLS, types.SecurityStartTLS:
+	default:
+		return errors.Errorf("unknown security %q", settings.Security)
+	}
+	if settings.CACertPEM != "" {
+		if ok := x509.NewCertPool().AppendCertsFromPEM([]byte(settings.CACertPEM)); !ok {
+			return errors.New("failed to parse ca cert")
+		}
+	}
+	if settings.UserSearch.BaseDN == "" {
+		return errors.New("user search base dn is required")
+	}
+
+	settings = withDefaults(settings)
+	if _, err := ldap.CompileFilter(userFilter(settings, "username")); err != nil {
+		return errors.Wrap(err, "invalid user search filter")
+	}
+	if settings.GroupSearch.BaseDN != "" {
+		if _, err := ldap.CompileFilter(groupFilter(settings, "member")); err != nil {
+			return errors.Wrap(err, "invalid group search filter")
+		}
+	}
+	return nil
+}
+
+// TestConnection connects to the server and binds with the service account to check that users can be searched for
+func TestConnection(settings types.Settings) error {
+	settings = withDefaults(settings)
+
+	conn, err := connect(settings)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	req := ldap.NewSearchRequest(settings.UserSearch.BaseDN, ldap.ScopeBaseObject, ldap.NeverDerefAliases, 1, 0, false,
+		"(objectClass=*)", []string{"dn"}, nil)
+	if _, err := conn.Search(req); err != nil {
+		return errors.Wrap(err, "failed to search user base dn")
+	}
+	return nil
+}
+
+// Authenticate searches for the user with the service account, binds as the user to check the password and returns
+// the names of the groups the user is a member of. ErrInvalidCredentials is returned if the user does not exist or
+// the password is wrong.
+func Authenticate(settings types.Settings, username string, password string) (*types.User, error) {
+	// an empty password is an unauthenticated bind, which most servers allow for any dn
+	if username == "" || password == "" {
+		return nil, ErrInvalidCredentials
+	}
+
+	settings = withDefaults(settings)
+
+	conn, err := connect(settings)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	userAttrs := []string{settings.UserSearch.UsernameAttr}
+	if settings.GroupSearch.UserAttr != "DN" {
+		userAttrs = append(userAttrs, settings.GroupSearch.UserAttr)
+	}
+	req := ldap.NewSearchRequest(settings.UserSearch.BaseDN, ldap.ScopeWholeSubtree, ldap.NeverDerefAliases, 2, 0, false,
+		userFilter(settings, username), userAttrs, nil)
+	result, err := conn.Search(req)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to search for user")
+	}
+	if len(result.Entries) == 0 {
+		return nil, ErrInvalidCredentials
+	}
+	if len(result.Entries) > 1 {
+		return nil, errors.Errorf("user search returned %d users for %q", len(result.Entries), username)
+	}
+	entry := result.Entries[0]
+
+	if err := conn.Bind(entry.DN, password); err != nil {
+		if ldap.IsErrorWithCode(err, ldap.LDAPResultInvalidCredentials) {
+			return nil, ErrInvalidCredentials
+		}
+		return nil, errors.Wrap(err, "failed to bind as user")
+	}
+
+	user := &types.User{
+		DN:       entry.DN,
+		Username: entry.GetAttributeValue(settings.UserSearch.UsernameAttr),
+		Groups:   []string{},
+	}
+	if user.Username == "" {
+		user.Username = username
+	}
+
+	if settings.GroupSearch.BaseDN == "" {
+		return user, nil
+	}
+
+	memberValue := entry.DN
+	if settings.GroupSearch.UserAttr != "DN" {
+		memberValue = entry.GetAttributeValue(settings.GroupSearch.UserAttr)
+		if memberValue == "" {
+			return user, nil
+		}
+	}
+
+	// search for groups as the service account, users may not be allowed to
+	if err := bindServiceAccount(conn, settings); err != nil {
+		return nil, err
+	}
+
+	req = ldap.NewSearchRequest(settings.GroupSearch.BaseDN, ldap.ScopeWholeSubtree, ldap.NeverDerefAliases, 0, 0, false,
+		groupFilter(settings, memberValue), []string{settings.GroupSearch.NameAttr}, nil)
+	result, err = conn.Search(req)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to search for groups")
+	}
+	for _, entry := range result.Entries {
+		if name := entry.GetAttributeValue(settings.GroupSearch.NameAttr); name != "" {
+			user.Groups = append(user.Groups, name)
+		}
+	}
+
+	return user, nil
+}
+
+func withDefaults(settings types.Settings) types.Settings {
+	if settings.Security == "" {
+		settings.Security = types.SecurityNone
+	}
+	if settings.UserSearch.UsernameAttr == "" {
+		settings.UserSearch.UsernameAttr = "uid"
+	}
+	if settings.GroupSearch.UserAttr == "" {
+		settings.GroupSearch.UserAttr = "DN"
+	}
+	if settings.GroupSearch.MemberAttr == "" {
+		settings.GroupSearch.MemberAttr = "member"
+	}
+	if settings.GroupSearch.NameAttr == "" {
+		settings.GroupSearch.NameAttr = "cn"
+	}
+	return settings
+}
+
+func userFilter(settings types.Settings, username string) string {
+	filter := fmt.Sprintf("(%s=%s)", settings.UserSearch.UsernameAttr, ldap.EscapeFilter(username))
+	return andFilter(settings.UserSearch.Filter, filter)
+}
+
+func groupFilter(settings types.Settings, memberValue string) string {
+	filter := fmt.Sprintf("(%s=%s)", settings.GroupSearch.MemberAttr, ldap.EscapeFilter(memberValue))
+	return andFilter(settings.GroupSearch.Filter, filter)
+}
+
+func andFilter(extra string, filter string) string {
+	extra = strings.TrimSpace(extra)
+	if extra == "" {
+		return filter
+	}
+	if !strings.HasPrefix(extra, "(") {
+		extra = fmt.Sprintf("(%s)", extra)
+	}
+	return fmt.Sprintf("(&%s%s)", extra, filter)
+}
+
+// CanReuseBindPassword returns true if the stored bind password can be used with the new settings, which is only the
+// case if they bind as the same account on the same host and port. Otherwise the stored password would be sent to a
+// server it was not set for.
+func CanReuseBindPassword(stored types.Settings, settings types.Settings) bool {
+	return stored.BindDN == settings.BindDN && hostAddress(stored) == hostAddress(settings)
+}
+
+func hostAddress(settings types.Settings) string {
+	if _, _, err := net.SplitHostPort(settings.Host); err == nil {
+		return settings.Host
+	}
+	if settings.Security == types.SecurityTLS {
+		return net.JoinHostPort(settings.Host, "636")
+	}
+	return net.JoinHostPort(settings.Host, "389")
+}
+
+func tlsConfig(settings types.Settings, address string) (*tls.Config, error) {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to split host")
+	}
+
+	config := &tls.Config{
+		ServerName:         host,
+		InsecureSkipVerify: settings.InsecureSkipTLSVerify,
+	}
+	if settings.CACertPEM != "" {
+		pool := x509.NewCertPool()
+		if ok := pool.AppendCertsFromPEM([]byte(settings.CACertPEM)); !ok {
+			return nil, errors.New("failed to parse ca cert")
+		}
+		config.RootCAs = pool
+	}
+	return config, nil
+}
+
+// connect returns a connection bound as the service account
+func connect(settings types.Settings) (*ldap.Conn, error) {
+	address := hostAddress(settings)
+
+	var conn *ldap.Conn
+	switch settings.Security {
+	case types.SecurityTLS:
+		config, err := tlsConfig(settings, address)
+		if err != nil {
+			return nil, err
+		}
+		conn, err = ldap.DialTLS("tcp", address, config)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to connect to %s", address)
+		}
+	default:
+		c, err := ldap.Dial("tcp", address)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to connect to %s", address)
+		}
+		conn = c
+	}
+	conn.SetTimeout(requestTimeout)
+
+	if settings.Security == types.SecurityStartTLS {
+		config, err := tlsConfig(settings, address)
+		if err != nil {
+			conn.Close()
+			return nil, err
+		}
+		if err := conn.StartTLS(config); err != nil {
+			conn.Close()
+			return nil, errors.Wrap(err, "failed to start tls")
+		}
+	}
+
+	if err := bindServiceAccount(conn, settings); err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	return conn, nil
+}
+
+func bindServiceAccount(conn *ldap.Conn, settings types.Settings) error {
+	if settings.BindDN == "" {
+		return nil
+	}
+	if err := conn.Bind(settings.BindDN, settings.BindPassword); err != nil {
+		return errors.Wrap(err, "failed to bind with the bind dn")
+	}
+	return nil
+}
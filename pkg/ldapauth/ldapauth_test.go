@@ -0,0 +1,135 @@
+package ldapauth
+
+import (
+	"testing"
+
+	"github.com/replicatedhq/kots/pkg/ldapauth/types"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_userFilter(t *testing.T) {
+	tests := []struct {
+		name     string
+		settings types.Settings
+		username string
+		want     string
+	}{
+		{
+			name:     "default attribute",
+			settings: types.Settings{},
+			username: "jane",
+			want:     "(uid=jane)",
+		},
+		{
+			name: "active directory with filter",
+			settings: types.Settings{
+				UserSearch: types.UserSearch{
+					Filter:       "(objectClass=person)",
+					UsernameAttr: "sAMAccountName",
+				},
+			},
+			username: "jane",
+			want:     "(&(objectClass=person)(sAMAccountName=jane))",
+		},
+		{
+			name: "filter without parens",
+			settings: types.Settings{
+				UserSearch: types.UserSearch{Filter: "objectClass=person"},
+			},
+			username: "jane",
+			want:     "(&(objectClass=person)(uid=jane))",
+		},
+		{
+			name:     "username is escaped",
+			settings: types.Settings{},
+			username: "*)(uid=*",
+			want:     `(uid=\2a\29\28uid=\2a)`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := userFilter(withDefaults(test.settings), test.username)
+			require.Equal(t, test.want, got)
+		})
+	}
+}
+
+func Test_groupFilter(t *testing.T) {
+	settings := withDefaults(types.Settings{
+		GroupSearch: types.GroupSearch{
+			Filter: "(objectClass=groupOfNames)",
+		},
+	})
+	got := groupFilter(settings, "cn=jane,ou=people,dc=example,dc=com")
+	require.Equal(t, "(&(objectClass=groupOfNames)(member=cn=jane,ou=people,dc=example,dc=com))", got)
+
+	settings = withDefaults(types.Settings{
+		GroupSearch: types.GroupSearch{
+			UserAttr:   "uid",
+			MemberAttr: "memberUid",
+		},
+	})
+	require.Equal(t, "(memberUid=jane)", groupFilter(settings, "jane"))
+}
+
+func Test_hostAddress(t *testing.T) {
+	require.Equal(t, "ldap.example.com:389", hostAddress(types.Settings{Host: "ldap.example.com", Security: types.SecurityNone}))
+	require.Equal(t, "ldap.example.com:389", hostAddress(types.Settings{Host: "ldap.example.com", Security: types.SecurityStartTLS}))
+	require.Equal(t, "ldap.example.com:636", hostAddress(types.Settings{Host: "ldap.example.com", Security: types.SecurityTLS}))
+	require.Equal(t, "ldap.example.com:3268", hostAddress(types.Settings{Host: "ldap.example.com:3268", Security: types.SecurityTLS}))
+}
+
+func Test_CanReuseBindPassword(t *testing.T) {
+	stored := types.Settings{
+		Host:     "ldap.example.com",
+		Security: types.SecurityStartTLS,
+		BindDN:   "cn=kotsadm,dc=example,dc=com",
+	}
+
+	same := stored
+	same.Host = "ldap.example.com:389"
+	same.UserSearch.BaseDN = "ou=people,dc=example,dc=com"
+	require.True(t, CanReuseBindPassword(stored, same))
+
+	otherHost := stored
+	otherHost.Host = "ldap.attacker.com"
+	require.False(t, CanReuseBindPassword(stored, otherHost))
+
+	otherPort := stored
+	otherPort.Host = "ldap.example.com:3389"
+	require.False(t, CanReuseBindPassword(stored, otherPort))
+
+	otherBindDN := stored
+	otherBindDN.BindDN = "cn=admin,dc=example,dc=com"
+	require.False(t, CanReuseBindPassword(stored, otherBindDN))
+}
+
+func Test_Validate(t *testing.T) {
+	valid := types.Settings{
+		Host:       "ldap.example.com",
+		Security:   types.SecurityStartTLS,
+		UserSearch: types.UserSearch{BaseDN: "ou=people,dc=example,dc=com"},
+	}
+	require.NoError(t, Validate(valid))
+
+	noHost := valid
+	noHost.Host = ""
+	require.Error(t, Validate(noHost))
+
+	badSecurity := valid
+	badSecurity.Security = "ssl"
+	require.Error(t, Validate(badSecurity))
+
+	noBaseDN := valid
+	noBaseDN.UserSearch.BaseDN = ""
+	require.Error(t, Validate(noBaseDN))
+
+	badFilter := valid
+	badFilter.UserSearch.Filter = "(objectClass=person"
+	require.Error(t, Validate(badFilter))
+
+	badCA := valid
+	badCA.CACertPEM = "not a cert"
+	require.Error(t, Validate(badCA))
+}
@@ -0,0 +1,61 @@
+package types
+
+import (
+	kotsv1beta1 "github.com/replicatedhq/kots/kotskinds/apis/kots/v1beta1"
+)
+
+type Security string
+
+const (
+	SecurityNone     Security = "none"
+	SecurityTLS      Security = "tls"
+	SecurityStartTLS Security = "starttls"
+)
+
+// Settings configure logging in to the admin console with the credentials of an LDAP or Active Directory user
+type Settings struct {
+	Enabled bool `json:"enabled"`
+	// Host is the host and port of the server, the port defaults to 389, or 636 for tls
+	Host                  string   `json:"host"`
+	Security              Security `json:"security"`
+	InsecureSkipTLSVerify bool     `json:"insecureSkipTLSVerify"`
+	CACertPEM             string   `json:"caCertPEM,omitempty"`
+	// BindDN and BindPassword are the credentials of the service account that searches for users and groups.
+	// The search is anonymous if BindDN is empty.
+	BindDN       string      `json:"bindDN,omitempty"`
+	BindPassword string      `json:"bindPassword,omitempty"`
+	UserSearch   UserSearch  `json:"userSearch"`
+	GroupSearch  GroupSearch `json:"groupSearch"`
+	// Groups maps the names of the groups a user is a member of to roles, as the groups of the identity service
+	// config do for OIDC
+	Groups []kotsv1beta1.IdentityConfigGroup `json:"groups,omitempty"`
+}
+
+type UserSearch struct {
+	BaseDN string `json:"baseDN"`
+	// Filter is combined with the username filter, e.g. "(objectClass=person)"
+	Filter string `json:"filter,omitempty"`
+	// UsernameAttr is the attribute matched against the username the user logs in with, "uid" by default.
+	// Active Directory uses "sAMAccountName".
+	UsernameAttr string `json:"usernameAttr,omitempty"`
+}
+
+type GroupSearch struct {
+	// BaseDN is empty if group membership is not used, the wildcard group still applies
+	BaseDN string `json:"baseDN,omitempty"`
+	// Filter is combined with the membership filter, e.g. "(objectClass=groupOfNames)"
+	Filter string `json:"filter,omitempty"`
+	// UserAttr is the attribute of the user that group members are listed by, "DN" by default
+	UserAttr string `json:"userAttr,omitempty"`
+	// MemberAttr is the attribute of the group that lists its members, "member" by default
+	MemberAttr string `json:"memberAttr,omitempty"`
+	// NameAttr is the attribute of the group matched against the ids of Groups, "cn" by default
+	NameAttr string `json:"nameAttr,omitempty"`
+}
+
+// User is an authenticated LDAP user
+type User struct {
+	DN       string
+	Username string
+	Groups   []string
+}
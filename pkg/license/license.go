@@ -10,6 +10,7 @@ import (
 	kotsv1beta1 "github.com/replicatedhq/kots/kotskinds/apis/kots/v1beta1"
 	"github.com/replicatedhq/kots/kotskinds/client/kotsclientset/scheme"
 	"github.com/replicatedhq/kots/pkg/buildversion"
+	"github.com/replicatedhq/kots/pkg/endpoints"
 )
 
 type LicenseData struct {
@@ -18,16 +19,18 @@ type LicenseData struct {
 }
 
 func GetLatestLicense(license *kotsv1beta1.License) (*LicenseData, error) {
-	url := fmt.Sprintf("%s/license/%s", license.Spec.Endpoint, license.Spec.AppSlug)
-
-	req, err := http.NewRequest("GET", url, nil)
-	if err != nil {
-		return nil, errors.Wrap(err, "failed to call newrequest")
-	}
-	req.Header.Add("User-Agent", fmt.Sprintf("KOTS/%s", buildversion.Version()))
-	req.Header.Set("Authorization", fmt.Sprintf("Basic %s", base64.StdEncoding.EncodeToString([]byte(fmt.Sprintf("%s:%s", license.Spec.LicenseID, license.Spec.LicenseID)))))
-
-	resp, err := http.DefaultClient.Do(req)
+	resp, err := endpoints.DoReplicatedAPI(license.Spec.Endpoint, func(endpoint string) (*http.Request, error) {
+		url := fmt.Sprintf("%s/license/%s", endpoint, license.Spec.AppSlug)
+
+		req, err := http.NewRequest("GET", url, nil)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to call newrequest")
+		}
+		req.Header.Add("User-Agent", fmt.Sprintf("KOTS/%s", buildversion.Version()))
+		req.Header.Set("Authorization", fmt.Sprintf("Basic %s", base64.StdEncoding.EncodeToString([]byte(fmt.Sprintf("%s:%s", license.Spec.LicenseID, license.Spec.LicenseID)))))
+
+		return req, nil
+	})
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to execute get request")
 	}
@@ -0,0 +1,76 @@
+package license
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/pkg/errors"
+	kotsv1beta1 "github.com/replicatedhq/kots/kotskinds/apis/kots/v1beta1"
+)
+
+// Summary is what an operator checks to make sure that a license file is the one they meant to install with
+type Summary struct {
+	AppSlug                    string               `json:"appSlug"`
+	CustomerName               string               `json:"customerName"`
+	ChannelName                string               `json:"channelName"`
+	LicenseID                  string               `json:"licenseID"`
+	LicenseType                string               `json:"licenseType"`
+	ExpiresAt                  *time.Time           `json:"expiresAt,omitempty"`
+	IsExpired                  bool                 `json:"isExpired"`
+	IsAirgapSupported          bool                 `json:"isAirgapSupported"`
+	IsGitOpsSupported          bool                 `json:"isGitOpsSupported"`
+	IsSnapshotSupported        bool                 `json:"isSnapshotSupported"`
+	IsIdentityServiceSupported bool                 `json:"isIdentityServiceSupported"`
+	Entitlements               []SummaryEntitlement `json:"entitlements"`
+}
+
+type SummaryEntitlement struct {
+	Name  string `json:"name"`
+	Title string `json:"title"`
+	Value string `json:"value"`
+}
+
+// Summarize returns the summary of the license. Hidden entitlements are left out, like in the admin console.
+func Summarize(license *kotsv1beta1.License, now time.Time) (*Summary, error) {
+	summary := &Summary{
+		AppSlug:                    license.Spec.AppSlug,
+		CustomerName:               license.Spec.CustomerName,
+		ChannelName:                license.Spec.ChannelName,
+		LicenseID:                  license.Spec.LicenseID,
+		LicenseType:                license.Spec.LicenseType,
+		IsAirgapSupported:          license.Spec.IsAirgapSupported,
+		IsGitOpsSupported:          license.Spec.IsGitOpsSupported,
+		IsSnapshotSupported:        license.Spec.IsSnapshotSupported,
+		IsIdentityServiceSupported: license.Spec.IsIdentityServiceSupported,
+		Entitlements:               []SummaryEntitlement{},
+	}
+
+	for name, entitlement := range license.Spec.Entitlements {
+		switch {
+		case name == "expires_at":
+			if entitlement.Value.StrVal == "" {
+				continue
+			}
+			expiresAt, err := time.Parse(time.RFC3339, entitlement.Value.StrVal)
+			if err != nil {
+				return nil, errors.Wrap(err, "failed to parse expiration date")
+			}
+			summary.ExpiresAt = &expiresAt
+			summary.IsExpired = expiresAt.Before(now)
+		case name == "gitops_enabled", entitlement.IsHidden:
+		default:
+			summary.Entitlements = append(summary.Entitlements, SummaryEntitlement{
+				Name:  name,
+				Title: entitlement.Title,
+				Value: fmt.Sprintf("%v", entitlement.Value.Value()),
+			})
+		}
+	}
+
+	sort.Slice(summary.Entitlements, func(i, j int) bool {
+		return summary.Entitlements[i].Name < summary.Entitlements[j].Name
+	})
+
+	return summary, nil
+}
@@ -0,0 +1,49 @@
+package license
+
+import (
+	"testing"
+	"time"
+
+	kotsv1beta1 "github.com/replicatedhq/kots/kotskinds/apis/kots/v1beta1"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Summarize(t *testing.T) {
+	now := time.Date(2021, 6, 1, 0, 0, 0, 0, time.UTC)
+	license := &kotsv1beta1.License{
+		Spec: kotsv1beta1.LicenseSpec{
+			AppSlug:           "my-app",
+			CustomerName:      "Acme",
+			ChannelName:       "Stable",
+			LicenseType:       "prod",
+			IsAirgapSupported: true,
+			Entitlements: map[string]kotsv1beta1.EntitlementField{
+				"expires_at": {
+					Value: kotsv1beta1.EntitlementValue{Type: kotsv1beta1.String, StrVal: "2021-05-01T00:00:00Z"},
+				},
+				"seats": {
+					Title: "Seats",
+					Value: kotsv1beta1.EntitlementValue{Type: kotsv1beta1.Int, IntVal: 10},
+				},
+				"internal": {
+					IsHidden: true,
+					Value:    kotsv1beta1.EntitlementValue{Type: kotsv1beta1.String, StrVal: "secret"},
+				},
+				"gitops_enabled": {
+					Value: kotsv1beta1.EntitlementValue{Type: kotsv1beta1.Bool, BoolVal: true},
+				},
+			},
+		},
+	}
+
+	summary, err := Summarize(license, now)
+	require.NoError(t, err)
+
+	require.Equal(t, "Acme", summary.CustomerName)
+	require.Equal(t, "Stable", summary.ChannelName)
+	require.True(t, summary.IsAirgapSupported)
+	require.False(t, summary.IsGitOpsSupported)
+	require.NotNil(t, summary.ExpiresAt)
+	require.True(t, summary.IsExpired)
+	require.Equal(t, []SummaryEntitlement{{Name: "seats", Title: "Seats", Value: "10"}}, summary.Entitlements)
+}
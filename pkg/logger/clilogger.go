@@ -2,6 +2,7 @@ package logger
 
 import (
 	"fmt"
+	"net/http"
 	"os"
 	"time"
 
@@ -16,10 +17,33 @@ type CLILogger struct {
 	spinnerArgs   []interface{}
 	isSilent      bool
 	isVerbose     bool
+	// isQuiet prints errors only, without progress, spinners and info messages
+	isQuiet bool
+}
+
+var (
+	// cliQuiet and cliVerbose are the defaults of the loggers of the cli, set by the --quiet and --verbose flags
+	cliQuiet   bool
+	cliVerbose bool
+)
+
+// SetCLIQuiet makes the loggers of the cli print errors only
+func SetCLIQuiet() {
+	cliQuiet = true
+}
+
+// SetCLIVerbose makes the loggers of the cli print debug messages, and logs the http requests made by the cli
+func SetCLIVerbose() {
+	cliVerbose = true
+	SetDebug()
+	http.DefaultClient.Transport = &debugTransport{next: http.DefaultTransport}
 }
 
 func NewCLILogger() *CLILogger {
-	return &CLILogger{}
+	return &CLILogger{
+		isQuiet:   cliQuiet,
+		isVerbose: cliVerbose,
+	}
 }
 
 func (l *CLILogger) Silence() {
@@ -36,8 +60,13 @@ func (l *CLILogger) Verbose() {
 	l.isVerbose = true
 }
 
+// isMuted returns true if progress and info messages are not printed
+func (l *CLILogger) isMuted() bool {
+	return l == nil || l.isSilent || l.isQuiet
+}
+
 func (l *CLILogger) Initialize() {
-	if l == nil || l.isSilent {
+	if l.isMuted() {
 		return
 	}
 
@@ -45,25 +74,24 @@ func (l *CLILogger) Initialize() {
 }
 
 func (l *CLILogger) Finish() {
-	if l == nil || l.isSilent {
+	if l.isMuted() {
 		return
 	}
 
 	fmt.Println("")
 }
 
+// Debug prints to stderr, so that debug messages do not mix with the output of commands
 func (l *CLILogger) Debug(msg string, args ...interface{}) {
 	if l == nil || l.isSilent || !l.isVerbose {
 		return
 	}
 
-	fmt.Printf("    ")
-	fmt.Println(fmt.Sprintf(msg, args...))
-	fmt.Println("")
+	fmt.Fprintf(os.Stderr, "    %s\n", fmt.Sprintf(msg, args...))
 }
 
 func (l *CLILogger) Info(msg string, args ...interface{}) {
-	if l == nil || l.isSilent {
+	if l.isMuted() {
 		return
 	}
 
@@ -73,7 +101,7 @@ func (l *CLILogger) Info(msg string, args ...interface{}) {
 }
 
 func (l *CLILogger) ActionWithoutSpinner(msg string, args ...interface{}) {
-	if l == nil || l.isSilent {
+	if l.isMuted() {
 		return
 	}
 
@@ -87,7 +115,7 @@ func (l *CLILogger) ActionWithoutSpinner(msg string, args ...interface{}) {
 }
 
 func (l *CLILogger) ChildActionWithoutSpinner(msg string, args ...interface{}) {
-	if l == nil || l.isSilent {
+	if l.isMuted() {
 		return
 	}
 
@@ -96,7 +124,7 @@ func (l *CLILogger) ChildActionWithoutSpinner(msg string, args ...interface{}) {
 }
 
 func (l *CLILogger) ActionWithSpinner(msg string, args ...interface{}) {
-	if l == nil || l.isSilent {
+	if l.isMuted() {
 		return
 	}
 
@@ -129,7 +157,7 @@ func (l *CLILogger) ActionWithSpinner(msg string, args ...interface{}) {
 }
 
 func (l *CLILogger) ChildActionWithSpinner(msg string, args ...interface{}) {
-	if l == nil || l.isSilent {
+	if l.isMuted() {
 		return
 	}
 
@@ -162,7 +190,7 @@ func (l *CLILogger) ChildActionWithSpinner(msg string, args ...interface{}) {
 }
 
 func (l *CLILogger) FinishChildSpinner() {
-	if l == nil || l.isSilent {
+	if l.isMuted() {
 		return
 	}
 
@@ -181,7 +209,7 @@ func (l *CLILogger) FinishChildSpinner() {
 }
 
 func (l *CLILogger) FinishSpinner() {
-	if l == nil || l.isSilent {
+	if l.isMuted() {
 		return
 	}
 
@@ -200,7 +228,7 @@ func (l *CLILogger) FinishSpinner() {
 }
 
 func (l *CLILogger) FinishSpinnerWithError() {
-	if l == nil || l.isSilent {
+	if l.isMuted() {
 		return
 	}
 
@@ -220,7 +248,7 @@ func (l *CLILogger) FinishSpinnerWithError() {
 
 // FinishSpinnerWithWarning if no color is provided, color.FgYellow will be used
 func (l *CLILogger) FinishSpinnerWithWarning(c *color.Color) {
-	if l == nil || l.isSilent {
+	if l.isMuted() {
 		return
 	}
 
@@ -259,3 +287,24 @@ func (l *CLILogger) Errorf(msg string, args ...interface{}) {
 	c.Printf("  • ")
 	c.Println(fmt.Sprintf(msg, args...))
 }
+
+// debugTransport prints the method, url, status and duration of the http requests of the cli to stderr
+type debugTransport struct {
+	next http.RoundTripper
+}
+
+func (t *debugTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	start := time.Now()
+	resp, err := t.next.RoundTrip(req)
+
+	// the query is left out, it can contain tokens
+	u := *req.URL
+	u.RawQuery = ""
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "    %s %s failed after %s: %v\n", req.Method, u.String(), time.Since(start).Round(time.Millisecond), err)
+		return nil, err
+	}
+	fmt.Fprintf(os.Stderr, "    %s %s %d (%s)\n", req.Method, u.String(), resp.StatusCode, time.Since(start).Round(time.Millisecond))
+
+	return resp, nil
+}
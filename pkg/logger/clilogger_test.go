@@ -0,0 +1,24 @@
+package logger
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_CLILoggerQuiet(t *testing.T) {
+	defer func() { cliQuiet = false }()
+
+	require.False(t, NewCLILogger().isMuted())
+
+	SetCLIQuiet()
+	l := NewCLILogger()
+	require.True(t, l.isMuted())
+
+	// spinners are not started, so finishing them must not block
+	l.ActionWithSpinner("Deploying")
+	l.FinishSpinner()
+
+	var nilLogger *CLILogger
+	require.True(t, nilLogger.isMuted())
+}
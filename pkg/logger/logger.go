@@ -1,14 +1,13 @@
 package logger
 
 import (
-	"os"
-
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
 )
 
 var log *zap.Logger
 var atom zap.AtomicLevel
+var out = &output{}
 
 func init() {
 	atom = zap.NewAtomicLevel()
@@ -18,7 +17,7 @@ func init() {
 
 	l := zap.New(zapcore.NewCore(
 		zapcore.NewJSONEncoder(encoderCfg),
-		zapcore.Lock(os.Stdout),
+		zapcore.Lock(out),
 		atom,
 	))
 	defer l.Sync()
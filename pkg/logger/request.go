@@ -0,0 +1,31 @@
+package logger
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+)
+
+type requestIDKey struct{}
+
+// ContextWithRequestID returns a copy of ctx that carries the request id
+func ContextWithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, requestID)
+}
+
+// RequestIDFromContext returns the request id stored in ctx, or an empty string if there is none
+func RequestIDFromContext(ctx context.Context) string {
+	requestID, _ := ctx.Value(requestIDKey{}).(string)
+	return requestID
+}
+
+// RequestID is the field used to correlate log lines that belong to the same request
+func RequestID(requestID string) zap.Field {
+	return zap.String("request_id", requestID)
+}
+
+// InfoFields logs msg with fields as structured keys instead of formatting them into the message
+func InfoFields(msg string, fields ...zap.Field) {
+	defer log.Sync()
+	log.Info(msg, fields...)
+}
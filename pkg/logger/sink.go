@@ -0,0 +1,387 @@
+package logger
+
+import (
+	"bytes"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"log/syslog"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/replicatedhq/kots/pkg/logger/types"
+	"go.uber.org/zap/zapcore"
+)
+
+const (
+	// sinkBufferSize is how many log lines are buffered for the sink, lines are dropped when the buffer is full so
+	// that a slow or unreachable sink never blocks logging
+	sinkBufferSize = 10000
+	sinkBatchSize  = 500
+	sinkFlushEvery = 2 * time.Second
+)
+
+// output writes log lines to stdout, and to the sink if one is configured
+type output struct {
+	mtx  sync.Mutex
+	sink *asyncSink
+}
+
+func (o *output) Write(p []byte) (int, error) {
+	n, err := os.Stdout.Write(p)
+
+	o.mtx.Lock()
+	if o.sink != nil {
+		o.sink.enqueue(p)
+	}
+	o.mtx.Unlock()
+
+	return n, err
+}
+
+func (o *output) Sync() error {
+	return os.Stdout.Sync()
+}
+
+func (o *output) setSink(s *asyncSink) {
+	o.mtx.Lock()
+	previous := o.sink
+	o.sink = s
+	o.mtx.Unlock()
+
+	if previous != nil {
+		previous.stop()
+	}
+}
+
+// Configure applies the log level and sink of the settings. Nothing is changed if the settings are invalid or the
+// sink cannot be created.
+func Configure(settings types.Settings) error {
+	level, err := ParseLevel(settings.Level)
+	if err != nil {
+		return err
+	}
+
+	var s *asyncSink
+	if settings.Sink != nil {
+		sndr, err := newSender(*settings.Sink)
+		if err != nil {
+			return errors.Wrap(err, "failed to create log sink")
+		}
+		s = newAsyncSink(sndr)
+	}
+
+	atom.SetLevel(level)
+	out.setSink(s)
+
+	return nil
+}
+
+// ParseLevel parses a log level setting, an empty level is info
+func ParseLevel(level string) (zapcore.Level, error) {
+	if level == "" {
+		return zapcore.InfoLevel, nil
+	}
+	var l zapcore.Level
+	if err := l.UnmarshalText([]byte(level)); err != nil {
+		return l, errors.Errorf("invalid log level %q", level)
+	}
+	switch l {
+	case zapcore.DebugLevel, zapcore.InfoLevel, zapcore.WarnLevel, zapcore.ErrorLevel:
+		return l, nil
+	}
+	return l, errors.Errorf("unsupported log level %q", level)
+}
+
+// GetLevel returns the current log level, e.g. "info"
+func GetLevel() string {
+	return atom.Level().String()
+}
+
+type sender interface {
+	send(lines [][]byte) error
+	close() error
+}
+
+func newSender(sink types.Sink) (sender, error) {
+	switch sink.Type {
+	case types.SinkTypeSyslog:
+		return newSyslogSender(sink)
+	case types.SinkTypeHTTP:
+		return newHTTPSender(sink)
+	case types.SinkTypeLoki:
+		return newLokiSender(sink)
+	}
+	return nil, errors.Errorf("unsupported sink type %q", sink.Type)
+}
+
+// asyncSink sends the log lines to the sender in batches from its own goroutine
+type asyncSink struct {
+	sender sender
+	lines  chan []byte
+	done   chan struct{}
+}
+
+func newAsyncSink(s sender) *asyncSink {
+	a := &asyncSink{
+		sender: s,
+		lines:  make(chan []byte, sinkBufferSize),
+		done:   make(chan struct{}),
+	}
+	go a.run()
+	return a
+}
+
+func (a *asyncSink) enqueue(p []byte) {
+	// the encoder reuses its buffer once the write returns
+	line := make([]byte, len(p))
+	copy(line, p)
+
+	select {
+	case a.lines <- line:
+	default:
+	}
+}
+
+// stop sends the buffered lines and closes the sender. Nothing can be enqueued once stop is called.
+func (a *asyncSink) stop() {
+	close(a.lines)
+	<-a.done
+}
+
+func (a *asyncSink) run() {
+	ticker := time.NewTicker(sinkFlushEvery)
+	defer ticker.Stop()
+
+	batch := [][]byte{}
+	flush := func() {
+		if len(batch) == 0 {
+			return
+		}
+		if err := a.sender.send(batch); err != nil {
+			// logging the error would send it to the sink again
+			fmt.Fprintf(os.Stderr, "failed to send %d log lines to sink: %v\n", len(batch), err)
+		}
+		batch = [][]byte{}
+	}
+
+	for {
+		select {
+		case line, ok := <-a.lines:
+			if !ok {
+				flush()
+				if err := a.sender.close(); err != nil {
+					fmt.Fprintf(os.Stderr, "failed to close log sink: %v\n", err)
+				}
+				close(a.done)
+				return
+			}
+			batch = append(batch, line)
+			if len(batch) >= sinkBatchSize {
+				flush()
+			}
+		case <-ticker.C:
+			flush()
+		}
+	}
+}
+
+type syslogSender struct {
+	writer *syslog.Writer
+}
+
+func newSyslogSender(sink types.Sink) (*syslogSender, error) {
+	u, err := url.Parse(sink.Address)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to parse address")
+	}
+	if u.Scheme != "udp" && u.Scheme != "tcp" {
+		return nil, errors.Errorf("address must start with udp:// or tcp://")
+	}
+
+	w, err := syslog.Dial(u.Scheme, u.Host, syslog.LOG_INFO|syslog.LOG_DAEMON, "kotsadm")
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to dial syslog server")
+	}
+
+	return &syslogSender{writer: w}, nil
+}
+
+func (s *syslogSender) send(lines [][]byte) error {
+	for _, line := range lines {
+		msg := strings.TrimSuffix(string(line), "\n")
+		var err error
+		switch lineLevel(line) {
+		case "debug":
+			err = s.writer.Debug(msg)
+		case "warn":
+			err = s.writer.Warning(msg)
+		case "error", "dpanic", "panic", "fatal":
+			err = s.writer.Err(msg)
+		default:
+			err = s.writer.Info(msg)
+		}
+		if err != nil {
+			return errors.Wrap(err, "failed to write to syslog")
+		}
+	}
+	return nil
+}
+
+func (s *syslogSender) close() error {
+	return s.writer.Close()
+}
+
+// lineLevel returns the level of a json encoded log line
+func lineLevel(line []byte) string {
+	l := struct {
+		Level string `json:"level"`
+	}{}
+	_ = json.Unmarshal(line, &l)
+	return l.Level
+}
+
+type httpSender struct {
+	address    string
+	authHeader string
+	client     *http.Client
+}
+
+func newHTTPSender(sink types.Sink) (*httpSender, error) {
+	u, err := url.Parse(sink.Address)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to parse address")
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, errors.Errorf("address must start with http:// or https://")
+	}
+
+	return &httpSender{
+		address:    sink.Address,
+		authHeader: sink.AuthHeader,
+		client:     sinkHTTPClient(sink.InsecureSkipTLSVerify),
+	}, nil
+}
+
+func (s *httpSender) send(lines [][]byte) error {
+	body := bytes.Join(lines, nil)
+	return postLogs(s.client, s.address, "application/x-ndjson", s.authHeader, body)
+}
+
+func (s *httpSender) close() error {
+	return nil
+}
+
+type lokiSender struct {
+	pushURL    string
+	authHeader string
+	labels     map[string]string
+	client     *http.Client
+}
+
+func newLokiSender(sink types.Sink) (*lokiSender, error) {
+	u, err := url.Parse(sink.Address)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to parse address")
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, errors.Errorf("address must start with http:// or https://")
+	}
+	u.Path = strings.TrimSuffix(u.Path, "/") + "/loki/api/v1/push"
+
+	labels := map[string]string{}
+	for k, v := range sink.Labels {
+		labels[k] = v
+	}
+	if _, ok := labels["app"]; !ok {
+		labels["app"] = "kotsadm"
+	}
+
+	return &lokiSender{
+		pushURL:    u.String(),
+		authHeader: sink.AuthHeader,
+		labels:     labels,
+		client:     sinkHTTPClient(sink.InsecureSkipTLSVerify),
+	}, nil
+}
+
+type lokiPushRequest struct {
+	Streams []lokiStream `json:"streams"`
+}
+
+type lokiStream struct {
+	Stream map[string]string `json:"stream"`
+	// Values are pairs of a unix timestamp in nanoseconds and a log line
+	Values [][2]string `json:"values"`
+}
+
+func (s *lokiSender) send(lines [][]byte) error {
+	body, err := json.Marshal(lokiPush(s.labels, lines, time.Now()))
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal push request")
+	}
+	return postLogs(s.client, s.pushURL, "application/json", s.authHeader, body)
+}
+
+func (s *lokiSender) close() error {
+	return nil
+}
+
+// lokiPush builds a push request with one stream for the lines. Loki rejects out of order lines in a stream, so the
+// lines are timestamped when they are sent rather than by their own timestamps.
+func lokiPush(labels map[string]string, lines [][]byte, now time.Time) lokiPushRequest {
+	stream := lokiStream{
+		Stream: labels,
+		Values: [][2]string{},
+	}
+	ts := now.UnixNano()
+	for i, line := range lines {
+		stream.Values = append(stream.Values, [2]string{
+			strconv.FormatInt(ts+int64(i), 10),
+			strings.TrimSuffix(string(line), "\n"),
+		})
+	}
+	return lokiPushRequest{Streams: []lokiStream{stream}}
+}
+
+func sinkHTTPClient(insecureSkipTLSVerify bool) *http.Client {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = &tls.Config{
+		InsecureSkipVerify: insecureSkipTLSVerify,
+	}
+	return &http.Client{
+		Transport: transport,
+		Timeout:   10 * time.Second,
+	}
+}
+
+func postLogs(client *http.Client, address string, contentType string, authHeader string, body []byte) error {
+	req, err := http.NewRequest("POST", address, bytes.NewReader(body))
+	if err != nil {
+		return errors.Wrap(err, "failed to create request")
+	}
+	req.Header.Set("Content-Type", contentType)
+	if authHeader != "" {
+		req.Header.Set("Authorization", authHeader)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return errors.Wrap(err, "failed to execute request")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		respBody, _ := ioutil.ReadAll(resp.Body)
+		return errors.Errorf("unexpected status code %d: %s", resp.StatusCode, respBody)
+	}
+
+	return nil
+}
@@ -0,0 +1,63 @@
+package logger
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zapcore"
+)
+
+func Test_ParseLevel(t *testing.T) {
+	tests := []struct {
+		level   string
+		want    zapcore.Level
+		wantErr bool
+	}{
+		{level: "", want: zapcore.InfoLevel},
+		{level: "debug", want: zapcore.DebugLevel},
+		{level: "warn", want: zapcore.WarnLevel},
+		{level: "error", want: zapcore.ErrorLevel},
+		{level: "fatal", wantErr: true},
+		{level: "verbose", wantErr: true},
+	}
+	for _, test := range tests {
+		t.Run(test.level, func(t *testing.T) {
+			req := require.New(t)
+
+			got, err := ParseLevel(test.level)
+			if test.wantErr {
+				req.Error(err)
+				return
+			}
+			req.NoError(err)
+			req.Equal(test.want, got)
+		})
+	}
+}
+
+func Test_lokiPush(t *testing.T) {
+	req := require.New(t)
+
+	now := time.Unix(1600000000, 0)
+	labels := map[string]string{"app": "kotsadm"}
+	lines := [][]byte{
+		[]byte("{\"level\":\"info\",\"msg\":\"a\"}\n"),
+		[]byte("{\"level\":\"error\",\"msg\":\"b\"}\n"),
+	}
+
+	got := lokiPush(labels, lines, now)
+	req.Len(got.Streams, 1)
+	req.Equal(labels, got.Streams[0].Stream)
+	req.Equal([][2]string{
+		{"1600000000000000000", "{\"level\":\"info\",\"msg\":\"a\"}"},
+		{"1600000000000000001", "{\"level\":\"error\",\"msg\":\"b\"}"},
+	}, got.Streams[0].Values)
+}
+
+func Test_lineLevel(t *testing.T) {
+	req := require.New(t)
+
+	req.Equal("warn", lineLevel([]byte("{\"level\":\"warn\",\"msg\":\"a\"}\n")))
+	req.Equal("", lineLevel([]byte("not json")))
+}
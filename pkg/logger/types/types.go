@@ -0,0 +1,31 @@
+package types
+
+type SinkType string
+
+const (
+	// SinkTypeSyslog sends each log line to a syslog server, Address is e.g. "udp://syslog.example.com:514"
+	SinkTypeSyslog SinkType = "syslog"
+	// SinkTypeHTTP posts batches of log lines as newline delimited json to Address
+	SinkTypeHTTP SinkType = "http"
+	// SinkTypeLoki pushes batches of log lines to the Loki server at Address
+	SinkTypeLoki SinkType = "loki"
+)
+
+// Settings configure the log level of kotsadm and the operator, and where the structured logs of kotsadm are shipped
+// to in addition to stdout. They are applied without restarting either.
+type Settings struct {
+	// Level is one of "debug", "info", "warn" or "error", "info" if empty
+	Level string `json:"level"`
+	// Sink is nil if logs are only written to stdout
+	Sink *Sink `json:"sink,omitempty"`
+}
+
+type Sink struct {
+	Type    SinkType `json:"type"`
+	Address string   `json:"address"`
+	// AuthHeader is sent as the Authorization header of http and loki requests
+	AuthHeader            string `json:"authHeader,omitempty"`
+	InsecureSkipTLSVerify bool   `json:"insecureSkipTLSVerify"`
+	// Labels are added to the loki stream, the "app" label is "kotsadm" if not set
+	Labels map[string]string `json:"labels,omitempty"`
+}
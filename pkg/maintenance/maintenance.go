@@ -0,0 +1,45 @@
+package maintenance
+
+import (
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/replicatedhq/kots/pkg/maintenance/types"
+	"github.com/replicatedhq/kots/pkg/store"
+)
+
+// GetMaintenanceMessage returns the maintenance message to show for an app: the app's own message
+// unless its window has ended, otherwise the global message unless its window has ended.
+// Nil is returned if there is no message to show.
+func GetMaintenanceMessage(appID string) (*types.MaintenanceMessage, error) {
+	now := time.Now()
+
+	appMessage, err := store.GetStore().GetAppMaintenanceMessage(appID)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get app maintenance message")
+	}
+	if appMessage != nil && !appMessage.IsExpired(now) {
+		return appMessage, nil
+	}
+
+	globalMessage, err := store.GetStore().GetGlobalMaintenanceMessage()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get global maintenance message")
+	}
+	if globalMessage != nil && !globalMessage.IsExpired(now) {
+		return globalMessage, nil
+	}
+
+	return nil, nil
+}
+
+// Validate returns an error if the maintenance message cannot be shown
+func Validate(message types.MaintenanceMessage) error {
+	if message.Message == "" {
+		return errors.New("message is required")
+	}
+	if message.StartsAt != nil && message.EndsAt != nil && !message.EndsAt.After(*message.StartsAt) {
+		return errors.New("end time must be after start time")
+	}
+	return nil
+}
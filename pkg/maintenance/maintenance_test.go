@@ -0,0 +1,66 @@
+package maintenance
+
+import (
+	"testing"
+	"time"
+
+	"github.com/replicatedhq/kots/pkg/maintenance/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Validate(t *testing.T) {
+	now := time.Now()
+	later := now.Add(time.Hour)
+
+	tests := []struct {
+		name    string
+		message types.MaintenanceMessage
+		wantErr bool
+	}{
+		{
+			name:    "message only",
+			message: types.MaintenanceMessage{Message: "upgrading the database"},
+			wantErr: false,
+		},
+		{
+			name:    "window",
+			message: types.MaintenanceMessage{Message: "upgrading the database", StartsAt: &now, EndsAt: &later},
+			wantErr: false,
+		},
+		{
+			name:    "empty message",
+			message: types.MaintenanceMessage{StartsAt: &now},
+			wantErr: true,
+		},
+		{
+			name:    "ends before it starts",
+			message: types.MaintenanceMessage{Message: "upgrading the database", StartsAt: &later, EndsAt: &now},
+			wantErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := Validate(test.message)
+			if test.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func Test_MaintenanceMessageWindow(t *testing.T) {
+	startsAt := time.Date(2021, 6, 1, 10, 0, 0, 0, time.UTC)
+	endsAt := startsAt.Add(2 * time.Hour)
+	message := types.MaintenanceMessage{Message: "upgrading the database", StartsAt: &startsAt, EndsAt: &endsAt}
+
+	assert.False(t, message.IsActive(startsAt.Add(-time.Minute)))
+	assert.False(t, message.IsExpired(startsAt.Add(-time.Minute)))
+
+	assert.True(t, message.IsActive(startsAt.Add(time.Minute)))
+
+	assert.False(t, message.IsActive(endsAt))
+	assert.True(t, message.IsExpired(endsAt))
+}
@@ -0,0 +1,27 @@
+package types
+
+import (
+	"time"
+)
+
+// MaintenanceMessage is shown to the users of the admin console during a maintenance window, and ahead of it
+type MaintenanceMessage struct {
+	Message string `json:"message"`
+	// StartsAt is nil if the maintenance window has already started
+	StartsAt *time.Time `json:"startsAt,omitempty"`
+	// EndsAt is nil if the maintenance window has no planned end
+	EndsAt *time.Time `json:"endsAt,omitempty"`
+}
+
+// IsActive returns true if now is within the maintenance window
+func (m MaintenanceMessage) IsActive(now time.Time) bool {
+	if m.StartsAt != nil && now.Before(*m.StartsAt) {
+		return false
+	}
+	return !m.IsExpired(now)
+}
+
+// IsExpired returns true if the maintenance window has ended
+func (m MaintenanceMessage) IsExpired(now time.Time) bool {
+	return m.EndsAt != nil && !now.Before(*m.EndsAt)
+}
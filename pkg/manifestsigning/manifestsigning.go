@@ -0,0 +1,302 @@
+// Package manifestsigning signs the manifests that kotsadm sends to the operator, and the checksums of the app
+// version archives, so that manifests and archives that were tampered with at rest or in transit are detected
+// before they are applied
+package manifestsigning
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+	"github.com/replicatedhq/kots/pkg/k8sutil"
+	"github.com/replicatedhq/kots/pkg/kotsadm/types"
+	"github.com/replicatedhq/kots/pkg/vault"
+	kuberneteserrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// PublicKeyKey is the key of the hex encoded public key in the signing secret, the operator verifies with it
+	PublicKeyKey = "public-key"
+	// PrivateKeyKey is the key of the hex encoded ed25519 seed in the signing secret
+	PrivateKeyKey = "private-key"
+	// VaultTransitKeyKey is the key of the name of an ed25519 key of the vault transit secrets engine in the signing
+	// secret. If it is set, the manifests are signed with the transit key instead of the private key, and the public
+	// key must be set to the public key of the transit key.
+	VaultTransitKeyKey = "vault-transit-key"
+	// VaultTransitMountKey is the key of the mount path of the transit secrets engine, transit by default
+	VaultTransitMountKey = "vault-transit-mount"
+
+	defaultVaultTransitMount = "transit"
+
+	manifestsDigestVersion = "kots-manifests-v1"
+	archiveDigestVersion   = "kots-archive-v1"
+)
+
+// ErrInvalidSignature is the cause of errors returned when a signature does not match
+var ErrInvalidSignature = errors.New("signature is invalid")
+
+// Signer signs with the manifest signing key
+type Signer interface {
+	Sign(message []byte) ([]byte, error)
+	PublicKey() ed25519.PublicKey
+}
+
+// Manifests are the fields of a deploy that are signed. The operator computes the same digest, the two must be
+// kept in sync.
+type Manifests struct {
+	AppID     string
+	Namespace string
+	// Manifests and PreviousManifests are base64 encoded, as they are sent to the operator
+	Manifests         string
+	PreviousManifests string
+	ImagePullSecret   string
+	ClearNamespaces   []string
+	// DeployPhase is empty unless the app is deployed in two phases
+	DeployPhase string
+}
+
+// Digest returns the sha256 of the length prefixed fields, so that no two deploys have the same digest
+func (m Manifests) Digest() []byte {
+	fields := []string{manifestsDigestVersion, m.AppID, m.Namespace, m.Manifests, m.PreviousManifests, m.ImagePullSecret, strings.Join(m.ClearNamespaces, ",")}
+	// the phase is only part of the digest when it is set, so that operators that don't know about phases verify the
+	// deploys of apps that are deployed in a single phase
+	if m.DeployPhase != "" {
+		fields = append(fields, m.DeployPhase)
+	}
+	return digest(fields...)
+}
+
+var (
+	sharedSigner                Signer
+	sharedSignerResourceVersion string
+	sharedSignerMtx             sync.Mutex
+)
+
+// GenerateKey returns a new hex encoded ed25519 key pair for the signing secret
+func GenerateKey() (publicKey string, privateKey string, err error) {
+	public, private, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return "", "", errors.Wrap(err, "failed to generate key")
+	}
+
+	return hex.EncodeToString(public), hex.EncodeToString(private.Seed()), nil
+}
+
+// GetSigner returns the signer of the signing secret in the kotsadm namespace. It returns nil if the secret does
+// not exist, as for installs from before manifests were signed.
+func GetSigner() (Signer, error) {
+	clientset, err := k8sutil.GetClientset()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get k8s clientset")
+	}
+
+	secret, err := clientset.CoreV1().Secrets(os.Getenv("POD_NAMESPACE")).Get(context.TODO(), types.ManifestSigningSecret, metav1.GetOptions{})
+	if kuberneteserrors.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get signing secret")
+	}
+
+	sharedSignerMtx.Lock()
+	defer sharedSignerMtx.Unlock()
+
+	if sharedSigner != nil && sharedSignerResourceVersion == secret.ResourceVersion {
+		return sharedSigner, nil
+	}
+
+	signer, err := newSigner(secret.Data)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create signer")
+	}
+	sharedSigner = signer
+	sharedSignerResourceVersion = secret.ResourceVersion
+
+	return sharedSigner, nil
+}
+
+func newSigner(data map[string][]byte) (Signer, error) {
+	publicKey, err := ParsePublicKey(string(data[PublicKeyKey]))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to parse public key")
+	}
+
+	if transitKey := string(data[VaultTransitKeyKey]); transitKey != "" {
+		mount := string(data[VaultTransitMountKey])
+		if mount == "" {
+			mount = defaultVaultTransitMount
+		}
+		return newVaultSigner(mount, transitKey, publicKey)
+	}
+
+	seed, err := hex.DecodeString(strings.TrimSpace(string(data[PrivateKeyKey])))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to decode private key")
+	}
+	if len(seed) != ed25519.SeedSize {
+		return nil, errors.Errorf("private key has length %d, expected %d", len(seed), ed25519.SeedSize)
+	}
+
+	signer := keySigner{privateKey: ed25519.NewKeyFromSeed(seed)}
+	if !signer.PublicKey().Equal(publicKey) {
+		return nil, errors.New("the public key is not the public key of the private key")
+	}
+
+	return signer, nil
+}
+
+// ParsePublicKey parses a hex encoded ed25519 public key
+func ParsePublicKey(s string) (ed25519.PublicKey, error) {
+	decoded, err := hex.DecodeString(strings.TrimSpace(s))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to decode")
+	}
+	if len(decoded) != ed25519.PublicKeySize {
+		return nil, errors.Errorf("public key has length %d, expected %d", len(decoded), ed25519.PublicKeySize)
+	}
+
+	return ed25519.PublicKey(decoded), nil
+}
+
+type keySigner struct {
+	privateKey ed25519.PrivateKey
+}
+
+func (s keySigner) Sign(message []byte) ([]byte, error) {
+	return ed25519.Sign(s.privateKey, message), nil
+}
+
+func (s keySigner) PublicKey() ed25519.PublicKey {
+	return s.privateKey.Public().(ed25519.PublicKey)
+}
+
+type vaultSigner struct {
+	client    *vault.Client
+	mount     string
+	keyName   string
+	publicKey ed25519.PublicKey
+}
+
+func newVaultSigner(mount string, keyName string, publicKey ed25519.PublicKey) (Signer, error) {
+	client, err := vault.GetSharedClient()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get vault client")
+	}
+	if client == nil {
+		return nil, errors.New("a vault transit key is set but the vault integration is not configured")
+	}
+
+	transitPublicKey, err := client.TransitPublicKey(mount, keyName)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get public key of transit key %s", keyName)
+	}
+	// the operator would reject every deploy
+	if !transitPublicKey.Equal(publicKey) {
+		return nil, errors.Errorf("the public key is not the public key of transit key %s", keyName)
+	}
+
+	return vaultSigner{
+		client:    client,
+		mount:     mount,
+		keyName:   keyName,
+		publicKey: publicKey,
+	}, nil
+}
+
+func (s vaultSigner) Sign(message []byte) ([]byte, error) {
+	return s.client.TransitSign(s.mount, s.keyName, message)
+}
+
+func (s vaultSigner) PublicKey() ed25519.PublicKey {
+	return s.publicKey
+}
+
+// SignManifests returns the base64 encoded signature of the manifests, empty if there is no signing key
+func SignManifests(manifests Manifests) (string, error) {
+	signer, err := GetSigner()
+	if err != nil {
+		return "", errors.Wrap(err, "failed to get signer")
+	}
+	if signer == nil {
+		return "", nil
+	}
+
+	signature, err := signer.Sign(manifests.Digest())
+	if err != nil {
+		return "", errors.Wrap(err, "failed to sign manifests")
+	}
+
+	return base64.StdEncoding.EncodeToString(signature), nil
+}
+
+// SignArchiveChecksum returns the base64 encoded signature of the checksum of an app version archive, recorded
+// when the version is created. It is empty if there is no signing key.
+func SignArchiveChecksum(appID string, sequence int64, sum string) (string, error) {
+	signer, err := GetSigner()
+	if err != nil {
+		return "", errors.Wrap(err, "failed to get signer")
+	}
+	if signer == nil {
+		return "", nil
+	}
+
+	signature, err := signer.Sign(archiveDigest(appID, sequence, sum))
+	if err != nil {
+		return "", errors.Wrap(err, "failed to sign archive checksum")
+	}
+
+	return base64.StdEncoding.EncodeToString(signature), nil
+}
+
+// VerifyArchiveChecksum verifies the signature of the checksum of an app version archive. Checksums that were
+// recorded without a signature, and all checksums if there is no signing key, are not verified.
+func VerifyArchiveChecksum(appID string, sequence int64, sum string, signature string) error {
+	if signature == "" {
+		return nil
+	}
+
+	signer, err := GetSigner()
+	if err != nil {
+		return errors.Wrap(err, "failed to get signer")
+	}
+	if signer == nil {
+		return nil
+	}
+
+	return verify(signer.PublicKey(), archiveDigest(appID, sequence, sum), signature)
+}
+
+func verify(publicKey ed25519.PublicKey, message []byte, signature string) error {
+	decoded, err := base64.StdEncoding.DecodeString(signature)
+	if err != nil {
+		return errors.Wrap(ErrInvalidSignature, "failed to decode signature")
+	}
+	if !ed25519.Verify(publicKey, message, decoded) {
+		return ErrInvalidSignature
+	}
+
+	return nil
+}
+
+func archiveDigest(appID string, sequence int64, sum string) []byte {
+	return digest(archiveDigestVersion, appID, fmt.Sprintf("%d", sequence), sum)
+}
+
+func digest(fields ...string) []byte {
+	h := sha256.New()
+	for _, field := range fields {
+		binary.Write(h, binary.BigEndian, uint64(len(field)))
+		h.Write([]byte(field))
+	}
+	return h.Sum(nil)
+}
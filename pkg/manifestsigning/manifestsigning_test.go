@@ -0,0 +1,68 @@
+package manifestsigning
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ManifestsDigest(t *testing.T) {
+	req := require.New(t)
+
+	manifests := Manifests{
+		AppID:           "app-id",
+		Namespace:       "default",
+		Manifests:       "bWFuaWZlc3Rz",
+		ClearNamespaces: []string{"ns-a", "ns-b"},
+	}
+	// the operator verifies against the same digest
+	req.Equal("657c2ae297b05c8fe29f0b320ff6823128bd1f7c50594d449e900aadbbfaf4b9", hex.EncodeToString(manifests.Digest()))
+
+	// moving a byte from one field to the next changes the digest
+	moved := manifests
+	moved.AppID = "app-i"
+	moved.Namespace = "ddefault"
+	req.NotEqual(manifests.Digest(), moved.Digest())
+
+	// the phase of a two phase deploy is signed
+	activate := manifests
+	activate.DeployPhase = "activate"
+	req.NotEqual(manifests.Digest(), activate.Digest())
+}
+
+func Test_newSigner(t *testing.T) {
+	req := require.New(t)
+
+	publicKey, privateKey, err := GenerateKey()
+	req.NoError(err)
+
+	signer, err := newSigner(map[string][]byte{
+		PublicKeyKey:  []byte(publicKey),
+		PrivateKeyKey: []byte(privateKey),
+	})
+	req.NoError(err)
+
+	message := archiveDigest("app-id", 3, "abc")
+	signature, err := signer.Sign(message)
+	req.NoError(err)
+
+	encoded := base64.StdEncoding.EncodeToString(signature)
+	req.NoError(verify(signer.PublicKey(), message, encoded))
+	req.Error(verify(signer.PublicKey(), archiveDigest("app-id", 4, "abc"), encoded))
+
+	otherPublicKey, _, err := GenerateKey()
+	req.NoError(err)
+	_, err = newSigner(map[string][]byte{
+		PublicKeyKey:  []byte(otherPublicKey),
+		PrivateKeyKey: []byte(privateKey),
+	})
+	req.Error(err)
+
+	_, err = newSigner(map[string][]byte{
+		PublicKeyKey:  []byte(publicKey),
+		PrivateKeyKey: []byte("abcd"),
+	})
+	req.Error(err)
+}
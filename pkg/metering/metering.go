@@ -0,0 +1,152 @@
+package metering
+
+import (
+	"time"
+
+	"github.com/pkg/errors"
+	kotsv1beta1 "github.com/replicatedhq/kots/kotskinds/apis/kots/v1beta1"
+	"github.com/replicatedhq/kots/pkg/metering/types"
+	"github.com/replicatedhq/kots/pkg/store"
+)
+
+var (
+	ErrUnknownEntitlement = errors.New("entitlement is not metered by this application")
+	ErrLimitExceeded      = errors.New("entitlement limit exceeded")
+)
+
+// ReportUsage records the value reported by source for a metered entitlement.
+// When the entitlement blocks on its limit and the new total would be over the limit,
+// the value is not recorded and ErrLimitExceeded is returned along with the current status.
+// The limit is checked by the store in the same transaction that records the value.
+func ReportUsage(appID string, name string, source string, value int64) (*types.EntitlementUsageStatus, error) {
+	entitlements, err := getMeteredEntitlements(appID)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get metered entitlements")
+	}
+
+	var entitlement *kotsv1beta1.MeteredEntitlement
+	for _, e := range entitlements {
+		if e.Name == name {
+			entitlement = &e
+			break
+		}
+	}
+	if entitlement == nil {
+		return nil, ErrUnknownEntitlement
+	}
+
+	license, err := store.GetStore().GetLatestLicenseForApp(appID)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get license")
+	}
+
+	var limit *int64
+	if types.Enforcement(entitlement.Enforcement) == types.EnforcementBlock {
+		limit = getLimit(*entitlement, license)
+	}
+
+	recorded, err := store.GetStore().SetEntitlementUsage(appID, name, source, value, time.Now(), limit)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to set entitlement usage")
+	}
+
+	usage, err := store.GetStore().ListEntitlementUsage(appID)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list entitlement usage")
+	}
+
+	status := Evaluate([]kotsv1beta1.MeteredEntitlement{*entitlement}, license, usage)[0]
+	if !recorded {
+		return &status, ErrLimitExceeded
+	}
+
+	return &status, nil
+}
+
+// GetUsage returns the current usage for all metered entitlements of the app
+func GetUsage(appID string) ([]types.EntitlementUsageStatus, error) {
+	entitlements, err := getMeteredEntitlements(appID)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get metered entitlements")
+	}
+
+	license, err := store.GetStore().GetLatestLicenseForApp(appID)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get license")
+	}
+
+	usage, err := store.GetStore().ListEntitlementUsage(appID)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list entitlement usage")
+	}
+
+	return Evaluate(entitlements, license, usage), nil
+}
+
+// Evaluate sums the usage of each entitlement across sources and compares it to the limit in the license
+func Evaluate(entitlements []kotsv1beta1.MeteredEntitlement, license *kotsv1beta1.License, usage []types.EntitlementUsage) []types.EntitlementUsageStatus {
+	statuses := []types.EntitlementUsageStatus{}
+	for _, entitlement := range entitlements {
+		status := types.EntitlementUsageStatus{
+			Name:        entitlement.Name,
+			Title:       entitlement.Title,
+			Enforcement: types.Enforcement(entitlement.Enforcement),
+		}
+		if status.Enforcement != types.EnforcementBlock {
+			status.Enforcement = types.EnforcementWarn
+		}
+
+		for _, u := range usage {
+			if u.Name != entitlement.Name {
+				continue
+			}
+			status.Value += u.Value
+			if status.LastReportedAt == nil || u.ReportedAt.After(*status.LastReportedAt) {
+				reportedAt := u.ReportedAt
+				status.LastReportedAt = &reportedAt
+			}
+		}
+
+		status.Limit = getLimit(entitlement, license)
+		if status.Limit != nil && status.Value > *status.Limit {
+			status.IsOverLimit = true
+		}
+
+		statuses = append(statuses, status)
+	}
+
+	return statuses
+}
+
+func getLimit(entitlement kotsv1beta1.MeteredEntitlement, license *kotsv1beta1.License) *int64 {
+	if license == nil {
+		return nil
+	}
+
+	licenseField := entitlement.LicenseField
+	if licenseField == "" {
+		licenseField = entitlement.Name
+	}
+
+	field, ok := license.Spec.Entitlements[licenseField]
+	if !ok || field.Value.Type != kotsv1beta1.Int {
+		return nil
+	}
+
+	limit := field.Value.IntVal
+	return &limit
+}
+
+func getMeteredEntitlements(appID string) ([]kotsv1beta1.MeteredEntitlement, error) {
+	a, err := store.GetStore().GetApp(appID)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get app")
+	}
+
+	appVersion, err := store.GetStore().GetAppVersion(appID, a.CurrentSequence)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get app version")
+	}
+
+	return appVersion.KOTSKinds.KotsApplication.Spec.MeteredEntitlements, nil
+}
@@ -0,0 +1,95 @@
+package metering
+
+import (
+	"testing"
+	"time"
+
+	kotsv1beta1 "github.com/replicatedhq/kots/kotskinds/apis/kots/v1beta1"
+	"github.com/replicatedhq/kots/pkg/metering/types"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Evaluate(t *testing.T) {
+	license := &kotsv1beta1.License{
+		Spec: kotsv1beta1.LicenseSpec{
+			Entitlements: map[string]kotsv1beta1.EntitlementField{
+				"seat_limit": {
+					Value: kotsv1beta1.EntitlementValue{Type: kotsv1beta1.Int, IntVal: 10},
+				},
+				"nodes": {
+					Value: kotsv1beta1.EntitlementValue{Type: kotsv1beta1.String, StrVal: "unlimited"},
+				},
+			},
+		},
+	}
+
+	earlier := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	later := earlier.Add(time.Hour)
+
+	tests := []struct {
+		name         string
+		entitlements []kotsv1beta1.MeteredEntitlement
+		usage        []types.EntitlementUsage
+		want         []types.EntitlementUsageStatus
+	}{
+		{
+			name: "sums sources under the limit",
+			entitlements: []kotsv1beta1.MeteredEntitlement{
+				{Name: "seats", LicenseField: "seat_limit", Enforcement: "block"},
+			},
+			usage: []types.EntitlementUsage{
+				{Name: "seats", Source: "a", Value: 4, ReportedAt: earlier},
+				{Name: "seats", Source: "b", Value: 5, ReportedAt: later},
+				{Name: "nodes", Source: "a", Value: 3, ReportedAt: later},
+			},
+			want: []types.EntitlementUsageStatus{
+				{Name: "seats", Value: 9, Limit: int64Ptr(10), Enforcement: types.EnforcementBlock, LastReportedAt: &later},
+			},
+		},
+		{
+			name: "over the limit defaults to warn",
+			entitlements: []kotsv1beta1.MeteredEntitlement{
+				{Name: "seats", LicenseField: "seat_limit"},
+			},
+			usage: []types.EntitlementUsage{
+				{Name: "seats", Source: "a", Value: 11, ReportedAt: earlier},
+			},
+			want: []types.EntitlementUsageStatus{
+				{Name: "seats", Value: 11, Limit: int64Ptr(10), Enforcement: types.EnforcementWarn, IsOverLimit: true, LastReportedAt: &earlier},
+			},
+		},
+		{
+			name: "non integer license field has no limit",
+			entitlements: []kotsv1beta1.MeteredEntitlement{
+				{Name: "nodes"},
+			},
+			usage: []types.EntitlementUsage{
+				{Name: "nodes", Source: "a", Value: 100, ReportedAt: earlier},
+			},
+			want: []types.EntitlementUsageStatus{
+				{Name: "nodes", Value: 100, Enforcement: types.EnforcementWarn, LastReportedAt: &earlier},
+			},
+		},
+		{
+			name: "no usage reported",
+			entitlements: []kotsv1beta1.MeteredEntitlement{
+				{Name: "seats", LicenseField: "seat_limit"},
+			},
+			want: []types.EntitlementUsageStatus{
+				{Name: "seats", Value: 0, Limit: int64Ptr(10), Enforcement: types.EnforcementWarn},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			req := require.New(t)
+			got := Evaluate(test.entitlements, license, test.usage)
+			req.Equal(test.want, got)
+		})
+	}
+}
+
+func int64Ptr(i int64) *int64 {
+	return &i
+}
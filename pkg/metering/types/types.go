@@ -0,0 +1,32 @@
+package types
+
+import (
+	"time"
+)
+
+type Enforcement string
+
+const (
+	EnforcementWarn  Enforcement = "warn"
+	EnforcementBlock Enforcement = "block"
+)
+
+// EntitlementUsage is the last value reported by a single source for an entitlement
+type EntitlementUsage struct {
+	Name       string    `json:"name"`
+	Source     string    `json:"source"`
+	Value      int64     `json:"value"`
+	ReportedAt time.Time `json:"reportedAt"`
+}
+
+// EntitlementUsageStatus is the usage for an entitlement summed across all sources
+// and evaluated against the limit in the license
+type EntitlementUsageStatus struct {
+	Name           string      `json:"name"`
+	Title          string      `json:"title,omitempty"`
+	Value          int64       `json:"value"`
+	Limit          *int64      `json:"limit,omitempty"`
+	Enforcement    Enforcement `json:"enforcement"`
+	IsOverLimit    bool        `json:"isOverLimit"`
+	LastReportedAt *time.Time  `json:"lastReportedAt,omitempty"`
+}
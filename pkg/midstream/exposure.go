@@ -0,0 +1,104 @@
+package midstream
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/replicatedhq/kots/pkg/exposure"
+	"github.com/replicatedhq/kots/pkg/k8sutil"
+	kustomizetypes "sigs.k8s.io/kustomize/api/types"
+)
+
+const (
+	exposureBase          = "exposure"
+	exposurePatchesPrefix = "exposure-"
+)
+
+// writeExposure writes the resources that expose the services of the application to their own base, and the patches
+// of the services to the midstream. It returns the base and the patch filenames.
+func (m *Midstream) writeExposure(options WriteOptions) (string, []string, error) {
+	if err := removeExposurePatches(options.MidstreamDir); err != nil {
+		return "", nil, errors.Wrap(err, "failed to remove previous patches")
+	}
+
+	absDir := filepath.Join(options.MidstreamDir, exposureBase)
+	if err := os.RemoveAll(absDir); err != nil {
+		return "", nil, errors.Wrap(err, "failed to remove previous resources")
+	}
+
+	if m.Exposure == nil {
+		return "", nil, nil
+	}
+
+	additionalLabels := map[string]string{
+		"kots.io/app": options.AppSlug,
+	}
+
+	resources, err := exposure.Render(*m.Exposure, options.Builder, additionalLabels)
+	if err != nil {
+		return "", nil, errors.Wrap(err, "failed to render exposure")
+	}
+
+	patchFilenames := []string{}
+	for filename, patch := range resources.Patches {
+		if err := ioutil.WriteFile(filepath.Join(options.MidstreamDir, filename), patch, 0644); err != nil {
+			return "", nil, errors.Wrapf(err, "failed to write patch %s", filename)
+		}
+		patchFilenames = append(patchFilenames, filename)
+	}
+	sort.Strings(patchFilenames)
+
+	if len(resources.Resources) == 0 {
+		return "", patchFilenames, nil
+	}
+
+	if err := os.MkdirAll(absDir, 0744); err != nil {
+		return "", nil, errors.Wrap(err, "failed to mkdir")
+	}
+
+	kustomization := kustomizetypes.Kustomization{
+		TypeMeta: kustomizetypes.TypeMeta{
+			APIVersion: "kustomize.config.k8s.io/v1beta1",
+			Kind:       "Kustomization",
+		},
+	}
+
+	for filename, resource := range resources.Resources {
+		if err := ioutil.WriteFile(filepath.Join(absDir, filename), resource, 0644); err != nil {
+			return "", nil, errors.Wrapf(err, "failed to write resource %s", filename)
+		}
+		kustomization.Resources = append(kustomization.Resources, filename)
+	}
+	sort.Strings(kustomization.Resources)
+
+	if err := k8sutil.WriteKustomizationToFile(kustomization, filepath.Join(absDir, "kustomization.yaml")); err != nil {
+		return "", nil, errors.Wrap(err, "failed to write kustomization file")
+	}
+
+	return exposureBase, patchFilenames, nil
+}
+
+// removeExposurePatches removes the patches of a previous exposure, the customer might have chosen another method
+func removeExposurePatches(midstreamDir string) error {
+	files, err := ioutil.ReadDir(midstreamDir)
+	if err != nil {
+		return errors.Wrap(err, "failed to read midstream dir")
+	}
+	for _, file := range files {
+		if file.IsDir() || !isExposurePatch(file.Name()) {
+			continue
+		}
+		if err := os.Remove(filepath.Join(midstreamDir, file.Name())); err != nil {
+			return errors.Wrapf(err, "failed to remove %s", file.Name())
+		}
+	}
+	return nil
+}
+
+func isExposurePatch(filename string) bool {
+	return strings.HasPrefix(filename, exposurePatchesPrefix) && strings.HasSuffix(filename, ".yaml")
+}
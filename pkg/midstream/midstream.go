@@ -15,9 +15,10 @@ type Midstream struct {
 	PullSecret     *corev1.Secret
 	IdentitySpec   *kotsv1beta1.Identity
 	IdentityConfig *kotsv1beta1.IdentityConfig
+	Exposure       *kotsv1beta1.Exposure
 }
 
-func CreateMidstream(b *base.Base, images []kustomizetypes.Image, objects []k8sdoc.K8sDoc, pullSecret *corev1.Secret, identitySpec *kotsv1beta1.Identity, identityConfig *kotsv1beta1.IdentityConfig) (*Midstream, error) {
+func CreateMidstream(b *base.Base, images []kustomizetypes.Image, objects []k8sdoc.K8sDoc, pullSecret *corev1.Secret, identitySpec *kotsv1beta1.Identity, identityConfig *kotsv1beta1.IdentityConfig, exposure *kotsv1beta1.Exposure) (*Midstream, error) {
 	kustomization := kustomizetypes.Kustomization{
 		TypeMeta: kustomizetypes.TypeMeta{
 			APIVersion: "kustomize.config.k8s.io/v1beta1",
@@ -37,6 +38,7 @@ func CreateMidstream(b *base.Base, images []kustomizetypes.Image, objects []k8sd
 		PullSecret:     pullSecret,
 		IdentitySpec:   identitySpec,
 		IdentityConfig: identityConfig,
+		Exposure:       exposure,
 	}
 
 	return &m, nil
@@ -21,6 +21,7 @@ const (
 	secretFilename                           = "secret.yaml"
 	patchesFilename                          = "pullsecrets.yaml"
 	disasterRecoveryLabelTransformerFileName = "backup-label-transformer.yaml"
+	ownershipLabelTransformerFileName        = "ownership-label-transformer.yaml"
 )
 
 type WriteOptions struct {
@@ -74,6 +75,18 @@ func (m *Midstream) WriteMidstream(options WriteOptions) error {
 		m.Kustomization.Resources = append(m.Kustomization.Resources, identityBase)
 	}
 
+	exposureDir, exposurePatches, err := m.writeExposure(options)
+	if err != nil {
+		return errors.Wrap(err, "failed to write exposure")
+	}
+
+	if exposureDir != "" {
+		m.Kustomization.Resources = append(m.Kustomization.Resources, exposureDir)
+	}
+	for _, exposurePatch := range exposurePatches {
+		m.Kustomization.PatchesStrategicMerge = append(m.Kustomization.PatchesStrategicMerge, kustomizetypes.PatchStrategicMerge(exposurePatch))
+	}
+
 	if err := m.writeObjectsWithPullSecret(options); err != nil {
 		return errors.Wrap(err, "failed to write patches")
 	}
@@ -108,6 +121,8 @@ func (m *Midstream) mergeKustomization(options WriteOptions, existing *kustomize
 	}
 
 	existing.PatchesStrategicMerge = removeFromPatches(existing.PatchesStrategicMerge, patchesFilename)
+	existing.PatchesStrategicMerge = removeExposureFromPatches(existing.PatchesStrategicMerge)
+	existing.Resources = removeFromStrings(existing.Resources, exposureBase)
 	newPatches := findNewPatches(m.Kustomization.PatchesStrategicMerge, existing.PatchesStrategicMerge)
 	m.Kustomization.PatchesStrategicMerge = append(existing.PatchesStrategicMerge, newPatches...)
 
@@ -270,6 +285,75 @@ func EnsureDisasterRecoveryLabelTransformer(archiveDir string, additionalLabels
 	return nil
 }
 
+// EnsureOwnershipLabelTransformer writes a transformer that sets the ownership labels on the metadata of every resource
+// in the midstream. Unlike the disaster recovery labels, they are not set on pod templates so that changing the
+// sequence does not restart pods.
+func EnsureOwnershipLabelTransformer(archiveDir string, labels map[string]string) error {
+	kustomizationFilename := filepath.Join(archiveDir, "overlays", "midstream", "kustomization.yaml")
+	k, err := k8sutil.ReadKustomizationFromFile(kustomizationFilename)
+	if err != nil {
+		return errors.Wrap(err, "failed to read kustomization file from midstream")
+	}
+
+	labelTransformer := disasterrecovery.LabelTransformer{
+		APIVersion: "builtin",
+		Kind:       "LabelTransformer",
+		Metadata: disasterrecovery.OverlySimpleMetadata{
+			Name: "ownership-label-transformer",
+		},
+		Labels: labels,
+		FieldSpecs: []kustomizetypes.FieldSpec{
+			{
+				Path:               "metadata/labels",
+				CreateIfNotPresent: true,
+			},
+		},
+	}
+	b, err := yaml.Marshal(labelTransformer)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal ownership label transformer")
+	}
+
+	// the labels change with every deploy, so the transformer is always written
+	absFilename := filepath.Join(archiveDir, "overlays", "midstream", ownershipLabelTransformerFileName)
+	if err := ioutil.WriteFile(absFilename, b, 0644); err != nil {
+		return errors.Wrap(err, "failed to write ownership label transformer yaml file")
+	}
+
+	for _, transformer := range k.Transformers {
+		if transformer == ownershipLabelTransformerFileName {
+			return nil
+		}
+	}
+
+	k.Transformers = append(k.Transformers, ownershipLabelTransformerFileName)
+	if err := k8sutil.WriteKustomizationToFile(*k, kustomizationFilename); err != nil {
+		return errors.Wrap(err, "failed to write kustomization file to midstream")
+	}
+
+	return nil
+}
+
+func removeExposureFromPatches(patches []kustomizetypes.PatchStrategicMerge) []kustomizetypes.PatchStrategicMerge {
+	newPatches := []kustomizetypes.PatchStrategicMerge{}
+	for _, patch := range patches {
+		if !isExposurePatch(string(patch)) {
+			newPatches = append(newPatches, patch)
+		}
+	}
+	return newPatches
+}
+
+func removeFromStrings(values []string, value string) []string {
+	newValues := []string{}
+	for _, v := range values {
+		if v != value {
+			newValues = append(newValues, v)
+		}
+	}
+	return newValues
+}
+
 func removeFromPatches(patches []kustomizetypes.PatchStrategicMerge, filename string) []kustomizetypes.PatchStrategicMerge {
 	newPatches := []kustomizetypes.PatchStrategicMerge{}
 	for _, patch := range patches {
@@ -0,0 +1,187 @@
+package objectstore
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	storagemgmt "github.com/Azure/azure-sdk-for-go/services/storage/mgmt/2018-02-01/storage"
+	"github.com/Azure/azure-sdk-for-go/storage"
+	"github.com/Azure/go-autorest/autorest"
+	"github.com/Azure/go-autorest/autorest/adal"
+	"github.com/Azure/go-autorest/autorest/azure"
+	"github.com/pkg/errors"
+)
+
+type azureDriver struct {
+	container *storage.Container
+}
+
+func newAzureDriver(account string, container string) (*azureDriver, error) {
+	env, err := getAzureEnvironment()
+	if err != nil {
+		return nil, err
+	}
+
+	return newAzureDriverWithKey(env, account, container, os.Getenv("AZURE_STORAGE_ACCOUNT_KEY"))
+}
+
+// newAzureManagedIdentityDriver lists the keys of the storage account with the managed identity of the pod, for
+// example assigned by aad pod identity, so that no account key has to be stored in the cluster.
+func newAzureManagedIdentityDriver(account string, container string, resourceGroup string, subscriptionID string) (*azureDriver, error) {
+	env, err := getAzureEnvironment()
+	if err != nil {
+		return nil, err
+	}
+
+	msiEndpoint, err := adal.GetMSIEndpoint()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get msi endpoint")
+	}
+	token, err := adal.NewServicePrincipalTokenFromMSI(msiEndpoint, env.ResourceManagerEndpoint)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get managed identity token")
+	}
+
+	accountsClient := storagemgmt.NewAccountsClientWithBaseURI(env.ResourceManagerEndpoint, subscriptionID)
+	accountsClient.Authorizer = autorest.NewBearerAuthorizer(token)
+
+	res, err := accountsClient.ListKeys(context.Background(), resourceGroup, account)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list account keys")
+	}
+
+	key := ""
+	if res.Keys != nil {
+		for _, k := range *res.Keys {
+			// the permissions are returned as e.g. "FULL" but the constant is "Full"
+			if strings.EqualFold(string(k.Permissions), string(storagemgmt.Full)) && k.Value != nil {
+				key = *k.Value
+				break
+			}
+		}
+	}
+	if key == "" {
+		return nil, errors.Errorf("no key with full permissions found for storage account %q", account)
+	}
+
+	return newAzureDriverWithKey(env, account, container, key)
+}
+
+func newAzureDriverWithKey(env azure.Environment, account string, container string, key string) (*azureDriver, error) {
+	client, err := storage.NewBasicClientOnSovereignCloud(account, key, env)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create storage client")
+	}
+
+	blobService := client.GetBlobService()
+	return &azureDriver{
+		container: blobService.GetContainerReference(container),
+	}, nil
+}
+
+func getAzureEnvironment() (azure.Environment, error) {
+	cloudName := os.Getenv("AZURE_CLOUD_NAME")
+	if cloudName == "" {
+		cloudName = azure.PublicCloud.Name
+	}
+	env, err := azure.EnvironmentFromName(cloudName)
+	if err != nil {
+		return azure.Environment{}, errors.Wrap(err, "failed to find azure env")
+	}
+	return env, nil
+}
+
+// the storage client does not accept a context, so the context is only checked between requests
+
+func (d *azureDriver) Ping(ctx context.Context) error {
+	if _, err := d.container.Exists(); err != nil {
+		return errors.Wrap(err, "failed to check container existence")
+	}
+	return nil
+}
+
+func (d *azureDriver) EnsureBucket(ctx context.Context) error {
+	_, err := d.container.CreateIfNotExists(&storage.CreateContainerOptions{
+		Access: storage.ContainerAccessTypePrivate,
+	})
+	if err != nil {
+		return errors.Wrap(err, "failed to create container")
+	}
+	return nil
+}
+
+func (d *azureDriver) PutObject(ctx context.Context, key string, body io.Reader) error {
+	if err := d.container.GetBlobReference(key).CreateBlockBlobFromReader(body, nil); err != nil {
+		return errors.Wrapf(err, "failed to upload %q to container %q", key, d.container.Name)
+	}
+	return nil
+}
+
+func (d *azureDriver) GetObject(ctx context.Context, key string) (io.ReadCloser, error) {
+	r, err := d.container.GetBlobReference(key).Get(nil)
+	if isAzureNotFound(err) {
+		return nil, errors.Wrapf(ErrNotFound, "%q in container %q", key, d.container.Name)
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get %q from container %q", key, d.container.Name)
+	}
+	return r, nil
+}
+
+func (d *azureDriver) ListObjects(ctx context.Context, prefix string, fn func(Object) error) error {
+	marker := ""
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		response, err := d.container.ListBlobs(storage.ListBlobsParameters{
+			Prefix: prefix,
+			Marker: marker,
+		})
+		if err != nil {
+			return errors.Wrapf(err, "failed to list blobs in container %q", d.container.Name)
+		}
+
+		for _, blob := range response.Blobs {
+			if err := fn(Object{
+				Key:          blob.Name,
+				Size:         blob.Properties.ContentLength,
+				LastModified: time.Time(blob.Properties.LastModified),
+			}); err != nil {
+				return err
+			}
+		}
+
+		if response.NextMarker == "" {
+			return nil
+		}
+		marker = response.NextMarker
+	}
+}
+
+func (d *azureDriver) DeleteObjects(ctx context.Context, keys []string) error {
+	for _, key := range keys {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if _, err := d.container.GetBlobReference(key).DeleteIfExists(nil); err != nil {
+			return errors.Wrapf(err, "failed to delete %q in container %q", key, d.container.Name)
+		}
+	}
+	return nil
+}
+
+func isAzureNotFound(err error) bool {
+	switch err := err.(type) {
+	case storage.AzureStorageServiceError:
+		return err.StatusCode == http.StatusNotFound
+	case *storage.AzureStorageServiceError:
+		return err.StatusCode == http.StatusNotFound
+	}
+	return false
+}
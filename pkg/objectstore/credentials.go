@@ -0,0 +1,76 @@
+package objectstore
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	kotsadmtypes "github.com/replicatedhq/kots/pkg/kotsadm/types"
+	corev1 "k8s.io/api/core/v1"
+	kuberneteserrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	// SecretName is the secret in the kotsadm namespace that holds the credentials of the gcs and azure drivers
+	SecretName = "kotsadm-objectstore"
+
+	GCSServiceAccountKey      = "gcsServiceAccount"
+	AzureStorageAccountKeyKey = "azureStorageAccountKey"
+)
+
+// Credentials authenticate the gcs and azure drivers
+type Credentials struct {
+	GCSServiceAccount      string
+	AzureStorageAccountKey string
+}
+
+// SetCredentials stores the credentials in the namespace. Credentials that are empty are not changed, so the
+// credentials of the driver that is being migrated from are kept.
+func SetCredentials(clientset kubernetes.Interface, namespace string, credentials Credentials) error {
+	data := map[string][]byte{}
+	if credentials.GCSServiceAccount != "" {
+		data[GCSServiceAccountKey] = []byte(credentials.GCSServiceAccount)
+	}
+	if credentials.AzureStorageAccountKey != "" {
+		data[AzureStorageAccountKeyKey] = []byte(credentials.AzureStorageAccountKey)
+	}
+	if len(data) == 0 {
+		return nil
+	}
+
+	existing, err := clientset.CoreV1().Secrets(namespace).Get(context.TODO(), SecretName, metav1.GetOptions{})
+	if err != nil {
+		if !kuberneteserrors.IsNotFound(err) {
+			return errors.Wrap(err, "failed to get secret")
+		}
+
+		secret := &corev1.Secret{
+			TypeMeta: metav1.TypeMeta{
+				APIVersion: "v1",
+				Kind:       "Secret",
+			},
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      SecretName,
+				Namespace: namespace,
+				Labels:    kotsadmtypes.GetKotsadmLabels(),
+			},
+			Data: data,
+		}
+		if _, err := clientset.CoreV1().Secrets(namespace).Create(context.TODO(), secret, metav1.CreateOptions{}); err != nil {
+			return errors.Wrap(err, "failed to create secret")
+		}
+		return nil
+	}
+
+	if existing.Data == nil {
+		existing.Data = map[string][]byte{}
+	}
+	for key, value := range data {
+		existing.Data[key] = value
+	}
+	if _, err := clientset.CoreV1().Secrets(namespace).Update(context.TODO(), existing, metav1.UpdateOptions{}); err != nil {
+		return errors.Wrap(err, "failed to update secret")
+	}
+	return nil
+}
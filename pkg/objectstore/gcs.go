@@ -0,0 +1,108 @@
+package objectstore
+
+import (
+	"context"
+	"io"
+	"os"
+
+	gcpstorage "cloud.google.com/go/storage"
+	"github.com/pkg/errors"
+	"google.golang.org/api/iterator"
+	"google.golang.org/api/option"
+)
+
+type gcsDriver struct {
+	client *gcpstorage.Client
+	bucket string
+}
+
+func newGCSDriver(bucket string) (*gcsDriver, error) {
+	options := []option.ClientOption{}
+	if serviceAccount := os.Getenv("GCS_SERVICE_ACCOUNT"); serviceAccount != "" {
+		options = append(options, option.WithCredentialsJSON([]byte(serviceAccount)))
+	}
+
+	client, err := gcpstorage.NewClient(context.Background(), options...)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create storage client")
+	}
+
+	return &gcsDriver{
+		client: client,
+		bucket: bucket,
+	}, nil
+}
+
+func (d *gcsDriver) Ping(ctx context.Context) error {
+	_, err := d.client.Bucket(d.bucket).Attrs(ctx)
+	if err == nil || err == gcpstorage.ErrBucketNotExist {
+		return nil
+	}
+	return errors.Wrap(err, "failed to get bucket attributes")
+}
+
+// EnsureBucket returns an error if the bucket does not exist. Creating a bucket requires a project, so gcs
+// buckets must be created before the admin console is installed.
+func (d *gcsDriver) EnsureBucket(ctx context.Context) error {
+	_, err := d.client.Bucket(d.bucket).Attrs(ctx)
+	if err == gcpstorage.ErrBucketNotExist {
+		return errors.Errorf("bucket %q does not exist", d.bucket)
+	}
+	if err != nil {
+		return errors.Wrap(err, "failed to get bucket attributes")
+	}
+	return nil
+}
+
+func (d *gcsDriver) PutObject(ctx context.Context, key string, body io.Reader) error {
+	w := d.client.Bucket(d.bucket).Object(key).NewWriter(ctx)
+	if _, err := io.Copy(w, body); err != nil {
+		w.Close()
+		return errors.Wrapf(err, "failed to upload %q to bucket %q", key, d.bucket)
+	}
+	if err := w.Close(); err != nil {
+		return errors.Wrapf(err, "failed to upload %q to bucket %q", key, d.bucket)
+	}
+	return nil
+}
+
+func (d *gcsDriver) GetObject(ctx context.Context, key string) (io.ReadCloser, error) {
+	r, err := d.client.Bucket(d.bucket).Object(key).NewReader(ctx)
+	if err == gcpstorage.ErrObjectNotExist {
+		return nil, errors.Wrapf(ErrNotFound, "%q in bucket %q", key, d.bucket)
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get %q from bucket %q", key, d.bucket)
+	}
+	return r, nil
+}
+
+func (d *gcsDriver) ListObjects(ctx context.Context, prefix string, fn func(Object) error) error {
+	it := d.client.Bucket(d.bucket).Objects(ctx, &gcpstorage.Query{Prefix: prefix})
+	for {
+		attrs, err := it.Next()
+		if err == iterator.Done {
+			return nil
+		}
+		if err != nil {
+			return errors.Wrapf(err, "failed to list objects in bucket %q", d.bucket)
+		}
+		if err := fn(Object{
+			Key:          attrs.Name,
+			Size:         attrs.Size,
+			LastModified: attrs.Updated,
+		}); err != nil {
+			return err
+		}
+	}
+}
+
+func (d *gcsDriver) DeleteObjects(ctx context.Context, keys []string) error {
+	for _, key := range keys {
+		err := d.client.Bucket(d.bucket).Object(key).Delete(ctx)
+		if err != nil && err != gcpstorage.ErrObjectNotExist {
+			return errors.Wrapf(err, "failed to delete %q in bucket %q", key, d.bucket)
+		}
+	}
+	return nil
+}
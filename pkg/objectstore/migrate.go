@@ -0,0 +1,80 @@
+package objectstore
+
+import (
+	"context"
+	"os"
+
+	"github.com/pkg/errors"
+	"github.com/replicatedhq/kots/pkg/logger"
+	"go.uber.org/zap"
+)
+
+// Migrate copies the objects in src to dst. Objects that are already in dst with the same size are skipped,
+// so a migration that was interrupted can be run again. It returns the number of objects copied.
+func Migrate(ctx context.Context, src Driver, dst Driver) (int64, error) {
+	existing := map[string]int64{}
+	err := dst.ListObjects(ctx, "", func(object Object) error {
+		existing[object.Key] = object.Size
+		return nil
+	})
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to list destination objects")
+	}
+
+	copied := int64(0)
+	err = src.ListObjects(ctx, "", func(object Object) error {
+		if size, ok := existing[object.Key]; ok && size == object.Size {
+			return nil
+		}
+
+		r, err := src.GetObject(ctx, object.Key)
+		if err != nil {
+			return errors.Wrap(err, "failed to get object")
+		}
+		defer r.Close()
+
+		if err := dst.PutObject(ctx, object.Key, r); err != nil {
+			return errors.Wrap(err, "failed to put object")
+		}
+
+		copied++
+		return nil
+	})
+	if err != nil {
+		return copied, errors.Wrap(err, "failed to copy objects")
+	}
+
+	return copied, nil
+}
+
+// MigrateFromEnv copies the objects from the object store in the STORAGE_MIGRATE_FROM_BASEURI env var to the
+// object store in the STORAGE_BASEURI env var. It does nothing if STORAGE_MIGRATE_FROM_BASEURI is not set.
+func MigrateFromEnv(ctx context.Context) error {
+	from := os.Getenv("STORAGE_MIGRATE_FROM_BASEURI")
+	if from == "" || from == os.Getenv("STORAGE_BASEURI") {
+		return nil
+	}
+
+	src, err := DriverForURI(from)
+	if err != nil {
+		return errors.Wrap(err, "failed to get source driver")
+	}
+	dst, err := GetDriver()
+	if err != nil {
+		return errors.Wrap(err, "failed to get destination driver")
+	}
+
+	logger.Info("migrating object store",
+		zap.String("from", from),
+		zap.String("to", os.Getenv("STORAGE_BASEURI")))
+
+	copied, err := Migrate(ctx, src, dst)
+	if err != nil {
+		return err
+	}
+
+	logger.Info("migrated object store",
+		zap.Int64("copied", copied))
+
+	return nil
+}
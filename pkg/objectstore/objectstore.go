@@ -0,0 +1,193 @@
+package objectstore
+
+import (
+	"context"
+	"io"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	SchemeS3    = "s3"
+	SchemeGCS   = "gs"
+	SchemeAzure = "azblob"
+)
+
+var (
+	// ErrNotFound is the cause of errors returned when an object does not exist
+	ErrNotFound = errors.New("object not found")
+
+	driversMtx sync.Mutex
+	drivers    = map[string]Driver{}
+)
+
+// Object is an object in the bucket of a driver
+type Object struct {
+	Key          string
+	Size         int64
+	LastModified time.Time
+}
+
+// Driver stores the app archives, support bundles and other files of the admin console in a bucket of an object store
+type Driver interface {
+	// Ping returns nil once the object store can be reached, even if the bucket does not exist yet
+	Ping(ctx context.Context) error
+	// EnsureBucket creates the bucket if it does not exist
+	EnsureBucket(ctx context.Context) error
+	PutObject(ctx context.Context, key string, body io.Reader) error
+	// GetObject returns the contents of the object. The caller must close the reader.
+	GetObject(ctx context.Context, key string) (io.ReadCloser, error)
+	// ListObjects calls fn for each object with a key that starts with the prefix
+	ListObjects(ctx context.Context, prefix string, fn func(Object) error) error
+	DeleteObjects(ctx context.Context, keys []string) error
+}
+
+// IsOCI returns true if the storage base uri is an oci registry rather than an object store
+func IsOCI(storageBaseURI string) bool {
+	return strings.HasPrefix(storageBaseURI, "docker://")
+}
+
+// IsExternal returns true if the storage base uri is an s3 bucket, a gcs bucket or an azure blob container,
+// which are not deployed with the admin console
+func IsExternal(storageBaseURI string) bool {
+	scheme, parts, err := parseURI(storageBaseURI)
+	if err != nil {
+		return false
+	}
+	return scheme != SchemeS3 || len(parts) > 0
+}
+
+// ValidateURI returns an error if the storage base uri is not an oci registry or an object store with a driver
+func ValidateURI(storageBaseURI string) error {
+	if storageBaseURI == "" || IsOCI(storageBaseURI) {
+		return nil
+	}
+	_, _, err := parseURI(storageBaseURI)
+	return err
+}
+
+// GetDriver returns the driver for the object store in the STORAGE_BASEURI env var. The s3 driver is used when
+// it is not set.
+func GetDriver() (Driver, error) {
+	return DriverForURI(os.Getenv("STORAGE_BASEURI"))
+}
+
+// DriverForURI returns the driver for the object store in the storage base uri:
+//
+//	s3://<endpoint>/<bucket> uses the S3_* env vars for the endpoint, bucket and credentials
+//	s3://<bucket>?region=<region> uses the default credentials, such as iam roles for service accounts
+//	gs://<bucket> uses the service account in the GCS_SERVICE_ACCOUNT env var, or the default credentials
+//	azblob://<account>/<container> uses the account key in the AZURE_STORAGE_ACCOUNT_KEY env var, or the managed
+//	identity of the pod when the uri has resourceGroup and subscriptionId query params
+func DriverForURI(storageBaseURI string) (Driver, error) {
+	if IsOCI(storageBaseURI) {
+		return nil, errors.Errorf("%s is an oci registry", storageBaseURI)
+	}
+
+	driversMtx.Lock()
+	defer driversMtx.Unlock()
+
+	if driver, ok := drivers[storageBaseURI]; ok {
+		return driver, nil
+	}
+
+	scheme, parts, err := parseURI(storageBaseURI)
+	if err != nil {
+		return nil, err
+	}
+
+	var driver Driver
+	switch scheme {
+	case SchemeS3:
+		if len(parts) > 0 {
+			driver = newS3BucketDriver(parts[0], parts[1])
+		} else {
+			driver = newS3Driver()
+		}
+	case SchemeGCS:
+		driver, err = newGCSDriver(parts[0])
+	case SchemeAzure:
+		if len(parts) > 2 {
+			driver, err = newAzureManagedIdentityDriver(parts[0], parts[1], parts[2], parts[3])
+		} else {
+			driver, err = newAzureDriver(parts[0], parts[1])
+		}
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to create %s driver", scheme)
+	}
+
+	drivers[storageBaseURI] = driver
+	return driver, nil
+}
+
+// Upload puts the contents of the file in the object
+func Upload(ctx context.Context, driver Driver, key string, filePath string) error {
+	f, err := os.Open(filePath)
+	if err != nil {
+		return errors.Wrap(err, "failed to open file")
+	}
+	defer f.Close()
+
+	return driver.PutObject(ctx, key, f)
+}
+
+// Download writes the contents of the object to w
+func Download(ctx context.Context, driver Driver, key string, w io.Writer) error {
+	r, err := driver.GetObject(ctx, key)
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+
+	if _, err := io.Copy(w, r); err != nil {
+		return errors.Wrapf(err, "failed to read object %q", key)
+	}
+	return nil
+}
+
+// parseURI returns the scheme of the storage base uri and the bucket and region for s3 buckets, the bucket for gcs,
+// or the account and container for azure, followed by the resource group and subscription of the managed identity
+func parseURI(storageBaseURI string) (string, []string, error) {
+	if storageBaseURI == "" {
+		return SchemeS3, nil, nil
+	}
+
+	u, err := url.Parse(storageBaseURI)
+	if err != nil {
+		return "", nil, errors.Wrapf(err, "failed to parse storage base uri %q", storageBaseURI)
+	}
+
+	path := strings.Trim(u.Path, "/")
+	switch u.Scheme {
+	case SchemeS3:
+		if u.Host != "" && u.Port() == "" && path == "" {
+			return SchemeS3, []string{u.Host, u.Query().Get("region")}, nil
+		}
+		return SchemeS3, nil, nil
+	case SchemeGCS:
+		if u.Host == "" || path != "" {
+			return "", nil, errors.Errorf("storage base uri %q must be in the form gs://<bucket>", storageBaseURI)
+		}
+		return SchemeGCS, []string{u.Host}, nil
+	case SchemeAzure:
+		if u.Host == "" || path == "" || strings.Contains(path, "/") {
+			return "", nil, errors.Errorf("storage base uri %q must be in the form azblob://<account>/<container>", storageBaseURI)
+		}
+		resourceGroup, subscriptionID := u.Query().Get("resourceGroup"), u.Query().Get("subscriptionId")
+		if resourceGroup == "" && subscriptionID == "" {
+			return SchemeAzure, []string{u.Host, path}, nil
+		}
+		if resourceGroup == "" || subscriptionID == "" {
+			return "", nil, errors.Errorf("storage base uri %q must have both the resourceGroup and subscriptionId query params", storageBaseURI)
+		}
+		return SchemeAzure, []string{u.Host, path, resourceGroup, subscriptionID}, nil
+	}
+
+	return "", nil, errors.Errorf("unsupported storage base uri %q, must be s3://, gs://, azblob:// or docker://", storageBaseURI)
+}
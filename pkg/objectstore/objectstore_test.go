@@ -0,0 +1,123 @@
+package objectstore
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+)
+
+type memDriver struct {
+	objects map[string][]byte
+	puts    int
+}
+
+func (d *memDriver) Ping(ctx context.Context) error         { return nil }
+func (d *memDriver) EnsureBucket(ctx context.Context) error { return nil }
+
+func (d *memDriver) PutObject(ctx context.Context, key string, body io.Reader) error {
+	b, err := ioutil.ReadAll(body)
+	if err != nil {
+		return err
+	}
+	d.objects[key] = b
+	d.puts++
+	return nil
+}
+
+func (d *memDriver) GetObject(ctx context.Context, key string) (io.ReadCloser, error) {
+	b, ok := d.objects[key]
+	if !ok {
+		return nil, errors.Wrap(ErrNotFound, key)
+	}
+	return ioutil.NopCloser(bytes.NewReader(b)), nil
+}
+
+func (d *memDriver) ListObjects(ctx context.Context, prefix string, fn func(Object) error) error {
+	for key, b := range d.objects {
+		if !strings.HasPrefix(key, prefix) {
+			continue
+		}
+		if err := fn(Object{Key: key, Size: int64(len(b))}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (d *memDriver) DeleteObjects(ctx context.Context, keys []string) error {
+	for _, key := range keys {
+		delete(d.objects, key)
+	}
+	return nil
+}
+
+func Test_parseURI(t *testing.T) {
+	tests := []struct {
+		uri     string
+		scheme  string
+		parts   []string
+		wantErr bool
+	}{
+		{uri: "", scheme: SchemeS3},
+		{uri: "s3://kotsadm-minio:9000/kotsadm", scheme: SchemeS3},
+		{uri: "s3://kotsadm-minio:9000", scheme: SchemeS3},
+		{uri: "s3://my-bucket?region=us-west-2", scheme: SchemeS3, parts: []string{"my-bucket", "us-west-2"}},
+		{uri: "s3://my-bucket", scheme: SchemeS3, parts: []string{"my-bucket", ""}},
+		{uri: "gs://my-bucket", scheme: SchemeGCS, parts: []string{"my-bucket"}},
+		{uri: "gs://my-bucket/prefix", wantErr: true},
+		{uri: "gs://", wantErr: true},
+		{uri: "azblob://account/container", scheme: SchemeAzure, parts: []string{"account", "container"}},
+		{uri: "azblob://account/container?resourceGroup=rg&subscriptionId=sub", scheme: SchemeAzure, parts: []string{"account", "container", "rg", "sub"}},
+		{uri: "azblob://account/container?resourceGroup=rg", wantErr: true},
+		{uri: "azblob://account", wantErr: true},
+		{uri: "azblob://account/container/prefix", wantErr: true},
+		{uri: "ftp://host/path", wantErr: true},
+	}
+	for _, test := range tests {
+		t.Run(test.uri, func(t *testing.T) {
+			scheme, parts, err := parseURI(test.uri)
+			if test.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, test.scheme, scheme)
+			require.Equal(t, test.parts, parts)
+		})
+	}
+
+	require.NoError(t, ValidateURI("docker://kotsadm-storage-registry:5000"))
+	require.True(t, IsExternal("gs://my-bucket"))
+	require.True(t, IsExternal("s3://my-bucket?region=us-west-2"))
+	require.False(t, IsExternal("s3://kotsadm-minio:9000/kotsadm"))
+	require.False(t, IsExternal("docker://kotsadm-storage-registry:5000"))
+	require.False(t, IsExternal(""))
+}
+
+func Test_Migrate(t *testing.T) {
+	src := &memDriver{objects: map[string][]byte{
+		"app/1.tar.gz":               []byte("one"),
+		"app/2.tar.gz":               []byte("two"),
+		"supportbundles/id/index.gz": []byte("index"),
+	}}
+	dst := &memDriver{objects: map[string][]byte{
+		"app/1.tar.gz": []byte("one"),
+		"app/2.tar.gz": []byte("partial two"),
+	}}
+
+	copied, err := Migrate(context.Background(), src, dst)
+	require.NoError(t, err)
+	require.Equal(t, int64(2), copied)
+	require.Equal(t, src.objects, dst.objects)
+
+	copied, err = Migrate(context.Background(), src, dst)
+	require.NoError(t, err)
+	require.Equal(t, int64(0), copied)
+	require.Equal(t, 2, dst.puts)
+}
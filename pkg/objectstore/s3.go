@@ -0,0 +1,196 @@
+package objectstore
+
+import (
+	"context"
+	"io"
+	"log"
+	"os"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	awssession "github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/s3manager"
+	"github.com/pkg/errors"
+	kotss3 "github.com/replicatedhq/kots/pkg/s3"
+)
+
+type s3Driver struct {
+	session *awssession.Session
+	bucket  string
+	// external buckets are not created by the driver, the credentials may not allow it
+	external bool
+}
+
+func newS3Driver() *s3Driver {
+	return &s3Driver{
+		session: awssession.New(kotss3.GetConfig()),
+		bucket:  os.Getenv("S3_BUCKET_NAME"),
+	}
+}
+
+// newS3BucketDriver returns a driver for an aws bucket. The credentials are not set, so the default credential chain
+// is used, which includes the web identity token of iam roles for service accounts and the instance role of the node.
+func newS3BucketDriver(bucket string, region string) *s3Driver {
+	if region == "" {
+		region = "us-east-1"
+	}
+
+	return &s3Driver{
+		session: awssession.New(&aws.Config{
+			Region: aws.String(region),
+		}),
+		bucket:   bucket,
+		external: true,
+	}
+}
+
+func (d *s3Driver) Ping(ctx context.Context) error {
+	if err := d.checkBucketName(); err != nil {
+		return err
+	}
+	if os.Getenv("S3_SKIP_ENSURE_BUCKET") == "1" {
+		return nil
+	}
+
+	_, err := s3.New(d.session).HeadBucketWithContext(ctx, &s3.HeadBucketInput{
+		Bucket: aws.String(d.bucket),
+	})
+	if err == nil || isS3NotFound(err) {
+		return nil
+	}
+	return errors.Wrap(err, "failed to head bucket")
+}
+
+func (d *s3Driver) EnsureBucket(ctx context.Context) error {
+	if err := d.checkBucketName(); err != nil {
+		return err
+	}
+	if os.Getenv("S3_SKIP_ENSURE_BUCKET") == "1" {
+		log.Println("Not creating bucket because S3_SKIP_ENSURE_BUCKET was set.")
+		return nil
+	}
+
+	s3Client := s3.New(d.session)
+
+	_, err := s3Client.HeadBucketWithContext(ctx, &s3.HeadBucketInput{
+		Bucket: aws.String(d.bucket),
+	})
+	if err == nil {
+		return nil
+	}
+	if d.external {
+		if isS3NotFound(err) {
+			return errors.Errorf("bucket %q does not exist", d.bucket)
+		}
+		return errors.Wrap(err, "failed to head bucket")
+	}
+
+	_, err = s3Client.CreateBucketWithContext(ctx, &s3.CreateBucketInput{
+		Bucket: aws.String(d.bucket),
+	})
+	if err != nil {
+		return errors.Wrap(err, "failed to create bucket")
+	}
+
+	return nil
+}
+
+func (d *s3Driver) PutObject(ctx context.Context, key string, body io.Reader) error {
+	_, err := s3manager.NewUploader(d.session).UploadWithContext(ctx, &s3manager.UploadInput{
+		Body:   body,
+		Bucket: aws.String(d.bucket),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		return errors.Wrapf(err, "failed to upload %q to bucket %q", key, d.bucket)
+	}
+	return nil
+}
+
+func (d *s3Driver) GetObject(ctx context.Context, key string) (io.ReadCloser, error) {
+	output, err := s3.New(d.session).GetObjectWithContext(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(d.bucket),
+		Key:    aws.String(key),
+	})
+	if isS3NotFound(err) {
+		return nil, errors.Wrapf(ErrNotFound, "%q in bucket %q", key, d.bucket)
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get %q from bucket %q", key, d.bucket)
+	}
+	return output.Body, nil
+}
+
+func (d *s3Driver) ListObjects(ctx context.Context, prefix string, fn func(Object) error) error {
+	var fnErr error
+	err := s3.New(d.session).ListObjectsV2PagesWithContext(ctx, &s3.ListObjectsV2Input{
+		Bucket: aws.String(d.bucket),
+		Prefix: aws.String(prefix),
+	}, func(page *s3.ListObjectsV2Output, lastPage bool) bool {
+		for _, object := range page.Contents {
+			fnErr = fn(Object{
+				Key:          aws.StringValue(object.Key),
+				Size:         aws.Int64Value(object.Size),
+				LastModified: aws.TimeValue(object.LastModified),
+			})
+			if fnErr != nil {
+				return false
+			}
+		}
+		return true
+	})
+	if err != nil {
+		return errors.Wrapf(err, "failed to list objects in bucket %q", d.bucket)
+	}
+	return fnErr
+}
+
+func (d *s3Driver) DeleteObjects(ctx context.Context, keys []string) error {
+	s3Client := s3.New(d.session)
+
+	// delete objects accepts at most 1000 keys per request
+	for len(keys) > 0 {
+		batch := keys
+		if len(batch) > 1000 {
+			batch = batch[:1000]
+		}
+		keys = keys[len(batch):]
+
+		objects := []*s3.ObjectIdentifier{}
+		for _, key := range batch {
+			objects = append(objects, &s3.ObjectIdentifier{Key: aws.String(key)})
+		}
+
+		_, err := s3Client.DeleteObjectsWithContext(ctx, &s3.DeleteObjectsInput{
+			Bucket: aws.String(d.bucket),
+			Delete: &s3.Delete{
+				Objects: objects,
+				Quiet:   aws.Bool(true),
+			},
+		})
+		if err != nil {
+			return errors.Wrapf(err, "failed to delete objects in bucket %q", d.bucket)
+		}
+	}
+
+	return nil
+}
+
+func (d *s3Driver) checkBucketName() error {
+	if d.bucket == "ship-pacts" {
+		log.Println("Not creating bucket because the desired name is ship-pacts. Consider using a different bucket name to make this work.")
+		return errors.New("bad bucket name")
+	}
+	return nil
+}
+
+func isS3NotFound(err error) bool {
+	if awsErr, ok := err.(awserr.Error); ok {
+		switch awsErr.Code() {
+		case "NotFound", "NoSuchKey":
+			return true
+		}
+	}
+	return false
+}
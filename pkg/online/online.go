@@ -10,6 +10,7 @@ import (
 	"github.com/pkg/errors"
 	kotsv1beta1 "github.com/replicatedhq/kots/kotskinds/apis/kots/v1beta1"
 	"github.com/replicatedhq/kots/pkg/crypto"
+	deployhistorytypes "github.com/replicatedhq/kots/pkg/deployhistory/types"
 	kotsadmconfig "github.com/replicatedhq/kots/pkg/kotsadmconfig"
 	identity "github.com/replicatedhq/kots/pkg/kotsadmidentity"
 	"github.com/replicatedhq/kots/pkg/kotsutil"
@@ -121,6 +122,11 @@ func CreateAppFromOnline(pendingApp *types.PendingApp, upstreamURI string, isAut
 		configFile = tmpFile.Name()
 	}
 
+	additionalManifests, err := kotsadmconfig.ReadAdditionalManifestsFromInClusterSecret()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read additional manifests from in cluster")
+	}
+
 	identityConfigFile, err := identity.InitAppIdentityConfig(pendingApp.Slug, kotsv1beta1.Storage{}, crypto.AESCipher{})
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to init identity config")
@@ -141,6 +147,7 @@ func CreateAppFromOnline(pendingApp *types.PendingApp, upstreamURI string, isAut
 		CreateAppDir:        false,
 		ConfigFile:          configFile,
 		IdentityConfigFile:  identityConfigFile,
+		AdditionalManifests: additionalManifests,
 		ReportWriter:        pipeWriter,
 		AppSlug:             pendingApp.Slug,
 		AppSequence:         0,
@@ -199,7 +206,8 @@ func CreateAppFromOnline(pendingApp *types.PendingApp, upstreamURI string, isAut
 		}
 		if !needsConfig {
 			if skipPreflights {
-				if err := version.DeployVersion(pendingApp.ID, newSequence); err != nil {
+				deployer := deployhistorytypes.Deployer{DeployedBy: deployhistorytypes.DeployedByInstall, SkippedPreflights: true}
+				if err := version.DeployVersion(pendingApp.ID, newSequence, deployer); err != nil {
 					return nil, errors.Wrap(err, "failed to deploy version")
 				}
 
@@ -8,7 +8,11 @@ import (
 	_ "github.com/lib/pq"
 )
 
-var DB *sql.DB
+var (
+	DB *sql.DB
+	// ReadDB is the connection to the read replica, if one is configured
+	ReadDB *sql.DB
+)
 
 func MustGetPGSession() *sql.DB {
 	if DB != nil {
@@ -23,3 +27,23 @@ func MustGetPGSession() *sql.DB {
 	DB = db
 	return db
 }
+
+// MustGetPGReadSession returns a connection to the read replica set in POSTGRES_READ_URI, or to the primary if no
+// replica is configured. Reads from the replica can lag behind writes to the primary.
+func MustGetPGReadSession() *sql.DB {
+	if os.Getenv("POSTGRES_READ_URI") == "" {
+		return MustGetPGSession()
+	}
+
+	if ReadDB != nil {
+		return ReadDB
+	}
+	db, err := sql.Open("postgres", os.Getenv("POSTGRES_READ_URI"))
+	if err != nil {
+		fmt.Printf("error connecting to postgres read replica: %v\n", err)
+		panic(err)
+	}
+
+	ReadDB = db
+	return db
+}
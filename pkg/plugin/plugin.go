@@ -0,0 +1,123 @@
+package plugin
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+)
+
+// Prefix is the prefix of the names of plugin executables, "kots-foo-bar" is run by "kubectl kots foo bar"
+const Prefix = "kots-"
+
+type Plugin struct {
+	// Name is the command that runs the plugin, its args joined with dashes
+	Name string
+	Path string
+	// ShadowedBy is the path of the plugin with the same name earlier in PATH, which is the one that runs
+	ShadowedBy string
+}
+
+// List returns the plugins in the dirs of pathEnv, sorted by name. Plugins that are shadowed by another plugin with
+// the same name are included so that they can be reported.
+func List(pathEnv string) []Plugin {
+	plugins := []Plugin{}
+	found := map[string]string{}
+
+	for _, dir := range filepath.SplitList(pathEnv) {
+		if dir == "" {
+			continue
+		}
+		files, err := ioutil.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+		for _, file := range files {
+			name, ok := pluginName(file)
+			if !ok {
+				continue
+			}
+			plugin := Plugin{
+				Name:       name,
+				Path:       filepath.Join(dir, file.Name()),
+				ShadowedBy: found[name],
+			}
+			if plugin.ShadowedBy == "" {
+				found[name] = plugin.Path
+			}
+			plugins = append(plugins, plugin)
+		}
+	}
+
+	sort.SliceStable(plugins, func(i, j int) bool {
+		return plugins[i].Name < plugins[j].Name
+	})
+	return plugins
+}
+
+// Find returns the plugin for the longest prefix of args that names one, and the args to pass to it. Args after the
+// first flag are never part of the name, as with kubectl plugins.
+func Find(pathEnv string, args []string) (*Plugin, []string) {
+	nameArgs := []string{}
+	for _, arg := range args {
+		if strings.HasPrefix(arg, "-") {
+			break
+		}
+		nameArgs = append(nameArgs, arg)
+	}
+
+	for i := len(nameArgs); i > 0; i-- {
+		name := strings.Join(nameArgs[:i], "-")
+		if path := lookPath(pathEnv, Prefix+name); path != "" {
+			return &Plugin{Name: name, Path: path}, args[i:]
+		}
+	}
+
+	return nil, nil
+}
+
+func lookPath(pathEnv string, filename string) string {
+	for _, dir := range filepath.SplitList(pathEnv) {
+		if dir == "" {
+			continue
+		}
+		candidates := []string{filepath.Join(dir, filename)}
+		if runtime.GOOS == "windows" {
+			candidates = append(candidates, filepath.Join(dir, filename+".exe"))
+		}
+		for _, candidate := range candidates {
+			info, err := os.Stat(candidate)
+			if err != nil {
+				continue
+			}
+			if _, ok := pluginName(info); ok {
+				return candidate
+			}
+		}
+	}
+	return ""
+}
+
+// pluginName returns the name of the plugin if the file is an executable with the plugin prefix
+func pluginName(info os.FileInfo) (string, bool) {
+	if info.IsDir() || !strings.HasPrefix(info.Name(), Prefix) {
+		return "", false
+	}
+
+	name := strings.TrimPrefix(info.Name(), Prefix)
+	if runtime.GOOS == "windows" {
+		if !strings.HasSuffix(name, ".exe") {
+			return "", false
+		}
+		name = strings.TrimSuffix(name, ".exe")
+	} else if info.Mode()&0111 == 0 {
+		return "", false
+	}
+
+	if name == "" {
+		return "", false
+	}
+	return name, true
+}
@@ -0,0 +1,97 @@
+package plugin
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func writePlugin(t *testing.T, dir string, name string, mode os.FileMode) string {
+	path := filepath.Join(dir, name)
+	err := ioutil.WriteFile(path, []byte("#!/bin/sh\n"), mode)
+	require.NoError(t, err)
+	return path
+}
+
+func Test_List(t *testing.T) {
+	first, err := ioutil.TempDir("", "kots-plugins")
+	require.NoError(t, err)
+	defer os.RemoveAll(first)
+
+	second, err := ioutil.TempDir("", "kots-plugins")
+	require.NoError(t, err)
+	defer os.RemoveAll(second)
+
+	report := writePlugin(t, first, "kots-report", 0755)
+	writePlugin(t, first, "kots-not-executable", 0644)
+	writePlugin(t, first, "kubectl-other", 0755)
+	shadowed := writePlugin(t, second, "kots-report", 0755)
+	sync := writePlugin(t, second, "kots-sync-jira", 0755)
+
+	plugins := List(strings.Join([]string{first, "", second}, string(os.PathListSeparator)))
+	require.Equal(t, []Plugin{
+		{Name: "report", Path: report},
+		{Name: "report", Path: shadowed, ShadowedBy: report},
+		{Name: "sync-jira", Path: sync},
+	}, plugins)
+}
+
+func Test_Find(t *testing.T) {
+	dir, err := ioutil.TempDir("", "kots-plugins")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	sync := writePlugin(t, dir, "kots-sync", 0755)
+	syncJira := writePlugin(t, dir, "kots-sync-jira", 0755)
+
+	tests := []struct {
+		name     string
+		args     []string
+		wantPath string
+		wantArgs []string
+	}{
+		{
+			name:     "longest match",
+			args:     []string{"sync", "jira", "PROJ-1"},
+			wantPath: syncJira,
+			wantArgs: []string{"PROJ-1"},
+		},
+		{
+			name:     "shorter match",
+			args:     []string{"sync", "github"},
+			wantPath: sync,
+			wantArgs: []string{"github"},
+		},
+		{
+			name:     "flags are not part of the name",
+			args:     []string{"sync", "--verbose", "jira"},
+			wantPath: sync,
+			wantArgs: []string{"--verbose", "jira"},
+		},
+		{
+			name: "no match",
+			args: []string{"install", "app"},
+		},
+		{
+			name: "leading flag",
+			args: []string{"-n", "sync"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			plugin, args := Find(dir, test.args)
+			if test.wantPath == "" {
+				require.Nil(t, plugin)
+				return
+			}
+			require.NotNil(t, plugin)
+			require.Equal(t, test.wantPath, plugin.Path)
+			require.Equal(t, test.wantArgs, args)
+		})
+	}
+}
@@ -4,8 +4,10 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"time"
 
 	"github.com/pkg/errors"
+	handlertypes "github.com/replicatedhq/kots/pkg/api/handlers/types"
 	"github.com/replicatedhq/kots/pkg/logger"
 	"github.com/replicatedhq/kots/pkg/rbac"
 	rbactypes "github.com/replicatedhq/kots/pkg/rbac/types"
@@ -28,6 +30,24 @@ func (e RBACError) Abort(w http.ResponseWriter) error {
 	return err
 }
 
+type ReadOnlyModeError struct{}
+
+func (e ReadOnlyModeError) Abort(w http.ResponseWriter) error {
+	err := errors.New("the admin console is in read-only mode, changes are not allowed until an administrator turns read-only mode off")
+	response := ErrorResponse{Error: err.Error()}
+	JSON(w, http.StatusForbidden, response)
+	return err
+}
+
+type ReauthRequiredError struct{}
+
+func (e ReauthRequiredError) Abort(w http.ResponseWriter) error {
+	err := errors.New("this operation requires a recent login, log in again to continue")
+	response := handlertypes.ErrorResponse{Error: err.Error(), Code: handlertypes.ErrorCodeReauthRequired}
+	JSON(w, http.StatusUnauthorized, response)
+	return err
+}
+
 type Middleware struct {
 	KOTSStore store.Store
 	Roles     []rbactypes.Role
@@ -71,6 +91,32 @@ func (m *Middleware) EnforceAccess(p *Policy, handler http.HandlerFunc) http.Han
 			}
 		}
 
+		if p.isDeniedInReadOnlyMode() {
+			isReadOnly, err := m.KOTSStore.IsReadOnlyMode()
+			if err != nil {
+				logger.Error(errors.Wrap(err, "failed to check read-only mode"))
+				w.WriteHeader(http.StatusInternalServerError)
+				return
+			}
+			if isReadOnly {
+				logger.Error(ReadOnlyModeError{}.Abort(w))
+				return
+			}
+		}
+
+		if p.requireRecentLogin {
+			settings, err := session.GetSettings(m.KOTSStore)
+			if err != nil {
+				logger.Error(errors.Wrap(err, "failed to get session settings"))
+				w.WriteHeader(http.StatusInternalServerError)
+				return
+			}
+			if !session.IsRecentLogin(sess, settings, time.Now()) {
+				logger.Error(ReauthRequiredError{}.Abort(w))
+				return
+			}
+		}
+
 		handler(w, r)
 	}
 }
@@ -24,6 +24,8 @@ var (
 
 var (
 	RegistryRead = Must(NewPolicy(ActionRead, "registry."))
+	// RegistryWrite rolls out the admin console with images from another registry
+	RegistryWrite = Must(NewPolicy(ActionWrite, "registry.")).RequireRecentLogin()
 )
 
 // Snapshots
@@ -32,11 +34,18 @@ var (
 	BackupRead            = Must(NewPolicy(ActionRead, "backup."))
 	BackupWrite           = Must(NewPolicy(ActionWrite, "backup."))
 	RestoreRead           = Must(NewPolicy(ActionRead, "restore."))
-	RestoreWrite          = Must(NewPolicy(ActionWrite, "restore."))
+	RestoreWrite          = Must(NewPolicy(ActionWrite, "restore.")).RequireRecentLogin()
 	SnapshotsettingsRead  = Must(NewPolicy(ActionRead, "snapshotsettings."))
 	SnapshotsettingsWrite = Must(NewPolicy(ActionWrite, "snapshotsettings."))
 )
 
+// Kotsadm state
+
+var (
+	// the export holds the config values, registry credentials and sessions of the admin console
+	KotsadmStateExport = Must(NewPolicy(ActionWrite, "kotsadmstate.")).RequireRecentLogin()
+)
+
 // Cluster
 
 var (
@@ -44,6 +53,12 @@ var (
 	ClusterWrite = Must(NewPolicy(ActionWrite, "cluster."))
 )
 
+// Diagnostics
+
+var (
+	DiagnosticsRead = Must(NewPolicy(ActionRead, "diagnostics."))
+)
+
 // Gitops
 
 var (
@@ -54,13 +69,95 @@ var (
 // Prometheus
 
 var (
+	PrometheussettingsRead  = Must(NewPolicy(ActionRead, "prometheussettings."))
 	PrometheussettingsWrite = Must(NewPolicy(ActionWrite, "prometheussettings."))
 )
 
+// CA Bundle
+
+var (
+	CABundleRead  = Must(NewPolicy(ActionRead, "cabundle."))
+	CABundleWrite = Must(NewPolicy(ActionWrite, "cabundle."))
+)
+
+// Client Certificates
+
+var (
+	ClientCertificatesRead  = Must(NewPolicy(ActionRead, "clientcertificates."))
+	ClientCertificatesWrite = Must(NewPolicy(ActionWrite, "clientcertificates."))
+)
+
+// Maintenance
+
+var (
+	MaintenanceRead  = Must(NewPolicy(ActionRead, "maintenance."))
+	MaintenanceWrite = Must(NewPolicy(ActionWrite, "maintenance."))
+)
+
+// Freeze
+
+var (
+	FreezeRead  = Must(NewPolicy(ActionRead, "freeze."))
+	FreezeWrite = Must(NewPolicy(ActionWrite, "freeze."))
+)
+
+// Read-only mode
+
+var (
+	ReadOnlyModeRead = Must(NewPolicy(ActionRead, "readonlymode."))
+	// ReadOnlyModeWrite is allowed in read-only mode so that it can be turned off
+	ReadOnlyModeWrite = Must(NewPolicy(ActionWrite, "readonlymode.")).AllowInReadOnlyMode()
+)
+
+// Upload quota
+
+var (
+	UploadQuotaRead  = Must(NewPolicy(ActionRead, "uploadquota."))
+	UploadQuotaWrite = Must(NewPolicy(ActionWrite, "uploadquota."))
+)
+
+// Session settings
+
+var (
+	SessionSettingsRead  = Must(NewPolicy(ActionRead, "sessionsettings."))
+	SessionSettingsWrite = Must(NewPolicy(ActionWrite, "sessionsettings.")).RequireRecentLogin()
+)
+
+// LDAP
+
+var (
+	LDAPRead  = Must(NewPolicy(ActionRead, "ldap."))
+	LDAPWrite = Must(NewPolicy(ActionWrite, "ldap.")).RequireRecentLogin()
+)
+
+// Temporary access
+
+var (
+	TemporaryAccessRead = Must(NewPolicy(ActionRead, "temporaryaccess."))
+	// an access link is a login to the admin console
+	TemporaryAccessWrite = Must(NewPolicy(ActionWrite, "temporaryaccess.")).RequireRecentLogin()
+)
+
+// Remote installs
+
+var (
+	RemoteInstallRead = Must(NewPolicy(ActionRead, "remoteinstall."))
+	// registering a remote install stores a token for it, which is a sensitive operation
+	RemoteInstallWrite = Must(NewPolicy(ActionWrite, "remoteinstall.")).RequireRecentLogin()
+)
+
+// Log settings
+
+var (
+	LogSettingsRead = Must(NewPolicy(ActionRead, "logsettings."))
+	// the sink receives the logs of kotsadm, changing it is a sensitive operation
+	LogSettingsWrite = Must(NewPolicy(ActionWrite, "logsettings.")).RequireRecentLogin()
+)
+
 // Kotsadm Identity Service
 
 var (
-	IdentityServiceWrite = Must(NewPolicy(ActionWrite, "identityservice."))
+	IdentityServiceWrite = Must(NewPolicy(ActionWrite, "identityservice.")).RequireRecentLogin()
 	IdentityServiceRead  = Must(NewPolicy(ActionRead, "identityservice."))
 )
 
@@ -85,6 +182,27 @@ var (
 	AppStatusRead = Must(NewPolicy(ActionRead, "app.{{.appSlug}}.status."))
 )
 
+// App maintenance
+
+var (
+	AppMaintenanceRead  = Must(NewPolicy(ActionRead, "app.{{.appSlug}}.maintenance."))
+	AppMaintenanceWrite = Must(NewPolicy(ActionWrite, "app.{{.appSlug}}.maintenance."))
+)
+
+// App freeze
+
+var (
+	AppFreezeRead  = Must(NewPolicy(ActionRead, "app.{{.appSlug}}.freeze."))
+	AppFreezeWrite = Must(NewPolicy(ActionWrite, "app.{{.appSlug}}.freeze."))
+)
+
+// App release feedback
+
+var (
+	AppReleaseFeedbackRead  = Must(NewPolicy(ActionRead, "app.{{.appSlug}}.releasefeedback."))
+	AppReleaseFeedbackWrite = Must(NewPolicy(ActionWrite, "app.{{.appSlug}}.releasefeedback."))
+)
+
 // App supportbundle
 
 var (
@@ -98,7 +216,7 @@ var (
 	AppBackupRead            = Must(NewPolicy(ActionRead, "app.{{.appSlug}}.backup."))
 	AppBackupWrite           = Must(NewPolicy(ActionWrite, "app.{{.appSlug}}.backup."))
 	AppRestoreRead           = Must(NewPolicy(ActionRead, "app.{{.appSlug}}.restore."))
-	AppRestoreWrite          = Must(NewPolicy(ActionWrite, "app.{{.appSlug}}.restore."))
+	AppRestoreWrite          = Must(NewPolicy(ActionWrite, "app.{{.appSlug}}.restore.")).RequireRecentLogin()
 	AppSnapshotsettingsRead  = Must(NewPolicy(ActionRead, "app.{{.appSlug}}.snapshotsettings."))
 	AppSnapshotsettingsWrite = Must(NewPolicy(ActionWrite, "app.{{.appSlug}}.snapshotsettings."))
 )
@@ -133,6 +251,12 @@ var (
 	AppDownstreamFiletreeRead = Must(NewPolicy(ActionRead, "app.{{.appSlug}}.downstream.filetree."))
 )
 
+// App deploy approval
+
+var (
+	AppDeployApprovalWrite = Must(NewPolicy(ActionWrite, "app.{{.appSlug}}.deployapproval."))
+)
+
 // App downstream preflight
 
 var (
@@ -145,4 +269,7 @@ var (
 var (
 	AppDownstreamConfigRead  = Must(NewPolicy(ActionRead, "app.{{.appSlug}}.downstream.config."))
 	AppDownstreamConfigWrite = Must(NewPolicy(ActionWrite, "app.{{.appSlug}}.downstream.config."))
+
+	// decrypted config values include the values of password items, the support and approver roles are denied
+	AppDownstreamConfigDecryptedRead = Must(NewPolicy(ActionRead, "app.{{.appSlug}}.downstream.config.decrypted.")).RequireRecentLogin()
 )
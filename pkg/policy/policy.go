@@ -12,10 +12,12 @@ import (
 type VarsGetter func(kotsStore store.Store, vars map[string]string) (map[string]string, error)
 
 type Policy struct {
-	action           string
-	resource         string
-	resourceTemplate *template.Template
-	varsGetterFns    []VarsGetter
+	action              string
+	resource            string
+	resourceTemplate    *template.Template
+	varsGetterFns       []VarsGetter
+	allowInReadOnlyMode bool
+	requireRecentLogin  bool
 }
 
 func NewPolicy(action, resource string, fns ...VarsGetter) (policy *Policy, err error) {
@@ -31,6 +33,23 @@ func Must(p *Policy, err error) *Policy {
 	return p
 }
 
+// AllowInReadOnlyMode lets requests with a write policy through when the console is in read-only mode
+func (p *Policy) AllowInReadOnlyMode() *Policy {
+	p.allowInReadOnlyMode = true
+	return p
+}
+
+// RequireRecentLogin marks the policy as a sensitive operation, the session must have been issued within the
+// re-auth window of the session settings
+func (p *Policy) RequireRecentLogin() *Policy {
+	p.requireRecentLogin = true
+	return p
+}
+
+func (p *Policy) isDeniedInReadOnlyMode() bool {
+	return p.action == ActionWrite && !p.allowInReadOnlyMode
+}
+
 func (p *Policy) execute(r *http.Request, kotsStore store.Store) (action, resource string, err error) {
 	vars := mux.Vars(r)
 	for _, fn := range p.varsGetterFns {
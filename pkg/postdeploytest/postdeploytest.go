@@ -0,0 +1,238 @@
+package postdeploytest
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/pkg/errors"
+	kotsv1beta1 "github.com/replicatedhq/kots/kotskinds/apis/kots/v1beta1"
+	deployhistorytypes "github.com/replicatedhq/kots/pkg/deployhistory/types"
+	"github.com/replicatedhq/kots/pkg/k8sutil"
+	"github.com/replicatedhq/kots/pkg/logger"
+	"github.com/replicatedhq/kots/pkg/postdeploytest/types"
+	"github.com/replicatedhq/kots/pkg/store"
+	"github.com/replicatedhq/kots/pkg/version"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	kuberneteserrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	defaultTimeout = 5 * time.Minute
+	pollInterval   = 2 * time.Second
+)
+
+// Run will run the post deploy tests declared in the application spec of the deployed sequence
+// and record the results. When a test fails and the application opts in, the previously deployed
+// sequence is redeployed.
+func Run(appID string, clusterID string, sequence int64) error {
+	kotsKinds, err := version.GetKotsKinds(appID, sequence)
+	if err != nil {
+		return errors.Wrap(err, "failed to load kotskinds")
+	}
+
+	tests := kotsKinds.KotsApplication.Spec.PostDeployTests
+	if len(tests) == 0 {
+		return nil
+	}
+
+	clientset, err := k8sutil.GetClientset()
+	if err != nil {
+		return errors.Wrap(err, "failed to get clientset")
+	}
+
+	results := make([]types.Result, len(tests))
+	for i, test := range tests {
+		results[i] = types.Result{
+			Name:   test.Name,
+			Status: types.StatusRunning,
+		}
+	}
+	if err := store.GetStore().SetDownstreamPostDeployTestResults(appID, clusterID, sequence, results); err != nil {
+		return errors.Wrap(err, "failed to set initial results")
+	}
+
+	for i, test := range tests {
+		startedAt := time.Now()
+		results[i].StartedAt = &startedAt
+
+		if err := runTest(clientset, test); err != nil {
+			results[i].Status = types.StatusFailed
+			results[i].Message = err.Error()
+		} else {
+			results[i].Status = types.StatusPassed
+		}
+
+		finishedAt := time.Now()
+		results[i].FinishedAt = &finishedAt
+
+		if err := store.GetStore().SetDownstreamPostDeployTestResults(appID, clusterID, sequence, results); err != nil {
+			return errors.Wrap(err, "failed to set results")
+		}
+	}
+
+	if !types.HasFailed(results) || !kotsKinds.KotsApplication.Spec.RollbackOnPostDeployTestFailure {
+		return nil
+	}
+
+	if err := rollback(appID, clusterID, sequence); err != nil {
+		return errors.Wrap(err, "failed to roll back")
+	}
+
+	return nil
+}
+
+// rollback will deploy the previously deployed sequence, as long as it did not fail its own tests.
+// this keeps a version that fails its tests from rolling back to another failing version.
+func rollback(appID string, clusterID string, failedSequence int64) error {
+	previousSequence, err := store.GetStore().GetPreviouslyDeployedSequence(appID, clusterID)
+	if err != nil {
+		return errors.Wrap(err, "failed to get previously deployed sequence")
+	}
+	if previousSequence == -1 || previousSequence == failedSequence {
+		logger.Infof("post deploy tests failed for sequence %d, but there is no version to roll back to", failedSequence)
+		return nil
+	}
+
+	previousResults, err := store.GetStore().GetDownstreamPostDeployTestResults(appID, clusterID, previousSequence)
+	if err != nil {
+		return errors.Wrap(err, "failed to get previous results")
+	}
+	if types.HasFailed(previousResults) {
+		logger.Infof("post deploy tests failed for sequence %d, not rolling back to sequence %d because it also failed", failedSequence, previousSequence)
+		return nil
+	}
+
+	logger.Infof("post deploy tests failed for sequence %d, rolling back to sequence %d", failedSequence, previousSequence)
+
+	if err := store.GetStore().DeleteDownstreamDeployStatus(appID, clusterID, previousSequence); err != nil {
+		return errors.Wrap(err, "failed to delete deploy status")
+	}
+
+	deployer := deployhistorytypes.Deployer{DeployedBy: deployhistorytypes.DeployedByPostDeployTest}
+	if err := version.DeployVersion(appID, previousSequence, deployer); err != nil {
+		return errors.Wrap(err, "failed to deploy version")
+	}
+
+	return nil
+}
+
+func runTest(clientset kubernetes.Interface, test kotsv1beta1.PostDeployTest) error {
+	timeout := defaultTimeout
+	if test.TimeoutSeconds > 0 {
+		timeout = time.Duration(test.TimeoutSeconds) * time.Second
+	}
+
+	if test.Job != nil {
+		return waitForJob(clientset, test.Job, timeout)
+	}
+	if test.HTTP != nil {
+		return waitForHTTP(test.HTTP, timeout)
+	}
+
+	return errors.New("test does not define a job or http check")
+}
+
+// DeleteJobs deletes the jobs of the post deploy tests before the version is deployed, so that a job that completed
+// for a previous deploy is not taken as the result of this one
+func DeleteJobs(tests []kotsv1beta1.PostDeployTest) error {
+	clientset, err := k8sutil.GetClientset()
+	if err != nil {
+		return errors.Wrap(err, "failed to get clientset")
+	}
+
+	return deleteJobs(clientset, tests)
+}
+
+func deleteJobs(clientset kubernetes.Interface, tests []kotsv1beta1.PostDeployTest) error {
+	propagationPolicy := metav1.DeletePropagationBackground
+	for _, test := range tests {
+		if test.Job == nil {
+			continue
+		}
+		err := clientset.BatchV1().Jobs(jobNamespace(test.Job)).Delete(context.TODO(), test.Job.Name, metav1.DeleteOptions{
+			PropagationPolicy: &propagationPolicy,
+		})
+		if err != nil && !kuberneteserrors.IsNotFound(err) {
+			return errors.Wrapf(err, "failed to delete job %s", test.Job.Name)
+		}
+	}
+
+	return nil
+}
+
+func jobNamespace(testJob *kotsv1beta1.PostDeployTestJob) string {
+	if testJob.Namespace != "" {
+		return testJob.Namespace
+	}
+	return os.Getenv("POD_NAMESPACE")
+}
+
+func waitForJob(clientset kubernetes.Interface, testJob *kotsv1beta1.PostDeployTestJob, timeout time.Duration) error {
+	namespace := jobNamespace(testJob)
+
+	start := time.Now()
+	for {
+		job, err := clientset.BatchV1().Jobs(namespace).Get(context.TODO(), testJob.Name, metav1.GetOptions{})
+		if err != nil && !kuberneteserrors.IsNotFound(err) {
+			return errors.Wrap(err, "failed to get job")
+		}
+
+		if err == nil {
+			for _, condition := range job.Status.Conditions {
+				if condition.Status != corev1.ConditionTrue {
+					continue
+				}
+				if condition.Type == batchv1.JobComplete {
+					return nil
+				}
+				if condition.Type == batchv1.JobFailed {
+					return errors.Errorf("job %s failed: %s", testJob.Name, condition.Message)
+				}
+			}
+		}
+
+		if time.Now().Sub(start) > timeout {
+			if err != nil {
+				return errors.Errorf("job %s was not found in namespace %s", testJob.Name, namespace)
+			}
+			return errors.Errorf("timeout waiting for job %s to complete", testJob.Name)
+		}
+
+		time.Sleep(pollInterval)
+	}
+}
+
+func waitForHTTP(testHTTP *kotsv1beta1.PostDeployTestHTTP, timeout time.Duration) error {
+	expectedStatusCode := testHTTP.ExpectedStatusCode
+	if expectedStatusCode == 0 {
+		expectedStatusCode = http.StatusOK
+	}
+
+	client := &http.Client{
+		Timeout: 10 * time.Second,
+	}
+
+	lastErr := errors.New("no requests were made")
+	start := time.Now()
+	for time.Now().Sub(start) < timeout {
+		resp, err := client.Get(testHTTP.URL)
+		if err != nil {
+			lastErr = errors.Wrap(err, "failed to make request")
+		} else {
+			resp.Body.Close()
+			if resp.StatusCode == expectedStatusCode {
+				return nil
+			}
+			lastErr = errors.Errorf("unexpected status code %d, expected %d", resp.StatusCode, expectedStatusCode)
+		}
+
+		time.Sleep(pollInterval)
+	}
+
+	return errors.Wrapf(lastErr, "timeout waiting for %s", testHTTP.URL)
+}
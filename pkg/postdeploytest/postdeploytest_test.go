@@ -0,0 +1,99 @@
+package postdeploytest
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	kotsv1beta1 "github.com/replicatedhq/kots/kotskinds/apis/kots/v1beta1"
+	"github.com/stretchr/testify/require"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func Test_deleteJobs(t *testing.T) {
+	req := require.New(t)
+
+	job := func(name string, namespace string) *batchv1.Job {
+		return &batchv1.Job{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace}}
+	}
+	clientset := fake.NewSimpleClientset(job("smoke-test", "default"), job("other-job", "default"))
+
+	tests := []kotsv1beta1.PostDeployTest{
+		{Name: "smoke", Job: &kotsv1beta1.PostDeployTestJob{Name: "smoke-test", Namespace: "default"}},
+		{Name: "not applied yet", Job: &kotsv1beta1.PostDeployTestJob{Name: "new-test", Namespace: "default"}},
+		{Name: "health", HTTP: &kotsv1beta1.PostDeployTestHTTP{URL: "http://localhost"}},
+	}
+	req.NoError(deleteJobs(clientset, tests))
+
+	jobs, err := clientset.BatchV1().Jobs("default").List(context.TODO(), metav1.ListOptions{})
+	req.NoError(err)
+	req.Len(jobs.Items, 1)
+	req.Equal("other-job", jobs.Items[0].Name)
+}
+
+func Test_waitForJob(t *testing.T) {
+	tests := []struct {
+		name      string
+		condition batchv1.JobConditionType
+		wantErr   bool
+	}{
+		{
+			name:      "complete",
+			condition: batchv1.JobComplete,
+			wantErr:   false,
+		},
+		{
+			name:      "failed",
+			condition: batchv1.JobFailed,
+			wantErr:   true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			req := require.New(t)
+
+			clientset := fake.NewSimpleClientset(&batchv1.Job{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "smoke-test",
+					Namespace: "default",
+				},
+				Status: batchv1.JobStatus{
+					Conditions: []batchv1.JobCondition{
+						{
+							Type:   test.condition,
+							Status: corev1.ConditionTrue,
+						},
+					},
+				},
+			})
+
+			err := waitForJob(clientset, &kotsv1beta1.PostDeployTestJob{Name: "smoke-test", Namespace: "default"}, time.Second)
+			if test.wantErr {
+				req.Error(err)
+			} else {
+				req.NoError(err)
+			}
+		})
+	}
+}
+
+func Test_waitForHTTP(t *testing.T) {
+	req := require.New(t)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	err := waitForHTTP(&kotsv1beta1.PostDeployTestHTTP{URL: server.URL, ExpectedStatusCode: http.StatusNoContent}, time.Second)
+	req.NoError(err)
+
+	err = waitForHTTP(&kotsv1beta1.PostDeployTestHTTP{URL: server.URL}, time.Second)
+	req.Error(err)
+}
@@ -0,0 +1,31 @@
+package types
+
+import (
+	"time"
+)
+
+type Status string
+
+const (
+	StatusRunning Status = "running"
+	StatusPassed  Status = "passed"
+	StatusFailed  Status = "failed"
+)
+
+type Result struct {
+	Name       string     `json:"name"`
+	Status     Status     `json:"status"`
+	Message    string     `json:"message,omitempty"`
+	StartedAt  *time.Time `json:"startedAt,omitempty"`
+	FinishedAt *time.Time `json:"finishedAt,omitempty"`
+}
+
+// HasFailed returns true if any of the results has failed
+func HasFailed(results []Result) bool {
+	for _, result := range results {
+		if result.Status == StatusFailed {
+			return true
+		}
+	}
+	return false
+}
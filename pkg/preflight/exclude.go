@@ -0,0 +1,90 @@
+package preflight
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+)
+
+// removeExcludedChecks drops the collectors and analyzers whose rendered "exclude" field is true.
+// The spec must already be rendered, so that checks can be excluded based on the config and license of the app,
+// for example to skip an object store check when the embedded object store is selected.
+// Excluded collectors are removed instead of being skipped by troubleshoot so that they are not reported as
+// collectors that did not run.
+func removeExcludedChecks(preflight *troubleshootv1beta2.Preflight) error {
+	collectors := []*troubleshootv1beta2.Collect{}
+	for _, collector := range preflight.Spec.Collectors {
+		excluded, err := isExcluded(collector)
+		if err != nil {
+			return errors.Wrap(err, "failed to check if collector is excluded")
+		}
+		if !excluded {
+			collectors = append(collectors, collector)
+		}
+	}
+
+	analyzers := []*troubleshootv1beta2.Analyze{}
+	for _, analyzer := range preflight.Spec.Analyzers {
+		excluded, err := isExcluded(analyzer)
+		if err != nil {
+			return errors.Wrap(err, "failed to check if analyzer is excluded")
+		}
+		if !excluded {
+			analyzers = append(analyzers, analyzer)
+		}
+	}
+
+	preflight.Spec.Collectors = collectors
+	preflight.Spec.Analyzers = analyzers
+
+	return nil
+}
+
+// isExcluded reads the exclude field of a collector or analyzer. Both are wrappers with a single non-nil member,
+// so the field is read from the json of that member instead of listing every collector and analyzer type.
+func isExcluded(check interface{}) (bool, error) {
+	if check == nil {
+		return false, nil
+	}
+
+	b, err := json.Marshal(check)
+	if err != nil {
+		return false, errors.Wrap(err, "failed to marshal")
+	}
+
+	members := map[string]struct {
+		Exclude interface{} `json:"exclude"`
+	}{}
+	if err := json.Unmarshal(b, &members); err != nil {
+		return false, errors.Wrap(err, "failed to unmarshal")
+	}
+
+	for name, member := range members {
+		switch exclude := member.Exclude.(type) {
+		case nil:
+		case bool:
+			if exclude {
+				return true, nil
+			}
+		case string:
+			exclude = strings.TrimSpace(exclude)
+			if exclude == "" {
+				continue
+			}
+			parsed, err := strconv.ParseBool(exclude)
+			if err != nil {
+				return false, errors.Wrapf(err, "failed to parse exclude value of %s", name)
+			}
+			if parsed {
+				return true, nil
+			}
+		default:
+			return false, errors.Errorf("unexpected exclude value of %s: %v", name, exclude)
+		}
+	}
+
+	return false, nil
+}
@@ -0,0 +1,69 @@
+package preflight
+
+import (
+	"testing"
+
+	"github.com/replicatedhq/kots/pkg/kotsutil"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_removeExcludedChecks(t *testing.T) {
+	spec := `apiVersion: troubleshoot.sh/v1beta2
+kind: Preflight
+metadata:
+  name: preflights
+spec:
+  collectors:
+    - clusterResources: {}
+    - run:
+        collectorName: s3-connectivity
+        exclude: 'true'
+    - run:
+        collectorName: postgres-connectivity
+        exclude: false
+  analyzers:
+    - clusterVersion:
+        checkName: Kubernetes Version
+        exclude: ""
+        outcomes:
+          - pass:
+              message: ok
+    - textAnalyze:
+        checkName: S3 Connectivity
+        exclude: true
+        fileName: s3-connectivity.log
+        outcomes:
+          - pass:
+              message: ok
+`
+	p, err := kotsutil.LoadPreflightFromContents([]byte(spec))
+	require.NoError(t, err)
+
+	err = removeExcludedChecks(p)
+	require.NoError(t, err)
+
+	require.Len(t, p.Spec.Collectors, 2)
+	require.NotNil(t, p.Spec.Collectors[0].ClusterResources)
+	require.Equal(t, "postgres-connectivity", p.Spec.Collectors[1].Run.CollectorName)
+
+	require.Len(t, p.Spec.Analyzers, 1)
+	require.Equal(t, "Kubernetes Version", p.Spec.Analyzers[0].ClusterVersion.CheckName)
+}
+
+func Test_removeExcludedChecksInvalid(t *testing.T) {
+	spec := `apiVersion: troubleshoot.sh/v1beta2
+kind: Preflight
+metadata:
+  name: preflights
+spec:
+  collectors:
+    - run:
+        collectorName: s3-connectivity
+        exclude: 'repl{{ ConfigOptionEquals "storage" "s3" }}'
+`
+	p, err := kotsutil.LoadPreflightFromContents([]byte(spec))
+	require.NoError(t, err)
+
+	err = removeExcludedChecks(p)
+	require.Error(t, err)
+}
@@ -2,12 +2,13 @@ package preflight
 
 import (
 	"encoding/json"
+	"fmt"
 	"strings"
-	"sync"
 	"time"
 
 	"github.com/pkg/errors"
 	"github.com/replicatedhq/kots/pkg/logger"
+	"github.com/replicatedhq/kots/pkg/preflight/types"
 	"github.com/replicatedhq/kots/pkg/store"
 	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
 	"github.com/replicatedhq/troubleshoot/pkg/preflight"
@@ -16,125 +17,330 @@ import (
 	"k8s.io/client-go/rest"
 )
 
+const (
+	// collectAttempts is how many times the collectors run while they fail with transient errors
+	collectAttempts = 3
+)
+
+var collectRetryBackoff = 5 * time.Second
+
 // execute will execute the preflights using spec in preflightSpec.
 // This spec should be rendered, no template functions remaining
-func execute(appID string, sequence int64, preflightSpec *troubleshootv1beta2.Preflight, ignorePermissionErrors bool) (*troubleshootpreflight.UploadPreflightResults, error) {
+func execute(appID string, sequence int64, preflightSpec *troubleshootv1beta2.Preflight, ignorePermissionErrors bool) (*types.PreflightResults, error) {
 	logger.Debug("executing preflight checks",
 		zap.String("appID", appID),
 		zap.Int64("sequence", sequence))
 
-	progressChan := make(chan interface{}, 0) // non-zero buffer will result in missed messages
-	defer close(progressChan)
+	restConfig, err := rest.InClusterConfig()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read in cluster config")
+	}
 
-	completeMx := sync.Mutex{}
-	isComplete := false
-	go func() {
-		for {
-			msg, ok := <-progressChan
-			if !ok {
-				return
+	collectOpts := troubleshootpreflight.CollectOpts{
+		Namespace:              "",
+		IgnorePermissionErrors: ignorePermissionErrors,
+		KubernetesRestConfig:   restConfig,
+	}
+
+	logger.Debug("preflight collect phase")
+	clusterCollectResult, collectors, err := collectWithRetries(appID, sequence, collectOpts, preflightSpec)
+
+	preflightResults := &types.PreflightResults{
+		Collectors: collectors,
+	}
+	if err != nil && !isPermissionsError(err) {
+		// the failure is stored as the result so that the version is not left pending preflights
+		logger.Error(errors.Wrap(err, "failed to collect"))
+		preflightResults.Errors = []*troubleshootpreflight.UploadPreflightError{
+			{Error: errors.Wrap(err, "failed to collect").Error()},
+		}
+	} else if isPermissionsError(err) {
+		logger.Debug("skipping analyze due to RBAC errors")
+		rbacErrors := []*troubleshootpreflight.UploadPreflightError{}
+		for _, collector := range clusterCollectResult.Collectors {
+			for _, e := range collector.RBACErrors {
+				rbacErrors = append(rbacErrors, &preflight.UploadPreflightError{
+					Error: e.Error(),
+				})
+			}
+		}
+		preflightResults.Errors = rbacErrors
+	} else {
+		preflightResults.Results = analyze(*clusterCollectResult)
+	}
+
+	if len(preflightResults.Errors) == 0 {
+		appendStorageResults(preflightResults)
+	}
+
+	logger.Debug("preflight marshalling")
+	b, err := json.Marshal(preflightResults)
+	if err != nil {
+		return preflightResults, errors.Wrap(err, "failed to marshal results")
+	}
+
+	if err := store.GetStore().SetPreflightResults(appID, sequence, b); err != nil {
+		return preflightResults, errors.Wrap(err, "failed to set preflight results")
+	}
+
+	return preflightResults, nil
+}
+
+// collectWithRetries runs the collectors, and runs them again with a backoff while collectors fail with transient
+// errors. Collected data is merged across attempts so that a collector that succeeded once keeps its output.
+// The returned result is nil only if the error is not a permissions error.
+func collectWithRetries(appID string, sequence int64, opts troubleshootpreflight.CollectOpts, preflightSpec *troubleshootv1beta2.Preflight) (*troubleshootpreflight.ClusterCollectResult, []types.CollectorResult, error) {
+	var merged *troubleshootpreflight.ClusterCollectResult
+	collectors := &collectorResults{byName: map[string]*types.CollectorResult{}}
+
+	var err error
+	for attempt := 1; attempt <= collectAttempts; attempt++ {
+		if attempt > 1 {
+			backoff := collectRetryBackoff * time.Duration(1<<uint(attempt-2))
+			logger.Infof("retrying preflight collectors in %s, attempt %d of %d", backoff, attempt, collectAttempts)
+			time.Sleep(backoff)
+		}
+
+		var collectResult troubleshootpreflight.CollectResult
+		var progress *collectProgress
+		collectResult, progress, err = collectOnce(appID, sequence, opts, preflightSpec)
+		if err != nil && !isPermissionsError(err) {
+			if !isTransientCollectError(err.Error()) {
+				break
+			}
+			continue
+		}
+
+		clusterCollectResult, ok := collectResult.(troubleshootpreflight.ClusterCollectResult)
+		if !ok {
+			return nil, nil, errors.Errorf("unexpected result type: %T", collectResult)
+		}
+
+		if clusterCollectResult.AllCollectedData == nil {
+			clusterCollectResult.AllCollectedData = map[string][]byte{}
+		}
+		if merged != nil {
+			for name, data := range merged.AllCollectedData {
+				if _, ok := clusterCollectResult.AllCollectedData[name]; !ok {
+					clusterCollectResult.AllCollectedData[name] = data
+				}
 			}
+		}
+		merged = &clusterCollectResult
+
+		runs := []collectorRun{}
+		for _, collector := range clusterCollectResult.Collectors {
+			runs = append(runs, collectorRun{
+				name:       collector.GetDisplayName(),
+				rbacErrors: collector.RBACErrors,
+			})
+		}
+		collectors.update(runs, progress)
+
+		if isPermissionsError(err) || !collectors.hasTransientFailures() {
+			break
+		}
+	}
 
+	if merged == nil {
+		return nil, nil, err
+	}
+	if err != nil && !isPermissionsError(err) {
+		// a retry failed as a whole, the data of the earlier attempt is analyzed
+		logger.Error(errors.Wrap(err, "failed to retry preflight collectors"))
+		err = nil
+	}
+	return merged, collectors.list(), err
+}
+
+// collectOnce runs the collectors once, reporting progress and recording the status of each collector
+func collectOnce(appID string, sequence int64, opts troubleshootpreflight.CollectOpts, preflightSpec *troubleshootv1beta2.Preflight) (troubleshootpreflight.CollectResult, *collectProgress, error) {
+	progressChan := make(chan interface{}, 0) // non-zero buffer will result in missed messages
+	progress := &collectProgress{statuses: map[string]string{}}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for msg := range progressChan {
 			logger.Debugf("%v", msg)
+			progress.record(msg)
 
-			progress, ok := msg.(preflight.CollectProgress)
+			collectProgress, ok := msg.(preflight.CollectProgress)
 			if !ok {
 				continue
 			}
 
 			// TODO: We need a nice title to display
 			progresBytes, err := json.Marshal(map[string]interface{}{
-				"completedCount": progress.CompletedCount,
-				"totalCount":     progress.TotalCount,
-				"currentName":    progress.CurrentName,
-				"currentStatus":  progress.CurrentStatus,
+				"completedCount": collectProgress.CompletedCount,
+				"totalCount":     collectProgress.TotalCount,
+				"currentName":    collectProgress.CurrentName,
+				"currentStatus":  collectProgress.CurrentStatus,
 				"updatedAt":      time.Now().Format(time.RFC3339),
 			})
 			if err != nil {
 				continue
 			}
-
-			completeMx.Lock()
-			if !isComplete {
-				_ = store.GetStore().SetPreflightProgress(appID, sequence, string(progresBytes))
-			}
-			completeMx.Unlock()
+			_ = store.GetStore().SetPreflightProgress(appID, sequence, string(progresBytes))
 		}
 	}()
 
-	restConfig, err := rest.InClusterConfig()
-	if err != nil {
-		return nil, errors.Wrap(err, "failed to read in cluster config")
-	}
+	opts.ProgressChan = progressChan
+	collectResult, err := troubleshootpreflight.Collect(opts, preflightSpec)
 
-	collectOpts := troubleshootpreflight.CollectOpts{
-		Namespace:              "",
-		IgnorePermissionErrors: ignorePermissionErrors,
-		ProgressChan:           progressChan,
-		KubernetesRestConfig:   restConfig,
-	}
+	// wait for the last progress message so that the status of every collector is recorded
+	close(progressChan)
+	<-done
 
-	logger.Debug("preflight collect phase")
-	collectResults, err := troubleshootpreflight.Collect(collectOpts, preflightSpec)
-	if err != nil && !isPermissionsError(err) {
-		return nil, errors.Wrap(err, "failed to collect")
+	return collectResult, progress, err
+}
+
+// collectProgress is the last status of each collector and the errors reported while collecting
+type collectProgress struct {
+	statuses map[string]string
+	errors   []string
+}
+
+func (p *collectProgress) record(msg interface{}) {
+	switch m := msg.(type) {
+	case preflight.CollectProgress:
+		p.statuses[m.CurrentName] = m.CurrentStatus
+	case error:
+		p.errors = append(p.errors, m.Error())
 	}
+}
 
-	clusterCollectResult, ok := collectResults.(troubleshootpreflight.ClusterCollectResult)
-	if !ok {
-		return nil, errors.Errorf("unexpected result type: %T", collectResults)
+// errorFor returns the last error that mentions the collector
+func (p *collectProgress) errorFor(name string) string {
+	for i := len(p.errors) - 1; i >= 0; i-- {
+		if strings.Contains(p.errors[i], name) {
+			return p.errors[i]
+		}
 	}
+	return ""
+}
 
-	uploadPreflightResults := &troubleshootpreflight.UploadPreflightResults{}
-	if isPermissionsError(err) {
-		logger.Debug("skipping analyze due to RBAC errors")
-		rbacErrors := []*troubleshootpreflight.UploadPreflightError{}
-		for _, collector := range clusterCollectResult.Collectors {
-			for _, e := range collector.RBACErrors {
-				rbacErrors = append(rbacErrors, &preflight.UploadPreflightError{
-					Error: e.Error(),
-				})
-			}
+type collectorRun struct {
+	name       string
+	rbacErrors []error
+}
+
+// collectorResults is the status of each collector across attempts, in the order the collectors run
+type collectorResults struct {
+	names  []string
+	byName map[string]*types.CollectorResult
+}
+
+func (c *collectorResults) update(runs []collectorRun, progress *collectProgress) {
+	for _, run := range runs {
+		result, ok := c.byName[run.name]
+		if !ok {
+			result = &types.CollectorResult{Name: run.name}
+			c.byName[run.name] = result
+			c.names = append(c.names, run.name)
 		}
-		uploadPreflightResults.Errors = rbacErrors
-	} else {
-		logger.Debug("preflight analyze phase")
-		analyzeResults := collectResults.Analyze()
-
-		// the typescript api added some flair to this result
-		// so let's keep it for compatibility
-		// MORE TYPES!
-		results := []*troubleshootpreflight.UploadPreflightResult{}
-		for _, analyzeResult := range analyzeResults {
-			uploadPreflightResult := &troubleshootpreflight.UploadPreflightResult{
-				IsFail:  analyzeResult.IsFail,
-				IsWarn:  analyzeResult.IsWarn,
-				IsPass:  analyzeResult.IsPass,
-				Title:   analyzeResult.Title,
-				Message: analyzeResult.Message,
-				URI:     analyzeResult.URI,
+
+		status, ran := progress.statuses[run.name]
+		if ran {
+			result.Attempts++
+		}
+
+		// a collector that succeeded in an earlier attempt keeps its output
+		if result.Status == types.CollectorStatusSucceeded {
+			continue
+		}
+
+		if len(run.rbacErrors) > 0 {
+			result.Status = types.CollectorStatusSkipped
+			result.Reason = fmt.Sprintf("insufficient permissions: %v", run.rbacErrors[0])
+			continue
+		}
+		if !ran {
+			result.Status = types.CollectorStatusSkipped
+			result.Reason = "the collector did not run, it may be excluded"
+			continue
+		}
+
+		if collectorErr := progress.errorFor(run.name); status != "completed" || collectorErr != "" {
+			result.Status = types.CollectorStatusFailed
+			result.Reason = collectorErr
+			if result.Reason == "" {
+				result.Reason = fmt.Sprintf("the collector finished with status %q", status)
 			}
+			continue
+		}
 
-			results = append(results, uploadPreflightResult)
+		result.Status = types.CollectorStatusSucceeded
+		result.Reason = ""
+	}
+}
+
+// hasTransientFailures returns true if a collector failed with an error that may not happen again
+func (c *collectorResults) hasTransientFailures() bool {
+	for _, result := range c.byName {
+		if result.Status == types.CollectorStatusFailed && isTransientCollectError(result.Reason) {
+			return true
 		}
-		uploadPreflightResults.Results = results
 	}
+	return false
+}
 
-	logger.Debug("preflight marshalling")
-	b, err := json.Marshal(uploadPreflightResults)
-	if err != nil {
-		return uploadPreflightResults, errors.Wrap(err, "failed to marshal results")
+func (c *collectorResults) list() []types.CollectorResult {
+	results := []types.CollectorResult{}
+	for _, name := range c.names {
+		results = append(results, *c.byName[name])
 	}
+	return results
+}
 
-	completeMx.Lock()
-	defer completeMx.Unlock()
+// transientCollectErrors are parts of error messages from the api server or the network that are worth retrying
+var transientCollectErrors = []string{
+	"timeout",
+	"timed out",
+	"connection refused",
+	"connection reset",
+	"broken pipe",
+	"unexpected eof",
+	"tls handshake",
+	"too many requests",
+	"service unavailable",
+	"the server is currently unable to handle the request",
+	"etcdserver: request timed out",
+	"internal error occurred",
+}
 
-	isComplete = true
-	if err := store.GetStore().SetPreflightResults(appID, sequence, b); err != nil {
-		return uploadPreflightResults, errors.Wrap(err, "failed to set preflight results")
+func isTransientCollectError(message string) bool {
+	message = strings.ToLower(message)
+	for _, transient := range transientCollectErrors {
+		if strings.Contains(message, transient) {
+			return true
+		}
+	}
+	return false
+}
+
+func analyze(collectResults troubleshootpreflight.CollectResult) []*troubleshootpreflight.UploadPreflightResult {
+	logger.Debug("preflight analyze phase")
+	analyzeResults := collectResults.Analyze()
+
+	// the typescript api added some flair to this result
+	// so let's keep it for compatibility
+	// MORE TYPES!
+	results := []*troubleshootpreflight.UploadPreflightResult{}
+	for _, analyzeResult := range analyzeResults {
+		uploadPreflightResult := &troubleshootpreflight.UploadPreflightResult{
+			IsFail:  analyzeResult.IsFail,
+			IsWarn:  analyzeResult.IsWarn,
+			IsPass:  analyzeResult.IsPass,
+			Title:   analyzeResult.Title,
+			Message: analyzeResult.Message,
+			URI:     analyzeResult.URI,
+		}
+
+		results = append(results, uploadPreflightResult)
 	}
 
-	return uploadPreflightResults, nil
+	return results
 }
 
 func isPermissionsError(err error) bool {
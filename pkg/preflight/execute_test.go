@@ -0,0 +1,71 @@
+package preflight
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/replicatedhq/kots/pkg/preflight/types"
+	troubleshootpreflight "github.com/replicatedhq/troubleshoot/pkg/preflight"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_collectorResults(t *testing.T) {
+	runs := []collectorRun{
+		{name: "cluster-info"},
+		{name: "run/ping"},
+		{name: "secret/registry", rbacErrors: []error{errors.New("cannot get secrets")}},
+		{name: "copy/excluded"},
+	}
+
+	first := &collectProgress{statuses: map[string]string{}}
+	first.record(troubleshootpreflight.CollectProgress{CurrentName: "cluster-info", CurrentStatus: "completed"})
+	first.record(troubleshootpreflight.CollectProgress{CurrentName: "run/ping", CurrentStatus: "running"})
+	first.record(errors.New("failed to run collector: run/ping: context deadline exceeded (Client.Timeout exceeded while awaiting headers)"))
+	first.record(troubleshootpreflight.CollectProgress{CurrentName: "run/ping", CurrentStatus: "failed"})
+
+	collectors := &collectorResults{byName: map[string]*types.CollectorResult{}}
+	collectors.update(runs, first)
+	require.True(t, collectors.hasTransientFailures())
+
+	second := &collectProgress{statuses: map[string]string{}}
+	second.record(errors.New("failed to run collector: cluster-info: connection refused"))
+	second.record(troubleshootpreflight.CollectProgress{CurrentName: "cluster-info", CurrentStatus: "failed"})
+	second.record(troubleshootpreflight.CollectProgress{CurrentName: "run/ping", CurrentStatus: "completed"})
+
+	collectors.update(runs, second)
+	require.False(t, collectors.hasTransientFailures())
+
+	require.Equal(t, []types.CollectorResult{
+		{Name: "cluster-info", Status: types.CollectorStatusSucceeded, Attempts: 2},
+		{Name: "run/ping", Status: types.CollectorStatusSucceeded, Attempts: 2},
+		{Name: "secret/registry", Status: types.CollectorStatusSkipped, Reason: "insufficient permissions: cannot get secrets"},
+		{Name: "copy/excluded", Status: types.CollectorStatusSkipped, Reason: "the collector did not run, it may be excluded"},
+	}, collectors.list())
+}
+
+func Test_collectorResultsNotRetried(t *testing.T) {
+	progress := &collectProgress{statuses: map[string]string{}}
+	progress.record(errors.New("failed to run collector: run/ping: image pull failed: not found"))
+	progress.record(troubleshootpreflight.CollectProgress{CurrentName: "run/ping", CurrentStatus: "completed"})
+
+	collectors := &collectorResults{byName: map[string]*types.CollectorResult{}}
+	collectors.update([]collectorRun{{name: "run/ping"}}, progress)
+
+	require.False(t, collectors.hasTransientFailures())
+	require.Equal(t, []types.CollectorResult{
+		{
+			Name:     "run/ping",
+			Status:   types.CollectorStatusFailed,
+			Reason:   "failed to run collector: run/ping: image pull failed: not found",
+			Attempts: 1,
+		},
+	}, collectors.list())
+}
+
+func Test_isTransientCollectError(t *testing.T) {
+	require.True(t, isTransientCollectError("Get https://10.96.0.1:443/version: dial tcp 10.96.0.1:443: connect: connection refused"))
+	require.True(t, isTransientCollectError("the server is currently unable to handle the request (get nodes)"))
+	require.True(t, isTransientCollectError("etcdserver: request timed out"))
+	require.False(t, isTransientCollectError("insufficient permissions to run all collectors"))
+	require.False(t, isTransientCollectError(""))
+}
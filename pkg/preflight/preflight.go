@@ -3,15 +3,18 @@ package preflight
 import (
 	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
 	"os"
 
 	"github.com/pkg/errors"
 	"github.com/replicatedhq/kots/kotskinds/client/kotsclientset/scheme"
+	deployhistorytypes "github.com/replicatedhq/kots/pkg/deployhistory/types"
 	"github.com/replicatedhq/kots/pkg/k8sutil"
 	kotstypes "github.com/replicatedhq/kots/pkg/kotsadm/types"
 	"github.com/replicatedhq/kots/pkg/kotsutil"
 	"github.com/replicatedhq/kots/pkg/logger"
+	"github.com/replicatedhq/kots/pkg/preflight/types"
 	"github.com/replicatedhq/kots/pkg/registry"
 	registrytypes "github.com/replicatedhq/kots/pkg/registry/types"
 	"github.com/replicatedhq/kots/pkg/render"
@@ -90,6 +93,10 @@ func Run(appID string, appSlug string, sequence int64, isAirgap bool, archiveDir
 			return errors.Wrap(err, "failed to load rendered preflight")
 		}
 
+		if err := removeExcludedChecks(p); err != nil {
+			return errors.Wrap(err, "failed to remove excluded preflight checks")
+		}
+
 		injectDefaultPreflights(p, renderedKotsKinds, registrySettings)
 
 		collectors, err := registry.UpdateCollectorSpecsWithRegistryData(p.Spec.Collectors, registrySettings, renderedKotsKinds.Installation.Spec.KnownImages, renderedKotsKinds.License)
@@ -100,7 +107,7 @@ func Run(appID string, appSlug string, sequence int64, isAirgap bool, archiveDir
 
 		go func() {
 			logger.Debug("preflight checks beginning")
-			uploadPreflightResults, err := execute(appID, sequence, p, ignoreRBAC)
+			preflightResults, err := execute(appID, sequence, p, ignoreRBAC)
 			if err != nil {
 				err = errors.Wrap(err, "failed to run preflight checks")
 				logger.Error(err)
@@ -108,7 +115,7 @@ func Run(appID string, appSlug string, sequence int64, isAirgap bool, archiveDir
 			}
 			logger.Debug("preflight checks completed")
 
-			isDeployed, err := maybeDeployFirstVersion(appID, sequence, uploadPreflightResults)
+			isDeployed, err := maybeDeployFirstVersion(appID, sequence, preflightResults)
 			if err != nil {
 				err = errors.Wrap(err, "failed to deploy first version")
 				logger.Error(err)
@@ -124,7 +131,7 @@ func Run(appID string, appSlug string, sequence int64, isAirgap bool, archiveDir
 			}
 		}()
 	} else if sequence == 0 {
-		_, err := maybeDeployFirstVersion(appID, sequence, &troubleshootpreflight.UploadPreflightResults{})
+		_, err := maybeDeployFirstVersion(appID, sequence, &types.PreflightResults{})
 		if err != nil {
 			return errors.Wrap(err, "failed to deploy first version")
 		}
@@ -145,8 +152,9 @@ func Run(appID string, appSlug string, sequence int64, isAirgap bool, archiveDir
 
 // maybeDeployFirstVersion will deploy the first version if
 // 1. preflight checks pass
-// 2. we have not already deployed it
-func maybeDeployFirstVersion(appID string, sequence int64, preflightResults *troubleshootpreflight.UploadPreflightResults) (bool, error) {
+// 2. every collector succeeded, so that the checks did not pass on partial data
+// 3. we have not already deployed it
+func maybeDeployFirstVersion(appID string, sequence int64, preflightResults *types.PreflightResults) (bool, error) {
 	if sequence != 0 {
 		return false, nil
 	}
@@ -161,17 +169,21 @@ func maybeDeployFirstVersion(appID string, sequence int64, preflightResults *tro
 		return false, nil
 	}
 
-	preflightState := getPreflightState(preflightResults)
+	preflightState := getPreflightState(&preflightResults.UploadPreflightResults)
 	if preflightState != "pass" {
 		return false, nil
 	}
+	if preflightResults.HasFailedCollectors() {
+		logger.Debug("not automatically deploying first app version because some preflight collectors failed")
+		return false, nil
+	}
 
 	logger.Debug("automatically deploying first app version")
 
 	// note: this may attempt to re-deploy the first version but the operator will take care of
 	// comparing the version to current
 
-	err = version.DeployVersion(appID, sequence)
+	err = version.DeployVersion(appID, sequence, deployhistorytypes.Deployer{DeployedBy: deployhistorytypes.DeployedByInstall})
 	if err != nil {
 		return false, errors.Wrap(err, "failed to deploy version")
 	}
@@ -179,6 +191,25 @@ func maybeDeployFirstVersion(appID string, sequence int64, preflightResults *tro
 	return true, nil
 }
 
+// GetState returns the state of the latest preflight run of the version, "pass", "warn" or "fail", or an empty
+// string if the preflight checks have not completed
+func GetState(appID string, sequence int64) (string, error) {
+	result, err := store.GetStore().GetPreflightResults(appID, sequence)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to get preflight results")
+	}
+	if result == nil || result.Result == "" {
+		return "", nil
+	}
+
+	preflightResults := types.PreflightResults{}
+	if err := json.Unmarshal([]byte(result.Result), &preflightResults); err != nil {
+		return "", errors.Wrap(err, "failed to unmarshal preflight results")
+	}
+
+	return getPreflightState(&preflightResults.UploadPreflightResults), nil
+}
+
 func getPreflightState(preflightResults *troubleshootpreflight.UploadPreflightResults) string {
 	if len(preflightResults.Errors) > 0 {
 		return "fail"
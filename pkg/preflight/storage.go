@@ -0,0 +1,44 @@
+package preflight
+
+import (
+	"fmt"
+
+	"github.com/pkg/errors"
+	"github.com/replicatedhq/kots/pkg/k8sutil"
+	"github.com/replicatedhq/kots/pkg/logger"
+	"github.com/replicatedhq/kots/pkg/preflight/types"
+	"github.com/replicatedhq/kots/pkg/storageprofile"
+	troubleshootpreflight "github.com/replicatedhq/troubleshoot/pkg/preflight"
+)
+
+// appendStorageResults adds warnings for distributed storage classes with settings that are known to cause data
+// corruption or loss. These are not troubleshoot analyzers and are checked on every run.
+func appendStorageResults(preflightResults *types.PreflightResults) {
+	clientset, err := k8sutil.GetClientset()
+	if err != nil {
+		logger.Error(errors.Wrap(err, "failed to get clientset for storage checks"))
+		return
+	}
+
+	profiles, err := storageprofile.GetProfiles(clientset)
+	if err != nil {
+		logger.Error(errors.Wrap(err, "failed to get storage profiles"))
+		return
+	}
+
+	preflightResults.Results = append(preflightResults.Results, storageResults(profiles)...)
+}
+
+func storageResults(profiles []storageprofile.Profile) []*troubleshootpreflight.UploadPreflightResult {
+	results := []*troubleshootpreflight.UploadPreflightResult{}
+	for _, profile := range profiles {
+		for _, warning := range profile.Warnings {
+			results = append(results, &troubleshootpreflight.UploadPreflightResult{
+				IsWarn:  true,
+				Title:   fmt.Sprintf("Storage Class %s", profile.StorageClass),
+				Message: warning,
+			})
+		}
+	}
+	return results
+}
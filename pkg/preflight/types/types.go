@@ -1,6 +1,10 @@
 package types
 
-import "time"
+import (
+	"time"
+
+	troubleshootpreflight "github.com/replicatedhq/troubleshoot/pkg/preflight"
+)
 
 type PreflightResult struct {
 	Result      string     `json:"result"`
@@ -8,3 +12,37 @@ type PreflightResult struct {
 	AppSlug     string     `json:"appSlug"`
 	ClusterSlug string     `json:"clusterSlug"`
 }
+
+// PreflightResults are the stored results of a preflight run, with the status of each collector so that a run with
+// some failed collectors can be shown instead of failing as a whole
+type PreflightResults struct {
+	troubleshootpreflight.UploadPreflightResults
+	Collectors []CollectorResult `json:"collectors,omitempty"`
+}
+
+// HasFailedCollectors returns true if the analysis is based on partial data
+func (r PreflightResults) HasFailedCollectors() bool {
+	for _, collector := range r.Collectors {
+		if collector.Status == CollectorStatusFailed {
+			return true
+		}
+	}
+	return false
+}
+
+type CollectorStatus string
+
+const (
+	CollectorStatusSucceeded CollectorStatus = "succeeded"
+	CollectorStatusFailed    CollectorStatus = "failed"
+	CollectorStatusSkipped   CollectorStatus = "skipped"
+)
+
+type CollectorResult struct {
+	Name   string          `json:"name"`
+	Status CollectorStatus `json:"status"`
+	// Reason is why the collector failed or was skipped
+	Reason string `json:"reason,omitempty"`
+	// Attempts is the number of times the collector ran
+	Attempts int `json:"attempts"`
+}
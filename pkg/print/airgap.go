@@ -0,0 +1,51 @@
+package print
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/replicatedhq/kots/pkg/airgap/verify"
+)
+
+func AirgapVerifyResult(result *verify.Result, format string) {
+	switch format {
+	case "json":
+		printAirgapVerifyResultJSON(result)
+	default:
+		printAirgapVerifyResultTable(result)
+	}
+}
+
+func printAirgapVerifyResultJSON(result *verify.Result) {
+	str, _ := json.MarshalIndent(result, "", "    ")
+	fmt.Println(string(str))
+}
+
+func printAirgapVerifyResultTable(result *verify.Result) {
+	w := NewTabWriter()
+
+	fmtColumns := "%s\t%s\t%s\n"
+	fmt.Fprintf(w, fmtColumns, "CHECK", "STATUS", "ERRORS")
+	for _, check := range result.Checks {
+		status := "passed"
+		if !check.Passed() {
+			status = "failed"
+		}
+		fmt.Fprintf(w, fmtColumns, check.Name, status, fmt.Sprintf("%d", len(check.Errors)))
+	}
+	w.Flush()
+
+	for _, check := range result.Checks {
+		for _, err := range check.Errors {
+			fmt.Printf("\n[%s] %s", check.Name, err)
+		}
+	}
+	for _, warning := range result.Warnings {
+		fmt.Printf("\n[warning] %s", warning)
+	}
+	if len(result.Warnings) > 0 || !result.Passed() {
+		fmt.Println()
+	}
+
+	fmt.Printf("\n%d images in the bundle, %d images referenced by the app\n", len(result.Images), len(result.AppImages))
+}
@@ -3,11 +3,14 @@ package print
 import (
 	"encoding/json"
 	"fmt"
+
+	"k8s.io/apimachinery/pkg/labels"
 )
 
 type App struct {
-	Slug  string `json:"slug"`
-	State string `json:"state"`
+	Slug   string            `json:"slug"`
+	State  string            `json:"state"`
+	Labels map[string]string `json:"labels,omitempty"`
 }
 
 func Apps(apps []App, format string) {
@@ -28,9 +31,9 @@ func printAppsTable(apps []App) {
 	w := NewTabWriter()
 	defer w.Flush()
 
-	fmtColumns := "%s\t%s\n"
-	fmt.Fprintf(w, fmtColumns, "SLUG", "STATUS")
+	fmtColumns := "%s\t%s\t%s\n"
+	fmt.Fprintf(w, fmtColumns, "SLUG", "STATUS", "LABELS")
 	for _, app := range apps {
-		fmt.Fprintf(w, fmtColumns, app.Slug, app.State)
+		fmt.Fprintf(w, fmtColumns, app.Slug, app.State, labels.Set(app.Labels).String())
 	}
 }
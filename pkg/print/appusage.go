@@ -0,0 +1,65 @@
+package print
+
+import (
+	"encoding/json"
+	"fmt"
+
+	units "github.com/docker/go-units"
+	appusagetypes "github.com/replicatedhq/kots/pkg/appusage/types"
+)
+
+func AppUsage(report *appusagetypes.Report, format string) {
+	switch format {
+	case "json":
+		printAppUsageJSON(report)
+	default:
+		printAppUsageTable(report)
+	}
+}
+
+func printAppUsageJSON(report *appusagetypes.Report) {
+	str, _ := json.MarshalIndent(report, "", "    ")
+	fmt.Println(string(str))
+}
+
+func printAppUsageTable(report *appusagetypes.Report) {
+	if !report.MetricsAvailable {
+		fmt.Printf("Usage is not available: %s\n\n", report.MetricsError)
+	}
+
+	w := NewTabWriter()
+
+	fmtColumns := "%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\n"
+	fmt.Fprintf(w, fmtColumns, "NAMESPACE", "POD", "CPU USAGE", "CPU REQUEST", "CPU LIMIT", "MEMORY USAGE", "MEMORY REQUEST", "MEMORY LIMIT")
+	for _, pod := range report.Pods {
+		fmt.Fprintf(w, fmtColumns, pod.Namespace, pod.Name, millicores(pod.CPUUsageMillis), millicores(pod.CPURequestMillis), millicores(pod.CPULimitMillis),
+			units.BytesSize(float64(pod.MemoryUsageBytes)), units.BytesSize(float64(pod.MemoryRequestBytes)), units.BytesSize(float64(pod.MemoryLimitBytes)))
+	}
+	total := report.Total
+	fmt.Fprintf(w, fmtColumns, "", "TOTAL", millicores(total.CPUUsageMillis), millicores(total.CPURequestMillis), millicores(total.CPULimitMillis),
+		units.BytesSize(float64(total.MemoryUsageBytes)), units.BytesSize(float64(total.MemoryRequestBytes)), units.BytesSize(float64(total.MemoryLimitBytes)))
+	w.Flush()
+
+	if len(report.Volumes) == 0 {
+		return
+	}
+
+	fmt.Println()
+
+	w = NewTabWriter()
+	defer w.Flush()
+
+	fmtColumns = "%s\t%s\t%s\t%s\t%s\t%s\n"
+	fmt.Fprintf(w, fmtColumns, "NAMESPACE", "PVC", "STORAGE CLASS", "USED", "REQUEST", "CAPACITY")
+	for _, volume := range report.Volumes {
+		used := "unknown"
+		if report.VolumeStatsAvailable {
+			used = units.BytesSize(float64(volume.UsedBytes))
+		}
+		fmt.Fprintf(w, fmtColumns, volume.Namespace, volume.Name, volume.StorageClass, used, units.BytesSize(float64(volume.RequestBytes)), units.BytesSize(float64(volume.CapacityBytes)))
+	}
+}
+
+func millicores(m int64) string {
+	return fmt.Sprintf("%dm", m)
+}
@@ -0,0 +1,43 @@
+package print
+
+import (
+	"encoding/json"
+	"fmt"
+
+	connectivitytypes "github.com/replicatedhq/kots/pkg/connectivity/types"
+)
+
+func Connectivity(report *connectivitytypes.Report, format string) {
+	switch format {
+	case "json":
+		printConnectivityJSON(report)
+	default:
+		printConnectivityTable(report)
+	}
+}
+
+func printConnectivityJSON(report *connectivitytypes.Report) {
+	str, _ := json.MarshalIndent(report, "", "    ")
+	fmt.Println(string(str))
+}
+
+func printConnectivityTable(report *connectivitytypes.Report) {
+	proxy := report.Proxy
+	if proxy == "" {
+		proxy = "none"
+	}
+	fmt.Printf("Proxy: %s\n\n", proxy)
+
+	w := NewTabWriter()
+	defer w.Flush()
+
+	fmtColumns := "%s\t%s\t%s\t%s\t%s\t%s\n"
+	fmt.Fprintf(w, fmtColumns, "TARGET", "ADDRESS", "REACHABLE", "LATENCY", "TLS", "ERROR")
+	for _, check := range report.Checks {
+		tls := ""
+		if check.TLS != nil {
+			tls = fmt.Sprintf("%s, issued by %s, expires %s", check.TLS.Version, check.TLS.Issuer, check.TLS.NotAfter.Format("2006-01-02"))
+		}
+		fmt.Fprintf(w, fmtColumns, check.Target, check.Address, fmt.Sprintf("%t", check.Reachable), fmt.Sprintf("%dms", check.LatencyMs), tls, check.Error)
+	}
+}
@@ -0,0 +1,44 @@
+package print
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/replicatedhq/kots/pkg/deployhistory"
+	deployhistorytypes "github.com/replicatedhq/kots/pkg/deployhistory/types"
+)
+
+func DeployHistory(events []deployhistorytypes.Event, format string) error {
+	switch format {
+	case "json":
+		printDeployHistoryJSON(events)
+	case "csv":
+		return deployhistory.WriteCSV(os.Stdout, events)
+	default:
+		printDeployHistoryTable(events)
+	}
+	return nil
+}
+
+func printDeployHistoryJSON(events []deployhistorytypes.Event) {
+	str, _ := json.MarshalIndent(events, "", "    ")
+	fmt.Println(string(str))
+}
+
+func printDeployHistoryTable(events []deployhistorytypes.Event) {
+	w := NewTabWriter()
+	defer w.Flush()
+
+	fmtColumns := "%s\t%s\t%s\t%d\t%s\t%s\t%s\t%s\t%s\t%s\n"
+	fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\n", "DEPLOYED AT", "APP", "DOWNSTREAM", "SEQUENCE", "VERSION", "PHASE", "DEPLOYED BY", "APPROVED BY", "PREFLIGHTS", "RESULT")
+	for _, event := range events {
+		phase := event.Phase
+		if phase == "" {
+			phase = "deploy"
+		}
+		fmt.Fprintf(w, fmtColumns, event.DeployedAt.UTC().Format(time.RFC3339), event.AppSlug, event.DownstreamName, event.Sequence,
+			event.VersionLabel, phase, event.DeployedBy, event.ApprovedBy, event.PreflightState, event.Result)
+	}
+}
@@ -0,0 +1,43 @@
+package print
+
+import (
+	"encoding/json"
+	"fmt"
+
+	fleetreporttypes "github.com/replicatedhq/kots/pkg/fleetreport/types"
+)
+
+func FleetReport(report *fleetreporttypes.Report, format string) {
+	switch format {
+	case "json":
+		printFleetReportJSON(report)
+	default:
+		printFleetReportTable(report)
+	}
+}
+
+func printFleetReportJSON(report *fleetreporttypes.Report) {
+	str, _ := json.MarshalIndent(report, "", "    ")
+	fmt.Println(string(str))
+}
+
+func printFleetReportTable(report *fleetreporttypes.Report) {
+	fmt.Printf("Cluster ID:         %s\n", report.ClusterID)
+	fmt.Printf("KOTS version:       %s\n", report.KotsVersion)
+	fmt.Printf("Kubernetes version: %s\n", report.K8sVersion)
+	fmt.Printf("kURL:               %t\n\n", report.IsKurl)
+
+	w := NewTabWriter()
+	defer w.Flush()
+
+	fmtColumns := "%s\t%s\t%s\t%s\t%s\t%s\t%d\n"
+	fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n", "SLUG", "VERSION", "SEQUENCE", "CHANNEL", "LICENSE ID", "STATUS", "PENDING")
+	for _, app := range report.Apps {
+		versionLabel, sequence := "", ""
+		if app.DeployedVersion != nil {
+			versionLabel = app.DeployedVersion.VersionLabel
+			sequence = fmt.Sprintf("%d", app.DeployedVersion.Sequence)
+		}
+		fmt.Fprintf(w, fmtColumns, app.Slug, versionLabel, sequence, app.ChannelName, app.LicenseID, app.State, app.PendingVersions)
+	}
+}
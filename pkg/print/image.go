@@ -0,0 +1,37 @@
+package print
+
+import (
+	"encoding/json"
+	"fmt"
+
+	imagereporttypes "github.com/replicatedhq/kots/pkg/imagereport/types"
+)
+
+func ImageReport(report *imagereporttypes.Report, format string) {
+	switch format {
+	case "json":
+		printImageReportJSON(report)
+	default:
+		printImageReportTable(report)
+	}
+}
+
+func printImageReportJSON(report *imagereporttypes.Report) {
+	str, _ := json.MarshalIndent(report, "", "    ")
+	fmt.Println(string(str))
+}
+
+func printImageReportTable(report *imagereporttypes.Report) {
+	w := NewTabWriter()
+	defer w.Flush()
+
+	fmtColumns := "%s\t%s\t%s\t%s\n"
+	fmt.Fprintf(w, fmtColumns, "RESOURCE", "ORIGINAL", "REWRITTEN", "DIGEST")
+	for _, image := range report.Images {
+		digest := image.Digest
+		if digest == "" {
+			digest = "<unresolved>"
+		}
+		fmt.Fprintf(w, fmtColumns, fmt.Sprintf("%s/%s", image.Kind, image.Name), image.Original, image.Rewritten, digest)
+	}
+}
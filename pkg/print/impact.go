@@ -0,0 +1,67 @@
+package print
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/replicatedhq/kots/pkg/downstream"
+)
+
+func Impact(report *downstream.ImpactReport, format string) {
+	switch format {
+	case "json":
+		printImpactJSON(report)
+	default:
+		printImpactText(report)
+	}
+}
+
+func printImpactJSON(report *downstream.ImpactReport) {
+	str, _ := json.MarshalIndent(report, "", "    ")
+	fmt.Println(string(str))
+}
+
+func printImpactText(report *downstream.ImpactReport) {
+	if report.DeployedSequence == nil {
+		fmt.Printf("Deploying sequence %d, no version is deployed\n", report.Sequence)
+	} else {
+		fmt.Printf("Deploying sequence %d over sequence %d\n", report.Sequence, *report.DeployedSequence)
+	}
+
+	fmt.Printf("\nImages: %d added, %d removed\n", len(report.ImagesAdded), len(report.ImagesRemoved))
+	for _, image := range report.ImagesAdded {
+		fmt.Printf("  + %s\n", image)
+	}
+	for _, image := range report.ImagesRemoved {
+		fmt.Printf("  - %s\n", image)
+	}
+
+	fmt.Printf("\nRollouts: %d workloads, an estimated %d pod restarts\n", len(report.RollingWorkloads), report.EstimatedRestarts)
+	if len(report.RollingWorkloads) > 0 {
+		w := NewTabWriter()
+		fmt.Fprintf(w, "  %s\t%s\t%s\t%s\n", "KIND", "NAMESPACE", "NAME", "PODS")
+		for _, workload := range report.RollingWorkloads {
+			fmt.Fprintf(w, "  %s\t%s\t%s\t%d\n", workload.Kind, workload.Namespace, workload.Name, workload.Pods)
+		}
+		w.Flush()
+	}
+
+	printResourceChanges("PVCs", report.PVCChanges)
+	printResourceChanges("CRDs", report.CRDChanges)
+	printResourceChanges("RBAC", report.RBACChanges)
+}
+
+func printResourceChanges(title string, changes []downstream.ResourceChange) {
+	fmt.Printf("\n%s: %d changed\n", title, len(changes))
+	if len(changes) == 0 {
+		return
+	}
+
+	w := NewTabWriter()
+	defer w.Flush()
+
+	fmt.Fprintf(w, "  %s\t%s\t%s\t%s\t%s\n", "KIND", "NAMESPACE", "NAME", "CHANGE", "DETAIL")
+	for _, change := range changes {
+		fmt.Fprintf(w, "  %s\t%s\t%s\t%s\t%s\n", change.Kind, change.Namespace, change.Name, change.Change, change.Detail)
+	}
+}
@@ -0,0 +1,62 @@
+package print
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/replicatedhq/kots/pkg/license"
+)
+
+func License(summary *license.Summary, format string) {
+	switch format {
+	case "json":
+		printLicenseJSON(summary)
+	default:
+		printLicenseTable(summary)
+	}
+}
+
+func printLicenseJSON(summary *license.Summary) {
+	str, _ := json.MarshalIndent(summary, "", "    ")
+	fmt.Println(string(str))
+}
+
+func printLicenseTable(summary *license.Summary) {
+	w := NewTabWriter()
+	defer w.Flush()
+
+	expires := "never"
+	if summary.ExpiresAt != nil {
+		expires = summary.ExpiresAt.Format(time.RFC3339)
+		if summary.IsExpired {
+			expires += " (expired)"
+		}
+	}
+
+	fmtColumns := "%s\t%s\n"
+	fmt.Fprintf(w, fmtColumns, "Customer:", summary.CustomerName)
+	fmt.Fprintf(w, fmtColumns, "App:", summary.AppSlug)
+	fmt.Fprintf(w, fmtColumns, "Channel:", summary.ChannelName)
+	fmt.Fprintf(w, fmtColumns, "License ID:", summary.LicenseID)
+	fmt.Fprintf(w, fmtColumns, "License type:", summary.LicenseType)
+	fmt.Fprintf(w, fmtColumns, "Expires:", expires)
+	fmt.Fprintf(w, fmtColumns, "Airgap:", supportedString(summary.IsAirgapSupported))
+	fmt.Fprintf(w, fmtColumns, "GitOps:", supportedString(summary.IsGitOpsSupported))
+	fmt.Fprintf(w, fmtColumns, "Snapshots:", supportedString(summary.IsSnapshotSupported))
+	fmt.Fprintf(w, fmtColumns, "Identity service:", supportedString(summary.IsIdentityServiceSupported))
+	for _, entitlement := range summary.Entitlements {
+		title := entitlement.Title
+		if title == "" {
+			title = entitlement.Name
+		}
+		fmt.Fprintf(w, fmtColumns, title+":", entitlement.Value)
+	}
+}
+
+func supportedString(supported bool) string {
+	if supported {
+		return "enabled"
+	}
+	return "disabled"
+}
@@ -0,0 +1,31 @@
+package print
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/replicatedhq/kots/pkg/downstream"
+)
+
+func Manifests(manifests []downstream.RenderedManifest, format string) {
+	switch format {
+	case "json":
+		printManifestsJSON(manifests)
+	default:
+		printManifestsYAML(manifests)
+	}
+}
+
+func printManifestsJSON(manifests []downstream.RenderedManifest) {
+	str, _ := json.MarshalIndent(manifests, "", "    ")
+	fmt.Println(string(str))
+}
+
+func printManifestsYAML(manifests []downstream.RenderedManifest) {
+	docs := []string{}
+	for _, manifest := range manifests {
+		docs = append(docs, strings.TrimSuffix(manifest.Content, "\n"))
+	}
+	fmt.Println(strings.Join(docs, "\n---\n"))
+}
@@ -0,0 +1,43 @@
+package print
+
+import (
+	"encoding/json"
+	"fmt"
+
+	handlertypes "github.com/replicatedhq/kots/pkg/api/handlers/types"
+)
+
+func PrometheusSettings(settings handlertypes.GetPrometheusSettingsResponse, format string) {
+	switch format {
+	case "json":
+		printPrometheusSettingsJSON(settings)
+	default:
+		printPrometheusSettingsTable(settings)
+	}
+}
+
+func printPrometheusSettingsJSON(settings handlertypes.GetPrometheusSettingsResponse) {
+	str, _ := json.MarshalIndent(settings, "", "    ")
+	fmt.Println(string(str))
+}
+
+func printPrometheusSettingsTable(settings handlertypes.GetPrometheusSettingsResponse) {
+	w := NewTabWriter()
+	defer w.Flush()
+
+	source := "console"
+	if settings.IsAddressFromEnv {
+		source = "env"
+	}
+
+	authType := "none"
+	if settings.Username != "" {
+		authType = fmt.Sprintf("basic (%s)", settings.Username)
+	} else if settings.HasBearerToken {
+		authType = "bearer token"
+	}
+
+	fmtColumns := "%s\t%s\t%s\n"
+	fmt.Fprintf(w, fmtColumns, "ADDRESS", "SOURCE", "AUTH")
+	fmt.Fprintf(w, fmtColumns, settings.Address, source, authType)
+}
@@ -0,0 +1,58 @@
+package print
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/replicatedhq/kots/pkg/release/dryrun"
+)
+
+func ReleaseDryRunResult(result *dryrun.Result, format string) {
+	switch format {
+	case "json":
+		printReleaseDryRunResultJSON(result)
+	default:
+		printReleaseDryRunResultTable(result)
+	}
+}
+
+func printReleaseDryRunResultJSON(result *dryrun.Result) {
+	str, _ := json.MarshalIndent(result, "", "    ")
+	fmt.Println(string(str))
+}
+
+func printReleaseDryRunResultTable(result *dryrun.Result) {
+	w := NewTabWriter()
+
+	fmtColumns := "%s\t%s\t%s\n"
+	fmt.Fprintf(w, fmtColumns, "CHECK", "STATUS", "ERRORS")
+	for _, check := range result.Checks {
+		status := "passed"
+		if !check.Passed() {
+			status = "failed"
+		}
+		fmt.Fprintf(w, fmtColumns, check.Name, status, fmt.Sprintf("%d", len(check.Errors)))
+	}
+	w.Flush()
+
+	for _, check := range result.Checks {
+		for _, err := range check.Errors {
+			fmt.Printf("\n[%s] %s", check.Name, err)
+		}
+	}
+	for _, warning := range result.Warnings {
+		fmt.Printf("\n[warning] %s", warning)
+	}
+	if len(result.Warnings) > 0 || !result.Passed() {
+		fmt.Println()
+	}
+
+	if len(result.Images) > 0 {
+		fmt.Printf("\nIMAGES\n")
+		for _, image := range result.Images {
+			fmt.Println(image)
+		}
+	}
+
+	fmt.Printf("\n%d files rendered, %d images referenced\n", len(result.Files), len(result.Images))
+}
@@ -0,0 +1,41 @@
+package print
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	handlertypes "github.com/replicatedhq/kots/pkg/api/handlers/types"
+)
+
+func ScheduledJobs(jobs []handlertypes.ScheduledJob, format string) {
+	switch format {
+	case "json":
+		printScheduledJobsJSON(jobs)
+	default:
+		printScheduledJobsTable(jobs)
+	}
+}
+
+func printScheduledJobsJSON(jobs []handlertypes.ScheduledJob) {
+	str, _ := json.MarshalIndent(jobs, "", "    ")
+	fmt.Println(string(str))
+}
+
+func printScheduledJobsTable(jobs []handlertypes.ScheduledJob) {
+	w := NewTabWriter()
+	defer w.Flush()
+
+	fmtColumns := "%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\n"
+	fmt.Fprintf(w, fmtColumns, "JOB", "APP", "SCHEDULE", "LAST RUN", "RESULT", "DURATION", "NEXT RUN", "MESSAGE")
+	for _, job := range jobs {
+		lastRun, result, duration, message := "", "", "", ""
+		if job.LastRun != nil {
+			lastRun = job.LastRun.StartedAt
+			result = string(job.LastRun.Status)
+			duration = time.Duration(job.LastRun.DurationSeconds * float64(time.Second)).Round(time.Second).String()
+			message = job.LastRun.Message
+		}
+		fmt.Fprintf(w, fmtColumns, job.Job, job.AppSlug, job.Schedule, lastRun, result, duration, job.NextRunAt, message)
+	}
+}
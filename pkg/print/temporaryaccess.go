@@ -0,0 +1,53 @@
+package print
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	temporaryaccesstypes "github.com/replicatedhq/kots/pkg/temporaryaccess/types"
+)
+
+func TemporaryAccess(grants []temporaryaccesstypes.Grant, format string) {
+	switch format {
+	case "json":
+		printTemporaryAccessJSON(grants)
+	default:
+		printTemporaryAccessTable(grants)
+	}
+}
+
+func printTemporaryAccessJSON(grants []temporaryaccesstypes.Grant) {
+	str, _ := json.MarshalIndent(grants, "", "    ")
+	fmt.Println(string(str))
+}
+
+func printTemporaryAccessTable(grants []temporaryaccesstypes.Grant) {
+	w := NewTabWriter()
+	defer w.Flush()
+
+	now := time.Now()
+
+	fmtColumns := "%s\t%s\t%s\t%s\t%s\t%s\t%s\n"
+	fmt.Fprintf(w, fmtColumns, "ID", "STATUS", "APP", "DESCRIPTION", "CREATED BY", "EXPIRES", "LAST USED")
+	for _, g := range grants {
+		status := "Active"
+		if g.RevokedAt != nil {
+			status = "Revoked"
+		} else if !g.IsActive(now) {
+			status = "Expired"
+		}
+
+		app := g.AppSlug
+		if app == "" {
+			app = "all"
+		}
+
+		lastUsed := ""
+		if g.LastUsedAt != nil {
+			lastUsed = g.LastUsedAt.UTC().Format(time.RFC3339)
+		}
+
+		fmt.Fprintf(w, fmtColumns, g.ID, status, app, g.Description, g.CreatedBy, g.ExpiresAt.UTC().Format(time.RFC3339), lastUsed)
+	}
+}
@@ -0,0 +1,61 @@
+package print
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	downstreamtypes "github.com/replicatedhq/kots/pkg/api/downstream/types"
+	"github.com/replicatedhq/kots/pkg/kustomize"
+)
+
+func Versions(versions []downstreamtypes.DownstreamVersion, totalCount int64, format string) {
+	switch format {
+	case "json":
+		printVersionsJSON(versions, totalCount)
+	default:
+		printVersionsTable(versions, totalCount)
+	}
+}
+
+func printVersionsJSON(versions []downstreamtypes.DownstreamVersion, totalCount int64) {
+	str, _ := json.MarshalIndent(struct {
+		Versions   []downstreamtypes.DownstreamVersion `json:"versions"`
+		TotalCount int64                               `json:"totalCount"`
+	}{
+		Versions:   versions,
+		TotalCount: totalCount,
+	}, "", "    ")
+	fmt.Println(string(str))
+}
+
+func printVersionsTable(versions []downstreamtypes.DownstreamVersion, totalCount int64) {
+	w := NewTabWriter()
+	defer w.Flush()
+
+	fmtColumns := "%d\t%s\t%s\t%s\t%s\t%s\t%s\t%s\n"
+	fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\n", "SEQUENCE", "VERSION", "STATUS", "SOURCE", "CREATED", "FILES CHANGED", "IMAGES CHANGED", "CONFIG CHANGED")
+	for _, version := range versions {
+		created := ""
+		if version.CreatedOn != nil {
+			created = version.CreatedOn.Format(time.RFC3339)
+		}
+
+		filesChanged, imagesChanged, configChanged := "", "", ""
+		if version.DiffSummary != "" {
+			diff := kustomize.Diff{}
+			if err := json.Unmarshal([]byte(version.DiffSummary), &diff); err == nil {
+				filesChanged = strconv.Itoa(diff.FilesChanged)
+				imagesChanged = strconv.Itoa(diff.ImagesChanged)
+				configChanged = strconv.FormatBool(diff.ConfigChanged)
+			}
+		}
+
+		fmt.Fprintf(w, fmtColumns, version.Sequence, version.VersionLabel, version.Status, version.Source, created, filesChanged, imagesChanged, configChanged)
+	}
+
+	if int64(len(versions)) < totalCount {
+		fmt.Fprintf(w, "\nShowing %d of %d versions\n", len(versions), totalCount)
+	}
+}
@@ -0,0 +1,105 @@
+package prometheus
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/replicatedhq/kots/pkg/prometheus/types"
+	"github.com/replicatedhq/kots/pkg/store"
+)
+
+const validateTimeout = 10 * time.Second
+
+// GetSettings returns the prometheus settings configured in the console. The address falls back to
+// the PROMETHEUS_ADDRESS env var of the kotsadm deployment if none is configured.
+func GetSettings() (*types.Settings, error) {
+	address, err := store.GetStore().GetPrometheusAddress()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get prometheus address")
+	}
+	if address == "" {
+		address = os.Getenv("PROMETHEUS_ADDRESS")
+	}
+
+	auth, err := store.GetStore().GetPrometheusAuth()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get prometheus auth")
+	}
+
+	return &types.Settings{
+		Address: address,
+		Auth:    auth,
+	}, nil
+}
+
+// SetRequestAuth adds the credentials of auth to a request to prometheus
+func SetRequestAuth(req *http.Request, auth types.Auth) {
+	if auth.Username != "" {
+		req.SetBasicAuth(auth.Username, auth.Password)
+	} else if auth.BearerToken != "" {
+		req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", auth.BearerToken))
+	}
+}
+
+// Validate checks that prometheus can be reached with the settings by running a trivial instant query
+func Validate(settings types.Settings) error {
+	if settings.Address == "" {
+		return errors.New("address is required")
+	}
+
+	u, err := url.Parse(settings.Address)
+	if err != nil {
+		return errors.Wrap(err, "failed to parse address")
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return errors.Errorf("address must start with http:// or https://")
+	}
+
+	v := url.Values{}
+	v.Set("query", "vector(1)")
+
+	req, err := http.NewRequest("GET", fmt.Sprintf("%s/api/v1/query?%s", settings.Address, v.Encode()), nil)
+	if err != nil {
+		return errors.Wrap(err, "failed to create request")
+	}
+	SetRequestAuth(req, settings.Auth)
+
+	client := &http.Client{
+		Timeout: validateTimeout,
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return errors.Wrap(err, "failed to connect to prometheus")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden {
+		return errors.Errorf("prometheus rejected the credentials with status code %d", resp.StatusCode)
+	} else if resp.StatusCode != http.StatusOK {
+		return errors.Errorf("unexpected status code %d", resp.StatusCode)
+	}
+
+	b, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return errors.Wrap(err, "failed to read response")
+	}
+
+	response := struct {
+		Status string `json:"status"`
+		Error  string `json:"error"`
+	}{}
+	if err := json.Unmarshal(b, &response); err != nil {
+		return errors.Wrap(err, "response is not from a prometheus api")
+	}
+	if response.Status != "success" {
+		return errors.Errorf("query failed: %s", response.Error)
+	}
+
+	return nil
+}
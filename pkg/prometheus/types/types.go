@@ -0,0 +1,19 @@
+package types
+
+// Settings are the connection settings of the prometheus that the console queries for metric graphs
+type Settings struct {
+	Address string
+	Auth    Auth
+}
+
+// Auth holds the credentials sent to prometheus. Basic auth is used if a username is set,
+// otherwise the bearer token is sent if it is set.
+type Auth struct {
+	Username    string `json:"username,omitempty"`
+	Password    string `json:"password,omitempty"`
+	BearerToken string `json:"bearerToken,omitempty"`
+}
+
+func (a Auth) IsEmpty() bool {
+	return a.Username == "" && a.Password == "" && a.BearerToken == ""
+}
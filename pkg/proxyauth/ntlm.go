@@ -0,0 +1,161 @@
+package proxyauth
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/md5"
+	"crypto/rand"
+	"encoding/binary"
+	"strings"
+	"time"
+	"unicode/utf16"
+
+	"github.com/pkg/errors"
+	"golang.org/x/crypto/md4"
+)
+
+// NTLMv2 messages as described in [MS-NLMP]. Only what is needed to authenticate a connection to a proxy is
+// implemented, messages are not signed or sealed.
+
+const (
+	ntlmNegotiateUnicode                 = 0x00000001
+	ntlmNegotiateOEM                     = 0x00000002
+	ntlmRequestTarget                    = 0x00000004
+	ntlmNegotiateNTLM                    = 0x00000200
+	ntlmNegotiateAlwaysSign              = 0x00008000
+	ntlmNegotiateExtendedSessionSecurity = 0x00080000
+	ntlmNegotiateTargetInfo              = 0x00800000
+	ntlmNegotiate128                     = 0x20000000
+	ntlmNegotiateKeyExchange             = 0x40000000
+	ntlmNegotiate56                      = 0x80000000
+
+	ntlmNegotiateFlags = ntlmNegotiateUnicode | ntlmNegotiateOEM | ntlmRequestTarget | ntlmNegotiateNTLM |
+		ntlmNegotiateAlwaysSign | ntlmNegotiateExtendedSessionSecurity | ntlmNegotiateTargetInfo |
+		ntlmNegotiate128 | ntlmNegotiate56
+
+	// the difference between the windows epoch (1601) and the unix epoch in 100 nanosecond intervals
+	windowsEpochOffset = 116444736000000000
+)
+
+var ntlmSignature = []byte("NTLMSSP\x00")
+
+type ntlmChallenge struct {
+	flags           uint32
+	serverChallenge []byte
+	targetInfo      []byte
+}
+
+// ntlmNegotiateMessage returns the first message of the handshake. The domain and workstation are not sent.
+func ntlmNegotiateMessage() []byte {
+	msg := make([]byte, 32)
+	copy(msg, ntlmSignature)
+	binary.LittleEndian.PutUint32(msg[8:], 1)
+	binary.LittleEndian.PutUint32(msg[12:], ntlmNegotiateFlags)
+	return msg
+}
+
+func parseNTLMChallenge(msg []byte) (*ntlmChallenge, error) {
+	if len(msg) < 48 || !bytes.Equal(msg[:8], ntlmSignature) || binary.LittleEndian.Uint32(msg[8:]) != 2 {
+		return nil, errors.New("not an ntlm challenge message")
+	}
+
+	targetInfoLen := int(binary.LittleEndian.Uint16(msg[40:]))
+	targetInfoOffset := int(binary.LittleEndian.Uint32(msg[44:]))
+	if targetInfoOffset+targetInfoLen > len(msg) {
+		return nil, errors.New("target info is out of bounds")
+	}
+
+	return &ntlmChallenge{
+		flags:           binary.LittleEndian.Uint32(msg[20:]),
+		serverChallenge: msg[24:32],
+		targetInfo:      msg[targetInfoOffset : targetInfoOffset+targetInfoLen],
+	}, nil
+}
+
+// ntlmAuthenticateMessage returns the last message of the handshake, the response to the challenge.
+// The username can include the domain in the form DOMAIN\username.
+func ntlmAuthenticateMessage(challenge *ntlmChallenge, username string, password string) ([]byte, error) {
+	clientChallenge := make([]byte, 8)
+	if _, err := rand.Read(clientChallenge); err != nil {
+		return nil, errors.Wrap(err, "failed to generate client challenge")
+	}
+
+	domain := ""
+	if parts := strings.SplitN(username, `\`, 2); len(parts) == 2 {
+		domain, username = parts[0], parts[1]
+	}
+
+	timestamp := uint64(time.Now().UnixNano()/100) + windowsEpochOffset
+	responseKey := ntowfv2(username, password, domain)
+	lmResponse, ntResponse := ntlmv2Responses(responseKey, challenge.serverChallenge, clientChallenge, timestamp, challenge.targetInfo)
+
+	// without key exchange there is no session key to send
+	flags := (challenge.flags | ntlmNegotiateUnicode) &^ ntlmNegotiateKeyExchange
+
+	fields := [][]byte{
+		lmResponse,
+		ntResponse,
+		utf16le(domain),
+		utf16le(username),
+		nil, // workstation
+		nil, // encrypted random session key
+	}
+
+	msg := make([]byte, 64)
+	copy(msg, ntlmSignature)
+	binary.LittleEndian.PutUint32(msg[8:], 3)
+	offset := len(msg)
+	for i, field := range fields {
+		buffer := msg[12+8*i:]
+		binary.LittleEndian.PutUint16(buffer[0:], uint16(len(field)))
+		binary.LittleEndian.PutUint16(buffer[2:], uint16(len(field)))
+		binary.LittleEndian.PutUint32(buffer[4:], uint32(offset))
+		offset += len(field)
+	}
+	binary.LittleEndian.PutUint32(msg[60:], flags)
+	for _, field := range fields {
+		msg = append(msg, field...)
+	}
+
+	return msg, nil
+}
+
+func ntowfv2(username string, password string, domain string) []byte {
+	hash := md4.New()
+	hash.Write(utf16le(password))
+	return hmacMD5(hash.Sum(nil), utf16le(strings.ToUpper(username)+domain))
+}
+
+func ntlmv2Responses(responseKey []byte, serverChallenge []byte, clientChallenge []byte, timestamp uint64, targetInfo []byte) ([]byte, []byte) {
+	temp := []byte{0x01, 0x01, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00}
+	temp = append(temp, make([]byte, 8)...)
+	binary.LittleEndian.PutUint64(temp[8:], timestamp)
+	temp = append(temp, clientChallenge...)
+	temp = append(temp, 0x00, 0x00, 0x00, 0x00)
+	temp = append(temp, targetInfo...)
+	temp = append(temp, 0x00, 0x00, 0x00, 0x00)
+
+	ntProofStr := hmacMD5(responseKey, serverChallenge, temp)
+	ntResponse := append(ntProofStr, temp...)
+
+	lmResponse := append(hmacMD5(responseKey, serverChallenge, clientChallenge), clientChallenge...)
+
+	return lmResponse, ntResponse
+}
+
+func hmacMD5(key []byte, data ...[]byte) []byte {
+	mac := hmac.New(md5.New, key)
+	for _, d := range data {
+		mac.Write(d)
+	}
+	return mac.Sum(nil)
+}
+
+func utf16le(s string) []byte {
+	encoded := utf16.Encode([]rune(s))
+	b := make([]byte, 2*len(encoded))
+	for i, c := range encoded {
+		binary.LittleEndian.PutUint16(b[2*i:], c)
+	}
+	return b
+}
@@ -0,0 +1,77 @@
+package proxyauth
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func mustDecodeHex(t *testing.T, s string) []byte {
+	b, err := hex.DecodeString(s)
+	require.NoError(t, err)
+	return b
+}
+
+// the values are from the NTLMv2 authentication example in [MS-NLMP] 4.2.4
+func Test_ntlmv2Responses(t *testing.T) {
+	responseKey := ntowfv2("User", "Password", "Domain")
+	require.Equal(t, "0c868a403bfd7a93a3001ef22ef02e3f", hex.EncodeToString(responseKey))
+
+	serverChallenge := mustDecodeHex(t, "0123456789abcdef")
+	clientChallenge := mustDecodeHex(t, "aaaaaaaaaaaaaaaa")
+	targetInfo := mustDecodeHex(t, "02000c0044006f006d00610069006e0001000c0053006500720076006500720000000000")
+
+	lmResponse, ntResponse := ntlmv2Responses(responseKey, serverChallenge, clientChallenge, 0, targetInfo)
+	require.Equal(t, "86c35097ac9cec102554764a57cccc19aaaaaaaaaaaaaaaa", hex.EncodeToString(lmResponse))
+	require.Equal(t, "68cd0ab851e51c96aabc927bebef6a1c", hex.EncodeToString(ntResponse[:16]))
+}
+
+func Test_ntlmAuthenticateMessage(t *testing.T) {
+	targetInfo := mustDecodeHex(t, "02000c0044006f006d00610069006e0000000000")
+
+	challengeMessage := make([]byte, 48)
+	copy(challengeMessage, ntlmSignature)
+	binary.LittleEndian.PutUint32(challengeMessage[8:], 2)
+	binary.LittleEndian.PutUint32(challengeMessage[20:], ntlmNegotiateFlags|ntlmNegotiateKeyExchange)
+	copy(challengeMessage[24:], mustDecodeHex(t, "0123456789abcdef"))
+	binary.LittleEndian.PutUint16(challengeMessage[40:], uint16(len(targetInfo)))
+	binary.LittleEndian.PutUint16(challengeMessage[42:], uint16(len(targetInfo)))
+	binary.LittleEndian.PutUint32(challengeMessage[44:], uint32(len(challengeMessage)))
+	challengeMessage = append(challengeMessage, targetInfo...)
+
+	challenge, err := parseNTLMChallenge(challengeMessage)
+	require.NoError(t, err)
+	require.Equal(t, targetInfo, challenge.targetInfo)
+
+	msg, err := ntlmAuthenticateMessage(challenge, `Domain\User`, "Password")
+	require.NoError(t, err)
+	require.Equal(t, ntlmSignature, msg[:8])
+	require.Equal(t, uint32(3), binary.LittleEndian.Uint32(msg[8:]))
+	require.Zero(t, binary.LittleEndian.Uint32(msg[60:])&ntlmNegotiateKeyExchange)
+
+	field := func(i int) []byte {
+		buffer := msg[12+8*i:]
+		length := int(binary.LittleEndian.Uint16(buffer))
+		offset := int(binary.LittleEndian.Uint32(buffer[4:]))
+		return msg[offset : offset+length]
+	}
+	require.Len(t, field(0), 24)
+	require.Equal(t, utf16le("Domain"), field(2))
+	require.Equal(t, utf16le("User"), field(3))
+	require.Empty(t, field(4))
+}
+
+func Test_parseNTLMChallenge(t *testing.T) {
+	_, err := parseNTLMChallenge([]byte("NTLMSSP\x00"))
+	require.Error(t, err)
+
+	msg := make([]byte, 48)
+	copy(msg, ntlmSignature)
+	binary.LittleEndian.PutUint32(msg[8:], 2)
+	binary.LittleEndian.PutUint16(msg[40:], 16)
+	binary.LittleEndian.PutUint32(msg[44:], 48)
+	_, err = parseNTLMChallenge(msg)
+	require.Error(t, err)
+}
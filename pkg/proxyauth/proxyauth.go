@@ -0,0 +1,367 @@
+package proxyauth
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"encoding/base64"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/replicatedhq/kots/pkg/hostaliases"
+	"github.com/replicatedhq/kots/pkg/k8sutil"
+	kotsadmtypes "github.com/replicatedhq/kots/pkg/kotsadm/types"
+	"github.com/replicatedhq/kots/pkg/logger"
+	corev1 "k8s.io/api/core/v1"
+	kuberneteserrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	// SecretName is the secret in the kotsadm namespace that holds the proxy credentials
+	SecretName = "kotsadm-proxy-auth"
+
+	SchemeBasic = "basic"
+	SchemeNTLM  = "ntlm"
+)
+
+// Credentials authenticate outbound requests to the proxy from the HTTP_PROXY and HTTPS_PROXY env vars
+type Credentials struct {
+	Scheme   string
+	Username string
+	Password string
+}
+
+var loaded atomic.Value // *Credentials
+
+// Validate returns an error if the credentials are incomplete or the scheme is not supported
+func (c Credentials) Validate() error {
+	if c.Scheme != SchemeBasic && c.Scheme != SchemeNTLM {
+		return errors.Errorf("unsupported proxy auth scheme %q, must be %s or %s", c.Scheme, SchemeBasic, SchemeNTLM)
+	}
+	if c.Username == "" {
+		return errors.New("proxy username is required")
+	}
+	return nil
+}
+
+// Get returns the proxy credentials stored in the namespace, or nil if there are none
+func Get(clientset kubernetes.Interface, namespace string) (*Credentials, error) {
+	secret, err := clientset.CoreV1().Secrets(namespace).Get(context.TODO(), SecretName, metav1.GetOptions{})
+	if kuberneteserrors.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get secret")
+	}
+	return &Credentials{
+		Scheme:   string(secret.Data["scheme"]),
+		Username: string(secret.Data["username"]),
+		Password: string(secret.Data["password"]),
+	}, nil
+}
+
+// Set stores the proxy credentials in the namespace. Nil credentials remove them.
+func Set(clientset kubernetes.Interface, namespace string, credentials *Credentials) error {
+	if credentials == nil {
+		err := clientset.CoreV1().Secrets(namespace).Delete(context.TODO(), SecretName, metav1.DeleteOptions{})
+		if err != nil && !kuberneteserrors.IsNotFound(err) {
+			return errors.Wrap(err, "failed to delete secret")
+		}
+		return nil
+	}
+
+	if err := credentials.Validate(); err != nil {
+		return errors.Wrap(err, "invalid proxy credentials")
+	}
+
+	data := map[string][]byte{
+		"scheme":   []byte(credentials.Scheme),
+		"username": []byte(credentials.Username),
+		"password": []byte(credentials.Password),
+	}
+
+	existing, err := clientset.CoreV1().Secrets(namespace).Get(context.TODO(), SecretName, metav1.GetOptions{})
+	if err != nil {
+		if !kuberneteserrors.IsNotFound(err) {
+			return errors.Wrap(err, "failed to get secret")
+		}
+
+		secret := &corev1.Secret{
+			TypeMeta: metav1.TypeMeta{
+				APIVersion: "v1",
+				Kind:       "Secret",
+			},
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      SecretName,
+				Namespace: namespace,
+				Labels:    kotsadmtypes.GetKotsadmLabels(),
+			},
+			Data: data,
+		}
+		if _, err := clientset.CoreV1().Secrets(namespace).Create(context.TODO(), secret, metav1.CreateOptions{}); err != nil {
+			return errors.Wrap(err, "failed to create secret")
+		}
+		return nil
+	}
+
+	existing.Data = data
+	if _, err := clientset.CoreV1().Secrets(namespace).Update(context.TODO(), existing, metav1.UpdateOptions{}); err != nil {
+		return errors.Wrap(err, "failed to update secret")
+	}
+	return nil
+}
+
+// Load makes all requests made with the default http transport authenticate to the proxy with the credentials.
+// Nil credentials stop authenticating.
+func Load(credentials *Credentials) error {
+	if credentials != nil {
+		if err := credentials.Validate(); err != nil {
+			return errors.Wrap(err, "invalid proxy credentials")
+		}
+	}
+	loaded.Store(credentials)
+
+	// the ca bundle replaces the default transport with one that is already configured
+	if transport, ok := http.DefaultTransport.(*http.Transport); ok {
+		Configure(transport)
+	}
+
+	return nil
+}
+
+// Configure makes the transport authenticate to the proxy with the loaded credentials
+func Configure(transport *http.Transport) {
+	transport.Proxy = Proxy
+	transport.DialContext = DialContext
+}
+
+// Start loads the proxy credentials stored in the namespace and reloads them whenever they change
+func Start(namespace string) error {
+	clientset, err := k8sutil.GetClientset()
+	if err != nil {
+		return errors.Wrap(err, "failed to get clientset")
+	}
+
+	reload := func() error {
+		credentials, err := Get(clientset, namespace)
+		if err != nil {
+			return errors.Wrap(err, "failed to get proxy credentials")
+		}
+		current := load()
+		if current == nil && credentials == nil {
+			return nil
+		}
+		if current != nil && credentials != nil && *current == *credentials {
+			return nil
+		}
+		if err := Load(credentials); err != nil {
+			return errors.Wrap(err, "failed to load proxy credentials")
+		}
+		return nil
+	}
+
+	if err := reload(); err != nil {
+		return err
+	}
+
+	go func() {
+		for {
+			time.Sleep(time.Minute)
+			if err := reload(); err != nil {
+				logger.Error(errors.Wrap(err, "failed to reload proxy credentials"))
+			}
+		}
+	}()
+
+	return nil
+}
+
+// Proxy returns the proxy from the environment for the request. Basic credentials are added to the url so that
+// the transport sends them to the proxy. Requests are not proxied by the transport when authenticating with ntlm,
+// DialContext tunnels them through the proxy instead.
+func Proxy(req *http.Request) (*url.URL, error) {
+	proxyURL, err := http.ProxyFromEnvironment(req)
+	if err != nil || proxyURL == nil {
+		return proxyURL, err
+	}
+
+	credentials := load()
+	if credentials == nil {
+		return proxyURL, nil
+	}
+	if credentials.Scheme == SchemeNTLM {
+		return nil, nil
+	}
+
+	withCredentials := *proxyURL
+	withCredentials.User = url.UserPassword(credentials.Username, credentials.Password)
+	return &withCredentials, nil
+}
+
+// DialContext dials the address with the host aliases applied. When authenticating with ntlm and the address is
+// proxied, the connection is a tunnel through the proxy that is authenticated with the ntlm handshake.
+func DialContext(ctx context.Context, network, address string) (net.Conn, error) {
+	credentials := load()
+	if credentials == nil || credentials.Scheme != SchemeNTLM {
+		return hostaliases.DialContext(ctx, network, address)
+	}
+
+	proxyURL, err := proxyForAddress(address)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get proxy")
+	}
+	if proxyURL == nil {
+		return hostaliases.DialContext(ctx, network, address)
+	}
+
+	conn, err := dialProxy(ctx, network, proxyURL)
+	if err != nil {
+		return nil, err
+	}
+
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+		defer conn.SetDeadline(time.Time{})
+	}
+
+	if err := connectNTLM(conn, address, *credentials); err != nil {
+		conn.Close()
+		return nil, errors.Wrap(err, "failed to connect through proxy")
+	}
+
+	return conn, nil
+}
+
+func load() *Credentials {
+	c, _ := loaded.Load().(*Credentials)
+	return c
+}
+
+// proxyForAddress returns the proxy from the environment for the address. The scheme of the request is not
+// known when dialing, so only port 80 is assumed to be plain http.
+func proxyForAddress(address string) (*url.URL, error) {
+	scheme := "https"
+	if _, port, err := net.SplitHostPort(address); err == nil && port == "80" {
+		scheme = "http"
+	}
+	return http.ProxyFromEnvironment(&http.Request{
+		URL: &url.URL{
+			Scheme: scheme,
+			Host:   address,
+		},
+	})
+}
+
+func dialProxy(ctx context.Context, network string, proxyURL *url.URL) (net.Conn, error) {
+	address := proxyURL.Host
+	if proxyURL.Port() == "" {
+		port := "80"
+		if proxyURL.Scheme == "https" {
+			port = "443"
+		}
+		address = net.JoinHostPort(proxyURL.Hostname(), port)
+	}
+
+	conn, err := hostaliases.DialContext(ctx, network, address)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to dial proxy")
+	}
+
+	if proxyURL.Scheme == "https" {
+		tlsConn := tls.Client(conn, &tls.Config{ServerName: proxyURL.Hostname()})
+		if err := tlsConn.Handshake(); err != nil {
+			conn.Close()
+			return nil, errors.Wrap(err, "failed tls handshake with proxy")
+		}
+		return tlsConn, nil
+	}
+
+	return conn, nil
+}
+
+// connectNTLM opens a tunnel to the address over the connection to the proxy. ntlm authenticates the connection
+// rather than the request, so the whole handshake happens on the same connection.
+func connectNTLM(conn net.Conn, address string, credentials Credentials) error {
+	br := bufio.NewReader(conn)
+
+	resp, err := connect(conn, br, address, "NTLM "+base64.StdEncoding.EncodeToString(ntlmNegotiateMessage()))
+	if err != nil {
+		return errors.Wrap(err, "failed to send negotiate message")
+	}
+	if resp.StatusCode == http.StatusOK {
+		return nil
+	}
+	if resp.StatusCode != http.StatusProxyAuthRequired {
+		return errors.Errorf("unexpected proxy response %s", resp.Status)
+	}
+
+	encodedChallenge := ""
+	for _, value := range resp.Header.Values("Proxy-Authenticate") {
+		if strings.HasPrefix(value, "NTLM ") {
+			encodedChallenge = strings.TrimPrefix(value, "NTLM ")
+			break
+		}
+	}
+	if encodedChallenge == "" {
+		return errors.New("proxy did not send an ntlm challenge")
+	}
+	challengeMessage, err := base64.StdEncoding.DecodeString(encodedChallenge)
+	if err != nil {
+		return errors.Wrap(err, "failed to decode challenge")
+	}
+	challenge, err := parseNTLMChallenge(challengeMessage)
+	if err != nil {
+		return errors.Wrap(err, "failed to parse challenge")
+	}
+
+	authenticateMessage, err := ntlmAuthenticateMessage(challenge, credentials.Username, credentials.Password)
+	if err != nil {
+		return errors.Wrap(err, "failed to create authenticate message")
+	}
+
+	resp, err = connect(conn, br, address, "NTLM "+base64.StdEncoding.EncodeToString(authenticateMessage))
+	if err != nil {
+		return errors.Wrap(err, "failed to send authenticate message")
+	}
+	if resp.StatusCode != http.StatusOK {
+		return errors.Errorf("proxy authentication failed: %s", resp.Status)
+	}
+
+	return nil
+}
+
+func connect(conn net.Conn, br *bufio.Reader, address string, authorization string) (*http.Response, error) {
+	req := &http.Request{
+		Method: http.MethodConnect,
+		URL:    &url.URL{Opaque: address},
+		Host:   address,
+		Header: http.Header{
+			"Proxy-Authorization": []string{authorization},
+			"Proxy-Connection":    []string{"Keep-Alive"},
+		},
+	}
+	if err := req.Write(conn); err != nil {
+		return nil, errors.Wrap(err, "failed to write request")
+	}
+
+	resp, err := http.ReadResponse(br, req)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read response")
+	}
+	if resp.StatusCode != http.StatusOK {
+		// the body must be read for the next message to be sent on the same connection
+		io.Copy(ioutil.Discard, resp.Body)
+		resp.Body.Close()
+	}
+
+	return resp, nil
+}
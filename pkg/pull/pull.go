@@ -2,6 +2,7 @@ package pull
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -34,6 +35,8 @@ import (
 )
 
 type PullOptions struct {
+	// Context is the parent of the spans of the pull, the background context if nil
+	Context                context.Context
 	HelmRepoURI            string
 	RootDir                string
 	Namespace              string
@@ -65,6 +68,8 @@ type PullOptions struct {
 	NoProxyEnvValue        string
 	ReportingInfo          *reportingtypes.ReportingInfo
 	IdentityPostgresConfig *kotsv1beta1.IdentityPostgresConfig
+	// AdditionalManifests are added to new downstreams, keyed by file name
+	AdditionalManifests map[string][]byte
 }
 
 type RewriteImageOptions struct {
@@ -74,6 +79,8 @@ type RewriteImageOptions struct {
 	Username   string
 	Password   string
 	IsReadOnly bool
+	// BandwidthLimit is the maximum rate in bytes per second to push images at, 0 is unlimited
+	BandwidthLimit int64
 }
 
 // PullApplicationMetadata will return the application metadata yaml, if one is
@@ -130,6 +137,7 @@ func Pull(upstreamURI string, pullOptions PullOptions) (string, error) {
 		CurrentCursor: pullOptions.UpdateCursor,
 		AppSlug:       pullOptions.AppSlug,
 		AppSequence:   pullOptions.AppSequence,
+		ReportWriter:  pullOptions.ReportWriter,
 		LocalRegistry: upstreamtypes.LocalRegistry{
 			Host:      pullOptions.RewriteImageOptions.Host,
 			Namespace: pullOptions.RewriteImageOptions.Namespace,
@@ -355,6 +363,7 @@ func Pull(upstreamURI string, pullOptions PullOptions) (string, error) {
 			}
 
 			writeUpstreamImageOptions := base.WriteUpstreamImageOptions{
+				Context: pullOptions.Context,
 				BaseDir: writeBaseOptions.BaseDir,
 				Log:     log,
 				SourceRegistry: registry.RegistryOptions{
@@ -409,7 +418,8 @@ func Pull(upstreamURI string, pullOptions PullOptions) (string, error) {
 					Endpoint:      replicatedRegistryInfo.Registry,
 					ProxyEndpoint: replicatedRegistryInfo.Proxy,
 				},
-				ReportWriter: pullOptions.ReportWriter,
+				ReportWriter:   pullOptions.ReportWriter,
+				BandwidthLimit: pullOptions.RewriteImageOptions.BandwidthLimit,
 				DestinationRegistry: registry.RegistryOptions{
 					Endpoint:  pullOptions.RewriteImageOptions.Host,
 					Namespace: pullOptions.RewriteImageOptions.Namespace,
@@ -539,10 +549,15 @@ func Pull(upstreamURI string, pullOptions PullOptions) (string, error) {
 		return "", errors.Wrap(err, "failed to load identity")
 	}
 
+	exposureSpec, err := upstream.LoadExposure(u.GetUpstreamDir(writeUpstreamOptions))
+	if err != nil {
+		return "", errors.Wrap(err, "failed to load exposure")
+	}
+
 	log.ActionWithSpinner("Creating midstream")
 	io.WriteString(pullOptions.ReportWriter, "Creating midstream\n")
 
-	m, err := midstream.CreateMidstream(b, images, objects, pullSecret, identitySpec, identityConfig)
+	m, err := midstream.CreateMidstream(b, images, objects, pullSecret, identitySpec, identityConfig, exposureSpec)
 	if err != nil {
 		return "", errors.Wrap(err, "failed to create midstream")
 	}
@@ -588,8 +603,9 @@ func Pull(upstreamURI string, pullOptions PullOptions) (string, error) {
 		}
 
 		writeDownstreamOptions := downstream.WriteOptions{
-			DownstreamDir: filepath.Join(b.GetOverlaysDir(writeBaseOptions), "downstreams", downstreamName),
-			MidstreamDir:  writeMidstreamOptions.MidstreamDir,
+			DownstreamDir:       filepath.Join(b.GetOverlaysDir(writeBaseOptions), "downstreams", downstreamName),
+			MidstreamDir:        writeMidstreamOptions.MidstreamDir,
+			AdditionalManifests: pullOptions.AdditionalManifests,
 		}
 
 		if err := d.WriteDownstream(writeDownstreamOptions); err != nil {
@@ -707,16 +723,70 @@ func GetAppMetadataFromAirgap(airgapArchive string) ([]byte, error) {
 		return nil, errors.Wrap(err, "failed to read kots kinds")
 	}
 
+	application := kotsKinds.KotsApplication.DeepCopy()
+	if application.Spec.ReleaseNotes == "" {
+		// the release notes of the channel release are in the airgap metadata of the bundle
+		airgap, err := getAirgapMetaFromAirgap(airgapArchive)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to get airgap metadata")
+		}
+		if airgap != nil {
+			application.Spec.ReleaseNotes = airgap.Spec.ReleaseNotes
+		}
+	}
+
 	s := k8sjson.NewYAMLSerializer(k8sjson.DefaultMetaFactory, scheme.Scheme, scheme.Scheme)
 
 	var b bytes.Buffer
-	if err := s.Encode(&kotsKinds.KotsApplication, &b); err != nil {
-		errors.Wrap(err, "failed to encode metadata")
+	if err := s.Encode(application, &b); err != nil {
+		return nil, errors.Wrap(err, "failed to encode metadata")
 	}
 
 	return b.Bytes(), nil
 }
 
+// GetAppMetadataFromFile returns the application metadata from a file with an Application kind, or from an airgap
+// bundle, so that the admin console is branded without access to the upstream
+func GetAppMetadataFromFile(filename string) ([]byte, error) {
+	contents, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read file")
+	}
+
+	decode := scheme.Codecs.UniversalDeserializer().Decode
+	_, gvk, err := decode(contents, nil, nil)
+	if err == nil {
+		if gvk.Group != "kots.io" || gvk.Version != "v1beta1" || gvk.Kind != "Application" {
+			return nil, errors.Errorf("expected an Application, found %s", gvk.String())
+		}
+		return contents, nil
+	}
+
+	return GetAppMetadataFromAirgap(filename)
+}
+
+// getAirgapMetaFromAirgap returns the airgap.yaml of the bundle, or nil if the bundle does not have one
+func getAirgapMetaFromAirgap(airgapArchive string) (*kotsv1beta1.Airgap, error) {
+	contents, err := archives.GetFileFromAirgap("airgap.yaml", airgapArchive)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to extract airgap.yaml")
+	}
+	if len(contents) == 0 {
+		return nil, nil
+	}
+
+	decode := scheme.Codecs.UniversalDeserializer().Decode
+	obj, gvk, err := decode(contents, nil, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to decode airgap.yaml")
+	}
+	if gvk.Group != "kots.io" || gvk.Version != "v1beta1" || gvk.Kind != "Airgap" {
+		return nil, errors.New("airgap.yaml is not an airgap spec")
+	}
+
+	return obj.(*kotsv1beta1.Airgap), nil
+}
+
 func parseInstallationFromFile(filename string) (*kotsv1beta1.Installation, error) {
 	contents, err := ioutil.ReadFile(filename)
 	if err != nil {
@@ -59,3 +59,54 @@ spec:
 	// require.IsType(t, util.ActionableError{}, errors.Cause(err))
 	// require.True(t, strings.Contains(err.Error(), "expired"), "error must contain expired")
 }
+
+func Test_GetAppMetadataFromFile(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		wantErr bool
+	}{
+		{
+			name: "application",
+			data: `apiVersion: kots.io/v1beta1
+kind: Application
+metadata:
+  name: my-app
+spec:
+  title: My App
+  icon: https://example.com/icon.png
+`,
+		},
+		{
+			name: "not an application",
+			data: `apiVersion: kots.io/v1beta1
+kind: Config
+metadata:
+  name: my-app
+spec:
+  groups: []
+`,
+			wantErr: true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			req := require.New(t)
+
+			f, err := ioutil.TempFile("", "app-metadata")
+			req.NoError(err)
+			defer os.RemoveAll(f.Name())
+			_, err = f.Write([]byte(test.data))
+			req.NoError(err)
+			req.NoError(f.Close())
+
+			got, err := GetAppMetadataFromFile(f.Name())
+			if test.wantErr {
+				req.Error(err)
+				return
+			}
+			req.NoError(err)
+			req.Equal(test.data, string(got))
+		})
+	}
+}
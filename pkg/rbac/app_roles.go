@@ -0,0 +1,84 @@
+package rbac
+
+import (
+	"strings"
+
+	"github.com/replicatedhq/kots/pkg/rbac/types"
+)
+
+// appRoleSeparator separates the role id and the app slug of an app role. App roles are session roles that grant a
+// role for a single app, so that teams sharing an admin console only see and manage their own apps.
+const appRoleSeparator = "@app."
+
+// AppRoleID returns the session role that grants the role for the app only
+func AppRoleID(roleID string, appSlug string) string {
+	return roleID + appRoleSeparator + appSlug
+}
+
+func parseAppRoleID(id string) (string, string, bool) {
+	parts := strings.SplitN(id, appRoleSeparator, 2)
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", "", false
+	}
+	return parts[0], parts[1], true
+}
+
+// appRoles returns the roles scoped to their app for the app roles in the session roles
+func appRoles(roles []types.Role, sessionRoles []string) []types.Role {
+	scoped := []types.Role{}
+	for _, sessionRole := range sessionRoles {
+		roleID, appSlug, ok := parseAppRoleID(sessionRole)
+		if !ok {
+			continue
+		}
+		for _, role := range roles {
+			if role.ID == roleID {
+				scoped = append(scoped, scopeRoleToApp(role, appSlug))
+				break
+			}
+		}
+	}
+	return scoped
+}
+
+// scopeRoleToApp limits the policies of the role to the resources of the app. Policies for resources outside of apps,
+// like snapshots or the cluster, are dropped. Reading the list of apps is allowed, it only includes the apps that can
+// be read.
+func scopeRoleToApp(role types.Role, appSlug string) types.Role {
+	prefix := "app." + appSlug
+
+	scoped := types.Role{
+		ID:          AppRoleID(role.ID, appSlug),
+		Name:        role.Name,
+		Description: role.Description,
+		Allow: []types.Policy{
+			{Name: "List Apps", Action: "read", Resource: "app."},
+		},
+		Deny: []types.Policy{},
+	}
+	for _, policy := range role.Allow {
+		scoped.Allow = append(scoped.Allow, scopePolicyToApp(policy, prefix)...)
+	}
+	for _, policy := range role.Deny {
+		scoped.Deny = append(scoped.Deny, scopePolicyToApp(policy, prefix)...)
+	}
+	return scoped
+}
+
+func scopePolicyToApp(policy types.Policy, prefix string) []types.Policy {
+	withResource := func(resource string) types.Policy {
+		p := policy
+		p.Resource = resource
+		return p
+	}
+
+	switch {
+	case policy.Resource == "**":
+		return []types.Policy{withResource(prefix), withResource(prefix + ".**")}
+	case strings.HasPrefix(policy.Resource, "**."):
+		return []types.Policy{withResource(prefix + "." + policy.Resource)}
+	case policy.Resource == "app.*" || strings.HasPrefix(policy.Resource, "app.*."):
+		return []types.Policy{withResource(prefix + strings.TrimPrefix(policy.Resource, "app.*"))}
+	}
+	return nil
+}
@@ -6,6 +6,8 @@ import (
 
 const (
 	ClusterAdminRoleID = "cluster-admin"
+	ApproverRoleID     = "approver"
+	ReadOnlyRoleID     = "read-only"
 )
 
 var (
@@ -29,6 +31,39 @@ var (
 		},
 		Deny: []types.Policy{
 			{Action: "**", Resource: "app.*.downstream.filetree."},
+			{Action: "**", Resource: "diagnostics."},
+			{Action: "**", Resource: "temporaryaccess."},
+			PolicyDenyDecryptedConfig,
+		},
+	}
+
+	ApproverRole = types.Role{
+		ID:          ApproverRoleID,
+		Name:        "Approver",
+		Description: "Read access to all resources, and approves deploys of apps that require deploy approval",
+		Allow: []types.Policy{
+			PolicyReadonly,
+			{Action: "**", Resource: "app.*.deployapproval."},
+		},
+		Deny: []types.Policy{
+			PolicyDenyDecryptedConfig,
+		},
+	}
+
+	// ReadOnlyRole is the role of temporary access links, it reads what support reads but can't collect support
+	// bundles or run preflight checks
+	ReadOnlyRole = types.Role{
+		ID:          ReadOnlyRoleID,
+		Name:        "Read Only",
+		Description: "Read access to all resources except the rendered files and decrypted config values",
+		Allow: []types.Policy{
+			PolicyReadonly,
+		},
+		Deny: []types.Policy{
+			{Action: "**", Resource: "app.*.downstream.filetree."},
+			{Action: "**", Resource: "diagnostics."},
+			{Action: "**", Resource: "temporaryaccess."},
+			PolicyDenyDecryptedConfig,
 		},
 	}
 
@@ -43,11 +78,21 @@ var (
 		Action:   "read",
 		Resource: "**",
 	}
+
+	// PolicyDenyDecryptedConfig denies reading the decrypted values of password config items, which read only
+	// access would otherwise allow
+	PolicyDenyDecryptedConfig = types.Policy{
+		Name:     "Deny Decrypted Config",
+		Action:   "**",
+		Resource: "app.*.downstream.config.decrypted.",
+	}
 )
 
 func DefaultRoles() []types.Role {
 	return []types.Role{
 		ClusterAdminRole,
 		SupportRole,
+		ApproverRole,
+		ReadOnlyRole,
 	}
 }
@@ -66,7 +66,10 @@ func init() {
 	}
 }
 
+// CheckAccess returns true if one of the session roles allows the action on the resource. Session roles are role ids,
+// or app roles from AppRoleID that grant a role for a single app.
 func CheckAccess(ctx context.Context, roles []types.Role, action, resource string, sessionRoles []string) (bool, error) {
+	roles = append(roles[:len(roles):len(roles)], appRoles(roles, sessionRoles)...)
 	for _, role := range roles {
 		i := map[string]interface{}{
 			"action":            action,
@@ -281,6 +281,123 @@ func TestCheckAccess(t *testing.T) {
 			},
 			want: true,
 		},
+		{
+			name: "decrypted config allow",
+			args: args{
+				action:       "read",
+				resource:     "app.my-app.downstream.config.decrypted.",
+				sessionRoles: []string{ClusterAdminRole.ID},
+			},
+			want: true,
+		},
+		{
+			name: "decrypted config deny",
+			args: args{
+				action:       "read",
+				resource:     "app.my-app.downstream.config.decrypted.",
+				sessionRoles: []string{SupportRole.ID, ApproverRole.ID},
+			},
+			want: false,
+		},
+		{
+			name: "app role write to its app",
+			args: args{
+				action:       "write",
+				resource:     "app.my-app.downstream.config.",
+				sessionRoles: []string{AppRoleID(ClusterAdminRole.ID, "my-app")},
+			},
+			want: true,
+		},
+		{
+			name: "app role read of its app",
+			args: args{
+				action:       "read",
+				resource:     "app.my-app",
+				sessionRoles: []string{AppRoleID(SupportRole.ID, "my-app")},
+			},
+			want: true,
+		},
+		{
+			name: "app role read of another app",
+			args: args{
+				action:       "read",
+				resource:     "app.other-app",
+				sessionRoles: []string{AppRoleID(ClusterAdminRole.ID, "my-app")},
+			},
+			want: false,
+		},
+		{
+			name: "app role list apps",
+			args: args{
+				action:       "read",
+				resource:     "app.",
+				sessionRoles: []string{AppRoleID(SupportRole.ID, "my-app")},
+			},
+			want: true,
+		},
+		{
+			name: "app role create app",
+			args: args{
+				action:       "write",
+				resource:     "app.",
+				sessionRoles: []string{AppRoleID(ClusterAdminRole.ID, "my-app")},
+			},
+			want: false,
+		},
+		{
+			name: "app role cluster wide resource",
+			args: args{
+				action:       "read",
+				resource:     "backup.",
+				sessionRoles: []string{AppRoleID(ClusterAdminRole.ID, "my-app")},
+			},
+			want: false,
+		},
+		{
+			name: "app role keeps the denies of the role",
+			args: args{
+				action:       "read",
+				resource:     "app.my-app.downstream.filetree.",
+				sessionRoles: []string{AppRoleID(SupportRole.ID, "my-app")},
+			},
+			want: false,
+		},
+		{
+			name: "read-only role read",
+			args: args{
+				action:       "read",
+				resource:     "app.my-app.downstream.logs.",
+				sessionRoles: []string{ReadOnlyRole.ID},
+			},
+			want: true,
+		},
+		{
+			name: "read-only role supportbundle",
+			args: args{
+				action:       "write",
+				resource:     "app.my-app.supportbundle.",
+				sessionRoles: []string{ReadOnlyRole.ID},
+			},
+			want: false,
+		},
+		{
+			name: "read-only role temporary access",
+			args: args{
+				action:       "read",
+				resource:     "temporaryaccess.",
+				sessionRoles: []string{ReadOnlyRole.ID},
+			},
+			want: false,
+		},
+		{
+			name: "app role of an unknown role",
+			args: args{
+				action:       "read",
+				resource:     "app.my-app",
+				sessionRoles: []string{AppRoleID("unknown", "my-app")},
+			},
+			want: false,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -0,0 +1,466 @@
+package dryrun
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/docker/distribution/reference"
+	"github.com/pkg/errors"
+	kotsv1beta1 "github.com/replicatedhq/kots/kotskinds/apis/kots/v1beta1"
+	"github.com/replicatedhq/kots/pkg/base"
+	kotsconfig "github.com/replicatedhq/kots/pkg/config"
+	"github.com/replicatedhq/kots/pkg/image"
+	"github.com/replicatedhq/kots/pkg/kotsadmconfig"
+	"github.com/replicatedhq/kots/pkg/kotsutil"
+	"github.com/replicatedhq/kots/pkg/logger"
+	"github.com/replicatedhq/kots/pkg/pull"
+	"github.com/replicatedhq/kots/pkg/template"
+	upstreamtypes "github.com/replicatedhq/kots/pkg/upstream/types"
+	"k8s.io/client-go/kubernetes/scheme"
+)
+
+const (
+	CheckLicense   = "license"
+	CheckConfig    = "config"
+	CheckRender    = "render"
+	CheckPreflight = "preflight"
+	CheckImages    = "images"
+)
+
+var checkNames = []string{CheckLicense, CheckConfig, CheckRender, CheckPreflight, CheckImages}
+
+// configItemTypes are the config item types the admin console can display
+var configItemTypes = map[string]struct{}{
+	"":            {},
+	"bool":        {},
+	"file":        {},
+	"heading":     {},
+	"label":       {},
+	"large_file":  {},
+	"password":    {},
+	"select":      {},
+	"select_many": {},
+	"select_one":  {},
+	"text":        {},
+	"textarea":    {},
+}
+
+type Options struct {
+	// AppDir is the local directory with the release manifests
+	AppDir string
+	// LicenseFile is the customer license to simulate the install with
+	LicenseFile string
+	// ConfigValuesFile is an optional ConfigValues file, config defaults are used when it's not set
+	ConfigValuesFile string
+	Namespace        string
+}
+
+// Check is the outcome of one step of the dry run
+type Check struct {
+	Name   string   `json:"name"`
+	Errors []string `json:"errors,omitempty"`
+}
+
+func (c Check) Passed() bool {
+	return len(c.Errors) == 0
+}
+
+// Result is the outcome of a release dry run
+type Result struct {
+	Checks []Check `json:"checks"`
+	// Images are the images the rendered release references
+	Images []string `json:"images"`
+	// Files are the rendered files of the release
+	Files    []string `json:"files"`
+	Warnings []string `json:"warnings,omitempty"`
+}
+
+func (r Result) Passed() bool {
+	for _, check := range r.Checks {
+		if !check.Passed() {
+			return false
+		}
+	}
+	return true
+}
+
+type dryRunner struct {
+	checks   map[string]*Check
+	warnings []string
+}
+
+func (d *dryRunner) fail(check string, format string, args ...interface{}) {
+	d.checks[check].Errors = append(d.checks[check].Errors, errors.Errorf(format, args...).Error())
+}
+
+func (d *dryRunner) warn(format string, args ...interface{}) {
+	d.warnings = append(d.warnings, errors.Errorf(format, args...).Error())
+}
+
+// DryRun simulates what a customer install of the release in the app dir does with the license, without a cluster
+// or network access: the license is verified, the config is validated and templated, the release is rendered, the
+// preflight and support bundle specs are parsed and the images of the rendered release are listed. An error is
+// returned only if the dry run can't be run, failed checks are in the result.
+func DryRun(opts Options) (*Result, error) {
+	d := &dryRunner{
+		checks: map[string]*Check{},
+	}
+	for _, name := range checkNames {
+		d.checks[name] = &Check{Name: name}
+	}
+
+	license := d.loadLicense(opts.LicenseFile)
+
+	var configValues *kotsv1beta1.ConfigValues
+	if opts.ConfigValuesFile != "" {
+		values, err := kotsutil.LoadConfigValuesFromFile(opts.ConfigValuesFile)
+		if err != nil {
+			d.fail(CheckConfig, "failed to load config values: %v", err)
+		} else {
+			configValues = values
+		}
+	}
+
+	kotsKinds, err := kotsutil.LoadKotsKindsFromPath(opts.AppDir)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to load kots kinds from app dir")
+	}
+	if kotsKinds.Config != nil {
+		d.checkConfig(kotsKinds.Config, configValues, license, kotsKinds.IdentityConfig)
+	}
+
+	renderedDir, err := ioutil.TempDir("", "kots-release-dry-run")
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create temp dir")
+	}
+	defer os.RemoveAll(renderedDir)
+
+	files, err := d.render(opts, license, configValues, renderedDir)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to render release")
+	}
+
+	images := []string{}
+	if d.checks[CheckRender].Passed() {
+		d.checkPreflight(renderedDir)
+
+		images, err = d.listImages(renderedDir)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to list images")
+		}
+	} else {
+		d.warn("preflights and images were not checked because the release failed to render")
+	}
+
+	result := &Result{
+		Images:   images,
+		Files:    files,
+		Warnings: d.warnings,
+	}
+	for _, name := range checkNames {
+		result.Checks = append(result.Checks, *d.checks[name])
+	}
+
+	return result, nil
+}
+
+// loadLicense verifies the license signature and expiration. An unverified license is still returned when it can
+// be decoded so that the rest of the release can be checked with it.
+func (d *dryRunner) loadLicense(licenseFile string) *kotsv1beta1.License {
+	license, err := pull.ParseLicenseFromFile(licenseFile)
+	if err != nil {
+		d.fail(CheckLicense, "license is invalid: %v", err)
+
+		license, err = kotsutil.LoadLicenseFromPath(licenseFile)
+		if err != nil {
+			return nil
+		}
+	}
+
+	expired, err := pull.LicenseIsExpired(license)
+	if err != nil {
+		d.fail(CheckLicense, "failed to check license expiration: %v", err)
+	} else if expired {
+		d.fail(CheckLicense, "license is expired")
+	}
+
+	return license
+}
+
+// checkConfig validates the config schema and that the config renders with the values and the license, and that
+// every required item that the customer can see has a value
+func (d *dryRunner) checkConfig(config *kotsv1beta1.Config, configValues *kotsv1beta1.ConfigValues, license *kotsv1beta1.License, identityConfig *kotsv1beta1.IdentityConfig) {
+	for _, problem := range validateConfigSchema(config) {
+		d.fail(CheckConfig, "%s", problem)
+	}
+
+	values := map[string]template.ItemValue{}
+	if configValues != nil {
+		for name, value := range configValues.Spec.Values {
+			values[name] = template.ItemValue{
+				Value:   value.Value,
+				Default: value.Default,
+			}
+		}
+	}
+
+	renderedConfig, err := kotsconfig.TemplateConfigObjects(config.DeepCopy(), values, license, template.LocalRegistry{}, &template.VersionInfo{}, identityConfig)
+	if err != nil {
+		d.fail(CheckConfig, "config failed to render: %v", err)
+		return
+	}
+
+	for _, group := range renderedConfig.Spec.Groups {
+		if group.When == "false" {
+			continue
+		}
+		for _, item := range group.Items {
+			if kotsadmconfig.IsRequiredItem(item) && kotsadmconfig.IsUnsetItem(item) {
+				d.fail(CheckConfig, "required item %s has no value", item.Name)
+			}
+		}
+	}
+}
+
+// validateConfigSchema returns the problems in the config that the admin console can't display or that make
+// item values ambiguous
+func validateConfigSchema(config *kotsv1beta1.Config) []string {
+	problems := []string{}
+
+	groupNames := map[string]bool{}
+	itemNames := map[string]bool{}
+	for _, group := range config.Spec.Groups {
+		if group.Name == "" {
+			problems = append(problems, "group with title "+group.Title+" has no name")
+		} else if groupNames[group.Name] {
+			problems = append(problems, "group "+group.Name+" is defined more than once")
+		}
+		groupNames[group.Name] = true
+
+		for _, item := range group.Items {
+			if item.Name == "" {
+				problems = append(problems, "item with title "+item.Title+" in group "+group.Name+" has no name")
+				continue
+			}
+			if itemNames[item.Name] {
+				problems = append(problems, "item "+item.Name+" is defined more than once")
+			}
+			itemNames[item.Name] = true
+
+			if _, ok := configItemTypes[item.Type]; !ok {
+				problems = append(problems, "item "+item.Name+" has unknown type "+item.Type)
+			}
+
+			if item.Type != "select_one" && item.Type != "select_many" {
+				continue
+			}
+			if len(item.Items) == 0 {
+				problems = append(problems, "item "+item.Name+" of type "+item.Type+" has no options")
+			}
+			for _, child := range item.Items {
+				if itemNames[child.Name] {
+					problems = append(problems, "option "+child.Name+" of item "+item.Name+" is defined more than once")
+				}
+				itemNames[child.Name] = true
+			}
+		}
+	}
+
+	return problems
+}
+
+// render renders the release the way an install does and writes the result to renderedDir, returning the rendered
+// file paths
+func (d *dryRunner) render(opts Options, license *kotsv1beta1.License, configValues *kotsv1beta1.ConfigValues, renderedDir string) ([]string, error) {
+	u, err := upstreamFromAppDir(opts.AppDir)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read app dir")
+	}
+
+	// the license and config values are added to the release the same way an install writes them to userdata
+	if license != nil {
+		content, err := ioutil.ReadFile(opts.LicenseFile)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to read license file")
+		}
+		u.Files = append(u.Files, upstreamtypes.UpstreamFile{Path: "userdata/license.yaml", Content: content})
+	}
+	if configValues != nil {
+		content, err := ioutil.ReadFile(opts.ConfigValuesFile)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to read config values file")
+		}
+		u.Files = append(u.Files, upstreamtypes.UpstreamFile{Path: "userdata/config.yaml", Content: content})
+	}
+
+	log := logger.NewCLILogger()
+	log.Silence()
+
+	renderOptions := base.RenderOptions{
+		SplitMultiDocYAML: true,
+		Namespace:         opts.Namespace,
+		ExcludeKotsKinds:  false,
+		Log:               log,
+	}
+	if license != nil {
+		renderOptions.AppSlug = license.Spec.AppSlug
+	}
+
+	b, err := base.RenderUpstream(u, &renderOptions)
+	if err != nil {
+		d.fail(CheckRender, "%v", err)
+		return []string{}, nil
+	}
+
+	for _, errorFile := range b.ListErrorFiles() {
+		d.fail(CheckRender, "%s: %v", errorFile.Path, errorFile.Error)
+	}
+
+	writeOptions := base.WriteOptions{
+		BaseDir:    filepath.Join(renderedDir, "base"),
+		SkippedDir: filepath.Join(renderedDir, "skippedFiles"),
+		Overwrite:  true,
+	}
+	if err := b.WriteBase(writeOptions); err != nil {
+		d.fail(CheckRender, "failed to write rendered release: %v", err)
+		return []string{}, nil
+	}
+
+	files := []string{}
+	err = filepath.Walk(writeOptions.BaseDir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() || info.Name() == "kustomization.yaml" {
+			return nil
+		}
+		rel, err := filepath.Rel(writeOptions.BaseDir, path)
+		if err != nil {
+			return err
+		}
+		files = append(files, rel)
+		return nil
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list rendered files")
+	}
+	sort.Strings(files)
+
+	return files, nil
+}
+
+func upstreamFromAppDir(appDir string) (*upstreamtypes.Upstream, error) {
+	u := &upstreamtypes.Upstream{
+		URI:  appDir,
+		Name: filepath.Base(appDir),
+		Type: "local",
+	}
+
+	err := filepath.Walk(appDir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			return nil
+		}
+
+		content, err := ioutil.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(appDir, path)
+		if err != nil {
+			return err
+		}
+
+		u.Files = append(u.Files, upstreamtypes.UpstreamFile{Path: rel, Content: content})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return u, nil
+}
+
+// checkPreflight checks that the rendered preflight and support bundle specs parse and have something to run
+func (d *dryRunner) checkPreflight(renderedDir string) {
+	found := false
+	err := filepath.Walk(renderedDir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			return nil
+		}
+
+		content, err := ioutil.ReadFile(path)
+		if err != nil {
+			return err
+		}
+
+		decode := scheme.Codecs.UniversalDeserializer().Decode
+		_, gvk, err := decode(content, nil, nil)
+		if err != nil || !strings.HasPrefix(gvk.Group, "troubleshoot.") {
+			return nil
+		}
+
+		switch gvk.Kind {
+		case "Preflight":
+			found = true
+			preflight, err := kotsutil.LoadPreflightFromContents(content)
+			if err != nil {
+				d.fail(CheckPreflight, "preflight %s failed to parse: %v", filepath.Base(path), err)
+			} else if len(preflight.Spec.Analyzers) == 0 {
+				d.fail(CheckPreflight, "preflight %s has no analyzers", preflight.Name)
+			}
+		case "SupportBundle":
+			if _, err := kotsutil.LoadSupportBundleFromContents(content); err != nil {
+				d.fail(CheckPreflight, "support bundle %s failed to parse: %v", filepath.Base(path), err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		d.fail(CheckPreflight, "failed to read rendered release: %v", err)
+		return
+	}
+
+	if !found {
+		d.warn("the release has no preflight checks")
+	}
+}
+
+// listImages lists the images the rendered release references and checks that they are valid image references
+func (d *dryRunner) listImages(renderedDir string) ([]string, error) {
+	images, err := image.ListImagesInDir(renderedDir)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list images in rendered release")
+	}
+
+	kotsKinds, err := kotsutil.LoadKotsKindsFromPath(renderedDir)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to load rendered kots kinds")
+	}
+	images = append(images, kotsKinds.KotsApplication.Spec.AdditionalImages...)
+
+	result := []string{}
+	seen := map[string]bool{}
+	for _, img := range images {
+		if seen[img] {
+			continue
+		}
+		seen[img] = true
+		result = append(result, img)
+
+		if _, err := reference.ParseNormalizedNamed(img); err != nil {
+			d.fail(CheckImages, "image %s is invalid: %v", img, err)
+		}
+	}
+	sort.Strings(result)
+
+	return result, nil
+}
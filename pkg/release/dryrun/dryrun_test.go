@@ -0,0 +1,78 @@
+package dryrun
+
+import (
+	"testing"
+
+	kotsv1beta1 "github.com/replicatedhq/kots/kotskinds/apis/kots/v1beta1"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_validateConfigSchema(t *testing.T) {
+	tests := []struct {
+		name   string
+		groups []kotsv1beta1.ConfigGroup
+		want   []string
+	}{
+		{
+			name: "valid",
+			groups: []kotsv1beta1.ConfigGroup{
+				{
+					Name: "database",
+					Items: []kotsv1beta1.ConfigItem{
+						{Name: "db_host", Type: "text"},
+						{Name: "db_type", Type: "select_one", Items: []kotsv1beta1.ConfigChildItem{{Name: "embedded"}, {Name: "external"}}},
+					},
+				},
+			},
+			want: []string{},
+		},
+		{
+			name: "duplicate names",
+			groups: []kotsv1beta1.ConfigGroup{
+				{Name: "database", Items: []kotsv1beta1.ConfigItem{{Name: "db_host", Type: "text"}}},
+				{Name: "database", Items: []kotsv1beta1.ConfigItem{{Name: "db_host", Type: "text"}}},
+			},
+			want: []string{
+				"group database is defined more than once",
+				"item db_host is defined more than once",
+			},
+		},
+		{
+			name: "unknown type and missing options",
+			groups: []kotsv1beta1.ConfigGroup{
+				{
+					Name: "database",
+					Items: []kotsv1beta1.ConfigItem{
+						{Name: "db_port", Type: "number"},
+						{Name: "db_type", Type: "select_one"},
+						{Title: "Untitled", Type: "text"},
+					},
+				},
+			},
+			want: []string{
+				"item db_port has unknown type number",
+				"item db_type of type select_one has no options",
+				"item with title Untitled in group database has no name",
+			},
+		},
+		{
+			name: "option shadows item",
+			groups: []kotsv1beta1.ConfigGroup{
+				{
+					Name: "database",
+					Items: []kotsv1beta1.ConfigItem{
+						{Name: "embedded", Type: "bool"},
+						{Name: "db_type", Type: "select_one", Items: []kotsv1beta1.ConfigChildItem{{Name: "embedded"}}},
+					},
+				},
+			},
+			want: []string{"option embedded of item db_type is defined more than once"},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			config := &kotsv1beta1.Config{Spec: kotsv1beta1.ConfigSpec{Groups: test.groups}}
+			require.Equal(t, test.want, validateConfigSchema(config))
+		})
+	}
+}
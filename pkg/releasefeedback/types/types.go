@@ -0,0 +1,52 @@
+package types
+
+import (
+	"time"
+)
+
+// Consent is the customer's consent to report the rollout of each release back to the vendor. Nothing is reported
+// for an app without it.
+type Consent struct {
+	Enabled bool `json:"enabled"`
+	// IncludePreflightDetails reports the titles and messages of the preflight checks that did not pass, only the
+	// number of checks of each outcome is reported otherwise
+	IncludePreflightDetails bool      `json:"includePreflightDetails"`
+	UpdatedBy               string    `json:"updatedBy,omitempty"`
+	UpdatedAt               time.Time `json:"updatedAt"`
+}
+
+// Report is the rollout of a release to one install, as it is reported to the vendor
+type Report struct {
+	AppID     string `json:"appId"`
+	ClusterID string `json:"clusterId"`
+	Sequence  int64  `json:"sequence"`
+	// VersionLabel, ChannelID and ChannelSequence identify the release of the vendor
+	VersionLabel    string `json:"versionLabel"`
+	ChannelID       string `json:"channelId,omitempty"`
+	ChannelSequence string `json:"channelSequence,omitempty"`
+	// DeployStatus is the status of the deploy of the version, such as deployed or failed
+	DeployStatus string `json:"deployStatus"`
+	// AppStatus is the state of the app once it was ready, or when waiting for it timed out
+	AppStatus      string            `json:"appStatus"`
+	SkipPreflights bool              `json:"skipPreflights"`
+	Preflights     *PreflightSummary `json:"preflights,omitempty"`
+	KotsVersion    string            `json:"kotsVersion"`
+	ReportedAt     time.Time         `json:"reportedAt"`
+}
+
+// PreflightSummary is the outcome of the preflight checks of a release
+type PreflightSummary struct {
+	// State is one of pass, warn or fail
+	State string `json:"state"`
+	Pass  int    `json:"pass"`
+	Warn  int    `json:"warn"`
+	Fail  int    `json:"fail"`
+	// Checks are the checks that did not pass, only if the customer consented to report them
+	Checks []PreflightCheck `json:"checks,omitempty"`
+}
+
+type PreflightCheck struct {
+	Title   string `json:"title"`
+	Outcome string `json:"outcome"`
+	Message string `json:"message"`
+}
@@ -0,0 +1,135 @@
+package remoteinstall
+
+import (
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	fleetreporttypes "github.com/replicatedhq/kots/pkg/fleetreport/types"
+	"github.com/replicatedhq/kots/pkg/logger"
+	"github.com/replicatedhq/kots/pkg/remoteinstall/types"
+	"github.com/replicatedhq/kots/pkg/store"
+)
+
+const (
+	// RefreshInterval is how often the reports of the remote installations are fetched
+	RefreshInterval = 5 * time.Minute
+
+	fetchTimeout = 30 * time.Second
+)
+
+func Start() error {
+	logger.Debug("starting remote install refresh loop")
+
+	startLoop(refreshLoop, RefreshInterval)
+
+	return nil
+}
+
+func startLoop(fn func(), interval time.Duration) {
+	go func() {
+		for {
+			fn()
+			time.Sleep(interval)
+		}
+	}()
+}
+
+func refreshLoop() {
+	remoteInstalls, err := store.GetStore().ListRemoteInstalls()
+	if err != nil {
+		logger.Error(errors.Wrap(err, "failed to list remote installs"))
+		return
+	}
+
+	for _, remoteInstall := range remoteInstalls {
+		if err := Refresh(remoteInstall); err != nil {
+			logger.Error(errors.Wrapf(err, "failed to refresh remote install %s", remoteInstall.ID))
+		}
+	}
+}
+
+// Refresh fetches the fleet report of the remote installation and stores it. A failed fetch is stored as the last
+// error of the remote installation, the returned error is only for failures to store the result.
+func Refresh(remoteInstall types.RemoteInstall) error {
+	report, err := FetchReport(remoteInstall)
+	if err != nil {
+		logger.Infof("Failed to fetch report of remote install %s: %v", remoteInstall.Name, err)
+		if err := store.GetStore().SetRemoteInstallError(remoteInstall.ID, err.Error()); err != nil {
+			return errors.Wrap(err, "failed to set remote install error")
+		}
+		return nil
+	}
+
+	if err := store.GetStore().SetRemoteInstallReport(remoteInstall.ID, report, time.Now()); err != nil {
+		return errors.Wrap(err, "failed to set remote install report")
+	}
+
+	return nil
+}
+
+// FetchReport gets the fleet report of the remote installation with its token
+func FetchReport(remoteInstall types.RemoteInstall) (*fleetreporttypes.Report, error) {
+	req, err := http.NewRequest("GET", fmt.Sprintf("%s/api/v1/fleet-report", remoteInstall.URL), nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create request")
+	}
+	req.Header.Set("Authorization", remoteInstall.Token)
+	req.Header.Set("Accept", "application/json")
+
+	client := &http.Client{
+		Timeout: fetchTimeout,
+		Transport: &http.Transport{
+			Proxy: http.ProxyFromEnvironment,
+			TLSClientConfig: &tls.Config{
+				InsecureSkipVerify: remoteInstall.InsecureSkipTLSVerify,
+			},
+		},
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to execute request")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden {
+		return nil, errors.Errorf("the token was rejected by the remote install with status %d", resp.StatusCode)
+	}
+	if resp.StatusCode != http.StatusOK {
+		body, _ := ioutil.ReadAll(resp.Body)
+		return nil, errors.Errorf("unexpected status code %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+
+	report := fleetreporttypes.Report{}
+	if err := json.NewDecoder(resp.Body).Decode(&report); err != nil {
+		return nil, errors.Wrap(err, "failed to decode report")
+	}
+
+	return &report, nil
+}
+
+// NormalizeURL validates the address of the admin console of a remote installation and removes its trailing slash
+func NormalizeURL(rawURL string) (string, error) {
+	u, err := url.Parse(strings.TrimSpace(rawURL))
+	if err != nil {
+		return "", errors.Wrap(err, "failed to parse url")
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return "", errors.New("url must start with http:// or https://")
+	}
+	if u.Host == "" {
+		return "", errors.New("url must include a host")
+	}
+	if u.RawQuery != "" || u.Fragment != "" {
+		return "", errors.New("url must not include a query or fragment")
+	}
+
+	return strings.TrimRight(u.String(), "/"), nil
+}
@@ -0,0 +1,79 @@
+package remoteinstall
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/replicatedhq/kots/pkg/remoteinstall/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalizeURL(t *testing.T) {
+	tests := []struct {
+		name    string
+		rawURL  string
+		want    string
+		wantErr bool
+	}{
+		{
+			name:   "trailing slash",
+			rawURL: "https://kotsadm.example.com:8800/",
+			want:   "https://kotsadm.example.com:8800",
+		},
+		{
+			name:   "path prefix",
+			rawURL: " http://proxy.example.com/customer-a ",
+			want:   "http://proxy.example.com/customer-a",
+		},
+		{
+			name:    "no scheme",
+			rawURL:  "kotsadm.example.com:8800",
+			wantErr: true,
+		},
+		{
+			name:    "query",
+			rawURL:  "https://kotsadm.example.com?token=abc",
+			wantErr: true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			req := require.New(t)
+
+			got, err := NormalizeURL(test.rawURL)
+			if test.wantErr {
+				req.Error(err)
+				return
+			}
+			req.NoError(err)
+			req.Equal(test.want, got)
+		})
+	}
+}
+
+func TestFetchReport(t *testing.T) {
+	req := require.New(t)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/fleet-report" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		if r.Header.Get("Authorization") != "Kots abc" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Write([]byte(`{"clusterId":"cluster-1","apps":[{"slug":"my-app","state":"ready"}]}`))
+	}))
+	defer server.Close()
+
+	report, err := FetchReport(types.RemoteInstall{URL: server.URL, Token: "Kots abc"})
+	req.NoError(err)
+	req.Equal("cluster-1", report.ClusterID)
+	req.Len(report.Apps, 1)
+	req.Equal("my-app", report.Apps[0].Slug)
+
+	_, err = FetchReport(types.RemoteInstall{URL: server.URL, Token: "Kots wrong"})
+	req.EqualError(err, "the token was rejected by the remote install with status 401")
+}
@@ -0,0 +1,26 @@
+package types
+
+import (
+	"time"
+
+	fleetreporttypes "github.com/replicatedhq/kots/pkg/fleetreport/types"
+)
+
+// RemoteInstall is another kotsadm installation registered with this one, whose apps are shown read-only in the
+// console of this installation
+type RemoteInstall struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	// URL is the address of the admin console of the remote installation
+	URL string `json:"url"`
+	// Token is sent in the authorization header of the requests to the remote installation, it is never returned
+	Token string `json:"-"`
+	// InsecureSkipTLSVerify allows remote installations that serve the console with a self-signed certificate
+	InsecureSkipTLSVerify bool      `json:"insecureSkipTLSVerify"`
+	CreatedAt             time.Time `json:"createdAt"`
+	// LastReport is the last fleet report fetched from the remote installation, nil if none has been fetched yet
+	LastReport   *fleetreporttypes.Report `json:"lastReport"`
+	LastReportAt *time.Time               `json:"lastReportAt"`
+	// LastError is the error of the last fetch, it is empty if the last fetch succeeded
+	LastError string `json:"lastError"`
+}
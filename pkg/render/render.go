@@ -1,6 +1,7 @@
 package render
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -15,6 +16,9 @@ import (
 	"github.com/replicatedhq/kots/pkg/reporting"
 	"github.com/replicatedhq/kots/pkg/rewrite"
 	"github.com/replicatedhq/kots/pkg/template"
+	"github.com/replicatedhq/kots/pkg/tracing"
+	"github.com/replicatedhq/kots/pkg/vault"
+	"go.opentelemetry.io/otel/attribute"
 )
 
 type Renderer struct {
@@ -81,7 +85,8 @@ func NewBuilder(kotsKinds *kotsutil.KotsKinds, registrySettings registrytypes.Re
 	}
 
 	appInfo := template.ApplicationInfo{
-		Slug: appSlug,
+		Slug:       appSlug,
+		VaultItems: vault.ItemNames(kotsKinds.ConfigValues),
 	}
 
 	versionInfo := template.VersionInfoFromInstallation(sequence, isAirgap, kotsKinds.Installation.Spec)
@@ -107,6 +112,13 @@ func (r Renderer) RenderDir(archiveDir string, a *apptypes.App, downstreams []do
 }
 
 func RenderDir(archiveDir string, a *apptypes.App, downstreams []downstreamtypes.Downstream, registrySettings registrytypes.RegistrySettings) error {
+	_, span := tracing.Start(context.Background(), "render.RenderDir", attribute.String("app.slug", a.Slug))
+	err := renderDir(archiveDir, a, downstreams, registrySettings)
+	tracing.End(span, err)
+	return err
+}
+
+func renderDir(archiveDir string, a *apptypes.App, downstreams []downstreamtypes.Downstream, registrySettings registrytypes.RegistrySettings) error {
 	installation, err := kotsutil.LoadInstallationFromPath(filepath.Join(archiveDir, "upstream", "userdata", "installation.yaml"))
 	if err != nil {
 		return errors.Wrap(err, "failed to load installation from path")
@@ -3,9 +3,7 @@ package reporting
 import (
 	"encoding/base64"
 	"fmt"
-	"io/ioutil"
 	"net/http"
-	"os"
 
 	"github.com/pkg/errors"
 	"github.com/replicatedhq/kots/pkg/api/reporting/types"
@@ -125,20 +123,15 @@ func getDownstreamInfo(appID string) (*types.DownstreamInfo, error) {
 
 	// info about the deployed app sequence
 	if deployedAppSequence != -1 {
-		deployedArchiveDir, err := ioutil.TempDir("", "kotsadm")
-		if err != nil {
-			return nil, errors.Wrap(err, "failed to create temp dir")
-		}
-		defer os.RemoveAll(deployedArchiveDir)
-
-		err = store.GetStore().GetAppVersionArchive(appID, deployedAppSequence, deployedArchiveDir)
+		archive, err := store.GetStore().GetAppVersionArchiveReader(appID, deployedAppSequence)
 		if err != nil {
 			return nil, errors.Wrap(err, "failed to get app version archive")
 		}
+		defer archive.Close()
 
-		deployedKotsKinds, err := kotsutil.LoadKotsKindsFromPath(deployedArchiveDir)
+		deployedKotsKinds, err := kotsutil.LoadKotsKindsFromArchive(archive)
 		if err != nil {
-			return nil, errors.Wrap(err, "failed to load kotskinds from path")
+			return nil, errors.Wrap(err, "failed to load kotskinds from archive")
 		}
 
 		di.Cursor = deployedKotsKinds.Installation.Spec.UpdateCursor
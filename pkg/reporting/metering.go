@@ -0,0 +1,77 @@
+package reporting
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/pkg/errors"
+	"github.com/replicatedhq/kots/pkg/metering"
+	"github.com/replicatedhq/kots/pkg/store"
+)
+
+// SendEntitlementUsage uploads the current metered entitlement usage for an online app
+func SendEntitlementUsage(appID string) error {
+	a, err := store.GetStore().GetApp(appID)
+	if err != nil {
+		if store.GetStore().IsNotFound(err) {
+			return nil
+		}
+		return errors.Wrap(err, "failed to get app")
+	}
+
+	if a.IsAirgap {
+		return nil
+	}
+
+	license, err := store.GetStore().GetLatestLicenseForApp(a.ID)
+	if err != nil {
+		return errors.Wrap(err, "failed to get license for app")
+	}
+
+	endpoint := license.Spec.Endpoint
+	if !canReport(endpoint) {
+		return nil
+	}
+
+	usage, err := metering.GetUsage(a.ID)
+	if err != nil {
+		return errors.Wrap(err, "failed to get entitlement usage")
+	}
+	if len(usage) == 0 {
+		return nil
+	}
+
+	payload, err := json.Marshal(map[string]interface{}{
+		"usage": usage,
+	})
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal usage")
+	}
+
+	url := fmt.Sprintf("%s/kots_metrics/license_instance/usage", endpoint)
+
+	postReq, err := http.NewRequest("POST", url, bytes.NewBuffer(payload))
+	if err != nil {
+		return errors.Wrap(err, "failed to create http request")
+	}
+	postReq.Header.Set("Authorization", fmt.Sprintf("Basic %s", base64.StdEncoding.EncodeToString([]byte(fmt.Sprintf("%s:%s", license.Spec.LicenseID, license.Spec.LicenseID)))))
+	postReq.Header.Set("Content-Type", "application/json")
+
+	reportingInfo := GetReportingInfo(a.ID)
+	InjectReportingInfoHeaders(postReq, reportingInfo)
+
+	resp, err := http.DefaultClient.Do(postReq)
+	if err != nil {
+		return errors.Wrap(err, "failed to post request")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 200 {
+		return errors.Errorf("Unexpected status code %d", resp.StatusCode)
+	}
+
+	return nil
+}
@@ -133,7 +133,10 @@ func ReportAppInfo(appID string, sequence int64, isSkipPreflights bool, isCLI bo
 
 		if err := SendPreflightsReportToReplicatedApp(license, appID, clusterID, sequence, isSkipPreflights, currentVersionStatus, isCLI, preflightState, string(appStatus)); err != nil {
 			logger.Debugf("failed to send preflights data to replicated app: %v", err)
-			return
+		}
+
+		if err := sendReleaseFeedback(license, appID, clusterID, sequence, isSkipPreflights, currentVersionStatus, string(appStatus), preflightResults); err != nil {
+			logger.Debugf("failed to send release feedback to replicated app: %v", err)
 		}
 	}()
 	return nil
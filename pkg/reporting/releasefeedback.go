@@ -0,0 +1,118 @@
+package reporting
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/pkg/errors"
+	kotsv1beta1 "github.com/replicatedhq/kots/kotskinds/apis/kots/v1beta1"
+	"github.com/replicatedhq/kots/pkg/buildversion"
+	"github.com/replicatedhq/kots/pkg/logger"
+	releasefeedbacktypes "github.com/replicatedhq/kots/pkg/releasefeedback/types"
+	"github.com/replicatedhq/kots/pkg/store"
+	troubleshootpreflight "github.com/replicatedhq/troubleshoot/pkg/preflight"
+	"go.uber.org/zap"
+)
+
+// sendReleaseFeedback reports the rollout of a release to the vendor, if the customer consented to it
+func sendReleaseFeedback(license *kotsv1beta1.License, appID string, clusterID string, sequence int64, skipPreflights bool, deployStatus string, appStatus string, preflightResults *troubleshootpreflight.UploadPreflightResults) error {
+	consent, err := store.GetStore().GetReleaseFeedbackConsent(appID)
+	if err != nil {
+		return errors.Wrap(err, "failed to get release feedback consent")
+	}
+	if consent == nil || !consent.Enabled {
+		return nil
+	}
+
+	endpoint := license.Spec.Endpoint
+	if !canReport(endpoint) {
+		return nil
+	}
+
+	report := releasefeedbacktypes.Report{
+		AppID:          appID,
+		ClusterID:      clusterID,
+		Sequence:       sequence,
+		DeployStatus:   deployStatus,
+		AppStatus:      appStatus,
+		SkipPreflights: skipPreflights,
+		Preflights:     getPreflightSummary(preflightResults, consent.IncludePreflightDetails),
+		KotsVersion:    buildversion.Version(),
+		ReportedAt:     time.Now(),
+	}
+
+	appVersion, err := store.GetStore().GetAppVersion(appID, sequence)
+	if err != nil {
+		return errors.Wrap(err, "failed to get app version")
+	}
+	if appVersion.KOTSKinds != nil {
+		report.VersionLabel = appVersion.KOTSKinds.Installation.Spec.VersionLabel
+		report.ChannelID = appVersion.KOTSKinds.Installation.Spec.ChannelID
+		report.ChannelSequence = appVersion.KOTSKinds.Installation.Spec.UpdateCursor
+	}
+
+	b, err := json.Marshal(report)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal report")
+	}
+
+	url := fmt.Sprintf("%s/kots_metrics/release_feedback/%s/%s", endpoint, appID, clusterID)
+	postReq, err := http.NewRequest("POST", url, bytes.NewReader(b))
+	if err != nil {
+		return errors.Wrap(err, "failed to create http request")
+	}
+	postReq.Header.Add("Authorization", license.Spec.LicenseID)
+	postReq.Header.Set("Content-Type", "application/json")
+
+	resp, err := http.DefaultClient.Do(postReq)
+	if err != nil {
+		return errors.Wrap(err, "failed to send release feedback")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated && resp.StatusCode != http.StatusOK {
+		return errors.Errorf("unexpected status code %d", resp.StatusCode)
+	}
+
+	// the customer can see what was shared with the vendor
+	logger.Info("sent release feedback to the vendor", zap.String("audit", "release-feedback"), zap.String("appID", appID), zap.Int64("sequence", sequence), zap.ByteString("report", b))
+
+	return nil
+}
+
+// getPreflightSummary returns nil if there are no preflight results. The checks that did not pass are only listed
+// with the details of the customer's consent.
+func getPreflightSummary(preflightResults *troubleshootpreflight.UploadPreflightResults, includeDetails bool) *releasefeedbacktypes.PreflightSummary {
+	if preflightResults == nil {
+		return nil
+	}
+
+	summary := releasefeedbacktypes.PreflightSummary{
+		State: getPreflightState(preflightResults),
+	}
+	for _, result := range preflightResults.Results {
+		outcome := "pass"
+		if result.IsFail {
+			outcome = "fail"
+			summary.Fail++
+		} else if result.IsWarn {
+			outcome = "warn"
+			summary.Warn++
+		} else {
+			summary.Pass++
+		}
+
+		if includeDetails && outcome != "pass" {
+			summary.Checks = append(summary.Checks, releasefeedbacktypes.PreflightCheck{
+				Title:   result.Title,
+				Outcome: outcome,
+				Message: result.Message,
+			})
+		}
+	}
+
+	return &summary
+}
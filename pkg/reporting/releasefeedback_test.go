@@ -0,0 +1,33 @@
+package reporting
+
+import (
+	"testing"
+
+	troubleshootpreflight "github.com/replicatedhq/troubleshoot/pkg/preflight"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_getPreflightSummary(t *testing.T) {
+	assert.Nil(t, getPreflightSummary(nil, true))
+
+	results := &troubleshootpreflight.UploadPreflightResults{
+		Results: []*troubleshootpreflight.UploadPreflightResult{
+			{Title: "Kubernetes version", Message: "supported"},
+			{Title: "Memory", Message: "at least 8Gi is recommended", IsWarn: true},
+			{Title: "Storage class", Message: "no default storage class", IsFail: true},
+		},
+	}
+
+	summary := getPreflightSummary(results, false)
+	assert.Equal(t, "fail", summary.State)
+	assert.Equal(t, 1, summary.Pass)
+	assert.Equal(t, 1, summary.Warn)
+	assert.Equal(t, 1, summary.Fail)
+	assert.Empty(t, summary.Checks, "checks are only reported with the customer's consent")
+
+	summary = getPreflightSummary(results, true)
+	assert.Len(t, summary.Checks, 2)
+	assert.Equal(t, "Memory", summary.Checks[0].Title)
+	assert.Equal(t, "warn", summary.Checks[0].Outcome)
+	assert.Equal(t, "fail", summary.Checks[1].Outcome)
+}
@@ -0,0 +1,466 @@
+package restoredrill
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	appstatustypes "github.com/replicatedhq/kots/pkg/api/appstatus/types"
+	apptypes "github.com/replicatedhq/kots/pkg/app/types"
+	"github.com/replicatedhq/kots/pkg/appclone"
+	"github.com/replicatedhq/kots/pkg/cronschedule"
+	"github.com/replicatedhq/kots/pkg/k8sutil"
+	snapshot "github.com/replicatedhq/kots/pkg/kotsadmsnapshot"
+	snapshottypes "github.com/replicatedhq/kots/pkg/kotsadmsnapshot/types"
+	"github.com/replicatedhq/kots/pkg/logger"
+	postdeploytesttypes "github.com/replicatedhq/kots/pkg/postdeploytest/types"
+	"github.com/replicatedhq/kots/pkg/restoredrill/types"
+	"github.com/replicatedhq/kots/pkg/store"
+	"github.com/replicatedhq/kots/pkg/version"
+	velerov1 "github.com/vmware-tanzu/velero/pkg/apis/velero/v1"
+	corev1 "k8s.io/api/core/v1"
+	kuberneteserrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/rand"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	// Timeout is how long a drill waits for the restored app to be ready and pass its post deploy tests
+	Timeout = time.Hour
+
+	namespaceSuffix = "-restore-drill"
+
+	// drillLabel is set to the id of the drill on the namespace that the drill creates, only namespaces with the
+	// label are deleted when the drill is torn down
+	drillLabel = "kots.io/restore-drill"
+)
+
+func Start() error {
+	logger.Debug("starting restore drill scheduler")
+
+	startLoop(drillLoop, 60)
+
+	return nil
+}
+
+func startLoop(fn func(), intervalInSeconds time.Duration) {
+	go func() {
+		for {
+			fn()
+			time.Sleep(time.Second * intervalInSeconds)
+		}
+	}()
+}
+
+func drillLoop() {
+	appsList, err := store.GetStore().ListInstalledApps()
+	if err != nil {
+		logger.Error(errors.Wrap(err, "failed to list installed apps for restore drills"))
+		return
+	}
+
+	for _, a := range appsList {
+		if a.IsArchived {
+			continue
+		}
+		if err := handleApp(a); err != nil {
+			logger.Error(errors.Wrapf(err, "failed to handle restore drills for app %s", a.ID))
+		}
+	}
+}
+
+/*
+* The restore_drill table holds the drills of each app. If drills are scheduled for the app there is exactly one
+* drill in the scheduled state, which starts once its time has passed and then schedules the next one. A drill
+* started from the api is a scheduled drill for the current time.
+*
+* A running drill is progressed on each pass of the loop until the restored clone passes or fails verification,
+* or times out. The clone and its namespace are then removed, regardless of the result.
+ */
+func handleApp(a *apptypes.App) error {
+	drills, err := store.GetStore().ListRestoreDrills(a.ID)
+	if err != nil {
+		return errors.Wrap(err, "failed to list restore drills")
+	}
+
+	for _, drill := range drills {
+		if drill.Status == types.StatusRunning {
+			return progressDrill(drill)
+		}
+	}
+
+	var next *types.Drill
+	for i := range drills {
+		if drills[i].Status == types.StatusScheduled {
+			next = &drills[i]
+			break
+		}
+	}
+
+	if next == nil {
+		if a.RestoreDrillSchedule == "" {
+			return nil
+		}
+		logger.Infof("No restore drill scheduled for app %s with schedule %s. Queueing one.", a.ID, a.RestoreDrillSchedule)
+		return Schedule(a.ID, a.RestoreDrillSchedule, a.ScheduleTimezone)
+	}
+
+	if next.ScheduledAt.After(time.Now()) {
+		logger.Debugf("Not yet time to run restore drill for app %s", a.ID)
+		return nil
+	}
+
+	if a.RestoreInProgressName != "" {
+		logger.Infof("Postponing restore drill for app %s because a restore is in progress", a.ID)
+		return nil
+	}
+
+	if err := startDrill(a, *next); err != nil {
+		return errors.Wrap(err, "failed to start restore drill")
+	}
+
+	if a.RestoreDrillSchedule != "" {
+		if err := Schedule(a.ID, a.RestoreDrillSchedule, a.ScheduleTimezone); err != nil {
+			return errors.Wrap(err, "failed to schedule next restore drill")
+		}
+	}
+
+	return nil
+}
+
+// Schedule queues a drill for the next time of the cron schedule, in the timezone unless the expression sets one
+// with CRON_TZ
+func Schedule(appID string, cronExpression string, timezone string) error {
+	cronSchedule, err := cronschedule.Parse(cronExpression, timezone)
+	if err != nil {
+		return errors.Wrap(err, "failed to parse cron expression")
+	}
+
+	return queueDrill(appID, cronSchedule.Next(time.Now()))
+}
+
+// RunNow queues a drill that is started on the next pass of the drill loop. Scheduled drills are replaced, the
+// schedule resumes after the drill.
+func RunNow(appID string) error {
+	if err := store.GetStore().DeleteScheduledRestoreDrills(appID); err != nil {
+		return errors.Wrap(err, "failed to delete scheduled restore drills")
+	}
+
+	return queueDrill(appID, time.Now())
+}
+
+func queueDrill(appID string, scheduledAt time.Time) error {
+	drill := types.Drill{
+		ID:          strings.ToLower(rand.String(32)),
+		AppID:       appID,
+		Status:      types.StatusScheduled,
+		ScheduledAt: scheduledAt,
+	}
+	if err := store.GetStore().CreateRestoreDrill(drill); err != nil {
+		return errors.Wrap(err, "failed to create restore drill")
+	}
+	return nil
+}
+
+// startDrill clones the app from its latest backup into the drill namespace. The drill fails if there is no backup
+// to restore.
+func startDrill(a *apptypes.App, drill types.Drill) error {
+	now := time.Now()
+	drill.Status = types.StatusRunning
+	drill.StartedAt = &now
+	drill.Namespace = Namespace(a)
+
+	ctx := context.Background()
+	kotsadmNamespace := os.Getenv("POD_NAMESPACE")
+
+	appBackups, err := snapshot.ListBackupsForApp(ctx, kotsadmNamespace, a.ID)
+	if err != nil {
+		return errors.Wrap(err, "failed to list app backups")
+	}
+	instanceBackups, err := snapshot.ListInstanceBackups(ctx, kotsadmNamespace)
+	if err != nil {
+		return errors.Wrap(err, "failed to list instance backups")
+	}
+
+	backup := latestCompletedBackup(a.Slug, appBackups, instanceBackups)
+	if backup == nil {
+		return finishDrill(drill, types.StatusFailed, "no completed backup includes the app")
+	}
+	drill.BackupName = backup.Name
+
+	logger.Infof("starting restore drill of backup %s for app %s in namespace %s", backup.Name, a.Slug, drill.Namespace)
+
+	clientset, err := k8sutil.GetClientset()
+	if err != nil {
+		return errors.Wrap(err, "failed to get clientset")
+	}
+	if err := createNamespace(ctx, clientset, drill); err != nil {
+		if kuberneteserrors.IsAlreadyExists(errors.Cause(err)) {
+			return finishDrill(drill, types.StatusFailed, fmt.Sprintf("namespace %s already exists", drill.Namespace))
+		}
+		return errors.Wrap(err, "failed to create namespace")
+	}
+
+	clone, err := appclone.CloneFromSnapshot(ctx, a, backup.Name, appclone.CloneOptions{
+		Name:      fmt.Sprintf("%s restore drill", a.Name),
+		Namespace: drill.Namespace,
+	})
+	if err != nil {
+		message := errors.Wrap(err, "failed to restore backup").Error()
+		if err := deleteNamespace(ctx, clientset, drill); err != nil {
+			message = fmt.Sprintf("%s; failed to tear down: %s", message, err.Error())
+		}
+		return finishDrill(drill, types.StatusFailed, message)
+	}
+	drill.CloneAppID = clone.ID
+
+	if err := store.GetStore().UpdateRestoreDrill(drill); err != nil {
+		return errors.Wrap(err, "failed to update restore drill")
+	}
+
+	return nil
+}
+
+// progressDrill verifies the clone of a running drill, and tears the drill down once it has passed, failed or
+// timed out
+func progressDrill(drill types.Drill) error {
+	clone, err := store.GetStore().GetApp(drill.CloneAppID)
+	if err != nil {
+		if store.GetStore().IsNotFound(err) {
+			return finishDrill(drill, types.StatusFailed, "restored app was removed")
+		}
+		return errors.Wrap(err, "failed to get restored app")
+	}
+
+	passed, reason, err := verifyClone(clone, drill.BackupName)
+	if err != nil {
+		return errors.Wrap(err, "failed to verify restored app")
+	}
+
+	status := types.StatusRunning
+	if passed {
+		status = types.StatusPassed
+		reason = "restored app is ready and passed its post deploy tests"
+	} else if reason != "" {
+		status = types.StatusFailed
+	} else if drill.StartedAt != nil && time.Since(*drill.StartedAt) > Timeout {
+		status = types.StatusFailed
+		reason = fmt.Sprintf("timed out after %s waiting for the restored app to be ready", Timeout)
+	}
+
+	if status == types.StatusRunning {
+		return nil
+	}
+
+	if err := teardown(clone, drill); err != nil {
+		logger.Error(errors.Wrapf(err, "failed to tear down restore drill %s", drill.ID))
+		reason = fmt.Sprintf("%s; failed to tear down: %s", reason, err.Error())
+	}
+
+	return finishDrill(drill, status, reason)
+}
+
+// verifyClone returns true if the restore into the clone completed, the clone is ready and all of its post deploy
+// tests passed. A non empty reason is returned if the clone failed verification, or neither while it is in progress.
+func verifyClone(clone *apptypes.App, backupName string) (bool, string, error) {
+	ctx := context.Background()
+	kotsadmNamespace := os.Getenv("POD_NAMESPACE")
+
+	restoreName := fmt.Sprintf("%s.%s", backupName, clone.Slug)
+	restore, err := snapshot.GetRestore(ctx, kotsadmNamespace, restoreName)
+	if err != nil {
+		return false, "", errors.Wrap(err, "failed to get restore")
+	}
+	if restore == nil {
+		return false, fmt.Sprintf("restore %s not found", restoreName), nil
+	}
+	switch restore.Status.Phase {
+	case velerov1.RestorePhaseCompleted:
+	case velerov1.RestorePhaseFailed, velerov1.RestorePhasePartiallyFailed:
+		return false, fmt.Sprintf("restore %s %s", restoreName, strings.ToLower(string(restore.Status.Phase))), nil
+	default:
+		return false, "", nil
+	}
+	if clone.RestoreInProgressName != "" {
+		// the clone is deployed once the restore is marked complete
+		return false, "", nil
+	}
+
+	appStatus, err := store.GetStore().GetAppStatus(clone.ID)
+	if err != nil {
+		return false, "", errors.Wrap(err, "failed to get app status")
+	}
+	if appStatus == nil || appStatus.State != appstatustypes.StateReady {
+		return false, "", nil
+	}
+
+	downstreams, err := store.GetStore().ListDownstreamsForApp(clone.ID)
+	if err != nil {
+		return false, "", errors.Wrap(err, "failed to list downstreams")
+	}
+	if len(downstreams) == 0 {
+		return false, "restored app has no downstream", nil
+	}
+	clusterID := downstreams[0].ClusterID
+
+	sequence, err := store.GetStore().GetCurrentSequence(clone.ID, clusterID)
+	if err != nil {
+		return false, "", errors.Wrap(err, "failed to get current sequence")
+	}
+	kotsKinds, err := version.GetKotsKinds(clone.ID, sequence)
+	if err != nil {
+		return false, "", errors.Wrap(err, "failed to load kots kinds")
+	}
+	results, err := store.GetStore().GetDownstreamPostDeployTestResults(clone.ID, clusterID, sequence)
+	if err != nil {
+		return false, "", errors.Wrap(err, "failed to get post deploy test results")
+	}
+
+	passed, reason := postDeployTestsPassed(len(kotsKinds.KotsApplication.Spec.PostDeployTests), results)
+	return passed, reason, nil
+}
+
+// postDeployTestsPassed returns true if all of the tests of the app have passed, or a non empty reason if one has
+// failed
+func postDeployTestsPassed(testCount int, results []postdeploytesttypes.Result) (bool, string) {
+	for _, result := range results {
+		if result.Status == postdeploytesttypes.StatusFailed {
+			return false, fmt.Sprintf("post deploy test %s failed: %s", result.Name, result.Message)
+		}
+	}
+	if len(results) < testCount {
+		return false, ""
+	}
+	for _, result := range results {
+		if result.Status != postdeploytesttypes.StatusPassed {
+			return false, ""
+		}
+	}
+	return true, ""
+}
+
+// teardown removes the velero restore, the drill namespace if the drill created it, and the clone. Cluster scoped resources that the clone
+// deployed are not removed.
+func teardown(clone *apptypes.App, drill types.Drill) error {
+	ctx := context.Background()
+
+	restoreName := fmt.Sprintf("%s.%s", drill.BackupName, clone.Slug)
+	if err := snapshot.DeleteRestore(ctx, os.Getenv("POD_NAMESPACE"), restoreName); err != nil {
+		return errors.Wrap(err, "failed to delete restore")
+	}
+
+	clientset, err := k8sutil.GetClientset()
+	if err != nil {
+		return errors.Wrap(err, "failed to get clientset")
+	}
+	if err := deleteNamespace(ctx, clientset, drill); err != nil {
+		return err
+	}
+
+	if err := store.GetStore().RemoveApp(clone.ID); err != nil {
+		return errors.Wrap(err, "failed to remove restored app")
+	}
+
+	return nil
+}
+
+// createNamespace creates the namespace of the drill, labeled with the id of the drill. It fails if the namespace
+// already exists, it could hold resources that are not part of the drill.
+func createNamespace(ctx context.Context, clientset kubernetes.Interface, drill types.Drill) error {
+	namespace := &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: drill.Namespace,
+			Labels: map[string]string{
+				drillLabel: drill.ID,
+			},
+		},
+	}
+	if _, err := clientset.CoreV1().Namespaces().Create(ctx, namespace, metav1.CreateOptions{}); err != nil {
+		return errors.Wrapf(err, "failed to create namespace %s", drill.Namespace)
+	}
+	return nil
+}
+
+// deleteNamespace deletes the namespace of the drill, unless it is not labeled with the id of the drill
+func deleteNamespace(ctx context.Context, clientset kubernetes.Interface, drill types.Drill) error {
+	namespace, err := clientset.CoreV1().Namespaces().Get(ctx, drill.Namespace, metav1.GetOptions{})
+	if kuberneteserrors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return errors.Wrapf(err, "failed to get namespace %s", drill.Namespace)
+	}
+	if namespace.Labels[drillLabel] != drill.ID {
+		logger.Infof("not deleting namespace %s, it was not created by restore drill %s", drill.Namespace, drill.ID)
+		return nil
+	}
+
+	err = clientset.CoreV1().Namespaces().Delete(ctx, drill.Namespace, metav1.DeleteOptions{})
+	if err != nil && !kuberneteserrors.IsNotFound(err) {
+		return errors.Wrapf(err, "failed to delete namespace %s", drill.Namespace)
+	}
+	return nil
+}
+
+func finishDrill(drill types.Drill, status types.Status, message string) error {
+	now := time.Now()
+	drill.Status = status
+	drill.Message = message
+	drill.FinishedAt = &now
+
+	logger.Infof("restore drill %s of backup %s %s: %s", drill.ID, drill.BackupName, status, message)
+
+	if err := store.GetStore().UpdateRestoreDrill(drill); err != nil {
+		return errors.Wrap(err, "failed to update restore drill")
+	}
+	return nil
+}
+
+// latestCompletedBackup returns the most recent completed app or instance backup that includes the app, or nil
+func latestCompletedBackup(appSlug string, appBackups []*snapshottypes.Backup, instanceBackups []*snapshottypes.Backup) *snapshottypes.Backup {
+	var latest *snapshottypes.Backup
+	isNewer := func(backup *snapshottypes.Backup) bool {
+		if backup.Status != string(velerov1.BackupPhaseCompleted) || backup.FinishedAt == nil {
+			return false
+		}
+		return latest == nil || backup.FinishedAt.After(*latest.FinishedAt)
+	}
+
+	for _, backup := range appBackups {
+		if isNewer(backup) {
+			latest = backup
+		}
+	}
+	for _, backup := range instanceBackups {
+		if !includesApp(backup, appSlug) {
+			continue
+		}
+		if isNewer(backup) {
+			latest = backup
+		}
+	}
+
+	return latest
+}
+
+func includesApp(backup *snapshottypes.Backup, appSlug string) bool {
+	for _, a := range backup.IncludedApps {
+		if a.Slug == appSlug {
+			return true
+		}
+	}
+	return false
+}
+
+// Namespace returns the scratch namespace that the app is restored to in a drill
+func Namespace(a *apptypes.App) string {
+	slug := a.Slug
+	if max := 63 - len(namespaceSuffix); len(slug) > max {
+		slug = strings.TrimRight(slug[:max], "-")
+	}
+	return slug + namespaceSuffix
+}
@@ -0,0 +1,114 @@
+package restoredrill
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	apptypes "github.com/replicatedhq/kots/pkg/app/types"
+	snapshottypes "github.com/replicatedhq/kots/pkg/kotsadmsnapshot/types"
+	postdeploytesttypes "github.com/replicatedhq/kots/pkg/postdeploytest/types"
+	"github.com/replicatedhq/kots/pkg/restoredrill/types"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	kuberneteserrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func Test_latestCompletedBackup(t *testing.T) {
+	at := func(hour int) *time.Time {
+		t := time.Date(2021, 6, 1, hour, 0, 0, 0, time.UTC)
+		return &t
+	}
+
+	appBackups := []*snapshottypes.Backup{
+		{Name: "app-old", Status: "Completed", FinishedAt: at(1)},
+		{Name: "app-failed", Status: "PartiallyFailed", FinishedAt: at(5)},
+		{Name: "app-in-progress", Status: "InProgress"},
+	}
+	instanceBackups := []*snapshottypes.Backup{
+		{Name: "instance-other-app", Status: "Completed", FinishedAt: at(6), IncludedApps: []snapshottypes.App{{Slug: "other-app"}}},
+		{Name: "instance", Status: "Completed", FinishedAt: at(3), IncludedApps: []snapshottypes.App{{Slug: "my-app"}}},
+	}
+
+	backup := latestCompletedBackup("my-app", appBackups, instanceBackups)
+	require.NotNil(t, backup)
+	require.Equal(t, "instance", backup.Name)
+
+	backup = latestCompletedBackup("my-app", appBackups, nil)
+	require.NotNil(t, backup)
+	require.Equal(t, "app-old", backup.Name)
+
+	require.Nil(t, latestCompletedBackup("my-app", nil, instanceBackups[:1]))
+}
+
+func Test_postDeployTestsPassed(t *testing.T) {
+	passed := postdeploytesttypes.Result{Name: "health", Status: postdeploytesttypes.StatusPassed}
+	running := postdeploytesttypes.Result{Name: "login", Status: postdeploytesttypes.StatusRunning}
+	failed := postdeploytesttypes.Result{Name: "login", Status: postdeploytesttypes.StatusFailed, Message: "exit code 1"}
+
+	ok, reason := postDeployTestsPassed(0, nil)
+	require.True(t, ok)
+	require.Empty(t, reason)
+
+	ok, reason = postDeployTestsPassed(2, nil)
+	require.False(t, ok)
+	require.Empty(t, reason)
+
+	ok, reason = postDeployTestsPassed(2, []postdeploytesttypes.Result{passed, running})
+	require.False(t, ok)
+	require.Empty(t, reason)
+
+	ok, reason = postDeployTestsPassed(2, []postdeploytesttypes.Result{passed, failed})
+	require.False(t, ok)
+	require.Equal(t, "post deploy test login failed: exit code 1", reason)
+
+	ok, reason = postDeployTestsPassed(1, []postdeploytesttypes.Result{passed})
+	require.True(t, ok)
+	require.Empty(t, reason)
+}
+
+func TestNamespace(t *testing.T) {
+	require.Equal(t, "my-app-restore-drill", Namespace(&apptypes.App{Slug: "my-app"}))
+
+	namespace := Namespace(&apptypes.App{Slug: strings.Repeat("a", 49) + "-" + strings.Repeat("b", 20)})
+	require.Equal(t, strings.Repeat("a", 49)+"-restore-drill", namespace)
+	require.LessOrEqual(t, len(namespace), 63)
+}
+
+func Test_createAndDeleteNamespace(t *testing.T) {
+	req := require.New(t)
+	ctx := context.Background()
+
+	existing := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "other-app-restore-drill"}}
+	clientset := fake.NewSimpleClientset(existing)
+
+	// a namespace that already exists is not used for a drill, and not deleted by it
+	drill := types.Drill{ID: "drill-1", Namespace: "other-app-restore-drill"}
+	err := createNamespace(ctx, clientset, drill)
+	req.True(kuberneteserrors.IsAlreadyExists(errors.Cause(err)))
+	req.NoError(deleteNamespace(ctx, clientset, drill))
+	_, err = clientset.CoreV1().Namespaces().Get(ctx, "other-app-restore-drill", metav1.GetOptions{})
+	req.NoError(err)
+
+	drill = types.Drill{ID: "drill-2", Namespace: "my-app-restore-drill"}
+	req.NoError(createNamespace(ctx, clientset, drill))
+	namespace, err := clientset.CoreV1().Namespaces().Get(ctx, "my-app-restore-drill", metav1.GetOptions{})
+	req.NoError(err)
+	req.Equal("drill-2", namespace.Labels[drillLabel])
+
+	// only the drill that created the namespace deletes it
+	req.NoError(deleteNamespace(ctx, clientset, types.Drill{ID: "drill-3", Namespace: "my-app-restore-drill"}))
+	_, err = clientset.CoreV1().Namespaces().Get(ctx, "my-app-restore-drill", metav1.GetOptions{})
+	req.NoError(err)
+
+	req.NoError(deleteNamespace(ctx, clientset, drill))
+	_, err = clientset.CoreV1().Namespaces().Get(ctx, "my-app-restore-drill", metav1.GetOptions{})
+	req.True(kuberneteserrors.IsNotFound(err))
+
+	// the namespace is already gone
+	req.NoError(deleteNamespace(ctx, clientset, drill))
+}
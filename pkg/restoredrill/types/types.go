@@ -0,0 +1,30 @@
+package types
+
+import (
+	"time"
+)
+
+type Status string
+
+const (
+	// StatusScheduled drills start once their scheduled time has passed
+	StatusScheduled Status = "scheduled"
+	StatusRunning   Status = "running"
+	StatusPassed    Status = "passed"
+	StatusFailed    Status = "failed"
+)
+
+// Drill is a restore of the latest backup of an app into a clone of the app in a scratch namespace, which is
+// verified and removed again
+type Drill struct {
+	ID          string     `json:"id"`
+	AppID       string     `json:"appId"`
+	Status      Status     `json:"status"`
+	ScheduledAt time.Time  `json:"scheduledAt"`
+	BackupName  string     `json:"backupName,omitempty"`
+	CloneAppID  string     `json:"cloneAppId,omitempty"`
+	Namespace   string     `json:"namespace,omitempty"`
+	Message     string     `json:"message,omitempty"`
+	StartedAt   *time.Time `json:"startedAt,omitempty"`
+	FinishedAt  *time.Time `json:"finishedAt,omitempty"`
+}
@@ -1,6 +1,7 @@
 package rewrite
 
 import (
+	"context"
 	"fmt"
 	"io"
 	"io/ioutil"
@@ -24,6 +25,8 @@ import (
 )
 
 type RewriteOptions struct {
+	// Context is the parent of the spans of the rewrite, the background context if nil
+	Context            context.Context
 	RootDir            string
 	UpstreamURI        string
 	UpstreamPath       string
@@ -129,7 +132,7 @@ func Rewrite(rewriteOptions RewriteOptions) error {
 	}
 	log.ActionWithSpinner("Creating base")
 	io.WriteString(rewriteOptions.ReportWriter, "Creating base\n")
-	b, err := base.RenderUpstream(u, &renderOptions)
+	b, err := base.RenderUpstreamCached(u, &renderOptions)
 	if err != nil {
 		return errors.Wrap(err, "failed to render upstream")
 	}
@@ -184,6 +187,11 @@ func Rewrite(rewriteOptions RewriteOptions) error {
 		return errors.Wrap(err, "failed to load identity config")
 	}
 
+	exposureSpec, err := upstream.LoadExposure(u.GetUpstreamDir(writeUpstreamOptions))
+	if err != nil {
+		return errors.Wrap(err, "failed to load exposure")
+	}
+
 	// TODO (ethan): rewrite dex image?
 
 	if rewriteOptions.CopyImages || rewriteOptions.RegistryEndpoint != "" {
@@ -202,6 +210,7 @@ func Rewrite(rewriteOptions RewriteOptions) error {
 		}
 
 		writeUpstreamImageOptions := base.WriteUpstreamImageOptions{
+			Context:      rewriteOptions.Context,
 			BaseDir:      writeBaseOptions.BaseDir,
 			ReportWriter: rewriteOptions.ReportWriter,
 			Log:          log,
@@ -325,7 +334,7 @@ func Rewrite(rewriteOptions RewriteOptions) error {
 	log.ActionWithSpinner("Creating midstream")
 	io.WriteString(rewriteOptions.ReportWriter, "Creating midstream\n")
 
-	m, err := midstream.CreateMidstream(b, images, objects, pullSecret, identitySpec, identityConfig)
+	m, err := midstream.CreateMidstream(b, images, objects, pullSecret, identitySpec, identityConfig, exposureSpec)
 	if err != nil {
 		return errors.Wrap(err, "failed to create midstream")
 	}
@@ -49,13 +49,17 @@ func GetConfig() *aws.Config {
 	}
 
 	s3Config := &aws.Config{
-		Credentials:      credentials.NewStaticCredentials(os.Getenv("S3_ACCESS_KEY_ID"), os.Getenv("S3_SECRET_ACCESS_KEY"), ""),
 		Endpoint:         aws.String(os.Getenv("S3_ENDPOINT")),
 		Region:           aws.String(region),
 		DisableSSL:       aws.Bool(true),
 		S3ForcePathStyle: aws.Bool(forcePathStyle),
 	}
 
+	// without static credentials the default chain is used, e.g. iam roles for service accounts
+	if os.Getenv("S3_ACCESS_KEY_ID") != "" || os.Getenv("S3_SECRET_ACCESS_KEY") != "" {
+		s3Config.Credentials = credentials.NewStaticCredentials(os.Getenv("S3_ACCESS_KEY_ID"), os.Getenv("S3_SECRET_ACCESS_KEY"), "")
+	}
+
 	return s3Config
 }
 
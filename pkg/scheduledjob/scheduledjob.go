@@ -0,0 +1,46 @@
+package scheduledjob
+
+import (
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/replicatedhq/kots/pkg/logger"
+	"github.com/replicatedhq/kots/pkg/scheduledjob/types"
+	"github.com/replicatedhq/kots/pkg/store"
+	"k8s.io/apimachinery/pkg/util/rand"
+)
+
+// Run runs fn as a run of the job and records it in the job history. The message returned by fn is recorded when
+// it succeeds, and the error when it fails. The history is best effort, the job runs even if it cannot be recorded.
+func Run(job types.Job, appID string, fn func() (string, error)) error {
+	run := types.Run{
+		ID:        strings.ToLower(rand.String(32)),
+		Job:       job,
+		AppID:     appID,
+		Status:    types.StatusRunning,
+		StartedAt: time.Now(),
+	}
+
+	recorded := true
+	if err := store.GetStore().CreateScheduledJobRun(run); err != nil {
+		logger.Error(errors.Wrapf(err, "failed to record run of job %s", job))
+		recorded = false
+	}
+
+	message, err := fn()
+
+	status := types.StatusSucceeded
+	if err != nil {
+		status = types.StatusFailed
+		message = err.Error()
+	}
+
+	if recorded {
+		if err := store.GetStore().FinishScheduledJobRun(run.ID, status, message, time.Now()); err != nil {
+			logger.Error(errors.Wrapf(err, "failed to record result of job %s", job))
+		}
+	}
+
+	return err
+}
@@ -0,0 +1,42 @@
+package types
+
+import (
+	"time"
+)
+
+type Job string
+
+const (
+	JobUpdateCheck      Job = "update-check"
+	JobSnapshot         Job = "snapshot"
+	JobInstanceSnapshot Job = "instance-snapshot"
+	JobJanitor          Job = "janitor"
+	JobArchiveScrub     Job = "archive-scrub"
+)
+
+type Status string
+
+const (
+	StatusRunning   Status = "running"
+	StatusSucceeded Status = "succeeded"
+	StatusFailed    Status = "failed"
+)
+
+// Run is a run of a scheduled job, AppID is empty for jobs that are not app specific
+type Run struct {
+	ID         string     `json:"id"`
+	Job        Job        `json:"job"`
+	AppID      string     `json:"appId,omitempty"`
+	Status     Status     `json:"status"`
+	Message    string     `json:"message,omitempty"`
+	StartedAt  time.Time  `json:"startedAt"`
+	FinishedAt *time.Time `json:"finishedAt,omitempty"`
+}
+
+// Duration returns how long the run took, or has been running for
+func (r Run) Duration(now time.Time) time.Duration {
+	if r.FinishedAt != nil {
+		return r.FinishedAt.Sub(r.StartedAt)
+	}
+	return now.Sub(r.StartedAt)
+}
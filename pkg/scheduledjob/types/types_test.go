@@ -0,0 +1,36 @@
+package types
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunDuration(t *testing.T) {
+	startedAt := time.Date(2021, 6, 1, 12, 0, 0, 0, time.UTC)
+	finishedAt := startedAt.Add(90 * time.Second)
+	now := startedAt.Add(time.Hour)
+
+	tests := []struct {
+		name string
+		run  Run
+		want time.Duration
+	}{
+		{
+			name: "finished",
+			run:  Run{StartedAt: startedAt, FinishedAt: &finishedAt, Status: StatusSucceeded},
+			want: 90 * time.Second,
+		},
+		{
+			name: "running",
+			run:  Run{StartedAt: startedAt, Status: StatusRunning},
+			want: time.Hour,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			require.Equal(t, test.want, test.run.Duration(now))
+		})
+	}
+}
@@ -0,0 +1,151 @@
+package sensitiveconfig
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+
+	"github.com/pkg/errors"
+	kotsv1beta1 "github.com/replicatedhq/kots/kotskinds/apis/kots/v1beta1"
+	"github.com/replicatedhq/kots/pkg/crypto"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	serializer "k8s.io/apimachinery/pkg/runtime/serializer/json"
+	"k8s.io/client-go/kubernetes/scheme"
+)
+
+// IsEnabled returns true if sensitive config items should be rendered as Secret references
+func IsEnabled(app *kotsv1beta1.Application) bool {
+	return app != nil && app.Spec.SensitiveConfigAsSecret
+}
+
+// IsSensitive returns true if the value of the item should not be written to the rendered archive.
+// Passwords are always considered sensitive.
+func IsSensitive(item kotsv1beta1.ConfigItem) bool {
+	return item.Sensitive || item.Type == "password"
+}
+
+// IsEncrypted returns true if the value of the item is stored encrypted in the config values, as the values of
+// passwords are. Large files are stored out-of-line and only their references are in the config values.
+func IsEncrypted(item kotsv1beta1.ConfigItem) bool {
+	return IsSensitive(item) && item.Type != "large_file"
+}
+
+// SecretName is the name of the Secret that holds the values of sensitive config items
+func SecretName(appSlug string) string {
+	return fmt.Sprintf("%s-sensitive-config", appSlug)
+}
+
+// SecretKey is the key in the Secret that holds the value of the config item
+func SecretKey(itemName string) string {
+	return itemName
+}
+
+// BuildSecret creates the Secret with the values of all sensitive config items and of the items read from vault.
+// Password values are decrypted and file values are decoded.
+func BuildSecret(appSlug string, config *kotsv1beta1.Config, configValues *kotsv1beta1.ConfigValues, cipher *crypto.AESCipher) (*corev1.Secret, error) {
+	secret := &corev1.Secret{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "v1",
+			Kind:       "Secret",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name: SecretName(appSlug),
+			Labels: map[string]string{
+				"kots.io/app-slug": appSlug,
+			},
+		},
+		Type: corev1.SecretTypeOpaque,
+		Data: map[string][]byte{},
+	}
+
+	if config == nil {
+		return secret, nil
+	}
+
+	values := map[string]kotsv1beta1.ConfigValue{}
+	if configValues != nil {
+		values = configValues.Spec.Values
+	}
+
+	for _, group := range config.Spec.Groups {
+		for _, item := range group.Items {
+			if !IsSensitive(item) && values[item.Name].VaultPath == "" {
+				continue
+			}
+
+			value, err := getItemValue(item, values[item.Name], cipher)
+			if err != nil {
+				return nil, errors.Wrapf(err, "failed to get value of %s", item.Name)
+			}
+			secret.Data[SecretKey(item.Name)] = value
+		}
+	}
+
+	return secret, nil
+}
+
+// BuildSecretManifest returns the yaml of the Secret from BuildSecret
+func BuildSecretManifest(appSlug string, config *kotsv1beta1.Config, configValues *kotsv1beta1.ConfigValues, cipher *crypto.AESCipher) ([]byte, error) {
+	secret, err := BuildSecret(appSlug, config, configValues, cipher)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to build secret")
+	}
+
+	s := serializer.NewYAMLSerializer(serializer.DefaultMetaFactory, scheme.Scheme, scheme.Scheme)
+	var b bytes.Buffer
+	if err := s.Encode(secret, &b); err != nil {
+		return nil, errors.Wrap(err, "failed to encode secret")
+	}
+
+	return b.Bytes(), nil
+}
+
+func getItemValue(item kotsv1beta1.ConfigItem, configValue kotsv1beta1.ConfigValue, cipher *crypto.AESCipher) ([]byte, error) {
+	if configValue.ValuePlaintext != "" {
+		return []byte(configValue.ValuePlaintext), nil
+	}
+
+	value := configValue.Value
+	if value == "" {
+		value = configValue.Default
+	}
+	if value == "" {
+		return []byte{}, nil
+	}
+
+	// defaults are not encrypted, and neither are the values of sensitive items that were set before sensitive
+	// values were encrypted
+	if IsEncrypted(item) && configValue.Value != "" && cipher != nil {
+		decrypted, err := decrypt(value, cipher)
+		if err == nil {
+			value = string(decrypted)
+		} else if item.Type == "password" {
+			return nil, errors.Wrap(err, "failed to decrypt password")
+		}
+	}
+
+	if item.Type == "file" {
+		decoded, err := base64.StdEncoding.DecodeString(value)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to base64 decode file")
+		}
+		return decoded, nil
+	}
+
+	return []byte(value), nil
+}
+
+func decrypt(value string, cipher *crypto.AESCipher) ([]byte, error) {
+	decoded, err := base64.StdEncoding.DecodeString(value)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to base64 decode")
+	}
+
+	decrypted, err := cipher.Decrypt(decoded)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to decrypt")
+	}
+
+	return decrypted, nil
+}
@@ -0,0 +1,64 @@
+package sensitiveconfig
+
+import (
+	"encoding/base64"
+	"testing"
+
+	kotsv1beta1 "github.com/replicatedhq/kots/kotskinds/apis/kots/v1beta1"
+	"github.com/replicatedhq/kots/pkg/crypto"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_BuildSecret(t *testing.T) {
+	req := require.New(t)
+
+	cipher, err := crypto.NewAESCipher()
+	req.NoError(err)
+
+	config := &kotsv1beta1.Config{
+		Spec: kotsv1beta1.ConfigSpec{
+			Groups: []kotsv1beta1.ConfigGroup{
+				{
+					Name: "database",
+					Items: []kotsv1beta1.ConfigItem{
+						{Name: "db_host", Type: "text"},
+						{Name: "db_password", Type: "password"},
+						{Name: "db_user", Type: "text", Sensitive: true},
+						{Name: "db_ca", Type: "file", Sensitive: true},
+						{Name: "db_token", Type: "text", Sensitive: true},
+					},
+				},
+			},
+		},
+	}
+
+	configValues := &kotsv1beta1.ConfigValues{
+		Spec: kotsv1beta1.ConfigValuesSpec{
+			Values: map[string]kotsv1beta1.ConfigValue{
+				"db_host":     {Value: "postgres"},
+				"db_password": {Value: base64.StdEncoding.EncodeToString(cipher.Encrypt([]byte("hunter2")))},
+				"db_user":     {Default: "admin"},
+				"db_ca":       {Value: base64.StdEncoding.EncodeToString(cipher.Encrypt([]byte(base64.StdEncoding.EncodeToString([]byte("-----BEGIN CERTIFICATE-----")))))},
+				"db_token":    {Value: "set-before-encryption"},
+			},
+		},
+	}
+
+	secret, err := BuildSecret("my-app", config, configValues, cipher)
+	req.NoError(err)
+
+	req.Equal("my-app-sensitive-config", secret.Name)
+	req.Equal(map[string][]byte{
+		"db_password": []byte("hunter2"),
+		"db_user":     []byte("admin"),
+		"db_ca":       []byte("-----BEGIN CERTIFICATE-----"),
+		"db_token":    []byte("set-before-encryption"),
+	}, secret.Data)
+
+	// items read from vault are in the secret even if they are not sensitive
+	configValues.Spec.Values["db_host"] = kotsv1beta1.ConfigValue{VaultPath: "secret/db", ValuePlaintext: "postgres.internal"}
+
+	secret, err = BuildSecret("my-app", config, configValues, cipher)
+	req.NoError(err)
+	req.Equal([]byte("postgres.internal"), secret.Data["db_host"])
+}
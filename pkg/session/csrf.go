@@ -0,0 +1,26 @@
+package session
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+
+	"github.com/replicatedhq/kots/pkg/session/types"
+)
+
+// CSRFToken returns the csrf token of a session. It is derived from the session id with the session key, so it
+// doesn't need to be stored and changes with every login.
+func CSRFToken(sess *types.Session) string {
+	mac := hmac.New(sha256.New, []byte(os.Getenv("SESSION_KEY")))
+	mac.Write([]byte("csrf:" + sess.ID))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// ValidCSRFToken returns true if token is the csrf token of the session
+func ValidCSRFToken(sess *types.Session, token string) bool {
+	if token == "" {
+		return false
+	}
+	return hmac.Equal([]byte(CSRFToken(sess)), []byte(token))
+}
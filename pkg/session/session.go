@@ -12,12 +12,16 @@ import (
 	kotsv1beta1 "github.com/replicatedhq/kots/kotskinds/apis/kots/v1beta1"
 	"github.com/replicatedhq/kots/pkg/identity"
 	"github.com/replicatedhq/kots/pkg/k8sutil"
+	"github.com/replicatedhq/kots/pkg/rbac"
 	"github.com/replicatedhq/kots/pkg/session/types"
 	"github.com/replicatedhq/kots/pkg/store"
 	kuberneteserrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
+// CLISessionID is the id of the sessions of kots cli requests, which are not stored
+const CLISessionID = "kots-cli"
+
 func Parse(kotsStore store.Store, signedToken string) (*types.Session, error) {
 	if signedToken == "" {
 		return nil, errors.New("missing token")
@@ -56,7 +60,8 @@ func Parse(kotsStore store.Store, signedToken string) (*types.Session, error) {
 		}
 
 		s := types.Session{
-			ID:        "kots-cli",
+			ID:        CLISessionID,
+			UserID:    "kots-cli",
 			IssuedAt:  time.Now(),
 			ExpiresAt: time.Now().Add(time.Minute),
 			// TODO: super user permissions
@@ -99,19 +104,34 @@ func SignJWT(s *types.Session) (string, error) {
 	return signedToken, nil
 }
 
+// GetSessionRolesFromRBAC returns the roles of the groups the session is a member of. The roles of groups that are
+// limited to some apps are returned as app roles, see rbac.AppRoleID.
 func GetSessionRolesFromRBAC(sessionGroupIDs []string, groups []kotsv1beta1.IdentityConfigGroup) []string {
 	var sessionRolesIDs []string
 	for _, group := range groups {
 		if group.ID == identity.WildcardGroupID {
-			sessionRolesIDs = append(sessionRolesIDs, group.RoleIDs...)
+			sessionRolesIDs = append(sessionRolesIDs, groupRoleIDs(group)...)
 			continue
 		}
 		for _, groupID := range sessionGroupIDs {
 			if group.ID == groupID {
-				sessionRolesIDs = append(sessionRolesIDs, group.RoleIDs...)
+				sessionRolesIDs = append(sessionRolesIDs, groupRoleIDs(group)...)
 				break
 			}
 		}
 	}
 	return sessionRolesIDs
 }
+
+func groupRoleIDs(group kotsv1beta1.IdentityConfigGroup) []string {
+	if len(group.AppSlugs) == 0 {
+		return group.RoleIDs
+	}
+	roleIDs := []string{}
+	for _, roleID := range group.RoleIDs {
+		for _, appSlug := range group.AppSlugs {
+			roleIDs = append(roleIDs, rbac.AppRoleID(roleID, appSlug))
+		}
+	}
+	return roleIDs
+}
@@ -0,0 +1,22 @@
+package session
+
+import (
+	"testing"
+
+	kotsv1beta1 "github.com/replicatedhq/kots/kotskinds/apis/kots/v1beta1"
+	"github.com/replicatedhq/kots/pkg/identity"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_GetSessionRolesFromRBAC(t *testing.T) {
+	groups := []kotsv1beta1.IdentityConfigGroup{
+		{ID: "admins", RoleIDs: []string{"cluster-admin"}},
+		{ID: "team-a", RoleIDs: []string{"cluster-admin", "support"}, AppSlugs: []string{"app-a"}},
+		{ID: "team-b", RoleIDs: []string{"support"}, AppSlugs: []string{"app-b", "app-c"}},
+		{ID: identity.WildcardGroupID, RoleIDs: []string{"approver"}, AppSlugs: []string{"app-d"}},
+	}
+
+	require.Equal(t, []string{"cluster-admin", "approver@app.app-d"}, GetSessionRolesFromRBAC([]string{"admins"}, groups))
+	require.Equal(t, []string{"cluster-admin@app.app-a", "support@app.app-a", "approver@app.app-d"}, GetSessionRolesFromRBAC([]string{"team-a"}, groups))
+	require.Equal(t, []string{"support@app.app-b", "support@app.app-c", "approver@app.app-d"}, GetSessionRolesFromRBAC([]string{"team-b", "unknown"}, groups))
+}
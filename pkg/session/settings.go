@@ -0,0 +1,137 @@
+package session
+
+import (
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/replicatedhq/kots/pkg/session/types"
+	"github.com/replicatedhq/kots/pkg/store"
+)
+
+// activityInterval is how often the last activity of a session is saved. Sessions are stored in a secret, writing
+// it on every request would be too much.
+const activityInterval = time.Minute
+
+// settingsCacheDuration is how long settings are cached, they are needed by every authenticated request
+const settingsCacheDuration = time.Minute
+
+var (
+	ErrSessionExpired = errors.New("session expired")
+	ErrSessionIdle    = errors.New("session timed out due to inactivity")
+)
+
+var (
+	settingsLock       = sync.Mutex{}
+	settingsCache      *types.SessionSettings
+	settingsExpiration time.Time
+)
+
+// DefaultSettings keeps the 14 day session lifetime that sessions had before it was configurable
+func DefaultSettings() types.SessionSettings {
+	return types.SessionSettings{
+		TTLMinutes: 14 * 24 * 60,
+	}
+}
+
+// GetSettings returns the settings set through the api or the install flags, or the defaults if none were set
+func GetSettings(kotsStore store.Store) (types.SessionSettings, error) {
+	settingsLock.Lock()
+	defer settingsLock.Unlock()
+
+	if settingsCache != nil && time.Now().Before(settingsExpiration) {
+		return *settingsCache, nil
+	}
+
+	settings, err := kotsStore.GetSessionSettings()
+	if err != nil {
+		return types.SessionSettings{}, errors.Wrap(err, "failed to get session settings")
+	}
+	if settings == nil {
+		defaults := DefaultSettings()
+		settings = &defaults
+	}
+
+	settingsCache = settings
+	settingsExpiration = time.Now().Add(settingsCacheDuration)
+
+	return *settings, nil
+}
+
+func SetSettings(kotsStore store.Store, settings types.SessionSettings) error {
+	if err := ValidateSettings(settings); err != nil {
+		return errors.Wrap(err, "invalid session settings")
+	}
+
+	settingsLock.Lock()
+	defer settingsLock.Unlock()
+
+	if err := kotsStore.SetSessionSettings(settings); err != nil {
+		return errors.Wrap(err, "failed to set session settings")
+	}
+	settingsCache = nil
+
+	return nil
+}
+
+// ValidateSettings returns an error if the lifetime is not set or any value is negative
+func ValidateSettings(settings types.SessionSettings) error {
+	if settings.TTLMinutes <= 0 {
+		return errors.New("session ttl must be greater than 0")
+	}
+	if settings.IdleTimeoutMinutes < 0 {
+		return errors.New("idle timeout cannot be negative")
+	}
+	if settings.ReauthWindowMinutes < 0 {
+		return errors.New("re-auth window cannot be negative")
+	}
+	return nil
+}
+
+// ExpiresAt returns the end of the lifetime of a session issued at issuedAt
+func ExpiresAt(settings types.SessionSettings, issuedAt time.Time) time.Time {
+	return issuedAt.Add(minutes(settings.TTLMinutes))
+}
+
+// CheckActive returns ErrSessionExpired if the session is past its lifetime and ErrSessionIdle if it has been idle
+// for longer than the idle timeout. The lifetime is checked against the current settings too, so that shortening it
+// applies to existing sessions.
+func CheckActive(sess *types.Session, settings types.SessionSettings, now time.Time) error {
+	if !sess.ExpiresAt.IsZero() && now.After(sess.ExpiresAt) {
+		return ErrSessionExpired
+	}
+	if now.After(ExpiresAt(settings, sess.IssuedAt)) {
+		return ErrSessionExpired
+	}
+	if settings.IdleTimeoutMinutes > 0 && now.Sub(lastActiveAt(sess)) > minutes(settings.IdleTimeoutMinutes) {
+		return ErrSessionIdle
+	}
+	return nil
+}
+
+// NeedsActivityUpdate returns true if the last activity of the session should be saved to slide its idle timeout
+func NeedsActivityUpdate(sess *types.Session, now time.Time) bool {
+	if sess.ID == CLISessionID {
+		return false
+	}
+	return now.Sub(lastActiveAt(sess)) >= activityInterval
+}
+
+// IsRecentLogin returns true if the session was issued within the re-auth window, or if the window is disabled
+func IsRecentLogin(sess *types.Session, settings types.SessionSettings, now time.Time) bool {
+	if settings.ReauthWindowMinutes <= 0 {
+		return true
+	}
+	return now.Sub(sess.IssuedAt) <= minutes(settings.ReauthWindowMinutes)
+}
+
+func lastActiveAt(sess *types.Session) time.Time {
+	if sess.LastActiveAt.IsZero() {
+		return sess.IssuedAt
+	}
+	return sess.LastActiveAt
+}
+
+func minutes(m int64) time.Duration {
+	return time.Duration(m) * time.Minute
+}
@@ -0,0 +1,97 @@
+package session
+
+import (
+	"testing"
+	"time"
+
+	"github.com/replicatedhq/kots/pkg/session/types"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_CheckActive(t *testing.T) {
+	now := time.Date(2021, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		sess     types.Session
+		settings types.SessionSettings
+		wantErr  error
+	}{
+		{
+			name: "within lifetime",
+			sess: types.Session{
+				IssuedAt:  now.Add(-time.Hour),
+				ExpiresAt: now.Add(time.Hour),
+			},
+			settings: types.SessionSettings{TTLMinutes: 120},
+		},
+		{
+			name: "past expiry",
+			sess: types.Session{
+				IssuedAt:  now.Add(-3 * time.Hour),
+				ExpiresAt: now.Add(-time.Hour),
+			},
+			settings: DefaultSettings(),
+			wantErr:  ErrSessionExpired,
+		},
+		{
+			name: "ttl shortened after login",
+			sess: types.Session{
+				IssuedAt:  now.Add(-2 * time.Hour),
+				ExpiresAt: now.Add(24 * time.Hour),
+			},
+			settings: types.SessionSettings{TTLMinutes: 60},
+			wantErr:  ErrSessionExpired,
+		},
+		{
+			name: "idle since login",
+			sess: types.Session{
+				IssuedAt:  now.Add(-time.Hour),
+				ExpiresAt: now.Add(time.Hour),
+			},
+			settings: types.SessionSettings{TTLMinutes: 120, IdleTimeoutMinutes: 30},
+			wantErr:  ErrSessionIdle,
+		},
+		{
+			name: "activity slides the idle timeout",
+			sess: types.Session{
+				IssuedAt:     now.Add(-time.Hour),
+				ExpiresAt:    now.Add(time.Hour),
+				LastActiveAt: now.Add(-10 * time.Minute),
+			},
+			settings: types.SessionSettings{TTLMinutes: 120, IdleTimeoutMinutes: 30},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := CheckActive(&test.sess, test.settings, now)
+			require.Equal(t, test.wantErr, err)
+		})
+	}
+}
+
+func Test_NeedsActivityUpdate(t *testing.T) {
+	now := time.Now()
+
+	require.False(t, NeedsActivityUpdate(&types.Session{ID: "a", IssuedAt: now}, now))
+	require.True(t, NeedsActivityUpdate(&types.Session{ID: "a", IssuedAt: now.Add(-time.Hour)}, now))
+	require.False(t, NeedsActivityUpdate(&types.Session{ID: "a", IssuedAt: now.Add(-time.Hour), LastActiveAt: now.Add(-time.Second)}, now))
+	require.False(t, NeedsActivityUpdate(&types.Session{ID: CLISessionID, IssuedAt: now.Add(-time.Hour)}, now))
+}
+
+func Test_IsRecentLogin(t *testing.T) {
+	now := time.Now()
+	sess := &types.Session{IssuedAt: now.Add(-20 * time.Minute)}
+
+	require.True(t, IsRecentLogin(sess, types.SessionSettings{TTLMinutes: 60}, now))
+	require.True(t, IsRecentLogin(sess, types.SessionSettings{TTLMinutes: 60, ReauthWindowMinutes: 30}, now))
+	require.False(t, IsRecentLogin(sess, types.SessionSettings{TTLMinutes: 60, ReauthWindowMinutes: 15}, now))
+}
+
+func Test_ValidateSettings(t *testing.T) {
+	require.NoError(t, ValidateSettings(DefaultSettings()))
+	require.Error(t, ValidateSettings(types.SessionSettings{}))
+	require.Error(t, ValidateSettings(types.SessionSettings{TTLMinutes: 60, IdleTimeoutMinutes: -1}))
+	require.Error(t, ValidateSettings(types.SessionSettings{TTLMinutes: 60, ReauthWindowMinutes: -1}))
+}
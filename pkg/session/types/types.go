@@ -4,8 +4,21 @@ import "time"
 
 type Session struct {
 	ID        string
+	UserID    string // empty for sessions that were created before the user was recorded
 	IssuedAt  time.Time
 	ExpiresAt time.Time
-	Roles     []string
-	HasRBAC   bool
+	// LastActiveAt is the time of the last request made with the session, zero if none was recorded
+	LastActiveAt time.Time
+	Roles        []string
+	HasRBAC      bool
+}
+
+// SessionSettings control how long admin console sessions last. An idle timeout or re-auth window of 0 is disabled.
+type SessionSettings struct {
+	// TTLMinutes is the maximum lifetime of a session from login
+	TTLMinutes int64 `json:"ttlMinutes"`
+	// IdleTimeoutMinutes ends a session that has made no requests for this long. Every request slides the window.
+	IdleTimeoutMinutes int64 `json:"idleTimeoutMinutes"`
+	// ReauthWindowMinutes is how recent the login must be for sensitive operations, like restoring a snapshot
+	ReauthWindowMinutes int64 `json:"reauthWindowMinutes"`
 }
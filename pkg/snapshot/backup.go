@@ -12,6 +12,7 @@ import (
 	"github.com/replicatedhq/kots/pkg/auth"
 	"github.com/replicatedhq/kots/pkg/k8sutil"
 	"github.com/replicatedhq/kots/pkg/logger"
+	"github.com/replicatedhq/kots/pkg/versionskew"
 	velerov1 "github.com/vmware-tanzu/velero/pkg/apis/velero/v1"
 	veleroclientv1 "github.com/vmware-tanzu/velero/pkg/generated/clientset/versioned/typed/velero/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -69,6 +70,12 @@ func CreateInstanceBackup(ctx context.Context, options CreateInstanceBackupOptio
 		}
 	}()
 
+	client, err := versionskew.NewClient(fmt.Sprintf("http://localhost:%d", localPort), log)
+	if err != nil {
+		log.FinishSpinnerWithError()
+		return err
+	}
+
 	log.FinishSpinner()
 	log.ActionWithSpinner("Creating Backup")
 
@@ -87,7 +94,7 @@ func CreateInstanceBackup(ctx context.Context, options CreateInstanceBackupOptio
 	}
 	newRequest.Header.Add("Authorization", authSlug)
 
-	resp, err := http.DefaultClient.Do(newRequest)
+	resp, err := client.Do(newRequest)
 	if err != nil {
 		log.FinishSpinnerWithError()
 		return errors.Wrap(err, "failed to get from kotsadm")
@@ -17,8 +17,6 @@ import (
 	"github.com/Azure/go-autorest/autorest/azure"
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/credentials"
-	"github.com/aws/aws-sdk-go/aws/credentials/ec2rolecreds"
-	"github.com/aws/aws-sdk-go/aws/ec2metadata"
 	"github.com/aws/aws-sdk-go/aws/endpoints"
 	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/aws/aws-sdk-go/service/s3"
@@ -982,14 +980,9 @@ func validateAWS(storeAWS *types.StoreAWS, bucket string) error {
 		S3ForcePathStyle: aws.Bool(false), // TODO: this may need to be configurable
 	}
 
-	if storeAWS.UseInstanceRole {
-		s3Config.Credentials = credentials.NewChainCredentials([]credentials.Provider{
-			&ec2rolecreds.EC2RoleProvider{
-				Client:       ec2metadata.New(session.New()),
-				ExpiryWindow: 5 * time.Minute,
-			},
-		})
-	} else {
+	// with the instance role the default credential chain is used, which includes the web identity token of iam
+	// roles for service accounts as well as the instance role of the node
+	if !storeAWS.UseInstanceRole {
 		s3Config.Credentials = credentials.NewStaticCredentials(storeAWS.AccessKeyID, storeAWS.SecretAccessKey, "")
 	}
 
@@ -2,6 +2,7 @@ package snapshotscheduler
 
 import (
 	"context"
+	"fmt"
 	"os"
 	"strings"
 	"time"
@@ -9,13 +10,14 @@ import (
 	"github.com/pkg/errors"
 	downstreamtypes "github.com/replicatedhq/kots/pkg/api/downstream/types"
 	apptypes "github.com/replicatedhq/kots/pkg/app/types"
+	"github.com/replicatedhq/kots/pkg/cronschedule"
 	snapshot "github.com/replicatedhq/kots/pkg/kotsadmsnapshot"
 	snapshottypes "github.com/replicatedhq/kots/pkg/kotsadmsnapshot/types"
 	"github.com/replicatedhq/kots/pkg/logger"
+	"github.com/replicatedhq/kots/pkg/scheduledjob"
+	scheduledjobtypes "github.com/replicatedhq/kots/pkg/scheduledjob/types"
 	"github.com/replicatedhq/kots/pkg/store"
 	"k8s.io/apimachinery/pkg/util/rand"
-
-	cron "github.com/robfig/cron/v3"
 )
 
 func Start() error {
@@ -44,7 +46,7 @@ func appScheduleLoop() {
 	}
 
 	for _, a := range appsList {
-		if a.RestoreInProgressName != "" {
+		if a.RestoreInProgressName != "" || a.IsArchived {
 			continue
 		}
 		if err := handleApp(a); err != nil {
@@ -95,7 +97,7 @@ func handleApp(a *apptypes.App) error {
 
 	if len(pending) == 0 {
 		logger.Infof("No pending snapshots scheduled for app %s with schedule %s. Queueing one.", a.ID, a.SnapshotSchedule)
-		queued, err := nextScheduledApplicationSnapshot(a.ID, a.SnapshotSchedule)
+		queued, err := nextScheduledApplicationSnapshot(a.ID, a.SnapshotSchedule, a.ScheduleTimezone)
 		if err != nil {
 			return errors.Wrap(err, "failed to get next schedule")
 		}
@@ -120,15 +122,22 @@ func handleApp(a *apptypes.App) error {
 		return nil
 	}
 
-	backup, err := snapshot.CreateApplicationBackup(context.Background(), a, true)
-	if err != nil {
-		return errors.Wrap(err, "failed to create backup")
-	}
+	err = scheduledjob.Run(scheduledjobtypes.JobSnapshot, a.ID, func() (string, error) {
+		backup, err := snapshot.CreateApplicationBackup(context.Background(), a, true)
+		if err != nil {
+			return "", errors.Wrap(err, "failed to create backup")
+		}
+
+		if err := store.GetStore().UpdateScheduledSnapshot(next.ID, backup.ObjectMeta.Name); err != nil {
+			return "", errors.Wrap(err, "failed to update scheduled snapshot")
+		}
+		logger.Infof("Created application backup %s from scheduled snapshot %s", backup.ObjectMeta.Name, next.ID)
 
-	if err := store.GetStore().UpdateScheduledSnapshot(next.ID, backup.ObjectMeta.Name); err != nil {
-		return errors.Wrap(err, "failed to update scheduled snapshot")
+		return fmt.Sprintf("created backup %s", backup.ObjectMeta.Name), nil
+	})
+	if err != nil {
+		return err
 	}
-	logger.Infof("Created application backup %s from scheduled snapshot %s", backup.ObjectMeta.Name, next.ID)
 
 	if len(pending) > 1 {
 		err := store.GetStore().DeletePendingScheduledSnapshots(a.ID)
@@ -137,7 +146,7 @@ func handleApp(a *apptypes.App) error {
 		}
 	}
 
-	queued, err := nextScheduledApplicationSnapshot(a.ID, a.SnapshotSchedule)
+	queued, err := nextScheduledApplicationSnapshot(a.ID, a.SnapshotSchedule, a.ScheduleTimezone)
 	if err != nil {
 		return errors.Wrap(err, "failed to get next schedule")
 	}
@@ -203,15 +212,22 @@ func handleCluster(c *downstreamtypes.Downstream) error {
 		return nil
 	}
 
-	backup, err := snapshot.CreateInstanceBackup(context.Background(), c, true)
-	if err != nil {
-		return errors.Wrap(err, "failed to create instance backup")
-	}
+	err = scheduledjob.Run(scheduledjobtypes.JobInstanceSnapshot, "", func() (string, error) {
+		backup, err := snapshot.CreateInstanceBackup(context.Background(), c, true)
+		if err != nil {
+			return "", errors.Wrap(err, "failed to create instance backup")
+		}
 
-	if err := store.GetStore().UpdateScheduledInstanceSnapshot(next.ID, backup.ObjectMeta.Name); err != nil {
-		return errors.Wrap(err, "failed to update scheduled instance snapshot")
+		if err := store.GetStore().UpdateScheduledInstanceSnapshot(next.ID, backup.ObjectMeta.Name); err != nil {
+			return "", errors.Wrap(err, "failed to update scheduled instance snapshot")
+		}
+		logger.Infof("Created instance backup %s from scheduled instance snapshot %s", backup.ObjectMeta.Name, next.ID)
+
+		return fmt.Sprintf("created backup %s", backup.ObjectMeta.Name), nil
+	})
+	if err != nil {
+		return err
 	}
-	logger.Infof("Created instance backup %s from scheduled instance snapshot %s", backup.ObjectMeta.Name, next.ID)
 
 	if len(pending) > 1 {
 		err := store.GetStore().DeletePendingScheduledInstanceSnapshots(c.ClusterID)
@@ -233,8 +249,9 @@ func handleCluster(c *downstreamtypes.Downstream) error {
 	return nil
 }
 
-func nextScheduledApplicationSnapshot(appID string, cronExpression string) (*snapshottypes.ScheduledSnapshot, error) {
-	cronSchedule, err := cron.ParseStandard(cronExpression)
+// nextScheduledApplicationSnapshot runs in the timezone of the app unless the expression sets one with CRON_TZ
+func nextScheduledApplicationSnapshot(appID string, cronExpression string, timezone string) (*snapshottypes.ScheduledSnapshot, error) {
+	cronSchedule, err := cronschedule.Parse(cronExpression, timezone)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to parse cron expression")
 	}
@@ -249,7 +266,7 @@ func nextScheduledApplicationSnapshot(appID string, cronExpression string) (*sna
 }
 
 func nextScheduledInstanceSnapshot(clusterID string, cronExpression string) (*snapshottypes.ScheduledInstanceSnapshot, error) {
-	cronSchedule, err := cron.ParseStandard(cronExpression)
+	cronSchedule, err := cronschedule.Parse(cronExpression, "")
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to parse cron expression")
 	}
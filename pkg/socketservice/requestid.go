@@ -0,0 +1,56 @@
+package socketservice
+
+import (
+	"context"
+	"sync"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+type deployRequest struct {
+	sequence    int64
+	requestID   string
+	spanContext trace.SpanContext
+}
+
+// deployRequests tracks the id of the api request that triggered the latest deploy of each app so that
+// the operator actions and deploy results for that version can be correlated with it
+var deployRequests = map[string]deployRequest{}
+var deployRequestsMtx sync.Mutex
+
+// SetDeployRequestID records the id and the span of the request that triggered the deploy of the sequence
+func SetDeployRequestID(ctx context.Context, appID string, sequence int64, requestID string) {
+	deployRequestsMtx.Lock()
+	defer deployRequestsMtx.Unlock()
+
+	deployRequests[appID] = deployRequest{
+		sequence:    sequence,
+		requestID:   requestID,
+		spanContext: trace.SpanContextFromContext(ctx),
+	}
+}
+
+// GetDeployRequestID returns the id of the request that triggered the deploy of the sequence, if known
+func GetDeployRequestID(appID string, sequence int64) string {
+	deployRequestsMtx.Lock()
+	defer deployRequestsMtx.Unlock()
+
+	d, ok := deployRequests[appID]
+	if !ok || d.sequence != sequence {
+		return ""
+	}
+	return d.requestID
+}
+
+// getDeployContext returns a context with the span of the request that triggered the deploy of the sequence, so
+// that the deploy span is its child. It is the background context if the request is not known.
+func getDeployContext(appID string, sequence int64) context.Context {
+	deployRequestsMtx.Lock()
+	defer deployRequestsMtx.Unlock()
+
+	d, ok := deployRequests[appID]
+	if !ok || d.sequence != sequence || !d.spanContext.IsValid() {
+		return context.Background()
+	}
+	return trace.ContextWithRemoteSpanContext(context.Background(), d.spanContext)
+}
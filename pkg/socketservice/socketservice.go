@@ -10,31 +10,44 @@ import (
 	"os/exec"
 	"path/filepath"
 	"strconv"
+	"strings"
 	"sync"
 	"time"
 
 	"github.com/pkg/errors"
+	kotsv1beta1 "github.com/replicatedhq/kots/kotskinds/apis/kots/v1beta1"
 	"github.com/replicatedhq/kots/kotskinds/multitype"
+	"github.com/replicatedhq/kots/pkg/admission"
 	appstatustypes "github.com/replicatedhq/kots/pkg/api/appstatus/types"
 	downstreamtypes "github.com/replicatedhq/kots/pkg/api/downstream/types"
 	"github.com/replicatedhq/kots/pkg/app"
 	apptypes "github.com/replicatedhq/kots/pkg/app/types"
+	"github.com/replicatedhq/kots/pkg/crypto"
+	deployhistorytypes "github.com/replicatedhq/kots/pkg/deployhistory/types"
+	"github.com/replicatedhq/kots/pkg/downstream"
 	identitydeploy "github.com/replicatedhq/kots/pkg/identity/deploy"
 	identitytypes "github.com/replicatedhq/kots/pkg/identity/types"
 	snapshot "github.com/replicatedhq/kots/pkg/kotsadmsnapshot"
 	"github.com/replicatedhq/kots/pkg/kotsutil"
 	"github.com/replicatedhq/kots/pkg/logger"
+	"github.com/replicatedhq/kots/pkg/manifestsigning"
 	"github.com/replicatedhq/kots/pkg/midstream"
+	"github.com/replicatedhq/kots/pkg/postdeploytest"
 	"github.com/replicatedhq/kots/pkg/redact"
 	"github.com/replicatedhq/kots/pkg/render"
 	"github.com/replicatedhq/kots/pkg/reporting"
+	"github.com/replicatedhq/kots/pkg/sensitiveconfig"
 	"github.com/replicatedhq/kots/pkg/socket"
 	"github.com/replicatedhq/kots/pkg/socket/transport"
 	"github.com/replicatedhq/kots/pkg/store"
 	"github.com/replicatedhq/kots/pkg/supportbundle"
+	"github.com/replicatedhq/kots/pkg/tracing"
 	"github.com/replicatedhq/kots/pkg/util"
+	"github.com/replicatedhq/kots/pkg/vault"
 	"github.com/replicatedhq/kots/pkg/version"
 	velerov1 "github.com/vmware-tanzu/velero/pkg/apis/velero/v1"
+	"go.opentelemetry.io/otel/attribute"
+	"go.uber.org/zap"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -42,24 +55,53 @@ type ClusterSocket struct {
 	ClusterID             string
 	SocketID              string
 	LastDeployedSequences map[string]int64
+	// LastActivatedSequences are the sequences of the apps deployed in two phases that were sent to be activated
+	LastActivatedSequences map[string]int64
 }
 
+const (
+	// DeployPhaseInstall applies the resources of a version alongside the resources of the active version, the
+	// resources that switch traffic are held back until the version is activated
+	DeployPhaseInstall = "install"
+	// DeployPhaseActivate applies all resources of the version and removes the resources of the previously
+	// activated version
+	DeployPhaseActivate = "activate"
+)
+
 type DeployArgs struct {
-	AppID                string                `json:"app_id"`
-	AppSlug              string                `json:"app_slug"`
-	KubectlVersion       string                `json:"kubectl_version"`
-	AdditionalNamespaces []string              `json:"additional_namespaces"`
-	ImagePullSecret      string                `json:"image_pull_secret"`
-	Namespace            string                `json:"namespace"`
-	PreviousManifests    string                `json:"previous_manifests"`
-	Manifests            string                `json:"manifests"`
-	Wait                 bool                  `json:"wait"`
-	ResultCallback       string                `json:"result_callback"`
-	ClearNamespaces      []string              `json:"clear_namespaces"`
-	ClearPVCs            bool                  `json:"clear_pvcs"`
-	AnnotateSlug         bool                  `json:"annotate_slug"`
-	IsRestore            bool                  `json:"is_restore"`
-	RestoreLabelSelector *metav1.LabelSelector `json:"restore_label_selector"`
+	AppID                string                             `json:"app_id"`
+	AppSlug              string                             `json:"app_slug"`
+	KubectlVersion       string                             `json:"kubectl_version"`
+	AdditionalNamespaces []string                           `json:"additional_namespaces"`
+	ManagedNamespaces    []kotsv1beta1.ApplicationNamespace `json:"managed_namespaces,omitempty"`
+	ImagePullSecret      string                             `json:"image_pull_secret"`
+	Namespace            string                             `json:"namespace"`
+	PreviousManifests    string                             `json:"previous_manifests"`
+	Manifests            string                             `json:"manifests"`
+	Wait                 bool                               `json:"wait"`
+	ResultCallback       string                             `json:"result_callback"`
+	ClearNamespaces      []string                           `json:"clear_namespaces"`
+	ClearPVCs            bool                               `json:"clear_pvcs"`
+	AnnotateSlug         bool                               `json:"annotate_slug"`
+	IsRestore            bool                               `json:"is_restore"`
+	RestoreLabelSelector *metav1.LabelSelector              `json:"restore_label_selector"`
+	RequestID            string                             `json:"request_id,omitempty"`
+	// Impersonate is the user that the operator applies the manifests as, the operator's own identity is used if empty
+	Impersonate string `json:"impersonate,omitempty"`
+	// ApplyRetries, ApplyRetryBackoffSeconds, KubectlTimeoutSeconds and PhaseWaitSeconds are the app's apply policy,
+	// the operator's defaults are used if zero
+	ApplyRetries             int `json:"apply_retries,omitempty"`
+	ApplyRetryBackoffSeconds int `json:"apply_retry_backoff_seconds,omitempty"`
+	KubectlTimeoutSeconds    int `json:"kubectl_timeout_seconds,omitempty"`
+	PhaseWaitSeconds         int `json:"phase_wait_seconds,omitempty"`
+	// AdoptExistingResources has the operator adopt existing resources that were not applied for the app
+	AdoptExistingResources bool `json:"adopt_existing_resources,omitempty"`
+	// ReapplyCriticalResources has the operator apply the critical resources of the app again when they are deleted
+	ReapplyCriticalResources bool `json:"reapply_critical_resources,omitempty"`
+	// ManifestsSignature is the signature of the manifests, the operator does not apply manifests that do not match it
+	ManifestsSignature string `json:"manifests_signature,omitempty"`
+	// DeployPhase is set if the app is deployed in two phases
+	DeployPhase string `json:"deploy_phase,omitempty"`
 }
 
 type AppInformersArgs struct {
@@ -68,6 +110,14 @@ type AppInformersArgs struct {
 	Sequence  int64    `json:"sequence"`
 }
 
+type StopAppInformersArgs struct {
+	AppID string `json:"app_id"`
+}
+
+type LogSettingsArgs struct {
+	Level string `json:"level"`
+}
+
 var server *socket.Server
 var clusterSocketHistory = []*ClusterSocket{}
 var socketMtx sync.Mutex
@@ -92,11 +142,15 @@ func Start() *socket.Server {
 		c.Join(clusterID)
 
 		clusterSocket := &ClusterSocket{
-			ClusterID:             clusterID,
-			SocketID:              c.Id(),
-			LastDeployedSequences: make(map[string]int64, 0),
+			ClusterID:              clusterID,
+			SocketID:               c.Id(),
+			LastDeployedSequences:  make(map[string]int64, 0),
+			LastActivatedSequences: make(map[string]int64, 0),
 		}
 		clusterSocketHistory = append(clusterSocketHistory, clusterSocket)
+
+		// operators start with their default log level
+		c.Emit("logSettings", LogSettingsArgs{Level: logger.GetLevel()})
 	})
 
 	server.On(socket.OnDisconnection, func(c *socket.Channel) {
@@ -152,12 +206,19 @@ func deployLoop() {
 			} else if deployed {
 				logger.Infof("Deploy success for app %s in cluster %s", a.ID, clusterSocket.ClusterID)
 			}
+
+			activated, err := processActivateSocketForApp(clusterSocket, a)
+			if err != nil {
+				logger.Error(errors.Wrapf(err, "failed to activate version of app %s in cluster %s", a.ID, clusterSocket.ClusterID))
+			} else if activated {
+				logger.Infof("Sent activation for app %s in cluster %s", a.ID, clusterSocket.ClusterID)
+			}
 		}
 	}
 }
 
 func processDeploySocketForApp(clusterSocket *ClusterSocket, a *apptypes.App) (bool, error) {
-	if a.RestoreInProgressName != "" {
+	if a.RestoreInProgressName != "" || a.IsArchived {
 		return false, nil
 	}
 
@@ -173,13 +234,63 @@ func processDeploySocketForApp(clusterSocket *ClusterSocket, a *apptypes.App) (b
 		return false, nil
 	}
 
-	if err := deployVersionForApp(clusterSocket, a, deployedVersion); err != nil {
+	_, span := tracing.Start(getDeployContext(a.ID, deployedVersion.Sequence), "socketservice.DeployVersion",
+		attribute.String("app.slug", a.Slug),
+		attribute.String("cluster.id", clusterSocket.ClusterID),
+		attribute.Int64("version.sequence", deployedVersion.Sequence),
+		attribute.String("request.id", GetDeployRequestID(a.ID, deployedVersion.Sequence)))
+	phase := ""
+	if deployedVersion.TwoPhaseDeploy {
+		phase = DeployPhaseInstall
+	}
+	err = deployVersionForApp(clusterSocket, a, deployedVersion, phase)
+	tracing.End(span, err)
+	if err != nil {
 		return false, errors.Wrap(err, "failed to deploy version")
 	}
 	return true, nil
 }
 
-func deployVersionForApp(clusterSocket *ClusterSocket, a *apptypes.App, deployedVersion *downstreamtypes.DownstreamVersion) error {
+// processActivateSocketForApp sends the version of an app deployed in two phases that is being activated to the
+// operator, once
+func processActivateSocketForApp(clusterSocket *ClusterSocket, a *apptypes.App) (bool, error) {
+	if a.RestoreInProgressName != "" || a.IsArchived {
+		return false, nil
+	}
+
+	sequence, err := store.GetStore().GetActivatingSequence(a.ID, clusterSocket.ClusterID)
+	if err != nil {
+		return false, errors.Wrap(err, "failed to get activating sequence")
+	} else if sequence == -1 {
+		return false, nil
+	}
+
+	if value, ok := clusterSocket.LastActivatedSequences[a.ID]; ok && value == sequence {
+		return false, nil
+	}
+
+	deployedVersion, err := store.GetStore().GetCurrentVersion(a.ID, clusterSocket.ClusterID)
+	if err != nil {
+		return false, errors.Wrap(err, "failed to get current downstream version")
+	}
+	if deployedVersion == nil || deployedVersion.Sequence != sequence {
+		// another version was deployed since the activation was requested
+		err := store.GetStore().SetDownstreamActivationResult(a.ID, clusterSocket.ClusterID, sequence, true, "the version is no longer deployed")
+		if err != nil {
+			return false, errors.Wrap(err, "failed to set activation result")
+		}
+		return false, nil
+	}
+
+	if err := deployVersionForApp(clusterSocket, a, deployedVersion, DeployPhaseActivate); err != nil {
+		return false, errors.Wrap(err, "failed to activate version")
+	}
+	return true, nil
+}
+
+// deployVersionForApp sends the manifests of the deployed version to the operator. The phase is empty unless the
+// app is deployed in two phases, activations are only sent to the operator, the status informers are not changed.
+func deployVersionForApp(clusterSocket *ClusterSocket, a *apptypes.App, deployedVersion *downstreamtypes.DownstreamVersion, phase string) error {
 	d, err := store.GetStore().GetDownstream(clusterSocket.ClusterID)
 	if err != nil {
 		return errors.Wrap(err, "failed to get downstream")
@@ -187,7 +298,12 @@ func deployVersionForApp(clusterSocket *ClusterSocket, a *apptypes.App, deployed
 
 	var deployError error
 	defer func() {
-		if deployError != nil {
+		if deployError != nil && phase == DeployPhaseActivate {
+			err := store.GetStore().SetDownstreamActivationResult(a.ID, clusterSocket.ClusterID, deployedVersion.Sequence, true, deployError.Error())
+			if err != nil {
+				logger.Error(errors.Wrap(err, "failed to set activation result"))
+			}
+		} else if deployError != nil {
 			err := store.GetStore().UpdateDownstreamVersionStatus(a.ID, deployedVersion.Sequence, "failed", deployError.Error())
 			if err != nil {
 				logger.Error(errors.Wrap(err, "failed to update downstream status"))
@@ -217,6 +333,12 @@ func deployVersionForApp(clusterSocket *ClusterSocket, a *apptypes.App, deployed
 		return deployError
 	}
 
+	ownershipLabels := kotsutil.OwnershipLabels(a.Slug, deployedVersion.VersionLabel, deployedVersion.Sequence)
+	if err := midstream.EnsureOwnershipLabelTransformer(deployedVersionArchive, ownershipLabels); err != nil {
+		deployError = errors.Wrap(err, "failed to ensure ownership label transformer")
+		return deployError
+	}
+
 	kotsKinds, err := kotsutil.LoadKotsKindsFromPath(deployedVersionArchive)
 	if err != nil {
 		deployError = errors.Wrap(err, "failed to load kotskinds")
@@ -260,6 +382,50 @@ func deployVersionForApp(clusterSocket *ClusterSocket, a *apptypes.App, deployed
 		deployError = errors.Wrap(err, "failed to run kustomize")
 		return deployError
 	}
+
+	// the values of sensitive config items are not in the archive, the secret that the manifests reference is created with the deploy.
+	// values that reference vault are only ever read here, so they are never written to disk.
+	if sensitiveconfig.IsEnabled(&kotsKinds.KotsApplication) || vault.HasVaultValues(kotsKinds.ConfigValues) {
+		cipher, err := crypto.AESCipherFromString(kotsKinds.Installation.Spec.EncryptionKey)
+		if err != nil {
+			deployError = errors.Wrap(err, "failed to load encryption cipher")
+			return deployError
+		}
+		configValues, err := vault.ResolveConfigValues(kotsKinds.ConfigValues)
+		if err != nil {
+			deployError = errors.Wrap(err, "failed to resolve config values from vault")
+			return deployError
+		}
+		sensitiveConfigSecret, err := sensitiveconfig.BuildSecretManifest(a.Slug, kotsKinds.Config, configValues, cipher)
+		if err != nil {
+			deployError = errors.Wrap(err, "failed to build sensitive config secret")
+			return deployError
+		}
+		renderedManifests = append(renderedManifests, []byte("\n---\n")...)
+		renderedManifests = append(renderedManifests, sensitiveConfigSecret...)
+	}
+
+	if a.AdmissionDryRun {
+		validation, err := admission.DryRun(renderedManifests, os.Getenv("POD_NAMESPACE"))
+		if err != nil {
+			deployError = errors.Wrap(err, "failed to dry run manifests")
+			return deployError
+		}
+		if err := store.GetStore().SetDownstreamAdmissionValidation(a.ID, clusterSocket.ClusterID, deployedVersion.Sequence, *validation); err != nil {
+			deployError = errors.Wrap(err, "failed to set admission validation")
+			return deployError
+		}
+		if len(validation.Rejected()) > 0 {
+			// do not retry until the version is redeployed, the manifests will be rejected again
+			socketMtx.Lock()
+			clusterSocket.LastDeployedSequences[a.ID] = deployedVersion.ParentSequence
+			socketMtx.Unlock()
+
+			deployError = errors.New(admission.RejectionMessage(validation))
+			return deployError
+		}
+	}
+
 	base64EncodedManifests := base64.StdEncoding.EncodeToString(renderedManifests)
 
 	imagePullSecret := ""
@@ -278,12 +444,23 @@ func deployVersionForApp(clusterSocket *ClusterSocket, a *apptypes.App, deployed
 		imagePullSecret = string(b)
 	}
 
-	// get previous manifests (if any)
+	// get previous manifests (if any), the install phase of a two phase deploy does not remove anything
 	base64EncodedPreviousManifests := ""
-	previouslyDeployedSequence, err := store.GetStore().GetPreviouslyDeployedSequence(a.ID, clusterSocket.ClusterID)
-	if err != nil {
-		deployError = errors.Wrap(err, "failed to get previously deployed sequence")
-		return deployError
+	previouslyDeployedSequence := int64(-1)
+	if phase == DeployPhaseActivate {
+		// the resources of the previously activated version are removed, not those of versions that were only installed
+		previouslyDeployedSequence, err = store.GetStore().GetActivatedSequence(a.ID, clusterSocket.ClusterID)
+		if err != nil {
+			deployError = errors.Wrap(err, "failed to get previously activated sequence")
+			return deployError
+		}
+	}
+	if previouslyDeployedSequence == -1 && phase != DeployPhaseInstall {
+		previouslyDeployedSequence, err = store.GetStore().GetPreviouslyDeployedSequence(a.ID, clusterSocket.ClusterID)
+		if err != nil {
+			deployError = errors.Wrap(err, "failed to get previously deployed sequence")
+			return deployError
+		}
 	}
 	if previouslyDeployedSequence != -1 {
 		previouslyDeployedParentSequence, err := store.GetStore().GetParentSequenceForSequence(a.ID, clusterSocket.ClusterID, previouslyDeployedSequence)
@@ -325,18 +502,50 @@ func deployVersionForApp(clusterSocket *ClusterSocket, a *apptypes.App, deployed
 		}
 	}
 
+	impersonate, err := getDeployImpersonateUser(a.ID, clusterSocket.ClusterID)
+	if err != nil {
+		deployError = errors.Wrap(err, "failed to get deploy service account")
+		return deployError
+	}
+
+	additionalNamespaces := kotsutil.AdditionalNamespaces(&kotsKinds.KotsApplication)
+	if a.Namespace != "" {
+		// the operator creates the additional namespaces and copies the image pull secret into them
+		additionalNamespaces = append(additionalNamespaces, a.Namespace)
+	}
+
 	deployArgs := DeployArgs{
 		AppID:                a.ID,
 		AppSlug:              a.Slug,
 		KubectlVersion:       kotsKinds.KotsApplication.Spec.KubectlVersion,
-		AdditionalNamespaces: kotsKinds.KotsApplication.Spec.AdditionalNamespaces,
+		AdditionalNamespaces: additionalNamespaces,
+		ManagedNamespaces:    kotsKinds.KotsApplication.Spec.Namespaces,
 		ImagePullSecret:      imagePullSecret,
-		Namespace:            ".",
+		Namespace:            targetNamespace(a),
 		Manifests:            base64EncodedManifests,
 		PreviousManifests:    base64EncodedPreviousManifests,
 		ResultCallback:       "/api/v1/deploy/result",
-		Wait:                 false,
+		Wait:                 a.ApplyPolicy.WaitForResources,
 		AnnotateSlug:         os.Getenv("ANNOTATE_SLUG") != "",
+		RequestID:            GetDeployRequestID(a.ID, deployedVersion.Sequence),
+		Impersonate:          impersonate,
+		DeployPhase:          phase,
+	}
+	if phase == DeployPhaseActivate {
+		deployArgs.ResultCallback = "/api/v1/activate/result"
+	}
+	setApplyPolicyArgs(&deployArgs, a.ApplyPolicy)
+
+	if err := signDeployArgs(&deployArgs); err != nil {
+		deployError = errors.Wrap(err, "failed to sign manifests")
+		return deployError
+	}
+
+	if phase != DeployPhaseActivate {
+		if err := postdeploytest.DeleteJobs(kotsKinds.KotsApplication.Spec.PostDeployTests); err != nil {
+			deployError = errors.Wrap(err, "failed to delete post deploy test jobs")
+			return deployError
+		}
 	}
 
 	c, err := server.GetChannel(clusterSocket.SocketID)
@@ -346,6 +555,20 @@ func deployVersionForApp(clusterSocket *ClusterSocket, a *apptypes.App, deployed
 	// Event is sent here
 	c.Emit("deploy", deployArgs)
 
+	logger.InfoFields("sent deploy to operator",
+		logger.RequestID(deployArgs.RequestID),
+		zap.String("app_id", a.ID),
+		zap.String("cluster_id", clusterSocket.ClusterID),
+		zap.Int64("sequence", deployedVersion.Sequence),
+		zap.String("phase", phase))
+
+	if phase == DeployPhaseActivate {
+		socketMtx.Lock()
+		clusterSocket.LastActivatedSequences[a.ID] = deployedVersion.Sequence
+		socketMtx.Unlock()
+		return nil
+	}
+
 	socketMtx.Lock()
 	clusterSocket.LastDeployedSequences[a.ID] = deployedVersion.ParentSequence
 	socketMtx.Unlock()
@@ -364,6 +587,10 @@ func deployVersionForApp(clusterSocket *ClusterSocket, a *apptypes.App, deployed
 			if renderedInformer == "" {
 				continue
 			}
+			if a.Namespace != "" && strings.Count(renderedInformer, "/") == 1 {
+				// informers without a namespace watch the namespace that the app is deployed to
+				renderedInformer = fmt.Sprintf("%s/%s", a.Namespace, renderedInformer)
+			}
 			renderedInformers = append(renderedInformers, renderedInformer)
 		}
 	}
@@ -461,7 +688,8 @@ func handleUndeployCompleted(clusterSocket *ClusterSocket, a *apptypes.App) erro
 	if err != nil {
 		return errors.Wrap(err, "failed to get backup")
 	}
-	if backup.Annotations["kots.io/instance"] == "true" {
+	if backup.Annotations["kots.io/instance"] == "true" || backup.Annotations["kots.io/app-id"] != a.ID {
+		// restores of instance snapshots and restores into a clone of the app that was backed up are named after the app
 		restoreName = fmt.Sprintf("%s.%s", snapshotName, a.Slug)
 	}
 
@@ -501,7 +729,14 @@ func checkRestoreComplete(clusterSocket *ClusterSocket, a *apptypes.App, restore
 		}
 
 		var sequence int64 = 0
-		if backupAnnotations["kots.io/instance"] == "true" {
+		if sequenceStr, ok := restore.Annotations[snapshot.RestoreSequenceAnnotation]; ok {
+			// the sequences in the backup are of the app that was backed up, not of the clone it was restored into
+			s, err := strconv.ParseInt(sequenceStr, 10, 64)
+			if err != nil {
+				return errors.Wrap(err, "failed to parse restore sequence")
+			}
+			sequence = s
+		} else if backupAnnotations["kots.io/instance"] == "true" {
 			b, ok := backupAnnotations["kots.io/apps-sequences"]
 			if !ok || b == "" {
 				return errors.New("instance backup is missing apps sequences annotation")
@@ -534,12 +769,18 @@ func checkRestoreComplete(clusterSocket *ClusterSocket, a *apptypes.App, restore
 
 		// mark the sequence as deployed both in the db and socket history
 		// so that the admin console does not try to re-deploy it
-		if err := version.DeployVersion(a.ID, sequence); err != nil {
+		deployer := deployhistorytypes.Deployer{DeployedBy: deployhistorytypes.DeployedBySnapshotRestore}
+		if err := version.DeployVersion(a.ID, sequence, deployer); err != nil {
 			return errors.Wrap(err, "failed to mark app version as deployed")
 		}
-		socketMtx.Lock()
-		clusterSocket.LastDeployedSequences[a.ID] = sequence
-		socketMtx.Unlock()
+		if restore.Annotations[snapshot.CloneRestoreSourceAnnotation] == "" {
+			socketMtx.Lock()
+			clusterSocket.LastDeployedSequences[a.ID] = sequence
+			socketMtx.Unlock()
+		} else {
+			// the restored resources still have the labels of the app that was backed up, the clone is deployed over them
+			logger.Info(fmt.Sprintf("restored clone of app %s, deploying version %d", restore.Annotations[snapshot.CloneRestoreSourceAnnotation], sequence))
+		}
 
 		if err := createSupportBundleSpec(a.ID, sequence, "", true); err != nil {
 			// support bundle is not essential.  keep processing restore status
@@ -652,11 +893,16 @@ func undeployApp(a *apptypes.App, d *downstreamtypes.Downstream, clusterSocket *
 	}
 	restoreLabelSelector.MatchLabels["kots.io/app-slug"] = a.Slug
 
+	impersonate, err := getDeployImpersonateUser(a.ID, clusterSocket.ClusterID)
+	if err != nil {
+		return errors.Wrap(err, "failed to get deploy service account")
+	}
+
 	args := DeployArgs{
 		AppID:                a.ID,
 		AppSlug:              a.Slug,
 		KubectlVersion:       kotsKinds.KotsApplication.Spec.KubectlVersion,
-		Namespace:            ".",
+		Namespace:            targetNamespace(a),
 		Manifests:            "",
 		PreviousManifests:    base64EncodedManifests,
 		ResultCallback:       "/api/v1/undeploy/result",
@@ -665,6 +911,12 @@ func undeployApp(a *apptypes.App, d *downstreamtypes.Downstream, clusterSocket *
 		ClearPVCs:            true,
 		IsRestore:            isRestore,
 		RestoreLabelSelector: restoreLabelSelector,
+		Impersonate:          impersonate,
+	}
+	setApplyPolicyArgs(&args, a.ApplyPolicy)
+
+	if err := signDeployArgs(&args); err != nil {
+		return errors.Wrap(err, "failed to sign manifests")
 	}
 
 	c, err := server.GetChannel(clusterSocket.SocketID)
@@ -680,10 +932,67 @@ func undeployApp(a *apptypes.App, d *downstreamtypes.Downstream, clusterSocket *
 	return nil
 }
 
+// signDeployArgs sets the signature of the manifests that the operator verifies before it applies or deletes them
+func signDeployArgs(args *DeployArgs) error {
+	signature, err := manifestsigning.SignManifests(manifestsigning.Manifests{
+		AppID:             args.AppID,
+		Namespace:         args.Namespace,
+		Manifests:         args.Manifests,
+		PreviousManifests: args.PreviousManifests,
+		ImagePullSecret:   args.ImagePullSecret,
+		ClearNamespaces:   args.ClearNamespaces,
+		DeployPhase:       args.DeployPhase,
+	})
+	if err != nil {
+		return err
+	}
+	args.ManifestsSignature = signature
+
+	return nil
+}
+
+// targetNamespace returns the namespace that the operator deploys the app's manifests to.
+// "." is the namespace of the operator.
+func targetNamespace(a *apptypes.App) string {
+	if a.Namespace == "" {
+		return "."
+	}
+	return a.Namespace
+}
+
+// getDeployImpersonateUser returns the user that the operator impersonates to apply the app's manifests to the
+// cluster, or an empty string if no service account is configured for the downstream
+func getDeployImpersonateUser(appID string, clusterID string) (string, error) {
+	serviceAccount, err := store.GetStore().GetDownstreamServiceAccount(appID, clusterID)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to get downstream service account")
+	}
+	if serviceAccount == "" {
+		return "", nil
+	}
+
+	username, err := downstream.ServiceAccountUsername(serviceAccount)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to get service account username")
+	}
+
+	return username, nil
+}
+
+// setApplyPolicyArgs sets the retries, timeouts and waits that the operator uses to apply the manifests
+func setApplyPolicyArgs(args *DeployArgs, applyPolicy apptypes.ApplyPolicy) {
+	args.ApplyRetries = applyPolicy.Retries
+	args.ApplyRetryBackoffSeconds = applyPolicy.RetryBackoffSeconds
+	args.KubectlTimeoutSeconds = applyPolicy.KubectlTimeoutSeconds
+	args.PhaseWaitSeconds = applyPolicy.PhaseWaitSeconds
+	args.AdoptExistingResources = applyPolicy.AdoptExistingResources
+	args.ReapplyCriticalResources = applyPolicy.ReapplyDeletedCriticalResources
+}
+
 // RedeployAppVersion will force trigger a redeploy of the app version, even if it's currently deployed
 // if clusterSocket is nil, a redeploy to all the cluster sockets (downstreams - which theoratically should always be 1) will be triggered
-func RedeployAppVersion(appID string, sequence int64, clusterSocket *ClusterSocket) error {
-	if err := version.DeployVersion(appID, sequence); err != nil {
+func RedeployAppVersion(appID string, sequence int64, clusterSocket *ClusterSocket, deployer deployhistorytypes.Deployer) error {
+	if err := version.DeployVersion(appID, sequence, deployer); err != nil {
 		return errors.Wrap(err, "failed to deploy version")
 	}
 
@@ -700,3 +1009,67 @@ func RedeployAppVersion(appID string, sequence int64, clusterSocket *ClusterSock
 
 	return nil
 }
+
+// ActivateAppVersion starts the activation of the deployed version of an app that is deployed in two phases. The
+// version is sent to the operator again even if an earlier activation of it failed.
+func ActivateAppVersion(appID string, clusterID string, sequence int64, deployer deployhistorytypes.Deployer) error {
+	if err := version.ActivateVersion(appID, clusterID, sequence, deployer); err != nil {
+		return err
+	}
+
+	socketMtx.Lock()
+	defer socketMtx.Unlock()
+
+	for _, clusterSocket := range clusterSocketHistory {
+		if clusterSocket.ClusterID == clusterID {
+			delete(clusterSocket.LastActivatedSequences, appID)
+		}
+	}
+
+	return nil
+}
+
+// StopAppInformers tells the operators to stop watching the status informers of an archived app.
+// The workloads of the app are not changed.
+func StopAppInformers(appID string) error {
+	socketMtx.Lock()
+	defer socketMtx.Unlock()
+
+	for _, clusterSocket := range clusterSocketHistory {
+		c, err := server.GetChannel(clusterSocket.SocketID)
+		if err != nil {
+			return errors.Wrapf(err, "failed to get socket channel for cluster %s", clusterSocket.ClusterID)
+		}
+		c.Emit("stopAppInformers", StopAppInformersArgs{AppID: appID})
+	}
+
+	return nil
+}
+
+// SetOperatorLogLevel changes the log level of the connected operators. Operators that connect later get the level
+// when they connect.
+func SetOperatorLogLevel(level string) error {
+	socketMtx.Lock()
+	defer socketMtx.Unlock()
+
+	for _, clusterSocket := range clusterSocketHistory {
+		c, err := server.GetChannel(clusterSocket.SocketID)
+		if err != nil {
+			return errors.Wrapf(err, "failed to get socket channel for cluster %s", clusterSocket.ClusterID)
+		}
+		c.Emit("logSettings", LogSettingsArgs{Level: level})
+	}
+
+	return nil
+}
+
+// ResumeAppInformers makes the deploy loop send the deployed version of an unarchived app to the operators again.
+// The manifests are applied again and the status informers of the app are restarted.
+func ResumeAppInformers(appID string) {
+	socketMtx.Lock()
+	defer socketMtx.Unlock()
+
+	for _, clusterSocket := range clusterSocketHistory {
+		delete(clusterSocket.LastDeployedSequences, appID)
+	}
+}
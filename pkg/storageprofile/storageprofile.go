@@ -0,0 +1,218 @@
+package storageprofile
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+type Provider string
+
+const (
+	ProviderLonghorn     Provider = "longhorn"
+	ProviderOpenEBSLocal Provider = "openebs-local"
+	ProviderOpenEBSCStor Provider = "openebs-cstor"
+	ProviderOpenEBSJiva  Provider = "openebs-jiva"
+	ProviderRookCephRBD  Provider = "rook-ceph-rbd"
+	ProviderRookCephFS   Provider = "rook-cephfs"
+)
+
+const (
+	// defaultLonghornReplicas is the number of replicas of a longhorn volume when the storage class doesn't set it
+	defaultLonghornReplicas = 3
+	// defaultCStorReplicas is the number of replicas of a cStor volume when the storage class doesn't set it
+	defaultCStorReplicas = 3
+)
+
+// Profile is the storage provider behind a storage class, the problems with its settings that are known to cause
+// postgres and minio data corruption or loss, and the recommended settings for the kotsadm volumes
+type Profile struct {
+	StorageClass    string   `json:"storageClass"`
+	Provisioner     string   `json:"provisioner"`
+	Provider        Provider `json:"provider"`
+	IsDefault       bool     `json:"isDefault"`
+	Warnings        []string `json:"warnings,omitempty"`
+	Recommendations []string `json:"recommendations,omitempty"`
+}
+
+// DetectProvider returns the distributed storage provider of a storage class provisioner, or an empty string if
+// it's not one that kots knows about
+func DetectProvider(provisioner string) Provider {
+	switch {
+	case provisioner == "driver.longhorn.io", provisioner == "rancher.io/longhorn":
+		return ProviderLonghorn
+	case provisioner == "openebs.io/local", provisioner == "local.csi.openebs.io":
+		return ProviderOpenEBSLocal
+	case provisioner == "cstor.csi.openebs.io":
+		return ProviderOpenEBSCStor
+	case provisioner == "jiva.csi.openebs.io":
+		return ProviderOpenEBSJiva
+	case strings.HasSuffix(provisioner, ".rbd.csi.ceph.com"), provisioner == "ceph.rook.io/block":
+		return ProviderRookCephRBD
+	case strings.HasSuffix(provisioner, ".cephfs.csi.ceph.com"):
+		return ProviderRookCephFS
+	}
+	return ""
+}
+
+// GetProfiles returns the profiles of the storage classes in the cluster that use a known distributed storage
+// provider. The default storage class is first.
+func GetProfiles(clientset kubernetes.Interface) ([]Profile, error) {
+	storageClasses, err := clientset.StorageV1().StorageClasses().List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list storage classes")
+	}
+
+	nodes, err := clientset.CoreV1().Nodes().List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list nodes")
+	}
+
+	return getProfiles(storageClasses.Items, countReadyNodes(nodes.Items)), nil
+}
+
+// GetDefaultProfile returns the profile of the default storage class, which is what the kotsadm volumes use. Nil is
+// returned if there is no default storage class or its provider is not known.
+func GetDefaultProfile(clientset kubernetes.Interface) (*Profile, error) {
+	profiles, err := GetProfiles(clientset)
+	if err != nil {
+		return nil, err
+	}
+	if len(profiles) == 0 || !profiles[0].IsDefault {
+		return nil, nil
+	}
+	return &profiles[0], nil
+}
+
+func getProfiles(storageClasses []storagev1.StorageClass, nodeCount int) []Profile {
+	profiles := []Profile{}
+	for _, storageClass := range storageClasses {
+		provider := DetectProvider(storageClass.Provisioner)
+		if provider == "" {
+			continue
+		}
+
+		profile := Profile{
+			StorageClass: storageClass.Name,
+			Provisioner:  storageClass.Provisioner,
+			Provider:     provider,
+			IsDefault:    isDefaultStorageClass(storageClass),
+		}
+		checkStorageClass(&profile, storageClass, nodeCount)
+		profiles = append(profiles, profile)
+	}
+
+	sort.SliceStable(profiles, func(i, j int) bool {
+		if profiles[i].IsDefault != profiles[j].IsDefault {
+			return profiles[i].IsDefault
+		}
+		return profiles[i].StorageClass < profiles[j].StorageClass
+	})
+
+	return profiles
+}
+
+func checkStorageClass(profile *Profile, storageClass storagev1.StorageClass, nodeCount int) {
+	params := storageClass.Parameters
+
+	switch profile.Provider {
+	case ProviderLonghorn:
+		checkReplicas(profile, "numberOfReplicas", params["numberOfReplicas"], defaultLonghornReplicas, nodeCount)
+		checkFSType(profile, params)
+		if params["dataLocality"] != "best-effort" {
+			profile.Recommendations = append(profile.Recommendations, "Set dataLocality to best-effort so that postgres and minio keep a replica on the node they run on.")
+		}
+		profile.Recommendations = append(profile.Recommendations, "Use 3 replicas, or one per node on clusters with fewer than 3 nodes.")
+
+	case ProviderOpenEBSLocal:
+		if nodeCount > 1 {
+			profile.Warnings = append(profile.Warnings, "OpenEBS Local PV volumes are not replicated. Data is lost if the node a volume is on fails, and pods using the volume can't be scheduled on other nodes.")
+		}
+		profile.Recommendations = append(profile.Recommendations, "Schedule Admin Console snapshots, since Local PV volumes are not replicated.")
+
+	case ProviderOpenEBSCStor:
+		checkReplicas(profile, "replicaCount", params["replicaCount"], defaultCStorReplicas, nodeCount)
+		checkFSType(profile, params)
+		profile.Recommendations = append(profile.Recommendations, "Use 3 replicas, with the pool on at least 3 nodes.")
+
+	case ProviderOpenEBSJiva:
+		profile.Warnings = append(profile.Warnings, "OpenEBS Jiva volumes are not recommended for databases. Postgres data can be corrupted when Jiva replicas are rebuilt.")
+		profile.Recommendations = append(profile.Recommendations, "Use a cStor or Local PV storage class for the Admin Console.")
+
+	case ProviderRookCephRBD:
+		if storageClass.Provisioner == "ceph.rook.io/block" {
+			profile.Warnings = append(profile.Warnings, "The Rook flex volume driver is deprecated and volumes can't be expanded. Migrate to the Ceph CSI RBD driver.")
+		}
+		checkFSType(profile, params)
+		profile.Recommendations = append(profile.Recommendations, "Use a block pool with a replicated size of 3 and a failure domain of host.")
+
+	case ProviderRookCephFS:
+		profile.Warnings = append(profile.Warnings, "CephFS is a shared filesystem. Postgres and minio need block volumes and can be corrupted on CephFS.")
+		profile.Recommendations = append(profile.Recommendations, "Use a Ceph RBD storage class for the Admin Console.")
+	}
+}
+
+// checkReplicas warns when volumes have more replicas than can be scheduled on separate nodes, or a single replica on
+// a cluster where a node can fail without the whole cluster failing
+func checkReplicas(profile *Profile, param string, value string, defaultReplicas int, nodeCount int) {
+	replicas := defaultReplicas
+	if value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil {
+			profile.Warnings = append(profile.Warnings, fmt.Sprintf("%s %q is not a number.", param, value))
+			return
+		}
+		replicas = parsed
+	}
+
+	if nodeCount > 0 && replicas > nodeCount {
+		profile.Warnings = append(profile.Warnings, fmt.Sprintf("%s is %d but there are %d ready nodes. Volumes will be degraded until there are enough nodes for every replica.", param, replicas, nodeCount))
+	} else if replicas < 2 && nodeCount > 1 {
+		profile.Warnings = append(profile.Warnings, fmt.Sprintf("%s is %d. Data is lost if the node with the only replica fails.", param, replicas))
+	}
+}
+
+// checkFSType warns when volumes are formatted with a filesystem other than ext4 or xfs, which are what postgres and
+// minio are tested on
+func checkFSType(profile *Profile, params map[string]string) {
+	fsType := ""
+	for _, key := range []string{"fsType", "fstype", "csi.storage.k8s.io/fstype"} {
+		if params[key] != "" {
+			fsType = params[key]
+			break
+		}
+	}
+
+	switch fsType {
+	case "", "ext4", "xfs":
+	default:
+		profile.Warnings = append(profile.Warnings, fmt.Sprintf("Volumes are formatted with %s. Use ext4 or xfs for postgres and minio.", fsType))
+	}
+}
+
+func isDefaultStorageClass(storageClass storagev1.StorageClass) bool {
+	if storageClass.Annotations["storageclass.kubernetes.io/is-default-class"] == "true" {
+		return true
+	}
+	return storageClass.Annotations["storageclass.beta.kubernetes.io/is-default-class"] == "true"
+}
+
+func countReadyNodes(nodes []corev1.Node) int {
+	count := 0
+	for _, node := range nodes {
+		for _, condition := range node.Status.Conditions {
+			if condition.Type == corev1.NodeReady && condition.Status == corev1.ConditionTrue {
+				count++
+			}
+		}
+	}
+	return count
+}
@@ -0,0 +1,110 @@
+package storageprofile
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	storagev1 "k8s.io/api/storage/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func Test_getProfiles(t *testing.T) {
+	storageClass := func(name string, provisioner string, isDefault bool, params map[string]string) storagev1.StorageClass {
+		sc := storagev1.StorageClass{
+			ObjectMeta:  metav1.ObjectMeta{Name: name},
+			Provisioner: provisioner,
+			Parameters:  params,
+		}
+		if isDefault {
+			sc.Annotations = map[string]string{"storageclass.kubernetes.io/is-default-class": "true"}
+		}
+		return sc
+	}
+
+	tests := []struct {
+		name           string
+		storageClasses []storagev1.StorageClass
+		nodeCount      int
+		wantClasses    []string
+		wantWarnings   []int
+	}{
+		{
+			name: "unknown providers are skipped",
+			storageClasses: []storagev1.StorageClass{
+				storageClass("standard", "kubernetes.io/gce-pd", true, nil),
+			},
+			nodeCount:    3,
+			wantClasses:  []string{},
+			wantWarnings: []int{},
+		},
+		{
+			name: "longhorn defaults on three nodes",
+			storageClasses: []storagev1.StorageClass{
+				storageClass("longhorn", "driver.longhorn.io", true, nil),
+			},
+			nodeCount:    3,
+			wantClasses:  []string{"longhorn"},
+			wantWarnings: []int{0},
+		},
+		{
+			name: "longhorn replicas exceed nodes",
+			storageClasses: []storagev1.StorageClass{
+				storageClass("longhorn", "driver.longhorn.io", true, map[string]string{"numberOfReplicas": "3"}),
+			},
+			nodeCount:    1,
+			wantClasses:  []string{"longhorn"},
+			wantWarnings: []int{1},
+		},
+		{
+			name: "longhorn single replica and unsupported filesystem",
+			storageClasses: []storagev1.StorageClass{
+				storageClass("longhorn", "driver.longhorn.io", true, map[string]string{"numberOfReplicas": "1", "fsType": "btrfs"}),
+			},
+			nodeCount:    3,
+			wantClasses:  []string{"longhorn"},
+			wantWarnings: []int{2},
+		},
+		{
+			name: "openebs local on one node",
+			storageClasses: []storagev1.StorageClass{
+				storageClass("openebs-hostpath", "openebs.io/local", true, nil),
+			},
+			nodeCount:    1,
+			wantClasses:  []string{"openebs-hostpath"},
+			wantWarnings: []int{0},
+		},
+		{
+			name: "default class first",
+			storageClasses: []storagev1.StorageClass{
+				storageClass("cephfs", "rook-ceph.cephfs.csi.ceph.com", false, nil),
+				storageClass("openebs-hostpath", "openebs.io/local", false, nil),
+				storageClass("rook-ceph-block", "rook-ceph.rbd.csi.ceph.com", true, map[string]string{"csi.storage.k8s.io/fstype": "ext4"}),
+			},
+			nodeCount:    3,
+			wantClasses:  []string{"rook-ceph-block", "cephfs", "openebs-hostpath"},
+			wantWarnings: []int{0, 1, 1},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			profiles := getProfiles(test.storageClasses, test.nodeCount)
+
+			classes := []string{}
+			warnings := []int{}
+			for _, profile := range profiles {
+				classes = append(classes, profile.StorageClass)
+				warnings = append(warnings, len(profile.Warnings))
+			}
+			require.Equal(t, test.wantClasses, classes)
+			require.Equal(t, test.wantWarnings, warnings)
+		})
+	}
+}
+
+func Test_DetectProvider(t *testing.T) {
+	require.Equal(t, ProviderLonghorn, DetectProvider("driver.longhorn.io"))
+	require.Equal(t, ProviderOpenEBSCStor, DetectProvider("cstor.csi.openebs.io"))
+	require.Equal(t, ProviderRookCephRBD, DetectProvider("rook-ceph.rbd.csi.ceph.com"))
+	require.Equal(t, ProviderRookCephFS, DetectProvider("my-cluster.cephfs.csi.ceph.com"))
+	require.Equal(t, Provider(""), DetectProvider("ebs.csi.aws.com"))
+}
@@ -0,0 +1,57 @@
+package kotsstore
+
+import (
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/replicatedhq/kots/pkg/persistence"
+)
+
+func (s *KOTSStore) AcquireAppLock(appID string, holder string, expiresAt time.Time) (bool, error) {
+	db := persistence.MustGetPGSession()
+
+	// an expired lock was left by a holder that crashed, it is taken over
+	query := `insert into app_version_lock (app_id, holder, expires_at) values ($1, $2, $3)
+	on conflict (app_id) do update set holder = EXCLUDED.holder, expires_at = EXCLUDED.expires_at where app_version_lock.expires_at < $4`
+	result, err := db.Exec(query, appID, holder, expiresAt, time.Now())
+	if err != nil {
+		return false, errors.Wrap(err, "failed to exec")
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, errors.Wrap(err, "failed to get rows affected")
+	}
+
+	return rowsAffected == 1, nil
+}
+
+func (s *KOTSStore) RefreshAppLock(appID string, holder string, expiresAt time.Time) error {
+	db := persistence.MustGetPGSession()
+	query := `update app_version_lock set expires_at = $1 where app_id = $2 and holder = $3`
+	result, err := db.Exec(query, expiresAt, appID, holder)
+	if err != nil {
+		return errors.Wrap(err, "failed to exec")
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return errors.Wrap(err, "failed to get rows affected")
+	}
+	if rowsAffected == 0 {
+		return errors.New("lock is no longer held")
+	}
+
+	return nil
+}
+
+func (s *KOTSStore) ReleaseAppLock(appID string, holder string) error {
+	db := persistence.MustGetPGSession()
+	query := `delete from app_version_lock where app_id = $1 and holder = $2`
+	_, err := db.Exec(query, appID, holder)
+	if err != nil {
+		return errors.Wrap(err, "failed to exec")
+	}
+
+	return nil
+}
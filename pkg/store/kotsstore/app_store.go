@@ -2,6 +2,7 @@ package kotsstore
 
 import (
 	"database/sql"
+	"encoding/json"
 	"fmt"
 	"strings"
 	"time"
@@ -72,6 +73,31 @@ func (s *KOTSStore) ListInstalledApps() ([]*apptypes.App, error) {
 	return apps, nil
 }
 
+func (s *KOTSStore) ListRemovedApps() ([]*apptypes.App, error) {
+	db := persistence.MustGetPGSession()
+	query := `select id from app where install_state = 'removed' order by removed_at`
+	rows, err := db.Query(query)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to query db")
+	}
+	defer rows.Close()
+
+	apps := []*apptypes.App{}
+	for rows.Next() {
+		var appID string
+		if err := rows.Scan(&appID); err != nil {
+			return nil, errors.Wrap(err, "failed to scan")
+		}
+		app, err := s.GetApp(appID)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to get app")
+		}
+		apps = append(apps, app)
+	}
+
+	return apps, nil
+}
+
 func (s *KOTSStore) ListInstalledAppSlugs() ([]string, error) {
 	db := persistence.MustGetPGSession()
 	query := `select slug from app where install_state = 'installed'`
@@ -112,7 +138,7 @@ func (s *KOTSStore) GetApp(id string) (*apptypes.App, error) {
 	// 	zap.String("id", id))
 
 	db := persistence.MustGetPGSession()
-	query := `select id, name, license, upstream_uri, icon_uri, created_at, updated_at, slug, current_sequence, last_update_check_at, is_airgap, snapshot_ttl_new, snapshot_schedule, restore_in_progress_name, restore_undeploy_status, update_checker_spec, install_state from app where id = $1`
+	query := `select id, name, license, upstream_uri, icon_uri, created_at, updated_at, slug, current_sequence, last_update_check_at, is_airgap, snapshot_ttl_new, snapshot_schedule, restore_in_progress_name, restore_undeploy_status, update_checker_spec, install_state, deploy_policy, admission_dry_run, image_push_bandwidth_limit, is_archived, require_deploy_approval, apply_policy, namespace, restore_drill_schedule, canary_policy, is_protected, removed_at, schedule_timezone, labels from app where id = $1`
 	row := db.QueryRow(query, id)
 
 	app := apptypes.App{}
@@ -128,8 +154,21 @@ func (s *KOTSStore) GetApp(id string) (*apptypes.App, error) {
 	var restoreInProgressName sql.NullString
 	var restoreUndeployStatus sql.NullString
 	var updateCheckerSpec sql.NullString
-
-	if err := row.Scan(&app.ID, &app.Name, &licenseStr, &upstreamURI, &iconURI, &app.CreatedAt, &updatedAt, &app.Slug, &currentSequence, &lastUpdateCheckAt, &app.IsAirgap, &snapshotTTLNew, &snapshotSchedule, &restoreInProgressName, &restoreUndeployStatus, &updateCheckerSpec, &app.InstallState); err != nil {
+	var deployPolicy sql.NullString
+	var admissionDryRun sql.NullBool
+	var imagePushBandwidthLimit sql.NullInt64
+	var isArchived sql.NullBool
+	var requireDeployApproval sql.NullBool
+	var applyPolicy sql.NullString
+	var namespace sql.NullString
+	var restoreDrillSchedule sql.NullString
+	var canaryPolicy sql.NullString
+	var isProtected sql.NullBool
+	var removedAt sql.NullTime
+	var scheduleTimezone sql.NullString
+	var labels sql.NullString
+
+	if err := row.Scan(&app.ID, &app.Name, &licenseStr, &upstreamURI, &iconURI, &app.CreatedAt, &updatedAt, &app.Slug, &currentSequence, &lastUpdateCheckAt, &app.IsAirgap, &snapshotTTLNew, &snapshotSchedule, &restoreInProgressName, &restoreUndeployStatus, &updateCheckerSpec, &app.InstallState, &deployPolicy, &admissionDryRun, &imagePushBandwidthLimit, &isArchived, &requireDeployApproval, &applyPolicy, &namespace, &restoreDrillSchedule, &canaryPolicy, &isProtected, &removedAt, &scheduleTimezone, &labels); err != nil {
 		return nil, errors.Wrap(err, "failed to scan app")
 	}
 
@@ -142,10 +181,43 @@ func (s *KOTSStore) GetApp(id string) (*apptypes.App, error) {
 	app.RestoreInProgressName = restoreInProgressName.String
 	app.RestoreUndeployStatus = apptypes.UndeployStatus(restoreUndeployStatus.String)
 	app.UpdateCheckerSpec = updateCheckerSpec.String
+	app.DeployPolicy = apptypes.DeployPolicy(deployPolicy.String)
+	if app.DeployPolicy == "" {
+		app.DeployPolicy = apptypes.DeployPolicyLatest
+	}
+	app.AdmissionDryRun = admissionDryRun.Bool
+	app.ImagePushBandwidthLimit = imagePushBandwidthLimit.Int64
+	app.IsArchived = isArchived.Bool
+	app.IsProtected = isProtected.Bool
+	app.RequireDeployApproval = requireDeployApproval.Bool
+	app.Namespace = namespace.String
+	app.RestoreDrillSchedule = restoreDrillSchedule.String
+	app.ScheduleTimezone = scheduleTimezone.String
+
+	if applyPolicy.Valid && applyPolicy.String != "" {
+		if err := json.Unmarshal([]byte(applyPolicy.String), &app.ApplyPolicy); err != nil {
+			return nil, errors.Wrap(err, "failed to unmarshal apply policy")
+		}
+	}
+
+	if canaryPolicy.Valid && canaryPolicy.String != "" {
+		if err := json.Unmarshal([]byte(canaryPolicy.String), &app.CanaryPolicy); err != nil {
+			return nil, errors.Wrap(err, "failed to unmarshal canary policy")
+		}
+	}
+
+	if labels.Valid && labels.String != "" {
+		if err := json.Unmarshal([]byte(labels.String), &app.Labels); err != nil {
+			return nil, errors.Wrap(err, "failed to unmarshal labels")
+		}
+	}
 
 	if updatedAt.Valid {
 		app.UpdatedAt = &updatedAt.Time
 	}
+	if removedAt.Valid {
+		app.RemovedAt = &removedAt.Time
+	}
 
 	if currentSequence.Valid {
 		app.CurrentSequence = currentSequence.Int64
@@ -364,6 +436,171 @@ func (s *KOTSStore) SetUpdateCheckerSpec(appID string, updateCheckerSpec string)
 	return nil
 }
 
+func (s *KOTSStore) SetDeployPolicy(appID string, deployPolicy apptypes.DeployPolicy) error {
+	logger.Debug("setting deploy policy",
+		zap.String("appID", appID),
+		zap.String("deployPolicy", string(deployPolicy)))
+
+	db := persistence.MustGetPGSession()
+	query := `update app set deploy_policy = $1 where id = $2`
+	_, err := db.Exec(query, deployPolicy, appID)
+	if err != nil {
+		return errors.Wrap(err, "failed to exec db query")
+	}
+
+	return nil
+}
+
+func (s *KOTSStore) SetAdmissionDryRun(appID string, enabled bool) error {
+	logger.Debug("setting admission dry run",
+		zap.String("appID", appID),
+		zap.Bool("enabled", enabled))
+
+	db := persistence.MustGetPGSession()
+	query := `update app set admission_dry_run = $1 where id = $2`
+	_, err := db.Exec(query, enabled, appID)
+	if err != nil {
+		return errors.Wrap(err, "failed to exec db query")
+	}
+
+	return nil
+}
+
+func (s *KOTSStore) SetAppArchived(appID string, archived bool) error {
+	logger.Debug("setting app archived",
+		zap.String("appID", appID),
+		zap.Bool("archived", archived))
+
+	db := persistence.MustGetPGSession()
+	query := `update app set is_archived = $1 where id = $2`
+	_, err := db.Exec(query, archived, appID)
+	if err != nil {
+		return errors.Wrap(err, "failed to exec db query")
+	}
+
+	return nil
+}
+
+func (s *KOTSStore) SetAppProtected(appID string, protected bool) error {
+	logger.Debug("setting app protected",
+		zap.String("appID", appID),
+		zap.Bool("protected", protected))
+
+	db := persistence.MustGetPGSession()
+	query := `update app set is_protected = $1 where id = $2`
+	_, err := db.Exec(query, protected, appID)
+	if err != nil {
+		return errors.Wrap(err, "failed to exec db query")
+	}
+
+	return nil
+}
+
+func (s *KOTSStore) SetRequireDeployApproval(appID string, required bool) error {
+	logger.Debug("setting require deploy approval",
+		zap.String("appID", appID),
+		zap.Bool("required", required))
+
+	db := persistence.MustGetPGSession()
+	query := `update app set require_deploy_approval = $1 where id = $2`
+	_, err := db.Exec(query, required, appID)
+	if err != nil {
+		return errors.Wrap(err, "failed to exec db query")
+	}
+
+	return nil
+}
+
+func (s *KOTSStore) SetApplyPolicy(appID string, applyPolicy apptypes.ApplyPolicy) error {
+	logger.Debug("setting apply policy",
+		zap.String("appID", appID),
+		zap.Any("applyPolicy", applyPolicy))
+
+	b, err := json.Marshal(applyPolicy)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal apply policy")
+	}
+
+	db := persistence.MustGetPGSession()
+	query := `update app set apply_policy = $1 where id = $2`
+	_, err = db.Exec(query, string(b), appID)
+	if err != nil {
+		return errors.Wrap(err, "failed to exec db query")
+	}
+
+	return nil
+}
+
+func (s *KOTSStore) SetCanaryPolicy(appID string, canaryPolicy apptypes.CanaryPolicy) error {
+	logger.Debug("setting canary policy",
+		zap.String("appID", appID),
+		zap.Any("canaryPolicy", canaryPolicy))
+
+	b, err := json.Marshal(canaryPolicy)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal canary policy")
+	}
+
+	db := persistence.MustGetPGSession()
+	query := `update app set canary_policy = $1 where id = $2`
+	_, err = db.Exec(query, string(b), appID)
+	if err != nil {
+		return errors.Wrap(err, "failed to exec db query")
+	}
+
+	return nil
+}
+
+func (s *KOTSStore) SetAppLabels(appID string, labels map[string]string) error {
+	logger.Debug("setting app labels",
+		zap.String("appID", appID),
+		zap.Any("labels", labels))
+
+	b, err := json.Marshal(labels)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal labels")
+	}
+
+	db := persistence.MustGetPGSession()
+	query := `update app set labels = $1 where id = $2`
+	_, err = db.Exec(query, string(b), appID)
+	if err != nil {
+		return errors.Wrap(err, "failed to exec db query")
+	}
+
+	return nil
+}
+
+func (s *KOTSStore) SetAppNamespace(appID string, namespace string) error {
+	logger.Debug("setting app namespace",
+		zap.String("appID", appID),
+		zap.String("namespace", namespace))
+
+	db := persistence.MustGetPGSession()
+	query := `update app set namespace = $1 where id = $2`
+	_, err := db.Exec(query, namespace, appID)
+	if err != nil {
+		return errors.Wrap(err, "failed to exec db query")
+	}
+
+	return nil
+}
+
+func (s *KOTSStore) SetImagePushBandwidthLimit(appID string, bytesPerSecond int64) error {
+	logger.Debug("setting image push bandwidth limit",
+		zap.String("appID", appID),
+		zap.Int64("bytesPerSecond", bytesPerSecond))
+
+	db := persistence.MustGetPGSession()
+	query := `update app set image_push_bandwidth_limit = $1 where id = $2`
+	_, err := db.Exec(query, bytesPerSecond, appID)
+	if err != nil {
+		return errors.Wrap(err, "failed to exec db query")
+	}
+
+	return nil
+}
+
 func (s *KOTSStore) SetSnapshotTTL(appID string, snapshotTTL string) error {
 	logger.Debug("Setting snapshot TTL",
 		zap.String("appID", appID))
@@ -390,6 +627,62 @@ func (s *KOTSStore) SetSnapshotSchedule(appID string, snapshotSchedule string) e
 	return nil
 }
 
+func (s *KOTSStore) SetRestoreDrillSchedule(appID string, restoreDrillSchedule string) error {
+	logger.Debug("Setting restore drill schedule",
+		zap.String("appID", appID))
+	db := persistence.MustGetPGSession()
+	query := `update app set restore_drill_schedule = $1 where id = $2`
+	_, err := db.Exec(query, restoreDrillSchedule, appID)
+	if err != nil {
+		return errors.Wrap(err, "failed to exec db query")
+	}
+
+	return nil
+}
+
+func (s *KOTSStore) SetScheduleTimezone(appID string, timezone string) error {
+	logger.Debug("Setting schedule timezone",
+		zap.String("appID", appID))
+	db := persistence.MustGetPGSession()
+	query := `update app set schedule_timezone = $1 where id = $2`
+	_, err := db.Exec(query, timezone, appID)
+	if err != nil {
+		return errors.Wrap(err, "failed to exec db query")
+	}
+
+	return nil
+}
+
+// TrashApp marks the app as removed, it is no longer listed or deployed but its versions and archives are kept until
+// it is removed permanently
+func (s *KOTSStore) TrashApp(appID string) error {
+	logger.Debug("Moving app to trash",
+		zap.String("appID", appID))
+
+	db := persistence.MustGetPGSession()
+	query := `update app set install_state = 'removed', removed_at = $1 where id = $2`
+	_, err := db.Exec(query, time.Now(), appID)
+	if err != nil {
+		return errors.Wrap(err, "failed to exec db query")
+	}
+
+	return nil
+}
+
+func (s *KOTSStore) RestoreTrashedApp(appID string) error {
+	logger.Debug("Restoring app from trash",
+		zap.String("appID", appID))
+
+	db := persistence.MustGetPGSession()
+	query := `update app set install_state = 'installed', removed_at = null where id = $1 and install_state = 'removed'`
+	_, err := db.Exec(query, appID)
+	if err != nil {
+		return errors.Wrap(err, "failed to exec db query")
+	}
+
+	return nil
+}
+
 func (s *KOTSStore) RemoveApp(appID string) error {
 	logger.Debug("Removing app",
 		zap.String("appID", appID))
@@ -447,6 +740,24 @@ func (s *KOTSStore) RemoveApp(appID string) error {
 		return errors.Wrap(err, "failed to delete from pending_supportbundle")
 	}
 
+	query = "delete from restore_drill where app_id = $1"
+	_, err = tx.Exec(query, appID)
+	if err != nil {
+		return errors.Wrap(err, "failed to delete from restore_drill")
+	}
+
+	query = "delete from app_canary_deploy where app_id = $1"
+	_, err = tx.Exec(query, appID)
+	if err != nil {
+		return errors.Wrap(err, "failed to delete from app_canary_deploy")
+	}
+
+	query = "delete from app_deploy_event where app_id = $1"
+	_, err = tx.Exec(query, appID)
+	if err != nil {
+		return errors.Wrap(err, "failed to delete from app_deploy_event")
+	}
+
 	query = "delete from app where id = $1"
 	_, err = tx.Exec(query, appID)
 	if err != nil {
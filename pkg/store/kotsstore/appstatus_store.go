@@ -8,7 +8,10 @@ import (
 	"github.com/pkg/errors"
 	appstatustypes "github.com/replicatedhq/kots/pkg/api/appstatus/types"
 	"github.com/replicatedhq/kots/pkg/appstatus"
+	"github.com/replicatedhq/kots/pkg/events"
+	eventtypes "github.com/replicatedhq/kots/pkg/events/types"
 	"github.com/replicatedhq/kots/pkg/persistence"
+	postdeploytesttypes "github.com/replicatedhq/kots/pkg/postdeploytest/types"
 )
 
 func (s *KOTSStore) GetAppStatus(appID string) (*appstatustypes.AppStatus, error) {
@@ -52,6 +55,15 @@ func (s *KOTSStore) GetAppStatus(appID string) (*appstatustypes.AppStatus, error
 
 	appStatus.State = appstatus.GetState(appStatus.ResourceStates)
 
+	// resources can be ready while the version is failing its post deploy tests
+	failedTests, err := hasFailedPostDeployTests(appID, appStatus.Sequence)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to check post deploy tests")
+	}
+	if failedTests && appStatus.State == appstatustypes.StateReady {
+		appStatus.State = appstatustypes.StateDegraded
+	}
+
 	return &appStatus, nil
 }
 
@@ -68,5 +80,40 @@ func (s *KOTSStore) SetAppStatus(appID string, resourceStates []appstatustypes.R
 		return errors.Wrap(err, "failed to exec")
 	}
 
+	events.Publish(eventtypes.EventTypeAppStatus, appID, eventtypes.AppStatusData{
+		State:    string(appstatus.GetState(resourceStates)),
+		Sequence: sequence,
+	})
+
 	return nil
 }
+
+func hasFailedPostDeployTests(appID string, sequence int64) (bool, error) {
+	db := persistence.MustGetPGSession()
+	query := `select post_deploy_test_results from app_downstream_output where app_id = $1 and downstream_sequence = $2`
+	rows, err := db.Query(query, appID, sequence)
+	if err != nil {
+		return false, errors.Wrap(err, "failed to query")
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var resultsStr sql.NullString
+		if err := rows.Scan(&resultsStr); err != nil {
+			return false, errors.Wrap(err, "failed to scan")
+		}
+		if !resultsStr.Valid || resultsStr.String == "" {
+			continue
+		}
+
+		results := []postdeploytesttypes.Result{}
+		if err := json.Unmarshal([]byte(resultsStr.String), &results); err != nil {
+			return false, errors.Wrap(err, "failed to unmarshal post deploy test results")
+		}
+		if postdeploytesttypes.HasFailed(results) {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
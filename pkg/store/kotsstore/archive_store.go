@@ -0,0 +1,429 @@
+package kotsstore
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"io"
+	"os"
+	"regexp"
+	"time"
+
+	"github.com/pkg/errors"
+	archiveintegritytypes "github.com/replicatedhq/kots/pkg/archiveintegrity/types"
+	"github.com/replicatedhq/kots/pkg/logger"
+	"github.com/replicatedhq/kots/pkg/manifestsigning"
+	"github.com/replicatedhq/kots/pkg/objectstore"
+	"github.com/replicatedhq/kots/pkg/persistence"
+	"go.uber.org/zap"
+)
+
+// appVersionArchiveKeyRegex matches the keys of app version archives, "<app id>/<sequence>.tar.gz"
+var appVersionArchiveKeyRegex = regexp.MustCompile(`^([^/]+)/(\d+)\.tar\.gz$`)
+
+// DeleteOrphanedAppVersionArchives deletes the app version archives that were last modified before olderThan and
+// are not referenced by any app version, such as the archives of removed apps or of versions that failed to be created.
+// It returns the number of archives deleted and their total size.
+func (s *KOTSStore) DeleteOrphanedAppVersionArchives(olderThan time.Time) (int64, int64, error) {
+	if objectstore.IsOCI(os.Getenv("STORAGE_BASEURI")) {
+		return 0, 0, nil
+	}
+
+	driver, err := objectstore.GetDriver()
+	if err != nil {
+		return 0, 0, errors.Wrap(err, "failed to get object store driver")
+	}
+
+	db := persistence.MustGetPGSession()
+	query := `select app_id, sequence from app_version`
+	rows, err := db.Query(query)
+	if err != nil {
+		return 0, 0, errors.Wrap(err, "failed to query")
+	}
+	defer rows.Close()
+
+	referencedKeys := map[string]bool{}
+	for rows.Next() {
+		var appID string
+		var sequence int64
+		if err := rows.Scan(&appID, &sequence); err != nil {
+			return 0, 0, errors.Wrap(err, "failed to scan")
+		}
+		referencedKeys[fmt.Sprintf("%s/%d.tar.gz", appID, sequence)] = true
+	}
+
+	keysToDelete := []string{}
+	size := int64(0)
+	err = driver.ListObjects(context.TODO(), "", func(object objectstore.Object) error {
+		if !appVersionArchiveKeyRegex.MatchString(object.Key) || referencedKeys[object.Key] {
+			return nil
+		}
+		if object.LastModified.After(olderThan) {
+			return nil
+		}
+		keysToDelete = append(keysToDelete, object.Key)
+		size += object.Size
+		return nil
+	})
+	if err != nil {
+		return 0, 0, errors.Wrap(err, "failed to list objects")
+	}
+
+	deleted := int64(len(keysToDelete))
+	if deleted > 0 {
+		logger.Debug("deleting orphaned app version archives",
+			zap.Int64("count", deleted))
+	}
+
+	if err := driver.DeleteObjects(context.TODO(), keysToDelete); err != nil {
+		return 0, 0, errors.Wrap(err, "failed to delete objects")
+	}
+
+	query = `delete from app_version_archive a where a.created_at < $1 and not exists (select 1 from app_version v where v.app_id = a.app_id and v.sequence = a.sequence)`
+	if _, err := db.Exec(query, olderThan); err != nil {
+		return 0, 0, errors.Wrap(err, "failed to delete orphaned archive checksums")
+	}
+
+	return deleted, size, nil
+}
+
+// SetAppVersionArchiveChecksum records the checksum of the archive that was uploaded for the app version, replacing
+// the checksum and the verification of a previous archive. The checksum is signed with the manifest signing key, so
+// that an archive that was replaced along with its checksum is detected too.
+func (s *KOTSStore) SetAppVersionArchiveChecksum(appID string, sequence int64, sum string, size int64) error {
+	signature, err := manifestsigning.SignArchiveChecksum(appID, sequence, sum)
+	if err != nil {
+		return errors.Wrap(err, "failed to sign checksum")
+	}
+
+	db := persistence.MustGetPGSession()
+	query := `insert into app_version_archive (app_id, sequence, sha256, size, created_at, verified_at, verify_error, signature) values ($1, $2, $3, $4, $5, null, null, $6)
+	on conflict (app_id, sequence) do update set sha256 = EXCLUDED.sha256, size = EXCLUDED.size, created_at = EXCLUDED.created_at, verified_at = null, verify_error = null, signature = EXCLUDED.signature`
+	_, err = db.Exec(query, appID, sequence, sum, size, time.Now(), sql.NullString{String: signature, Valid: signature != ""})
+	if err != nil {
+		return errors.Wrap(err, "failed to upsert")
+	}
+
+	return nil
+}
+
+// GetAppVersionArchiveChecksum returns nil if no checksum was recorded for the archive, as for archives created
+// before checksums were recorded
+func (s *KOTSStore) GetAppVersionArchiveChecksum(appID string, sequence int64) (*archiveintegritytypes.Checksum, error) {
+	db := persistence.MustGetPGSession()
+	query := `select app_id, sequence, sha256, size, created_at, verified_at, verify_error, signature from app_version_archive where app_id = $1 and sequence = $2`
+	row := db.QueryRow(query, appID, sequence)
+
+	checksum, err := scanAppVersionArchiveChecksum(row)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to scan")
+	}
+
+	return checksum, nil
+}
+
+// ListAppVersionArchiveChecksumsToVerify returns up to limit archives that were not verified since verifiedBefore,
+// the archives that were never verified first
+func (s *KOTSStore) ListAppVersionArchiveChecksumsToVerify(verifiedBefore time.Time, limit int) ([]archiveintegritytypes.Checksum, error) {
+	db := persistence.MustGetPGSession()
+	query := `select app_id, sequence, sha256, size, created_at, verified_at, verify_error, signature from app_version_archive
+	where verified_at is null or verified_at < $1 order by verified_at asc nulls first limit $2`
+	rows, err := db.Query(query, verifiedBefore, limit)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to query")
+	}
+	defer rows.Close()
+
+	checksums := []archiveintegritytypes.Checksum{}
+	for rows.Next() {
+		checksum, err := scanAppVersionArchiveChecksum(rows)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to scan")
+		}
+		checksums = append(checksums, *checksum)
+	}
+
+	return checksums, nil
+}
+
+// ListCorruptAppVersionArchives returns the archives of the app that failed their last verification
+func (s *KOTSStore) ListCorruptAppVersionArchives(appID string) ([]archiveintegritytypes.Checksum, error) {
+	db := persistence.MustGetPGSession()
+	query := `select app_id, sequence, sha256, size, created_at, verified_at, verify_error, signature from app_version_archive
+	where app_id = $1 and verify_error is not null order by sequence desc`
+	rows, err := db.Query(query, appID)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to query")
+	}
+	defer rows.Close()
+
+	checksums := []archiveintegritytypes.Checksum{}
+	for rows.Next() {
+		checksum, err := scanAppVersionArchiveChecksum(rows)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to scan")
+		}
+		checksums = append(checksums, *checksum)
+	}
+
+	return checksums, nil
+}
+
+// SetAppVersionArchiveVerified records the result of a verification of the archive, verifyError is empty if the
+// archive matches its checksum
+func (s *KOTSStore) SetAppVersionArchiveVerified(appID string, sequence int64, verifyError string) error {
+	db := persistence.MustGetPGSession()
+	query := `update app_version_archive set verified_at = $1, verify_error = $2 where app_id = $3 and sequence = $4`
+	_, err := db.Exec(query, time.Now(), sql.NullString{String: verifyError, Valid: verifyError != ""}, appID, sequence)
+	if err != nil {
+		return errors.Wrap(err, "failed to update")
+	}
+
+	return nil
+}
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanAppVersionArchiveChecksum(row rowScanner) (*archiveintegritytypes.Checksum, error) {
+	var size sql.NullInt64
+	var verifiedAt sql.NullTime
+	var verifyError sql.NullString
+	var signature sql.NullString
+
+	checksum := archiveintegritytypes.Checksum{}
+	if err := row.Scan(&checksum.AppID, &checksum.Sequence, &checksum.SHA256, &size, &checksum.CreatedAt, &verifiedAt, &verifyError, &signature); err != nil {
+		return nil, err
+	}
+
+	checksum.Size = size.Int64
+	if verifiedAt.Valid {
+		checksum.VerifiedAt = &verifiedAt.Time
+	}
+	checksum.VerifyError = verifyError.String
+	checksum.Signature = signature.String
+
+	return &checksum, nil
+}
+
+// verifyAppVersionArchiveChecksum compares the checksum of an archive that was read from the object store with the
+// checksum recorded when it was created, and verifies the signature of the recorded checksum. The archive is
+// recorded as corrupt if either does not match.
+func (s *KOTSStore) verifyAppVersionArchiveChecksum(appID string, sequence int64, sum string) error {
+	checksum, err := s.GetAppVersionArchiveChecksum(appID, sequence)
+	if err != nil {
+		return errors.Wrap(err, "failed to get archive checksum")
+	}
+	if checksum == nil {
+		return nil
+	}
+
+	if checksum.SHA256 != sum {
+		err = errors.Wrapf(archiveintegritytypes.ErrChecksumMismatch, "archive of app %s sequence %d has sha256 %s, expected %s", appID, sequence, sum, checksum.SHA256)
+	} else {
+		verifyErr := manifestsigning.VerifyArchiveChecksum(appID, sequence, checksum.SHA256, checksum.Signature)
+		if verifyErr == nil {
+			return nil
+		}
+		if errors.Cause(verifyErr) != manifestsigning.ErrInvalidSignature {
+			return errors.Wrap(verifyErr, "failed to verify archive checksum signature")
+		}
+		err = errors.Wrapf(archiveintegritytypes.ErrChecksumMismatch, "signature of the checksum of the archive of app %s sequence %d is invalid", appID, sequence)
+	}
+
+	if setErr := s.SetAppVersionArchiveVerified(appID, sequence, err.Error()); setErr != nil {
+		logger.Error(errors.Wrap(setErr, "failed to record corrupt archive"))
+	}
+
+	return err
+}
+
+// checksumReader verifies the checksum of an archive when it has been read to the end. The mismatch error is
+// returned instead of io.EOF, so that a corrupt archive cannot be mistaken for a complete one.
+type checksumReader struct {
+	io.ReadCloser
+	hash   hash.Hash
+	verify func(sum string) error
+}
+
+func (r *checksumReader) Read(p []byte) (int, error) {
+	n, err := r.ReadCloser.Read(p)
+	r.hash.Write(p[:n])
+	if err == io.EOF {
+		if verifyErr := r.verify(hex.EncodeToString(r.hash.Sum(nil))); verifyErr != nil {
+			return n, verifyErr
+		}
+	}
+	return n, err
+}
+
+// fileSHA256 returns the hex encoded sha256 and the size of the file
+func fileSHA256(filePath string) (string, int64, error) {
+	f, err := os.Open(filePath)
+	if err != nil {
+		return "", 0, errors.Wrap(err, "failed to open file")
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	size, err := io.Copy(h, f)
+	if err != nil {
+		return "", 0, errors.Wrap(err, "failed to read file")
+	}
+
+	return hex.EncodeToString(h.Sum(nil)), size, nil
+}
+
+// deleteUncommittedAppVersionArchive deletes the archive and the checksum of a version that failed to be created.
+// The archive is kept if a version with the sequence is committed, as when the first version of an app is created
+// again. Errors are logged, the archive is deleted by the janitor otherwise.
+func (s *KOTSStore) deleteUncommittedAppVersionArchive(appID string, sequence int64) {
+	db := persistence.MustGetPGSession()
+	query := `select exists(select 1 from app_version where app_id = $1 and sequence = $2)`
+	var committed bool
+	if err := db.QueryRow(query, appID, sequence).Scan(&committed); err != nil {
+		logger.Error(errors.Wrap(err, "failed to check for committed app version"))
+		return
+	}
+	if committed {
+		return
+	}
+
+	if err := s.deleteAppVersionArchive(appID, sequence); err != nil {
+		logger.Error(errors.Wrapf(err, "failed to delete archive of app %s sequence %d", appID, sequence))
+	}
+}
+
+func (s *KOTSStore) deleteAppVersionArchive(appID string, sequence int64) error {
+	if !objectstore.IsOCI(os.Getenv("STORAGE_BASEURI")) {
+		driver, err := objectstore.GetDriver()
+		if err != nil {
+			return errors.Wrap(err, "failed to get object store driver")
+		}
+		if err := driver.DeleteObjects(context.TODO(), []string{fmt.Sprintf("%s/%d.tar.gz", appID, sequence)}); err != nil {
+			return errors.Wrap(err, "failed to delete object")
+		}
+	}
+
+	db := persistence.MustGetPGSession()
+	query := `delete from app_version_archive where app_id = $1 and sequence = $2`
+	if _, err := db.Exec(query, appID, sequence); err != nil {
+		return errors.Wrap(err, "failed to delete checksum")
+	}
+
+	return nil
+}
+
+// DeleteIncompleteAppVersions deletes the versions created before olderThan that were left half created, by
+// failures before the versions were created in a savepoint: versions without an archive, and versions of apps with
+// downstreams that have no downstream version. Versions that were deployed, and the only version of an app, are
+// kept. It returns the number of versions deleted.
+func (s *KOTSStore) DeleteIncompleteAppVersions(olderThan time.Time) (int64, error) {
+	if objectstore.IsOCI(os.Getenv("STORAGE_BASEURI")) {
+		return 0, nil
+	}
+
+	driver, err := objectstore.GetDriver()
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to get object store driver")
+	}
+
+	existingKeys := map[string]bool{}
+	err = driver.ListObjects(context.TODO(), "", func(object objectstore.Object) error {
+		if appVersionArchiveKeyRegex.MatchString(object.Key) {
+			existingKeys[object.Key] = true
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to list objects")
+	}
+
+	db := persistence.MustGetPGSession()
+	query := `select v.app_id, v.sequence,
+		exists(select 1 from app_downstream_version dv where dv.app_id = v.app_id and dv.sequence = v.sequence),
+		exists(select 1 from app_downstream d where d.app_id = v.app_id)
+	from app_version v
+	where v.created_at < $1
+		and not exists(select 1 from app_downstream d where d.app_id = v.app_id and d.current_sequence = v.sequence)
+		and not exists(select 1 from app_downstream_version dv where dv.app_id = v.app_id and dv.sequence = v.sequence and dv.applied_at is not null)
+		and exists(select 1 from app_version o where o.app_id = v.app_id and o.sequence != v.sequence)`
+	rows, err := db.Query(query, olderThan)
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to query")
+	}
+	defer rows.Close()
+
+	type appVersion struct {
+		appID    string
+		sequence int64
+	}
+	incomplete := []appVersion{}
+	for rows.Next() {
+		var v appVersion
+		var hasDownstreamVersion, hasDownstreams bool
+		if err := rows.Scan(&v.appID, &v.sequence, &hasDownstreamVersion, &hasDownstreams); err != nil {
+			return 0, errors.Wrap(err, "failed to scan")
+		}
+		hasArchive := existingKeys[fmt.Sprintf("%s/%d.tar.gz", v.appID, v.sequence)]
+		if !hasArchive || (hasDownstreams && !hasDownstreamVersion) {
+			incomplete = append(incomplete, v)
+		}
+	}
+	rows.Close()
+
+	deleted := int64(0)
+	for _, v := range incomplete {
+		if err := s.deleteIncompleteAppVersion(v.appID, v.sequence); err != nil {
+			return deleted, errors.Wrapf(err, "failed to delete app %s sequence %d", v.appID, v.sequence)
+		}
+		logger.Info("deleted incomplete app version",
+			zap.String("appID", v.appID),
+			zap.Int64("sequence", v.sequence))
+		deleted++
+	}
+
+	return deleted, nil
+}
+
+func (s *KOTSStore) deleteIncompleteAppVersion(appID string, sequence int64) error {
+	db := persistence.MustGetPGSession()
+
+	tx, err := db.Begin()
+	if err != nil {
+		return errors.Wrap(err, "failed to begin")
+	}
+	defer tx.Rollback()
+
+	query := `delete from app_downstream_version where app_id = $1 and sequence = $2`
+	if _, err := tx.Exec(query, appID, sequence); err != nil {
+		return errors.Wrap(err, "failed to delete from app_downstream_version")
+	}
+
+	query = `delete from app_version where app_id = $1 and sequence = $2`
+	if _, err := tx.Exec(query, appID, sequence); err != nil {
+		return errors.Wrap(err, "failed to delete from app_version")
+	}
+
+	query = `update app set current_sequence = (select max(sequence) from app_version where app_id = $1) where id = $1 and current_sequence = $2`
+	if _, err := tx.Exec(query, appID, sequence); err != nil {
+		return errors.Wrap(err, "failed to update app current sequence")
+	}
+
+	if err := tx.Commit(); err != nil {
+		return errors.Wrap(err, "failed to commit")
+	}
+
+	if err := s.deleteAppVersionArchive(appID, sequence); err != nil {
+		return errors.Wrap(err, "failed to delete archive")
+	}
+
+	return nil
+}
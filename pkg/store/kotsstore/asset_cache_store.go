@@ -0,0 +1,81 @@
+package kotsstore
+
+import (
+	"database/sql"
+	"encoding/base64"
+
+	"github.com/pkg/errors"
+	assetcachetypes "github.com/replicatedhq/kots/pkg/assetcache/types"
+	"github.com/replicatedhq/kots/pkg/persistence"
+)
+
+func (s *KOTSStore) RegisterCachedAsset(cacheKey string, uri string) error {
+	db := persistence.MustGetPGSession()
+	query := `insert into asset_cache (cache_key, uri) values ($1, $2) on conflict (cache_key) do nothing`
+	_, err := db.Exec(query, cacheKey, uri)
+	if err != nil {
+		return errors.Wrap(err, "failed to exec")
+	}
+
+	return nil
+}
+
+// GetCachedAsset returns the cached asset, or nil if no asset is registered with the cache key
+func (s *KOTSStore) GetCachedAsset(cacheKey string) (*assetcachetypes.Asset, error) {
+	db := persistence.MustGetPGSession()
+	query := `select uri, content_type, data, etag, fetched_at, fetch_error from asset_cache where cache_key = $1`
+	row := db.QueryRow(query, cacheKey)
+
+	var contentType sql.NullString
+	var data sql.NullString
+	var etag sql.NullString
+	var fetchedAt sql.NullTime
+	var fetchError sql.NullString
+
+	asset := assetcachetypes.Asset{
+		CacheKey: cacheKey,
+	}
+	if err := row.Scan(&asset.URI, &contentType, &data, &etag, &fetchedAt, &fetchError); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, errors.Wrap(err, "failed to scan")
+	}
+
+	if data.Valid && data.String != "" {
+		decoded, err := base64.StdEncoding.DecodeString(data.String)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to decode data")
+		}
+		asset.Data = decoded
+	}
+
+	asset.ContentType = contentType.String
+	asset.ETag = etag.String
+	asset.FetchError = fetchError.String
+	if fetchedAt.Valid {
+		asset.FetchedAt = &fetchedAt.Time
+	}
+
+	return &asset, nil
+}
+
+// SetCachedAsset stores the result of a fetch of the asset. The data of a failed fetch is kept by the caller,
+// so that the last successfully fetched asset can still be served.
+func (s *KOTSStore) SetCachedAsset(asset assetcachetypes.Asset) error {
+	var data sql.NullString
+	if len(asset.Data) > 0 {
+		data = sql.NullString{String: base64.StdEncoding.EncodeToString(asset.Data), Valid: true}
+	}
+
+	db := persistence.MustGetPGSession()
+	query := `insert into asset_cache (cache_key, uri, content_type, data, etag, fetched_at, fetch_error) values ($1, $2, $3, $4, $5, $6, $7)
+on conflict (cache_key) do update set uri = EXCLUDED.uri, content_type = EXCLUDED.content_type, data = EXCLUDED.data,
+etag = EXCLUDED.etag, fetched_at = EXCLUDED.fetched_at, fetch_error = EXCLUDED.fetch_error`
+	_, err := db.Exec(query, asset.CacheKey, asset.URI, asset.ContentType, data, asset.ETag, asset.FetchedAt, asset.FetchError)
+	if err != nil {
+		return errors.Wrap(err, "failed to exec")
+	}
+
+	return nil
+}
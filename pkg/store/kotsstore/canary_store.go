@@ -0,0 +1,81 @@
+package kotsstore
+
+import (
+	"database/sql"
+
+	"github.com/pkg/errors"
+	canarytypes "github.com/replicatedhq/kots/pkg/canary/types"
+	"github.com/replicatedhq/kots/pkg/logger"
+	"github.com/replicatedhq/kots/pkg/persistence"
+	"go.uber.org/zap"
+)
+
+func (s *KOTSStore) ListCanaryDeploys(appID string) ([]canarytypes.Deploy, error) {
+	db := persistence.MustGetPGSession()
+	query := `SELECT id, app_id, sequence, cluster_id, previous_sequence, status, message, started_at, bake_until, finished_at FROM app_canary_deploy WHERE app_id = $1 ORDER BY started_at DESC`
+	rows, err := db.Query(query, appID)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to query")
+	}
+	defer rows.Close()
+
+	deploys := []canarytypes.Deploy{}
+	for rows.Next() {
+		var previousSequence sql.NullInt64
+		var message sql.NullString
+		var finishedAt sql.NullTime
+
+		deploy := canarytypes.Deploy{}
+		if err := rows.Scan(&deploy.ID, &deploy.AppID, &deploy.Sequence, &deploy.ClusterID, &previousSequence, &deploy.Status, &message, &deploy.StartedAt, &deploy.BakeUntil, &finishedAt); err != nil {
+			return nil, errors.Wrap(err, "failed to scan")
+		}
+
+		deploy.PreviousSequence = -1
+		if previousSequence.Valid {
+			deploy.PreviousSequence = previousSequence.Int64
+		}
+		deploy.Message = message.String
+		if finishedAt.Valid {
+			deploy.FinishedAt = &finishedAt.Time
+		}
+
+		deploys = append(deploys, deploy)
+	}
+
+	return deploys, nil
+}
+
+func (s *KOTSStore) CreateCanaryDeploy(deploy canarytypes.Deploy) error {
+	logger.Debug("Creating canary deploy",
+		zap.String("appID", deploy.AppID),
+		zap.Int64("sequence", deploy.Sequence))
+
+	var previousSequence sql.NullInt64
+	if deploy.PreviousSequence != -1 {
+		previousSequence = sql.NullInt64{Int64: deploy.PreviousSequence, Valid: true}
+	}
+
+	db := persistence.MustGetPGSession()
+	query := `INSERT INTO app_canary_deploy (id, app_id, sequence, cluster_id, previous_sequence, status, started_at, bake_until) VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`
+	_, err := db.Exec(query, deploy.ID, deploy.AppID, deploy.Sequence, deploy.ClusterID, previousSequence, deploy.Status, deploy.StartedAt, deploy.BakeUntil)
+	if err != nil {
+		return errors.Wrap(err, "failed to exec")
+	}
+
+	return nil
+}
+
+func (s *KOTSStore) UpdateCanaryDeploy(deploy canarytypes.Deploy) error {
+	logger.Debug("Updating canary deploy",
+		zap.String("ID", deploy.ID),
+		zap.String("status", string(deploy.Status)))
+
+	db := persistence.MustGetPGSession()
+	query := `UPDATE app_canary_deploy SET status = $1, message = $2, finished_at = $3 WHERE id = $4`
+	_, err := db.Exec(query, deploy.Status, deploy.Message, deploy.FinishedAt, deploy.ID)
+	if err != nil {
+		return errors.Wrap(err, "failed to exec")
+	}
+
+	return nil
+}
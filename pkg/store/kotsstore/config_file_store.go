@@ -0,0 +1,43 @@
+package kotsstore
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/pkg/errors"
+	"github.com/replicatedhq/kots/pkg/objectstore"
+)
+
+// config files are content addressed so that the same file uploaded for several versions is stored once
+func configFileKey(checksum string) string {
+	return fmt.Sprintf("configfiles/%s", checksum)
+}
+
+// CreateConfigFile uploads a large config item file that is referenced from config values by its checksum
+func (s *KOTSStore) CreateConfigFile(checksum string, filePath string) error {
+	driver, err := objectstore.GetDriver()
+	if err != nil {
+		return errors.Wrap(err, "failed to get object store driver")
+	}
+
+	if err := objectstore.Upload(context.TODO(), driver, configFileKey(checksum), filePath); err != nil {
+		return errors.Wrap(err, "failed to upload config file")
+	}
+
+	return nil
+}
+
+func (s *KOTSStore) GetConfigFile(checksum string) (io.ReadCloser, error) {
+	driver, err := objectstore.GetDriver()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get object store driver")
+	}
+
+	r, err := driver.GetObject(context.TODO(), configFileKey(checksum))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get config file")
+	}
+
+	return r, nil
+}
@@ -0,0 +1,162 @@
+package kotsstore
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/pkg/errors"
+	deployapprovaltypes "github.com/replicatedhq/kots/pkg/deployapproval/types"
+	"github.com/replicatedhq/kots/pkg/logger"
+	"github.com/replicatedhq/kots/pkg/persistence"
+	"go.uber.org/zap"
+)
+
+const deployApprovalColumns = `id, app_id, sequence, status, requested_by, requested_at, decided_by, decided_at, is_skip_preflights, continue_with_failed_preflights, is_cli`
+
+func (s *KOTSStore) CreateDeployApproval(approval deployapprovaltypes.DeployApproval) error {
+	logger.Debug("creating deploy approval",
+		zap.String("appID", approval.AppID),
+		zap.Int64("sequence", approval.Sequence))
+
+	db := persistence.MustGetPGSession()
+	query := `insert into app_deploy_approval (` + deployApprovalColumns + `) values ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)`
+	_, err := db.Exec(query,
+		approval.ID,
+		approval.AppID,
+		approval.Sequence,
+		approval.Status,
+		approval.RequestedBy,
+		approval.RequestedAt,
+		approval.DecidedBy,
+		approval.DecidedAt,
+		approval.IsSkipPreflights,
+		approval.ContinueWithFailedPreflights,
+		approval.IsCLI,
+	)
+	if err != nil {
+		return errors.Wrap(err, "failed to exec")
+	}
+
+	return nil
+}
+
+func (s *KOTSStore) GetDeployApproval(approvalID string) (*deployapprovaltypes.DeployApproval, error) {
+	db := persistence.MustGetPGSession()
+	query := `select ` + deployApprovalColumns + ` from app_deploy_approval where id = $1`
+	row := db.QueryRow(query, approvalID)
+
+	approval, err := scanDeployApproval(row)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to scan")
+	}
+
+	return approval, nil
+}
+
+// GetPendingDeployApproval returns the pending approval for the sequence, or nil if there is none
+func (s *KOTSStore) GetPendingDeployApproval(appID string, sequence int64) (*deployapprovaltypes.DeployApproval, error) {
+	db := persistence.MustGetPGSession()
+	query := `select ` + deployApprovalColumns + ` from app_deploy_approval where app_id = $1 and sequence = $2 and status = $3`
+	row := db.QueryRow(query, appID, sequence, deployapprovaltypes.StatusPending)
+
+	approval, err := scanDeployApproval(row)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, errors.Wrap(err, "failed to scan")
+	}
+
+	return approval, nil
+}
+
+// ListDeployApprovals returns the deploy approvals of an app, newest first
+func (s *KOTSStore) ListDeployApprovals(appID string) ([]deployapprovaltypes.DeployApproval, error) {
+	db := persistence.MustGetPGSession()
+	query := `select ` + deployApprovalColumns + ` from app_deploy_approval where app_id = $1 order by requested_at desc`
+	rows, err := db.Query(query, appID)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to query")
+	}
+	defer rows.Close()
+
+	approvals := []deployapprovaltypes.DeployApproval{}
+	for rows.Next() {
+		approval, err := scanDeployApproval(rows)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to scan")
+		}
+		approvals = append(approvals, *approval)
+	}
+
+	return approvals, nil
+}
+
+func (s *KOTSStore) SetDeployApprovalDecision(approvalID string, status deployapprovaltypes.Status, decidedBy string, decidedAt time.Time) (bool, error) {
+	logger.Debug("setting deploy approval decision",
+		zap.String("approvalID", approvalID),
+		zap.String("status", string(status)))
+
+	db := persistence.MustGetPGSession()
+	// only a pending approval is decided, so that two concurrent decisions can't both succeed
+	query := `update app_deploy_approval set status = $1, decided_by = $2, decided_at = $3 where id = $4 and status = $5`
+	result, err := db.Exec(query, status, decidedBy, decidedAt, approvalID, deployapprovaltypes.StatusPending)
+	if err != nil {
+		return false, errors.Wrap(err, "failed to exec")
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, errors.Wrap(err, "failed to get rows affected")
+	}
+
+	return rowsAffected > 0, nil
+}
+
+func (s *KOTSStore) ReopenDeployApproval(approvalID string) error {
+	logger.Debug("reopening deploy approval",
+		zap.String("approvalID", approvalID))
+
+	db := persistence.MustGetPGSession()
+	query := `update app_deploy_approval set status = $1, decided_by = null, decided_at = null where id = $2 and status = $3`
+	_, err := db.Exec(query, deployapprovaltypes.StatusPending, approvalID, deployapprovaltypes.StatusApproved)
+	if err != nil {
+		return errors.Wrap(err, "failed to exec")
+	}
+
+	return nil
+}
+
+type deployApprovalScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanDeployApproval(row deployApprovalScanner) (*deployapprovaltypes.DeployApproval, error) {
+	approval := deployapprovaltypes.DeployApproval{}
+
+	var status string
+	var requestedBy sql.NullString
+	var requestedAt sql.NullTime
+	var decidedBy sql.NullString
+	var decidedAt sql.NullTime
+	var isSkipPreflights sql.NullBool
+	var continueWithFailedPreflights sql.NullBool
+	var isCLI sql.NullBool
+
+	if err := row.Scan(&approval.ID, &approval.AppID, &approval.Sequence, &status, &requestedBy, &requestedAt, &decidedBy, &decidedAt, &isSkipPreflights, &continueWithFailedPreflights, &isCLI); err != nil {
+		return nil, err
+	}
+
+	approval.Status = deployapprovaltypes.Status(status)
+	approval.RequestedBy = requestedBy.String
+	approval.RequestedAt = requestedAt.Time
+	approval.DecidedBy = decidedBy.String
+	if decidedAt.Valid {
+		approval.DecidedAt = &decidedAt.Time
+	}
+	approval.IsSkipPreflights = isSkipPreflights.Bool
+	approval.ContinueWithFailedPreflights = continueWithFailedPreflights.Bool
+	approval.IsCLI = isCLI.Bool
+
+	return &approval, nil
+}
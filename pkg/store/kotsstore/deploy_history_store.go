@@ -0,0 +1,103 @@
+package kotsstore
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/pkg/errors"
+	deployhistorytypes "github.com/replicatedhq/kots/pkg/deployhistory/types"
+	"github.com/replicatedhq/kots/pkg/persistence"
+)
+
+// SetDeployEventResult sets the result of the pending deploy events of the sequence, once the operator reports it.
+// The events of activations are set with SetDownstreamActivationResult.
+func (s *KOTSStore) SetDeployEventResult(appID string, clusterID string, sequence int64, isError bool) error {
+	result := deployhistorytypes.ResultDeployed
+	if isError {
+		result = deployhistorytypes.ResultFailed
+	}
+
+	db := persistence.MustGetPGSession()
+	query := `update app_deploy_event set result = $1, result_at = $2 where app_id = $3 and cluster_id = $4 and sequence = $5 and result = $6 and phase is null`
+	_, err := db.Exec(query, result, time.Now(), appID, clusterID, sequence, deployhistorytypes.ResultPending)
+	if err != nil {
+		return errors.Wrap(err, "failed to exec")
+	}
+
+	return nil
+}
+
+// ListDeployEvents returns the deploy events that match the options, oldest first
+func (s *KOTSStore) ListDeployEvents(opts deployhistorytypes.ListOptions) ([]deployhistorytypes.Event, error) {
+	db := persistence.MustGetPGSession()
+	query := `select e.id, e.app_id, a.slug, e.cluster_id, d.downstream_name, e.sequence, v.version_label, e.deployed_at,
+	e.deployed_by, e.approved_by, e.preflight_state, e.result, e.result_at, e.phase
+	from app_deploy_event e
+	inner join app a on a.id = e.app_id
+	left join app_downstream d on d.app_id = e.app_id and d.cluster_id = e.cluster_id
+	left join app_downstream_version v on v.app_id = e.app_id and v.cluster_id = e.cluster_id and v.sequence = e.sequence
+	where true`
+	args := []interface{}{}
+	if opts.AppID != "" {
+		args = append(args, opts.AppID)
+		query += fmt.Sprintf(" and e.app_id = $%d", len(args))
+	}
+	if opts.From != nil {
+		args = append(args, *opts.From)
+		query += fmt.Sprintf(" and e.deployed_at >= $%d", len(args))
+	}
+	if opts.To != nil {
+		args = append(args, *opts.To)
+		query += fmt.Sprintf(" and e.deployed_at < $%d", len(args))
+	}
+	query += " order by e.deployed_at asc, e.id asc"
+
+	rows, err := db.Query(query, args...)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to query")
+	}
+	defer rows.Close()
+
+	events := []deployhistorytypes.Event{}
+	for rows.Next() {
+		event := deployhistorytypes.Event{}
+		var downstreamName, versionLabel, deployedBy, approvedBy, preflightState, phase sql.NullString
+		var resultAt sql.NullTime
+		if err := rows.Scan(
+			&event.ID,
+			&event.AppID,
+			&event.AppSlug,
+			&event.ClusterID,
+			&downstreamName,
+			&event.Sequence,
+			&versionLabel,
+			&event.DeployedAt,
+			&deployedBy,
+			&approvedBy,
+			&preflightState,
+			&event.Result,
+			&resultAt,
+			&phase,
+		); err != nil {
+			return nil, errors.Wrap(err, "failed to scan")
+		}
+
+		event.DownstreamName = downstreamName.String
+		event.VersionLabel = versionLabel.String
+		event.DeployedBy = deployedBy.String
+		event.ApprovedBy = approvedBy.String
+		event.PreflightState = preflightState.String
+		event.Phase = phase.String
+		if resultAt.Valid {
+			event.ResultAt = &resultAt.Time
+		}
+
+		events = append(events, event)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, errors.Wrap(err, "failed to iterate rows")
+	}
+
+	return events, nil
+}
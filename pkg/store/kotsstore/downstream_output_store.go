@@ -0,0 +1,95 @@
+package kotsstore
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/replicatedhq/kots/pkg/objectstore"
+)
+
+func downstreamOutputArchivePrefix(appID string, clusterID string) string {
+	return fmt.Sprintf("downstreamoutput/%s/%s/", appID, clusterID)
+}
+
+func downstreamOutputArchiveKey(appID string, clusterID string, sequence int64) string {
+	return fmt.Sprintf("%s%d.tar.gz", downstreamOutputArchivePrefix(appID, clusterID), sequence)
+}
+
+// CreateDownstreamOutputArchive uploads the compressed, untruncated dry run and apply output for a sequence
+func (s *KOTSStore) CreateDownstreamOutputArchive(appID string, clusterID string, sequence int64, archivePath string) error {
+	driver, err := objectstore.GetDriver()
+	if err != nil {
+		return errors.Wrap(err, "failed to get object store driver")
+	}
+
+	if err := objectstore.Upload(context.TODO(), driver, downstreamOutputArchiveKey(appID, clusterID, sequence), archivePath); err != nil {
+		return errors.Wrap(err, "failed to upload downstream output archive")
+	}
+
+	return nil
+}
+
+// GetDownstreamOutputArchive downloads the output archive for a sequence to a temp dir and returns its path.
+// The caller is responsible for removing the temp dir.
+func (s *KOTSStore) GetDownstreamOutputArchive(appID string, clusterID string, sequence int64) (string, error) {
+	driver, err := objectstore.GetDriver()
+	if err != nil {
+		return "", errors.Wrap(err, "failed to get object store driver")
+	}
+
+	tmpDir, err := ioutil.TempDir("", "kotsadm")
+	if err != nil {
+		return "", errors.Wrap(err, "failed to create temp dir")
+	}
+
+	outputFile, err := os.Create(filepath.Join(tmpDir, "output.tar.gz"))
+	if err != nil {
+		os.RemoveAll(tmpDir)
+		return "", errors.Wrap(err, "failed to open file")
+	}
+	defer outputFile.Close()
+
+	if err := objectstore.Download(context.TODO(), driver, downstreamOutputArchiveKey(appID, clusterID, sequence), outputFile); err != nil {
+		os.RemoveAll(tmpDir)
+		return "", errors.Wrap(err, "failed to download downstream output archive")
+	}
+
+	return outputFile.Name(), nil
+}
+
+// DeleteDownstreamOutputArchivesBefore removes the output archives for all sequences lower than the one provided
+func (s *KOTSStore) DeleteDownstreamOutputArchivesBefore(appID string, clusterID string, sequence int64) error {
+	driver, err := objectstore.GetDriver()
+	if err != nil {
+		return errors.Wrap(err, "failed to get object store driver")
+	}
+
+	keysToDelete := []string{}
+	err = driver.ListObjects(context.TODO(), downstreamOutputArchivePrefix(appID, clusterID), func(object objectstore.Object) error {
+		name := strings.TrimSuffix(path.Base(object.Key), ".tar.gz")
+		archiveSequence, err := strconv.ParseInt(name, 10, 64)
+		if err != nil {
+			return nil
+		}
+		if archiveSequence < sequence {
+			keysToDelete = append(keysToDelete, object.Key)
+		}
+		return nil
+	})
+	if err != nil {
+		return errors.Wrap(err, "failed to list downstream output archives")
+	}
+
+	if err := driver.DeleteObjects(context.TODO(), keysToDelete); err != nil {
+		return errors.Wrap(err, "failed to delete downstream output archives")
+	}
+
+	return nil
+}
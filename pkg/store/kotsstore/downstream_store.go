@@ -3,17 +3,24 @@ package kotsstore
 import (
 	"database/sql"
 	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
 
 	"github.com/pkg/errors"
 	kotsv1beta1 "github.com/replicatedhq/kots/kotskinds/apis/kots/v1beta1"
+	admissiontypes "github.com/replicatedhq/kots/pkg/admission/types"
 	"github.com/replicatedhq/kots/pkg/api/downstream/types"
+	deployhistorytypes "github.com/replicatedhq/kots/pkg/deployhistory/types"
 	"github.com/replicatedhq/kots/pkg/logger"
 	"github.com/replicatedhq/kots/pkg/persistence"
+	postdeploytesttypes "github.com/replicatedhq/kots/pkg/postdeploytest/types"
 	"k8s.io/client-go/kubernetes/scheme"
 )
 
 func (s *KOTSStore) GetCurrentSequence(appID string, clusterID string) (int64, error) {
-	db := persistence.MustGetPGSession()
+	db := s.readSession()
 	query := `select current_sequence from app_downstream where app_id = $1 and cluster_id = $2`
 	row := db.QueryRow(query, appID, clusterID)
 
@@ -174,7 +181,7 @@ func (s *KOTSStore) GetCurrentVersion(appID string, clusterID string) (*types.Do
 		return nil, nil
 	}
 
-	db := persistence.MustGetPGSession()
+	db := s.readSession()
 	query := `SELECT
 	adv.created_at,
 	adv.version_label,
@@ -191,7 +198,13 @@ func (s *KOTSStore) GetCurrentVersion(appID string, clusterID string) (*types.Do
 	adv.git_deployable,
 	ado.is_error,
 	av.upstream_released_at,
-	av.kots_installation_spec
+	av.kots_installation_spec,
+	av.notes,
+	av.labels,
+	av.kots_app_spec,
+	adv.activation_status,
+	adv.activation_status_info,
+	adv.activated_at
  FROM
 	 app_downstream_version AS adv
  LEFT JOIN
@@ -248,7 +261,7 @@ func (s *KOTSStore) GetPendingVersions(appID string, clusterID string) ([]types.
 		return nil, errors.Wrap(err, "failed to get current sequence")
 	}
 
-	db := persistence.MustGetPGSession()
+	db := s.readSession()
 	query := `SELECT
 	adv.created_at,
 	adv.version_label,
@@ -265,7 +278,13 @@ func (s *KOTSStore) GetPendingVersions(appID string, clusterID string) ([]types.
 	adv.git_deployable,
 	ado.is_error,
 	av.upstream_released_at,
-	av.kots_installation_spec
+	av.kots_installation_spec,
+	av.notes,
+	av.labels,
+	av.kots_app_spec,
+	adv.activation_status,
+	adv.activation_status_info,
+	adv.activated_at
  FROM
 	 app_downstream_version AS adv
  LEFT JOIN
@@ -303,6 +322,44 @@ func (s *KOTSStore) GetPendingVersions(appID string, clusterID string) ([]types.
 	return versions, nil
 }
 
+func (s *KOTSStore) ListPendingVersionCounts() (map[string]int64, error) {
+	db := s.readSession()
+	query := `SELECT
+	adv.app_id,
+	count(*)
+ FROM
+	 app_downstream_version AS adv
+ INNER JOIN
+	 app_downstream AS ad
+ ON
+	 adv.app_id = ad.app_id AND adv.cluster_id = ad.cluster_id
+ WHERE
+	 ad.current_sequence IS NULL OR adv.sequence > ad.current_sequence
+ GROUP BY
+	 adv.app_id`
+
+	rows, err := db.Query(query)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to query")
+	}
+	defer rows.Close()
+
+	counts := map[string]int64{}
+	for rows.Next() {
+		var appID string
+		var count int64
+		if err := rows.Scan(&appID, &count); err != nil {
+			return nil, errors.Wrap(err, "failed to scan")
+		}
+		counts[appID] = count
+	}
+	if err := rows.Err(); err != nil {
+		return nil, errors.Wrap(err, "failed to iterate rows")
+	}
+
+	return counts, nil
+}
+
 func (s *KOTSStore) GetPastVersions(appID string, clusterID string) ([]types.DownstreamVersion, error) {
 	currentSequence, err := s.GetCurrentSequence(appID, clusterID)
 	if err != nil {
@@ -312,7 +369,7 @@ func (s *KOTSStore) GetPastVersions(appID string, clusterID string) ([]types.Dow
 		return []types.DownstreamVersion{}, nil
 	}
 
-	db := persistence.MustGetPGSession()
+	db := s.readSession()
 	query := `SELECT
 	adv.created_at,
 	adv.version_label,
@@ -329,7 +386,13 @@ func (s *KOTSStore) GetPastVersions(appID string, clusterID string) ([]types.Dow
 	adv.git_deployable,
 	ado.is_error,
 	av.upstream_released_at,
-	av.kots_installation_spec
+	av.kots_installation_spec,
+	av.notes,
+	av.labels,
+	av.kots_app_spec,
+	adv.activation_status,
+	adv.activation_status_info,
+	adv.activated_at
  FROM
 	 app_downstream_version AS adv
  LEFT JOIN
@@ -367,6 +430,128 @@ func (s *KOTSStore) GetPastVersions(appID string, clusterID string) ([]types.Dow
 	return versions, nil
 }
 
+// downstreamVersionStatusSQL is getDownstreamVersionStatus as a sql expression, so that versions can be filtered by
+// status in the query
+const downstreamVersionStatusSQL = `CASE
+	WHEN ado.is_error = false THEN COALESCE(adv.status, '')
+	WHEN ado.is_error = true THEN 'failed'
+	WHEN adv.status = 'deployed' THEN 'deploying'
+	WHEN COALESCE(adv.status, '') != '' THEN adv.status
+	ELSE 'unknown'
+END`
+
+func (s *KOTSStore) ListDownstreamVersions(appID string, clusterID string, opts types.VersionHistoryOptions) ([]types.DownstreamVersion, int64, error) {
+	db := s.readSession()
+
+	args := []interface{}{appID, clusterID}
+	arg := func(value interface{}) string {
+		args = append(args, value)
+		return fmt.Sprintf("$%d", len(args))
+	}
+
+	where := []string{"adv.app_id = $1", "adv.cluster_id = $2"}
+	if len(opts.Statuses) > 0 {
+		placeholders := []string{}
+		for _, status := range opts.Statuses {
+			placeholders = append(placeholders, arg(strings.ToLower(status)))
+		}
+		where = append(where, fmt.Sprintf("lower(%s) IN (%s)", downstreamVersionStatusSQL, strings.Join(placeholders, ", ")))
+	}
+	if len(opts.Sources) > 0 {
+		placeholders := []string{}
+		for _, source := range opts.Sources {
+			placeholders = append(placeholders, arg(strings.ToLower(source)))
+		}
+		where = append(where, fmt.Sprintf("lower(adv.source) IN (%s)", strings.Join(placeholders, ", ")))
+	}
+	if opts.CreatedAfter != nil {
+		where = append(where, fmt.Sprintf("adv.created_at >= %s", arg(*opts.CreatedAfter)))
+	}
+	if opts.CreatedBefore != nil {
+		where = append(where, fmt.Sprintf("adv.created_at < %s", arg(*opts.CreatedBefore)))
+	}
+	whereClause := strings.Join(where, " AND\n\t ")
+
+	countQuery := fmt.Sprintf(`SELECT
+	count(1)
+ FROM
+	 app_downstream_version AS adv
+ LEFT JOIN
+	 app_downstream_output AS ado
+ ON
+	 adv.app_id = ado.app_id AND adv.cluster_id = ado.cluster_id AND adv.sequence = ado.downstream_sequence
+ WHERE
+	 %s`, whereClause)
+
+	var total int64
+	if err := db.QueryRow(countQuery, args...).Scan(&total); err != nil {
+		return nil, 0, errors.Wrap(err, "failed to count versions")
+	}
+
+	query := fmt.Sprintf(`SELECT
+	adv.created_at,
+	adv.version_label,
+	adv.status,
+	adv.sequence,
+	adv.parent_sequence,
+	adv.applied_at,
+	adv.source,
+	adv.diff_summary,
+	adv.diff_summary_error,
+	adv.preflight_result,
+	adv.preflight_result_created_at,
+	adv.git_commit_url,
+	adv.git_deployable,
+	ado.is_error,
+	av.upstream_released_at,
+	av.kots_installation_spec,
+	av.notes,
+	av.labels,
+	av.kots_app_spec,
+	adv.activation_status,
+	adv.activation_status_info,
+	adv.activated_at
+ FROM
+	 app_downstream_version AS adv
+ LEFT JOIN
+	 app_version AS av
+ ON
+	 adv.app_id = av.app_id AND adv.parent_sequence = av.sequence
+ LEFT JOIN
+	 app_downstream_output AS ado
+ ON
+	 adv.app_id = ado.app_id AND adv.cluster_id = ado.cluster_id AND adv.sequence = ado.downstream_sequence
+ WHERE
+	 %s
+ ORDER BY
+	 adv.sequence DESC`, whereClause)
+	if opts.Limit > 0 {
+		query += fmt.Sprintf("\n LIMIT %s", arg(opts.Limit))
+	}
+	if opts.Offset > 0 {
+		query += fmt.Sprintf("\n OFFSET %s", arg(opts.Offset))
+	}
+
+	rows, err := db.Query(query, args...)
+	if err != nil {
+		return nil, 0, errors.Wrap(err, "failed to query")
+	}
+	defer rows.Close()
+
+	versions := []types.DownstreamVersion{}
+	for rows.Next() {
+		v, err := downstreamVersionFromRow(appID, rows)
+		if err != nil {
+			return nil, 0, errors.Wrap(err, "failed to get version from row")
+		}
+		if v != nil {
+			versions = append(versions, *v)
+		}
+	}
+
+	return versions, total, nil
+}
+
 func downstreamVersionFromRow(appID string, row scannable) (*types.DownstreamVersion, error) {
 	v := &types.DownstreamVersion{}
 
@@ -385,6 +570,12 @@ func downstreamVersionFromRow(appID string, row scannable) (*types.DownstreamVer
 	var hasError sql.NullBool
 	var upstreamReleasedAt sql.NullTime
 	var kotsInstallationSpecStr sql.NullString
+	var notes sql.NullString
+	var labels sql.NullString
+	var kotsAppSpecStr sql.NullString
+	var activationStatus sql.NullString
+	var activationStatusInfo sql.NullString
+	var activatedAt sql.NullTime
 
 	if err := row.Scan(
 		&createdOn,
@@ -403,6 +594,12 @@ func downstreamVersionFromRow(appID string, row scannable) (*types.DownstreamVer
 		&hasError,
 		&upstreamReleasedAt,
 		&kotsInstallationSpecStr,
+		&notes,
+		&labels,
+		&kotsAppSpecStr,
+		&activationStatus,
+		&activationStatusInfo,
+		&activatedAt,
 	); err != nil {
 		return nil, errors.Wrap(err, "failed to scan")
 	}
@@ -447,6 +644,29 @@ func downstreamVersionFromRow(appID string, row scannable) (*types.DownstreamVer
 		installationSpec := obj.(*kotsv1beta1.Installation)
 
 		v.YamlErrors = installationSpec.Spec.YAMLErrors
+		v.IsRequired = installationSpec.Spec.IsRequired
+	}
+
+	v.Notes = notes.String
+	versionLabels, err := versionLabelsFromString(labels)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get labels")
+	}
+	v.Labels = versionLabels
+
+	if kotsAppSpecStr.Valid && kotsAppSpecStr.String != "" {
+		decode := scheme.Codecs.UniversalDeserializer().Decode
+		obj, _, err := decode([]byte(kotsAppSpecStr.String), nil, nil)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to decode kots app spec yaml")
+		}
+		v.TwoPhaseDeploy = obj.(*kotsv1beta1.Application).Spec.TwoPhaseDeploy
+	}
+
+	v.ActivationStatus = activationStatus.String
+	v.ActivationStatusInfo = activationStatusInfo.String
+	if activatedAt.Valid {
+		v.ActivatedAt = &activatedAt.Time
 	}
 
 	return v, nil
@@ -585,15 +805,34 @@ func (s *KOTSStore) IsDownstreamDeploySuccessful(appID string, clusterID string,
 func (s *KOTSStore) UpdateDownstreamDeployStatus(appID string, clusterID string, sequence int64, isError bool, output types.DownstreamOutput) error {
 	db := persistence.MustGetPGSession()
 
+	tx, err := db.Begin()
+	if err != nil {
+		return errors.Wrap(err, "failed to begin")
+	}
+	defer tx.Rollback()
+
 	query := `insert into app_downstream_output (app_id, cluster_id, downstream_sequence, is_error, dryrun_stdout, dryrun_stderr, apply_stdout, apply_stderr)
 	values ($1, $2, $3, $4, $5, $6, $7, $8) on conflict (app_id, cluster_id, downstream_sequence) do update set is_error = EXCLUDED.is_error,
 	dryrun_stdout = EXCLUDED.dryrun_stdout, dryrun_stderr = EXCLUDED.dryrun_stderr, apply_stdout = EXCLUDED.apply_stdout, apply_stderr = EXCLUDED.apply_stderr`
 
-	_, err := db.Exec(query, appID, clusterID, sequence, isError, output.DryrunStdout, output.DryrunStderr, output.ApplyStdout, output.ApplyStderr)
+	_, err = tx.Exec(query, appID, clusterID, sequence, isError, output.DryrunStdout, output.DryrunStderr, output.ApplyStdout, output.ApplyStderr)
 	if err != nil {
 		return errors.Wrap(err, "failed to exec")
 	}
 
+	// versions deployed to a single downstream are deploying until the operator reports a successful deploy
+	if !isError {
+		query = `update app_downstream_version set status = 'deployed' where app_id = $1 and cluster_id = $2 and sequence = $3 and status = 'deploying'`
+		_, err = tx.Exec(query, appID, clusterID, sequence)
+		if err != nil {
+			return errors.Wrap(err, "failed to update app downstream version status")
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return errors.Wrap(err, "failed to commit")
+	}
+
 	return nil
 }
 
@@ -609,3 +848,197 @@ func (s *KOTSStore) DeleteDownstreamDeployStatus(appID string, clusterID string,
 
 	return nil
 }
+
+func (s *KOTSStore) GetDownstreamPostDeployTestResults(appID string, clusterID string, sequence int64) ([]postdeploytesttypes.Result, error) {
+	db := persistence.MustGetPGSession()
+
+	query := `select post_deploy_test_results from app_downstream_output where app_id = $1 and cluster_id = $2 and downstream_sequence = $3`
+	row := db.QueryRow(query, appID, clusterID, sequence)
+
+	var resultsStr sql.NullString
+	if err := row.Scan(&resultsStr); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, errors.Wrap(err, "failed to scan")
+	}
+
+	if !resultsStr.Valid || resultsStr.String == "" {
+		return nil, nil
+	}
+
+	results := []postdeploytesttypes.Result{}
+	if err := json.Unmarshal([]byte(resultsStr.String), &results); err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal post deploy test results")
+	}
+
+	return results, nil
+}
+
+func (s *KOTSStore) SetDownstreamPostDeployTestResults(appID string, clusterID string, sequence int64, results []postdeploytesttypes.Result) error {
+	marshalledResults, err := json.Marshal(results)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal post deploy test results")
+	}
+
+	db := persistence.MustGetPGSession()
+
+	query := `update app_downstream_output set post_deploy_test_results = $4 where app_id = $1 and cluster_id = $2 and downstream_sequence = $3`
+	_, err = db.Exec(query, appID, clusterID, sequence, string(marshalledResults))
+	if err != nil {
+		return errors.Wrap(err, "failed to exec")
+	}
+
+	return nil
+}
+
+func (s *KOTSStore) GetDownstreamAdmissionValidation(appID string, clusterID string, sequence int64) (*admissiontypes.Validation, error) {
+	db := persistence.MustGetPGSession()
+
+	query := `select results, validated_at from app_downstream_admission_validation where app_id = $1 and cluster_id = $2 and downstream_sequence = $3`
+	row := db.QueryRow(query, appID, clusterID, sequence)
+
+	var resultsStr sql.NullString
+	var validatedAt sql.NullTime
+	if err := row.Scan(&resultsStr, &validatedAt); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, errors.Wrap(err, "failed to scan")
+	}
+
+	validation := admissiontypes.Validation{
+		Results:     []admissiontypes.Result{},
+		ValidatedAt: validatedAt.Time,
+	}
+	if resultsStr.Valid && resultsStr.String != "" {
+		if err := json.Unmarshal([]byte(resultsStr.String), &validation.Results); err != nil {
+			return nil, errors.Wrap(err, "failed to unmarshal admission validation results")
+		}
+	}
+
+	return &validation, nil
+}
+
+func (s *KOTSStore) SetDownstreamAdmissionValidation(appID string, clusterID string, sequence int64, validation admissiontypes.Validation) error {
+	marshalledResults, err := json.Marshal(validation.Results)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal admission validation results")
+	}
+
+	db := persistence.MustGetPGSession()
+
+	query := `insert into app_downstream_admission_validation (app_id, cluster_id, downstream_sequence, results, validated_at) values ($1, $2, $3, $4, $5)
+	on conflict (app_id, cluster_id, downstream_sequence) do update set results = EXCLUDED.results, validated_at = EXCLUDED.validated_at`
+	_, err = db.Exec(query, appID, clusterID, sequence, string(marshalledResults), validation.ValidatedAt)
+	if err != nil {
+		return errors.Wrap(err, "failed to exec")
+	}
+
+	return nil
+}
+
+// GetDownstreamServiceAccount returns the service account ("namespace/name") that the operator applies the
+// app's manifests as, or an empty string if the operator's own identity is used
+func (s *KOTSStore) GetDownstreamServiceAccount(appID string, clusterID string) (string, error) {
+	db := persistence.MustGetPGSession()
+	query := `select deploy_service_account from app_downstream where app_id = $1 and cluster_id = $2`
+	row := db.QueryRow(query, appID, clusterID)
+
+	var serviceAccount sql.NullString
+	if err := row.Scan(&serviceAccount); err != nil {
+		return "", errors.Wrap(err, "failed to scan")
+	}
+
+	return serviceAccount.String, nil
+}
+
+// SetDownstreamServiceAccount sets the service account that the operator applies the app's manifests as.
+// An empty service account clears it.
+func (s *KOTSStore) SetDownstreamServiceAccount(appID string, clusterID string, serviceAccount string) error {
+	db := persistence.MustGetPGSession()
+	query := `update app_downstream set deploy_service_account = $1 where app_id = $2 and cluster_id = $3`
+	_, err := db.Exec(query, sql.NullString{String: serviceAccount, Valid: serviceAccount != ""}, appID, clusterID)
+	if err != nil {
+		return errors.Wrap(err, "failed to exec")
+	}
+
+	return nil
+}
+
+// GetActivatingSequence returns the sequence that is being activated on the downstream, -1 if none is
+func (s *KOTSStore) GetActivatingSequence(appID string, clusterID string) (int64, error) {
+	db := persistence.MustGetPGSession()
+	query := `select sequence from app_downstream_version where app_id = $1 and cluster_id = $2 and activation_status = $3 order by sequence desc limit 1`
+	row := db.QueryRow(query, appID, clusterID, types.ActivationStatusActivating)
+
+	var sequence int64
+	if err := row.Scan(&sequence); err != nil {
+		if err == sql.ErrNoRows {
+			return -1, nil
+		}
+		return -1, errors.Wrap(err, "failed to scan")
+	}
+
+	return sequence, nil
+}
+
+// GetActivatedSequence returns the sequence that was activated last on the downstream, -1 if none was
+func (s *KOTSStore) GetActivatedSequence(appID string, clusterID string) (int64, error) {
+	db := persistence.MustGetPGSession()
+	query := `select sequence from app_downstream_version where app_id = $1 and cluster_id = $2 and activation_status = $3 order by activated_at desc limit 1`
+	row := db.QueryRow(query, appID, clusterID, types.ActivationStatusActivated)
+
+	var sequence int64
+	if err := row.Scan(&sequence); err != nil {
+		if err == sql.ErrNoRows {
+			return -1, nil
+		}
+		return -1, errors.Wrap(err, "failed to scan")
+	}
+
+	return sequence, nil
+}
+
+// SetDownstreamActivationResult sets the result of the activation of the sequence and of its deploy event, once the
+// operator reports it
+func (s *KOTSStore) SetDownstreamActivationResult(appID string, clusterID string, sequence int64, isError bool, statusInfo string) error {
+	status := types.ActivationStatusActivated
+	result := deployhistorytypes.ResultDeployed
+	if isError {
+		status = types.ActivationStatusFailed
+		result = deployhistorytypes.ResultFailed
+	}
+
+	db := persistence.MustGetPGSession()
+	tx, err := db.Begin()
+	if err != nil {
+		return errors.Wrap(err, "failed to begin")
+	}
+	defer tx.Rollback()
+
+	now := time.Now()
+
+	query := `update app_downstream_version set activation_status = $1, activation_status_info = $2, activated_at = $3
+	where app_id = $4 and cluster_id = $5 and sequence = $6 and activation_status = $7`
+	var activatedAt *time.Time
+	if !isError {
+		activatedAt = &now
+	}
+	_, err = tx.Exec(query, status, statusInfo, activatedAt, appID, clusterID, sequence, types.ActivationStatusActivating)
+	if err != nil {
+		return errors.Wrap(err, "failed to update downstream version")
+	}
+
+	query = `update app_deploy_event set result = $1, result_at = $2 where app_id = $3 and cluster_id = $4 and sequence = $5 and result = $6 and phase = $7`
+	_, err = tx.Exec(query, result, now, appID, clusterID, sequence, deployhistorytypes.ResultPending, deployhistorytypes.PhaseActivate)
+	if err != nil {
+		return errors.Wrap(err, "failed to update deploy event")
+	}
+
+	if err := tx.Commit(); err != nil {
+		return errors.Wrap(err, "failed to commit")
+	}
+
+	return nil
+}
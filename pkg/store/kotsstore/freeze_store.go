@@ -0,0 +1,103 @@
+package kotsstore
+
+import (
+	"database/sql"
+	"encoding/json"
+
+	"github.com/pkg/errors"
+	freezetypes "github.com/replicatedhq/kots/pkg/freeze/types"
+	"github.com/replicatedhq/kots/pkg/persistence"
+)
+
+const deployFreezeParam = "DEPLOY_FREEZE"
+
+// GetGlobalFreeze returns the deploy freeze of all apps, or nil if there is none
+func (s *KOTSStore) GetGlobalFreeze() (*freezetypes.Freeze, error) {
+	db := persistence.MustGetPGSession()
+	query := `select value from kotsadm_params where key = $1`
+	row := db.QueryRow(query, deployFreezeParam)
+
+	var value string
+	if err := row.Scan(&value); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, errors.Wrap(err, "failed to scan")
+	}
+
+	return unmarshalFreeze(value)
+}
+
+// SetGlobalFreeze sets the deploy freeze of all apps. A nil freeze lifts it.
+func (s *KOTSStore) SetGlobalFreeze(freeze *freezetypes.Freeze) error {
+	db := persistence.MustGetPGSession()
+
+	if freeze == nil {
+		query := `delete from kotsadm_params where key = $1`
+		_, err := db.Exec(query, deployFreezeParam)
+		if err != nil {
+			return errors.Wrap(err, "failed to exec delete")
+		}
+		return nil
+	}
+
+	marshalled, err := json.Marshal(freeze)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal freeze")
+	}
+
+	query := `insert into kotsadm_params (key, value) values ($1, $2) on conflict (key) do update set value = $2`
+	_, err = db.Exec(query, deployFreezeParam, string(marshalled))
+	if err != nil {
+		return errors.Wrap(err, "failed to exec")
+	}
+
+	return nil
+}
+
+// GetAppFreeze returns the deploy freeze of an app, or nil if there is none
+func (s *KOTSStore) GetAppFreeze(appID string) (*freezetypes.Freeze, error) {
+	db := persistence.MustGetPGSession()
+	query := `select deploy_freeze from app where id = $1`
+	row := db.QueryRow(query, appID)
+
+	var value sql.NullString
+	if err := row.Scan(&value); err != nil {
+		return nil, errors.Wrap(err, "failed to scan")
+	}
+
+	if !value.Valid || value.String == "" {
+		return nil, nil
+	}
+
+	return unmarshalFreeze(value.String)
+}
+
+// SetAppFreeze sets the deploy freeze of an app. A nil freeze lifts it.
+func (s *KOTSStore) SetAppFreeze(appID string, freeze *freezetypes.Freeze) error {
+	var value sql.NullString
+	if freeze != nil {
+		marshalled, err := json.Marshal(freeze)
+		if err != nil {
+			return errors.Wrap(err, "failed to marshal freeze")
+		}
+		value = sql.NullString{String: string(marshalled), Valid: true}
+	}
+
+	db := persistence.MustGetPGSession()
+	query := `update app set deploy_freeze = $1 where id = $2`
+	_, err := db.Exec(query, value, appID)
+	if err != nil {
+		return errors.Wrap(err, "failed to exec")
+	}
+
+	return nil
+}
+
+func unmarshalFreeze(value string) (*freezetypes.Freeze, error) {
+	freeze := freezetypes.Freeze{}
+	if err := json.Unmarshal([]byte(value), &freeze); err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal freeze")
+	}
+	return &freeze, nil
+}
@@ -0,0 +1,59 @@
+package kotsstore
+
+import (
+	"database/sql"
+	"encoding/json"
+
+	"github.com/pkg/errors"
+	imagereporttypes "github.com/replicatedhq/kots/pkg/imagereport/types"
+	"github.com/replicatedhq/kots/pkg/persistence"
+)
+
+// GetImageReport returns the stored image report for the sequence, or nil if one was not generated
+func (s *KOTSStore) GetImageReport(appID string, sequence int64) (*imagereporttypes.Report, error) {
+	db := persistence.MustGetPGSession()
+
+	query := `select images, created_at from app_version_image_report where app_id = $1 and sequence = $2`
+	row := db.QueryRow(query, appID, sequence)
+
+	var imagesStr sql.NullString
+	var createdAt sql.NullTime
+	if err := row.Scan(&imagesStr, &createdAt); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, errors.Wrap(err, "failed to scan")
+	}
+
+	report := imagereporttypes.Report{
+		Sequence:  sequence,
+		Images:    []imagereporttypes.Image{},
+		CreatedAt: createdAt.Time,
+	}
+	if imagesStr.Valid && imagesStr.String != "" {
+		if err := json.Unmarshal([]byte(imagesStr.String), &report.Images); err != nil {
+			return nil, errors.Wrap(err, "failed to unmarshal images")
+		}
+	}
+
+	return &report, nil
+}
+
+// SetImageReport stores the image report, replacing an earlier report for the same sequence
+func (s *KOTSStore) SetImageReport(appID string, report imagereporttypes.Report) error {
+	marshalledImages, err := json.Marshal(report.Images)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal images")
+	}
+
+	db := persistence.MustGetPGSession()
+
+	query := `insert into app_version_image_report (app_id, sequence, images, created_at) values ($1, $2, $3, $4)
+	on conflict (app_id, sequence) do update set images = EXCLUDED.images, created_at = EXCLUDED.created_at`
+	_, err = db.Exec(query, appID, report.Sequence, string(marshalledImages), report.CreatedAt)
+	if err != nil {
+		return errors.Wrap(err, "failed to exec")
+	}
+
+	return nil
+}
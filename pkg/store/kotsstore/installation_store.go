@@ -10,7 +10,7 @@ import (
 
 func (s *KOTSStore) GetPendingInstallationStatus() (*installationtypes.InstallStatus, error) {
 	db := persistence.MustGetPGSession()
-	query := `SELECT install_state from app ORDER BY created_at DESC LIMIT 1`
+	query := `SELECT install_state from app WHERE install_state != 'removed' ORDER BY created_at DESC LIMIT 1`
 	row := db.QueryRow(query)
 
 	var installState sql.NullString
@@ -8,17 +8,14 @@ import (
 	"strings"
 	"time"
 
-	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/awserr"
-	awssession "github.com/aws/aws-sdk-go/aws/session"
-	"github.com/aws/aws-sdk-go/service/s3"
 	"github.com/pkg/errors"
 	kotsscheme "github.com/replicatedhq/kots/kotskinds/client/kotsclientset/scheme"
 	"github.com/replicatedhq/kots/pkg/k8sutil"
 	kotsadmtypes "github.com/replicatedhq/kots/pkg/kotsadm/types"
 	"github.com/replicatedhq/kots/pkg/logger"
+	"github.com/replicatedhq/kots/pkg/objectstore"
 	"github.com/replicatedhq/kots/pkg/persistence"
-	kotss3 "github.com/replicatedhq/kots/pkg/s3"
 	troubleshootscheme "github.com/replicatedhq/troubleshoot/pkg/client/troubleshootclientset/scheme"
 	veleroscheme "github.com/vmware-tanzu/velero/pkg/generated/clientset/versioned/scheme"
 	corev1 "k8s.io/api/core/v1"
@@ -41,6 +38,9 @@ type KOTSStore struct {
 	sessionExpiration time.Time
 
 	cachedTaskStatus map[string]*cachedTaskStatus
+
+	// useReadReplica is set on the store that serves the read path, see ReadStoreFromEnv
+	useReadReplica bool
 }
 
 func init() {
@@ -50,36 +50,21 @@ func init() {
 }
 
 func (s *KOTSStore) Init() error {
-	if strings.HasPrefix(os.Getenv("STORAGE_BASEURI"), "docker://") {
+	if objectstore.IsOCI(os.Getenv("STORAGE_BASEURI")) {
 		return nil
 	}
 
-	if os.Getenv("S3_BUCKET_NAME") == "ship-pacts" {
-		log.Println("Not creating bucket because the desired name is ship-pacts. Consider using a different bucket name to make this work.")
-		return errors.New("bad bucket name")
+	driver, err := objectstore.GetDriver()
+	if err != nil {
+		return errors.Wrap(err, "failed to get object store driver")
 	}
 
-	if os.Getenv("S3_SKIP_ENSURE_BUCKET") == "1" {
-		log.Println("Not creating bucket because S3_SKIP_ENSURE_BUCKET was set.")
-		return nil
+	if err := driver.EnsureBucket(context.TODO()); err != nil {
+		return errors.Wrap(err, "failed to ensure bucket")
 	}
 
-	newSession := awssession.New(kotss3.GetConfig())
-	s3Client := s3.New(newSession)
-
-	_, err := s3Client.HeadBucket(&s3.HeadBucketInput{
-		Bucket: aws.String(os.Getenv("S3_BUCKET_NAME")),
-	})
-
-	if err == nil {
-		return nil
-	}
-
-	_, err = s3Client.CreateBucket(&s3.CreateBucketInput{
-		Bucket: aws.String(os.Getenv("S3_BUCKET_NAME")),
-	})
-	if err != nil {
-		return errors.Wrap(err, "failed to create bucket")
+	if err := objectstore.MigrateFromEnv(context.TODO()); err != nil {
+		return errors.Wrap(err, "failed to migrate object store")
 	}
 
 	return nil
@@ -141,38 +126,24 @@ func waitForPostgres(ctx context.Context) error {
 }
 
 func waitForS3(ctx context.Context) error {
-	if strings.HasPrefix(os.Getenv("STORAGE_BASEURI"), "docker://") {
+	if objectstore.IsOCI(os.Getenv("STORAGE_BASEURI")) {
 		return nil
 	}
 
-	if os.Getenv("S3_BUCKET_NAME") == "ship-pacts" {
-		log.Println("Not creating bucket because the desired name is ship-pacts. Consider using a different bucket name to make this work.")
-		return errors.New("bad bucket name")
-	}
-
-	if os.Getenv("S3_SKIP_ENSURE_BUCKET") == "1" {
-		log.Println("Not creating bucket because S3_SKIP_ENSURE_BUCKET was set.")
-		return nil
+	driver, err := objectstore.GetDriver()
+	if err != nil {
+		return errors.Wrap(err, "failed to get object store driver")
 	}
 
 	logger.Debug("waiting for object store to be ready")
 
-	newSession := awssession.New(kotss3.GetConfig())
-	s3Client := s3.New(newSession)
-
 	period := 1 * time.Second // TOOD: backoff
 	for {
-		_, err := s3Client.HeadBucket(&s3.HeadBucketInput{
-			Bucket: aws.String(os.Getenv("S3_BUCKET_NAME")),
-		})
+		err := driver.Ping(ctx)
 		if err == nil {
 			logger.Debug("object store is ready")
 			return nil
 		}
-		if awsErr, ok := err.(awserr.Error); ok && awsErr.Code() == "NotFound" {
-			logger.Debug("object store is ready")
-			return nil
-		}
 
 		select {
 		case <-time.After(period):
@@ -193,7 +164,7 @@ func (s *KOTSStore) IsNotFound(err error) bool {
 		return true
 	}
 
-	if cause == ErrNotFound {
+	if cause == ErrNotFound || cause == objectstore.ErrNotFound {
 		return true
 	}
 
@@ -239,6 +210,22 @@ func StoreFromEnv() *KOTSStore {
 	}
 }
 
+// ReadStoreFromEnv returns a store whose version list queries go to the read replica, if one is configured
+func ReadStoreFromEnv() *KOTSStore {
+	return &KOTSStore{
+		cachedTaskStatus: make(map[string]*cachedTaskStatus),
+		useReadReplica:   true,
+	}
+}
+
+// readSession is the connection for queries that can be served by the read replica
+func (s *KOTSStore) readSession() *sql.DB {
+	if s.useReadReplica {
+		return persistence.MustGetPGReadSession()
+	}
+	return persistence.MustGetPGSession()
+}
+
 func (s *KOTSStore) getConfigmap(name string) (*corev1.ConfigMap, error) {
 	clientset, err := k8sutil.GetClientset()
 	if err != nil {
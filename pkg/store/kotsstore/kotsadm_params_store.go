@@ -2,6 +2,7 @@ package kotsstore
 
 import (
 	"database/sql"
+	"strconv"
 
 	"github.com/pkg/errors"
 	"github.com/replicatedhq/kots/pkg/persistence"
@@ -34,3 +35,45 @@ func (s *KOTSStore) SetIsKotsadmIDGenerated() error {
 	}
 	return nil
 }
+
+const readOnlyModeParam = "READ_ONLY_MODE"
+
+// IsReadOnlyMode returns true if all changes through the admin console are disabled
+func (s *KOTSStore) IsReadOnlyMode() (bool, error) {
+	db := persistence.MustGetPGSession()
+	query := `select value from kotsadm_params where key = $1`
+	row := db.QueryRow(query, readOnlyModeParam)
+
+	var value string
+	if err := row.Scan(&value); err != nil {
+		if err == sql.ErrNoRows {
+			return false, nil
+		}
+		return false, errors.Wrap(err, "failed to scan")
+	}
+	return value == "true", nil
+}
+
+func (s *KOTSStore) SetReadOnlyMode(enabled bool) error {
+	db := persistence.MustGetPGSession()
+
+	query := `insert into kotsadm_params (key, value) values ($1, $2) on conflict (key) do update set value = $2`
+	_, err := db.Exec(query, readOnlyModeParam, strconv.FormatBool(enabled))
+	if err != nil {
+		return errors.Wrap(err, "failed to exec")
+	}
+	return nil
+}
+
+// InitReadOnlyMode sets read-only mode only if it has never been set, so that the install flag does not
+// turn it back on after an administrator has turned it off
+func (s *KOTSStore) InitReadOnlyMode(enabled bool) error {
+	db := persistence.MustGetPGSession()
+
+	query := `insert into kotsadm_params (key, value) values ($1, $2) on conflict (key) do nothing`
+	_, err := db.Exec(query, readOnlyModeParam, strconv.FormatBool(enabled))
+	if err != nil {
+		return errors.Wrap(err, "failed to exec")
+	}
+	return nil
+}
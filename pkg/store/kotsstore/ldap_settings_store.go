@@ -0,0 +1,76 @@
+package kotsstore
+
+import (
+	"database/sql"
+	"encoding/base64"
+	"encoding/json"
+	"os"
+
+	"github.com/pkg/errors"
+	"github.com/replicatedhq/kots/pkg/crypto"
+	ldapauthtypes "github.com/replicatedhq/kots/pkg/ldapauth/types"
+	"github.com/replicatedhq/kots/pkg/persistence"
+)
+
+const ldapSettingsParam = "LDAP_SETTINGS"
+
+// GetLDAPSettings returns the ldap settings, or nil if they were never set. They are stored encrypted because they
+// include the bind password.
+func (s *KOTSStore) GetLDAPSettings() (*ldapauthtypes.Settings, error) {
+	db := persistence.MustGetPGSession()
+	query := `select value from kotsadm_params where key = $1`
+	row := db.QueryRow(query, ldapSettingsParam)
+
+	var value string
+	if err := row.Scan(&value); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, errors.Wrap(err, "failed to scan")
+	}
+
+	cipher, err := crypto.AESCipherFromString(os.Getenv("API_ENCRYPTION_KEY"))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create aes cipher")
+	}
+
+	decoded, err := base64.StdEncoding.DecodeString(value)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to decode")
+	}
+
+	decrypted, err := cipher.Decrypt(decoded)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to decrypt")
+	}
+
+	settings := ldapauthtypes.Settings{}
+	if err := json.Unmarshal(decrypted, &settings); err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal")
+	}
+
+	return &settings, nil
+}
+
+func (s *KOTSStore) SetLDAPSettings(settings ldapauthtypes.Settings) error {
+	marshalled, err := json.Marshal(settings)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal")
+	}
+
+	cipher, err := crypto.AESCipherFromString(os.Getenv("API_ENCRYPTION_KEY"))
+	if err != nil {
+		return errors.Wrap(err, "failed to create aes cipher")
+	}
+
+	value := base64.StdEncoding.EncodeToString(cipher.Encrypt(marshalled))
+
+	db := persistence.MustGetPGSession()
+	query := `insert into kotsadm_params (key, value) values ($1, $2) on conflict (key) do update set value = $2`
+	_, err = db.Exec(query, ldapSettingsParam, value)
+	if err != nil {
+		return errors.Wrap(err, "failed to exec")
+	}
+
+	return nil
+}
@@ -3,16 +3,23 @@ package kotsstore
 import (
 	"bytes"
 	"database/sql"
+	"fmt"
 	"io/ioutil"
 	"path/filepath"
+	"strings"
+	"time"
 
 	"github.com/pkg/errors"
 	kotsv1beta1 "github.com/replicatedhq/kots/kotskinds/apis/kots/v1beta1"
 	"github.com/replicatedhq/kots/kotskinds/client/kotsclientset/scheme"
+	"github.com/replicatedhq/kots/pkg/events"
+	eventtypes "github.com/replicatedhq/kots/pkg/events/types"
 	gitopstypes "github.com/replicatedhq/kots/pkg/gitops/types"
+	"github.com/replicatedhq/kots/pkg/kotsutil"
 	"github.com/replicatedhq/kots/pkg/logger"
 	"github.com/replicatedhq/kots/pkg/persistence"
 	rendertypes "github.com/replicatedhq/kots/pkg/render/types"
+	"github.com/replicatedhq/kots/pkg/template"
 	serializer "k8s.io/apimachinery/pkg/runtime/serializer/json"
 )
 
@@ -102,6 +109,15 @@ func (s *KOTSStore) UpdateAppLicense(appID string, sequence int64, archiveDir st
 		return int64(0), errors.Wrap(err, "failed to encode license")
 	}
 	encodedLicense := b.Bytes()
+
+	source := "License Change"
+	previousLicense, err := kotsutil.LoadLicenseFromPath(filepath.Join(archiveDir, "upstream", "userdata", "license.yaml"))
+	if err != nil {
+		logger.Errorf("Failed to load previous license to find changed fields: %v", err)
+	} else if changedFields := template.ChangedLicenseFields(previousLicense, newLicense); len(changedFields) > 0 {
+		source = fmt.Sprintf("License Change (%s)", strings.Join(changedFields, ", "))
+	}
+
 	if err := ioutil.WriteFile(filepath.Join(archiveDir, "upstream", "userdata", "license.yaml"), encodedLicense, 0644); err != nil {
 		return int64(0), errors.Wrap(err, "failed to write new license")
 	}
@@ -113,7 +129,8 @@ func (s *KOTSStore) UpdateAppLicense(appID string, sequence int64, archiveDir st
 		return int64(0), errors.Wrapf(err, "update app %q license", appID)
 	}
 
-	newSeq, err := s.createNewVersionForLicenseChange(tx, appID, sequence, archiveDir, gitops, renderer)
+	newSeq, err := s.createNewVersionForLicenseChange(tx, appID, sequence, archiveDir, source, gitops, renderer)
+	versionCreated := err == nil
 	if err != nil {
 		// ignore error here to prevent a failure to render the current version
 		// preventing the end-user from updating the application
@@ -127,10 +144,34 @@ func (s *KOTSStore) UpdateAppLicense(appID string, sequence int64, archiveDir st
 		return int64(0), errors.Wrap(err, "failed to commit transaction")
 	}
 
+	if versionCreated {
+		events.Publish(eventtypes.EventTypeNewVersion, appID, eventtypes.NewVersionData{
+			Sequence: newSeq,
+			Source:   source,
+		})
+	}
+
 	return newSeq, nil
 }
 
-func (s *KOTSStore) createNewVersionForLicenseChange(tx *sql.Tx, appID string, sequence int64, archiveDir string, gitops gitopstypes.DownstreamGitOps, renderer rendertypes.Renderer) (int64, error) {
+func (s *KOTSStore) ArchiveAppLicense(appID string, replacedByLicenseID string, replacedBy string) error {
+	license, err := s.GetLatestLicenseForApp(appID)
+	if err != nil {
+		return errors.Wrap(err, "failed to get current license")
+	}
+
+	db := persistence.MustGetPGSession()
+	query := `insert into app_license_history (app_id, replaced_at, license_id, license, replaced_by_license_id, replaced_by)
+	select id, $2, $3, license, $4, $5 from app where id = $1`
+	_, err = db.Exec(query, appID, time.Now(), license.Spec.LicenseID, replacedByLicenseID, replacedBy)
+	if err != nil {
+		return errors.Wrap(err, "failed to insert")
+	}
+
+	return nil
+}
+
+func (s *KOTSStore) createNewVersionForLicenseChange(tx *sql.Tx, appID string, sequence int64, archiveDir string, source string, gitops gitopstypes.DownstreamGitOps, renderer rendertypes.Renderer) (int64, error) {
 	registrySettings, err := s.GetRegistryDetailsForApp(appID)
 	if err != nil {
 		return int64(0), errors.Wrap(err, "failed to get registry settings for app")
@@ -150,7 +191,7 @@ func (s *KOTSStore) createNewVersionForLicenseChange(tx *sql.Tx, appID string, s
 		return int64(0), errors.Wrap(err, "failed to render new version")
 	}
 
-	newSequence, err := s.createAppVersion(tx, appID, &sequence, archiveDir, "License Change", false, gitops)
+	newSequence, err := s.createAppVersion(tx, appID, &sequence, archiveDir, source, false, gitops)
 	if err != nil {
 		return int64(0), errors.Wrap(err, "failed to create new version")
 	}
@@ -0,0 +1,103 @@
+package kotsstore
+
+import (
+	"database/sql"
+	"encoding/json"
+
+	"github.com/pkg/errors"
+	maintenancetypes "github.com/replicatedhq/kots/pkg/maintenance/types"
+	"github.com/replicatedhq/kots/pkg/persistence"
+)
+
+const maintenanceMessageParam = "MAINTENANCE_MESSAGE"
+
+// GetGlobalMaintenanceMessage returns the maintenance message for all apps, or nil if there is none
+func (s *KOTSStore) GetGlobalMaintenanceMessage() (*maintenancetypes.MaintenanceMessage, error) {
+	db := persistence.MustGetPGSession()
+	query := `select value from kotsadm_params where key = $1`
+	row := db.QueryRow(query, maintenanceMessageParam)
+
+	var value string
+	if err := row.Scan(&value); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, errors.Wrap(err, "failed to scan")
+	}
+
+	return unmarshalMaintenanceMessage(value)
+}
+
+// SetGlobalMaintenanceMessage sets the maintenance message for all apps. A nil message clears it.
+func (s *KOTSStore) SetGlobalMaintenanceMessage(message *maintenancetypes.MaintenanceMessage) error {
+	db := persistence.MustGetPGSession()
+
+	if message == nil {
+		query := `delete from kotsadm_params where key = $1`
+		_, err := db.Exec(query, maintenanceMessageParam)
+		if err != nil {
+			return errors.Wrap(err, "failed to exec delete")
+		}
+		return nil
+	}
+
+	marshalled, err := json.Marshal(message)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal maintenance message")
+	}
+
+	query := `insert into kotsadm_params (key, value) values ($1, $2) on conflict (key) do update set value = $2`
+	_, err = db.Exec(query, maintenanceMessageParam, string(marshalled))
+	if err != nil {
+		return errors.Wrap(err, "failed to exec")
+	}
+
+	return nil
+}
+
+// GetAppMaintenanceMessage returns the maintenance message of an app, or nil if there is none
+func (s *KOTSStore) GetAppMaintenanceMessage(appID string) (*maintenancetypes.MaintenanceMessage, error) {
+	db := persistence.MustGetPGSession()
+	query := `select maintenance_message from app where id = $1`
+	row := db.QueryRow(query, appID)
+
+	var value sql.NullString
+	if err := row.Scan(&value); err != nil {
+		return nil, errors.Wrap(err, "failed to scan")
+	}
+
+	if !value.Valid || value.String == "" {
+		return nil, nil
+	}
+
+	return unmarshalMaintenanceMessage(value.String)
+}
+
+// SetAppMaintenanceMessage sets the maintenance message of an app. A nil message clears it.
+func (s *KOTSStore) SetAppMaintenanceMessage(appID string, message *maintenancetypes.MaintenanceMessage) error {
+	var value sql.NullString
+	if message != nil {
+		marshalled, err := json.Marshal(message)
+		if err != nil {
+			return errors.Wrap(err, "failed to marshal maintenance message")
+		}
+		value = sql.NullString{String: string(marshalled), Valid: true}
+	}
+
+	db := persistence.MustGetPGSession()
+	query := `update app set maintenance_message = $1 where id = $2`
+	_, err := db.Exec(query, value, appID)
+	if err != nil {
+		return errors.Wrap(err, "failed to exec")
+	}
+
+	return nil
+}
+
+func unmarshalMaintenanceMessage(value string) (*maintenancetypes.MaintenanceMessage, error) {
+	message := maintenancetypes.MaintenanceMessage{}
+	if err := json.Unmarshal([]byte(value), &message); err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal maintenance message")
+	}
+	return &message, nil
+}
@@ -0,0 +1,82 @@
+package kotsstore
+
+import (
+	"time"
+
+	"github.com/pkg/errors"
+	meteringtypes "github.com/replicatedhq/kots/pkg/metering/types"
+	"github.com/replicatedhq/kots/pkg/persistence"
+)
+
+// SetEntitlementUsage records the latest value reported by a single source for an entitlement.
+// When limit is set, the value is only recorded if the total across all sources stays within the limit, or if the
+// value does not grow, and false is returned otherwise. The check and the write are done in one transaction that
+// holds a lock on the entitlement, so that concurrent reports from different sources can't go over the limit together.
+func (s *KOTSStore) SetEntitlementUsage(appID string, name string, source string, value int64, reportedAt time.Time, limit *int64) (bool, error) {
+	db := persistence.MustGetPGSession()
+
+	tx, err := db.Begin()
+	if err != nil {
+		return false, errors.Wrap(err, "failed to begin")
+	}
+	defer tx.Rollback()
+
+	if limit != nil {
+		// rows of sources that have not reported yet can't be locked, so the entitlement is locked instead
+		if _, err := tx.Exec(`select pg_advisory_xact_lock(hashtext($1), hashtext($2))`, appID, name); err != nil {
+			return false, errors.Wrap(err, "failed to lock")
+		}
+
+		query := `select
+	coalesce(sum(value) filter (where source != $3), 0),
+	coalesce(sum(value) filter (where source = $3), 0)
+from app_entitlement_usage where app_id = $1 and entitlement_name = $2`
+		row := tx.QueryRow(query, appID, name, source)
+
+		var otherSources, previous int64
+		if err := row.Scan(&otherSources, &previous); err != nil {
+			return false, errors.Wrap(err, "failed to scan")
+		}
+
+		// usage that does not grow is always accepted so that applications can get back under the limit
+		if otherSources+value > *limit && value > previous {
+			return false, nil
+		}
+	}
+
+	query := `insert into app_entitlement_usage (app_id, entitlement_name, source, value, reported_at) values ($1, $2, $3, $4, $5)
+	on conflict (app_id, entitlement_name, source) do update set value = EXCLUDED.value, reported_at = EXCLUDED.reported_at`
+	_, err = tx.Exec(query, appID, name, source, value, reportedAt)
+	if err != nil {
+		return false, errors.Wrap(err, "failed to exec")
+	}
+
+	if err := tx.Commit(); err != nil {
+		return false, errors.Wrap(err, "failed to commit")
+	}
+
+	return true, nil
+}
+
+// ListEntitlementUsage returns the usage reported by every source for every entitlement of the app
+func (s *KOTSStore) ListEntitlementUsage(appID string) ([]meteringtypes.EntitlementUsage, error) {
+	db := persistence.MustGetPGSession()
+
+	query := `select entitlement_name, source, value, reported_at from app_entitlement_usage where app_id = $1 order by entitlement_name, source`
+	rows, err := db.Query(query, appID)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to query")
+	}
+	defer rows.Close()
+
+	usage := []meteringtypes.EntitlementUsage{}
+	for rows.Next() {
+		u := meteringtypes.EntitlementUsage{}
+		if err := rows.Scan(&u.Name, &u.Source, &u.Value, &u.ReportedAt); err != nil {
+			return nil, errors.Wrap(err, "failed to scan")
+		}
+		usage = append(usage, u)
+	}
+
+	return usage, nil
+}
@@ -41,7 +41,7 @@ func (s *KOTSStore) GetPreflightProgress(appID string, sequence int64) (string,
 func (s *KOTSStore) SetPreflightResults(appID string, sequence int64, results []byte) error {
 	db := persistence.MustGetPGSession()
 	query := `update app_downstream_version set preflight_result = $1, preflight_result_created_at = $2,
-status = (case when status = 'deployed' then 'deployed' else 'pending' end),
+status = (case when status in ('deployed', 'deploying') then status else 'pending' end),
 preflight_progress = NULL
 where app_id = $3 and parent_sequence = $4`
 
@@ -2,9 +2,14 @@ package kotsstore
 
 import (
 	"database/sql"
+	"encoding/base64"
+	"encoding/json"
+	"os"
 
 	"github.com/pkg/errors"
+	"github.com/replicatedhq/kots/pkg/crypto"
 	"github.com/replicatedhq/kots/pkg/persistence"
+	prometheustypes "github.com/replicatedhq/kots/pkg/prometheus/types"
 )
 
 func (s *KOTSStore) GetPrometheusAddress() (string, error) {
@@ -34,3 +39,73 @@ func (s *KOTSStore) SetPrometheusAddress(address string) error {
 
 	return nil
 }
+
+// GetPrometheusAuth returns the credentials used to query prometheus. They are stored encrypted.
+func (s *KOTSStore) GetPrometheusAuth() (prometheustypes.Auth, error) {
+	db := persistence.MustGetPGSession()
+	query := `select value from kotsadm_params where key = $1`
+	row := db.QueryRow(query, "PROMETHEUS_AUTH")
+
+	var value string
+	if err := row.Scan(&value); err != nil {
+		if err == sql.ErrNoRows {
+			return prometheustypes.Auth{}, nil
+		}
+		return prometheustypes.Auth{}, errors.Wrap(err, "failed to scan")
+	}
+
+	cipher, err := crypto.AESCipherFromString(os.Getenv("API_ENCRYPTION_KEY"))
+	if err != nil {
+		return prometheustypes.Auth{}, errors.Wrap(err, "failed to create aes cipher")
+	}
+
+	decoded, err := base64.StdEncoding.DecodeString(value)
+	if err != nil {
+		return prometheustypes.Auth{}, errors.Wrap(err, "failed to decode")
+	}
+
+	decrypted, err := cipher.Decrypt(decoded)
+	if err != nil {
+		return prometheustypes.Auth{}, errors.Wrap(err, "failed to decrypt")
+	}
+
+	auth := prometheustypes.Auth{}
+	if err := json.Unmarshal(decrypted, &auth); err != nil {
+		return prometheustypes.Auth{}, errors.Wrap(err, "failed to unmarshal")
+	}
+
+	return auth, nil
+}
+
+func (s *KOTSStore) SetPrometheusAuth(auth prometheustypes.Auth) error {
+	db := persistence.MustGetPGSession()
+
+	if auth.IsEmpty() {
+		query := `delete from kotsadm_params where key = $1`
+		_, err := db.Exec(query, "PROMETHEUS_AUTH")
+		if err != nil {
+			return errors.Wrap(err, "failed to exec delete")
+		}
+		return nil
+	}
+
+	marshalled, err := json.Marshal(auth)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal")
+	}
+
+	cipher, err := crypto.AESCipherFromString(os.Getenv("API_ENCRYPTION_KEY"))
+	if err != nil {
+		return errors.Wrap(err, "failed to create aes cipher")
+	}
+
+	value := base64.StdEncoding.EncodeToString(cipher.Encrypt(marshalled))
+
+	query := `insert into kotsadm_params (key, value) values ($1, $2) on conflict (key) do update set value = $2`
+	_, err = db.Exec(query, "PROMETHEUS_AUTH", value)
+	if err != nil {
+		return errors.Wrap(err, "failed to exec")
+	}
+
+	return nil
+}
@@ -0,0 +1,50 @@
+package kotsstore
+
+import (
+	"database/sql"
+	"encoding/json"
+
+	"github.com/pkg/errors"
+	"github.com/replicatedhq/kots/pkg/persistence"
+	releasefeedbacktypes "github.com/replicatedhq/kots/pkg/releasefeedback/types"
+)
+
+// GetReleaseFeedbackConsent returns the consent of the customer to report the rollout of releases of the app to the
+// vendor, or nil if it was never given
+func (s *KOTSStore) GetReleaseFeedbackConsent(appID string) (*releasefeedbacktypes.Consent, error) {
+	db := persistence.MustGetPGSession()
+	query := `select release_feedback_consent from app where id = $1`
+	row := db.QueryRow(query, appID)
+
+	var value sql.NullString
+	if err := row.Scan(&value); err != nil {
+		return nil, errors.Wrap(err, "failed to scan")
+	}
+
+	if !value.Valid || value.String == "" {
+		return nil, nil
+	}
+
+	consent := releasefeedbacktypes.Consent{}
+	if err := json.Unmarshal([]byte(value.String), &consent); err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal consent")
+	}
+
+	return &consent, nil
+}
+
+func (s *KOTSStore) SetReleaseFeedbackConsent(appID string, consent releasefeedbacktypes.Consent) error {
+	marshalled, err := json.Marshal(consent)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal consent")
+	}
+
+	db := persistence.MustGetPGSession()
+	query := `update app set release_feedback_consent = $1 where id = $2`
+	_, err = db.Exec(query, string(marshalled), appID)
+	if err != nil {
+		return errors.Wrap(err, "failed to exec")
+	}
+
+	return nil
+}
@@ -0,0 +1,179 @@
+package kotsstore
+
+import (
+	"database/sql"
+	"encoding/base64"
+	"encoding/json"
+	"os"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/replicatedhq/kots/pkg/crypto"
+	fleetreporttypes "github.com/replicatedhq/kots/pkg/fleetreport/types"
+	"github.com/replicatedhq/kots/pkg/logger"
+	"github.com/replicatedhq/kots/pkg/persistence"
+	remoteinstalltypes "github.com/replicatedhq/kots/pkg/remoteinstall/types"
+	"go.uber.org/zap"
+)
+
+func (s *KOTSStore) ListRemoteInstalls() ([]remoteinstalltypes.RemoteInstall, error) {
+	db := persistence.MustGetPGSession()
+	query := `SELECT id, name, url, token_enc, insecure_skip_tls_verify, created_at, last_report, last_report_at, last_error FROM remote_install ORDER BY name`
+	rows, err := db.Query(query)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to query")
+	}
+	defer rows.Close()
+
+	remoteInstalls := []remoteinstalltypes.RemoteInstall{}
+	for rows.Next() {
+		remoteInstall, err := scanRemoteInstall(rows)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to scan remote install")
+		}
+		remoteInstalls = append(remoteInstalls, *remoteInstall)
+	}
+
+	return remoteInstalls, nil
+}
+
+func (s *KOTSStore) GetRemoteInstall(id string) (*remoteinstalltypes.RemoteInstall, error) {
+	db := persistence.MustGetPGSession()
+	query := `SELECT id, name, url, token_enc, insecure_skip_tls_verify, created_at, last_report, last_report_at, last_error FROM remote_install WHERE id = $1`
+	row := db.QueryRow(query, id)
+
+	remoteInstall, err := scanRemoteInstall(row)
+	if err != nil {
+		if errors.Cause(err) == sql.ErrNoRows {
+			return nil, ErrNotFound
+		}
+		return nil, errors.Wrap(err, "failed to scan remote install")
+	}
+
+	return remoteInstall, nil
+}
+
+type remoteInstallScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanRemoteInstall(row remoteInstallScanner) (*remoteinstalltypes.RemoteInstall, error) {
+	var tokenEnc string
+	var lastReport sql.NullString
+	var lastReportAt sql.NullTime
+	var lastError sql.NullString
+
+	remoteInstall := remoteinstalltypes.RemoteInstall{}
+	if err := row.Scan(&remoteInstall.ID, &remoteInstall.Name, &remoteInstall.URL, &tokenEnc, &remoteInstall.InsecureSkipTLSVerify, &remoteInstall.CreatedAt, &lastReport, &lastReportAt, &lastError); err != nil {
+		return nil, errors.Wrap(err, "failed to scan")
+	}
+
+	token, err := decryptRemoteInstallToken(tokenEnc)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to decrypt token")
+	}
+	remoteInstall.Token = token
+
+	if lastReport.Valid {
+		report := fleetreporttypes.Report{}
+		if err := json.Unmarshal([]byte(lastReport.String), &report); err != nil {
+			return nil, errors.Wrap(err, "failed to unmarshal last report")
+		}
+		remoteInstall.LastReport = &report
+	}
+	if lastReportAt.Valid {
+		remoteInstall.LastReportAt = &lastReportAt.Time
+	}
+	remoteInstall.LastError = lastError.String
+
+	return &remoteInstall, nil
+}
+
+func (s *KOTSStore) CreateRemoteInstall(remoteInstall remoteinstalltypes.RemoteInstall) error {
+	logger.Debug("Creating remote install",
+		zap.String("name", remoteInstall.Name),
+		zap.String("url", remoteInstall.URL))
+
+	tokenEnc, err := encryptRemoteInstallToken(remoteInstall.Token)
+	if err != nil {
+		return errors.Wrap(err, "failed to encrypt token")
+	}
+
+	db := persistence.MustGetPGSession()
+	query := `INSERT INTO remote_install (id, name, url, token_enc, insecure_skip_tls_verify, created_at) VALUES ($1, $2, $3, $4, $5, $6)`
+	_, err = db.Exec(query, remoteInstall.ID, remoteInstall.Name, remoteInstall.URL, tokenEnc, remoteInstall.InsecureSkipTLSVerify, remoteInstall.CreatedAt)
+	if err != nil {
+		return errors.Wrap(err, "failed to exec")
+	}
+
+	return nil
+}
+
+func (s *KOTSStore) DeleteRemoteInstall(id string) error {
+	logger.Debug("Deleting remote install",
+		zap.String("id", id))
+
+	db := persistence.MustGetPGSession()
+	query := `DELETE FROM remote_install WHERE id = $1`
+	_, err := db.Exec(query, id)
+	if err != nil {
+		return errors.Wrap(err, "failed to exec")
+	}
+
+	return nil
+}
+
+func (s *KOTSStore) SetRemoteInstallReport(id string, report *fleetreporttypes.Report, fetchedAt time.Time) error {
+	b, err := json.Marshal(report)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal report")
+	}
+
+	db := persistence.MustGetPGSession()
+	query := `UPDATE remote_install SET last_report = $1, last_report_at = $2, last_error = NULL WHERE id = $3`
+	_, err = db.Exec(query, string(b), fetchedAt, id)
+	if err != nil {
+		return errors.Wrap(err, "failed to exec")
+	}
+
+	return nil
+}
+
+func (s *KOTSStore) SetRemoteInstallError(id string, message string) error {
+	db := persistence.MustGetPGSession()
+	query := `UPDATE remote_install SET last_error = $1 WHERE id = $2`
+	_, err := db.Exec(query, message, id)
+	if err != nil {
+		return errors.Wrap(err, "failed to exec")
+	}
+
+	return nil
+}
+
+func encryptRemoteInstallToken(token string) (string, error) {
+	cipher, err := crypto.AESCipherFromString(os.Getenv("API_ENCRYPTION_KEY"))
+	if err != nil {
+		return "", errors.Wrap(err, "failed to create aes cipher")
+	}
+
+	return base64.StdEncoding.EncodeToString(cipher.Encrypt([]byte(token))), nil
+}
+
+func decryptRemoteInstallToken(tokenEnc string) (string, error) {
+	cipher, err := crypto.AESCipherFromString(os.Getenv("API_ENCRYPTION_KEY"))
+	if err != nil {
+		return "", errors.Wrap(err, "failed to create aes cipher")
+	}
+
+	decoded, err := base64.StdEncoding.DecodeString(tokenEnc)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to decode")
+	}
+
+	decrypted, err := cipher.Decrypt(decoded)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to decrypt")
+	}
+
+	return string(decrypted), nil
+}
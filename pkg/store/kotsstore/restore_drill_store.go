@@ -0,0 +1,94 @@
+package kotsstore
+
+import (
+	"database/sql"
+
+	"github.com/pkg/errors"
+	"github.com/replicatedhq/kots/pkg/logger"
+	"github.com/replicatedhq/kots/pkg/persistence"
+	restoredrilltypes "github.com/replicatedhq/kots/pkg/restoredrill/types"
+	"go.uber.org/zap"
+)
+
+func (s *KOTSStore) ListRestoreDrills(appID string) ([]restoredrilltypes.Drill, error) {
+	db := persistence.MustGetPGSession()
+	query := `SELECT id, app_id, status, scheduled_at, backup_name, clone_app_id, namespace, message, started_at, finished_at FROM restore_drill WHERE app_id = $1 ORDER BY scheduled_at DESC`
+	rows, err := db.Query(query, appID)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to query")
+	}
+	defer rows.Close()
+
+	drills := []restoredrilltypes.Drill{}
+	for rows.Next() {
+		var backupName sql.NullString
+		var cloneAppID sql.NullString
+		var namespace sql.NullString
+		var message sql.NullString
+		var startedAt sql.NullTime
+		var finishedAt sql.NullTime
+
+		drill := restoredrilltypes.Drill{}
+		if err := rows.Scan(&drill.ID, &drill.AppID, &drill.Status, &drill.ScheduledAt, &backupName, &cloneAppID, &namespace, &message, &startedAt, &finishedAt); err != nil {
+			return nil, errors.Wrap(err, "failed to scan")
+		}
+
+		drill.BackupName = backupName.String
+		drill.CloneAppID = cloneAppID.String
+		drill.Namespace = namespace.String
+		drill.Message = message.String
+		if startedAt.Valid {
+			drill.StartedAt = &startedAt.Time
+		}
+		if finishedAt.Valid {
+			drill.FinishedAt = &finishedAt.Time
+		}
+
+		drills = append(drills, drill)
+	}
+
+	return drills, nil
+}
+
+func (s *KOTSStore) CreateRestoreDrill(drill restoredrilltypes.Drill) error {
+	logger.Debug("Creating restore drill",
+		zap.String("appID", drill.AppID))
+
+	db := persistence.MustGetPGSession()
+	query := `INSERT INTO restore_drill (id, app_id, status, scheduled_at) VALUES ($1, $2, $3, $4)`
+	_, err := db.Exec(query, drill.ID, drill.AppID, drill.Status, drill.ScheduledAt)
+	if err != nil {
+		return errors.Wrap(err, "failed to exec")
+	}
+
+	return nil
+}
+
+func (s *KOTSStore) UpdateRestoreDrill(drill restoredrilltypes.Drill) error {
+	logger.Debug("Updating restore drill",
+		zap.String("ID", drill.ID),
+		zap.String("status", string(drill.Status)))
+
+	db := persistence.MustGetPGSession()
+	query := `UPDATE restore_drill SET status = $1, backup_name = $2, clone_app_id = $3, namespace = $4, message = $5, started_at = $6, finished_at = $7 WHERE id = $8`
+	_, err := db.Exec(query, drill.Status, drill.BackupName, drill.CloneAppID, drill.Namespace, drill.Message, drill.StartedAt, drill.FinishedAt, drill.ID)
+	if err != nil {
+		return errors.Wrap(err, "failed to exec")
+	}
+
+	return nil
+}
+
+func (s *KOTSStore) DeleteScheduledRestoreDrills(appID string) error {
+	logger.Debug("Deleting scheduled restore drills",
+		zap.String("appID", appID))
+
+	db := persistence.MustGetPGSession()
+	query := `DELETE FROM restore_drill WHERE app_id = $1 AND status = $2`
+	_, err := db.Exec(query, appID, restoredrilltypes.StatusScheduled)
+	if err != nil {
+		return errors.Wrap(err, "failed to exec")
+	}
+
+	return nil
+}
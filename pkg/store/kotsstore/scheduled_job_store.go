@@ -0,0 +1,80 @@
+package kotsstore
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/replicatedhq/kots/pkg/persistence"
+	scheduledjobtypes "github.com/replicatedhq/kots/pkg/scheduledjob/types"
+)
+
+func (s *KOTSStore) CreateScheduledJobRun(run scheduledjobtypes.Run) error {
+	db := persistence.MustGetPGSession()
+	query := `INSERT INTO scheduled_job_run (id, job, app_id, status, started_at) VALUES ($1, $2, $3, $4, $5)`
+	_, err := db.Exec(query, run.ID, run.Job, sql.NullString{String: run.AppID, Valid: run.AppID != ""}, run.Status, run.StartedAt)
+	if err != nil {
+		return errors.Wrap(err, "failed to insert")
+	}
+
+	return nil
+}
+
+func (s *KOTSStore) FinishScheduledJobRun(id string, status scheduledjobtypes.Status, message string, finishedAt time.Time) error {
+	db := persistence.MustGetPGSession()
+	query := `UPDATE scheduled_job_run SET status = $1, message = $2, finished_at = $3 WHERE id = $4`
+	_, err := db.Exec(query, status, message, finishedAt, id)
+	if err != nil {
+		return errors.Wrap(err, "failed to update")
+	}
+
+	return nil
+}
+
+func (s *KOTSStore) ListLatestScheduledJobRuns() ([]scheduledjobtypes.Run, error) {
+	db := persistence.MustGetPGSession()
+	query := `SELECT DISTINCT ON (job, app_id) id, job, app_id, status, message, started_at, finished_at FROM scheduled_job_run ORDER BY job, app_id, started_at DESC`
+	rows, err := db.Query(query)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to query")
+	}
+	defer rows.Close()
+
+	runs := []scheduledjobtypes.Run{}
+	for rows.Next() {
+		var appID sql.NullString
+		var message sql.NullString
+		var finishedAt sql.NullTime
+
+		run := scheduledjobtypes.Run{}
+		if err := rows.Scan(&run.ID, &run.Job, &appID, &run.Status, &message, &run.StartedAt, &finishedAt); err != nil {
+			return nil, errors.Wrap(err, "failed to scan")
+		}
+
+		run.AppID = appID.String
+		run.Message = message.String
+		if finishedAt.Valid {
+			run.FinishedAt = &finishedAt.Time
+		}
+
+		runs = append(runs, run)
+	}
+
+	return runs, nil
+}
+
+func (s *KOTSStore) DeleteScheduledJobRunsBefore(before time.Time) (int64, error) {
+	db := persistence.MustGetPGSession()
+	query := `DELETE FROM scheduled_job_run WHERE started_at < $1 AND status != $2`
+	result, err := db.Exec(query, before, scheduledjobtypes.StatusRunning)
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to delete")
+	}
+
+	deleted, err := result.RowsAffected()
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to get rows affected")
+	}
+
+	return deleted, nil
+}
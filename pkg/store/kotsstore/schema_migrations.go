@@ -0,0 +1,140 @@
+package kotsstore
+
+import (
+	"database/sql"
+	"os"
+
+	"github.com/pkg/errors"
+	"github.com/replicatedhq/kots/pkg/logger"
+	"github.com/replicatedhq/kots/pkg/persistence"
+	"github.com/replicatedhq/kots/pkg/store/schema"
+	"go.uber.org/zap"
+)
+
+// schemaMigrationLockID is the postgres advisory lock held while migrations run, so that only one
+// kotsadm replica migrates the database at a time
+const schemaMigrationLockID = 7130152611
+
+// schemaMigrations are applied in order on startup, after schemahero has created the tables.
+// Never change or remove a migration that has been released, add a new one instead.
+var schemaMigrations = []schema.Migration{
+	{
+		Version: 1,
+		Name:    "baseline",
+		Up:      func(tx *sql.Tx) error { return nil },
+		Down:    func(tx *sql.Tx) error { return nil },
+	},
+}
+
+// RunSchemaMigrations applies the schema migrations that have not been applied yet. It returns a
+// schema.TooNewError without changing anything if the database was migrated by a newer kotsadm.
+func (s *KOTSStore) RunSchemaMigrations() error {
+	if err := schema.Validate(schemaMigrations); err != nil {
+		return errors.Wrap(err, "invalid schema migrations")
+	}
+
+	db := persistence.MustGetPGSession()
+	tx, err := db.Begin()
+	if err != nil {
+		return errors.Wrap(err, "failed to begin")
+	}
+	defer tx.Rollback()
+
+	applied, err := lockAndListAppliedSchemaMigrations(tx)
+	if err != nil {
+		return errors.Wrap(err, "failed to list applied schema migrations")
+	}
+
+	if err := schema.CheckVersion(schemaMigrations, applied); err != nil {
+		return err
+	}
+
+	for _, m := range schema.Pending(schemaMigrations, applied) {
+		logger.Info("applying schema migration", zap.Int64("version", m.Version), zap.String("name", m.Name))
+
+		if err := m.Up(tx); err != nil {
+			return errors.Wrapf(err, "failed to apply schema migration %d (%s)", m.Version, m.Name)
+		}
+
+		query := `insert into kotsadm_schema_migration (version, name, applied_at) values ($1, $2, now())`
+		if _, err := tx.Exec(query, m.Version, m.Name); err != nil {
+			return errors.Wrap(err, "failed to exec")
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return errors.Wrap(err, "failed to commit")
+	}
+
+	return nil
+}
+
+// RollbackSchemaMigrations reverts the applied schema migrations newer than the target version.
+// This is only allowed in dev environments.
+func (s *KOTSStore) RollbackSchemaMigrations(targetVersion int64) error {
+	if os.Getenv("KOTSADM_ENV") != "dev" {
+		return errors.New("schema migrations can only be rolled back in dev environments")
+	}
+
+	db := persistence.MustGetPGSession()
+	tx, err := db.Begin()
+	if err != nil {
+		return errors.Wrap(err, "failed to begin")
+	}
+	defer tx.Rollback()
+
+	applied, err := lockAndListAppliedSchemaMigrations(tx)
+	if err != nil {
+		return errors.Wrap(err, "failed to list applied schema migrations")
+	}
+
+	rollback, err := schema.ToRollBack(schemaMigrations, applied, targetVersion)
+	if err != nil {
+		return errors.Wrap(err, "failed to get schema migrations to roll back")
+	}
+
+	for _, m := range rollback {
+		logger.Info("rolling back schema migration", zap.Int64("version", m.Version), zap.String("name", m.Name))
+
+		if err := m.Down(tx); err != nil {
+			return errors.Wrapf(err, "failed to roll back schema migration %d (%s)", m.Version, m.Name)
+		}
+
+		query := `delete from kotsadm_schema_migration where version = $1`
+		if _, err := tx.Exec(query, m.Version); err != nil {
+			return errors.Wrap(err, "failed to exec")
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return errors.Wrap(err, "failed to commit")
+	}
+
+	return nil
+}
+
+func lockAndListAppliedSchemaMigrations(tx *sql.Tx) ([]int64, error) {
+	if _, err := tx.Exec(`select pg_advisory_xact_lock($1)`, schemaMigrationLockID); err != nil {
+		return nil, errors.Wrap(err, "failed to lock")
+	}
+
+	rows, err := tx.Query(`select version from kotsadm_schema_migration`)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to query")
+	}
+	defer rows.Close()
+
+	applied := []int64{}
+	for rows.Next() {
+		var version int64
+		if err := rows.Scan(&version); err != nil {
+			return nil, errors.Wrap(err, "failed to scan")
+		}
+		applied = append(applied, version)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, errors.Wrap(err, "failed to iterate rows")
+	}
+
+	return applied, nil
+}
@@ -0,0 +1,67 @@
+package kotsstore
+
+import (
+	"database/sql"
+	"encoding/json"
+
+	"github.com/pkg/errors"
+	"github.com/replicatedhq/kots/pkg/persistence"
+	sessiontypes "github.com/replicatedhq/kots/pkg/session/types"
+)
+
+const sessionSettingsParam = "SESSION_SETTINGS"
+
+// GetSessionSettings returns the session settings set through the api or the install flags, or nil if they were never set
+func (s *KOTSStore) GetSessionSettings() (*sessiontypes.SessionSettings, error) {
+	db := persistence.MustGetPGSession()
+	query := `select value from kotsadm_params where key = $1`
+	row := db.QueryRow(query, sessionSettingsParam)
+
+	var value string
+	if err := row.Scan(&value); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, errors.Wrap(err, "failed to scan")
+	}
+
+	settings := sessiontypes.SessionSettings{}
+	if err := json.Unmarshal([]byte(value), &settings); err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal session settings")
+	}
+	return &settings, nil
+}
+
+func (s *KOTSStore) SetSessionSettings(settings sessiontypes.SessionSettings) error {
+	marshalled, err := json.Marshal(settings)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal session settings")
+	}
+
+	db := persistence.MustGetPGSession()
+	query := `insert into kotsadm_params (key, value) values ($1, $2) on conflict (key) do update set value = $2`
+	_, err = db.Exec(query, sessionSettingsParam, string(marshalled))
+	if err != nil {
+		return errors.Wrap(err, "failed to exec")
+	}
+
+	return nil
+}
+
+// InitSessionSettings sets the session settings only if they have never been set, so that the install flags do not
+// override settings changed through the api
+func (s *KOTSStore) InitSessionSettings(settings sessiontypes.SessionSettings) error {
+	marshalled, err := json.Marshal(settings)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal session settings")
+	}
+
+	db := persistence.MustGetPGSession()
+	query := `insert into kotsadm_params (key, value) values ($1, $2) on conflict (key) do nothing`
+	_, err = db.Exec(query, sessionSettingsParam, string(marshalled))
+	if err != nil {
+		return errors.Wrap(err, "failed to exec")
+	}
+
+	return nil
+}
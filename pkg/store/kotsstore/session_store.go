@@ -127,6 +127,7 @@ func (s *KOTSStore) CreateSession(forUser *usertypes.User, issuedAt time.Time, e
 
 	session := sessiontypes.Session{
 		ID:        id,
+		UserID:    forUser.ID,
 		IssuedAt:  issuedAt,
 		ExpiresAt: expiresAt,
 		Roles:     roles,
@@ -198,6 +199,111 @@ func (s *KOTSStore) DeleteSession(id string) error {
 	return nil
 }
 
+// DeleteSessions deletes the sessions with the ids, ids of sessions that do not exist are ignored
+func (s *KOTSStore) DeleteSessions(ids []string) error {
+	sessionLock.Lock()
+	defer sessionLock.Unlock()
+
+	s.sessionSecret = nil
+
+	secret, err := s.getSessionSecret()
+	if err != nil {
+		return errors.Wrap(err, "failed to get session secret")
+	}
+
+	for _, id := range ids {
+		delete(secret.Data, id)
+	}
+
+	if err := s.saveSessionSecret(secret); err != nil {
+		return errors.Wrap(err, "failed to update session secret")
+	}
+
+	return nil
+}
+
+func (s *KOTSStore) ListSessions() ([]sessiontypes.Session, error) {
+	sessionLock.Lock()
+	defer sessionLock.Unlock()
+
+	secret, err := s.getSessionSecret()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get session secret")
+	}
+
+	sessions := []sessiontypes.Session{}
+	for _, data := range secret.Data {
+		session := sessiontypes.Session{}
+		if err := json.Unmarshal(data, &session); err != nil {
+			return nil, errors.Wrap(err, "failed to unmarshal session")
+		}
+		sessions = append(sessions, session)
+	}
+
+	return sessions, nil
+}
+
+func (s *KOTSStore) ImportSessions(sessions []sessiontypes.Session) error {
+	sessionLock.Lock()
+	defer sessionLock.Unlock()
+
+	secret, err := s.getSessionSecret()
+	if err != nil {
+		return errors.Wrap(err, "failed to get session secret")
+	}
+
+	if secret.Data == nil {
+		secret.Data = map[string][]byte{}
+	}
+
+	for _, session := range sessions {
+		b, err := json.Marshal(session)
+		if err != nil {
+			return errors.Wrap(err, "failed to encoded session")
+		}
+		secret.Data[session.ID] = b
+	}
+
+	if err := s.saveSessionSecret(secret); err != nil {
+		return errors.Wrap(err, "failed to update session secret")
+	}
+
+	return nil
+}
+
+func (s *KOTSStore) UpdateSessionActivity(id string, lastActiveAt time.Time) error {
+	sessionLock.Lock()
+	defer sessionLock.Unlock()
+
+	secret, err := s.getSessionSecret()
+	if err != nil {
+		return errors.Wrap(err, "failed to get session secret")
+	}
+
+	data, ok := secret.Data[id]
+	if !ok {
+		return nil
+	}
+
+	session := sessiontypes.Session{}
+	if err := json.Unmarshal(data, &session); err != nil {
+		return errors.Wrap(err, "failed to unmarshal session")
+	}
+	session.LastActiveAt = lastActiveAt
+
+	b, err := json.Marshal(session)
+	if err != nil {
+		return errors.Wrap(err, "failed to encoded session")
+	}
+	secret.Data[id] = b
+
+	if err := s.saveSessionSecret(secret); err != nil {
+		return errors.Wrap(err, "failed to update session secret")
+	}
+
+	return nil
+}
+
 func (s *KOTSStore) getSessionSecret() (*corev1.Secret, error) {
 	if s.sessionSecret != nil && time.Now().Before(s.sessionExpiration) {
 		return s.sessionSecret, nil
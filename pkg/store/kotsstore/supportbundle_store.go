@@ -15,16 +15,12 @@ import (
 	"strconv"
 	"time"
 
-	"github.com/aws/aws-sdk-go/aws"
-	awssession "github.com/aws/aws-sdk-go/aws/session"
-	"github.com/aws/aws-sdk-go/service/s3"
-	"github.com/aws/aws-sdk-go/service/s3/s3manager"
 	"github.com/pkg/errors"
 	"github.com/replicatedhq/kots/pkg/k8sutil"
 	kotsadmtypes "github.com/replicatedhq/kots/pkg/kotsadm/types"
 	"github.com/replicatedhq/kots/pkg/logger"
+	"github.com/replicatedhq/kots/pkg/objectstore"
 	"github.com/replicatedhq/kots/pkg/persistence"
-	kotss3 "github.com/replicatedhq/kots/pkg/s3"
 	"github.com/replicatedhq/kots/pkg/supportbundle/types"
 	troubleshootredact "github.com/replicatedhq/troubleshoot/pkg/redact"
 	"go.uber.org/zap"
@@ -295,26 +291,15 @@ func (s *KOTSStore) CreateSupportBundle(id string, appID string, archivePath str
 		return nil, errors.Wrap(err, "faile to save treeindex")
 	}
 
-	// upload the bundle to s3
-	bucket := aws.String(os.Getenv("S3_BUCKET_NAME"))
-	key := aws.String(filepath.Join("supportbundles", id, "supportbundle.tar.gz"))
-
-	newSession := awssession.New(kotss3.GetConfig())
-
-	s3Client := s3.New(newSession)
-
-	f, err := os.Open(archivePath)
+	// upload the bundle to the object store
+	driver, err := objectstore.GetDriver()
 	if err != nil {
-		return nil, errors.Wrap(err, "failed to open archive file")
+		return nil, errors.Wrap(err, "failed to get object store driver")
 	}
 
-	_, err = s3Client.PutObject(&s3.PutObjectInput{
-		Body:   f,
-		Bucket: bucket,
-		Key:    key,
-	})
-	if err != nil {
-		return nil, errors.Wrap(err, "failed to upload to s3")
+	key := filepath.Join("supportbundles", id, "supportbundle.tar.gz")
+	if err := objectstore.Upload(context.TODO(), driver, key, archivePath); err != nil {
+		return nil, errors.Wrap(err, "failed to upload support bundle")
 	}
 
 	supportBundle := types.SupportBundle{
@@ -402,26 +387,15 @@ func (s *KOTSStore) UploadSupportBundle(id string, archivePath string, marshalle
 		return errors.Wrap(err, "faile to save treeindex")
 	}
 
-	// upload the bundle to s3
-	bucket := aws.String(os.Getenv("S3_BUCKET_NAME"))
-	key := aws.String(filepath.Join("supportbundles", id, "supportbundle.tar.gz"))
-
-	newSession := awssession.New(kotss3.GetConfig())
-
-	s3Client := s3.New(newSession)
-
-	f, err := os.Open(archivePath)
+	// upload the bundle to the object store
+	driver, err := objectstore.GetDriver()
 	if err != nil {
-		return errors.Wrap(err, "failed to open archive file")
+		return errors.Wrap(err, "failed to get object store driver")
 	}
 
-	_, err = s3Client.PutObject(&s3.PutObjectInput{
-		Body:   f,
-		Bucket: bucket,
-		Key:    key,
-	})
-	if err != nil {
-		return errors.Wrap(err, "failed to upload to s3")
+	key := filepath.Join("supportbundles", id, "supportbundle.tar.gz")
+	if err := objectstore.Upload(context.TODO(), driver, key, archivePath); err != nil {
+		return errors.Wrap(err, "failed to upload support bundle")
 	}
 
 	return nil
@@ -438,10 +412,10 @@ func (s *KOTSStore) GetSupportBundleArchive(bundleID string) (string, error) {
 		return "", errors.Wrap(err, "failed to create temp dir")
 	}
 
-	newSession := awssession.New(kotss3.GetConfig())
-
-	bucket := aws.String(os.Getenv("S3_BUCKET_NAME"))
-	key := aws.String(fmt.Sprintf("supportbundles/%s/supportbundle.tar.gz", bundleID))
+	driver, err := objectstore.GetDriver()
+	if err != nil {
+		return "", errors.Wrap(err, "failed to get object store driver")
+	}
 
 	outputFile, err := os.Create(filepath.Join(tmpDir, "supportbundle.tar.gz"))
 	if err != nil {
@@ -449,14 +423,9 @@ func (s *KOTSStore) GetSupportBundleArchive(bundleID string) (string, error) {
 	}
 	defer outputFile.Close()
 
-	downloader := s3manager.NewDownloader(newSession)
-	_, err = downloader.Download(outputFile,
-		&s3.GetObjectInput{
-			Bucket: bucket,
-			Key:    key,
-		})
-	if err != nil {
-		return "", errors.Wrapf(err, "failed to download support bundle archive %q from bucket %q", *key, *bucket)
+	key := fmt.Sprintf("supportbundles/%s/supportbundle.tar.gz", bundleID)
+	if err := objectstore.Download(context.TODO(), driver, key, outputFile); err != nil {
+		return "", errors.Wrap(err, "failed to download support bundle archive")
 	}
 
 	return filepath.Join(tmpDir, "supportbundle.tar.gz"), nil
@@ -571,53 +540,31 @@ func (s *KOTSStore) saveSupportBundleMetafile(id string, filename string, data [
 	}
 	gzipWriter.Close()
 
-	bucket := aws.String(os.Getenv("S3_BUCKET_NAME"))
-	key := aws.String(filepath.Join("supportbundles", id, fmt.Sprintf("%s.gz", filename)))
-
-	newSession := awssession.New(kotss3.GetConfig())
-
-	s3Client := s3.New(newSession)
-
-	_, err := s3Client.PutObject(&s3.PutObjectInput{
-		Body:   bytes.NewReader(gzipped.Bytes()),
-		Bucket: bucket,
-		Key:    key,
-	})
+	driver, err := objectstore.GetDriver()
 	if err != nil {
-		return errors.Wrap(err, "failed to upload to s3")
+		return errors.Wrap(err, "failed to get object store driver")
+	}
+
+	key := filepath.Join("supportbundles", id, fmt.Sprintf("%s.gz", filename))
+	if err := driver.PutObject(context.TODO(), key, bytes.NewReader(gzipped.Bytes())); err != nil {
+		return errors.Wrap(err, "failed to upload metafile")
 	}
 
 	return nil
 }
 
 func (s *KOTSStore) getSupportBundleMetafile(id string, filename string) ([]byte, error) {
-	newSession := awssession.New(kotss3.GetConfig())
-
-	bucket := aws.String(os.Getenv("S3_BUCKET_NAME"))
-	key := aws.String(filepath.Join("supportbundles", id, fmt.Sprintf("%s.gz", filename)))
-
-	// Using a temp file here because Download uses WriterAt type, which bytes.Buffer does not implement.
-	gzipFile, err := ioutil.TempFile("", filename)
-	if err != nil {
-		return nil, errors.Wrap(err, "failed to create tmp file")
-	}
-	defer gzipFile.Close()
-	defer os.Remove(gzipFile.Name())
-
-	downloader := s3manager.NewDownloader(newSession)
-	_, err = downloader.Download(gzipFile,
-		&s3.GetObjectInput{
-			Bucket: bucket,
-			Key:    key,
-		})
+	driver, err := objectstore.GetDriver()
 	if err != nil {
-		return nil, errors.Wrap(err, "failed to download from s3")
+		return nil, errors.Wrap(err, "failed to get object store driver")
 	}
 
-	_, err = gzipFile.Seek(0, 0)
+	key := filepath.Join("supportbundles", id, fmt.Sprintf("%s.gz", filename))
+	gzipFile, err := driver.GetObject(context.TODO(), key)
 	if err != nil {
-		return nil, errors.Wrap(err, "failed to seek temp file back to 0")
+		return nil, errors.Wrap(err, "failed to get metafile")
 	}
+	defer gzipFile.Close()
 
 	gzipReader, err := gzip.NewReader(gzipFile)
 	if err != nil {
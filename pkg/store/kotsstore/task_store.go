@@ -7,6 +7,8 @@ import (
 	"time"
 
 	"github.com/pkg/errors"
+	"github.com/replicatedhq/kots/pkg/events"
+	eventtypes "github.com/replicatedhq/kots/pkg/events/types"
 	"github.com/replicatedhq/kots/pkg/persistence"
 )
 
@@ -95,6 +97,12 @@ func (s *KOTSStore) SetTaskStatus(id string, message string, status string) erro
 	cached.taskStatus.UpdatedAt = time.Now()
 	cached.expirationTime = time.Now().Add(taskCacheTTL)
 
+	events.Publish(eventtypes.EventTypeTaskStatus, "", eventtypes.TaskStatusData{
+		ID:      id,
+		Status:  status,
+		Message: message,
+	})
+
 	configmap, err := s.getConfigmap(TaskStatusConfigMapName)
 	if err != nil {
 		if canIgnoreEtcdError(err) {
@@ -181,6 +189,11 @@ func (s *KOTSStore) ClearTaskStatus(id string) error {
 
 	defer delete(s.cachedTaskStatus, id)
 
+	events.Publish(eventtypes.EventTypeTaskStatus, "", eventtypes.TaskStatusData{
+		ID:      id,
+		Cleared: true,
+	})
+
 	configmap, err := s.getConfigmap(TaskStatusConfigMapName)
 	if err != nil {
 		return errors.Wrap(err, "failed to get task status configmap")
@@ -204,6 +217,52 @@ func (s *KOTSStore) ClearTaskStatus(id string) error {
 	return nil
 }
 
+// ClearStaleTaskStatuses clears the running task statuses that were not updated since notUpdatedSince, they were
+// left behind by an operation that crashed, and returns their ids
+func (s *KOTSStore) ClearStaleTaskStatuses(notUpdatedSince time.Time) ([]string, error) {
+	taskStatusLock.Lock()
+	defer taskStatusLock.Unlock()
+
+	configmap, err := s.getConfigmap(TaskStatusConfigMapName)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get task status configmap")
+	}
+
+	cleared := []string{}
+	for id, data := range configmap.Data {
+		ts := taskStatus{}
+		if err := json.Unmarshal([]byte(data), &ts); err != nil {
+			return nil, errors.Wrapf(err, "failed to unmarshal task status %s", id)
+		}
+		if ts.Status != "running" || ts.UpdatedAt.After(notUpdatedSince) {
+			continue
+		}
+		cleared = append(cleared, id)
+	}
+
+	if len(cleared) == 0 {
+		return cleared, nil
+	}
+
+	for _, id := range cleared {
+		delete(configmap.Data, id)
+	}
+
+	if err := s.updateConfigmap(configmap); err != nil {
+		return nil, errors.Wrap(err, "failed to update task status configmap")
+	}
+
+	for _, id := range cleared {
+		delete(s.cachedTaskStatus, id)
+		events.Publish(eventtypes.EventTypeTaskStatus, "", eventtypes.TaskStatusData{
+			ID:      id,
+			Cleared: true,
+		})
+	}
+
+	return cleared, nil
+}
+
 func (s *KOTSStore) GetTaskStatus(id string) (string, string, error) {
 	taskStatusLock.Lock()
 	defer taskStatusLock.Unlock()
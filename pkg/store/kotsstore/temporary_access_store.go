@@ -0,0 +1,153 @@
+package kotsstore
+
+import (
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/replicatedhq/kots/pkg/persistence"
+	temporaryaccesstypes "github.com/replicatedhq/kots/pkg/temporaryaccess/types"
+)
+
+const temporaryAccessColumns = `id, description, app_slug, allow_support_bundles, created_by, created_at, expires_at, revoked_at, revoked_by, last_used_at, session_ids`
+
+// CreateTemporaryAccess stores the grant with the sha256 of its token, the token itself is never stored
+func (s *KOTSStore) CreateTemporaryAccess(grant temporaryaccesstypes.Grant, tokenSHA256 string) error {
+	db := persistence.MustGetPGSession()
+	query := `insert into temporary_access (id, token_sha256, description, app_slug, allow_support_bundles, created_by, created_at, expires_at) values ($1, $2, $3, $4, $5, $6, $7, $8)`
+	_, err := db.Exec(query, grant.ID, tokenSHA256, grant.Description, grant.AppSlug, grant.AllowSupportBundles, grant.CreatedBy, grant.CreatedAt, grant.ExpiresAt)
+	if err != nil {
+		return errors.Wrap(err, "failed to insert")
+	}
+
+	return nil
+}
+
+// ListTemporaryAccess returns all grants, the most recent first
+func (s *KOTSStore) ListTemporaryAccess() ([]temporaryaccesstypes.Grant, error) {
+	db := persistence.MustGetPGSession()
+	query := `select ` + temporaryAccessColumns + ` from temporary_access order by created_at desc`
+	rows, err := db.Query(query)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to query")
+	}
+	defer rows.Close()
+
+	grants := []temporaryaccesstypes.Grant{}
+	for rows.Next() {
+		grant, err := scanTemporaryAccess(rows)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to scan")
+		}
+		grants = append(grants, *grant)
+	}
+
+	return grants, nil
+}
+
+// GetTemporaryAccess returns nil if there is no grant with the id
+func (s *KOTSStore) GetTemporaryAccess(id string) (*temporaryaccesstypes.Grant, error) {
+	db := persistence.MustGetPGSession()
+	query := `select ` + temporaryAccessColumns + ` from temporary_access where id = $1`
+	row := db.QueryRow(query, id)
+
+	grant, err := scanTemporaryAccess(row)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to scan")
+	}
+
+	return grant, nil
+}
+
+// GetTemporaryAccessByToken returns nil if there is no grant with the sha256 of the token
+func (s *KOTSStore) GetTemporaryAccessByToken(tokenSHA256 string) (*temporaryaccesstypes.Grant, error) {
+	db := persistence.MustGetPGSession()
+	query := `select ` + temporaryAccessColumns + ` from temporary_access where token_sha256 = $1`
+	row := db.QueryRow(query, tokenSHA256)
+
+	grant, err := scanTemporaryAccess(row)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to scan")
+	}
+
+	return grant, nil
+}
+
+// AddTemporaryAccessSession records a session that was created with the access link of the grant
+func (s *KOTSStore) AddTemporaryAccessSession(id string, sessionID string, usedAt time.Time) error {
+	grant, err := s.GetTemporaryAccess(id)
+	if err != nil {
+		return errors.Wrap(err, "failed to get grant")
+	}
+	if grant == nil {
+		return errors.Errorf("temporary access %s not found", id)
+	}
+
+	marshalled, err := json.Marshal(append(grant.SessionIDs, sessionID))
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal session ids")
+	}
+
+	db := persistence.MustGetPGSession()
+	query := `update temporary_access set session_ids = $1, last_used_at = $2 where id = $3`
+	_, err = db.Exec(query, string(marshalled), usedAt, id)
+	if err != nil {
+		return errors.Wrap(err, "failed to update")
+	}
+
+	return nil
+}
+
+// RevokeTemporaryAccess ends the grant, it does nothing if the grant was already revoked
+func (s *KOTSStore) RevokeTemporaryAccess(id string, revokedBy string, revokedAt time.Time) error {
+	db := persistence.MustGetPGSession()
+	query := `update temporary_access set revoked_at = $1, revoked_by = $2 where id = $3 and revoked_at is null`
+	_, err := db.Exec(query, revokedAt, revokedBy, id)
+	if err != nil {
+		return errors.Wrap(err, "failed to update")
+	}
+
+	return nil
+}
+
+func scanTemporaryAccess(row rowScanner) (*temporaryaccesstypes.Grant, error) {
+	var description sql.NullString
+	var appSlug sql.NullString
+	var allowSupportBundles sql.NullBool
+	var createdBy sql.NullString
+	var revokedAt sql.NullTime
+	var revokedBy sql.NullString
+	var lastUsedAt sql.NullTime
+	var sessionIDs sql.NullString
+
+	grant := temporaryaccesstypes.Grant{}
+	if err := row.Scan(&grant.ID, &description, &appSlug, &allowSupportBundles, &createdBy, &grant.CreatedAt, &grant.ExpiresAt, &revokedAt, &revokedBy, &lastUsedAt, &sessionIDs); err != nil {
+		return nil, err
+	}
+
+	grant.Description = description.String
+	grant.AppSlug = appSlug.String
+	grant.AllowSupportBundles = allowSupportBundles.Bool
+	grant.CreatedBy = createdBy.String
+	if revokedAt.Valid {
+		grant.RevokedAt = &revokedAt.Time
+	}
+	grant.RevokedBy = revokedBy.String
+	if lastUsedAt.Valid {
+		grant.LastUsedAt = &lastUsedAt.Time
+	}
+	if sessionIDs.Valid && sessionIDs.String != "" {
+		if err := json.Unmarshal([]byte(sessionIDs.String), &grant.SessionIDs); err != nil {
+			return nil, errors.Wrap(err, "failed to unmarshal session ids")
+		}
+	}
+
+	return &grant, nil
+}
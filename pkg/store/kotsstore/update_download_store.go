@@ -0,0 +1,62 @@
+package kotsstore
+
+import (
+	"database/sql"
+
+	"github.com/pkg/errors"
+	"github.com/replicatedhq/kots/pkg/persistence"
+	updatecheckertypes "github.com/replicatedhq/kots/pkg/updatechecker/types"
+)
+
+// SetUpdateDownloadFailure records that an update was not downloaded, replacing an earlier record for the same cursor
+func (s *KOTSStore) SetUpdateDownloadFailure(appID string, failure updatecheckertypes.UpdateDownloadFailure) error {
+	db := persistence.MustGetPGSession()
+
+	query := `insert into app_update_download_failure (app_id, update_cursor, version_label, status, error, failed_at) values ($1, $2, $3, $4, $5, $6)
+	on conflict (app_id, update_cursor) do update set version_label = EXCLUDED.version_label, status = EXCLUDED.status, error = EXCLUDED.error, failed_at = EXCLUDED.failed_at`
+	_, err := db.Exec(query, appID, failure.UpdateCursor, failure.VersionLabel, failure.Status, failure.Error, failure.FailedAt)
+	if err != nil {
+		return errors.Wrap(err, "failed to exec")
+	}
+
+	return nil
+}
+
+// ListUpdateDownloadFailures returns the updates that were not downloaded in the order they were found
+func (s *KOTSStore) ListUpdateDownloadFailures(appID string) ([]updatecheckertypes.UpdateDownloadFailure, error) {
+	db := persistence.MustGetPGSession()
+
+	query := `select update_cursor, version_label, status, error, failed_at from app_update_download_failure where app_id = $1 order by failed_at`
+	rows, err := db.Query(query, appID)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to query")
+	}
+	defer rows.Close()
+
+	failures := []updatecheckertypes.UpdateDownloadFailure{}
+	for rows.Next() {
+		f := updatecheckertypes.UpdateDownloadFailure{}
+		var versionLabel sql.NullString
+		var errorMessage sql.NullString
+		if err := rows.Scan(&f.UpdateCursor, &versionLabel, &f.Status, &errorMessage, &f.FailedAt); err != nil {
+			return nil, errors.Wrap(err, "failed to scan")
+		}
+		f.VersionLabel = versionLabel.String
+		f.Error = errorMessage.String
+		failures = append(failures, f)
+	}
+
+	return failures, nil
+}
+
+func (s *KOTSStore) ClearUpdateDownloadFailures(appID string) error {
+	db := persistence.MustGetPGSession()
+
+	query := `delete from app_update_download_failure where app_id = $1`
+	_, err := db.Exec(query, appID)
+	if err != nil {
+		return errors.Wrap(err, "failed to exec")
+	}
+
+	return nil
+}
@@ -0,0 +1,49 @@
+package kotsstore
+
+import (
+	"database/sql"
+	"encoding/json"
+
+	"github.com/pkg/errors"
+	"github.com/replicatedhq/kots/pkg/persistence"
+	uploadquotatypes "github.com/replicatedhq/kots/pkg/uploadquota/types"
+)
+
+const uploadQuotaParam = "UPLOAD_QUOTA"
+
+// GetUploadQuota returns the upload quota set through the api, or nil if it was never set
+func (s *KOTSStore) GetUploadQuota() (*uploadquotatypes.UploadQuota, error) {
+	db := persistence.MustGetPGSession()
+	query := `select value from kotsadm_params where key = $1`
+	row := db.QueryRow(query, uploadQuotaParam)
+
+	var value string
+	if err := row.Scan(&value); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, errors.Wrap(err, "failed to scan")
+	}
+
+	quota := uploadquotatypes.UploadQuota{}
+	if err := json.Unmarshal([]byte(value), &quota); err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal upload quota")
+	}
+	return &quota, nil
+}
+
+func (s *KOTSStore) SetUploadQuota(quota uploadquotatypes.UploadQuota) error {
+	marshalled, err := json.Marshal(quota)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal upload quota")
+	}
+
+	db := persistence.MustGetPGSession()
+	query := `insert into kotsadm_params (key, value) values ($1, $2) on conflict (key) do update set value = $2`
+	_, err = db.Exec(query, uploadQuotaParam, string(marshalled))
+	if err != nil {
+		return errors.Wrap(err, "failed to exec")
+	}
+
+	return nil
+}
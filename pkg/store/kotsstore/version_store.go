@@ -2,34 +2,38 @@ package kotsstore
 
 import (
 	"context"
+	"crypto/sha256"
 	"database/sql"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
+	"io"
 	"io/ioutil"
 	"os"
 	"path/filepath"
 	"time"
 
-	"github.com/aws/aws-sdk-go/aws"
-	awssession "github.com/aws/aws-sdk-go/aws/session"
-	"github.com/aws/aws-sdk-go/service/s3"
-	"github.com/aws/aws-sdk-go/service/s3/s3manager"
 	"github.com/mholt/archiver"
 	"github.com/pkg/errors"
 	kotsv1beta1 "github.com/replicatedhq/kots/kotskinds/apis/kots/v1beta1"
 	versiontypes "github.com/replicatedhq/kots/pkg/api/version/types"
 	apptypes "github.com/replicatedhq/kots/pkg/app/types"
+	"github.com/replicatedhq/kots/pkg/events"
+	eventtypes "github.com/replicatedhq/kots/pkg/events/types"
 	gitopstypes "github.com/replicatedhq/kots/pkg/gitops/types"
 	"github.com/replicatedhq/kots/pkg/k8sutil"
 	kotsadmobjects "github.com/replicatedhq/kots/pkg/kotsadm/objects"
 	kotsadmconfig "github.com/replicatedhq/kots/pkg/kotsadmconfig"
 	"github.com/replicatedhq/kots/pkg/kotsutil"
 	"github.com/replicatedhq/kots/pkg/kustomize"
+	"github.com/replicatedhq/kots/pkg/logger"
+	"github.com/replicatedhq/kots/pkg/objectstore"
 	"github.com/replicatedhq/kots/pkg/persistence"
 	rendertypes "github.com/replicatedhq/kots/pkg/render/types"
-	kotss3 "github.com/replicatedhq/kots/pkg/s3"
 	"github.com/replicatedhq/kots/pkg/secrets"
+	"github.com/replicatedhq/kots/pkg/tracing"
 	velerov1 "github.com/vmware-tanzu/velero/pkg/apis/velero/v1"
+	"go.opentelemetry.io/otel/attribute"
 	kuberneteserrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes/scheme"
@@ -207,54 +211,42 @@ func (s *KOTSStore) CreateAppVersionArchive(appID string, sequence int64, archiv
 		return errors.Wrap(err, "failed to create archive")
 	}
 
-	storageBaseURI := os.Getenv("STORAGE_BASEURI")
-	if storageBaseURI == "" {
-		storageBaseURI = fmt.Sprintf("s3://%s/%s", os.Getenv("S3_ENDPOINT"), os.Getenv("S3_BUCKET_NAME"))
+	driver, err := objectstore.GetDriver()
+	if err != nil {
+		return errors.Wrap(err, "failed to get object store driver")
 	}
 
-	bucket := aws.String(os.Getenv("S3_BUCKET_NAME"))
-	key := aws.String(fmt.Sprintf("%s/%d.tar.gz", appID, sequence))
-
-	newSession := awssession.New(kotss3.GetConfig())
-
-	s3Client := s3.New(newSession)
-
-	f, err := os.Open(fileToUpload)
+	sum, size, err := fileSHA256(fileToUpload)
 	if err != nil {
-		return errors.Wrap(err, "failed to open archive file")
+		return errors.Wrap(err, "failed to get archive checksum")
 	}
 
-	_, err = s3Client.PutObject(&s3.PutObjectInput{
-		Body:   f,
-		Bucket: bucket,
-		Key:    key,
-	})
-	if err != nil {
-		return errors.Wrap(err, "failed to upload to s3")
+	key := fmt.Sprintf("%s/%d.tar.gz", appID, sequence)
+	if err := objectstore.Upload(context.TODO(), driver, key, fileToUpload); err != nil {
+		return errors.Wrap(err, "failed to upload archive")
+	}
+
+	if err := s.SetAppVersionArchiveChecksum(appID, sequence, sum, size); err != nil {
+		return errors.Wrap(err, "failed to set archive checksum")
 	}
 
 	return nil
 }
 
 // GetAppVersionArchive will fetch the archive and return a string that contains a
-// directory name where it's extracted into
+// directory name where it's extracted into. An error with ErrChecksumMismatch as the cause is returned if the
+// archive does not match the checksum recorded when it was created.
 func (s *KOTSStore) GetAppVersionArchive(appID string, sequence int64, dstPath string) error {
 	// too noisy
 	// logger.Debug("getting app version archive",
 	// 	zap.String("appID", appID),
 	// 	zap.Int64("sequence", sequence))
 
-	storageBaseURI := os.Getenv("STORAGE_BASEURI")
-	if storageBaseURI == "" {
-		storageBaseURI = fmt.Sprintf("s3://%s/%s", os.Getenv("S3_ENDPOINT"), os.Getenv("S3_BUCKET_NAME"))
+	driver, err := objectstore.GetDriver()
+	if err != nil {
+		return errors.Wrap(err, "failed to get object store driver")
 	}
 
-	// Get the archive from object store
-	newSession := awssession.New(kotss3.GetConfig())
-
-	bucket := aws.String(os.Getenv("S3_BUCKET_NAME"))
-	key := aws.String(fmt.Sprintf("%s/%d.tar.gz", appID, sequence))
-
 	tmpFile, err := ioutil.TempFile("", "kotsadm")
 	if err != nil {
 		return errors.Wrap(err, "failed to create temp file")
@@ -262,14 +254,14 @@ func (s *KOTSStore) GetAppVersionArchive(appID string, sequence int64, dstPath s
 	defer tmpFile.Close()
 	defer os.RemoveAll(tmpFile.Name())
 
-	downloader := s3manager.NewDownloader(newSession)
-	_, err = downloader.Download(tmpFile,
-		&s3.GetObjectInput{
-			Bucket: bucket,
-			Key:    key,
-		})
-	if err != nil {
-		return errors.Wrapf(err, "failed to download app version archive %q from bucket %q", *key, *bucket)
+	// Get the archive from object store
+	h := sha256.New()
+	if err := objectstore.Download(context.TODO(), driver, fmt.Sprintf("%s/%d.tar.gz", appID, sequence), io.MultiWriter(tmpFile, h)); err != nil {
+		return errors.Wrap(err, "failed to download app version archive")
+	}
+
+	if err := s.verifyAppVersionArchiveChecksum(appID, sequence, hex.EncodeToString(h.Sum(nil))); err != nil {
+		return err
 	}
 
 	tarGz := archiver.TarGz{
@@ -284,7 +276,36 @@ func (s *KOTSStore) GetAppVersionArchive(appID string, sequence int64, dstPath s
 	return nil
 }
 
+// GetAppVersionArchiveReader returns the gzipped tar archive of the app version without extracting it.
+// The checksum of the archive is verified when it has been read to the end. The caller must close the reader.
+func (s *KOTSStore) GetAppVersionArchiveReader(appID string, sequence int64) (io.ReadCloser, error) {
+	driver, err := objectstore.GetDriver()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get object store driver")
+	}
+
+	r, err := driver.GetObject(context.TODO(), fmt.Sprintf("%s/%d.tar.gz", appID, sequence))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get app version archive")
+	}
+
+	return &checksumReader{
+		ReadCloser: r,
+		hash:       sha256.New(),
+		verify: func(sum string) error {
+			return s.verifyAppVersionArchiveChecksum(appID, sequence, sum)
+		},
+	}, nil
+}
+
 func (s *KOTSStore) CreateAppVersion(appID string, currentSequence *int64, filesInDir string, source string, skipPreflights bool, gitops gitopstypes.DownstreamGitOps) (int64, error) {
+	_, span := tracing.Start(context.Background(), "store.CreateAppVersion", attribute.String("app.id", appID), attribute.String("version.source", source))
+	newSequence, err := s.createAppVersionInTx(appID, currentSequence, filesInDir, source, skipPreflights, gitops)
+	tracing.End(span, err)
+	return newSequence, err
+}
+
+func (s *KOTSStore) createAppVersionInTx(appID string, currentSequence *int64, filesInDir string, source string, skipPreflights bool, gitops gitopstypes.DownstreamGitOps) (int64, error) {
 	db := persistence.MustGetPGSession()
 
 	tx, err := db.Begin()
@@ -299,22 +320,57 @@ func (s *KOTSStore) CreateAppVersion(appID string, currentSequence *int64, files
 	}
 
 	if err := tx.Commit(); err != nil {
-		return 0, errors.Wrap(err, "failed to commit")
+		s.deleteUncommittedAppVersionArchive(appID, newSequence)
+		return 0, versiontypes.AppVersionNotCreatedError{Err: errors.Wrap(err, "failed to commit")}
 	}
 
+	events.Publish(eventtypes.EventTypeNewVersion, appID, eventtypes.NewVersionData{
+		Sequence: newSequence,
+		Source:   source,
+	})
+
 	return newSequence, nil
 }
 
+// createAppVersion creates the version in a savepoint of the transaction. If it fails, the rows it wrote are rolled
+// back and its archive is deleted, so that a caller that commits the transaction anyway does not keep a version
+// that is half created. The error is an AppVersionNotCreatedError.
 func (s *KOTSStore) createAppVersion(tx *sql.Tx, appID string, currentSequence *int64, filesInDir string, source string, skipPreflights bool, gitops gitopstypes.DownstreamGitOps) (int64, error) {
+	if _, err := tx.Exec(`savepoint create_app_version`); err != nil {
+		return 0, versiontypes.AppVersionNotCreatedError{Err: errors.Wrap(err, "failed to create savepoint")}
+	}
+
+	newSequence, err := s.writeAppVersion(tx, appID, currentSequence, filesInDir, source, skipPreflights, gitops)
+	if err != nil {
+		if _, rollbackErr := tx.Exec(`rollback to savepoint create_app_version`); rollbackErr != nil {
+			logger.Error(errors.Wrap(rollbackErr, "failed to roll back to savepoint"))
+		}
+		if newSequence != nil {
+			s.deleteUncommittedAppVersionArchive(appID, *newSequence)
+		}
+		return 0, versiontypes.AppVersionNotCreatedError{Err: err}
+	}
+
+	if _, err := tx.Exec(`release savepoint create_app_version`); err != nil {
+		s.deleteUncommittedAppVersionArchive(appID, *newSequence)
+		return 0, versiontypes.AppVersionNotCreatedError{Err: errors.Wrap(err, "failed to release savepoint")}
+	}
+
+	return *newSequence, nil
+}
+
+// writeAppVersion returns the sequence of the version once its record has been written, also when it fails after
+// that, so that the archive of the version can be deleted
+func (s *KOTSStore) writeAppVersion(tx *sql.Tx, appID string, currentSequence *int64, filesInDir string, source string, skipPreflights bool, gitops gitopstypes.DownstreamGitOps) (*int64, error) {
 	kotsKinds, err := kotsutil.LoadKotsKindsFromPath(filesInDir)
 	if err != nil {
-		return int64(0), errors.Wrap(err, "failed to read kots kinds")
+		return nil, errors.Wrap(err, "failed to read kots kinds")
 	}
 
 	appName := kotsKinds.KotsApplication.Spec.Title
 	a, err := s.GetApp(appID)
 	if err != nil {
-		return int64(0), errors.Wrap(err, "failed to get app")
+		return nil, errors.Wrap(err, "failed to get app")
 	}
 	if appName == "" {
 		appName = a.Name
@@ -323,30 +379,30 @@ func (s *KOTSStore) createAppVersion(tx *sql.Tx, appID string, currentSequence *
 	appIcon := kotsKinds.KotsApplication.Spec.Icon
 
 	if err := secrets.ReplaceSecretsInPath(filesInDir); err != nil {
-		return int64(0), errors.Wrap(err, "failed to replace secrets")
+		return nil, errors.Wrap(err, "failed to replace secrets")
 	}
 
 	newSequence, err := s.createAppVersionRecord(tx, appID, currentSequence, appName, appIcon, kotsKinds)
 	if err != nil {
-		return int64(0), errors.Wrap(err, "failed to create app version")
+		return nil, errors.Wrap(err, "failed to create app version")
 	}
 
 	if err := s.CreateAppVersionArchive(appID, int64(newSequence), filesInDir); err != nil {
-		return int64(0), errors.Wrap(err, "failed to create app version archive")
+		return &newSequence, errors.Wrap(err, "failed to create app version archive")
 	}
 
 	previousArchiveDir := ""
 	if currentSequence != nil {
 		previousDir, err := ioutil.TempDir("", "kotsadm")
 		if err != nil {
-			return int64(0), errors.Wrap(err, "failed to create temp dir")
+			return &newSequence, errors.Wrap(err, "failed to create temp dir")
 		}
 		defer os.RemoveAll(previousDir)
 
 		// Get the previous archive, we need this to calculate the diff
 		err = s.GetAppVersionArchive(appID, *currentSequence, previousDir)
 		if err != nil {
-			return int64(0), errors.Wrap(err, "failed to get previous archive")
+			return &newSequence, errors.Wrap(err, "failed to get previous archive")
 		}
 
 		previousArchiveDir = previousDir
@@ -354,12 +410,12 @@ func (s *KOTSStore) createAppVersion(tx *sql.Tx, appID string, currentSequence *
 
 	registrySettings, err := s.GetRegistryDetailsForApp(appID)
 	if err != nil {
-		return int64(0), errors.Wrap(err, "failed to get app registry info")
+		return &newSequence, errors.Wrap(err, "failed to get app registry info")
 	}
 
 	downstreams, err := s.ListDownstreamsForApp(appID)
 	if err != nil {
-		return int64(0), errors.Wrap(err, "failed to list downstreams")
+		return &newSequence, errors.Wrap(err, "failed to list downstreams")
 	}
 
 	for _, d := range downstreams {
@@ -376,7 +432,7 @@ func (s *KOTSStore) createAppVersion(tx *sql.Tx, appID string, currentSequence *
 			// check if version needs additional configuration
 			t, err := kotsadmconfig.NeedsConfiguration(kotsKinds, registrySettings)
 			if err != nil {
-				return int64(0), errors.Wrap(err, "failed to check if version needs configuration")
+				return &newSequence, errors.Wrap(err, "failed to check if version needs configuration")
 			}
 			if t {
 				downstreamStatus = "pending_config"
@@ -400,28 +456,28 @@ func (s *KOTSStore) createAppVersion(tx *sql.Tx, appID string, currentSequence *
 
 		commitURL, err := gitops.CreateGitOpsDownstreamCommit(appID, d.ClusterID, int(newSequence), filesInDir, d.Name)
 		if err != nil {
-			return int64(0), errors.Wrap(err, "failed to create gitops commit")
+			return &newSequence, errors.Wrap(err, "failed to create gitops commit")
 		}
 
 		err = s.addAppVersionToDownstream(tx, appID, d.ClusterID, newSequence,
 			kotsKinds.Installation.Spec.VersionLabel, downstreamStatus, source,
 			diffSummary, diffSummaryError, commitURL, commitURL != "")
 		if err != nil {
-			return int64(0), errors.Wrap(err, "failed to create downstream version")
+			return &newSequence, errors.Wrap(err, "failed to create downstream version")
 		}
 
 		// update metadata configmap
 		applicationSpec, err := kotsKinds.Marshal("kots.io", "v1beta1", "Application")
 		if err != nil {
-			return int64(0), errors.Wrap(err, "failed to marshal application spec")
+			return &newSequence, errors.Wrap(err, "failed to marshal application spec")
 		}
 
 		if err := s.ensureApplicationMetadata(applicationSpec, os.Getenv("POD_NAMESPACE"), a.UpstreamURI); err != nil {
-			return int64(0), errors.Wrap(err, "failed to get metadata config map")
+			return &newSequence, errors.Wrap(err, "failed to get metadata config map")
 		}
 	}
 
-	return newSequence, nil
+	return &newSequence, nil
 }
 
 func (s *KOTSStore) createAppVersionRecord(tx *sql.Tx, appID string, currentSequence *int64, appName string, appIcon string, kotsKinds *kotsutil.KotsKinds) (int64, error) {
@@ -568,16 +624,18 @@ func (s *KOTSStore) addAppVersionToDownstream(tx *sql.Tx, appID string, clusterI
 
 func (s *KOTSStore) GetAppVersion(appID string, sequence int64) (*versiontypes.AppVersion, error) {
 	db := persistence.MustGetPGSession()
-	query := `select sequence, created_at, status, applied_at, kots_installation_spec, kots_app_spec from app_version where app_id = $1 and sequence = $2`
+	query := `select sequence, created_at, status, applied_at, kots_installation_spec, kots_app_spec, notes, labels from app_version where app_id = $1 and sequence = $2`
 	row := db.QueryRow(query, appID, sequence)
 
 	var status sql.NullString
 	var deployedAt sql.NullTime
 	var installationSpec sql.NullString
 	var kotsAppSpec sql.NullString
+	var notes sql.NullString
+	var labels sql.NullString
 
 	v := versiontypes.AppVersion{}
-	if err := row.Scan(&v.Sequence, &v.CreatedOn, &status, &deployedAt, &installationSpec, &kotsAppSpec); err != nil {
+	if err := row.Scan(&v.Sequence, &v.CreatedOn, &status, &deployedAt, &installationSpec, &kotsAppSpec, &notes, &labels); err != nil {
 		if err == sql.ErrNoRows {
 			return nil, ErrNotFound
 		}
@@ -610,12 +668,18 @@ func (s *KOTSStore) GetAppVersion(appID string, sequence int64) (*versiontypes.A
 	v.KOTSKinds = &kotsKinds
 	v.Status = status.String
 
+	v.Notes = notes.String
+	v.Labels, err = versionLabelsFromString(labels)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get labels")
+	}
+
 	return &v, nil
 }
 
 func (s *KOTSStore) GetAppVersionsAfter(appID string, sequence int64) ([]*versiontypes.AppVersion, error) {
 	db := persistence.MustGetPGSession()
-	query := `select sequence, created_at, status, applied_at, kots_installation_spec from app_version where app_id = $1 and sequence > $2`
+	query := `select sequence, created_at, status, applied_at, kots_installation_spec, notes, labels from app_version where app_id = $1 and sequence > $2`
 	rows, err := db.Query(query, appID, sequence)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to query")
@@ -625,12 +689,14 @@ func (s *KOTSStore) GetAppVersionsAfter(appID string, sequence int64) ([]*versio
 	var status sql.NullString
 	var deployedAt sql.NullTime
 	var installationSpec sql.NullString
+	var notes sql.NullString
+	var labels sql.NullString
 
 	versions := []*versiontypes.AppVersion{}
 
 	for rows.Next() {
 		v := versiontypes.AppVersion{}
-		if err := rows.Scan(&v.Sequence, &v.CreatedOn, &status, &deployedAt, &installationSpec); err != nil {
+		if err := rows.Scan(&v.Sequence, &v.CreatedOn, &status, &deployedAt, &installationSpec, &notes, &labels); err != nil {
 			return nil, errors.Wrap(err, "failed to scan")
 		}
 
@@ -648,8 +714,50 @@ func (s *KOTSStore) GetAppVersionsAfter(appID string, sequence int64) ([]*versio
 
 		v.Status = status.String
 
+		v.Notes = notes.String
+		v.Labels, err = versionLabelsFromString(labels)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to get labels")
+		}
+
 		versions = append(versions, &v)
 	}
 
 	return versions, nil
 }
+
+func (s *KOTSStore) SetAppVersionNotes(appID string, sequence int64, notes string, labels []string) error {
+	labelsStr := ""
+	if len(labels) > 0 {
+		b, err := json.Marshal(labels)
+		if err != nil {
+			return errors.Wrap(err, "failed to marshal labels")
+		}
+		labelsStr = string(b)
+	}
+
+	db := persistence.MustGetPGSession()
+	query := `update app_version set notes = $1, labels = $2 where app_id = $3 and sequence = $4`
+	result, err := db.Exec(query, notes, labelsStr, appID, sequence)
+	if err != nil {
+		return errors.Wrap(err, "failed to exec db query")
+	}
+	if rowsAffected, err := result.RowsAffected(); err == nil && rowsAffected == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+// versionLabelsFromString returns the labels stored as a json array
+func versionLabelsFromString(labels sql.NullString) ([]string, error) {
+	if labels.String == "" {
+		return nil, nil
+	}
+
+	versionLabels := []string{}
+	if err := json.Unmarshal([]byte(labels.String), &versionLabels); err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal")
+	}
+	return versionLabels, nil
+}
@@ -8,21 +8,44 @@ import (
 	context "context"
 	gomock "github.com/golang/mock/gomock"
 	v1beta1 "github.com/replicatedhq/kots/kotskinds/apis/kots/v1beta1"
-	types "github.com/replicatedhq/kots/pkg/airgap/types"
-	types0 "github.com/replicatedhq/kots/pkg/api/appstatus/types"
-	types1 "github.com/replicatedhq/kots/pkg/api/downstream/types"
-	types2 "github.com/replicatedhq/kots/pkg/api/version/types"
-	types3 "github.com/replicatedhq/kots/pkg/app/types"
-	types4 "github.com/replicatedhq/kots/pkg/gitops/types"
-	types5 "github.com/replicatedhq/kots/pkg/kotsadmsnapshot/types"
-	types6 "github.com/replicatedhq/kots/pkg/online/types"
-	types7 "github.com/replicatedhq/kots/pkg/preflight/types"
-	types8 "github.com/replicatedhq/kots/pkg/registry/types"
-	types9 "github.com/replicatedhq/kots/pkg/render/types"
-	types10 "github.com/replicatedhq/kots/pkg/session/types"
-	types11 "github.com/replicatedhq/kots/pkg/supportbundle/types"
-	types12 "github.com/replicatedhq/kots/pkg/user/types"
+	types "github.com/replicatedhq/kots/pkg/admission/types"
+	types0 "github.com/replicatedhq/kots/pkg/airgap/types"
+	types1 "github.com/replicatedhq/kots/pkg/api/appstatus/types"
+	types2 "github.com/replicatedhq/kots/pkg/api/downstream/types"
+	types3 "github.com/replicatedhq/kots/pkg/api/version/types"
+	types4 "github.com/replicatedhq/kots/pkg/app/types"
+	types5 "github.com/replicatedhq/kots/pkg/archiveintegrity/types"
+	types6 "github.com/replicatedhq/kots/pkg/assetcache/types"
+	types7 "github.com/replicatedhq/kots/pkg/canary/types"
+	types8 "github.com/replicatedhq/kots/pkg/deployapproval/types"
+	types9 "github.com/replicatedhq/kots/pkg/deployhistory/types"
+	types10 "github.com/replicatedhq/kots/pkg/fleetreport/types"
+	types11 "github.com/replicatedhq/kots/pkg/freeze/types"
+	types12 "github.com/replicatedhq/kots/pkg/gitops/types"
+	types13 "github.com/replicatedhq/kots/pkg/imagereport/types"
+	types14 "github.com/replicatedhq/kots/pkg/kotsadmsnapshot/types"
+	types15 "github.com/replicatedhq/kots/pkg/ldapauth/types"
+	types16 "github.com/replicatedhq/kots/pkg/logger/types"
+	types17 "github.com/replicatedhq/kots/pkg/maintenance/types"
+	types18 "github.com/replicatedhq/kots/pkg/metering/types"
+	types19 "github.com/replicatedhq/kots/pkg/online/types"
+	types20 "github.com/replicatedhq/kots/pkg/postdeploytest/types"
+	types21 "github.com/replicatedhq/kots/pkg/preflight/types"
+	types22 "github.com/replicatedhq/kots/pkg/prometheus/types"
+	types23 "github.com/replicatedhq/kots/pkg/registry/types"
+	types24 "github.com/replicatedhq/kots/pkg/releasefeedback/types"
+	types25 "github.com/replicatedhq/kots/pkg/remoteinstall/types"
+	types26 "github.com/replicatedhq/kots/pkg/render/types"
+	types27 "github.com/replicatedhq/kots/pkg/restoredrill/types"
+	types28 "github.com/replicatedhq/kots/pkg/scheduledjob/types"
+	types29 "github.com/replicatedhq/kots/pkg/session/types"
+	types30 "github.com/replicatedhq/kots/pkg/supportbundle/types"
+	types31 "github.com/replicatedhq/kots/pkg/temporaryaccess/types"
+	types32 "github.com/replicatedhq/kots/pkg/updatechecker/types"
+	types33 "github.com/replicatedhq/kots/pkg/uploadquota/types"
+	types34 "github.com/replicatedhq/kots/pkg/user/types"
 	redact "github.com/replicatedhq/troubleshoot/pkg/redact"
+	io "io"
 	reflect "reflect"
 	time "time"
 )
@@ -62,11 +85,39 @@ func (mr *MockStoreMockRecorder) RunMigrations() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RunMigrations", reflect.TypeOf((*MockStore)(nil).RunMigrations))
 }
 
+// RunSchemaMigrations mocks base method
+func (m *MockStore) RunSchemaMigrations() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RunSchemaMigrations")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RunSchemaMigrations indicates an expected call of RunSchemaMigrations
+func (mr *MockStoreMockRecorder) RunSchemaMigrations() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RunSchemaMigrations", reflect.TypeOf((*MockStore)(nil).RunSchemaMigrations))
+}
+
+// RollbackSchemaMigrations mocks base method
+func (m *MockStore) RollbackSchemaMigrations(targetVersion int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RollbackSchemaMigrations", targetVersion)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RollbackSchemaMigrations indicates an expected call of RollbackSchemaMigrations
+func (mr *MockStoreMockRecorder) RollbackSchemaMigrations(targetVersion interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RollbackSchemaMigrations", reflect.TypeOf((*MockStore)(nil).RollbackSchemaMigrations), targetVersion)
+}
+
 // GetRegistryDetailsForApp mocks base method
-func (m *MockStore) GetRegistryDetailsForApp(appID string) (types8.RegistrySettings, error) {
+func (m *MockStore) GetRegistryDetailsForApp(appID string) (types23.RegistrySettings, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "GetRegistryDetailsForApp", appID)
-	ret0, _ := ret[0].(types8.RegistrySettings)
+	ret0, _ := ret[0].(types23.RegistrySettings)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
@@ -92,10 +143,10 @@ func (mr *MockStoreMockRecorder) UpdateRegistry(appID, hostname, username, passw
 }
 
 // ListSupportBundles mocks base method
-func (m *MockStore) ListSupportBundles(appID string) ([]*types11.SupportBundle, error) {
+func (m *MockStore) ListSupportBundles(appID string) ([]*types30.SupportBundle, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "ListSupportBundles", appID)
-	ret0, _ := ret[0].([]*types11.SupportBundle)
+	ret0, _ := ret[0].([]*types30.SupportBundle)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
@@ -107,10 +158,10 @@ func (mr *MockStoreMockRecorder) ListSupportBundles(appID interface{}) *gomock.C
 }
 
 // GetSupportBundle mocks base method
-func (m *MockStore) GetSupportBundle(bundleID string) (*types11.SupportBundle, error) {
+func (m *MockStore) GetSupportBundle(bundleID string) (*types30.SupportBundle, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "GetSupportBundle", bundleID)
-	ret0, _ := ret[0].(*types11.SupportBundle)
+	ret0, _ := ret[0].(*types30.SupportBundle)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
@@ -122,10 +173,10 @@ func (mr *MockStoreMockRecorder) GetSupportBundle(bundleID interface{}) *gomock.
 }
 
 // CreateSupportBundle mocks base method
-func (m *MockStore) CreateSupportBundle(bundleID, appID, archivePath string, marshalledTree []byte) (*types11.SupportBundle, error) {
+func (m *MockStore) CreateSupportBundle(bundleID, appID, archivePath string, marshalledTree []byte) (*types30.SupportBundle, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "CreateSupportBundle", bundleID, appID, archivePath, marshalledTree)
-	ret0, _ := ret[0].(*types11.SupportBundle)
+	ret0, _ := ret[0].(*types30.SupportBundle)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
@@ -152,10 +203,10 @@ func (mr *MockStoreMockRecorder) GetSupportBundleArchive(bundleID interface{}) *
 }
 
 // GetSupportBundleAnalysis mocks base method
-func (m *MockStore) GetSupportBundleAnalysis(bundleID string) (*types11.SupportBundleAnalysis, error) {
+func (m *MockStore) GetSupportBundleAnalysis(bundleID string) (*types30.SupportBundleAnalysis, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "GetSupportBundleAnalysis", bundleID)
-	ret0, _ := ret[0].(*types11.SupportBundleAnalysis)
+	ret0, _ := ret[0].(*types30.SupportBundleAnalysis)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
@@ -210,7 +261,7 @@ func (mr *MockStoreMockRecorder) SetRedactions(bundleID, redacts interface{}) *g
 }
 
 // CreateInProgressSupportBundle mocks base method
-func (m *MockStore) CreateInProgressSupportBundle(supportBundle *types11.SupportBundle) error {
+func (m *MockStore) CreateInProgressSupportBundle(supportBundle *types30.SupportBundle) error {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "CreateInProgressSupportBundle", supportBundle)
 	ret0, _ := ret[0].(error)
@@ -224,7 +275,7 @@ func (mr *MockStoreMockRecorder) CreateInProgressSupportBundle(supportBundle int
 }
 
 // UpdateSupportBundle mocks base method
-func (m *MockStore) UpdateSupportBundle(bundle *types11.SupportBundle) error {
+func (m *MockStore) UpdateSupportBundle(bundle *types30.SupportBundle) error {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "UpdateSupportBundle", bundle)
 	ret0, _ := ret[0].(error)
@@ -295,10 +346,10 @@ func (mr *MockStoreMockRecorder) SetPreflightResults(appID, sequence, results in
 }
 
 // GetPreflightResults mocks base method
-func (m *MockStore) GetPreflightResults(appID string, sequence int64) (*types7.PreflightResult, error) {
+func (m *MockStore) GetPreflightResults(appID string, sequence int64) (*types21.PreflightResult, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "GetPreflightResults", appID, sequence)
-	ret0, _ := ret[0].(*types7.PreflightResult)
+	ret0, _ := ret[0].(*types21.PreflightResult)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
@@ -366,11 +417,40 @@ func (mr *MockStoreMockRecorder) SetPrometheusAddress(address interface{}) *gomo
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetPrometheusAddress", reflect.TypeOf((*MockStore)(nil).SetPrometheusAddress), address)
 }
 
+// GetPrometheusAuth mocks base method
+func (m *MockStore) GetPrometheusAuth() (types22.Auth, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPrometheusAuth")
+	ret0, _ := ret[0].(types22.Auth)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPrometheusAuth indicates an expected call of GetPrometheusAuth
+func (mr *MockStoreMockRecorder) GetPrometheusAuth() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPrometheusAuth", reflect.TypeOf((*MockStore)(nil).GetPrometheusAuth))
+}
+
+// SetPrometheusAuth mocks base method
+func (m *MockStore) SetPrometheusAuth(auth types22.Auth) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetPrometheusAuth", auth)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetPrometheusAuth indicates an expected call of SetPrometheusAuth
+func (mr *MockStoreMockRecorder) SetPrometheusAuth(auth interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetPrometheusAuth", reflect.TypeOf((*MockStore)(nil).SetPrometheusAuth), auth)
+}
+
 // GetPendingAirgapUploadApp mocks base method
-func (m *MockStore) GetPendingAirgapUploadApp() (*types.PendingApp, error) {
+func (m *MockStore) GetPendingAirgapUploadApp() (*types0.PendingApp, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "GetPendingAirgapUploadApp")
-	ret0, _ := ret[0].(*types.PendingApp)
+	ret0, _ := ret[0].(*types0.PendingApp)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
@@ -382,10 +462,10 @@ func (mr *MockStoreMockRecorder) GetPendingAirgapUploadApp() *gomock.Call {
 }
 
 // GetAirgapInstallStatus mocks base method
-func (m *MockStore) GetAirgapInstallStatus(appID string) (*types.InstallStatus, error) {
+func (m *MockStore) GetAirgapInstallStatus(appID string) (*types0.InstallStatus, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "GetAirgapInstallStatus", appID)
-	ret0, _ := ret[0].(*types.InstallStatus)
+	ret0, _ := ret[0].(*types0.InstallStatus)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
@@ -482,11 +562,26 @@ func (mr *MockStoreMockRecorder) GetTaskStatus(taskID interface{}) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTaskStatus", reflect.TypeOf((*MockStore)(nil).GetTaskStatus), taskID)
 }
 
+// ClearStaleTaskStatuses mocks base method
+func (m *MockStore) ClearStaleTaskStatuses(notUpdatedSince time.Time) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClearStaleTaskStatuses", notUpdatedSince)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClearStaleTaskStatuses indicates an expected call of ClearStaleTaskStatuses
+func (mr *MockStoreMockRecorder) ClearStaleTaskStatuses(notUpdatedSince interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClearStaleTaskStatuses", reflect.TypeOf((*MockStore)(nil).ClearStaleTaskStatuses), notUpdatedSince)
+}
+
 // CreateSession mocks base method
-func (m *MockStore) CreateSession(user *types12.User, issuedAt, expiresAt time.Time, roles []string) (*types10.Session, error) {
+func (m *MockStore) CreateSession(user *types34.User, issuedAt, expiresAt time.Time, roles []string) (*types29.Session, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "CreateSession", user, issuedAt, expiresAt, roles)
-	ret0, _ := ret[0].(*types10.Session)
+	ret0, _ := ret[0].(*types29.Session)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
@@ -511,11 +606,25 @@ func (mr *MockStoreMockRecorder) DeleteSession(sessionID interface{}) *gomock.Ca
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteSession", reflect.TypeOf((*MockStore)(nil).DeleteSession), sessionID)
 }
 
+// DeleteSessions mocks base method
+func (m *MockStore) DeleteSessions(sessionIDs []string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteSessions", sessionIDs)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteSessions indicates an expected call of DeleteSessions
+func (mr *MockStoreMockRecorder) DeleteSessions(sessionIDs interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteSessions", reflect.TypeOf((*MockStore)(nil).DeleteSessions), sessionIDs)
+}
+
 // GetSession mocks base method
-func (m *MockStore) GetSession(sessionID string) (*types10.Session, error) {
+func (m *MockStore) GetSession(sessionID string) (*types29.Session, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "GetSession", sessionID)
-	ret0, _ := ret[0].(*types10.Session)
+	ret0, _ := ret[0].(*types29.Session)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
@@ -526,11 +635,54 @@ func (mr *MockStoreMockRecorder) GetSession(sessionID interface{}) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSession", reflect.TypeOf((*MockStore)(nil).GetSession), sessionID)
 }
 
+// ListSessions mocks base method
+func (m *MockStore) ListSessions() ([]types29.Session, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListSessions")
+	ret0, _ := ret[0].([]types29.Session)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListSessions indicates an expected call of ListSessions
+func (mr *MockStoreMockRecorder) ListSessions() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListSessions", reflect.TypeOf((*MockStore)(nil).ListSessions))
+}
+
+// ImportSessions mocks base method
+func (m *MockStore) ImportSessions(sessions []types29.Session) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ImportSessions", sessions)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ImportSessions indicates an expected call of ImportSessions
+func (mr *MockStoreMockRecorder) ImportSessions(sessions interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ImportSessions", reflect.TypeOf((*MockStore)(nil).ImportSessions), sessions)
+}
+
+// UpdateSessionActivity mocks base method
+func (m *MockStore) UpdateSessionActivity(sessionID string, lastActiveAt time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateSessionActivity", sessionID, lastActiveAt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateSessionActivity indicates an expected call of UpdateSessionActivity
+func (mr *MockStoreMockRecorder) UpdateSessionActivity(sessionID, lastActiveAt interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateSessionActivity", reflect.TypeOf((*MockStore)(nil).UpdateSessionActivity), sessionID, lastActiveAt)
+}
+
 // GetAppStatus mocks base method
-func (m *MockStore) GetAppStatus(appID string) (*types0.AppStatus, error) {
+func (m *MockStore) GetAppStatus(appID string) (*types1.AppStatus, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "GetAppStatus", appID)
-	ret0, _ := ret[0].(*types0.AppStatus)
+	ret0, _ := ret[0].(*types1.AppStatus)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
@@ -542,7 +694,7 @@ func (mr *MockStoreMockRecorder) GetAppStatus(appID interface{}) *gomock.Call {
 }
 
 // SetAppStatus mocks base method
-func (m *MockStore) SetAppStatus(appID string, resourceStates []types0.ResourceState, updatedAt time.Time, sequence int64) error {
+func (m *MockStore) SetAppStatus(appID string, resourceStates []types1.ResourceState, updatedAt time.Time, sequence int64) error {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "SetAppStatus", appID, resourceStates, updatedAt, sequence)
 	ret0, _ := ret[0].(error)
@@ -584,10 +736,10 @@ func (mr *MockStoreMockRecorder) SetAppInstallState(appID, state interface{}) *g
 }
 
 // ListInstalledApps mocks base method
-func (m *MockStore) ListInstalledApps() ([]*types3.App, error) {
+func (m *MockStore) ListInstalledApps() ([]*types4.App, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "ListInstalledApps")
-	ret0, _ := ret[0].([]*types3.App)
+	ret0, _ := ret[0].([]*types4.App)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
@@ -629,10 +781,10 @@ func (mr *MockStoreMockRecorder) GetAppIDFromSlug(slug interface{}) *gomock.Call
 }
 
 // GetApp mocks base method
-func (m *MockStore) GetApp(appID string) (*types3.App, error) {
+func (m *MockStore) GetApp(appID string) (*types4.App, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "GetApp", appID)
-	ret0, _ := ret[0].(*types3.App)
+	ret0, _ := ret[0].(*types4.App)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
@@ -644,10 +796,10 @@ func (mr *MockStoreMockRecorder) GetApp(appID interface{}) *gomock.Call {
 }
 
 // GetAppFromSlug mocks base method
-func (m *MockStore) GetAppFromSlug(slug string) (*types3.App, error) {
+func (m *MockStore) GetAppFromSlug(slug string) (*types4.App, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "GetAppFromSlug", slug)
-	ret0, _ := ret[0].(*types3.App)
+	ret0, _ := ret[0].(*types4.App)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
@@ -659,10 +811,10 @@ func (mr *MockStoreMockRecorder) GetAppFromSlug(slug interface{}) *gomock.Call {
 }
 
 // CreateApp mocks base method
-func (m *MockStore) CreateApp(name, upstreamURI, licenseData string, isAirgapEnabled, skipImagePush, registryIsReadOnly bool) (*types3.App, error) {
+func (m *MockStore) CreateApp(name, upstreamURI, licenseData string, isAirgapEnabled, skipImagePush, registryIsReadOnly bool) (*types4.App, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "CreateApp", name, upstreamURI, licenseData, isAirgapEnabled, skipImagePush, registryIsReadOnly)
-	ret0, _ := ret[0].(*types3.App)
+	ret0, _ := ret[0].(*types4.App)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
@@ -674,10 +826,10 @@ func (mr *MockStoreMockRecorder) CreateApp(name, upstreamURI, licenseData, isAir
 }
 
 // ListDownstreamsForApp mocks base method
-func (m *MockStore) ListDownstreamsForApp(appID string) ([]types1.Downstream, error) {
+func (m *MockStore) ListDownstreamsForApp(appID string) ([]types2.Downstream, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "ListDownstreamsForApp", appID)
-	ret0, _ := ret[0].([]types1.Downstream)
+	ret0, _ := ret[0].([]types2.Downstream)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
@@ -689,10 +841,10 @@ func (mr *MockStoreMockRecorder) ListDownstreamsForApp(appID interface{}) *gomoc
 }
 
 // ListAppsForDownstream mocks base method
-func (m *MockStore) ListAppsForDownstream(clusterID string) ([]*types3.App, error) {
+func (m *MockStore) ListAppsForDownstream(clusterID string) ([]*types4.App, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "ListAppsForDownstream", clusterID)
-	ret0, _ := ret[0].([]*types3.App)
+	ret0, _ := ret[0].([]*types4.App)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
@@ -704,10 +856,10 @@ func (mr *MockStoreMockRecorder) ListAppsForDownstream(clusterID interface{}) *g
 }
 
 // GetDownstream mocks base method
-func (m *MockStore) GetDownstream(clusterID string) (*types1.Downstream, error) {
+func (m *MockStore) GetDownstream(clusterID string) (*types2.Downstream, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "GetDownstream", clusterID)
-	ret0, _ := ret[0].(*types1.Downstream)
+	ret0, _ := ret[0].(*types2.Downstream)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
@@ -747,6 +899,146 @@ func (mr *MockStoreMockRecorder) SetUpdateCheckerSpec(appID, updateCheckerSpec i
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetUpdateCheckerSpec", reflect.TypeOf((*MockStore)(nil).SetUpdateCheckerSpec), appID, updateCheckerSpec)
 }
 
+// SetDeployPolicy mocks base method
+func (m *MockStore) SetDeployPolicy(appID string, deployPolicy types4.DeployPolicy) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetDeployPolicy", appID, deployPolicy)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetDeployPolicy indicates an expected call of SetDeployPolicy
+func (mr *MockStoreMockRecorder) SetDeployPolicy(appID, deployPolicy interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetDeployPolicy", reflect.TypeOf((*MockStore)(nil).SetDeployPolicy), appID, deployPolicy)
+}
+
+// SetAdmissionDryRun mocks base method
+func (m *MockStore) SetAdmissionDryRun(appID string, enabled bool) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetAdmissionDryRun", appID, enabled)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetAdmissionDryRun indicates an expected call of SetAdmissionDryRun
+func (mr *MockStoreMockRecorder) SetAdmissionDryRun(appID, enabled interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetAdmissionDryRun", reflect.TypeOf((*MockStore)(nil).SetAdmissionDryRun), appID, enabled)
+}
+
+// SetAppArchived mocks base method
+func (m *MockStore) SetAppArchived(appID string, archived bool) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetAppArchived", appID, archived)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetAppArchived indicates an expected call of SetAppArchived
+func (mr *MockStoreMockRecorder) SetAppArchived(appID, archived interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetAppArchived", reflect.TypeOf((*MockStore)(nil).SetAppArchived), appID, archived)
+}
+
+// SetAppProtected mocks base method
+func (m *MockStore) SetAppProtected(appID string, protected bool) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetAppProtected", appID, protected)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetAppProtected indicates an expected call of SetAppProtected
+func (mr *MockStoreMockRecorder) SetAppProtected(appID, protected interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetAppProtected", reflect.TypeOf((*MockStore)(nil).SetAppProtected), appID, protected)
+}
+
+// SetRequireDeployApproval mocks base method
+func (m *MockStore) SetRequireDeployApproval(appID string, required bool) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetRequireDeployApproval", appID, required)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetRequireDeployApproval indicates an expected call of SetRequireDeployApproval
+func (mr *MockStoreMockRecorder) SetRequireDeployApproval(appID, required interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetRequireDeployApproval", reflect.TypeOf((*MockStore)(nil).SetRequireDeployApproval), appID, required)
+}
+
+// SetApplyPolicy mocks base method
+func (m *MockStore) SetApplyPolicy(appID string, applyPolicy types4.ApplyPolicy) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetApplyPolicy", appID, applyPolicy)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetApplyPolicy indicates an expected call of SetApplyPolicy
+func (mr *MockStoreMockRecorder) SetApplyPolicy(appID, applyPolicy interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetApplyPolicy", reflect.TypeOf((*MockStore)(nil).SetApplyPolicy), appID, applyPolicy)
+}
+
+// SetCanaryPolicy mocks base method
+func (m *MockStore) SetCanaryPolicy(appID string, canaryPolicy types4.CanaryPolicy) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetCanaryPolicy", appID, canaryPolicy)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetCanaryPolicy indicates an expected call of SetCanaryPolicy
+func (mr *MockStoreMockRecorder) SetCanaryPolicy(appID, canaryPolicy interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetCanaryPolicy", reflect.TypeOf((*MockStore)(nil).SetCanaryPolicy), appID, canaryPolicy)
+}
+
+// SetAppNamespace mocks base method
+func (m *MockStore) SetAppNamespace(appID, namespace string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetAppNamespace", appID, namespace)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetAppNamespace indicates an expected call of SetAppNamespace
+func (mr *MockStoreMockRecorder) SetAppNamespace(appID, namespace interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetAppNamespace", reflect.TypeOf((*MockStore)(nil).SetAppNamespace), appID, namespace)
+}
+
+// SetAppLabels mocks base method
+func (m *MockStore) SetAppLabels(appID string, labels map[string]string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetAppLabels", appID, labels)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetAppLabels indicates an expected call of SetAppLabels
+func (mr *MockStoreMockRecorder) SetAppLabels(appID, labels interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetAppLabels", reflect.TypeOf((*MockStore)(nil).SetAppLabels), appID, labels)
+}
+
+// SetImagePushBandwidthLimit mocks base method
+func (m *MockStore) SetImagePushBandwidthLimit(appID string, bytesPerSecond int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetImagePushBandwidthLimit", appID, bytesPerSecond)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetImagePushBandwidthLimit indicates an expected call of SetImagePushBandwidthLimit
+func (mr *MockStoreMockRecorder) SetImagePushBandwidthLimit(appID, bytesPerSecond interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetImagePushBandwidthLimit", reflect.TypeOf((*MockStore)(nil).SetImagePushBandwidthLimit), appID, bytesPerSecond)
+}
+
 // SetSnapshotTTL mocks base method
 func (m *MockStore) SetSnapshotTTL(appID, snapshotTTL string) error {
 	m.ctrl.T.Helper()
@@ -775,6 +1067,77 @@ func (mr *MockStoreMockRecorder) SetSnapshotSchedule(appID, snapshotSchedule int
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetSnapshotSchedule", reflect.TypeOf((*MockStore)(nil).SetSnapshotSchedule), appID, snapshotSchedule)
 }
 
+// SetRestoreDrillSchedule mocks base method
+func (m *MockStore) SetRestoreDrillSchedule(appID, restoreDrillSchedule string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetRestoreDrillSchedule", appID, restoreDrillSchedule)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetRestoreDrillSchedule indicates an expected call of SetRestoreDrillSchedule
+func (mr *MockStoreMockRecorder) SetRestoreDrillSchedule(appID, restoreDrillSchedule interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetRestoreDrillSchedule", reflect.TypeOf((*MockStore)(nil).SetRestoreDrillSchedule), appID, restoreDrillSchedule)
+}
+
+// SetScheduleTimezone mocks base method
+func (m *MockStore) SetScheduleTimezone(appID, timezone string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetScheduleTimezone", appID, timezone)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetScheduleTimezone indicates an expected call of SetScheduleTimezone
+func (mr *MockStoreMockRecorder) SetScheduleTimezone(appID, timezone interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetScheduleTimezone", reflect.TypeOf((*MockStore)(nil).SetScheduleTimezone), appID, timezone)
+}
+
+// TrashApp mocks base method
+func (m *MockStore) TrashApp(appID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TrashApp", appID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// TrashApp indicates an expected call of TrashApp
+func (mr *MockStoreMockRecorder) TrashApp(appID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TrashApp", reflect.TypeOf((*MockStore)(nil).TrashApp), appID)
+}
+
+// RestoreTrashedApp mocks base method
+func (m *MockStore) RestoreTrashedApp(appID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RestoreTrashedApp", appID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RestoreTrashedApp indicates an expected call of RestoreTrashedApp
+func (mr *MockStoreMockRecorder) RestoreTrashedApp(appID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestoreTrashedApp", reflect.TypeOf((*MockStore)(nil).RestoreTrashedApp), appID)
+}
+
+// ListRemovedApps mocks base method
+func (m *MockStore) ListRemovedApps() ([]*types4.App, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListRemovedApps")
+	ret0, _ := ret[0].([]*types4.App)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListRemovedApps indicates an expected call of ListRemovedApps
+func (mr *MockStoreMockRecorder) ListRemovedApps() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListRemovedApps", reflect.TypeOf((*MockStore)(nil).ListRemovedApps))
+}
+
 // RemoveApp mocks base method
 func (m *MockStore) RemoveApp(appID string) error {
 	m.ctrl.T.Helper()
@@ -922,10 +1285,10 @@ func (mr *MockStoreMockRecorder) GetIgnoreRBACErrors(appID, sequence interface{}
 }
 
 // GetCurrentVersion mocks base method
-func (m *MockStore) GetCurrentVersion(appID, clusterID string) (*types1.DownstreamVersion, error) {
+func (m *MockStore) GetCurrentVersion(appID, clusterID string) (*types2.DownstreamVersion, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "GetCurrentVersion", appID, clusterID)
-	ret0, _ := ret[0].(*types1.DownstreamVersion)
+	ret0, _ := ret[0].(*types2.DownstreamVersion)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
@@ -952,10 +1315,10 @@ func (mr *MockStoreMockRecorder) GetStatusForVersion(appID, clusterID, sequence
 }
 
 // GetPendingVersions mocks base method
-func (m *MockStore) GetPendingVersions(appID, clusterID string) ([]types1.DownstreamVersion, error) {
+func (m *MockStore) GetPendingVersions(appID, clusterID string) ([]types2.DownstreamVersion, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "GetPendingVersions", appID, clusterID)
-	ret0, _ := ret[0].([]types1.DownstreamVersion)
+	ret0, _ := ret[0].([]types2.DownstreamVersion)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
@@ -967,10 +1330,10 @@ func (mr *MockStoreMockRecorder) GetPendingVersions(appID, clusterID interface{}
 }
 
 // GetPastVersions mocks base method
-func (m *MockStore) GetPastVersions(appID, clusterID string) ([]types1.DownstreamVersion, error) {
+func (m *MockStore) GetPastVersions(appID, clusterID string) ([]types2.DownstreamVersion, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "GetPastVersions", appID, clusterID)
-	ret0, _ := ret[0].([]types1.DownstreamVersion)
+	ret0, _ := ret[0].([]types2.DownstreamVersion)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
@@ -981,11 +1344,27 @@ func (mr *MockStoreMockRecorder) GetPastVersions(appID, clusterID interface{}) *
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPastVersions", reflect.TypeOf((*MockStore)(nil).GetPastVersions), appID, clusterID)
 }
 
+// ListDownstreamVersions mocks base method
+func (m *MockStore) ListDownstreamVersions(appID, clusterID string, opts types2.VersionHistoryOptions) ([]types2.DownstreamVersion, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListDownstreamVersions", appID, clusterID, opts)
+	ret0, _ := ret[0].([]types2.DownstreamVersion)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ListDownstreamVersions indicates an expected call of ListDownstreamVersions
+func (mr *MockStoreMockRecorder) ListDownstreamVersions(appID, clusterID, opts interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListDownstreamVersions", reflect.TypeOf((*MockStore)(nil).ListDownstreamVersions), appID, clusterID, opts)
+}
+
 // GetDownstreamOutput mocks base method
-func (m *MockStore) GetDownstreamOutput(appID, clusterID string, sequence int64) (*types1.DownstreamOutput, error) {
+func (m *MockStore) GetDownstreamOutput(appID, clusterID string, sequence int64) (*types2.DownstreamOutput, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "GetDownstreamOutput", appID, clusterID, sequence)
-	ret0, _ := ret[0].(*types1.DownstreamOutput)
+	ret0, _ := ret[0].(*types2.DownstreamOutput)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
@@ -1012,7 +1391,7 @@ func (mr *MockStoreMockRecorder) IsDownstreamDeploySuccessful(appID, clusterID,
 }
 
 // UpdateDownstreamDeployStatus mocks base method
-func (m *MockStore) UpdateDownstreamDeployStatus(appID, clusterID string, sequence int64, isError bool, output types1.DownstreamOutput) error {
+func (m *MockStore) UpdateDownstreamDeployStatus(appID, clusterID string, sequence int64, isError bool, output types2.DownstreamOutput) error {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "UpdateDownstreamDeployStatus", appID, clusterID, sequence, isError, output)
 	ret0, _ := ret[0].(error)
@@ -1039,99 +1418,319 @@ func (mr *MockStoreMockRecorder) DeleteDownstreamDeployStatus(appID, clusterID,
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteDownstreamDeployStatus", reflect.TypeOf((*MockStore)(nil).DeleteDownstreamDeployStatus), appID, clusterID, sequence)
 }
 
-// IsIdentityServiceSupportedForVersion mocks base method
-func (m *MockStore) IsIdentityServiceSupportedForVersion(appID string, sequence int64) (bool, error) {
+// GetDownstreamPostDeployTestResults mocks base method
+func (m *MockStore) GetDownstreamPostDeployTestResults(appID, clusterID string, sequence int64) ([]types20.Result, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "IsIdentityServiceSupportedForVersion", appID, sequence)
-	ret0, _ := ret[0].(bool)
+	ret := m.ctrl.Call(m, "GetDownstreamPostDeployTestResults", appID, clusterID, sequence)
+	ret0, _ := ret[0].([]types20.Result)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// IsIdentityServiceSupportedForVersion indicates an expected call of IsIdentityServiceSupportedForVersion
-func (mr *MockStoreMockRecorder) IsIdentityServiceSupportedForVersion(appID, sequence interface{}) *gomock.Call {
+// GetDownstreamPostDeployTestResults indicates an expected call of GetDownstreamPostDeployTestResults
+func (mr *MockStoreMockRecorder) GetDownstreamPostDeployTestResults(appID, clusterID, sequence interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsIdentityServiceSupportedForVersion", reflect.TypeOf((*MockStore)(nil).IsIdentityServiceSupportedForVersion), appID, sequence)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDownstreamPostDeployTestResults", reflect.TypeOf((*MockStore)(nil).GetDownstreamPostDeployTestResults), appID, clusterID, sequence)
 }
 
-// IsRollbackSupportedForVersion mocks base method
-func (m *MockStore) IsRollbackSupportedForVersion(appID string, sequence int64) (bool, error) {
+// SetDownstreamPostDeployTestResults mocks base method
+func (m *MockStore) SetDownstreamPostDeployTestResults(appID, clusterID string, sequence int64, results []types20.Result) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "IsRollbackSupportedForVersion", appID, sequence)
-	ret0, _ := ret[0].(bool)
+	ret := m.ctrl.Call(m, "SetDownstreamPostDeployTestResults", appID, clusterID, sequence, results)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetDownstreamPostDeployTestResults indicates an expected call of SetDownstreamPostDeployTestResults
+func (mr *MockStoreMockRecorder) SetDownstreamPostDeployTestResults(appID, clusterID, sequence, results interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetDownstreamPostDeployTestResults", reflect.TypeOf((*MockStore)(nil).SetDownstreamPostDeployTestResults), appID, clusterID, sequence, results)
+}
+
+// GetDownstreamAdmissionValidation mocks base method
+func (m *MockStore) GetDownstreamAdmissionValidation(appID, clusterID string, sequence int64) (*types.Validation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDownstreamAdmissionValidation", appID, clusterID, sequence)
+	ret0, _ := ret[0].(*types.Validation)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// IsRollbackSupportedForVersion indicates an expected call of IsRollbackSupportedForVersion
-func (mr *MockStoreMockRecorder) IsRollbackSupportedForVersion(appID, sequence interface{}) *gomock.Call {
+// GetDownstreamAdmissionValidation indicates an expected call of GetDownstreamAdmissionValidation
+func (mr *MockStoreMockRecorder) GetDownstreamAdmissionValidation(appID, clusterID, sequence interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsRollbackSupportedForVersion", reflect.TypeOf((*MockStore)(nil).IsRollbackSupportedForVersion), appID, sequence)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDownstreamAdmissionValidation", reflect.TypeOf((*MockStore)(nil).GetDownstreamAdmissionValidation), appID, clusterID, sequence)
 }
 
-// IsSnapshotsSupportedForVersion mocks base method
-func (m *MockStore) IsSnapshotsSupportedForVersion(a *types3.App, sequence int64, renderer types9.Renderer) (bool, error) {
+// SetDownstreamAdmissionValidation mocks base method
+func (m *MockStore) SetDownstreamAdmissionValidation(appID, clusterID string, sequence int64, validation types.Validation) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "IsSnapshotsSupportedForVersion", a, sequence, renderer)
-	ret0, _ := ret[0].(bool)
+	ret := m.ctrl.Call(m, "SetDownstreamAdmissionValidation", appID, clusterID, sequence, validation)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetDownstreamAdmissionValidation indicates an expected call of SetDownstreamAdmissionValidation
+func (mr *MockStoreMockRecorder) SetDownstreamAdmissionValidation(appID, clusterID, sequence, validation interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetDownstreamAdmissionValidation", reflect.TypeOf((*MockStore)(nil).SetDownstreamAdmissionValidation), appID, clusterID, sequence, validation)
+}
+
+// GetDownstreamServiceAccount mocks base method
+func (m *MockStore) GetDownstreamServiceAccount(appID, clusterID string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDownstreamServiceAccount", appID, clusterID)
+	ret0, _ := ret[0].(string)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// IsSnapshotsSupportedForVersion indicates an expected call of IsSnapshotsSupportedForVersion
-func (mr *MockStoreMockRecorder) IsSnapshotsSupportedForVersion(a, sequence, renderer interface{}) *gomock.Call {
+// GetDownstreamServiceAccount indicates an expected call of GetDownstreamServiceAccount
+func (mr *MockStoreMockRecorder) GetDownstreamServiceAccount(appID, clusterID interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsSnapshotsSupportedForVersion", reflect.TypeOf((*MockStore)(nil).IsSnapshotsSupportedForVersion), a, sequence, renderer)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDownstreamServiceAccount", reflect.TypeOf((*MockStore)(nil).GetDownstreamServiceAccount), appID, clusterID)
 }
 
-// GetAppVersionArchive mocks base method
-func (m *MockStore) GetAppVersionArchive(appID string, sequence int64, dstPath string) error {
+// SetDownstreamServiceAccount mocks base method
+func (m *MockStore) SetDownstreamServiceAccount(appID, clusterID, serviceAccount string) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetAppVersionArchive", appID, sequence, dstPath)
+	ret := m.ctrl.Call(m, "SetDownstreamServiceAccount", appID, clusterID, serviceAccount)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-// GetAppVersionArchive indicates an expected call of GetAppVersionArchive
-func (mr *MockStoreMockRecorder) GetAppVersionArchive(appID, sequence, dstPath interface{}) *gomock.Call {
+// SetDownstreamServiceAccount indicates an expected call of SetDownstreamServiceAccount
+func (mr *MockStoreMockRecorder) SetDownstreamServiceAccount(appID, clusterID, serviceAccount interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAppVersionArchive", reflect.TypeOf((*MockStore)(nil).GetAppVersionArchive), appID, sequence, dstPath)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetDownstreamServiceAccount", reflect.TypeOf((*MockStore)(nil).SetDownstreamServiceAccount), appID, clusterID, serviceAccount)
 }
 
-// CreateAppVersionArchive mocks base method
-func (m *MockStore) CreateAppVersionArchive(appID string, sequence int64, archivePath string) error {
+// CreateDownstreamOutputArchive mocks base method
+func (m *MockStore) CreateDownstreamOutputArchive(appID, clusterID string, sequence int64, archivePath string) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreateAppVersionArchive", appID, sequence, archivePath)
+	ret := m.ctrl.Call(m, "CreateDownstreamOutputArchive", appID, clusterID, sequence, archivePath)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-// CreateAppVersionArchive indicates an expected call of CreateAppVersionArchive
-func (mr *MockStoreMockRecorder) CreateAppVersionArchive(appID, sequence, archivePath interface{}) *gomock.Call {
+// CreateDownstreamOutputArchive indicates an expected call of CreateDownstreamOutputArchive
+func (mr *MockStoreMockRecorder) CreateDownstreamOutputArchive(appID, clusterID, sequence, archivePath interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAppVersionArchive", reflect.TypeOf((*MockStore)(nil).CreateAppVersionArchive), appID, sequence, archivePath)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateDownstreamOutputArchive", reflect.TypeOf((*MockStore)(nil).CreateDownstreamOutputArchive), appID, clusterID, sequence, archivePath)
 }
 
-// CreateAppVersion mocks base method
-func (m *MockStore) CreateAppVersion(appID string, currentSequence *int64, filesInDir, source string, skipPreflights bool, gitops types4.DownstreamGitOps) (int64, error) {
+// GetDownstreamOutputArchive mocks base method
+func (m *MockStore) GetDownstreamOutputArchive(appID, clusterID string, sequence int64) (string, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreateAppVersion", appID, currentSequence, filesInDir, source, skipPreflights, gitops)
-	ret0, _ := ret[0].(int64)
+	ret := m.ctrl.Call(m, "GetDownstreamOutputArchive", appID, clusterID, sequence)
+	ret0, _ := ret[0].(string)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// CreateAppVersion indicates an expected call of CreateAppVersion
-func (mr *MockStoreMockRecorder) CreateAppVersion(appID, currentSequence, filesInDir, source, skipPreflights, gitops interface{}) *gomock.Call {
+// GetDownstreamOutputArchive indicates an expected call of GetDownstreamOutputArchive
+func (mr *MockStoreMockRecorder) GetDownstreamOutputArchive(appID, clusterID, sequence interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDownstreamOutputArchive", reflect.TypeOf((*MockStore)(nil).GetDownstreamOutputArchive), appID, clusterID, sequence)
+}
+
+// DeleteDownstreamOutputArchivesBefore mocks base method
+func (m *MockStore) DeleteDownstreamOutputArchivesBefore(appID, clusterID string, sequence int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteDownstreamOutputArchivesBefore", appID, clusterID, sequence)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteDownstreamOutputArchivesBefore indicates an expected call of DeleteDownstreamOutputArchivesBefore
+func (mr *MockStoreMockRecorder) DeleteDownstreamOutputArchivesBefore(appID, clusterID, sequence interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteDownstreamOutputArchivesBefore", reflect.TypeOf((*MockStore)(nil).DeleteDownstreamOutputArchivesBefore), appID, clusterID, sequence)
+}
+
+// GetActivatingSequence mocks base method
+func (m *MockStore) GetActivatingSequence(appID, clusterID string) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetActivatingSequence", appID, clusterID)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetActivatingSequence indicates an expected call of GetActivatingSequence
+func (mr *MockStoreMockRecorder) GetActivatingSequence(appID, clusterID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetActivatingSequence", reflect.TypeOf((*MockStore)(nil).GetActivatingSequence), appID, clusterID)
+}
+
+// GetActivatedSequence mocks base method
+func (m *MockStore) GetActivatedSequence(appID, clusterID string) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetActivatedSequence", appID, clusterID)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetActivatedSequence indicates an expected call of GetActivatedSequence
+func (mr *MockStoreMockRecorder) GetActivatedSequence(appID, clusterID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetActivatedSequence", reflect.TypeOf((*MockStore)(nil).GetActivatedSequence), appID, clusterID)
+}
+
+// SetDownstreamActivationResult mocks base method
+func (m *MockStore) SetDownstreamActivationResult(appID, clusterID string, sequence int64, isError bool, statusInfo string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetDownstreamActivationResult", appID, clusterID, sequence, isError, statusInfo)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetDownstreamActivationResult indicates an expected call of SetDownstreamActivationResult
+func (mr *MockStoreMockRecorder) SetDownstreamActivationResult(appID, clusterID, sequence, isError, statusInfo interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetDownstreamActivationResult", reflect.TypeOf((*MockStore)(nil).SetDownstreamActivationResult), appID, clusterID, sequence, isError, statusInfo)
+}
+
+// IsIdentityServiceSupportedForVersion mocks base method
+func (m *MockStore) IsIdentityServiceSupportedForVersion(appID string, sequence int64) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsIdentityServiceSupportedForVersion", appID, sequence)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IsIdentityServiceSupportedForVersion indicates an expected call of IsIdentityServiceSupportedForVersion
+func (mr *MockStoreMockRecorder) IsIdentityServiceSupportedForVersion(appID, sequence interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsIdentityServiceSupportedForVersion", reflect.TypeOf((*MockStore)(nil).IsIdentityServiceSupportedForVersion), appID, sequence)
+}
+
+// IsRollbackSupportedForVersion mocks base method
+func (m *MockStore) IsRollbackSupportedForVersion(appID string, sequence int64) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsRollbackSupportedForVersion", appID, sequence)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IsRollbackSupportedForVersion indicates an expected call of IsRollbackSupportedForVersion
+func (mr *MockStoreMockRecorder) IsRollbackSupportedForVersion(appID, sequence interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsRollbackSupportedForVersion", reflect.TypeOf((*MockStore)(nil).IsRollbackSupportedForVersion), appID, sequence)
+}
+
+// IsSnapshotsSupportedForVersion mocks base method
+func (m *MockStore) IsSnapshotsSupportedForVersion(a *types4.App, sequence int64, renderer types26.Renderer) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsSnapshotsSupportedForVersion", a, sequence, renderer)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IsSnapshotsSupportedForVersion indicates an expected call of IsSnapshotsSupportedForVersion
+func (mr *MockStoreMockRecorder) IsSnapshotsSupportedForVersion(a, sequence, renderer interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsSnapshotsSupportedForVersion", reflect.TypeOf((*MockStore)(nil).IsSnapshotsSupportedForVersion), a, sequence, renderer)
+}
+
+// GetAppVersionArchive mocks base method
+func (m *MockStore) GetAppVersionArchive(appID string, sequence int64, dstPath string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAppVersionArchive", appID, sequence, dstPath)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// GetAppVersionArchive indicates an expected call of GetAppVersionArchive
+func (mr *MockStoreMockRecorder) GetAppVersionArchive(appID, sequence, dstPath interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAppVersionArchive", reflect.TypeOf((*MockStore)(nil).GetAppVersionArchive), appID, sequence, dstPath)
+}
+
+// GetAppVersionArchiveReader mocks base method
+func (m *MockStore) GetAppVersionArchiveReader(appID string, sequence int64) (io.ReadCloser, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAppVersionArchiveReader", appID, sequence)
+	ret0, _ := ret[0].(io.ReadCloser)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAppVersionArchiveReader indicates an expected call of GetAppVersionArchiveReader
+func (mr *MockStoreMockRecorder) GetAppVersionArchiveReader(appID, sequence interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAppVersionArchiveReader", reflect.TypeOf((*MockStore)(nil).GetAppVersionArchiveReader), appID, sequence)
+}
+
+// CreateAppVersionArchive mocks base method
+func (m *MockStore) CreateAppVersionArchive(appID string, sequence int64, archivePath string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateAppVersionArchive", appID, sequence, archivePath)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateAppVersionArchive indicates an expected call of CreateAppVersionArchive
+func (mr *MockStoreMockRecorder) CreateAppVersionArchive(appID, sequence, archivePath interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAppVersionArchive", reflect.TypeOf((*MockStore)(nil).CreateAppVersionArchive), appID, sequence, archivePath)
+}
+
+// DeleteOrphanedAppVersionArchives mocks base method
+func (m *MockStore) DeleteOrphanedAppVersionArchives(olderThan time.Time) (int64, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteOrphanedAppVersionArchives", olderThan)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// DeleteOrphanedAppVersionArchives indicates an expected call of DeleteOrphanedAppVersionArchives
+func (mr *MockStoreMockRecorder) DeleteOrphanedAppVersionArchives(olderThan interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteOrphanedAppVersionArchives", reflect.TypeOf((*MockStore)(nil).DeleteOrphanedAppVersionArchives), olderThan)
+}
+
+// DeleteIncompleteAppVersions mocks base method
+func (m *MockStore) DeleteIncompleteAppVersions(olderThan time.Time) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteIncompleteAppVersions", olderThan)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteIncompleteAppVersions indicates an expected call of DeleteIncompleteAppVersions
+func (mr *MockStoreMockRecorder) DeleteIncompleteAppVersions(olderThan interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteIncompleteAppVersions", reflect.TypeOf((*MockStore)(nil).DeleteIncompleteAppVersions), olderThan)
+}
+
+// CreateAppVersion mocks base method
+func (m *MockStore) CreateAppVersion(appID string, currentSequence *int64, filesInDir, source string, skipPreflights bool, gitops types12.DownstreamGitOps) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateAppVersion", appID, currentSequence, filesInDir, source, skipPreflights, gitops)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateAppVersion indicates an expected call of CreateAppVersion
+func (mr *MockStoreMockRecorder) CreateAppVersion(appID, currentSequence, filesInDir, source, skipPreflights, gitops interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAppVersion", reflect.TypeOf((*MockStore)(nil).CreateAppVersion), appID, currentSequence, filesInDir, source, skipPreflights, gitops)
 }
 
 // GetAppVersion mocks base method
-func (m *MockStore) GetAppVersion(arg0 string, arg1 int64) (*types2.AppVersion, error) {
+func (m *MockStore) GetAppVersion(arg0 string, arg1 int64) (*types3.AppVersion, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "GetAppVersion", arg0, arg1)
-	ret0, _ := ret[0].(*types2.AppVersion)
+	ret0, _ := ret[0].(*types3.AppVersion)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
@@ -1143,10 +1742,10 @@ func (mr *MockStoreMockRecorder) GetAppVersion(arg0, arg1 interface{}) *gomock.C
 }
 
 // GetAppVersionsAfter mocks base method
-func (m *MockStore) GetAppVersionsAfter(arg0 string, arg1 int64) ([]*types2.AppVersion, error) {
+func (m *MockStore) GetAppVersionsAfter(arg0 string, arg1 int64) ([]*types3.AppVersion, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "GetAppVersionsAfter", arg0, arg1)
-	ret0, _ := ret[0].([]*types2.AppVersion)
+	ret0, _ := ret[0].([]*types3.AppVersion)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
@@ -1157,6 +1756,20 @@ func (mr *MockStoreMockRecorder) GetAppVersionsAfter(arg0, arg1 interface{}) *go
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAppVersionsAfter", reflect.TypeOf((*MockStore)(nil).GetAppVersionsAfter), arg0, arg1)
 }
 
+// SetAppVersionNotes mocks base method
+func (m *MockStore) SetAppVersionNotes(appID string, sequence int64, notes string, labels []string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetAppVersionNotes", appID, sequence, notes, labels)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetAppVersionNotes indicates an expected call of SetAppVersionNotes
+func (mr *MockStoreMockRecorder) SetAppVersionNotes(appID, sequence, notes, labels interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetAppVersionNotes", reflect.TypeOf((*MockStore)(nil).SetAppVersionNotes), appID, sequence, notes, labels)
+}
+
 // GetLatestLicenseForApp mocks base method
 func (m *MockStore) GetLatestLicenseForApp(appID string) (*v1beta1.License, error) {
 	m.ctrl.T.Helper()
@@ -1203,7 +1816,7 @@ func (mr *MockStoreMockRecorder) GetAllAppLicenses() *gomock.Call {
 }
 
 // UpdateAppLicense mocks base method
-func (m *MockStore) UpdateAppLicense(appID string, sequence int64, archiveDir string, newLicense *v1beta1.License, originalLicenseData string, failOnVersionCreate bool, gitops types4.DownstreamGitOps, renderer types9.Renderer) (int64, error) {
+func (m *MockStore) UpdateAppLicense(appID string, sequence int64, archiveDir string, newLicense *v1beta1.License, originalLicenseData string, failOnVersionCreate bool, gitops types12.DownstreamGitOps, renderer types26.Renderer) (int64, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "UpdateAppLicense", appID, sequence, archiveDir, newLicense, originalLicenseData, failOnVersionCreate, gitops, renderer)
 	ret0, _ := ret[0].(int64)
@@ -1217,11 +1830,25 @@ func (mr *MockStoreMockRecorder) UpdateAppLicense(appID, sequence, archiveDir, n
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateAppLicense", reflect.TypeOf((*MockStore)(nil).UpdateAppLicense), appID, sequence, archiveDir, newLicense, originalLicenseData, failOnVersionCreate, gitops, renderer)
 }
 
+// ArchiveAppLicense mocks base method
+func (m *MockStore) ArchiveAppLicense(appID, replacedByLicenseID, replacedBy string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ArchiveAppLicense", appID, replacedByLicenseID, replacedBy)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ArchiveAppLicense indicates an expected call of ArchiveAppLicense
+func (mr *MockStoreMockRecorder) ArchiveAppLicense(appID, replacedByLicenseID, replacedBy interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ArchiveAppLicense", reflect.TypeOf((*MockStore)(nil).ArchiveAppLicense), appID, replacedByLicenseID, replacedBy)
+}
+
 // ListClusters mocks base method
-func (m *MockStore) ListClusters() ([]*types1.Downstream, error) {
+func (m *MockStore) ListClusters() ([]*types2.Downstream, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "ListClusters")
-	ret0, _ := ret[0].([]*types1.Downstream)
+	ret0, _ := ret[0].([]*types2.Downstream)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
@@ -1306,10 +1933,10 @@ func (mr *MockStoreMockRecorder) SetInstanceSnapshotSchedule(clusterID, snapshot
 }
 
 // ListPendingScheduledSnapshots mocks base method
-func (m *MockStore) ListPendingScheduledSnapshots(appID string) ([]types5.ScheduledSnapshot, error) {
+func (m *MockStore) ListPendingScheduledSnapshots(appID string) ([]types14.ScheduledSnapshot, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "ListPendingScheduledSnapshots", appID)
-	ret0, _ := ret[0].([]types5.ScheduledSnapshot)
+	ret0, _ := ret[0].([]types14.ScheduledSnapshot)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
@@ -1363,10 +1990,10 @@ func (mr *MockStoreMockRecorder) CreateScheduledSnapshot(snapshotID, appID, time
 }
 
 // ListPendingScheduledInstanceSnapshots mocks base method
-func (m *MockStore) ListPendingScheduledInstanceSnapshots(clusterID string) ([]types5.ScheduledInstanceSnapshot, error) {
+func (m *MockStore) ListPendingScheduledInstanceSnapshots(clusterID string) ([]types14.ScheduledInstanceSnapshot, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "ListPendingScheduledInstanceSnapshots", clusterID)
-	ret0, _ := ret[0].([]types5.ScheduledInstanceSnapshot)
+	ret0, _ := ret[0].([]types14.ScheduledInstanceSnapshot)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
@@ -1420,10 +2047,10 @@ func (mr *MockStoreMockRecorder) CreateScheduledInstanceSnapshot(snapshotID, clu
 }
 
 // GetPendingInstallationStatus mocks base method
-func (m *MockStore) GetPendingInstallationStatus() (*types6.InstallStatus, error) {
+func (m *MockStore) GetPendingInstallationStatus() (*types19.InstallStatus, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "GetPendingInstallationStatus")
-	ret0, _ := ret[0].(*types6.InstallStatus)
+	ret0, _ := ret[0].(*types19.InstallStatus)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
@@ -1463,1848 +2090,5175 @@ func (mr *MockStoreMockRecorder) SetIsKotsadmIDGenerated() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetIsKotsadmIDGenerated", reflect.TypeOf((*MockStore)(nil).SetIsKotsadmIDGenerated))
 }
 
-// Init mocks base method
-func (m *MockStore) Init() error {
+// IsReadOnlyMode mocks base method
+func (m *MockStore) IsReadOnlyMode() (bool, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Init")
-	ret0, _ := ret[0].(error)
-	return ret0
+	ret := m.ctrl.Call(m, "IsReadOnlyMode")
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
-// Init indicates an expected call of Init
-func (mr *MockStoreMockRecorder) Init() *gomock.Call {
+// IsReadOnlyMode indicates an expected call of IsReadOnlyMode
+func (mr *MockStoreMockRecorder) IsReadOnlyMode() *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Init", reflect.TypeOf((*MockStore)(nil).Init))
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsReadOnlyMode", reflect.TypeOf((*MockStore)(nil).IsReadOnlyMode))
 }
 
-// WaitForReady mocks base method
-func (m *MockStore) WaitForReady(ctx context.Context) error {
+// SetReadOnlyMode mocks base method
+func (m *MockStore) SetReadOnlyMode(enabled bool) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "WaitForReady", ctx)
+	ret := m.ctrl.Call(m, "SetReadOnlyMode", enabled)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-// WaitForReady indicates an expected call of WaitForReady
-func (mr *MockStoreMockRecorder) WaitForReady(ctx interface{}) *gomock.Call {
+// SetReadOnlyMode indicates an expected call of SetReadOnlyMode
+func (mr *MockStoreMockRecorder) SetReadOnlyMode(enabled interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WaitForReady", reflect.TypeOf((*MockStore)(nil).WaitForReady), ctx)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetReadOnlyMode", reflect.TypeOf((*MockStore)(nil).SetReadOnlyMode), enabled)
 }
 
-// IsNotFound mocks base method
-func (m *MockStore) IsNotFound(err error) bool {
+// InitReadOnlyMode mocks base method
+func (m *MockStore) InitReadOnlyMode(enabled bool) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "IsNotFound", err)
-	ret0, _ := ret[0].(bool)
+	ret := m.ctrl.Call(m, "InitReadOnlyMode", enabled)
+	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-// IsNotFound indicates an expected call of IsNotFound
-func (mr *MockStoreMockRecorder) IsNotFound(err interface{}) *gomock.Call {
+// InitReadOnlyMode indicates an expected call of InitReadOnlyMode
+func (mr *MockStoreMockRecorder) InitReadOnlyMode(enabled interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsNotFound", reflect.TypeOf((*MockStore)(nil).IsNotFound), err)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InitReadOnlyMode", reflect.TypeOf((*MockStore)(nil).InitReadOnlyMode), enabled)
 }
 
-// MockMigrations is a mock of Migrations interface
-type MockMigrations struct {
-	ctrl     *gomock.Controller
-	recorder *MockMigrationsMockRecorder
+// SetEntitlementUsage mocks base method
+func (m *MockStore) SetEntitlementUsage(appID, name, source string, value int64, reportedAt time.Time, limit *int64) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetEntitlementUsage", appID, name, source, value, reportedAt, limit)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
-// MockMigrationsMockRecorder is the mock recorder for MockMigrations
-type MockMigrationsMockRecorder struct {
-	mock *MockMigrations
+// SetEntitlementUsage indicates an expected call of SetEntitlementUsage
+func (mr *MockStoreMockRecorder) SetEntitlementUsage(appID, name, source, value, reportedAt, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetEntitlementUsage", reflect.TypeOf((*MockStore)(nil).SetEntitlementUsage), appID, name, source, value, reportedAt, limit)
 }
 
-// NewMockMigrations creates a new mock instance
-func NewMockMigrations(ctrl *gomock.Controller) *MockMigrations {
-	mock := &MockMigrations{ctrl: ctrl}
-	mock.recorder = &MockMigrationsMockRecorder{mock}
-	return mock
+// ListEntitlementUsage mocks base method
+func (m *MockStore) ListEntitlementUsage(appID string) ([]types18.EntitlementUsage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListEntitlementUsage", appID)
+	ret0, _ := ret[0].([]types18.EntitlementUsage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
-// EXPECT returns an object that allows the caller to indicate expected use
-func (m *MockMigrations) EXPECT() *MockMigrationsMockRecorder {
-	return m.recorder
+// ListEntitlementUsage indicates an expected call of ListEntitlementUsage
+func (mr *MockStoreMockRecorder) ListEntitlementUsage(appID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListEntitlementUsage", reflect.TypeOf((*MockStore)(nil).ListEntitlementUsage), appID)
 }
 
-// RunMigrations mocks base method
-func (m *MockMigrations) RunMigrations() {
+// CreateConfigFile mocks base method
+func (m *MockStore) CreateConfigFile(checksum, filePath string) error {
 	m.ctrl.T.Helper()
-	m.ctrl.Call(m, "RunMigrations")
+	ret := m.ctrl.Call(m, "CreateConfigFile", checksum, filePath)
+	ret0, _ := ret[0].(error)
+	return ret0
 }
 
-// RunMigrations indicates an expected call of RunMigrations
-func (mr *MockMigrationsMockRecorder) RunMigrations() *gomock.Call {
+// CreateConfigFile indicates an expected call of CreateConfigFile
+func (mr *MockStoreMockRecorder) CreateConfigFile(checksum, filePath interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RunMigrations", reflect.TypeOf((*MockMigrations)(nil).RunMigrations))
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateConfigFile", reflect.TypeOf((*MockStore)(nil).CreateConfigFile), checksum, filePath)
 }
 
-// MockRegistryStore is a mock of RegistryStore interface
-type MockRegistryStore struct {
-	ctrl     *gomock.Controller
-	recorder *MockRegistryStoreMockRecorder
+// GetConfigFile mocks base method
+func (m *MockStore) GetConfigFile(checksum string) (io.ReadCloser, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetConfigFile", checksum)
+	ret0, _ := ret[0].(io.ReadCloser)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
-// MockRegistryStoreMockRecorder is the mock recorder for MockRegistryStore
-type MockRegistryStoreMockRecorder struct {
-	mock *MockRegistryStore
+// GetConfigFile indicates an expected call of GetConfigFile
+func (mr *MockStoreMockRecorder) GetConfigFile(checksum interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetConfigFile", reflect.TypeOf((*MockStore)(nil).GetConfigFile), checksum)
 }
 
-// NewMockRegistryStore creates a new mock instance
-func NewMockRegistryStore(ctrl *gomock.Controller) *MockRegistryStore {
-	mock := &MockRegistryStore{ctrl: ctrl}
-	mock.recorder = &MockRegistryStoreMockRecorder{mock}
-	return mock
+// SetUpdateDownloadFailure mocks base method
+func (m *MockStore) SetUpdateDownloadFailure(appID string, failure types32.UpdateDownloadFailure) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetUpdateDownloadFailure", appID, failure)
+	ret0, _ := ret[0].(error)
+	return ret0
 }
 
-// EXPECT returns an object that allows the caller to indicate expected use
-func (m *MockRegistryStore) EXPECT() *MockRegistryStoreMockRecorder {
-	return m.recorder
+// SetUpdateDownloadFailure indicates an expected call of SetUpdateDownloadFailure
+func (mr *MockStoreMockRecorder) SetUpdateDownloadFailure(appID, failure interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetUpdateDownloadFailure", reflect.TypeOf((*MockStore)(nil).SetUpdateDownloadFailure), appID, failure)
 }
 
-// GetRegistryDetailsForApp mocks base method
-func (m *MockRegistryStore) GetRegistryDetailsForApp(appID string) (types8.RegistrySettings, error) {
+// ListUpdateDownloadFailures mocks base method
+func (m *MockStore) ListUpdateDownloadFailures(appID string) ([]types32.UpdateDownloadFailure, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetRegistryDetailsForApp", appID)
-	ret0, _ := ret[0].(types8.RegistrySettings)
+	ret := m.ctrl.Call(m, "ListUpdateDownloadFailures", appID)
+	ret0, _ := ret[0].([]types32.UpdateDownloadFailure)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetRegistryDetailsForApp indicates an expected call of GetRegistryDetailsForApp
-func (mr *MockRegistryStoreMockRecorder) GetRegistryDetailsForApp(appID interface{}) *gomock.Call {
+// ListUpdateDownloadFailures indicates an expected call of ListUpdateDownloadFailures
+func (mr *MockStoreMockRecorder) ListUpdateDownloadFailures(appID interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRegistryDetailsForApp", reflect.TypeOf((*MockRegistryStore)(nil).GetRegistryDetailsForApp), appID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListUpdateDownloadFailures", reflect.TypeOf((*MockStore)(nil).ListUpdateDownloadFailures), appID)
 }
 
-// UpdateRegistry mocks base method
-func (m *MockRegistryStore) UpdateRegistry(appID, hostname, username, password, namespace string, isReadOnly bool) error {
+// ClearUpdateDownloadFailures mocks base method
+func (m *MockStore) ClearUpdateDownloadFailures(appID string) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "UpdateRegistry", appID, hostname, username, password, namespace, isReadOnly)
+	ret := m.ctrl.Call(m, "ClearUpdateDownloadFailures", appID)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-// UpdateRegistry indicates an expected call of UpdateRegistry
-func (mr *MockRegistryStoreMockRecorder) UpdateRegistry(appID, hostname, username, password, namespace, isReadOnly interface{}) *gomock.Call {
+// ClearUpdateDownloadFailures indicates an expected call of ClearUpdateDownloadFailures
+func (mr *MockStoreMockRecorder) ClearUpdateDownloadFailures(appID interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateRegistry", reflect.TypeOf((*MockRegistryStore)(nil).UpdateRegistry), appID, hostname, username, password, namespace, isReadOnly)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClearUpdateDownloadFailures", reflect.TypeOf((*MockStore)(nil).ClearUpdateDownloadFailures), appID)
 }
 
-// MockSupportBundleStore is a mock of SupportBundleStore interface
-type MockSupportBundleStore struct {
-	ctrl     *gomock.Controller
-	recorder *MockSupportBundleStoreMockRecorder
+// GetImageReport mocks base method
+func (m *MockStore) GetImageReport(appID string, sequence int64) (*types13.Report, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetImageReport", appID, sequence)
+	ret0, _ := ret[0].(*types13.Report)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
-// MockSupportBundleStoreMockRecorder is the mock recorder for MockSupportBundleStore
-type MockSupportBundleStoreMockRecorder struct {
-	mock *MockSupportBundleStore
+// GetImageReport indicates an expected call of GetImageReport
+func (mr *MockStoreMockRecorder) GetImageReport(appID, sequence interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetImageReport", reflect.TypeOf((*MockStore)(nil).GetImageReport), appID, sequence)
 }
 
-// NewMockSupportBundleStore creates a new mock instance
-func NewMockSupportBundleStore(ctrl *gomock.Controller) *MockSupportBundleStore {
-	mock := &MockSupportBundleStore{ctrl: ctrl}
-	mock.recorder = &MockSupportBundleStoreMockRecorder{mock}
-	return mock
+// SetImageReport mocks base method
+func (m *MockStore) SetImageReport(appID string, report types13.Report) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetImageReport", appID, report)
+	ret0, _ := ret[0].(error)
+	return ret0
 }
 
-// EXPECT returns an object that allows the caller to indicate expected use
-func (m *MockSupportBundleStore) EXPECT() *MockSupportBundleStoreMockRecorder {
-	return m.recorder
+// SetImageReport indicates an expected call of SetImageReport
+func (mr *MockStoreMockRecorder) SetImageReport(appID, report interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetImageReport", reflect.TypeOf((*MockStore)(nil).SetImageReport), appID, report)
 }
 
-// ListSupportBundles mocks base method
-func (m *MockSupportBundleStore) ListSupportBundles(appID string) ([]*types11.SupportBundle, error) {
+// GetGlobalMaintenanceMessage mocks base method
+func (m *MockStore) GetGlobalMaintenanceMessage() (*types17.MaintenanceMessage, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ListSupportBundles", appID)
-	ret0, _ := ret[0].([]*types11.SupportBundle)
+	ret := m.ctrl.Call(m, "GetGlobalMaintenanceMessage")
+	ret0, _ := ret[0].(*types17.MaintenanceMessage)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// ListSupportBundles indicates an expected call of ListSupportBundles
-func (mr *MockSupportBundleStoreMockRecorder) ListSupportBundles(appID interface{}) *gomock.Call {
+// GetGlobalMaintenanceMessage indicates an expected call of GetGlobalMaintenanceMessage
+func (mr *MockStoreMockRecorder) GetGlobalMaintenanceMessage() *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListSupportBundles", reflect.TypeOf((*MockSupportBundleStore)(nil).ListSupportBundles), appID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetGlobalMaintenanceMessage", reflect.TypeOf((*MockStore)(nil).GetGlobalMaintenanceMessage))
 }
 
-// GetSupportBundle mocks base method
-func (m *MockSupportBundleStore) GetSupportBundle(bundleID string) (*types11.SupportBundle, error) {
+// SetGlobalMaintenanceMessage mocks base method
+func (m *MockStore) SetGlobalMaintenanceMessage(message *types17.MaintenanceMessage) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetSupportBundle", bundleID)
-	ret0, _ := ret[0].(*types11.SupportBundle)
+	ret := m.ctrl.Call(m, "SetGlobalMaintenanceMessage", message)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetGlobalMaintenanceMessage indicates an expected call of SetGlobalMaintenanceMessage
+func (mr *MockStoreMockRecorder) SetGlobalMaintenanceMessage(message interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetGlobalMaintenanceMessage", reflect.TypeOf((*MockStore)(nil).SetGlobalMaintenanceMessage), message)
+}
+
+// GetAppMaintenanceMessage mocks base method
+func (m *MockStore) GetAppMaintenanceMessage(appID string) (*types17.MaintenanceMessage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAppMaintenanceMessage", appID)
+	ret0, _ := ret[0].(*types17.MaintenanceMessage)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetSupportBundle indicates an expected call of GetSupportBundle
-func (mr *MockSupportBundleStoreMockRecorder) GetSupportBundle(bundleID interface{}) *gomock.Call {
+// GetAppMaintenanceMessage indicates an expected call of GetAppMaintenanceMessage
+func (mr *MockStoreMockRecorder) GetAppMaintenanceMessage(appID interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSupportBundle", reflect.TypeOf((*MockSupportBundleStore)(nil).GetSupportBundle), bundleID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAppMaintenanceMessage", reflect.TypeOf((*MockStore)(nil).GetAppMaintenanceMessage), appID)
 }
 
-// CreateSupportBundle mocks base method
-func (m *MockSupportBundleStore) CreateSupportBundle(bundleID, appID, archivePath string, marshalledTree []byte) (*types11.SupportBundle, error) {
+// SetAppMaintenanceMessage mocks base method
+func (m *MockStore) SetAppMaintenanceMessage(appID string, message *types17.MaintenanceMessage) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreateSupportBundle", bundleID, appID, archivePath, marshalledTree)
-	ret0, _ := ret[0].(*types11.SupportBundle)
+	ret := m.ctrl.Call(m, "SetAppMaintenanceMessage", appID, message)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetAppMaintenanceMessage indicates an expected call of SetAppMaintenanceMessage
+func (mr *MockStoreMockRecorder) SetAppMaintenanceMessage(appID, message interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetAppMaintenanceMessage", reflect.TypeOf((*MockStore)(nil).SetAppMaintenanceMessage), appID, message)
+}
+
+// CreateDeployApproval mocks base method
+func (m *MockStore) CreateDeployApproval(approval types8.DeployApproval) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateDeployApproval", approval)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateDeployApproval indicates an expected call of CreateDeployApproval
+func (mr *MockStoreMockRecorder) CreateDeployApproval(approval interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateDeployApproval", reflect.TypeOf((*MockStore)(nil).CreateDeployApproval), approval)
+}
+
+// GetDeployApproval mocks base method
+func (m *MockStore) GetDeployApproval(approvalID string) (*types8.DeployApproval, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDeployApproval", approvalID)
+	ret0, _ := ret[0].(*types8.DeployApproval)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// CreateSupportBundle indicates an expected call of CreateSupportBundle
-func (mr *MockSupportBundleStoreMockRecorder) CreateSupportBundle(bundleID, appID, archivePath, marshalledTree interface{}) *gomock.Call {
+// GetDeployApproval indicates an expected call of GetDeployApproval
+func (mr *MockStoreMockRecorder) GetDeployApproval(approvalID interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateSupportBundle", reflect.TypeOf((*MockSupportBundleStore)(nil).CreateSupportBundle), bundleID, appID, archivePath, marshalledTree)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDeployApproval", reflect.TypeOf((*MockStore)(nil).GetDeployApproval), approvalID)
 }
 
-// GetSupportBundleArchive mocks base method
-func (m *MockSupportBundleStore) GetSupportBundleArchive(bundleID string) (string, error) {
+// GetPendingDeployApproval mocks base method
+func (m *MockStore) GetPendingDeployApproval(appID string, sequence int64) (*types8.DeployApproval, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetSupportBundleArchive", bundleID)
-	ret0, _ := ret[0].(string)
+	ret := m.ctrl.Call(m, "GetPendingDeployApproval", appID, sequence)
+	ret0, _ := ret[0].(*types8.DeployApproval)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetSupportBundleArchive indicates an expected call of GetSupportBundleArchive
-func (mr *MockSupportBundleStoreMockRecorder) GetSupportBundleArchive(bundleID interface{}) *gomock.Call {
+// GetPendingDeployApproval indicates an expected call of GetPendingDeployApproval
+func (mr *MockStoreMockRecorder) GetPendingDeployApproval(appID, sequence interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSupportBundleArchive", reflect.TypeOf((*MockSupportBundleStore)(nil).GetSupportBundleArchive), bundleID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPendingDeployApproval", reflect.TypeOf((*MockStore)(nil).GetPendingDeployApproval), appID, sequence)
 }
 
-// GetSupportBundleAnalysis mocks base method
-func (m *MockSupportBundleStore) GetSupportBundleAnalysis(bundleID string) (*types11.SupportBundleAnalysis, error) {
+// ListDeployApprovals mocks base method
+func (m *MockStore) ListDeployApprovals(appID string) ([]types8.DeployApproval, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetSupportBundleAnalysis", bundleID)
-	ret0, _ := ret[0].(*types11.SupportBundleAnalysis)
+	ret := m.ctrl.Call(m, "ListDeployApprovals", appID)
+	ret0, _ := ret[0].([]types8.DeployApproval)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetSupportBundleAnalysis indicates an expected call of GetSupportBundleAnalysis
-func (mr *MockSupportBundleStoreMockRecorder) GetSupportBundleAnalysis(bundleID interface{}) *gomock.Call {
+// ListDeployApprovals indicates an expected call of ListDeployApprovals
+func (mr *MockStoreMockRecorder) ListDeployApprovals(appID interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSupportBundleAnalysis", reflect.TypeOf((*MockSupportBundleStore)(nil).GetSupportBundleAnalysis), bundleID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListDeployApprovals", reflect.TypeOf((*MockStore)(nil).ListDeployApprovals), appID)
 }
 
-// SetSupportBundleAnalysis mocks base method
-func (m *MockSupportBundleStore) SetSupportBundleAnalysis(bundleID string, insights []byte) error {
+// SetDeployApprovalDecision mocks base method
+func (m *MockStore) SetDeployApprovalDecision(approvalID string, status types8.Status, decidedBy string, decidedAt time.Time) (bool, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "SetSupportBundleAnalysis", bundleID, insights)
+	ret := m.ctrl.Call(m, "SetDeployApprovalDecision", approvalID, status, decidedBy, decidedAt)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetDeployApprovalDecision indicates an expected call of SetDeployApprovalDecision
+func (mr *MockStoreMockRecorder) SetDeployApprovalDecision(approvalID, status, decidedBy, decidedAt interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetDeployApprovalDecision", reflect.TypeOf((*MockStore)(nil).SetDeployApprovalDecision), approvalID, status, decidedBy, decidedAt)
+}
+
+// ReopenDeployApproval mocks base method
+func (m *MockStore) ReopenDeployApproval(approvalID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReopenDeployApproval", approvalID)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-// SetSupportBundleAnalysis indicates an expected call of SetSupportBundleAnalysis
-func (mr *MockSupportBundleStoreMockRecorder) SetSupportBundleAnalysis(bundleID, insights interface{}) *gomock.Call {
+// ReopenDeployApproval indicates an expected call of ReopenDeployApproval
+func (mr *MockStoreMockRecorder) ReopenDeployApproval(approvalID interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetSupportBundleAnalysis", reflect.TypeOf((*MockSupportBundleStore)(nil).SetSupportBundleAnalysis), bundleID, insights)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReopenDeployApproval", reflect.TypeOf((*MockStore)(nil).ReopenDeployApproval), approvalID)
 }
 
-// GetRedactions mocks base method
-func (m *MockSupportBundleStore) GetRedactions(bundleID string) (redact.RedactionList, error) {
+// AcquireAppLock mocks base method
+func (m *MockStore) AcquireAppLock(appID, holder string, expiresAt time.Time) (bool, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetRedactions", bundleID)
-	ret0, _ := ret[0].(redact.RedactionList)
+	ret := m.ctrl.Call(m, "AcquireAppLock", appID, holder, expiresAt)
+	ret0, _ := ret[0].(bool)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetRedactions indicates an expected call of GetRedactions
-func (mr *MockSupportBundleStoreMockRecorder) GetRedactions(bundleID interface{}) *gomock.Call {
+// AcquireAppLock indicates an expected call of AcquireAppLock
+func (mr *MockStoreMockRecorder) AcquireAppLock(appID, holder, expiresAt interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRedactions", reflect.TypeOf((*MockSupportBundleStore)(nil).GetRedactions), bundleID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AcquireAppLock", reflect.TypeOf((*MockStore)(nil).AcquireAppLock), appID, holder, expiresAt)
 }
 
-// SetRedactions mocks base method
-func (m *MockSupportBundleStore) SetRedactions(bundleID string, redacts redact.RedactionList) error {
+// RefreshAppLock mocks base method
+func (m *MockStore) RefreshAppLock(appID, holder string, expiresAt time.Time) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "SetRedactions", bundleID, redacts)
+	ret := m.ctrl.Call(m, "RefreshAppLock", appID, holder, expiresAt)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-// SetRedactions indicates an expected call of SetRedactions
-func (mr *MockSupportBundleStoreMockRecorder) SetRedactions(bundleID, redacts interface{}) *gomock.Call {
+// RefreshAppLock indicates an expected call of RefreshAppLock
+func (mr *MockStoreMockRecorder) RefreshAppLock(appID, holder, expiresAt interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetRedactions", reflect.TypeOf((*MockSupportBundleStore)(nil).SetRedactions), bundleID, redacts)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RefreshAppLock", reflect.TypeOf((*MockStore)(nil).RefreshAppLock), appID, holder, expiresAt)
 }
 
-// CreateInProgressSupportBundle mocks base method
-func (m *MockSupportBundleStore) CreateInProgressSupportBundle(supportBundle *types11.SupportBundle) error {
+// ReleaseAppLock mocks base method
+func (m *MockStore) ReleaseAppLock(appID, holder string) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreateInProgressSupportBundle", supportBundle)
+	ret := m.ctrl.Call(m, "ReleaseAppLock", appID, holder)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-// CreateInProgressSupportBundle indicates an expected call of CreateInProgressSupportBundle
-func (mr *MockSupportBundleStoreMockRecorder) CreateInProgressSupportBundle(supportBundle interface{}) *gomock.Call {
+// ReleaseAppLock indicates an expected call of ReleaseAppLock
+func (mr *MockStoreMockRecorder) ReleaseAppLock(appID, holder interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateInProgressSupportBundle", reflect.TypeOf((*MockSupportBundleStore)(nil).CreateInProgressSupportBundle), supportBundle)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReleaseAppLock", reflect.TypeOf((*MockStore)(nil).ReleaseAppLock), appID, holder)
 }
 
-// UpdateSupportBundle mocks base method
-func (m *MockSupportBundleStore) UpdateSupportBundle(bundle *types11.SupportBundle) error {
+// GetUploadQuota mocks base method
+func (m *MockStore) GetUploadQuota() (*types33.UploadQuota, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "UpdateSupportBundle", bundle)
-	ret0, _ := ret[0].(error)
-	return ret0
+	ret := m.ctrl.Call(m, "GetUploadQuota")
+	ret0, _ := ret[0].(*types33.UploadQuota)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
-// UpdateSupportBundle indicates an expected call of UpdateSupportBundle
-func (mr *MockSupportBundleStoreMockRecorder) UpdateSupportBundle(bundle interface{}) *gomock.Call {
+// GetUploadQuota indicates an expected call of GetUploadQuota
+func (mr *MockStoreMockRecorder) GetUploadQuota() *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateSupportBundle", reflect.TypeOf((*MockSupportBundleStore)(nil).UpdateSupportBundle), bundle)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUploadQuota", reflect.TypeOf((*MockStore)(nil).GetUploadQuota))
 }
 
-// UploadSupportBundle mocks base method
-func (m *MockSupportBundleStore) UploadSupportBundle(bundleID, archivePath string, marshalledTree []byte) error {
+// SetUploadQuota mocks base method
+func (m *MockStore) SetUploadQuota(quota types33.UploadQuota) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "UploadSupportBundle", bundleID, archivePath, marshalledTree)
+	ret := m.ctrl.Call(m, "SetUploadQuota", quota)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-// UploadSupportBundle indicates an expected call of UploadSupportBundle
-func (mr *MockSupportBundleStoreMockRecorder) UploadSupportBundle(bundleID, archivePath, marshalledTree interface{}) *gomock.Call {
+// SetUploadQuota indicates an expected call of SetUploadQuota
+func (mr *MockStoreMockRecorder) SetUploadQuota(quota interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UploadSupportBundle", reflect.TypeOf((*MockSupportBundleStore)(nil).UploadSupportBundle), bundleID, archivePath, marshalledTree)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetUploadQuota", reflect.TypeOf((*MockStore)(nil).SetUploadQuota), quota)
 }
 
-// MockPreflightStore is a mock of PreflightStore interface
-type MockPreflightStore struct {
-	ctrl     *gomock.Controller
-	recorder *MockPreflightStoreMockRecorder
+// GetSessionSettings mocks base method
+func (m *MockStore) GetSessionSettings() (*types29.SessionSettings, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSessionSettings")
+	ret0, _ := ret[0].(*types29.SessionSettings)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
-// MockPreflightStoreMockRecorder is the mock recorder for MockPreflightStore
-type MockPreflightStoreMockRecorder struct {
-	mock *MockPreflightStore
+// GetSessionSettings indicates an expected call of GetSessionSettings
+func (mr *MockStoreMockRecorder) GetSessionSettings() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSessionSettings", reflect.TypeOf((*MockStore)(nil).GetSessionSettings))
 }
 
-// NewMockPreflightStore creates a new mock instance
-func NewMockPreflightStore(ctrl *gomock.Controller) *MockPreflightStore {
-	mock := &MockPreflightStore{ctrl: ctrl}
-	mock.recorder = &MockPreflightStoreMockRecorder{mock}
-	return mock
+// SetSessionSettings mocks base method
+func (m *MockStore) SetSessionSettings(settings types29.SessionSettings) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetSessionSettings", settings)
+	ret0, _ := ret[0].(error)
+	return ret0
 }
 
-// EXPECT returns an object that allows the caller to indicate expected use
-func (m *MockPreflightStore) EXPECT() *MockPreflightStoreMockRecorder {
-	return m.recorder
+// SetSessionSettings indicates an expected call of SetSessionSettings
+func (mr *MockStoreMockRecorder) SetSessionSettings(settings interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetSessionSettings", reflect.TypeOf((*MockStore)(nil).SetSessionSettings), settings)
 }
 
-// SetPreflightProgress mocks base method
-func (m *MockPreflightStore) SetPreflightProgress(appID string, sequence int64, progress string) error {
+// InitSessionSettings mocks base method
+func (m *MockStore) InitSessionSettings(settings types29.SessionSettings) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "SetPreflightProgress", appID, sequence, progress)
+	ret := m.ctrl.Call(m, "InitSessionSettings", settings)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-// SetPreflightProgress indicates an expected call of SetPreflightProgress
-func (mr *MockPreflightStoreMockRecorder) SetPreflightProgress(appID, sequence, progress interface{}) *gomock.Call {
+// InitSessionSettings indicates an expected call of InitSessionSettings
+func (mr *MockStoreMockRecorder) InitSessionSettings(settings interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetPreflightProgress", reflect.TypeOf((*MockPreflightStore)(nil).SetPreflightProgress), appID, sequence, progress)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InitSessionSettings", reflect.TypeOf((*MockStore)(nil).InitSessionSettings), settings)
 }
 
-// GetPreflightProgress mocks base method
-func (m *MockPreflightStore) GetPreflightProgress(appID string, sequence int64) (string, error) {
+// GetLDAPSettings mocks base method
+func (m *MockStore) GetLDAPSettings() (*types15.Settings, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetPreflightProgress", appID, sequence)
-	ret0, _ := ret[0].(string)
+	ret := m.ctrl.Call(m, "GetLDAPSettings")
+	ret0, _ := ret[0].(*types15.Settings)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetPreflightProgress indicates an expected call of GetPreflightProgress
-func (mr *MockPreflightStoreMockRecorder) GetPreflightProgress(appID, sequence interface{}) *gomock.Call {
+// GetLDAPSettings indicates an expected call of GetLDAPSettings
+func (mr *MockStoreMockRecorder) GetLDAPSettings() *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPreflightProgress", reflect.TypeOf((*MockPreflightStore)(nil).GetPreflightProgress), appID, sequence)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLDAPSettings", reflect.TypeOf((*MockStore)(nil).GetLDAPSettings))
 }
 
-// SetPreflightResults mocks base method
-func (m *MockPreflightStore) SetPreflightResults(appID string, sequence int64, results []byte) error {
+// SetLDAPSettings mocks base method
+func (m *MockStore) SetLDAPSettings(settings types15.Settings) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "SetPreflightResults", appID, sequence, results)
+	ret := m.ctrl.Call(m, "SetLDAPSettings", settings)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-// SetPreflightResults indicates an expected call of SetPreflightResults
-func (mr *MockPreflightStoreMockRecorder) SetPreflightResults(appID, sequence, results interface{}) *gomock.Call {
+// SetLDAPSettings indicates an expected call of SetLDAPSettings
+func (mr *MockStoreMockRecorder) SetLDAPSettings(settings interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetPreflightResults", reflect.TypeOf((*MockPreflightStore)(nil).SetPreflightResults), appID, sequence, results)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetLDAPSettings", reflect.TypeOf((*MockStore)(nil).SetLDAPSettings), settings)
 }
 
-// GetPreflightResults mocks base method
-func (m *MockPreflightStore) GetPreflightResults(appID string, sequence int64) (*types7.PreflightResult, error) {
+// ListRestoreDrills mocks base method
+func (m *MockStore) ListRestoreDrills(appID string) ([]types27.Drill, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetPreflightResults", appID, sequence)
-	ret0, _ := ret[0].(*types7.PreflightResult)
+	ret := m.ctrl.Call(m, "ListRestoreDrills", appID)
+	ret0, _ := ret[0].([]types27.Drill)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetPreflightResults indicates an expected call of GetPreflightResults
-func (mr *MockPreflightStoreMockRecorder) GetPreflightResults(appID, sequence interface{}) *gomock.Call {
+// ListRestoreDrills indicates an expected call of ListRestoreDrills
+func (mr *MockStoreMockRecorder) ListRestoreDrills(appID interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPreflightResults", reflect.TypeOf((*MockPreflightStore)(nil).GetPreflightResults), appID, sequence)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListRestoreDrills", reflect.TypeOf((*MockStore)(nil).ListRestoreDrills), appID)
 }
 
-// ResetPreflightResults mocks base method
-func (m *MockPreflightStore) ResetPreflightResults(appID string, sequence int64) error {
+// CreateRestoreDrill mocks base method
+func (m *MockStore) CreateRestoreDrill(drill types27.Drill) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ResetPreflightResults", appID, sequence)
+	ret := m.ctrl.Call(m, "CreateRestoreDrill", drill)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-// ResetPreflightResults indicates an expected call of ResetPreflightResults
-func (mr *MockPreflightStoreMockRecorder) ResetPreflightResults(appID, sequence interface{}) *gomock.Call {
+// CreateRestoreDrill indicates an expected call of CreateRestoreDrill
+func (mr *MockStoreMockRecorder) CreateRestoreDrill(drill interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResetPreflightResults", reflect.TypeOf((*MockPreflightStore)(nil).ResetPreflightResults), appID, sequence)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateRestoreDrill", reflect.TypeOf((*MockStore)(nil).CreateRestoreDrill), drill)
 }
 
-// SetIgnorePreflightPermissionErrors mocks base method
-func (m *MockPreflightStore) SetIgnorePreflightPermissionErrors(appID string, sequence int64) error {
+// UpdateRestoreDrill mocks base method
+func (m *MockStore) UpdateRestoreDrill(drill types27.Drill) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "SetIgnorePreflightPermissionErrors", appID, sequence)
+	ret := m.ctrl.Call(m, "UpdateRestoreDrill", drill)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-// SetIgnorePreflightPermissionErrors indicates an expected call of SetIgnorePreflightPermissionErrors
-func (mr *MockPreflightStoreMockRecorder) SetIgnorePreflightPermissionErrors(appID, sequence interface{}) *gomock.Call {
+// UpdateRestoreDrill indicates an expected call of UpdateRestoreDrill
+func (mr *MockStoreMockRecorder) UpdateRestoreDrill(drill interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetIgnorePreflightPermissionErrors", reflect.TypeOf((*MockPreflightStore)(nil).SetIgnorePreflightPermissionErrors), appID, sequence)
-}
-
-// MockPrometheusStore is a mock of PrometheusStore interface
-type MockPrometheusStore struct {
-	ctrl     *gomock.Controller
-	recorder *MockPrometheusStoreMockRecorder
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateRestoreDrill", reflect.TypeOf((*MockStore)(nil).UpdateRestoreDrill), drill)
 }
 
-// MockPrometheusStoreMockRecorder is the mock recorder for MockPrometheusStore
-type MockPrometheusStoreMockRecorder struct {
-	mock *MockPrometheusStore
-}
-
-// NewMockPrometheusStore creates a new mock instance
-func NewMockPrometheusStore(ctrl *gomock.Controller) *MockPrometheusStore {
-	mock := &MockPrometheusStore{ctrl: ctrl}
-	mock.recorder = &MockPrometheusStoreMockRecorder{mock}
-	return mock
+// DeleteScheduledRestoreDrills mocks base method
+func (m *MockStore) DeleteScheduledRestoreDrills(appID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteScheduledRestoreDrills", appID)
+	ret0, _ := ret[0].(error)
+	return ret0
 }
 
-// EXPECT returns an object that allows the caller to indicate expected use
-func (m *MockPrometheusStore) EXPECT() *MockPrometheusStoreMockRecorder {
-	return m.recorder
+// DeleteScheduledRestoreDrills indicates an expected call of DeleteScheduledRestoreDrills
+func (mr *MockStoreMockRecorder) DeleteScheduledRestoreDrills(appID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteScheduledRestoreDrills", reflect.TypeOf((*MockStore)(nil).DeleteScheduledRestoreDrills), appID)
 }
 
-// GetPrometheusAddress mocks base method
-func (m *MockPrometheusStore) GetPrometheusAddress() (string, error) {
+// ListCanaryDeploys mocks base method
+func (m *MockStore) ListCanaryDeploys(appID string) ([]types7.Deploy, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetPrometheusAddress")
-	ret0, _ := ret[0].(string)
+	ret := m.ctrl.Call(m, "ListCanaryDeploys", appID)
+	ret0, _ := ret[0].([]types7.Deploy)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetPrometheusAddress indicates an expected call of GetPrometheusAddress
-func (mr *MockPrometheusStoreMockRecorder) GetPrometheusAddress() *gomock.Call {
+// ListCanaryDeploys indicates an expected call of ListCanaryDeploys
+func (mr *MockStoreMockRecorder) ListCanaryDeploys(appID interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPrometheusAddress", reflect.TypeOf((*MockPrometheusStore)(nil).GetPrometheusAddress))
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListCanaryDeploys", reflect.TypeOf((*MockStore)(nil).ListCanaryDeploys), appID)
 }
 
-// SetPrometheusAddress mocks base method
-func (m *MockPrometheusStore) SetPrometheusAddress(address string) error {
+// CreateCanaryDeploy mocks base method
+func (m *MockStore) CreateCanaryDeploy(deploy types7.Deploy) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "SetPrometheusAddress", address)
+	ret := m.ctrl.Call(m, "CreateCanaryDeploy", deploy)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-// SetPrometheusAddress indicates an expected call of SetPrometheusAddress
-func (mr *MockPrometheusStoreMockRecorder) SetPrometheusAddress(address interface{}) *gomock.Call {
+// CreateCanaryDeploy indicates an expected call of CreateCanaryDeploy
+func (mr *MockStoreMockRecorder) CreateCanaryDeploy(deploy interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetPrometheusAddress", reflect.TypeOf((*MockPrometheusStore)(nil).SetPrometheusAddress), address)
-}
-
-// MockAirgapStore is a mock of AirgapStore interface
-type MockAirgapStore struct {
-	ctrl     *gomock.Controller
-	recorder *MockAirgapStoreMockRecorder
-}
-
-// MockAirgapStoreMockRecorder is the mock recorder for MockAirgapStore
-type MockAirgapStoreMockRecorder struct {
-	mock *MockAirgapStore
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateCanaryDeploy", reflect.TypeOf((*MockStore)(nil).CreateCanaryDeploy), deploy)
 }
 
-// NewMockAirgapStore creates a new mock instance
-func NewMockAirgapStore(ctrl *gomock.Controller) *MockAirgapStore {
-	mock := &MockAirgapStore{ctrl: ctrl}
-	mock.recorder = &MockAirgapStoreMockRecorder{mock}
-	return mock
+// UpdateCanaryDeploy mocks base method
+func (m *MockStore) UpdateCanaryDeploy(deploy types7.Deploy) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateCanaryDeploy", deploy)
+	ret0, _ := ret[0].(error)
+	return ret0
 }
 
-// EXPECT returns an object that allows the caller to indicate expected use
-func (m *MockAirgapStore) EXPECT() *MockAirgapStoreMockRecorder {
-	return m.recorder
+// UpdateCanaryDeploy indicates an expected call of UpdateCanaryDeploy
+func (mr *MockStoreMockRecorder) UpdateCanaryDeploy(deploy interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateCanaryDeploy", reflect.TypeOf((*MockStore)(nil).UpdateCanaryDeploy), deploy)
 }
 
-// GetPendingAirgapUploadApp mocks base method
-func (m *MockAirgapStore) GetPendingAirgapUploadApp() (*types.PendingApp, error) {
+// ListRemoteInstalls mocks base method
+func (m *MockStore) ListRemoteInstalls() ([]types25.RemoteInstall, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetPendingAirgapUploadApp")
-	ret0, _ := ret[0].(*types.PendingApp)
+	ret := m.ctrl.Call(m, "ListRemoteInstalls")
+	ret0, _ := ret[0].([]types25.RemoteInstall)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetPendingAirgapUploadApp indicates an expected call of GetPendingAirgapUploadApp
-func (mr *MockAirgapStoreMockRecorder) GetPendingAirgapUploadApp() *gomock.Call {
+// ListRemoteInstalls indicates an expected call of ListRemoteInstalls
+func (mr *MockStoreMockRecorder) ListRemoteInstalls() *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPendingAirgapUploadApp", reflect.TypeOf((*MockAirgapStore)(nil).GetPendingAirgapUploadApp))
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListRemoteInstalls", reflect.TypeOf((*MockStore)(nil).ListRemoteInstalls))
 }
 
-// GetAirgapInstallStatus mocks base method
-func (m *MockAirgapStore) GetAirgapInstallStatus(appID string) (*types.InstallStatus, error) {
+// GetRemoteInstall mocks base method
+func (m *MockStore) GetRemoteInstall(id string) (*types25.RemoteInstall, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetAirgapInstallStatus", appID)
-	ret0, _ := ret[0].(*types.InstallStatus)
+	ret := m.ctrl.Call(m, "GetRemoteInstall", id)
+	ret0, _ := ret[0].(*types25.RemoteInstall)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetAirgapInstallStatus indicates an expected call of GetAirgapInstallStatus
-func (mr *MockAirgapStoreMockRecorder) GetAirgapInstallStatus(appID interface{}) *gomock.Call {
+// GetRemoteInstall indicates an expected call of GetRemoteInstall
+func (mr *MockStoreMockRecorder) GetRemoteInstall(id interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAirgapInstallStatus", reflect.TypeOf((*MockAirgapStore)(nil).GetAirgapInstallStatus), appID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRemoteInstall", reflect.TypeOf((*MockStore)(nil).GetRemoteInstall), id)
 }
 
-// ResetAirgapInstallInProgress mocks base method
-func (m *MockAirgapStore) ResetAirgapInstallInProgress(appID string) error {
+// CreateRemoteInstall mocks base method
+func (m *MockStore) CreateRemoteInstall(remoteInstall types25.RemoteInstall) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ResetAirgapInstallInProgress", appID)
+	ret := m.ctrl.Call(m, "CreateRemoteInstall", remoteInstall)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-// ResetAirgapInstallInProgress indicates an expected call of ResetAirgapInstallInProgress
-func (mr *MockAirgapStoreMockRecorder) ResetAirgapInstallInProgress(appID interface{}) *gomock.Call {
+// CreateRemoteInstall indicates an expected call of CreateRemoteInstall
+func (mr *MockStoreMockRecorder) CreateRemoteInstall(remoteInstall interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResetAirgapInstallInProgress", reflect.TypeOf((*MockAirgapStore)(nil).ResetAirgapInstallInProgress), appID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateRemoteInstall", reflect.TypeOf((*MockStore)(nil).CreateRemoteInstall), remoteInstall)
 }
 
-// SetAppIsAirgap mocks base method
-func (m *MockAirgapStore) SetAppIsAirgap(appID string, isAirgap bool) error {
+// DeleteRemoteInstall mocks base method
+func (m *MockStore) DeleteRemoteInstall(id string) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "SetAppIsAirgap", appID, isAirgap)
+	ret := m.ctrl.Call(m, "DeleteRemoteInstall", id)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-// SetAppIsAirgap indicates an expected call of SetAppIsAirgap
-func (mr *MockAirgapStoreMockRecorder) SetAppIsAirgap(appID, isAirgap interface{}) *gomock.Call {
+// DeleteRemoteInstall indicates an expected call of DeleteRemoteInstall
+func (mr *MockStoreMockRecorder) DeleteRemoteInstall(id interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetAppIsAirgap", reflect.TypeOf((*MockAirgapStore)(nil).SetAppIsAirgap), appID, isAirgap)
-}
-
-// MockTaskStore is a mock of TaskStore interface
-type MockTaskStore struct {
-	ctrl     *gomock.Controller
-	recorder *MockTaskStoreMockRecorder
-}
-
-// MockTaskStoreMockRecorder is the mock recorder for MockTaskStore
-type MockTaskStoreMockRecorder struct {
-	mock *MockTaskStore
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteRemoteInstall", reflect.TypeOf((*MockStore)(nil).DeleteRemoteInstall), id)
 }
 
-// NewMockTaskStore creates a new mock instance
-func NewMockTaskStore(ctrl *gomock.Controller) *MockTaskStore {
-	mock := &MockTaskStore{ctrl: ctrl}
-	mock.recorder = &MockTaskStoreMockRecorder{mock}
-	return mock
+// SetRemoteInstallReport mocks base method
+func (m *MockStore) SetRemoteInstallReport(id string, report *types10.Report, fetchedAt time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetRemoteInstallReport", id, report, fetchedAt)
+	ret0, _ := ret[0].(error)
+	return ret0
 }
 
-// EXPECT returns an object that allows the caller to indicate expected use
-func (m *MockTaskStore) EXPECT() *MockTaskStoreMockRecorder {
-	return m.recorder
+// SetRemoteInstallReport indicates an expected call of SetRemoteInstallReport
+func (mr *MockStoreMockRecorder) SetRemoteInstallReport(id, report, fetchedAt interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetRemoteInstallReport", reflect.TypeOf((*MockStore)(nil).SetRemoteInstallReport), id, report, fetchedAt)
 }
 
-// SetTaskStatus mocks base method
-func (m *MockTaskStore) SetTaskStatus(taskID, message, status string) error {
+// SetRemoteInstallError mocks base method
+func (m *MockStore) SetRemoteInstallError(id, message string) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "SetTaskStatus", taskID, message, status)
+	ret := m.ctrl.Call(m, "SetRemoteInstallError", id, message)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-// SetTaskStatus indicates an expected call of SetTaskStatus
-func (mr *MockTaskStoreMockRecorder) SetTaskStatus(taskID, message, status interface{}) *gomock.Call {
+// SetRemoteInstallError indicates an expected call of SetRemoteInstallError
+func (mr *MockStoreMockRecorder) SetRemoteInstallError(id, message interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetTaskStatus", reflect.TypeOf((*MockTaskStore)(nil).SetTaskStatus), taskID, message, status)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetRemoteInstallError", reflect.TypeOf((*MockStore)(nil).SetRemoteInstallError), id, message)
 }
 
-// UpdateTaskStatusTimestamp mocks base method
-func (m *MockTaskStore) UpdateTaskStatusTimestamp(taskID string) error {
+// GetLogSettings mocks base method
+func (m *MockStore) GetLogSettings() (*types16.Settings, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "UpdateTaskStatusTimestamp", taskID)
-	ret0, _ := ret[0].(error)
-	return ret0
+	ret := m.ctrl.Call(m, "GetLogSettings")
+	ret0, _ := ret[0].(*types16.Settings)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
-// UpdateTaskStatusTimestamp indicates an expected call of UpdateTaskStatusTimestamp
-func (mr *MockTaskStoreMockRecorder) UpdateTaskStatusTimestamp(taskID interface{}) *gomock.Call {
+// GetLogSettings indicates an expected call of GetLogSettings
+func (mr *MockStoreMockRecorder) GetLogSettings() *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateTaskStatusTimestamp", reflect.TypeOf((*MockTaskStore)(nil).UpdateTaskStatusTimestamp), taskID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLogSettings", reflect.TypeOf((*MockStore)(nil).GetLogSettings))
 }
 
-// ClearTaskStatus mocks base method
-func (m *MockTaskStore) ClearTaskStatus(taskID string) error {
+// SetLogSettings mocks base method
+func (m *MockStore) SetLogSettings(settings types16.Settings) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ClearTaskStatus", taskID)
+	ret := m.ctrl.Call(m, "SetLogSettings", settings)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-// ClearTaskStatus indicates an expected call of ClearTaskStatus
-func (mr *MockTaskStoreMockRecorder) ClearTaskStatus(taskID interface{}) *gomock.Call {
+// SetLogSettings indicates an expected call of SetLogSettings
+func (mr *MockStoreMockRecorder) SetLogSettings(settings interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClearTaskStatus", reflect.TypeOf((*MockTaskStore)(nil).ClearTaskStatus), taskID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetLogSettings", reflect.TypeOf((*MockStore)(nil).SetLogSettings), settings)
 }
 
-// GetTaskStatus mocks base method
-func (m *MockTaskStore) GetTaskStatus(taskID string) (string, string, error) {
+// CreateScheduledJobRun mocks base method
+func (m *MockStore) CreateScheduledJobRun(run types28.Run) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetTaskStatus", taskID)
-	ret0, _ := ret[0].(string)
-	ret1, _ := ret[1].(string)
-	ret2, _ := ret[2].(error)
-	return ret0, ret1, ret2
+	ret := m.ctrl.Call(m, "CreateScheduledJobRun", run)
+	ret0, _ := ret[0].(error)
+	return ret0
 }
 
-// GetTaskStatus indicates an expected call of GetTaskStatus
-func (mr *MockTaskStoreMockRecorder) GetTaskStatus(taskID interface{}) *gomock.Call {
+// CreateScheduledJobRun indicates an expected call of CreateScheduledJobRun
+func (mr *MockStoreMockRecorder) CreateScheduledJobRun(run interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTaskStatus", reflect.TypeOf((*MockTaskStore)(nil).GetTaskStatus), taskID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateScheduledJobRun", reflect.TypeOf((*MockStore)(nil).CreateScheduledJobRun), run)
 }
 
-// MockSessionStore is a mock of SessionStore interface
-type MockSessionStore struct {
-	ctrl     *gomock.Controller
-	recorder *MockSessionStoreMockRecorder
+// FinishScheduledJobRun mocks base method
+func (m *MockStore) FinishScheduledJobRun(id string, status types28.Status, message string, finishedAt time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FinishScheduledJobRun", id, status, message, finishedAt)
+	ret0, _ := ret[0].(error)
+	return ret0
 }
 
-// MockSessionStoreMockRecorder is the mock recorder for MockSessionStore
-type MockSessionStoreMockRecorder struct {
-	mock *MockSessionStore
+// FinishScheduledJobRun indicates an expected call of FinishScheduledJobRun
+func (mr *MockStoreMockRecorder) FinishScheduledJobRun(id, status, message, finishedAt interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FinishScheduledJobRun", reflect.TypeOf((*MockStore)(nil).FinishScheduledJobRun), id, status, message, finishedAt)
 }
 
-// NewMockSessionStore creates a new mock instance
-func NewMockSessionStore(ctrl *gomock.Controller) *MockSessionStore {
-	mock := &MockSessionStore{ctrl: ctrl}
-	mock.recorder = &MockSessionStoreMockRecorder{mock}
-	return mock
+// ListLatestScheduledJobRuns mocks base method
+func (m *MockStore) ListLatestScheduledJobRuns() ([]types28.Run, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListLatestScheduledJobRuns")
+	ret0, _ := ret[0].([]types28.Run)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
-// EXPECT returns an object that allows the caller to indicate expected use
-func (m *MockSessionStore) EXPECT() *MockSessionStoreMockRecorder {
-	return m.recorder
+// ListLatestScheduledJobRuns indicates an expected call of ListLatestScheduledJobRuns
+func (mr *MockStoreMockRecorder) ListLatestScheduledJobRuns() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListLatestScheduledJobRuns", reflect.TypeOf((*MockStore)(nil).ListLatestScheduledJobRuns))
 }
 
-// CreateSession mocks base method
-func (m *MockSessionStore) CreateSession(user *types12.User, issuedAt, expiresAt time.Time, roles []string) (*types10.Session, error) {
+// DeleteScheduledJobRunsBefore mocks base method
+func (m *MockStore) DeleteScheduledJobRunsBefore(before time.Time) (int64, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreateSession", user, issuedAt, expiresAt, roles)
-	ret0, _ := ret[0].(*types10.Session)
+	ret := m.ctrl.Call(m, "DeleteScheduledJobRunsBefore", before)
+	ret0, _ := ret[0].(int64)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// CreateSession indicates an expected call of CreateSession
-func (mr *MockSessionStoreMockRecorder) CreateSession(user, issuedAt, expiresAt, roles interface{}) *gomock.Call {
+// DeleteScheduledJobRunsBefore indicates an expected call of DeleteScheduledJobRunsBefore
+func (mr *MockStoreMockRecorder) DeleteScheduledJobRunsBefore(before interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateSession", reflect.TypeOf((*MockSessionStore)(nil).CreateSession), user, issuedAt, expiresAt, roles)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteScheduledJobRunsBefore", reflect.TypeOf((*MockStore)(nil).DeleteScheduledJobRunsBefore), before)
 }
 
-// DeleteSession mocks base method
-func (m *MockSessionStore) DeleteSession(sessionID string) error {
+// SetAppVersionArchiveChecksum mocks base method
+func (m *MockStore) SetAppVersionArchiveChecksum(appID string, sequence int64, sum string, size int64) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeleteSession", sessionID)
+	ret := m.ctrl.Call(m, "SetAppVersionArchiveChecksum", appID, sequence, sum, size)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-// DeleteSession indicates an expected call of DeleteSession
-func (mr *MockSessionStoreMockRecorder) DeleteSession(sessionID interface{}) *gomock.Call {
+// SetAppVersionArchiveChecksum indicates an expected call of SetAppVersionArchiveChecksum
+func (mr *MockStoreMockRecorder) SetAppVersionArchiveChecksum(appID, sequence, sum, size interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteSession", reflect.TypeOf((*MockSessionStore)(nil).DeleteSession), sessionID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetAppVersionArchiveChecksum", reflect.TypeOf((*MockStore)(nil).SetAppVersionArchiveChecksum), appID, sequence, sum, size)
 }
 
-// GetSession mocks base method
-func (m *MockSessionStore) GetSession(sessionID string) (*types10.Session, error) {
+// GetAppVersionArchiveChecksum mocks base method
+func (m *MockStore) GetAppVersionArchiveChecksum(appID string, sequence int64) (*types5.Checksum, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetSession", sessionID)
-	ret0, _ := ret[0].(*types10.Session)
+	ret := m.ctrl.Call(m, "GetAppVersionArchiveChecksum", appID, sequence)
+	ret0, _ := ret[0].(*types5.Checksum)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetSession indicates an expected call of GetSession
-func (mr *MockSessionStoreMockRecorder) GetSession(sessionID interface{}) *gomock.Call {
+// GetAppVersionArchiveChecksum indicates an expected call of GetAppVersionArchiveChecksum
+func (mr *MockStoreMockRecorder) GetAppVersionArchiveChecksum(appID, sequence interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSession", reflect.TypeOf((*MockSessionStore)(nil).GetSession), sessionID)
-}
-
-// MockAppStatusStore is a mock of AppStatusStore interface
-type MockAppStatusStore struct {
-	ctrl     *gomock.Controller
-	recorder *MockAppStatusStoreMockRecorder
-}
-
-// MockAppStatusStoreMockRecorder is the mock recorder for MockAppStatusStore
-type MockAppStatusStoreMockRecorder struct {
-	mock *MockAppStatusStore
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAppVersionArchiveChecksum", reflect.TypeOf((*MockStore)(nil).GetAppVersionArchiveChecksum), appID, sequence)
 }
 
-// NewMockAppStatusStore creates a new mock instance
-func NewMockAppStatusStore(ctrl *gomock.Controller) *MockAppStatusStore {
-	mock := &MockAppStatusStore{ctrl: ctrl}
-	mock.recorder = &MockAppStatusStoreMockRecorder{mock}
-	return mock
+// ListAppVersionArchiveChecksumsToVerify mocks base method
+func (m *MockStore) ListAppVersionArchiveChecksumsToVerify(verifiedBefore time.Time, limit int) ([]types5.Checksum, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAppVersionArchiveChecksumsToVerify", verifiedBefore, limit)
+	ret0, _ := ret[0].([]types5.Checksum)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
-// EXPECT returns an object that allows the caller to indicate expected use
-func (m *MockAppStatusStore) EXPECT() *MockAppStatusStoreMockRecorder {
-	return m.recorder
+// ListAppVersionArchiveChecksumsToVerify indicates an expected call of ListAppVersionArchiveChecksumsToVerify
+func (mr *MockStoreMockRecorder) ListAppVersionArchiveChecksumsToVerify(verifiedBefore, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAppVersionArchiveChecksumsToVerify", reflect.TypeOf((*MockStore)(nil).ListAppVersionArchiveChecksumsToVerify), verifiedBefore, limit)
 }
 
-// GetAppStatus mocks base method
-func (m *MockAppStatusStore) GetAppStatus(appID string) (*types0.AppStatus, error) {
+// ListCorruptAppVersionArchives mocks base method
+func (m *MockStore) ListCorruptAppVersionArchives(appID string) ([]types5.Checksum, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetAppStatus", appID)
-	ret0, _ := ret[0].(*types0.AppStatus)
+	ret := m.ctrl.Call(m, "ListCorruptAppVersionArchives", appID)
+	ret0, _ := ret[0].([]types5.Checksum)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetAppStatus indicates an expected call of GetAppStatus
-func (mr *MockAppStatusStoreMockRecorder) GetAppStatus(appID interface{}) *gomock.Call {
+// ListCorruptAppVersionArchives indicates an expected call of ListCorruptAppVersionArchives
+func (mr *MockStoreMockRecorder) ListCorruptAppVersionArchives(appID interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAppStatus", reflect.TypeOf((*MockAppStatusStore)(nil).GetAppStatus), appID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListCorruptAppVersionArchives", reflect.TypeOf((*MockStore)(nil).ListCorruptAppVersionArchives), appID)
 }
 
-// SetAppStatus mocks base method
-func (m *MockAppStatusStore) SetAppStatus(appID string, resourceStates []types0.ResourceState, updatedAt time.Time, sequence int64) error {
+// SetAppVersionArchiveVerified mocks base method
+func (m *MockStore) SetAppVersionArchiveVerified(appID string, sequence int64, verifyError string) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "SetAppStatus", appID, resourceStates, updatedAt, sequence)
+	ret := m.ctrl.Call(m, "SetAppVersionArchiveVerified", appID, sequence, verifyError)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-// SetAppStatus indicates an expected call of SetAppStatus
-func (mr *MockAppStatusStoreMockRecorder) SetAppStatus(appID, resourceStates, updatedAt, sequence interface{}) *gomock.Call {
+// SetAppVersionArchiveVerified indicates an expected call of SetAppVersionArchiveVerified
+func (mr *MockStoreMockRecorder) SetAppVersionArchiveVerified(appID, sequence, verifyError interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetAppStatus", reflect.TypeOf((*MockAppStatusStore)(nil).SetAppStatus), appID, resourceStates, updatedAt, sequence)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetAppVersionArchiveVerified", reflect.TypeOf((*MockStore)(nil).SetAppVersionArchiveVerified), appID, sequence, verifyError)
 }
 
-// MockAppStore is a mock of AppStore interface
-type MockAppStore struct {
-	ctrl     *gomock.Controller
-	recorder *MockAppStoreMockRecorder
+// SetDeployEventResult mocks base method
+func (m *MockStore) SetDeployEventResult(appID, clusterID string, sequence int64, isError bool) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetDeployEventResult", appID, clusterID, sequence, isError)
+	ret0, _ := ret[0].(error)
+	return ret0
 }
 
-// MockAppStoreMockRecorder is the mock recorder for MockAppStore
-type MockAppStoreMockRecorder struct {
-	mock *MockAppStore
+// SetDeployEventResult indicates an expected call of SetDeployEventResult
+func (mr *MockStoreMockRecorder) SetDeployEventResult(appID, clusterID, sequence, isError interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetDeployEventResult", reflect.TypeOf((*MockStore)(nil).SetDeployEventResult), appID, clusterID, sequence, isError)
 }
 
-// NewMockAppStore creates a new mock instance
-func NewMockAppStore(ctrl *gomock.Controller) *MockAppStore {
-	mock := &MockAppStore{ctrl: ctrl}
-	mock.recorder = &MockAppStoreMockRecorder{mock}
-	return mock
+// ListDeployEvents mocks base method
+func (m *MockStore) ListDeployEvents(opts types9.ListOptions) ([]types9.Event, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListDeployEvents", opts)
+	ret0, _ := ret[0].([]types9.Event)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
-// EXPECT returns an object that allows the caller to indicate expected use
-func (m *MockAppStore) EXPECT() *MockAppStoreMockRecorder {
-	return m.recorder
+// ListDeployEvents indicates an expected call of ListDeployEvents
+func (mr *MockStoreMockRecorder) ListDeployEvents(opts interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListDeployEvents", reflect.TypeOf((*MockStore)(nil).ListDeployEvents), opts)
 }
 
-// AddAppToAllDownstreams mocks base method
-func (m *MockAppStore) AddAppToAllDownstreams(appID string) error {
+// RegisterCachedAsset mocks base method
+func (m *MockStore) RegisterCachedAsset(cacheKey, uri string) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "AddAppToAllDownstreams", appID)
+	ret := m.ctrl.Call(m, "RegisterCachedAsset", cacheKey, uri)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-// AddAppToAllDownstreams indicates an expected call of AddAppToAllDownstreams
-func (mr *MockAppStoreMockRecorder) AddAppToAllDownstreams(appID interface{}) *gomock.Call {
+// RegisterCachedAsset indicates an expected call of RegisterCachedAsset
+func (mr *MockStoreMockRecorder) RegisterCachedAsset(cacheKey, uri interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddAppToAllDownstreams", reflect.TypeOf((*MockAppStore)(nil).AddAppToAllDownstreams), appID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RegisterCachedAsset", reflect.TypeOf((*MockStore)(nil).RegisterCachedAsset), cacheKey, uri)
 }
 
-// SetAppInstallState mocks base method
-func (m *MockAppStore) SetAppInstallState(appID, state string) error {
+// GetCachedAsset mocks base method
+func (m *MockStore) GetCachedAsset(cacheKey string) (*types6.Asset, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "SetAppInstallState", appID, state)
+	ret := m.ctrl.Call(m, "GetCachedAsset", cacheKey)
+	ret0, _ := ret[0].(*types6.Asset)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCachedAsset indicates an expected call of GetCachedAsset
+func (mr *MockStoreMockRecorder) GetCachedAsset(cacheKey interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCachedAsset", reflect.TypeOf((*MockStore)(nil).GetCachedAsset), cacheKey)
+}
+
+// SetCachedAsset mocks base method
+func (m *MockStore) SetCachedAsset(asset types6.Asset) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetCachedAsset", asset)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-// SetAppInstallState indicates an expected call of SetAppInstallState
-func (mr *MockAppStoreMockRecorder) SetAppInstallState(appID, state interface{}) *gomock.Call {
+// SetCachedAsset indicates an expected call of SetCachedAsset
+func (mr *MockStoreMockRecorder) SetCachedAsset(asset interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetAppInstallState", reflect.TypeOf((*MockAppStore)(nil).SetAppInstallState), appID, state)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetCachedAsset", reflect.TypeOf((*MockStore)(nil).SetCachedAsset), asset)
 }
 
-// ListInstalledApps mocks base method
-func (m *MockAppStore) ListInstalledApps() ([]*types3.App, error) {
+// GetGlobalFreeze mocks base method
+func (m *MockStore) GetGlobalFreeze() (*types11.Freeze, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ListInstalledApps")
-	ret0, _ := ret[0].([]*types3.App)
+	ret := m.ctrl.Call(m, "GetGlobalFreeze")
+	ret0, _ := ret[0].(*types11.Freeze)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// ListInstalledApps indicates an expected call of ListInstalledApps
-func (mr *MockAppStoreMockRecorder) ListInstalledApps() *gomock.Call {
+// GetGlobalFreeze indicates an expected call of GetGlobalFreeze
+func (mr *MockStoreMockRecorder) GetGlobalFreeze() *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListInstalledApps", reflect.TypeOf((*MockAppStore)(nil).ListInstalledApps))
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetGlobalFreeze", reflect.TypeOf((*MockStore)(nil).GetGlobalFreeze))
 }
 
-// ListInstalledAppSlugs mocks base method
-func (m *MockAppStore) ListInstalledAppSlugs() ([]string, error) {
+// SetGlobalFreeze mocks base method
+func (m *MockStore) SetGlobalFreeze(freeze *types11.Freeze) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ListInstalledAppSlugs")
-	ret0, _ := ret[0].([]string)
-	ret1, _ := ret[1].(error)
-	return ret0, ret1
+	ret := m.ctrl.Call(m, "SetGlobalFreeze", freeze)
+	ret0, _ := ret[0].(error)
+	return ret0
 }
 
-// ListInstalledAppSlugs indicates an expected call of ListInstalledAppSlugs
-func (mr *MockAppStoreMockRecorder) ListInstalledAppSlugs() *gomock.Call {
+// SetGlobalFreeze indicates an expected call of SetGlobalFreeze
+func (mr *MockStoreMockRecorder) SetGlobalFreeze(freeze interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListInstalledAppSlugs", reflect.TypeOf((*MockAppStore)(nil).ListInstalledAppSlugs))
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetGlobalFreeze", reflect.TypeOf((*MockStore)(nil).SetGlobalFreeze), freeze)
 }
 
-// GetAppIDFromSlug mocks base method
-func (m *MockAppStore) GetAppIDFromSlug(slug string) (string, error) {
+// GetAppFreeze mocks base method
+func (m *MockStore) GetAppFreeze(appID string) (*types11.Freeze, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetAppIDFromSlug", slug)
-	ret0, _ := ret[0].(string)
+	ret := m.ctrl.Call(m, "GetAppFreeze", appID)
+	ret0, _ := ret[0].(*types11.Freeze)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetAppIDFromSlug indicates an expected call of GetAppIDFromSlug
-func (mr *MockAppStoreMockRecorder) GetAppIDFromSlug(slug interface{}) *gomock.Call {
+// GetAppFreeze indicates an expected call of GetAppFreeze
+func (mr *MockStoreMockRecorder) GetAppFreeze(appID interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAppIDFromSlug", reflect.TypeOf((*MockAppStore)(nil).GetAppIDFromSlug), slug)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAppFreeze", reflect.TypeOf((*MockStore)(nil).GetAppFreeze), appID)
 }
 
-// GetApp mocks base method
-func (m *MockAppStore) GetApp(appID string) (*types3.App, error) {
+// SetAppFreeze mocks base method
+func (m *MockStore) SetAppFreeze(appID string, freeze *types11.Freeze) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetApp", appID)
-	ret0, _ := ret[0].(*types3.App)
-	ret1, _ := ret[1].(error)
-	return ret0, ret1
+	ret := m.ctrl.Call(m, "SetAppFreeze", appID, freeze)
+	ret0, _ := ret[0].(error)
+	return ret0
 }
 
-// GetApp indicates an expected call of GetApp
-func (mr *MockAppStoreMockRecorder) GetApp(appID interface{}) *gomock.Call {
+// SetAppFreeze indicates an expected call of SetAppFreeze
+func (mr *MockStoreMockRecorder) SetAppFreeze(appID, freeze interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetApp", reflect.TypeOf((*MockAppStore)(nil).GetApp), appID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetAppFreeze", reflect.TypeOf((*MockStore)(nil).SetAppFreeze), appID, freeze)
 }
 
-// GetAppFromSlug mocks base method
-func (m *MockAppStore) GetAppFromSlug(slug string) (*types3.App, error) {
+// GetReleaseFeedbackConsent mocks base method
+func (m *MockStore) GetReleaseFeedbackConsent(appID string) (*types24.Consent, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetAppFromSlug", slug)
-	ret0, _ := ret[0].(*types3.App)
+	ret := m.ctrl.Call(m, "GetReleaseFeedbackConsent", appID)
+	ret0, _ := ret[0].(*types24.Consent)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetAppFromSlug indicates an expected call of GetAppFromSlug
-func (mr *MockAppStoreMockRecorder) GetAppFromSlug(slug interface{}) *gomock.Call {
+// GetReleaseFeedbackConsent indicates an expected call of GetReleaseFeedbackConsent
+func (mr *MockStoreMockRecorder) GetReleaseFeedbackConsent(appID interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAppFromSlug", reflect.TypeOf((*MockAppStore)(nil).GetAppFromSlug), slug)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetReleaseFeedbackConsent", reflect.TypeOf((*MockStore)(nil).GetReleaseFeedbackConsent), appID)
 }
 
-// CreateApp mocks base method
-func (m *MockAppStore) CreateApp(name, upstreamURI, licenseData string, isAirgapEnabled, skipImagePush, registryIsReadOnly bool) (*types3.App, error) {
+// SetReleaseFeedbackConsent mocks base method
+func (m *MockStore) SetReleaseFeedbackConsent(appID string, consent types24.Consent) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreateApp", name, upstreamURI, licenseData, isAirgapEnabled, skipImagePush, registryIsReadOnly)
-	ret0, _ := ret[0].(*types3.App)
-	ret1, _ := ret[1].(error)
-	return ret0, ret1
+	ret := m.ctrl.Call(m, "SetReleaseFeedbackConsent", appID, consent)
+	ret0, _ := ret[0].(error)
+	return ret0
 }
 
-// CreateApp indicates an expected call of CreateApp
-func (mr *MockAppStoreMockRecorder) CreateApp(name, upstreamURI, licenseData, isAirgapEnabled, skipImagePush, registryIsReadOnly interface{}) *gomock.Call {
+// SetReleaseFeedbackConsent indicates an expected call of SetReleaseFeedbackConsent
+func (mr *MockStoreMockRecorder) SetReleaseFeedbackConsent(appID, consent interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateApp", reflect.TypeOf((*MockAppStore)(nil).CreateApp), name, upstreamURI, licenseData, isAirgapEnabled, skipImagePush, registryIsReadOnly)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetReleaseFeedbackConsent", reflect.TypeOf((*MockStore)(nil).SetReleaseFeedbackConsent), appID, consent)
 }
 
-// ListDownstreamsForApp mocks base method
-func (m *MockAppStore) ListDownstreamsForApp(appID string) ([]types1.Downstream, error) {
+// CreateTemporaryAccess mocks base method
+func (m *MockStore) CreateTemporaryAccess(grant types31.Grant, tokenSHA256 string) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ListDownstreamsForApp", appID)
-	ret0, _ := ret[0].([]types1.Downstream)
-	ret1, _ := ret[1].(error)
-	return ret0, ret1
+	ret := m.ctrl.Call(m, "CreateTemporaryAccess", grant, tokenSHA256)
+	ret0, _ := ret[0].(error)
+	return ret0
 }
 
-// ListDownstreamsForApp indicates an expected call of ListDownstreamsForApp
-func (mr *MockAppStoreMockRecorder) ListDownstreamsForApp(appID interface{}) *gomock.Call {
+// CreateTemporaryAccess indicates an expected call of CreateTemporaryAccess
+func (mr *MockStoreMockRecorder) CreateTemporaryAccess(grant, tokenSHA256 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListDownstreamsForApp", reflect.TypeOf((*MockAppStore)(nil).ListDownstreamsForApp), appID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateTemporaryAccess", reflect.TypeOf((*MockStore)(nil).CreateTemporaryAccess), grant, tokenSHA256)
 }
 
-// ListAppsForDownstream mocks base method
-func (m *MockAppStore) ListAppsForDownstream(clusterID string) ([]*types3.App, error) {
+// ListTemporaryAccess mocks base method
+func (m *MockStore) ListTemporaryAccess() ([]types31.Grant, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ListAppsForDownstream", clusterID)
-	ret0, _ := ret[0].([]*types3.App)
+	ret := m.ctrl.Call(m, "ListTemporaryAccess")
+	ret0, _ := ret[0].([]types31.Grant)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// ListAppsForDownstream indicates an expected call of ListAppsForDownstream
-func (mr *MockAppStoreMockRecorder) ListAppsForDownstream(clusterID interface{}) *gomock.Call {
+// ListTemporaryAccess indicates an expected call of ListTemporaryAccess
+func (mr *MockStoreMockRecorder) ListTemporaryAccess() *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAppsForDownstream", reflect.TypeOf((*MockAppStore)(nil).ListAppsForDownstream), clusterID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTemporaryAccess", reflect.TypeOf((*MockStore)(nil).ListTemporaryAccess))
 }
 
-// GetDownstream mocks base method
-func (m *MockAppStore) GetDownstream(clusterID string) (*types1.Downstream, error) {
+// GetTemporaryAccess mocks base method
+func (m *MockStore) GetTemporaryAccess(id string) (*types31.Grant, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetDownstream", clusterID)
-	ret0, _ := ret[0].(*types1.Downstream)
+	ret := m.ctrl.Call(m, "GetTemporaryAccess", id)
+	ret0, _ := ret[0].(*types31.Grant)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetDownstream indicates an expected call of GetDownstream
-func (mr *MockAppStoreMockRecorder) GetDownstream(clusterID interface{}) *gomock.Call {
+// GetTemporaryAccess indicates an expected call of GetTemporaryAccess
+func (mr *MockStoreMockRecorder) GetTemporaryAccess(id interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDownstream", reflect.TypeOf((*MockAppStore)(nil).GetDownstream), clusterID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTemporaryAccess", reflect.TypeOf((*MockStore)(nil).GetTemporaryAccess), id)
 }
 
-// IsGitOpsEnabledForApp mocks base method
-func (m *MockAppStore) IsGitOpsEnabledForApp(appID string) (bool, error) {
+// GetTemporaryAccessByToken mocks base method
+func (m *MockStore) GetTemporaryAccessByToken(tokenSHA256 string) (*types31.Grant, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "IsGitOpsEnabledForApp", appID)
-	ret0, _ := ret[0].(bool)
+	ret := m.ctrl.Call(m, "GetTemporaryAccessByToken", tokenSHA256)
+	ret0, _ := ret[0].(*types31.Grant)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// IsGitOpsEnabledForApp indicates an expected call of IsGitOpsEnabledForApp
-func (mr *MockAppStoreMockRecorder) IsGitOpsEnabledForApp(appID interface{}) *gomock.Call {
+// GetTemporaryAccessByToken indicates an expected call of GetTemporaryAccessByToken
+func (mr *MockStoreMockRecorder) GetTemporaryAccessByToken(tokenSHA256 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsGitOpsEnabledForApp", reflect.TypeOf((*MockAppStore)(nil).IsGitOpsEnabledForApp), appID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTemporaryAccessByToken", reflect.TypeOf((*MockStore)(nil).GetTemporaryAccessByToken), tokenSHA256)
 }
 
-// SetUpdateCheckerSpec mocks base method
-func (m *MockAppStore) SetUpdateCheckerSpec(appID, updateCheckerSpec string) error {
+// AddTemporaryAccessSession mocks base method
+func (m *MockStore) AddTemporaryAccessSession(id, sessionID string, usedAt time.Time) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "SetUpdateCheckerSpec", appID, updateCheckerSpec)
+	ret := m.ctrl.Call(m, "AddTemporaryAccessSession", id, sessionID, usedAt)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-// SetUpdateCheckerSpec indicates an expected call of SetUpdateCheckerSpec
-func (mr *MockAppStoreMockRecorder) SetUpdateCheckerSpec(appID, updateCheckerSpec interface{}) *gomock.Call {
+// AddTemporaryAccessSession indicates an expected call of AddTemporaryAccessSession
+func (mr *MockStoreMockRecorder) AddTemporaryAccessSession(id, sessionID, usedAt interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetUpdateCheckerSpec", reflect.TypeOf((*MockAppStore)(nil).SetUpdateCheckerSpec), appID, updateCheckerSpec)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddTemporaryAccessSession", reflect.TypeOf((*MockStore)(nil).AddTemporaryAccessSession), id, sessionID, usedAt)
 }
 
-// SetSnapshotTTL mocks base method
-func (m *MockAppStore) SetSnapshotTTL(appID, snapshotTTL string) error {
+// RevokeTemporaryAccess mocks base method
+func (m *MockStore) RevokeTemporaryAccess(id, revokedBy string, revokedAt time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeTemporaryAccess", id, revokedBy, revokedAt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokeTemporaryAccess indicates an expected call of RevokeTemporaryAccess
+func (mr *MockStoreMockRecorder) RevokeTemporaryAccess(id, revokedBy, revokedAt interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeTemporaryAccess", reflect.TypeOf((*MockStore)(nil).RevokeTemporaryAccess), id, revokedBy, revokedAt)
+}
+
+// Init mocks base method
+func (m *MockStore) Init() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Init")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Init indicates an expected call of Init
+func (mr *MockStoreMockRecorder) Init() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Init", reflect.TypeOf((*MockStore)(nil).Init))
+}
+
+// WaitForReady mocks base method
+func (m *MockStore) WaitForReady(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WaitForReady", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// WaitForReady indicates an expected call of WaitForReady
+func (mr *MockStoreMockRecorder) WaitForReady(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WaitForReady", reflect.TypeOf((*MockStore)(nil).WaitForReady), ctx)
+}
+
+// IsNotFound mocks base method
+func (m *MockStore) IsNotFound(err error) bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsNotFound", err)
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// IsNotFound indicates an expected call of IsNotFound
+func (mr *MockStoreMockRecorder) IsNotFound(err interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsNotFound", reflect.TypeOf((*MockStore)(nil).IsNotFound), err)
+}
+
+// MockReadStore is a mock of ReadStore interface
+type MockReadStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockReadStoreMockRecorder
+}
+
+// MockReadStoreMockRecorder is the mock recorder for MockReadStore
+type MockReadStoreMockRecorder struct {
+	mock *MockReadStore
+}
+
+// NewMockReadStore creates a new mock instance
+func NewMockReadStore(ctrl *gomock.Controller) *MockReadStore {
+	mock := &MockReadStore{ctrl: ctrl}
+	mock.recorder = &MockReadStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockReadStore) EXPECT() *MockReadStoreMockRecorder {
+	return m.recorder
+}
+
+// GetCurrentVersion mocks base method
+func (m *MockReadStore) GetCurrentVersion(appID, clusterID string) (*types2.DownstreamVersion, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCurrentVersion", appID, clusterID)
+	ret0, _ := ret[0].(*types2.DownstreamVersion)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCurrentVersion indicates an expected call of GetCurrentVersion
+func (mr *MockReadStoreMockRecorder) GetCurrentVersion(appID, clusterID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCurrentVersion", reflect.TypeOf((*MockReadStore)(nil).GetCurrentVersion), appID, clusterID)
+}
+
+// GetPendingVersions mocks base method
+func (m *MockReadStore) GetPendingVersions(appID, clusterID string) ([]types2.DownstreamVersion, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPendingVersions", appID, clusterID)
+	ret0, _ := ret[0].([]types2.DownstreamVersion)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPendingVersions indicates an expected call of GetPendingVersions
+func (mr *MockReadStoreMockRecorder) GetPendingVersions(appID, clusterID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPendingVersions", reflect.TypeOf((*MockReadStore)(nil).GetPendingVersions), appID, clusterID)
+}
+
+// GetPastVersions mocks base method
+func (m *MockReadStore) GetPastVersions(appID, clusterID string) ([]types2.DownstreamVersion, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPastVersions", appID, clusterID)
+	ret0, _ := ret[0].([]types2.DownstreamVersion)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPastVersions indicates an expected call of GetPastVersions
+func (mr *MockReadStoreMockRecorder) GetPastVersions(appID, clusterID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPastVersions", reflect.TypeOf((*MockReadStore)(nil).GetPastVersions), appID, clusterID)
+}
+
+// ListPendingVersionCounts mocks base method
+func (m *MockReadStore) ListPendingVersionCounts() (map[string]int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListPendingVersionCounts")
+	ret0, _ := ret[0].(map[string]int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListPendingVersionCounts indicates an expected call of ListPendingVersionCounts
+func (mr *MockReadStoreMockRecorder) ListPendingVersionCounts() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPendingVersionCounts", reflect.TypeOf((*MockReadStore)(nil).ListPendingVersionCounts))
+}
+
+// ListDownstreamVersions mocks base method
+func (m *MockReadStore) ListDownstreamVersions(appID, clusterID string, opts types2.VersionHistoryOptions) ([]types2.DownstreamVersion, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListDownstreamVersions", appID, clusterID, opts)
+	ret0, _ := ret[0].([]types2.DownstreamVersion)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ListDownstreamVersions indicates an expected call of ListDownstreamVersions
+func (mr *MockReadStoreMockRecorder) ListDownstreamVersions(appID, clusterID, opts interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListDownstreamVersions", reflect.TypeOf((*MockReadStore)(nil).ListDownstreamVersions), appID, clusterID, opts)
+}
+
+// GetAppVersionArchive mocks base method
+func (m *MockReadStore) GetAppVersionArchive(appID string, sequence int64, dstPath string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAppVersionArchive", appID, sequence, dstPath)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// GetAppVersionArchive indicates an expected call of GetAppVersionArchive
+func (mr *MockReadStoreMockRecorder) GetAppVersionArchive(appID, sequence, dstPath interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAppVersionArchive", reflect.TypeOf((*MockReadStore)(nil).GetAppVersionArchive), appID, sequence, dstPath)
+}
+
+// GetAppVersionArchiveReader mocks base method
+func (m *MockReadStore) GetAppVersionArchiveReader(appID string, sequence int64) (io.ReadCloser, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAppVersionArchiveReader", appID, sequence)
+	ret0, _ := ret[0].(io.ReadCloser)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAppVersionArchiveReader indicates an expected call of GetAppVersionArchiveReader
+func (mr *MockReadStoreMockRecorder) GetAppVersionArchiveReader(appID, sequence interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAppVersionArchiveReader", reflect.TypeOf((*MockReadStore)(nil).GetAppVersionArchiveReader), appID, sequence)
+}
+
+// MockMigrations is a mock of Migrations interface
+type MockMigrations struct {
+	ctrl     *gomock.Controller
+	recorder *MockMigrationsMockRecorder
+}
+
+// MockMigrationsMockRecorder is the mock recorder for MockMigrations
+type MockMigrationsMockRecorder struct {
+	mock *MockMigrations
+}
+
+// NewMockMigrations creates a new mock instance
+func NewMockMigrations(ctrl *gomock.Controller) *MockMigrations {
+	mock := &MockMigrations{ctrl: ctrl}
+	mock.recorder = &MockMigrationsMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockMigrations) EXPECT() *MockMigrationsMockRecorder {
+	return m.recorder
+}
+
+// RunMigrations mocks base method
+func (m *MockMigrations) RunMigrations() {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "RunMigrations")
+}
+
+// RunMigrations indicates an expected call of RunMigrations
+func (mr *MockMigrationsMockRecorder) RunMigrations() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RunMigrations", reflect.TypeOf((*MockMigrations)(nil).RunMigrations))
+}
+
+// RunSchemaMigrations mocks base method
+func (m *MockMigrations) RunSchemaMigrations() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RunSchemaMigrations")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RunSchemaMigrations indicates an expected call of RunSchemaMigrations
+func (mr *MockMigrationsMockRecorder) RunSchemaMigrations() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RunSchemaMigrations", reflect.TypeOf((*MockMigrations)(nil).RunSchemaMigrations))
+}
+
+// RollbackSchemaMigrations mocks base method
+func (m *MockMigrations) RollbackSchemaMigrations(targetVersion int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RollbackSchemaMigrations", targetVersion)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RollbackSchemaMigrations indicates an expected call of RollbackSchemaMigrations
+func (mr *MockMigrationsMockRecorder) RollbackSchemaMigrations(targetVersion interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RollbackSchemaMigrations", reflect.TypeOf((*MockMigrations)(nil).RollbackSchemaMigrations), targetVersion)
+}
+
+// MockRegistryStore is a mock of RegistryStore interface
+type MockRegistryStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockRegistryStoreMockRecorder
+}
+
+// MockRegistryStoreMockRecorder is the mock recorder for MockRegistryStore
+type MockRegistryStoreMockRecorder struct {
+	mock *MockRegistryStore
+}
+
+// NewMockRegistryStore creates a new mock instance
+func NewMockRegistryStore(ctrl *gomock.Controller) *MockRegistryStore {
+	mock := &MockRegistryStore{ctrl: ctrl}
+	mock.recorder = &MockRegistryStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockRegistryStore) EXPECT() *MockRegistryStoreMockRecorder {
+	return m.recorder
+}
+
+// GetRegistryDetailsForApp mocks base method
+func (m *MockRegistryStore) GetRegistryDetailsForApp(appID string) (types23.RegistrySettings, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRegistryDetailsForApp", appID)
+	ret0, _ := ret[0].(types23.RegistrySettings)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRegistryDetailsForApp indicates an expected call of GetRegistryDetailsForApp
+func (mr *MockRegistryStoreMockRecorder) GetRegistryDetailsForApp(appID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRegistryDetailsForApp", reflect.TypeOf((*MockRegistryStore)(nil).GetRegistryDetailsForApp), appID)
+}
+
+// UpdateRegistry mocks base method
+func (m *MockRegistryStore) UpdateRegistry(appID, hostname, username, password, namespace string, isReadOnly bool) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateRegistry", appID, hostname, username, password, namespace, isReadOnly)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateRegistry indicates an expected call of UpdateRegistry
+func (mr *MockRegistryStoreMockRecorder) UpdateRegistry(appID, hostname, username, password, namespace, isReadOnly interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateRegistry", reflect.TypeOf((*MockRegistryStore)(nil).UpdateRegistry), appID, hostname, username, password, namespace, isReadOnly)
+}
+
+// MockSupportBundleStore is a mock of SupportBundleStore interface
+type MockSupportBundleStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockSupportBundleStoreMockRecorder
+}
+
+// MockSupportBundleStoreMockRecorder is the mock recorder for MockSupportBundleStore
+type MockSupportBundleStoreMockRecorder struct {
+	mock *MockSupportBundleStore
+}
+
+// NewMockSupportBundleStore creates a new mock instance
+func NewMockSupportBundleStore(ctrl *gomock.Controller) *MockSupportBundleStore {
+	mock := &MockSupportBundleStore{ctrl: ctrl}
+	mock.recorder = &MockSupportBundleStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockSupportBundleStore) EXPECT() *MockSupportBundleStoreMockRecorder {
+	return m.recorder
+}
+
+// ListSupportBundles mocks base method
+func (m *MockSupportBundleStore) ListSupportBundles(appID string) ([]*types30.SupportBundle, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListSupportBundles", appID)
+	ret0, _ := ret[0].([]*types30.SupportBundle)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListSupportBundles indicates an expected call of ListSupportBundles
+func (mr *MockSupportBundleStoreMockRecorder) ListSupportBundles(appID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListSupportBundles", reflect.TypeOf((*MockSupportBundleStore)(nil).ListSupportBundles), appID)
+}
+
+// GetSupportBundle mocks base method
+func (m *MockSupportBundleStore) GetSupportBundle(bundleID string) (*types30.SupportBundle, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSupportBundle", bundleID)
+	ret0, _ := ret[0].(*types30.SupportBundle)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSupportBundle indicates an expected call of GetSupportBundle
+func (mr *MockSupportBundleStoreMockRecorder) GetSupportBundle(bundleID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSupportBundle", reflect.TypeOf((*MockSupportBundleStore)(nil).GetSupportBundle), bundleID)
+}
+
+// CreateSupportBundle mocks base method
+func (m *MockSupportBundleStore) CreateSupportBundle(bundleID, appID, archivePath string, marshalledTree []byte) (*types30.SupportBundle, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateSupportBundle", bundleID, appID, archivePath, marshalledTree)
+	ret0, _ := ret[0].(*types30.SupportBundle)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateSupportBundle indicates an expected call of CreateSupportBundle
+func (mr *MockSupportBundleStoreMockRecorder) CreateSupportBundle(bundleID, appID, archivePath, marshalledTree interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateSupportBundle", reflect.TypeOf((*MockSupportBundleStore)(nil).CreateSupportBundle), bundleID, appID, archivePath, marshalledTree)
+}
+
+// GetSupportBundleArchive mocks base method
+func (m *MockSupportBundleStore) GetSupportBundleArchive(bundleID string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSupportBundleArchive", bundleID)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSupportBundleArchive indicates an expected call of GetSupportBundleArchive
+func (mr *MockSupportBundleStoreMockRecorder) GetSupportBundleArchive(bundleID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSupportBundleArchive", reflect.TypeOf((*MockSupportBundleStore)(nil).GetSupportBundleArchive), bundleID)
+}
+
+// GetSupportBundleAnalysis mocks base method
+func (m *MockSupportBundleStore) GetSupportBundleAnalysis(bundleID string) (*types30.SupportBundleAnalysis, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSupportBundleAnalysis", bundleID)
+	ret0, _ := ret[0].(*types30.SupportBundleAnalysis)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSupportBundleAnalysis indicates an expected call of GetSupportBundleAnalysis
+func (mr *MockSupportBundleStoreMockRecorder) GetSupportBundleAnalysis(bundleID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSupportBundleAnalysis", reflect.TypeOf((*MockSupportBundleStore)(nil).GetSupportBundleAnalysis), bundleID)
+}
+
+// SetSupportBundleAnalysis mocks base method
+func (m *MockSupportBundleStore) SetSupportBundleAnalysis(bundleID string, insights []byte) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetSupportBundleAnalysis", bundleID, insights)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetSupportBundleAnalysis indicates an expected call of SetSupportBundleAnalysis
+func (mr *MockSupportBundleStoreMockRecorder) SetSupportBundleAnalysis(bundleID, insights interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetSupportBundleAnalysis", reflect.TypeOf((*MockSupportBundleStore)(nil).SetSupportBundleAnalysis), bundleID, insights)
+}
+
+// GetRedactions mocks base method
+func (m *MockSupportBundleStore) GetRedactions(bundleID string) (redact.RedactionList, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRedactions", bundleID)
+	ret0, _ := ret[0].(redact.RedactionList)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRedactions indicates an expected call of GetRedactions
+func (mr *MockSupportBundleStoreMockRecorder) GetRedactions(bundleID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRedactions", reflect.TypeOf((*MockSupportBundleStore)(nil).GetRedactions), bundleID)
+}
+
+// SetRedactions mocks base method
+func (m *MockSupportBundleStore) SetRedactions(bundleID string, redacts redact.RedactionList) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetRedactions", bundleID, redacts)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetRedactions indicates an expected call of SetRedactions
+func (mr *MockSupportBundleStoreMockRecorder) SetRedactions(bundleID, redacts interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetRedactions", reflect.TypeOf((*MockSupportBundleStore)(nil).SetRedactions), bundleID, redacts)
+}
+
+// CreateInProgressSupportBundle mocks base method
+func (m *MockSupportBundleStore) CreateInProgressSupportBundle(supportBundle *types30.SupportBundle) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateInProgressSupportBundle", supportBundle)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateInProgressSupportBundle indicates an expected call of CreateInProgressSupportBundle
+func (mr *MockSupportBundleStoreMockRecorder) CreateInProgressSupportBundle(supportBundle interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateInProgressSupportBundle", reflect.TypeOf((*MockSupportBundleStore)(nil).CreateInProgressSupportBundle), supportBundle)
+}
+
+// UpdateSupportBundle mocks base method
+func (m *MockSupportBundleStore) UpdateSupportBundle(bundle *types30.SupportBundle) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateSupportBundle", bundle)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateSupportBundle indicates an expected call of UpdateSupportBundle
+func (mr *MockSupportBundleStoreMockRecorder) UpdateSupportBundle(bundle interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateSupportBundle", reflect.TypeOf((*MockSupportBundleStore)(nil).UpdateSupportBundle), bundle)
+}
+
+// UploadSupportBundle mocks base method
+func (m *MockSupportBundleStore) UploadSupportBundle(bundleID, archivePath string, marshalledTree []byte) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UploadSupportBundle", bundleID, archivePath, marshalledTree)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UploadSupportBundle indicates an expected call of UploadSupportBundle
+func (mr *MockSupportBundleStoreMockRecorder) UploadSupportBundle(bundleID, archivePath, marshalledTree interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UploadSupportBundle", reflect.TypeOf((*MockSupportBundleStore)(nil).UploadSupportBundle), bundleID, archivePath, marshalledTree)
+}
+
+// MockPreflightStore is a mock of PreflightStore interface
+type MockPreflightStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockPreflightStoreMockRecorder
+}
+
+// MockPreflightStoreMockRecorder is the mock recorder for MockPreflightStore
+type MockPreflightStoreMockRecorder struct {
+	mock *MockPreflightStore
+}
+
+// NewMockPreflightStore creates a new mock instance
+func NewMockPreflightStore(ctrl *gomock.Controller) *MockPreflightStore {
+	mock := &MockPreflightStore{ctrl: ctrl}
+	mock.recorder = &MockPreflightStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockPreflightStore) EXPECT() *MockPreflightStoreMockRecorder {
+	return m.recorder
+}
+
+// SetPreflightProgress mocks base method
+func (m *MockPreflightStore) SetPreflightProgress(appID string, sequence int64, progress string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetPreflightProgress", appID, sequence, progress)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetPreflightProgress indicates an expected call of SetPreflightProgress
+func (mr *MockPreflightStoreMockRecorder) SetPreflightProgress(appID, sequence, progress interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetPreflightProgress", reflect.TypeOf((*MockPreflightStore)(nil).SetPreflightProgress), appID, sequence, progress)
+}
+
+// GetPreflightProgress mocks base method
+func (m *MockPreflightStore) GetPreflightProgress(appID string, sequence int64) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPreflightProgress", appID, sequence)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPreflightProgress indicates an expected call of GetPreflightProgress
+func (mr *MockPreflightStoreMockRecorder) GetPreflightProgress(appID, sequence interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPreflightProgress", reflect.TypeOf((*MockPreflightStore)(nil).GetPreflightProgress), appID, sequence)
+}
+
+// SetPreflightResults mocks base method
+func (m *MockPreflightStore) SetPreflightResults(appID string, sequence int64, results []byte) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetPreflightResults", appID, sequence, results)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetPreflightResults indicates an expected call of SetPreflightResults
+func (mr *MockPreflightStoreMockRecorder) SetPreflightResults(appID, sequence, results interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetPreflightResults", reflect.TypeOf((*MockPreflightStore)(nil).SetPreflightResults), appID, sequence, results)
+}
+
+// GetPreflightResults mocks base method
+func (m *MockPreflightStore) GetPreflightResults(appID string, sequence int64) (*types21.PreflightResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPreflightResults", appID, sequence)
+	ret0, _ := ret[0].(*types21.PreflightResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPreflightResults indicates an expected call of GetPreflightResults
+func (mr *MockPreflightStoreMockRecorder) GetPreflightResults(appID, sequence interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPreflightResults", reflect.TypeOf((*MockPreflightStore)(nil).GetPreflightResults), appID, sequence)
+}
+
+// ResetPreflightResults mocks base method
+func (m *MockPreflightStore) ResetPreflightResults(appID string, sequence int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResetPreflightResults", appID, sequence)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ResetPreflightResults indicates an expected call of ResetPreflightResults
+func (mr *MockPreflightStoreMockRecorder) ResetPreflightResults(appID, sequence interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResetPreflightResults", reflect.TypeOf((*MockPreflightStore)(nil).ResetPreflightResults), appID, sequence)
+}
+
+// SetIgnorePreflightPermissionErrors mocks base method
+func (m *MockPreflightStore) SetIgnorePreflightPermissionErrors(appID string, sequence int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetIgnorePreflightPermissionErrors", appID, sequence)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetIgnorePreflightPermissionErrors indicates an expected call of SetIgnorePreflightPermissionErrors
+func (mr *MockPreflightStoreMockRecorder) SetIgnorePreflightPermissionErrors(appID, sequence interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetIgnorePreflightPermissionErrors", reflect.TypeOf((*MockPreflightStore)(nil).SetIgnorePreflightPermissionErrors), appID, sequence)
+}
+
+// MockPrometheusStore is a mock of PrometheusStore interface
+type MockPrometheusStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockPrometheusStoreMockRecorder
+}
+
+// MockPrometheusStoreMockRecorder is the mock recorder for MockPrometheusStore
+type MockPrometheusStoreMockRecorder struct {
+	mock *MockPrometheusStore
+}
+
+// NewMockPrometheusStore creates a new mock instance
+func NewMockPrometheusStore(ctrl *gomock.Controller) *MockPrometheusStore {
+	mock := &MockPrometheusStore{ctrl: ctrl}
+	mock.recorder = &MockPrometheusStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockPrometheusStore) EXPECT() *MockPrometheusStoreMockRecorder {
+	return m.recorder
+}
+
+// GetPrometheusAddress mocks base method
+func (m *MockPrometheusStore) GetPrometheusAddress() (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPrometheusAddress")
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPrometheusAddress indicates an expected call of GetPrometheusAddress
+func (mr *MockPrometheusStoreMockRecorder) GetPrometheusAddress() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPrometheusAddress", reflect.TypeOf((*MockPrometheusStore)(nil).GetPrometheusAddress))
+}
+
+// SetPrometheusAddress mocks base method
+func (m *MockPrometheusStore) SetPrometheusAddress(address string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetPrometheusAddress", address)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetPrometheusAddress indicates an expected call of SetPrometheusAddress
+func (mr *MockPrometheusStoreMockRecorder) SetPrometheusAddress(address interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetPrometheusAddress", reflect.TypeOf((*MockPrometheusStore)(nil).SetPrometheusAddress), address)
+}
+
+// GetPrometheusAuth mocks base method
+func (m *MockPrometheusStore) GetPrometheusAuth() (types22.Auth, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPrometheusAuth")
+	ret0, _ := ret[0].(types22.Auth)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPrometheusAuth indicates an expected call of GetPrometheusAuth
+func (mr *MockPrometheusStoreMockRecorder) GetPrometheusAuth() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPrometheusAuth", reflect.TypeOf((*MockPrometheusStore)(nil).GetPrometheusAuth))
+}
+
+// SetPrometheusAuth mocks base method
+func (m *MockPrometheusStore) SetPrometheusAuth(auth types22.Auth) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetPrometheusAuth", auth)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetPrometheusAuth indicates an expected call of SetPrometheusAuth
+func (mr *MockPrometheusStoreMockRecorder) SetPrometheusAuth(auth interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetPrometheusAuth", reflect.TypeOf((*MockPrometheusStore)(nil).SetPrometheusAuth), auth)
+}
+
+// MockAirgapStore is a mock of AirgapStore interface
+type MockAirgapStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockAirgapStoreMockRecorder
+}
+
+// MockAirgapStoreMockRecorder is the mock recorder for MockAirgapStore
+type MockAirgapStoreMockRecorder struct {
+	mock *MockAirgapStore
+}
+
+// NewMockAirgapStore creates a new mock instance
+func NewMockAirgapStore(ctrl *gomock.Controller) *MockAirgapStore {
+	mock := &MockAirgapStore{ctrl: ctrl}
+	mock.recorder = &MockAirgapStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockAirgapStore) EXPECT() *MockAirgapStoreMockRecorder {
+	return m.recorder
+}
+
+// GetPendingAirgapUploadApp mocks base method
+func (m *MockAirgapStore) GetPendingAirgapUploadApp() (*types0.PendingApp, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPendingAirgapUploadApp")
+	ret0, _ := ret[0].(*types0.PendingApp)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPendingAirgapUploadApp indicates an expected call of GetPendingAirgapUploadApp
+func (mr *MockAirgapStoreMockRecorder) GetPendingAirgapUploadApp() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPendingAirgapUploadApp", reflect.TypeOf((*MockAirgapStore)(nil).GetPendingAirgapUploadApp))
+}
+
+// GetAirgapInstallStatus mocks base method
+func (m *MockAirgapStore) GetAirgapInstallStatus(appID string) (*types0.InstallStatus, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAirgapInstallStatus", appID)
+	ret0, _ := ret[0].(*types0.InstallStatus)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAirgapInstallStatus indicates an expected call of GetAirgapInstallStatus
+func (mr *MockAirgapStoreMockRecorder) GetAirgapInstallStatus(appID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAirgapInstallStatus", reflect.TypeOf((*MockAirgapStore)(nil).GetAirgapInstallStatus), appID)
+}
+
+// ResetAirgapInstallInProgress mocks base method
+func (m *MockAirgapStore) ResetAirgapInstallInProgress(appID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResetAirgapInstallInProgress", appID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ResetAirgapInstallInProgress indicates an expected call of ResetAirgapInstallInProgress
+func (mr *MockAirgapStoreMockRecorder) ResetAirgapInstallInProgress(appID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResetAirgapInstallInProgress", reflect.TypeOf((*MockAirgapStore)(nil).ResetAirgapInstallInProgress), appID)
+}
+
+// SetAppIsAirgap mocks base method
+func (m *MockAirgapStore) SetAppIsAirgap(appID string, isAirgap bool) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetAppIsAirgap", appID, isAirgap)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetAppIsAirgap indicates an expected call of SetAppIsAirgap
+func (mr *MockAirgapStoreMockRecorder) SetAppIsAirgap(appID, isAirgap interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetAppIsAirgap", reflect.TypeOf((*MockAirgapStore)(nil).SetAppIsAirgap), appID, isAirgap)
+}
+
+// MockTaskStore is a mock of TaskStore interface
+type MockTaskStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockTaskStoreMockRecorder
+}
+
+// MockTaskStoreMockRecorder is the mock recorder for MockTaskStore
+type MockTaskStoreMockRecorder struct {
+	mock *MockTaskStore
+}
+
+// NewMockTaskStore creates a new mock instance
+func NewMockTaskStore(ctrl *gomock.Controller) *MockTaskStore {
+	mock := &MockTaskStore{ctrl: ctrl}
+	mock.recorder = &MockTaskStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockTaskStore) EXPECT() *MockTaskStoreMockRecorder {
+	return m.recorder
+}
+
+// SetTaskStatus mocks base method
+func (m *MockTaskStore) SetTaskStatus(taskID, message, status string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetTaskStatus", taskID, message, status)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetTaskStatus indicates an expected call of SetTaskStatus
+func (mr *MockTaskStoreMockRecorder) SetTaskStatus(taskID, message, status interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetTaskStatus", reflect.TypeOf((*MockTaskStore)(nil).SetTaskStatus), taskID, message, status)
+}
+
+// UpdateTaskStatusTimestamp mocks base method
+func (m *MockTaskStore) UpdateTaskStatusTimestamp(taskID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateTaskStatusTimestamp", taskID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateTaskStatusTimestamp indicates an expected call of UpdateTaskStatusTimestamp
+func (mr *MockTaskStoreMockRecorder) UpdateTaskStatusTimestamp(taskID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateTaskStatusTimestamp", reflect.TypeOf((*MockTaskStore)(nil).UpdateTaskStatusTimestamp), taskID)
+}
+
+// ClearTaskStatus mocks base method
+func (m *MockTaskStore) ClearTaskStatus(taskID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClearTaskStatus", taskID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ClearTaskStatus indicates an expected call of ClearTaskStatus
+func (mr *MockTaskStoreMockRecorder) ClearTaskStatus(taskID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClearTaskStatus", reflect.TypeOf((*MockTaskStore)(nil).ClearTaskStatus), taskID)
+}
+
+// GetTaskStatus mocks base method
+func (m *MockTaskStore) GetTaskStatus(taskID string) (string, string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTaskStatus", taskID)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(string)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetTaskStatus indicates an expected call of GetTaskStatus
+func (mr *MockTaskStoreMockRecorder) GetTaskStatus(taskID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTaskStatus", reflect.TypeOf((*MockTaskStore)(nil).GetTaskStatus), taskID)
+}
+
+// ClearStaleTaskStatuses mocks base method
+func (m *MockTaskStore) ClearStaleTaskStatuses(notUpdatedSince time.Time) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClearStaleTaskStatuses", notUpdatedSince)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClearStaleTaskStatuses indicates an expected call of ClearStaleTaskStatuses
+func (mr *MockTaskStoreMockRecorder) ClearStaleTaskStatuses(notUpdatedSince interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClearStaleTaskStatuses", reflect.TypeOf((*MockTaskStore)(nil).ClearStaleTaskStatuses), notUpdatedSince)
+}
+
+// MockSessionStore is a mock of SessionStore interface
+type MockSessionStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockSessionStoreMockRecorder
+}
+
+// MockSessionStoreMockRecorder is the mock recorder for MockSessionStore
+type MockSessionStoreMockRecorder struct {
+	mock *MockSessionStore
+}
+
+// NewMockSessionStore creates a new mock instance
+func NewMockSessionStore(ctrl *gomock.Controller) *MockSessionStore {
+	mock := &MockSessionStore{ctrl: ctrl}
+	mock.recorder = &MockSessionStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockSessionStore) EXPECT() *MockSessionStoreMockRecorder {
+	return m.recorder
+}
+
+// CreateSession mocks base method
+func (m *MockSessionStore) CreateSession(user *types34.User, issuedAt, expiresAt time.Time, roles []string) (*types29.Session, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateSession", user, issuedAt, expiresAt, roles)
+	ret0, _ := ret[0].(*types29.Session)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateSession indicates an expected call of CreateSession
+func (mr *MockSessionStoreMockRecorder) CreateSession(user, issuedAt, expiresAt, roles interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateSession", reflect.TypeOf((*MockSessionStore)(nil).CreateSession), user, issuedAt, expiresAt, roles)
+}
+
+// DeleteSession mocks base method
+func (m *MockSessionStore) DeleteSession(sessionID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteSession", sessionID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteSession indicates an expected call of DeleteSession
+func (mr *MockSessionStoreMockRecorder) DeleteSession(sessionID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteSession", reflect.TypeOf((*MockSessionStore)(nil).DeleteSession), sessionID)
+}
+
+// DeleteSessions mocks base method
+func (m *MockSessionStore) DeleteSessions(sessionIDs []string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteSessions", sessionIDs)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteSessions indicates an expected call of DeleteSessions
+func (mr *MockSessionStoreMockRecorder) DeleteSessions(sessionIDs interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteSessions", reflect.TypeOf((*MockSessionStore)(nil).DeleteSessions), sessionIDs)
+}
+
+// GetSession mocks base method
+func (m *MockSessionStore) GetSession(sessionID string) (*types29.Session, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSession", sessionID)
+	ret0, _ := ret[0].(*types29.Session)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSession indicates an expected call of GetSession
+func (mr *MockSessionStoreMockRecorder) GetSession(sessionID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSession", reflect.TypeOf((*MockSessionStore)(nil).GetSession), sessionID)
+}
+
+// ListSessions mocks base method
+func (m *MockSessionStore) ListSessions() ([]types29.Session, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListSessions")
+	ret0, _ := ret[0].([]types29.Session)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListSessions indicates an expected call of ListSessions
+func (mr *MockSessionStoreMockRecorder) ListSessions() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListSessions", reflect.TypeOf((*MockSessionStore)(nil).ListSessions))
+}
+
+// ImportSessions mocks base method
+func (m *MockSessionStore) ImportSessions(sessions []types29.Session) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ImportSessions", sessions)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ImportSessions indicates an expected call of ImportSessions
+func (mr *MockSessionStoreMockRecorder) ImportSessions(sessions interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ImportSessions", reflect.TypeOf((*MockSessionStore)(nil).ImportSessions), sessions)
+}
+
+// UpdateSessionActivity mocks base method
+func (m *MockSessionStore) UpdateSessionActivity(sessionID string, lastActiveAt time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateSessionActivity", sessionID, lastActiveAt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateSessionActivity indicates an expected call of UpdateSessionActivity
+func (mr *MockSessionStoreMockRecorder) UpdateSessionActivity(sessionID, lastActiveAt interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateSessionActivity", reflect.TypeOf((*MockSessionStore)(nil).UpdateSessionActivity), sessionID, lastActiveAt)
+}
+
+// MockAppStatusStore is a mock of AppStatusStore interface
+type MockAppStatusStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockAppStatusStoreMockRecorder
+}
+
+// MockAppStatusStoreMockRecorder is the mock recorder for MockAppStatusStore
+type MockAppStatusStoreMockRecorder struct {
+	mock *MockAppStatusStore
+}
+
+// NewMockAppStatusStore creates a new mock instance
+func NewMockAppStatusStore(ctrl *gomock.Controller) *MockAppStatusStore {
+	mock := &MockAppStatusStore{ctrl: ctrl}
+	mock.recorder = &MockAppStatusStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockAppStatusStore) EXPECT() *MockAppStatusStoreMockRecorder {
+	return m.recorder
+}
+
+// GetAppStatus mocks base method
+func (m *MockAppStatusStore) GetAppStatus(appID string) (*types1.AppStatus, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAppStatus", appID)
+	ret0, _ := ret[0].(*types1.AppStatus)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAppStatus indicates an expected call of GetAppStatus
+func (mr *MockAppStatusStoreMockRecorder) GetAppStatus(appID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAppStatus", reflect.TypeOf((*MockAppStatusStore)(nil).GetAppStatus), appID)
+}
+
+// SetAppStatus mocks base method
+func (m *MockAppStatusStore) SetAppStatus(appID string, resourceStates []types1.ResourceState, updatedAt time.Time, sequence int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetAppStatus", appID, resourceStates, updatedAt, sequence)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetAppStatus indicates an expected call of SetAppStatus
+func (mr *MockAppStatusStoreMockRecorder) SetAppStatus(appID, resourceStates, updatedAt, sequence interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetAppStatus", reflect.TypeOf((*MockAppStatusStore)(nil).SetAppStatus), appID, resourceStates, updatedAt, sequence)
+}
+
+// MockAppStore is a mock of AppStore interface
+type MockAppStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockAppStoreMockRecorder
+}
+
+// MockAppStoreMockRecorder is the mock recorder for MockAppStore
+type MockAppStoreMockRecorder struct {
+	mock *MockAppStore
+}
+
+// NewMockAppStore creates a new mock instance
+func NewMockAppStore(ctrl *gomock.Controller) *MockAppStore {
+	mock := &MockAppStore{ctrl: ctrl}
+	mock.recorder = &MockAppStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockAppStore) EXPECT() *MockAppStoreMockRecorder {
+	return m.recorder
+}
+
+// AddAppToAllDownstreams mocks base method
+func (m *MockAppStore) AddAppToAllDownstreams(appID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddAppToAllDownstreams", appID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddAppToAllDownstreams indicates an expected call of AddAppToAllDownstreams
+func (mr *MockAppStoreMockRecorder) AddAppToAllDownstreams(appID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddAppToAllDownstreams", reflect.TypeOf((*MockAppStore)(nil).AddAppToAllDownstreams), appID)
+}
+
+// SetAppInstallState mocks base method
+func (m *MockAppStore) SetAppInstallState(appID, state string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetAppInstallState", appID, state)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetAppInstallState indicates an expected call of SetAppInstallState
+func (mr *MockAppStoreMockRecorder) SetAppInstallState(appID, state interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetAppInstallState", reflect.TypeOf((*MockAppStore)(nil).SetAppInstallState), appID, state)
+}
+
+// ListInstalledApps mocks base method
+func (m *MockAppStore) ListInstalledApps() ([]*types4.App, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListInstalledApps")
+	ret0, _ := ret[0].([]*types4.App)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListInstalledApps indicates an expected call of ListInstalledApps
+func (mr *MockAppStoreMockRecorder) ListInstalledApps() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListInstalledApps", reflect.TypeOf((*MockAppStore)(nil).ListInstalledApps))
+}
+
+// ListInstalledAppSlugs mocks base method
+func (m *MockAppStore) ListInstalledAppSlugs() ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListInstalledAppSlugs")
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListInstalledAppSlugs indicates an expected call of ListInstalledAppSlugs
+func (mr *MockAppStoreMockRecorder) ListInstalledAppSlugs() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListInstalledAppSlugs", reflect.TypeOf((*MockAppStore)(nil).ListInstalledAppSlugs))
+}
+
+// GetAppIDFromSlug mocks base method
+func (m *MockAppStore) GetAppIDFromSlug(slug string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAppIDFromSlug", slug)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAppIDFromSlug indicates an expected call of GetAppIDFromSlug
+func (mr *MockAppStoreMockRecorder) GetAppIDFromSlug(slug interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAppIDFromSlug", reflect.TypeOf((*MockAppStore)(nil).GetAppIDFromSlug), slug)
+}
+
+// GetApp mocks base method
+func (m *MockAppStore) GetApp(appID string) (*types4.App, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetApp", appID)
+	ret0, _ := ret[0].(*types4.App)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetApp indicates an expected call of GetApp
+func (mr *MockAppStoreMockRecorder) GetApp(appID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetApp", reflect.TypeOf((*MockAppStore)(nil).GetApp), appID)
+}
+
+// GetAppFromSlug mocks base method
+func (m *MockAppStore) GetAppFromSlug(slug string) (*types4.App, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAppFromSlug", slug)
+	ret0, _ := ret[0].(*types4.App)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAppFromSlug indicates an expected call of GetAppFromSlug
+func (mr *MockAppStoreMockRecorder) GetAppFromSlug(slug interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAppFromSlug", reflect.TypeOf((*MockAppStore)(nil).GetAppFromSlug), slug)
+}
+
+// CreateApp mocks base method
+func (m *MockAppStore) CreateApp(name, upstreamURI, licenseData string, isAirgapEnabled, skipImagePush, registryIsReadOnly bool) (*types4.App, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateApp", name, upstreamURI, licenseData, isAirgapEnabled, skipImagePush, registryIsReadOnly)
+	ret0, _ := ret[0].(*types4.App)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateApp indicates an expected call of CreateApp
+func (mr *MockAppStoreMockRecorder) CreateApp(name, upstreamURI, licenseData, isAirgapEnabled, skipImagePush, registryIsReadOnly interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateApp", reflect.TypeOf((*MockAppStore)(nil).CreateApp), name, upstreamURI, licenseData, isAirgapEnabled, skipImagePush, registryIsReadOnly)
+}
+
+// ListDownstreamsForApp mocks base method
+func (m *MockAppStore) ListDownstreamsForApp(appID string) ([]types2.Downstream, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListDownstreamsForApp", appID)
+	ret0, _ := ret[0].([]types2.Downstream)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListDownstreamsForApp indicates an expected call of ListDownstreamsForApp
+func (mr *MockAppStoreMockRecorder) ListDownstreamsForApp(appID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListDownstreamsForApp", reflect.TypeOf((*MockAppStore)(nil).ListDownstreamsForApp), appID)
+}
+
+// ListAppsForDownstream mocks base method
+func (m *MockAppStore) ListAppsForDownstream(clusterID string) ([]*types4.App, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAppsForDownstream", clusterID)
+	ret0, _ := ret[0].([]*types4.App)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAppsForDownstream indicates an expected call of ListAppsForDownstream
+func (mr *MockAppStoreMockRecorder) ListAppsForDownstream(clusterID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAppsForDownstream", reflect.TypeOf((*MockAppStore)(nil).ListAppsForDownstream), clusterID)
+}
+
+// GetDownstream mocks base method
+func (m *MockAppStore) GetDownstream(clusterID string) (*types2.Downstream, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDownstream", clusterID)
+	ret0, _ := ret[0].(*types2.Downstream)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDownstream indicates an expected call of GetDownstream
+func (mr *MockAppStoreMockRecorder) GetDownstream(clusterID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDownstream", reflect.TypeOf((*MockAppStore)(nil).GetDownstream), clusterID)
+}
+
+// IsGitOpsEnabledForApp mocks base method
+func (m *MockAppStore) IsGitOpsEnabledForApp(appID string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsGitOpsEnabledForApp", appID)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IsGitOpsEnabledForApp indicates an expected call of IsGitOpsEnabledForApp
+func (mr *MockAppStoreMockRecorder) IsGitOpsEnabledForApp(appID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsGitOpsEnabledForApp", reflect.TypeOf((*MockAppStore)(nil).IsGitOpsEnabledForApp), appID)
+}
+
+// SetUpdateCheckerSpec mocks base method
+func (m *MockAppStore) SetUpdateCheckerSpec(appID, updateCheckerSpec string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetUpdateCheckerSpec", appID, updateCheckerSpec)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetUpdateCheckerSpec indicates an expected call of SetUpdateCheckerSpec
+func (mr *MockAppStoreMockRecorder) SetUpdateCheckerSpec(appID, updateCheckerSpec interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetUpdateCheckerSpec", reflect.TypeOf((*MockAppStore)(nil).SetUpdateCheckerSpec), appID, updateCheckerSpec)
+}
+
+// SetDeployPolicy mocks base method
+func (m *MockAppStore) SetDeployPolicy(appID string, deployPolicy types4.DeployPolicy) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetDeployPolicy", appID, deployPolicy)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetDeployPolicy indicates an expected call of SetDeployPolicy
+func (mr *MockAppStoreMockRecorder) SetDeployPolicy(appID, deployPolicy interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetDeployPolicy", reflect.TypeOf((*MockAppStore)(nil).SetDeployPolicy), appID, deployPolicy)
+}
+
+// SetAdmissionDryRun mocks base method
+func (m *MockAppStore) SetAdmissionDryRun(appID string, enabled bool) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetAdmissionDryRun", appID, enabled)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetAdmissionDryRun indicates an expected call of SetAdmissionDryRun
+func (mr *MockAppStoreMockRecorder) SetAdmissionDryRun(appID, enabled interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetAdmissionDryRun", reflect.TypeOf((*MockAppStore)(nil).SetAdmissionDryRun), appID, enabled)
+}
+
+// SetAppArchived mocks base method
+func (m *MockAppStore) SetAppArchived(appID string, archived bool) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetAppArchived", appID, archived)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetAppArchived indicates an expected call of SetAppArchived
+func (mr *MockAppStoreMockRecorder) SetAppArchived(appID, archived interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetAppArchived", reflect.TypeOf((*MockAppStore)(nil).SetAppArchived), appID, archived)
+}
+
+// SetAppProtected mocks base method
+func (m *MockAppStore) SetAppProtected(appID string, protected bool) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetAppProtected", appID, protected)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetAppProtected indicates an expected call of SetAppProtected
+func (mr *MockAppStoreMockRecorder) SetAppProtected(appID, protected interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetAppProtected", reflect.TypeOf((*MockAppStore)(nil).SetAppProtected), appID, protected)
+}
+
+// SetRequireDeployApproval mocks base method
+func (m *MockAppStore) SetRequireDeployApproval(appID string, required bool) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetRequireDeployApproval", appID, required)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetRequireDeployApproval indicates an expected call of SetRequireDeployApproval
+func (mr *MockAppStoreMockRecorder) SetRequireDeployApproval(appID, required interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetRequireDeployApproval", reflect.TypeOf((*MockAppStore)(nil).SetRequireDeployApproval), appID, required)
+}
+
+// SetApplyPolicy mocks base method
+func (m *MockAppStore) SetApplyPolicy(appID string, applyPolicy types4.ApplyPolicy) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetApplyPolicy", appID, applyPolicy)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetApplyPolicy indicates an expected call of SetApplyPolicy
+func (mr *MockAppStoreMockRecorder) SetApplyPolicy(appID, applyPolicy interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetApplyPolicy", reflect.TypeOf((*MockAppStore)(nil).SetApplyPolicy), appID, applyPolicy)
+}
+
+// SetCanaryPolicy mocks base method
+func (m *MockAppStore) SetCanaryPolicy(appID string, canaryPolicy types4.CanaryPolicy) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetCanaryPolicy", appID, canaryPolicy)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetCanaryPolicy indicates an expected call of SetCanaryPolicy
+func (mr *MockAppStoreMockRecorder) SetCanaryPolicy(appID, canaryPolicy interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetCanaryPolicy", reflect.TypeOf((*MockAppStore)(nil).SetCanaryPolicy), appID, canaryPolicy)
+}
+
+// SetAppNamespace mocks base method
+func (m *MockAppStore) SetAppNamespace(appID, namespace string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetAppNamespace", appID, namespace)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetAppNamespace indicates an expected call of SetAppNamespace
+func (mr *MockAppStoreMockRecorder) SetAppNamespace(appID, namespace interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetAppNamespace", reflect.TypeOf((*MockAppStore)(nil).SetAppNamespace), appID, namespace)
+}
+
+// SetAppLabels mocks base method
+func (m *MockAppStore) SetAppLabels(appID string, labels map[string]string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetAppLabels", appID, labels)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetAppLabels indicates an expected call of SetAppLabels
+func (mr *MockAppStoreMockRecorder) SetAppLabels(appID, labels interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetAppLabels", reflect.TypeOf((*MockAppStore)(nil).SetAppLabels), appID, labels)
+}
+
+// SetImagePushBandwidthLimit mocks base method
+func (m *MockAppStore) SetImagePushBandwidthLimit(appID string, bytesPerSecond int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetImagePushBandwidthLimit", appID, bytesPerSecond)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetImagePushBandwidthLimit indicates an expected call of SetImagePushBandwidthLimit
+func (mr *MockAppStoreMockRecorder) SetImagePushBandwidthLimit(appID, bytesPerSecond interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetImagePushBandwidthLimit", reflect.TypeOf((*MockAppStore)(nil).SetImagePushBandwidthLimit), appID, bytesPerSecond)
+}
+
+// SetSnapshotTTL mocks base method
+func (m *MockAppStore) SetSnapshotTTL(appID, snapshotTTL string) error {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "SetSnapshotTTL", appID, snapshotTTL)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-// SetSnapshotTTL indicates an expected call of SetSnapshotTTL
-func (mr *MockAppStoreMockRecorder) SetSnapshotTTL(appID, snapshotTTL interface{}) *gomock.Call {
+// SetSnapshotTTL indicates an expected call of SetSnapshotTTL
+func (mr *MockAppStoreMockRecorder) SetSnapshotTTL(appID, snapshotTTL interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetSnapshotTTL", reflect.TypeOf((*MockAppStore)(nil).SetSnapshotTTL), appID, snapshotTTL)
+}
+
+// SetSnapshotSchedule mocks base method
+func (m *MockAppStore) SetSnapshotSchedule(appID, snapshotSchedule string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetSnapshotSchedule", appID, snapshotSchedule)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetSnapshotSchedule indicates an expected call of SetSnapshotSchedule
+func (mr *MockAppStoreMockRecorder) SetSnapshotSchedule(appID, snapshotSchedule interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetSnapshotSchedule", reflect.TypeOf((*MockAppStore)(nil).SetSnapshotSchedule), appID, snapshotSchedule)
+}
+
+// SetRestoreDrillSchedule mocks base method
+func (m *MockAppStore) SetRestoreDrillSchedule(appID, restoreDrillSchedule string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetRestoreDrillSchedule", appID, restoreDrillSchedule)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetRestoreDrillSchedule indicates an expected call of SetRestoreDrillSchedule
+func (mr *MockAppStoreMockRecorder) SetRestoreDrillSchedule(appID, restoreDrillSchedule interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetRestoreDrillSchedule", reflect.TypeOf((*MockAppStore)(nil).SetRestoreDrillSchedule), appID, restoreDrillSchedule)
+}
+
+// SetScheduleTimezone mocks base method
+func (m *MockAppStore) SetScheduleTimezone(appID, timezone string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetScheduleTimezone", appID, timezone)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetScheduleTimezone indicates an expected call of SetScheduleTimezone
+func (mr *MockAppStoreMockRecorder) SetScheduleTimezone(appID, timezone interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetScheduleTimezone", reflect.TypeOf((*MockAppStore)(nil).SetScheduleTimezone), appID, timezone)
+}
+
+// TrashApp mocks base method
+func (m *MockAppStore) TrashApp(appID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TrashApp", appID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// TrashApp indicates an expected call of TrashApp
+func (mr *MockAppStoreMockRecorder) TrashApp(appID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TrashApp", reflect.TypeOf((*MockAppStore)(nil).TrashApp), appID)
+}
+
+// RestoreTrashedApp mocks base method
+func (m *MockAppStore) RestoreTrashedApp(appID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RestoreTrashedApp", appID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RestoreTrashedApp indicates an expected call of RestoreTrashedApp
+func (mr *MockAppStoreMockRecorder) RestoreTrashedApp(appID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestoreTrashedApp", reflect.TypeOf((*MockAppStore)(nil).RestoreTrashedApp), appID)
+}
+
+// ListRemovedApps mocks base method
+func (m *MockAppStore) ListRemovedApps() ([]*types4.App, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListRemovedApps")
+	ret0, _ := ret[0].([]*types4.App)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListRemovedApps indicates an expected call of ListRemovedApps
+func (mr *MockAppStoreMockRecorder) ListRemovedApps() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListRemovedApps", reflect.TypeOf((*MockAppStore)(nil).ListRemovedApps))
+}
+
+// RemoveApp mocks base method
+func (m *MockAppStore) RemoveApp(appID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveApp", appID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RemoveApp indicates an expected call of RemoveApp
+func (mr *MockAppStoreMockRecorder) RemoveApp(appID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveApp", reflect.TypeOf((*MockAppStore)(nil).RemoveApp), appID)
+}
+
+// MockDownstreamStore is a mock of DownstreamStore interface
+type MockDownstreamStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockDownstreamStoreMockRecorder
+}
+
+// MockDownstreamStoreMockRecorder is the mock recorder for MockDownstreamStore
+type MockDownstreamStoreMockRecorder struct {
+	mock *MockDownstreamStore
+}
+
+// NewMockDownstreamStore creates a new mock instance
+func NewMockDownstreamStore(ctrl *gomock.Controller) *MockDownstreamStore {
+	mock := &MockDownstreamStore{ctrl: ctrl}
+	mock.recorder = &MockDownstreamStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockDownstreamStore) EXPECT() *MockDownstreamStoreMockRecorder {
+	return m.recorder
+}
+
+// GetCurrentSequence mocks base method
+func (m *MockDownstreamStore) GetCurrentSequence(appID, clusterID string) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCurrentSequence", appID, clusterID)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCurrentSequence indicates an expected call of GetCurrentSequence
+func (mr *MockDownstreamStoreMockRecorder) GetCurrentSequence(appID, clusterID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCurrentSequence", reflect.TypeOf((*MockDownstreamStore)(nil).GetCurrentSequence), appID, clusterID)
+}
+
+// GetCurrentParentSequence mocks base method
+func (m *MockDownstreamStore) GetCurrentParentSequence(appID, clusterID string) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCurrentParentSequence", appID, clusterID)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCurrentParentSequence indicates an expected call of GetCurrentParentSequence
+func (mr *MockDownstreamStoreMockRecorder) GetCurrentParentSequence(appID, clusterID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCurrentParentSequence", reflect.TypeOf((*MockDownstreamStore)(nil).GetCurrentParentSequence), appID, clusterID)
+}
+
+// GetParentSequenceForSequence mocks base method
+func (m *MockDownstreamStore) GetParentSequenceForSequence(appID, clusterID string, sequence int64) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetParentSequenceForSequence", appID, clusterID, sequence)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetParentSequenceForSequence indicates an expected call of GetParentSequenceForSequence
+func (mr *MockDownstreamStoreMockRecorder) GetParentSequenceForSequence(appID, clusterID, sequence interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetParentSequenceForSequence", reflect.TypeOf((*MockDownstreamStore)(nil).GetParentSequenceForSequence), appID, clusterID, sequence)
+}
+
+// GetPreviouslyDeployedSequence mocks base method
+func (m *MockDownstreamStore) GetPreviouslyDeployedSequence(appID, clusterID string) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPreviouslyDeployedSequence", appID, clusterID)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPreviouslyDeployedSequence indicates an expected call of GetPreviouslyDeployedSequence
+func (mr *MockDownstreamStoreMockRecorder) GetPreviouslyDeployedSequence(appID, clusterID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPreviouslyDeployedSequence", reflect.TypeOf((*MockDownstreamStore)(nil).GetPreviouslyDeployedSequence), appID, clusterID)
+}
+
+// SetDownstreamVersionReady mocks base method
+func (m *MockDownstreamStore) SetDownstreamVersionReady(appID string, sequence int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetDownstreamVersionReady", appID, sequence)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetDownstreamVersionReady indicates an expected call of SetDownstreamVersionReady
+func (mr *MockDownstreamStoreMockRecorder) SetDownstreamVersionReady(appID, sequence interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetDownstreamVersionReady", reflect.TypeOf((*MockDownstreamStore)(nil).SetDownstreamVersionReady), appID, sequence)
+}
+
+// SetDownstreamVersionPendingPreflight mocks base method
+func (m *MockDownstreamStore) SetDownstreamVersionPendingPreflight(appID string, sequence int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetDownstreamVersionPendingPreflight", appID, sequence)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetDownstreamVersionPendingPreflight indicates an expected call of SetDownstreamVersionPendingPreflight
+func (mr *MockDownstreamStoreMockRecorder) SetDownstreamVersionPendingPreflight(appID, sequence interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetDownstreamVersionPendingPreflight", reflect.TypeOf((*MockDownstreamStore)(nil).SetDownstreamVersionPendingPreflight), appID, sequence)
+}
+
+// UpdateDownstreamVersionStatus mocks base method
+func (m *MockDownstreamStore) UpdateDownstreamVersionStatus(appID string, sequence int64, status, statusInfo string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateDownstreamVersionStatus", appID, sequence, status, statusInfo)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateDownstreamVersionStatus indicates an expected call of UpdateDownstreamVersionStatus
+func (mr *MockDownstreamStoreMockRecorder) UpdateDownstreamVersionStatus(appID, sequence, status, statusInfo interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateDownstreamVersionStatus", reflect.TypeOf((*MockDownstreamStore)(nil).UpdateDownstreamVersionStatus), appID, sequence, status, statusInfo)
+}
+
+// GetDownstreamVersionStatus mocks base method
+func (m *MockDownstreamStore) GetDownstreamVersionStatus(appID string, sequence int64) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDownstreamVersionStatus", appID, sequence)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDownstreamVersionStatus indicates an expected call of GetDownstreamVersionStatus
+func (mr *MockDownstreamStoreMockRecorder) GetDownstreamVersionStatus(appID, sequence interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDownstreamVersionStatus", reflect.TypeOf((*MockDownstreamStore)(nil).GetDownstreamVersionStatus), appID, sequence)
+}
+
+// GetIgnoreRBACErrors mocks base method
+func (m *MockDownstreamStore) GetIgnoreRBACErrors(appID string, sequence int64) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetIgnoreRBACErrors", appID, sequence)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetIgnoreRBACErrors indicates an expected call of GetIgnoreRBACErrors
+func (mr *MockDownstreamStoreMockRecorder) GetIgnoreRBACErrors(appID, sequence interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetIgnoreRBACErrors", reflect.TypeOf((*MockDownstreamStore)(nil).GetIgnoreRBACErrors), appID, sequence)
+}
+
+// GetCurrentVersion mocks base method
+func (m *MockDownstreamStore) GetCurrentVersion(appID, clusterID string) (*types2.DownstreamVersion, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCurrentVersion", appID, clusterID)
+	ret0, _ := ret[0].(*types2.DownstreamVersion)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCurrentVersion indicates an expected call of GetCurrentVersion
+func (mr *MockDownstreamStoreMockRecorder) GetCurrentVersion(appID, clusterID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCurrentVersion", reflect.TypeOf((*MockDownstreamStore)(nil).GetCurrentVersion), appID, clusterID)
+}
+
+// GetStatusForVersion mocks base method
+func (m *MockDownstreamStore) GetStatusForVersion(appID, clusterID string, sequence int64) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetStatusForVersion", appID, clusterID, sequence)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetStatusForVersion indicates an expected call of GetStatusForVersion
+func (mr *MockDownstreamStoreMockRecorder) GetStatusForVersion(appID, clusterID, sequence interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetStatusForVersion", reflect.TypeOf((*MockDownstreamStore)(nil).GetStatusForVersion), appID, clusterID, sequence)
+}
+
+// GetPendingVersions mocks base method
+func (m *MockDownstreamStore) GetPendingVersions(appID, clusterID string) ([]types2.DownstreamVersion, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPendingVersions", appID, clusterID)
+	ret0, _ := ret[0].([]types2.DownstreamVersion)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPendingVersions indicates an expected call of GetPendingVersions
+func (mr *MockDownstreamStoreMockRecorder) GetPendingVersions(appID, clusterID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPendingVersions", reflect.TypeOf((*MockDownstreamStore)(nil).GetPendingVersions), appID, clusterID)
+}
+
+// GetPastVersions mocks base method
+func (m *MockDownstreamStore) GetPastVersions(appID, clusterID string) ([]types2.DownstreamVersion, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPastVersions", appID, clusterID)
+	ret0, _ := ret[0].([]types2.DownstreamVersion)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPastVersions indicates an expected call of GetPastVersions
+func (mr *MockDownstreamStoreMockRecorder) GetPastVersions(appID, clusterID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPastVersions", reflect.TypeOf((*MockDownstreamStore)(nil).GetPastVersions), appID, clusterID)
+}
+
+// ListDownstreamVersions mocks base method
+func (m *MockDownstreamStore) ListDownstreamVersions(appID, clusterID string, opts types2.VersionHistoryOptions) ([]types2.DownstreamVersion, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListDownstreamVersions", appID, clusterID, opts)
+	ret0, _ := ret[0].([]types2.DownstreamVersion)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ListDownstreamVersions indicates an expected call of ListDownstreamVersions
+func (mr *MockDownstreamStoreMockRecorder) ListDownstreamVersions(appID, clusterID, opts interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListDownstreamVersions", reflect.TypeOf((*MockDownstreamStore)(nil).ListDownstreamVersions), appID, clusterID, opts)
+}
+
+// GetDownstreamOutput mocks base method
+func (m *MockDownstreamStore) GetDownstreamOutput(appID, clusterID string, sequence int64) (*types2.DownstreamOutput, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDownstreamOutput", appID, clusterID, sequence)
+	ret0, _ := ret[0].(*types2.DownstreamOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDownstreamOutput indicates an expected call of GetDownstreamOutput
+func (mr *MockDownstreamStoreMockRecorder) GetDownstreamOutput(appID, clusterID, sequence interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDownstreamOutput", reflect.TypeOf((*MockDownstreamStore)(nil).GetDownstreamOutput), appID, clusterID, sequence)
+}
+
+// IsDownstreamDeploySuccessful mocks base method
+func (m *MockDownstreamStore) IsDownstreamDeploySuccessful(appID, clusterID string, sequence int64) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsDownstreamDeploySuccessful", appID, clusterID, sequence)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IsDownstreamDeploySuccessful indicates an expected call of IsDownstreamDeploySuccessful
+func (mr *MockDownstreamStoreMockRecorder) IsDownstreamDeploySuccessful(appID, clusterID, sequence interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsDownstreamDeploySuccessful", reflect.TypeOf((*MockDownstreamStore)(nil).IsDownstreamDeploySuccessful), appID, clusterID, sequence)
+}
+
+// UpdateDownstreamDeployStatus mocks base method
+func (m *MockDownstreamStore) UpdateDownstreamDeployStatus(appID, clusterID string, sequence int64, isError bool, output types2.DownstreamOutput) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateDownstreamDeployStatus", appID, clusterID, sequence, isError, output)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateDownstreamDeployStatus indicates an expected call of UpdateDownstreamDeployStatus
+func (mr *MockDownstreamStoreMockRecorder) UpdateDownstreamDeployStatus(appID, clusterID, sequence, isError, output interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateDownstreamDeployStatus", reflect.TypeOf((*MockDownstreamStore)(nil).UpdateDownstreamDeployStatus), appID, clusterID, sequence, isError, output)
+}
+
+// DeleteDownstreamDeployStatus mocks base method
+func (m *MockDownstreamStore) DeleteDownstreamDeployStatus(appID, clusterID string, sequence int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteDownstreamDeployStatus", appID, clusterID, sequence)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteDownstreamDeployStatus indicates an expected call of DeleteDownstreamDeployStatus
+func (mr *MockDownstreamStoreMockRecorder) DeleteDownstreamDeployStatus(appID, clusterID, sequence interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteDownstreamDeployStatus", reflect.TypeOf((*MockDownstreamStore)(nil).DeleteDownstreamDeployStatus), appID, clusterID, sequence)
+}
+
+// GetDownstreamPostDeployTestResults mocks base method
+func (m *MockDownstreamStore) GetDownstreamPostDeployTestResults(appID, clusterID string, sequence int64) ([]types20.Result, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDownstreamPostDeployTestResults", appID, clusterID, sequence)
+	ret0, _ := ret[0].([]types20.Result)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDownstreamPostDeployTestResults indicates an expected call of GetDownstreamPostDeployTestResults
+func (mr *MockDownstreamStoreMockRecorder) GetDownstreamPostDeployTestResults(appID, clusterID, sequence interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDownstreamPostDeployTestResults", reflect.TypeOf((*MockDownstreamStore)(nil).GetDownstreamPostDeployTestResults), appID, clusterID, sequence)
+}
+
+// SetDownstreamPostDeployTestResults mocks base method
+func (m *MockDownstreamStore) SetDownstreamPostDeployTestResults(appID, clusterID string, sequence int64, results []types20.Result) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetDownstreamPostDeployTestResults", appID, clusterID, sequence, results)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetDownstreamPostDeployTestResults indicates an expected call of SetDownstreamPostDeployTestResults
+func (mr *MockDownstreamStoreMockRecorder) SetDownstreamPostDeployTestResults(appID, clusterID, sequence, results interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetDownstreamPostDeployTestResults", reflect.TypeOf((*MockDownstreamStore)(nil).SetDownstreamPostDeployTestResults), appID, clusterID, sequence, results)
+}
+
+// GetDownstreamAdmissionValidation mocks base method
+func (m *MockDownstreamStore) GetDownstreamAdmissionValidation(appID, clusterID string, sequence int64) (*types.Validation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDownstreamAdmissionValidation", appID, clusterID, sequence)
+	ret0, _ := ret[0].(*types.Validation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDownstreamAdmissionValidation indicates an expected call of GetDownstreamAdmissionValidation
+func (mr *MockDownstreamStoreMockRecorder) GetDownstreamAdmissionValidation(appID, clusterID, sequence interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDownstreamAdmissionValidation", reflect.TypeOf((*MockDownstreamStore)(nil).GetDownstreamAdmissionValidation), appID, clusterID, sequence)
+}
+
+// SetDownstreamAdmissionValidation mocks base method
+func (m *MockDownstreamStore) SetDownstreamAdmissionValidation(appID, clusterID string, sequence int64, validation types.Validation) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetDownstreamAdmissionValidation", appID, clusterID, sequence, validation)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetDownstreamAdmissionValidation indicates an expected call of SetDownstreamAdmissionValidation
+func (mr *MockDownstreamStoreMockRecorder) SetDownstreamAdmissionValidation(appID, clusterID, sequence, validation interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetDownstreamAdmissionValidation", reflect.TypeOf((*MockDownstreamStore)(nil).SetDownstreamAdmissionValidation), appID, clusterID, sequence, validation)
+}
+
+// GetDownstreamServiceAccount mocks base method
+func (m *MockDownstreamStore) GetDownstreamServiceAccount(appID, clusterID string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDownstreamServiceAccount", appID, clusterID)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDownstreamServiceAccount indicates an expected call of GetDownstreamServiceAccount
+func (mr *MockDownstreamStoreMockRecorder) GetDownstreamServiceAccount(appID, clusterID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDownstreamServiceAccount", reflect.TypeOf((*MockDownstreamStore)(nil).GetDownstreamServiceAccount), appID, clusterID)
+}
+
+// SetDownstreamServiceAccount mocks base method
+func (m *MockDownstreamStore) SetDownstreamServiceAccount(appID, clusterID, serviceAccount string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetDownstreamServiceAccount", appID, clusterID, serviceAccount)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetDownstreamServiceAccount indicates an expected call of SetDownstreamServiceAccount
+func (mr *MockDownstreamStoreMockRecorder) SetDownstreamServiceAccount(appID, clusterID, serviceAccount interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetDownstreamServiceAccount", reflect.TypeOf((*MockDownstreamStore)(nil).SetDownstreamServiceAccount), appID, clusterID, serviceAccount)
+}
+
+// CreateDownstreamOutputArchive mocks base method
+func (m *MockDownstreamStore) CreateDownstreamOutputArchive(appID, clusterID string, sequence int64, archivePath string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateDownstreamOutputArchive", appID, clusterID, sequence, archivePath)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateDownstreamOutputArchive indicates an expected call of CreateDownstreamOutputArchive
+func (mr *MockDownstreamStoreMockRecorder) CreateDownstreamOutputArchive(appID, clusterID, sequence, archivePath interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateDownstreamOutputArchive", reflect.TypeOf((*MockDownstreamStore)(nil).CreateDownstreamOutputArchive), appID, clusterID, sequence, archivePath)
+}
+
+// GetDownstreamOutputArchive mocks base method
+func (m *MockDownstreamStore) GetDownstreamOutputArchive(appID, clusterID string, sequence int64) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDownstreamOutputArchive", appID, clusterID, sequence)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDownstreamOutputArchive indicates an expected call of GetDownstreamOutputArchive
+func (mr *MockDownstreamStoreMockRecorder) GetDownstreamOutputArchive(appID, clusterID, sequence interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDownstreamOutputArchive", reflect.TypeOf((*MockDownstreamStore)(nil).GetDownstreamOutputArchive), appID, clusterID, sequence)
+}
+
+// DeleteDownstreamOutputArchivesBefore mocks base method
+func (m *MockDownstreamStore) DeleteDownstreamOutputArchivesBefore(appID, clusterID string, sequence int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteDownstreamOutputArchivesBefore", appID, clusterID, sequence)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteDownstreamOutputArchivesBefore indicates an expected call of DeleteDownstreamOutputArchivesBefore
+func (mr *MockDownstreamStoreMockRecorder) DeleteDownstreamOutputArchivesBefore(appID, clusterID, sequence interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteDownstreamOutputArchivesBefore", reflect.TypeOf((*MockDownstreamStore)(nil).DeleteDownstreamOutputArchivesBefore), appID, clusterID, sequence)
+}
+
+// GetActivatingSequence mocks base method
+func (m *MockDownstreamStore) GetActivatingSequence(appID, clusterID string) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetActivatingSequence", appID, clusterID)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetActivatingSequence indicates an expected call of GetActivatingSequence
+func (mr *MockDownstreamStoreMockRecorder) GetActivatingSequence(appID, clusterID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetActivatingSequence", reflect.TypeOf((*MockDownstreamStore)(nil).GetActivatingSequence), appID, clusterID)
+}
+
+// GetActivatedSequence mocks base method
+func (m *MockDownstreamStore) GetActivatedSequence(appID, clusterID string) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetActivatedSequence", appID, clusterID)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetActivatedSequence indicates an expected call of GetActivatedSequence
+func (mr *MockDownstreamStoreMockRecorder) GetActivatedSequence(appID, clusterID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetActivatedSequence", reflect.TypeOf((*MockDownstreamStore)(nil).GetActivatedSequence), appID, clusterID)
+}
+
+// SetDownstreamActivationResult mocks base method
+func (m *MockDownstreamStore) SetDownstreamActivationResult(appID, clusterID string, sequence int64, isError bool, statusInfo string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetDownstreamActivationResult", appID, clusterID, sequence, isError, statusInfo)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetDownstreamActivationResult indicates an expected call of SetDownstreamActivationResult
+func (mr *MockDownstreamStoreMockRecorder) SetDownstreamActivationResult(appID, clusterID, sequence, isError, statusInfo interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetDownstreamActivationResult", reflect.TypeOf((*MockDownstreamStore)(nil).SetDownstreamActivationResult), appID, clusterID, sequence, isError, statusInfo)
+}
+
+// MockSnapshotStore is a mock of SnapshotStore interface
+type MockSnapshotStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockSnapshotStoreMockRecorder
+}
+
+// MockSnapshotStoreMockRecorder is the mock recorder for MockSnapshotStore
+type MockSnapshotStoreMockRecorder struct {
+	mock *MockSnapshotStore
+}
+
+// NewMockSnapshotStore creates a new mock instance
+func NewMockSnapshotStore(ctrl *gomock.Controller) *MockSnapshotStore {
+	mock := &MockSnapshotStore{ctrl: ctrl}
+	mock.recorder = &MockSnapshotStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockSnapshotStore) EXPECT() *MockSnapshotStoreMockRecorder {
+	return m.recorder
+}
+
+// ListPendingScheduledSnapshots mocks base method
+func (m *MockSnapshotStore) ListPendingScheduledSnapshots(appID string) ([]types14.ScheduledSnapshot, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListPendingScheduledSnapshots", appID)
+	ret0, _ := ret[0].([]types14.ScheduledSnapshot)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListPendingScheduledSnapshots indicates an expected call of ListPendingScheduledSnapshots
+func (mr *MockSnapshotStoreMockRecorder) ListPendingScheduledSnapshots(appID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPendingScheduledSnapshots", reflect.TypeOf((*MockSnapshotStore)(nil).ListPendingScheduledSnapshots), appID)
+}
+
+// UpdateScheduledSnapshot mocks base method
+func (m *MockSnapshotStore) UpdateScheduledSnapshot(snapshotID, backupName string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateScheduledSnapshot", snapshotID, backupName)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateScheduledSnapshot indicates an expected call of UpdateScheduledSnapshot
+func (mr *MockSnapshotStoreMockRecorder) UpdateScheduledSnapshot(snapshotID, backupName interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateScheduledSnapshot", reflect.TypeOf((*MockSnapshotStore)(nil).UpdateScheduledSnapshot), snapshotID, backupName)
+}
+
+// DeletePendingScheduledSnapshots mocks base method
+func (m *MockSnapshotStore) DeletePendingScheduledSnapshots(appID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeletePendingScheduledSnapshots", appID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeletePendingScheduledSnapshots indicates an expected call of DeletePendingScheduledSnapshots
+func (mr *MockSnapshotStoreMockRecorder) DeletePendingScheduledSnapshots(appID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeletePendingScheduledSnapshots", reflect.TypeOf((*MockSnapshotStore)(nil).DeletePendingScheduledSnapshots), appID)
+}
+
+// CreateScheduledSnapshot mocks base method
+func (m *MockSnapshotStore) CreateScheduledSnapshot(snapshotID, appID string, timestamp time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateScheduledSnapshot", snapshotID, appID, timestamp)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateScheduledSnapshot indicates an expected call of CreateScheduledSnapshot
+func (mr *MockSnapshotStoreMockRecorder) CreateScheduledSnapshot(snapshotID, appID, timestamp interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateScheduledSnapshot", reflect.TypeOf((*MockSnapshotStore)(nil).CreateScheduledSnapshot), snapshotID, appID, timestamp)
+}
+
+// ListPendingScheduledInstanceSnapshots mocks base method
+func (m *MockSnapshotStore) ListPendingScheduledInstanceSnapshots(clusterID string) ([]types14.ScheduledInstanceSnapshot, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListPendingScheduledInstanceSnapshots", clusterID)
+	ret0, _ := ret[0].([]types14.ScheduledInstanceSnapshot)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListPendingScheduledInstanceSnapshots indicates an expected call of ListPendingScheduledInstanceSnapshots
+func (mr *MockSnapshotStoreMockRecorder) ListPendingScheduledInstanceSnapshots(clusterID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPendingScheduledInstanceSnapshots", reflect.TypeOf((*MockSnapshotStore)(nil).ListPendingScheduledInstanceSnapshots), clusterID)
+}
+
+// UpdateScheduledInstanceSnapshot mocks base method
+func (m *MockSnapshotStore) UpdateScheduledInstanceSnapshot(snapshotID, backupName string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateScheduledInstanceSnapshot", snapshotID, backupName)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateScheduledInstanceSnapshot indicates an expected call of UpdateScheduledInstanceSnapshot
+func (mr *MockSnapshotStoreMockRecorder) UpdateScheduledInstanceSnapshot(snapshotID, backupName interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateScheduledInstanceSnapshot", reflect.TypeOf((*MockSnapshotStore)(nil).UpdateScheduledInstanceSnapshot), snapshotID, backupName)
+}
+
+// DeletePendingScheduledInstanceSnapshots mocks base method
+func (m *MockSnapshotStore) DeletePendingScheduledInstanceSnapshots(clusterID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeletePendingScheduledInstanceSnapshots", clusterID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeletePendingScheduledInstanceSnapshots indicates an expected call of DeletePendingScheduledInstanceSnapshots
+func (mr *MockSnapshotStoreMockRecorder) DeletePendingScheduledInstanceSnapshots(clusterID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeletePendingScheduledInstanceSnapshots", reflect.TypeOf((*MockSnapshotStore)(nil).DeletePendingScheduledInstanceSnapshots), clusterID)
+}
+
+// CreateScheduledInstanceSnapshot mocks base method
+func (m *MockSnapshotStore) CreateScheduledInstanceSnapshot(snapshotID, clusterID string, timestamp time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateScheduledInstanceSnapshot", snapshotID, clusterID, timestamp)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateScheduledInstanceSnapshot indicates an expected call of CreateScheduledInstanceSnapshot
+func (mr *MockSnapshotStoreMockRecorder) CreateScheduledInstanceSnapshot(snapshotID, clusterID, timestamp interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateScheduledInstanceSnapshot", reflect.TypeOf((*MockSnapshotStore)(nil).CreateScheduledInstanceSnapshot), snapshotID, clusterID, timestamp)
+}
+
+// MockVersionStore is a mock of VersionStore interface
+type MockVersionStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockVersionStoreMockRecorder
+}
+
+// MockVersionStoreMockRecorder is the mock recorder for MockVersionStore
+type MockVersionStoreMockRecorder struct {
+	mock *MockVersionStore
+}
+
+// NewMockVersionStore creates a new mock instance
+func NewMockVersionStore(ctrl *gomock.Controller) *MockVersionStore {
+	mock := &MockVersionStore{ctrl: ctrl}
+	mock.recorder = &MockVersionStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockVersionStore) EXPECT() *MockVersionStoreMockRecorder {
+	return m.recorder
+}
+
+// IsIdentityServiceSupportedForVersion mocks base method
+func (m *MockVersionStore) IsIdentityServiceSupportedForVersion(appID string, sequence int64) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsIdentityServiceSupportedForVersion", appID, sequence)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IsIdentityServiceSupportedForVersion indicates an expected call of IsIdentityServiceSupportedForVersion
+func (mr *MockVersionStoreMockRecorder) IsIdentityServiceSupportedForVersion(appID, sequence interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsIdentityServiceSupportedForVersion", reflect.TypeOf((*MockVersionStore)(nil).IsIdentityServiceSupportedForVersion), appID, sequence)
+}
+
+// IsRollbackSupportedForVersion mocks base method
+func (m *MockVersionStore) IsRollbackSupportedForVersion(appID string, sequence int64) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsRollbackSupportedForVersion", appID, sequence)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IsRollbackSupportedForVersion indicates an expected call of IsRollbackSupportedForVersion
+func (mr *MockVersionStoreMockRecorder) IsRollbackSupportedForVersion(appID, sequence interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsRollbackSupportedForVersion", reflect.TypeOf((*MockVersionStore)(nil).IsRollbackSupportedForVersion), appID, sequence)
+}
+
+// IsSnapshotsSupportedForVersion mocks base method
+func (m *MockVersionStore) IsSnapshotsSupportedForVersion(a *types4.App, sequence int64, renderer types26.Renderer) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsSnapshotsSupportedForVersion", a, sequence, renderer)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IsSnapshotsSupportedForVersion indicates an expected call of IsSnapshotsSupportedForVersion
+func (mr *MockVersionStoreMockRecorder) IsSnapshotsSupportedForVersion(a, sequence, renderer interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsSnapshotsSupportedForVersion", reflect.TypeOf((*MockVersionStore)(nil).IsSnapshotsSupportedForVersion), a, sequence, renderer)
+}
+
+// GetAppVersionArchive mocks base method
+func (m *MockVersionStore) GetAppVersionArchive(appID string, sequence int64, dstPath string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAppVersionArchive", appID, sequence, dstPath)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// GetAppVersionArchive indicates an expected call of GetAppVersionArchive
+func (mr *MockVersionStoreMockRecorder) GetAppVersionArchive(appID, sequence, dstPath interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAppVersionArchive", reflect.TypeOf((*MockVersionStore)(nil).GetAppVersionArchive), appID, sequence, dstPath)
+}
+
+// GetAppVersionArchiveReader mocks base method
+func (m *MockVersionStore) GetAppVersionArchiveReader(appID string, sequence int64) (io.ReadCloser, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAppVersionArchiveReader", appID, sequence)
+	ret0, _ := ret[0].(io.ReadCloser)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAppVersionArchiveReader indicates an expected call of GetAppVersionArchiveReader
+func (mr *MockVersionStoreMockRecorder) GetAppVersionArchiveReader(appID, sequence interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAppVersionArchiveReader", reflect.TypeOf((*MockVersionStore)(nil).GetAppVersionArchiveReader), appID, sequence)
+}
+
+// CreateAppVersionArchive mocks base method
+func (m *MockVersionStore) CreateAppVersionArchive(appID string, sequence int64, archivePath string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateAppVersionArchive", appID, sequence, archivePath)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateAppVersionArchive indicates an expected call of CreateAppVersionArchive
+func (mr *MockVersionStoreMockRecorder) CreateAppVersionArchive(appID, sequence, archivePath interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAppVersionArchive", reflect.TypeOf((*MockVersionStore)(nil).CreateAppVersionArchive), appID, sequence, archivePath)
+}
+
+// DeleteOrphanedAppVersionArchives mocks base method
+func (m *MockVersionStore) DeleteOrphanedAppVersionArchives(olderThan time.Time) (int64, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteOrphanedAppVersionArchives", olderThan)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// DeleteOrphanedAppVersionArchives indicates an expected call of DeleteOrphanedAppVersionArchives
+func (mr *MockVersionStoreMockRecorder) DeleteOrphanedAppVersionArchives(olderThan interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteOrphanedAppVersionArchives", reflect.TypeOf((*MockVersionStore)(nil).DeleteOrphanedAppVersionArchives), olderThan)
+}
+
+// DeleteIncompleteAppVersions mocks base method
+func (m *MockVersionStore) DeleteIncompleteAppVersions(olderThan time.Time) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteIncompleteAppVersions", olderThan)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteIncompleteAppVersions indicates an expected call of DeleteIncompleteAppVersions
+func (mr *MockVersionStoreMockRecorder) DeleteIncompleteAppVersions(olderThan interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteIncompleteAppVersions", reflect.TypeOf((*MockVersionStore)(nil).DeleteIncompleteAppVersions), olderThan)
+}
+
+// CreateAppVersion mocks base method
+func (m *MockVersionStore) CreateAppVersion(appID string, currentSequence *int64, filesInDir, source string, skipPreflights bool, gitops types12.DownstreamGitOps) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateAppVersion", appID, currentSequence, filesInDir, source, skipPreflights, gitops)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateAppVersion indicates an expected call of CreateAppVersion
+func (mr *MockVersionStoreMockRecorder) CreateAppVersion(appID, currentSequence, filesInDir, source, skipPreflights, gitops interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAppVersion", reflect.TypeOf((*MockVersionStore)(nil).CreateAppVersion), appID, currentSequence, filesInDir, source, skipPreflights, gitops)
+}
+
+// GetAppVersion mocks base method
+func (m *MockVersionStore) GetAppVersion(arg0 string, arg1 int64) (*types3.AppVersion, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAppVersion", arg0, arg1)
+	ret0, _ := ret[0].(*types3.AppVersion)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAppVersion indicates an expected call of GetAppVersion
+func (mr *MockVersionStoreMockRecorder) GetAppVersion(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAppVersion", reflect.TypeOf((*MockVersionStore)(nil).GetAppVersion), arg0, arg1)
+}
+
+// GetAppVersionsAfter mocks base method
+func (m *MockVersionStore) GetAppVersionsAfter(arg0 string, arg1 int64) ([]*types3.AppVersion, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAppVersionsAfter", arg0, arg1)
+	ret0, _ := ret[0].([]*types3.AppVersion)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAppVersionsAfter indicates an expected call of GetAppVersionsAfter
+func (mr *MockVersionStoreMockRecorder) GetAppVersionsAfter(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAppVersionsAfter", reflect.TypeOf((*MockVersionStore)(nil).GetAppVersionsAfter), arg0, arg1)
+}
+
+// SetAppVersionNotes mocks base method
+func (m *MockVersionStore) SetAppVersionNotes(appID string, sequence int64, notes string, labels []string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetAppVersionNotes", appID, sequence, notes, labels)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetAppVersionNotes indicates an expected call of SetAppVersionNotes
+func (mr *MockVersionStoreMockRecorder) SetAppVersionNotes(appID, sequence, notes, labels interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetAppVersionNotes", reflect.TypeOf((*MockVersionStore)(nil).SetAppVersionNotes), appID, sequence, notes, labels)
+}
+
+// MockLicenseStore is a mock of LicenseStore interface
+type MockLicenseStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockLicenseStoreMockRecorder
+}
+
+// MockLicenseStoreMockRecorder is the mock recorder for MockLicenseStore
+type MockLicenseStoreMockRecorder struct {
+	mock *MockLicenseStore
+}
+
+// NewMockLicenseStore creates a new mock instance
+func NewMockLicenseStore(ctrl *gomock.Controller) *MockLicenseStore {
+	mock := &MockLicenseStore{ctrl: ctrl}
+	mock.recorder = &MockLicenseStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockLicenseStore) EXPECT() *MockLicenseStoreMockRecorder {
+	return m.recorder
+}
+
+// GetLatestLicenseForApp mocks base method
+func (m *MockLicenseStore) GetLatestLicenseForApp(appID string) (*v1beta1.License, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLatestLicenseForApp", appID)
+	ret0, _ := ret[0].(*v1beta1.License)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLatestLicenseForApp indicates an expected call of GetLatestLicenseForApp
+func (mr *MockLicenseStoreMockRecorder) GetLatestLicenseForApp(appID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLatestLicenseForApp", reflect.TypeOf((*MockLicenseStore)(nil).GetLatestLicenseForApp), appID)
+}
+
+// GetLicenseForAppVersion mocks base method
+func (m *MockLicenseStore) GetLicenseForAppVersion(appID string, sequence int64) (*v1beta1.License, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLicenseForAppVersion", appID, sequence)
+	ret0, _ := ret[0].(*v1beta1.License)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLicenseForAppVersion indicates an expected call of GetLicenseForAppVersion
+func (mr *MockLicenseStoreMockRecorder) GetLicenseForAppVersion(appID, sequence interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLicenseForAppVersion", reflect.TypeOf((*MockLicenseStore)(nil).GetLicenseForAppVersion), appID, sequence)
+}
+
+// GetAllAppLicenses mocks base method
+func (m *MockLicenseStore) GetAllAppLicenses() ([]*v1beta1.License, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAllAppLicenses")
+	ret0, _ := ret[0].([]*v1beta1.License)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAllAppLicenses indicates an expected call of GetAllAppLicenses
+func (mr *MockLicenseStoreMockRecorder) GetAllAppLicenses() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllAppLicenses", reflect.TypeOf((*MockLicenseStore)(nil).GetAllAppLicenses))
+}
+
+// UpdateAppLicense mocks base method
+func (m *MockLicenseStore) UpdateAppLicense(appID string, sequence int64, archiveDir string, newLicense *v1beta1.License, originalLicenseData string, failOnVersionCreate bool, gitops types12.DownstreamGitOps, renderer types26.Renderer) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateAppLicense", appID, sequence, archiveDir, newLicense, originalLicenseData, failOnVersionCreate, gitops, renderer)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateAppLicense indicates an expected call of UpdateAppLicense
+func (mr *MockLicenseStoreMockRecorder) UpdateAppLicense(appID, sequence, archiveDir, newLicense, originalLicenseData, failOnVersionCreate, gitops, renderer interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateAppLicense", reflect.TypeOf((*MockLicenseStore)(nil).UpdateAppLicense), appID, sequence, archiveDir, newLicense, originalLicenseData, failOnVersionCreate, gitops, renderer)
+}
+
+// ArchiveAppLicense mocks base method
+func (m *MockLicenseStore) ArchiveAppLicense(appID, replacedByLicenseID, replacedBy string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ArchiveAppLicense", appID, replacedByLicenseID, replacedBy)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ArchiveAppLicense indicates an expected call of ArchiveAppLicense
+func (mr *MockLicenseStoreMockRecorder) ArchiveAppLicense(appID, replacedByLicenseID, replacedBy interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ArchiveAppLicense", reflect.TypeOf((*MockLicenseStore)(nil).ArchiveAppLicense), appID, replacedByLicenseID, replacedBy)
+}
+
+// MockClusterStore is a mock of ClusterStore interface
+type MockClusterStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockClusterStoreMockRecorder
+}
+
+// MockClusterStoreMockRecorder is the mock recorder for MockClusterStore
+type MockClusterStoreMockRecorder struct {
+	mock *MockClusterStore
+}
+
+// NewMockClusterStore creates a new mock instance
+func NewMockClusterStore(ctrl *gomock.Controller) *MockClusterStore {
+	mock := &MockClusterStore{ctrl: ctrl}
+	mock.recorder = &MockClusterStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockClusterStore) EXPECT() *MockClusterStoreMockRecorder {
+	return m.recorder
+}
+
+// ListClusters mocks base method
+func (m *MockClusterStore) ListClusters() ([]*types2.Downstream, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListClusters")
+	ret0, _ := ret[0].([]*types2.Downstream)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListClusters indicates an expected call of ListClusters
+func (mr *MockClusterStoreMockRecorder) ListClusters() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListClusters", reflect.TypeOf((*MockClusterStore)(nil).ListClusters))
+}
+
+// GetClusterIDFromSlug mocks base method
+func (m *MockClusterStore) GetClusterIDFromSlug(slug string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetClusterIDFromSlug", slug)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetClusterIDFromSlug indicates an expected call of GetClusterIDFromSlug
+func (mr *MockClusterStoreMockRecorder) GetClusterIDFromSlug(slug interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetClusterIDFromSlug", reflect.TypeOf((*MockClusterStore)(nil).GetClusterIDFromSlug), slug)
+}
+
+// GetClusterIDFromDeployToken mocks base method
+func (m *MockClusterStore) GetClusterIDFromDeployToken(deployToken string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetClusterIDFromDeployToken", deployToken)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetClusterIDFromDeployToken indicates an expected call of GetClusterIDFromDeployToken
+func (mr *MockClusterStoreMockRecorder) GetClusterIDFromDeployToken(deployToken interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetClusterIDFromDeployToken", reflect.TypeOf((*MockClusterStore)(nil).GetClusterIDFromDeployToken), deployToken)
+}
+
+// CreateNewCluster mocks base method
+func (m *MockClusterStore) CreateNewCluster(userID string, isAllUsers bool, title, token string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateNewCluster", userID, isAllUsers, title, token)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateNewCluster indicates an expected call of CreateNewCluster
+func (mr *MockClusterStoreMockRecorder) CreateNewCluster(userID, isAllUsers, title, token interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateNewCluster", reflect.TypeOf((*MockClusterStore)(nil).CreateNewCluster), userID, isAllUsers, title, token)
+}
+
+// SetInstanceSnapshotTTL mocks base method
+func (m *MockClusterStore) SetInstanceSnapshotTTL(clusterID, snapshotTTL string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetInstanceSnapshotTTL", clusterID, snapshotTTL)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetInstanceSnapshotTTL indicates an expected call of SetInstanceSnapshotTTL
+func (mr *MockClusterStoreMockRecorder) SetInstanceSnapshotTTL(clusterID, snapshotTTL interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetInstanceSnapshotTTL", reflect.TypeOf((*MockClusterStore)(nil).SetInstanceSnapshotTTL), clusterID, snapshotTTL)
+}
+
+// SetInstanceSnapshotSchedule mocks base method
+func (m *MockClusterStore) SetInstanceSnapshotSchedule(clusterID, snapshotSchedule string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetInstanceSnapshotSchedule", clusterID, snapshotSchedule)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetInstanceSnapshotSchedule indicates an expected call of SetInstanceSnapshotSchedule
+func (mr *MockClusterStoreMockRecorder) SetInstanceSnapshotSchedule(clusterID, snapshotSchedule interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetInstanceSnapshotSchedule", reflect.TypeOf((*MockClusterStore)(nil).SetInstanceSnapshotSchedule), clusterID, snapshotSchedule)
+}
+
+// MockInstallationStore is a mock of InstallationStore interface
+type MockInstallationStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockInstallationStoreMockRecorder
+}
+
+// MockInstallationStoreMockRecorder is the mock recorder for MockInstallationStore
+type MockInstallationStoreMockRecorder struct {
+	mock *MockInstallationStore
+}
+
+// NewMockInstallationStore creates a new mock instance
+func NewMockInstallationStore(ctrl *gomock.Controller) *MockInstallationStore {
+	mock := &MockInstallationStore{ctrl: ctrl}
+	mock.recorder = &MockInstallationStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockInstallationStore) EXPECT() *MockInstallationStoreMockRecorder {
+	return m.recorder
+}
+
+// GetPendingInstallationStatus mocks base method
+func (m *MockInstallationStore) GetPendingInstallationStatus() (*types19.InstallStatus, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPendingInstallationStatus")
+	ret0, _ := ret[0].(*types19.InstallStatus)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPendingInstallationStatus indicates an expected call of GetPendingInstallationStatus
+func (mr *MockInstallationStoreMockRecorder) GetPendingInstallationStatus() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPendingInstallationStatus", reflect.TypeOf((*MockInstallationStore)(nil).GetPendingInstallationStatus))
+}
+
+// MockKotsadmParamsStore is a mock of KotsadmParamsStore interface
+type MockKotsadmParamsStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockKotsadmParamsStoreMockRecorder
+}
+
+// MockKotsadmParamsStoreMockRecorder is the mock recorder for MockKotsadmParamsStore
+type MockKotsadmParamsStoreMockRecorder struct {
+	mock *MockKotsadmParamsStore
+}
+
+// NewMockKotsadmParamsStore creates a new mock instance
+func NewMockKotsadmParamsStore(ctrl *gomock.Controller) *MockKotsadmParamsStore {
+	mock := &MockKotsadmParamsStore{ctrl: ctrl}
+	mock.recorder = &MockKotsadmParamsStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockKotsadmParamsStore) EXPECT() *MockKotsadmParamsStoreMockRecorder {
+	return m.recorder
+}
+
+// IsKotsadmIDGenerated mocks base method
+func (m *MockKotsadmParamsStore) IsKotsadmIDGenerated() (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsKotsadmIDGenerated")
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IsKotsadmIDGenerated indicates an expected call of IsKotsadmIDGenerated
+func (mr *MockKotsadmParamsStoreMockRecorder) IsKotsadmIDGenerated() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsKotsadmIDGenerated", reflect.TypeOf((*MockKotsadmParamsStore)(nil).IsKotsadmIDGenerated))
+}
+
+// SetIsKotsadmIDGenerated mocks base method
+func (m *MockKotsadmParamsStore) SetIsKotsadmIDGenerated() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetIsKotsadmIDGenerated")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetIsKotsadmIDGenerated indicates an expected call of SetIsKotsadmIDGenerated
+func (mr *MockKotsadmParamsStoreMockRecorder) SetIsKotsadmIDGenerated() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetIsKotsadmIDGenerated", reflect.TypeOf((*MockKotsadmParamsStore)(nil).SetIsKotsadmIDGenerated))
+}
+
+// IsReadOnlyMode mocks base method
+func (m *MockKotsadmParamsStore) IsReadOnlyMode() (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsReadOnlyMode")
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IsReadOnlyMode indicates an expected call of IsReadOnlyMode
+func (mr *MockKotsadmParamsStoreMockRecorder) IsReadOnlyMode() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsReadOnlyMode", reflect.TypeOf((*MockKotsadmParamsStore)(nil).IsReadOnlyMode))
+}
+
+// SetReadOnlyMode mocks base method
+func (m *MockKotsadmParamsStore) SetReadOnlyMode(enabled bool) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetReadOnlyMode", enabled)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetReadOnlyMode indicates an expected call of SetReadOnlyMode
+func (mr *MockKotsadmParamsStoreMockRecorder) SetReadOnlyMode(enabled interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetReadOnlyMode", reflect.TypeOf((*MockKotsadmParamsStore)(nil).SetReadOnlyMode), enabled)
+}
+
+// InitReadOnlyMode mocks base method
+func (m *MockKotsadmParamsStore) InitReadOnlyMode(enabled bool) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InitReadOnlyMode", enabled)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// InitReadOnlyMode indicates an expected call of InitReadOnlyMode
+func (mr *MockKotsadmParamsStoreMockRecorder) InitReadOnlyMode(enabled interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InitReadOnlyMode", reflect.TypeOf((*MockKotsadmParamsStore)(nil).InitReadOnlyMode), enabled)
+}
+
+// MockMeteringStore is a mock of MeteringStore interface
+type MockMeteringStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockMeteringStoreMockRecorder
+}
+
+// MockMeteringStoreMockRecorder is the mock recorder for MockMeteringStore
+type MockMeteringStoreMockRecorder struct {
+	mock *MockMeteringStore
+}
+
+// NewMockMeteringStore creates a new mock instance
+func NewMockMeteringStore(ctrl *gomock.Controller) *MockMeteringStore {
+	mock := &MockMeteringStore{ctrl: ctrl}
+	mock.recorder = &MockMeteringStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockMeteringStore) EXPECT() *MockMeteringStoreMockRecorder {
+	return m.recorder
+}
+
+// SetEntitlementUsage mocks base method
+func (m *MockMeteringStore) SetEntitlementUsage(appID, name, source string, value int64, reportedAt time.Time, limit *int64) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetEntitlementUsage", appID, name, source, value, reportedAt, limit)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetEntitlementUsage indicates an expected call of SetEntitlementUsage
+func (mr *MockMeteringStoreMockRecorder) SetEntitlementUsage(appID, name, source, value, reportedAt, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetEntitlementUsage", reflect.TypeOf((*MockMeteringStore)(nil).SetEntitlementUsage), appID, name, source, value, reportedAt, limit)
+}
+
+// ListEntitlementUsage mocks base method
+func (m *MockMeteringStore) ListEntitlementUsage(appID string) ([]types18.EntitlementUsage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListEntitlementUsage", appID)
+	ret0, _ := ret[0].([]types18.EntitlementUsage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListEntitlementUsage indicates an expected call of ListEntitlementUsage
+func (mr *MockMeteringStoreMockRecorder) ListEntitlementUsage(appID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListEntitlementUsage", reflect.TypeOf((*MockMeteringStore)(nil).ListEntitlementUsage), appID)
+}
+
+// MockConfigFileStore is a mock of ConfigFileStore interface
+type MockConfigFileStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockConfigFileStoreMockRecorder
+}
+
+// MockConfigFileStoreMockRecorder is the mock recorder for MockConfigFileStore
+type MockConfigFileStoreMockRecorder struct {
+	mock *MockConfigFileStore
+}
+
+// NewMockConfigFileStore creates a new mock instance
+func NewMockConfigFileStore(ctrl *gomock.Controller) *MockConfigFileStore {
+	mock := &MockConfigFileStore{ctrl: ctrl}
+	mock.recorder = &MockConfigFileStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockConfigFileStore) EXPECT() *MockConfigFileStoreMockRecorder {
+	return m.recorder
+}
+
+// CreateConfigFile mocks base method
+func (m *MockConfigFileStore) CreateConfigFile(checksum, filePath string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateConfigFile", checksum, filePath)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateConfigFile indicates an expected call of CreateConfigFile
+func (mr *MockConfigFileStoreMockRecorder) CreateConfigFile(checksum, filePath interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateConfigFile", reflect.TypeOf((*MockConfigFileStore)(nil).CreateConfigFile), checksum, filePath)
+}
+
+// GetConfigFile mocks base method
+func (m *MockConfigFileStore) GetConfigFile(checksum string) (io.ReadCloser, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetConfigFile", checksum)
+	ret0, _ := ret[0].(io.ReadCloser)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetConfigFile indicates an expected call of GetConfigFile
+func (mr *MockConfigFileStoreMockRecorder) GetConfigFile(checksum interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetConfigFile", reflect.TypeOf((*MockConfigFileStore)(nil).GetConfigFile), checksum)
+}
+
+// MockUpdateDownloadStore is a mock of UpdateDownloadStore interface
+type MockUpdateDownloadStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockUpdateDownloadStoreMockRecorder
+}
+
+// MockUpdateDownloadStoreMockRecorder is the mock recorder for MockUpdateDownloadStore
+type MockUpdateDownloadStoreMockRecorder struct {
+	mock *MockUpdateDownloadStore
+}
+
+// NewMockUpdateDownloadStore creates a new mock instance
+func NewMockUpdateDownloadStore(ctrl *gomock.Controller) *MockUpdateDownloadStore {
+	mock := &MockUpdateDownloadStore{ctrl: ctrl}
+	mock.recorder = &MockUpdateDownloadStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockUpdateDownloadStore) EXPECT() *MockUpdateDownloadStoreMockRecorder {
+	return m.recorder
+}
+
+// SetUpdateDownloadFailure mocks base method
+func (m *MockUpdateDownloadStore) SetUpdateDownloadFailure(appID string, failure types32.UpdateDownloadFailure) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetUpdateDownloadFailure", appID, failure)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetUpdateDownloadFailure indicates an expected call of SetUpdateDownloadFailure
+func (mr *MockUpdateDownloadStoreMockRecorder) SetUpdateDownloadFailure(appID, failure interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetUpdateDownloadFailure", reflect.TypeOf((*MockUpdateDownloadStore)(nil).SetUpdateDownloadFailure), appID, failure)
+}
+
+// ListUpdateDownloadFailures mocks base method
+func (m *MockUpdateDownloadStore) ListUpdateDownloadFailures(appID string) ([]types32.UpdateDownloadFailure, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListUpdateDownloadFailures", appID)
+	ret0, _ := ret[0].([]types32.UpdateDownloadFailure)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListUpdateDownloadFailures indicates an expected call of ListUpdateDownloadFailures
+func (mr *MockUpdateDownloadStoreMockRecorder) ListUpdateDownloadFailures(appID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListUpdateDownloadFailures", reflect.TypeOf((*MockUpdateDownloadStore)(nil).ListUpdateDownloadFailures), appID)
+}
+
+// ClearUpdateDownloadFailures mocks base method
+func (m *MockUpdateDownloadStore) ClearUpdateDownloadFailures(appID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClearUpdateDownloadFailures", appID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ClearUpdateDownloadFailures indicates an expected call of ClearUpdateDownloadFailures
+func (mr *MockUpdateDownloadStoreMockRecorder) ClearUpdateDownloadFailures(appID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClearUpdateDownloadFailures", reflect.TypeOf((*MockUpdateDownloadStore)(nil).ClearUpdateDownloadFailures), appID)
+}
+
+// MockImageReportStore is a mock of ImageReportStore interface
+type MockImageReportStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockImageReportStoreMockRecorder
+}
+
+// MockImageReportStoreMockRecorder is the mock recorder for MockImageReportStore
+type MockImageReportStoreMockRecorder struct {
+	mock *MockImageReportStore
+}
+
+// NewMockImageReportStore creates a new mock instance
+func NewMockImageReportStore(ctrl *gomock.Controller) *MockImageReportStore {
+	mock := &MockImageReportStore{ctrl: ctrl}
+	mock.recorder = &MockImageReportStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockImageReportStore) EXPECT() *MockImageReportStoreMockRecorder {
+	return m.recorder
+}
+
+// GetImageReport mocks base method
+func (m *MockImageReportStore) GetImageReport(appID string, sequence int64) (*types13.Report, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetImageReport", appID, sequence)
+	ret0, _ := ret[0].(*types13.Report)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetImageReport indicates an expected call of GetImageReport
+func (mr *MockImageReportStoreMockRecorder) GetImageReport(appID, sequence interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetImageReport", reflect.TypeOf((*MockImageReportStore)(nil).GetImageReport), appID, sequence)
+}
+
+// SetImageReport mocks base method
+func (m *MockImageReportStore) SetImageReport(appID string, report types13.Report) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetImageReport", appID, report)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetImageReport indicates an expected call of SetImageReport
+func (mr *MockImageReportStoreMockRecorder) SetImageReport(appID, report interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetImageReport", reflect.TypeOf((*MockImageReportStore)(nil).SetImageReport), appID, report)
+}
+
+// MockAssetCacheStore is a mock of AssetCacheStore interface
+type MockAssetCacheStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockAssetCacheStoreMockRecorder
+}
+
+// MockAssetCacheStoreMockRecorder is the mock recorder for MockAssetCacheStore
+type MockAssetCacheStoreMockRecorder struct {
+	mock *MockAssetCacheStore
+}
+
+// NewMockAssetCacheStore creates a new mock instance
+func NewMockAssetCacheStore(ctrl *gomock.Controller) *MockAssetCacheStore {
+	mock := &MockAssetCacheStore{ctrl: ctrl}
+	mock.recorder = &MockAssetCacheStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockAssetCacheStore) EXPECT() *MockAssetCacheStoreMockRecorder {
+	return m.recorder
+}
+
+// RegisterCachedAsset mocks base method
+func (m *MockAssetCacheStore) RegisterCachedAsset(cacheKey, uri string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RegisterCachedAsset", cacheKey, uri)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RegisterCachedAsset indicates an expected call of RegisterCachedAsset
+func (mr *MockAssetCacheStoreMockRecorder) RegisterCachedAsset(cacheKey, uri interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetSnapshotTTL", reflect.TypeOf((*MockAppStore)(nil).SetSnapshotTTL), appID, snapshotTTL)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RegisterCachedAsset", reflect.TypeOf((*MockAssetCacheStore)(nil).RegisterCachedAsset), cacheKey, uri)
 }
 
-// SetSnapshotSchedule mocks base method
-func (m *MockAppStore) SetSnapshotSchedule(appID, snapshotSchedule string) error {
+// GetCachedAsset mocks base method
+func (m *MockAssetCacheStore) GetCachedAsset(cacheKey string) (*types6.Asset, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "SetSnapshotSchedule", appID, snapshotSchedule)
+	ret := m.ctrl.Call(m, "GetCachedAsset", cacheKey)
+	ret0, _ := ret[0].(*types6.Asset)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCachedAsset indicates an expected call of GetCachedAsset
+func (mr *MockAssetCacheStoreMockRecorder) GetCachedAsset(cacheKey interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCachedAsset", reflect.TypeOf((*MockAssetCacheStore)(nil).GetCachedAsset), cacheKey)
+}
+
+// SetCachedAsset mocks base method
+func (m *MockAssetCacheStore) SetCachedAsset(asset types6.Asset) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetCachedAsset", asset)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-// SetSnapshotSchedule indicates an expected call of SetSnapshotSchedule
-func (mr *MockAppStoreMockRecorder) SetSnapshotSchedule(appID, snapshotSchedule interface{}) *gomock.Call {
+// SetCachedAsset indicates an expected call of SetCachedAsset
+func (mr *MockAssetCacheStoreMockRecorder) SetCachedAsset(asset interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetSnapshotSchedule", reflect.TypeOf((*MockAppStore)(nil).SetSnapshotSchedule), appID, snapshotSchedule)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetCachedAsset", reflect.TypeOf((*MockAssetCacheStore)(nil).SetCachedAsset), asset)
 }
 
-// RemoveApp mocks base method
-func (m *MockAppStore) RemoveApp(appID string) error {
+// MockMaintenanceStore is a mock of MaintenanceStore interface
+type MockMaintenanceStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockMaintenanceStoreMockRecorder
+}
+
+// MockMaintenanceStoreMockRecorder is the mock recorder for MockMaintenanceStore
+type MockMaintenanceStoreMockRecorder struct {
+	mock *MockMaintenanceStore
+}
+
+// NewMockMaintenanceStore creates a new mock instance
+func NewMockMaintenanceStore(ctrl *gomock.Controller) *MockMaintenanceStore {
+	mock := &MockMaintenanceStore{ctrl: ctrl}
+	mock.recorder = &MockMaintenanceStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockMaintenanceStore) EXPECT() *MockMaintenanceStoreMockRecorder {
+	return m.recorder
+}
+
+// GetGlobalMaintenanceMessage mocks base method
+func (m *MockMaintenanceStore) GetGlobalMaintenanceMessage() (*types17.MaintenanceMessage, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "RemoveApp", appID)
+	ret := m.ctrl.Call(m, "GetGlobalMaintenanceMessage")
+	ret0, _ := ret[0].(*types17.MaintenanceMessage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetGlobalMaintenanceMessage indicates an expected call of GetGlobalMaintenanceMessage
+func (mr *MockMaintenanceStoreMockRecorder) GetGlobalMaintenanceMessage() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetGlobalMaintenanceMessage", reflect.TypeOf((*MockMaintenanceStore)(nil).GetGlobalMaintenanceMessage))
+}
+
+// SetGlobalMaintenanceMessage mocks base method
+func (m *MockMaintenanceStore) SetGlobalMaintenanceMessage(message *types17.MaintenanceMessage) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetGlobalMaintenanceMessage", message)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-// RemoveApp indicates an expected call of RemoveApp
-func (mr *MockAppStoreMockRecorder) RemoveApp(appID interface{}) *gomock.Call {
+// SetGlobalMaintenanceMessage indicates an expected call of SetGlobalMaintenanceMessage
+func (mr *MockMaintenanceStoreMockRecorder) SetGlobalMaintenanceMessage(message interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveApp", reflect.TypeOf((*MockAppStore)(nil).RemoveApp), appID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetGlobalMaintenanceMessage", reflect.TypeOf((*MockMaintenanceStore)(nil).SetGlobalMaintenanceMessage), message)
 }
 
-// MockDownstreamStore is a mock of DownstreamStore interface
-type MockDownstreamStore struct {
+// GetAppMaintenanceMessage mocks base method
+func (m *MockMaintenanceStore) GetAppMaintenanceMessage(appID string) (*types17.MaintenanceMessage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAppMaintenanceMessage", appID)
+	ret0, _ := ret[0].(*types17.MaintenanceMessage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAppMaintenanceMessage indicates an expected call of GetAppMaintenanceMessage
+func (mr *MockMaintenanceStoreMockRecorder) GetAppMaintenanceMessage(appID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAppMaintenanceMessage", reflect.TypeOf((*MockMaintenanceStore)(nil).GetAppMaintenanceMessage), appID)
+}
+
+// SetAppMaintenanceMessage mocks base method
+func (m *MockMaintenanceStore) SetAppMaintenanceMessage(appID string, message *types17.MaintenanceMessage) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetAppMaintenanceMessage", appID, message)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetAppMaintenanceMessage indicates an expected call of SetAppMaintenanceMessage
+func (mr *MockMaintenanceStoreMockRecorder) SetAppMaintenanceMessage(appID, message interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetAppMaintenanceMessage", reflect.TypeOf((*MockMaintenanceStore)(nil).SetAppMaintenanceMessage), appID, message)
+}
+
+// MockFreezeStore is a mock of FreezeStore interface
+type MockFreezeStore struct {
 	ctrl     *gomock.Controller
-	recorder *MockDownstreamStoreMockRecorder
+	recorder *MockFreezeStoreMockRecorder
+}
+
+// MockFreezeStoreMockRecorder is the mock recorder for MockFreezeStore
+type MockFreezeStoreMockRecorder struct {
+	mock *MockFreezeStore
+}
+
+// NewMockFreezeStore creates a new mock instance
+func NewMockFreezeStore(ctrl *gomock.Controller) *MockFreezeStore {
+	mock := &MockFreezeStore{ctrl: ctrl}
+	mock.recorder = &MockFreezeStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockFreezeStore) EXPECT() *MockFreezeStoreMockRecorder {
+	return m.recorder
+}
+
+// GetGlobalFreeze mocks base method
+func (m *MockFreezeStore) GetGlobalFreeze() (*types11.Freeze, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetGlobalFreeze")
+	ret0, _ := ret[0].(*types11.Freeze)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetGlobalFreeze indicates an expected call of GetGlobalFreeze
+func (mr *MockFreezeStoreMockRecorder) GetGlobalFreeze() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetGlobalFreeze", reflect.TypeOf((*MockFreezeStore)(nil).GetGlobalFreeze))
+}
+
+// SetGlobalFreeze mocks base method
+func (m *MockFreezeStore) SetGlobalFreeze(freeze *types11.Freeze) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetGlobalFreeze", freeze)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetGlobalFreeze indicates an expected call of SetGlobalFreeze
+func (mr *MockFreezeStoreMockRecorder) SetGlobalFreeze(freeze interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetGlobalFreeze", reflect.TypeOf((*MockFreezeStore)(nil).SetGlobalFreeze), freeze)
+}
+
+// GetAppFreeze mocks base method
+func (m *MockFreezeStore) GetAppFreeze(appID string) (*types11.Freeze, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAppFreeze", appID)
+	ret0, _ := ret[0].(*types11.Freeze)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAppFreeze indicates an expected call of GetAppFreeze
+func (mr *MockFreezeStoreMockRecorder) GetAppFreeze(appID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAppFreeze", reflect.TypeOf((*MockFreezeStore)(nil).GetAppFreeze), appID)
+}
+
+// SetAppFreeze mocks base method
+func (m *MockFreezeStore) SetAppFreeze(appID string, freeze *types11.Freeze) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetAppFreeze", appID, freeze)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetAppFreeze indicates an expected call of SetAppFreeze
+func (mr *MockFreezeStoreMockRecorder) SetAppFreeze(appID, freeze interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetAppFreeze", reflect.TypeOf((*MockFreezeStore)(nil).SetAppFreeze), appID, freeze)
+}
+
+// MockReleaseFeedbackStore is a mock of ReleaseFeedbackStore interface
+type MockReleaseFeedbackStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockReleaseFeedbackStoreMockRecorder
+}
+
+// MockReleaseFeedbackStoreMockRecorder is the mock recorder for MockReleaseFeedbackStore
+type MockReleaseFeedbackStoreMockRecorder struct {
+	mock *MockReleaseFeedbackStore
+}
+
+// NewMockReleaseFeedbackStore creates a new mock instance
+func NewMockReleaseFeedbackStore(ctrl *gomock.Controller) *MockReleaseFeedbackStore {
+	mock := &MockReleaseFeedbackStore{ctrl: ctrl}
+	mock.recorder = &MockReleaseFeedbackStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockReleaseFeedbackStore) EXPECT() *MockReleaseFeedbackStoreMockRecorder {
+	return m.recorder
+}
+
+// GetReleaseFeedbackConsent mocks base method
+func (m *MockReleaseFeedbackStore) GetReleaseFeedbackConsent(appID string) (*types24.Consent, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetReleaseFeedbackConsent", appID)
+	ret0, _ := ret[0].(*types24.Consent)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetReleaseFeedbackConsent indicates an expected call of GetReleaseFeedbackConsent
+func (mr *MockReleaseFeedbackStoreMockRecorder) GetReleaseFeedbackConsent(appID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetReleaseFeedbackConsent", reflect.TypeOf((*MockReleaseFeedbackStore)(nil).GetReleaseFeedbackConsent), appID)
+}
+
+// SetReleaseFeedbackConsent mocks base method
+func (m *MockReleaseFeedbackStore) SetReleaseFeedbackConsent(appID string, consent types24.Consent) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetReleaseFeedbackConsent", appID, consent)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetReleaseFeedbackConsent indicates an expected call of SetReleaseFeedbackConsent
+func (mr *MockReleaseFeedbackStoreMockRecorder) SetReleaseFeedbackConsent(appID, consent interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetReleaseFeedbackConsent", reflect.TypeOf((*MockReleaseFeedbackStore)(nil).SetReleaseFeedbackConsent), appID, consent)
+}
+
+// MockTemporaryAccessStore is a mock of TemporaryAccessStore interface
+type MockTemporaryAccessStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockTemporaryAccessStoreMockRecorder
+}
+
+// MockTemporaryAccessStoreMockRecorder is the mock recorder for MockTemporaryAccessStore
+type MockTemporaryAccessStoreMockRecorder struct {
+	mock *MockTemporaryAccessStore
+}
+
+// NewMockTemporaryAccessStore creates a new mock instance
+func NewMockTemporaryAccessStore(ctrl *gomock.Controller) *MockTemporaryAccessStore {
+	mock := &MockTemporaryAccessStore{ctrl: ctrl}
+	mock.recorder = &MockTemporaryAccessStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockTemporaryAccessStore) EXPECT() *MockTemporaryAccessStoreMockRecorder {
+	return m.recorder
+}
+
+// CreateTemporaryAccess mocks base method
+func (m *MockTemporaryAccessStore) CreateTemporaryAccess(grant types31.Grant, tokenSHA256 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateTemporaryAccess", grant, tokenSHA256)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateTemporaryAccess indicates an expected call of CreateTemporaryAccess
+func (mr *MockTemporaryAccessStoreMockRecorder) CreateTemporaryAccess(grant, tokenSHA256 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateTemporaryAccess", reflect.TypeOf((*MockTemporaryAccessStore)(nil).CreateTemporaryAccess), grant, tokenSHA256)
+}
+
+// ListTemporaryAccess mocks base method
+func (m *MockTemporaryAccessStore) ListTemporaryAccess() ([]types31.Grant, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListTemporaryAccess")
+	ret0, _ := ret[0].([]types31.Grant)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListTemporaryAccess indicates an expected call of ListTemporaryAccess
+func (mr *MockTemporaryAccessStoreMockRecorder) ListTemporaryAccess() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTemporaryAccess", reflect.TypeOf((*MockTemporaryAccessStore)(nil).ListTemporaryAccess))
+}
+
+// GetTemporaryAccess mocks base method
+func (m *MockTemporaryAccessStore) GetTemporaryAccess(id string) (*types31.Grant, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTemporaryAccess", id)
+	ret0, _ := ret[0].(*types31.Grant)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTemporaryAccess indicates an expected call of GetTemporaryAccess
+func (mr *MockTemporaryAccessStoreMockRecorder) GetTemporaryAccess(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTemporaryAccess", reflect.TypeOf((*MockTemporaryAccessStore)(nil).GetTemporaryAccess), id)
+}
+
+// GetTemporaryAccessByToken mocks base method
+func (m *MockTemporaryAccessStore) GetTemporaryAccessByToken(tokenSHA256 string) (*types31.Grant, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTemporaryAccessByToken", tokenSHA256)
+	ret0, _ := ret[0].(*types31.Grant)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTemporaryAccessByToken indicates an expected call of GetTemporaryAccessByToken
+func (mr *MockTemporaryAccessStoreMockRecorder) GetTemporaryAccessByToken(tokenSHA256 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTemporaryAccessByToken", reflect.TypeOf((*MockTemporaryAccessStore)(nil).GetTemporaryAccessByToken), tokenSHA256)
+}
+
+// AddTemporaryAccessSession mocks base method
+func (m *MockTemporaryAccessStore) AddTemporaryAccessSession(id, sessionID string, usedAt time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddTemporaryAccessSession", id, sessionID, usedAt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddTemporaryAccessSession indicates an expected call of AddTemporaryAccessSession
+func (mr *MockTemporaryAccessStoreMockRecorder) AddTemporaryAccessSession(id, sessionID, usedAt interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddTemporaryAccessSession", reflect.TypeOf((*MockTemporaryAccessStore)(nil).AddTemporaryAccessSession), id, sessionID, usedAt)
+}
+
+// RevokeTemporaryAccess mocks base method
+func (m *MockTemporaryAccessStore) RevokeTemporaryAccess(id, revokedBy string, revokedAt time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeTemporaryAccess", id, revokedBy, revokedAt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokeTemporaryAccess indicates an expected call of RevokeTemporaryAccess
+func (mr *MockTemporaryAccessStoreMockRecorder) RevokeTemporaryAccess(id, revokedBy, revokedAt interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeTemporaryAccess", reflect.TypeOf((*MockTemporaryAccessStore)(nil).RevokeTemporaryAccess), id, revokedBy, revokedAt)
+}
+
+// MockDeployApprovalStore is a mock of DeployApprovalStore interface
+type MockDeployApprovalStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockDeployApprovalStoreMockRecorder
+}
+
+// MockDeployApprovalStoreMockRecorder is the mock recorder for MockDeployApprovalStore
+type MockDeployApprovalStoreMockRecorder struct {
+	mock *MockDeployApprovalStore
+}
+
+// NewMockDeployApprovalStore creates a new mock instance
+func NewMockDeployApprovalStore(ctrl *gomock.Controller) *MockDeployApprovalStore {
+	mock := &MockDeployApprovalStore{ctrl: ctrl}
+	mock.recorder = &MockDeployApprovalStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockDeployApprovalStore) EXPECT() *MockDeployApprovalStoreMockRecorder {
+	return m.recorder
+}
+
+// CreateDeployApproval mocks base method
+func (m *MockDeployApprovalStore) CreateDeployApproval(approval types8.DeployApproval) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateDeployApproval", approval)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateDeployApproval indicates an expected call of CreateDeployApproval
+func (mr *MockDeployApprovalStoreMockRecorder) CreateDeployApproval(approval interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateDeployApproval", reflect.TypeOf((*MockDeployApprovalStore)(nil).CreateDeployApproval), approval)
+}
+
+// GetDeployApproval mocks base method
+func (m *MockDeployApprovalStore) GetDeployApproval(approvalID string) (*types8.DeployApproval, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDeployApproval", approvalID)
+	ret0, _ := ret[0].(*types8.DeployApproval)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDeployApproval indicates an expected call of GetDeployApproval
+func (mr *MockDeployApprovalStoreMockRecorder) GetDeployApproval(approvalID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDeployApproval", reflect.TypeOf((*MockDeployApprovalStore)(nil).GetDeployApproval), approvalID)
+}
+
+// GetPendingDeployApproval mocks base method
+func (m *MockDeployApprovalStore) GetPendingDeployApproval(appID string, sequence int64) (*types8.DeployApproval, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPendingDeployApproval", appID, sequence)
+	ret0, _ := ret[0].(*types8.DeployApproval)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPendingDeployApproval indicates an expected call of GetPendingDeployApproval
+func (mr *MockDeployApprovalStoreMockRecorder) GetPendingDeployApproval(appID, sequence interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPendingDeployApproval", reflect.TypeOf((*MockDeployApprovalStore)(nil).GetPendingDeployApproval), appID, sequence)
+}
+
+// ListDeployApprovals mocks base method
+func (m *MockDeployApprovalStore) ListDeployApprovals(appID string) ([]types8.DeployApproval, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListDeployApprovals", appID)
+	ret0, _ := ret[0].([]types8.DeployApproval)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListDeployApprovals indicates an expected call of ListDeployApprovals
+func (mr *MockDeployApprovalStoreMockRecorder) ListDeployApprovals(appID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListDeployApprovals", reflect.TypeOf((*MockDeployApprovalStore)(nil).ListDeployApprovals), appID)
+}
+
+// SetDeployApprovalDecision mocks base method
+func (m *MockDeployApprovalStore) SetDeployApprovalDecision(approvalID string, status types8.Status, decidedBy string, decidedAt time.Time) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetDeployApprovalDecision", approvalID, status, decidedBy, decidedAt)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetDeployApprovalDecision indicates an expected call of SetDeployApprovalDecision
+func (mr *MockDeployApprovalStoreMockRecorder) SetDeployApprovalDecision(approvalID, status, decidedBy, decidedAt interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetDeployApprovalDecision", reflect.TypeOf((*MockDeployApprovalStore)(nil).SetDeployApprovalDecision), approvalID, status, decidedBy, decidedAt)
+}
+
+// ReopenDeployApproval mocks base method
+func (m *MockDeployApprovalStore) ReopenDeployApproval(approvalID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReopenDeployApproval", approvalID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ReopenDeployApproval indicates an expected call of ReopenDeployApproval
+func (mr *MockDeployApprovalStoreMockRecorder) ReopenDeployApproval(approvalID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReopenDeployApproval", reflect.TypeOf((*MockDeployApprovalStore)(nil).ReopenDeployApproval), approvalID)
+}
+
+// MockAppLockStore is a mock of AppLockStore interface
+type MockAppLockStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockAppLockStoreMockRecorder
 }
 
-// MockDownstreamStoreMockRecorder is the mock recorder for MockDownstreamStore
-type MockDownstreamStoreMockRecorder struct {
-	mock *MockDownstreamStore
+// MockAppLockStoreMockRecorder is the mock recorder for MockAppLockStore
+type MockAppLockStoreMockRecorder struct {
+	mock *MockAppLockStore
 }
 
-// NewMockDownstreamStore creates a new mock instance
-func NewMockDownstreamStore(ctrl *gomock.Controller) *MockDownstreamStore {
-	mock := &MockDownstreamStore{ctrl: ctrl}
-	mock.recorder = &MockDownstreamStoreMockRecorder{mock}
+// NewMockAppLockStore creates a new mock instance
+func NewMockAppLockStore(ctrl *gomock.Controller) *MockAppLockStore {
+	mock := &MockAppLockStore{ctrl: ctrl}
+	mock.recorder = &MockAppLockStoreMockRecorder{mock}
 	return mock
 }
 
 // EXPECT returns an object that allows the caller to indicate expected use
-func (m *MockDownstreamStore) EXPECT() *MockDownstreamStoreMockRecorder {
+func (m *MockAppLockStore) EXPECT() *MockAppLockStoreMockRecorder {
 	return m.recorder
 }
 
-// GetCurrentSequence mocks base method
-func (m *MockDownstreamStore) GetCurrentSequence(appID, clusterID string) (int64, error) {
+// AcquireAppLock mocks base method
+func (m *MockAppLockStore) AcquireAppLock(appID, holder string, expiresAt time.Time) (bool, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetCurrentSequence", appID, clusterID)
-	ret0, _ := ret[0].(int64)
+	ret := m.ctrl.Call(m, "AcquireAppLock", appID, holder, expiresAt)
+	ret0, _ := ret[0].(bool)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetCurrentSequence indicates an expected call of GetCurrentSequence
-func (mr *MockDownstreamStoreMockRecorder) GetCurrentSequence(appID, clusterID interface{}) *gomock.Call {
+// AcquireAppLock indicates an expected call of AcquireAppLock
+func (mr *MockAppLockStoreMockRecorder) AcquireAppLock(appID, holder, expiresAt interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCurrentSequence", reflect.TypeOf((*MockDownstreamStore)(nil).GetCurrentSequence), appID, clusterID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AcquireAppLock", reflect.TypeOf((*MockAppLockStore)(nil).AcquireAppLock), appID, holder, expiresAt)
 }
 
-// GetCurrentParentSequence mocks base method
-func (m *MockDownstreamStore) GetCurrentParentSequence(appID, clusterID string) (int64, error) {
+// RefreshAppLock mocks base method
+func (m *MockAppLockStore) RefreshAppLock(appID, holder string, expiresAt time.Time) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetCurrentParentSequence", appID, clusterID)
-	ret0, _ := ret[0].(int64)
-	ret1, _ := ret[1].(error)
-	return ret0, ret1
+	ret := m.ctrl.Call(m, "RefreshAppLock", appID, holder, expiresAt)
+	ret0, _ := ret[0].(error)
+	return ret0
 }
 
-// GetCurrentParentSequence indicates an expected call of GetCurrentParentSequence
-func (mr *MockDownstreamStoreMockRecorder) GetCurrentParentSequence(appID, clusterID interface{}) *gomock.Call {
+// RefreshAppLock indicates an expected call of RefreshAppLock
+func (mr *MockAppLockStoreMockRecorder) RefreshAppLock(appID, holder, expiresAt interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCurrentParentSequence", reflect.TypeOf((*MockDownstreamStore)(nil).GetCurrentParentSequence), appID, clusterID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RefreshAppLock", reflect.TypeOf((*MockAppLockStore)(nil).RefreshAppLock), appID, holder, expiresAt)
 }
 
-// GetParentSequenceForSequence mocks base method
-func (m *MockDownstreamStore) GetParentSequenceForSequence(appID, clusterID string, sequence int64) (int64, error) {
+// ReleaseAppLock mocks base method
+func (m *MockAppLockStore) ReleaseAppLock(appID, holder string) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetParentSequenceForSequence", appID, clusterID, sequence)
-	ret0, _ := ret[0].(int64)
-	ret1, _ := ret[1].(error)
-	return ret0, ret1
+	ret := m.ctrl.Call(m, "ReleaseAppLock", appID, holder)
+	ret0, _ := ret[0].(error)
+	return ret0
 }
 
-// GetParentSequenceForSequence indicates an expected call of GetParentSequenceForSequence
-func (mr *MockDownstreamStoreMockRecorder) GetParentSequenceForSequence(appID, clusterID, sequence interface{}) *gomock.Call {
+// ReleaseAppLock indicates an expected call of ReleaseAppLock
+func (mr *MockAppLockStoreMockRecorder) ReleaseAppLock(appID, holder interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetParentSequenceForSequence", reflect.TypeOf((*MockDownstreamStore)(nil).GetParentSequenceForSequence), appID, clusterID, sequence)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReleaseAppLock", reflect.TypeOf((*MockAppLockStore)(nil).ReleaseAppLock), appID, holder)
 }
 
-// GetPreviouslyDeployedSequence mocks base method
-func (m *MockDownstreamStore) GetPreviouslyDeployedSequence(appID, clusterID string) (int64, error) {
+// MockUploadQuotaStore is a mock of UploadQuotaStore interface
+type MockUploadQuotaStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockUploadQuotaStoreMockRecorder
+}
+
+// MockUploadQuotaStoreMockRecorder is the mock recorder for MockUploadQuotaStore
+type MockUploadQuotaStoreMockRecorder struct {
+	mock *MockUploadQuotaStore
+}
+
+// NewMockUploadQuotaStore creates a new mock instance
+func NewMockUploadQuotaStore(ctrl *gomock.Controller) *MockUploadQuotaStore {
+	mock := &MockUploadQuotaStore{ctrl: ctrl}
+	mock.recorder = &MockUploadQuotaStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockUploadQuotaStore) EXPECT() *MockUploadQuotaStoreMockRecorder {
+	return m.recorder
+}
+
+// GetUploadQuota mocks base method
+func (m *MockUploadQuotaStore) GetUploadQuota() (*types33.UploadQuota, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetPreviouslyDeployedSequence", appID, clusterID)
-	ret0, _ := ret[0].(int64)
+	ret := m.ctrl.Call(m, "GetUploadQuota")
+	ret0, _ := ret[0].(*types33.UploadQuota)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetPreviouslyDeployedSequence indicates an expected call of GetPreviouslyDeployedSequence
-func (mr *MockDownstreamStoreMockRecorder) GetPreviouslyDeployedSequence(appID, clusterID interface{}) *gomock.Call {
+// GetUploadQuota indicates an expected call of GetUploadQuota
+func (mr *MockUploadQuotaStoreMockRecorder) GetUploadQuota() *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPreviouslyDeployedSequence", reflect.TypeOf((*MockDownstreamStore)(nil).GetPreviouslyDeployedSequence), appID, clusterID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUploadQuota", reflect.TypeOf((*MockUploadQuotaStore)(nil).GetUploadQuota))
 }
 
-// SetDownstreamVersionReady mocks base method
-func (m *MockDownstreamStore) SetDownstreamVersionReady(appID string, sequence int64) error {
+// SetUploadQuota mocks base method
+func (m *MockUploadQuotaStore) SetUploadQuota(quota types33.UploadQuota) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "SetDownstreamVersionReady", appID, sequence)
+	ret := m.ctrl.Call(m, "SetUploadQuota", quota)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-// SetDownstreamVersionReady indicates an expected call of SetDownstreamVersionReady
-func (mr *MockDownstreamStoreMockRecorder) SetDownstreamVersionReady(appID, sequence interface{}) *gomock.Call {
+// SetUploadQuota indicates an expected call of SetUploadQuota
+func (mr *MockUploadQuotaStoreMockRecorder) SetUploadQuota(quota interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetDownstreamVersionReady", reflect.TypeOf((*MockDownstreamStore)(nil).SetDownstreamVersionReady), appID, sequence)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetUploadQuota", reflect.TypeOf((*MockUploadQuotaStore)(nil).SetUploadQuota), quota)
 }
 
-// SetDownstreamVersionPendingPreflight mocks base method
-func (m *MockDownstreamStore) SetDownstreamVersionPendingPreflight(appID string, sequence int64) error {
-	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "SetDownstreamVersionPendingPreflight", appID, sequence)
-	ret0, _ := ret[0].(error)
-	return ret0
+// MockSessionSettingsStore is a mock of SessionSettingsStore interface
+type MockSessionSettingsStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockSessionSettingsStoreMockRecorder
 }
 
-// SetDownstreamVersionPendingPreflight indicates an expected call of SetDownstreamVersionPendingPreflight
-func (mr *MockDownstreamStoreMockRecorder) SetDownstreamVersionPendingPreflight(appID, sequence interface{}) *gomock.Call {
-	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetDownstreamVersionPendingPreflight", reflect.TypeOf((*MockDownstreamStore)(nil).SetDownstreamVersionPendingPreflight), appID, sequence)
+// MockSessionSettingsStoreMockRecorder is the mock recorder for MockSessionSettingsStore
+type MockSessionSettingsStoreMockRecorder struct {
+	mock *MockSessionSettingsStore
 }
 
-// UpdateDownstreamVersionStatus mocks base method
-func (m *MockDownstreamStore) UpdateDownstreamVersionStatus(appID string, sequence int64, status, statusInfo string) error {
-	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "UpdateDownstreamVersionStatus", appID, sequence, status, statusInfo)
-	ret0, _ := ret[0].(error)
-	return ret0
+// NewMockSessionSettingsStore creates a new mock instance
+func NewMockSessionSettingsStore(ctrl *gomock.Controller) *MockSessionSettingsStore {
+	mock := &MockSessionSettingsStore{ctrl: ctrl}
+	mock.recorder = &MockSessionSettingsStoreMockRecorder{mock}
+	return mock
 }
 
-// UpdateDownstreamVersionStatus indicates an expected call of UpdateDownstreamVersionStatus
-func (mr *MockDownstreamStoreMockRecorder) UpdateDownstreamVersionStatus(appID, sequence, status, statusInfo interface{}) *gomock.Call {
-	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateDownstreamVersionStatus", reflect.TypeOf((*MockDownstreamStore)(nil).UpdateDownstreamVersionStatus), appID, sequence, status, statusInfo)
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockSessionSettingsStore) EXPECT() *MockSessionSettingsStoreMockRecorder {
+	return m.recorder
 }
 
-// GetDownstreamVersionStatus mocks base method
-func (m *MockDownstreamStore) GetDownstreamVersionStatus(appID string, sequence int64) (string, error) {
+// GetSessionSettings mocks base method
+func (m *MockSessionSettingsStore) GetSessionSettings() (*types29.SessionSettings, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetDownstreamVersionStatus", appID, sequence)
-	ret0, _ := ret[0].(string)
+	ret := m.ctrl.Call(m, "GetSessionSettings")
+	ret0, _ := ret[0].(*types29.SessionSettings)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetDownstreamVersionStatus indicates an expected call of GetDownstreamVersionStatus
-func (mr *MockDownstreamStoreMockRecorder) GetDownstreamVersionStatus(appID, sequence interface{}) *gomock.Call {
+// GetSessionSettings indicates an expected call of GetSessionSettings
+func (mr *MockSessionSettingsStoreMockRecorder) GetSessionSettings() *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDownstreamVersionStatus", reflect.TypeOf((*MockDownstreamStore)(nil).GetDownstreamVersionStatus), appID, sequence)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSessionSettings", reflect.TypeOf((*MockSessionSettingsStore)(nil).GetSessionSettings))
 }
 
-// GetIgnoreRBACErrors mocks base method
-func (m *MockDownstreamStore) GetIgnoreRBACErrors(appID string, sequence int64) (bool, error) {
+// SetSessionSettings mocks base method
+func (m *MockSessionSettingsStore) SetSessionSettings(settings types29.SessionSettings) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetIgnoreRBACErrors", appID, sequence)
-	ret0, _ := ret[0].(bool)
-	ret1, _ := ret[1].(error)
-	return ret0, ret1
+	ret := m.ctrl.Call(m, "SetSessionSettings", settings)
+	ret0, _ := ret[0].(error)
+	return ret0
 }
 
-// GetIgnoreRBACErrors indicates an expected call of GetIgnoreRBACErrors
-func (mr *MockDownstreamStoreMockRecorder) GetIgnoreRBACErrors(appID, sequence interface{}) *gomock.Call {
+// SetSessionSettings indicates an expected call of SetSessionSettings
+func (mr *MockSessionSettingsStoreMockRecorder) SetSessionSettings(settings interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetIgnoreRBACErrors", reflect.TypeOf((*MockDownstreamStore)(nil).GetIgnoreRBACErrors), appID, sequence)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetSessionSettings", reflect.TypeOf((*MockSessionSettingsStore)(nil).SetSessionSettings), settings)
 }
 
-// GetCurrentVersion mocks base method
-func (m *MockDownstreamStore) GetCurrentVersion(appID, clusterID string) (*types1.DownstreamVersion, error) {
+// InitSessionSettings mocks base method
+func (m *MockSessionSettingsStore) InitSessionSettings(settings types29.SessionSettings) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetCurrentVersion", appID, clusterID)
-	ret0, _ := ret[0].(*types1.DownstreamVersion)
-	ret1, _ := ret[1].(error)
-	return ret0, ret1
+	ret := m.ctrl.Call(m, "InitSessionSettings", settings)
+	ret0, _ := ret[0].(error)
+	return ret0
 }
 
-// GetCurrentVersion indicates an expected call of GetCurrentVersion
-func (mr *MockDownstreamStoreMockRecorder) GetCurrentVersion(appID, clusterID interface{}) *gomock.Call {
+// InitSessionSettings indicates an expected call of InitSessionSettings
+func (mr *MockSessionSettingsStoreMockRecorder) InitSessionSettings(settings interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCurrentVersion", reflect.TypeOf((*MockDownstreamStore)(nil).GetCurrentVersion), appID, clusterID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InitSessionSettings", reflect.TypeOf((*MockSessionSettingsStore)(nil).InitSessionSettings), settings)
 }
 
-// GetStatusForVersion mocks base method
-func (m *MockDownstreamStore) GetStatusForVersion(appID, clusterID string, sequence int64) (string, error) {
-	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetStatusForVersion", appID, clusterID, sequence)
-	ret0, _ := ret[0].(string)
-	ret1, _ := ret[1].(error)
-	return ret0, ret1
+// MockLDAPSettingsStore is a mock of LDAPSettingsStore interface
+type MockLDAPSettingsStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockLDAPSettingsStoreMockRecorder
 }
 
-// GetStatusForVersion indicates an expected call of GetStatusForVersion
-func (mr *MockDownstreamStoreMockRecorder) GetStatusForVersion(appID, clusterID, sequence interface{}) *gomock.Call {
-	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetStatusForVersion", reflect.TypeOf((*MockDownstreamStore)(nil).GetStatusForVersion), appID, clusterID, sequence)
+// MockLDAPSettingsStoreMockRecorder is the mock recorder for MockLDAPSettingsStore
+type MockLDAPSettingsStoreMockRecorder struct {
+	mock *MockLDAPSettingsStore
 }
 
-// GetPendingVersions mocks base method
-func (m *MockDownstreamStore) GetPendingVersions(appID, clusterID string) ([]types1.DownstreamVersion, error) {
-	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetPendingVersions", appID, clusterID)
-	ret0, _ := ret[0].([]types1.DownstreamVersion)
-	ret1, _ := ret[1].(error)
-	return ret0, ret1
+// NewMockLDAPSettingsStore creates a new mock instance
+func NewMockLDAPSettingsStore(ctrl *gomock.Controller) *MockLDAPSettingsStore {
+	mock := &MockLDAPSettingsStore{ctrl: ctrl}
+	mock.recorder = &MockLDAPSettingsStoreMockRecorder{mock}
+	return mock
 }
 
-// GetPendingVersions indicates an expected call of GetPendingVersions
-func (mr *MockDownstreamStoreMockRecorder) GetPendingVersions(appID, clusterID interface{}) *gomock.Call {
-	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPendingVersions", reflect.TypeOf((*MockDownstreamStore)(nil).GetPendingVersions), appID, clusterID)
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockLDAPSettingsStore) EXPECT() *MockLDAPSettingsStoreMockRecorder {
+	return m.recorder
 }
 
-// GetPastVersions mocks base method
-func (m *MockDownstreamStore) GetPastVersions(appID, clusterID string) ([]types1.DownstreamVersion, error) {
+// GetLDAPSettings mocks base method
+func (m *MockLDAPSettingsStore) GetLDAPSettings() (*types15.Settings, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetPastVersions", appID, clusterID)
-	ret0, _ := ret[0].([]types1.DownstreamVersion)
+	ret := m.ctrl.Call(m, "GetLDAPSettings")
+	ret0, _ := ret[0].(*types15.Settings)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetPastVersions indicates an expected call of GetPastVersions
-func (mr *MockDownstreamStoreMockRecorder) GetPastVersions(appID, clusterID interface{}) *gomock.Call {
+// GetLDAPSettings indicates an expected call of GetLDAPSettings
+func (mr *MockLDAPSettingsStoreMockRecorder) GetLDAPSettings() *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPastVersions", reflect.TypeOf((*MockDownstreamStore)(nil).GetPastVersions), appID, clusterID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLDAPSettings", reflect.TypeOf((*MockLDAPSettingsStore)(nil).GetLDAPSettings))
 }
 
-// GetDownstreamOutput mocks base method
-func (m *MockDownstreamStore) GetDownstreamOutput(appID, clusterID string, sequence int64) (*types1.DownstreamOutput, error) {
+// SetLDAPSettings mocks base method
+func (m *MockLDAPSettingsStore) SetLDAPSettings(settings types15.Settings) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetDownstreamOutput", appID, clusterID, sequence)
-	ret0, _ := ret[0].(*types1.DownstreamOutput)
-	ret1, _ := ret[1].(error)
-	return ret0, ret1
+	ret := m.ctrl.Call(m, "SetLDAPSettings", settings)
+	ret0, _ := ret[0].(error)
+	return ret0
 }
 
-// GetDownstreamOutput indicates an expected call of GetDownstreamOutput
-func (mr *MockDownstreamStoreMockRecorder) GetDownstreamOutput(appID, clusterID, sequence interface{}) *gomock.Call {
+// SetLDAPSettings indicates an expected call of SetLDAPSettings
+func (mr *MockLDAPSettingsStoreMockRecorder) SetLDAPSettings(settings interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDownstreamOutput", reflect.TypeOf((*MockDownstreamStore)(nil).GetDownstreamOutput), appID, clusterID, sequence)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetLDAPSettings", reflect.TypeOf((*MockLDAPSettingsStore)(nil).SetLDAPSettings), settings)
 }
 
-// IsDownstreamDeploySuccessful mocks base method
-func (m *MockDownstreamStore) IsDownstreamDeploySuccessful(appID, clusterID string, sequence int64) (bool, error) {
-	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "IsDownstreamDeploySuccessful", appID, clusterID, sequence)
-	ret0, _ := ret[0].(bool)
-	ret1, _ := ret[1].(error)
-	return ret0, ret1
+// MockLogSettingsStore is a mock of LogSettingsStore interface
+type MockLogSettingsStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockLogSettingsStoreMockRecorder
 }
 
-// IsDownstreamDeploySuccessful indicates an expected call of IsDownstreamDeploySuccessful
-func (mr *MockDownstreamStoreMockRecorder) IsDownstreamDeploySuccessful(appID, clusterID, sequence interface{}) *gomock.Call {
-	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsDownstreamDeploySuccessful", reflect.TypeOf((*MockDownstreamStore)(nil).IsDownstreamDeploySuccessful), appID, clusterID, sequence)
+// MockLogSettingsStoreMockRecorder is the mock recorder for MockLogSettingsStore
+type MockLogSettingsStoreMockRecorder struct {
+	mock *MockLogSettingsStore
 }
 
-// UpdateDownstreamDeployStatus mocks base method
-func (m *MockDownstreamStore) UpdateDownstreamDeployStatus(appID, clusterID string, sequence int64, isError bool, output types1.DownstreamOutput) error {
+// NewMockLogSettingsStore creates a new mock instance
+func NewMockLogSettingsStore(ctrl *gomock.Controller) *MockLogSettingsStore {
+	mock := &MockLogSettingsStore{ctrl: ctrl}
+	mock.recorder = &MockLogSettingsStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockLogSettingsStore) EXPECT() *MockLogSettingsStoreMockRecorder {
+	return m.recorder
+}
+
+// GetLogSettings mocks base method
+func (m *MockLogSettingsStore) GetLogSettings() (*types16.Settings, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "UpdateDownstreamDeployStatus", appID, clusterID, sequence, isError, output)
-	ret0, _ := ret[0].(error)
-	return ret0
+	ret := m.ctrl.Call(m, "GetLogSettings")
+	ret0, _ := ret[0].(*types16.Settings)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
-// UpdateDownstreamDeployStatus indicates an expected call of UpdateDownstreamDeployStatus
-func (mr *MockDownstreamStoreMockRecorder) UpdateDownstreamDeployStatus(appID, clusterID, sequence, isError, output interface{}) *gomock.Call {
+// GetLogSettings indicates an expected call of GetLogSettings
+func (mr *MockLogSettingsStoreMockRecorder) GetLogSettings() *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateDownstreamDeployStatus", reflect.TypeOf((*MockDownstreamStore)(nil).UpdateDownstreamDeployStatus), appID, clusterID, sequence, isError, output)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLogSettings", reflect.TypeOf((*MockLogSettingsStore)(nil).GetLogSettings))
 }
 
-// DeleteDownstreamDeployStatus mocks base method
-func (m *MockDownstreamStore) DeleteDownstreamDeployStatus(appID, clusterID string, sequence int64) error {
+// SetLogSettings mocks base method
+func (m *MockLogSettingsStore) SetLogSettings(settings types16.Settings) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeleteDownstreamDeployStatus", appID, clusterID, sequence)
+	ret := m.ctrl.Call(m, "SetLogSettings", settings)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-// DeleteDownstreamDeployStatus indicates an expected call of DeleteDownstreamDeployStatus
-func (mr *MockDownstreamStoreMockRecorder) DeleteDownstreamDeployStatus(appID, clusterID, sequence interface{}) *gomock.Call {
+// SetLogSettings indicates an expected call of SetLogSettings
+func (mr *MockLogSettingsStoreMockRecorder) SetLogSettings(settings interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteDownstreamDeployStatus", reflect.TypeOf((*MockDownstreamStore)(nil).DeleteDownstreamDeployStatus), appID, clusterID, sequence)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetLogSettings", reflect.TypeOf((*MockLogSettingsStore)(nil).SetLogSettings), settings)
 }
 
-// MockSnapshotStore is a mock of SnapshotStore interface
-type MockSnapshotStore struct {
+// MockRestoreDrillStore is a mock of RestoreDrillStore interface
+type MockRestoreDrillStore struct {
 	ctrl     *gomock.Controller
-	recorder *MockSnapshotStoreMockRecorder
+	recorder *MockRestoreDrillStoreMockRecorder
 }
 
-// MockSnapshotStoreMockRecorder is the mock recorder for MockSnapshotStore
-type MockSnapshotStoreMockRecorder struct {
-	mock *MockSnapshotStore
+// MockRestoreDrillStoreMockRecorder is the mock recorder for MockRestoreDrillStore
+type MockRestoreDrillStoreMockRecorder struct {
+	mock *MockRestoreDrillStore
 }
 
-// NewMockSnapshotStore creates a new mock instance
-func NewMockSnapshotStore(ctrl *gomock.Controller) *MockSnapshotStore {
-	mock := &MockSnapshotStore{ctrl: ctrl}
-	mock.recorder = &MockSnapshotStoreMockRecorder{mock}
+// NewMockRestoreDrillStore creates a new mock instance
+func NewMockRestoreDrillStore(ctrl *gomock.Controller) *MockRestoreDrillStore {
+	mock := &MockRestoreDrillStore{ctrl: ctrl}
+	mock.recorder = &MockRestoreDrillStoreMockRecorder{mock}
 	return mock
 }
 
 // EXPECT returns an object that allows the caller to indicate expected use
-func (m *MockSnapshotStore) EXPECT() *MockSnapshotStoreMockRecorder {
+func (m *MockRestoreDrillStore) EXPECT() *MockRestoreDrillStoreMockRecorder {
 	return m.recorder
 }
 
-// ListPendingScheduledSnapshots mocks base method
-func (m *MockSnapshotStore) ListPendingScheduledSnapshots(appID string) ([]types5.ScheduledSnapshot, error) {
+// ListRestoreDrills mocks base method
+func (m *MockRestoreDrillStore) ListRestoreDrills(appID string) ([]types27.Drill, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ListPendingScheduledSnapshots", appID)
-	ret0, _ := ret[0].([]types5.ScheduledSnapshot)
+	ret := m.ctrl.Call(m, "ListRestoreDrills", appID)
+	ret0, _ := ret[0].([]types27.Drill)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// ListPendingScheduledSnapshots indicates an expected call of ListPendingScheduledSnapshots
-func (mr *MockSnapshotStoreMockRecorder) ListPendingScheduledSnapshots(appID interface{}) *gomock.Call {
+// ListRestoreDrills indicates an expected call of ListRestoreDrills
+func (mr *MockRestoreDrillStoreMockRecorder) ListRestoreDrills(appID interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPendingScheduledSnapshots", reflect.TypeOf((*MockSnapshotStore)(nil).ListPendingScheduledSnapshots), appID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListRestoreDrills", reflect.TypeOf((*MockRestoreDrillStore)(nil).ListRestoreDrills), appID)
 }
 
-// UpdateScheduledSnapshot mocks base method
-func (m *MockSnapshotStore) UpdateScheduledSnapshot(snapshotID, backupName string) error {
+// CreateRestoreDrill mocks base method
+func (m *MockRestoreDrillStore) CreateRestoreDrill(drill types27.Drill) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "UpdateScheduledSnapshot", snapshotID, backupName)
+	ret := m.ctrl.Call(m, "CreateRestoreDrill", drill)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-// UpdateScheduledSnapshot indicates an expected call of UpdateScheduledSnapshot
-func (mr *MockSnapshotStoreMockRecorder) UpdateScheduledSnapshot(snapshotID, backupName interface{}) *gomock.Call {
+// CreateRestoreDrill indicates an expected call of CreateRestoreDrill
+func (mr *MockRestoreDrillStoreMockRecorder) CreateRestoreDrill(drill interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateScheduledSnapshot", reflect.TypeOf((*MockSnapshotStore)(nil).UpdateScheduledSnapshot), snapshotID, backupName)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateRestoreDrill", reflect.TypeOf((*MockRestoreDrillStore)(nil).CreateRestoreDrill), drill)
 }
 
-// DeletePendingScheduledSnapshots mocks base method
-func (m *MockSnapshotStore) DeletePendingScheduledSnapshots(appID string) error {
+// UpdateRestoreDrill mocks base method
+func (m *MockRestoreDrillStore) UpdateRestoreDrill(drill types27.Drill) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeletePendingScheduledSnapshots", appID)
+	ret := m.ctrl.Call(m, "UpdateRestoreDrill", drill)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-// DeletePendingScheduledSnapshots indicates an expected call of DeletePendingScheduledSnapshots
-func (mr *MockSnapshotStoreMockRecorder) DeletePendingScheduledSnapshots(appID interface{}) *gomock.Call {
+// UpdateRestoreDrill indicates an expected call of UpdateRestoreDrill
+func (mr *MockRestoreDrillStoreMockRecorder) UpdateRestoreDrill(drill interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeletePendingScheduledSnapshots", reflect.TypeOf((*MockSnapshotStore)(nil).DeletePendingScheduledSnapshots), appID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateRestoreDrill", reflect.TypeOf((*MockRestoreDrillStore)(nil).UpdateRestoreDrill), drill)
 }
 
-// CreateScheduledSnapshot mocks base method
-func (m *MockSnapshotStore) CreateScheduledSnapshot(snapshotID, appID string, timestamp time.Time) error {
+// DeleteScheduledRestoreDrills mocks base method
+func (m *MockRestoreDrillStore) DeleteScheduledRestoreDrills(appID string) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreateScheduledSnapshot", snapshotID, appID, timestamp)
+	ret := m.ctrl.Call(m, "DeleteScheduledRestoreDrills", appID)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-// CreateScheduledSnapshot indicates an expected call of CreateScheduledSnapshot
-func (mr *MockSnapshotStoreMockRecorder) CreateScheduledSnapshot(snapshotID, appID, timestamp interface{}) *gomock.Call {
+// DeleteScheduledRestoreDrills indicates an expected call of DeleteScheduledRestoreDrills
+func (mr *MockRestoreDrillStoreMockRecorder) DeleteScheduledRestoreDrills(appID interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateScheduledSnapshot", reflect.TypeOf((*MockSnapshotStore)(nil).CreateScheduledSnapshot), snapshotID, appID, timestamp)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteScheduledRestoreDrills", reflect.TypeOf((*MockRestoreDrillStore)(nil).DeleteScheduledRestoreDrills), appID)
 }
 
-// ListPendingScheduledInstanceSnapshots mocks base method
-func (m *MockSnapshotStore) ListPendingScheduledInstanceSnapshots(clusterID string) ([]types5.ScheduledInstanceSnapshot, error) {
-	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ListPendingScheduledInstanceSnapshots", clusterID)
-	ret0, _ := ret[0].([]types5.ScheduledInstanceSnapshot)
-	ret1, _ := ret[1].(error)
-	return ret0, ret1
+// MockCanaryStore is a mock of CanaryStore interface
+type MockCanaryStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockCanaryStoreMockRecorder
 }
 
-// ListPendingScheduledInstanceSnapshots indicates an expected call of ListPendingScheduledInstanceSnapshots
-func (mr *MockSnapshotStoreMockRecorder) ListPendingScheduledInstanceSnapshots(clusterID interface{}) *gomock.Call {
-	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPendingScheduledInstanceSnapshots", reflect.TypeOf((*MockSnapshotStore)(nil).ListPendingScheduledInstanceSnapshots), clusterID)
+// MockCanaryStoreMockRecorder is the mock recorder for MockCanaryStore
+type MockCanaryStoreMockRecorder struct {
+	mock *MockCanaryStore
 }
 
-// UpdateScheduledInstanceSnapshot mocks base method
-func (m *MockSnapshotStore) UpdateScheduledInstanceSnapshot(snapshotID, backupName string) error {
+// NewMockCanaryStore creates a new mock instance
+func NewMockCanaryStore(ctrl *gomock.Controller) *MockCanaryStore {
+	mock := &MockCanaryStore{ctrl: ctrl}
+	mock.recorder = &MockCanaryStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockCanaryStore) EXPECT() *MockCanaryStoreMockRecorder {
+	return m.recorder
+}
+
+// ListCanaryDeploys mocks base method
+func (m *MockCanaryStore) ListCanaryDeploys(appID string) ([]types7.Deploy, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "UpdateScheduledInstanceSnapshot", snapshotID, backupName)
-	ret0, _ := ret[0].(error)
-	return ret0
+	ret := m.ctrl.Call(m, "ListCanaryDeploys", appID)
+	ret0, _ := ret[0].([]types7.Deploy)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
-// UpdateScheduledInstanceSnapshot indicates an expected call of UpdateScheduledInstanceSnapshot
-func (mr *MockSnapshotStoreMockRecorder) UpdateScheduledInstanceSnapshot(snapshotID, backupName interface{}) *gomock.Call {
+// ListCanaryDeploys indicates an expected call of ListCanaryDeploys
+func (mr *MockCanaryStoreMockRecorder) ListCanaryDeploys(appID interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateScheduledInstanceSnapshot", reflect.TypeOf((*MockSnapshotStore)(nil).UpdateScheduledInstanceSnapshot), snapshotID, backupName)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListCanaryDeploys", reflect.TypeOf((*MockCanaryStore)(nil).ListCanaryDeploys), appID)
 }
 
-// DeletePendingScheduledInstanceSnapshots mocks base method
-func (m *MockSnapshotStore) DeletePendingScheduledInstanceSnapshots(clusterID string) error {
+// CreateCanaryDeploy mocks base method
+func (m *MockCanaryStore) CreateCanaryDeploy(deploy types7.Deploy) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeletePendingScheduledInstanceSnapshots", clusterID)
+	ret := m.ctrl.Call(m, "CreateCanaryDeploy", deploy)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-// DeletePendingScheduledInstanceSnapshots indicates an expected call of DeletePendingScheduledInstanceSnapshots
-func (mr *MockSnapshotStoreMockRecorder) DeletePendingScheduledInstanceSnapshots(clusterID interface{}) *gomock.Call {
+// CreateCanaryDeploy indicates an expected call of CreateCanaryDeploy
+func (mr *MockCanaryStoreMockRecorder) CreateCanaryDeploy(deploy interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeletePendingScheduledInstanceSnapshots", reflect.TypeOf((*MockSnapshotStore)(nil).DeletePendingScheduledInstanceSnapshots), clusterID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateCanaryDeploy", reflect.TypeOf((*MockCanaryStore)(nil).CreateCanaryDeploy), deploy)
 }
 
-// CreateScheduledInstanceSnapshot mocks base method
-func (m *MockSnapshotStore) CreateScheduledInstanceSnapshot(snapshotID, clusterID string, timestamp time.Time) error {
+// UpdateCanaryDeploy mocks base method
+func (m *MockCanaryStore) UpdateCanaryDeploy(deploy types7.Deploy) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreateScheduledInstanceSnapshot", snapshotID, clusterID, timestamp)
+	ret := m.ctrl.Call(m, "UpdateCanaryDeploy", deploy)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-// CreateScheduledInstanceSnapshot indicates an expected call of CreateScheduledInstanceSnapshot
-func (mr *MockSnapshotStoreMockRecorder) CreateScheduledInstanceSnapshot(snapshotID, clusterID, timestamp interface{}) *gomock.Call {
+// UpdateCanaryDeploy indicates an expected call of UpdateCanaryDeploy
+func (mr *MockCanaryStoreMockRecorder) UpdateCanaryDeploy(deploy interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateScheduledInstanceSnapshot", reflect.TypeOf((*MockSnapshotStore)(nil).CreateScheduledInstanceSnapshot), snapshotID, clusterID, timestamp)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateCanaryDeploy", reflect.TypeOf((*MockCanaryStore)(nil).UpdateCanaryDeploy), deploy)
 }
 
-// MockVersionStore is a mock of VersionStore interface
-type MockVersionStore struct {
+// MockRemoteInstallStore is a mock of RemoteInstallStore interface
+type MockRemoteInstallStore struct {
 	ctrl     *gomock.Controller
-	recorder *MockVersionStoreMockRecorder
+	recorder *MockRemoteInstallStoreMockRecorder
 }
 
-// MockVersionStoreMockRecorder is the mock recorder for MockVersionStore
-type MockVersionStoreMockRecorder struct {
-	mock *MockVersionStore
+// MockRemoteInstallStoreMockRecorder is the mock recorder for MockRemoteInstallStore
+type MockRemoteInstallStoreMockRecorder struct {
+	mock *MockRemoteInstallStore
 }
 
-// NewMockVersionStore creates a new mock instance
-func NewMockVersionStore(ctrl *gomock.Controller) *MockVersionStore {
-	mock := &MockVersionStore{ctrl: ctrl}
-	mock.recorder = &MockVersionStoreMockRecorder{mock}
+// NewMockRemoteInstallStore creates a new mock instance
+func NewMockRemoteInstallStore(ctrl *gomock.Controller) *MockRemoteInstallStore {
+	mock := &MockRemoteInstallStore{ctrl: ctrl}
+	mock.recorder = &MockRemoteInstallStoreMockRecorder{mock}
 	return mock
 }
 
 // EXPECT returns an object that allows the caller to indicate expected use
-func (m *MockVersionStore) EXPECT() *MockVersionStoreMockRecorder {
+func (m *MockRemoteInstallStore) EXPECT() *MockRemoteInstallStoreMockRecorder {
 	return m.recorder
 }
 
-// IsIdentityServiceSupportedForVersion mocks base method
-func (m *MockVersionStore) IsIdentityServiceSupportedForVersion(appID string, sequence int64) (bool, error) {
-	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "IsIdentityServiceSupportedForVersion", appID, sequence)
-	ret0, _ := ret[0].(bool)
-	ret1, _ := ret[1].(error)
-	return ret0, ret1
-}
-
-// IsIdentityServiceSupportedForVersion indicates an expected call of IsIdentityServiceSupportedForVersion
-func (mr *MockVersionStoreMockRecorder) IsIdentityServiceSupportedForVersion(appID, sequence interface{}) *gomock.Call {
-	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsIdentityServiceSupportedForVersion", reflect.TypeOf((*MockVersionStore)(nil).IsIdentityServiceSupportedForVersion), appID, sequence)
-}
-
-// IsRollbackSupportedForVersion mocks base method
-func (m *MockVersionStore) IsRollbackSupportedForVersion(appID string, sequence int64) (bool, error) {
+// ListRemoteInstalls mocks base method
+func (m *MockRemoteInstallStore) ListRemoteInstalls() ([]types25.RemoteInstall, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "IsRollbackSupportedForVersion", appID, sequence)
-	ret0, _ := ret[0].(bool)
+	ret := m.ctrl.Call(m, "ListRemoteInstalls")
+	ret0, _ := ret[0].([]types25.RemoteInstall)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// IsRollbackSupportedForVersion indicates an expected call of IsRollbackSupportedForVersion
-func (mr *MockVersionStoreMockRecorder) IsRollbackSupportedForVersion(appID, sequence interface{}) *gomock.Call {
+// ListRemoteInstalls indicates an expected call of ListRemoteInstalls
+func (mr *MockRemoteInstallStoreMockRecorder) ListRemoteInstalls() *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsRollbackSupportedForVersion", reflect.TypeOf((*MockVersionStore)(nil).IsRollbackSupportedForVersion), appID, sequence)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListRemoteInstalls", reflect.TypeOf((*MockRemoteInstallStore)(nil).ListRemoteInstalls))
 }
 
-// IsSnapshotsSupportedForVersion mocks base method
-func (m *MockVersionStore) IsSnapshotsSupportedForVersion(a *types3.App, sequence int64, renderer types9.Renderer) (bool, error) {
+// GetRemoteInstall mocks base method
+func (m *MockRemoteInstallStore) GetRemoteInstall(id string) (*types25.RemoteInstall, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "IsSnapshotsSupportedForVersion", a, sequence, renderer)
-	ret0, _ := ret[0].(bool)
+	ret := m.ctrl.Call(m, "GetRemoteInstall", id)
+	ret0, _ := ret[0].(*types25.RemoteInstall)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// IsSnapshotsSupportedForVersion indicates an expected call of IsSnapshotsSupportedForVersion
-func (mr *MockVersionStoreMockRecorder) IsSnapshotsSupportedForVersion(a, sequence, renderer interface{}) *gomock.Call {
+// GetRemoteInstall indicates an expected call of GetRemoteInstall
+func (mr *MockRemoteInstallStoreMockRecorder) GetRemoteInstall(id interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsSnapshotsSupportedForVersion", reflect.TypeOf((*MockVersionStore)(nil).IsSnapshotsSupportedForVersion), a, sequence, renderer)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRemoteInstall", reflect.TypeOf((*MockRemoteInstallStore)(nil).GetRemoteInstall), id)
 }
 
-// GetAppVersionArchive mocks base method
-func (m *MockVersionStore) GetAppVersionArchive(appID string, sequence int64, dstPath string) error {
+// CreateRemoteInstall mocks base method
+func (m *MockRemoteInstallStore) CreateRemoteInstall(remoteInstall types25.RemoteInstall) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetAppVersionArchive", appID, sequence, dstPath)
+	ret := m.ctrl.Call(m, "CreateRemoteInstall", remoteInstall)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-// GetAppVersionArchive indicates an expected call of GetAppVersionArchive
-func (mr *MockVersionStoreMockRecorder) GetAppVersionArchive(appID, sequence, dstPath interface{}) *gomock.Call {
+// CreateRemoteInstall indicates an expected call of CreateRemoteInstall
+func (mr *MockRemoteInstallStoreMockRecorder) CreateRemoteInstall(remoteInstall interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAppVersionArchive", reflect.TypeOf((*MockVersionStore)(nil).GetAppVersionArchive), appID, sequence, dstPath)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateRemoteInstall", reflect.TypeOf((*MockRemoteInstallStore)(nil).CreateRemoteInstall), remoteInstall)
 }
 
-// CreateAppVersionArchive mocks base method
-func (m *MockVersionStore) CreateAppVersionArchive(appID string, sequence int64, archivePath string) error {
+// DeleteRemoteInstall mocks base method
+func (m *MockRemoteInstallStore) DeleteRemoteInstall(id string) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreateAppVersionArchive", appID, sequence, archivePath)
+	ret := m.ctrl.Call(m, "DeleteRemoteInstall", id)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-// CreateAppVersionArchive indicates an expected call of CreateAppVersionArchive
-func (mr *MockVersionStoreMockRecorder) CreateAppVersionArchive(appID, sequence, archivePath interface{}) *gomock.Call {
-	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAppVersionArchive", reflect.TypeOf((*MockVersionStore)(nil).CreateAppVersionArchive), appID, sequence, archivePath)
-}
-
-// CreateAppVersion mocks base method
-func (m *MockVersionStore) CreateAppVersion(appID string, currentSequence *int64, filesInDir, source string, skipPreflights bool, gitops types4.DownstreamGitOps) (int64, error) {
-	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreateAppVersion", appID, currentSequence, filesInDir, source, skipPreflights, gitops)
-	ret0, _ := ret[0].(int64)
-	ret1, _ := ret[1].(error)
-	return ret0, ret1
-}
-
-// CreateAppVersion indicates an expected call of CreateAppVersion
-func (mr *MockVersionStoreMockRecorder) CreateAppVersion(appID, currentSequence, filesInDir, source, skipPreflights, gitops interface{}) *gomock.Call {
+// DeleteRemoteInstall indicates an expected call of DeleteRemoteInstall
+func (mr *MockRemoteInstallStoreMockRecorder) DeleteRemoteInstall(id interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAppVersion", reflect.TypeOf((*MockVersionStore)(nil).CreateAppVersion), appID, currentSequence, filesInDir, source, skipPreflights, gitops)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteRemoteInstall", reflect.TypeOf((*MockRemoteInstallStore)(nil).DeleteRemoteInstall), id)
 }
 
-// GetAppVersion mocks base method
-func (m *MockVersionStore) GetAppVersion(arg0 string, arg1 int64) (*types2.AppVersion, error) {
+// SetRemoteInstallReport mocks base method
+func (m *MockRemoteInstallStore) SetRemoteInstallReport(id string, report *types10.Report, fetchedAt time.Time) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetAppVersion", arg0, arg1)
-	ret0, _ := ret[0].(*types2.AppVersion)
-	ret1, _ := ret[1].(error)
-	return ret0, ret1
+	ret := m.ctrl.Call(m, "SetRemoteInstallReport", id, report, fetchedAt)
+	ret0, _ := ret[0].(error)
+	return ret0
 }
 
-// GetAppVersion indicates an expected call of GetAppVersion
-func (mr *MockVersionStoreMockRecorder) GetAppVersion(arg0, arg1 interface{}) *gomock.Call {
+// SetRemoteInstallReport indicates an expected call of SetRemoteInstallReport
+func (mr *MockRemoteInstallStoreMockRecorder) SetRemoteInstallReport(id, report, fetchedAt interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAppVersion", reflect.TypeOf((*MockVersionStore)(nil).GetAppVersion), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetRemoteInstallReport", reflect.TypeOf((*MockRemoteInstallStore)(nil).SetRemoteInstallReport), id, report, fetchedAt)
 }
 
-// GetAppVersionsAfter mocks base method
-func (m *MockVersionStore) GetAppVersionsAfter(arg0 string, arg1 int64) ([]*types2.AppVersion, error) {
+// SetRemoteInstallError mocks base method
+func (m *MockRemoteInstallStore) SetRemoteInstallError(id, message string) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetAppVersionsAfter", arg0, arg1)
-	ret0, _ := ret[0].([]*types2.AppVersion)
-	ret1, _ := ret[1].(error)
-	return ret0, ret1
+	ret := m.ctrl.Call(m, "SetRemoteInstallError", id, message)
+	ret0, _ := ret[0].(error)
+	return ret0
 }
 
-// GetAppVersionsAfter indicates an expected call of GetAppVersionsAfter
-func (mr *MockVersionStoreMockRecorder) GetAppVersionsAfter(arg0, arg1 interface{}) *gomock.Call {
+// SetRemoteInstallError indicates an expected call of SetRemoteInstallError
+func (mr *MockRemoteInstallStoreMockRecorder) SetRemoteInstallError(id, message interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAppVersionsAfter", reflect.TypeOf((*MockVersionStore)(nil).GetAppVersionsAfter), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetRemoteInstallError", reflect.TypeOf((*MockRemoteInstallStore)(nil).SetRemoteInstallError), id, message)
 }
 
-// MockLicenseStore is a mock of LicenseStore interface
-type MockLicenseStore struct {
+// MockScheduledJobStore is a mock of ScheduledJobStore interface
+type MockScheduledJobStore struct {
 	ctrl     *gomock.Controller
-	recorder *MockLicenseStoreMockRecorder
+	recorder *MockScheduledJobStoreMockRecorder
 }
 
-// MockLicenseStoreMockRecorder is the mock recorder for MockLicenseStore
-type MockLicenseStoreMockRecorder struct {
-	mock *MockLicenseStore
+// MockScheduledJobStoreMockRecorder is the mock recorder for MockScheduledJobStore
+type MockScheduledJobStoreMockRecorder struct {
+	mock *MockScheduledJobStore
 }
 
-// NewMockLicenseStore creates a new mock instance
-func NewMockLicenseStore(ctrl *gomock.Controller) *MockLicenseStore {
-	mock := &MockLicenseStore{ctrl: ctrl}
-	mock.recorder = &MockLicenseStoreMockRecorder{mock}
+// NewMockScheduledJobStore creates a new mock instance
+func NewMockScheduledJobStore(ctrl *gomock.Controller) *MockScheduledJobStore {
+	mock := &MockScheduledJobStore{ctrl: ctrl}
+	mock.recorder = &MockScheduledJobStoreMockRecorder{mock}
 	return mock
 }
 
 // EXPECT returns an object that allows the caller to indicate expected use
-func (m *MockLicenseStore) EXPECT() *MockLicenseStoreMockRecorder {
+func (m *MockScheduledJobStore) EXPECT() *MockScheduledJobStoreMockRecorder {
 	return m.recorder
 }
 
-// GetLatestLicenseForApp mocks base method
-func (m *MockLicenseStore) GetLatestLicenseForApp(appID string) (*v1beta1.License, error) {
+// CreateScheduledJobRun mocks base method
+func (m *MockScheduledJobStore) CreateScheduledJobRun(run types28.Run) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetLatestLicenseForApp", appID)
-	ret0, _ := ret[0].(*v1beta1.License)
-	ret1, _ := ret[1].(error)
-	return ret0, ret1
+	ret := m.ctrl.Call(m, "CreateScheduledJobRun", run)
+	ret0, _ := ret[0].(error)
+	return ret0
 }
 
-// GetLatestLicenseForApp indicates an expected call of GetLatestLicenseForApp
-func (mr *MockLicenseStoreMockRecorder) GetLatestLicenseForApp(appID interface{}) *gomock.Call {
+// CreateScheduledJobRun indicates an expected call of CreateScheduledJobRun
+func (mr *MockScheduledJobStoreMockRecorder) CreateScheduledJobRun(run interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLatestLicenseForApp", reflect.TypeOf((*MockLicenseStore)(nil).GetLatestLicenseForApp), appID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateScheduledJobRun", reflect.TypeOf((*MockScheduledJobStore)(nil).CreateScheduledJobRun), run)
 }
 
-// GetLicenseForAppVersion mocks base method
-func (m *MockLicenseStore) GetLicenseForAppVersion(appID string, sequence int64) (*v1beta1.License, error) {
+// FinishScheduledJobRun mocks base method
+func (m *MockScheduledJobStore) FinishScheduledJobRun(id string, status types28.Status, message string, finishedAt time.Time) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetLicenseForAppVersion", appID, sequence)
-	ret0, _ := ret[0].(*v1beta1.License)
-	ret1, _ := ret[1].(error)
-	return ret0, ret1
+	ret := m.ctrl.Call(m, "FinishScheduledJobRun", id, status, message, finishedAt)
+	ret0, _ := ret[0].(error)
+	return ret0
 }
 
-// GetLicenseForAppVersion indicates an expected call of GetLicenseForAppVersion
-func (mr *MockLicenseStoreMockRecorder) GetLicenseForAppVersion(appID, sequence interface{}) *gomock.Call {
+// FinishScheduledJobRun indicates an expected call of FinishScheduledJobRun
+func (mr *MockScheduledJobStoreMockRecorder) FinishScheduledJobRun(id, status, message, finishedAt interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLicenseForAppVersion", reflect.TypeOf((*MockLicenseStore)(nil).GetLicenseForAppVersion), appID, sequence)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FinishScheduledJobRun", reflect.TypeOf((*MockScheduledJobStore)(nil).FinishScheduledJobRun), id, status, message, finishedAt)
 }
 
-// GetAllAppLicenses mocks base method
-func (m *MockLicenseStore) GetAllAppLicenses() ([]*v1beta1.License, error) {
+// ListLatestScheduledJobRuns mocks base method
+func (m *MockScheduledJobStore) ListLatestScheduledJobRuns() ([]types28.Run, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetAllAppLicenses")
-	ret0, _ := ret[0].([]*v1beta1.License)
+	ret := m.ctrl.Call(m, "ListLatestScheduledJobRuns")
+	ret0, _ := ret[0].([]types28.Run)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetAllAppLicenses indicates an expected call of GetAllAppLicenses
-func (mr *MockLicenseStoreMockRecorder) GetAllAppLicenses() *gomock.Call {
+// ListLatestScheduledJobRuns indicates an expected call of ListLatestScheduledJobRuns
+func (mr *MockScheduledJobStoreMockRecorder) ListLatestScheduledJobRuns() *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllAppLicenses", reflect.TypeOf((*MockLicenseStore)(nil).GetAllAppLicenses))
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListLatestScheduledJobRuns", reflect.TypeOf((*MockScheduledJobStore)(nil).ListLatestScheduledJobRuns))
 }
 
-// UpdateAppLicense mocks base method
-func (m *MockLicenseStore) UpdateAppLicense(appID string, sequence int64, archiveDir string, newLicense *v1beta1.License, originalLicenseData string, failOnVersionCreate bool, gitops types4.DownstreamGitOps, renderer types9.Renderer) (int64, error) {
+// DeleteScheduledJobRunsBefore mocks base method
+func (m *MockScheduledJobStore) DeleteScheduledJobRunsBefore(before time.Time) (int64, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "UpdateAppLicense", appID, sequence, archiveDir, newLicense, originalLicenseData, failOnVersionCreate, gitops, renderer)
+	ret := m.ctrl.Call(m, "DeleteScheduledJobRunsBefore", before)
 	ret0, _ := ret[0].(int64)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// UpdateAppLicense indicates an expected call of UpdateAppLicense
-func (mr *MockLicenseStoreMockRecorder) UpdateAppLicense(appID, sequence, archiveDir, newLicense, originalLicenseData, failOnVersionCreate, gitops, renderer interface{}) *gomock.Call {
+// DeleteScheduledJobRunsBefore indicates an expected call of DeleteScheduledJobRunsBefore
+func (mr *MockScheduledJobStoreMockRecorder) DeleteScheduledJobRunsBefore(before interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateAppLicense", reflect.TypeOf((*MockLicenseStore)(nil).UpdateAppLicense), appID, sequence, archiveDir, newLicense, originalLicenseData, failOnVersionCreate, gitops, renderer)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteScheduledJobRunsBefore", reflect.TypeOf((*MockScheduledJobStore)(nil).DeleteScheduledJobRunsBefore), before)
 }
 
-// MockClusterStore is a mock of ClusterStore interface
-type MockClusterStore struct {
+// MockArchiveChecksumStore is a mock of ArchiveChecksumStore interface
+type MockArchiveChecksumStore struct {
 	ctrl     *gomock.Controller
-	recorder *MockClusterStoreMockRecorder
+	recorder *MockArchiveChecksumStoreMockRecorder
 }
 
-// MockClusterStoreMockRecorder is the mock recorder for MockClusterStore
-type MockClusterStoreMockRecorder struct {
-	mock *MockClusterStore
+// MockArchiveChecksumStoreMockRecorder is the mock recorder for MockArchiveChecksumStore
+type MockArchiveChecksumStoreMockRecorder struct {
+	mock *MockArchiveChecksumStore
 }
 
-// NewMockClusterStore creates a new mock instance
-func NewMockClusterStore(ctrl *gomock.Controller) *MockClusterStore {
-	mock := &MockClusterStore{ctrl: ctrl}
-	mock.recorder = &MockClusterStoreMockRecorder{mock}
+// NewMockArchiveChecksumStore creates a new mock instance
+func NewMockArchiveChecksumStore(ctrl *gomock.Controller) *MockArchiveChecksumStore {
+	mock := &MockArchiveChecksumStore{ctrl: ctrl}
+	mock.recorder = &MockArchiveChecksumStoreMockRecorder{mock}
 	return mock
 }
 
 // EXPECT returns an object that allows the caller to indicate expected use
-func (m *MockClusterStore) EXPECT() *MockClusterStoreMockRecorder {
+func (m *MockArchiveChecksumStore) EXPECT() *MockArchiveChecksumStoreMockRecorder {
 	return m.recorder
 }
 
-// ListClusters mocks base method
-func (m *MockClusterStore) ListClusters() ([]*types1.Downstream, error) {
+// SetAppVersionArchiveChecksum mocks base method
+func (m *MockArchiveChecksumStore) SetAppVersionArchiveChecksum(appID string, sequence int64, sum string, size int64) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ListClusters")
-	ret0, _ := ret[0].([]*types1.Downstream)
-	ret1, _ := ret[1].(error)
-	return ret0, ret1
+	ret := m.ctrl.Call(m, "SetAppVersionArchiveChecksum", appID, sequence, sum, size)
+	ret0, _ := ret[0].(error)
+	return ret0
 }
 
-// ListClusters indicates an expected call of ListClusters
-func (mr *MockClusterStoreMockRecorder) ListClusters() *gomock.Call {
+// SetAppVersionArchiveChecksum indicates an expected call of SetAppVersionArchiveChecksum
+func (mr *MockArchiveChecksumStoreMockRecorder) SetAppVersionArchiveChecksum(appID, sequence, sum, size interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListClusters", reflect.TypeOf((*MockClusterStore)(nil).ListClusters))
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetAppVersionArchiveChecksum", reflect.TypeOf((*MockArchiveChecksumStore)(nil).SetAppVersionArchiveChecksum), appID, sequence, sum, size)
 }
 
-// GetClusterIDFromSlug mocks base method
-func (m *MockClusterStore) GetClusterIDFromSlug(slug string) (string, error) {
+// GetAppVersionArchiveChecksum mocks base method
+func (m *MockArchiveChecksumStore) GetAppVersionArchiveChecksum(appID string, sequence int64) (*types5.Checksum, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetClusterIDFromSlug", slug)
-	ret0, _ := ret[0].(string)
+	ret := m.ctrl.Call(m, "GetAppVersionArchiveChecksum", appID, sequence)
+	ret0, _ := ret[0].(*types5.Checksum)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetClusterIDFromSlug indicates an expected call of GetClusterIDFromSlug
-func (mr *MockClusterStoreMockRecorder) GetClusterIDFromSlug(slug interface{}) *gomock.Call {
+// GetAppVersionArchiveChecksum indicates an expected call of GetAppVersionArchiveChecksum
+func (mr *MockArchiveChecksumStoreMockRecorder) GetAppVersionArchiveChecksum(appID, sequence interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetClusterIDFromSlug", reflect.TypeOf((*MockClusterStore)(nil).GetClusterIDFromSlug), slug)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAppVersionArchiveChecksum", reflect.TypeOf((*MockArchiveChecksumStore)(nil).GetAppVersionArchiveChecksum), appID, sequence)
 }
 
-// GetClusterIDFromDeployToken mocks base method
-func (m *MockClusterStore) GetClusterIDFromDeployToken(deployToken string) (string, error) {
+// ListAppVersionArchiveChecksumsToVerify mocks base method
+func (m *MockArchiveChecksumStore) ListAppVersionArchiveChecksumsToVerify(verifiedBefore time.Time, limit int) ([]types5.Checksum, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetClusterIDFromDeployToken", deployToken)
-	ret0, _ := ret[0].(string)
+	ret := m.ctrl.Call(m, "ListAppVersionArchiveChecksumsToVerify", verifiedBefore, limit)
+	ret0, _ := ret[0].([]types5.Checksum)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetClusterIDFromDeployToken indicates an expected call of GetClusterIDFromDeployToken
-func (mr *MockClusterStoreMockRecorder) GetClusterIDFromDeployToken(deployToken interface{}) *gomock.Call {
+// ListAppVersionArchiveChecksumsToVerify indicates an expected call of ListAppVersionArchiveChecksumsToVerify
+func (mr *MockArchiveChecksumStoreMockRecorder) ListAppVersionArchiveChecksumsToVerify(verifiedBefore, limit interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetClusterIDFromDeployToken", reflect.TypeOf((*MockClusterStore)(nil).GetClusterIDFromDeployToken), deployToken)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAppVersionArchiveChecksumsToVerify", reflect.TypeOf((*MockArchiveChecksumStore)(nil).ListAppVersionArchiveChecksumsToVerify), verifiedBefore, limit)
 }
 
-// CreateNewCluster mocks base method
-func (m *MockClusterStore) CreateNewCluster(userID string, isAllUsers bool, title, token string) (string, error) {
+// ListCorruptAppVersionArchives mocks base method
+func (m *MockArchiveChecksumStore) ListCorruptAppVersionArchives(appID string) ([]types5.Checksum, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreateNewCluster", userID, isAllUsers, title, token)
-	ret0, _ := ret[0].(string)
+	ret := m.ctrl.Call(m, "ListCorruptAppVersionArchives", appID)
+	ret0, _ := ret[0].([]types5.Checksum)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// CreateNewCluster indicates an expected call of CreateNewCluster
-func (mr *MockClusterStoreMockRecorder) CreateNewCluster(userID, isAllUsers, title, token interface{}) *gomock.Call {
-	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateNewCluster", reflect.TypeOf((*MockClusterStore)(nil).CreateNewCluster), userID, isAllUsers, title, token)
-}
-
-// SetInstanceSnapshotTTL mocks base method
-func (m *MockClusterStore) SetInstanceSnapshotTTL(clusterID, snapshotTTL string) error {
-	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "SetInstanceSnapshotTTL", clusterID, snapshotTTL)
-	ret0, _ := ret[0].(error)
-	return ret0
-}
-
-// SetInstanceSnapshotTTL indicates an expected call of SetInstanceSnapshotTTL
-func (mr *MockClusterStoreMockRecorder) SetInstanceSnapshotTTL(clusterID, snapshotTTL interface{}) *gomock.Call {
+// ListCorruptAppVersionArchives indicates an expected call of ListCorruptAppVersionArchives
+func (mr *MockArchiveChecksumStoreMockRecorder) ListCorruptAppVersionArchives(appID interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetInstanceSnapshotTTL", reflect.TypeOf((*MockClusterStore)(nil).SetInstanceSnapshotTTL), clusterID, snapshotTTL)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListCorruptAppVersionArchives", reflect.TypeOf((*MockArchiveChecksumStore)(nil).ListCorruptAppVersionArchives), appID)
 }
 
-// SetInstanceSnapshotSchedule mocks base method
-func (m *MockClusterStore) SetInstanceSnapshotSchedule(clusterID, snapshotSchedule string) error {
+// SetAppVersionArchiveVerified mocks base method
+func (m *MockArchiveChecksumStore) SetAppVersionArchiveVerified(appID string, sequence int64, verifyError string) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "SetInstanceSnapshotSchedule", clusterID, snapshotSchedule)
+	ret := m.ctrl.Call(m, "SetAppVersionArchiveVerified", appID, sequence, verifyError)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-// SetInstanceSnapshotSchedule indicates an expected call of SetInstanceSnapshotSchedule
-func (mr *MockClusterStoreMockRecorder) SetInstanceSnapshotSchedule(clusterID, snapshotSchedule interface{}) *gomock.Call {
+// SetAppVersionArchiveVerified indicates an expected call of SetAppVersionArchiveVerified
+func (mr *MockArchiveChecksumStoreMockRecorder) SetAppVersionArchiveVerified(appID, sequence, verifyError interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetInstanceSnapshotSchedule", reflect.TypeOf((*MockClusterStore)(nil).SetInstanceSnapshotSchedule), clusterID, snapshotSchedule)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetAppVersionArchiveVerified", reflect.TypeOf((*MockArchiveChecksumStore)(nil).SetAppVersionArchiveVerified), appID, sequence, verifyError)
 }
 
-// MockInstallationStore is a mock of InstallationStore interface
-type MockInstallationStore struct {
+// MockDeployHistoryStore is a mock of DeployHistoryStore interface
+type MockDeployHistoryStore struct {
 	ctrl     *gomock.Controller
-	recorder *MockInstallationStoreMockRecorder
+	recorder *MockDeployHistoryStoreMockRecorder
 }
 
-// MockInstallationStoreMockRecorder is the mock recorder for MockInstallationStore
-type MockInstallationStoreMockRecorder struct {
-	mock *MockInstallationStore
+// MockDeployHistoryStoreMockRecorder is the mock recorder for MockDeployHistoryStore
+type MockDeployHistoryStoreMockRecorder struct {
+	mock *MockDeployHistoryStore
 }
 
-// NewMockInstallationStore creates a new mock instance
-func NewMockInstallationStore(ctrl *gomock.Controller) *MockInstallationStore {
-	mock := &MockInstallationStore{ctrl: ctrl}
-	mock.recorder = &MockInstallationStoreMockRecorder{mock}
+// NewMockDeployHistoryStore creates a new mock instance
+func NewMockDeployHistoryStore(ctrl *gomock.Controller) *MockDeployHistoryStore {
+	mock := &MockDeployHistoryStore{ctrl: ctrl}
+	mock.recorder = &MockDeployHistoryStoreMockRecorder{mock}
 	return mock
 }
 
 // EXPECT returns an object that allows the caller to indicate expected use
-func (m *MockInstallationStore) EXPECT() *MockInstallationStoreMockRecorder {
+func (m *MockDeployHistoryStore) EXPECT() *MockDeployHistoryStoreMockRecorder {
 	return m.recorder
 }
 
-// GetPendingInstallationStatus mocks base method
-func (m *MockInstallationStore) GetPendingInstallationStatus() (*types6.InstallStatus, error) {
+// SetDeployEventResult mocks base method
+func (m *MockDeployHistoryStore) SetDeployEventResult(appID, clusterID string, sequence int64, isError bool) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetPendingInstallationStatus")
-	ret0, _ := ret[0].(*types6.InstallStatus)
-	ret1, _ := ret[1].(error)
-	return ret0, ret1
+	ret := m.ctrl.Call(m, "SetDeployEventResult", appID, clusterID, sequence, isError)
+	ret0, _ := ret[0].(error)
+	return ret0
 }
 
-// GetPendingInstallationStatus indicates an expected call of GetPendingInstallationStatus
-func (mr *MockInstallationStoreMockRecorder) GetPendingInstallationStatus() *gomock.Call {
+// SetDeployEventResult indicates an expected call of SetDeployEventResult
+func (mr *MockDeployHistoryStoreMockRecorder) SetDeployEventResult(appID, clusterID, sequence, isError interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPendingInstallationStatus", reflect.TypeOf((*MockInstallationStore)(nil).GetPendingInstallationStatus))
-}
-
-// MockKotsadmParamsStore is a mock of KotsadmParamsStore interface
-type MockKotsadmParamsStore struct {
-	ctrl     *gomock.Controller
-	recorder *MockKotsadmParamsStoreMockRecorder
-}
-
-// MockKotsadmParamsStoreMockRecorder is the mock recorder for MockKotsadmParamsStore
-type MockKotsadmParamsStoreMockRecorder struct {
-	mock *MockKotsadmParamsStore
-}
-
-// NewMockKotsadmParamsStore creates a new mock instance
-func NewMockKotsadmParamsStore(ctrl *gomock.Controller) *MockKotsadmParamsStore {
-	mock := &MockKotsadmParamsStore{ctrl: ctrl}
-	mock.recorder = &MockKotsadmParamsStoreMockRecorder{mock}
-	return mock
-}
-
-// EXPECT returns an object that allows the caller to indicate expected use
-func (m *MockKotsadmParamsStore) EXPECT() *MockKotsadmParamsStoreMockRecorder {
-	return m.recorder
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetDeployEventResult", reflect.TypeOf((*MockDeployHistoryStore)(nil).SetDeployEventResult), appID, clusterID, sequence, isError)
 }
 
-// IsKotsadmIDGenerated mocks base method
-func (m *MockKotsadmParamsStore) IsKotsadmIDGenerated() (bool, error) {
+// ListDeployEvents mocks base method
+func (m *MockDeployHistoryStore) ListDeployEvents(opts types9.ListOptions) ([]types9.Event, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "IsKotsadmIDGenerated")
-	ret0, _ := ret[0].(bool)
+	ret := m.ctrl.Call(m, "ListDeployEvents", opts)
+	ret0, _ := ret[0].([]types9.Event)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// IsKotsadmIDGenerated indicates an expected call of IsKotsadmIDGenerated
-func (mr *MockKotsadmParamsStoreMockRecorder) IsKotsadmIDGenerated() *gomock.Call {
-	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsKotsadmIDGenerated", reflect.TypeOf((*MockKotsadmParamsStore)(nil).IsKotsadmIDGenerated))
-}
-
-// SetIsKotsadmIDGenerated mocks base method
-func (m *MockKotsadmParamsStore) SetIsKotsadmIDGenerated() error {
-	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "SetIsKotsadmIDGenerated")
-	ret0, _ := ret[0].(error)
-	return ret0
-}
-
-// SetIsKotsadmIDGenerated indicates an expected call of SetIsKotsadmIDGenerated
-func (mr *MockKotsadmParamsStoreMockRecorder) SetIsKotsadmIDGenerated() *gomock.Call {
+// ListDeployEvents indicates an expected call of ListDeployEvents
+func (mr *MockDeployHistoryStoreMockRecorder) ListDeployEvents(opts interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetIsKotsadmIDGenerated", reflect.TypeOf((*MockKotsadmParamsStore)(nil).SetIsKotsadmIDGenerated))
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListDeployEvents", reflect.TypeOf((*MockDeployHistoryStore)(nil).ListDeployEvents), opts)
 }
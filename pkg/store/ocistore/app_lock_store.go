@@ -0,0 +1,17 @@
+package ocistore
+
+import (
+	"time"
+)
+
+func (s *OCIStore) AcquireAppLock(appID string, holder string, expiresAt time.Time) (bool, error) {
+	return false, ErrNotImplemented
+}
+
+func (s *OCIStore) RefreshAppLock(appID string, holder string, expiresAt time.Time) error {
+	return ErrNotImplemented
+}
+
+func (s *OCIStore) ReleaseAppLock(appID string, holder string) error {
+	return ErrNotImplemented
+}
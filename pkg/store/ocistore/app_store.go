@@ -325,10 +325,58 @@ func (c OCIStore) SetUpdateCheckerSpec(appID string, updateCheckerSpec string) e
 	return ErrNotImplemented
 }
 
+func (c OCIStore) SetDeployPolicy(appID string, deployPolicy apptypes.DeployPolicy) error {
+	return ErrNotImplemented
+}
+
+func (c OCIStore) SetAdmissionDryRun(appID string, enabled bool) error {
+	return ErrNotImplemented
+}
+
+func (c OCIStore) SetAppArchived(appID string, archived bool) error {
+	return ErrNotImplemented
+}
+
+func (c OCIStore) SetAppProtected(appID string, protected bool) error {
+	return ErrNotImplemented
+}
+
+func (c OCIStore) SetRequireDeployApproval(appID string, required bool) error {
+	return ErrNotImplemented
+}
+
+func (c OCIStore) SetApplyPolicy(appID string, applyPolicy apptypes.ApplyPolicy) error {
+	return ErrNotImplemented
+}
+
+func (c OCIStore) SetCanaryPolicy(appID string, canaryPolicy apptypes.CanaryPolicy) error {
+	return ErrNotImplemented
+}
+
+func (c OCIStore) SetAppLabels(appID string, labels map[string]string) error {
+	return ErrNotImplemented
+}
+
+func (c OCIStore) SetAppNamespace(appID string, namespace string) error {
+	return ErrNotImplemented
+}
+
+func (c OCIStore) SetImagePushBandwidthLimit(appID string, bytesPerSecond int64) error {
+	return ErrNotImplemented
+}
+
 func (c OCIStore) SetSnapshotSchedule(appID string, snapshotSchedule string) error {
 	return ErrNotImplemented
 }
 
+func (c OCIStore) SetRestoreDrillSchedule(appID string, restoreDrillSchedule string) error {
+	return ErrNotImplemented
+}
+
+func (c OCIStore) SetScheduleTimezone(appID string, timezone string) error {
+	return ErrNotImplemented
+}
+
 func (c OCIStore) SetSnapshotTTL(appID string, snapshotTTL string) error {
 	return ErrNotImplemented
 }
@@ -357,6 +405,18 @@ func (s *OCIStore) updateApp(app *apptypes.App) error {
 	return nil
 }
 
+func (s *OCIStore) TrashApp(appID string) error {
+	return ErrNotImplemented
+}
+
+func (s *OCIStore) RestoreTrashedApp(appID string) error {
+	return ErrNotImplemented
+}
+
+func (s *OCIStore) ListRemovedApps() ([]*apptypes.App, error) {
+	return nil, ErrNotImplemented
+}
+
 func (s *OCIStore) RemoveApp(appID string) error {
 	return ErrNotImplemented
 }
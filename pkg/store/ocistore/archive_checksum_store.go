@@ -0,0 +1,27 @@
+package ocistore
+
+import (
+	"time"
+
+	archiveintegritytypes "github.com/replicatedhq/kots/pkg/archiveintegrity/types"
+)
+
+func (s *OCIStore) SetAppVersionArchiveChecksum(appID string, sequence int64, sum string, size int64) error {
+	return ErrNotImplemented
+}
+
+func (s *OCIStore) GetAppVersionArchiveChecksum(appID string, sequence int64) (*archiveintegritytypes.Checksum, error) {
+	return nil, ErrNotImplemented
+}
+
+func (s *OCIStore) ListAppVersionArchiveChecksumsToVerify(verifiedBefore time.Time, limit int) ([]archiveintegritytypes.Checksum, error) {
+	return nil, ErrNotImplemented
+}
+
+func (s *OCIStore) ListCorruptAppVersionArchives(appID string) ([]archiveintegritytypes.Checksum, error) {
+	return nil, ErrNotImplemented
+}
+
+func (s *OCIStore) SetAppVersionArchiveVerified(appID string, sequence int64, verifyError string) error {
+	return ErrNotImplemented
+}
@@ -0,0 +1,17 @@
+package ocistore
+
+import (
+	assetcachetypes "github.com/replicatedhq/kots/pkg/assetcache/types"
+)
+
+func (s *OCIStore) RegisterCachedAsset(cacheKey string, uri string) error {
+	return ErrNotImplemented
+}
+
+func (s *OCIStore) GetCachedAsset(cacheKey string) (*assetcachetypes.Asset, error) {
+	return nil, ErrNotImplemented
+}
+
+func (s *OCIStore) SetCachedAsset(asset assetcachetypes.Asset) error {
+	return ErrNotImplemented
+}
@@ -0,0 +1,17 @@
+package ocistore
+
+import (
+	canarytypes "github.com/replicatedhq/kots/pkg/canary/types"
+)
+
+func (s *OCIStore) ListCanaryDeploys(appID string) ([]canarytypes.Deploy, error) {
+	return nil, ErrNotImplemented
+}
+
+func (s *OCIStore) CreateCanaryDeploy(deploy canarytypes.Deploy) error {
+	return ErrNotImplemented
+}
+
+func (s *OCIStore) UpdateCanaryDeploy(deploy canarytypes.Deploy) error {
+	return ErrNotImplemented
+}
@@ -0,0 +1,13 @@
+package ocistore
+
+import (
+	"io"
+)
+
+func (s *OCIStore) CreateConfigFile(checksum string, filePath string) error {
+	return ErrNotImplemented
+}
+
+func (s *OCIStore) GetConfigFile(checksum string) (io.ReadCloser, error) {
+	return nil, ErrNotImplemented
+}
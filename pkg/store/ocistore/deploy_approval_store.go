@@ -0,0 +1,31 @@
+package ocistore
+
+import (
+	"time"
+
+	deployapprovaltypes "github.com/replicatedhq/kots/pkg/deployapproval/types"
+)
+
+func (s *OCIStore) CreateDeployApproval(approval deployapprovaltypes.DeployApproval) error {
+	return ErrNotImplemented
+}
+
+func (s *OCIStore) GetDeployApproval(approvalID string) (*deployapprovaltypes.DeployApproval, error) {
+	return nil, ErrNotImplemented
+}
+
+func (s *OCIStore) GetPendingDeployApproval(appID string, sequence int64) (*deployapprovaltypes.DeployApproval, error) {
+	return nil, ErrNotImplemented
+}
+
+func (s *OCIStore) ListDeployApprovals(appID string) ([]deployapprovaltypes.DeployApproval, error) {
+	return nil, ErrNotImplemented
+}
+
+func (s *OCIStore) SetDeployApprovalDecision(approvalID string, status deployapprovaltypes.Status, decidedBy string, decidedAt time.Time) (bool, error) {
+	return false, ErrNotImplemented
+}
+
+func (s *OCIStore) ReopenDeployApproval(approvalID string) error {
+	return ErrNotImplemented
+}
@@ -0,0 +1,13 @@
+package ocistore
+
+import (
+	deployhistorytypes "github.com/replicatedhq/kots/pkg/deployhistory/types"
+)
+
+func (s *OCIStore) SetDeployEventResult(appID string, clusterID string, sequence int64, isError bool) error {
+	return ErrNotImplemented
+}
+
+func (s *OCIStore) ListDeployEvents(opts deployhistorytypes.ListOptions) ([]deployhistorytypes.Event, error) {
+	return nil, ErrNotImplemented
+}
@@ -1,7 +1,9 @@
 package ocistore
 
 import (
+	admissiontypes "github.com/replicatedhq/kots/pkg/admission/types"
 	"github.com/replicatedhq/kots/pkg/api/downstream/types"
+	postdeploytesttypes "github.com/replicatedhq/kots/pkg/postdeploytest/types"
 )
 
 func (s *OCIStore) GetCurrentSequence(appID string, clusterID string) (int64, error) {
@@ -52,10 +54,18 @@ func (s *OCIStore) GetPendingVersions(appID string, clusterID string) ([]types.D
 	return nil, ErrNotImplemented
 }
 
+func (s *OCIStore) ListPendingVersionCounts() (map[string]int64, error) {
+	return nil, ErrNotImplemented
+}
+
 func (s *OCIStore) GetPastVersions(appID string, clusterID string) ([]types.DownstreamVersion, error) {
 	return nil, ErrNotImplemented
 }
 
+func (s *OCIStore) ListDownstreamVersions(appID string, clusterID string, opts types.VersionHistoryOptions) ([]types.DownstreamVersion, int64, error) {
+	return nil, 0, ErrNotImplemented
+}
+
 func (s *OCIStore) GetDownstreamOutput(appID string, clusterID string, sequence int64) (*types.DownstreamOutput, error) {
 	return nil, ErrNotImplemented
 }
@@ -71,3 +81,51 @@ func (s *OCIStore) UpdateDownstreamDeployStatus(appID string, clusterID string,
 func (s *OCIStore) DeleteDownstreamDeployStatus(appID string, clusterID string, sequence int64) error {
 	return ErrNotImplemented
 }
+
+func (s *OCIStore) GetDownstreamPostDeployTestResults(appID string, clusterID string, sequence int64) ([]postdeploytesttypes.Result, error) {
+	return nil, ErrNotImplemented
+}
+
+func (s *OCIStore) SetDownstreamPostDeployTestResults(appID string, clusterID string, sequence int64, results []postdeploytesttypes.Result) error {
+	return ErrNotImplemented
+}
+
+func (s *OCIStore) GetDownstreamAdmissionValidation(appID string, clusterID string, sequence int64) (*admissiontypes.Validation, error) {
+	return nil, ErrNotImplemented
+}
+
+func (s *OCIStore) SetDownstreamAdmissionValidation(appID string, clusterID string, sequence int64, validation admissiontypes.Validation) error {
+	return ErrNotImplemented
+}
+
+func (s *OCIStore) GetDownstreamServiceAccount(appID string, clusterID string) (string, error) {
+	return "", ErrNotImplemented
+}
+
+func (s *OCIStore) SetDownstreamServiceAccount(appID string, clusterID string, serviceAccount string) error {
+	return ErrNotImplemented
+}
+
+func (s *OCIStore) CreateDownstreamOutputArchive(appID string, clusterID string, sequence int64, archivePath string) error {
+	return ErrNotImplemented
+}
+
+func (s *OCIStore) GetDownstreamOutputArchive(appID string, clusterID string, sequence int64) (string, error) {
+	return "", ErrNotImplemented
+}
+
+func (s *OCIStore) DeleteDownstreamOutputArchivesBefore(appID string, clusterID string, sequence int64) error {
+	return ErrNotImplemented
+}
+
+func (s *OCIStore) GetActivatingSequence(appID string, clusterID string) (int64, error) {
+	return -1, ErrNotImplemented
+}
+
+func (s *OCIStore) GetActivatedSequence(appID string, clusterID string) (int64, error) {
+	return -1, ErrNotImplemented
+}
+
+func (s *OCIStore) SetDownstreamActivationResult(appID string, clusterID string, sequence int64, isError bool, statusInfo string) error {
+	return ErrNotImplemented
+}
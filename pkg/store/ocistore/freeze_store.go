@@ -0,0 +1,21 @@
+package ocistore
+
+import (
+	freezetypes "github.com/replicatedhq/kots/pkg/freeze/types"
+)
+
+func (s *OCIStore) GetGlobalFreeze() (*freezetypes.Freeze, error) {
+	return nil, ErrNotImplemented
+}
+
+func (s *OCIStore) SetGlobalFreeze(freeze *freezetypes.Freeze) error {
+	return ErrNotImplemented
+}
+
+func (s *OCIStore) GetAppFreeze(appID string) (*freezetypes.Freeze, error) {
+	return nil, ErrNotImplemented
+}
+
+func (s *OCIStore) SetAppFreeze(appID string, freeze *freezetypes.Freeze) error {
+	return ErrNotImplemented
+}
@@ -0,0 +1,13 @@
+package ocistore
+
+import (
+	imagereporttypes "github.com/replicatedhq/kots/pkg/imagereport/types"
+)
+
+func (s *OCIStore) GetImageReport(appID string, sequence int64) (*imagereporttypes.Report, error) {
+	return nil, ErrNotImplemented
+}
+
+func (s *OCIStore) SetImageReport(appID string, report imagereporttypes.Report) error {
+	return ErrNotImplemented
+}
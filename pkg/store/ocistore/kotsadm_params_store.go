@@ -7,3 +7,15 @@ func (s *OCIStore) IsKotsadmIDGenerated() (bool, error) {
 func (s *OCIStore) SetIsKotsadmIDGenerated() error {
 	return ErrNotImplemented
 }
+
+func (s *OCIStore) IsReadOnlyMode() (bool, error) {
+	return false, ErrNotImplemented
+}
+
+func (s *OCIStore) SetReadOnlyMode(enabled bool) error {
+	return ErrNotImplemented
+}
+
+func (s *OCIStore) InitReadOnlyMode(enabled bool) error {
+	return ErrNotImplemented
+}
@@ -0,0 +1,13 @@
+package ocistore
+
+import (
+	ldapauthtypes "github.com/replicatedhq/kots/pkg/ldapauth/types"
+)
+
+func (s *OCIStore) GetLDAPSettings() (*ldapauthtypes.Settings, error) {
+	return nil, ErrNotImplemented
+}
+
+func (s *OCIStore) SetLDAPSettings(settings ldapauthtypes.Settings) error {
+	return ErrNotImplemented
+}
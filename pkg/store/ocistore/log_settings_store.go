@@ -0,0 +1,13 @@
+package ocistore
+
+import (
+	loggertypes "github.com/replicatedhq/kots/pkg/logger/types"
+)
+
+func (s *OCIStore) GetLogSettings() (*loggertypes.Settings, error) {
+	return nil, ErrNotImplemented
+}
+
+func (s *OCIStore) SetLogSettings(settings loggertypes.Settings) error {
+	return ErrNotImplemented
+}
@@ -0,0 +1,21 @@
+package ocistore
+
+import (
+	maintenancetypes "github.com/replicatedhq/kots/pkg/maintenance/types"
+)
+
+func (s *OCIStore) GetGlobalMaintenanceMessage() (*maintenancetypes.MaintenanceMessage, error) {
+	return nil, ErrNotImplemented
+}
+
+func (s *OCIStore) SetGlobalMaintenanceMessage(message *maintenancetypes.MaintenanceMessage) error {
+	return ErrNotImplemented
+}
+
+func (s *OCIStore) GetAppMaintenanceMessage(appID string) (*maintenancetypes.MaintenanceMessage, error) {
+	return nil, ErrNotImplemented
+}
+
+func (s *OCIStore) SetAppMaintenanceMessage(appID string, message *maintenancetypes.MaintenanceMessage) error {
+	return ErrNotImplemented
+}
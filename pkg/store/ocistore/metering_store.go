@@ -0,0 +1,15 @@
+package ocistore
+
+import (
+	"time"
+
+	meteringtypes "github.com/replicatedhq/kots/pkg/metering/types"
+)
+
+func (s *OCIStore) SetEntitlementUsage(appID string, name string, source string, value int64, reportedAt time.Time, limit *int64) (bool, error) {
+	return false, ErrNotImplemented
+}
+
+func (s *OCIStore) ListEntitlementUsage(appID string) ([]meteringtypes.EntitlementUsage, error) {
+	return nil, ErrNotImplemented
+}
@@ -2,3 +2,11 @@ package ocistore
 
 func (_ OCIStore) RunMigrations() {
 }
+
+func (_ OCIStore) RunSchemaMigrations() error {
+	return nil
+}
+
+func (_ OCIStore) RollbackSchemaMigrations(targetVersion int64) error {
+	return ErrNotImplemented
+}
@@ -1,5 +1,9 @@
 package ocistore
 
+import (
+	prometheustypes "github.com/replicatedhq/kots/pkg/prometheus/types"
+)
+
 func (s *OCIStore) GetPrometheusAddress() (string, error) {
 	return "", ErrNotImplemented
 }
@@ -7,3 +11,11 @@ func (s *OCIStore) GetPrometheusAddress() (string, error) {
 func (s *OCIStore) SetPrometheusAddress(address string) error {
 	return ErrNotImplemented
 }
+
+func (s *OCIStore) GetPrometheusAuth() (prometheustypes.Auth, error) {
+	return prometheustypes.Auth{}, ErrNotImplemented
+}
+
+func (s *OCIStore) SetPrometheusAuth(auth prometheustypes.Auth) error {
+	return ErrNotImplemented
+}
@@ -0,0 +1,13 @@
+package ocistore
+
+import (
+	releasefeedbacktypes "github.com/replicatedhq/kots/pkg/releasefeedback/types"
+)
+
+func (s *OCIStore) GetReleaseFeedbackConsent(appID string) (*releasefeedbacktypes.Consent, error) {
+	return nil, ErrNotImplemented
+}
+
+func (s *OCIStore) SetReleaseFeedbackConsent(appID string, consent releasefeedbacktypes.Consent) error {
+	return ErrNotImplemented
+}
@@ -0,0 +1,32 @@
+package ocistore
+
+import (
+	"time"
+
+	fleetreporttypes "github.com/replicatedhq/kots/pkg/fleetreport/types"
+	remoteinstalltypes "github.com/replicatedhq/kots/pkg/remoteinstall/types"
+)
+
+func (s *OCIStore) ListRemoteInstalls() ([]remoteinstalltypes.RemoteInstall, error) {
+	return nil, ErrNotImplemented
+}
+
+func (s *OCIStore) GetRemoteInstall(id string) (*remoteinstalltypes.RemoteInstall, error) {
+	return nil, ErrNotImplemented
+}
+
+func (s *OCIStore) CreateRemoteInstall(remoteInstall remoteinstalltypes.RemoteInstall) error {
+	return ErrNotImplemented
+}
+
+func (s *OCIStore) DeleteRemoteInstall(id string) error {
+	return ErrNotImplemented
+}
+
+func (s *OCIStore) SetRemoteInstallReport(id string, report *fleetreporttypes.Report, fetchedAt time.Time) error {
+	return ErrNotImplemented
+}
+
+func (s *OCIStore) SetRemoteInstallError(id string, message string) error {
+	return ErrNotImplemented
+}
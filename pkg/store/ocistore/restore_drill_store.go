@@ -0,0 +1,21 @@
+package ocistore
+
+import (
+	restoredrilltypes "github.com/replicatedhq/kots/pkg/restoredrill/types"
+)
+
+func (s *OCIStore) ListRestoreDrills(appID string) ([]restoredrilltypes.Drill, error) {
+	return nil, ErrNotImplemented
+}
+
+func (s *OCIStore) CreateRestoreDrill(drill restoredrilltypes.Drill) error {
+	return ErrNotImplemented
+}
+
+func (s *OCIStore) UpdateRestoreDrill(drill restoredrilltypes.Drill) error {
+	return ErrNotImplemented
+}
+
+func (s *OCIStore) DeleteScheduledRestoreDrills(appID string) error {
+	return ErrNotImplemented
+}
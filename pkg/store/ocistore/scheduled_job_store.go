@@ -0,0 +1,23 @@
+package ocistore
+
+import (
+	"time"
+
+	scheduledjobtypes "github.com/replicatedhq/kots/pkg/scheduledjob/types"
+)
+
+func (s *OCIStore) CreateScheduledJobRun(run scheduledjobtypes.Run) error {
+	return ErrNotImplemented
+}
+
+func (s *OCIStore) FinishScheduledJobRun(id string, status scheduledjobtypes.Status, message string, finishedAt time.Time) error {
+	return ErrNotImplemented
+}
+
+func (s *OCIStore) ListLatestScheduledJobRuns() ([]scheduledjobtypes.Run, error) {
+	return nil, ErrNotImplemented
+}
+
+func (s *OCIStore) DeleteScheduledJobRunsBefore(before time.Time) (int64, error) {
+	return 0, ErrNotImplemented
+}
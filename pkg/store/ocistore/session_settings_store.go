@@ -0,0 +1,17 @@
+package ocistore
+
+import (
+	sessiontypes "github.com/replicatedhq/kots/pkg/session/types"
+)
+
+func (s *OCIStore) GetSessionSettings() (*sessiontypes.SessionSettings, error) {
+	return nil, ErrNotImplemented
+}
+
+func (s *OCIStore) SetSessionSettings(settings sessiontypes.SessionSettings) error {
+	return ErrNotImplemented
+}
+
+func (s *OCIStore) InitSessionSettings(settings sessiontypes.SessionSettings) error {
+	return ErrNotImplemented
+}
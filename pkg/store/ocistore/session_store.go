@@ -44,6 +44,7 @@ func (s *OCIStore) CreateSession(forUser *usertypes.User, issuedAt time.Time, ex
 
 	session := sessiontypes.Session{
 		ID:        id,
+		UserID:    forUser.ID,
 		IssuedAt:  issuedAt,
 		ExpiresAt: expiresAt,
 		Roles:     roles,
@@ -107,6 +108,48 @@ func (s *OCIStore) DeleteSession(id string) error {
 	return nil
 }
 
+func (s *OCIStore) DeleteSessions(ids []string) error {
+	return ErrNotImplemented
+}
+
+func (s *OCIStore) ListSessions() ([]sessiontypes.Session, error) {
+	return nil, ErrNotImplemented
+}
+
+func (s *OCIStore) ImportSessions(sessions []sessiontypes.Session) error {
+	return ErrNotImplemented
+}
+
+func (s *OCIStore) UpdateSessionActivity(id string, lastActiveAt time.Time) error {
+	secret, err := s.getSessionSecret()
+	if err != nil {
+		return errors.Wrap(err, "failed to get session secret")
+	}
+
+	data, ok := secret.Data[id]
+	if !ok {
+		return nil
+	}
+
+	session := sessiontypes.Session{}
+	if err := json.Unmarshal(data, &session); err != nil {
+		return errors.Wrap(err, "failed to unmarshal session")
+	}
+	session.LastActiveAt = lastActiveAt
+
+	b, err := json.Marshal(session)
+	if err != nil {
+		return errors.Wrap(err, "failed to encoded session")
+	}
+	secret.Data[id] = b
+
+	if err := s.updateSessionSecret(secret); err != nil {
+		return errors.Wrap(err, "failed to update session secret")
+	}
+
+	return nil
+}
+
 func (s *OCIStore) getSessionSecret() (*corev1.Secret, error) {
 	if s.sessionSecret != nil && time.Now().Before(s.sessionExpiration) {
 		return s.sessionSecret, nil
@@ -147,6 +147,10 @@ func (s *OCIStore) ClearTaskStatus(id string) error {
 	return nil
 }
 
+func (s *OCIStore) ClearStaleTaskStatuses(notUpdatedSince time.Time) ([]string, error) {
+	return nil, ErrNotImplemented
+}
+
 func (s *OCIStore) GetTaskStatus(id string) (string, string, error) {
 	taskStatusLock.Lock()
 	defer taskStatusLock.Unlock()
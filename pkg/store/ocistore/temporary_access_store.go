@@ -0,0 +1,31 @@
+package ocistore
+
+import (
+	"time"
+
+	temporaryaccesstypes "github.com/replicatedhq/kots/pkg/temporaryaccess/types"
+)
+
+func (s *OCIStore) CreateTemporaryAccess(grant temporaryaccesstypes.Grant, tokenSHA256 string) error {
+	return ErrNotImplemented
+}
+
+func (s *OCIStore) ListTemporaryAccess() ([]temporaryaccesstypes.Grant, error) {
+	return nil, ErrNotImplemented
+}
+
+func (s *OCIStore) GetTemporaryAccess(id string) (*temporaryaccesstypes.Grant, error) {
+	return nil, ErrNotImplemented
+}
+
+func (s *OCIStore) GetTemporaryAccessByToken(tokenSHA256 string) (*temporaryaccesstypes.Grant, error) {
+	return nil, ErrNotImplemented
+}
+
+func (s *OCIStore) AddTemporaryAccessSession(id string, sessionID string, usedAt time.Time) error {
+	return ErrNotImplemented
+}
+
+func (s *OCIStore) RevokeTemporaryAccess(id string, revokedBy string, revokedAt time.Time) error {
+	return ErrNotImplemented
+}
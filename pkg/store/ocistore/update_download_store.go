@@ -0,0 +1,17 @@
+package ocistore
+
+import (
+	updatecheckertypes "github.com/replicatedhq/kots/pkg/updatechecker/types"
+)
+
+func (s *OCIStore) SetUpdateDownloadFailure(appID string, failure updatecheckertypes.UpdateDownloadFailure) error {
+	return ErrNotImplemented
+}
+
+func (s *OCIStore) ListUpdateDownloadFailures(appID string) ([]updatecheckertypes.UpdateDownloadFailure, error) {
+	return nil, ErrNotImplemented
+}
+
+func (s *OCIStore) ClearUpdateDownloadFailures(appID string) error {
+	return ErrNotImplemented
+}
@@ -0,0 +1,13 @@
+package ocistore
+
+import (
+	uploadquotatypes "github.com/replicatedhq/kots/pkg/uploadquota/types"
+)
+
+func (s *OCIStore) GetUploadQuota() (*uploadquotatypes.UploadQuota, error) {
+	return nil, ErrNotImplemented
+}
+
+func (s *OCIStore) SetUploadQuota(quota uploadquotatypes.UploadQuota) error {
+	return ErrNotImplemented
+}
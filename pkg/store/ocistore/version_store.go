@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"io/ioutil"
 	"net/http"
 	"os"
@@ -143,6 +144,14 @@ func (s *OCIStore) IsSnapshotsSupportedForVersion(a *apptypes.App, sequence int6
 
 // CreateAppVersion takes an unarchived app, makes an archive and then uploads it
 // to s3 with the appID and sequence specified
+func (s *OCIStore) DeleteOrphanedAppVersionArchives(olderThan time.Time) (int64, int64, error) {
+	return 0, 0, ErrNotImplemented
+}
+
+func (s *OCIStore) DeleteIncompleteAppVersions(olderThan time.Time) (int64, error) {
+	return 0, ErrNotImplemented
+}
+
 func (s *OCIStore) CreateAppVersionArchive(appID string, sequence int64, archivePath string) error {
 	paths := []string{
 		filepath.Join(archivePath, "upstream"),
@@ -230,6 +239,58 @@ func (s *OCIStore) CreateAppVersionArchive(appID string, sequence int64, archive
 // GetAppVersionArchive will fetch the archive and return a string that contains a
 // directory name where it's extracted into
 func (s *OCIStore) GetAppVersionArchive(appID string, sequence int64, dstPath string) error {
+	archiveFilename, err := s.pullAppVersionArchive(appID, sequence, dstPath)
+	if err != nil {
+		return err
+	}
+
+	tarGz := archiver.TarGz{
+		Tar: &archiver.Tar{
+			ImplicitTopLevelFolder: false,
+		},
+	}
+	if err := tarGz.Unarchive(archiveFilename, dstPath); err != nil {
+		return errors.Wrap(err, "failed to unarchive")
+	}
+
+	return nil
+}
+
+// GetAppVersionArchiveReader returns the gzipped tar archive of the app version without extracting it
+func (s *OCIStore) GetAppVersionArchiveReader(appID string, sequence int64) (io.ReadCloser, error) {
+	tmpDir, err := ioutil.TempDir("", "kotsadm")
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create temp dir")
+	}
+
+	archiveFilename, err := s.pullAppVersionArchive(appID, sequence, tmpDir)
+	if err != nil {
+		os.RemoveAll(tmpDir)
+		return nil, err
+	}
+
+	f, err := os.Open(archiveFilename)
+	if err != nil {
+		os.RemoveAll(tmpDir)
+		return nil, errors.Wrap(err, "failed to open archive")
+	}
+
+	return &tempDirFile{File: f, dir: tmpDir}, nil
+}
+
+// tempDirFile removes the directory the file is in when it's closed
+type tempDirFile struct {
+	*os.File
+	dir string
+}
+
+func (f *tempDirFile) Close() error {
+	defer os.RemoveAll(f.dir)
+	return f.File.Close()
+}
+
+// pullAppVersionArchive pulls the archive of the app version into dstPath and returns the path to the archive file
+func (s *OCIStore) pullAppVersionArchive(appID string, sequence int64, dstPath string) (string, error) {
 	// too noisy
 	// logger.Debug("getting app version archive",
 	// 	zap.String("appID", appID),
@@ -272,7 +333,7 @@ func (s *OCIStore) GetAppVersionArchive(appID string, sequence int64, dstPath st
 
 	pulledDescriptor, _, err := oras.Pull(context.Background(), resolver, ref, fileStore, oras.WithAllowedMediaTypes(allowedMediaTypes))
 	if err != nil {
-		return errors.Wrap(err, "failed to pull from registry storage")
+		return "", errors.Wrap(err, "failed to pull from registry storage")
 	}
 
 	logger.Debug("pulled app archive from docker registry",
@@ -281,16 +342,7 @@ func (s *OCIStore) GetAppVersionArchive(appID string, sequence int64, dstPath st
 		zap.String("ref", ref),
 		zap.String("digest", pulledDescriptor.Digest.String()))
 
-	tarGz := archiver.TarGz{
-		Tar: &archiver.Tar{
-			ImplicitTopLevelFolder: false,
-		},
-	}
-	if err := tarGz.Unarchive(filepath.Join(dstPath, fmt.Sprintf("appversion-%s-%d.tar.gz", appID, sequence)), dstPath); err != nil {
-		return errors.Wrap(err, "failed to unarchive")
-	}
-
-	return nil
+	return filepath.Join(dstPath, fmt.Sprintf("appversion-%s-%d.tar.gz", appID, sequence)), nil
 }
 
 func (s *OCIStore) CreateAppVersion(appID string, currentSequence *int64, filesInDir string, source string, skipPreflights bool, gitops gitopstypes.DownstreamGitOps) (int64, error) {
@@ -497,6 +549,10 @@ func (s *OCIStore) GetAppVersionsAfter(appID string, sequence int64) ([]*version
 	return nil, ErrNotImplemented
 }
 
+func (s *OCIStore) SetAppVersionNotes(appID string, sequence int64, notes string, labels []string) error {
+	return ErrNotImplemented
+}
+
 func refFromAppVersion(appID string, sequence int64, baseURI string) string {
 	baseURI = strings.TrimSuffix(baseURI, "/")
 
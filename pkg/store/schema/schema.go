@@ -0,0 +1,125 @@
+package schema
+
+import (
+	"database/sql"
+	"fmt"
+	"sort"
+
+	"github.com/pkg/errors"
+)
+
+// Migration is a versioned change to the kotsadm database that schemahero cannot express on its own,
+// such as moving or transforming existing data. Versions must be unique and are applied in increasing order.
+type Migration struct {
+	Version int64
+	Name    string
+	Up      func(tx *sql.Tx) error
+	// Down reverts Up. It is only run in dev environments and may be nil if the migration cannot be reverted.
+	Down func(tx *sql.Tx) error
+}
+
+// TooNewError is returned when the database has migrations applied that this binary does not know about,
+// which happens when kotsadm is downgraded after an upgrade migrated the schema.
+type TooNewError struct {
+	DatabaseVersion  int64
+	SupportedVersion int64
+}
+
+func (e TooNewError) Error() string {
+	return fmt.Sprintf("database schema version %d is newer than the latest version %d supported by this version of kotsadm", e.DatabaseVersion, e.SupportedVersion)
+}
+
+// Validate checks that the migrations are ordered by version, have unique positive versions and can be applied
+func Validate(migrations []Migration) error {
+	for i, m := range migrations {
+		if m.Version <= 0 {
+			return errors.Errorf("migration %q has invalid version %d", m.Name, m.Version)
+		}
+		if m.Name == "" {
+			return errors.Errorf("migration %d has no name", m.Version)
+		}
+		if m.Up == nil {
+			return errors.Errorf("migration %d (%s) has no up function", m.Version, m.Name)
+		}
+		if i > 0 && m.Version <= migrations[i-1].Version {
+			return errors.Errorf("migration %d (%s) is out of order", m.Version, m.Name)
+		}
+	}
+	return nil
+}
+
+// LatestVersion returns the version of the last migration, or 0 if there are none
+func LatestVersion(migrations []Migration) int64 {
+	if len(migrations) == 0 {
+		return 0
+	}
+	return migrations[len(migrations)-1].Version
+}
+
+// CheckVersion returns a TooNewError if any of the applied versions is unknown to the migrations
+// and newer than all of them
+func CheckVersion(migrations []Migration, applied []int64) error {
+	latest := LatestVersion(migrations)
+	for _, version := range applied {
+		if version > latest {
+			return TooNewError{
+				DatabaseVersion:  maxVersion(applied),
+				SupportedVersion: latest,
+			}
+		}
+	}
+	return nil
+}
+
+// Pending returns the migrations that have not been applied, in the order they must be applied
+func Pending(migrations []Migration, applied []int64) []Migration {
+	isApplied := map[int64]bool{}
+	for _, version := range applied {
+		isApplied[version] = true
+	}
+
+	pending := []Migration{}
+	for _, m := range migrations {
+		if !isApplied[m.Version] {
+			pending = append(pending, m)
+		}
+	}
+	return pending
+}
+
+// ToRollBack returns the applied migrations newer than the target version, in the order they must be reverted
+func ToRollBack(migrations []Migration, applied []int64, targetVersion int64) ([]Migration, error) {
+	byVersion := map[int64]Migration{}
+	for _, m := range migrations {
+		byVersion[m.Version] = m
+	}
+
+	sorted := append([]int64{}, applied...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] > sorted[j] })
+
+	rollback := []Migration{}
+	for _, version := range sorted {
+		if version <= targetVersion {
+			break
+		}
+		m, ok := byVersion[version]
+		if !ok {
+			return nil, errors.Errorf("applied migration %d is unknown to this version of kotsadm", version)
+		}
+		if m.Down == nil {
+			return nil, errors.Errorf("migration %d (%s) cannot be rolled back", m.Version, m.Name)
+		}
+		rollback = append(rollback, m)
+	}
+	return rollback, nil
+}
+
+func maxVersion(versions []int64) int64 {
+	max := int64(0)
+	for _, version := range versions {
+		if version > max {
+			max = version
+		}
+	}
+	return max
+}
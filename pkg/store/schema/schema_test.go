@@ -0,0 +1,117 @@
+package schema
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func noop(tx *sql.Tx) error {
+	return nil
+}
+
+func testMigrations() []Migration {
+	return []Migration{
+		{Version: 1, Name: "one", Up: noop, Down: noop},
+		{Version: 2, Name: "two", Up: noop},
+		{Version: 3, Name: "three", Up: noop, Down: noop},
+	}
+}
+
+func Test_Validate(t *testing.T) {
+	tests := []struct {
+		name       string
+		migrations []Migration
+		wantErr    bool
+	}{
+		{
+			name:       "valid",
+			migrations: testMigrations(),
+		},
+		{
+			name:       "empty",
+			migrations: []Migration{},
+		},
+		{
+			name: "out of order",
+			migrations: []Migration{
+				{Version: 2, Name: "two", Up: noop},
+				{Version: 1, Name: "one", Up: noop},
+			},
+			wantErr: true,
+		},
+		{
+			name: "duplicate version",
+			migrations: []Migration{
+				{Version: 1, Name: "one", Up: noop},
+				{Version: 1, Name: "also one", Up: noop},
+			},
+			wantErr: true,
+		},
+		{
+			name: "no up",
+			migrations: []Migration{
+				{Version: 1, Name: "one"},
+			},
+			wantErr: true,
+		},
+		{
+			name: "zero version",
+			migrations: []Migration{
+				{Version: 0, Name: "zero", Up: noop},
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Validate(tt.migrations)
+			if tt.wantErr {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func Test_CheckVersion(t *testing.T) {
+	req := require.New(t)
+
+	req.NoError(CheckVersion(testMigrations(), []int64{}))
+	req.NoError(CheckVersion(testMigrations(), []int64{1, 2, 3}))
+
+	err := CheckVersion(testMigrations(), []int64{1, 2, 3, 5, 4})
+	req.Equal(TooNewError{DatabaseVersion: 5, SupportedVersion: 3}, err)
+}
+
+func Test_Pending(t *testing.T) {
+	req := require.New(t)
+
+	pending := Pending(testMigrations(), []int64{1, 3})
+	req.Len(pending, 1)
+	req.Equal(int64(2), pending[0].Version)
+
+	req.Len(Pending(testMigrations(), []int64{}), 3)
+	req.Empty(Pending(testMigrations(), []int64{1, 2, 3}))
+}
+
+func Test_ToRollBack(t *testing.T) {
+	req := require.New(t)
+
+	rollback, err := ToRollBack(testMigrations(), []int64{1, 3}, 0)
+	req.NoError(err)
+	req.Len(rollback, 2)
+	req.Equal(int64(3), rollback[0].Version)
+	req.Equal(int64(1), rollback[1].Version)
+
+	// migration 2 has no down
+	_, err = ToRollBack(testMigrations(), []int64{1, 2, 3}, 1)
+	req.Error(err)
+
+	rollback, err = ToRollBack(testMigrations(), []int64{1, 2, 3}, 2)
+	req.NoError(err)
+	req.Len(rollback, 1)
+	req.Equal(int64(3), rollback[0].Version)
+}
@@ -8,10 +8,14 @@ import (
 var (
 	hasStore    = false
 	globalStore Store
+
+	hasReadStore    = false
+	globalReadStore ReadStore
 )
 
 var _ Store = (*kotsstore.KOTSStore)(nil)
 var _ Store = (*ocistore.OCIStore)(nil)
+var _ ReadStore = (*kotsstore.KOTSStore)(nil)
 
 func GetStore() Store {
 	if !hasStore {
@@ -25,3 +29,18 @@ func GetStore() Store {
 func storeFromEnv() Store {
 	return kotsstore.StoreFromEnv()
 }
+
+// GetReadStore returns the store for the heavy GET endpoints. It uses the read replica in POSTGRES_READ_URI if one is
+// configured, and the same database as GetStore otherwise.
+func GetReadStore() ReadStore {
+	if !hasReadStore {
+		globalReadStore = readStoreFromEnv()
+		hasReadStore = true
+	}
+
+	return globalReadStore
+}
+
+func readStoreFromEnv() ReadStore {
+	return kotsstore.ReadStoreFromEnv()
+}
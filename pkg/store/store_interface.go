@@ -2,23 +2,46 @@ package store
 
 import (
 	"context"
+	"io"
 	"time"
 
 	kotsv1beta1 "github.com/replicatedhq/kots/kotskinds/apis/kots/v1beta1"
+	admissiontypes "github.com/replicatedhq/kots/pkg/admission/types"
 	airgaptypes "github.com/replicatedhq/kots/pkg/airgap/types"
 	appstatustypes "github.com/replicatedhq/kots/pkg/api/appstatus/types"
 	downstreamtypes "github.com/replicatedhq/kots/pkg/api/downstream/types"
 	versiontypes "github.com/replicatedhq/kots/pkg/api/version/types"
 	apptypes "github.com/replicatedhq/kots/pkg/app/types"
+	archiveintegritytypes "github.com/replicatedhq/kots/pkg/archiveintegrity/types"
+	assetcachetypes "github.com/replicatedhq/kots/pkg/assetcache/types"
+	canarytypes "github.com/replicatedhq/kots/pkg/canary/types"
+	deployapprovaltypes "github.com/replicatedhq/kots/pkg/deployapproval/types"
+	deployhistorytypes "github.com/replicatedhq/kots/pkg/deployhistory/types"
+	fleetreporttypes "github.com/replicatedhq/kots/pkg/fleetreport/types"
+	freezetypes "github.com/replicatedhq/kots/pkg/freeze/types"
 	gitopstypes "github.com/replicatedhq/kots/pkg/gitops/types"
+	imagereporttypes "github.com/replicatedhq/kots/pkg/imagereport/types"
 	snapshottypes "github.com/replicatedhq/kots/pkg/kotsadmsnapshot/types"
+	ldapauthtypes "github.com/replicatedhq/kots/pkg/ldapauth/types"
+	loggertypes "github.com/replicatedhq/kots/pkg/logger/types"
+	maintenancetypes "github.com/replicatedhq/kots/pkg/maintenance/types"
+	meteringtypes "github.com/replicatedhq/kots/pkg/metering/types"
 	installationtypes "github.com/replicatedhq/kots/pkg/online/types"
+	postdeploytesttypes "github.com/replicatedhq/kots/pkg/postdeploytest/types"
 	preflighttypes "github.com/replicatedhq/kots/pkg/preflight/types"
+	prometheustypes "github.com/replicatedhq/kots/pkg/prometheus/types"
 	registrytypes "github.com/replicatedhq/kots/pkg/registry/types"
+	releasefeedbacktypes "github.com/replicatedhq/kots/pkg/releasefeedback/types"
+	remoteinstalltypes "github.com/replicatedhq/kots/pkg/remoteinstall/types"
 	rendertypes "github.com/replicatedhq/kots/pkg/render/types"
+	restoredrilltypes "github.com/replicatedhq/kots/pkg/restoredrill/types"
+	scheduledjobtypes "github.com/replicatedhq/kots/pkg/scheduledjob/types"
 	sessiontypes "github.com/replicatedhq/kots/pkg/session/types"
 	"github.com/replicatedhq/kots/pkg/supportbundle/types"
 	supportbundletypes "github.com/replicatedhq/kots/pkg/supportbundle/types"
+	temporaryaccesstypes "github.com/replicatedhq/kots/pkg/temporaryaccess/types"
+	updatecheckertypes "github.com/replicatedhq/kots/pkg/updatechecker/types"
+	uploadquotatypes "github.com/replicatedhq/kots/pkg/uploadquota/types"
 	usertypes "github.com/replicatedhq/kots/pkg/user/types"
 	troubleshootredact "github.com/replicatedhq/troubleshoot/pkg/redact"
 )
@@ -41,14 +64,51 @@ type Store interface {
 	SnapshotStore
 	InstallationStore
 	KotsadmParamsStore
+	MeteringStore
+	ConfigFileStore
+	UpdateDownloadStore
+	ImageReportStore
+	MaintenanceStore
+	DeployApprovalStore
+	AppLockStore
+	UploadQuotaStore
+	SessionSettingsStore
+	LDAPSettingsStore
+	RestoreDrillStore
+	CanaryStore
+	RemoteInstallStore
+	LogSettingsStore
+	ScheduledJobStore
+	ArchiveChecksumStore
+	DeployHistoryStore
+	AssetCacheStore
+	FreezeStore
+	ReleaseFeedbackStore
+	TemporaryAccessStore
 
 	Init() error // this may need options
 	WaitForReady(ctx context.Context) error
 	IsNotFound(err error) bool
 }
 
+// ReadStore is the read path of the store used by the heavy GET endpoints, like the version history and file trees.
+// It can be backed by a read replica, so results can lag behind writes made through the Store.
+type ReadStore interface {
+	GetCurrentVersion(appID string, clusterID string) (*downstreamtypes.DownstreamVersion, error)
+	GetPendingVersions(appID string, clusterID string) ([]downstreamtypes.DownstreamVersion, error)
+	GetPastVersions(appID string, clusterID string) ([]downstreamtypes.DownstreamVersion, error)
+	// ListPendingVersionCounts returns the number of versions newer than the deployed version of each downstream,
+	// summed per app id
+	ListPendingVersionCounts() (map[string]int64, error)
+	ListDownstreamVersions(appID string, clusterID string, opts downstreamtypes.VersionHistoryOptions) ([]downstreamtypes.DownstreamVersion, int64, error)
+	GetAppVersionArchive(appID string, sequence int64, dstPath string) error
+	GetAppVersionArchiveReader(appID string, sequence int64) (io.ReadCloser, error)
+}
+
 type Migrations interface {
 	RunMigrations()
+	RunSchemaMigrations() error
+	RollbackSchemaMigrations(targetVersion int64) error
 }
 
 type RegistryStore interface {
@@ -82,6 +142,8 @@ type PreflightStore interface {
 type PrometheusStore interface {
 	GetPrometheusAddress() (address string, err error)
 	SetPrometheusAddress(address string) error
+	GetPrometheusAuth() (prometheustypes.Auth, error)
+	SetPrometheusAuth(auth prometheustypes.Auth) error
 }
 
 type AirgapStore interface {
@@ -96,12 +158,20 @@ type TaskStore interface {
 	UpdateTaskStatusTimestamp(taskID string) error
 	ClearTaskStatus(taskID string) error
 	GetTaskStatus(taskID string) (status string, message string, err error)
+	// ClearStaleTaskStatuses clears the running task statuses that were not updated since notUpdatedSince
+	ClearStaleTaskStatuses(notUpdatedSince time.Time) (taskIDs []string, err error)
 }
 
 type SessionStore interface {
 	CreateSession(user *usertypes.User, issuedAt time.Time, expiresAt time.Time, roles []string) (*sessiontypes.Session, error)
 	DeleteSession(sessionID string) error
+	DeleteSessions(sessionIDs []string) error
 	GetSession(sessionID string) (*sessiontypes.Session, error)
+	ListSessions() ([]sessiontypes.Session, error)
+	// ImportSessions stores sessions exported from another kotsadm, keeping their ids
+	ImportSessions(sessions []sessiontypes.Session) error
+	// UpdateSessionActivity saves the time of the last request made with the session, it does nothing if the session was deleted
+	UpdateSessionActivity(sessionID string, lastActiveAt time.Time) error
 }
 
 type AppStatusStore interface {
@@ -123,8 +193,26 @@ type AppStore interface {
 	GetDownstream(clusterID string) (*downstreamtypes.Downstream, error)
 	IsGitOpsEnabledForApp(appID string) (bool, error)
 	SetUpdateCheckerSpec(appID string, updateCheckerSpec string) error
+	SetDeployPolicy(appID string, deployPolicy apptypes.DeployPolicy) error
+	SetAdmissionDryRun(appID string, enabled bool) error
+	SetAppArchived(appID string, archived bool) error
+	SetAppProtected(appID string, protected bool) error
+	SetRequireDeployApproval(appID string, required bool) error
+	SetApplyPolicy(appID string, applyPolicy apptypes.ApplyPolicy) error
+	SetCanaryPolicy(appID string, canaryPolicy apptypes.CanaryPolicy) error
+	SetAppNamespace(appID string, namespace string) error
+	SetAppLabels(appID string, labels map[string]string) error
+	SetImagePushBandwidthLimit(appID string, bytesPerSecond int64) error
 	SetSnapshotTTL(appID string, snapshotTTL string) error
 	SetSnapshotSchedule(appID string, snapshotSchedule string) error
+	SetRestoreDrillSchedule(appID string, restoreDrillSchedule string) error
+	// SetScheduleTimezone sets the timezone of the schedules of the app that do not set their own with CRON_TZ
+	SetScheduleTimezone(appID string, timezone string) error
+	// TrashApp removes the app until it is restored with RestoreTrashedApp, or removed permanently with RemoveApp
+	TrashApp(appID string) error
+	RestoreTrashedApp(appID string) error
+	// ListRemovedApps returns the apps in the trash, the least recently removed first
+	ListRemovedApps() ([]*apptypes.App, error)
 	RemoveApp(appID string) error
 }
 
@@ -142,10 +230,27 @@ type DownstreamStore interface {
 	GetStatusForVersion(appID string, clusterID string, sequence int64) (string, error)
 	GetPendingVersions(appID string, clusterID string) ([]downstreamtypes.DownstreamVersion, error)
 	GetPastVersions(appID string, clusterID string) ([]downstreamtypes.DownstreamVersion, error)
+	// ListDownstreamVersions returns a page of the versions that match the options, newest first, and the number of
+	// versions that match
+	ListDownstreamVersions(appID string, clusterID string, opts downstreamtypes.VersionHistoryOptions) ([]downstreamtypes.DownstreamVersion, int64, error)
 	GetDownstreamOutput(appID string, clusterID string, sequence int64) (*downstreamtypes.DownstreamOutput, error)
 	IsDownstreamDeploySuccessful(appID string, clusterID string, sequence int64) (bool, error)
 	UpdateDownstreamDeployStatus(appID string, clusterID string, sequence int64, isError bool, output downstreamtypes.DownstreamOutput) error
 	DeleteDownstreamDeployStatus(appID string, clusterID string, sequence int64) error
+	GetDownstreamPostDeployTestResults(appID string, clusterID string, sequence int64) ([]postdeploytesttypes.Result, error)
+	SetDownstreamPostDeployTestResults(appID string, clusterID string, sequence int64, results []postdeploytesttypes.Result) error
+	GetDownstreamAdmissionValidation(appID string, clusterID string, sequence int64) (*admissiontypes.Validation, error)
+	SetDownstreamAdmissionValidation(appID string, clusterID string, sequence int64, validation admissiontypes.Validation) error
+	GetDownstreamServiceAccount(appID string, clusterID string) (string, error)
+	SetDownstreamServiceAccount(appID string, clusterID string, serviceAccount string) error
+	CreateDownstreamOutputArchive(appID string, clusterID string, sequence int64, archivePath string) error
+	GetDownstreamOutputArchive(appID string, clusterID string, sequence int64) (archivePath string, err error)
+	DeleteDownstreamOutputArchivesBefore(appID string, clusterID string, sequence int64) error
+	// GetActivatingSequence and GetActivatedSequence return -1 if no version of the downstream is being, or was,
+	// activated
+	GetActivatingSequence(appID string, clusterID string) (int64, error)
+	GetActivatedSequence(appID string, clusterID string) (int64, error)
+	SetDownstreamActivationResult(appID string, clusterID string, sequence int64, isError bool, statusInfo string) error
 }
 
 type SnapshotStore interface {
@@ -165,10 +270,15 @@ type VersionStore interface {
 	IsRollbackSupportedForVersion(appID string, sequence int64) (bool, error)
 	IsSnapshotsSupportedForVersion(a *apptypes.App, sequence int64, renderer rendertypes.Renderer) (bool, error)
 	GetAppVersionArchive(appID string, sequence int64, dstPath string) error
+	GetAppVersionArchiveReader(appID string, sequence int64) (io.ReadCloser, error)
 	CreateAppVersionArchive(appID string, sequence int64, archivePath string) error
+	DeleteOrphanedAppVersionArchives(olderThan time.Time) (deleted int64, reclaimedBytes int64, err error)
+	// DeleteIncompleteAppVersions deletes the versions that were left half created and returns how many
+	DeleteIncompleteAppVersions(olderThan time.Time) (int64, error)
 	CreateAppVersion(appID string, currentSequence *int64, filesInDir string, source string, skipPreflights bool, gitops gitopstypes.DownstreamGitOps) (int64, error)
 	GetAppVersion(string, int64) (*versiontypes.AppVersion, error)
 	GetAppVersionsAfter(string, int64) ([]*versiontypes.AppVersion, error)
+	SetAppVersionNotes(appID string, sequence int64, notes string, labels []string) error
 }
 
 type LicenseStore interface {
@@ -178,6 +288,8 @@ type LicenseStore interface {
 
 	// originalLicenseData is the data received from the replicated API that was never marshalled locally so all fields are intact
 	UpdateAppLicense(appID string, sequence int64, archiveDir string, newLicense *kotsv1beta1.License, originalLicenseData string, failOnVersionCreate bool, gitops gitopstypes.DownstreamGitOps, renderer rendertypes.Renderer) (int64, error)
+	// ArchiveAppLicense keeps a copy of the current license of the app before it is replaced by a different license
+	ArchiveAppLicense(appID string, replacedByLicenseID string, replacedBy string) error
 }
 
 type ClusterStore interface {
@@ -196,4 +308,158 @@ type InstallationStore interface {
 type KotsadmParamsStore interface {
 	IsKotsadmIDGenerated() (bool, error)
 	SetIsKotsadmIDGenerated() error
+	IsReadOnlyMode() (bool, error)
+	SetReadOnlyMode(enabled bool) error
+	// InitReadOnlyMode sets read-only mode only if it has never been set
+	InitReadOnlyMode(enabled bool) error
+}
+
+type MeteringStore interface {
+	SetEntitlementUsage(appID string, name string, source string, value int64, reportedAt time.Time, limit *int64) (bool, error)
+	ListEntitlementUsage(appID string) ([]meteringtypes.EntitlementUsage, error)
+}
+
+type ConfigFileStore interface {
+	CreateConfigFile(checksum string, filePath string) error
+	GetConfigFile(checksum string) (io.ReadCloser, error)
+}
+
+type UpdateDownloadStore interface {
+	SetUpdateDownloadFailure(appID string, failure updatecheckertypes.UpdateDownloadFailure) error
+	ListUpdateDownloadFailures(appID string) ([]updatecheckertypes.UpdateDownloadFailure, error)
+	ClearUpdateDownloadFailures(appID string) error
+}
+
+type ImageReportStore interface {
+	GetImageReport(appID string, sequence int64) (*imagereporttypes.Report, error)
+	SetImageReport(appID string, report imagereporttypes.Report) error
+}
+
+type AssetCacheStore interface {
+	// RegisterCachedAsset records the uri of an asset so that it can be served from the cache key. It is a no-op if the asset is already registered.
+	RegisterCachedAsset(cacheKey string, uri string) error
+	GetCachedAsset(cacheKey string) (*assetcachetypes.Asset, error)
+	SetCachedAsset(asset assetcachetypes.Asset) error
+}
+
+type MaintenanceStore interface {
+	GetGlobalMaintenanceMessage() (*maintenancetypes.MaintenanceMessage, error)
+	SetGlobalMaintenanceMessage(message *maintenancetypes.MaintenanceMessage) error
+	GetAppMaintenanceMessage(appID string) (*maintenancetypes.MaintenanceMessage, error)
+	SetAppMaintenanceMessage(appID string, message *maintenancetypes.MaintenanceMessage) error
+}
+
+type FreezeStore interface {
+	GetGlobalFreeze() (*freezetypes.Freeze, error)
+	SetGlobalFreeze(freeze *freezetypes.Freeze) error
+	GetAppFreeze(appID string) (*freezetypes.Freeze, error)
+	SetAppFreeze(appID string, freeze *freezetypes.Freeze) error
+}
+
+type ReleaseFeedbackStore interface {
+	GetReleaseFeedbackConsent(appID string) (*releasefeedbacktypes.Consent, error)
+	SetReleaseFeedbackConsent(appID string, consent releasefeedbacktypes.Consent) error
+}
+
+type TemporaryAccessStore interface {
+	CreateTemporaryAccess(grant temporaryaccesstypes.Grant, tokenSHA256 string) error
+	ListTemporaryAccess() ([]temporaryaccesstypes.Grant, error)
+	// GetTemporaryAccess and GetTemporaryAccessByToken return nil if the grant does not exist
+	GetTemporaryAccess(id string) (*temporaryaccesstypes.Grant, error)
+	GetTemporaryAccessByToken(tokenSHA256 string) (*temporaryaccesstypes.Grant, error)
+	AddTemporaryAccessSession(id string, sessionID string, usedAt time.Time) error
+	RevokeTemporaryAccess(id string, revokedBy string, revokedAt time.Time) error
+}
+
+type DeployApprovalStore interface {
+	CreateDeployApproval(approval deployapprovaltypes.DeployApproval) error
+	GetDeployApproval(approvalID string) (*deployapprovaltypes.DeployApproval, error)
+	GetPendingDeployApproval(appID string, sequence int64) (*deployapprovaltypes.DeployApproval, error)
+	ListDeployApprovals(appID string) ([]deployapprovaltypes.DeployApproval, error)
+	// SetDeployApprovalDecision decides the approval if it is pending. It returns false if it was decided already.
+	SetDeployApprovalDecision(approvalID string, status deployapprovaltypes.Status, decidedBy string, decidedAt time.Time) (bool, error)
+	// ReopenDeployApproval sets an approved approval back to pending
+	ReopenDeployApproval(approvalID string) error
+}
+
+type AppLockStore interface {
+	// AcquireAppLock takes the lock of the app for the holder if it is not held or has expired. It returns false if another holder has it.
+	AcquireAppLock(appID string, holder string, expiresAt time.Time) (bool, error)
+	RefreshAppLock(appID string, holder string, expiresAt time.Time) error
+	ReleaseAppLock(appID string, holder string) error
+}
+
+type UploadQuotaStore interface {
+	// GetUploadQuota returns nil if the quota was never set
+	GetUploadQuota() (*uploadquotatypes.UploadQuota, error)
+	SetUploadQuota(quota uploadquotatypes.UploadQuota) error
+}
+
+type SessionSettingsStore interface {
+	// GetSessionSettings returns nil if the settings were never set
+	GetSessionSettings() (*sessiontypes.SessionSettings, error)
+	SetSessionSettings(settings sessiontypes.SessionSettings) error
+	// InitSessionSettings sets the settings only if they have never been set
+	InitSessionSettings(settings sessiontypes.SessionSettings) error
+}
+
+type LDAPSettingsStore interface {
+	// GetLDAPSettings returns nil if the settings were never set
+	GetLDAPSettings() (*ldapauthtypes.Settings, error)
+	SetLDAPSettings(settings ldapauthtypes.Settings) error
+}
+
+type LogSettingsStore interface {
+	// GetLogSettings returns nil if the settings were never set
+	GetLogSettings() (*loggertypes.Settings, error)
+	SetLogSettings(settings loggertypes.Settings) error
+}
+
+type RestoreDrillStore interface {
+	// ListRestoreDrills returns the drills of the app, the most recently scheduled first
+	ListRestoreDrills(appID string) ([]restoredrilltypes.Drill, error)
+	CreateRestoreDrill(drill restoredrilltypes.Drill) error
+	UpdateRestoreDrill(drill restoredrilltypes.Drill) error
+	DeleteScheduledRestoreDrills(appID string) error
+}
+
+type CanaryStore interface {
+	// ListCanaryDeploys returns the canary deploys of the app, the most recently started first
+	ListCanaryDeploys(appID string) ([]canarytypes.Deploy, error)
+	CreateCanaryDeploy(deploy canarytypes.Deploy) error
+	UpdateCanaryDeploy(deploy canarytypes.Deploy) error
+}
+
+type RemoteInstallStore interface {
+	// ListRemoteInstalls returns the registered remote installations ordered by name, with their decrypted tokens
+	ListRemoteInstalls() ([]remoteinstalltypes.RemoteInstall, error)
+	GetRemoteInstall(id string) (*remoteinstalltypes.RemoteInstall, error)
+	CreateRemoteInstall(remoteInstall remoteinstalltypes.RemoteInstall) error
+	DeleteRemoteInstall(id string) error
+	// SetRemoteInstallReport stores the report fetched from the remote installation and clears the last error
+	SetRemoteInstallReport(id string, report *fleetreporttypes.Report, fetchedAt time.Time) error
+	// SetRemoteInstallError records a failed fetch, the last report is kept
+	SetRemoteInstallError(id string, message string) error
+}
+
+type ScheduledJobStore interface {
+	CreateScheduledJobRun(run scheduledjobtypes.Run) error
+	FinishScheduledJobRun(id string, status scheduledjobtypes.Status, message string, finishedAt time.Time) error
+	// ListLatestScheduledJobRuns returns the latest run of each job, per app for app specific jobs
+	ListLatestScheduledJobRuns() ([]scheduledjobtypes.Run, error)
+	// DeleteScheduledJobRunsBefore deletes the finished runs that started before the time and returns how many
+	DeleteScheduledJobRunsBefore(before time.Time) (int64, error)
+}
+
+type ArchiveChecksumStore interface {
+	SetAppVersionArchiveChecksum(appID string, sequence int64, sum string, size int64) error
+	GetAppVersionArchiveChecksum(appID string, sequence int64) (*archiveintegritytypes.Checksum, error)
+	ListAppVersionArchiveChecksumsToVerify(verifiedBefore time.Time, limit int) ([]archiveintegritytypes.Checksum, error)
+	ListCorruptAppVersionArchives(appID string) ([]archiveintegritytypes.Checksum, error)
+	SetAppVersionArchiveVerified(appID string, sequence int64, verifyError string) error
+}
+
+type DeployHistoryStore interface {
+	SetDeployEventResult(appID string, clusterID string, sequence int64, isError bool) error
+	ListDeployEvents(opts deployhistorytypes.ListOptions) ([]deployhistorytypes.Event, error)
 }
@@ -2,4 +2,9 @@ package template
 
 type ApplicationInfo struct {
 	Slug string
+	// SensitiveConfigAsSecret hides the values of sensitive config items from templates
+	SensitiveConfigAsSecret bool
+	// VaultItems are the config items whose values are read from vault at deploy time, like sensitive items
+	// their values are only available from the sensitive config Secret
+	VaultItems []string
 }
@@ -38,6 +38,7 @@ func NewBuilder(opts BuilderOptions) (Builder, map[string]ItemValue, error) {
 	if err != nil {
 		return Builder{}, nil, errors.Wrap(err, "create config context")
 	}
+	configCtx.setApplicationInfo(opts.ConfigGroups, opts.ApplicationInfo)
 
 	b.Ctx = []Ctx{
 		StaticCtx{},
@@ -12,13 +12,24 @@ import (
 	"github.com/replicatedhq/kots/pkg/crypto"
 	"github.com/replicatedhq/kots/pkg/docker/registry"
 	"github.com/replicatedhq/kots/pkg/image"
+	"github.com/replicatedhq/kots/pkg/sensitiveconfig"
 	corev1 "k8s.io/api/core/v1"
 )
 
 var (
 	dockerImageNameRegex = regexp.MustCompile("(?:([^\\/]+)\\/)?(?:([^\\/]+)\\/)?([^@:\\/]+)(?:[@:](.+))")
+
+	// configFileReader resolves values of large file config items that are stored out-of-line
+	configFileReader func(value string) ([]byte, bool)
 )
 
+// SetConfigFileReader registers the function used to read large file config items.
+// The function returns false if the value is not a reference to a stored file.
+// Without a reader, ConfigOptionData base64 decodes the value like it does for all other items.
+func SetConfigFileReader(reader func(value string) ([]byte, bool)) {
+	configFileReader = reader
+}
+
 type LocalRegistry struct {
 	Host      string
 	Namespace string
@@ -67,6 +78,35 @@ type ConfigCtx struct {
 
 	license *kotsv1beta1.License // Another agument for unifying all these contexts
 	app     *kotsv1beta1.Application
+
+	appSlug string
+	// sensitiveItems are the items whose values are only available from the sensitive config Secret
+	sensitiveItems map[string]struct{}
+}
+
+// setApplicationInfo is called after the config items have been resolved so that
+// sensitive values can still be used to build the values of other items
+func (ctx *ConfigCtx) setApplicationInfo(configGroups []kotsv1beta1.ConfigGroup, appInfo *ApplicationInfo) {
+	if appInfo == nil {
+		return
+	}
+
+	ctx.appSlug = appInfo.Slug
+	ctx.sensitiveItems = map[string]struct{}{}
+	for _, name := range appInfo.VaultItems {
+		ctx.sensitiveItems[name] = struct{}{}
+	}
+	if !appInfo.SensitiveConfigAsSecret {
+		return
+	}
+
+	for _, configGroup := range configGroups {
+		for _, configItem := range configGroup.Items {
+			if sensitiveconfig.IsSensitive(configItem) {
+				ctx.sensitiveItems[configItem.Name] = struct{}{}
+			}
+		}
+	}
 }
 
 // newConfigContext creates and returns a context for template rendering
@@ -77,23 +117,15 @@ func (b *Builder) newConfigContext(configGroups []kotsv1beta1.ConfigGroup, exist
 		license:       license,
 	}
 
-	builder := Builder{
-		Ctx: []Ctx{
-			configCtx,
-			StaticCtx{},
-			&licenseCtx{License: license},
-			newKurlContext("base", "default"),
-			newVersionCtx(info),
-		},
-	}
+	builder := newConfigItemBuilder(configCtx, license, info)
 
 	configItemsByName := make(map[string]kotsv1beta1.ConfigItem)
 	for _, configGroup := range configGroups {
 		for _, configItem := range configGroup.Items {
 			configItemsByName[configItem.Name] = configItem
 
-			// decrypt password if it exists
-			if configItem.Type == "password" {
+			// decrypt password and other sensitive values if they exist
+			if sensitiveconfig.IsEncrypted(configItem) {
 				existingVal, ok := existingValues[configItem.Name]
 				if ok && existingVal.HasValue() {
 					val, err := decrypt(existingVal.ValueStr(), cipher)
@@ -159,6 +191,8 @@ func (ctx ConfigCtx) FuncMap() template.FuncMap {
 		"ConfigOptionData":             ctx.configOptionData,
 		"ConfigOptionEquals":           ctx.configOptionEquals,
 		"ConfigOptionNotEquals":        ctx.configOptionNotEquals,
+		"ConfigOptionSecretName":       ctx.configOptionSecretName,
+		"ConfigOptionSecretKey":        ctx.configOptionSecretKey,
 		"LocalRegistryAddress":         ctx.localRegistryAddress,
 		"LocalRegistryHost":            ctx.localRegistryHost,
 		"LocalRegistryNamespace":       ctx.localRegistryNamespace,
@@ -168,6 +202,20 @@ func (ctx ConfigCtx) FuncMap() template.FuncMap {
 	}
 }
 
+// newConfigItemBuilder returns the builder for the values of config items, only the config items that the item
+// depends on must be in the config context
+func newConfigItemBuilder(configCtx *ConfigCtx, license *kotsv1beta1.License, info *VersionInfo) Builder {
+	return Builder{
+		Ctx: []Ctx{
+			configCtx,
+			StaticCtx{},
+			&licenseCtx{License: license},
+			newKurlContext("base", "default"),
+			newVersionCtx(info),
+		},
+	}
+}
+
 // isReadOnly checks to see if it should be possible to edit a field
 // for instance, it should not be possible to edit the value of a label
 func isReadOnly(item kotsv1beta1.ConfigItem) bool {
@@ -180,6 +228,7 @@ func isReadOnly(item kotsv1beta1.ConfigItem) bool {
 		"":            {},
 		"bool":        {},
 		"file":        {},
+		"large_file":  {},
 		"password":    {},
 		"select":      {},
 		"select_many": {},
@@ -192,48 +241,91 @@ func isReadOnly(item kotsv1beta1.ConfigItem) bool {
 	return !editable
 }
 
-func (ctx ConfigCtx) configOption(name string) string {
+func (ctx ConfigCtx) configOption(name string) (string, error) {
+	if err := ctx.checkNotSensitive(name); err != nil {
+		return "", err
+	}
+
 	v, err := ctx.getConfigOptionValue(name)
 	if err != nil {
-		return ""
+		return "", nil
 	}
-	return v
+	return v, nil
 }
 
 func (ctx ConfigCtx) configOptionIndex(name string) string {
 	return ""
 }
 
-func (ctx ConfigCtx) configOptionData(name string) string {
+func (ctx ConfigCtx) configOptionData(name string) (string, error) {
+	if err := ctx.checkNotSensitive(name); err != nil {
+		return "", err
+	}
+
 	v, err := ctx.getConfigOptionValue(name)
 	if err != nil {
-		return ""
+		return "", nil
+	}
+
+	if configFileReader != nil {
+		if contents, ok := configFileReader(v); ok {
+			return string(contents), nil
+		}
 	}
 
 	decoded, err := base64.StdEncoding.DecodeString(v)
 	if err != nil {
-		return ""
+		return "", nil
 	}
 
-	return string(decoded)
+	return string(decoded), nil
 }
 
-func (ctx ConfigCtx) configOptionEquals(name string, value string) bool {
+func (ctx ConfigCtx) configOptionEquals(name string, value string) (bool, error) {
+	if err := ctx.checkNotSensitive(name); err != nil {
+		return false, err
+	}
+
 	val, err := ctx.getConfigOptionValue(name)
 	if err != nil {
-		return false
+		return false, nil
 	}
 
-	return value == val
+	return value == val, nil
 }
 
-func (ctx ConfigCtx) configOptionNotEquals(name string, value string) bool {
+func (ctx ConfigCtx) configOptionNotEquals(name string, value string) (bool, error) {
+	if err := ctx.checkNotSensitive(name); err != nil {
+		return false, err
+	}
+
 	val, err := ctx.getConfigOptionValue(name)
 	if err != nil {
-		return false
+		return false, nil
+	}
+
+	return value != val, nil
+}
+
+// checkNotSensitive returns an error if the item is sensitive and sensitive values are rendered as references to
+// the secret, so that a template that would render the value fails instead of rendering an empty value
+func (ctx ConfigCtx) checkNotSensitive(name string) error {
+	if _, ok := ctx.sensitiveItems[name]; ok {
+		return errors.Errorf("config item %s is sensitive, its value must be read from the secret with ConfigOptionSecretName and ConfigOptionSecretKey", name)
 	}
+	return nil
+}
+
+func (ctx ConfigCtx) configOptionSecretName() string {
+	appSlug := ctx.appSlug
+	if appSlug == "" && ctx.license != nil {
+		appSlug = ctx.license.Spec.AppSlug
+	}
+	return sensitiveconfig.SecretName(appSlug)
+}
 
-	return value != val
+func (ctx ConfigCtx) configOptionSecretKey(name string) string {
+	return sensitiveconfig.SecretKey(name)
 }
 
 func (ctx ConfigCtx) localRegistryAddress() string {
@@ -550,3 +550,78 @@ func TestConfigCtx_localRegistryImagePullSecret(t *testing.T) {
 		})
 	}
 }
+
+func TestConfigCtx_sensitiveConfigOption(t *testing.T) {
+	req := require.New(t)
+
+	cipher, err := crypto.NewAESCipher()
+	req.NoError(err)
+
+	configGroups := []kotsv1beta1.ConfigGroup{
+		{
+			Name: "database",
+			Items: []kotsv1beta1.ConfigItem{
+				{Name: "db_host", Type: "text"},
+				{Name: "db_token", Type: "text", Sensitive: true},
+			},
+		},
+	}
+	existingValues := func() map[string]ItemValue {
+		return map[string]ItemValue{
+			"db_host":  {Value: "postgres"},
+			"db_token": {Value: base64.StdEncoding.EncodeToString(cipher.Encrypt([]byte("token")))},
+		}
+	}
+
+	// the value of a sensitive item is decrypted like a password
+	builder, _, err := NewBuilder(BuilderOptions{
+		ConfigGroups:    configGroups,
+		ExistingValues:  existingValues(),
+		Cipher:          cipher,
+		ApplicationInfo: &ApplicationInfo{Slug: "my-app"},
+	})
+	req.NoError(err)
+
+	built, err := builder.String(`{{repl ConfigOption "db_token" }}`)
+	req.NoError(err)
+	req.Equal("token", built)
+
+	// sensitive values are only available from the secret when they are rendered as references to it
+	builder, _, err = NewBuilder(BuilderOptions{
+		ConfigGroups:    configGroups,
+		ExistingValues:  existingValues(),
+		Cipher:          cipher,
+		ApplicationInfo: &ApplicationInfo{Slug: "my-app", SensitiveConfigAsSecret: true},
+	})
+	req.NoError(err)
+
+	built, err = builder.String(`{{repl ConfigOption "db_host" }}`)
+	req.NoError(err)
+	req.Equal("postgres", built)
+
+	_, err = builder.String(`{{repl ConfigOption "db_token" }}`)
+	req.Error(err)
+
+	_, err = builder.String(`{{repl if ConfigOptionEquals "db_token" "token" }}yes{{repl end }}`)
+	req.Error(err)
+
+	built, err = builder.String(`{{repl ConfigOptionSecretName }}/{{repl ConfigOptionSecretKey "db_token" }}`)
+	req.NoError(err)
+	req.Equal("my-app-sensitive-config/db_token", built)
+
+	// values read from vault are only available from the secret, even if sensitive values are not rendered as references
+	builder, _, err = NewBuilder(BuilderOptions{
+		ConfigGroups:    configGroups,
+		ExistingValues:  existingValues(),
+		Cipher:          cipher,
+		ApplicationInfo: &ApplicationInfo{Slug: "my-app", VaultItems: []string{"db_host"}},
+	})
+	req.NoError(err)
+
+	_, err = builder.String(`{{repl ConfigOption "db_host" }}`)
+	req.Error(err)
+
+	built, err = builder.String(`{{repl ConfigOption "db_token" }}`)
+	req.NoError(err)
+	req.Equal("token", built)
+}
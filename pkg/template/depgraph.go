@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"encoding/json"
 	"fmt"
+	"sort"
 	"strings"
 	"text/template"
 
@@ -194,6 +195,37 @@ func (d *depGraph) GetHeadNodes() ([]string, error) {
 	return headNodes, nil
 }
 
+// Dependents returns the nodes that depend on any of the items, directly or through other nodes, sorted by name
+func (d *depGraph) Dependents(items []string) []string {
+	dependents := map[string][]string{}
+	for node, deps := range d.Dependencies {
+		for dep := range deps {
+			dependents[dep] = append(dependents[dep], node)
+		}
+	}
+
+	found := map[string]struct{}{}
+	queue := append([]string{}, items...)
+	for len(queue) > 0 {
+		item := queue[0]
+		queue = queue[1:]
+		for _, dependent := range dependents[item] {
+			if _, ok := found[dependent]; ok {
+				continue
+			}
+			found[dependent] = struct{}{}
+			queue = append(queue, dependent)
+		}
+	}
+
+	result := make([]string, 0, len(found))
+	for dependent := range found {
+		result = append(result, dependent)
+	}
+	sort.Strings(result)
+	return result
+}
+
 func (d *depGraph) PrintData() string {
 	return fmt.Sprintf("deps: %+v", d.Dependencies)
 }
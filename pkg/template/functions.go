@@ -0,0 +1,78 @@
+package template
+
+import (
+	"text/template/parse"
+
+	"github.com/pkg/errors"
+)
+
+// ReferencedFunctions returns the names of the template functions that are called in text.
+// The text is parsed with both of the delimiters it is rendered with, text that does not parse returns an error.
+func ReferencedFunctions(text string) (map[string]struct{}, error) {
+	b := Builder{
+		Ctx: []Ctx{
+			StaticCtx{},
+			licenseCtx{},
+			kurlCtx{},
+			versionCtx{},
+			identityCtx{},
+			ConfigCtx{},
+		},
+	}
+
+	functions := map[string]struct{}{}
+	for _, d := range [][]string{{"{{repl", "}}"}, {"repl{{", "}}"}} {
+		tmpl, err := b.GetTemplate("text", text, d[0], d[1])
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to parse template")
+		}
+		for _, t := range tmpl.Templates() {
+			if t.Tree != nil {
+				addReferencedFunctions(t.Tree.Root, functions)
+			}
+		}
+	}
+
+	return functions, nil
+}
+
+func addReferencedFunctions(node parse.Node, functions map[string]struct{}) {
+	switch n := node.(type) {
+	case *parse.ListNode:
+		if n == nil {
+			return
+		}
+		for _, child := range n.Nodes {
+			addReferencedFunctions(child, functions)
+		}
+	case *parse.ActionNode:
+		addReferencedFunctions(n.Pipe, functions)
+	case *parse.PipeNode:
+		if n == nil {
+			return
+		}
+		for _, cmd := range n.Cmds {
+			addReferencedFunctions(cmd, functions)
+		}
+	case *parse.CommandNode:
+		for _, arg := range n.Args {
+			addReferencedFunctions(arg, functions)
+		}
+	case *parse.ChainNode:
+		addReferencedFunctions(n.Node, functions)
+	case *parse.IdentifierNode:
+		functions[n.Ident] = struct{}{}
+	case *parse.IfNode:
+		addReferencedFunctions(&n.BranchNode, functions)
+	case *parse.RangeNode:
+		addReferencedFunctions(&n.BranchNode, functions)
+	case *parse.WithNode:
+		addReferencedFunctions(&n.BranchNode, functions)
+	case *parse.BranchNode:
+		addReferencedFunctions(n.Pipe, functions)
+		addReferencedFunctions(n.List, functions)
+		addReferencedFunctions(n.ElseList, functions)
+	case *parse.TemplateNode:
+		addReferencedFunctions(n.Pipe, functions)
+	}
+}
@@ -0,0 +1,26 @@
+package template
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ReferencedFunctions(t *testing.T) {
+	req := require.New(t)
+
+	functions, err := ReferencedFunctions(`a: '{{repl ConfigOption "a" }}'
+b: repl{{ if eq (ConfigOption "b") "c" }}{{repl Sequence }}repl{{ else }}{{repl Now | upper }}repl{{ end }}
+c: '{{ .Values.NodeCount }}'`)
+	req.NoError(err)
+	req.Equal(map[string]struct{}{
+		"ConfigOption": {},
+		"eq":           {},
+		"Sequence":     {},
+		"Now":          {},
+		"upper":        {},
+	}, functions)
+
+	_, err = ReferencedFunctions(`a: '{{repl NotAFunction }}'`)
+	req.Error(err)
+}
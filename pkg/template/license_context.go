@@ -4,12 +4,29 @@ import (
 	"encoding/base64"
 	"encoding/json"
 	"fmt"
+	"sort"
 	"strconv"
 	"text/template"
 
 	kotsv1beta1 "github.com/replicatedhq/kots/kotskinds/apis/kots/v1beta1"
 )
 
+// licenseFields are the fields of the license spec that LicenseFieldValue returns, in addition to the entitlements.
+// The signature and the license sequence are left out, they change every time the license does.
+var licenseFields = []string{
+	"isGitOpsSupported",
+	"isIdentityServiceSupported",
+	"isGeoaxisSupported",
+	"isAirgapSupported",
+	"licenseType",
+	"appSlug",
+	"channelID",
+	"channelName",
+	"customerName",
+	"endpoint",
+	"licenseID",
+}
+
 type licenseCtx struct {
 	License *kotsv1beta1.License
 }
@@ -22,6 +39,39 @@ func (ctx licenseCtx) FuncMap() template.FuncMap {
 	}
 }
 
+// LicenseFieldValue returns the value of a license field or entitlement the same way the
+// LicenseFieldValue template function does, so it can be evaluated outside of templates.
+func LicenseFieldValue(license *kotsv1beta1.License, name string) string {
+	return licenseCtx{License: license}.licenseFieldValue(name)
+}
+
+// ChangedLicenseFields returns the sorted names of the license fields and entitlements whose LicenseFieldValue
+// differs between the licenses
+func ChangedLicenseFields(previous *kotsv1beta1.License, updated *kotsv1beta1.License) []string {
+	names := map[string]bool{}
+	for _, name := range licenseFields {
+		names[name] = true
+	}
+	for _, license := range []*kotsv1beta1.License{previous, updated} {
+		if license == nil {
+			continue
+		}
+		for name := range license.Spec.Entitlements {
+			names[name] = true
+		}
+	}
+
+	changed := []string{}
+	for name := range names {
+		if LicenseFieldValue(previous, name) != LicenseFieldValue(updated, name) {
+			changed = append(changed, name)
+		}
+	}
+	sort.Strings(changed)
+
+	return changed
+}
+
 func (ctx licenseCtx) licenseFieldValue(name string) string {
 	// return "" for a nil license - it's better than an error, which makes the template engine return "" for the full string
 	if ctx.License == nil {
@@ -255,3 +255,45 @@ func TestLicenseCtx_licenseFieldValue(t *testing.T) {
 		})
 	}
 }
+
+func TestChangedLicenseFields(t *testing.T) {
+	previous := &kotsv1beta1.License{
+		Spec: kotsv1beta1.LicenseSpec{
+			LicenseSequence: 1,
+			CustomerName:    "customer",
+			Entitlements: map[string]kotsv1beta1.EntitlementField{
+				"maxNodes": {
+					Value: kotsv1beta1.EntitlementValue{Type: kotsv1beta1.Int, IntVal: 3},
+				},
+				"tier": {
+					Value: kotsv1beta1.EntitlementValue{Type: kotsv1beta1.String, StrVal: "basic"},
+				},
+				"removed": {
+					Value: kotsv1beta1.EntitlementValue{Type: kotsv1beta1.String, StrVal: "value"},
+				},
+			},
+		},
+	}
+	updated := &kotsv1beta1.License{
+		Spec: kotsv1beta1.LicenseSpec{
+			LicenseSequence:   2,
+			CustomerName:      "customer",
+			IsGitOpsSupported: true,
+			Entitlements: map[string]kotsv1beta1.EntitlementField{
+				"maxNodes": {
+					Value: kotsv1beta1.EntitlementValue{Type: kotsv1beta1.Int, IntVal: 5},
+				},
+				"tier": {
+					Value: kotsv1beta1.EntitlementValue{Type: kotsv1beta1.String, StrVal: "basic"},
+				},
+				"added": {
+					Value: kotsv1beta1.EntitlementValue{Type: kotsv1beta1.Bool, BoolVal: true},
+				},
+			},
+		},
+	}
+
+	req := require.New(t)
+	req.Equal([]string{"added", "isGitOpsSupported", "maxNodes", "removed"}, ChangedLicenseFields(previous, updated))
+	req.Equal([]string{}, ChangedLicenseFields(updated, updated))
+}
@@ -0,0 +1,85 @@
+package template
+
+import (
+	"github.com/pkg/errors"
+	kotsv1beta1 "github.com/replicatedhq/kots/kotskinds/apis/kots/v1beta1"
+)
+
+// ConfigDependents returns the config items whose value or default is templated from any of the changed items,
+// directly or through other items, sorted by name
+func ConfigDependents(configGroups []kotsv1beta1.ConfigGroup, changedItems []string) ([]string, error) {
+	deps := depGraph{}
+	if err := deps.ParseConfigGroup(configGroups); err != nil {
+		return nil, errors.Wrap(err, "generate config groups dep graph")
+	}
+
+	return deps.Dependents(changedItems), nil
+}
+
+// RecomputeConfigValues returns the values of the config items that are derived from the changed items: the read
+// only items that depend on them, and the dependent items that have no value yet. Only these items are built, in
+// the order of their dependencies, with the existing values of all other items, so that derived values can be
+// updated without rendering the whole config.
+func RecomputeConfigValues(opts BuilderOptions, changedItems []string) (map[string]ItemValue, error) {
+	deps := depGraph{}
+	if err := deps.ParseConfigGroup(opts.ConfigGroups); err != nil {
+		return nil, errors.Wrap(err, "generate config groups dep graph")
+	}
+
+	dependents := map[string]struct{}{}
+	for _, dependent := range deps.Dependents(changedItems) {
+		dependents[dependent] = struct{}{}
+	}
+
+	configCtx := &ConfigCtx{
+		ItemValues:    map[string]ItemValue{},
+		LocalRegistry: opts.LocalRegistry,
+		license:       opts.License,
+	}
+	for name, value := range opts.ExistingValues {
+		configCtx.ItemValues[name] = value
+	}
+	builder := newConfigItemBuilder(configCtx, opts.License, opts.VersionInfo)
+
+	configItemsByName := make(map[string]kotsv1beta1.ConfigItem)
+	for _, configGroup := range opts.ConfigGroups {
+		for _, configItem := range configGroup.Items {
+			configItemsByName[configItem.Name] = configItem
+		}
+	}
+
+	recomputed := map[string]ItemValue{}
+
+	headNodes, err := deps.GetHeadNodes()
+	for len(headNodes) > 0 && err == nil {
+		for _, node := range headNodes {
+			deps.ResolveDep(node)
+
+			configItem := configItemsByName[node]
+			_, isDependent := dependents[node]
+			_, hasValue := configCtx.ItemValues[node]
+			if hasValue && !(isDependent && isReadOnly(configItem)) {
+				continue
+			}
+
+			builtDefault, _ := builder.String(configItem.Default.String())
+			builtValue, _ := builder.String(configItem.Value.String())
+			itemValue := ItemValue{
+				Value:   builtValue,
+				Default: builtDefault,
+			}
+			configCtx.ItemValues[node] = itemValue
+
+			if isDependent {
+				recomputed[node] = itemValue
+			}
+		}
+
+		headNodes, err = deps.GetHeadNodes()
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to resolve config item dependencies")
+	}
+
+	return recomputed, nil
+}
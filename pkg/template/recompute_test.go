@@ -0,0 +1,94 @@
+package template
+
+import (
+	"testing"
+
+	kotsv1beta1 "github.com/replicatedhq/kots/kotskinds/apis/kots/v1beta1"
+	"github.com/replicatedhq/kots/kotskinds/multitype"
+	"github.com/stretchr/testify/require"
+)
+
+func recomputeTestConfigGroups() []kotsv1beta1.ConfigGroup {
+	return []kotsv1beta1.ConfigGroup{
+		{
+			Name: "database",
+			Items: []kotsv1beta1.ConfigItem{
+				{
+					Name: "hostname",
+					Type: "text",
+				},
+				{
+					Name: "port",
+					Type: "text",
+				},
+				{
+					Name:     "address",
+					Type:     "text",
+					ReadOnly: true,
+					Value:    multitype.FromString(`{{repl ConfigOption "hostname"}}:{{repl ConfigOption "port"}}`),
+				},
+				{
+					Name:  "url",
+					Type:  "label",
+					Value: multitype.FromString(`postgres://{{repl ConfigOption "address"}}/app`),
+				},
+				{
+					Name:    "replica_hostname",
+					Type:    "text",
+					Default: multitype.FromString(`replica.{{repl ConfigOption "hostname"}}`),
+				},
+				{
+					Name:     "port_label",
+					Type:     "label",
+					ReadOnly: true,
+					Value:    multitype.FromString(`port {{repl ConfigOption "port"}}`),
+				},
+			},
+		},
+	}
+}
+
+func TestConfigDependents(t *testing.T) {
+	req := require.New(t)
+
+	dependents, err := ConfigDependents(recomputeTestConfigGroups(), []string{"hostname"})
+	req.NoError(err)
+	req.Equal([]string{"address", "replica_hostname", "url"}, dependents)
+
+	dependents, err = ConfigDependents(recomputeTestConfigGroups(), []string{"url"})
+	req.NoError(err)
+	req.Empty(dependents)
+}
+
+func TestRecomputeConfigValues(t *testing.T) {
+	req := require.New(t)
+
+	opts := BuilderOptions{
+		ConfigGroups: recomputeTestConfigGroups(),
+		ExistingValues: map[string]ItemValue{
+			"hostname":         {Value: "db.example.com"},
+			"port":             {Value: "5432"},
+			"address":          {Value: "old:5432"},
+			"url":              {Value: "postgres://old:5432/app"},
+			"replica_hostname": {Value: "replica.internal"},
+			"port_label":       {Value: "port 5433"},
+		},
+	}
+
+	recomputed, err := RecomputeConfigValues(opts, []string{"hostname"})
+	req.NoError(err)
+	// the editable item keeps its value, the item that does not depend on the hostname is not built
+	req.Equal(map[string]ItemValue{
+		"address": {Value: "db.example.com:5432", Default: ""},
+		"url":     {Value: "postgres://db.example.com:5432/app", Default: ""},
+	}, recomputed)
+
+	// dependents without a value are built
+	delete(opts.ExistingValues, "replica_hostname")
+	recomputed, err = RecomputeConfigValues(opts, []string{"hostname"})
+	req.NoError(err)
+	req.Equal(ItemValue{Value: "", Default: "replica.db.example.com"}, recomputed["replica_hostname"])
+
+	// the existing values are not changed
+	req.Equal(ItemValue{Value: "old:5432"}, opts.ExistingValues["address"])
+}
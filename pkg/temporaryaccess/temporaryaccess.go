@@ -0,0 +1,182 @@
+// Package temporaryaccess mints time limited, read only access links to the admin console, for an admin to hand to
+// vendor support during an incident instead of sharing the console password
+package temporaryaccess
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/replicatedhq/kots/pkg/logger"
+	"github.com/replicatedhq/kots/pkg/rbac"
+	sessiontypes "github.com/replicatedhq/kots/pkg/session/types"
+	"github.com/replicatedhq/kots/pkg/store"
+	"github.com/replicatedhq/kots/pkg/temporaryaccess/types"
+	usertypes "github.com/replicatedhq/kots/pkg/user/types"
+	"github.com/segmentio/ksuid"
+	"go.uber.org/zap"
+)
+
+const (
+	MinDuration = 5 * time.Minute
+	MaxDuration = 72 * time.Hour
+
+	// userIDPrefix identifies the sessions that were created with an access link
+	userIDPrefix = "temporary-access:"
+)
+
+var (
+	ErrInvalidToken = errors.New("the access link is invalid, has expired or was revoked")
+)
+
+type CreateOptions struct {
+	Description         string
+	AppSlug             string
+	AllowSupportBundles bool
+	Duration            time.Duration
+	CreatedBy           string
+}
+
+// Create creates a grant and returns it with the token of its access link. The token is only returned here, only
+// its sha256 is stored.
+func Create(opts CreateOptions) (*types.Grant, string, error) {
+	if err := ValidateDuration(opts.Duration); err != nil {
+		return nil, "", err
+	}
+
+	token, err := newToken()
+	if err != nil {
+		return nil, "", errors.Wrap(err, "failed to generate token")
+	}
+
+	now := time.Now()
+	grant := types.Grant{
+		ID:                  ksuid.New().String(),
+		Description:         opts.Description,
+		AppSlug:             opts.AppSlug,
+		AllowSupportBundles: opts.AllowSupportBundles,
+		CreatedBy:           opts.CreatedBy,
+		CreatedAt:           now,
+		ExpiresAt:           now.Add(opts.Duration),
+	}
+	if err := store.GetStore().CreateTemporaryAccess(grant, HashToken(token)); err != nil {
+		return nil, "", errors.Wrap(err, "failed to create temporary access")
+	}
+
+	audit("temporary access created", grant, opts.CreatedBy)
+
+	return &grant, token, nil
+}
+
+// ValidateDuration returns an error if access can't be granted for the duration
+func ValidateDuration(d time.Duration) error {
+	if d < MinDuration || d > MaxDuration {
+		return errors.Errorf("the access must expire in between %s and %s", MinDuration, MaxDuration)
+	}
+	return nil
+}
+
+// Redeem creates a session for the token of an access link. The session expires with the grant.
+func Redeem(token string) (*types.Grant, *sessiontypes.Session, error) {
+	grant, err := store.GetStore().GetTemporaryAccessByToken(HashToken(token))
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "failed to get temporary access")
+	}
+
+	now := time.Now()
+	if grant == nil || !grant.IsActive(now) {
+		return nil, nil, ErrInvalidToken
+	}
+
+	createdSession, err := store.GetStore().CreateSession(&usertypes.User{ID: UserID(*grant)}, now, grant.ExpiresAt, Roles(*grant))
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "failed to create session")
+	}
+
+	if err := store.GetStore().AddTemporaryAccessSession(grant.ID, createdSession.ID, now); err != nil {
+		// a session that can't be revoked with its grant must not be handed out
+		if err := store.GetStore().DeleteSessions([]string{createdSession.ID}); err != nil {
+			logger.Error(errors.Wrap(err, "failed to delete session"))
+		}
+		return nil, nil, errors.Wrap(err, "failed to record session")
+	}
+
+	audit("temporary access redeemed", *grant, UserID(*grant))
+
+	return grant, createdSession, nil
+}
+
+// Revoke ends a grant and the sessions that were created with its access link
+func Revoke(id string, revokedBy string) error {
+	grant, err := store.GetStore().GetTemporaryAccess(id)
+	if err != nil {
+		return errors.Wrap(err, "failed to get temporary access")
+	}
+	if grant == nil {
+		return errors.Errorf("temporary access %s not found", id)
+	}
+
+	if err := store.GetStore().RevokeTemporaryAccess(id, revokedBy, time.Now()); err != nil {
+		return errors.Wrap(err, "failed to revoke temporary access")
+	}
+
+	if len(grant.SessionIDs) > 0 {
+		if err := store.GetStore().DeleteSessions(grant.SessionIDs); err != nil {
+			return errors.Wrap(err, "failed to delete sessions")
+		}
+	}
+
+	audit("temporary access revoked", *grant, revokedBy)
+
+	return nil
+}
+
+// Roles returns the session roles of a grant, limited to its app if it has one
+func Roles(grant types.Grant) []string {
+	roleID := rbac.ReadOnlyRoleID
+	if grant.AllowSupportBundles {
+		roleID = rbac.SupportRole.ID
+	}
+	if grant.AppSlug != "" {
+		return []string{rbac.AppRoleID(roleID, grant.AppSlug)}
+	}
+	return []string{roleID}
+}
+
+// UserID returns the user of the sessions that are created with the access link of a grant
+func UserID(grant types.Grant) string {
+	return userIDPrefix + grant.ID
+}
+
+// IsTemporaryAccessSession returns true if the session was created with an access link
+func IsTemporaryAccessSession(sess *sessiontypes.Session) bool {
+	return sess != nil && strings.HasPrefix(sess.UserID, userIDPrefix)
+}
+
+// HashToken returns the hex encoded sha256 of a token, as it is stored
+func HashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+func newToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+func audit(msg string, grant types.Grant, userID string) {
+	logger.Info(msg,
+		zap.String("audit", "temporary-access"),
+		zap.String("grantID", grant.ID),
+		zap.String("appSlug", grant.AppSlug),
+		zap.Bool("allowSupportBundles", grant.AllowSupportBundles),
+		zap.Time("expiresAt", grant.ExpiresAt),
+		zap.String("user", userID))
+}
@@ -0,0 +1,77 @@
+package temporaryaccess
+
+import (
+	"testing"
+	"time"
+
+	"github.com/replicatedhq/kots/pkg/rbac"
+	sessiontypes "github.com/replicatedhq/kots/pkg/session/types"
+	"github.com/replicatedhq/kots/pkg/temporaryaccess/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Roles(t *testing.T) {
+	tests := []struct {
+		name  string
+		grant types.Grant
+		want  []string
+	}{
+		{
+			name:  "all apps",
+			grant: types.Grant{},
+			want:  []string{rbac.ReadOnlyRoleID},
+		},
+		{
+			name:  "one app",
+			grant: types.Grant{AppSlug: "my-app"},
+			want:  []string{rbac.AppRoleID(rbac.ReadOnlyRoleID, "my-app")},
+		},
+		{
+			name:  "support bundles",
+			grant: types.Grant{AppSlug: "my-app", AllowSupportBundles: true},
+			want:  []string{rbac.AppRoleID(rbac.SupportRole.ID, "my-app")},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, Roles(tt.grant))
+		})
+	}
+}
+
+func Test_ValidateDuration(t *testing.T) {
+	assert.NoError(t, ValidateDuration(time.Hour))
+	assert.NoError(t, ValidateDuration(MaxDuration))
+	assert.Error(t, ValidateDuration(time.Minute))
+	assert.Error(t, ValidateDuration(MaxDuration+time.Minute))
+}
+
+func Test_IsTemporaryAccessSession(t *testing.T) {
+	grant := types.Grant{ID: "abc"}
+	assert.True(t, IsTemporaryAccessSession(&sessiontypes.Session{UserID: UserID(grant)}))
+	assert.False(t, IsTemporaryAccessSession(&sessiontypes.Session{UserID: "kots-admin"}))
+	assert.False(t, IsTemporaryAccessSession(nil))
+}
+
+func Test_newToken(t *testing.T) {
+	req := require.New(t)
+
+	token, err := newToken()
+	req.NoError(err)
+	other, err := newToken()
+	req.NoError(err)
+
+	req.NotEqual(token, other)
+	req.Len(HashToken(token), 64)
+	req.NotEqual(HashToken(token), HashToken(other))
+}
+
+func Test_GrantIsActive(t *testing.T) {
+	now := time.Now()
+	revokedAt := now.Add(-time.Minute)
+
+	assert.True(t, types.Grant{ExpiresAt: now.Add(time.Hour)}.IsActive(now))
+	assert.False(t, types.Grant{ExpiresAt: now.Add(-time.Hour)}.IsActive(now))
+	assert.False(t, types.Grant{ExpiresAt: now.Add(time.Hour), RevokedAt: &revokedAt}.IsActive(now))
+}
@@ -0,0 +1,29 @@
+package types
+
+import (
+	"time"
+)
+
+// Grant is a time limited, read only access to the admin console that an admin hands to vendor support, instead of
+// sharing the console password. The access link can be used until the grant expires or is revoked.
+type Grant struct {
+	ID          string `json:"id"`
+	Description string `json:"description"`
+	// AppSlug limits the access to one app, all apps can be read if empty
+	AppSlug string `json:"appSlug,omitempty"`
+	// AllowSupportBundles also allows collecting support bundles and running preflight checks
+	AllowSupportBundles bool       `json:"allowSupportBundles"`
+	CreatedBy           string     `json:"createdBy"`
+	CreatedAt           time.Time  `json:"createdAt"`
+	ExpiresAt           time.Time  `json:"expiresAt"`
+	RevokedAt           *time.Time `json:"revokedAt,omitempty"`
+	RevokedBy           string     `json:"revokedBy,omitempty"`
+	LastUsedAt          *time.Time `json:"lastUsedAt,omitempty"`
+	// SessionIDs are the sessions that were created with the access link, they are deleted when the grant is revoked
+	SessionIDs []string `json:"-"`
+}
+
+// IsActive returns true if the grant has neither expired nor been revoked
+func (g Grant) IsActive(now time.Time) bool {
+	return g.RevokedAt == nil && now.Before(g.ExpiresAt)
+}
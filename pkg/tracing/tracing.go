@@ -0,0 +1,82 @@
+package tracing
+
+import (
+	"context"
+	"os"
+
+	"github.com/pkg/errors"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/trace/jaeger"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/semconv"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	tracerName = "github.com/replicatedhq/kots"
+)
+
+// Init configures the global tracer provider to export spans to the jaeger collector endpoint in
+// OTEL_EXPORTER_JAEGER_ENDPOINT, such as http://jaeger-collector:14268/api/traces. The OpenTelemetry collector
+// accepts spans on this endpoint with its jaeger receiver. When the endpoint is not set, tracing stays disabled and
+// spans are no-ops. The returned function flushes and stops the exporter.
+//
+// The otlp exporter is not used because its generated grpc code requires a newer grpc than the version kots is
+// pinned to.
+func Init(serviceName string) (func(), error) {
+	endpoint := os.Getenv("OTEL_EXPORTER_JAEGER_ENDPOINT")
+	if endpoint == "" {
+		return func() {}, nil
+	}
+
+	ctx := context.Background()
+
+	// the user and password are read from OTEL_EXPORTER_JAEGER_USER and OTEL_EXPORTER_JAEGER_PASSWORD
+	exporter, err := jaeger.NewRawExporter(jaeger.WithCollectorEndpoint(jaeger.WithEndpoint(endpoint)))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create jaeger exporter")
+	}
+
+	res, err := resource.New(ctx, resource.WithAttributes(
+		semconv.ServiceNameKey.String(serviceName),
+		semconv.ServiceVersionKey.String(os.Getenv("VERSION")),
+	))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create resource")
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+	)
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+
+	return func() {
+		provider.Shutdown(context.Background())
+	}, nil
+}
+
+// Start starts a span as a child of the span in ctx, if there is one
+func Start(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return otel.Tracer(tracerName).Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+// Detach returns a context without the deadline and cancellation of ctx that keeps its span, for work that
+// continues after the request of ctx has returned
+func Detach(ctx context.Context) context.Context {
+	return trace.ContextWithSpanContext(context.Background(), trace.SpanContextFromContext(ctx))
+}
+
+// End records err on the span, if there is one, and ends the span
+func End(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
@@ -0,0 +1,167 @@
+package updatechecker
+
+import (
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	appstatustypes "github.com/replicatedhq/kots/pkg/api/appstatus/types"
+	downstreamtypes "github.com/replicatedhq/kots/pkg/api/downstream/types"
+	apptypes "github.com/replicatedhq/kots/pkg/app/types"
+	"github.com/replicatedhq/kots/pkg/deployapproval"
+	"github.com/replicatedhq/kots/pkg/logger"
+	"github.com/replicatedhq/kots/pkg/reporting"
+	"github.com/replicatedhq/kots/pkg/store"
+	"github.com/replicatedhq/kots/pkg/version"
+	"go.uber.org/zap"
+)
+
+var (
+	sequentialDeployPollInterval = 5 * time.Second
+	sequentialDeployReadyTimeout = 30 * time.Minute
+)
+
+// pendingDeploys holds the ids of apps that are currently stepping through their pending versions
+var pendingDeploys sync.Map
+
+// deployPendingVersions deploys pending versions one at a time and waits for each one to be deployed
+// and for the app to be ready before deploying the next. the sequential policy deploys every pending version,
+// the latest policy deploys required versions and then the newest version.
+// versions downloaded while this is running are picked up as well.
+func deployPendingVersions(appID string, deployPolicy apptypes.DeployPolicy, skipPreflights bool, isCLI bool) error {
+	if _, running := pendingDeploys.LoadOrStore(appID, true); running {
+		logger.Debug("pending versions are already being deployed", zap.String("appID", appID))
+		return nil
+	}
+	defer pendingDeploys.Delete(appID)
+
+	downstreams, err := store.GetStore().ListDownstreamsForApp(appID)
+	if err != nil {
+		return errors.Wrap(err, "failed to list downstreams for app")
+	}
+	if len(downstreams) == 0 {
+		return errors.New("no downstreams found for app")
+	}
+	clusterID := downstreams[0].ClusterID
+
+	a, err := store.GetStore().GetApp(appID)
+	if err != nil {
+		return errors.Wrap(err, "failed to get app")
+	}
+
+	for {
+		pendingVersions, err := store.GetStore().GetPendingVersions(appID, clusterID)
+		if err != nil {
+			return errors.Wrap(err, "failed to get pending versions")
+		}
+		if len(pendingVersions) == 0 {
+			return nil
+		}
+
+		next := nextVersionToDeploy(pendingVersions, deployPolicy)
+
+		logger.Infof("deploying version %s (sequence %d) of %d pending versions", next.VersionLabel, next.Sequence, len(pendingVersions))
+
+		if err := version.CheckMinKotsVersion(appID, next.ParentSequence); err != nil {
+			return errors.Wrapf(err, "cannot deploy sequence %d", next.Sequence)
+		}
+
+		approval, err := deployapproval.DeployOrRequest(a, next.Sequence, deployapproval.RequestOptions{
+			RequestedBy:      deployapproval.RequestedByAutomaticDeploy,
+			IsSkipPreflights: skipPreflights,
+			IsCLI:            isCLI,
+		})
+		if err != nil {
+			return errors.Wrapf(err, "failed to deploy sequence %d", next.Sequence)
+		}
+		if approval != nil {
+			// the versions after this one are deployed by later update checks, once it has been approved and deployed
+			return nil
+		}
+
+		// preflights reporting
+		go func(sequence int64) {
+			if err := reporting.ReportAppInfo(appID, sequence, skipPreflights, isCLI); err != nil {
+				logger.Debugf("failed to update preflights reports: %v", err)
+			}
+		}(next.ParentSequence)
+
+		if err := waitForVersionReady(appID, clusterID, next.Sequence); err != nil {
+			return errors.Wrapf(err, "version %s did not become ready, not deploying later versions", next.VersionLabel)
+		}
+	}
+}
+
+// mustStepThroughPendingVersions returns true if the pending versions must be deployed one at a time
+// instead of deploying the newest version directly
+func mustStepThroughPendingVersions(appID string, deployPolicy apptypes.DeployPolicy) (bool, error) {
+	if deployPolicy == apptypes.DeployPolicySequential {
+		return true, nil
+	}
+
+	downstreams, err := store.GetStore().ListDownstreamsForApp(appID)
+	if err != nil {
+		return false, errors.Wrap(err, "failed to list downstreams for app")
+	}
+	if len(downstreams) == 0 {
+		return false, nil
+	}
+
+	pendingVersions, err := store.GetStore().GetPendingVersions(appID, downstreams[0].ClusterID)
+	if err != nil {
+		return false, errors.Wrap(err, "failed to get pending versions")
+	}
+	if len(pendingVersions) == 0 {
+		return false, nil
+	}
+
+	// pending versions are sorted newest first
+	return version.FindSkippedRequiredVersion(pendingVersions, pendingVersions[0].Sequence) != nil, nil
+}
+
+// nextVersionToDeploy picks the version to deploy from the pending versions, which are sorted newest first
+func nextVersionToDeploy(pendingVersions []downstreamtypes.DownstreamVersion, deployPolicy apptypes.DeployPolicy) downstreamtypes.DownstreamVersion {
+	if deployPolicy == apptypes.DeployPolicySequential {
+		return pendingVersions[len(pendingVersions)-1]
+	}
+
+	newest := pendingVersions[0]
+	if required := version.FindSkippedRequiredVersion(pendingVersions, newest.Sequence); required != nil {
+		return *required
+	}
+	return newest
+}
+
+// waitForVersionReady waits for the operator to report a successful deploy of the sequence
+// and for all of the app's resources to be ready
+func waitForVersionReady(appID string, clusterID string, sequence int64) error {
+	start := time.Now()
+	for {
+		status, err := store.GetStore().GetStatusForVersion(appID, clusterID, sequence)
+		if err != nil {
+			return errors.Wrap(err, "failed to get version status")
+		}
+		if status == "failed" {
+			return errors.New("deploy failed")
+		}
+
+		deployed, err := store.GetStore().IsDownstreamDeploySuccessful(appID, clusterID, sequence)
+		if err != nil {
+			return errors.Wrap(err, "failed to check if deploy was successful")
+		}
+		if deployed {
+			appStatus, err := store.GetStore().GetAppStatus(appID)
+			if err != nil {
+				return errors.Wrap(err, "failed to get app status")
+			}
+			if appStatus.Sequence == sequence && appStatus.State == appstatustypes.StateReady {
+				return nil
+			}
+		}
+
+		if time.Since(start) > sequentialDeployReadyTimeout {
+			return errors.Errorf("timed out after %s", sequentialDeployReadyTimeout)
+		}
+		time.Sleep(sequentialDeployPollInterval)
+	}
+}
@@ -0,0 +1,23 @@
+package types
+
+import (
+	"time"
+)
+
+type DownloadFailureStatus string
+
+const (
+	// DownloadFailureStatusFailed is an update that failed to download
+	DownloadFailureStatusFailed DownloadFailureStatus = "failed"
+	// DownloadFailureStatusSkipped is an update that was not downloaded because an earlier update failed
+	DownloadFailureStatusSkipped DownloadFailureStatus = "skipped"
+)
+
+// UpdateDownloadFailure is an available update that was not downloaded and has no app version
+type UpdateDownloadFailure struct {
+	UpdateCursor string                `json:"updateCursor"`
+	VersionLabel string                `json:"versionLabel"`
+	Status       DownloadFailureStatus `json:"status"`
+	Error        string                `json:"error"`
+	FailedAt     time.Time             `json:"failedAt"`
+}
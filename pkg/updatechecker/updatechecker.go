@@ -9,13 +9,22 @@ import (
 
 	"github.com/pkg/errors"
 	"github.com/replicatedhq/kots/pkg/app"
+	"github.com/replicatedhq/kots/pkg/applock"
+	"github.com/replicatedhq/kots/pkg/cronschedule"
+	"github.com/replicatedhq/kots/pkg/deployapproval"
+	"github.com/replicatedhq/kots/pkg/freeze"
 	license "github.com/replicatedhq/kots/pkg/kotsadmlicense"
 	upstream "github.com/replicatedhq/kots/pkg/kotsadmupstream"
 	"github.com/replicatedhq/kots/pkg/kotsutil"
 	"github.com/replicatedhq/kots/pkg/logger"
 	kotspull "github.com/replicatedhq/kots/pkg/pull"
 	"github.com/replicatedhq/kots/pkg/reporting"
+	"github.com/replicatedhq/kots/pkg/scheduledjob"
+	scheduledjobtypes "github.com/replicatedhq/kots/pkg/scheduledjob/types"
 	"github.com/replicatedhq/kots/pkg/store"
+	updatecheckertypes "github.com/replicatedhq/kots/pkg/updatechecker/types"
+	kotsupstream "github.com/replicatedhq/kots/pkg/upstream"
+	"github.com/replicatedhq/kots/pkg/util"
 	"github.com/replicatedhq/kots/pkg/version"
 	cron "github.com/robfig/cron/v3"
 	"go.uber.org/zap"
@@ -36,7 +45,7 @@ func Start() error {
 	}
 
 	for _, a := range appsList {
-		if a.IsAirgap {
+		if a.IsAirgap || a.IsArchived || kotsupstream.IsLocalUpstream(a.UpstreamURI) {
 			continue
 		}
 		if err := Configure(a.ID); err != nil {
@@ -51,14 +60,21 @@ func Start() error {
 // if enabled, and cron job was NOT found: add a new cron job to check app updates
 // if enabled, and a cron job was found, update the existing cron job with the latest cron spec
 // if disabled: stop the current running cron job (if exists)
-// no-op for airgap applications
+// no-op for applications installed from a local directory
+// airgap and archived applications have their cron job stopped, an airgap application converted to online mode is
+// configured again
 func Configure(appID string) error {
 	a, err := store.GetStore().GetApp(appID)
 	if err != nil {
 		return errors.Wrap(err, "failed to get app")
 	}
 
-	if a.IsAirgap {
+	if kotsupstream.IsLocalUpstream(a.UpstreamURI) {
+		return nil
+	}
+
+	if a.IsAirgap || a.IsArchived {
+		Stop(a.ID)
 		return nil
 	}
 
@@ -83,6 +99,11 @@ func Configure(appID string) error {
 		cronSpec = fmt.Sprintf("%d %d/4 * * *", m, h)
 	}
 
+	schedule, err := cronschedule.Parse(cronSpec, a.ScheduleTimezone)
+	if err != nil {
+		return errors.Wrap(err, "failed to parse cron spec")
+	}
+
 	job, ok := jobs[a.ID]
 	if ok {
 		// job already exists, remove entries
@@ -99,26 +120,30 @@ func Configure(appID string) error {
 
 	jobAppID := a.ID
 	jobAppSlug := a.Slug
-	_, err = job.AddFunc(cronSpec, func() {
+	job.Schedule(schedule, cron.FuncJob(func() {
 		logger.Debug("checking updates for app", zap.String("slug", jobAppSlug))
 
-		availableUpdates, err := CheckForUpdates(jobAppID, false, false, false)
+		err := scheduledjob.Run(scheduledjobtypes.JobUpdateCheck, jobAppID, func() (string, error) {
+			availableUpdates, err := CheckForUpdates(jobAppID, false, false, false)
+			if err != nil {
+				return "", err
+			}
+
+			if availableUpdates > 0 {
+				logger.Debug("updates found for app",
+					zap.String("slug", jobAppSlug),
+					zap.Int64("available updates", availableUpdates))
+			} else {
+				logger.Debug("no updates found for app", zap.String("slug", jobAppSlug))
+			}
+
+			return fmt.Sprintf("%d updates available", availableUpdates), nil
+		})
 		if err != nil {
 			logger.Error(errors.Wrapf(err, "failed to check updates for app %s", jobAppSlug))
 			return
 		}
-
-		if availableUpdates > 0 {
-			logger.Debug("updates found for app",
-				zap.String("slug", jobAppSlug),
-				zap.Int64("available updates", availableUpdates))
-		} else {
-			logger.Debug("no updates found for app", zap.String("slug", jobAppSlug))
-		}
-	})
-	if err != nil {
-		return errors.Wrap(err, "failed to add func")
-	}
+	}))
 
 	job.Start()
 	jobs[a.ID] = job
@@ -134,11 +159,36 @@ func Stop(appID string) {
 	}
 	if job, ok := jobs[appID]; ok {
 		job.Stop()
+		delete(jobs, appID)
 	} else {
 		logger.Debug("cron job not found for app", zap.String("appID", appID))
 	}
 }
 
+// NextRun returns the time of the next scheduled update check of the app, or nil if updates are not checked on a
+// schedule
+func NextRun(appID string) *time.Time {
+	mtx.Lock()
+	defer mtx.Unlock()
+
+	job, ok := jobs[appID]
+	if !ok {
+		return nil
+	}
+
+	entries := job.Entries()
+	if len(entries) == 0 {
+		return nil
+	}
+
+	next := entries[0].Next
+	if next.IsZero() {
+		// the job has not computed the next time yet
+		next = entries[0].Schedule.Next(time.Now())
+	}
+	return &next
+}
+
 // CheckForUpdates checks (and downloads) latest updates for a specific app
 // if "deploy" is set to true, the latest version/update will be deployed
 // returns the number of available updates
@@ -162,10 +212,15 @@ func CheckForUpdates(appID string, deploy bool, skipPreflights bool, isCLI bool)
 		return 0, errors.Wrap(err, "failed to get app")
 	}
 
+	// git and http upstreams are not licensed
+	isGenericUpstream := kotsupstream.IsGenericUpstream(a.UpstreamURI)
+
 	// sync license, this method is only called when online
-	_, _, err = license.Sync(a, "", false)
-	if err != nil {
-		return 0, errors.Wrap(err, "failed to sync license")
+	if !isGenericUpstream {
+		_, _, err = license.Sync(a, "", false)
+		if err != nil {
+			return 0, errors.Wrap(err, "failed to sync license")
+		}
 	}
 
 	// reload app because license sync could have created a new release
@@ -197,13 +252,7 @@ func CheckForUpdates(appID string, deploy bool, skipPreflights bool, isCLI bool)
 		return 0, errors.Wrap(err, "failed to load kotskinds from path")
 	}
 
-	latestLicense, err := store.GetStore().GetLatestLicenseForApp(a.ID)
-	if err != nil {
-		return 0, errors.Wrap(err, "failed to get latest license")
-	}
-
 	getUpdatesOptions := kotspull.GetUpdatesOptions{
-		License:             latestLicense,
 		CurrentCursor:       kotsKinds.Installation.Spec.UpdateCursor,
 		CurrentChannelID:    kotsKinds.Installation.Spec.ChannelID,
 		CurrentChannelName:  kotsKinds.Installation.Spec.ChannelName,
@@ -212,8 +261,18 @@ func CheckForUpdates(appID string, deploy bool, skipPreflights bool, isCLI bool)
 		ReportingInfo:       reporting.GetReportingInfo(a.ID),
 	}
 
+	upstreamURI := a.UpstreamURI
+	if !isGenericUpstream {
+		latestLicense, err := store.GetStore().GetLatestLicenseForApp(a.ID)
+		if err != nil {
+			return 0, errors.Wrap(err, "failed to get latest license")
+		}
+		getUpdatesOptions.License = latestLicense
+		upstreamURI = fmt.Sprintf("replicated://%s", kotsKinds.License.Spec.AppSlug)
+	}
+
 	// get updates
-	updates, err := kotspull.GetUpdates(fmt.Sprintf("replicated://%s", kotsKinds.License.Spec.AppSlug), getUpdatesOptions)
+	updates, err := kotspull.GetUpdates(upstreamURI, getUpdatesOptions)
 	if err != nil {
 		return 0, errors.Wrap(err, "failed to get updates")
 	}
@@ -226,10 +285,28 @@ func CheckForUpdates(appID string, deploy bool, skipPreflights bool, isCLI bool)
 
 	// if there are updates, go routine it
 	if len(updates) == 0 {
+		// the app is at the latest release, so there are no updates left to retry
+		if err := store.GetStore().ClearUpdateDownloadFailures(a.ID); err != nil {
+			return 0, errors.Wrap(err, "failed to clear update download failures")
+		}
+
 		if !deploy {
 			return 0, nil
 		}
 
+		stepThrough, err := mustStepThroughPendingVersions(a.ID, a.DeployPolicy)
+		if err != nil {
+			return 0, errors.Wrap(err, "failed to check if pending versions must be deployed in order")
+		}
+		if stepThrough {
+			go func() {
+				if err := deployPendingVersions(a.ID, a.DeployPolicy, skipPreflights, isCLI); err != nil {
+					logger.Error(errors.Wrap(err, "failed to deploy pending versions"))
+				}
+			}()
+			return 0, nil
+		}
+
 		// ensure that the latest version is deployed
 		allVersions, err := version.GetVersions(a.ID)
 		if err != nil {
@@ -254,7 +331,17 @@ func CheckForUpdates(appID string, deploy bool, skipPreflights bool, isCLI bool)
 		}
 
 		if latestVersion.Sequence != downstreamParentSequence {
-			err := version.DeployVersion(a.ID, latestVersion.Sequence)
+			if err := version.CheckMinKotsVersion(a.ID, latestVersion.Sequence); err != nil {
+				if _, ok := errors.Cause(err).(version.KotsUpgradeRequiredError); ok {
+					return 0, util.ActionableError{Message: err.Error()}
+				}
+				return 0, errors.Wrap(err, "failed to check minimum kots version")
+			}
+			_, err := deployapproval.DeployOrRequest(a, latestVersion.Sequence, deployapproval.RequestOptions{
+				RequestedBy:      deployapproval.RequestedByAutomaticDeploy,
+				IsSkipPreflights: skipPreflights,
+				IsCLI:            isCLI,
+			})
 			if err != nil {
 				return 0, errors.Wrap(err, "failed to deploy latest version")
 			}
@@ -274,30 +361,164 @@ func CheckForUpdates(appID string, deploy bool, skipPreflights bool, isCLI bool)
 	removeArchiveDir = false
 	go func() {
 		defer os.RemoveAll(archiveDir)
+		// failures from an earlier check are replaced by the result of this one
+		if err := store.GetStore().ClearUpdateDownloadFailures(a.ID); err != nil {
+			logger.Error(errors.Wrap(err, "failed to clear update download failures"))
+		}
+
+		unlock, err := applock.Lock(a.ID, applock.WaitTimeout)
+		if err != nil {
+			logger.Error(errors.Wrap(err, "failed to lock app"))
+			recordDownloadFailures(a.ID, updates, err)
+			return
+		}
+
+		// a version may have been created while waiting for the lock
+		if err := refreshArchiveDir(a.ID, a.CurrentSequence, archiveDir); err != nil {
+			unlock()
+			logger.Error(errors.Wrap(err, "failed to refresh app version archive"))
+			recordDownloadFailures(a.ID, updates, err)
+			return
+		}
+
+		var latestSequence *int64
+		downloadFailed := false
 		for index, update := range updates {
 			// the latest version is in archive dir
 			sequence, err := upstream.DownloadUpdate(a.ID, archiveDir, update.Cursor, skipPreflights)
 			if err != nil {
 				logger.Error(err)
-				continue
+				// stop here so that versions are not created out of order, the remaining updates are downloaded on retry
+				recordDownloadFailures(a.ID, updates[index:], err)
+				downloadFailed = true
+				break
 			}
-			// deploy latest version?
-			if deploy && index == len(updates)-1 {
-				err := version.DeployVersion(a.ID, sequence)
-				if err != nil {
-					logger.Error(err)
-				}
+			latestSequence = &sequence
+		}
+		unlock()
+
+		if !deploy || latestSequence == nil {
+			return
+		}
 
-				// preflights reporting
-				go func() {
-					err = reporting.ReportAppInfo(a.ID, sequence, skipPreflights, isCLI)
-					if err != nil {
-						logger.Debugf("failed to update preflights reports: %v", err)
-					}
-				}()
+		// the downloaded versions stay pending, to be deployed once the freeze has ended
+		if err := freeze.CheckDeploy(a.ID); err != nil {
+			if freeze.IsFrozen(err) {
+				logger.Infof("not deploying updates of app %s: %v", a.Slug, err)
+			} else {
+				logger.Error(errors.Wrap(err, "failed to check deploy freeze"))
 			}
+			return
 		}
+
+		stepThrough, err := mustStepThroughPendingVersions(a.ID, a.DeployPolicy)
+		if err != nil {
+			logger.Error(errors.Wrap(err, "failed to check if pending versions must be deployed in order"))
+			return
+		}
+
+		// step through the versions that were downloaded, even if a later update failed to download
+		if stepThrough {
+			if err := deployPendingVersions(a.ID, a.DeployPolicy, skipPreflights, isCLI); err != nil {
+				logger.Error(errors.Wrap(err, "failed to deploy pending versions"))
+			}
+			return
+		}
+
+		// deploy the latest version, unless a download failed and it is not the latest update
+		if downloadFailed {
+			return
+		}
+		sequence := *latestSequence
+		if err := version.CheckMinKotsVersion(a.ID, sequence); err != nil {
+			logger.Error(errors.Wrapf(err, "cannot deploy sequence %d", sequence))
+			return
+		}
+		approval, err := deployapproval.DeployOrRequest(a, sequence, deployapproval.RequestOptions{
+			RequestedBy:      deployapproval.RequestedByAutomaticDeploy,
+			IsSkipPreflights: skipPreflights,
+			IsCLI:            isCLI,
+		})
+		if err != nil {
+			logger.Error(err)
+		} else if approval != nil {
+			// preflights are reported when the approved version is deployed
+			return
+		}
+
+		// preflights reporting
+		go func() {
+			err := reporting.ReportAppInfo(a.ID, sequence, skipPreflights, isCLI)
+			if err != nil {
+				logger.Debugf("failed to update preflights reports: %v", err)
+			}
+		}()
 	}()
 
 	return availableUpdates, nil
 }
+
+// RetryFailedDownloads downloads the updates that failed or were skipped in the last update check
+// returns the number of updates that will be retried
+func RetryFailedDownloads(appID string, skipPreflights bool) (int64, error) {
+	failures, err := store.GetStore().ListUpdateDownloadFailures(appID)
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to list update download failures")
+	}
+	if len(failures) == 0 {
+		return 0, nil
+	}
+
+	// downloads stop at the first failure, so all failed updates are newer than the latest version and a new check downloads them in order
+	availableUpdates, err := CheckForUpdates(appID, false, skipPreflights, false)
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to check for updates")
+	}
+
+	return availableUpdates, nil
+}
+
+// recordDownloadFailures records the first update as failed and the rest as skipped
+// refreshArchiveDir replaces the archive in archiveDir with the archive of the current sequence of the app if a
+// version was created since the archive of sequence was downloaded
+func refreshArchiveDir(appID string, sequence int64, archiveDir string) error {
+	a, err := store.GetStore().GetApp(appID)
+	if err != nil {
+		return errors.Wrap(err, "failed to get app")
+	}
+	if a.CurrentSequence == sequence {
+		return nil
+	}
+
+	if err := os.RemoveAll(archiveDir); err != nil {
+		return errors.Wrap(err, "failed to remove archive dir")
+	}
+	if err := os.MkdirAll(archiveDir, 0755); err != nil {
+		return errors.Wrap(err, "failed to create archive dir")
+	}
+
+	if err := store.GetStore().GetAppVersionArchive(a.ID, a.CurrentSequence, archiveDir); err != nil {
+		return errors.Wrap(err, "failed to get app version archive")
+	}
+
+	return nil
+}
+
+func recordDownloadFailures(appID string, updates []kotsupstream.Update, downloadErr error) {
+	for i, update := range updates {
+		failure := updatecheckertypes.UpdateDownloadFailure{
+			UpdateCursor: update.Cursor,
+			VersionLabel: update.VersionLabel,
+			Status:       updatecheckertypes.DownloadFailureStatusFailed,
+			Error:        downloadErr.Error(),
+			FailedAt:     time.Now(),
+		}
+		if i > 0 {
+			failure.Status = updatecheckertypes.DownloadFailureStatusSkipped
+			failure.Error = fmt.Sprintf("not downloaded because version %s failed", updates[0].VersionLabel)
+		}
+		if err := store.GetStore().SetUpdateDownloadFailure(appID, failure); err != nil {
+			logger.Error(errors.Wrapf(err, "failed to record download failure for update %s", update.Cursor))
+		}
+	}
+}
@@ -17,11 +17,13 @@ import (
 	"github.com/manifoldco/promptui"
 	"github.com/pkg/errors"
 	kotsscheme "github.com/replicatedhq/kots/kotskinds/client/kotsclientset/scheme"
+	handlertypes "github.com/replicatedhq/kots/pkg/api/handlers/types"
 	"github.com/replicatedhq/kots/pkg/auth"
 	"github.com/replicatedhq/kots/pkg/docker/registry"
 	"github.com/replicatedhq/kots/pkg/k8sutil"
 	"github.com/replicatedhq/kots/pkg/logger"
 	"github.com/replicatedhq/kots/pkg/util"
+	"github.com/replicatedhq/kots/pkg/versionskew"
 	"k8s.io/client-go/kubernetes/scheme"
 )
 
@@ -35,6 +37,7 @@ type UploadOptions struct {
 	Silent          bool
 	Deploy          bool
 	SkipPreflights  bool
+	SkipValidation  bool
 	updateCursor    string
 	license         *string
 	versionLabel    string
@@ -45,26 +48,32 @@ func init() {
 }
 
 // Upload will upload the application version at path
-// using the options in uploadOptions
-func Upload(path string, uploadOptions UploadOptions) error {
+// using the options in uploadOptions. It returns the slug of the app.
+func Upload(path string, uploadOptions UploadOptions) (string, error) {
 	license, err := findLicense(path)
 	if err != nil {
-		return errors.Wrap(err, "failed to find license")
+		return "", errors.Wrap(err, "failed to find license")
 	}
 	uploadOptions.license = license
 
 	updateCursor, err := findUpdateCursor(path)
 	if err != nil {
-		return errors.Wrapf(err, "failed to find update cursor in %q. Please double check the path provided.", path)
+		return "", errors.Wrapf(err, "failed to find update cursor in %q. Please double check the path provided.", path)
 	}
 	if updateCursor == "" {
-		return errors.Errorf("no update cursor found in %q. Please double check the path provided.", path)
+		return "", errors.Errorf("no update cursor found in %q. Please double check the path provided.", path)
 	}
 	uploadOptions.updateCursor = updateCursor
 
+	if !uploadOptions.SkipValidation {
+		if err := validateUploadableArchive(path); err != nil {
+			return "", errors.Wrap(err, "failed to validate application")
+		}
+	}
+
 	archiveFilename, err := createUploadableArchive(path)
 	if err != nil {
-		return errors.Wrap(err, "failed to create uploadable archive")
+		return "", errors.Wrap(err, "failed to create uploadable archive")
 	}
 
 	defer os.Remove(archiveFilename)
@@ -86,7 +95,7 @@ func Upload(path string, uploadOptions UploadOptions) error {
 
 		appName, err := relentlesslyPromptForAppName(lastPathPart)
 		if err != nil {
-			return errors.Wrap(err, "failed to prompt for app name")
+			return "", errors.Wrap(err, "failed to prompt for app name")
 		}
 
 		uploadOptions.NewAppName = appName
@@ -96,7 +105,7 @@ func Upload(path string, uploadOptions UploadOptions) error {
 	if uploadOptions.ExistingAppSlug == "" && uploadOptions.UpstreamURI == "" {
 		upstreamURI, err := promptForUpstreamURI()
 		if err != nil {
-			return errors.Wrap(err, "failed to prompt for upstream uri")
+			return "", errors.Wrap(err, "failed to prompt for upstream uri")
 		}
 
 		uploadOptions.UpstreamURI = upstreamURI
@@ -110,28 +119,34 @@ func Upload(path string, uploadOptions UploadOptions) error {
 
 	log.ActionWithSpinner("Uploading local application to Admin Console")
 
+	client, err := versionskew.NewClient(uploadOptions.Endpoint, log)
+	if err != nil {
+		log.FinishSpinnerWithError()
+		return "", err
+	}
+
 	// upload using http to the pod directly
 	req, err := createUploadRequest(archiveFilename, uploadOptions, fmt.Sprintf("%s/api/v1/upload", uploadOptions.Endpoint))
 	if err != nil {
 		log.FinishSpinnerWithError()
-		return errors.Wrap(err, "failed to create upload request")
+		return "", errors.Wrap(err, "failed to create upload request")
 	}
-	resp, err := http.DefaultClient.Do(req)
+	resp, err := client.Do(req)
 	if err != nil {
 		log.FinishSpinnerWithError()
-		return errors.Wrap(err, "failed to execute request")
+		return "", errors.Wrap(err, "failed to execute request")
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != 200 {
 		log.FinishSpinnerWithError()
-		return errors.Errorf("unexpected status code: %d", resp.StatusCode)
+		return "", errors.Wrap(handlertypes.ErrorFromResponse(resp), "failed to upload")
 	}
 
 	b, err := ioutil.ReadAll(resp.Body)
 	if err != nil {
 		log.FinishSpinnerWithError()
-		return errors.Wrap(err, "failed to read response body")
+		return "", errors.Wrap(err, "failed to read response body")
 	}
 	type UploadResponse struct {
 		Slug string `json:"slug"`
@@ -139,12 +154,12 @@ func Upload(path string, uploadOptions UploadOptions) error {
 	var uploadResponse UploadResponse
 	if err := json.Unmarshal(b, &uploadResponse); err != nil {
 		log.FinishSpinnerWithError()
-		return errors.Wrap(err, "failed to unmarshal response")
+		return "", errors.Wrap(err, "failed to unmarshal response")
 	}
 
 	log.FinishSpinner()
 
-	return nil
+	return uploadResponse.Slug, nil
 }
 
 func createUploadRequest(path string, uploadOptions UploadOptions, uri string) (*http.Request, error) {
@@ -289,6 +304,13 @@ func promptForUpstreamURI() (string, error) {
 	supportedSchemes := map[string]interface{}{
 		"helm":       nil,
 		"replicated": nil,
+		"git":        nil,
+		"git+http":   nil,
+		"git+https":  nil,
+		"git+ssh":    nil,
+		"http":       nil,
+		"https":      nil,
+		"file":       nil,
 	}
 
 	prompt := promptui.Prompt{
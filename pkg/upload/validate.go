@@ -0,0 +1,140 @@
+package upload
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/replicatedhq/kots/pkg/kotsutil"
+	"sigs.k8s.io/kustomize/api/filesys"
+	"sigs.k8s.io/kustomize/api/krusty"
+	"sigs.k8s.io/yaml"
+)
+
+// ValidationError lists the problems that were found in an application before it was uploaded
+type ValidationError struct {
+	Problems []string
+}
+
+func (e ValidationError) Error() string {
+	return fmt.Sprintf("the application is not valid:\n  %s", strings.Join(e.Problems, "\n  "))
+}
+
+// validateUploadableArchive checks the application at rootPath before it is uploaded, so that a version that cannot
+// be deployed is not created. The kots kinds must load, the yaml files in base and overlays must parse,
+// and each downstream, or the midstream if there are no downstreams, must build with kustomize.
+func validateUploadableArchive(rootPath string) error {
+	problems := []string{}
+
+	for _, dir := range []string{"upstream", "base", filepath.Join("overlays", "midstream")} {
+		if _, err := os.Stat(filepath.Join(rootPath, dir)); err != nil {
+			problems = append(problems, fmt.Sprintf("%s: directory is missing", dir))
+		}
+	}
+	if len(problems) > 0 {
+		return ValidationError{Problems: problems}
+	}
+
+	if _, err := kotsutil.LoadKotsKindsFromPath(rootPath); err != nil {
+		problems = append(problems, fmt.Sprintf("upstream: failed to load kots kinds: %s", err.Error()))
+	}
+
+	for _, dir := range []string{"base", "overlays"} {
+		yamlProblems, err := validateYAMLFiles(rootPath, dir)
+		if err != nil {
+			return errors.Wrapf(err, "failed to validate yaml files in %s", dir)
+		}
+		problems = append(problems, yamlProblems...)
+	}
+
+	buildDirs, err := kustomizeBuildDirs(rootPath)
+	if err != nil {
+		return errors.Wrap(err, "failed to list downstreams")
+	}
+	k := krusty.MakeKustomizer(krusty.MakeDefaultOptions())
+	for _, dir := range buildDirs {
+		if _, err := k.Run(filesys.MakeFsOnDisk(), filepath.Join(rootPath, dir)); err != nil {
+			problems = append(problems, fmt.Sprintf("%s: kustomize build failed: %s", dir, err.Error()))
+		}
+	}
+
+	if len(problems) > 0 {
+		return ValidationError{Problems: problems}
+	}
+	return nil
+}
+
+// validateYAMLFiles returns a problem for each document in a yaml file in dir that does not parse,
+// or that is not a kubernetes object
+func validateYAMLFiles(rootPath string, dir string) ([]string, error) {
+	problems := []string{}
+	err := filepath.Walk(filepath.Join(rootPath, dir), func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			return nil
+		}
+		if ext := filepath.Ext(path); ext != ".yaml" && ext != ".yml" {
+			return nil
+		}
+
+		relPath, err := filepath.Rel(rootPath, path)
+		if err != nil {
+			return err
+		}
+
+		content, err := ioutil.ReadFile(path)
+		if err != nil {
+			return errors.Wrapf(err, "failed to read %s", relPath)
+		}
+
+		for i, doc := range bytes.Split(content, []byte("\n---")) {
+			if len(bytes.TrimSpace(doc)) == 0 {
+				continue
+			}
+
+			obj := map[string]interface{}{}
+			if err := yaml.Unmarshal(doc, &obj); err != nil {
+				problems = append(problems, fmt.Sprintf("%s: document %d is not valid yaml: %s", relPath, i+1, err.Error()))
+				continue
+			}
+			if len(obj) == 0 {
+				continue
+			}
+			if obj["apiVersion"] == nil || obj["kind"] == nil {
+				problems = append(problems, fmt.Sprintf("%s: document %d is missing apiVersion or kind", relPath, i+1))
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return problems, nil
+}
+
+func kustomizeBuildDirs(rootPath string) ([]string, error) {
+	downstreamsDir := filepath.Join("overlays", "downstreams")
+	entries, err := ioutil.ReadDir(filepath.Join(rootPath, downstreamsDir))
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+
+	dirs := []string{}
+	for _, entry := range entries {
+		if entry.IsDir() {
+			dirs = append(dirs, filepath.Join(downstreamsDir, entry.Name()))
+		}
+	}
+	if len(dirs) == 0 {
+		dirs = append(dirs, filepath.Join("overlays", "midstream"))
+	}
+
+	return dirs, nil
+}
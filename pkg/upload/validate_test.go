@@ -0,0 +1,76 @@
+package upload
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_validateUploadableArchive(t *testing.T) {
+	validFiles := map[string]string{
+		"upstream/deployment.yaml":                             "apiVersion: apps/v1\nkind: Deployment\nmetadata:\n  name: web\n",
+		"base/deployment.yaml":                                 "apiVersion: apps/v1\nkind: Deployment\nmetadata:\n  name: web\n",
+		"base/kustomization.yaml":                              "apiVersion: kustomize.config.k8s.io/v1beta1\nkind: Kustomization\nresources:\n- deployment.yaml\n",
+		"overlays/midstream/kustomization.yaml":                "apiVersion: kustomize.config.k8s.io/v1beta1\nkind: Kustomization\nresources:\n- ../../base\n",
+		"overlays/downstreams/this-cluster/kustomization.yaml": "apiVersion: kustomize.config.k8s.io/v1beta1\nkind: Kustomization\nresources:\n- ../../midstream\n",
+	}
+
+	tests := []struct {
+		name     string
+		override map[string]string
+		wantErr  bool
+	}{
+		{
+			name:     "valid",
+			override: map[string]string{},
+			wantErr:  false,
+		},
+		{
+			name: "invalid yaml in base",
+			override: map[string]string{
+				"base/deployment.yaml": "apiVersion: apps/v1\nkind: Deployment\n  metadata: [\n",
+			},
+			wantErr: true,
+		},
+		{
+			name: "downstream references a missing patch",
+			override: map[string]string{
+				"overlays/downstreams/this-cluster/kustomization.yaml": "apiVersion: kustomize.config.k8s.io/v1beta1\nkind: Kustomization\nresources:\n- ../../midstream\npatchesStrategicMerge:\n- missing.yaml\n",
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			req := require.New(t)
+
+			rootPath, err := ioutil.TempDir("", "kots-upload")
+			req.NoError(err)
+			defer os.RemoveAll(rootPath)
+
+			files := map[string]string{}
+			for name, content := range validFiles {
+				files[name] = content
+			}
+			for name, content := range test.override {
+				files[name] = content
+			}
+			for name, content := range files {
+				req.NoError(os.MkdirAll(filepath.Dir(filepath.Join(rootPath, name)), 0755))
+				req.NoError(ioutil.WriteFile(filepath.Join(rootPath, name), []byte(content), 0644))
+			}
+
+			err = validateUploadableArchive(rootPath)
+			if test.wantErr {
+				req.Error(err)
+				req.IsType(ValidationError{}, err)
+			} else {
+				req.NoError(err)
+			}
+		})
+	}
+}
@@ -0,0 +1,10 @@
+package types
+
+// UploadQuota limits the size of files uploaded to the admin console. A limit of 0 is unlimited.
+type UploadQuota struct {
+	AirgapBundleBytes  int64 `json:"airgapBundleBytes"`
+	LicenseBytes       int64 `json:"licenseBytes"`
+	SupportBundleBytes int64 `json:"supportBundleBytes"`
+	// DiskReserveBytes is the disk space that must remain free after a large upload is written
+	DiskReserveBytes int64 `json:"diskReserveBytes"`
+}
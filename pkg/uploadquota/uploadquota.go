@@ -0,0 +1,140 @@
+package uploadquota
+
+import (
+	"fmt"
+	"os"
+	"syscall"
+
+	"github.com/pkg/errors"
+	"github.com/replicatedhq/kots/pkg/store"
+	"github.com/replicatedhq/kots/pkg/uploadquota/types"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// the environment variables set the quota until it's set through the api. values are quantities like 20Gi.
+const (
+	airgapBundleLimitEnv  = "UPLOAD_LIMIT_AIRGAP_BUNDLE"
+	licenseLimitEnv       = "UPLOAD_LIMIT_LICENSE"
+	supportBundleLimitEnv = "UPLOAD_LIMIT_SUPPORT_BUNDLE"
+	diskReserveEnv        = "UPLOAD_DISK_RESERVE"
+)
+
+// Defaults keeps airgap bundles and support bundles unlimited, as they were before limits were configurable.
+// Licenses are a few kilobytes, anything larger is not a license.
+func Defaults() types.UploadQuota {
+	return types.UploadQuota{
+		LicenseBytes:     1024 * 1024,
+		DiskReserveBytes: 100 * 1024 * 1024,
+	}
+}
+
+// Get returns the quota set through the api, or the quota from the environment if none was set
+func Get() (types.UploadQuota, error) {
+	quota, err := store.GetStore().GetUploadQuota()
+	if err != nil {
+		return types.UploadQuota{}, errors.Wrap(err, "failed to get upload quota")
+	}
+	if quota != nil {
+		return *quota, nil
+	}
+	return FromEnv()
+}
+
+// FromEnv returns the defaults overridden by the environment variables that are set
+func FromEnv() (types.UploadQuota, error) {
+	quota := Defaults()
+
+	for env, value := range map[string]*int64{
+		airgapBundleLimitEnv:  &quota.AirgapBundleBytes,
+		licenseLimitEnv:       &quota.LicenseBytes,
+		supportBundleLimitEnv: &quota.SupportBundleBytes,
+		diskReserveEnv:        &quota.DiskReserveBytes,
+	} {
+		s := os.Getenv(env)
+		if s == "" {
+			continue
+		}
+		q, err := resource.ParseQuantity(s)
+		if err != nil {
+			return types.UploadQuota{}, errors.Wrapf(err, "failed to parse %s", env)
+		}
+		*value = q.Value()
+	}
+
+	if err := Validate(quota); err != nil {
+		return types.UploadQuota{}, errors.Wrap(err, "invalid upload quota in environment")
+	}
+
+	return quota, nil
+}
+
+// Validate returns an error if the quota has negative values
+func Validate(quota types.UploadQuota) error {
+	if quota.AirgapBundleBytes < 0 {
+		return errors.New("airgap bundle limit cannot be negative")
+	}
+	if quota.LicenseBytes < 0 {
+		return errors.New("license limit cannot be negative")
+	}
+	if quota.SupportBundleBytes < 0 {
+		return errors.New("support bundle limit cannot be negative")
+	}
+	if quota.DiskReserveBytes < 0 {
+		return errors.New("disk reserve cannot be negative")
+	}
+	return nil
+}
+
+// TooLargeError is returned when an upload is larger than its limit
+type TooLargeError struct {
+	Name  string
+	Size  int64
+	Limit int64
+}
+
+func (e TooLargeError) Error() string {
+	if e.Size <= 0 {
+		return fmt.Sprintf("%s exceeds the upload limit of %s", e.Name, formatBytes(e.Limit))
+	}
+	return fmt.Sprintf("%s of %s exceeds the upload limit of %s", e.Name, formatBytes(e.Size), formatBytes(e.Limit))
+}
+
+// CheckSize returns a TooLargeError if size is over limit. A limit of 0 is unlimited.
+func CheckSize(name string, size int64, limit int64) error {
+	if limit > 0 && size > limit {
+		return TooLargeError{Name: name, Size: size, Limit: limit}
+	}
+	return nil
+}
+
+// InsufficientDiskSpaceError is returned when an upload would not leave the reserved disk space free
+type InsufficientDiskSpaceError struct {
+	Name      string
+	Size      int64
+	Available int64
+	Reserve   int64
+}
+
+func (e InsufficientDiskSpaceError) Error() string {
+	return fmt.Sprintf("not enough disk space for %s of %s: %s is available and %s must remain free",
+		e.Name, formatBytes(e.Size), formatBytes(e.Available), formatBytes(e.Reserve))
+}
+
+// CheckDiskSpace returns an InsufficientDiskSpaceError if writing size bytes to dir would leave less than reserve
+// bytes free, so that large uploads are rejected before they fill the volume mid-transfer
+func CheckDiskSpace(name string, dir string, size int64, reserve int64) error {
+	stat := syscall.Statfs_t{}
+	if err := syscall.Statfs(dir, &stat); err != nil {
+		return errors.Wrapf(err, "failed to stat filesystem of %s", dir)
+	}
+	available := int64(stat.Bavail) * int64(stat.Bsize)
+
+	if size+reserve > available {
+		return InsufficientDiskSpaceError{Name: name, Size: size, Available: available, Reserve: reserve}
+	}
+	return nil
+}
+
+func formatBytes(b int64) string {
+	return resource.NewQuantity(b, resource.BinarySI).String()
+}
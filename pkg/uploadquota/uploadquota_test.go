@@ -0,0 +1,44 @@
+package uploadquota
+
+import (
+	"os"
+	"testing"
+
+	"github.com/replicatedhq/kots/pkg/uploadquota/types"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_FromEnv(t *testing.T) {
+	os.Setenv(airgapBundleLimitEnv, "20Gi")
+	os.Setenv(licenseLimitEnv, "")
+	defer os.Unsetenv(airgapBundleLimitEnv)
+
+	quota, err := FromEnv()
+	require.NoError(t, err)
+	require.Equal(t, types.UploadQuota{
+		AirgapBundleBytes: 20 * 1024 * 1024 * 1024,
+		LicenseBytes:      Defaults().LicenseBytes,
+		DiskReserveBytes:  Defaults().DiskReserveBytes,
+	}, quota)
+
+	os.Setenv(supportBundleLimitEnv, "lots")
+	defer os.Unsetenv(supportBundleLimitEnv)
+	_, err = FromEnv()
+	require.Error(t, err)
+}
+
+func Test_CheckSize(t *testing.T) {
+	require.NoError(t, CheckSize("license", 10, 0))
+	require.NoError(t, CheckSize("license", 10, 10))
+
+	err := CheckSize("license", 2048, 1024)
+	require.Equal(t, TooLargeError{Name: "license", Size: 2048, Limit: 1024}, err)
+	require.Equal(t, "license of 2Ki exceeds the upload limit of 1Ki", err.Error())
+}
+
+func Test_CheckDiskSpace(t *testing.T) {
+	require.NoError(t, CheckDiskSpace("airgap bundle", os.TempDir(), 1, 0))
+
+	err := CheckDiskSpace("airgap bundle", os.TempDir(), 1<<62, 0)
+	require.IsType(t, InsufficientDiskSpaceError{}, err)
+}
@@ -0,0 +1,104 @@
+package upstream
+
+import (
+	"sort"
+
+	"github.com/pkg/errors"
+	kotsv1beta1 "github.com/replicatedhq/kots/kotskinds/apis/kots/v1beta1"
+)
+
+// renderMigrationTemplate renders a template of a migration step with the config values as they are before the step
+type renderMigrationTemplate func(values map[string]kotsv1beta1.ConfigValue, tmpl string) (string, error)
+
+// migrateConfigValues applies the migrations of the config to values saved for an older schema version, in schema
+// version order. Only migrations newer than schemaVersion and not newer than the schema version of the config are
+// applied. The values are not modified, the migrated values are returned in a new map.
+func migrateConfigValues(config *kotsv1beta1.Config, values map[string]kotsv1beta1.ConfigValue, schemaVersion int, render renderMigrationTemplate) (map[string]kotsv1beta1.ConfigValue, error) {
+	migrated := map[string]kotsv1beta1.ConfigValue{}
+	for k, v := range values {
+		migrated[k] = v
+	}
+
+	migrations := []kotsv1beta1.ConfigMigration{}
+	for _, migration := range config.Spec.Migrations {
+		if migration.SchemaVersion > schemaVersion && migration.SchemaVersion <= config.Spec.SchemaVersion {
+			migrations = append(migrations, migration)
+		}
+	}
+	sort.SliceStable(migrations, func(i, j int) bool {
+		return migrations[i].SchemaVersion < migrations[j].SchemaVersion
+	})
+
+	for _, migration := range migrations {
+		for i, step := range migration.Steps {
+			if err := applyConfigMigrationStep(migrated, step, render); err != nil {
+				return nil, errors.Wrapf(err, "failed to apply step %d of migration to schema version %d", i, migration.SchemaVersion)
+			}
+		}
+	}
+
+	return migrated, nil
+}
+
+func applyConfigMigrationStep(values map[string]kotsv1beta1.ConfigValue, step kotsv1beta1.ConfigMigrationStep, render renderMigrationTemplate) error {
+	switch {
+	case step.Rename != nil:
+		if step.Rename.From == "" || step.Rename.To == "" {
+			return errors.New("rename requires from and to")
+		}
+		value, ok := values[step.Rename.From]
+		if !ok {
+			return nil
+		}
+		delete(values, step.Rename.From)
+		values[step.Rename.To] = value
+
+	case step.Split != nil:
+		if step.Split.From == "" || len(step.Split.To) == 0 {
+			return errors.New("split requires from and to")
+		}
+		if _, ok := values[step.Split.From]; !ok {
+			return nil
+		}
+		// all new values are rendered before any is set, so that each template reads the values before the split
+		newValues := map[string]string{}
+		for _, item := range step.Split.To {
+			rendered, err := render(values, item.Value)
+			if err != nil {
+				return errors.Wrapf(err, "failed to render value of %s", item.Name)
+			}
+			newValues[item.Name] = rendered
+		}
+		delete(values, step.Split.From)
+		for name, value := range newValues {
+			setMigratedValue(values, name, value)
+		}
+
+	case step.Transform != nil:
+		if step.Transform.Name == "" {
+			return errors.New("transform requires name")
+		}
+		if _, ok := values[step.Transform.Name]; !ok {
+			return nil
+		}
+		rendered, err := render(values, step.Transform.Value)
+		if err != nil {
+			return errors.Wrapf(err, "failed to render value of %s", step.Transform.Name)
+		}
+		setMigratedValue(values, step.Transform.Name, rendered)
+
+	default:
+		return errors.New("step must set one of rename, split or transform")
+	}
+
+	return nil
+}
+
+// setMigratedValue sets a value rendered by a migration. An empty value removes the item, so that its default is used.
+func setMigratedValue(values map[string]kotsv1beta1.ConfigValue, name string, value string) {
+	if value == "" {
+		delete(values, name)
+		return
+	}
+	values[name] = kotsv1beta1.ConfigValue{Value: value}
+}
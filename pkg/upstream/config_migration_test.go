@@ -0,0 +1,159 @@
+package upstream
+
+import (
+	"testing"
+
+	kotsv1beta1 "github.com/replicatedhq/kots/kotskinds/apis/kots/v1beta1"
+	"github.com/replicatedhq/kots/pkg/template"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func Test_createConfigValuesMigrations(t *testing.T) {
+	config := &kotsv1beta1.Config{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "test-app",
+		},
+		Spec: kotsv1beta1.ConfigSpec{
+			SchemaVersion: 2,
+			Groups: []kotsv1beta1.ConfigGroup{
+				{
+					Name:  "database",
+					Title: "Database",
+					Items: []kotsv1beta1.ConfigItem{
+						{Name: "hostname", Type: "text"},
+						{Name: "db_host", Type: "text"},
+						{Name: "db_port", Type: "text"},
+						{Name: "enable_tls", Type: "bool"},
+					},
+				},
+			},
+			Migrations: []kotsv1beta1.ConfigMigration{
+				{
+					SchemaVersion: 2,
+					Steps: []kotsv1beta1.ConfigMigrationStep{
+						{
+							Split: &kotsv1beta1.ConfigSplit{
+								From: "db_address",
+								To: []kotsv1beta1.ConfigSplitItem{
+									{Name: "db_host", Value: `repl{{ (split ":" (ConfigOption "db_address"))._0 }}`},
+									{Name: "db_port", Value: `repl{{ (split ":" (ConfigOption "db_address"))._1 }}`},
+								},
+							},
+						},
+						{
+							Transform: &kotsv1beta1.ConfigTransform{
+								Name:  "enable_tls",
+								Value: `repl{{ if ConfigOptionEquals "enable_tls" "yes" }}1repl{{ else }}0repl{{ end }}`,
+							},
+						},
+					},
+				},
+				{
+					SchemaVersion: 1,
+					Steps: []kotsv1beta1.ConfigMigrationStep{
+						{
+							Rename: &kotsv1beta1.ConfigRename{From: "host", To: "hostname"},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	existingValues := func(schemaVersion int) *kotsv1beta1.ConfigValues {
+		return &kotsv1beta1.ConfigValues{
+			Spec: kotsv1beta1.ConfigValuesSpec{
+				SchemaVersion: schemaVersion,
+				Values: map[string]kotsv1beta1.ConfigValue{
+					"host":       {Value: "app.example.com"},
+					"db_address": {Value: "db.example.com:5432"},
+					"enable_tls": {Value: "yes"},
+				},
+			},
+		}
+	}
+
+	tests := []struct {
+		name          string
+		schemaVersion int
+		sequence      int64
+		want          map[string]kotsv1beta1.ConfigValue
+	}{
+		{
+			name:          "values without a schema version get every migration",
+			schemaVersion: 0,
+			sequence:      1,
+			want: map[string]kotsv1beta1.ConfigValue{
+				"hostname":   {Value: "app.example.com"},
+				"db_host":    {Value: "db.example.com"},
+				"db_port":    {Value: "5432"},
+				"enable_tls": {Value: "1"},
+			},
+		},
+		{
+			name:          "migrations up to the schema version of the values are skipped",
+			schemaVersion: 1,
+			sequence:      1,
+			want: map[string]kotsv1beta1.ConfigValue{
+				"host":       {Value: "app.example.com"},
+				"hostname":   {},
+				"db_host":    {Value: "db.example.com"},
+				"db_port":    {Value: "5432"},
+				"enable_tls": {Value: "1"},
+			},
+		},
+		{
+			name:          "values of the current schema version are not migrated",
+			schemaVersion: 2,
+			sequence:      1,
+			want: map[string]kotsv1beta1.ConfigValue{
+				"host":       {Value: "app.example.com"},
+				"hostname":   {},
+				"db_address": {Value: "db.example.com:5432"},
+				"db_host":    {},
+				"db_port":    {},
+				"enable_tls": {Value: "yes"},
+			},
+		},
+		{
+			name:          "values given for the first version are not migrated",
+			schemaVersion: 0,
+			sequence:      0,
+			want: map[string]kotsv1beta1.ConfigValue{
+				"host":       {Value: "app.example.com"},
+				"hostname":   {},
+				"db_address": {Value: "db.example.com:5432"},
+				"db_host":    {},
+				"db_port":    {},
+				"enable_tls": {Value: "yes"},
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			versionInfo := &template.VersionInfo{Sequence: test.sequence}
+			values, err := createConfigValues("test-app", config, existingValues(test.schemaVersion), nil, nil, nil, versionInfo, template.LocalRegistry{}, nil)
+			require.NoError(t, err)
+			require.Equal(t, test.want, values.Spec.Values)
+			require.Equal(t, 2, values.Spec.SchemaVersion)
+		})
+	}
+}
+
+func Test_migrateConfigValuesInvalidStep(t *testing.T) {
+	config := &kotsv1beta1.Config{
+		Spec: kotsv1beta1.ConfigSpec{
+			SchemaVersion: 1,
+			Migrations: []kotsv1beta1.ConfigMigration{
+				{
+					SchemaVersion: 1,
+					Steps:         []kotsv1beta1.ConfigMigrationStep{{}},
+				},
+			},
+		},
+	}
+
+	_, err := migrateConfigValues(config, map[string]kotsv1beta1.ConfigValue{}, 0, nil)
+	require.Error(t, err)
+}
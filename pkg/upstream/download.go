@@ -0,0 +1,228 @@
+package upstream
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/replicatedhq/kots/pkg/util"
+)
+
+const (
+	// DefaultDownloadAttempts is how many times an upstream archive download is attempted before giving up
+	DefaultDownloadAttempts = 5
+)
+
+var downloadRetryBackoff = 2 * time.Second
+
+// downloadedArchive is an upstream archive that was downloaded to a temp file.
+// The caller must call Close to remove the file.
+type downloadedArchive struct {
+	File     *os.File
+	Header   http.Header
+	Checksum string // hex encoded sha256 of the archive
+}
+
+func (a *downloadedArchive) Close() error {
+	a.File.Close()
+	return os.Remove(a.File.Name())
+}
+
+// errPermanent wraps errors that will not succeed on retry
+type errPermanent struct {
+	error
+}
+
+// downloadArchive downloads the body returned by the request to a temp file. When the connection fails or the body
+// is truncated, the download is retried and resumed from where it left off using a range request.
+// The sha256 checksum of the archive is verified against the Digest header if the server sends one.
+func downloadArchive(newRequest func() (*http.Request, error), reportWriter io.Writer) (*downloadedArchive, error) {
+	if reportWriter == nil {
+		reportWriter = ioutil.Discard
+	}
+
+	f, err := ioutil.TempFile("", "kots-upstream")
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create temp file")
+	}
+	archive := &downloadedArchive{File: f}
+
+	var lastErr error
+	for attempt := 1; attempt <= DefaultDownloadAttempts; attempt++ {
+		if attempt > 1 {
+			fmt.Fprintf(reportWriter, "Retrying download (attempt %d of %d)\n", attempt, DefaultDownloadAttempts)
+			time.Sleep(downloadRetryBackoff * time.Duration(attempt-1))
+		}
+
+		lastErr = downloadArchiveAttempt(newRequest, archive)
+		if lastErr == nil {
+			if _, err := archive.File.Seek(0, io.SeekStart); err != nil {
+				archive.Close()
+				return nil, errors.Wrap(err, "failed to seek to start of archive")
+			}
+			return archive, nil
+		}
+
+		if perm, ok := lastErr.(errPermanent); ok {
+			archive.Close()
+			return nil, perm.error
+		}
+	}
+
+	archive.Close()
+	return nil, errors.Wrapf(lastErr, "failed to download after %d attempts", DefaultDownloadAttempts)
+}
+
+func downloadArchiveAttempt(newRequest func() (*http.Request, error), archive *downloadedArchive) error {
+	written, err := archive.File.Seek(0, io.SeekEnd)
+	if err != nil {
+		return errPermanent{errors.Wrap(err, "failed to seek to end of archive")}
+	}
+
+	req, err := newRequest()
+	if err != nil {
+		return errPermanent{errors.Wrap(err, "failed to create http request")}
+	}
+	resuming := written > 0 && archive.Header != nil
+	if resuming {
+		req.Header.Set("Range", fmt.Sprintf("bytes=%d-", written))
+		if etag := archive.Header.Get("ETag"); etag != "" {
+			req.Header.Set("If-Range", etag)
+		}
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return errors.Wrap(err, "failed to execute get request")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		body, _ := ioutil.ReadAll(resp.Body)
+		var statusErr error = errors.Errorf("unexpected result from get request: %d", resp.StatusCode)
+		if len(body) > 0 {
+			statusErr = util.ActionableError{Message: string(body)}
+		}
+		if resp.StatusCode >= 500 || resp.StatusCode == http.StatusTooManyRequests {
+			return statusErr
+		}
+		return errPermanent{statusErr}
+	}
+
+	totalSize := resp.ContentLength
+	if resuming && resp.StatusCode == http.StatusPartialContent {
+		start, total, err := parseContentRange(resp.Header.Get("Content-Range"))
+		if err != nil || start != written {
+			// start over if the server did not resume from where we left off
+			return resetArchive(archive, errors.New("unexpected content range in resumed download"))
+		}
+		totalSize = total
+	} else {
+		// the server sent the whole archive
+		if err := truncateArchive(archive); err != nil {
+			return errPermanent{err}
+		}
+		archive.Header = resp.Header
+		written = 0
+	}
+
+	n, err := io.Copy(archive.File, resp.Body)
+	written += n
+	if err != nil {
+		return errors.Wrap(err, "failed to read response body")
+	}
+	if totalSize >= 0 && written != totalSize {
+		return errors.Errorf("archive is truncated, received %d of %d bytes", written, totalSize)
+	}
+
+	checksum, err := fileChecksum(archive.File)
+	if err != nil {
+		return errPermanent{errors.Wrap(err, "failed to calculate checksum")}
+	}
+
+	if expected := digestChecksum(archive.Header.Get("Digest")); expected != "" && expected != checksum {
+		return resetArchive(archive, errors.Errorf("checksum mismatch, expected %s got %s", expected, checksum))
+	}
+	archive.Checksum = checksum
+
+	return nil
+}
+
+func resetArchive(archive *downloadedArchive, cause error) error {
+	if err := truncateArchive(archive); err != nil {
+		return errPermanent{err}
+	}
+	archive.Header = nil
+	return cause
+}
+
+func truncateArchive(archive *downloadedArchive) error {
+	if err := archive.File.Truncate(0); err != nil {
+		return errors.Wrap(err, "failed to truncate archive")
+	}
+	if _, err := archive.File.Seek(0, io.SeekStart); err != nil {
+		return errors.Wrap(err, "failed to seek to start of archive")
+	}
+	return nil
+}
+
+func fileChecksum(f *os.File) (string, error) {
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		return "", errors.Wrap(err, "failed to seek")
+	}
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", errors.Wrap(err, "failed to read")
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// parseContentRange parses a header like "bytes 100-199/200" and returns the start and total size.
+// The total is -1 if it is unknown.
+func parseContentRange(contentRange string) (int64, int64, error) {
+	contentRange = strings.TrimPrefix(contentRange, "bytes ")
+	parts := strings.SplitN(contentRange, "/", 2)
+	if len(parts) != 2 {
+		return 0, 0, errors.Errorf("invalid content range %q", contentRange)
+	}
+
+	start, err := strconv.ParseInt(strings.SplitN(parts[0], "-", 2)[0], 10, 64)
+	if err != nil {
+		return 0, 0, errors.Wrap(err, "failed to parse start")
+	}
+
+	if parts[1] == "*" {
+		return start, -1, nil
+	}
+	total, err := strconv.ParseInt(parts[1], 10, 64)
+	if err != nil {
+		return 0, 0, errors.Wrap(err, "failed to parse total")
+	}
+
+	return start, total, nil
+}
+
+// digestChecksum returns the hex encoded sha256 from a Digest header (RFC 3230) such as "SHA-256=<base64>"
+func digestChecksum(digest string) string {
+	for _, d := range strings.Split(digest, ",") {
+		parts := strings.SplitN(strings.TrimSpace(d), "=", 2)
+		if len(parts) != 2 || !strings.EqualFold(parts[0], "sha-256") {
+			continue
+		}
+		b, err := base64.StdEncoding.DecodeString(parts[1])
+		if err != nil {
+			return ""
+		}
+		return hex.EncodeToString(b)
+	}
+	return ""
+}
@@ -0,0 +1,105 @@
+package upstream
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_downloadArchive(t *testing.T) {
+	downloadRetryBackoff = time.Millisecond
+
+	content := bytes.Repeat([]byte("0123456789"), 1000)
+	sum := sha256.Sum256(content)
+	digest := "SHA-256=" + base64.StdEncoding.EncodeToString(sum[:])
+
+	tests := []struct {
+		name         string
+		handler      func(attempt int, w http.ResponseWriter, r *http.Request)
+		wantErr      bool
+		wantAttempts int
+	}{
+		{
+			name: "resumes a truncated download",
+			handler: func(attempt int, w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Digest", digest)
+				if attempt == 1 {
+					w.Header().Set("Content-Length", strconv.Itoa(len(content)))
+					w.Write(content[:len(content)/2])
+					return
+				}
+				http.ServeContent(w, r, "", time.Time{}, bytes.NewReader(content))
+			},
+			wantAttempts: 2,
+		},
+		{
+			name: "retries server errors",
+			handler: func(attempt int, w http.ResponseWriter, r *http.Request) {
+				if attempt < 3 {
+					w.WriteHeader(http.StatusBadGateway)
+					return
+				}
+				w.Write(content)
+			},
+			wantAttempts: 3,
+		},
+		{
+			name: "does not retry client errors",
+			handler: func(attempt int, w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusUnauthorized)
+				w.Write([]byte("license is expired"))
+			},
+			wantErr:      true,
+			wantAttempts: 1,
+		},
+		{
+			name: "fails on checksum mismatch",
+			handler: func(attempt int, w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Digest", digest)
+				corrupt := append([]byte{}, content...)
+				corrupt[len(corrupt)-1] = 'x'
+				w.Write(corrupt)
+			},
+			wantErr:      true,
+			wantAttempts: DefaultDownloadAttempts,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			req := require.New(t)
+
+			attempts := 0
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				attempts++
+				test.handler(attempts, w, r)
+			}))
+			defer server.Close()
+
+			archive, err := downloadArchive(func() (*http.Request, error) {
+				return http.NewRequest("GET", server.URL, nil)
+			}, nil)
+			req.Equal(test.wantAttempts, attempts)
+			if test.wantErr {
+				req.Error(err)
+				return
+			}
+			req.NoError(err)
+			defer archive.Close()
+
+			downloaded, err := ioutil.ReadAll(archive.File)
+			req.NoError(err)
+			req.Equal(content, downloaded)
+			req.Equal(hex.EncodeToString(sum[:]), archive.Checksum)
+		})
+	}
+}
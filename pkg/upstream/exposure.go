@@ -0,0 +1,140 @@
+package upstream
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/pkg/errors"
+	kotsv1beta1 "github.com/replicatedhq/kots/kotskinds/apis/kots/v1beta1"
+	"github.com/replicatedhq/kots/pkg/exposure"
+	"github.com/replicatedhq/kots/pkg/upstream/types"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	serializer "k8s.io/apimachinery/pkg/runtime/serializer/json"
+	"k8s.io/client-go/kubernetes/scheme"
+)
+
+const (
+	exposureConfigFilename = "kots-exposure-config.yaml"
+)
+
+func LoadExposure(upstreamDir string) (*kotsv1beta1.Exposure, error) {
+	var exposureSpec *kotsv1beta1.Exposure
+
+	err := filepath.Walk(upstreamDir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if info.IsDir() {
+			return nil
+		}
+
+		content, err := ioutil.ReadFile(path)
+		if err != nil {
+			return err
+		}
+
+		if e := contentToExposure(content); e != nil {
+			exposureSpec = e
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to walk archive dir")
+	}
+
+	return exposureSpec, nil
+}
+
+// addExposureConfigGroup adds the config group of the exposure to the config of the application, so that the
+// customer chooses how the services are exposed on the config screen. A config is added if the application does
+// not have one.
+func addExposureConfigGroup(files []types.UpstreamFile) ([]types.UpstreamFile, error) {
+	var exposureSpec *kotsv1beta1.Exposure
+	configIndex := -1
+	var config *kotsv1beta1.Config
+
+	for i, file := range files {
+		if e := contentToExposure(file.Content); e != nil {
+			exposureSpec = e
+		} else if c := contentToConfig(file.Content); c != nil {
+			configIndex = i
+			config = c
+		}
+	}
+
+	if exposureSpec == nil {
+		return files, nil
+	}
+
+	if err := exposure.Validate(exposureSpec.Spec); err != nil {
+		return nil, errors.Wrap(err, "invalid exposure")
+	}
+
+	if config == nil {
+		config = &kotsv1beta1.Config{
+			TypeMeta: metav1.TypeMeta{
+				APIVersion: "kots.io/v1beta1",
+				Kind:       "Config",
+			},
+			ObjectMeta: metav1.ObjectMeta{
+				Name: exposureSpec.Name,
+			},
+		}
+	}
+
+	groups := []kotsv1beta1.ConfigGroup{}
+	for _, group := range config.Spec.Groups {
+		if group.Name != exposure.ConfigGroupName {
+			groups = append(groups, group)
+		}
+	}
+	config.Spec.Groups = append(groups, exposure.ConfigGroup(exposureSpec.Spec))
+
+	s := serializer.NewYAMLSerializer(serializer.DefaultMetaFactory, scheme.Scheme, scheme.Scheme)
+	var b bytes.Buffer
+	if err := s.Encode(config, &b); err != nil {
+		return nil, errors.Wrap(err, "failed to encode config")
+	}
+
+	if configIndex == -1 {
+		return append(files, types.UpstreamFile{
+			Path:    exposureConfigFilename,
+			Content: b.Bytes(),
+		}), nil
+	}
+
+	files[configIndex].Content = b.Bytes()
+	return files, nil
+}
+
+func contentToExposure(content []byte) *kotsv1beta1.Exposure {
+	decode := scheme.Codecs.UniversalDeserializer().Decode
+	obj, gvk, err := decode(content, nil, nil)
+	if err != nil {
+		return nil
+	}
+
+	if gvk.Group == "kots.io" && gvk.Version == "v1beta1" && gvk.Kind == "Exposure" {
+		return obj.(*kotsv1beta1.Exposure)
+	}
+
+	return nil
+}
+
+func contentToConfig(content []byte) *kotsv1beta1.Config {
+	decode := scheme.Codecs.UniversalDeserializer().Decode
+	obj, gvk, err := decode(content, nil, nil)
+	if err != nil {
+		return nil
+	}
+
+	if gvk.Group == "kots.io" && gvk.Version == "v1beta1" && gvk.Kind == "Config" {
+		return obj.(*kotsv1beta1.Config)
+	}
+
+	return nil
+}
@@ -4,7 +4,6 @@ import (
 	"net/url"
 
 	"github.com/pkg/errors"
-	"github.com/replicatedhq/kots/pkg/crypto"
 	"github.com/replicatedhq/kots/pkg/upstream/types"
 	"github.com/replicatedhq/kots/pkg/util"
 )
@@ -23,49 +22,17 @@ func downloadUpstream(upstreamURI string, fetchOptions *types.FetchOptions) (*ty
 		return readFilesFromPath(upstreamURI)
 	}
 
-	var cipher *crypto.AESCipher
-	if fetchOptions.EncryptionKey != "" {
-		c, err := crypto.AESCipherFromString(fetchOptions.EncryptionKey)
-		if err != nil {
-			return nil, errors.Wrap(err, "failed to create cipher")
-		}
-		cipher = c
-	}
-
 	u, err := url.ParseRequestURI(upstreamURI)
 	if err != nil {
 		return nil, errors.Wrap(err, "parse request uri failed")
 	}
-	if u.Scheme == "helm" {
-		return downloadHelm(u, fetchOptions.HelmRepoURI)
-	}
-	if u.Scheme == "replicated" {
-		return downloadReplicated(
-			u,
-			fetchOptions.LocalPath,
-			fetchOptions.RootDir,
-			fetchOptions.UseAppDir,
-			fetchOptions.License,
-			fetchOptions.ConfigValues,
-			fetchOptions.IdentityConfig,
-			pickCursor(fetchOptions),
-			pickVersionLabel(fetchOptions),
-			cipher,
-			fetchOptions.AppSlug,
-			fetchOptions.AppSequence,
-			fetchOptions.Airgap != nil,
-			fetchOptions.LocalRegistry,
-			fetchOptions.ReportingInfo,
-		)
-	}
-	if u.Scheme == "git" {
-		return downloadGit(upstreamURI)
-	}
-	if u.Scheme == "http" || u.Scheme == "https" {
-		return downloadHttp(upstreamURI)
+
+	provider, err := getProvider(u)
+	if err != nil {
+		return nil, err
 	}
 
-	return nil, errors.Errorf("unknown protocol scheme %q", u.Scheme)
+	return provider.Download(u, fetchOptions)
 }
 
 func pickVersionLabel(fetchOptions *types.FetchOptions) string {
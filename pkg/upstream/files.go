@@ -1,12 +1,77 @@
 package upstream
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+
 	"github.com/pkg/errors"
 	"github.com/replicatedhq/kots/pkg/upstream/types"
 )
 
-func readFilesFromPath(filepath string) (*types.Upstream, error) {
-	return nil, errors.New("readFilesFromPath not implemented")
+// localProvider reads an app from a directory on the local filesystem, for example file:///home/dev/manifests.
+// The checksum of the files is the version, so there is a new version each time the files change.
+type localProvider struct{}
+
+func (localProvider) GetUpdates(u *url.URL, fetchOptions *types.FetchOptions) ([]Update, error) {
+	upstream, err := readFilesFromPath(u.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	if upstream.UpdateCursor == fetchOptions.CurrentCursor {
+		return []Update{}, nil
+	}
+	return []Update{{Cursor: upstream.UpdateCursor, VersionLabel: upstream.VersionLabel}}, nil
+}
+
+func (localProvider) Download(u *url.URL, fetchOptions *types.FetchOptions) (*types.Upstream, error) {
+	upstream, err := readFilesFromPath(u.Path)
+	if err != nil {
+		return nil, err
+	}
+	upstream.URI = u.RequestURI()
+	return upstream, nil
+}
+
+func readFilesFromPath(dir string) (*types.Upstream, error) {
+	info, err := os.Stat(dir)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to stat upstream path")
+	}
+	if !info.IsDir() {
+		return nil, errors.Errorf("%s is not a directory", dir)
+	}
+
+	files, err := readUpstreamFilesFromDir(dir)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read files")
+	}
+
+	checksum := filesChecksum(files)
+
+	return &types.Upstream{
+		URI:          dir,
+		Name:         filepath.Base(dir),
+		Type:         "local",
+		Files:        files,
+		UpdateCursor: checksum,
+		VersionLabel: shortChecksum(checksum),
+		Checksum:     checksum,
+	}, nil
+}
+
+// filesChecksum returns the hex encoded sha256 of the paths and contents of the files
+func filesChecksum(files []types.UpstreamFile) string {
+	h := sha256.New()
+	for _, file := range files {
+		fmt.Fprintf(h, "%s\x00%d\x00", file.Path, len(file.Content))
+		h.Write(file.Content)
+	}
+	return hex.EncodeToString(h.Sum(nil))
 }
 
 func readFilesFromURI(upstreamURI string) (*types.Upstream, error) {
@@ -1,10 +1,189 @@
 package upstream
 
 import (
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/storage/memory"
 	"github.com/pkg/errors"
 	"github.com/replicatedhq/kots/pkg/upstream/types"
+	"github.com/replicatedhq/kots/pkg/util"
 )
 
-func downloadGit(gitURI string) (*types.Upstream, error) {
-	return nil, errors.New("downloadGit not implemented")
+// gitProvider pulls an app from a git repo, where each tag that is valid semver is a version of the app.
+// The uri is the clone url, optionally with a "git+" prefix on the scheme, an "@tag" suffix to pin a version
+// and a "path" query parameter to read the app from a subdirectory of the repo, for example
+// git+https://github.com/org/repo@v1.2.0?path=manifests
+type gitProvider struct{}
+
+type gitUpstream struct {
+	CloneURL string
+	Tag      string
+	Path     string
+}
+
+func parseGitURL(u *url.URL) (*gitUpstream, error) {
+	cloneURL := *u
+	cloneURL.Scheme = strings.TrimPrefix(u.Scheme, "git+")
+	cloneURL.RawQuery = ""
+
+	tag := ""
+	if i := strings.LastIndex(u.Path, "@"); i != -1 && !strings.Contains(u.Path[i:], "/") {
+		cloneURL.Path = u.Path[:i]
+		cloneURL.RawPath = ""
+		tag = u.Path[i+1:]
+	}
+
+	subPath := path.Clean("/" + u.Query().Get("path"))
+
+	if cloneURL.Host == "" || cloneURL.Path == "" {
+		return nil, errors.Errorf("invalid git uri %q", u.String())
+	}
+
+	return &gitUpstream{
+		CloneURL: cloneURL.String(),
+		Tag:      tag,
+		Path:     strings.TrimPrefix(subPath, "/"),
+	}, nil
+}
+
+func (gitProvider) GetUpdates(u *url.URL, fetchOptions *types.FetchOptions) ([]Update, error) {
+	g, err := parseGitURL(u)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to parse git uri")
+	}
+
+	// a pinned tag never has updates
+	if g.Tag != "" {
+		return []Update{}, nil
+	}
+
+	tags, err := listGitTags(g.CloneURL)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list tags")
+	}
+
+	return semverUpdates(tags, fetchOptions.CurrentCursor), nil
+}
+
+func (gitProvider) Download(u *url.URL, fetchOptions *types.FetchOptions) (*types.Upstream, error) {
+	g, err := parseGitURL(u)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to parse git uri")
+	}
+
+	tag := g.Tag
+	if tag == "" {
+		tag = fetchOptions.CurrentCursor
+	}
+	if tag == "" {
+		tags, err := listGitTags(g.CloneURL)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to list tags")
+		}
+		tag = latestSemver(tags)
+	}
+	if tag == "" {
+		return nil, util.ActionableError{Message: "No semver tags were found in the git repo. Tag a release or pin a tag with @<tag> at the end of the uri."}
+	}
+
+	cloneDir, err := ioutil.TempDir("", "kots-git")
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create temp dir")
+	}
+	defer os.RemoveAll(cloneDir)
+
+	_, err = git.PlainClone(cloneDir, false, &git.CloneOptions{
+		URL:           g.CloneURL,
+		ReferenceName: plumbing.NewTagReferenceName(tag),
+		SingleBranch:  true,
+		Depth:         1,
+		Tags:          git.NoTags,
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to clone %s at tag %s", g.CloneURL, tag)
+	}
+
+	files, err := readUpstreamFilesFromDir(filepath.Join(cloneDir, g.Path))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read files from repo")
+	}
+
+	return &types.Upstream{
+		URI:          u.RequestURI(),
+		Name:         strings.TrimSuffix(path.Base(strings.TrimSuffix(g.CloneURL, "/")), ".git"),
+		Type:         "git",
+		Files:        files,
+		UpdateCursor: tag,
+		VersionLabel: tag,
+	}, nil
+}
+
+func listGitTags(cloneURL string) ([]string, error) {
+	remote := git.NewRemote(memory.NewStorage(), &config.RemoteConfig{
+		Name: git.DefaultRemoteName,
+		URLs: []string{cloneURL},
+	})
+
+	refs, err := remote.List(&git.ListOptions{})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to list refs in %s", cloneURL)
+	}
+
+	tags := []string{}
+	for _, ref := range refs {
+		if !ref.Name().IsTag() || strings.HasSuffix(ref.Name().String(), "^{}") {
+			continue
+		}
+		tags = append(tags, ref.Name().Short())
+	}
+
+	return tags, nil
+}
+
+// readUpstreamFilesFromDir reads all files in the directory, skipping the .git directory
+func readUpstreamFilesFromDir(dir string) ([]types.UpstreamFile, error) {
+	files := []types.UpstreamFile{}
+	err := filepath.Walk(dir, func(filePath string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			if info.Name() == ".git" {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+
+		content, err := ioutil.ReadFile(filePath)
+		if err != nil {
+			return errors.Wrapf(err, "failed to read %s", filePath)
+		}
+
+		relPath, err := filepath.Rel(dir, filePath)
+		if err != nil {
+			return errors.Wrapf(err, "failed to get relative path of %s", filePath)
+		}
+
+		files = append(files, types.UpstreamFile{
+			Path:    filepath.ToSlash(relPath),
+			Content: content,
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return files, nil
 }
@@ -1,10 +1,202 @@
 package upstream
 
 import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+
+	"github.com/ghodss/yaml"
 	"github.com/pkg/errors"
+	"github.com/replicatedhq/kots/pkg/buildversion"
 	"github.com/replicatedhq/kots/pkg/upstream/types"
 )
 
-func downloadHttp(httpURI string) (*types.Upstream, error) {
-	return nil, errors.New("downloadHttp not implemented")
+// httpProvider pulls an app from a .tar.gz archive served over http. The uri is either the url of the archive,
+// in which case the sha256 checksum of the archive is the version, or the url of a yaml or json version index
+// that lists the versions of the app, for example
+//
+//	versions:
+//	- version: 1.2.0
+//	  url: app-1.2.0.tar.gz
+//
+// Archive urls in the index are resolved relative to the index url.
+type httpProvider struct{}
+
+type httpIndex struct {
+	Versions []httpIndexVersion `json:"versions"`
+}
+
+type httpIndexVersion struct {
+	Version      string `json:"version"`
+	URL          string `json:"url"`
+	ReleaseNotes string `json:"releaseNotes,omitempty"`
+	IsRequired   bool   `json:"isRequired,omitempty"`
+}
+
+func isHTTPIndex(u *url.URL) bool {
+	switch path.Ext(u.Path) {
+	case ".yaml", ".yml", ".json":
+		return true
+	}
+	return false
+}
+
+func (httpProvider) GetUpdates(u *url.URL, fetchOptions *types.FetchOptions) ([]Update, error) {
+	if !isHTTPIndex(u) {
+		archive, err := downloadHTTPArchive(u.String(), fetchOptions)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to download archive")
+		}
+		defer archive.Close()
+
+		if archive.Checksum == fetchOptions.CurrentCursor {
+			return []Update{}, nil
+		}
+		return []Update{{Cursor: archive.Checksum, VersionLabel: shortChecksum(archive.Checksum)}}, nil
+	}
+
+	index, err := getHTTPIndex(u)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get version index")
+	}
+
+	versions := []string{}
+	required := map[string]bool{}
+	for _, v := range index.Versions {
+		versions = append(versions, v.Version)
+		required[v.Version] = v.IsRequired
+	}
+
+	updates := semverUpdates(versions, fetchOptions.CurrentCursor)
+	for i := range updates {
+		updates[i].IsRequired = required[updates[i].Cursor]
+	}
+	return updates, nil
+}
+
+func (httpProvider) Download(u *url.URL, fetchOptions *types.FetchOptions) (*types.Upstream, error) {
+	if !isHTTPIndex(u) {
+		return downloadHTTPUpstream(u, u.String(), "", "", fetchOptions)
+	}
+
+	index, err := getHTTPIndex(u)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get version index")
+	}
+
+	versions := []string{}
+	for _, v := range index.Versions {
+		versions = append(versions, v.Version)
+	}
+
+	version := fetchOptions.CurrentCursor
+	if version == "" {
+		version = latestSemver(versions)
+	}
+
+	for _, v := range index.Versions {
+		if v.Version != version {
+			continue
+		}
+
+		archiveURL, err := u.Parse(v.URL)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to parse url of version %s", v.Version)
+		}
+
+		upstream, err := downloadHTTPUpstream(u, archiveURL.String(), v.Version, v.ReleaseNotes, fetchOptions)
+		if err != nil {
+			return nil, err
+		}
+		upstream.IsRequired = v.IsRequired
+		return upstream, nil
+	}
+
+	return nil, errors.Errorf("version %q not found in index", version)
+}
+
+// downloadHTTPUpstream downloads the archive and reads the files in it. The checksum of the archive is the
+// cursor when version is empty.
+func downloadHTTPUpstream(u *url.URL, archiveURL string, version string, releaseNotes string, fetchOptions *types.FetchOptions) (*types.Upstream, error) {
+	archive, err := downloadHTTPArchive(archiveURL, fetchOptions)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to download archive")
+	}
+	defer archive.Close()
+
+	files, err := readTarGz(archive.File.Name())
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read archive")
+	}
+
+	upstream := &types.Upstream{
+		URI:          u.RequestURI(),
+		Name:         strings.TrimSuffix(path.Base(u.Path), path.Ext(u.Path)),
+		Type:         "http",
+		Files:        files,
+		UpdateCursor: version,
+		VersionLabel: version,
+		ReleaseNotes: releaseNotes,
+		Checksum:     archive.Checksum,
+	}
+	if version == "" {
+		upstream.UpdateCursor = archive.Checksum
+		upstream.VersionLabel = shortChecksum(archive.Checksum)
+	}
+
+	return upstream, nil
+}
+
+func downloadHTTPArchive(archiveURL string, fetchOptions *types.FetchOptions) (*downloadedArchive, error) {
+	return downloadArchive(func() (*http.Request, error) {
+		return newHTTPUpstreamRequest(archiveURL)
+	}, fetchOptions.ReportWriter)
+}
+
+func getHTTPIndex(u *url.URL) (*httpIndex, error) {
+	req, err := newHTTPUpstreamRequest(u.String())
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create request")
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to execute get request")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, errors.Errorf("unexpected result from get request: %d", resp.StatusCode)
+	}
+
+	b, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read response body")
+	}
+
+	index := httpIndex{}
+	if err := yaml.Unmarshal(b, &index); err != nil {
+		return nil, errors.Wrap(err, "failed to parse index")
+	}
+
+	return &index, nil
+}
+
+func newHTTPUpstreamRequest(u string) (*http.Request, error) {
+	req, err := http.NewRequest("GET", u, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to call newrequest")
+	}
+	req.Header.Add("User-Agent", fmt.Sprintf("KOTS/%s", buildversion.Version()))
+	return req, nil
+}
+
+func shortChecksum(checksum string) string {
+	if len(checksum) > 12 {
+		return checksum[:12]
+	}
+	return checksum
 }
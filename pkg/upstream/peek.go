@@ -11,6 +11,7 @@ import (
 type Update struct {
 	Cursor       string `json:"cursor"`
 	VersionLabel string `json:"versionLabel"`
+	IsRequired   bool   `json:"isRequired"`
 }
 
 func GetUpdatesUpstream(upstreamURI string, fetchOptions *types.FetchOptions) ([]Update, error) {
@@ -31,25 +32,11 @@ func getUpdatesUpstream(upstreamURI string, fetchOptions *types.FetchOptions) ([
 	if err != nil {
 		return nil, errors.Wrap(err, "parse request uri failed")
 	}
-	if u.Scheme == "helm" {
-		return getUpdatesHelm(u, fetchOptions.HelmRepoURI)
-	}
-	if u.Scheme == "replicated" {
-		currentCursor := ReplicatedCursor{
-			ChannelID:   fetchOptions.CurrentChannelID,
-			ChannelName: fetchOptions.CurrentChannelName,
-			Cursor:      fetchOptions.CurrentCursor,
-		}
-		return getUpdatesReplicated(u, fetchOptions.LocalPath, currentCursor, fetchOptions.CurrentVersionLabel, fetchOptions.License, fetchOptions.ReportingInfo)
-	}
-	if u.Scheme == "git" {
-		// return getUpdatesGit(upstreamURI)
-		// TODO
-	}
-	if u.Scheme == "http" || u.Scheme == "https" {
-		// return getUpdatesHttp(upstreamURI)
-		// TODO
+
+	provider, err := getProvider(u)
+	if err != nil {
+		return nil, err
 	}
 
-	return nil, errors.Errorf("unknown protocol scheme %q", u.Scheme)
+	return provider.GetUpdates(u, fetchOptions)
 }
@@ -0,0 +1,154 @@
+package upstream
+
+import (
+	"net/url"
+	"sort"
+
+	"github.com/Masterminds/semver"
+	"github.com/pkg/errors"
+	"github.com/replicatedhq/kots/pkg/crypto"
+	"github.com/replicatedhq/kots/pkg/upstream/types"
+)
+
+// Provider downloads an upstream and discovers the versions that are available for it
+type Provider interface {
+	// GetUpdates returns the versions that are newer than the current cursor in the fetch options, oldest first
+	GetUpdates(u *url.URL, fetchOptions *types.FetchOptions) ([]Update, error)
+	// Download returns the files of the version at the current cursor in the fetch options, or of the latest version
+	Download(u *url.URL, fetchOptions *types.FetchOptions) (*types.Upstream, error)
+}
+
+var providers = map[string]Provider{
+	"helm":       helmProvider{},
+	"replicated": replicatedProvider{},
+	"git":        gitProvider{},
+	"git+http":   gitProvider{},
+	"git+https":  gitProvider{},
+	"git+ssh":    gitProvider{},
+	"http":       httpProvider{},
+	"https":      httpProvider{},
+	"file":       localProvider{},
+}
+
+func getProvider(u *url.URL) (Provider, error) {
+	provider, ok := providers[u.Scheme]
+	if !ok {
+		return nil, errors.Errorf("unknown protocol scheme %q", u.Scheme)
+	}
+	return provider, nil
+}
+
+// IsGenericUpstream returns true if the upstream is a git repo, an http endpoint or a local directory. These apps
+// are not licensed, and updates are discovered from the tags, the version index or the files of the upstream.
+func IsGenericUpstream(upstreamURI string) bool {
+	u, err := url.ParseRequestURI(upstreamURI)
+	if err != nil {
+		return false
+	}
+	switch providers[u.Scheme].(type) {
+	case gitProvider, httpProvider, localProvider:
+		return true
+	}
+	return false
+}
+
+// IsLocalUpstream returns true if the upstream is a directory on the machine that installed the app,
+// which the admin console cannot read to check for updates
+func IsLocalUpstream(upstreamURI string) bool {
+	u, err := url.ParseRequestURI(upstreamURI)
+	if err != nil {
+		return false
+	}
+	_, ok := providers[u.Scheme].(localProvider)
+	return ok
+}
+
+type helmProvider struct{}
+
+func (helmProvider) GetUpdates(u *url.URL, fetchOptions *types.FetchOptions) ([]Update, error) {
+	return getUpdatesHelm(u, fetchOptions.HelmRepoURI)
+}
+
+func (helmProvider) Download(u *url.URL, fetchOptions *types.FetchOptions) (*types.Upstream, error) {
+	return downloadHelm(u, fetchOptions.HelmRepoURI)
+}
+
+type replicatedProvider struct{}
+
+func (replicatedProvider) GetUpdates(u *url.URL, fetchOptions *types.FetchOptions) ([]Update, error) {
+	currentCursor := ReplicatedCursor{
+		ChannelID:   fetchOptions.CurrentChannelID,
+		ChannelName: fetchOptions.CurrentChannelName,
+		Cursor:      fetchOptions.CurrentCursor,
+	}
+	return getUpdatesReplicated(u, fetchOptions.LocalPath, currentCursor, fetchOptions.CurrentVersionLabel, fetchOptions.License, fetchOptions.ReportingInfo)
+}
+
+func (replicatedProvider) Download(u *url.URL, fetchOptions *types.FetchOptions) (*types.Upstream, error) {
+	var cipher *crypto.AESCipher
+	if fetchOptions.EncryptionKey != "" {
+		c, err := crypto.AESCipherFromString(fetchOptions.EncryptionKey)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to create cipher")
+		}
+		cipher = c
+	}
+
+	return downloadReplicated(
+		u,
+		fetchOptions.LocalPath,
+		fetchOptions.RootDir,
+		fetchOptions.UseAppDir,
+		fetchOptions.License,
+		fetchOptions.ConfigValues,
+		fetchOptions.IdentityConfig,
+		pickCursor(fetchOptions),
+		pickVersionLabel(fetchOptions),
+		cipher,
+		fetchOptions.AppSlug,
+		fetchOptions.AppSequence,
+		fetchOptions.Airgap != nil,
+		fetchOptions.LocalRegistry,
+		fetchOptions.ReportingInfo,
+		fetchOptions.ReportWriter,
+	)
+}
+
+// semverUpdates returns the versions that are greater than the current version, sorted oldest first.
+// Versions that are not valid semver are ignored. All versions are returned if current is not valid semver.
+func semverUpdates(versions []string, current string) []Update {
+	currentVersion, _ := semver.NewVersion(current)
+
+	parsed := []*semver.Version{}
+	labels := map[*semver.Version]string{}
+	for _, v := range versions {
+		sv, err := semver.NewVersion(v)
+		if err != nil {
+			continue
+		}
+		if currentVersion != nil && !sv.GreaterThan(currentVersion) {
+			continue
+		}
+		parsed = append(parsed, sv)
+		labels[sv] = v
+	}
+	sort.Sort(semver.Collection(parsed))
+
+	updates := []Update{}
+	for _, sv := range parsed {
+		updates = append(updates, Update{
+			Cursor:       labels[sv],
+			VersionLabel: labels[sv],
+		})
+	}
+	return updates
+}
+
+// latestSemver returns the greatest valid semver version, or an empty string if there is none
+func latestSemver(versions []string) string {
+	updates := semverUpdates(versions, "")
+	if len(updates) == 0 {
+		return ""
+	}
+	return updates[len(updates)-1].Cursor
+}
@@ -0,0 +1,201 @@
+package upstream
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/replicatedhq/kots/pkg/upstream/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_semverUpdates(t *testing.T) {
+	tests := []struct {
+		name     string
+		versions []string
+		current  string
+		want     []string
+	}{
+		{
+			name:     "no current version",
+			versions: []string{"v1.1.0", "v1.0.0", "latest"},
+			current:  "",
+			want:     []string{"v1.0.0", "v1.1.0"},
+		},
+		{
+			name:     "newer versions only",
+			versions: []string{"1.0.0", "1.2.0", "1.10.0", "0.9.0"},
+			current:  "1.0.0",
+			want:     []string{"1.2.0", "1.10.0"},
+		},
+		{
+			name:     "up to date",
+			versions: []string{"1.0.0"},
+			current:  "1.0.0",
+			want:     []string{},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := []string{}
+			for _, update := range semverUpdates(test.versions, test.current) {
+				got = append(got, update.Cursor)
+			}
+			assert.Equal(t, test.want, got)
+		})
+	}
+}
+
+func Test_parseGitURL(t *testing.T) {
+	tests := []struct {
+		uri  string
+		want gitUpstream
+	}{
+		{
+			uri:  "git+https://github.com/org/repo",
+			want: gitUpstream{CloneURL: "https://github.com/org/repo"},
+		},
+		{
+			uri:  "git+https://github.com/org/repo.git@v1.2.0?path=deploy/manifests",
+			want: gitUpstream{CloneURL: "https://github.com/org/repo.git", Tag: "v1.2.0", Path: "deploy/manifests"},
+		},
+		{
+			uri:  "git+ssh://git@github.com/org/repo@v1.2.0",
+			want: gitUpstream{CloneURL: "ssh://git@github.com/org/repo", Tag: "v1.2.0"},
+		},
+		{
+			uri:  "git://example.com/repo?path=../../etc",
+			want: gitUpstream{CloneURL: "git://example.com/repo", Path: "etc"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.uri, func(t *testing.T) {
+			u, err := url.ParseRequestURI(test.uri)
+			require.NoError(t, err)
+
+			got, err := parseGitURL(u)
+			require.NoError(t, err)
+			assert.Equal(t, test.want, *got)
+		})
+	}
+}
+
+func Test_httpProvider(t *testing.T) {
+	req := require.New(t)
+
+	archives := map[string][]byte{
+		"/app-1.0.0.tar.gz": testTarGz(t, map[string]string{"app/deployment.yaml": "version: 1.0.0"}),
+		"/app-1.1.0.tar.gz": testTarGz(t, map[string]string{"app/deployment.yaml": "version: 1.1.0"}),
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/index.yaml" {
+			fmt.Fprint(w, `versions:
+- version: 1.0.0
+  url: app-1.0.0.tar.gz
+- version: 1.1.0
+  url: app-1.1.0.tar.gz
+  releaseNotes: fixes
+  isRequired: true
+`)
+			return
+		}
+		b, ok := archives[r.URL.Path]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Write(b)
+	}))
+	defer server.Close()
+
+	index, err := url.ParseRequestURI(server.URL + "/index.yaml")
+	req.NoError(err)
+
+	updates, err := httpProvider{}.GetUpdates(index, &types.FetchOptions{CurrentCursor: "1.0.0"})
+	req.NoError(err)
+	req.Equal([]Update{{Cursor: "1.1.0", VersionLabel: "1.1.0", IsRequired: true}}, updates)
+
+	upstream, err := httpProvider{}.Download(index, &types.FetchOptions{})
+	req.NoError(err)
+	req.Equal("1.1.0", upstream.UpdateCursor)
+	req.Equal("fixes", upstream.ReleaseNotes)
+	req.Equal([]types.UpstreamFile{{Path: "deployment.yaml", Content: []byte("version: 1.1.0")}}, upstream.Files)
+
+	upstream, err = httpProvider{}.Download(index, &types.FetchOptions{CurrentCursor: "1.0.0"})
+	req.NoError(err)
+	req.Equal("1.0.0", upstream.UpdateCursor)
+
+	archive, err := url.ParseRequestURI(server.URL + "/app-1.0.0.tar.gz")
+	req.NoError(err)
+
+	upstream, err = httpProvider{}.Download(archive, &types.FetchOptions{})
+	req.NoError(err)
+	req.Equal(upstream.Checksum, upstream.UpdateCursor)
+
+	updates, err = httpProvider{}.GetUpdates(archive, &types.FetchOptions{CurrentCursor: upstream.Checksum})
+	req.NoError(err)
+	req.Empty(updates)
+}
+
+func testTarGz(t *testing.T, files map[string]string) []byte {
+	var b bytes.Buffer
+	gzw := gzip.NewWriter(&b)
+	tw := tar.NewWriter(gzw)
+	for name, content := range files {
+		err := tw.WriteHeader(&tar.Header{
+			Name:     name,
+			Mode:     0644,
+			Size:     int64(len(content)),
+			Typeflag: tar.TypeReg,
+		})
+		require.NoError(t, err)
+		_, err = tw.Write([]byte(content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, tw.Close())
+	require.NoError(t, gzw.Close())
+	return b.Bytes()
+}
+
+func Test_localProvider(t *testing.T) {
+	req := require.New(t)
+
+	dir, err := ioutil.TempDir("", "kots-local")
+	req.NoError(err)
+	defer os.RemoveAll(dir)
+
+	req.NoError(os.MkdirAll(filepath.Join(dir, "manifests"), 0755))
+	req.NoError(os.MkdirAll(filepath.Join(dir, ".git"), 0755))
+	req.NoError(ioutil.WriteFile(filepath.Join(dir, "manifests", "deployment.yaml"), []byte("replicas: 1"), 0644))
+	req.NoError(ioutil.WriteFile(filepath.Join(dir, ".git", "HEAD"), []byte("ref: refs/heads/main"), 0644))
+
+	u, err := url.ParseRequestURI("file://" + filepath.ToSlash(dir))
+	req.NoError(err)
+
+	upstream, err := localProvider{}.Download(u, &types.FetchOptions{})
+	req.NoError(err)
+	req.Equal([]types.UpstreamFile{{Path: "manifests/deployment.yaml", Content: []byte("replicas: 1")}}, upstream.Files)
+
+	updates, err := localProvider{}.GetUpdates(u, &types.FetchOptions{CurrentCursor: upstream.UpdateCursor})
+	req.NoError(err)
+	req.Empty(updates)
+
+	req.NoError(ioutil.WriteFile(filepath.Join(dir, "manifests", "deployment.yaml"), []byte("replicas: 2"), 0644))
+
+	updates, err = localProvider{}.GetUpdates(u, &types.FetchOptions{CurrentCursor: upstream.UpdateCursor})
+	req.NoError(err)
+	req.Len(updates, 1)
+	req.NotEqual(upstream.UpdateCursor, updates[0].Cursor)
+}
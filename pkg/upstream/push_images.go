@@ -1,12 +1,14 @@
 package upstream
 
 import (
+	"context"
 	"io"
 	"time"
 
 	"github.com/pkg/errors"
 	"github.com/replicatedhq/kots/pkg/docker/registry"
 	"github.com/replicatedhq/kots/pkg/image"
+	"github.com/replicatedhq/kots/pkg/imagepushjob"
 	"github.com/replicatedhq/kots/pkg/kotsadm"
 	kotsadmtypes "github.com/replicatedhq/kots/pkg/kotsadm/types"
 	"github.com/replicatedhq/kots/pkg/logger"
@@ -26,6 +28,7 @@ type ProcessUpstreamImagesOptions struct {
 	ReplicatedRegistry  registry.RegistryOptions
 	ReportWriter        io.Writer
 	DestinationRegistry registry.RegistryOptions
+	BandwidthLimit      int64
 }
 
 func ProcessUpstreamImages(u *types.Upstream, options ProcessUpstreamImagesOptions) ([]kustomizetypes.Image, error) {
@@ -34,6 +37,7 @@ func ProcessUpstreamImages(u *types.Upstream, options ProcessUpstreamImagesOptio
 		Log:            options.Log,
 		ProgressWriter: options.ReportWriter,
 		LogForUI:       true,
+		BandwidthLimit: options.BandwidthLimit,
 	}
 
 	var foundImages []kustomizetypes.Image
@@ -51,6 +55,17 @@ func ProcessUpstreamImages(u *types.Upstream, options ProcessUpstreamImagesOptio
 				// TODO: Implement GetImagesFromPath
 				return nil, errors.New("GetImagesFromPath is not implemented")
 			}
+		} else if workers := imagepushjob.Workers(); workers > 1 {
+			images, err := imagepushjob.Push(context.TODO(), imagepushjob.PushOptions{
+				ImagesDir:    options.ImagesDir,
+				AirgapBundle: options.AirgapBundle,
+				Workers:      workers,
+				PushOptions:  pushOpts,
+			})
+			if err != nil {
+				return nil, errors.Wrap(err, "failed to push images with jobs")
+			}
+			foundImages = images
 		} else {
 			if options.AirgapBundle != "" {
 				images, err := kotsadm.TagAndPushAppImagesFromBundle(options.AirgapBundle, pushOpts)
@@ -23,6 +23,7 @@ import (
 	reportingtypes "github.com/replicatedhq/kots/pkg/api/reporting/types"
 	"github.com/replicatedhq/kots/pkg/buildversion"
 	"github.com/replicatedhq/kots/pkg/crypto"
+	"github.com/replicatedhq/kots/pkg/endpoints"
 	kotslicense "github.com/replicatedhq/kots/pkg/license"
 	reporting "github.com/replicatedhq/kots/pkg/reporting"
 	"github.com/replicatedhq/kots/pkg/template"
@@ -65,6 +66,8 @@ type Release struct {
 	VersionLabel string
 	ReleaseNotes string
 	ReleasedAt   *time.Time
+	Checksum     string // sha256 of the downloaded archive, empty if read from a local path
+	IsRequired   bool
 	Manifests    map[string][]byte
 }
 
@@ -72,6 +75,7 @@ type ChannelRelease struct {
 	ChannelSequence int    `json:"channelSequence"`
 	ReleaseSequence int    `json:"releaseSequence"`
 	VersionLabel    string `json:"versionLabel"`
+	IsRequired      bool   `json:"isRequired"`
 }
 
 func (this ReplicatedCursor) Equal(other ReplicatedCursor) bool {
@@ -115,6 +119,7 @@ func getUpdatesReplicated(u *url.URL, localPath string, currentCursor Replicated
 		updates = append(updates, Update{
 			Cursor:       strconv.Itoa(pendingRelease.ChannelSequence),
 			VersionLabel: pendingRelease.VersionLabel,
+			IsRequired:   pendingRelease.IsRequired,
 		})
 	}
 	return updates, nil
@@ -136,6 +141,7 @@ func downloadReplicated(
 	isAirgap bool,
 	registry types.LocalRegistry,
 	reportingInfo *reportingtypes.ReportingInfo,
+	reportWriter io.Writer,
 ) (*types.Upstream, error) {
 	var release *Release
 
@@ -161,7 +167,7 @@ func downloadReplicated(
 			return nil, errors.Wrap(err, "failed to get successful head response")
 		}
 
-		downloadedRelease, err := downloadReplicatedApp(replicatedUpstream, license, updateCursor, reportingInfo)
+		downloadedRelease, err := downloadReplicatedApp(replicatedUpstream, license, updateCursor, reportingInfo, reportWriter)
 		if err != nil {
 			return nil, errors.Wrap(err, "failed to download replicated app")
 		}
@@ -279,6 +285,8 @@ func downloadReplicated(
 		VersionLabel:  release.VersionLabel,
 		ReleaseNotes:  release.ReleaseNotes,
 		ReleasedAt:    release.ReleasedAt,
+		Checksum:      release.Checksum,
+		IsRequired:    release.IsRequired,
 		EncryptionKey: cipher.ToString(),
 	}
 
@@ -286,9 +294,15 @@ func downloadReplicated(
 }
 
 func (r *ReplicatedUpstream) getRequest(method string, license *kotsv1beta1.License, cursor ReplicatedCursor) (*http.Request, error) {
-	u, err := url.Parse(license.Spec.Endpoint)
+	return r.getRequestForEndpoint(method, license.Spec.Endpoint, license, cursor)
+}
+
+// getRequestForEndpoint returns the request for the release of the license from the endpoint, which is the endpoint
+// of the license or one of its mirrors
+func (r *ReplicatedUpstream) getRequestForEndpoint(method string, endpoint string, license *kotsv1beta1.License, cursor ReplicatedCursor) (*http.Request, error) {
+	u, err := url.Parse(endpoint)
 	if err != nil {
-		return nil, errors.Wrap(err, "failed to parse endpoint from license")
+		return nil, errors.Wrap(err, "failed to parse endpoint")
 	}
 
 	hostname := u.Hostname()
@@ -340,11 +354,9 @@ func parseReplicatedURL(u *url.URL) (*ReplicatedUpstream, error) {
 }
 
 func getSuccessfulHeadResponse(replicatedUpstream *ReplicatedUpstream, license *kotsv1beta1.License) error {
-	headReq, err := replicatedUpstream.getRequest("HEAD", license, ReplicatedCursor{})
-	if err != nil {
-		return errors.Wrap(err, "failed to create http request")
-	}
-	headResp, err := http.DefaultClient.Do(headReq)
+	headResp, err := endpoints.DoReplicatedAPI(license.Spec.Endpoint, func(endpoint string) (*http.Request, error) {
+		return replicatedUpstream.getRequestForEndpoint("HEAD", endpoint, license, ReplicatedCursor{})
+	})
 	if err != nil {
 		return errors.Wrap(err, "failed to execute head request")
 	}
@@ -402,33 +414,33 @@ func readReplicatedAppFromLocalPath(localPath string, localCursor ReplicatedCurs
 	return &release, nil
 }
 
-func downloadReplicatedApp(replicatedUpstream *ReplicatedUpstream, license *kotsv1beta1.License, cursor ReplicatedCursor, reportingInfo *reportingtypes.ReportingInfo) (*Release, error) {
-	getReq, err := replicatedUpstream.getRequest("GET", license, cursor)
-	if err != nil {
-		return nil, errors.Wrap(err, "failed to create http request")
-	}
-
-	reporting.InjectReportingInfoHeaders(getReq, reportingInfo)
-
-	getResp, err := http.DefaultClient.Do(getReq)
-	if err != nil {
-		return nil, errors.Wrap(err, "failed to execute get request")
-	}
-	defer getResp.Body.Close()
-
-	if getResp.StatusCode >= 300 {
-		body, _ := ioutil.ReadAll(getResp.Body)
-		if len(body) > 0 {
-			return nil, util.ActionableError{Message: string(body)}
+func downloadReplicatedApp(replicatedUpstream *ReplicatedUpstream, license *kotsv1beta1.License, cursor ReplicatedCursor, reportingInfo *reportingtypes.ReportingInfo, reportWriter io.Writer) (*Release, error) {
+	// every attempt after the first means that the previous endpoint failed, so that the download fails over to the
+	// mirrors of the endpoint
+	endpoint := ""
+	archive, err := downloadArchive(func() (*http.Request, error) {
+		if endpoint != "" {
+			endpoints.MarkFailed(endpoint)
 		}
-		return nil, errors.Errorf("unexpected result from get request: %d", getResp.StatusCode)
+		endpoint = endpoints.ReplicatedAPIEndpoints(license.Spec.Endpoint)[0]
+		getReq, err := replicatedUpstream.getRequestForEndpoint("GET", endpoint, license, cursor)
+		if err != nil {
+			return nil, err
+		}
+		reporting.InjectReportingInfoHeaders(getReq, reportingInfo)
+		return getReq, nil
+	}, reportWriter)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to download archive")
 	}
+	defer archive.Close()
 
-	updateSequence := getResp.Header.Get("X-Replicated-ChannelSequence")
-	updateChannelID := getResp.Header.Get("X-Replicated-ChannelID")
-	updateChannelName := getResp.Header.Get("X-Replicated-ChannelName")
-	versionLabel := getResp.Header.Get("X-Replicated-VersionLabel")
-	releasedAtStr := getResp.Header.Get("X-Replicated-ReleasedAt")
+	updateSequence := archive.Header.Get("X-Replicated-ChannelSequence")
+	updateChannelID := archive.Header.Get("X-Replicated-ChannelID")
+	updateChannelName := archive.Header.Get("X-Replicated-ChannelName")
+	versionLabel := archive.Header.Get("X-Replicated-VersionLabel")
+	releasedAtStr := archive.Header.Get("X-Replicated-ReleasedAt")
+	isRequired, _ := strconv.ParseBool(archive.Header.Get("X-Replicated-IsRequired"))
 
 	var releasedAt *time.Time
 	r, err := time.Parse(time.RFC3339, releasedAtStr)
@@ -436,7 +448,7 @@ func downloadReplicatedApp(replicatedUpstream *ReplicatedUpstream, license *kots
 		releasedAt = &r
 	}
 
-	gzf, err := gzip.NewReader(getResp.Body)
+	gzf, err := gzip.NewReader(archive.File)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to create new gzip reader")
 	}
@@ -450,6 +462,8 @@ func downloadReplicatedApp(replicatedUpstream *ReplicatedUpstream, license *kots
 		},
 		VersionLabel: versionLabel,
 		ReleasedAt:   releasedAt,
+		Checksum:     archive.Checksum,
+		IsRequired:   isRequired,
 		// NOTE: release notes come from Application spec
 	}
 	tarReader := tar.NewReader(gzf)
@@ -485,16 +499,6 @@ func downloadReplicatedApp(replicatedUpstream *ReplicatedUpstream, license *kots
 }
 
 func listPendingChannelReleases(replicatedUpstream *ReplicatedUpstream, license *kotsv1beta1.License, currentCursor ReplicatedCursor, reportingInfo *reportingtypes.ReportingInfo) ([]ChannelRelease, error) {
-	u, err := url.Parse(license.Spec.Endpoint)
-	if err != nil {
-		return nil, errors.Wrap(err, "failed to parse endpoint from license")
-	}
-
-	hostname := u.Hostname()
-	if u.Port() != "" {
-		hostname = fmt.Sprintf("%s:%s", u.Hostname(), u.Port())
-	}
-
 	sequence := currentCursor.Cursor
 	if license.Spec.ChannelID != "" && currentCursor.ChannelID != "" && license.Spec.ChannelID != currentCursor.ChannelID {
 		sequence = ""
@@ -506,19 +510,29 @@ func listPendingChannelReleases(replicatedUpstream *ReplicatedUpstream, license
 	urlValues.Set("channelSequence", sequence)
 	urlValues.Add("licenseSequence", fmt.Sprintf("%d", license.Spec.LicenseSequence))
 
-	url := fmt.Sprintf("%s://%s/release/%s/pending?%s", u.Scheme, hostname, license.Spec.AppSlug, urlValues.Encode())
+	resp, err := endpoints.DoReplicatedAPI(license.Spec.Endpoint, func(endpoint string) (*http.Request, error) {
+		u, err := url.Parse(endpoint)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to parse endpoint")
+		}
 
-	req, err := http.NewRequest("GET", url, nil)
-	if err != nil {
-		return nil, errors.Wrap(err, "failed to call newrequest")
-	}
+		hostname := u.Hostname()
+		if u.Port() != "" {
+			hostname = fmt.Sprintf("%s:%s", u.Hostname(), u.Port())
+		}
+
+		req, err := http.NewRequest("GET", fmt.Sprintf("%s://%s/release/%s/pending?%s", u.Scheme, hostname, license.Spec.AppSlug, urlValues.Encode()), nil)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to call newrequest")
+		}
 
-	reporting.InjectReportingInfoHeaders(req, reportingInfo)
+		reporting.InjectReportingInfoHeaders(req, reportingInfo)
 
-	req.Header.Add("User-Agent", fmt.Sprintf("KOTS/%s", buildversion.Version()))
-	req.Header.Set("Authorization", fmt.Sprintf("Basic %s", base64.StdEncoding.EncodeToString([]byte(fmt.Sprintf("%s:%s", license.Spec.LicenseID, license.Spec.LicenseID)))))
+		req.Header.Add("User-Agent", fmt.Sprintf("KOTS/%s", buildversion.Version()))
+		req.Header.Set("Authorization", fmt.Sprintf("Basic %s", base64.StdEncoding.EncodeToString([]byte(fmt.Sprintf("%s:%s", license.Spec.LicenseID, license.Spec.LicenseID)))))
 
-	resp, err := http.DefaultClient.Do(req)
+		return req, nil
+	})
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to execute get request")
 	}
@@ -573,18 +587,47 @@ func createConfigValues(applicationName string, config *kotsv1beta1.Config, exis
 
 	var newValues kotsv1beta1.ConfigValuesSpec
 	if existingConfigValues != nil {
-		for k, v := range existingConfigValues.Spec.Values {
-			value := v.Value
-			if value == "" {
-				value = v.ValuePlaintext
+		existingValues := existingConfigValues.Spec.Values
+		schemaVersion := existingConfigValues.Spec.SchemaVersion
+
+		// values given for the first version are for the schema of its release, there is nothing to migrate from
+		isFirstVersion := versionInfo != nil && versionInfo.Sequence == 0 && schemaVersion == 0
+		if config != nil && !isFirstVersion && schemaVersion < config.Spec.SchemaVersion {
+			render := func(values map[string]kotsv1beta1.ConfigValue, tmpl string) (string, error) {
+				builder, _, err := template.NewBuilder(template.BuilderOptions{
+					ExistingValues:  configValuesToItemValues(values),
+					LocalRegistry:   localRegistry,
+					Cipher:          cipher,
+					License:         license,
+					ApplicationInfo: appInfo,
+					VersionInfo:     versionInfo,
+					IdentityConfig:  identityConfig,
+				})
+				if err != nil {
+					return "", errors.Wrap(err, "failed to create config context")
+				}
+				return builder.RenderTemplate("config-migration", tmpl)
 			}
-			templateContextValues[k] = template.ItemValue{
-				Value:   value,
-				Default: v.Default,
+
+			migrated, err := migrateConfigValues(config, existingValues, schemaVersion, render)
+			if err != nil {
+				return nil, errors.Wrap(err, "failed to migrate config values")
 			}
+			existingValues = migrated
+		}
+		if config != nil && schemaVersion < config.Spec.SchemaVersion {
+			schemaVersion = config.Spec.SchemaVersion
+		}
+
+		templateContextValues = configValuesToItemValues(existingValues)
+		newValues = kotsv1beta1.ConfigValuesSpec{
+			Values:        existingValues,
+			SchemaVersion: schemaVersion,
 		}
+	} else if config != nil {
 		newValues = kotsv1beta1.ConfigValuesSpec{
-			Values: existingConfigValues.Spec.Values,
+			Values:        map[string]kotsv1beta1.ConfigValue{},
+			SchemaVersion: config.Spec.SchemaVersion,
 		}
 	} else {
 		newValues = kotsv1beta1.ConfigValuesSpec{
@@ -622,11 +665,13 @@ func createConfigValues(applicationName string, config *kotsv1beta1.Config, exis
 
 	for _, group := range config.Spec.Groups {
 		for _, item := range group.Items {
-			var foundValue, foundValuePlaintext string
+			var foundValue, foundValuePlaintext, foundVaultPath, foundVaultKey string
 			prevValue, ok := newValues.Values[item.Name]
 			if ok {
 				foundValue = prevValue.Value
 				foundValuePlaintext = prevValue.ValuePlaintext
+				foundVaultPath = prevValue.VaultPath
+				foundVaultKey = prevValue.VaultKey
 			}
 
 			renderedValue, err := builder.RenderTemplate(item.Name, item.Value.String())
@@ -639,11 +684,13 @@ func createConfigValues(applicationName string, config *kotsv1beta1.Config, exis
 				return nil, errors.Wrap(err, "failed to render config item default")
 			}
 
-			if foundValue != "" || foundValuePlaintext != "" {
+			if foundValue != "" || foundValuePlaintext != "" || foundVaultPath != "" {
 				newValues.Values[item.Name] = kotsv1beta1.ConfigValue{
 					Value:          foundValue,
 					ValuePlaintext: foundValuePlaintext,
 					Default:        renderedDefault,
+					VaultPath:      foundVaultPath,
+					VaultKey:       foundVaultKey,
 				}
 			} else {
 				newValues.Values[item.Name] = kotsv1beta1.ConfigValue{
@@ -672,6 +719,22 @@ func createConfigValues(applicationName string, config *kotsv1beta1.Config, exis
 	return &configValues, nil
 }
 
+// configValuesToItemValues returns the values of the config items for the template context
+func configValuesToItemValues(values map[string]kotsv1beta1.ConfigValue) map[string]template.ItemValue {
+	itemValues := map[string]template.ItemValue{}
+	for k, v := range values {
+		value := v.Value
+		if value == "" {
+			value = v.ValuePlaintext
+		}
+		itemValues[k] = template.ItemValue{
+			Value:   value,
+			Default: v.Default,
+		}
+	}
+	return itemValues
+}
+
 func findConfigValuesInFile(filename string) (*kotsv1beta1.ConfigValues, error) {
 	content, err := ioutil.ReadFile(filename)
 	if err != nil {
@@ -1,6 +1,7 @@
 package types
 
 import (
+	"io"
 	"path"
 	"time"
 
@@ -30,6 +31,8 @@ type Upstream struct {
 	VersionLabel  string
 	ReleaseNotes  string
 	ReleasedAt    *time.Time
+	Checksum      string
+	IsRequired    bool
 	EncryptionKey string
 }
 
@@ -70,6 +73,8 @@ type FetchOptions struct {
 	LocalRegistry          LocalRegistry
 	ReportingInfo          *reportingtypes.ReportingInfo
 	IdentityPostgresConfig *kotsv1beta1.IdentityPostgresConfig
+	// ReportWriter receives progress messages such as download retries
+	ReportWriter io.Writer
 }
 
 type LocalRegistry struct {
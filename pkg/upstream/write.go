@@ -10,6 +10,7 @@ import (
 	"github.com/pkg/errors"
 	kotsv1beta1 "github.com/replicatedhq/kots/kotskinds/apis/kots/v1beta1"
 	"github.com/replicatedhq/kots/pkg/crypto"
+	"github.com/replicatedhq/kots/pkg/sensitiveconfig"
 	"github.com/replicatedhq/kots/pkg/upstream/types"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime/serializer/json"
@@ -69,6 +70,20 @@ func WriteUpstream(u *types.Upstream, options types.WriteOptions) error {
 	}
 	u.EncryptionKey = encryptionKey
 
+	files, err := addExposureConfigGroup(u.Files)
+	if err != nil {
+		return errors.Wrap(err, "failed to add exposure config group")
+	}
+	u.Files = files
+
+	var config *kotsv1beta1.Config
+	for _, file := range u.Files {
+		if c := contentToConfig(file.Content); c != nil {
+			config = c
+			break
+		}
+	}
+
 	for i, file := range u.Files {
 		fileRenderPath := path.Join(renderDir, file.Path)
 		d, _ := path.Split(fileRenderPath)
@@ -81,7 +96,7 @@ func WriteUpstream(u *types.Upstream, options types.WriteOptions) error {
 		if options.EncryptConfig {
 			configValues := contentToConfigValues(file.Content)
 			if configValues != nil {
-				content, err := encryptConfigValues(configValues, encryptionKey)
+				content, err := encryptConfigValues(configValues, config, encryptionKey)
 				if err != nil {
 					return errors.Wrap(err, "failed to encrypt config values")
 				}
@@ -114,6 +129,12 @@ func WriteUpstream(u *types.Upstream, options types.WriteOptions) error {
 		channelName = u.ChannelName
 	}
 
+	// the checksum is only known when the archive is downloaded, keep it when re-writing the same release
+	upstreamChecksum := u.Checksum
+	if upstreamChecksum == "" && prevInstallation != nil && prevInstallation.Spec.UpdateCursor == u.UpdateCursor {
+		upstreamChecksum = prevInstallation.Spec.UpstreamChecksum
+	}
+
 	installation := kotsv1beta1.Installation{
 		TypeMeta: metav1.TypeMeta{
 			APIVersion: "kots.io/v1beta1",
@@ -123,12 +144,14 @@ func WriteUpstream(u *types.Upstream, options types.WriteOptions) error {
 			Name: u.Name,
 		},
 		Spec: kotsv1beta1.InstallationSpec{
-			UpdateCursor:  u.UpdateCursor,
-			ChannelID:     channelID,
-			ChannelName:   channelName,
-			VersionLabel:  u.VersionLabel,
-			ReleaseNotes:  u.ReleaseNotes,
-			EncryptionKey: encryptionKey,
+			UpdateCursor:     u.UpdateCursor,
+			ChannelID:        channelID,
+			ChannelName:      channelName,
+			VersionLabel:     u.VersionLabel,
+			ReleaseNotes:     u.ReleaseNotes,
+			EncryptionKey:    encryptionKey,
+			UpstreamChecksum: upstreamChecksum,
+			IsRequired:       u.IsRequired,
 		},
 	}
 
@@ -174,11 +197,34 @@ func mustMarshalInstallation(installation *kotsv1beta1.Installation) []byte {
 	return b.Bytes()
 }
 
-func encryptConfigValues(configValues *kotsv1beta1.ConfigValues, encryptionKey string) ([]byte, error) {
+// encryptConfigValues encrypts the plaintext values, and the values of the sensitive items of the config that are
+// not encrypted yet
+func encryptConfigValues(configValues *kotsv1beta1.ConfigValues, config *kotsv1beta1.Config, encryptionKey string) ([]byte, error) {
 	cipher, err := crypto.AESCipherFromString(encryptionKey)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to load encryption cipher")
 	}
+
+	if config != nil {
+		for _, group := range config.Spec.Groups {
+			for _, item := range group.Items {
+				v, ok := configValues.Spec.Values[item.Name]
+				if !ok || v.Value == "" || v.ValuePlaintext != "" || !sensitiveconfig.IsEncrypted(item) {
+					continue
+				}
+				if decoded, err := base64.StdEncoding.DecodeString(v.Value); err == nil {
+					if _, err := cipher.Decrypt(decoded); err == nil {
+						continue
+					}
+				}
+
+				v.ValuePlaintext = v.Value
+				v.Value = ""
+				configValues.Spec.Values[item.Name] = v
+			}
+		}
+	}
+
 	for k, v := range configValues.Spec.Values {
 		if v.ValuePlaintext == "" {
 			continue
@@ -0,0 +1,73 @@
+package upstream
+
+import (
+	"encoding/base64"
+	"testing"
+
+	kotsv1beta1 "github.com/replicatedhq/kots/kotskinds/apis/kots/v1beta1"
+	"github.com/replicatedhq/kots/pkg/crypto"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func Test_encryptConfigValues(t *testing.T) {
+	req := require.New(t)
+
+	cipher, err := crypto.NewAESCipher()
+	req.NoError(err)
+
+	encryptedToken := base64.StdEncoding.EncodeToString(cipher.Encrypt([]byte("token")))
+
+	config := &kotsv1beta1.Config{
+		Spec: kotsv1beta1.ConfigSpec{
+			Groups: []kotsv1beta1.ConfigGroup{
+				{
+					Name: "database",
+					Items: []kotsv1beta1.ConfigItem{
+						{Name: "db_host", Type: "text"},
+						{Name: "db_password", Type: "password"},
+						{Name: "db_user", Type: "text", Sensitive: true},
+						{Name: "db_token", Type: "text", Sensitive: true},
+					},
+				},
+			},
+		},
+	}
+	configValues := &kotsv1beta1.ConfigValues{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "kots.io/v1beta1",
+			Kind:       "ConfigValues",
+		},
+		Spec: kotsv1beta1.ConfigValuesSpec{
+			Values: map[string]kotsv1beta1.ConfigValue{
+				"db_host":     {Value: "postgres"},
+				"db_password": {ValuePlaintext: "hunter2"},
+				"db_user":     {Value: "admin"},
+				"db_token":    {Value: encryptedToken},
+			},
+		},
+	}
+
+	content, err := encryptConfigValues(configValues, config, cipher.ToString())
+	req.NoError(err)
+
+	encrypted := contentToConfigValues(content)
+	req.NotNil(encrypted)
+
+	decrypt := func(value string) string {
+		decoded, err := base64.StdEncoding.DecodeString(value)
+		req.NoError(err)
+		decrypted, err := cipher.Decrypt(decoded)
+		req.NoError(err)
+		return string(decrypted)
+	}
+
+	req.Equal("postgres", encrypted.Spec.Values["db_host"].Value)
+	req.Equal("hunter2", decrypt(encrypted.Spec.Values["db_password"].Value))
+	req.Equal("admin", decrypt(encrypted.Spec.Values["db_user"].Value))
+	// values that are already encrypted are not encrypted again
+	req.Equal(encryptedToken, encrypted.Spec.Values["db_token"].Value)
+	for _, v := range encrypted.Spec.Values {
+		req.Empty(v.ValuePlaintext)
+	}
+}
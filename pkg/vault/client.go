@@ -0,0 +1,265 @@
+package vault
+
+import (
+	"bytes"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// DefaultSecretCacheTTL is how long secrets without a lease, such as kv secrets, are cached
+	DefaultSecretCacheTTL = 5 * time.Minute
+
+	// tokens are renewed when they are this close to expiring
+	tokenRenewBefore = 30 * time.Second
+)
+
+// Client reads secrets from vault. Secrets are cached for their lease duration and
+// the auth token is renewed before it expires, or replaced by logging in again when it cannot be renewed.
+type Client struct {
+	config     Config
+	httpClient *http.Client
+
+	mtx            sync.Mutex
+	token          string
+	tokenExpiresAt time.Time // zero if the token does not expire
+	tokenRenewable bool
+	secrets        map[string]cachedSecret
+}
+
+type cachedSecret struct {
+	data      map[string]interface{}
+	expiresAt time.Time
+}
+
+type secretResponse struct {
+	LeaseDuration int                    `json:"lease_duration"`
+	Data          map[string]interface{} `json:"data"`
+	Auth          *authResponse          `json:"auth"`
+	Errors        []string               `json:"errors"`
+}
+
+type authResponse struct {
+	ClientToken   string `json:"client_token"`
+	LeaseDuration int    `json:"lease_duration"`
+	Renewable     bool   `json:"renewable"`
+}
+
+func NewClient(config Config) (*Client, error) {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if len(config.CACert) > 0 {
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(config.CACert) {
+			return nil, errors.New("failed to parse ca cert")
+		}
+		transport.TLSClientConfig = &tls.Config{RootCAs: pool}
+	}
+
+	return &Client{
+		config: config,
+		httpClient: &http.Client{
+			Transport: transport,
+			Timeout:   30 * time.Second,
+		},
+		secrets: map[string]cachedSecret{},
+	}, nil
+}
+
+// GetValue returns the value of key in the secret at path.
+// Both kv version 1 and version 2 secrets are supported, for version 2 the path must include "data/".
+func (c *Client) GetValue(path string, key string) (string, error) {
+	data, err := c.readSecret(path)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to read secret %s", path)
+	}
+
+	value, ok := data[key]
+	if !ok {
+		return "", errors.Errorf("key %s not found in secret %s", key, path)
+	}
+
+	if s, ok := value.(string); ok {
+		return s, nil
+	}
+
+	b, err := json.Marshal(value)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to marshal value")
+	}
+	return string(b), nil
+}
+
+func (c *Client) readSecret(path string) (map[string]interface{}, error) {
+	path = strings.Trim(path, "/")
+
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	if cached, ok := c.secrets[path]; ok && time.Now().Before(cached.expiresAt) {
+		return cached.data, nil
+	}
+
+	token, err := c.getToken()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get token")
+	}
+
+	response, err := c.do("GET", path, nil, token)
+	if err != nil {
+		return nil, err
+	}
+
+	data := response.Data
+	// kv version 2 nests the secret under data with its metadata
+	if nested, ok := data["data"].(map[string]interface{}); ok {
+		if _, ok := data["metadata"]; ok {
+			data = nested
+		}
+	}
+
+	ttl := DefaultSecretCacheTTL
+	if response.LeaseDuration > 0 {
+		ttl = time.Duration(response.LeaseDuration) * time.Second
+	}
+	c.secrets[path] = cachedSecret{
+		data:      data,
+		expiresAt: time.Now().Add(ttl),
+	}
+
+	return data, nil
+}
+
+// getToken must be called with the mutex held
+func (c *Client) getToken() (string, error) {
+	if c.token != "" {
+		if c.tokenExpiresAt.IsZero() || time.Now().Add(tokenRenewBefore).Before(c.tokenExpiresAt) {
+			return c.token, nil
+		}
+
+		if c.tokenRenewable && time.Now().Before(c.tokenExpiresAt) {
+			response, err := c.do("POST", "auth/token/renew-self", map[string]interface{}{}, c.token)
+			if err == nil && response.Auth != nil {
+				c.setToken(response.Auth)
+				return c.token, nil
+			}
+		}
+	}
+
+	if err := c.login(); err != nil {
+		return "", errors.Wrap(err, "failed to login")
+	}
+
+	// cached secrets may have been read with a token whose leases have been revoked
+	c.secrets = map[string]cachedSecret{}
+
+	return c.token, nil
+}
+
+func (c *Client) login() error {
+	switch c.config.AuthMethod {
+	case AuthMethodToken:
+		response, err := c.do("GET", "auth/token/lookup-self", nil, c.config.Token)
+		if err != nil {
+			return errors.Wrap(err, "failed to lookup token")
+		}
+
+		ttl, _ := response.Data["ttl"].(float64)
+		renewable, _ := response.Data["renewable"].(bool)
+		c.setToken(&authResponse{
+			ClientToken:   c.config.Token,
+			LeaseDuration: int(ttl),
+			Renewable:     renewable,
+		})
+		return nil
+
+	case AuthMethodKubernetes:
+		jwt, err := ioutil.ReadFile(serviceAccountTokenPath)
+		if err != nil {
+			return errors.Wrap(err, "failed to read service account token")
+		}
+
+		response, err := c.do("POST", fmt.Sprintf("auth/%s/login", strings.Trim(c.config.AuthMountPath, "/")), map[string]interface{}{
+			"role": c.config.Role,
+			"jwt":  string(jwt),
+		}, "")
+		if err != nil {
+			return err
+		}
+		if response.Auth == nil {
+			return errors.New("login response did not include auth")
+		}
+
+		c.setToken(response.Auth)
+		return nil
+	}
+
+	return errors.Errorf("unsupported auth method %q", c.config.AuthMethod)
+}
+
+func (c *Client) setToken(auth *authResponse) {
+	c.token = auth.ClientToken
+	c.tokenRenewable = auth.Renewable
+	c.tokenExpiresAt = time.Time{}
+	if auth.LeaseDuration > 0 {
+		c.tokenExpiresAt = time.Now().Add(time.Duration(auth.LeaseDuration) * time.Second)
+	}
+}
+
+func (c *Client) do(method string, path string, body interface{}, token string) (*secretResponse, error) {
+	var reqBody *bytes.Reader
+	if body != nil {
+		b, err := json.Marshal(body)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to marshal request")
+		}
+		reqBody = bytes.NewReader(b)
+	} else {
+		reqBody = bytes.NewReader(nil)
+	}
+
+	url := fmt.Sprintf("%s/v1/%s", strings.TrimSuffix(c.config.Address, "/"), path)
+	req, err := http.NewRequest(method, url, reqBody)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create request")
+	}
+	if token != "" {
+		req.Header.Set("X-Vault-Token", token)
+	}
+	if c.config.Namespace != "" {
+		req.Header.Set("X-Vault-Namespace", c.config.Namespace)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to %s %s", method, path)
+	}
+	defer resp.Body.Close()
+
+	b, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read response body")
+	}
+
+	response := &secretResponse{}
+	if len(b) > 0 {
+		if err := json.Unmarshal(b, response); err != nil {
+			return nil, errors.Wrapf(err, "failed to unmarshal response with status %d", resp.StatusCode)
+		}
+	}
+
+	if resp.StatusCode >= 400 {
+		return nil, errors.Errorf("unexpected status code %d from %s %s: %s", resp.StatusCode, method, path, strings.Join(response.Errors, ", "))
+	}
+
+	return response, nil
+}
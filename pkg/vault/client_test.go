@@ -0,0 +1,163 @@
+package vault
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ClientGetValue(t *testing.T) {
+	req := require.New(t)
+
+	secretReads := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Vault-Token") != "s.token" {
+			w.WriteHeader(http.StatusForbidden)
+			json.NewEncoder(w).Encode(map[string]interface{}{"errors": []string{"permission denied"}})
+			return
+		}
+
+		switch r.URL.Path {
+		case "/v1/auth/token/lookup-self":
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"data": map[string]interface{}{"ttl": 3600, "renewable": true},
+			})
+		case "/v1/secret/data/db":
+			secretReads++
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"data": map[string]interface{}{
+					"data":     map[string]interface{}{"password": "hunter2", "port": 5432},
+					"metadata": map[string]interface{}{"version": 1},
+				},
+			})
+		case "/v1/kv/db":
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"lease_duration": 60,
+				"data":           map[string]interface{}{"password": "swordfish"},
+			})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			json.NewEncoder(w).Encode(map[string]interface{}{"errors": []string{}})
+		}
+	}))
+	defer server.Close()
+
+	config := Config{Address: server.URL, AuthMethod: AuthMethodToken, Token: "s.token"}
+	req.NoError(config.validate())
+
+	client, err := NewClient(config)
+	req.NoError(err)
+
+	// kv version 2
+	value, err := client.GetValue("secret/data/db", "password")
+	req.NoError(err)
+	req.Equal("hunter2", value)
+
+	value, err = client.GetValue("/secret/data/db", "port")
+	req.NoError(err)
+	req.Equal("5432", value)
+	req.Equal(1, secretReads, "secret should be read from the cache")
+
+	// kv version 1
+	value, err = client.GetValue("kv/db", "password")
+	req.NoError(err)
+	req.Equal("swordfish", value)
+
+	_, err = client.GetValue("kv/db", "missing")
+	req.Error(err)
+
+	_, err = client.GetValue("kv/missing", "password")
+	req.Error(err)
+}
+
+func Test_ClientTransit(t *testing.T) {
+	req := require.New(t)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v1/auth/token/lookup-self":
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"data": map[string]interface{}{"ttl": 0, "renewable": false},
+			})
+		case "/v1/transit/keys/kots":
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"data": map[string]interface{}{
+					"type":           "ed25519",
+					"latest_version": 2,
+					"keys": map[string]interface{}{
+						"1": map[string]interface{}{"public_key": "invalid"},
+						"2": map[string]interface{}{"public_key": "O2onvM62pC1io6jQKm8Nc2UyFXcd4kOmOsBIoYtZ2ik="},
+					},
+				},
+			})
+		case "/v1/transit/sign/kots":
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"data": map[string]interface{}{"signature": "vault:v2:c2lnbmF0dXJl"},
+			})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			json.NewEncoder(w).Encode(map[string]interface{}{"errors": []string{}})
+		}
+	}))
+	defer server.Close()
+
+	client, err := NewClient(Config{Address: server.URL, AuthMethod: AuthMethodToken, Token: "s.token"})
+	req.NoError(err)
+
+	publicKey, err := client.TransitPublicKey("/transit/", "kots")
+	req.NoError(err)
+	req.Len(publicKey, 32)
+
+	signature, err := client.TransitSign("transit", "kots", []byte("message"))
+	req.NoError(err)
+	req.Equal("signature", string(signature))
+
+	_, err = client.TransitPublicKey("transit", "missing")
+	req.Error(err)
+}
+
+func Test_ConfigValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  Config
+		wantErr bool
+	}{
+		{
+			name:   "token is the default auth method",
+			config: Config{Address: "https://vault:8200", Token: "s.token"},
+		},
+		{
+			name:    "token auth without a token",
+			config:  Config{Address: "https://vault:8200", AuthMethod: AuthMethodToken},
+			wantErr: true,
+		},
+		{
+			name:   "kubernetes auth",
+			config: Config{Address: "https://vault:8200", AuthMethod: AuthMethodKubernetes, Role: "kotsadm"},
+		},
+		{
+			name:    "kubernetes auth without a role",
+			config:  Config{Address: "https://vault:8200", AuthMethod: AuthMethodKubernetes},
+			wantErr: true,
+		},
+		{
+			name:    "missing address",
+			config:  Config{Token: "s.token"},
+			wantErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := test.config.validate()
+			if test.wantErr {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
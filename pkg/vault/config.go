@@ -0,0 +1,95 @@
+package vault
+
+import (
+	"context"
+	"os"
+
+	"github.com/pkg/errors"
+	"github.com/replicatedhq/kots/pkg/k8sutil"
+	kuberneteserrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// ConfigSecretName is the secret in the kotsadm namespace that configures the vault integration
+	ConfigSecretName = "kotsadm-vault"
+
+	AuthMethodToken      = "token"
+	AuthMethodKubernetes = "kubernetes"
+
+	defaultKubernetesAuthMountPath = "kubernetes"
+	serviceAccountTokenPath        = "/var/run/secrets/kubernetes.io/serviceaccount/token"
+)
+
+// Config is how kotsadm connects and authenticates to vault
+type Config struct {
+	Address string
+	// Namespace is the vault enterprise namespace, if any
+	Namespace string
+	// AuthMethod is one of "token" or "kubernetes"
+	AuthMethod string
+	// Token is used with the token auth method
+	Token string
+	// Role and AuthMountPath are used with the kubernetes auth method
+	Role          string
+	AuthMountPath string
+	CACert        []byte
+}
+
+// GetConfig reads the vault config from the kotsadm-vault secret.
+// It returns nil if the integration is not configured.
+func GetConfig() (*Config, error) {
+	clientset, err := k8sutil.GetClientset()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get k8s clientset")
+	}
+
+	secret, err := clientset.CoreV1().Secrets(os.Getenv("POD_NAMESPACE")).Get(context.TODO(), ConfigSecretName, metav1.GetOptions{})
+	if kuberneteserrors.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get vault config secret")
+	}
+
+	config := &Config{
+		Address:       string(secret.Data["address"]),
+		Namespace:     string(secret.Data["namespace"]),
+		AuthMethod:    string(secret.Data["auth-method"]),
+		Token:         string(secret.Data["token"]),
+		Role:          string(secret.Data["role"]),
+		AuthMountPath: string(secret.Data["auth-mount-path"]),
+		CACert:        secret.Data["ca-cert"],
+	}
+
+	if err := config.validate(); err != nil {
+		return nil, errors.Wrap(err, "invalid vault config")
+	}
+
+	return config, nil
+}
+
+func (c *Config) validate() error {
+	if c.Address == "" {
+		return errors.New("address is required")
+	}
+
+	switch c.AuthMethod {
+	case "", AuthMethodToken:
+		c.AuthMethod = AuthMethodToken
+		if c.Token == "" {
+			return errors.New("token is required for the token auth method")
+		}
+	case AuthMethodKubernetes:
+		if c.Role == "" {
+			return errors.New("role is required for the kubernetes auth method")
+		}
+		if c.AuthMountPath == "" {
+			c.AuthMountPath = defaultKubernetesAuthMountPath
+		}
+	default:
+		return errors.Errorf("unsupported auth method %q", c.AuthMethod)
+	}
+
+	return nil
+}
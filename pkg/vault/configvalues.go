@@ -0,0 +1,111 @@
+package vault
+
+import (
+	"reflect"
+	"sort"
+	"sync"
+
+	"github.com/pkg/errors"
+	kotsv1beta1 "github.com/replicatedhq/kots/kotskinds/apis/kots/v1beta1"
+)
+
+var (
+	sharedClient    *Client
+	sharedClientMtx sync.Mutex
+)
+
+// HasVaultValues returns true if any of the config values are read from vault
+func HasVaultValues(configValues *kotsv1beta1.ConfigValues) bool {
+	if configValues == nil {
+		return false
+	}
+
+	for _, value := range configValues.Spec.Values {
+		if value.VaultPath != "" {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ItemNames returns the names of the config items whose values are read from vault. Their values are only
+// resolved at deploy time, into the sensitive config Secret, so templates can only reference them through it.
+func ItemNames(configValues *kotsv1beta1.ConfigValues) []string {
+	if configValues == nil {
+		return nil
+	}
+
+	names := []string{}
+	for name, value := range configValues.Spec.Values {
+		if value.VaultPath != "" {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// ResolveConfigValues returns a copy of the config values with the values that reference vault
+// read into ValuePlaintext. The original config values are not modified so that they can be stored without the secrets.
+func ResolveConfigValues(configValues *kotsv1beta1.ConfigValues) (*kotsv1beta1.ConfigValues, error) {
+	if !HasVaultValues(configValues) {
+		return configValues, nil
+	}
+
+	client, err := getClient()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get vault client")
+	}
+
+	resolved := configValues.DeepCopy()
+	for name, value := range resolved.Spec.Values {
+		if value.VaultPath == "" {
+			continue
+		}
+
+		key := value.VaultKey
+		if key == "" {
+			key = name
+		}
+
+		secretValue, err := client.GetValue(value.VaultPath, key)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to get value of %s from vault", name)
+		}
+
+		value.Value = ""
+		value.ValuePlaintext = secretValue
+		resolved.Spec.Values[name] = value
+	}
+
+	return resolved, nil
+}
+
+// getClient returns a client that is shared for as long as the config does not change,
+// so that tokens and secrets are cached across renders and deploys
+func getClient() (*Client, error) {
+	config, err := GetConfig()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get vault config")
+	}
+	if config == nil {
+		return nil, errors.New("config values reference vault but the vault integration is not configured")
+	}
+
+	sharedClientMtx.Lock()
+	defer sharedClientMtx.Unlock()
+
+	if sharedClient != nil && reflect.DeepEqual(sharedClient.config, *config) {
+		return sharedClient, nil
+	}
+
+	client, err := NewClient(*config)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create client")
+	}
+	sharedClient = client
+
+	return sharedClient, nil
+}
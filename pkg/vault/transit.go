@@ -0,0 +1,99 @@
+package vault
+
+import (
+	"crypto/ed25519"
+	"encoding/base64"
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// GetSharedClient returns the client that is shared with the config values, nil if the integration is not configured
+func GetSharedClient() (*Client, error) {
+	config, err := GetConfig()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get vault config")
+	}
+	if config == nil {
+		return nil, nil
+	}
+
+	return getClient()
+}
+
+// TransitSign signs the message with the latest version of an ed25519 key of the transit secrets engine mounted at
+// mountPath, and returns the raw signature
+func (c *Client) TransitSign(mountPath string, keyName string, message []byte) ([]byte, error) {
+	path := fmt.Sprintf("%s/sign/%s", strings.Trim(mountPath, "/"), keyName)
+
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	token, err := c.getToken()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get token")
+	}
+
+	response, err := c.do("POST", path, map[string]interface{}{
+		"input": base64.StdEncoding.EncodeToString(message),
+	}, token)
+	if err != nil {
+		return nil, err
+	}
+
+	signature, _ := response.Data["signature"].(string)
+	// the signature is prefixed with the vault and key version, as in vault:v1:<base64>
+	parts := strings.Split(signature, ":")
+	if len(parts) != 3 || parts[0] != "vault" {
+		return nil, errors.Errorf("unexpected signature format %q", signature)
+	}
+
+	decoded, err := base64.StdEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to decode signature")
+	}
+
+	return decoded, nil
+}
+
+// TransitPublicKey returns the public key of the latest version of an ed25519 key of the transit secrets engine
+// mounted at mountPath
+func (c *Client) TransitPublicKey(mountPath string, keyName string) (ed25519.PublicKey, error) {
+	path := fmt.Sprintf("%s/keys/%s", strings.Trim(mountPath, "/"), keyName)
+
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	token, err := c.getToken()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get token")
+	}
+
+	response, err := c.do("GET", path, nil, token)
+	if err != nil {
+		return nil, err
+	}
+
+	if keyType, _ := response.Data["type"].(string); keyType != "ed25519" {
+		return nil, errors.Errorf("transit key %s has type %q, an ed25519 key is required", keyName, keyType)
+	}
+
+	latestVersion, _ := response.Data["latest_version"].(float64)
+	keys, _ := response.Data["keys"].(map[string]interface{})
+	key, _ := keys[fmt.Sprintf("%d", int(latestVersion))].(map[string]interface{})
+	publicKey, _ := key["public_key"].(string)
+	if publicKey == "" {
+		return nil, errors.Errorf("transit key %s has no public key for version %d", keyName, int(latestVersion))
+	}
+
+	decoded, err := base64.StdEncoding.DecodeString(publicKey)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to decode public key")
+	}
+	if len(decoded) != ed25519.PublicKeySize {
+		return nil, errors.Errorf("public key of transit key %s has length %d", keyName, len(decoded))
+	}
+
+	return ed25519.PublicKey(decoded), nil
+}
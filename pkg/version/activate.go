@@ -0,0 +1,88 @@
+package version
+
+import (
+	"time"
+
+	"github.com/pkg/errors"
+	downstreamtypes "github.com/replicatedhq/kots/pkg/api/downstream/types"
+	deployhistorytypes "github.com/replicatedhq/kots/pkg/deployhistory/types"
+	"github.com/replicatedhq/kots/pkg/freeze"
+	"github.com/replicatedhq/kots/pkg/persistence"
+	"github.com/replicatedhq/kots/pkg/store"
+)
+
+var (
+	ErrNotTwoPhaseDeploy = errors.New("the version is not deployed in two phases")
+	ErrNotCurrentVersion = errors.New("only the deployed version can be activated")
+	ErrNotDeployed       = errors.New("the version can only be activated once it has been deployed successfully")
+	ErrAlreadyActivated  = errors.New("the version is already activated or being activated")
+)
+
+// CheckActivate returns an error if the sequence can't be activated, the current version is the version that is
+// deployed to the downstream
+func CheckActivate(currentVersion *downstreamtypes.DownstreamVersion, sequence int64) error {
+	if currentVersion == nil || currentVersion.Sequence != sequence {
+		return ErrNotCurrentVersion
+	}
+	if !currentVersion.TwoPhaseDeploy {
+		return ErrNotTwoPhaseDeploy
+	}
+	if currentVersion.Status != "deployed" {
+		return ErrNotDeployed
+	}
+	switch currentVersion.ActivationStatus {
+	case downstreamtypes.ActivationStatusActivating, downstreamtypes.ActivationStatusActivated:
+		return ErrAlreadyActivated
+	}
+	return nil
+}
+
+// ActivateVersion starts the activation of the deployed sequence of an app that is deployed in two phases. The
+// operator applies the resources that were held back when the sequence was deployed, then removes the resources of
+// the previously activated version. The activation is recorded in the deploy history.
+func ActivateVersion(appID string, clusterID string, sequence int64, deployer deployhistorytypes.Deployer) error {
+	if err := freeze.CheckDeploy(appID); err != nil {
+		return err
+	}
+
+	currentVersion, err := store.GetStore().GetCurrentVersion(appID, clusterID)
+	if err != nil {
+		return errors.Wrap(err, "failed to get current version")
+	}
+	if err := CheckActivate(currentVersion, sequence); err != nil {
+		return err
+	}
+
+	db := persistence.MustGetPGSession()
+
+	tx, err := db.Begin()
+	if err != nil {
+		return errors.Wrap(err, "failed to begin")
+	}
+	defer tx.Rollback()
+
+	// the status is checked again in the update, so that two concurrent requests do not both activate the version
+	query := `update app_downstream_version set activation_status = $1, activation_status_info = null, activated_at = null
+	where app_id = $2 and cluster_id = $3 and sequence = $4 and (activation_status is null or activation_status = $5)`
+	result, err := tx.Exec(query, downstreamtypes.ActivationStatusActivating, appID, clusterID, sequence, downstreamtypes.ActivationStatusFailed)
+	if err != nil {
+		return errors.Wrap(err, "failed to update app downstream version activation status")
+	}
+	updated, err := result.RowsAffected()
+	if err != nil {
+		return errors.Wrap(err, "failed to get rows affected")
+	}
+	if updated == 0 {
+		return ErrAlreadyActivated
+	}
+
+	if err := recordDeployEvents(tx, appID, clusterID, sequence, deployhistorytypes.PhaseActivate, deployer, time.Now()); err != nil {
+		return errors.Wrap(err, "failed to record deploy events")
+	}
+
+	if err := tx.Commit(); err != nil {
+		return errors.Wrap(err, "failed to commit")
+	}
+
+	return nil
+}
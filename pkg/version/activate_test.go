@@ -0,0 +1,68 @@
+package version
+
+import (
+	"testing"
+
+	downstreamtypes "github.com/replicatedhq/kots/pkg/api/downstream/types"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_CheckActivate(t *testing.T) {
+	tests := []struct {
+		name           string
+		currentVersion *downstreamtypes.DownstreamVersion
+		sequence       int64
+		want           error
+	}{
+		{
+			name:           "deployed",
+			currentVersion: &downstreamtypes.DownstreamVersion{Sequence: 3, TwoPhaseDeploy: true, Status: "deployed"},
+			sequence:       3,
+		},
+		{
+			name:           "activation failed",
+			currentVersion: &downstreamtypes.DownstreamVersion{Sequence: 3, TwoPhaseDeploy: true, Status: "deployed", ActivationStatus: downstreamtypes.ActivationStatusFailed},
+			sequence:       3,
+		},
+		{
+			name:     "nothing deployed",
+			sequence: 3,
+			want:     ErrNotCurrentVersion,
+		},
+		{
+			name:           "not the current version",
+			currentVersion: &downstreamtypes.DownstreamVersion{Sequence: 4, TwoPhaseDeploy: true, Status: "deployed"},
+			sequence:       3,
+			want:           ErrNotCurrentVersion,
+		},
+		{
+			name:           "single phase",
+			currentVersion: &downstreamtypes.DownstreamVersion{Sequence: 3, Status: "deployed"},
+			sequence:       3,
+			want:           ErrNotTwoPhaseDeploy,
+		},
+		{
+			name:           "deploy failed",
+			currentVersion: &downstreamtypes.DownstreamVersion{Sequence: 3, TwoPhaseDeploy: true, Status: "failed"},
+			sequence:       3,
+			want:           ErrNotDeployed,
+		},
+		{
+			name:           "activating",
+			currentVersion: &downstreamtypes.DownstreamVersion{Sequence: 3, TwoPhaseDeploy: true, Status: "deployed", ActivationStatus: downstreamtypes.ActivationStatusActivating},
+			sequence:       3,
+			want:           ErrAlreadyActivated,
+		},
+		{
+			name:           "activated",
+			currentVersion: &downstreamtypes.DownstreamVersion{Sequence: 3, TwoPhaseDeploy: true, Status: "deployed", ActivationStatus: downstreamtypes.ActivationStatusActivated},
+			sequence:       3,
+			want:           ErrAlreadyActivated,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			require.Equal(t, test.want, CheckActivate(test.currentVersion, test.sequence))
+		})
+	}
+}
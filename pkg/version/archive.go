@@ -5,6 +5,8 @@ import (
 
 	"github.com/mholt/archiver"
 	"github.com/pkg/errors"
+	"github.com/replicatedhq/kots/pkg/kotsutil"
+	"github.com/replicatedhq/kots/pkg/store"
 )
 
 func ExtractArchiveToTempDirectory(archiveFilename string) (string, error) {
@@ -25,3 +27,32 @@ func ExtractArchiveToTempDirectory(archiveFilename string) (string, error) {
 
 	return tmpDir, nil
 }
+
+// GetKotsKinds loads the kots kinds of an app version from the archive stream.
+// This is cheaper than extracting the archive when only the kots kinds are needed.
+func GetKotsKinds(appID string, sequence int64) (*kotsutil.KotsKinds, error) {
+	archive, err := store.GetStore().GetAppVersionArchiveReader(appID, sequence)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get app version archive")
+	}
+	defer archive.Close()
+
+	kotsKinds, err := kotsutil.LoadKotsKindsFromArchive(archive)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to load kots kinds from archive")
+	}
+
+	return kotsKinds, nil
+}
+
+// GetArchiveFile returns a single file of an app version, e.g. "upstream/userdata/license.yaml", from the archive stream.
+// The returned error satisfies os.IsNotExist when the file is not in the archive.
+func GetArchiveFile(appID string, sequence int64, filename string) ([]byte, error) {
+	archive, err := store.GetStore().GetAppVersionArchiveReader(appID, sequence)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get app version archive")
+	}
+	defer archive.Close()
+
+	return kotsutil.ReadFileFromArchive(archive, filename)
+}
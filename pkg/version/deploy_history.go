@@ -0,0 +1,93 @@
+package version
+
+import (
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	"github.com/pkg/errors"
+	deployhistorytypes "github.com/replicatedhq/kots/pkg/deployhistory/types"
+	troubleshootpreflight "github.com/replicatedhq/troubleshoot/pkg/preflight"
+	"github.com/segmentio/ksuid"
+)
+
+// recordDeployEvents adds the deploy of the sequence to the deploy history of the downstream, or of every downstream
+// of the app if clusterID is empty. It's recorded in the transaction that deploys the sequence. The phase is empty
+// for deploys, see deployhistorytypes.PhaseActivate.
+func recordDeployEvents(tx *sql.Tx, appID string, clusterID string, sequence int64, phase string, deployer deployhistorytypes.Deployer, deployedAt time.Time) error {
+	query := `select cluster_id, preflight_result from app_downstream_version where app_id = $1 and sequence = $2`
+	args := []interface{}{appID, sequence}
+	if clusterID != "" {
+		query += ` and cluster_id = $3`
+		args = append(args, clusterID)
+	}
+
+	rows, err := tx.Query(query, args...)
+	if err != nil {
+		return errors.Wrap(err, "failed to query downstream versions")
+	}
+
+	// the rows must be closed before the events are inserted in the same transaction
+	type downstreamVersion struct {
+		clusterID      string
+		preflightState string
+	}
+	downstreamVersions := []downstreamVersion{}
+	for rows.Next() {
+		var id string
+		var preflightResult sql.NullString
+		if err := rows.Scan(&id, &preflightResult); err != nil {
+			rows.Close()
+			return errors.Wrap(err, "failed to scan downstream version")
+		}
+
+		preflightState := deployhistorytypes.PreflightStateSkipped
+		if !deployer.SkippedPreflights {
+			preflightState = getPreflightState(preflightResult.String)
+		}
+		downstreamVersions = append(downstreamVersions, downstreamVersion{clusterID: id, preflightState: preflightState})
+	}
+	err = rows.Err()
+	rows.Close()
+	if err != nil {
+		return errors.Wrap(err, "failed to iterate downstream versions")
+	}
+
+	query = `insert into app_deploy_event (id, app_id, cluster_id, sequence, deployed_at, deployed_by, approved_by, preflight_state, result, phase)
+	values ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)`
+	for _, dv := range downstreamVersions {
+		_, err := tx.Exec(query, ksuid.New().String(), appID, dv.clusterID, sequence, deployedAt, deployer.DeployedBy,
+			deployer.ApprovedBy, dv.preflightState, deployhistorytypes.ResultPending, sql.NullString{String: phase, Valid: phase != ""})
+		if err != nil {
+			return errors.Wrap(err, "failed to insert deploy event")
+		}
+	}
+
+	return nil
+}
+
+// getPreflightState returns pass, warn or fail for the preflight result of a downstream version, or an empty string
+// if preflights have not run
+func getPreflightState(preflightResult string) string {
+	if preflightResult == "" {
+		return ""
+	}
+
+	preflightResults := troubleshootpreflight.UploadPreflightResults{}
+	if err := json.Unmarshal([]byte(preflightResult), &preflightResults); err != nil {
+		return ""
+	}
+
+	if len(preflightResults.Errors) > 0 {
+		return "fail"
+	}
+	state := "pass"
+	for _, result := range preflightResults.Results {
+		if result.IsFail {
+			return "fail"
+		} else if result.IsWarn {
+			state = "warn"
+		}
+	}
+	return state
+}
@@ -15,6 +15,8 @@ import (
 	kotsv1beta1 "github.com/replicatedhq/kots/kotskinds/apis/kots/v1beta1"
 	"github.com/replicatedhq/kots/pkg/logger"
 	"github.com/replicatedhq/kots/pkg/persistence"
+	"github.com/replicatedhq/kots/pkg/prometheus"
+	prometheustypes "github.com/replicatedhq/kots/pkg/prometheus/types"
 	"k8s.io/client-go/kubernetes/scheme"
 )
 
@@ -79,11 +81,65 @@ var (
 	}
 )
 
-func GetMetricCharts(appID string, sequence int64, prometheusAddress string) ([]MetricChart, error) {
-	if prometheusAddress == "" {
+var (
+	ErrGraphNotFound = errors.New("graph not found")
+)
+
+func GetMetricCharts(appID string, sequence int64, prometheusSettings prometheustypes.Settings) ([]MetricChart, error) {
+	if prometheusSettings.Address == "" {
 		return []MetricChart{}, nil
 	}
 
+	graphs, err := getMetricGraphs(appID, sequence)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get metric graphs")
+	}
+
+	endTime := uint(time.Now().Unix())
+	charts := []MetricChart{}
+	for _, graph := range graphs {
+		duration := DefaultQueryDurationSeconds
+		if graph.DurationSeconds > 0 {
+			duration = graph.DurationSeconds
+		}
+
+		chart := queryMetricChart(prometheusSettings, graph, endTime-duration, endTime, duration/DefaultGraphStepPoints)
+		charts = append(charts, chart)
+	}
+
+	return charts, nil
+}
+
+// GetMetricChart runs the queries of one of the graphs declared by the app version over the given range.
+// Only the declared queries can be run, so that the console cannot be used to send arbitrary queries to prometheus.
+// If step is 0, the range is divided into DefaultGraphStepPoints steps.
+func GetMetricChart(appID string, sequence int64, prometheusSettings prometheustypes.Settings, graphIndex int, start uint, end uint, step uint) (*MetricChart, error) {
+	if end <= start {
+		return nil, errors.New("end must be after start")
+	}
+
+	graphs, err := getMetricGraphs(appID, sequence)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get metric graphs")
+	}
+
+	if graphIndex < 0 || graphIndex >= len(graphs) {
+		return nil, ErrGraphNotFound
+	}
+
+	if step == 0 {
+		step = (end - start) / DefaultGraphStepPoints
+	}
+	if step == 0 {
+		step = 1
+	}
+
+	chart := queryMetricChart(prometheusSettings, graphs[graphIndex], start, end, step)
+	return &chart, nil
+}
+
+// getMetricGraphs returns the graphs declared in the application spec of the version, or the default graphs
+func getMetricGraphs(appID string, sequence int64) ([]kotsv1beta1.MetricGraph, error) {
 	db := persistence.MustGetPGSession()
 	query := `select kots_app_spec from app_version where app_id = $1 and sequence = $2`
 	row := db.QueryRow(query, appID, sequence)
@@ -91,7 +147,7 @@ func GetMetricCharts(appID string, sequence int64, prometheusAddress string) ([]
 	var kotsAppSpecStr sql.NullString
 	if err := row.Scan(&kotsAppSpecStr); err != nil {
 		if err == sql.ErrNoRows {
-			return []MetricChart{}, nil
+			return []kotsv1beta1.MetricGraph{}, nil
 		}
 		return nil, errors.Wrap(err, "failed to scan")
 	}
@@ -110,80 +166,72 @@ func GetMetricCharts(appID string, sequence int64, prometheusAddress string) ([]
 		}
 	}
 
-	endTime := uint(time.Now().Unix())
-	charts := []MetricChart{}
-	for _, graph := range graphs {
-		queries := []kotsv1beta1.MetricQuery{}
+	return graphs, nil
+}
 
-		if graph.Query != "" {
-			query := kotsv1beta1.MetricQuery{
-				Query:  graph.Query,
-				Legend: graph.Legend,
-			}
-			queries = append(queries, query)
-		}
+func queryMetricChart(prometheusSettings prometheustypes.Settings, graph kotsv1beta1.MetricGraph, start uint, end uint, step uint) MetricChart {
+	queries := []kotsv1beta1.MetricQuery{}
 
-		for _, query := range graph.Queries {
-			queries = append(queries, query)
+	if graph.Query != "" {
+		query := kotsv1beta1.MetricQuery{
+			Query:  graph.Query,
+			Legend: graph.Legend,
 		}
+		queries = append(queries, query)
+	}
 
-		series := []Series{}
-		for _, query := range queries {
-			duration := DefaultQueryDurationSeconds
-			if graph.DurationSeconds > 0 {
-				duration = graph.DurationSeconds
-			}
-
-			matrix, err := prometheusQueryRange(prometheusAddress, query.Query, endTime-duration, endTime, duration/DefaultGraphStepPoints)
-			if err != nil {
-				logger.Error(errors.Wrap(err, "failed to prometheus query range"))
-				continue // don't stop
-			}
+	for _, query := range graph.Queries {
+		queries = append(queries, query)
+	}
 
-			for _, sampleStream := range matrix {
-				data := []ValuePair{}
-				for _, v := range sampleStream.Values {
-					timestamp := v[0].(float64)
-					value, _ := strconv.ParseFloat(v[1].(string), 64)
-					valuePair := ValuePair{
-						Timestamp: timestamp,
-						Value:     value,
-					}
-					data = append(data, valuePair)
-				}
+	series := []Series{}
+	for _, query := range queries {
+		matrix, err := prometheusQueryRange(prometheusSettings, query.Query, start, end, step)
+		if err != nil {
+			logger.Error(errors.Wrap(err, "failed to prometheus query range"))
+			continue // don't stop
+		}
 
-				metric := []Metric{}
-				for k, v := range sampleStream.Metric {
-					m := Metric{
-						Name:  k,
-						Value: v,
-					}
-					metric = append(metric, m)
+		for _, sampleStream := range matrix {
+			data := []ValuePair{}
+			for _, v := range sampleStream.Values {
+				timestamp := v[0].(float64)
+				value, _ := strconv.ParseFloat(v[1].(string), 64)
+				valuePair := ValuePair{
+					Timestamp: timestamp,
+					Value:     value,
 				}
+				data = append(data, valuePair)
+			}
 
-				s := Series{
-					LegendTemplate: query.Legend,
-					Metric:         metric,
-					Data:           data,
+			metric := []Metric{}
+			for k, v := range sampleStream.Metric {
+				m := Metric{
+					Name:  k,
+					Value: v,
 				}
-				series = append(series, s)
+				metric = append(metric, m)
 			}
-		}
 
-		chart := MetricChart{
-			Title:        graph.Title,
-			TickFormat:   graph.YAxisFormat,
-			TickTemplate: graph.YAxisTemplate,
-			Series:       series,
+			s := Series{
+				LegendTemplate: query.Legend,
+				Metric:         metric,
+				Data:           data,
+			}
+			series = append(series, s)
 		}
-		charts = append(charts, chart)
 	}
 
-	return charts, nil
+	return MetricChart{
+		Title:        graph.Title,
+		TickFormat:   graph.YAxisFormat,
+		TickTemplate: graph.YAxisTemplate,
+		Series:       series,
+	}
 }
 
-func prometheusQueryRange(address string, query string, start uint, end uint, step uint) ([]SampleStream, error) {
-	host := fmt.Sprintf("%s/api/v1/query_range", address)
+func prometheusQueryRange(settings prometheustypes.Settings, query string, start uint, end uint, step uint) ([]SampleStream, error) {
+	host := fmt.Sprintf("%s/api/v1/query_range", settings.Address)
 
 	v := url.Values{}
 	v.Set("query", query)
@@ -197,6 +245,7 @@ func prometheusQueryRange(address string, query string, start uint, end uint, st
 		return nil, errors.Wrap(err, "failed to create request")
 	}
 	req.Header.Add("Content-Type", "application/json")
+	prometheus.SetRequestAuth(req, settings.Auth)
 
 	resp, err := http.DefaultClient.Do(req)
 	if err != nil {
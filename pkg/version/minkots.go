@@ -0,0 +1,47 @@
+package version
+
+import (
+	"fmt"
+
+	"github.com/pkg/errors"
+	"github.com/replicatedhq/kots/pkg/buildversion"
+)
+
+// KotsUpgradeRequiredError is returned when a version cannot be deployed until the admin console is upgraded
+type KotsUpgradeRequiredError struct {
+	VersionLabel   string
+	MinKotsVersion string
+	KotsVersion    string
+}
+
+func (e KotsUpgradeRequiredError) Error() string {
+	return fmt.Sprintf("Version %s requires KOTS %s or later, but the admin console is running %s. Upgrade the admin console first.", e.VersionLabel, e.MinKotsVersion, e.KotsVersion)
+}
+
+// CheckMinKotsVersion returns a KotsUpgradeRequiredError if the release of the app version
+// declares a minimum kots version that is newer than this admin console
+func CheckMinKotsVersion(appID string, sequence int64) error {
+	kotsKinds, err := GetKotsKinds(appID, sequence)
+	if err != nil {
+		return errors.Wrap(err, "failed to get kots kinds")
+	}
+
+	minKotsVersion := kotsKinds.KotsApplication.Spec.MinKotsVersion
+	if minKotsVersion == "" {
+		return nil
+	}
+
+	isAtLeast, err := buildversion.IsAtLeast(minKotsVersion)
+	if err != nil {
+		return errors.Wrap(err, "failed to compare kots version")
+	}
+	if isAtLeast {
+		return nil
+	}
+
+	return KotsUpgradeRequiredError{
+		VersionLabel:   kotsKinds.Installation.Spec.VersionLabel,
+		MinKotsVersion: minKotsVersion,
+		KotsVersion:    buildversion.Version(),
+	}
+}
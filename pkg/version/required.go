@@ -0,0 +1,33 @@
+package version
+
+import (
+	"github.com/pkg/errors"
+	downstreamtypes "github.com/replicatedhq/kots/pkg/api/downstream/types"
+	"github.com/replicatedhq/kots/pkg/store"
+)
+
+// GetSkippedRequiredVersion returns the oldest required version that has never been deployed
+// and would be skipped by deploying the given sequence, or nil if there is none
+func GetSkippedRequiredVersion(appID string, clusterID string, sequence int64) (*downstreamtypes.DownstreamVersion, error) {
+	pendingVersions, err := store.GetStore().GetPendingVersions(appID, clusterID)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get pending versions")
+	}
+
+	return FindSkippedRequiredVersion(pendingVersions, sequence), nil
+}
+
+// FindSkippedRequiredVersion returns the oldest of the pending versions that is required, has never been deployed,
+// and is older than the given sequence
+func FindSkippedRequiredVersion(pendingVersions []downstreamtypes.DownstreamVersion, sequence int64) *downstreamtypes.DownstreamVersion {
+	var skipped *downstreamtypes.DownstreamVersion
+	for i, v := range pendingVersions {
+		if !v.IsRequired || v.DeployedAt != nil || v.Sequence >= sequence {
+			continue
+		}
+		if skipped == nil || v.Sequence < skipped.Sequence {
+			skipped = &pendingVersions[i]
+		}
+	}
+	return skipped
+}
@@ -0,0 +1,57 @@
+package version
+
+import (
+	"testing"
+	"time"
+
+	downstreamtypes "github.com/replicatedhq/kots/pkg/api/downstream/types"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_FindSkippedRequiredVersion(t *testing.T) {
+	deployedAt := time.Now()
+
+	// pending versions are sorted newest first
+	pendingVersions := []downstreamtypes.DownstreamVersion{
+		{Sequence: 5},
+		{Sequence: 4, IsRequired: true},
+		{Sequence: 3},
+		{Sequence: 2, IsRequired: true},
+		{Sequence: 1, IsRequired: true, DeployedAt: &deployedAt},
+	}
+
+	tests := []struct {
+		name         string
+		sequence     int64
+		wantSequence int64
+		wantNil      bool
+	}{
+		{
+			name:         "skipping two required versions returns the oldest",
+			sequence:     5,
+			wantSequence: 2,
+		},
+		{
+			name:         "skipping one required version",
+			sequence:     3,
+			wantSequence: 2,
+		},
+		{
+			name:     "required versions that were deployed before can be skipped",
+			sequence: 2,
+			wantNil:  true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			skipped := FindSkippedRequiredVersion(pendingVersions, test.sequence)
+			if test.wantNil {
+				require.Nil(t, skipped)
+				return
+			}
+			require.NotNil(t, skipped)
+			require.Equal(t, test.wantSequence, skipped.Sequence)
+		})
+	}
+}
@@ -10,6 +10,8 @@ import (
 	"github.com/pkg/errors"
 	kotsv1beta1 "github.com/replicatedhq/kots/kotskinds/apis/kots/v1beta1"
 	"github.com/replicatedhq/kots/pkg/api/version/types"
+	deployhistorytypes "github.com/replicatedhq/kots/pkg/deployhistory/types"
+	"github.com/replicatedhq/kots/pkg/freeze"
 	"github.com/replicatedhq/kots/pkg/gitops"
 	"github.com/replicatedhq/kots/pkg/k8sutil"
 	"github.com/replicatedhq/kots/pkg/logger"
@@ -91,8 +93,12 @@ func GetVersions(appID string) ([]types.AppVersion, error) {
 	return versions, nil
 }
 
-// DeployVersion deploys the version for the given sequence
-func DeployVersion(appID string, sequence int64) error {
+// DeployVersion deploys the version for the given sequence and records the deployer in the deploy history
+func DeployVersion(appID string, sequence int64, deployer deployhistorytypes.Deployer) error {
+	if err := freeze.CheckDeploy(appID); err != nil {
+		return err
+	}
+
 	db := persistence.MustGetPGSession()
 
 	tx, err := db.Begin()
@@ -107,12 +113,56 @@ func DeployVersion(appID string, sequence int64) error {
 		return errors.Wrap(err, "failed to update app downstream current sequence")
 	}
 
+	now := time.Now()
 	query = `update app_downstream_version set status = 'deployed', applied_at = $3 where sequence = $1 and app_id = $2`
-	_, err = tx.Exec(query, sequence, appID, time.Now())
+	_, err = tx.Exec(query, sequence, appID, now)
 	if err != nil {
 		return errors.Wrap(err, "failed to update app downstream version status")
 	}
 
+	if err := recordDeployEvents(tx, appID, "", sequence, "", deployer, now); err != nil {
+		return errors.Wrap(err, "failed to record deploy events")
+	}
+
+	if err := tx.Commit(); err != nil {
+		return errors.Wrap(err, "failed to commit")
+	}
+
+	return nil
+}
+
+// DeployVersionToDownstream deploys the sequence to a single downstream of the app, the other downstreams keep
+// their current sequence. The version is deploying on the downstream until the operator reports the deploy result.
+func DeployVersionToDownstream(appID string, clusterID string, sequence int64, deployer deployhistorytypes.Deployer) error {
+	if err := freeze.CheckDeploy(appID); err != nil {
+		return err
+	}
+
+	db := persistence.MustGetPGSession()
+
+	tx, err := db.Begin()
+	if err != nil {
+		return errors.Wrap(err, "failed to begin")
+	}
+	defer tx.Rollback()
+
+	query := `update app_downstream set current_sequence = $1 where app_id = $2 and cluster_id = $3`
+	_, err = tx.Exec(query, sequence, appID, clusterID)
+	if err != nil {
+		return errors.Wrap(err, "failed to update app downstream current sequence")
+	}
+
+	now := time.Now()
+	query = `update app_downstream_version set status = 'deploying', applied_at = $4 where sequence = $1 and app_id = $2 and cluster_id = $3`
+	_, err = tx.Exec(query, sequence, appID, clusterID, now)
+	if err != nil {
+		return errors.Wrap(err, "failed to update app downstream version status")
+	}
+
+	if err := recordDeployEvents(tx, appID, clusterID, sequence, "", deployer, now); err != nil {
+		return errors.Wrap(err, "failed to record deploy events")
+	}
+
 	if err := tx.Commit(); err != nil {
 		return errors.Wrap(err, "failed to commit")
 	}
@@ -0,0 +1,318 @@
+package versionskew
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+
+	semver "github.com/Masterminds/semver/v3"
+	"github.com/pkg/errors"
+	"github.com/replicatedhq/kots/pkg/buildversion"
+	"github.com/replicatedhq/kots/pkg/logger"
+)
+
+type Skew string
+
+const (
+	// SkewNone is the same minor version
+	SkewNone Skew = "none"
+	// SkewMinor is one minor version apart, the requests of the cli are translated
+	SkewMinor Skew = "minor"
+	// SkewIncompatible is a different major version or more than one minor version apart
+	SkewIncompatible Skew = "incompatible"
+	// SkewUnknown is a development build or a kotsadm that does not report its version
+	SkewUnknown Skew = "unknown"
+)
+
+// Compare returns the skew between the versions of the cli and kotsadm
+func Compare(cliVersion string, serverVersion string) Skew {
+	cli, ok := parseVersion(cliVersion)
+	if !ok {
+		return SkewUnknown
+	}
+	server, ok := parseVersion(serverVersion)
+	if !ok {
+		return SkewUnknown
+	}
+
+	if cli.Major() != server.Major() {
+		return SkewIncompatible
+	}
+	switch diff := int64(cli.Minor()) - int64(server.Minor()); {
+	case diff == 0:
+		return SkewNone
+	case diff == 1 || diff == -1:
+		return SkewMinor
+	}
+	return SkewIncompatible
+}
+
+// IsReleaseVersion returns false for development builds and versions that do not parse
+func IsReleaseVersion(version string) bool {
+	_, ok := parseVersion(version)
+	return ok
+}
+
+// parseVersion returns false for versions that cannot be parsed and for development builds, which are "v0.0.0-unknown"
+func parseVersion(version string) (*semver.Version, bool) {
+	v, err := semver.NewVersion(version)
+	if err != nil {
+		return nil, false
+	}
+	if v.Major() == 0 && v.Minor() == 0 && v.Patch() == 0 {
+		return nil, false
+	}
+	return v, true
+}
+
+// GetServerVersion returns the version that kotsadm reports in its healthz response. Versions of kotsadm before the
+// version was reported return "test".
+func GetServerVersion(endpoint string) (string, error) {
+	resp, err := http.Get(fmt.Sprintf("%s/healthz", endpoint))
+	if err != nil {
+		return "", errors.Wrap(err, "failed to execute request")
+	}
+	defer resp.Body.Close()
+
+	healthz := struct {
+		Version string `json:"version"`
+	}{}
+	if err := json.NewDecoder(resp.Body).Decode(&healthz); err != nil {
+		return "", errors.Wrap(err, "failed to decode healthz response")
+	}
+
+	return healthz.Version, nil
+}
+
+// Translation is a route that kotsadm moved. Paths can have "{name}" segments and a query with "{name}" or literal
+// values.
+type Translation struct {
+	Method  string
+	OldPath string
+	NewPath string
+}
+
+// translations are the routes that kotsadm moved. kotsadm keeps serving the old path for older clis, and a request for
+// the new path is retried on the old path if kotsadm is too old to have it.
+var translations = []Translation{
+	{Method: "GET", OldPath: "/api/v1/download?slug={appSlug}&decryptPasswordValues=true", NewPath: "/api/v1/app/{appSlug}/download/decrypted"},
+	{Method: "GET", OldPath: "/api/v1/download?slug={appSlug}", NewPath: "/api/v1/app/{appSlug}/download"},
+}
+
+// NewClient compares the version of the cli with the version of kotsadm at the endpoint. It returns a client for the
+// requests of the cli to kotsadm, and an error if the versions are too far apart for the requests to be translated.
+func NewClient(endpoint string, log *logger.CLILogger) (*http.Client, error) {
+	cliVersion := buildversion.Version()
+
+	serverVersion, err := GetServerVersion(endpoint)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Unable to get the version of the Admin Console: %v\n", err)
+	}
+
+	switch Compare(cliVersion, serverVersion) {
+	case SkewUnknown:
+		log.Debug("Skipping the version check of kots %s and Admin Console %s", cliVersion, serverVersion)
+	case SkewIncompatible:
+		return nil, errors.Errorf("kots %s is not compatible with Admin Console %s, use kots %s to manage this Admin Console or upgrade it with kots admin-console upgrade", cliVersion, serverVersion, serverVersion)
+	case SkewMinor:
+		// warnings go to stderr so that they do not break json output
+		fmt.Fprintf(os.Stderr, "Warning: kots %s is one minor version apart from Admin Console %s, use kots %s to avoid unsupported commands\n", cliVersion, serverVersion, serverVersion)
+	}
+
+	// the default client has the request logging transport of --verbose
+	base := http.DefaultClient.Transport
+	if base == nil {
+		base = http.DefaultTransport
+	}
+
+	return &http.Client{Transport: NewTransport(base, cliVersion, serverVersion)}, nil
+}
+
+// Transport retries the requests of the cli for routes that kotsadm does not have on the other path of a translation,
+// and turns requests for routes that kotsadm does not have into a clear error instead of a 404
+type Transport struct {
+	Base          http.RoundTripper
+	CLIVersion    string
+	ServerVersion string
+
+	translations []Translation
+}
+
+func NewTransport(base http.RoundTripper, cliVersion string, serverVersion string) *Transport {
+	return &Transport{
+		Base:          base,
+		CLIVersion:    cliVersion,
+		ServerVersion: serverVersion,
+		translations:  translations,
+	}
+}
+
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, missing, err := t.roundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	if !missing {
+		return resp, nil
+	}
+
+	if translated, ok := t.translate(req.Method, req.URL); ok {
+		retry := req.Clone(req.Context())
+		retry.URL = translated
+		if req.Body != nil && req.Body != http.NoBody {
+			if req.GetBody == nil {
+				return nil, errors.Errorf("%s %s is not supported by kotsadm %s and the request cannot be retried on %s", req.Method, req.URL.Path, t.ServerVersion, translated.Path)
+			}
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, errors.Wrap(err, "failed to get request body")
+			}
+			retry.Body = body
+		}
+
+		resp, missing, err = t.roundTrip(retry)
+		if err != nil {
+			return nil, err
+		}
+		if !missing {
+			return resp, nil
+		}
+	}
+
+	return nil, errors.Errorf("%s %s is not supported by kotsadm %s, use kots cli %s to manage this admin console or upgrade it", req.Method, req.URL.Path, t.ServerVersion, t.ServerVersion)
+}
+
+// roundTrip returns true if kotsadm does not have the route of the request. Handlers return json errors for missing
+// resources, the router returns plain text or an empty body for missing routes.
+func (t *Transport) roundTrip(req *http.Request) (*http.Response, bool, error) {
+	resp, err := t.Base.RoundTrip(req)
+	if err != nil {
+		return nil, false, err
+	}
+
+	if resp.StatusCode != http.StatusNotFound && resp.StatusCode != http.StatusMethodNotAllowed {
+		return resp, false, nil
+	}
+
+	body, err := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, false, errors.Wrap(err, "failed to read response body")
+	}
+	switch strings.TrimSpace(string(body)) {
+	case "", "404 page not found":
+		return nil, true, nil
+	}
+	resp.Body = ioutil.NopCloser(bytes.NewReader(body))
+
+	return resp, false, nil
+}
+
+// translate returns the url of the request on the other path of a translation that matches it
+func (t *Transport) translate(method string, u *url.URL) (*url.URL, bool) {
+	for _, translation := range t.translations {
+		if translation.Method != method {
+			continue
+		}
+		if vars, ok := matchURL(translation.NewPath, u); ok {
+			return fillURL(translation.NewPath, translation.OldPath, vars, u), true
+		}
+		if vars, ok := matchURL(translation.OldPath, u); ok {
+			return fillURL(translation.OldPath, translation.NewPath, vars, u), true
+		}
+	}
+
+	return nil, false
+}
+
+// matchURL returns the values of the "{name}" segments and query values of the pattern if the url matches it
+func matchURL(pattern string, u *url.URL) (map[string]string, bool) {
+	patternPath, patternQuery := splitPattern(pattern)
+
+	patternParts := strings.Split(strings.Trim(patternPath, "/"), "/")
+	pathParts := strings.Split(strings.Trim(u.Path, "/"), "/")
+	if len(patternParts) != len(pathParts) {
+		return nil, false
+	}
+
+	vars := map[string]string{}
+	for i, part := range patternParts {
+		if name, ok := varName(part); ok {
+			vars[name] = pathParts[i]
+			continue
+		}
+		if part != pathParts[i] {
+			return nil, false
+		}
+	}
+
+	query := u.Query()
+	for key, values := range patternQuery {
+		if query.Get(key) == "" {
+			return nil, false
+		}
+		name, ok := varName(values[0])
+		if !ok {
+			if query.Get(key) != values[0] {
+				return nil, false
+			}
+			continue
+		}
+		vars[name] = query.Get(key)
+	}
+
+	return vars, true
+}
+
+// fillURL returns the url matched by the from pattern with the path and query of the to pattern. Query values of the
+// url that are not in the from pattern are kept.
+func fillURL(from string, to string, vars map[string]string, u *url.URL) *url.URL {
+	_, fromQuery := splitPattern(from)
+	toPath, toQuery := splitPattern(to)
+
+	parts := strings.Split(toPath, "/")
+	for i, part := range parts {
+		if name, ok := varName(part); ok {
+			parts[i] = vars[name]
+		}
+	}
+
+	query := u.Query()
+	for key := range fromQuery {
+		query.Del(key)
+	}
+	for key, values := range toQuery {
+		value := values[0]
+		if name, ok := varName(value); ok {
+			value = vars[name]
+		}
+		query.Set(key, value)
+	}
+
+	filled := *u
+	filled.Path = strings.Join(parts, "/")
+	filled.RawPath = ""
+	filled.RawQuery = query.Encode()
+	return &filled
+}
+
+func splitPattern(pattern string) (string, url.Values) {
+	parts := strings.SplitN(pattern, "?", 2)
+	if len(parts) == 1 {
+		return parts[0], url.Values{}
+	}
+	query, _ := url.ParseQuery(parts[1])
+	return parts[0], query
+}
+
+func varName(part string) (string, bool) {
+	if strings.HasPrefix(part, "{") && strings.HasSuffix(part, "}") {
+		return strings.Trim(part, "{}"), true
+	}
+	return "", false
+}
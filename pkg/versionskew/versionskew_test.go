@@ -0,0 +1,140 @@
+package versionskew
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Compare(t *testing.T) {
+	tests := []struct {
+		cli    string
+		server string
+		want   Skew
+	}{
+		{cli: "v1.50.1", server: "v1.50.0", want: SkewNone},
+		{cli: "v1.51.0", server: "v1.50.2", want: SkewMinor},
+		{cli: "v1.49.0", server: "v1.50.0", want: SkewMinor},
+		{cli: "v1.52.0", server: "v1.50.0", want: SkewIncompatible},
+		{cli: "v2.0.0", server: "v1.50.0", want: SkewIncompatible},
+		{cli: "v0.0.0-unknown", server: "v1.50.0", want: SkewUnknown},
+		{cli: "v1.50.0", server: "", want: SkewUnknown},
+	}
+	for _, test := range tests {
+		t.Run(fmt.Sprintf("%s/%s", test.cli, test.server), func(t *testing.T) {
+			require.Equal(t, test.want, Compare(test.cli, test.server))
+		})
+	}
+}
+
+func Test_translate(t *testing.T) {
+	tests := []struct {
+		name   string
+		url    string
+		want   string
+		wantOK bool
+	}{
+		{
+			name:   "new path",
+			url:    "http://localhost:3000/api/v1/app/my-app/download?sequence=2",
+			want:   "http://localhost:3000/api/v1/download?sequence=2&slug=my-app",
+			wantOK: true,
+		},
+		{
+			name:   "old path",
+			url:    "http://localhost:3000/api/v1/download?slug=my-app&sequence=2",
+			want:   "http://localhost:3000/api/v1/app/my-app/download?sequence=2",
+			wantOK: true,
+		},
+		{
+			name:   "new path with a literal query value",
+			url:    "http://localhost:3000/api/v1/app/my-app/download/decrypted",
+			want:   "http://localhost:3000/api/v1/download?decryptPasswordValues=true&slug=my-app",
+			wantOK: true,
+		},
+		{
+			name:   "old path with a literal query value",
+			url:    "http://localhost:3000/api/v1/download?slug=my-app&decryptPasswordValues=true",
+			want:   "http://localhost:3000/api/v1/app/my-app/download/decrypted",
+			wantOK: true,
+		},
+		{
+			name: "old path without the query value",
+			url:  "http://localhost:3000/api/v1/download",
+		},
+		{
+			name: "other route",
+			url:  "http://localhost:3000/api/v1/app/my-app/other",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			req := require.New(t)
+
+			u, err := url.Parse(test.url)
+			req.NoError(err)
+
+			transport := NewTransport(http.DefaultTransport, "v1.51.0", "v1.50.0")
+			got, ok := transport.translate("GET", u)
+			req.Equal(test.wantOK, ok)
+			if test.wantOK {
+				req.Equal(test.want, got.String())
+			}
+		})
+	}
+}
+
+func Test_TransportMissingRoute(t *testing.T) {
+	req := require.New(t)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api/v1/app/missing" {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"error":"app not found"}`))
+			return
+		}
+		http.NotFound(w, r)
+	}))
+	defer server.Close()
+
+	client := &http.Client{Transport: NewTransport(http.DefaultTransport, "v1.51.0", "v1.50.0")}
+
+	resp, err := client.Get(server.URL + "/api/v1/app/missing")
+	req.NoError(err)
+	resp.Body.Close()
+	req.Equal(http.StatusNotFound, resp.StatusCode)
+
+	_, err = client.Get(server.URL + "/api/v1/new-route")
+	req.Error(err)
+	req.Contains(err.Error(), "is not supported by kotsadm v1.50.0")
+}
+
+func Test_TransportRetriesTranslatedRoute(t *testing.T) {
+	req := require.New(t)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api/v1/download" && r.URL.Query().Get("slug") == "my-app" {
+			w.Write([]byte("archive"))
+			return
+		}
+		http.Error(w, "", http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	client := &http.Client{Transport: NewTransport(http.DefaultTransport, "v1.51.0", "v1.50.0")}
+
+	resp, err := client.Get(server.URL + "/api/v1/app/my-app/download")
+	req.NoError(err)
+	defer resp.Body.Close()
+	req.Equal(http.StatusOK, resp.StatusCode)
+
+	body, err := ioutil.ReadAll(resp.Body)
+	req.NoError(err)
+	req.Equal("archive", string(body))
+}
@@ -7,6 +7,7 @@ import Modal from "react-modal";
 import find from "lodash/find";
 import ConnectionTerminated from "./ConnectionTerminated";
 import GitOps from "././components/clusters/GitOps";
+import RemoteInstalls from "./components/clusters/RemoteInstalls";
 import PreflightResultPage from "./components/PreflightResultPage";
 // import Redactors from "./components/redactors/Redactors";
 // import EditRedactor from "./components/redactors/EditRedactor";
@@ -208,7 +209,7 @@ class Root extends Component {
         }
 
         this.setState({
-          appLogo: data.iconUri,
+          appLogo: Utilities.getIconUri(data),
           selectedAppName: data.name,
           appSlugFromMetadata: parseUpstreamUri(data.upstreamUri),
           appNameSpace: data.namespace,
@@ -370,6 +371,7 @@ class Root extends Component {
                   <Route path="/unsupported" component={UnsupportedBrowser} />
                   <ProtectedRoute path="/cluster/manage" render={(props) => <ClusterNodes {...props} appName={this.state.selectedAppName} />} />
                   <ProtectedRoute path="/gitops" render={(props) => <GitOps {...props} appName={this.state.selectedAppName} />} />
+                  <ProtectedRoute path="/remote-installs" render={(props) => <RemoteInstalls {...props} />} />
                   <ProtectedRoute path="/access/:tab?" render={(props) => <Access {...props} appName={this.state.selectedAppName} isKurlEnabled={this.state.isKurlEnabled} isGeoaxisSupported={this.isGeoaxisSupported()} />} />
                   <ProtectedRoute
                     path={["/snapshots/:tab?"]}
@@ -10,7 +10,9 @@ class SecureAdminConsole extends React.Component {
     super(props);
 
     this.state = {
+      username: "",
       password: "",
+      useLDAP: false,
       loginErr: false,
       loginErrMessage: "",
       authLoading: false,
@@ -29,6 +31,10 @@ class SecureAdminConsole extends React.Component {
         window.localStorage.setItem("token", token);
         loggedIn = true;
 
+        if (data.csrfToken) {
+          Utilities.setCSRFToken(data.csrfToken);
+        }
+
         if (data.sessionRoles) {
           window.localStorage.setItem("session_roles", data.sessionRoles);
         }
@@ -103,6 +109,101 @@ class SecureAdminConsole extends React.Component {
     }
   }
 
+  loginWithLDAP = async () => {
+    if (!this.state.username) {
+      this.setState({
+        loginErr: true,
+        loginErrMessage: `Please provide your username`,
+      });
+      return;
+    }
+    if (!this.validatePassword()) {
+      return;
+    }
+
+    this.setState({ authLoading: true, loginErr: false, loginErrMessage: "" });
+    try {
+      const res = await fetch(`${window.env.API_ENDPOINT}/login/ldap`, {
+        headers: {
+          "Content-Type": "application/json",
+        },
+        method: "POST",
+        body: JSON.stringify({
+          username: this.state.username,
+          password: this.state.password,
+        })
+      });
+      if (res.status >= 400) {
+        const body = await res.json();
+        let msg = body.error;
+        if (!msg) {
+          msg = "There was an error logging in. Please try again.";
+        }
+        this.setState({
+          authLoading: false,
+          loginErr: true,
+          loginErrMessage: msg,
+        });
+        return;
+      }
+      this.completeLogin(await res.json());
+    } catch(err) {
+      console.log("Login failed:", err);
+      this.setState({
+        authLoading: false,
+        loginErr: true,
+        loginErrMessage: "There was an error logging in. Please try again",
+      });
+    }
+  }
+
+  // redeems the token of a temporary access link, the token is in the url fragment so that it is never sent to the server
+  loginWithAccessLink = async accessToken => {
+    window.history.replaceState(null, "", window.location.pathname + window.location.search);
+
+    this.setState({ authLoading: true, loginErr: false, loginErrMessage: "" });
+    try {
+      const res = await fetch(`${window.env.API_ENDPOINT}/login/temporary-access`, {
+        headers: {
+          "Content-Type": "application/json",
+        },
+        method: "POST",
+        body: JSON.stringify({
+          token: accessToken,
+        })
+      });
+      if (res.status >= 400) {
+        const body = await res.json();
+        let msg = body.error;
+        if (!msg) {
+          msg = "There was an error opening the access link. Please try again.";
+        }
+        this.setState({
+          authLoading: false,
+          loginErr: true,
+          loginErrMessage: msg,
+        });
+        return;
+      }
+      this.completeLogin(await res.json());
+    } catch(err) {
+      console.log("Access link login failed:", err);
+      this.setState({
+        authLoading: false,
+        loginErr: true,
+        loginErrMessage: "There was an error opening the access link. Please try again",
+      });
+    }
+  }
+
+  login = () => {
+    if (this.state.useLDAP) {
+      this.loginWithLDAP();
+    } else {
+      this.loginWithSharedPassword();
+    }
+  }
+
   loginWithIdentityProvider = async () => {
     try {
       this.setState({ loginErr: false, loginErrMessage: "" });
@@ -143,7 +244,7 @@ class SecureAdminConsole extends React.Component {
     if (enterKey) {
       e.preventDefault();
       e.stopPropagation();
-      this.loginWithSharedPassword();
+      this.login();
     }
   }
 
@@ -209,6 +310,13 @@ class SecureAdminConsole extends React.Component {
       return;
     }
 
+    const hashParams = new URLSearchParams(window.location.hash.replace(/^#/, ""));
+    const accessToken = hashParams.get("access");
+    if (accessToken) {
+      await this.loginWithAccessLink(accessToken);
+      return;
+    }
+
     const urlParams = new URLSearchParams(window.location.search);
     const encodedMessage = urlParams.get("message");
     if (encodedMessage) {
@@ -248,7 +356,9 @@ class SecureAdminConsole extends React.Component {
       fetchingMetadata,
     } = this.props;
     const {
+      username,
       password,
+      useLDAP,
       authLoading,
       loginErr,
       loginErrMessage,
@@ -308,17 +418,29 @@ class SecureAdminConsole extends React.Component {
             </div>
             <div className="flex-auto flex-column justifyContent--center">
               <p className="u-marginTop--10 u-marginTop--5 u-fontSize--large u-textAlign--center u-fontWeight--medium u-lineHeight--normal u-textColor--bodyCopy">
-                Enter the password to access the {appName} admin console.
+                {useLDAP ? `Enter your LDAP credentials to access the ${appName} admin console.` : `Enter the password to access the ${appName} admin console.`}
               </p>
               <div className="u-marginTop--20 flex-column">
                 {loginErr && <p className="u-fontSize--normal u-fontWeight--medium u-textColor--error u-lineHeight--normal u-marginBottom--20">{loginErrMessage}</p>}
                 <div>
+                  {useLDAP &&
+                    <div className="component-wrapper u-marginBottom--10">
+                      <input type="text" className="Input" placeholder="username" autoComplete="username" value={username} onChange={(e) => { this.setState({ username: e.target.value }) }}/>
+                    </div>
+                  }
                   <div className="component-wrapper">
                     <input type="password" className="Input" placeholder="password" autoComplete="current-password" value={password} onChange={(e) => { this.setState({ password: e.target.value }) }}/>
                   </div>
                   <div className="u-marginTop--20 flex">
-                    <button type="submit" className="btn primary" disabled={authLoading} onClick={this.loginWithSharedPassword}>{authLoading ? "Logging in" : "Log in"}</button>
+                    <button type="submit" className="btn primary" disabled={authLoading} onClick={this.login}>{authLoading ? "Logging in" : "Log in"}</button>
                   </div>
+                  {loginInfo?.ldapEnabled &&
+                    <div className="u-marginTop--20">
+                      <span className="replicated-link u-fontSize--normal" onClick={() => this.setState({ useLDAP: !useLDAP, loginErr: false, loginErrMessage: "" })}>
+                        {useLDAP ? "Log in with the shared password" : "Log in with LDAP"}
+                      </span>
+                    </div>
+                  }
                 </div>
               </div>
             </div>
@@ -309,6 +309,70 @@ class AppConfig extends Component {
     return foundItem;
   }
 
+  onConfigChange = groups => {
+    this.recomputeDerivedItems(groups);
+    this.handleConfigChange(groups);
+  }
+
+  // recomputeDerivedItems updates the items that are templated from the changed items right away, the full render of
+  // the config follows once the user stops typing
+  recomputeDerivedItems = groups => {
+    const changedItems = [];
+    map(groups, group => {
+      map(group.items, item => {
+        const oldItem = this.getItemInConfigGroups(this.state.configGroups, item.name);
+        if (oldItem && oldItem.value !== item.value) {
+          changedItems.push(item.name);
+        }
+      });
+    });
+    if (!changedItems.length) {
+      return;
+    }
+
+    const sequence = this.getSequence();
+    const slug = this.getSlug();
+
+    if (this.recomputeController) {
+      this.recomputeController.abort();
+    }
+    this.recomputeController = new AbortController();
+
+    fetch(`${window.env.API_ENDPOINT}/app/${slug}/config/recompute`, {
+      signal: this.recomputeController.signal,
+      headers: {
+        "Authorization": Utilities.getToken(),
+        "Content-Type": "application/json",
+        "Accept": "application/json",
+      },
+      method: "POST",
+      body: JSON.stringify({ "configGroups": groups, "sequence": sequence, "changedItems": changedItems }),
+    }).then(async (response) => {
+      if (!response.ok) {
+        // the full render reports the error
+        return;
+      }
+
+      const data = await response.json();
+      if (!data.items?.length) {
+        return;
+      }
+      const recomputed = {};
+      data.items.forEach(item => {
+        recomputed[item.name] = item;
+      });
+      const newGroups = map(groups, group => ({
+        ...group,
+        items: map(group.items, item => recomputed[item.name] ? { ...item, value: recomputed[item.name].value, default: recomputed[item.name].default } : item),
+      }));
+      this.setState({ configGroups: newGroups });
+    }).catch((error) => {
+      if (error.name !== 'AbortError') {
+        console.log(error);
+      }
+    });
+  }
+
   handleConfigChange = groups => {
     const sequence = this.getSequence();
     const slug = this.getSlug();
@@ -495,7 +559,7 @@ class AppConfig extends Component {
             {this.renderConfigInfo(app)}
             <div className={classNames("ConfigOuterWrapper u-paddingTop--30", { "u-marginTop--20": fromLicenseFlow })}>
               <div className="ConfigInnerWrapper">
-                <AppConfigRenderer groups={configGroups} getData={this.handleConfigChange} readonly={this.isConfigReadOnly(app)} />
+                <AppConfigRenderer groups={configGroups} getData={this.onConfigChange} readonly={this.isConfigReadOnly(app)} />
               </div>
             </div>
             {savingConfig ?
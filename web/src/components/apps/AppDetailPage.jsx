@@ -45,13 +45,31 @@ class AppDetailPage extends Component {
       getAppJob: new Repeater(),
       gettingAppErrMsg: "",
       makingCurrentReleaseErrMsg: "",
+      makingCurrentReleaseNeedsKotsUpgrade: false,
       makingCurrentRelease: false,
       displayErrorModal: false,
       isVeleroInstalled: false,
-      redeployVersionErrMsg: ""
+      redeployVersionErrMsg: "",
+      activateVersionErrMsg: "",
+      labelFilter: ""
     }
   }
 
+  // matchesLabelFilter supports comma separated "key=value" and "key" terms, a subset of the label selectors of the api
+  matchesLabelFilter = (app) => {
+    const labels = app.labels || {};
+    return this.state.labelFilter.split(",").every(term => {
+      const [key, value] = term.split("=").map(s => s.trim());
+      if (!key) {
+        return true;
+      }
+      if (value === undefined) {
+        return key in labels;
+      }
+      return labels[key] === value;
+    });
+  }
+
   componentDidUpdate(_, lastState) {
     const { getThemeState, setThemeState, match, appsList, history } = this.props;
     const { app, loadingApp } = this.state;
@@ -71,12 +89,13 @@ class AppDetailPage extends Component {
 
     // Handle updating the theme state when switching apps.
     const currentApp = appsList?.find(w => w.slug === match.params.slug);
-    if (currentApp?.iconUri) {
+    const iconUri = Utilities.getIconUri(currentApp);
+    if (iconUri) {
       const { navbarLogo, ...rest } = getThemeState();
-      if (navbarLogo === null || navbarLogo !== currentApp.iconUri) {
+      if (navbarLogo === null || navbarLogo !== iconUri) {
         setThemeState({
           ...rest,
-          navbarLogo: currentApp.iconUri
+          navbarLogo: iconUri
         });
       }
     }
@@ -102,7 +121,7 @@ class AppDetailPage extends Component {
 
   makeCurrentRelease = async (upstreamSlug, version, isSkipPreflights, continueWithFailedPreflights = false) => {
     try {
-      this.setState({ makingCurrentReleaseErrMsg: "" });
+      this.setState({ makingCurrentReleaseErrMsg: "", makingCurrentReleaseNeedsKotsUpgrade: false });
 
       const res = await fetch(`${window.env.API_ENDPOINT}/app/${upstreamSlug}/sequence/${version.sequence}/deploy`, {
         headers: {
@@ -119,6 +138,12 @@ class AppDetailPage extends Component {
       if (res.ok && res.status === 204) {
         this.setState({ makingCurrentReleaseErrMsg: "" });
         this.refetchData();
+      } else if (res.status === 409) {
+        const response = await res.json();
+        this.setState({
+          makingCurrentReleaseErrMsg: `Unable to deploy release ${version.versionLabel}, sequence ${version.sequence}: ${response.error}`,
+          makingCurrentReleaseNeedsKotsUpgrade: response.code === "kots_upgrade_required",
+        });
       } else {
         this.setState({
           makingCurrentReleaseErrMsg: `Unable to deploy release ${version.versionLabel}, sequence ${version.sequence}: Unexpected status code: ${res.status}`,
@@ -159,6 +184,34 @@ class AppDetailPage extends Component {
     }
   }
 
+  activateVersion = async (upstreamSlug, version) => {
+    try {
+      this.setState({ activateVersionErrMsg: "" });
+
+      const res = await fetch(`${window.env.API_ENDPOINT}/app/${upstreamSlug}/sequence/${version.sequence}/activate`, {
+        headers: {
+          "Authorization": Utilities.getToken(),
+          "Content-Type": "application/json",
+        },
+        method: "POST",
+      });
+      if (res.ok && res.status === 204) {
+        this.setState({ activateVersionErrMsg: "" });
+        this.refetchData();
+      } else {
+        const body = await res.json().catch(() => ({}));
+        this.setState({
+          activateVersionErrMsg: `Unable to activate release ${version.versionLabel}, sequence ${version.sequence}: ${body.error || `Unexpected status code: ${res.status}`}`
+        });
+      }
+    } catch (err) {
+      console.log(err)
+      this.setState({
+        activateVersionErrMsg: err ? `Unable to activate release ${version.versionLabel}, sequence ${version.sequence}: ${err.message}` : "Something went wrong, please try again."
+      });
+    }
+  }
+
   toggleDisplayDownloadModal = () => {
     this.setState({ displayDownloadCommandModal: !this.state.displayDownloadCommandModal });
   }
@@ -273,9 +326,23 @@ class AppDetailPage extends Component {
       displayDownloadCommandModal,
       isBundleUploading,
       gettingAppErrMsg,
-      isVeleroInstalled
+      isVeleroInstalled,
+      labelFilter
     } = this.state;
 
+    const hasLabels = appsList?.some(item => item.labels && Object.keys(item.labels).length > 0);
+    const labelFilterNode = (
+      <div className="u-padding--15">
+        <input
+          type="text"
+          className="Input"
+          placeholder="Filter by label, e.g. environment=production"
+          value={labelFilter}
+          onChange={(e) => this.setState({ labelFilter: e.target.value })}
+        />
+      </div>
+    );
+
     const centeredLoader = (
       <div className="flex-column flex1 alignItems--center justifyContent--center">
         <Loader size="60" />
@@ -303,7 +370,7 @@ class AppDetailPage extends Component {
           condition={appsList?.length > 1}
           sidebar={(
             <SideBar
-              items={appsList?.map((item, idx) => {
+              items={(hasLabels ? [labelFilterNode] : []).concat(appsList?.filter(item => !item.name || this.matchesLabelFilter(item)).map((item, idx) => {
                 let sidebarItemNode;
                 if (item.name) {
                   const slugFromRoute = match.params.slug;
@@ -327,7 +394,7 @@ class AppDetailPage extends Component {
                   );
                 }
                 return sidebarItemNode;
-              })}
+              }))}
             />
           )}>
           <div className="flex-column flex1 u-width--full u-height--full u-overflow--auto">
@@ -365,6 +432,8 @@ class AppDetailPage extends Component {
                         match={this.props.match}
                         makeCurrentVersion={this.makeCurrentRelease}
                         makingCurrentVersionErrMsg={this.state.makingCurrentReleaseErrMsg}
+                        makingCurrentVersionNeedsKotsUpgrade={this.state.makingCurrentReleaseNeedsKotsUpgrade}
+                        appNameSpace={this.props.appNameSpace}
                         updateCallback={this.refetchData}
                         toggleIsBundleUploading={this.toggleIsBundleUploading}
                         isBundleUploading={isBundleUploading}
@@ -374,6 +443,8 @@ class AppDetailPage extends Component {
                         makingCurrentRelease={this.state.makingCurrentRelease}
                         redeployVersion={this.redeployVersion}
                         redeployVersionErrMsg={this.state.redeployVersionErrMsg}
+                        activateVersion={this.activateVersion}
+                        activateVersionErrMsg={this.state.activateVersionErrMsg}
                       />
                     } />
                     <Route exact path="/app/:slug/downstreams/:downstreamSlug/version-history/preflight/:sequence" render={props => <PreflightResultPage logo={app.iconUri} {...props} />} />
@@ -32,7 +32,11 @@ const SERVICES = [
   },
   {
     value: "bitbucket_server",
-    label: "Bitbucket Server",
+    label: "Bitbucket Server / Data Center",
+  },
+  {
+    value: "azure_devops",
+    label: "Azure DevOps",
   },
   // {
   //   value: "other",
@@ -72,6 +76,12 @@ class AppGitops extends Component {
       if (project && repo) {
         ownerRepo = `${project}/${repo}`;
       }
+    } else if (gitops?.provider === "azure_devops") {
+      // /organization/project/_git/repository
+      const [, organization, project, , repo] = parsed.path.split("/");
+      if (organization && project && repo) {
+        ownerRepo = `${organization}/${project}/${repo}`;
+      }
     } else {
       ownerRepo = parsed.path.slice(1);  // remove the "/"
     }
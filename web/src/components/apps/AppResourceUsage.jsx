@@ -0,0 +1,105 @@
+import React from "react";
+import { Utilities } from "../../utilities/utilities";
+
+const formatBytes = (bytes) => {
+  if (!bytes) {
+    return "0";
+  }
+  const units = ["B", "Ki", "Mi", "Gi", "Ti"];
+  let i = 0;
+  let value = bytes;
+  while (value >= 1024 && i < units.length - 1) {
+    value = value / 1024;
+    i++;
+  }
+  return `${Math.round(value * 10) / 10}${units[i]}`;
+};
+
+const formatMillis = (millis) => {
+  return `${millis}m`;
+};
+
+export default class AppResourceUsage extends React.Component {
+  state = {
+    usage: null,
+    errorMsg: "",
+  };
+
+  componentDidMount() {
+    this.getUsage();
+  }
+
+  componentDidUpdate(lastProps) {
+    if (this.props.appSlug !== lastProps.appSlug) {
+      this.getUsage();
+    }
+  }
+
+  getUsage = async () => {
+    try {
+      const res = await fetch(`${window.env.API_ENDPOINT}/app/${this.props.appSlug}/usage`, {
+        headers: {
+          "Authorization": Utilities.getToken(),
+          "Content-Type": "application/json",
+        },
+        method: "GET",
+      });
+      if (!res.ok) {
+        if (res.status === 401) {
+          Utilities.logoutUser();
+          return;
+        }
+        this.setState({ errorMsg: `Failed to get resource usage, unexpected status code ${res.status}` });
+        return;
+      }
+      const usage = await res.json();
+      this.setState({ usage, errorMsg: "" });
+    } catch (err) {
+      console.log(err);
+      this.setState({ errorMsg: err.message });
+    }
+  }
+
+  render() {
+    const { usage, errorMsg } = this.state;
+
+    if (errorMsg) {
+      return <p className="u-fontSize--small u-textColor--error u-marginTop--20">{errorMsg}</p>;
+    }
+    if (!usage) {
+      return null;
+    }
+
+    const { total } = usage;
+    return (
+      <div className="u-marginTop--30 flex-column">
+        <div className="flex alignItems--center">
+          <p className="u-fontSize--large u-fontWeight--bold u-textColor--primary flex1">Resource usage</p>
+          <span className="card-link" onClick={this.getUsage}>Refresh</span>
+        </div>
+        {!usage.metricsAvailable &&
+          <p className="u-fontSize--small u-textColor--bodyCopy u-marginTop--5">Usage is not available, metrics-server could not be reached: {usage.metricsError}</p>
+        }
+        <div className="flex u-marginTop--10 u-fontSize--normal u-textColor--bodyCopy">
+          <span className="flex1">CPU: {formatMillis(total.cpuUsageMillis)} used, {formatMillis(total.cpuRequestMillis)} requested, {formatMillis(total.cpuLimitMillis)} limit</span>
+          <span className="flex1">Memory: {formatBytes(total.memoryUsageBytes)} used, {formatBytes(total.memoryRequestBytes)} requested, {formatBytes(total.memoryLimitBytes)} limit</span>
+          <span className="flex1">Storage: {usage.volumeStatsAvailable ? `${formatBytes(total.storageUsedBytes)} used, ` : ""}{formatBytes(total.storageRequestBytes)} requested</span>
+        </div>
+        {usage.pods.map(pod => (
+          <div key={`${pod.namespace}/${pod.name}`} className="flex u-marginTop--5 u-fontSize--small u-textColor--bodyCopy">
+            <span className="flex1 u-fontWeight--bold">{pod.namespace}/{pod.name}</span>
+            <span className="flex1">{formatMillis(pod.cpuUsageMillis)} / {formatMillis(pod.cpuRequestMillis)} CPU</span>
+            <span className="flex1">{formatBytes(pod.memoryUsageBytes)} / {formatBytes(pod.memoryRequestBytes)} memory</span>
+          </div>
+        ))}
+        {usage.volumes.map(volume => (
+          <div key={`${volume.namespace}/${volume.name}`} className="flex u-marginTop--5 u-fontSize--small u-textColor--bodyCopy">
+            <span className="flex1 u-fontWeight--bold">{volume.namespace}/{volume.name}</span>
+            <span className="flex1">{formatBytes(volume.usedBytes)} / {formatBytes(volume.capacityBytes)} used</span>
+            <span className="flex1">{volume.storageClass}</span>
+          </div>
+        ))}
+      </div>
+    );
+  }
+}
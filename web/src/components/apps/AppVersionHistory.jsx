@@ -71,7 +71,8 @@ class AppVersionHistory extends Component {
     displayErrorModal: false,
     displayConfirmDeploymentModal: false,
     confirmType: "",
-    isSkipPreflights: false
+    isSkipPreflights: false,
+    deployImpact: null
   }
 
   componentDidMount() {
@@ -348,12 +349,63 @@ class AppVersionHistory extends Component {
         versionToDeploy: version,
         confirmType: "deploy"
       });
+      this.fetchDeployImpact(version);
       return;
     } else { // force deploy is set to true so finalize the deployment
       this.finalizeDeployment(continueWithFailedPreflights);
     }
   }
 
+  fetchDeployImpact = async (version) => {
+    const { app } = this.props;
+    this.setState({ deployImpact: null });
+    try {
+      const res = await fetch(`${window.env.API_ENDPOINT}/app/${app.slug}/sequence/${version.sequence}/impact`, {
+        method: "GET",
+        headers: {
+          "Content-Type": "application/json",
+          "Authorization": Utilities.getToken(),
+        }
+      });
+      if (res.ok) {
+        const deployImpact = await res.json();
+        this.setState({ deployImpact });
+      }
+    } catch {
+      // no-op, the deploy can be confirmed without the impact
+    }
+  }
+
+  renderDeployImpact = (impact) => {
+    const resourceChanges = [
+      { title: "PVC", changes: impact.pvcChanges },
+      { title: "CRD", changes: impact.crdChanges },
+      { title: "RBAC", changes: impact.rbacChanges },
+    ];
+    return (
+      <div className="u-marginBottom--10">
+        <p className="u-fontSize--normal u-fontWeight--bold u-textColor--primary u-lineHeight--normal">Impact</p>
+        <p className="u-fontSize--small u-fontWeight--medium u-textColor--bodyCopy u-lineHeight--normal">
+          {impact.imagesAdded?.length || 0} image{impact.imagesAdded?.length !== 1 ? "s" : ""} added, {impact.imagesRemoved?.length || 0} removed
+        </p>
+        <p className="u-fontSize--small u-fontWeight--medium u-textColor--bodyCopy u-lineHeight--normal">
+          {impact.rollingWorkloads?.length || 0} workload{impact.rollingWorkloads?.length !== 1 ? "s" : ""} will roll, an estimated {impact.estimatedRestarts} pod restart{impact.estimatedRestarts !== 1 ? "s" : ""}
+        </p>
+        {impact.rollingWorkloads?.map(workload => (
+          <p key={`${workload.kind}/${workload.namespace}/${workload.name}`} className="u-fontSize--small u-textColor--bodyCopy u-lineHeight--normal u-marginLeft--10">{workload.kind} {workload.name} ({workload.pods} pod{workload.pods !== 1 ? "s" : ""})</p>
+        ))}
+        {resourceChanges.map(({ title, changes }) => changes?.length > 0 &&
+          <div key={title}>
+            <p className="u-fontSize--small u-fontWeight--medium u-textColor--warning u-lineHeight--normal">{changes.length} {title} change{changes.length !== 1 ? "s" : ""}</p>
+            {changes.map(change => (
+              <p key={`${change.kind}/${change.namespace}/${change.name}`} className="u-fontSize--small u-textColor--bodyCopy u-lineHeight--normal u-marginLeft--10">{change.kind} {change.name} {change.change}{change.detail ? ` (${change.detail})` : ""}</p>
+            ))}
+          </div>
+        )}
+      </div>
+    );
+  }
+
   finalizeDeployment = async (continueWithFailedPreflights) => {
     const { match, updateCallback } = this.props;
     const { versionToDeploy, isSkipPreflights } = this.state;
@@ -390,6 +442,16 @@ class AppVersionHistory extends Component {
     }
   }
 
+  activateVersion = async (version) => {
+    const { match, updateCallback } = this.props;
+    await this.props.activateVersion(match.params.slug, version);
+    await this.fetchKotsDownstreamHistory();
+
+    if (updateCallback && typeof updateCallback === "function") {
+      updateCallback();
+    }
+  }
+
   finalizeRedeployment = async () => {
     const { match, updateCallback } = this.props;
     const { versionToDeploy } = this.state;
@@ -767,7 +829,8 @@ class AppVersionHistory extends Component {
       match,
       isBundleUploading,
       makingCurrentVersionErrMsg,
-      redeployVersionErrMsg
+      redeployVersionErrMsg,
+      activateVersionErrMsg
     } = this.props;
 
     const {
@@ -915,6 +978,11 @@ class AppVersionHistory extends Component {
                   <div>
                     <p className="title">Failed to deploy version</p>
                     <p className="err">{makingCurrentVersionErrMsg}</p>
+                    {this.props.makingCurrentVersionNeedsKotsUpgrade &&
+                      <p className="err">
+                        Run <span className="u-fontWeight--bold">kubectl kots admin-console upgrade -n {this.props.appNameSpace}</span> or see <a href="https://kots.io/kotsadm/updating/updating-kotsadm/" target="_blank" rel="noopener noreferrer" className="replicated-link">updating the admin console</a>.
+                      </p>
+                    }
                   </div>
                 </div>}
               {redeployVersionErrMsg &&
@@ -926,6 +994,15 @@ class AppVersionHistory extends Component {
                   </div>
                 </div>
               }
+              {activateVersionErrMsg &&
+                <div className="ErrorWrapper flex justifyContent--center">
+                  <div className="icon redWarningIcon u-marginRight--10" />
+                  <div>
+                    <p className="title">Failed to activate version</p>
+                    <p className="err">{activateVersionErrMsg}</p>
+                  </div>
+                </div>
+              }
 
               <div className="TableDiff--Wrapper flex-column flex1">
                 <div className={`flex-column flex1 ${showDiffOverlay ? "u-visibility--hidden" : ""}`}>
@@ -960,6 +1037,7 @@ class AppVersionHistory extends Component {
                         handleViewLogs={this.handleViewLogs}
                         handleSelectReleasesToDiff={this.handleSelectReleasesToDiff}
                         redeployVersion={this.redeployVersion}
+                        activateVersion={this.activateVersion}
                       />
                     );
                   }) :
@@ -1076,6 +1154,7 @@ class AppVersionHistory extends Component {
           >
             <div className="Modal-body">
               <p className="u-fontSize--largest u-fontWeight--bold u-textColor--primary u-lineHeight--normal u-marginBottom--10">{this.state.confirmType === "rollback" ? "Rollback to" : this.state.confirmType === "redeploy" ? "Redeploy" : "Deploy"} {this.state.versionToDeploy?.versionLabel} (Sequence {this.state.versionToDeploy?.sequence})?</p>
+              {this.state.confirmType === "deploy" && this.state.deployImpact && this.renderDeployImpact(this.state.deployImpact)}
               <div className="flex u-paddingTop--10">
                 <button className="btn secondary blue" onClick={() => this.setState({ displayConfirmDeploymentModal: false, confirmType: "", versionToDeploy: null })}>Cancel</button>
                 <button className="u-marginLeft--10 btn primary" onClick={this.state.confirmType === "redeploy" ? this.finalizeRedeployment : () => this.finalizeDeployment(false)}>Yes, {this.state.confirmType === "rollback" ? "rollback" : this.state.confirmType === "redeploy" ? "redeploy" : "deploy"}</button>
@@ -1089,6 +1168,7 @@ class AppVersionHistory extends Component {
             isOpen={showUpdateCheckerModal}
             onRequestClose={this.hideUpdateCheckerModal}
             updateCheckerSpec={app.updateCheckerSpec}
+            deployPolicy={app.deployPolicy}
             appSlug={app.slug}
             gitopsEnabled={gitopsEnabled}
             onUpdateCheckerSpecSubmitted={() => {
@@ -225,6 +225,38 @@ function renderVersionStatus(version, app, match, viewLogs) {
   }
 }
 
+// renderActivation shows the activation of a version that is deployed in two phases, the deployed version can be
+// activated once its resources have been installed
+function renderActivation(version, app, activateVersion) {
+  if (!version.twoPhaseDeploy) {
+    return null;
+  }
+  const downstream = app.downstreams?.length && app.downstreams[0];
+  const isCurrentVersion = version.sequence === downstream?.currentVersion?.sequence;
+  const canActivate = isCurrentVersion && version.status === "deployed" &&
+    version.activationStatus !== "activating" && version.activationStatus !== "activated";
+
+  return (
+    <div className="flex alignItems--center justifyContent--flexEnd u-marginTop--10">
+      <span className={classNames("u-fontSize--small u-fontWeight--medium u-lineHeight--normal", {
+        "u-textColor--accent": version.activationStatus === "activated",
+        "u-textColor--bodyCopy": !version.activationStatus || version.activationStatus === "activating",
+        "u-textColor--error": version.activationStatus === "failed"
+      })} data-tip={version.activationStatusInfo || ""}>
+        {version.activationStatus === "activated" ?
+          "Activated" :
+          version.activationStatus === "activating" ?
+            "Activating" :
+            version.activationStatus === "failed" ?
+              "Activation failed" : "Installed, not activated"}
+      </span>
+      {canActivate &&
+        <button className="btn secondary blue u-marginLeft--10" onClick={() => activateVersion(version)}>Activate</button>
+      }
+    </div>
+  );
+}
+
 export default function AppVersionHistoryRow(props) {
   const { version, selectedDiffReleases, nothingToCommit,
     isChecked, isNew, showDownstreamReleaseNotes, renderSourceAndDiff, handleSelectReleasesToDiff,
@@ -242,8 +274,18 @@ export default function AppVersionHistoryRow(props) {
         <div className="flex alignItems--center">
           <p className="u-fontSize--large u-fontWeight--bold u-lineHeight--medium u-textColor--primary">{version.versionLabel || version.title}</p>
           <p className="u-fontSize--small u-fontWeight--medium u-lineHeight--normal u-textColor--secondary u-marginLeft--5" style={{ marginTop: "2px" }}>Sequence {version.sequence}</p>
+          {version.isRequired &&
+            <span className="u-fontSize--small u-fontWeight--bold u-lineHeight--normal u-textColor--warning u-marginLeft--10" style={{ marginTop: "2px" }} data-tip="This version must be deployed before any later version can be deployed">Required</span>
+          }
+        </div>
+        <div className="flex alignItems--center u-marginTop--10">
+          {version.labels?.map(label => (
+            <span key={label} className="u-fontSize--small u-fontWeight--bold u-lineHeight--normal u-textColor--secondary u-marginRight--10">{label}</span>
+          ))}
+          {version.notes &&
+            <p className="u-fontSize--small u-fontWeight--medium u-lineHeight--normal u-textColor--bodyCopy" style={{ whiteSpace: "pre-wrap" }}>{version.notes}</p>
+          }
         </div>
-        <div className="flex alignItems--center u-marginTop--10"></div>
         <div className="flex flex1 u-marginTop--15 alignItems--center">
           <p className="u-fontSize--small u-lineHeight--normal u-textColor--bodyCopy u-fontWeight--medium">Released <span className="u-fontWeight--bold">{version.upstreamReleasedAt ? Utilities.dateFormat(version.upstreamReleasedAt, "MMMM D, YYYY") : Utilities.dateFormat(version.createdOn, "MMMM D, YYYY")}</span></p>
           {version.releaseNotes ?
@@ -267,6 +309,7 @@ export default function AppVersionHistoryRow(props) {
             renderVersionAction(version, latestVersion, nothingToCommit && selectedDiffReleases, props.app, props.history, props.deployVersion)
           }
         </div>
+        {renderActivation(version, props.app, props.activateVersion)}
         <p className="u-fontSize--small u-lineHeight--normal u-textColor--bodyCopy u-fontWeight--medium u-marginTop--15">Deployed: <span className="u-fontWeight--bold">{version.deployedAt ? Utilities.dateFormat(version.deployedAt, "MMMM D, YYYY @ hh:mm a z") : "N/A"}</span></p>
       </div>
     </div>
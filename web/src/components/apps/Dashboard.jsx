@@ -6,11 +6,13 @@ import size from "lodash/size";
 import get from "lodash/get";
 import Loader from "../shared/Loader";
 import DashboardCard from "./DashboardCard";
+import AppResourceUsage from "./AppResourceUsage";
 import ConfigureGraphsModal from "../shared/modals/ConfigureGraphsModal";
 import UpdateCheckerModal from "@src/components/modals/UpdateCheckerModal";
 import SnapshotDifferencesModal from "@src/components/modals/SnapshotDifferencesModal";
 import Modal from "react-modal";
 import { Repeater } from "../../utilities/repeater";
+import { EventStream } from "../../utilities/events";
 import { Utilities } from "../../utilities/utilities";
 import { AirgapUploader } from "../../utilities/airgapUploader";
 
@@ -22,6 +24,9 @@ import Handlebars from "handlebars";
 import "../../scss/components/watches/Dashboard.scss";
 import "../../../node_modules/react-vis/dist/style";
 
+// while the event stream is connected, the dashboard and the update status are only polled this often, as a fallback
+const EVENTS_CONNECTED_POLL_MS = 30000;
+
 const COMMON_ERRORS = {
   "HTTP 401": "Registry credentials are invalid",
   "invalid username/password": "Registry credentials are invalid",
@@ -60,6 +65,7 @@ class Dashboard extends Component {
       prometheusAddress: "",
     },
     getAppDashboardJob: new Repeater(),
+    eventStream: null,
     gettingAppLicenseErrMsg: "",
     startSnapshotOptions: [
       { option: "partial", name: "Start a Partial snapshot" },
@@ -126,7 +132,7 @@ class Dashboard extends Component {
   setWatchState = (app) => {
     this.setState({
       appName: app.name,
-      iconUri: app.iconUri,
+      iconUri: Utilities.getIconUri(app),
       currentVersion: app.downstreams[0]?.currentVersion,
       downstream: app.downstreams[0],
       links: app.downstreams[0]?.links
@@ -174,8 +180,12 @@ class Dashboard extends Component {
       this.getAirgapConfig()
     }
 
-    this.state.updateChecker.start(this.updateStatus, 1000);
-    this.state.getAppDashboardJob.start(this.getAppDashboard, 2000);
+    const eventStream = new EventStream(this.onEvent);
+    eventStream.start();
+    this.setState({ eventStream });
+
+    this.state.updateChecker.start(this.pollUpdateStatus, 1000);
+    this.state.getAppDashboardJob.start(this.pollAppDashboard, 2000);
 
     if (app) {
       this.setWatchState(app);
@@ -186,6 +196,51 @@ class Dashboard extends Component {
   componentWillUnmount() {
     this.state.updateChecker.stop();
     this.state.getAppDashboardJob.stop();
+    this.state.eventStream?.stop();
+  }
+
+  isEventStreamConnected = () => {
+    return !!this.state.eventStream?.isConnected();
+  }
+
+  onEvent = (event) => {
+    const { app } = this.props;
+
+    switch (event.type) {
+      case "resync":
+        this.getAppDashboard().catch(() => {});
+        this.updateStatus().catch(() => {});
+        break;
+      case "appStatus":
+        if (event.appId === app?.id) {
+          this.getAppDashboard().catch(() => {});
+        }
+        break;
+      case "taskStatus":
+        if (event.data?.id === "update-download") {
+          this.updateStatus().catch(() => {});
+        }
+        break;
+      case "newVersion":
+        if (event.appId === app?.id && this.props.updateCallback) {
+          this.props.updateCallback();
+        }
+        break;
+    }
+  }
+
+  pollAppDashboard = () => {
+    if (this.isEventStreamConnected() && Date.now() - this.lastAppDashboardFetch < EVENTS_CONNECTED_POLL_MS) {
+      return Promise.resolve();
+    }
+    return this.getAppDashboard();
+  }
+
+  pollUpdateStatus = () => {
+    if (this.isEventStreamConnected() && Date.now() - this.lastUpdateStatusFetch < EVENTS_CONNECTED_POLL_MS) {
+      return Promise.resolve();
+    }
+    return this.updateStatus();
   }
 
   getAirgapConfig = async () => {
@@ -214,6 +269,7 @@ class Dashboard extends Component {
   }
 
   getAppDashboard = () => {
+    this.lastAppDashboardFetch = Date.now();
     return new Promise((resolve, reject) => {
       fetch(`${window.env.API_ENDPOINT}/app/${this.props.app?.slug}/cluster/${this.props.cluster?.id}/dashboard`, {
         headers: {
@@ -260,7 +316,7 @@ class Dashboard extends Component {
       method: "POST",
     })
       .then(async (res) => {
-        this.state.updateChecker.start(this.updateStatus, 1000);
+        this.state.updateChecker.start(this.pollUpdateStatus, 1000);
       })
       .catch((err) => {
         this.setState({ errorCheckingUpdate: true });
@@ -282,6 +338,7 @@ class Dashboard extends Component {
   updateStatus = () => {
     const { app } = this.props;
 
+    this.lastUpdateStatusFetch = Date.now();
     return new Promise((resolve, reject) => {
       fetch(`${window.env.API_ENDPOINT}/app/${app?.slug}/task/updatedownload`, {
         headers: {
@@ -359,7 +416,7 @@ class Dashboard extends Component {
   }
 
   onUploadComplete = () => {
-    this.state.updateChecker.start(this.updateStatus, 1000);
+    this.state.updateChecker.start(this.pollUpdateStatus, 1000);
     this.setState({
       uploadingAirgapFile: false,
       uploadProgress: 0,
@@ -714,6 +771,7 @@ class Dashboard extends Component {
                 />
               }
             </div>
+            <AppResourceUsage appSlug={app.slug} />
             <div className="u-marginTop--30 flex flex1">
               {this.state.dashboard?.prometheusAddress ?
                 <div>
@@ -793,6 +851,7 @@ class Dashboard extends Component {
             isOpen={this.state.showUpdateCheckerModal}
             onRequestClose={this.hideUpdateCheckerModal}
             updateCheckerSpec={app.updateCheckerSpec}
+            deployPolicy={app.deployPolicy}
             appSlug={app.slug}
             gitopsEnabled={downstream?.gitops?.enabled}
             onUpdateCheckerSpecSubmitted={() => {
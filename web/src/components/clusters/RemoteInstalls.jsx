@@ -0,0 +1,166 @@
+import * as React from "react";
+import Helmet from "react-helmet";
+import { withRouter } from "react-router-dom";
+import { Utilities } from "../../utilities/utilities";
+
+import "../../scss/components/watches/WatchedApps.scss";
+
+export class RemoteInstalls extends React.Component {
+  state = {
+    remoteInstalls: [],
+    loading: true,
+    name: "",
+    url: "",
+    token: "",
+    insecureSkipTLSVerify: false,
+    adding: false,
+    errorMsg: "",
+  };
+
+  componentDidMount() {
+    this.getRemoteInstalls();
+  }
+
+  getRemoteInstalls = async () => {
+    try {
+      const res = await fetch(`${window.env.API_ENDPOINT}/remote-installs`, {
+        headers: {
+          "Authorization": Utilities.getToken(),
+          "Content-Type": "application/json",
+        },
+        method: "GET",
+      });
+      if (!res.ok) {
+        if (res.status === 401) {
+          Utilities.logoutUser();
+          return;
+        }
+        console.log("failed to get remote installs, unexpected status code", res.status);
+        return;
+      }
+      const response = await res.json();
+      this.setState({ remoteInstalls: response.remoteInstalls, loading: false });
+    } catch (err) {
+      console.log(err);
+      this.setState({ loading: false });
+    }
+  }
+
+  addRemoteInstall = async () => {
+    const { name, url, token, insecureSkipTLSVerify } = this.state;
+    this.setState({ adding: true, errorMsg: "" });
+    try {
+      const res = await fetch(`${window.env.API_ENDPOINT}/remote-installs`, {
+        headers: {
+          "Authorization": Utilities.getToken(),
+          "Content-Type": "application/json",
+        },
+        method: "POST",
+        body: JSON.stringify({ name, url, token, insecureSkipTLSVerify }),
+      });
+      if (!res.ok) {
+        if (res.status === 401) {
+          Utilities.logoutUser();
+          return;
+        }
+        const response = await res.json();
+        this.setState({ adding: false, errorMsg: response.error || `Unexpected status code ${res.status}` });
+        return;
+      }
+      this.setState({ adding: false, name: "", url: "", token: "", insecureSkipTLSVerify: false });
+      this.getRemoteInstalls();
+    } catch (err) {
+      console.log(err);
+      this.setState({ adding: false, errorMsg: err.message });
+    }
+  }
+
+  updateRemoteInstall = async (remoteInstallId, path, method) => {
+    try {
+      const res = await fetch(`${window.env.API_ENDPOINT}/remote-install/${remoteInstallId}${path}`, {
+        headers: {
+          "Authorization": Utilities.getToken(),
+          "Content-Type": "application/json",
+        },
+        method,
+      });
+      if (!res.ok) {
+        if (res.status === 401) {
+          Utilities.logoutUser();
+          return;
+        }
+        console.log("failed to update remote install, unexpected status code", res.status);
+        return;
+      }
+      this.getRemoteInstalls();
+    } catch (err) {
+      console.log(err);
+    }
+  }
+
+  renderApps = (remoteInstall) => {
+    const apps = remoteInstall.lastReport?.apps || [];
+    if (!apps.length) {
+      return <p className="u-fontSize--small u-textColor--bodyCopy u-marginTop--10">No apps were reported</p>;
+    }
+    return apps.map(app => (
+      <div key={app.id} className="flex u-marginTop--10 u-fontSize--small u-textColor--bodyCopy">
+        <span className="flex1 u-fontWeight--bold">{app.name}</span>
+        <span className="flex1">{app.deployedVersion ? `${app.deployedVersion.versionLabel} (sequence ${app.deployedVersion.sequence})` : "No version deployed"}</span>
+        <span className="flex1">{app.pendingVersions} pending</span>
+        <span className="flex1">{app.state || "unknown"}</span>
+      </div>
+    ));
+  }
+
+  render() {
+    const { remoteInstalls, loading, name, url, token, insecureSkipTLSVerify, adding, errorMsg } = this.state;
+
+    return (
+      <div className="ClusterDashboard--wrapper container flex-column flex1 u-overflow--auto">
+        <Helmet>
+          <title>Remote installs</title>
+        </Helmet>
+        <div className="flex-column flex1 u-paddingBottom--20 u-paddingTop--30 u-marginTop--10">
+          <p className="u-fontSize--larger u-fontWeight--bold u-textColor--primary">Remote installs</p>
+          <p className="u-fontSize--normal u-textColor--bodyCopy u-marginTop--5">The apps of other admin consoles, as reported by their fleet report. Remote installs are read-only, manage their apps in their own admin console.</p>
+          {loading ?
+            <p className="u-fontSize--normal u-marginTop--20">Loading...</p>
+            :
+            remoteInstalls.map(remoteInstall => (
+              <div key={remoteInstall.id} className="u-marginTop--20 u-paddingBottom--20 u-borderBottom--gray">
+                <div className="flex alignItems--center">
+                  <p className="flex1 u-fontSize--large u-fontWeight--bold u-textColor--primary">{remoteInstall.name} <span className="u-fontSize--small u-fontWeight--normal u-textColor--bodyCopy">{remoteInstall.url}</span></p>
+                  <span className="replicated-link u-fontSize--small u-marginRight--10" onClick={() => this.updateRemoteInstall(remoteInstall.id, "/refresh", "POST")}>Refresh</span>
+                  <span className="replicated-link u-fontSize--small" onClick={() => this.updateRemoteInstall(remoteInstall.id, "", "DELETE")}>Remove</span>
+                </div>
+                <p className="u-fontSize--small u-textColor--bodyCopy u-marginTop--5">
+                  {remoteInstall.lastReportAt ? `Last reported ${Utilities.dateFormat(remoteInstall.lastReportAt, "MMMM D, YYYY @ hh:mm a z")}` : "Not reported yet"}
+                  {remoteInstall.lastReport && ` · kots ${remoteInstall.lastReport.kotsVersion} · kubernetes ${remoteInstall.lastReport.k8sVersion}`}
+                </p>
+                {remoteInstall.lastError && <p className="u-fontSize--small u-textColor--error u-marginTop--5">{remoteInstall.lastError}</p>}
+                {this.renderApps(remoteInstall)}
+              </div>
+            ))
+          }
+          <div className="u-marginTop--30">
+            <p className="u-fontSize--large u-fontWeight--bold u-textColor--primary">Register a remote install</p>
+            <div className="flex u-marginTop--10">
+              <input type="text" className="Input u-marginRight--10" placeholder="name" value={name} onChange={(e) => this.setState({ name: e.target.value })} />
+              <input type="text" className="Input u-marginRight--10" placeholder="https://admin-console.example.com:8800" value={url} onChange={(e) => this.setState({ url: e.target.value })} />
+              <input type="password" className="Input" placeholder="auth token" value={token} onChange={(e) => this.setState({ token: e.target.value })} />
+            </div>
+            <div className="flex alignItems--center u-marginTop--10">
+              <input type="checkbox" id="insecureSkipTLSVerify" checked={insecureSkipTLSVerify} onChange={(e) => this.setState({ insecureSkipTLSVerify: e.target.checked })} />
+              <label htmlFor="insecureSkipTLSVerify" className="u-fontSize--small u-textColor--bodyCopy u-marginLeft--5">Skip TLS verification for self-signed certificates</label>
+            </div>
+            {errorMsg && <p className="u-fontSize--small u-textColor--error u-marginTop--10">{errorMsg}</p>}
+            <button className="btn primary blue u-marginTop--10" disabled={adding || !name || !url || !token} onClick={this.addRemoteInstall}>{adding ? "Registering" : "Register"}</button>
+          </div>
+        </div>
+      </div>
+    );
+  }
+}
+
+export default withRouter(RemoteInstalls);
@@ -8,7 +8,7 @@ import ErrorModal from "../modals/ErrorModal";
 import { withRouter, Link } from "react-router-dom";
 import GitOpsFlowIllustration from "./GitOpsFlowIllustration";
 import GitOpsRepoDetails from "./GitOpsRepoDetails";
-import { getGitOpsUri, requiresHostname, Utilities } from "../../utilities/utilities";
+import { getGitOpsUri, requiresHostname, supportsCommitStatus, Utilities } from "../../utilities/utilities";
 
 import "../../scss/components/gitops/GitOpsDeploymentManager.scss";
 
@@ -50,7 +50,11 @@ const SERVICES = [
   },
   {
     value: "bitbucket_server",
-    label: "Bitbucket Server",
+    label: "Bitbucket Server / Data Center",
+  },
+  {
+    value: "azure_devops",
+    label: "Azure DevOps",
   },
   // {
   //   value: "other",
@@ -67,6 +71,7 @@ class GitOpsDeploymentManager extends React.Component {
     hostname: "",
     httpPort: "",
     sshPort: "",
+    accessToken: "",
     services: SERVICES,
     selectedService: SERVICES[0],
     providerError: null,
@@ -184,7 +189,7 @@ class GitOpsDeploymentManager extends React.Component {
     return !this.providerChanged() && isBitbucketServer && sshPort !== savedSshPort;
   }
 
-  getGitOpsInput = (provider, uri, branch, path, format, action, hostname, httpPort, sshPort) => {
+  getGitOpsInput = (provider, uri, branch, path, format, action, hostname, httpPort, sshPort, accessToken = "") => {
     let gitOpsInput = new Object();
     gitOpsInput.provider = provider;
     gitOpsInput.uri = uri;
@@ -203,6 +208,10 @@ class GitOpsDeploymentManager extends React.Component {
       gitOpsInput.sshPort = sshPort;
     }
 
+    if (supportsCommitStatus(provider) && accessToken) {
+      gitOpsInput.accessToken = accessToken;
+    }
+
     return gitOpsInput;
   }
 
@@ -227,7 +236,7 @@ class GitOpsDeploymentManager extends React.Component {
 
     const provider = selectedService.value;
     const repoUri = getGitOpsUri(provider, ownerRepo, hostname, httpPort);
-    const gitOpsInput = this.getGitOpsInput(provider, repoUri, branch, path, format, action, hostname, httpPort, sshPort);
+    const gitOpsInput = this.getGitOpsInput(provider, repoUri, branch, path, format, action, hostname, httpPort, sshPort, this.state.accessToken);
 
     try {
       if (this.state.gitops?.enabled && this.providerChanged()) {
@@ -244,7 +253,7 @@ class GitOpsDeploymentManager extends React.Component {
 
         this.props.history.push(`/app/${app.slug}/gitops`);
       } else {
-        this.setState({ step: "", finishingSetup: false });
+        this.setState({ step: "", finishingSetup: false, accessToken: "" });
         this.getAppsList();
         this.getGitops();
       }
@@ -482,6 +491,20 @@ class GitOpsDeploymentManager extends React.Component {
     );
   }
 
+  renderAccessToken = (provider, accessToken) => {
+    if (!supportsCommitStatus(provider)) {
+      return null;
+    }
+    const hasAccessToken = !this.providerChanged() && this.state.gitops?.hasAccessToken;
+    return (
+      <div className="flex flex1 flex-column">
+        <p className="u-fontSize--large u-textColor--primary u-fontWeight--bold u-lineHeight--normal">Access token <span className="u-fontSize--small u-textColor--bodyCopy">(optional)</span></p>
+        <p className="u-fontSize--normal u-textColor--bodyCopy u-fontWeight--medium u-lineHeight--normal u-marginBottom--10">Used to set a commit status on the commits made by the Admin Console.{hasAccessToken ? " Leave blank to keep the current token." : ""}</p>
+        <input type="password" className="Input" placeholder="access token" value={accessToken} onChange={(e) => this.setState({ accessToken: e.target.value })} />
+      </div>
+    );
+  }
+
   renderActiveStep = (step) => {
     const {
       hostname,
@@ -491,6 +514,7 @@ class GitOpsDeploymentManager extends React.Component {
       selectedService,
       providerError,
       finishingSetup,
+      accessToken,
     } = this.state;
 
     const provider = selectedService?.value;
@@ -524,6 +548,11 @@ class GitOpsDeploymentManager extends React.Component {
                   {this.renderSshPort(provider, sshPort)}
                 </div>
               }
+              {supportsCommitStatus(provider) &&
+                <div className="flex flex1 u-marginTop--30">
+                  {this.renderAccessToken(provider, accessToken)}
+                </div>
+              }
             </div>
             <div>
               <button
@@ -622,11 +651,11 @@ class GitOpsDeploymentManager extends React.Component {
   }
 
   dataChanged = () => {
-    return this.providerChanged() || this.hostnameChanged() || this.httpPortChanged() || this.sshPortChanged();
+    return this.providerChanged() || this.hostnameChanged() || this.httpPortChanged() || this.sshPortChanged() || this.state.accessToken !== "";
   }
 
   renderConfiguredGitOps = () => {
-    const { services, selectedService, hostname, httpPort, sshPort, providerError, finishingSetup } = this.state;
+    const { services, selectedService, hostname, httpPort, sshPort, accessToken, providerError, finishingSetup } = this.state;
     const provider = selectedService?.value;
     const isBitbucketServer = provider === "bitbucket_server";
     const dataChanged = this.dataChanged();
@@ -644,6 +673,11 @@ class GitOpsDeploymentManager extends React.Component {
                 {this.renderSshPort(selectedService?.value, sshPort)}
               </div>
             }
+            {supportsCommitStatus(provider) &&
+              <div className="flex u-marginBottom--30">
+                {this.renderAccessToken(provider, accessToken)}
+              </div>
+            }
             {dataChanged &&
               <button className="btn secondary u-marginBottom--30" disabled={finishingSetup} onClick={this.updateSettings}>
                 {finishingSetup ? "Updating" : "Update"}
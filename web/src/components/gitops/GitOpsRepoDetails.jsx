@@ -151,6 +151,20 @@ class GitOpsRepoDetails extends React.Component {
     const provider = selectedService?.value;
     const serviceSite = getGitOpsServiceSite(provider, hostname);
     const isBitbucketServer = provider === "bitbucket_server";
+    const isAzureDevOps = provider === "azure_devops";
+
+    let ownerRepoTitle = "Owner & Repository";
+    let ownerRepoPlaceholder = "owner/repository";
+    let ownerRepoError = "An owner and repository must be provided";
+    if (isBitbucketServer) {
+      ownerRepoTitle = "Project & Repository";
+      ownerRepoPlaceholder = "project/repository";
+      ownerRepoError = "A project and repository must be provided";
+    } else if (isAzureDevOps) {
+      ownerRepoTitle = "Organization, Project & Repository";
+      ownerRepoPlaceholder = "organization/project/repository";
+      ownerRepoError = "An organization, project and repository must be provided";
+    }
 
     return (
       <div key={`action-active`} className="GitOpsDeploy--step u-textAlign--left">
@@ -160,10 +174,10 @@ class GitOpsRepoDetails extends React.Component {
             <div className="flex flex1 u-marginBottom--30 u-marginTop--20">
               {provider !== "other" &&
                 <div className="flex flex1 flex-column u-marginRight--20">
-                  <p className="u-fontSize--large u-textColor--primary u-fontWeight--bold u-lineHeight--normal">{isBitbucketServer ? "Project & Repository" : "Owner & Repository"}</p>
+                  <p className="u-fontSize--large u-textColor--primary u-fontWeight--bold u-lineHeight--normal">{ownerRepoTitle}</p>
                   <p className="u-fontSize--normal u-textColor--bodyCopy u-fontWeight--medium u-lineHeight--normal u-marginBottom--10">Where will the commit be made?</p>
-                  <input type="text" className={`Input ${providerError?.field === "ownerRepo" && "has-error"}`} placeholder={isBitbucketServer ? "project/repository" : "owner/repository"} value={ownerRepo} onChange={(e) => this.setState({ ownerRepo: e.target.value })} />
-                  {providerError?.field === "ownerRepo" && <p className="u-fontSize--small u-marginTop--5 u-color--chestnut u-fontWeight--medium u-lineHeight--normal">{isBitbucketServer ? "A project and repository must be provided" : "An owner and repository must be provided"}</p>}
+                  <input type="text" className={`Input ${providerError?.field === "ownerRepo" && "has-error"}`} placeholder={ownerRepoPlaceholder} value={ownerRepo} onChange={(e) => this.setState({ ownerRepo: e.target.value })} />
+                  {providerError?.field === "ownerRepo" && <p className="u-fontSize--small u-marginTop--5 u-color--chestnut u-fontWeight--medium u-lineHeight--normal">{ownerRepoError}</p>}
                 </div>
               }
               {provider !== "other" &&
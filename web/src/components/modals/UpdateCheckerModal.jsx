@@ -38,13 +38,14 @@ export default class UpdateCheckerModal extends React.Component {
 
     this.state = {
       updateCheckerSpec: props.updateCheckerSpec,
+      deployPolicy: props.deployPolicy || "latest",
       submitUpdateCheckerSpecErr: "",
       selectedSchedule,
     };
   }
 
   onSubmitUpdateCheckerSpec = () => {
-    const { updateCheckerSpec } = this.state;
+    const { updateCheckerSpec, deployPolicy } = this.state;
     const { appSlug } = this.props;
 
     this.setState({
@@ -59,6 +60,7 @@ export default class UpdateCheckerModal extends React.Component {
       method: "PUT",
       body: JSON.stringify({
         updateCheckerSpec: updateCheckerSpec,
+        deployPolicy: deployPolicy,
       })
     })
       .then(async (res) => {
@@ -114,7 +116,7 @@ export default class UpdateCheckerModal extends React.Component {
 
   render() {
     const { isOpen, onRequestClose, gitopsEnabled } = this.props;
-    const { updateCheckerSpec, selectedSchedule, submitUpdateCheckerSpecErr } = this.state;
+    const { updateCheckerSpec, deployPolicy, selectedSchedule, submitUpdateCheckerSpecErr } = this.state;
 
     const humanReadableCron = this.getReadableCronExpression(updateCheckerSpec);
 
@@ -180,6 +182,23 @@ export default class UpdateCheckerModal extends React.Component {
                 }
               </div>
             </div>
+            <div className="BoxedCheckbox-wrapper flex1 u-textAlign--left u-marginTop--20">
+              <div className={`flex-auto flex ${deployPolicy === "sequential" ? "is-active" : ""}`}>
+                <input
+                  type="checkbox"
+                  className="u-cursor--pointer"
+                  id="sequentialDeployPolicy"
+                  checked={deployPolicy === "sequential"}
+                  onChange={(e) => { this.setState({ deployPolicy: e.target.checked ? "sequential" : "latest" }) }}
+                />
+                <label htmlFor="sequentialDeployPolicy" className="flex1 flex u-width--full u-position--relative u-cursor--pointer u-userSelect--none" style={{ marginTop: "2px" }}>
+                  <div className="flex flex-column u-marginLeft--5 justifyContent--center">
+                    <p className="u-fontSize--normal u-textColor--primary u-fontWeight--bold u-marginBottom--5">Deploy every version in order</p>
+                    <p className="u-lineHeight--normal u-fontSize--small u-textColor--bodyCopy u-fontWeight--medium">When updates are deployed automatically, deploy each new version and wait for the application to be ready before deploying the next one, instead of only deploying the newest version.</p>
+                  </div>
+                </label>
+              </div>
+            </div>
             {submitUpdateCheckerSpecErr && <span className="u-textColor--error u-fontSize--small u-fontWeight--bold u-marginTop--15">Error: {submitUpdateCheckerSpecErr}</span>}
           </div>
           <div className="flex u-marginTop--20">
@@ -85,6 +85,8 @@ export class NavBar extends PureComponent {
       selectedTab = "snapshots";
     } else if (pathname.startsWith("/access")) {
       selectedTab = "access";
+    } else if (pathname === "/remote-installs") {
+      selectedTab = "remote_installs";
     }
     this.setState({ selectedTab });
   }
@@ -111,6 +113,10 @@ export class NavBar extends PureComponent {
     this.props.history.push("/access");
   }
 
+  handleGoToRemoteInstalls = () => {
+    this.props.history.push("/remote-installs");
+  }
+
   redirectToDashboard = () => {
     const { refetchAppsList, history } = this.props;
     refetchAppsList().then(() => {
@@ -128,7 +134,7 @@ export class NavBar extends PureComponent {
     let licenseType;
     if (pathname.length > 2 && pathname[1] === "app") {
       selectedApp = appsList.find(app => app.slug === pathname[2]);
-      appLogo = selectedApp?.iconUri;
+      appLogo = Utilities.getIconUri(selectedApp);
       licenseType = selectedApp?.licenseType;
     } else {
       appLogo = logo;
@@ -201,6 +207,13 @@ export class NavBar extends PureComponent {
                         </span>
                       </div>
                     }
+                    <div className={classNames("NavItem u-position--relative flex", { "is-active": selectedTab === "remote_installs" })}>
+                      <span className="HeaderLink flex flex1 u-cursor--pointer" onClick={this.handleGoToRemoteInstalls}>
+                        <div className="flex flex1 alignItems--center">
+                          <span className="text u-fontSize--normal u-fontWeight--medium flex"> Remote Installs </span>
+                        </div>
+                      </span>
+                    </div>
                   </div>
                 )}
               </div>
@@ -1,10 +1,12 @@
 import React from "react";
 import classNames from "classnames";
 import { Link } from "react-router-dom";
+import { Utilities } from "@src/utilities/utilities";
 
 export default function KotsSidebarItem(props) {
   const { className, app } = props;
-  const { iconUri, name, slug } = app;
+  const { name, slug, labels } = app;
+  const iconUri = Utilities.getIconUri(app);
 
   let downstreamPendingLengths = [];
   app.downstreams?.map((w) => { 
@@ -55,6 +57,11 @@ export default function KotsSidebarItem(props) {
                 </span>
               </div>
             }
+            {labels && Object.keys(labels).length > 0 &&
+              <span className="u-fontSize--small u-textColor--info u-marginBottom--5">
+                {Object.keys(labels).sort().map(key => `${key}=${labels[key]}`).join(", ")}
+              </span>
+            }
           </div>
       </Link>
     </div>
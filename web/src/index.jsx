@@ -2,6 +2,9 @@ import * as React from "react";
 import * as ReactDOM from "react-dom";
 import ReplicatedErrorBoundary from "./components/shared/ErrorBoundary";
 import Root from "./Root";
+import { installCSRFProtection } from "./utilities/csrf";
+
+installCSRFProtection();
 
 ReactDOM.render((
   <ReplicatedErrorBoundary>
@@ -11,6 +11,7 @@ export class AirgapUploader {
       target: `${window.env.API_ENDPOINT}/app/${this.appSlug}/airgap/chunk`,
       headers: {
         "Authorization": Utilities.getToken(),
+        "X-Csrf-Token": Utilities.getCSRFToken(),
       },
       fileType: ["airgap"],
       maxFiles: 1,
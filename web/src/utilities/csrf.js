@@ -0,0 +1,27 @@
+import { Utilities } from "./utilities";
+
+const CSRF_TOKEN_HEADER = "X-Csrf-Token";
+const MUTATING_METHODS = ["POST", "PUT", "PATCH", "DELETE"];
+
+// installCSRFProtection wraps window.fetch so that the csrf token returned by the api is saved, and sent back on
+// mutating requests to the api
+export function installCSRFProtection() {
+  const originalFetch = window.fetch.bind(window);
+
+  window.fetch = async (url, options = {}) => {
+    const method = (options.method || "GET").toUpperCase();
+    const token = Utilities.getCSRFToken();
+    if (token && MUTATING_METHODS.includes(method) && String(url).startsWith(window.env.API_ENDPOINT)) {
+      const headers = new Headers(options.headers || {});
+      headers.set(CSRF_TOKEN_HEADER, token);
+      options = { ...options, headers };
+    }
+
+    const res = await originalFetch(url, options);
+    const newToken = res.headers.get(CSRF_TOKEN_HEADER);
+    if (newToken) {
+      Utilities.setCSRFToken(newToken);
+    }
+    return res;
+  };
+}
@@ -0,0 +1,119 @@
+import { Utilities } from "./utilities";
+
+const RECONNECT_MIN_MS = 1000;
+const RECONNECT_MAX_MS = 30000;
+
+// EventStream reads the server-sent events of the api, so that components can refresh when the app status, a task
+// status or the versions of an app change instead of polling for them. EventSource is not used because it cannot
+// send the authorization header. Components should keep polling, less often, while isConnected is false.
+export class EventStream {
+  constructor(onEvent) {
+    this.onEvent = onEvent;
+    this.controller = null;
+    this.stopped = true;
+    this.connected = false;
+    this.reconnectMs = RECONNECT_MIN_MS;
+  }
+
+  start = () => {
+    if (!this.stopped) {
+      return;
+    }
+    this.stopped = false;
+    this.connect();
+  }
+
+  stop = () => {
+    this.stopped = true;
+    this.connected = false;
+    if (this.controller) {
+      this.controller.abort();
+    }
+  }
+
+  isConnected = () => {
+    return this.connected;
+  }
+
+  connect = async () => {
+    if (this.stopped) {
+      return;
+    }
+
+    this.controller = new AbortController();
+    try {
+      const res = await fetch(`${window.env.API_ENDPOINT}/events`, {
+        headers: {
+          "Authorization": Utilities.getToken(),
+        },
+        method: "GET",
+        signal: this.controller.signal,
+      });
+      if (!res.ok) {
+        if (res.status === 401) {
+          Utilities.logoutUser();
+          return;
+        }
+        throw new Error(`Unexpected status code: ${res.status}`);
+      }
+
+      this.connected = true;
+      this.reconnectMs = RECONNECT_MIN_MS;
+      // events may have been missed while disconnected
+      this.onEvent({ type: "resync" });
+
+      await this.read(res.body.getReader());
+    } catch (err) {
+      if (!this.stopped) {
+        console.log("event stream disconnected", err);
+      }
+    }
+
+    this.connected = false;
+    if (!this.stopped) {
+      setTimeout(this.connect, this.reconnectMs);
+      this.reconnectMs = Math.min(this.reconnectMs * 2, RECONNECT_MAX_MS);
+    }
+  }
+
+  read = async (reader) => {
+    const decoder = new TextDecoder();
+    let buffer = "";
+    for (;;) {
+      const { done, value } = await reader.read();
+      if (done) {
+        return;
+      }
+
+      buffer += decoder.decode(value, { stream: true });
+      let end = buffer.indexOf("\n\n");
+      while (end !== -1) {
+        this.dispatch(buffer.slice(0, end));
+        buffer = buffer.slice(end + 2);
+        end = buffer.indexOf("\n\n");
+      }
+    }
+  }
+
+  dispatch = (frame) => {
+    let type = "";
+    let data = "";
+    frame.split("\n").forEach(line => {
+      if (line.startsWith("event: ")) {
+        type = line.slice("event: ".length);
+      } else if (line.startsWith("data: ")) {
+        data += line.slice("data: ".length);
+      }
+    });
+    if (!type) {
+      return; // heartbeat
+    }
+
+    try {
+      const event = JSON.parse(data);
+      this.onEvent({ ...event, type });
+    } catch (err) {
+      console.log("failed to parse event", err);
+    }
+  }
+}
@@ -226,6 +226,11 @@ export function getGitOpsUri(provider, ownerRepo, hostname = "", httpPort = "")
       return `https://bitbucket.org/${ownerRepo}`;
     case "bitbucket_server":
       return `https://${hostname}:${httpPort}/projects/${owner}/repos/${repo}`;
+    case "azure_devops": {
+      // ownerRepo is organization/project/repository
+      const parts = ownerRepo.split("/");
+      return `https://dev.azure.com/${parts[0] || ""}/${parts[1] || ""}/_git/${parts[2] || ""}`;
+    }
     default:
       return `https://github.com/${ownerRepo}`;
   }
@@ -245,6 +250,8 @@ export function getGitOpsServiceSite(provider, hostname = "") {
       return "bitbucket.org";
     case "bitbucket_server":
       return hostname;
+    case "azure_devops":
+      return "dev.azure.com";
     default:
       return "github.com";
   }
@@ -258,6 +265,7 @@ export function getAddKeyUri(gitops, ownerRepo) {
   const isGitlab = provider === "gitlab" || provider === "gitlab_enterprise";
   const isBitbucket = provider === "bitbucket";
   const isBitbucketServer = provider === "bitbucket_server";
+  const isAzureDevOps = provider === "azure_devops";
 
   let addKeyUri = `${gitUri}/settings/keys/new`;
   if (isGitlab) {
@@ -269,11 +277,18 @@ export function getAddKeyUri(gitops, ownerRepo) {
     const project = ownerRepo.split("/").length && ownerRepo.split("/")[0];
     const repo = ownerRepo.split("/").length > 1 && ownerRepo.split("/")[1];
     addKeyUri = `https://${hostname}:${httpPort}/plugins/servlet/ssh/projects/${project}/repos/${repo}/keys`;
+  } else if (isAzureDevOps) {
+    const organization = ownerRepo.split("/").length && ownerRepo.split("/")[0];
+    addKeyUri = `https://dev.azure.com/${organization}/_usersSettings/keys`;
   }
 
   return addKeyUri;
 }
 
+export function supportsCommitStatus(provider) {
+  return provider === "bitbucket_server" || provider === "azure_devops";
+}
+
 export function requiresHostname(provider) {
   return provider === "gitlab_enterprise" || provider === "github_enterprise" || provider === "bitbucket_server" || provider === "other";
 }
@@ -455,6 +470,15 @@ export function isVeleroCorrectVersion (snapshotSettings) {
 }
 
 export const Utilities = {
+  // getIconUri returns the uri of the icon of an app or of the metadata. The icon is served from the cache of the api
+  // when it has one, so that airgapped consoles do not wait for the icon uri to time out.
+  getIconUri(obj) {
+    if (obj?.cachedIconPath) {
+      return `${window.env.API_ENDPOINT}${obj.cachedIconPath}`;
+    }
+    return obj?.iconUri;
+  },
+
   getToken() {
     if (this.localStorageEnabled()) {
       return window.localStorage.getItem("token");
@@ -463,6 +487,20 @@ export const Utilities = {
     }
   },
 
+  getCSRFToken() {
+    if (this.localStorageEnabled()) {
+      return window.localStorage.getItem("csrf_token");
+    } else {
+      return "";
+    }
+  },
+
+  setCSRFToken(token) {
+    if (this.localStorageEnabled()) {
+      window.localStorage.setItem("csrf_token", token);
+    }
+  },
+
   getSessionRoles() {
     if (this.localStorageEnabled()) {
       return window.localStorage.getItem("session_roles");
@@ -578,6 +616,10 @@ export const Utilities = {
       window.localStorage.removeItem("session_roles");
     }
 
+    if (this.getCSRFToken()) {
+      window.localStorage.removeItem("csrf_token");
+    }
+
     if (window.location.pathname !== "/secure-console") {
       window.location = "/secure-console";
     }